@@ -0,0 +1,471 @@
+package cli
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/entireio/cli/cmd/entire/cli/agent"
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+	"github.com/entireio/cli/cmd/entire/cli/settings"
+	"github.com/entireio/cli/cmd/entire/cli/strategy"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/spf13/cobra"
+)
+
+// Admin report output formats.
+const (
+	reportFormatText = "text"
+	reportFormatCSV  = "csv"
+	reportFormatHTML = "html"
+)
+
+// adminReportOptions controls `entire admin report`.
+type adminReportOptions struct {
+	Format string
+
+	// Prices in USD per million tokens. Zero leaves cost out of the report.
+	InputPrice     float64
+	CacheReadPrice float64
+	OutputPrice    float64
+}
+
+// hasPrices reports whether any token price was given.
+func (o adminReportOptions) hasPrices() bool {
+	return o.InputPrice > 0 || o.CacheReadPrice > 0 || o.OutputPrice > 0
+}
+
+// repoReport is one row of the admin report.
+type repoReport struct {
+	Repo             string
+	Checkpoints      int
+	Sessions         int
+	InputTokens      int // fresh input plus cache writes
+	CacheReadTokens  int
+	OutputTokens     int
+	AgentKept        int
+	AgentWritten     int
+	PolicyViolations int
+	DestructiveOps   int
+	Err              error
+}
+
+// acceptance returns the share of agent-written lines kept, or -1 if unknown.
+func (r repoReport) acceptance() float64 {
+	if r.AgentWritten == 0 {
+		return -1
+	}
+	return float64(r.AgentKept) / float64(r.AgentWritten) * 100
+}
+
+// cost returns the estimated token cost in USD under opts' prices.
+func (r repoReport) cost(opts adminReportOptions) float64 {
+	return (float64(r.InputTokens)*opts.InputPrice +
+		float64(r.CacheReadTokens)*opts.CacheReadPrice +
+		float64(r.OutputTokens)*opts.OutputPrice) / 1e6
+}
+
+func newAdminCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "admin",
+		Short: "Tools for reporting across repositories",
+	}
+
+	cmd.AddCommand(newAdminReportCmd())
+
+	return cmd
+}
+
+func newAdminReportCmd() *cobra.Command {
+	var opts adminReportOptions
+	var reposFileFlag string
+	var outputFlag string
+
+	cmd := &cobra.Command{
+		Use:   "report [repo...]",
+		Short: "Summarize checkpoints across repositories",
+		Long: `Report rolls up the checkpoints of several repositories into one table:
+checkpoints, sessions, token usage, acceptance rate (the share of lines agents
+wrote that were still in the commit), and policy violations.
+
+Repositories are local paths or git URLs, given as arguments or one per line
+in --repos-file. For URLs, only the tip of ` + paths.MetadataBranchName + ` is
+fetched, into a temporary repository that is removed afterwards. Local paths
+are read as they are; run 'entire sync' there first for current data.
+
+Policy violations are commands run past .entire/policy.json with an override
+token. Destructive operations (reset, clean, purge, ...) come from each
+repository's audit log.
+
+Checkpoints record tokens, not prices. Pass --input-price, --cache-read-price,
+and --output-price (USD per million tokens) to add an estimated cost column.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			repos := args
+			if reposFileFlag != "" {
+				fromFile, err := readReposFile(reposFileFlag)
+				if err != nil {
+					return err
+				}
+				repos = append(repos, fromFile...)
+			}
+			if len(repos) == 0 {
+				return errors.New("no repositories given: pass paths or URLs, or --repos-file")
+			}
+			switch opts.Format {
+			case reportFormatText, reportFormatCSV, reportFormatHTML:
+			default:
+				return fmt.Errorf("invalid --format %q: must be %q, %q, or %q", opts.Format, reportFormatText, reportFormatCSV, reportFormatHTML)
+			}
+
+			w := cmd.OutOrStdout()
+			if outputFlag != "" {
+				f, err := os.Create(outputFlag) //nolint:gosec // user-chosen output path
+				if err != nil {
+					return fmt.Errorf("failed to create %s: %w", outputFlag, err)
+				}
+				defer f.Close()
+				w = f
+			}
+			return runAdminReport(cmd.Context(), w, cmd.ErrOrStderr(), repos, opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&reposFileFlag, "repos-file", "", "File listing repository paths or URLs, one per line")
+	cmd.Flags().StringVar(&opts.Format, "format", reportFormatText, "Output format: text, csv, or html")
+	cmd.Flags().StringVarP(&outputFlag, "output", "o", "", "Write the report to a file instead of stdout")
+	cmd.Flags().Float64Var(&opts.InputPrice, "input-price", 0, "USD per million input tokens (including cache writes)")
+	cmd.Flags().Float64Var(&opts.CacheReadPrice, "cache-read-price", 0, "USD per million cache read tokens")
+	cmd.Flags().Float64Var(&opts.OutputPrice, "output-price", 0, "USD per million output tokens")
+
+	return cmd
+}
+
+// readReposFile returns the non-empty, non-comment lines of path.
+func readReposFile(path string) ([]string, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // user-chosen input path
+	if err != nil {
+		return nil, fmt.Errorf("failed to read repos file: %w", err)
+	}
+	var repos []string
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "#") {
+			repos = append(repos, line)
+		}
+	}
+	return repos, nil
+}
+
+func runAdminReport(ctx context.Context, w, errW io.Writer, repos []string, opts adminReportOptions) error {
+	tmpDir, err := os.MkdirTemp("", "entire-admin-report-")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	reports := make([]repoReport, 0, len(repos))
+	failed := 0
+	for i, source := range repos {
+		report := repoReport{Repo: source}
+		repo, err := openReportRepo(ctx, source, filepath.Join(tmpDir, strconv.Itoa(i)))
+		if err == nil {
+			err = collectRepoReport(ctx, repo, &report)
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err() //nolint:wrapcheck // Propagating context cancellation
+			}
+			report.Err = err
+			failed++
+			fmt.Fprintf(errW, "Skipping %s: %v\n", source, err)
+		}
+		reports = append(reports, report)
+	}
+
+	switch opts.Format {
+	case reportFormatCSV:
+		err = writeReportCSV(w, reports, opts)
+	case reportFormatHTML:
+		err = writeReportHTML(w, reports, opts, time.Now())
+	default:
+		err = writeReportText(w, reports, opts)
+	}
+	if err != nil {
+		return err
+	}
+	if failed == len(repos) {
+		return NewSilentError(errors.New("no repository could be read"))
+	}
+	return nil
+}
+
+// openReportRepo opens source, a local path or a git URL. URLs are fetched
+// into a new bare repository at scratchDir.
+func openReportRepo(ctx context.Context, source, scratchDir string) (*git.Repository, error) {
+	if info, err := os.Stat(source); err == nil && info.IsDir() {
+		repo, err := git.PlainOpenWithOptions(source, &git.PlainOpenOptions{DetectDotGit: true, EnableDotGitCommonDir: true})
+		if err != nil {
+			return nil, fmt.Errorf("failed to open repository: %w", err)
+		}
+		if err := requireBranchStoreIn(repo); err != nil {
+			return nil, err
+		}
+		return repo, nil
+	}
+
+	if _, err := git.PlainInit(scratchDir, true); err != nil {
+		return nil, fmt.Errorf("failed to create scratch repository: %w", err)
+	}
+	branch := paths.MetadataBranchName
+	fetchCtx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+	defer cancel()
+	cmd := exec.CommandContext(fetchCtx, "git", "-C", scratchDir, "fetch", "--depth=1", "--", source,
+		"+refs/heads/"+branch+":refs/heads/"+branch)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		msg, _, _ := strings.Cut(strings.TrimSpace(string(output)), "\n")
+		return nil, fmt.Errorf("failed to fetch %s: %s", branch, strings.TrimPrefix(msg, "fatal: "))
+	}
+	repo, err := git.PlainOpen(scratchDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open fetched repository: %w", err)
+	}
+	return repo, nil
+}
+
+// requireBranchStoreIn fails when the settings of repo, a repository other
+// than the current one, keep committed checkpoints in an object store: only
+// its entire/checkpoints/v1 branch can be read.
+func requireBranchStoreIn(repo *git.Repository) error {
+	wt, err := repo.Worktree()
+	if err != nil {
+		return nil //nolint:nilerr // a bare repository has no settings
+	}
+	for _, file := range []string{settings.EntireSettingsFile, settings.EntireSettingsLocalFile} {
+		s, err := settings.LoadFromFile(filepath.Join(wt.Filesystem.Root(), file))
+		if err != nil {
+			continue
+		}
+		if s.GetCheckpointStore() != nil {
+			return strategy.ErrBranchStoreRequired
+		}
+	}
+	return nil
+}
+
+// collectRepoReport fills report from the checkpoints and audit log of repo.
+func collectRepoReport(ctx context.Context, repo *git.Repository, report *repoReport) error {
+	store := checkpoint.NewGitStore(repo)
+	committed, err := store.ListCommitted(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list checkpoints: %w", err)
+	}
+
+	sessions := make(map[string]bool)
+	for _, info := range committed {
+		report.Checkpoints++
+		metas, err := store.ReadSessionMetadata(ctx, info.CheckpointID)
+		if err != nil {
+			continue
+		}
+		for _, meta := range metas {
+			sessions[meta.SessionID] = true
+			addTokenUsage(report, meta.TokenUsage)
+			if attr := meta.InitialAttribution; attr != nil && attr.AgentWritten > 0 {
+				report.AgentKept += attr.AgentLines
+				report.AgentWritten += attr.AgentWritten
+			}
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return err //nolint:wrapcheck // Propagating context cancellation
+	}
+	report.Sessions = len(sessions)
+
+	entries, err := store.ReadAuditLog(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to read audit log: %w", err)
+	}
+	for _, entry := range entries {
+		if entry.Operation == checkpoint.AuditOpPolicyOverride {
+			report.PolicyViolations++
+		} else {
+			report.DestructiveOps++
+		}
+	}
+	return nil
+}
+
+// addTokenUsage adds usage, including its subagents, to report.
+func addTokenUsage(report *repoReport, usage *agent.TokenUsage) {
+	for u := usage; u != nil; u = u.SubagentTokens {
+		report.InputTokens += u.InputTokens + u.CacheCreationTokens
+		report.CacheReadTokens += u.CacheReadTokens
+		report.OutputTokens += u.OutputTokens
+	}
+}
+
+// reportTotal sums the rows that could be read.
+func reportTotal(reports []repoReport) repoReport {
+	total := repoReport{Repo: "Total"}
+	for _, r := range reports {
+		if r.Err != nil {
+			continue
+		}
+		total.Checkpoints += r.Checkpoints
+		total.Sessions += r.Sessions
+		total.InputTokens += r.InputTokens
+		total.CacheReadTokens += r.CacheReadTokens
+		total.OutputTokens += r.OutputTokens
+		total.AgentKept += r.AgentKept
+		total.AgentWritten += r.AgentWritten
+		total.PolicyViolations += r.PolicyViolations
+		total.DestructiveOps += r.DestructiveOps
+	}
+	return total
+}
+
+// reportColumns are the column titles shared by every format.
+func reportColumns(opts adminReportOptions) []string {
+	cols := []string{"Repository", "Checkpoints", "Sessions", "Input tokens", "Cache read tokens", "Output tokens", "Acceptance", "Policy violations", "Destructive ops"}
+	if opts.hasPrices() {
+		cols = append(cols, "Cost (USD)")
+	}
+	return cols
+}
+
+// reportCells formats one row in column order. Unreadable repositories
+// get their error in place of the numbers.
+func reportCells(r repoReport, opts adminReportOptions) []string {
+	if r.Err != nil {
+		return []string{r.Repo, "error: " + r.Err.Error()}
+	}
+	acceptance := "-"
+	if rate := r.acceptance(); rate >= 0 {
+		acceptance = fmt.Sprintf("%.1f%%", rate)
+	}
+	cells := []string{
+		r.Repo,
+		strconv.Itoa(r.Checkpoints),
+		strconv.Itoa(r.Sessions),
+		strconv.Itoa(r.InputTokens),
+		strconv.Itoa(r.CacheReadTokens),
+		strconv.Itoa(r.OutputTokens),
+		acceptance,
+		strconv.Itoa(r.PolicyViolations),
+		strconv.Itoa(r.DestructiveOps),
+	}
+	if opts.hasPrices() {
+		cells = append(cells, fmt.Sprintf("%.2f", r.cost(opts)))
+	}
+	return cells
+}
+
+func writeReportText(w io.Writer, reports []repoReport, opts adminReportOptions) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(reportColumns(opts), "\t"))
+	for _, r := range reports {
+		fmt.Fprintln(tw, strings.Join(reportCells(r, opts), "\t"))
+	}
+	fmt.Fprintln(tw, strings.Join(reportCells(reportTotal(reports), opts), "\t"))
+	if err := tw.Flush(); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	return nil
+}
+
+func writeReportCSV(w io.Writer, reports []repoReport, opts adminReportOptions) error {
+	cw := csv.NewWriter(w)
+	columns := reportColumns(opts)
+	records := [][]string{columns}
+	for _, r := range reports {
+		cells := reportCells(r, opts)
+		// Every record needs the same number of fields
+		for len(cells) < len(columns) {
+			cells = append(cells, "")
+		}
+		records = append(records, cells)
+	}
+	records = append(records, reportCells(reportTotal(reports), opts))
+	if err := cw.WriteAll(records); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	return nil
+}
+
+var reportHTMLTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Entire report</title>
+<style>
+body { font-family: system-ui, sans-serif; margin: 2rem; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ccc; padding: 0.3rem 0.6rem; text-align: right; }
+th:first-child, td:first-child { text-align: left; }
+tr.total td { font-weight: bold; }
+td.error { color: #b00; text-align: left; }
+</style>
+</head>
+<body>
+<h1>Entire report</h1>
+<p>Generated {{.Generated}} from {{len .Rows}} repositories.</p>
+<table>
+<thead><tr>{{range .Columns}}<th>{{.}}</th>{{end}}</tr></thead>
+<tbody>
+{{- range .Rows}}
+<tr>{{if .Error}}<td>{{.Repo}}</td><td class="error" colspan="{{$.ErrorSpan}}">{{.Error}}</td>{{else}}{{range .Cells}}<td>{{.}}</td>{{end}}{{end}}</tr>
+{{- end}}
+<tr class="total">{{range .Total}}<td>{{.}}</td>{{end}}</tr>
+</tbody>
+</table>
+</body>
+</html>
+`))
+
+// reportHTMLRow is a table row for reportHTMLTemplate.
+type reportHTMLRow struct {
+	Repo  string
+	Error string
+	Cells []string
+}
+
+func writeReportHTML(w io.Writer, reports []repoReport, opts adminReportOptions, now time.Time) error {
+	columns := reportColumns(opts)
+	rows := make([]reportHTMLRow, 0, len(reports))
+	for _, r := range reports {
+		row := reportHTMLRow{Repo: r.Repo, Cells: reportCells(r, opts)}
+		if r.Err != nil {
+			row.Error = r.Err.Error()
+		}
+		rows = append(rows, row)
+	}
+	data := struct {
+		Generated string
+		Columns   []string
+		Rows      []reportHTMLRow
+		Total     []string
+		ErrorSpan int
+	}{
+		Generated: now.UTC().Format(time.RFC3339),
+		Columns:   columns,
+		Rows:      rows,
+		Total:     reportCells(reportTotal(reports), opts),
+		ErrorSpan: len(columns) - 1,
+	}
+	if err := reportHTMLTemplate.Execute(w, data); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	return nil
+}
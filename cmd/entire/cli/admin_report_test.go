@@ -0,0 +1,125 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/entireio/cli/cmd/entire/cli/agent"
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+	"github.com/entireio/cli/cmd/entire/cli/strategy"
+	"github.com/entireio/cli/cmd/entire/cli/testutil"
+
+	"github.com/go-git/go-git/v5"
+)
+
+// setupReportRepo creates a repository with one checkpoint for the report.
+func setupReportRepo(t *testing.T, cpID id.CheckpointID, sessionID string, violations int) string {
+	t.Helper()
+	dir := t.TempDir()
+	testutil.InitRepo(t, dir)
+	testutil.WriteFile(t, dir, "README.md", "# Test")
+	testutil.GitAdd(t, dir, "README.md")
+	testutil.GitCommit(t, dir, "Initial commit")
+
+	repo, err := git.PlainOpen(dir)
+	if err != nil {
+		t.Fatalf("PlainOpen() error = %v", err)
+	}
+	ctx := context.Background()
+	store := checkpoint.NewGitStore(repo)
+	if err := store.WriteCommitted(ctx, checkpoint.WriteCommittedOptions{
+		CheckpointID: cpID,
+		SessionID:    sessionID,
+		Strategy:     strategy.StrategyNameManualCommit,
+		Transcript:   []byte(`{"type":"user"}` + "\n"),
+		TokenUsage: &agent.TokenUsage{
+			InputTokens:    1000,
+			OutputTokens:   500,
+			SubagentTokens: &agent.TokenUsage{InputTokens: 200, OutputTokens: 100},
+		},
+		InitialAttribution: &checkpoint.InitialAttribution{AgentLines: 30, AgentWritten: 40},
+		AuthorName:         "Test",
+		AuthorEmail:        "test@test.com",
+	}); err != nil {
+		t.Fatalf("WriteCommitted() error = %v", err)
+	}
+	for range violations {
+		if err := store.AppendAuditEntry(ctx, checkpoint.AuditEntry{Operation: checkpoint.AuditOpPolicyOverride}); err != nil {
+			t.Fatalf("AppendAuditEntry() error = %v", err)
+		}
+	}
+	return dir
+}
+
+func TestRunAdminReport_CSV(t *testing.T) {
+	t.Parallel()
+	local := setupReportRepo(t, id.MustCheckpointID("c1c1c1c1c1c1"), "session-a", 2)
+	remote := setupReportRepo(t, id.MustCheckpointID("c2c2c2c2c2c2"), "session-b", 0)
+	missing := filepath.Join(t.TempDir(), "missing")
+
+	var out, errOut bytes.Buffer
+	opts := adminReportOptions{Format: reportFormatCSV, InputPrice: 3, OutputPrice: 15}
+	if err := runAdminReport(context.Background(), &out, &errOut, []string{local, "file://" + remote, missing}, opts); err != nil {
+		t.Fatalf("runAdminReport() error = %v", err)
+	}
+
+	records, err := csv.NewReader(strings.NewReader(out.String())).ReadAll()
+	if err != nil {
+		t.Fatalf("report is not valid CSV: %v\n%s", err, out.String())
+	}
+	if len(records) != 5 {
+		t.Fatalf("got %d records, want header, 3 repositories, and total:\n%s", len(records), out.String())
+	}
+	want := []string{local, "1", "1", "1200", "0", "600", "75.0%", "2", "0", "0.01"}
+	if strings.Join(records[1], ",") != strings.Join(want, ",") {
+		t.Errorf("local row = %v, want %v", records[1], want)
+	}
+	if records[2][0] != "file://"+remote || records[2][1] != "1" {
+		t.Errorf("fetched row = %v, want one checkpoint", records[2])
+	}
+	if !strings.HasPrefix(records[3][1], "error: ") || !strings.Contains(errOut.String(), "Skipping "+missing) {
+		t.Errorf("missing repository should be reported as an error, got %v (%s)", records[3], errOut.String())
+	}
+	total := records[4]
+	if total[0] != "Total" || total[1] != "2" || total[2] != "2" || total[7] != "2" {
+		t.Errorf("total row = %v", total)
+	}
+}
+
+func TestRunAdminReport_HTML(t *testing.T) {
+	t.Parallel()
+	dir := setupReportRepo(t, id.MustCheckpointID("c3c3c3c3c3c3"), "session-<c>", 0)
+
+	var out, errOut bytes.Buffer
+	if err := runAdminReport(context.Background(), &out, &errOut, []string{dir}, adminReportOptions{Format: reportFormatHTML}); err != nil {
+		t.Fatalf("runAdminReport() error = %v", err)
+	}
+	html := out.String()
+	if !strings.Contains(html, "<th>Acceptance</th>") || !strings.Contains(html, "<td>75.0%</td>") {
+		t.Errorf("HTML report missing columns or values:\n%s", html)
+	}
+	if strings.Contains(html, "Cost (USD)") {
+		t.Error("cost column should only appear when prices are given")
+	}
+}
+
+func TestReadReposFile(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "repos.txt")
+	if err := os.WriteFile(path, []byte("# team A\n/src/api\n\n  git@example.com:org/web.git  \n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	repos, err := readReposFile(path)
+	if err != nil {
+		t.Fatalf("readReposFile() error = %v", err)
+	}
+	if strings.Join(repos, "|") != "/src/api|git@example.com:org/web.git" {
+		t.Errorf("readReposFile() = %v", repos)
+	}
+}
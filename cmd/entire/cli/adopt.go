@@ -0,0 +1,90 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+	"github.com/entireio/cli/cmd/entire/cli/strategy"
+
+	"github.com/spf13/cobra"
+)
+
+func newAdoptCmd() *cobra.Command {
+	var remoteFlag string
+
+	cmd := &cobra.Command{
+		Use:   "adopt",
+		Short: "Set up checkpoints and shadow branches pushed by others",
+		Long: `Fetch Entire's branches from a remote and create the local branches that
+are missing, so a fresh clone can browse the checkpoints colleagues pushed.
+
+The ` + paths.MetadataBranchName + ` branch is created, or fast-forwarded if
+the local one is behind. If both have new checkpoints, it is left for
+'entire sync' to merge.
+
+Shadow branches that only exist on the remote are created locally, and an
+ended session is reconstructed from each branch's commits, so 'entire
+rewind' offers their checkpoints once the branch's base commit is checked
+out. Local branches are never overwritten, and reconstructed sessions are
+never resumed by agent hooks.
+
+The remote defaults to the sync.remote setting, or origin.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := cmd.Context()
+			if _, err := paths.WorktreeRoot(ctx); err != nil {
+				return paths.ErrNotGitRepository
+			}
+			remote := remoteFlag
+			if remote == "" {
+				remote = strategy.MetadataRemote(ctx)
+			}
+			return runAdopt(ctx, cmd.OutOrStdout(), remote)
+		},
+	}
+
+	cmd.Flags().StringVar(&remoteFlag, "remote", "", "Remote to adopt branches from (default from settings, or origin)")
+
+	return cmd
+}
+
+func runAdopt(ctx context.Context, w io.Writer, remote string) error {
+	result, err := strategy.AdoptRemoteRefs(ctx, remote)
+	if err != nil {
+		return err //nolint:wrapcheck // already wrapped by strategy
+	}
+
+	switch result.Metadata {
+	case "":
+		fmt.Fprintf(w, "%s has no %s branch.\n", remote, paths.MetadataBranchName)
+	case "diverged":
+		fmt.Fprintf(w, "%s: diverged from %s; run 'entire sync' to merge.\n", paths.MetadataBranchName, remote)
+	default:
+		fmt.Fprintf(w, "%s: %s\n", paths.MetadataBranchName, result.Metadata)
+	}
+
+	if len(result.ShadowBranches) > 0 {
+		fmt.Fprintf(w, "Adopted %d shadow branch(es):\n", len(result.ShadowBranches))
+		for _, branch := range result.ShadowBranches {
+			fmt.Fprintf(w, "  %s\n", branch)
+		}
+	}
+	if len(result.Skipped) > 0 {
+		fmt.Fprintf(w, "Skipped %d shadow branch(es) that differ locally:\n", len(result.Skipped))
+		for _, branch := range result.Skipped {
+			fmt.Fprintf(w, "  %s\n", branch)
+		}
+	}
+	if len(result.Sessions) > 0 {
+		fmt.Fprintf(w, "Reconstructed %d session(s):\n", len(result.Sessions))
+		for _, sessionID := range result.Sessions {
+			fmt.Fprintf(w, "  %s\n", sessionID)
+		}
+	}
+	if len(result.ShadowBranches) == 0 && len(result.Skipped) == 0 {
+		fmt.Fprintln(w, "No new shadow branches.")
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+)
+
+func TestRunAdopt(t *testing.T) {
+	dir := setupExecTestRepo(t)
+	writePurgeTestCheckpoint(t, id.MustCheckpointID("b8b8b8b8b8b8"))
+
+	remote := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "--bare", remote},
+		{"-C", dir, "remote", "add", "origin", remote},
+		{"-C", dir, "push", "-q", "origin", paths.MetadataBranchName},
+		// Start over as a fresh clone would
+		{"-C", dir, "branch", "-D", paths.MetadataBranchName},
+	} {
+		if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+
+	var out bytes.Buffer
+	if err := runAdopt(context.Background(), &out, "origin"); err != nil {
+		t.Fatalf("runAdopt() error = %v", err)
+	}
+	if !strings.Contains(out.String(), paths.MetadataBranchName+": created") {
+		t.Errorf("output should report the created branch, got: %s", out.String())
+	}
+	if !strings.Contains(out.String(), "No new shadow branches.") {
+		t.Errorf("output should report no shadow branches, got: %s", out.String())
+	}
+
+	out.Reset()
+	if err := runAdopt(context.Background(), &out, "origin"); err != nil {
+		t.Fatalf("runAdopt() again error = %v", err)
+	}
+	if !strings.Contains(out.String(), paths.MetadataBranchName+": up to date") {
+		t.Errorf("second adopt should have nothing to do, got: %s", out.String())
+	}
+}
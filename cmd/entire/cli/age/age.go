@@ -0,0 +1,159 @@
+// Package age encrypts checkpoint content to a team's public keys with the
+// age v1 file format (age-encryption.org/v1) and X25519 recipients. It wraps
+// filippo.io/age, so files and keys are interchangeable with the age and rage
+// tools, and keeps the key types the rest of the CLI uses.
+package age
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"filippo.io/age"
+)
+
+// versionLine starts every age v1 file.
+const versionLine = "age-encryption.org/v1"
+
+// ErrNoMatchingIdentity is returned by Decrypt when none of the identities
+// can open the file.
+var ErrNoMatchingIdentity = errors.New("no identity matched any of the file's recipients")
+
+// Recipient is an X25519 public key, "age1...".
+type Recipient struct {
+	key *age.X25519Recipient
+}
+
+// ParseRecipient parses an "age1..." public key.
+func ParseRecipient(s string) (*Recipient, error) {
+	key, err := age.ParseX25519Recipient(s)
+	if err != nil {
+		return nil, fmt.Errorf("malformed age recipient %q: %w", s, err)
+	}
+	return &Recipient{key: key}, nil
+}
+
+// String returns the "age1..." encoding of the recipient.
+func (r *Recipient) String() string {
+	return r.key.String()
+}
+
+// Identity is an X25519 private key, "AGE-SECRET-KEY-1...".
+type Identity struct {
+	key *age.X25519Identity
+}
+
+// GenerateIdentity returns a new random identity.
+func GenerateIdentity() (*Identity, error) {
+	key, err := age.GenerateX25519Identity()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate key: %w", err)
+	}
+	return &Identity{key: key}, nil
+}
+
+// ParseIdentity parses an "AGE-SECRET-KEY-1..." private key.
+func ParseIdentity(s string) (*Identity, error) {
+	key, err := age.ParseX25519Identity(s)
+	if err != nil {
+		// The error would quote the key
+		return nil, errors.New("malformed age identity: not an X25519 secret key")
+	}
+	return &Identity{key: key}, nil
+}
+
+// ParseIdentities parses an identity file: one key per line, with blank
+// lines and "#" comments ignored, as written by age-keygen.
+func ParseIdentities(r io.Reader) ([]*Identity, error) {
+	var ids []*Identity
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		id, err := ParseIdentity(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNo, err)
+		}
+		ids = append(ids, id)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading identities: %w", err)
+	}
+	if len(ids) == 0 {
+		return nil, errors.New("no identities found")
+	}
+	return ids, nil
+}
+
+// String returns the "AGE-SECRET-KEY-1..." encoding of the identity.
+func (i *Identity) String() string {
+	return i.key.String()
+}
+
+// Recipient returns the identity's public key.
+func (i *Identity) Recipient() *Recipient {
+	return &Recipient{key: i.key.Recipient()}
+}
+
+// IsEncrypted reports whether data starts with an age v1 header.
+func IsEncrypted(data []byte) bool {
+	return bytes.HasPrefix(data, []byte(versionLine+"\n"))
+}
+
+// Encrypt encrypts plaintext to the recipients.
+func Encrypt(plaintext []byte, recipients ...*Recipient) ([]byte, error) {
+	if len(recipients) == 0 {
+		return nil, errors.New("no recipients")
+	}
+	keys := make([]age.Recipient, len(recipients))
+	for i, r := range recipients {
+		keys[i] = r.key
+	}
+	var out bytes.Buffer
+	w, err := age.Encrypt(&out, keys...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt: %w", err)
+	}
+	if _, err := w.Write(plaintext); err != nil {
+		return nil, fmt.Errorf("failed to encrypt: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encrypt: %w", err)
+	}
+	return out.Bytes(), nil
+}
+
+// Decrypt decrypts an age file with the first identity that matches one of
+// its recipient stanzas.
+func Decrypt(ciphertext []byte, identities ...*Identity) ([]byte, error) {
+	if !IsEncrypted(ciphertext) {
+		return nil, errors.New("not an age v1 file")
+	}
+	if len(identities) == 0 {
+		return nil, ErrNoMatchingIdentity
+	}
+	keys := make([]age.Identity, len(identities))
+	for i, id := range identities {
+		keys[i] = id.key
+	}
+	r, err := age.Decrypt(bytes.NewReader(ciphertext), keys...)
+	var noMatch *age.NoIdentityMatchError
+	if errors.As(err, &noMatch) {
+		return nil, ErrNoMatchingIdentity
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt: %w", err)
+	}
+	plaintext, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt: %w", err)
+	}
+	return plaintext, nil
+}
@@ -0,0 +1,217 @@
+package age
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// chunkSize is the age payload chunk size, to test sizes around a boundary.
+const chunkSize = 64 * 1024
+
+// testIdentity is the 0x42-scalar key used by the age test vectors.
+const (
+	testIdentity  = "AGE-SECRET-KEY-1GFPYYSJZGFPYYSJZGFPYYSJZGFPYYSJZGFPYYSJZGFPYYSJZGFPQ4EGAEX"
+	testRecipient = "age1zvkyg2lqzraa2lnjvqej32nkuu0ues2s82hzrye869xeexvn73equnujwj"
+)
+
+func TestKeys(t *testing.T) {
+	t.Parallel()
+	id, err := ParseIdentity(testIdentity)
+	if err != nil {
+		t.Fatalf("ParseIdentity() error = %v", err)
+	}
+	if got := id.String(); got != testIdentity {
+		t.Errorf("Identity.String() = %s", got)
+	}
+	if got := id.Recipient().String(); got != testRecipient {
+		t.Errorf("Recipient() = %s, want %s", got, testRecipient)
+	}
+	r, err := ParseRecipient(testRecipient)
+	if err != nil || r.String() != testRecipient {
+		t.Errorf("ParseRecipient() = %v, %v", r, err)
+	}
+
+	for _, bad := range []string{
+		testRecipient[:len(testRecipient)-1] + "q",               // checksum
+		strings.ToUpper(testRecipient[:10]) + testRecipient[10:], // mixed case
+		testIdentity,
+	} {
+		if _, err := ParseRecipient(bad); err == nil {
+			t.Errorf("ParseRecipient(%q) should fail", bad)
+		}
+	}
+	if _, err := ParseIdentity(testRecipient); err == nil {
+		t.Error("ParseIdentity() should reject a public key")
+	}
+}
+
+func TestParseIdentities(t *testing.T) {
+	t.Parallel()
+	ids, err := ParseIdentities(strings.NewReader("# created: 2026-01-01\n# public key: " + testRecipient + "\n" + testIdentity + "\n\n"))
+	if err != nil || len(ids) != 1 {
+		t.Fatalf("ParseIdentities() = %v, %v", ids, err)
+	}
+	if _, err := ParseIdentities(strings.NewReader("# nothing here\n")); err == nil {
+		t.Error("ParseIdentities() should reject a file without keys")
+	}
+	if _, err := ParseIdentities(strings.NewReader("not-a-key\n")); err == nil {
+		t.Error("ParseIdentities() should reject malformed lines")
+	}
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	t.Parallel()
+	alice, err := ParseIdentity(testIdentity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bob, err := GenerateIdentity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	eve, err := GenerateIdentity()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sizes := []int{0, 1, chunkSize - 1, chunkSize, chunkSize + 1, 3 * chunkSize}
+	for _, size := range sizes {
+		plaintext := bytes.Repeat([]byte("x"), size)
+		ciphertext, err := Encrypt(plaintext, alice.Recipient(), bob.Recipient())
+		if err != nil {
+			t.Fatalf("Encrypt(%d bytes) error = %v", size, err)
+		}
+		if !IsEncrypted(ciphertext) {
+			t.Fatalf("Encrypt(%d bytes) output has no age header", size)
+		}
+		for name, id := range map[string]*Identity{"alice": alice, "bob": bob} {
+			got, err := Decrypt(ciphertext, eve, id)
+			if err != nil {
+				t.Fatalf("Decrypt(%d bytes) as %s error = %v", size, name, err)
+			}
+			if !bytes.Equal(got, plaintext) {
+				t.Errorf("Decrypt(%d bytes) as %s returned different content", size, name)
+			}
+		}
+		if _, err := Decrypt(ciphertext); !errors.Is(err, ErrNoMatchingIdentity) {
+			t.Errorf("Decrypt(%d bytes) without identities error = %v, want ErrNoMatchingIdentity", size, err)
+		}
+		if _, err := Decrypt(ciphertext, eve); !errors.Is(err, ErrNoMatchingIdentity) {
+			t.Errorf("Decrypt(%d bytes) as eve error = %v, want ErrNoMatchingIdentity", size, err)
+		}
+		if size > 0 {
+			truncated := ciphertext[:len(ciphertext)-1]
+			if _, err := Decrypt(truncated, alice); err == nil {
+				t.Errorf("Decrypt(%d bytes) should reject a truncated payload", size)
+			}
+		}
+	}
+}
+
+func TestDecrypt_RejectsTamperedHeader(t *testing.T) {
+	t.Parallel()
+	id, err := GenerateIdentity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := GenerateIdentity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ciphertext, err := Encrypt([]byte("secret prompt"), id.Recipient(), other.Recipient())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Dropping a recipient stanza keeps the file key valid but breaks the MAC
+	lines := strings.SplitN(string(ciphertext), "\n", 6)
+	tampered := []byte(strings.Join(append(lines[:3], lines[5]), "\n"))
+	if _, err := Decrypt(tampered, id); err == nil || errors.Is(err, ErrNoMatchingIdentity) {
+		t.Errorf("Decrypt() of a tampered header error = %v, want MAC mismatch", err)
+	}
+	if _, err := Decrypt([]byte("plain text"), id); err == nil {
+		t.Error("Decrypt() should reject non-age input")
+	}
+}
+
+// TestDecrypt_AgeFixtures decrypts files written by the age tool:
+// testdata/recipient.age from "age -r <testRecipient>", and the example file
+// and key from filippo.io/age's own testdata.
+func TestDecrypt_AgeFixtures(t *testing.T) {
+	t.Parallel()
+	id, err := ParseIdentity(testIdentity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyFile, err := os.Open(filepath.Join("testdata", "example_keys.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer keyFile.Close()
+	exampleIDs, err := ParseIdentities(keyFile)
+	if err != nil {
+		t.Fatalf("ParseIdentities() error = %v", err)
+	}
+
+	for _, tt := range []struct {
+		file string
+		ids  []*Identity
+		want string
+	}{
+		{"recipient.age", []*Identity{id}, "checkpoint prompt encrypted by age -r\n"},
+		{"example.age", exampleIDs, "Black lives matter."},
+	} {
+		ciphertext, err := os.ReadFile(filepath.Join("testdata", tt.file))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !IsEncrypted(ciphertext) {
+			t.Errorf("IsEncrypted(%s) = false", tt.file)
+		}
+		got, err := Decrypt(ciphertext, tt.ids...)
+		if err != nil {
+			t.Fatalf("Decrypt(%s) error = %v", tt.file, err)
+		}
+		if string(got) != tt.want {
+			t.Errorf("Decrypt(%s) = %q, want %q", tt.file, got, tt.want)
+		}
+	}
+}
+
+// TestEncrypt_AgeTool checks that the age tool decrypts Encrypt's output,
+// when it's installed.
+func TestEncrypt_AgeTool(t *testing.T) {
+	t.Parallel()
+	agePath, err := exec.LookPath("age")
+	if err != nil {
+		t.Skip("age is not installed")
+	}
+	dir := t.TempDir()
+	keyPath := filepath.Join(dir, "key.txt")
+	if err := os.WriteFile(keyPath, []byte(testIdentity+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	r, err := ParseRecipient(testRecipient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	plaintext := bytes.Repeat([]byte("prompt "), chunkSize/3)
+	ciphertext, err := Encrypt(plaintext, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command(agePath, "--decrypt", "-i", keyPath)
+	cmd.Stdin = bytes.NewReader(ciphertext)
+	got, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("age --decrypt error = %v", err)
+	}
+	if !bytes.Equal(got, plaintext) {
+		t.Error("age --decrypt returned different content")
+	}
+}
@@ -187,3 +187,27 @@ type SubagentAwareExtractor interface {
 	// The subagentsDir parameter specifies where subagent transcripts are stored.
 	CalculateTotalTokenUsage(transcriptData []byte, fromOffset int, subagentsDir string) (*TokenUsage, error)
 }
+
+// ConfigFileProvider lists the files that configure how the agent behaves in a
+// repository: instruction files (CLAUDE.md, .cursorrules) and project settings.
+// The framework snapshots them into checkpoints when snapshot_agent_config is enabled.
+type ConfigFileProvider interface {
+	Agent
+
+	// ConfigFiles returns repo-relative paths of the agent's config files.
+	// Paths that are directories include every file beneath them.
+	ConfigFiles() []string
+}
+
+// InstructionFileProvider lists the agent's instruction files: the files
+// whose text the agent reads as instructions (CLAUDE.md, .cursorrules), a
+// subset of ConfigFiles. The framework records their versions in every
+// checkpoint, so `entire instructions history` can tell which session
+// changed them.
+type InstructionFileProvider interface {
+	Agent
+
+	// InstructionFiles returns repo-relative paths of the instruction files.
+	// Paths that are directories include every file beneath them.
+	InstructionFiles() []string
+}
@@ -88,6 +88,20 @@ func (c *ClaudeCodeAgent) ResolveSessionFile(sessionDir, agentSessionID string)
 // ProtectedDirs returns directories that Claude uses for config/state.
 func (c *ClaudeCodeAgent) ProtectedDirs() []string { return []string{".claude"} }
 
+var _ agent.ConfigFileProvider = (*ClaudeCodeAgent)(nil)
+
+// ConfigFiles returns Claude's instruction files and project settings.
+func (c *ClaudeCodeAgent) ConfigFiles() []string {
+	return []string{"CLAUDE.md", "CLAUDE.local.md", ".claude/CLAUDE.md", ".claude/settings.json", ".claude/commands", ".claude/agents"}
+}
+
+var _ agent.InstructionFileProvider = (*ClaudeCodeAgent)(nil)
+
+// InstructionFiles returns Claude's memory files.
+func (c *ClaudeCodeAgent) InstructionFiles() []string {
+	return []string{"CLAUDE.md", "CLAUDE.local.md", ".claude/CLAUDE.md"}
+}
+
 // GetSessionDir returns the directory where Claude stores session transcripts.
 func (c *ClaudeCodeAgent) GetSessionDir(repoPath string) (string, error) {
 	// Check for test environment override
@@ -88,6 +88,20 @@ func (c *CursorAgent) ResolveSessionFile(sessionDir, agentSessionID string) stri
 // ProtectedDirs returns directories that Cursor uses for config/state.
 func (c *CursorAgent) ProtectedDirs() []string { return []string{".cursor"} }
 
+var _ agent.ConfigFileProvider = (*CursorAgent)(nil)
+
+// ConfigFiles returns Cursor's rule files.
+func (c *CursorAgent) ConfigFiles() []string {
+	return []string{".cursorrules", ".cursor/rules", "AGENTS.md"}
+}
+
+var _ agent.InstructionFileProvider = (*CursorAgent)(nil)
+
+// InstructionFiles returns Cursor's rule files, which are all instructions.
+func (c *CursorAgent) InstructionFiles() []string {
+	return c.ConfigFiles()
+}
+
 // GetSessionDir returns the directory where Cursor stores session transcripts.
 func (c *CursorAgent) GetSessionDir(repoPath string) (string, error) {
 	if override := os.Getenv("ENTIRE_TEST_CURSOR_PROJECT_DIR"); override != "" {
@@ -105,10 +105,12 @@ func (c *CursorAgent) parseTurnEnd(ctx context.Context, stdin io.Reader) (*agent
 		return nil, err
 	}
 	return &agent.Event{
-		Type:       agent.TurnEnd,
-		SessionID:  raw.ConversationID,
-		SessionRef: c.resolveTranscriptRef(ctx, raw.ConversationID, raw.TranscriptPath),
-		Timestamp:  time.Now(),
+		Type:         agent.TurnEnd,
+		SessionID:    raw.ConversationID,
+		SessionRef:   c.resolveTranscriptRef(ctx, raw.ConversationID, raw.TranscriptPath),
+		Timestamp:    time.Now(),
+		Model:        reportedModel(raw.Model),
+		AgentVersion: raw.CursorVersion,
 	}, nil
 }
 
@@ -178,3 +180,12 @@ func (c *CursorAgent) parseSubagentStop(stdin io.Reader) (*agent.Event, error) {
 	}
 	return event, nil
 }
+
+// reportedModel returns the hook's model name, or "" for the IDE's "default"
+// placeholder, which doesn't say which model actually ran.
+func reportedModel(model string) string {
+	if model == "default" {
+		return ""
+	}
+	return model
+}
@@ -120,6 +120,31 @@ func TestParseHookEvent_TurnEnd(t *testing.T) {
 	}
 }
 
+func TestParseHookEvent_TurnEnd_Model(t *testing.T) {
+	t.Parallel()
+
+	ag := &CursorAgent{}
+	input := `{"conversation_id": "sess-789", "transcript_path": "/tmp/stop.jsonl", "model": "gpt-5", "cursor_version": "1.7.2"}`
+
+	event, err := ag.ParseHookEvent(context.Background(), HookNameStop, strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if event.Model != "gpt-5" || event.AgentVersion != "1.7.2" {
+		t.Errorf("expected model gpt-5 and version 1.7.2, got %q and %q", event.Model, event.AgentVersion)
+	}
+
+	// The IDE's "default" placeholder doesn't identify a model
+	input = `{"conversation_id": "sess-789", "transcript_path": "/tmp/stop.jsonl", "model": "default"}`
+	event, err = ag.ParseHookEvent(context.Background(), HookNameStop, strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if event.Model != "" {
+		t.Errorf("expected empty model for default placeholder, got %q", event.Model)
+	}
+}
+
 func TestParseHookEvent_SessionEnd(t *testing.T) {
 	t.Parallel()
 
@@ -96,6 +96,13 @@ type Event struct {
 	SubagentType    string
 	TaskDescription string
 
+	// Model is the model name reported by the hook payload, if the agent sends one
+	// (populated on TurnEnd events). Agents that don't fall back to the transcript.
+	Model string
+
+	// AgentVersion is the agent client version reported by the hook payload, if any.
+	AgentVersion string
+
 	// ResponseMessage is an optional message to display to the user via the agent.
 	ResponseMessage string
 
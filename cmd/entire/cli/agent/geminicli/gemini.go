@@ -81,6 +81,20 @@ func (g *GeminiCLIAgent) GetSessionID(input *agent.HookInput) string {
 // ProtectedDirs returns directories that Gemini uses for config/state.
 func (g *GeminiCLIAgent) ProtectedDirs() []string { return []string{".gemini"} }
 
+var _ agent.ConfigFileProvider = (*GeminiCLIAgent)(nil)
+
+// ConfigFiles returns Gemini's context files and project settings.
+func (g *GeminiCLIAgent) ConfigFiles() []string {
+	return []string{"GEMINI.md", ".gemini/settings.json", ".gemini/commands"}
+}
+
+var _ agent.InstructionFileProvider = (*GeminiCLIAgent)(nil)
+
+// InstructionFiles returns Gemini's context file.
+func (g *GeminiCLIAgent) InstructionFiles() []string {
+	return []string{"GEMINI.md"}
+}
+
 // ResolveSessionFile returns the path to a Gemini session file.
 // Gemini names files as session-<date>-<shortid>.json where shortid is the first 8 chars
 // of the session UUID. This searches for an existing file matching the pattern, falling
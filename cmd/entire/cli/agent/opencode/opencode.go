@@ -39,6 +39,20 @@ func (a *OpenCodeAgent) Description() string     { return "OpenCode - AI-powered
 func (a *OpenCodeAgent) IsPreview() bool         { return true }
 func (a *OpenCodeAgent) ProtectedDirs() []string { return []string{".opencode"} }
 
+var _ agent.ConfigFileProvider = (*OpenCodeAgent)(nil)
+
+// ConfigFiles returns OpenCode's instruction file and project config.
+func (a *OpenCodeAgent) ConfigFiles() []string {
+	return []string{"AGENTS.md", "opencode.json", ".opencode/agent", ".opencode/command"}
+}
+
+var _ agent.InstructionFileProvider = (*OpenCodeAgent)(nil)
+
+// InstructionFiles returns OpenCode's instruction file.
+func (a *OpenCodeAgent) InstructionFiles() []string {
+	return []string{"AGENTS.md"}
+}
+
 func (a *OpenCodeAgent) DetectPresence(ctx context.Context) (bool, error) {
 	repoRoot, err := paths.WorktreeRoot(ctx)
 	if err != nil {
@@ -0,0 +1,76 @@
+package agent
+
+import "strings"
+
+// ModelPrice is a model's list price in USD per million tokens.
+type ModelPrice struct {
+	Input      float64
+	CacheWrite float64
+	CacheRead  float64
+	Output     float64
+}
+
+// modelPrices maps model ID prefixes to list prices. More specific prefixes
+// come first, since the first match wins.
+var modelPrices = []struct {
+	prefix string
+	price  ModelPrice
+}{
+	{"claude-opus-4-5", ModelPrice{Input: 5, CacheWrite: 6.25, CacheRead: 0.5, Output: 25}},
+	{"claude-opus-4", ModelPrice{Input: 15, CacheWrite: 18.75, CacheRead: 1.5, Output: 75}},
+	{"claude-3-opus", ModelPrice{Input: 15, CacheWrite: 18.75, CacheRead: 1.5, Output: 75}},
+	{"claude-sonnet-4", ModelPrice{Input: 3, CacheWrite: 3.75, CacheRead: 0.3, Output: 15}},
+	{"claude-3-7-sonnet", ModelPrice{Input: 3, CacheWrite: 3.75, CacheRead: 0.3, Output: 15}},
+	{"claude-3-5-sonnet", ModelPrice{Input: 3, CacheWrite: 3.75, CacheRead: 0.3, Output: 15}},
+	{"claude-haiku-4-5", ModelPrice{Input: 1, CacheWrite: 1.25, CacheRead: 0.1, Output: 5}},
+	{"claude-3-5-haiku", ModelPrice{Input: 0.8, CacheWrite: 1, CacheRead: 0.08, Output: 4}},
+	{"gpt-5-nano", ModelPrice{Input: 0.05, CacheWrite: 0.05, CacheRead: 0.005, Output: 0.4}},
+	{"gpt-5-mini", ModelPrice{Input: 0.25, CacheWrite: 0.25, CacheRead: 0.025, Output: 2}},
+	{"gpt-5", ModelPrice{Input: 1.25, CacheWrite: 1.25, CacheRead: 0.125, Output: 10}},
+	{"gpt-4.1-mini", ModelPrice{Input: 0.4, CacheWrite: 0.4, CacheRead: 0.1, Output: 1.6}},
+	{"gpt-4.1", ModelPrice{Input: 2, CacheWrite: 2, CacheRead: 0.5, Output: 8}},
+	{"gpt-4o-mini", ModelPrice{Input: 0.15, CacheWrite: 0.15, CacheRead: 0.075, Output: 0.6}},
+	{"gpt-4o", ModelPrice{Input: 2.5, CacheWrite: 2.5, CacheRead: 1.25, Output: 10}},
+	{"gemini-2.5-pro", ModelPrice{Input: 1.25, CacheWrite: 1.25, CacheRead: 0.31, Output: 10}},
+	{"gemini-2.5-flash-lite", ModelPrice{Input: 0.1, CacheWrite: 0.1, CacheRead: 0.025, Output: 0.4}},
+	{"gemini-2.5-flash", ModelPrice{Input: 0.3, CacheWrite: 0.3, CacheRead: 0.075, Output: 2.5}},
+}
+
+// PriceForModel returns the list price of a model, matched by ID prefix so
+// dated snapshots (claude-sonnet-4-5-20250929) price like their family.
+// Provider prefixes such as "anthropic/" are ignored. The second result is
+// false for models without a known price.
+func PriceForModel(model string) (ModelPrice, bool) {
+	model = strings.ToLower(strings.TrimSpace(model))
+	if i := strings.LastIndex(model, "/"); i >= 0 {
+		model = model[i+1:]
+	}
+	if model == "" {
+		return ModelPrice{}, false
+	}
+	for _, p := range modelPrices {
+		if strings.HasPrefix(model, p.prefix) {
+			return p.price, true
+		}
+	}
+	return ModelPrice{}, false
+}
+
+// EstimateCost returns the estimated cost in USD of usage, including its
+// subagents, at model's list price. Subagents are priced as model too, since
+// transcripts don't say which model a subagent ran. Returns 0 when usage is
+// nil or the model has no known price.
+func EstimateCost(model string, usage *TokenUsage) float64 {
+	price, ok := PriceForModel(model)
+	if !ok {
+		return 0
+	}
+	var cost float64
+	for u := usage; u != nil; u = u.SubagentTokens {
+		cost += float64(u.InputTokens)*price.Input +
+			float64(u.CacheCreationTokens)*price.CacheWrite +
+			float64(u.CacheReadTokens)*price.CacheRead +
+			float64(u.OutputTokens)*price.Output
+	}
+	return cost / 1e6
+}
@@ -0,0 +1,54 @@
+package agent
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPriceForModel(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		model     string
+		wantInput float64
+		wantOK    bool
+	}{
+		{"claude-sonnet-4-5-20250929", 3, true},
+		{"claude-opus-4-5-20251101", 5, true},
+		{"claude-opus-4-1-20250805", 15, true},
+		{"anthropic/Claude-Haiku-4-5", 1, true},
+		{"gpt-5-mini", 0.25, true},
+		{"gpt-5-codex", 1.25, true},
+		{"gemini-2.5-flash-lite", 0.1, true},
+		{"llama-3-70b", 0, false},
+		{"", 0, false},
+	}
+	for _, tt := range tests {
+		price, ok := PriceForModel(tt.model)
+		if ok != tt.wantOK || price.Input != tt.wantInput {
+			t.Errorf("PriceForModel(%q) = %+v, %v; want input %v, %v", tt.model, price, ok, tt.wantInput, tt.wantOK)
+		}
+	}
+}
+
+func TestEstimateCost(t *testing.T) {
+	t.Parallel()
+
+	usage := &TokenUsage{
+		InputTokens:         1_000_000,
+		CacheCreationTokens: 1_000_000,
+		CacheReadTokens:     1_000_000,
+		OutputTokens:        1_000_000,
+		SubagentTokens:      &TokenUsage{OutputTokens: 100_000},
+	}
+	// 3 + 3.75 + 0.3 + 15, plus 1.5 for the subagent's output
+	if got := EstimateCost("claude-sonnet-4-5", usage); math.Abs(got-23.55) > 1e-9 {
+		t.Errorf("EstimateCost() = %v, want 23.55", got)
+	}
+	if got := EstimateCost("unknown-model", usage); got != 0 {
+		t.Errorf("EstimateCost(unknown) = %v, want 0", got)
+	}
+	if got := EstimateCost("claude-sonnet-4-5", nil); got != 0 {
+		t.Errorf("EstimateCost(nil) = %v, want 0", got)
+	}
+}
@@ -0,0 +1,181 @@
+// Package wrapped implements the Agent interface for agents launched through
+// `entire exec`. Such agents have no hook system, so the wrapper drives the
+// session lifecycle itself and writes a JSONL transcript of what it observed:
+// when the process started, each periodic snapshot, and how it exited.
+//
+// The agent is not registered: it can't be enabled or detected, and only
+// exists for the duration of a wrapped run.
+package wrapped
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/entireio/cli/cmd/entire/cli/agent"
+	"github.com/entireio/cli/cmd/entire/cli/agent/types"
+)
+
+// AgentName is the name of wrapped agents.
+const AgentName types.AgentName = "exec"
+
+// Record types written to the transcript.
+const (
+	RecordStart    = "start"
+	RecordSnapshot = "snapshot"
+	RecordExit     = "exit"
+)
+
+// Record is one line of a wrapped agent's transcript.
+type Record struct {
+	Type      string    `json:"type"`
+	Timestamp time.Time `json:"timestamp"`
+
+	// Command is the wrapped command line (start records).
+	Command []string `json:"command,omitempty"`
+
+	// ExitCode is the process exit code, -1 if it was killed by a signal (exit records).
+	ExitCode int `json:"exit_code,omitempty"`
+
+	// Signal names the signal that killed the process, if any (exit records).
+	Signal string `json:"signal,omitempty"`
+
+	// Crashed is true when the process didn't exit cleanly (exit records).
+	Crashed bool `json:"crashed,omitempty"`
+}
+
+// AppendRecord appends a record to the transcript at path.
+func AppendRecord(path string, record Record) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to encode transcript record: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return fmt.Errorf("failed to create transcript directory: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600) //nolint:gosec // path is under .entire/tmp
+	if err != nil {
+		return fmt.Errorf("failed to open transcript: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write transcript: %w", err)
+	}
+	return nil
+}
+
+// WrappedAgent is an agent process supervised by `entire exec`.
+//
+//nolint:revive // WrappedAgent is clearer than Agent in this context
+type WrappedAgent struct {
+	command string
+}
+
+// NewWrappedAgent creates an agent for the given command. The command's base
+// name becomes the agent type, so sessions are attributed to e.g. "aider".
+func NewWrappedAgent(command string) *WrappedAgent {
+	name := filepath.Base(command)
+	if strings.EqualFold(filepath.Ext(name), ".exe") {
+		name = strings.TrimSuffix(name, filepath.Ext(name))
+	}
+	if name == "" || name == "." {
+		name = string(AgentName)
+	}
+	return &WrappedAgent{command: name}
+}
+
+// Name returns the agent registry key.
+func (w *WrappedAgent) Name() types.AgentName {
+	return AgentName
+}
+
+// Type returns the wrapped command's name as the agent type.
+func (w *WrappedAgent) Type() types.AgentType {
+	return types.AgentType(w.command)
+}
+
+// Description returns a human-readable description.
+func (w *WrappedAgent) Description() string {
+	return w.command + " - run through entire exec"
+}
+
+func (w *WrappedAgent) IsPreview() bool { return true }
+
+// DetectPresence always reports false: wrapped agents are launched explicitly.
+func (w *WrappedAgent) DetectPresence(_ context.Context) (bool, error) {
+	return false, nil
+}
+
+// ProtectedDirs returns nil; the wrapper doesn't know the agent's config directories.
+func (w *WrappedAgent) ProtectedDirs() []string { return nil }
+
+// ReadTranscript reads the wrapper's transcript.
+func (w *WrappedAgent) ReadTranscript(sessionRef string) ([]byte, error) {
+	data, err := os.ReadFile(sessionRef) //nolint:gosec // path is under .entire/tmp
+	if err != nil {
+		return nil, fmt.Errorf("failed to read transcript: %w", err)
+	}
+	return data, nil
+}
+
+// ChunkTranscript splits a JSONL transcript at line boundaries.
+func (w *WrappedAgent) ChunkTranscript(_ context.Context, content []byte, maxSize int) ([][]byte, error) {
+	chunks, err := agent.ChunkJSONL(content, maxSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to chunk JSONL transcript: %w", err)
+	}
+	return chunks, nil
+}
+
+// ReassembleTranscript concatenates JSONL chunks with newlines.
+func (w *WrappedAgent) ReassembleTranscript(chunks [][]byte) ([]byte, error) {
+	return agent.ReassembleJSONL(chunks), nil
+}
+
+// GetSessionID extracts the session ID from hook input.
+func (w *WrappedAgent) GetSessionID(input *agent.HookInput) string {
+	return input.SessionID
+}
+
+// GetSessionDir is not supported: the transcript lives wherever the wrapper put it.
+func (w *WrappedAgent) GetSessionDir(_ string) (string, error) {
+	return "", errors.New("wrapped agents have no session directory")
+}
+
+// ResolveSessionFile returns the transcript path for a session in sessionDir.
+func (w *WrappedAgent) ResolveSessionFile(sessionDir, agentSessionID string) string {
+	return filepath.Join(sessionDir, agentSessionID+".jsonl")
+}
+
+// ReadSession reads the wrapper's transcript as the session data.
+func (w *WrappedAgent) ReadSession(input *agent.HookInput) (*agent.AgentSession, error) {
+	if input.SessionRef == "" {
+		return nil, errors.New("session reference (transcript path) is required")
+	}
+	data, err := w.ReadTranscript(input.SessionRef)
+	if err != nil {
+		return nil, err
+	}
+	return &agent.AgentSession{
+		SessionID:  input.SessionID,
+		AgentName:  w.Name(),
+		SessionRef: input.SessionRef,
+		StartTime:  time.Now(),
+		NativeData: data,
+	}, nil
+}
+
+// WriteSession is not supported: a wrapped process can't be resumed.
+func (w *WrappedAgent) WriteSession(_ context.Context, _ *agent.AgentSession) error {
+	return errors.New("wrapped agent sessions can't be restored")
+}
+
+// FormatResumeCommand returns an instruction to start the agent again.
+func (w *WrappedAgent) FormatResumeCommand(_ string) string {
+	return "Run 'entire exec -- " + w.command + "' to start a new session."
+}
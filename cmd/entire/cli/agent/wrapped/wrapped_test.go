@@ -0,0 +1,55 @@
+package wrapped
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/entireio/cli/cmd/entire/cli/agent/types"
+)
+
+func TestNewWrappedAgent_TypeFromCommand(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		command string
+		want    types.AgentType
+	}{
+		{command: "aider", want: "aider"},
+		{command: "/usr/local/bin/goose", want: "goose"},
+		{command: "python3.12", want: "python3.12"},
+		{command: "bin/codex.exe", want: "codex"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.command, func(t *testing.T) {
+			t.Parallel()
+			if got := NewWrappedAgent(filepath.FromSlash(tt.command)).Type(); got != tt.want {
+				t.Errorf("Type() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAppendRecord(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "tmp", "session.jsonl")
+	ag := NewWrappedAgent("aider")
+
+	start := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	if err := AppendRecord(path, Record{Type: RecordStart, Timestamp: start, Command: []string{"aider", "--yes"}}); err != nil {
+		t.Fatalf("AppendRecord() error = %v", err)
+	}
+	if err := AppendRecord(path, Record{Type: RecordExit, Timestamp: start, ExitCode: -1, Signal: "killed", Crashed: true}); err != nil {
+		t.Fatalf("AppendRecord() error = %v", err)
+	}
+
+	data, err := ag.ReadTranscript(path)
+	if err != nil {
+		t.Fatalf("ReadTranscript() error = %v", err)
+	}
+	want := `{"type":"start","timestamp":"2026-01-02T03:04:05Z","command":["aider","--yes"]}` + "\n" +
+		`{"type":"exit","timestamp":"2026-01-02T03:04:05Z","exit_code":-1,"signal":"killed","crashed":true}` + "\n"
+	if string(data) != want {
+		t.Errorf("transcript = %q, want %q", data, want)
+	}
+}
@@ -0,0 +1,165 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+	"github.com/entireio/cli/cmd/entire/cli/strategy"
+
+	"github.com/sergi/go-diff/diffmatchpatch"
+	"github.com/spf13/cobra"
+)
+
+func newAgentConfigCmd() *cobra.Command {
+	var diffFlag bool
+
+	cmd := &cobra.Command{
+		Use:   "agent-config [path]",
+		Short: "Show when agent config files changed between checkpoints",
+		Long: `Agent-config lists the checkpoints at which the agent's config files
+(CLAUDE.md, .cursorrules, agent settings, ...) changed, oldest first, so you
+can see which instructions a session ran under.
+
+Config files are only stored in checkpoints when "snapshot_agent_config" is
+enabled in .entire/settings.json. Pass a path to follow a single file, and
+--diff to show the changed lines.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			if _, err := paths.WorktreeRoot(ctx); err != nil {
+				return paths.ErrNotGitRepository
+			}
+			var pathFilter string
+			if len(args) == 1 {
+				pathFilter = args[0]
+			}
+			return runAgentConfig(ctx, cmd.OutOrStdout(), pathFilter, diffFlag)
+		},
+	}
+
+	cmd.Flags().BoolVar(&diffFlag, "diff", false, "Show the changed lines of each file")
+
+	return cmd
+}
+
+func runAgentConfig(ctx context.Context, w io.Writer, pathFilter string, showDiff bool) error {
+	repo, err := openRepository(ctx)
+	if err != nil {
+		return err
+	}
+	store, err := strategy.CommittedStore(ctx, checkpoint.NewGitStore(repo))
+	if err != nil {
+		return err //nolint:wrapcheck // already names the setting
+	}
+
+	committed, err := store.ListCommitted(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list checkpoints: %w", err)
+	}
+	// Oldest first, so each checkpoint is compared with the one before it
+	sort.SliceStable(committed, func(i, j int) bool {
+		return committed[i].CreatedAt.Before(committed[j].CreatedAt)
+	})
+
+	var previous map[string][]byte
+	changes := 0
+	for _, info := range committed {
+		if info.SessionCount == 0 {
+			continue
+		}
+		current, err := store.ReadAgentConfig(ctx, info.CheckpointID, info.SessionCount-1)
+		if err != nil || len(current) == 0 {
+			continue
+		}
+		if pathFilter != "" {
+			filtered := make(map[string][]byte)
+			if content, ok := current[pathFilter]; ok {
+				filtered[pathFilter] = content
+			}
+			current = filtered
+		}
+
+		changed := changedConfigPaths(previous, current)
+		previous = current
+		if len(changed) == 0 {
+			continue
+		}
+
+		if changes > 0 {
+			fmt.Fprintln(w)
+		}
+		changes++
+		fmt.Fprintf(w, "%s  %s  session %s\n", info.CheckpointID, info.CreatedAt.Local().Format("2006-01-02 15:04:05"), info.SessionID)
+		for _, change := range changed {
+			fmt.Fprintf(w, "  %s %s\n", change.status, change.path)
+			if showDiff {
+				writeConfigDiff(w, change.before, change.after)
+			}
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return err //nolint:wrapcheck // Propagating context cancellation
+	}
+
+	if changes == 0 {
+		fmt.Fprintln(w, "No agent config snapshots found. Enable \"snapshot_agent_config\" in .entire/settings.json to record them.")
+	}
+	return nil
+}
+
+// configChange is one file that differs between two config snapshots.
+type configChange struct {
+	path          string
+	status        string // "A" added, "M" modified, "D" deleted
+	before, after []byte
+}
+
+// changedConfigPaths returns the files that differ between two snapshots, sorted by path.
+// A nil previous snapshot reports every current file as added.
+func changedConfigPaths(previous, current map[string][]byte) []configChange {
+	var changes []configChange
+	for path, after := range current {
+		before, existed := previous[path]
+		switch {
+		case !existed:
+			changes = append(changes, configChange{path: path, status: "A", after: after})
+		case !bytes.Equal(before, after):
+			changes = append(changes, configChange{path: path, status: "M", before: before, after: after})
+		}
+	}
+	for path, before := range previous {
+		if _, ok := current[path]; !ok {
+			changes = append(changes, configChange{path: path, status: "D", before: before})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].path < changes[j].path })
+	return changes
+}
+
+// writeConfigDiff prints the added and removed lines between two file versions.
+func writeConfigDiff(w io.Writer, before, after []byte) {
+	dmp := diffmatchpatch.New()
+	text1, text2, lineArray := dmp.DiffLinesToChars(string(before), string(after))
+	diffs := dmp.DiffCharsToLines(dmp.DiffMain(text1, text2, false), lineArray)
+
+	for _, d := range diffs {
+		var prefix string
+		switch d.Type {
+		case diffmatchpatch.DiffInsert:
+			prefix = "+"
+		case diffmatchpatch.DiffDelete:
+			prefix = "-"
+		case diffmatchpatch.DiffEqual:
+			continue
+		}
+		for _, line := range strings.Split(strings.TrimSuffix(d.Text, "\n"), "\n") {
+			fmt.Fprintf(w, "      %s %s\n", prefix, line)
+		}
+	}
+}
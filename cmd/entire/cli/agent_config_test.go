@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+)
+
+func TestRunAgentConfig(t *testing.T) {
+	setupCleanTestRepo(t)
+	ctx := context.Background()
+
+	var stdout bytes.Buffer
+	if err := runAgentConfig(ctx, &stdout, "", false); err != nil {
+		t.Fatalf("runAgentConfig() error = %v", err)
+	}
+	if !strings.Contains(stdout.String(), "snapshot_agent_config") {
+		t.Errorf("expected hint to enable snapshots, got:\n%s", stdout.String())
+	}
+
+	repo, err := openRepository(ctx)
+	if err != nil {
+		t.Fatalf("openRepository() error = %v", err)
+	}
+	store := checkpoint.NewGitStore(repo)
+	for _, cp := range []struct {
+		id     string
+		config map[string][]byte
+	}{
+		{"d1d1d1d1d1d1", map[string][]byte{"CLAUDE.md": []byte("Use tabs.\n"), ".cursorrules": []byte("Be brief.\n")}},
+		{"d2d2d2d2d2d2", map[string][]byte{"CLAUDE.md": []byte("Use tabs.\n"), ".cursorrules": []byte("Be brief.\n")}},
+		{"d3d3d3d3d3d3", map[string][]byte{"CLAUDE.md": []byte("Use spaces.\n")}},
+	} {
+		if err := store.WriteCommitted(ctx, checkpoint.WriteCommittedOptions{
+			CheckpointID: id.MustCheckpointID(cp.id),
+			SessionID:    "session-" + cp.id,
+			Strategy:     "manual-commit",
+			Transcript:   []byte(`{"type":"user"}` + "\n"),
+			AuthorName:   "Test",
+			AuthorEmail:  "test@test.com",
+			AgentConfig:  cp.config,
+		}); err != nil {
+			t.Fatalf("WriteCommitted() error = %v", err)
+		}
+	}
+
+	stdout.Reset()
+	if err := runAgentConfig(ctx, &stdout, "", true); err != nil {
+		t.Fatalf("runAgentConfig() error = %v", err)
+	}
+	out := stdout.String()
+	for _, want := range []string{"d1d1d1d1d1d1", "A CLAUDE.md", "d3d3d3d3d3d3", "M CLAUDE.md", "D .cursorrules", "- Use tabs.", "+ Use spaces."} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "d2d2d2d2d2d2") {
+		t.Errorf("checkpoint without config changes should not be listed:\n%s", out)
+	}
+
+	stdout.Reset()
+	if err := runAgentConfig(ctx, &stdout, ".cursorrules", false); err != nil {
+		t.Fatalf("runAgentConfig() error = %v", err)
+	}
+	if out := stdout.String(); strings.Contains(out, "CLAUDE.md") || !strings.Contains(out, "D .cursorrules") {
+		t.Errorf("expected only .cursorrules changes, got:\n%s", out)
+	}
+}
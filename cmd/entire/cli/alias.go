@@ -0,0 +1,190 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/entireio/cli/cmd/entire/cli/settings"
+
+	"github.com/spf13/cobra"
+)
+
+// Alias is a user-defined shortcut from the "alias" setting, expanded like a
+// git alias.
+type Alias struct {
+	// Name is the alias the user typed
+	Name string
+
+	// Args are the entire arguments the alias expands to, followed by the
+	// arguments given after it. Empty for a shell alias.
+	Args []string
+
+	// Shell is the command line of an alias starting with "!", run by the
+	// shell with the arguments given after the alias as "$@"
+	Shell string
+
+	// ShellArgs are the arguments given after a shell alias
+	ShellArgs []string
+}
+
+// ResolveAlias expands the alias named by args[0]. It returns nil if args
+// doesn't start with an alias. As in git, an alias can't replace a built-in
+// command: one with a command's name is ignored. Aliases may refer to other
+// aliases.
+func ResolveAlias(ctx context.Context, root *cobra.Command, args []string) (*Alias, error) {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") || isBuiltinCommand(root, args[0]) {
+		return nil, nil
+	}
+	s, err := settings.Load(ctx)
+	if err != nil || len(s.Aliases) == 0 {
+		// Let the command report unknown commands and broken settings
+		return nil, nil //nolint:nilerr // not an alias as far as we can tell
+	}
+	return expandAlias(root, s.Aliases, args)
+}
+
+func expandAlias(root *cobra.Command, aliases map[string]string, args []string) (*Alias, error) {
+	name := args[0]
+	seen := []string{}
+	for {
+		value, ok := aliases[args[0]]
+		if !ok || isBuiltinCommand(root, args[0]) {
+			if len(seen) == 0 {
+				return nil, nil
+			}
+			return &Alias{Name: name, Args: args}, nil
+		}
+		for _, s := range seen {
+			if s == args[0] {
+				return nil, fmt.Errorf("alias loop: %s -> %s", strings.Join(seen, " -> "), args[0])
+			}
+		}
+		seen = append(seen, args[0])
+
+		if shell, ok := strings.CutPrefix(value, "!"); ok {
+			return &Alias{Name: name, Shell: shell, ShellArgs: args[1:]}, nil
+		}
+		words, err := splitAliasWords(value)
+		if err != nil {
+			return nil, fmt.Errorf("alias %s: %w", args[0], err)
+		}
+		if len(words) == 0 {
+			return nil, fmt.Errorf("alias %s is empty", args[0])
+		}
+		args = append(words, args[1:]...)
+	}
+}
+
+// isBuiltinCommand reports whether name is a command or command alias of
+// root, including the help and completion commands cobra adds on execution.
+func isBuiltinCommand(root *cobra.Command, name string) bool {
+	if name == "help" || (name == "completion" && !root.CompletionOptions.DisableDefaultCmd) {
+		return true
+	}
+	for _, c := range root.Commands() {
+		if c.Name() == name || c.HasAlias(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// splitAliasWords splits an alias value into words like a shell would:
+// whitespace separates words, quotes group them, and a backslash escapes the
+// next character outside single quotes.
+func splitAliasWords(value string) ([]string, error) {
+	var words []string
+	var word strings.Builder
+	inWord := false
+	var quote rune
+	escaped := false
+	for _, r := range value {
+		switch {
+		case escaped:
+			word.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped = true
+			inWord = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inWord = true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote", quote)
+	}
+	if escaped {
+		return nil, errors.New("trailing backslash")
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
+
+// RunShell runs a shell alias with the terminal's stdio and returns its exit
+// code. Like git, the alias's arguments are appended as "$@".
+func (a *Alias) RunShell(ctx context.Context) int {
+	sh, err := findShell()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "alias %s: %v\n", a.Name, err)
+		return 1
+	}
+	shellArgs := append([]string{"-c", a.Shell + ` "$@"`, a.Name}, a.ShellArgs...)
+	cmd := exec.CommandContext(ctx, sh, shellArgs...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return exitErr.ExitCode()
+		}
+		fmt.Fprintf(os.Stderr, "alias %s: %v\n", a.Name, err)
+		return 1
+	}
+	return 0
+}
+
+// findShell returns the sh that runs shell aliases. On Windows sh is rarely
+// on PATH, so like git it falls back to the sh bundled with Git for Windows,
+// which also runs Entire's git hooks there.
+func findShell() (string, error) {
+	if sh, err := exec.LookPath("sh"); err == nil {
+		return sh, nil
+	}
+	if runtime.GOOS == "windows" {
+		if git, err := exec.LookPath("git"); err == nil {
+			// git.exe is in <install>\cmd or <install>\bin
+			root := filepath.Dir(filepath.Dir(git))
+			for _, sh := range []string{filepath.Join(root, "bin", "sh.exe"), filepath.Join(root, "usr", "bin", "sh.exe")} {
+				if fileExists(sh) {
+					return sh, nil
+				}
+			}
+		}
+	}
+	return "", errors.New("shell aliases need sh, which was not found (on Windows, install Git for Windows)")
+}
@@ -0,0 +1,140 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestExpandAlias(t *testing.T) {
+	t.Parallel()
+
+	root := NewRootCmd()
+	aliases := map[string]string{
+		"rw":     "rewind --list",
+		"last":   "rw --to HEAD",
+		"status": "explain", // shadows a built-in command
+		"up":     "!git pull",
+		"loop1":  "loop2",
+		"loop2":  "loop1 --flag",
+	}
+
+	tests := []struct {
+		name     string
+		args     []string
+		wantArgs []string
+		wantNil  bool
+	}{
+		{name: "simple", args: []string{"rw"}, wantArgs: []string{"rewind", "--list"}},
+		{name: "extra args appended", args: []string{"rw", "--abort"}, wantArgs: []string{"rewind", "--list", "--abort"}},
+		{name: "chained", args: []string{"last"}, wantArgs: []string{"rewind", "--list", "--to", "HEAD"}},
+		{name: "built-in wins", args: []string{"status"}, wantNil: true},
+		{name: "not an alias", args: []string{"nope"}, wantNil: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			alias, err := expandAlias(root, aliases, tt.args)
+			if err != nil {
+				t.Fatalf("expandAlias() error = %v", err)
+			}
+			if tt.wantNil {
+				if alias != nil {
+					t.Errorf("expandAlias() = %+v, want nil", alias)
+				}
+				return
+			}
+			if alias == nil || !slices.Equal(alias.Args, tt.wantArgs) {
+				t.Errorf("expandAlias() = %+v, want args %v", alias, tt.wantArgs)
+			}
+		})
+	}
+
+	t.Run("shell", func(t *testing.T) {
+		t.Parallel()
+		alias, err := expandAlias(root, aliases, []string{"up", "--rebase"})
+		if err != nil {
+			t.Fatalf("expandAlias() error = %v", err)
+		}
+		if alias.Shell != "git pull" || !slices.Equal(alias.ShellArgs, []string{"--rebase"}) {
+			t.Errorf("expandAlias() = %+v, want shell alias", alias)
+		}
+	})
+
+	t.Run("loop", func(t *testing.T) {
+		t.Parallel()
+		_, err := expandAlias(root, aliases, []string{"loop1"})
+		if err == nil || !strings.Contains(err.Error(), "alias loop: loop1 -> loop2 -> loop1") {
+			t.Errorf("expandAlias() error = %v, want alias loop", err)
+		}
+	})
+}
+
+func TestSplitAliasWords(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		value string
+		want  []string
+	}{
+		{`rewind --list`, []string{"rewind", "--list"}},
+		{`  log   -n 5 `, []string{"log", "-n", "5"}},
+		{`comment "needs a test" 'it''s'`, []string{"comment", "needs a test", "its"}},
+		{`a\ b "c \"d\"" 'e\f'`, []string{"a b", `c "d"`, `e\f`}},
+		{`x ""`, []string{"x", ""}},
+	}
+	for _, tt := range tests {
+		got, err := splitAliasWords(tt.value)
+		if err != nil {
+			t.Errorf("splitAliasWords(%q) error = %v", tt.value, err)
+			continue
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("splitAliasWords(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+
+	for _, bad := range []string{`"unterminated`, `trailing\`} {
+		if _, err := splitAliasWords(bad); err == nil {
+			t.Errorf("splitAliasWords(%q) should fail", bad)
+		}
+	}
+}
+
+func TestResolveAlias_FromSettings(t *testing.T) {
+	dir := setupExecTestRepo(t)
+	writeSettings(t, `{"enabled": true, "alias": {"rw": "rewind --list", "pwn": "!touch pwned"}}`)
+	local := `{"alias": {"touch": "!touch \"$1\".out"}}`
+	if err := os.WriteFile(filepath.Join(dir, ".entire", "settings.local.json"), []byte(local), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	root := NewRootCmd()
+
+	// Shell aliases in the committed settings are ignored, as in git
+	if alias, err := ResolveAlias(ctx, root, []string{"pwn"}); err != nil || alias != nil {
+		t.Errorf("ResolveAlias(pwn) = %+v, %v; want no alias from the committed settings", alias, err)
+	}
+
+	alias, err := ResolveAlias(ctx, root, []string{"rw"})
+	if err != nil {
+		t.Fatalf("ResolveAlias() error = %v", err)
+	}
+	if alias == nil || !slices.Equal(alias.Args, []string{"rewind", "--list"}) {
+		t.Errorf("ResolveAlias() = %+v, want rewind --list", alias)
+	}
+
+	alias, err = ResolveAlias(ctx, root, []string{"touch", "marker"})
+	if err != nil {
+		t.Fatalf("ResolveAlias() error = %v", err)
+	}
+	if code := alias.RunShell(ctx); code != 0 {
+		t.Fatalf("RunShell() = %d, want 0", code)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "marker.out")); err != nil {
+		t.Errorf("shell alias didn't run with its arguments: %v", err)
+	}
+}
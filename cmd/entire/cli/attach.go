@@ -0,0 +1,162 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+	"github.com/entireio/cli/cmd/entire/cli/strategy"
+	"github.com/entireio/cli/cmd/entire/cli/validation"
+
+	"github.com/spf13/cobra"
+)
+
+func newAttachCmd() *cobra.Command {
+	var sessionFlag string
+
+	cmd := &cobra.Command{
+		Use:   "attach <file>...",
+		Short: "Attach screenshots or logs to the session's next checkpoint",
+		Long: `Attach stages files, such as screenshots or build logs, to be stored with a
+session's next checkpoint under its attachments/ directory. The content type
+is recorded in the checkpoint metadata and shown by 'entire explain'.
+
+Attachments default to the most recently active session in this worktree.
+Each file may be up to 10 MiB, and a session's attachments up to 25 MiB.
+Text attachments are redacted like transcripts; binary attachments are
+stored as given. Attachments are never treated as prompts, transcript, or
+context.`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			if _, err := paths.WorktreeRoot(ctx); err != nil {
+				cmd.SilenceUsage = true
+				return paths.ErrNotGitRepository
+			}
+			return runAttach(ctx, cmd.OutOrStdout(), sessionFlag, args)
+		},
+	}
+
+	cmd.Flags().StringVar(&sessionFlag, "session", "", "Session to attach to (default: most recently active)")
+
+	return cmd
+}
+
+func runAttach(ctx context.Context, w io.Writer, sessionID string, files []string) error {
+	if sessionID == "" {
+		state, err := mostRecentActiveSession(ctx)
+		if err != nil {
+			return err
+		}
+		sessionID = state.SessionID
+	} else if err := validation.ValidateSessionID(sessionID); err != nil {
+		return fmt.Errorf("invalid session ID: %w", err)
+	}
+
+	dir, err := strategy.PendingAttachmentsDir(ctx, sessionID)
+	if err != nil {
+		return err //nolint:wrapcheck // already wrapped
+	}
+	staged, err := stagedAttachmentsBytes(dir)
+	if err != nil {
+		return err
+	}
+
+	// Check every file before copying any, so a bad argument stages nothing
+	type pending struct {
+		src, name string
+		size      int64
+	}
+	var toStage []pending
+	for _, file := range files {
+		name := filepath.Base(file)
+		if err := checkpoint.ValidateAttachmentName(name); err != nil {
+			return err //nolint:wrapcheck // message names the file
+		}
+		info, err := os.Stat(file)
+		if err != nil {
+			return fmt.Errorf("cannot attach %s: %w", file, err)
+		}
+		if !info.Mode().IsRegular() {
+			return fmt.Errorf("cannot attach %s: not a regular file", file)
+		}
+		if info.Size() > checkpoint.MaxAttachmentBytes {
+			return fmt.Errorf("cannot attach %s: %d bytes is over the %d byte limit", file, info.Size(), checkpoint.MaxAttachmentBytes)
+		}
+		// Re-attaching a file replaces the staged copy
+		if existing, statErr := os.Stat(filepath.Join(dir, name)); statErr == nil {
+			staged -= existing.Size()
+		}
+		staged += info.Size()
+		if staged > checkpoint.MaxSessionAttachmentsBytes {
+			return fmt.Errorf("cannot attach %s: session attachments would exceed %d bytes", file, checkpoint.MaxSessionAttachmentsBytes)
+		}
+		toStage = append(toStage, pending{src: file, name: name, size: info.Size()})
+	}
+
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return fmt.Errorf("failed to create attachments directory: %w", err)
+	}
+	for _, p := range toStage {
+		data, err := os.ReadFile(p.src)
+		if err != nil {
+			return fmt.Errorf("cannot attach %s: %w", p.src, err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, p.name), data, 0o600); err != nil {
+			return fmt.Errorf("failed to stage %s: %w", p.src, err)
+		}
+		fmt.Fprintf(w, "Attached %s (%s, %d bytes) to session %s\n",
+			p.name, checkpoint.DetectContentType(p.name, data), p.size, sessionID)
+	}
+	fmt.Fprintln(w, "Attachments are stored with the session's next checkpoint.")
+	return nil
+}
+
+// mostRecentActiveSession returns the session in this worktree that hasn't
+// ended and was active most recently.
+func mostRecentActiveSession(ctx context.Context) (*strategy.SessionState, error) {
+	repoRoot, err := paths.WorktreeRoot(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get worktree root: %w", err)
+	}
+	states, err := strategy.ListSessionStates(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list session states: %w", err)
+	}
+	var latest *strategy.SessionState
+	for _, state := range states {
+		if state.EndedAt != nil || state.WorktreePath != repoRoot {
+			continue
+		}
+		if latest == nil || lastSessionActivity(state).After(lastSessionActivity(latest)) {
+			latest = state
+		}
+	}
+	if latest == nil {
+		return nil, errors.New("no active session in this worktree: pass --session")
+	}
+	return latest, nil
+}
+
+// stagedAttachmentsBytes returns the total size of files already staged in dir.
+func stagedAttachmentsBytes(dir string) (int64, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read attachments directory: %w", err)
+	}
+	var total int64
+	for _, entry := range entries {
+		if info, err := entry.Info(); err == nil && entry.Type().IsRegular() {
+			total += info.Size()
+		}
+	}
+	return total, nil
+}
@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/strategy"
+)
+
+func TestRunAttach_StagesForMostRecentSession(t *testing.T) {
+	dir := setupExecTestRepo(t)
+	now := time.Now()
+	saveIdleSession(t, dir, "older-session", now.Add(-2*time.Hour))
+	saveIdleSession(t, dir, "recent-session", now.Add(-time.Minute))
+
+	src := filepath.Join(t.TempDir(), "build.log")
+	if err := os.WriteFile(src, []byte("FAIL: TestLogin\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := runAttach(context.Background(), &out, "", []string{src}); err != nil {
+		t.Fatalf("runAttach() error = %v", err)
+	}
+	if !strings.Contains(out.String(), "Attached build.log") || !strings.Contains(out.String(), "recent-session") {
+		t.Errorf("unexpected output: %s", out.String())
+	}
+
+	stagingDir, err := strategy.PendingAttachmentsDir(context.Background(), "recent-session")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filepath.Join(stagingDir, "build.log"))
+	if err != nil || string(data) != "FAIL: TestLogin\n" {
+		t.Errorf("staged file = %q, %v", data, err)
+	}
+}
+
+func TestRunAttach_RejectsBeforeStaging(t *testing.T) {
+	dir := setupExecTestRepo(t)
+	saveIdleSession(t, dir, "attach-session", time.Now())
+
+	tmp := t.TempDir()
+	ok := filepath.Join(tmp, "ok.txt")
+	if err := os.WriteFile(ok, []byte("ok"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	huge := filepath.Join(tmp, "huge.bin")
+	if err := os.WriteFile(huge, make([]byte, checkpoint.MaxAttachmentBytes+1), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	err := runAttach(context.Background(), &bytes.Buffer{}, "attach-session", []string{ok, huge})
+	if err == nil || !strings.Contains(err.Error(), "byte limit") {
+		t.Fatalf("runAttach() error = %v, want size limit error", err)
+	}
+	stagingDir, err := strategy.PendingAttachmentsDir(context.Background(), "attach-session")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(stagingDir, "ok.txt")); !os.IsNotExist(err) {
+		t.Error("no file should be staged when any argument is rejected")
+	}
+}
+
+func TestRunAttach_NoActiveSession(t *testing.T) {
+	setupExecTestRepo(t)
+	src := filepath.Join(t.TempDir(), "a.txt")
+	if err := os.WriteFile(src, []byte("a"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	err := runAttach(context.Background(), &bytes.Buffer{}, "", []string{src})
+	if err == nil || !strings.Contains(err.Error(), "no active session") {
+		t.Errorf("runAttach() error = %v, want no active session", err)
+	}
+}
@@ -0,0 +1,266 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+	"github.com/entireio/cli/cmd/entire/cli/strategy"
+	"github.com/entireio/cli/cmd/entire/cli/transcript"
+	"github.com/spf13/cobra"
+)
+
+func newAuditLogCmd() *cobra.Command {
+	var operationFlag string
+	var limitFlag int
+	var jsonFlag bool
+	var permissionsFlag bool
+	var deniedFlag bool
+	var flaggedFlag bool
+
+	cmd := &cobra.Command{
+		Use:     "audit-log",
+		Aliases: []string{"audit"},
+		Short:   "Show the log of destructive operations",
+		Long: `Show the audit log of destructive operations recorded on the
+entire/checkpoints/v1 branch.
+
+Every reset, rewind, clean, gc, context compaction, and session purge appends an
+entry recording who ran it, when, which refs it acted on, and what it removed.
+Commands run past .entire/policy.json with an override token are recorded too.
+Entries are never rewritten, and they travel with the metadata branch when it
+is pushed. Purge entries are signed when ENTIRE_AUDIT_SIGNING_KEY is set.
+
+Entries are shown newest first. Use --operation to filter by operation type
+(reset, rewind, clean, gc, compaction, purge, policy-override) and --limit to cap the number shown.
+
+With --permissions, show instead what agents were allowed to do: for each
+checkpoint session, newest first, the shell commands and web fetches the
+agent ran, the files it wrote outside the repository, and the tool calls that
+were denied, read from the transcript when the checkpoint was written.
+--denied shows only the denied calls. Checkpoints written by earlier versions
+have no permission events.
+
+--flagged shows only the sessions that "escape_detection" in settings marked
+for review, with the heuristics they tripped (outside_write, sudo, network).`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := cmd.Context()
+			if _, err := paths.WorktreeRoot(ctx); err != nil {
+				return paths.ErrNotGitRepository
+			}
+			if limitFlag < 0 {
+				return errors.New("--limit must not be negative")
+			}
+			if permissionsFlag {
+				if operationFlag != "" {
+					return errors.New("--operation can't be combined with --permissions")
+				}
+				return runAuditPermissions(ctx, cmd.OutOrStdout(), auditPermissionsOptions{
+					deniedOnly:  deniedFlag,
+					flaggedOnly: flaggedFlag,
+					limit:       limitFlag,
+					asJSON:      jsonFlag,
+				})
+			}
+			if deniedFlag || flaggedFlag {
+				return errors.New("--denied and --flagged need --permissions")
+			}
+			return runAuditLog(ctx, cmd.OutOrStdout(), operationFlag, limitFlag, jsonFlag)
+		},
+	}
+
+	cmd.Flags().StringVar(&operationFlag, "operation", "", "Only show entries for this operation")
+	cmd.Flags().IntVar(&limitFlag, "limit", 0, "Maximum number of entries to show (0 for all)")
+	cmd.Flags().BoolVar(&jsonFlag, "json", false, "Output entries as JSON")
+	cmd.Flags().BoolVar(&permissionsFlag, "permissions", false, "Show the commands, fetches, outside writes, and denied tool calls of agent sessions")
+	cmd.Flags().BoolVar(&deniedFlag, "denied", false, "With --permissions, only show denied tool calls")
+	cmd.Flags().BoolVar(&flaggedFlag, "flagged", false, "With --permissions, only show sessions flagged for review")
+
+	return cmd
+}
+
+// runAuditLog prints audit entries newest first, optionally filtered by operation.
+func runAuditLog(ctx context.Context, w io.Writer, operation string, limit int, asJSON bool) error {
+	repo, err := strategy.OpenRepository(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to open repository: %w", err)
+	}
+
+	entries, err := checkpoint.NewGitStore(repo).ReadAuditLog(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to read audit log: %w", err)
+	}
+
+	selected := make([]checkpoint.AuditEntry, 0, len(entries))
+	for i := len(entries) - 1; i >= 0; i-- {
+		if operation != "" && entries[i].Operation != operation {
+			continue
+		}
+		selected = append(selected, entries[i])
+		if limit > 0 && len(selected) >= limit {
+			break
+		}
+	}
+
+	if asJSON {
+		data, err := json.MarshalIndent(selected, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal audit log: %w", err)
+		}
+		fmt.Fprintln(w, string(data))
+		return nil
+	}
+
+	if len(selected) == 0 {
+		fmt.Fprintln(w, "No audit entries found.")
+		return nil
+	}
+
+	for _, entry := range selected {
+		actor := entry.ActorName
+		if entry.ActorEmail != "" {
+			actor = strings.TrimSpace(fmt.Sprintf("%s <%s>", entry.ActorName, entry.ActorEmail))
+		}
+		fmt.Fprintf(w, "%s  %-10s  %s\n", entry.Timestamp.Local().Format("2006-01-02 15:04:05"), entry.Operation, actor)
+		if len(entry.Refs) > 0 {
+			fmt.Fprintf(w, "  refs:    %s\n", strings.Join(entry.Refs, ", "))
+		}
+		for _, item := range entry.Removed {
+			fmt.Fprintf(w, "  removed: %s\n", item)
+		}
+		if entry.Details != "" {
+			fmt.Fprintf(w, "  details: %s\n", entry.Details)
+		}
+		if entry.Signature != "" {
+			fmt.Fprintln(w, "  signed:  ed25519")
+		}
+	}
+	return nil
+}
+
+// permissionAudit is the permission events of one checkpoint session.
+type permissionAudit struct {
+	CheckpointID string                      `json:"checkpoint_id"`
+	SessionID    string                      `json:"session_id"`
+	Agent        string                      `json:"agent,omitempty"`
+	Branch       string                      `json:"branch,omitempty"`
+	CreatedAt    time.Time                   `json:"created_at"`
+	Permissions  []transcript.ToolPermission `json:"permissions"`
+	EscapeFlags  []transcript.EscapeFlag     `json:"escape_flags,omitempty"`
+}
+
+// auditPermissionsOptions selects what runAuditPermissions shows.
+type auditPermissionsOptions struct {
+	deniedOnly  bool // only denied tool calls
+	flaggedOnly bool // only sessions escape detection flagged
+	limit       int  // maximum number of sessions; 0 for all
+	asJSON      bool
+}
+
+// runAuditPermissions prints the permission events recorded in checkpoint
+// sessions, newest first.
+func runAuditPermissions(ctx context.Context, w io.Writer, opts auditPermissionsOptions) error {
+	repo, err := strategy.OpenRepository(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to open repository: %w", err)
+	}
+	store, err := strategy.CommittedStore(ctx, checkpoint.NewGitStore(repo))
+	if err != nil {
+		return err //nolint:wrapcheck // already names the setting
+	}
+	committed, err := store.ListCommitted(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list checkpoints: %w", err)
+	}
+
+	audits := []permissionAudit{}
+	for _, info := range committed {
+		metas, err := store.ReadSessionMetadata(ctx, info.CheckpointID)
+		if err != nil {
+			continue
+		}
+		for _, meta := range metas {
+			if opts.flaggedOnly && len(meta.EscapeFlags) == 0 {
+				continue
+			}
+			var events []transcript.ToolPermission
+			for _, event := range meta.Permissions {
+				if !opts.deniedOnly || event.Decision == transcript.PermissionDenied {
+					events = append(events, event)
+				}
+			}
+			if len(events) == 0 {
+				continue
+			}
+			audits = append(audits, permissionAudit{
+				CheckpointID: info.CheckpointID.String(),
+				SessionID:    meta.SessionID,
+				Agent:        string(meta.Agent),
+				Branch:       meta.Branch,
+				CreatedAt:    meta.CreatedAt,
+				Permissions:  events,
+				EscapeFlags:  meta.EscapeFlags,
+			})
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return err //nolint:wrapcheck // Propagating context cancellation
+	}
+	sort.SliceStable(audits, func(i, j int) bool { return audits[i].CreatedAt.After(audits[j].CreatedAt) })
+	if opts.limit > 0 && len(audits) > opts.limit {
+		audits = audits[:opts.limit]
+	}
+
+	if opts.asJSON {
+		data, err := json.MarshalIndent(audits, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal permission events: %w", err)
+		}
+		fmt.Fprintln(w, string(data))
+		return nil
+	}
+
+	if len(audits) == 0 {
+		fmt.Fprintln(w, "No permission events recorded.")
+		return nil
+	}
+
+	for i, audit := range audits {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		line := fmt.Sprintf("%s  %s  session %s", audit.CheckpointID, audit.CreatedAt.Local().Format("2006-01-02 15:04:05"), audit.SessionID)
+		if audit.Agent != "" {
+			line += " (" + audit.Agent + ")"
+		}
+		fmt.Fprintln(w, line)
+		for _, flag := range audit.EscapeFlags {
+			fmt.Fprintf(w, "  flagged  %s: %s\n", flag.Heuristic, flag.Detail)
+		}
+		for _, event := range audit.Permissions {
+			fmt.Fprintf(w, "  %-7s  %-8s  %s\n", event.Decision, event.Tool, permissionTarget(event))
+		}
+	}
+	return nil
+}
+
+// permissionTarget describes what a tool call acted on.
+func permissionTarget(event transcript.ToolPermission) string {
+	switch {
+	case event.Command != "":
+		return strings.Join(strings.Fields(event.Command), " ")
+	case event.OutsideRepo:
+		return event.Path + "  (outside the repository)"
+	case event.Path != "":
+		return event.Path
+	default:
+		return event.URL
+	}
+}
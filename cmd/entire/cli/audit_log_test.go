@@ -0,0 +1,162 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+	"github.com/entireio/cli/cmd/entire/cli/transcript"
+)
+
+func TestRunAuditLog_Empty(t *testing.T) {
+	setupCleanTestRepo(t)
+
+	var stdout bytes.Buffer
+	if err := runAuditLog(context.Background(), &stdout, "", 0, false); err != nil {
+		t.Fatalf("runAuditLog() error = %v", err)
+	}
+	if !strings.Contains(stdout.String(), "No audit entries found") {
+		t.Errorf("expected empty message, got: %s", stdout.String())
+	}
+}
+
+func TestRunAuditLog_FilterAndLimit(t *testing.T) {
+	repo, _ := setupCleanTestRepo(t)
+	store := checkpoint.NewGitStore(repo)
+	ctx := context.Background()
+
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	for i, op := range []string{checkpoint.AuditOpReset, checkpoint.AuditOpClean, checkpoint.AuditOpReset} {
+		if err := store.AppendAuditEntry(ctx, checkpoint.AuditEntry{
+			Timestamp: base.Add(time.Duration(i) * time.Minute),
+			Operation: op,
+			Removed:   []string{op + "-item"},
+		}); err != nil {
+			t.Fatalf("AppendAuditEntry() error = %v", err)
+		}
+	}
+
+	var stdout bytes.Buffer
+	if err := runAuditLog(ctx, &stdout, checkpoint.AuditOpReset, 1, true); err != nil {
+		t.Fatalf("runAuditLog() error = %v", err)
+	}
+
+	var got []checkpoint.AuditEntry
+	if err := json.Unmarshal(stdout.Bytes(), &got); err != nil {
+		t.Fatalf("failed to parse JSON output: %v\n%s", err, stdout.String())
+	}
+	if len(got) != 1 {
+		t.Fatalf("got %d entries, want 1", len(got))
+	}
+	if got[0].Operation != checkpoint.AuditOpReset || !got[0].Timestamp.Equal(base.Add(2*time.Minute)) {
+		t.Errorf("expected newest reset entry, got %+v", got[0])
+	}
+
+	stdout.Reset()
+	if err := runAuditLog(ctx, &stdout, "", 0, false); err != nil {
+		t.Fatalf("runAuditLog() error = %v", err)
+	}
+	output := stdout.String()
+	if strings.Count(output, "removed:") != 3 {
+		t.Errorf("expected 3 entries in text output, got:\n%s", output)
+	}
+	if strings.Index(output, "clean-item") > strings.LastIndex(output, "reset-item") {
+		t.Errorf("expected newest entries first, got:\n%s", output)
+	}
+}
+
+func TestRunAuditPermissions(t *testing.T) {
+	repo, _ := setupCleanTestRepo(t)
+	store := checkpoint.NewGitStore(repo)
+	ctx := context.Background()
+
+	var stdout bytes.Buffer
+	if err := runAuditPermissions(ctx, &stdout, auditPermissionsOptions{}); err != nil {
+		t.Fatalf("runAuditPermissions() error = %v", err)
+	}
+	if !strings.Contains(stdout.String(), "No permission events recorded") {
+		t.Errorf("expected empty message, got: %s", stdout.String())
+	}
+
+	for _, cp := range []struct {
+		id          string
+		permissions []transcript.ToolPermission
+		flags       []transcript.EscapeFlag
+	}{
+		{"d1d1d1d1d1d1", []transcript.ToolPermission{
+			{Tool: "Bash", Decision: transcript.PermissionAllowed, Command: "go test\n  ./..."},
+			{Tool: "Write", Decision: transcript.PermissionAllowed, Path: "/etc/hosts", OutsideRepo: true},
+		}, []transcript.EscapeFlag{{Heuristic: transcript.EscapeOutsideWrite, Detail: "/etc/hosts"}}},
+		{"d2d2d2d2d2d2", []transcript.ToolPermission{
+			{Tool: "Bash", Decision: transcript.PermissionDenied, Command: "rm -rf /"},
+		}, nil},
+		{"d3d3d3d3d3d3", nil, nil},
+	} {
+		if err := store.WriteCommitted(ctx, checkpoint.WriteCommittedOptions{
+			CheckpointID: id.MustCheckpointID(cp.id),
+			SessionID:    "session-" + cp.id,
+			Strategy:     "manual-commit",
+			Transcript:   []byte(`{"type":"user"}` + "\n"),
+			AuthorName:   "Test",
+			AuthorEmail:  "test@test.com",
+			Agent:        "Claude Code",
+			Permissions:  cp.permissions,
+			EscapeFlags:  cp.flags,
+		}); err != nil {
+			t.Fatalf("WriteCommitted() error = %v", err)
+		}
+	}
+
+	stdout.Reset()
+	if err := runAuditPermissions(ctx, &stdout, auditPermissionsOptions{}); err != nil {
+		t.Fatalf("runAuditPermissions() error = %v", err)
+	}
+	output := stdout.String()
+	for _, want := range []string{
+		"d1d1d1d1d1d1", "session session-d1d1d1d1d1d1 (Claude Code)",
+		"allowed  Bash      go test ./...",
+		"allowed  Write     /etc/hosts  (outside the repository)",
+		"denied   Bash      rm -rf /",
+		"flagged  outside_write: /etc/hosts",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q in output:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "d3d3d3d3d3d3") {
+		t.Errorf("sessions without events should be left out:\n%s", output)
+	}
+
+	stdout.Reset()
+	if err := runAuditPermissions(ctx, &stdout, auditPermissionsOptions{deniedOnly: true, asJSON: true}); err != nil {
+		t.Fatalf("runAuditPermissions() error = %v", err)
+	}
+	var got []permissionAudit
+	if err := json.Unmarshal(stdout.Bytes(), &got); err != nil {
+		t.Fatalf("failed to parse JSON output: %v\n%s", err, stdout.String())
+	}
+	if len(got) != 1 || got[0].CheckpointID != "d2d2d2d2d2d2" || len(got[0].Permissions) != 1 {
+		t.Errorf("--denied = %+v, want only the denied command", got)
+	}
+
+	stdout.Reset()
+	if err := runAuditPermissions(ctx, &stdout, auditPermissionsOptions{limit: 1, asJSON: true}); err != nil {
+		t.Fatalf("runAuditPermissions() error = %v", err)
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &got); err != nil || len(got) != 1 {
+		t.Errorf("--limit 1 = %+v, %v; want one session", got, err)
+	}
+
+	stdout.Reset()
+	if err := runAuditPermissions(ctx, &stdout, auditPermissionsOptions{flaggedOnly: true, asJSON: true}); err != nil {
+		t.Fatalf("runAuditPermissions() error = %v", err)
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &got); err != nil || len(got) != 1 || got[0].CheckpointID != "d1d1d1d1d1d1" || len(got[0].EscapeFlags) != 1 {
+		t.Errorf("--flagged = %+v, %v; want only the flagged session", got, err)
+	}
+}
@@ -0,0 +1,418 @@
+package cli
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/logging"
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+	"github.com/entireio/cli/cmd/entire/cli/strategy"
+	"github.com/entireio/cli/cmd/entire/cli/versioninfo"
+	"github.com/entireio/cli/redact"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// lastFailureFile is the journal of the most recent failed command, kept
+// next to the logs so 'entire bugreport' can include it.
+const lastFailureFile = "last-failure.json"
+
+// bugreportLogLines caps how much of entire.log goes into a bundle.
+const bugreportLogLines = 2000
+
+// failedOperation is the journal entry written when a command fails.
+type failedOperation struct {
+	Command    string    `json:"command"`
+	Args       []string  `json:"args,omitempty"`
+	Flags      []string  `json:"flags,omitempty"`
+	Error      string    `json:"error"`
+	Version    string    `json:"cli_version"`
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+}
+
+// RecordFailedOperation journals a failed command to .entire/logs so the
+// next 'entire bugreport' can include it. Best effort: outside a repository
+// or on write errors nothing is recorded. Called by main after Execute returns.
+func RecordFailedOperation(cmd *cobra.Command, args []string, err error, startedAt time.Time) {
+	if err == nil || cmd == nil || cmd.Name() == "__send_analytics" {
+		return
+	}
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	repoRoot, rootErr := paths.WorktreeRoot(ctx)
+	if rootErr != nil {
+		return
+	}
+
+	op := failedOperation{
+		Command:    cmd.CommandPath(),
+		Args:       args,
+		Error:      err.Error(),
+		Version:    versioninfo.Version,
+		StartedAt:  startedAt.UTC(),
+		FinishedAt: time.Now().UTC(),
+	}
+	cmd.Flags().Visit(func(flag *pflag.Flag) {
+		op.Flags = append(op.Flags, flag.Name)
+	})
+
+	data, marshalErr := json.MarshalIndent(op, "", "  ")
+	if marshalErr != nil {
+		return
+	}
+	logsDir := filepath.Join(repoRoot, logging.LogsDir)
+	if os.MkdirAll(logsDir, 0o750) != nil {
+		return
+	}
+	_ = os.WriteFile(filepath.Join(logsDir, lastFailureFile), data, 0o600) //nolint:errcheck // Best effort journal
+}
+
+func newBugreportCmd() *cobra.Command {
+	var outputFlag string
+
+	cmd := &cobra.Command{
+		Use:   "bugreport",
+		Short: "Bundle diagnostics into a zip to attach to an issue",
+		Long: `Bugreport collects what's needed to reproduce a problem into one zip file:
+
+  version.txt         CLI version, Go version, OS and architecture
+  repo.json           repository shape: commit, branch, worktree, checkpoint
+                      and session counts, hooks location (no file names)
+  settings/*.json     settings files, with tokens, keys, and URLs redacted
+  entire.log          the last ` + strconv.Itoa(bugreportLogLines) + ` lines of .entire/logs/entire.log
+  last-failure.json   the last failed command, and its log lines in
+  last-failure.log    last-failure.log
+
+Secrets are redacted and the repository and home paths are replaced with
+<repo> and ~ throughout. Transcripts, prompts, and code are never included.
+Review the bundle before attaching it.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			output := outputFlag
+			if output == "" {
+				output = "entire-bugreport-" + time.Now().UTC().Format("20060102T150405Z") + ".zip"
+			}
+			return runBugreport(cmd.Context(), cmd.OutOrStdout(), output)
+		},
+	}
+
+	cmd.Flags().StringVarP(&outputFlag, "output", "o", "", "Write the bundle to this path (default entire-bugreport-<time>.zip)")
+
+	return cmd
+}
+
+func runBugreport(ctx context.Context, w io.Writer, output string) error {
+	repoRoot, err := paths.WorktreeRoot(ctx)
+	if err != nil {
+		repoRoot = ""
+	}
+	s := newBundleSanitizer(repoRoot)
+
+	files := []bundleFile{{Name: "version.txt", Data: []byte(versionString())}}
+	if repoRoot == "" {
+		files = append(files, bundleFile{Name: "repo.json", Data: []byte(`{"git_repository": false}` + "\n")})
+	} else {
+		shape := collectRepoShape(ctx)
+		data, err := json.MarshalIndent(shape, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode repository stats: %w", err)
+		}
+		files = append(files, bundleFile{Name: "repo.json", Data: append(data, '\n')})
+
+		for _, name := range []string{EntireSettingsFile, EntireSettingsLocalFile} {
+			data, err := os.ReadFile(filepath.Join(repoRoot, name)) //nolint:gosec // fixed settings paths under the repo root
+			if err != nil {
+				continue
+			}
+			files = append(files, bundleFile{Name: "settings/" + filepath.Base(name), Data: s.sanitize(redactSettingsJSON(data))})
+		}
+
+		logsDir := filepath.Join(repoRoot, logging.LogsDir)
+		lines, logErr := readLogLines(filepath.Join(logsDir, "entire.log"))
+		if logErr == nil {
+			tail := lines
+			if len(tail) > bugreportLogLines {
+				tail = tail[len(tail)-bugreportLogLines:]
+			}
+			files = append(files, bundleFile{Name: "entire.log", Data: s.lines(tail)})
+		}
+		if journal, err := os.ReadFile(filepath.Join(logsDir, lastFailureFile)); err == nil { //nolint:gosec // fixed journal path under the repo root
+			files = append(files, bundleFile{Name: lastFailureFile, Data: s.sanitize(journal)})
+			var op failedOperation
+			if logErr == nil && json.Unmarshal(journal, &op) == nil {
+				files = append(files, bundleFile{Name: "last-failure.log", Data: s.lines(logLinesBetween(lines, op.StartedAt, op.FinishedAt))})
+			}
+		}
+	}
+
+	if err := writeBundle(output, files); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "Wrote %s:\n", output)
+	for _, f := range files {
+		fmt.Fprintf(w, "  %-22s %s\n", f.Name, formatByteSize(int64(len(f.Data))))
+	}
+	fmt.Fprintln(w, "\nSecrets and paths were redacted, but please review the bundle before attaching it to an issue.")
+	return nil
+}
+
+// bundleFile is one file in a bug report zip.
+type bundleFile struct {
+	Name string
+	Data []byte
+}
+
+func writeBundle(output string, files []bundleFile) error {
+	f, err := os.OpenFile(output, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600) //nolint:gosec // output path is chosen by the user
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", output, err)
+	}
+	zw := zip.NewWriter(f)
+	modified := time.Now()
+	for _, file := range files {
+		fw, err := zw.CreateHeader(&zip.FileHeader{Name: file.Name, Method: zip.Deflate, Modified: modified})
+		if err != nil {
+			_ = f.Close()
+			return fmt.Errorf("failed to add %s: %w", file.Name, err)
+		}
+		if _, err := fw.Write(file.Data); err != nil {
+			_ = f.Close()
+			return fmt.Errorf("failed to add %s: %w", file.Name, err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to write %s: %w", output, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", output, err)
+	}
+	return nil
+}
+
+// repoShape describes a repository's size and layout without naming any of
+// its files, branches, or remotes.
+type repoShape struct {
+	GitVersion       string         `json:"git_version,omitempty"`
+	Commits          int            `json:"head_commits"`
+	Branches         int            `json:"branches"`
+	Remotes          int            `json:"remotes"`
+	Worktrees        int            `json:"worktrees"`
+	TrackedFiles     int            `json:"tracked_files"`
+	Shallow          bool           `json:"shallow"`
+	HooksPathScope   string         `json:"hooks_path_scope,omitempty"`
+	MetadataBranch   bool           `json:"metadata_branch"`
+	Checkpoints      int            `json:"checkpoints"`
+	ShadowBranches   int            `json:"shadow_branches"`
+	SessionsByPhase  map[string]int `json:"sessions_by_phase,omitempty"`
+	CollectionErrors []string       `json:"collection_errors,omitempty"`
+}
+
+func collectRepoShape(ctx context.Context) repoShape {
+	var shape repoShape
+	fail := func(what string, err error) {
+		shape.CollectionErrors = append(shape.CollectionErrors, what+": "+err.Error())
+	}
+
+	if out, err := bugreportGit(ctx, "--version"); err == nil {
+		shape.GitVersion = strings.TrimPrefix(out, "git version ")
+	}
+	if out, err := bugreportGit(ctx, "rev-list", "--count", "HEAD"); err == nil {
+		shape.Commits, _ = strconv.Atoi(out) //nolint:errcheck // zero on unexpected output
+	}
+	if out, err := bugreportGit(ctx, "for-each-ref", "--format=x", "refs/heads/"); err == nil {
+		shape.Branches = countOutputLines(out)
+	}
+	if out, err := bugreportGit(ctx, "remote"); err == nil {
+		shape.Remotes = countOutputLines(out)
+	}
+	if out, err := bugreportGit(ctx, "worktree", "list", "--porcelain"); err == nil {
+		shape.Worktrees = strings.Count(out+"\n", "worktree ")
+	}
+	if out, err := bugreportGit(ctx, "ls-files"); err == nil {
+		shape.TrackedFiles = countOutputLines(out)
+	}
+	if out, err := bugreportGit(ctx, "rev-parse", "--is-shallow-repository"); err == nil {
+		shape.Shallow = out == "true"
+	}
+	if source, err := strategy.DescribeHooksDir(ctx); err == nil {
+		shape.HooksPathScope = source.Scope
+	}
+
+	if repo, err := openRepository(ctx); err != nil {
+		fail("open repository", err)
+	} else {
+		_, err := repo.Reference(plumbing.NewBranchReferenceName(paths.MetadataBranchName), true)
+		shape.MetadataBranch = err == nil
+		if store, err := strategy.CommittedStore(ctx, checkpoint.NewGitStore(repo)); err != nil {
+			fail("open checkpoint store", err)
+		} else if committed, err := store.ListCommitted(ctx); err != nil {
+			fail("list checkpoints", err)
+		} else {
+			shape.Checkpoints = len(committed)
+		}
+	}
+	if branches, err := strategy.ListShadowBranches(ctx); err != nil {
+		fail("list shadow branches", err)
+	} else {
+		shape.ShadowBranches = len(branches)
+	}
+	if states, err := strategy.ListSessionStates(ctx); err != nil {
+		fail("list sessions", err)
+	} else if len(states) > 0 {
+		shape.SessionsByPhase = make(map[string]int)
+		for _, state := range states {
+			phase := string(state.Phase)
+			if phase == "" {
+				phase = "unknown"
+			}
+			shape.SessionsByPhase[phase]++
+		}
+	}
+	return shape
+}
+
+func bugreportGit(ctx context.Context, args ...string) (string, error) {
+	out, err := exec.CommandContext(ctx, "git", args...).Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// countOutputLines counts the lines of trimmed command output.
+func countOutputLines(s string) int {
+	if s == "" {
+		return 0
+	}
+	return strings.Count(s, "\n") + 1
+}
+
+// sensitiveSettingKey matches settings keys whose values are replaced
+// wholesale rather than scanned for secrets.
+var sensitiveSettingKey = regexp.MustCompile(`(?i)token|secret|password|key|auth|credential|url|endpoint`)
+
+// redactSettingsJSON returns a settings file with sensitive values replaced
+// and every other string run through secret redaction. Files that aren't
+// valid JSON are summarized instead of copied.
+func redactSettingsJSON(data []byte) []byte {
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return []byte(fmt.Sprintf("settings file is not valid JSON (%d bytes): %s\n", len(data), redact.String(err.Error())))
+	}
+	out, err := json.MarshalIndent(redactSettingsValue(v), "", "  ")
+	if err != nil {
+		return []byte("settings file could not be re-encoded\n")
+	}
+	return append(out, '\n')
+}
+
+func redactSettingsValue(v any) any {
+	switch value := v.(type) {
+	case map[string]any:
+		for key, inner := range value {
+			if _, isString := inner.(string); isString && sensitiveSettingKey.MatchString(key) {
+				value[key] = "[REDACTED]"
+				continue
+			}
+			value[key] = redactSettingsValue(inner)
+		}
+		return value
+	case []any:
+		for i, inner := range value {
+			value[i] = redactSettingsValue(inner)
+		}
+		return value
+	case string:
+		return redact.String(value)
+	default:
+		return v
+	}
+}
+
+// bundleSanitizer redacts secrets and replaces the repository and home
+// directory paths in text copied into a bundle.
+type bundleSanitizer struct {
+	replacer *strings.Replacer
+}
+
+func newBundleSanitizer(repoRoot string) *bundleSanitizer {
+	var pairs []string
+	// Longer paths first, so a repository under the home directory becomes <repo>
+	if repoRoot != "" {
+		pairs = append(pairs, repoRoot, "<repo>")
+	}
+	if home, err := os.UserHomeDir(); err == nil && home != "" && home != "/" {
+		pairs = append(pairs, home, "~")
+	}
+	return &bundleSanitizer{replacer: strings.NewReplacer(pairs...)}
+}
+
+func (s *bundleSanitizer) sanitize(data []byte) []byte {
+	return []byte(s.replacer.Replace(redact.String(string(data))))
+}
+
+func (s *bundleSanitizer) lines(lines []string) []byte {
+	var buf bytes.Buffer
+	for _, line := range lines {
+		buf.WriteString(s.replacer.Replace(redact.String(line)))
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes()
+}
+
+func readLogLines(path string) ([]string, error) {
+	f, err := os.Open(path) //nolint:gosec // fixed log path under the repo root
+	if err != nil {
+		return nil, err //nolint:wrapcheck // Callers only check for absence
+	}
+	defer f.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return lines, nil
+}
+
+// logLinesBetween returns the JSON log lines whose time falls within
+// [start, end].
+func logLinesBetween(lines []string, start, end time.Time) []string {
+	var out []string
+	for _, line := range lines {
+		var entry struct {
+			Time time.Time `json:"time"`
+		}
+		if json.Unmarshal([]byte(line), &entry) != nil || entry.Time.IsZero() {
+			continue
+		}
+		if !entry.Time.Before(start) && !entry.Time.After(end) {
+			out = append(out, line)
+		}
+	}
+	return out
+}
@@ -0,0 +1,110 @@
+package cli
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/entireio/cli/cmd/entire/cli/logging"
+
+	"github.com/spf13/cobra"
+)
+
+// readBundle returns the files of a bug report zip by name.
+func readBundle(t *testing.T, path string) map[string]string {
+	t.Helper()
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		t.Fatalf("failed to open bundle: %v", err)
+	}
+	defer zr.Close()
+	files := make(map[string]string)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatalf("failed to open %s: %v", f.Name, err)
+		}
+		data, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", f.Name, err)
+		}
+		files[f.Name] = string(data)
+	}
+	return files
+}
+
+func TestRunBugreport(t *testing.T) {
+	dir := setupExecTestRepo(t)
+	writeSettings(t, `{"enabled": true, "strategy_options": {"summarize": {"api_key": "plain-value"}, "note": "see `+dir+`/notes"}}`)
+
+	// A failed command, with log lines before, during, and after it
+	started := time.Now().Add(-time.Minute)
+	cmd := &cobra.Command{Use: "rewind"}
+	cmd.SetContext(context.Background())
+	RecordFailedOperation(cmd, []string{"rewind", "--to", "abc123"}, errors.New("failed to read "+dir+"/src/main.go"), started)
+
+	logsDir := filepath.Join(dir, logging.LogsDir)
+	logLine := func(at time.Time, msg string) string {
+		return fmt.Sprintf(`{"time":%q,"level":"INFO","msg":%q}`, at.Format(time.RFC3339Nano), msg)
+	}
+	log := strings.Join([]string{
+		logLine(started.Add(-time.Hour), "earlier"),
+		logLine(started.Add(time.Second), "during in "+dir),
+		logLine(time.Now().Add(time.Hour), "later"),
+	}, "\n") + "\n"
+	if err := os.WriteFile(filepath.Join(logsDir, "entire.log"), []byte(log), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	output := filepath.Join(t.TempDir(), "report.zip")
+	var out bytes.Buffer
+	if err := runBugreport(context.Background(), &out, output); err != nil {
+		t.Fatalf("runBugreport() error = %v", err)
+	}
+	files := readBundle(t, output)
+
+	for _, name := range []string{"version.txt", "repo.json", "settings/settings.json", "entire.log", "last-failure.json", "last-failure.log"} {
+		if _, ok := files[name]; !ok {
+			t.Errorf("bundle is missing %s (has %v)", name, out.String())
+		}
+	}
+	if !strings.Contains(files["repo.json"], `"head_commits": 1`) {
+		t.Errorf("repo.json = %s", files["repo.json"])
+	}
+	settingsJSON := files["settings/settings.json"]
+	if strings.Contains(settingsJSON, "plain-value") || !strings.Contains(settingsJSON, "[REDACTED]") {
+		t.Errorf("settings were not redacted:\n%s", settingsJSON)
+	}
+	for name, content := range files {
+		if strings.Contains(content, dir) {
+			t.Errorf("%s contains the repository path:\n%s", name, content)
+		}
+	}
+	if !strings.Contains(files["last-failure.json"], "<repo>/src/main.go") {
+		t.Errorf("last-failure.json = %s", files["last-failure.json"])
+	}
+	failureLog := files["last-failure.log"]
+	if !strings.Contains(failureLog, "during in <repo>") || strings.Contains(failureLog, "earlier") || strings.Contains(failureLog, "later") {
+		t.Errorf("last-failure.log should hold only the failed command's lines:\n%s", failureLog)
+	}
+}
+
+func TestRunBugreport_RefusesToOverwrite(t *testing.T) {
+	setupExecTestRepo(t)
+	output := filepath.Join(t.TempDir(), "report.zip")
+	if err := os.WriteFile(output, []byte("keep"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := runBugreport(context.Background(), io.Discard, output); err == nil {
+		t.Fatal("runBugreport() should not overwrite an existing file")
+	}
+}
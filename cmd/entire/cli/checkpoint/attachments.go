@@ -0,0 +1,222 @@
+package checkpoint
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"mime"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+	"github.com/entireio/cli/cmd/entire/cli/logging"
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+	"github.com/entireio/cli/redact"
+
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+const (
+	// MaxAttachmentBytes caps a single attachment.
+	MaxAttachmentBytes = 10 << 20
+
+	// MaxSessionAttachmentsBytes caps the attachments stored with one session.
+	MaxSessionAttachmentsBytes = 25 << 20
+
+	// maxAttachmentNameLength caps attachment file names.
+	maxAttachmentNameLength = 128
+)
+
+// Attachment is a file, such as a screenshot or a log, stored with a session
+// as evidence. Attachments live under the session's attachments/ directory
+// and are never read as session content, so they stay out of prompt,
+// transcript, and context processing.
+type Attachment struct {
+	Name        string
+	ContentType string // detected from the name and content if empty
+	Data        []byte
+}
+
+// AttachmentInfo describes a stored attachment in session metadata.
+type AttachmentInfo struct {
+	Name        string `json:"name"`
+	ContentType string `json:"content_type"`
+	Size        int    `json:"size"`
+}
+
+// ValidateAttachmentName checks that name is a plain file name that can be
+// stored under attachments/.
+func ValidateAttachmentName(name string) error {
+	switch {
+	case name == "" || name == "." || name == "..":
+		return fmt.Errorf("invalid attachment name %q", name)
+	case len(name) > maxAttachmentNameLength:
+		return fmt.Errorf("attachment name %q is longer than %d characters", name, maxAttachmentNameLength)
+	case strings.ContainsAny(name, `/\`) || strings.HasPrefix(name, "."):
+		return fmt.Errorf("invalid attachment name %q: use a file name without directories or a leading dot", name)
+	case strings.ContainsFunc(name, func(r rune) bool { return r < 0x20 || r == 0x7f }):
+		return fmt.Errorf("invalid attachment name %q: contains control characters", name)
+	}
+	return nil
+}
+
+// DetectContentType returns the content type for an attachment, from its
+// extension if known and otherwise by sniffing its content.
+func DetectContentType(name string, data []byte) string {
+	if contentType := mime.TypeByExtension(path.Ext(name)); contentType != "" {
+		return contentType
+	}
+	return http.DetectContentType(data)
+}
+
+// isTextContentType reports whether attachments of contentType are text and
+// so are redacted like other session content.
+func isTextContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return strings.HasPrefix(mediaType, "text/") || mediaType == "application/json" ||
+		mediaType == "application/x-ndjson" || mediaType == "application/xml"
+}
+
+// writeAttachments stores attachments under sessionPath/attachments/ and
+// returns their metadata sorted by name. Attachments with invalid names, or
+// over the per-file or per-session caps, are skipped with a warning rather
+// than failing the checkpoint.
+func (s *GitStore) writeAttachments(ctx context.Context, attachments []Attachment, sessionPath string, entries map[string]object.TreeEntry) ([]AttachmentInfo, error) {
+	return s.writeSessionFiles(ctx, attachments, sessionPath+paths.AttachmentsDirName+"/", entries)
+}
+
+// writeArtifacts stores agent-produced artifacts under sessionPath/artifacts/,
+// with the same name rules and caps as attachments.
+func (s *GitStore) writeArtifacts(ctx context.Context, artifacts map[string][]byte, sessionPath string, entries map[string]object.TreeEntry) ([]AttachmentInfo, error) {
+	names := make([]string, 0, len(artifacts))
+	for name := range artifacts {
+		names = append(names, name)
+	}
+	// Sorted so the session cap keeps the same files on every write
+	sort.Strings(names)
+	files := make([]Attachment, 0, len(names))
+	for _, name := range names {
+		files = append(files, Attachment{Name: name, Data: artifacts[name]})
+	}
+	return s.writeSessionFiles(ctx, files, sessionPath+paths.ArtifactsDirName+"/", entries)
+}
+
+// writeSessionFiles stores files under dirPath for writeAttachments and
+// writeArtifacts, and returns their metadata sorted by name.
+func (s *GitStore) writeSessionFiles(ctx context.Context, attachments []Attachment, dirPath string, entries map[string]object.TreeEntry) ([]AttachmentInfo, error) {
+	infos := make([]AttachmentInfo, 0, len(attachments))
+	seen := make(map[string]bool)
+	total := 0
+	for _, att := range attachments {
+		skip := func(reason string) {
+			logging.Warn(ctx, "skipping checkpoint attachment",
+				slog.String("name", att.Name),
+				slog.String("reason", reason))
+		}
+		if err := ValidateAttachmentName(att.Name); err != nil {
+			skip(err.Error())
+			continue
+		}
+		if seen[att.Name] {
+			skip("duplicate name")
+			continue
+		}
+		if len(att.Data) > MaxAttachmentBytes {
+			skip(fmt.Sprintf("%d bytes is over the %d byte limit", len(att.Data), MaxAttachmentBytes))
+			continue
+		}
+		if total+len(att.Data) > MaxSessionAttachmentsBytes {
+			skip(fmt.Sprintf("session attachments would exceed %d bytes", MaxSessionAttachmentsBytes))
+			continue
+		}
+
+		contentType := att.ContentType
+		if contentType == "" {
+			contentType = DetectContentType(att.Name, att.Data)
+		}
+		data := att.Data
+		if isTextContentType(contentType) {
+			data = redact.Bytes(data)
+		}
+		blobHash, err := CreateBlobFromContent(s.repo, data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create attachment blob: %w", err)
+		}
+		name := dirPath + att.Name
+		entries[name] = object.TreeEntry{
+			Name: name,
+			Mode: filemode.Regular,
+			Hash: blobHash,
+		}
+		seen[att.Name] = true
+		total += len(att.Data)
+		infos = append(infos, AttachmentInfo{Name: att.Name, ContentType: contentType, Size: len(data)})
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+	return infos, nil
+}
+
+// readArtifactsFromTree reads the artifacts listed in a session's metadata
+// from its artifacts/ directory. Artifacts that can't be read are skipped with
+// a warning, like other optional session content.
+func readArtifactsFromTree(ctx context.Context, sessionTree *object.Tree, infos []AttachmentInfo) map[string][]byte {
+	if len(infos) == 0 {
+		return nil
+	}
+	artifacts := make(map[string][]byte, len(infos))
+	for _, info := range infos {
+		if ValidateAttachmentName(info.Name) != nil {
+			continue
+		}
+		file, err := sessionTree.File(paths.ArtifactsDirName + "/" + info.Name)
+		if err != nil {
+			logging.Warn(ctx, "checkpoint artifact not found",
+				slog.String("name", info.Name),
+				slog.String("error", err.Error()))
+			continue
+		}
+		content, err := file.Contents()
+		if err != nil {
+			logging.Warn(ctx, "failed to read checkpoint artifact",
+				slog.String("name", info.Name),
+				slog.String("error", err.Error()))
+			continue
+		}
+		artifacts[info.Name] = []byte(content)
+	}
+	return artifacts
+}
+
+// ReadAttachment returns the content of a session's attachment.
+func (s *GitStore) ReadAttachment(ctx context.Context, checkpointID id.CheckpointID, sessionIndex int, name string) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err //nolint:wrapcheck // Propagating context cancellation
+	}
+	if err := ValidateAttachmentName(name); err != nil {
+		return nil, err
+	}
+
+	tree, err := s.getSessionsBranchTree()
+	if err != nil {
+		return nil, ErrCheckpointNotFound
+	}
+	checkpointTree, err := tree.Tree(checkpointID.Path())
+	if err != nil {
+		return nil, ErrCheckpointNotFound
+	}
+	file, err := checkpointTree.File(fmt.Sprintf("%d/%s/%s", sessionIndex, paths.AttachmentsDirName, name))
+	if err != nil {
+		return nil, fmt.Errorf("attachment %q not found in checkpoint %s", name, checkpointID)
+	}
+	content, err := file.Contents()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read attachment %q: %w", name, err)
+	}
+	return []byte(content), nil
+}
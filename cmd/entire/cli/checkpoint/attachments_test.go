@@ -0,0 +1,176 @@
+package checkpoint
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestValidateAttachmentName(t *testing.T) {
+	t.Parallel()
+	for _, name := range []string{"screenshot.png", "build log.txt", "trace-2026.json"} {
+		if err := ValidateAttachmentName(name); err != nil {
+			t.Errorf("ValidateAttachmentName(%q) error = %v", name, err)
+		}
+	}
+	for _, name := range []string{"", ".", "..", ".env", "dir/file.txt", `dir\file.txt`, "bell\x07.txt", strings.Repeat("a", 129)} {
+		if err := ValidateAttachmentName(name); err == nil {
+			t.Errorf("ValidateAttachmentName(%q) should fail", name)
+		}
+	}
+}
+
+func TestDetectContentType(t *testing.T) {
+	t.Parallel()
+	png := []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+	tests := []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{"shot.png", png, "image/png"},
+		{"shot", png, "image/png"},
+		{"build", []byte("ok\n"), "text/plain; charset=utf-8"},
+		{"blob", []byte{0x00, 0x01, 0x02}, "application/octet-stream"},
+	}
+	for _, tt := range tests {
+		if got := DetectContentType(tt.name, tt.data); got != tt.want {
+			t.Errorf("DetectContentType(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestWriteCommitted_Attachments(t *testing.T) {
+	t.Parallel()
+	_, store, cpID := setupRepoForUpdate(t)
+
+	png := append([]byte("\x89PNG\r\n\x1a\n"), bytes.Repeat([]byte{0xff}, 64)...)
+	attachments := []Attachment{
+		{Name: "test.log", Data: []byte("token " + highEntropySecret + "\n")},
+		{Name: "shot.png", Data: png},
+		{Name: "../escape.txt", Data: []byte("x")},
+		{Name: "huge.bin", Data: make([]byte, MaxAttachmentBytes+1)},
+	}
+	if err := store.WriteCommitted(context.Background(), WriteCommittedOptions{
+		CheckpointID: cpID,
+		SessionID:    "session-001",
+		Strategy:     "manual-commit",
+		Transcript:   []byte("line\n"),
+		Attachments:  attachments,
+		AuthorName:   "Test",
+		AuthorEmail:  "test@test.com",
+	}); err != nil {
+		t.Fatalf("WriteCommitted() error = %v", err)
+	}
+
+	content, err := store.ReadSessionContent(context.Background(), cpID, 0)
+	if err != nil {
+		t.Fatalf("ReadSessionContent() error = %v", err)
+	}
+	infos := content.Metadata.Attachments
+	if len(infos) != 2 || infos[0].Name != "shot.png" || infos[1].Name != "test.log" {
+		t.Fatalf("Attachments = %+v, want shot.png and test.log only", infos)
+	}
+	if infos[0].ContentType != "image/png" || infos[0].Size != len(png) {
+		t.Errorf("shot.png info = %+v", infos[0])
+	}
+
+	got, err := store.ReadAttachment(context.Background(), cpID, 0, "shot.png")
+	if err != nil {
+		t.Fatalf("ReadAttachment() error = %v", err)
+	}
+	if !bytes.Equal(got, png) {
+		t.Error("binary attachment should be stored as given")
+	}
+	got, err = store.ReadAttachment(context.Background(), cpID, 0, "test.log")
+	if err != nil {
+		t.Fatalf("ReadAttachment() error = %v", err)
+	}
+	if strings.Contains(string(got), highEntropySecret) {
+		t.Errorf("text attachment should be redacted: %q", got)
+	}
+	if _, err := store.ReadAttachment(context.Background(), cpID, 0, "missing.txt"); err == nil {
+		t.Error("ReadAttachment() of a missing attachment should fail")
+	}
+}
+
+func TestWriteCommitted_AttachmentsSessionCap(t *testing.T) {
+	t.Parallel()
+	_, store, cpID := setupRepoForUpdate(t)
+
+	attachments := []Attachment{
+		{Name: "a.bin", Data: make([]byte, MaxAttachmentBytes)},
+		{Name: "b.bin", Data: make([]byte, MaxAttachmentBytes)},
+		{Name: "c.bin", Data: make([]byte, MaxAttachmentBytes)},
+	}
+	if err := store.WriteCommitted(context.Background(), WriteCommittedOptions{
+		CheckpointID: cpID,
+		SessionID:    "session-001",
+		Strategy:     "manual-commit",
+		Transcript:   []byte("line\n"),
+		Attachments:  attachments,
+		AuthorName:   "Test",
+		AuthorEmail:  "test@test.com",
+	}); err != nil {
+		t.Fatalf("WriteCommitted() error = %v", err)
+	}
+
+	content, err := store.ReadSessionContent(context.Background(), cpID, 0)
+	if err != nil {
+		t.Fatalf("ReadSessionContent() error = %v", err)
+	}
+	if got := len(content.Metadata.Attachments); got != 2 {
+		t.Errorf("stored %d attachments, want 2 within the session cap", got)
+	}
+}
+
+func TestWriteCommitted_Artifacts(t *testing.T) {
+	t.Parallel()
+	_, store, cpID := setupRepoForUpdate(t)
+
+	png := append([]byte("\x89PNG\r\n\x1a\n"), bytes.Repeat([]byte{0xff}, 64)...)
+	if err := store.WriteCommitted(context.Background(), WriteCommittedOptions{
+		CheckpointID: cpID,
+		SessionID:    "session-001",
+		Strategy:     "manual-commit",
+		Transcript:   []byte("line\n"),
+		Artifacts: map[string][]byte{
+			"plan.md":      []byte("# Plan\nkey " + highEntropySecret + "\n"),
+			"shot.png":     png,
+			"../escape.md": []byte("x"),
+		},
+		Attachments: []Attachment{{Name: "shot.png", Data: []byte("attached")}},
+		AuthorName:  "Test",
+		AuthorEmail: "test@test.com",
+	}); err != nil {
+		t.Fatalf("WriteCommitted() error = %v", err)
+	}
+
+	content, err := store.ReadSessionContent(context.Background(), cpID, 0)
+	if err != nil {
+		t.Fatalf("ReadSessionContent() error = %v", err)
+	}
+	infos := content.Metadata.Artifacts
+	if len(infos) != 2 || infos[0].Name != "plan.md" || infos[1].Name != "shot.png" {
+		t.Fatalf("Artifacts = %+v, want plan.md and shot.png only", infos)
+	}
+	if len(content.Artifacts) != 2 {
+		t.Fatalf("ReadSessionContent() returned %d artifacts, want 2", len(content.Artifacts))
+	}
+	if !bytes.Equal(content.Artifacts["shot.png"], png) {
+		t.Error("binary artifact should be stored as given")
+	}
+	if strings.Contains(string(content.Artifacts["plan.md"]), highEntropySecret) {
+		t.Errorf("text artifact should be redacted: %q", content.Artifacts["plan.md"])
+	}
+
+	// Artifacts and attachments with the same name don't collide
+	got, err := store.ReadAttachment(context.Background(), cpID, 0, "shot.png")
+	if err != nil {
+		t.Fatalf("ReadAttachment() error = %v", err)
+	}
+	if string(got) != "attached" {
+		t.Errorf("ReadAttachment(shot.png) = %q, want the attachment", got)
+	}
+}
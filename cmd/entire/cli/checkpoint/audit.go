@@ -0,0 +1,211 @@
+package checkpoint
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+	"github.com/entireio/cli/cmd/entire/cli/jsonutil"
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// Audit operations recorded on the metadata branch.
+const (
+	AuditOpReset      = "reset"
+	AuditOpRewind     = "rewind"
+	AuditOpClean      = "clean"
+	AuditOpCompaction = "compaction"
+	AuditOpPurge      = "purge"
+	AuditOpGC         = "gc"
+	AuditOpDelete     = "delete"
+	// AuditOpAdoptPrunes records sync dropping local checkpoints that a
+	// remote pruned from its history.
+	AuditOpAdoptPrunes = "adopt-prunes"
+	// AuditOpPolicyOverride records a command run past .entire/policy.json
+	// with an override token.
+	AuditOpPolicyOverride = "policy-override"
+)
+
+// AuditSigningKeyEnvVar holds a base64 ed25519 private key (or its 32-byte
+// seed) used to sign audit entries that serve as compliance records.
+const AuditSigningKeyEnvVar = "ENTIRE_AUDIT_SIGNING_KEY"
+
+// ErrInvalidAuditSignature is returned when a signed audit entry fails verification.
+var ErrInvalidAuditSignature = errors.New("audit entry signature is invalid")
+
+// AuditEntry describes a single destructive operation.
+// Entries are stored one file per entry under paths.AuditLogDir on the
+// metadata branch, so concurrent writers on different machines never
+// conflict when the branch is merged.
+type AuditEntry struct {
+	Timestamp  time.Time `json:"timestamp"`
+	Operation  string    `json:"operation"`
+	ActorName  string    `json:"actor_name,omitempty"`
+	ActorEmail string    `json:"actor_email,omitempty"`
+	// Refs lists the branches or commits the operation acted on.
+	Refs []string `json:"refs,omitempty"`
+	// Removed lists what the operation deleted (branches, session IDs, checkpoint IDs).
+	Removed []string `json:"removed,omitempty"`
+	Details string   `json:"details,omitempty"`
+	// Signature is a base64 ed25519 signature over SigningPayload().
+	Signature string `json:"signature,omitempty"`
+}
+
+// SigningPayload returns the canonical bytes covered by the signature:
+// the JSON encoding of the entry with the signature field cleared.
+func (e AuditEntry) SigningPayload() ([]byte, error) {
+	e.Signature = ""
+	data, err := json.Marshal(e)
+	if err != nil {
+		return nil, fmt.Errorf("encoding audit entry: %w", err)
+	}
+	return data, nil
+}
+
+// Sign sets the entry's signature. Timestamp and actor must be filled in
+// beforehand, since AppendAuditEntry would otherwise change signed fields.
+func (e *AuditEntry) Sign(key ed25519.PrivateKey) error {
+	payload, err := e.SigningPayload()
+	if err != nil {
+		return err
+	}
+	e.Signature = base64.StdEncoding.EncodeToString(ed25519.Sign(key, payload))
+	return nil
+}
+
+// Verify checks the entry's signature against publicKey.
+func (e AuditEntry) Verify(publicKey ed25519.PublicKey) error {
+	if e.Signature == "" {
+		return fmt.Errorf("%w: entry is not signed", ErrInvalidAuditSignature)
+	}
+	sig, err := base64.StdEncoding.DecodeString(e.Signature)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidAuditSignature, err)
+	}
+	payload, err := e.SigningPayload()
+	if err != nil {
+		return err
+	}
+	if !ed25519.Verify(publicKey, payload, sig) {
+		return ErrInvalidAuditSignature
+	}
+	return nil
+}
+
+// ParseAuditSigningKey decodes a base64 ed25519 private key or seed.
+func ParseAuditSigningKey(s string) (ed25519.PrivateKey, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(s))
+	if err != nil {
+		return nil, fmt.Errorf("decoding audit signing key: %w", err)
+	}
+	switch len(key) {
+	case ed25519.SeedSize:
+		return ed25519.NewKeyFromSeed(key), nil
+	case ed25519.PrivateKeySize:
+		return ed25519.PrivateKey(key), nil
+	default:
+		return nil, fmt.Errorf("audit signing key must be %d or %d bytes, got %d", ed25519.SeedSize, ed25519.PrivateKeySize, len(key))
+	}
+}
+
+// AppendAuditEntry records an entry in the audit log on the metadata branch.
+// Missing timestamp and actor fields are filled from the clock and git config.
+// Existing entries are never rewritten.
+func (s *GitStore) AppendAuditEntry(ctx context.Context, entry AuditEntry) error {
+	if err := ctx.Err(); err != nil {
+		return err //nolint:wrapcheck // Propagating context cancellation
+	}
+	if entry.Operation == "" {
+		return errors.New("audit entry operation is required")
+	}
+
+	authorName, authorEmail := GetGitAuthorFromRepo(s.repo)
+	if entry.Timestamp.IsZero() {
+		entry.Timestamp = time.Now().UTC()
+	}
+	if entry.ActorName == "" {
+		entry.ActorName = authorName
+	}
+	if entry.ActorEmail == "" {
+		entry.ActorEmail = authorEmail
+	}
+
+	if err := s.ensureSessionsBranch(); err != nil {
+		return fmt.Errorf("failed to ensure sessions branch: %w", err)
+	}
+	return s.commitMetadata(ctx, func(_, rootTreeHash plumbing.Hash) (*metadataCommit, error) {
+		entryJSON, err := jsonutil.MarshalIndentWithNewline(entry, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal audit entry: %w", err)
+		}
+		blobHash, err := CreateBlobFromContent(s.repo, entryJSON)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create audit entry blob: %w", err)
+		}
+
+		// A random suffix keeps names unique when two entries share a timestamp.
+		suffix, err := id.Generate()
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate audit entry name: %w", err)
+		}
+		fileName := fmt.Sprintf("%019d-%s-%s.json", entry.Timestamp.UnixNano(), entry.Operation, suffix)
+
+		newTreeHash, err := UpdateSubtree(s.repo, rootTreeHash, []string{paths.AuditLogDir}, []object.TreeEntry{
+			{Name: fileName, Mode: filemode.Regular, Hash: blobHash},
+		}, UpdateSubtreeOptions{MergeMode: MergeKeepExisting})
+		if err != nil {
+			return nil, fmt.Errorf("failed to update audit log tree: %w", err)
+		}
+		return &metadataCommit{
+			Tree:        newTreeHash,
+			Message:     "Audit: " + entry.Operation,
+			AuthorName:  authorName,
+			AuthorEmail: authorEmail,
+		}, nil
+	})
+}
+
+// ReadAuditLog returns all audit entries, oldest first.
+// Returns an empty slice if the metadata branch or audit log doesn't exist.
+func (s *GitStore) ReadAuditLog(ctx context.Context) ([]AuditEntry, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err //nolint:wrapcheck // Propagating context cancellation
+	}
+
+	tree, err := s.getSessionsBranchTree()
+	if err != nil {
+		return []AuditEntry{}, nil //nolint:nilerr // No metadata branch means no audit entries
+	}
+	auditTree, err := tree.Tree(paths.AuditLogDir)
+	if err != nil {
+		return []AuditEntry{}, nil //nolint:nilerr // No audit directory means no audit entries
+	}
+
+	entries := make([]AuditEntry, 0, len(auditTree.Entries))
+	for _, treeEntry := range auditTree.Entries {
+		if treeEntry.Mode != filemode.Regular || !strings.HasSuffix(treeEntry.Name, ".json") {
+			continue
+		}
+		entry, err := readJSONFromBlob[AuditEntry](s.repo, treeEntry.Hash)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read audit entry %s: %w", treeEntry.Name, err)
+		}
+		entries = append(entries, *entry)
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Timestamp.Before(entries[j].Timestamp)
+	})
+	return entries, nil
+}
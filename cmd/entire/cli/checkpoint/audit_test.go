@@ -0,0 +1,136 @@
+package checkpoint
+
+import (
+	"context"
+	"crypto/ed25519"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+)
+
+func TestReadAuditLog_NoBranch(t *testing.T) {
+	t.Parallel()
+	repo, _ := setupBranchTestRepo(t)
+	store := NewGitStore(repo)
+
+	entries, err := store.ReadAuditLog(context.Background())
+	if err != nil {
+		t.Fatalf("ReadAuditLog() error = %v", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("ReadAuditLog() returned %d entries, want 0", len(entries))
+	}
+}
+
+func TestAppendAuditEntry_RoundTrip(t *testing.T) {
+	t.Parallel()
+	repo, _ := setupBranchTestRepo(t)
+	store := NewGitStore(repo)
+	ctx := context.Background()
+
+	base := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	if err := store.AppendAuditEntry(ctx, AuditEntry{
+		Timestamp: base.Add(time.Minute),
+		Operation: AuditOpClean,
+		Removed:   []string{"entire/abc1234-e3b0c4"},
+	}); err != nil {
+		t.Fatalf("AppendAuditEntry(clean) error = %v", err)
+	}
+	if err := store.AppendAuditEntry(ctx, AuditEntry{
+		Timestamp:  base,
+		Operation:  AuditOpReset,
+		ActorName:  "Alice",
+		ActorEmail: "alice@example.com",
+		Refs:       []string{"entire/def5678-e3b0c4"},
+	}); err != nil {
+		t.Fatalf("AppendAuditEntry(reset) error = %v", err)
+	}
+
+	entries, err := store.ReadAuditLog(ctx)
+	if err != nil {
+		t.Fatalf("ReadAuditLog() error = %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("ReadAuditLog() returned %d entries, want 2", len(entries))
+	}
+	if entries[0].Operation != AuditOpReset || entries[1].Operation != AuditOpClean {
+		t.Errorf("entries not sorted oldest first: got %q, %q", entries[0].Operation, entries[1].Operation)
+	}
+	if entries[0].ActorName != "Alice" || entries[0].ActorEmail != "alice@example.com" {
+		t.Errorf("explicit actor not preserved: %+v", entries[0])
+	}
+	if len(entries[1].Removed) != 1 || entries[1].Removed[0] != "entire/abc1234-e3b0c4" {
+		t.Errorf("Removed = %v, want [entire/abc1234-e3b0c4]", entries[1].Removed)
+	}
+}
+
+func TestAppendAuditEntry_RequiresOperation(t *testing.T) {
+	t.Parallel()
+	repo, _ := setupBranchTestRepo(t)
+	store := NewGitStore(repo)
+
+	if err := store.AppendAuditEntry(context.Background(), AuditEntry{}); err == nil {
+		t.Error("AppendAuditEntry() with empty operation should fail")
+	}
+}
+
+func TestAppendAuditEntry_DoesNotAffectCheckpointListing(t *testing.T) {
+	t.Parallel()
+	repo, _ := setupBranchTestRepo(t)
+	store := NewGitStore(repo)
+	ctx := context.Background()
+
+	cpID := id.MustCheckpointID("a1b2c3d4e5f6")
+	if err := store.WriteCommitted(ctx, WriteCommittedOptions{
+		CheckpointID: cpID,
+		SessionID:    "session-1",
+		Strategy:     "manual-commit",
+		Transcript:   []byte(`{"type":"user"}` + "\n"),
+		AuthorName:   "Test",
+		AuthorEmail:  "test@test.com",
+	}); err != nil {
+		t.Fatalf("WriteCommitted() error = %v", err)
+	}
+	if err := store.AppendAuditEntry(ctx, AuditEntry{Operation: AuditOpRewind}); err != nil {
+		t.Fatalf("AppendAuditEntry() error = %v", err)
+	}
+
+	checkpoints, err := store.ListCommitted(ctx)
+	if err != nil {
+		t.Fatalf("ListCommitted() error = %v", err)
+	}
+	if len(checkpoints) != 1 || checkpoints[0].CheckpointID != cpID {
+		t.Errorf("ListCommitted() = %+v, want only %s", checkpoints, cpID)
+	}
+}
+
+func TestAuditEntry_SignAndVerify(t *testing.T) {
+	t.Parallel()
+	key, err := ParseAuditSigningKey("AAECAwQFBgcICQoLDA0ODxAREhMUFRYXGBkaGxwdHh8=")
+	if err != nil {
+		t.Fatalf("ParseAuditSigningKey() error = %v", err)
+	}
+	entry := AuditEntry{
+		Timestamp: time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC),
+		Operation: AuditOpPurge,
+		Removed:   []string{"session-001"},
+	}
+	if err := entry.Sign(key); err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+
+	publicKey, ok := key.Public().(ed25519.PublicKey)
+	if !ok {
+		t.Fatal("unexpected public key type")
+	}
+	if err := entry.Verify(publicKey); err != nil {
+		t.Errorf("Verify() error = %v", err)
+	}
+
+	entry.Removed = []string{"session-002"}
+	if err := entry.Verify(publicKey); !errors.Is(err, ErrInvalidAuditSignature) {
+		t.Errorf("Verify() of a modified entry = %v, want ErrInvalidAuditSignature", err)
+	}
+}
@@ -0,0 +1,170 @@
+package checkpoint
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// ErrBatchDone is returned when a Batch is used after Commit or Discard.
+var ErrBatchDone = errors.New("checkpoint batch already finished")
+
+// Batch stages several committed checkpoint writes and commits them to the
+// metadata branch as a single commit, so a post-commit hook that condenses
+// several sessions moves the branch once.
+// Start one with GitStore.Begin. A Batch is not safe for concurrent use.
+type Batch struct {
+	store  *GitStore
+	parent plumbing.Hash
+	tree   plumbing.Hash
+	writes []stagedWrite
+	done   bool
+}
+
+// stagedWrite is a write of a Batch, kept for the commit message.
+type stagedWrite struct {
+	opts             WriteCommittedOptions
+	taskMetadataPath string
+}
+
+// Begin starts a batch of writes on top of the current metadata branch.
+func (s *GitStore) Begin() (*Batch, error) {
+	if err := s.ensureSessionsBranch(); err != nil {
+		return nil, fmt.Errorf("failed to ensure sessions branch: %w", err)
+	}
+	parentHash, rootTreeHash, err := s.getSessionsBranchRef()
+	if err != nil {
+		return nil, err
+	}
+	return &Batch{store: s, parent: parentHash, tree: rootTreeHash}, nil
+}
+
+// WriteCommitted stages a checkpoint write as GitStore.WriteCommitted does.
+// Later writes see the earlier ones, so several sessions can be written to
+// the same checkpoint. Nothing is visible on the branch until Commit.
+func (b *Batch) WriteCommitted(ctx context.Context, opts WriteCommittedOptions) error {
+	if b.done {
+		return ErrBatchDone
+	}
+	if err := validateWriteCommittedOptions(opts); err != nil {
+		return err
+	}
+	tree, taskMetadataPath, err := b.store.stageCommitted(ctx, b.tree, opts)
+	if err != nil {
+		return err
+	}
+	b.tree = tree
+	b.writes = append(b.writes, stagedWrite{opts: opts, taskMetadataPath: taskMetadataPath})
+	return nil
+}
+
+// Len returns the number of staged writes.
+func (b *Batch) Len() int {
+	return len(b.writes)
+}
+
+// Commit writes the staged checkpoints to the metadata branch in one commit,
+// authored by the first write's author. A batch without writes commits
+// nothing. If another writer moved the branch since Begin, the writes are
+// staged again on its new tip, as GitStore.WriteCommitted does.
+func (b *Batch) Commit(ctx context.Context) error {
+	if b.done {
+		return ErrBatchDone
+	}
+	b.done = true
+	if len(b.writes) == 0 {
+		return nil
+	}
+
+	first := b.writes[0].opts
+	return b.store.commitMetadata(ctx, func(parentHash, rootTreeHash plumbing.Hash) (*metadataCommit, error) {
+		tree, err := b.stageOn(ctx, parentHash, rootTreeHash)
+		if err != nil {
+			return nil, err
+		}
+		return &metadataCommit{
+			Tree:          tree,
+			Message:       b.commitMessage(),
+			AuthorName:    first.AuthorName,
+			AuthorEmail:   first.AuthorEmail,
+			CheckpointIDs: b.checkpointIDs(),
+		}, nil
+	})
+}
+
+// stageOn returns the tree of the staged writes on top of the branch at
+// parentHash, restaging them if the branch moved since they were staged.
+func (b *Batch) stageOn(ctx context.Context, parentHash, rootTreeHash plumbing.Hash) (plumbing.Hash, error) {
+	if parentHash == b.parent {
+		return b.tree, nil
+	}
+	tree := rootTreeHash
+	for i, w := range b.writes {
+		staged, taskMetadataPath, err := b.store.stageCommitted(ctx, tree, w.opts)
+		if err != nil {
+			return plumbing.ZeroHash, err
+		}
+		tree = staged
+		b.writes[i].taskMetadataPath = taskMetadataPath
+	}
+	b.parent, b.tree = parentHash, tree
+	return tree, nil
+}
+
+// Discard drops the staged writes. The blobs and trees already written are
+// left for git gc.
+func (b *Batch) Discard() {
+	b.done = true
+	b.writes = nil
+}
+
+// checkpointIDs returns the IDs of the staged writes, in order and without
+// duplicates.
+func (b *Batch) checkpointIDs() []id.CheckpointID {
+	var ids []id.CheckpointID
+	for _, w := range b.writes {
+		if !slices.Contains(ids, w.opts.CheckpointID) {
+			ids = append(ids, w.opts.CheckpointID)
+		}
+	}
+	return ids
+}
+
+// commitMessage returns the message of the batch commit. A single write gets
+// the same message as GitStore.WriteCommitted; several list their checkpoint
+// IDs in the subject and share one trailer block.
+func (b *Batch) commitMessage() string {
+	if len(b.writes) == 1 {
+		return b.store.buildCommitMessage(b.writes[0].opts, b.writes[0].taskMetadataPath)
+	}
+
+	var msg strings.Builder
+	ids := b.checkpointIDs()
+	names := make([]string, len(ids))
+	for i, cpID := range ids {
+		names[i] = cpID.String()
+	}
+	fmt.Fprintf(&msg, "Checkpoints: %s\n\n", strings.Join(names, ", "))
+
+	var trailerLines []string
+	for _, w := range b.writes {
+		if w.opts.CommitSubject != "" {
+			msg.WriteString(w.opts.CommitSubject + "\n\n")
+		}
+		for _, line := range commitTrailers(w.opts, w.taskMetadataPath) {
+			if !slices.Contains(trailerLines, line) {
+				trailerLines = append(trailerLines, line)
+			}
+		}
+	}
+	for _, line := range trailerLines {
+		msg.WriteString(line + "\n")
+	}
+	return msg.String()
+}
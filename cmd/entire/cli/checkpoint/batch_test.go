@@ -0,0 +1,169 @@
+package checkpoint
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+func batchTestOptions(cpID id.CheckpointID, sessionID string) WriteCommittedOptions {
+	return WriteCommittedOptions{
+		CheckpointID: cpID,
+		SessionID:    sessionID,
+		Strategy:     "manual-commit",
+		Transcript:   []byte(`{"type":"user","session":"` + sessionID + `"}` + "\n"),
+		AuthorName:   "Test",
+		AuthorEmail:  "test@test.com",
+	}
+}
+
+func metadataBranchHash(t *testing.T, store *GitStore) plumbing.Hash {
+	t.Helper()
+	ref, err := store.repo.Reference(plumbing.NewBranchReferenceName(paths.MetadataBranchName), true)
+	if err != nil {
+		t.Fatalf("failed to read metadata branch: %v", err)
+	}
+	return ref.Hash()
+}
+
+func TestBatch_CommitsOnce(t *testing.T) {
+	t.Parallel()
+	repo, _ := setupBranchTestRepo(t)
+	store := NewGitStore(repo)
+	ctx := context.Background()
+	cpA := id.MustCheckpointID("a1a1a1a1a1a1")
+	cpB := id.MustCheckpointID("b2b2b2b2b2b2")
+
+	batch, err := store.Begin()
+	if err != nil {
+		t.Fatalf("Begin() error = %v", err)
+	}
+	before := metadataBranchHash(t, store)
+
+	for _, opts := range []WriteCommittedOptions{
+		batchTestOptions(cpA, "session-1"),
+		batchTestOptions(cpA, "session-2"),
+		batchTestOptions(cpB, "session-1"),
+	} {
+		if err := batch.WriteCommitted(ctx, opts); err != nil {
+			t.Fatalf("Batch.WriteCommitted() error = %v", err)
+		}
+	}
+	if metadataBranchHash(t, store) != before {
+		t.Fatal("metadata branch moved before Commit")
+	}
+	if err := batch.Commit(ctx); err != nil {
+		t.Fatalf("Commit() error = %v", err)
+	}
+
+	commit, err := repo.CommitObject(metadataBranchHash(t, store))
+	if err != nil {
+		t.Fatalf("CommitObject() error = %v", err)
+	}
+	if len(commit.ParentHashes) != 1 || commit.ParentHashes[0] != before {
+		t.Errorf("batch commit parents = %v, want [%s]", commit.ParentHashes, before)
+	}
+	if !strings.HasPrefix(commit.Message, "Checkpoints: a1a1a1a1a1a1, b2b2b2b2b2b2\n") {
+		t.Errorf("batch commit message = %q", commit.Message)
+	}
+	if strings.Count(commit.Message, "Entire-Session: session-1") != 1 {
+		t.Errorf("batch commit message repeats trailers: %q", commit.Message)
+	}
+
+	summary, err := store.ReadCommitted(ctx, cpA)
+	if err != nil || summary == nil {
+		t.Fatalf("ReadCommitted() = %v, %v", summary, err)
+	}
+	if len(summary.Sessions) != 2 {
+		t.Errorf("checkpoint A has %d sessions, want 2", len(summary.Sessions))
+	}
+	if summary, err := store.ReadCommitted(ctx, cpB); err != nil || summary == nil {
+		t.Errorf("ReadCommitted(B) = %v, %v", summary, err)
+	}
+
+	if err := batch.WriteCommitted(ctx, batchTestOptions(cpB, "session-3")); !errors.Is(err, ErrBatchDone) {
+		t.Errorf("WriteCommitted() after Commit error = %v, want ErrBatchDone", err)
+	}
+}
+
+func TestBatch_SingleWriteMatchesWriteCommitted(t *testing.T) {
+	t.Parallel()
+	repo, _ := setupBranchTestRepo(t)
+	store := NewGitStore(repo)
+	ctx := context.Background()
+
+	batch, err := store.Begin()
+	if err != nil {
+		t.Fatalf("Begin() error = %v", err)
+	}
+	opts := batchTestOptions(id.MustCheckpointID("a1a1a1a1a1a1"), "session-1")
+	if err := batch.WriteCommitted(ctx, opts); err != nil {
+		t.Fatalf("Batch.WriteCommitted() error = %v", err)
+	}
+	if err := batch.Commit(ctx); err != nil {
+		t.Fatalf("Commit() error = %v", err)
+	}
+
+	commit, err := repo.CommitObject(metadataBranchHash(t, store))
+	if err != nil {
+		t.Fatalf("CommitObject() error = %v", err)
+	}
+	if want := store.buildCommitMessage(opts, ""); commit.Message != want {
+		t.Errorf("commit message = %q, want %q", commit.Message, want)
+	}
+}
+
+func TestBatch_EmptyAndConcurrentWrite(t *testing.T) {
+	t.Parallel()
+	repo, _ := setupBranchTestRepo(t)
+	store := NewGitStore(repo)
+	ctx := context.Background()
+
+	empty, err := store.Begin()
+	if err != nil {
+		t.Fatalf("Begin() error = %v", err)
+	}
+	before := metadataBranchHash(t, store)
+	if err := empty.Commit(ctx); err != nil {
+		t.Fatalf("Commit() of an empty batch error = %v", err)
+	}
+	if metadataBranchHash(t, store) != before {
+		t.Error("empty batch moved the metadata branch")
+	}
+
+	batch, err := store.Begin()
+	if err != nil {
+		t.Fatalf("Begin() error = %v", err)
+	}
+	if err := batch.WriteCommitted(ctx, batchTestOptions(id.MustCheckpointID("a1a1a1a1a1a1"), "session-1")); err != nil {
+		t.Fatalf("Batch.WriteCommitted() error = %v", err)
+	}
+	// Another writer moves the branch meanwhile
+	if err := store.WriteCommitted(ctx, batchTestOptions(id.MustCheckpointID("b2b2b2b2b2b2"), "session-2")); err != nil {
+		t.Fatalf("WriteCommitted() error = %v", err)
+	}
+	moved := metadataBranchHash(t, store)
+	if err := batch.Commit(ctx); err != nil {
+		t.Fatalf("Commit() error = %v", err)
+	}
+
+	// The batch is restaged on top of the other write, which is kept
+	commit, err := repo.CommitObject(metadataBranchHash(t, store))
+	if err != nil {
+		t.Fatalf("CommitObject() error = %v", err)
+	}
+	if len(commit.ParentHashes) != 1 || commit.ParentHashes[0] != moved {
+		t.Errorf("batch commit parents = %v, want [%s]", commit.ParentHashes, moved)
+	}
+	for _, cpID := range []id.CheckpointID{"a1a1a1a1a1a1", "b2b2b2b2b2b2"} {
+		if summary, err := store.ReadCommitted(ctx, cpID); err != nil || summary == nil {
+			t.Errorf("ReadCommitted(%s) = %v, %v; want both checkpoints", cpID, summary, err)
+		}
+	}
+}
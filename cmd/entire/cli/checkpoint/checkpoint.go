@@ -9,11 +9,14 @@ package checkpoint
 import (
 	"context"
 	"errors"
+	"io"
 	"time"
 
+	"github.com/entireio/cli/cmd/entire/cli/age"
 	"github.com/entireio/cli/cmd/entire/cli/agent"
 	"github.com/entireio/cli/cmd/entire/cli/agent/types"
 	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+	"github.com/entireio/cli/cmd/entire/cli/transcript"
 
 	"github.com/go-git/go-git/v5/plumbing"
 )
@@ -25,6 +28,10 @@ var (
 
 	// ErrNoTranscript is returned when a checkpoint exists but has no transcript.
 	ErrNoTranscript = errors.New("no transcript found for checkpoint")
+
+	// ErrSessionNotFound is returned when a checkpoint has no session with
+	// the requested session ID.
+	ErrSessionNotFound = errors.New("session not found")
 )
 
 // Checkpoint represents a save point within a session.
@@ -100,9 +107,24 @@ type Store interface {
 	// Useful when you have the session ID but don't know its index within the checkpoint.
 	ReadSessionContentByID(ctx context.Context, checkpointID id.CheckpointID, sessionID string) (*SessionContent, error)
 
+	// ListSessions returns the sessions of a checkpoint with their session IDs,
+	// without loading their content.
+	ListSessions(ctx context.Context, checkpointID id.CheckpointID) ([]SessionRef, error)
+
+	// ReadLatestSessionContent reads the content of the checkpoint's most recent session.
+	ReadLatestSessionContent(ctx context.Context, checkpointID id.CheckpointID) (*SessionContent, error)
+
+	// OpenTranscript streams a session's transcript instead of loading it into
+	// memory. sessionIndex is 0-based. The caller must close the reader.
+	OpenTranscript(ctx context.Context, checkpointID id.CheckpointID, sessionIndex int) (io.ReadCloser, error)
+
 	// ListCommitted lists all committed checkpoints.
 	ListCommitted(ctx context.Context) ([]CommittedInfo, error)
 
+	// ListCommittedPage lists a page of committed checkpoints, most recent
+	// first, optionally filtered by session and creation time.
+	ListCommittedPage(ctx context.Context, opts ListOptions) (*CommittedPage, error)
+
 	// UpdateCommitted replaces the transcript, prompts, and context for an existing
 	// committed checkpoint. Used at stop time to finalize checkpoints with the full
 	// session transcript (prompt to stop event).
@@ -110,6 +132,53 @@ type Store interface {
 	UpdateCommitted(ctx context.Context, opts UpdateCommittedOptions) error
 }
 
+// CommittedStore is the API commands use for committed checkpoints. GitStore
+// implements it on the entire/checkpoints/v1 branch and ObjectStore in an
+// object store bucket (the checkpoint_store setting), so commands work the
+// same with either.
+type CommittedStore interface {
+	Store
+
+	// SetIdentities sets the identities used to decrypt encrypted checkpoints.
+	SetIdentities(ids []*age.Identity)
+
+	// ReadSessionMetadata reads the metadata of each session of a checkpoint,
+	// without their content.
+	ReadSessionMetadata(ctx context.Context, checkpointID id.CheckpointID) ([]CommittedMetadata, error)
+
+	// ReadAgentConfig reads the agent config files stored with a session.
+	ReadAgentConfig(ctx context.Context, checkpointID id.CheckpointID, sessionIndex int) (map[string][]byte, error)
+
+	// UpdateSummary replaces the AI summary of a checkpoint's latest session.
+	UpdateSummary(ctx context.Context, checkpointID id.CheckpointID, summary *Summary) error
+
+	// AddComment and ReadComments manage a checkpoint's review comments.
+	AddComment(ctx context.Context, checkpointID id.CheckpointID, comment Comment) (Comment, error)
+	ReadComments(ctx context.Context, checkpointID id.CheckpointID) ([]Comment, error)
+
+	// Tag, Untag, and ListByTag manage checkpoint tags.
+	Tag(ctx context.Context, checkpointID id.CheckpointID, tags ...string) error
+	Untag(ctx context.Context, checkpointID id.CheckpointID, tags ...string) error
+	ListByTag(ctx context.Context, tag string) ([]CommittedInfo, error)
+
+	// DeleteCommitted and PruneCommitted remove checkpoints.
+	DeleteCommitted(ctx context.Context, checkpointID id.CheckpointID, opts DeleteOptions) error
+	PruneCommitted(ctx context.Context, checkpointIDs []id.CheckpointID, message string) (int, error)
+
+	// DiffCheckpoints compares two checkpoints.
+	DiffCheckpoints(ctx context.Context, a, b id.CheckpointID) (*CheckpointDiff, error)
+
+	// VerifyCommitted checks every checkpoint against its content hashes.
+	VerifyCommitted(ctx context.Context) ([]CheckpointVerification, error)
+
+	// ExportCheckpoint and ImportCheckpoint move a checkpoint's files in and
+	// out of bundles; the shadow branch variants always use the local repository.
+	ExportCheckpoint(ctx context.Context, checkpointID id.CheckpointID) (map[string][]byte, error)
+	ImportCheckpoint(ctx context.Context, checkpointID id.CheckpointID, files map[string][]byte) error
+	ExportShadowBranch(ctx context.Context, branchName string) (*ShadowSnapshot, error)
+	ImportShadowBranch(ctx context.Context, branchName string, snapshot *ShadowSnapshot) error
+}
+
 // WriteTemporaryResult contains the result of writing a temporary checkpoint.
 type WriteTemporaryResult struct {
 	// CommitHash is the hash of the created or existing checkpoint commit
@@ -197,6 +266,31 @@ type TemporaryInfo struct {
 	Timestamp time.Time
 }
 
+// ContentLevel controls how much session content a committed checkpoint stores.
+// Structural metadata (files touched, token usage, attribution) is stored at
+// every level.
+type ContentLevel string
+
+const (
+	// ContentFull stores the transcript, prompts, and context.
+	ContentFull ContentLevel = "full"
+	// ContentPrompts stores prompts and context but not the transcript.
+	ContentPrompts ContentLevel = "prompts"
+	// ContentMetadata stores no session content.
+	ContentMetadata ContentLevel = "metadata"
+)
+
+// StoresTranscript reports whether the level keeps transcripts. The zero
+// value is treated as ContentFull.
+func (l ContentLevel) StoresTranscript() bool {
+	return l == "" || l == ContentFull
+}
+
+// StoresPrompts reports whether the level keeps prompts and context.
+func (l ContentLevel) StoresPrompts() bool {
+	return l != ContentMetadata
+}
+
 // WriteCommittedOptions contains options for writing a committed checkpoint.
 type WriteCommittedOptions struct {
 	// CheckpointID is the stable 12-hex-char identifier
@@ -214,12 +308,39 @@ type WriteCommittedOptions struct {
 	// Transcript is the session transcript content (full.jsonl)
 	Transcript []byte
 
+	// TranscriptPointer, if set, stores a pointer to the agent's transcript
+	// file instead of a copy. Falls back to copying Transcript if the file
+	// can't be read.
+	TranscriptPointer *TranscriptPointerOptions
+
+	// ContentLevel limits the session content stored. Empty stores everything.
+	// Later updates to the checkpoint honour the level it was written with.
+	ContentLevel ContentLevel
+
+	// EncryptTo, if set, age-encrypts the transcript, prompts, and context
+	// to these recipients. Metadata stays readable.
+	EncryptTo []*age.Recipient
+
 	// Prompts contains user prompts from the session
 	Prompts []string
 
 	// Context is the generated context.md content
 	Context []byte
 
+	// StructuredContext, if set, is stored as context.json. When Context is
+	// empty, context.md is rendered from it.
+	StructuredContext *SessionContext
+
+	// Attachments are files such as screenshots or logs stored under the
+	// session's attachments/ directory, subject to MaxAttachmentBytes and
+	// MaxSessionAttachmentsBytes. Only stored at levels that keep transcripts.
+	Attachments []Attachment
+
+	// Artifacts are files the agent produced, such as plan files or test
+	// logs, keyed by file name and stored under the session's artifacts/
+	// directory. Names and sizes follow the same rules as Attachments.
+	Artifacts map[string][]byte
+
 	// FilesTouched are files modified during the session
 	FilesTouched []string
 
@@ -265,6 +386,40 @@ type WriteCommittedOptions struct {
 	// TurnID correlates checkpoints from the same agent turn.
 	TurnID string
 
+	// CorrelationID links the session to sessions in other repositories.
+	CorrelationID string
+
+	// Label is the conventional-commit type of the checkpoint (feat, fix, ...).
+	Label string
+
+	// Model and AgentVersion identify the model and agent client that produced the session.
+	Model        string
+	AgentVersion string
+
+	// DiffStats is the line/file change count of the linked commit.
+	// Nil keeps the stats already stored for the checkpoint, if any.
+	DiffStats *DiffStats
+
+	// Turns holds the timing of each agent turn since the previous checkpoint.
+	Turns []TurnTiming
+
+	// AgentConfig holds the agent's config files (CLAUDE.md, .cursorrules, ...)
+	// keyed by repo-relative path. Nil when snapshot_agent_config is disabled.
+	AgentConfig map[string][]byte
+
+	// Instructions maps the agent's instruction files (CLAUDE.md, ...) to the
+	// git blob hash of their content, recorded whatever snapshot_agent_config
+	// says. Nil when the agent has none.
+	Instructions map[string]string
+
+	// Permissions holds the shell commands, writes outside the repository,
+	// and denied tool calls since the previous checkpoint.
+	Permissions []transcript.ToolPermission
+
+	// EscapeFlags holds the permission events that escape detection flagged
+	// for review.
+	EscapeFlags []transcript.EscapeFlag
+
 	// Transcript position at checkpoint start - tracks what was added during this checkpoint
 	TranscriptIdentifierAtStart string // Last identifier when checkpoint started (UUID for Claude, message ID for Gemini)
 	CheckpointTranscriptStart   int    // Transcript line offset at start of this checkpoint's data
@@ -275,6 +430,10 @@ type WriteCommittedOptions struct {
 	// TokenUsage contains the token usage for this checkpoint
 	TokenUsage *agent.TokenUsage
 
+	// EstimatedCostUSD is TokenUsage priced at Model's list price
+	// (see agent.EstimateCost); zero when the model's price is unknown.
+	EstimatedCostUSD float64
+
 	// InitialAttribution is line-level attribution calculated at commit time
 	// comparing checkpoint tree (agent work) to committed tree (may include human edits)
 	InitialAttribution *InitialAttribution
@@ -302,14 +461,58 @@ type UpdateCommittedOptions struct {
 	// Transcript is the full session transcript (replaces existing)
 	Transcript []byte
 
-	// Prompts contains all user prompts (replaces existing)
+	// TranscriptPointer, if set, replaces the transcript with a pointer to the
+	// agent's transcript file, as in WriteCommittedOptions.
+	TranscriptPointer *TranscriptPointerOptions
+
+	// Prompts contains all user prompts (replaces existing). Empty leaves the
+	// stored prompts unchanged; use ClearPrompts to remove them.
 	Prompts []string
 
-	// Context is the updated context.md content (replaces existing)
+	// ClearPrompts removes the stored prompts. Can't be combined with Prompts.
+	ClearPrompts bool
+
+	// Context is the updated context.md content (replaces existing). Empty
+	// leaves the stored context unchanged; use ClearContext to remove it.
 	Context []byte
 
+	// ClearContext removes the stored context.md and context.json. Can't be
+	// combined with Context or StructuredContext.
+	ClearContext bool
+
+	// StructuredContext is the updated context.json content, as in
+	// WriteCommittedOptions. Replacing Context without it removes context.json.
+	StructuredContext *SessionContext
+
 	// Agent identifies the agent type (needed for transcript chunking)
 	Agent types.AgentType
+
+	// EncryptTo, if set, age-encrypts the replaced content, as in
+	// WriteCommittedOptions.
+	EncryptTo []*age.Recipient
+
+	// Strict makes the update fail with ErrSessionNotFound when no session in
+	// the checkpoint has SessionID, instead of updating the latest session.
+	Strict bool
+}
+
+// AppendTranscriptOptions contains parameters for appending to the transcript
+// of a committed checkpoint.
+type AppendTranscriptOptions struct {
+	// CheckpointID identifies the checkpoint to append to
+	CheckpointID id.CheckpointID
+
+	// SessionID identifies which session slot to append to within the
+	// checkpoint. Unlike UpdateCommitted, there is no fallback to the latest
+	// session.
+	SessionID string
+
+	// Chunk is the JSONL to append: whole lines written since the last update
+	Chunk []byte
+
+	// EncryptTo, if set, age-encrypts the appended chunk, as in
+	// WriteCommittedOptions.
+	EncryptTo []*age.Recipient
 }
 
 // CommittedInfo contains summary information about a committed checkpoint.
@@ -338,11 +541,39 @@ type CommittedInfo struct {
 	// ToolUseID is the tool use ID for task checkpoints
 	ToolUseID string
 
+	// CorrelationID is the cross-repo correlation ID of the most recent session
+	CorrelationID string
+
+	// Label is the classification of the most recent session (feat, fix, ...)
+	Label string
+
+	// Model is the model of the most recent session
+	Model string
+
+	// Branch is the branch of the most recent session (empty if detached HEAD)
+	Branch string
+
+	// DiffStats is the change size of the linked commit (nil for older checkpoints)
+	DiffStats *DiffStats
+
+	// Tags are the labels users put on the checkpoint, sorted
+	Tags []string
+
 	// Multi-session support
 	SessionCount int      // Number of sessions (1 if single session)
 	SessionIDs   []string // All session IDs that contributed
 }
 
+// SessionRef identifies one session of a checkpoint. SessionID is stable;
+// Index is the session's slot in the checkpoint, which ReadSessionContent
+// takes, and only stays valid while the checkpoint isn't rewritten.
+type SessionRef struct {
+	Index     int
+	SessionID string
+	Agent     types.AgentType
+	CreatedAt time.Time
+}
+
 // SessionContent contains the actual content for a session.
 // This is used when reading full session data (transcript, prompts, context)
 // as opposed to just the metadata/summary.
@@ -353,15 +584,31 @@ type SessionContent struct {
 	// Transcript is the session transcript content
 	Transcript []byte
 
-	// Prompts contains user prompts from this session
+	// Prompts contains user prompts from this session, joined with
+	// PromptSeparator as stored in prompt.txt
 	Prompts string
 
+	// PromptList contains the same prompts individually. Prefer it to
+	// splitting Prompts, since a prompt can contain the separator.
+	PromptList []string
+
 	// Context is the context.md content
 	Context string
+
+	// StructuredContext is the parsed context.json, or nil for checkpoints
+	// written with only context.md
+	StructuredContext *SessionContext
+
+	// Artifacts holds the content of the files listed in Metadata.Artifacts,
+	// keyed by name
+	Artifacts map[string][]byte
 }
 
 // CommittedMetadata contains the metadata stored in metadata.json for each checkpoint.
 type CommittedMetadata struct {
+	// SchemaVersion is the metadata format version (see CurrentSchemaVersion);
+	// zero for checkpoints written before versioning
+	SchemaVersion    int             `json:"schema_version,omitempty"`
 	CLIVersion       string          `json:"cli_version,omitempty"`
 	CheckpointID     id.CheckpointID `json:"checkpoint_id"`
 	SessionID        string          `json:"session_id"`
@@ -379,6 +626,32 @@ type CommittedMetadata struct {
 	// but they share the same TurnID for future aggregation/deduplication.
 	TurnID string `json:"turn_id,omitempty"`
 
+	// CorrelationID links this session to sessions in other repositories
+	// that share the same ID (cross-repo work).
+	CorrelationID string `json:"correlation_id,omitempty"`
+
+	// Label classifies the checkpoint as feat, fix, refactor, test, or docs.
+	Label string `json:"label,omitempty"`
+
+	// Model and AgentVersion identify the model and agent client that produced the session.
+	Model        string `json:"model,omitempty"`
+	AgentVersion string `json:"agent_version,omitempty"`
+
+	// Turns holds the timing of each agent turn since the session's previous checkpoint.
+	Turns []TurnTiming `json:"turns,omitempty"`
+
+	// Instructions maps the agent's instruction files to the git blob hash
+	// of their content at the checkpoint
+	Instructions map[string]string `json:"instructions,omitempty"`
+
+	// Permissions holds the shell commands, writes outside the repository,
+	// and denied tool calls since the session's previous checkpoint.
+	Permissions []transcript.ToolPermission `json:"permissions,omitempty"`
+
+	// EscapeFlags marks the session for review: its permission events
+	// suggest the agent acted outside its sandbox.
+	EscapeFlags []transcript.EscapeFlag `json:"escape_flags,omitempty"`
+
 	// Task checkpoint fields (only populated for task checkpoints)
 	IsTask    bool   `json:"is_task,omitempty"`
 	ToolUseID string `json:"tool_use_id,omitempty"`
@@ -393,11 +666,43 @@ type CommittedMetadata struct {
 	// Token usage for this checkpoint
 	TokenUsage *agent.TokenUsage `json:"token_usage,omitempty"`
 
+	// EstimatedCostUSD is TokenUsage priced at Model's list price; zero when unknown
+	EstimatedCostUSD float64 `json:"estimated_cost_usd,omitempty"`
+
 	// AI-generated summary of the checkpoint
 	Summary *Summary `json:"summary,omitempty"`
 
 	// InitialAttribution is line-level attribution calculated at commit time
 	InitialAttribution *InitialAttribution `json:"initial_attribution,omitempty"`
+
+	// ContentLevel records which session content was stored (empty means full)
+	ContentLevel ContentLevel `json:"content_level,omitempty"`
+
+	// PurgedAt is set when the session's content was purged from the metadata branch
+	PurgedAt *time.Time `json:"purged_at,omitempty"`
+
+	// PromptsNormalized and ContextNormalized record the original size of
+	// prompts or context that had to be truncated or cleaned before storing
+	PromptsNormalized *NormalizedText `json:"prompts_normalized,omitempty"`
+	ContextNormalized *NormalizedText `json:"context_normalized,omitempty"`
+
+	// TranscriptEncoding and ContextEncoding record whether the transcript
+	// and context.md are stored compressed (EncodingZstd); empty means as is
+	TranscriptEncoding string `json:"transcript_encoding,omitempty"`
+	ContextEncoding    string `json:"context_encoding,omitempty"`
+
+	// TranscriptRecipients fingerprints the age recipients the transcript is
+	// encrypted to, so a checkpoint with the same transcript can reuse its
+	// chunks; empty when the transcript isn't encrypted
+	TranscriptRecipients string `json:"transcript_recipients,omitempty"`
+
+	// Attachments lists the files stored under the session's attachments/
+	// directory. Their content is only read through ReadAttachment.
+	Attachments []AttachmentInfo `json:"attachments,omitempty"`
+
+	// Artifacts lists the files stored under the session's artifacts/
+	// directory. ReadSessionContent returns their content.
+	Artifacts []AttachmentInfo `json:"artifacts,omitempty"`
 }
 
 // GetTranscriptStart returns the transcript line offset at which this checkpoint's data begins.
@@ -419,6 +724,8 @@ type SessionFilePaths struct {
 	Context     string `json:"context"`
 	ContentHash string `json:"content_hash"`
 	Prompt      string `json:"prompt"`
+	Prompts     string `json:"prompts,omitempty"`
+	ContextJSON string `json:"context_json,omitempty"`
 }
 
 // CheckpointSummary is the root-level metadata.json for a checkpoint.
@@ -430,17 +737,24 @@ type SessionFilePaths struct {
 //
 //	<checkpoint-id[:2]>/<checkpoint-id[2:]>/
 //	├── metadata.json         # This CheckpointSummary
+//	├── comments/             # Team comments, one file per comment
 //	├── 1/                    # First session
 //	│   ├── metadata.json     # Session-specific CommittedMetadata
 //	│   ├── full.jsonl
 //	│   ├── prompt.txt
+//	│   ├── prompts.json
 //	│   ├── context.md
-//	│   └── content_hash.txt
+//	│   ├── context.json
+//	│   ├── content_hash.txt
+//	│   └── attachments/
 //	├── 2/                    # Second session
 //	└── 3/                    # Third session...
 //
 //nolint:revive // Named CheckpointSummary to avoid conflict with existing Summary struct
 type CheckpointSummary struct {
+	// SchemaVersion is the metadata format version (see CurrentSchemaVersion);
+	// zero for checkpoints written before versioning
+	SchemaVersion    int                `json:"schema_version,omitempty"`
 	CLIVersion       string             `json:"cli_version,omitempty"`
 	CheckpointID     id.CheckpointID    `json:"checkpoint_id"`
 	Strategy         string             `json:"strategy"`
@@ -449,6 +763,10 @@ type CheckpointSummary struct {
 	FilesTouched     []string           `json:"files_touched"`
 	Sessions         []SessionFilePaths `json:"sessions"`
 	TokenUsage       *agent.TokenUsage  `json:"token_usage,omitempty"`
+	EstimatedCostUSD float64            `json:"estimated_cost_usd,omitempty"` // Sum of the sessions' estimates
+	Models           []string           `json:"models,omitempty"`             // Sorted models of the sessions
+	DiffStats        *DiffStats         `json:"diff_stats,omitempty"`
+	Tags             []string           `json:"tags,omitempty"` // User labels, see GitStore.Tag
 }
 
 // Summary contains AI-generated summary of a checkpoint.
@@ -492,6 +810,7 @@ type CodeLearning struct {
 type InitialAttribution struct {
 	CalculatedAt    time.Time `json:"calculated_at"`
 	AgentLines      int       `json:"agent_lines"`      // Lines added by agent (base → shadow diff)
+	AgentWritten    int       `json:"agent_written"`    // Lines the agent wrote, before human edits (0 for older checkpoints)
 	HumanAdded      int       `json:"human_added"`      // Lines added by human (excluding modifications)
 	HumanModified   int       `json:"human_modified"`   // Lines modified by human (estimate: min(added, removed))
 	HumanRemoved    int       `json:"human_removed"`    // Lines removed by human (excluding modifications)
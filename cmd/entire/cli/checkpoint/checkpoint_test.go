@@ -933,8 +933,8 @@ func TestWriteCommitted_MultipleSessionsSameCheckpoint(t *testing.T) {
 }
 
 // TestWriteCommitted_Aggregation verifies that CheckpointSummary correctly
-// aggregates statistics (CheckpointsCount, FilesTouched, TokenUsage) from
-// multiple sessions written to the same checkpoint.
+// aggregates statistics (CheckpointsCount, FilesTouched, TokenUsage, cost,
+// models) from multiple sessions written to the same checkpoint.
 func TestWriteCommitted_Aggregation(t *testing.T) {
 	repo, _ := setupBranchTestRepo(t)
 	store := NewGitStore(repo)
@@ -953,8 +953,10 @@ func TestWriteCommitted_Aggregation(t *testing.T) {
 			OutputTokens: 50,
 			APICallCount: 5,
 		},
-		AuthorName:  "Test Author",
-		AuthorEmail: "test@example.com",
+		Model:            "claude-sonnet-4-5",
+		EstimatedCostUSD: 0.25,
+		AuthorName:       "Test Author",
+		AuthorEmail:      "test@example.com",
 	})
 	if err != nil {
 		t.Fatalf("WriteCommitted() first session error = %v", err)
@@ -973,8 +975,10 @@ func TestWriteCommitted_Aggregation(t *testing.T) {
 			OutputTokens: 25,
 			APICallCount: 3,
 		},
-		AuthorName:  "Test Author",
-		AuthorEmail: "test@example.com",
+		Model:            "claude-opus-4-5",
+		EstimatedCostUSD: 0.5,
+		AuthorName:       "Test Author",
+		AuthorEmail:      "test@example.com",
 	})
 	if err != nil {
 		t.Fatalf("WriteCommitted() second session error = %v", err)
@@ -1022,6 +1026,14 @@ func TestWriteCommitted_Aggregation(t *testing.T) {
 	if summary.TokenUsage.APICallCount != 8 {
 		t.Errorf("summary.TokenUsage.APICallCount = %d, want 8", summary.TokenUsage.APICallCount)
 	}
+
+	// Verify summed cost and the sorted models
+	if summary.EstimatedCostUSD != 0.75 {
+		t.Errorf("summary.EstimatedCostUSD = %v, want 0.75", summary.EstimatedCostUSD)
+	}
+	if len(summary.Models) != 2 || summary.Models[0] != "claude-opus-4-5" || summary.Models[1] != "claude-sonnet-4-5" {
+		t.Errorf("summary.Models = %v, want [claude-opus-4-5 claude-sonnet-4-5]", summary.Models)
+	}
 }
 
 // TestReadCommitted_ReturnsCheckpointSummary verifies that ReadCommitted returns
@@ -1275,6 +1287,49 @@ func TestReadSessionContentByID_NotFound(t *testing.T) {
 	}
 }
 
+// TestListSessions verifies that ListSessions enumerates a checkpoint's
+// sessions with their IDs, so callers can address them by ID.
+func TestListSessions(t *testing.T) {
+	repo, _ := setupBranchTestRepo(t)
+	store := NewGitStore(repo)
+	checkpointID := id.MustCheckpointID("212223242526")
+
+	for _, sid := range []string{"session-a", "session-b"} {
+		if err := store.WriteCommitted(context.Background(), WriteCommittedOptions{
+			CheckpointID: checkpointID,
+			SessionID:    sid,
+			Strategy:     "manual-commit",
+			Agent:        agent.AgentTypeClaudeCode,
+			Transcript:   []byte(`{"session": "` + sid + `"}`),
+			AuthorName:   "Test Author",
+			AuthorEmail:  "test@example.com",
+		}); err != nil {
+			t.Fatalf("WriteCommitted(%s) error = %v", sid, err)
+		}
+	}
+
+	sessions, err := store.ListSessions(context.Background(), checkpointID)
+	if err != nil {
+		t.Fatalf("ListSessions() error = %v", err)
+	}
+	if len(sessions) != 2 {
+		t.Fatalf("ListSessions() returned %d sessions, want 2", len(sessions))
+	}
+	for i, want := range []string{"session-a", "session-b"} {
+		if sessions[i].Index != i || sessions[i].SessionID != want || sessions[i].Agent != agent.AgentTypeClaudeCode {
+			t.Errorf("sessions[%d] = %+v, want index %d, session %s", i, sessions[i], i, want)
+		}
+	}
+
+	_, err = store.ReadSessionContentByID(context.Background(), checkpointID, "session-c")
+	if !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("ReadSessionContentByID() error = %v, want ErrSessionNotFound", err)
+	}
+	if _, err := store.ListSessions(context.Background(), id.MustCheckpointID("ffffffffffff")); !errors.Is(err, ErrCheckpointNotFound) {
+		t.Errorf("ListSessions() of a missing checkpoint error = %v, want ErrCheckpointNotFound", err)
+	}
+}
+
 // TestListCommitted_MultiSessionInfo verifies that ListCommitted returns correct
 // information for checkpoints with multiple sessions.
 func TestListCommitted_MultiSessionInfo(t *testing.T) {
@@ -2791,6 +2846,39 @@ func TestWriteCommitted_RedactsContextSecrets(t *testing.T) {
 	}
 }
 
+func TestWriteCommitted_AgentConfig(t *testing.T) {
+	repo, _ := setupBranchTestRepo(t)
+	store := NewGitStore(repo)
+	checkpointID := id.MustCheckpointID("aabbccddeef4")
+
+	err := store.WriteCommitted(context.Background(), WriteCommittedOptions{
+		CheckpointID: checkpointID,
+		SessionID:    "agent-config-session",
+		Strategy:     "manual-commit",
+		Transcript:   []byte(`{"msg":"safe"}`),
+		AgentConfig: map[string][]byte{
+			"CLAUDE.md":             []byte("Run tests before committing.\n"),
+			".claude/settings.json": []byte(`{"env":{"API_KEY":"` + highEntropySecret + `"}}`),
+		},
+		AuthorName:  "Test Author",
+		AuthorEmail: "test@example.com",
+	})
+	if err != nil {
+		t.Fatalf("WriteCommitted() error = %v", err)
+	}
+
+	files, err := store.ReadAgentConfig(context.Background(), checkpointID, 0)
+	if err != nil {
+		t.Fatalf("ReadAgentConfig() error = %v", err)
+	}
+	if got := string(files["CLAUDE.md"]); got != "Run tests before committing.\n" {
+		t.Errorf("CLAUDE.md = %q, want original content", got)
+	}
+	if settings := string(files[".claude/settings.json"]); settings == "" || strings.Contains(settings, highEntropySecret) {
+		t.Errorf(".claude/settings.json = %q, want redacted content", settings)
+	}
+}
+
 func TestCopyMetadataDir_RedactsSecrets(t *testing.T) {
 	tempDir := t.TempDir()
 
@@ -0,0 +1,207 @@
+package checkpoint
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+	"github.com/entireio/cli/cmd/entire/cli/jsonutil"
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+	"github.com/entireio/cli/redact"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// MaxCommentBytes caps the body of a checkpoint comment.
+const MaxCommentBytes = 16 << 10
+
+// ErrCommentNotFound is returned when a reply names a comment that doesn't exist.
+var ErrCommentNotFound = errors.New("comment not found")
+
+// Comment is a teammate's note on a committed checkpoint. Comments are
+// stored one file per comment under the checkpoint's comments/ directory,
+// so comments added on different machines never conflict when the metadata
+// branch is merged. Existing comments are never rewritten.
+type Comment struct {
+	ID string `json:"id"`
+	// ReplyTo is the ID of the comment this one answers; empty for a new thread.
+	ReplyTo     string    `json:"reply_to,omitempty"`
+	AuthorName  string    `json:"author_name,omitempty"`
+	AuthorEmail string    `json:"author_email,omitempty"`
+	Timestamp   time.Time `json:"timestamp"`
+	Body        string    `json:"body"`
+	// Verdict, if set, makes the comment a review: ReviewApproved or
+	// ReviewChangesRequested. See ComputeReviewStatus.
+	Verdict ReviewState `json:"verdict,omitempty"`
+}
+
+// ThreadedComment is a comment with its reply depth, as returned by ThreadComments.
+type ThreadedComment struct {
+	Comment
+	Depth int
+}
+
+// AddComment appends a comment to a committed checkpoint and returns it
+// with its ID filled in. Missing timestamp and author fields are filled
+// from the clock and git config. The body is normalized and redacted like
+// prompts, and may be empty only when the comment carries a Verdict.
+func (s *GitStore) AddComment(ctx context.Context, checkpointID id.CheckpointID, comment Comment) (Comment, error) {
+	if err := ctx.Err(); err != nil {
+		return Comment{}, err //nolint:wrapcheck // Propagating context cancellation
+	}
+	switch comment.Verdict {
+	case "", ReviewApproved, ReviewChangesRequested:
+	default:
+		return Comment{}, fmt.Errorf("invalid review verdict %q", comment.Verdict)
+	}
+	body, _ := normalizeText(strings.TrimSpace(comment.Body), MaxCommentBytes)
+	if body == "" && comment.Verdict == "" {
+		return Comment{}, errors.New("comment is empty")
+	}
+	comment.Body = redact.String(body)
+
+	tree, err := s.getSessionsBranchTree()
+	if err != nil {
+		return Comment{}, ErrCheckpointNotFound
+	}
+	if _, err := tree.File(checkpointID.Path() + "/" + paths.MetadataFileName); err != nil {
+		return Comment{}, ErrCheckpointNotFound
+	}
+	if comment.ReplyTo != "" {
+		existing, err := s.ReadComments(ctx, checkpointID)
+		if err != nil {
+			return Comment{}, err
+		}
+		found := false
+		for _, c := range existing {
+			found = found || c.ID == comment.ReplyTo
+		}
+		if !found {
+			return Comment{}, fmt.Errorf("%w: %s", ErrCommentNotFound, comment.ReplyTo)
+		}
+	}
+
+	commentID, err := id.Generate()
+	if err != nil {
+		return Comment{}, fmt.Errorf("failed to generate comment ID: %w", err)
+	}
+	comment.ID = commentID.String()
+	authorName, authorEmail := GetGitAuthorFromRepo(s.repo)
+	if comment.Timestamp.IsZero() {
+		comment.Timestamp = time.Now().UTC()
+	}
+	if comment.AuthorName == "" {
+		comment.AuthorName = authorName
+	}
+	if comment.AuthorEmail == "" {
+		comment.AuthorEmail = authorEmail
+	}
+
+	commentJSON, err := jsonutil.MarshalIndentWithNewline(comment, "", "  ")
+	if err != nil {
+		return Comment{}, fmt.Errorf("failed to marshal comment: %w", err)
+	}
+	blobHash, err := CreateBlobFromContent(s.repo, commentJSON)
+	if err != nil {
+		return Comment{}, fmt.Errorf("failed to create comment blob: %w", err)
+	}
+
+	fileName := fmt.Sprintf("%019d-%s.json", comment.Timestamp.UnixNano(), comment.ID)
+	segments := []string{string(checkpointID[:2]), string(checkpointID[2:]), paths.CommentsDirName}
+	err = s.commitMetadata(ctx, func(_, rootTreeHash plumbing.Hash) (*metadataCommit, error) {
+		newTreeHash, err := UpdateSubtree(s.repo, rootTreeHash, segments, []object.TreeEntry{
+			{Name: fileName, Mode: filemode.Regular, Hash: blobHash},
+		}, UpdateSubtreeOptions{MergeMode: MergeKeepExisting})
+		if err != nil {
+			return nil, fmt.Errorf("failed to update comments tree: %w", err)
+		}
+		return &metadataCommit{
+			Tree:          newTreeHash,
+			Message:       fmt.Sprintf("Comment on checkpoint %s", checkpointID),
+			AuthorName:    authorName,
+			AuthorEmail:   authorEmail,
+			CheckpointIDs: []id.CheckpointID{checkpointID},
+		}, nil
+	})
+	if err != nil {
+		return Comment{}, err
+	}
+	return comment, nil
+}
+
+// ReadComments returns a checkpoint's comments, oldest first. Returns an
+// empty slice if the checkpoint has none.
+func (s *GitStore) ReadComments(ctx context.Context, checkpointID id.CheckpointID) ([]Comment, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err //nolint:wrapcheck // Propagating context cancellation
+	}
+
+	tree, err := s.getSessionsBranchTree()
+	if err != nil {
+		return []Comment{}, nil //nolint:nilerr // No metadata branch means no comments
+	}
+	commentsTree, err := tree.Tree(checkpointID.Path() + "/" + paths.CommentsDirName)
+	if err != nil {
+		return []Comment{}, nil //nolint:nilerr // No comments directory means no comments
+	}
+
+	comments := make([]Comment, 0, len(commentsTree.Entries))
+	for _, treeEntry := range commentsTree.Entries {
+		if treeEntry.Mode != filemode.Regular || !strings.HasSuffix(treeEntry.Name, ".json") {
+			continue
+		}
+		comment, err := readJSONFromBlob[Comment](s.repo, treeEntry.Hash)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read comment %s: %w", treeEntry.Name, err)
+		}
+		comments = append(comments, *comment)
+	}
+
+	sort.SliceStable(comments, func(i, j int) bool {
+		return comments[i].Timestamp.Before(comments[j].Timestamp)
+	})
+	return comments, nil
+}
+
+// ThreadComments orders comments as threads: each top-level comment is
+// followed by its replies, depth first, oldest first at every level.
+// Replies to a missing comment are shown as top-level comments.
+func ThreadComments(comments []Comment) []ThreadedComment {
+	ids := make(map[string]bool, len(comments))
+	for _, c := range comments {
+		ids[c.ID] = true
+	}
+	replies := make(map[string][]Comment)
+	var roots []Comment
+	for _, c := range comments {
+		if c.ReplyTo == "" || !ids[c.ReplyTo] || c.ReplyTo == c.ID {
+			roots = append(roots, c)
+			continue
+		}
+		replies[c.ReplyTo] = append(replies[c.ReplyTo], c)
+	}
+
+	threaded := make([]ThreadedComment, 0, len(comments))
+	visited := make(map[string]bool, len(comments))
+	var walk func(c Comment, depth int)
+	walk = func(c Comment, depth int) {
+		if visited[c.ID] {
+			return
+		}
+		visited[c.ID] = true
+		threaded = append(threaded, ThreadedComment{Comment: c, Depth: depth})
+		for _, reply := range replies[c.ID] {
+			walk(reply, depth+1)
+		}
+	}
+	for _, root := range roots {
+		walk(root, 0)
+	}
+	return threaded
+}
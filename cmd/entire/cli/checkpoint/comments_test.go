@@ -0,0 +1,88 @@
+package checkpoint
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+)
+
+func TestAddComment_Threads(t *testing.T) {
+	t.Parallel()
+	_, store, cpID := setupRepoForUpdate(t)
+	ctx := context.Background()
+	base := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	first, err := store.AddComment(ctx, cpID, Comment{Body: "Why was the retry removed?", Timestamp: base})
+	if err != nil {
+		t.Fatalf("AddComment() error = %v", err)
+	}
+	if first.ID == "" || first.AuthorName == "" {
+		t.Errorf("AddComment() should fill ID and author, got %+v", first)
+	}
+	second, err := store.AddComment(ctx, cpID, Comment{Body: "Looks good", Timestamp: base.Add(time.Minute)})
+	if err != nil {
+		t.Fatalf("AddComment() error = %v", err)
+	}
+	reply, err := store.AddComment(ctx, cpID, Comment{ReplyTo: first.ID, Body: "  It hid real failures.\n", Timestamp: base.Add(2 * time.Minute)})
+	if err != nil {
+		t.Fatalf("AddComment() reply error = %v", err)
+	}
+
+	comments, err := store.ReadComments(ctx, cpID)
+	if err != nil {
+		t.Fatalf("ReadComments() error = %v", err)
+	}
+	if len(comments) != 3 || comments[2].Body != "It hid real failures." {
+		t.Fatalf("ReadComments() = %+v", comments)
+	}
+
+	threaded := ThreadComments(comments)
+	var order []string
+	for _, c := range threaded {
+		order = append(order, c.ID)
+	}
+	want := []string{first.ID, reply.ID, second.ID}
+	if strings.Join(order, ",") != strings.Join(want, ",") || threaded[1].Depth != 1 {
+		t.Errorf("ThreadComments() order = %v (depths %d,%d,%d), want %v with the reply nested",
+			order, threaded[0].Depth, threaded[1].Depth, threaded[2].Depth, want)
+	}
+
+	// Session content is untouched by comments
+	content, err := store.ReadSessionContent(ctx, cpID, 0)
+	if err != nil || content.Metadata.SessionID != "session-001" {
+		t.Errorf("ReadSessionContent() = %v, %v", content, err)
+	}
+}
+
+func TestAddComment_Rejects(t *testing.T) {
+	t.Parallel()
+	_, store, cpID := setupRepoForUpdate(t)
+	ctx := context.Background()
+
+	if _, err := store.AddComment(ctx, cpID, Comment{Body: "  \n"}); err == nil {
+		t.Error("AddComment() should reject an empty comment")
+	}
+	if _, err := store.AddComment(ctx, cpID, Comment{ReplyTo: "ffffffffffff", Body: "reply"}); !errors.Is(err, ErrCommentNotFound) {
+		t.Errorf("AddComment() reply to missing comment error = %v, want ErrCommentNotFound", err)
+	}
+	if _, err := store.AddComment(ctx, id.MustCheckpointID("ffffffffffff"), Comment{Body: "hi"}); !errors.Is(err, ErrCheckpointNotFound) {
+		t.Errorf("AddComment() on missing checkpoint error = %v, want ErrCheckpointNotFound", err)
+	}
+}
+
+func TestAddComment_Redacts(t *testing.T) {
+	t.Parallel()
+	_, store, cpID := setupRepoForUpdate(t)
+
+	comment, err := store.AddComment(context.Background(), cpID, Comment{Body: "key is " + highEntropySecret})
+	if err != nil {
+		t.Fatalf("AddComment() error = %v", err)
+	}
+	if strings.Contains(comment.Body, highEntropySecret) {
+		t.Errorf("comment body should be redacted: %q", comment.Body)
+	}
+}
@@ -10,11 +10,13 @@ import (
 	"log/slog"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/entireio/cli/cmd/entire/cli/age"
 	"github.com/entireio/cli/cmd/entire/cli/agent"
 	"github.com/entireio/cli/cmd/entire/cli/agent/types"
 	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
@@ -31,6 +33,7 @@ import (
 	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/filemode"
 	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage"
 	"github.com/go-git/go-git/v5/utils/binary"
 )
 
@@ -44,7 +47,34 @@ var errStopIteration = errors.New("stop iteration")
 //   - For incremental checkpoints: checkpoints/NNN-<tool-use-id>.json
 //   - For final checkpoints: checkpoint.json and agent-<agent-id>.jsonl
 func (s *GitStore) WriteCommitted(ctx context.Context, opts WriteCommittedOptions) error {
-	// Validate identifiers to prevent path traversal and malformed data
+	if err := validateWriteCommittedOptions(opts); err != nil {
+		return err
+	}
+
+	// Ensure sessions branch exists
+	if err := s.ensureSessionsBranch(); err != nil {
+		return fmt.Errorf("failed to ensure sessions branch: %w", err)
+	}
+
+	// Staged on the branch tip, and restaged if a concurrent write moves it
+	return s.commitMetadata(ctx, func(_, rootTreeHash plumbing.Hash) (*metadataCommit, error) {
+		newTreeHash, taskMetadataPath, err := s.stageCommitted(ctx, rootTreeHash, opts)
+		if err != nil {
+			return nil, err
+		}
+		return &metadataCommit{
+			Tree:          newTreeHash,
+			Message:       s.buildCommitMessage(opts, taskMetadataPath),
+			AuthorName:    opts.AuthorName,
+			AuthorEmail:   opts.AuthorEmail,
+			CheckpointIDs: []id.CheckpointID{opts.CheckpointID},
+		}, nil
+	})
+}
+
+// validateWriteCommittedOptions checks the identifiers of opts, to prevent
+// path traversal and malformed data.
+func validateWriteCommittedOptions(opts WriteCommittedOptions) error {
 	if opts.CheckpointID.IsEmpty() {
 		return errors.New("invalid checkpoint options: checkpoint ID is required")
 	}
@@ -57,18 +87,13 @@ func (s *GitStore) WriteCommitted(ctx context.Context, opts WriteCommittedOption
 	if err := validation.ValidateAgentID(opts.AgentID); err != nil {
 		return fmt.Errorf("invalid checkpoint options: %w", err)
 	}
+	return nil
+}
 
-	// Ensure sessions branch exists
-	if err := s.ensureSessionsBranch(); err != nil {
-		return fmt.Errorf("failed to ensure sessions branch: %w", err)
-	}
-
-	// Get branch ref and root tree hash (O(1), no flatten)
-	parentHash, rootTreeHash, err := s.getSessionsBranchRef()
-	if err != nil {
-		return err
-	}
-
+// stageCommitted writes the objects of a committed checkpoint and returns the
+// root tree with the checkpoint spliced into rootTreeHash, and the task
+// metadata path for the commit trailer. Nothing is committed.
+func (s *GitStore) stageCommitted(ctx context.Context, rootTreeHash plumbing.Hash, opts WriteCommittedOptions) (plumbing.Hash, string, error) {
 	// Use sharded path: <id[:2]>/<id[2:]>/
 	basePath := opts.CheckpointID.Path() + "/"
 	checkpointPath := opts.CheckpointID.Path()
@@ -76,7 +101,7 @@ func (s *GitStore) WriteCommitted(ctx context.Context, opts WriteCommittedOption
 	// Flatten only the checkpoint subtree (O(files in checkpoint))
 	entries, err := s.flattenCheckpointEntries(rootTreeHash, checkpointPath)
 	if err != nil {
-		return err
+		return plumbing.ZeroHash, "", err
 	}
 
 	// Track task metadata path for commit trailer
@@ -86,34 +111,21 @@ func (s *GitStore) WriteCommitted(ctx context.Context, opts WriteCommittedOption
 	if opts.IsTask && opts.ToolUseID != "" {
 		taskMetadataPath, err = s.writeTaskCheckpointEntries(ctx, opts, basePath, entries)
 		if err != nil {
-			return err
+			return plumbing.ZeroHash, "", err
 		}
 	}
 
 	// Write standard checkpoint entries (transcript, prompts, context, metadata)
 	if err := s.writeStandardCheckpointEntries(ctx, opts, basePath, entries); err != nil {
-		return err
+		return plumbing.ZeroHash, "", err
 	}
 
 	// Build checkpoint subtree and splice into root (O(depth) tree surgery)
 	newTreeHash, err := s.spliceCheckpointSubtree(rootTreeHash, opts.CheckpointID, basePath, entries)
 	if err != nil {
-		return err
-	}
-
-	commitMsg := s.buildCommitMessage(opts, taskMetadataPath)
-	newCommitHash, err := s.createCommit(newTreeHash, parentHash, commitMsg, opts.AuthorName, opts.AuthorEmail)
-	if err != nil {
-		return err
+		return plumbing.ZeroHash, "", err
 	}
-
-	refName := plumbing.NewBranchReferenceName(paths.MetadataBranchName)
-	newRef := plumbing.NewHashReference(refName, newCommitHash)
-	if err := s.repo.Storer.SetReference(newRef); err != nil {
-		return fmt.Errorf("failed to set branch reference: %w", err)
-	}
-
-	return nil
+	return newTreeHash, taskMetadataPath, nil
 }
 
 // flattenCheckpointEntries reads only the entries under a specific checkpoint path
@@ -298,6 +310,11 @@ func (s *GitStore) writeStandardCheckpointEntries(ctx context.Context, opts Writ
 		}
 	}
 
+	// Keep stats from an earlier session of the same checkpoint (same commit)
+	if opts.DiffStats == nil && existingSummary != nil {
+		opts.DiffStats = existingSummary.DiffStats
+	}
+
 	// Determine session index: reuse existing slot if session ID matches, otherwise append
 	sessionIndex := s.findSessionIndex(ctx, basePath, existingSummary, entries, opts.SessionID)
 
@@ -308,8 +325,9 @@ func (s *GitStore) writeStandardCheckpointEntries(ctx context.Context, opts Writ
 		return err
 	}
 
-	// Copy additional metadata files from directory if specified (to session subdirectory)
-	if opts.MetadataDir != "" {
+	// Copy additional metadata files from directory if specified (to session subdirectory).
+	// They include subagent transcripts, so only levels that keep transcripts copy them.
+	if opts.MetadataDir != "" && opts.ContentLevel.StoresTranscript() {
 		if err := s.copyMetadataDir(opts.MetadataDir, sessionPath, entries); err != nil {
 			return fmt.Errorf("failed to copy metadata directory: %w", err)
 		}
@@ -343,31 +361,43 @@ func (s *GitStore) writeSessionToSubdirectory(ctx context.Context, opts WriteCom
 	}
 
 	// Write transcript
-	if err := s.writeTranscript(ctx, opts, sessionPath, entries); err != nil {
-		return filePaths, err
+	var transcriptEncoding string
+	if opts.ContentLevel.StoresTranscript() {
+		var err error
+		if transcriptEncoding, err = s.writeTranscript(ctx, opts, sessionPath, entries); err != nil {
+			return filePaths, err
+		}
+		filePaths.Transcript = "/" + sessionPath + paths.TranscriptFileName
+		if _, ok := entries[sessionPath+paths.TranscriptPointerFileName]; ok {
+			filePaths.Transcript = "/" + sessionPath + paths.TranscriptPointerFileName
+		}
+		filePaths.ContentHash = "/" + sessionPath + paths.ContentHashFileName
 	}
-	filePaths.Transcript = "/" + sessionPath + paths.TranscriptFileName
-	filePaths.ContentHash = "/" + sessionPath + paths.ContentHashFileName
 
 	// Write prompts
-	if len(opts.Prompts) > 0 {
-		promptContent := redact.String(strings.Join(opts.Prompts, "\n\n---\n\n"))
-		blobHash, err := CreateBlobFromContent(s.repo, []byte(promptContent))
-		if err != nil {
+	var promptsNormalized, contextNormalized *NormalizedText
+	var contextEncoding string
+	if len(opts.Prompts) > 0 && opts.ContentLevel.StoresPrompts() {
+		var prompts []string
+		prompts, promptsNormalized = normalizePrompts(opts.Prompts)
+		if err := s.writePromptEntries(prompts, sessionPath, entries, opts.EncryptTo); err != nil {
 			return filePaths, err
 		}
-		entries[sessionPath+paths.PromptFileName] = object.TreeEntry{
-			Name: sessionPath + paths.PromptFileName,
-			Mode: filemode.Regular,
-			Hash: blobHash,
-		}
 		filePaths.Prompt = "/" + sessionPath + paths.PromptFileName
+		filePaths.Prompts = "/" + sessionPath + paths.PromptsFileName
 	}
 
-	// Write context
-	if len(opts.Context) > 0 {
-		blobHash, err := CreateBlobFromContent(s.repo, redact.Bytes(opts.Context))
-		if err != nil {
+	// Write context, rendering context.md from the structured form if needed
+	rawContext := opts.Context
+	if len(rawContext) == 0 && !opts.StructuredContext.IsEmpty() {
+		rawContext = opts.StructuredContext.Markdown()
+	}
+	if len(rawContext) > 0 && opts.ContentLevel.StoresPrompts() {
+		var contextContent []byte
+		contextContent, contextNormalized = normalizeContext(rawContext)
+		var blobHash plumbing.Hash
+		var err error
+		if blobHash, contextEncoding, err = s.writeContextBlob(redact.Bytes(contextContent), true, opts.EncryptTo); err != nil {
 			return filePaths, err
 		}
 		entries[sessionPath+paths.ContextFileName] = object.TreeEntry{
@@ -377,9 +407,48 @@ func (s *GitStore) writeSessionToSubdirectory(ctx context.Context, opts WriteCom
 		}
 		filePaths.Context = "/" + sessionPath + paths.ContextFileName
 	}
+	if !opts.StructuredContext.IsEmpty() && opts.ContentLevel.StoresPrompts() {
+		if err := s.writeStructuredContext(opts.StructuredContext, sessionPath, entries, opts.EncryptTo); err != nil {
+			return filePaths, err
+		}
+		filePaths.ContextJSON = "/" + sessionPath + paths.ContextJSONFileName
+	}
+
+	// Write attachments
+	var attachments []AttachmentInfo
+	if len(opts.Attachments) > 0 && opts.ContentLevel.StoresTranscript() {
+		var err error
+		if attachments, err = s.writeAttachments(ctx, opts.Attachments, sessionPath, entries); err != nil {
+			return filePaths, err
+		}
+	}
+	var artifacts []AttachmentInfo
+	if len(opts.Artifacts) > 0 && opts.ContentLevel.StoresTranscript() {
+		var err error
+		if artifacts, err = s.writeArtifacts(ctx, opts.Artifacts, sessionPath, entries); err != nil {
+			return filePaths, err
+		}
+	}
+
+	// Write agent config snapshot, encrypted like prompts
+	if opts.ContentLevel.StoresPrompts() {
+		for configPath, content := range opts.AgentConfig {
+			blobHash, err := s.createContentBlob(redact.Bytes(content), opts.EncryptTo)
+			if err != nil {
+				return filePaths, fmt.Errorf("failed to create agent config blob: %w", err)
+			}
+			name := sessionPath + paths.AgentConfigDirName + "/" + configPath
+			entries[name] = object.TreeEntry{
+				Name: name,
+				Mode: filemode.Regular,
+				Hash: blobHash,
+			}
+		}
+	}
 
 	// Write session-level metadata.json (CommittedMetadata with all fields including initial_attribution)
 	sessionMetadata := CommittedMetadata{
+		SchemaVersion:               CurrentSchemaVersion,
 		CheckpointID:                opts.CheckpointID,
 		SessionID:                   opts.SessionID,
 		Strategy:                    opts.Strategy,
@@ -389,15 +458,34 @@ func (s *GitStore) writeSessionToSubdirectory(ctx context.Context, opts WriteCom
 		FilesTouched:                opts.FilesTouched,
 		Agent:                       opts.Agent,
 		TurnID:                      opts.TurnID,
+		CorrelationID:               opts.CorrelationID,
+		Label:                       opts.Label,
+		Model:                       opts.Model,
+		AgentVersion:                opts.AgentVersion,
+		Turns:                       opts.Turns,
+		Instructions:                opts.Instructions,
+		Permissions:                 opts.Permissions,
+		EscapeFlags:                 opts.EscapeFlags,
 		IsTask:                      opts.IsTask,
 		ToolUseID:                   opts.ToolUseID,
 		TranscriptIdentifierAtStart: opts.TranscriptIdentifierAtStart,
 		CheckpointTranscriptStart:   opts.CheckpointTranscriptStart,
 		TranscriptLinesAtStart:      opts.CheckpointTranscriptStart, // Deprecated: kept for backward compat
 		TokenUsage:                  opts.TokenUsage,
+		EstimatedCostUSD:            opts.EstimatedCostUSD,
 		InitialAttribution:          opts.InitialAttribution,
 		Summary:                     redactSummary(opts.Summary),
 		CLIVersion:                  versioninfo.Version,
+		PromptsNormalized:           promptsNormalized,
+		ContextNormalized:           contextNormalized,
+		TranscriptEncoding:          transcriptEncoding,
+		TranscriptRecipients:        recipientsFingerprint(opts.EncryptTo),
+		ContextEncoding:             contextEncoding,
+		Attachments:                 attachments,
+		Artifacts:                   artifacts,
+	}
+	if !opts.ContentLevel.StoresTranscript() {
+		sessionMetadata.ContentLevel = opts.ContentLevel
 	}
 
 	metadataJSON, err := jsonutil.MarshalIndentWithNewline(sessionMetadata, "", "  ")
@@ -421,24 +509,36 @@ func (s *GitStore) writeSessionToSubdirectory(ctx context.Context, opts WriteCom
 // writeCheckpointSummary writes the root-level CheckpointSummary with aggregated statistics.
 // sessions is the complete sessions array (already built by the caller).
 func (s *GitStore) writeCheckpointSummary(opts WriteCommittedOptions, basePath string, entries map[string]object.TreeEntry, sessions []SessionFilePaths) error {
-	checkpointsCount, filesTouched, tokenUsage, err :=
-		s.reaggregateFromEntries(basePath, len(sessions), entries)
+	totals, err := s.reaggregateFromEntries(basePath, len(sessions), entries)
 	if err != nil {
 		return fmt.Errorf("failed to aggregate session stats: %w", err)
 	}
 
 	summary := CheckpointSummary{
+		SchemaVersion:    CurrentSchemaVersion,
 		CheckpointID:     opts.CheckpointID,
 		CLIVersion:       versioninfo.Version,
 		Strategy:         opts.Strategy,
 		Branch:           opts.Branch,
-		CheckpointsCount: checkpointsCount,
-		FilesTouched:     filesTouched,
+		CheckpointsCount: totals.checkpointsCount,
+		FilesTouched:     totals.filesTouched,
 		Sessions:         sessions,
-		TokenUsage:       tokenUsage,
+		TokenUsage:       totals.tokenUsage,
+		EstimatedCostUSD: totals.estimatedCostUSD,
+		Models:           totals.models,
+		DiffStats:        opts.DiffStats,
 	}
 
-	metadataJSON, err := jsonutil.MarshalIndentWithNewline(summary, "", "  ")
+	var metadataJSON []byte
+	if existing, ok := entries[basePath+paths.MetadataFileName]; ok {
+		// Tags belong to the checkpoint, not a session, so adding a session keeps them
+		if existingSummary, readErr := s.readSummaryFromBlob(existing.Hash); readErr == nil {
+			summary.Tags = existingSummary.Tags
+		}
+		metadataJSON, err = s.marshalUpdatedMetadata(summary, existing.Hash)
+	} else {
+		metadataJSON, err = jsonutil.MarshalIndentWithNewline(summary, "", "  ")
+	}
 	if err != nil {
 		return fmt.Errorf("failed to marshal checkpoint summary: %w", err)
 	}
@@ -480,29 +580,42 @@ func (s *GitStore) findSessionIndex(ctx context.Context, basePath string, existi
 	return len(existingSummary.Sessions)
 }
 
+// sessionTotals are the session stats a CheckpointSummary aggregates.
+type sessionTotals struct {
+	checkpointsCount int
+	filesTouched     []string
+	tokenUsage       *agent.TokenUsage
+	estimatedCostUSD float64
+	models           []string
+}
+
 // reaggregateFromEntries reads all session metadata from the entries map and
-// reaggregates CheckpointsCount, FilesTouched, and TokenUsage.
-func (s *GitStore) reaggregateFromEntries(basePath string, sessionCount int, entries map[string]object.TreeEntry) (int, []string, *agent.TokenUsage, error) {
-	var totalCount int
-	var allFiles []string
-	var totalTokens *agent.TokenUsage
+// reaggregates CheckpointsCount, FilesTouched, TokenUsage, the estimated
+// cost, and the models used.
+func (s *GitStore) reaggregateFromEntries(basePath string, sessionCount int, entries map[string]object.TreeEntry) (sessionTotals, error) {
+	var totals sessionTotals
 
 	for i := range sessionCount {
 		path := fmt.Sprintf("%s%d/%s", basePath, i, paths.MetadataFileName)
 		entry, exists := entries[path]
 		if !exists {
-			return 0, nil, nil, fmt.Errorf("session %d metadata not found at %s", i, path)
+			return sessionTotals{}, fmt.Errorf("session %d metadata not found at %s", i, path)
 		}
 		meta, err := s.readMetadataFromBlob(entry.Hash)
 		if err != nil {
-			return 0, nil, nil, fmt.Errorf("failed to read session %d metadata: %w", i, err)
+			return sessionTotals{}, fmt.Errorf("failed to read session %d metadata: %w", i, err)
+		}
+		totals.checkpointsCount += meta.CheckpointsCount
+		totals.filesTouched = mergeFilesTouched(totals.filesTouched, meta.FilesTouched)
+		totals.tokenUsage = aggregateTokenUsage(totals.tokenUsage, meta.TokenUsage)
+		totals.estimatedCostUSD += meta.EstimatedCostUSD
+		if meta.Model != "" && !slices.Contains(totals.models, meta.Model) {
+			totals.models = append(totals.models, meta.Model)
 		}
-		totalCount += meta.CheckpointsCount
-		allFiles = mergeFilesTouched(allFiles, meta.FilesTouched)
-		totalTokens = aggregateTokenUsage(totalTokens, meta.TokenUsage)
 	}
+	slices.Sort(totals.models)
 
-	return totalCount, allFiles, totalTokens, nil
+	return totals, nil
 }
 
 // readJSONFromBlob reads JSON from a blob hash and decodes it to the given type.
@@ -557,7 +670,14 @@ func aggregateTokenUsage(a, b *agent.TokenUsage) *agent.TokenUsage {
 
 // writeTranscript writes the transcript file from in-memory content or file path.
 // If the transcript exceeds MaxChunkSize, it's split into multiple chunk files.
-func (s *GitStore) writeTranscript(ctx context.Context, opts WriteCommittedOptions, basePath string, entries map[string]object.TreeEntry) error {
+// Returns the encoding the transcript was stored with.
+func (s *GitStore) writeTranscript(ctx context.Context, opts WriteCommittedOptions, basePath string, entries map[string]object.TreeEntry) (string, error) {
+	if opts.TranscriptPointer != nil {
+		if ok, err := s.writeTranscriptPointer(ctx, opts.TranscriptPointer, basePath, entries); err != nil || ok {
+			return "", err
+		}
+	}
+
 	transcript := opts.Transcript
 	if len(transcript) == 0 && opts.TranscriptPath != "" {
 		var readErr error
@@ -568,28 +688,50 @@ func (s *GitStore) writeTranscript(ctx context.Context, opts WriteCommittedOptio
 		}
 	}
 	if len(transcript) == 0 {
-		return nil
+		return "", nil
 	}
 
 	// Redact secrets before chunking so content hash reflects redacted content
 	transcript, err := redact.JSONLBytes(transcript)
 	if err != nil {
-		return fmt.Errorf("failed to redact transcript secrets: %w", err)
+		return "", fmt.Errorf("failed to redact transcript secrets: %w", err)
 	}
+	return s.writeTranscriptChunks(ctx, transcript, opts.Agent, basePath, entries, true, opts.EncryptTo)
+}
 
-	// Chunk the transcript if it's too large
-	chunks, err := agent.ChunkTranscript(ctx, transcript, opts.Agent)
-	if err != nil {
-		return fmt.Errorf("failed to chunk transcript: %w", err)
+// writeTranscriptChunks chunks a redacted transcript, compresses the chunks
+// if allowed and the transcript is large, encrypts them to recipients if
+// any, and writes them with the content hash. Returns the encoding the
+// chunks were stored with.
+func (s *GitStore) writeTranscriptChunks(ctx context.Context, transcript []byte, agentType types.AgentType, basePath string, entries map[string]object.TreeEntry, allowCompression bool, recipients []*age.Recipient) (string, error) {
+	var encoding string
+	if allowCompression && shouldCompress(len(transcript)) {
+		encoding = EncodingZstd
 	}
 
-	// Write chunk files
-	for i, chunk := range chunks {
-		chunkPath := basePath + agent.ChunkFileName(paths.TranscriptFileName, i)
-		blobHash, err := CreateBlobFromContent(s.repo, chunk)
+	// Content hash for deduplication (hash of the full, uncompressed transcript)
+	contentHash := fmt.Sprintf("sha256:%x", sha256.Sum256(transcript))
+
+	// Reuse the chunks of an identical transcript written earlier, which
+	// saves re-encrypting it into blobs that differ only in their keys
+	chunkHashes := s.storedTranscriptChunks(contentHash, agentType, encoding, recipients)
+	if chunkHashes == nil {
+		// Chunk the transcript if it's too large
+		chunks, err := agent.ChunkTranscript(ctx, transcript, agentType)
 		if err != nil {
-			return err
+			return "", fmt.Errorf("failed to chunk transcript: %w", err)
+		}
+		// Chunks are compressed individually so each still fits a blob on its own
+		for _, chunk := range chunks {
+			blobHash, err := s.createContentBlob(encodePayload(chunk, encoding), recipients)
+			if err != nil {
+				return "", fmt.Errorf("failed to create transcript blob: %w", err)
+			}
+			chunkHashes = append(chunkHashes, blobHash)
 		}
+	}
+	for i, blobHash := range chunkHashes {
+		chunkPath := basePath + agent.ChunkFileName(paths.TranscriptFileName, i)
 		entries[chunkPath] = object.TreeEntry{
 			Name: chunkPath,
 			Mode: filemode.Regular,
@@ -597,18 +739,31 @@ func (s *GitStore) writeTranscript(ctx context.Context, opts WriteCommittedOptio
 		}
 	}
 
-	// Content hash for deduplication (hash of full transcript)
-	contentHash := fmt.Sprintf("sha256:%x", sha256.Sum256(transcript))
 	hashBlob, err := CreateBlobFromContent(s.repo, []byte(contentHash))
 	if err != nil {
-		return err
+		return "", fmt.Errorf("failed to create content hash blob: %w", err)
 	}
 	entries[basePath+paths.ContentHashFileName] = object.TreeEntry{
 		Name: basePath + paths.ContentHashFileName,
 		Mode: filemode.Regular,
 		Hash: hashBlob,
 	}
-	return nil
+	return encoding, nil
+}
+
+// writeContextBlob stores context.md content, compressed if allowed and
+// it's large, and encrypted to recipients if any. Returns the blob and the
+// encoding it was stored with.
+func (s *GitStore) writeContextBlob(content []byte, allowCompression bool, recipients []*age.Recipient) (plumbing.Hash, string, error) {
+	var encoding string
+	if allowCompression && shouldCompress(len(content)) {
+		encoding = EncodingZstd
+	}
+	blobHash, err := s.createContentBlob(encodePayload(content, encoding), recipients)
+	if err != nil {
+		return plumbing.ZeroHash, "", err
+	}
+	return blobHash, encoding, nil
 }
 
 // mergeFilesTouched combines two file lists, removing duplicates.
@@ -688,6 +843,21 @@ func (s *GitStore) readMetadataFromBlob(hash plumbing.Hash) (*CommittedMetadata,
 	return readJSONFromBlob[CommittedMetadata](s.repo, hash)
 }
 
+// marshalUpdatedMetadata marshals metadata that was read from the blob at
+// hash and then changed. Top-level fields this version doesn't know, written
+// by a newer CLI, are kept so an update doesn't drop them.
+func (s *GitStore) marshalUpdatedMetadata(v any, hash plumbing.Hash) ([]byte, error) {
+	original, err := readBlob(s.repo, hash)
+	if err != nil {
+		return nil, err
+	}
+	data, err := jsonutil.MarshalIndentPreservingUnknown(v, original, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal metadata: %w", err)
+	}
+	return data, nil
+}
+
 // buildCommitMessage constructs the commit message with proper trailers.
 // The commit subject is always "Checkpoint: <id>" for consistency.
 // If CommitSubject is provided (e.g., for task checkpoints), it's included in the body.
@@ -701,19 +871,29 @@ func (s *GitStore) buildCommitMessage(opts WriteCommittedOptions, taskMetadataPa
 	if opts.CommitSubject != "" {
 		commitMsg.WriteString(opts.CommitSubject + "\n\n")
 	}
-	fmt.Fprintf(&commitMsg, "%s: %s\n", trailers.SessionTrailerKey, opts.SessionID)
-	fmt.Fprintf(&commitMsg, "%s: %s\n", trailers.StrategyTrailerKey, opts.Strategy)
+	for _, trailer := range commitTrailers(opts, taskMetadataPath) {
+		commitMsg.WriteString(trailer + "\n")
+	}
+
+	return commitMsg.String()
+}
+
+// commitTrailers returns the trailer lines of a metadata commit writing opts.
+func commitTrailers(opts WriteCommittedOptions, taskMetadataPath string) []string {
+	lines := []string{
+		fmt.Sprintf("%s: %s", trailers.SessionTrailerKey, opts.SessionID),
+		fmt.Sprintf("%s: %s", trailers.StrategyTrailerKey, opts.Strategy),
+	}
 	if opts.Agent != "" {
-		fmt.Fprintf(&commitMsg, "%s: %s\n", trailers.AgentTrailerKey, opts.Agent)
+		lines = append(lines, fmt.Sprintf("%s: %s", trailers.AgentTrailerKey, opts.Agent))
 	}
 	if opts.EphemeralBranch != "" {
-		fmt.Fprintf(&commitMsg, "%s: %s\n", trailers.EphemeralBranchTrailerKey, opts.EphemeralBranch)
+		lines = append(lines, fmt.Sprintf("%s: %s", trailers.EphemeralBranchTrailerKey, opts.EphemeralBranch))
 	}
 	if taskMetadataPath != "" {
-		fmt.Fprintf(&commitMsg, "%s: %s\n", trailers.MetadataTaskTrailerKey, taskMetadataPath)
+		lines = append(lines, fmt.Sprintf("%s: %s", trailers.MetadataTaskTrailerKey, taskMetadataPath))
 	}
-
-	return commitMsg.String()
+	return lines
 }
 
 // incrementalCheckpointData represents an incremental checkpoint during subagent execution.
@@ -823,24 +1003,59 @@ func (s *GitStore) ReadSessionContent(ctx context.Context, checkpointID id.Check
 	}
 
 	// Read transcript
-	if transcript, transcriptErr := readTranscriptFromTree(ctx, sessionTree, agentType); transcriptErr == nil && transcript != nil {
+	transcript, transcriptErr := readTranscriptFromTree(ctx, sessionTree, agentType, result.Metadata.TranscriptEncoding, s.decryptContent)
+	switch {
+	case errors.Is(transcriptErr, ErrNoIdentity):
+		return nil, fmt.Errorf("checkpoint %s: %w", checkpointID, transcriptErr)
+	case transcriptErr != nil:
+		logging.Warn(ctx, "failed to read checkpoint transcript",
+			slog.String("checkpoint_id", string(checkpointID)),
+			slog.String("error", transcriptErr.Error()))
+	case transcript != nil:
 		result.Transcript = transcript
 	}
 
 	// Read prompts
-	if file, fileErr := sessionTree.File(paths.PromptFileName); fileErr == nil {
-		if content, contentErr := file.Contents(); contentErr == nil {
-			result.Prompts = content
-		}
+	result.PromptList, result.Prompts, err = readPromptsFromTree(sessionTree, s.decryptContent)
+	if err != nil {
+		return nil, fmt.Errorf("checkpoint %s: %w", checkpointID, err)
 	}
 
 	// Read context
 	if file, fileErr := sessionTree.File(paths.ContextFileName); fileErr == nil {
 		if content, contentErr := file.Contents(); contentErr == nil {
-			result.Context = content
+			decrypted, decryptErr := s.decryptContent([]byte(content))
+			if decryptErr != nil {
+				return nil, fmt.Errorf("checkpoint %s: %w", checkpointID, decryptErr)
+			}
+			if decoded, decodeErr := decodePayload(decrypted, result.Metadata.ContextEncoding); decodeErr == nil {
+				result.Context = string(decoded)
+			} else {
+				logging.Warn(ctx, "failed to read checkpoint context",
+					slog.String("checkpoint_id", string(checkpointID)),
+					slog.String("error", decodeErr.Error()))
+			}
+		}
+	}
+	if file, fileErr := sessionTree.File(paths.ContextJSONFileName); fileErr == nil {
+		if content, contentErr := file.Contents(); contentErr == nil {
+			decrypted, decryptErr := s.decryptContent([]byte(content))
+			if decryptErr != nil {
+				return nil, fmt.Errorf("checkpoint %s: %w", checkpointID, decryptErr)
+			}
+			if parsed, parseErr := ParseSessionContext(decrypted); parseErr == nil {
+				result.StructuredContext = parsed
+			} else {
+				logging.Warn(ctx, "ignoring invalid checkpoint context.json",
+					slog.String("checkpoint_id", string(checkpointID)),
+					slog.String("error", parseErr.Error()))
+			}
 		}
 	}
 
+	// Read artifacts
+	result.Artifacts = readArtifactsFromTree(ctx, sessionTree, result.Metadata.Artifacts)
+
 	return result, nil
 }
 
@@ -865,8 +1080,62 @@ func (s *GitStore) ReadLatestSessionContent(ctx context.Context, checkpointID id
 // ReadSessionContentByID reads a session's content by its session ID.
 // This is useful when you have the session ID but don't know its index within the checkpoint.
 // Returns ErrCheckpointNotFound if the checkpoint doesn't exist.
-// Returns an error if no session with the given ID exists in the checkpoint.
+// Returns ErrSessionNotFound if no session with the given ID exists in the checkpoint.
 func (s *GitStore) ReadSessionContentByID(ctx context.Context, checkpointID id.CheckpointID, sessionID string) (*SessionContent, error) {
+	sessions, err := s.ListSessions(ctx, checkpointID)
+	if err != nil {
+		return nil, err
+	}
+	for _, session := range sessions {
+		if session.SessionID == sessionID {
+			return s.ReadSessionContent(ctx, checkpointID, session.Index)
+		}
+	}
+	return nil, fmt.Errorf("%w: %q in checkpoint %s", ErrSessionNotFound, sessionID, checkpointID)
+}
+
+// ListSessions returns the sessions of a checkpoint in slot order, read from
+// their metadata without loading content. A session whose metadata can't be
+// read is listed with an empty SessionID.
+// Returns ErrCheckpointNotFound if the checkpoint doesn't exist.
+func (s *GitStore) ListSessions(ctx context.Context, checkpointID id.CheckpointID) ([]SessionRef, error) {
+	summary, err := s.ReadCommitted(ctx, checkpointID)
+	if err != nil {
+		return nil, err
+	}
+	if summary == nil {
+		return nil, ErrCheckpointNotFound
+	}
+
+	tree, err := s.getSessionsBranchTree()
+	if err != nil {
+		return nil, ErrCheckpointNotFound
+	}
+	checkpointTree, err := tree.Tree(checkpointID.Path())
+	if err != nil {
+		return nil, ErrCheckpointNotFound
+	}
+
+	sessions := make([]SessionRef, len(summary.Sessions))
+	for i := range sessions {
+		sessions[i].Index = i
+		file, fileErr := checkpointTree.File(strconv.Itoa(i) + "/" + paths.MetadataFileName)
+		if fileErr != nil {
+			continue
+		}
+		if meta, metaErr := s.readMetadataFromBlob(file.Hash); metaErr == nil {
+			sessions[i].SessionID = meta.SessionID
+			sessions[i].Agent = meta.Agent
+			sessions[i].CreatedAt = meta.CreatedAt
+		}
+	}
+	return sessions, nil
+}
+
+// ReadSessionMetadata reads the metadata of every session in a checkpoint without
+// loading transcripts. Sessions whose metadata can't be read are skipped.
+// Returns ErrCheckpointNotFound if the checkpoint doesn't exist.
+func (s *GitStore) ReadSessionMetadata(ctx context.Context, checkpointID id.CheckpointID) ([]CommittedMetadata, error) {
 	summary, err := s.ReadCommitted(ctx, checkpointID)
 	if err != nil {
 		return nil, err
@@ -875,18 +1144,69 @@ func (s *GitStore) ReadSessionContentByID(ctx context.Context, checkpointID id.C
 		return nil, ErrCheckpointNotFound
 	}
 
-	// Iterate through sessions to find the one with matching session ID
+	tree, err := s.getSessionsBranchTree()
+	if err != nil {
+		return nil, ErrCheckpointNotFound
+	}
+	checkpointTree, err := tree.Tree(checkpointID.Path())
+	if err != nil {
+		return nil, ErrCheckpointNotFound
+	}
+
+	metadata := make([]CommittedMetadata, 0, len(summary.Sessions))
 	for i := range len(summary.Sessions) {
-		content, readErr := s.ReadSessionContent(ctx, checkpointID, i)
-		if readErr != nil {
+		file, fileErr := checkpointTree.File(strconv.Itoa(i) + "/" + paths.MetadataFileName)
+		if fileErr != nil {
 			continue
 		}
-		if content != nil && content.Metadata.SessionID == sessionID {
-			return content, nil
+		meta, metaErr := s.readMetadataFromBlob(file.Hash)
+		if metaErr != nil {
+			continue
 		}
+		metadata = append(metadata, *meta)
+	}
+	return metadata, nil
+}
+
+// ReadAgentConfig reads the agent config files snapshotted for one session of a
+// checkpoint, keyed by repo-relative path, decrypting them if they are
+// encrypted. Returns an empty map if the session was written without
+// snapshot_agent_config.
+func (s *GitStore) ReadAgentConfig(ctx context.Context, checkpointID id.CheckpointID, sessionIndex int) (map[string][]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err //nolint:wrapcheck // Propagating context cancellation
+	}
+
+	tree, err := s.getSessionsBranchTree()
+	if err != nil {
+		return nil, ErrCheckpointNotFound
+	}
+	checkpointTree, err := tree.Tree(checkpointID.Path())
+	if err != nil {
+		return nil, ErrCheckpointNotFound
 	}
 
-	return nil, fmt.Errorf("session %q not found in checkpoint %s", sessionID, checkpointID)
+	files := make(map[string][]byte)
+	configTree, err := checkpointTree.Tree(strconv.Itoa(sessionIndex) + "/" + paths.AgentConfigDirName)
+	if err != nil {
+		return files, nil //nolint:nilerr // No snapshot for this session
+	}
+	err = configTree.Files().ForEach(func(f *object.File) error {
+		content, contentErr := f.Contents()
+		if contentErr != nil {
+			return fmt.Errorf("failed to read %s: %w", f.Name, contentErr)
+		}
+		decrypted, decryptErr := s.decryptContent([]byte(content))
+		if decryptErr != nil {
+			return fmt.Errorf("failed to read %s: %w", f.Name, decryptErr)
+		}
+		files[f.Name] = decrypted
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read agent config: %w", err)
+	}
+	return files, nil
 }
 
 // ListCommitted lists all committed checkpoints from the entire/checkpoints/v1 branch.
@@ -898,11 +1218,28 @@ func (s *GitStore) ListCommitted(ctx context.Context) ([]CommittedInfo, error) {
 		return nil, err //nolint:wrapcheck // Propagating context cancellation
 	}
 
-	tree, err := s.getSessionsBranchTree()
+	commit, err := s.getSessionsBranchCommit()
 	if err != nil {
 		return []CommittedInfo{}, nil //nolint:nilerr // No sessions branch means empty list
 	}
 
+	// The index answers without walking the tree while it matches the branch
+	if idx := s.readIndex(); idx != nil && idx.Tip == commit.Hash.String() {
+		return idx.list(), nil
+	}
+
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commit tree: %w", err)
+	}
+	checkpoints := s.scanCommitted(tree)
+	s.writeIndexFor(ctx, commit.Hash, checkpoints)
+	return checkpoints, nil
+}
+
+// scanCommitted walks the sharded tree of the metadata branch and returns
+// every checkpoint in it, most recent first.
+func (s *GitStore) scanCommitted(tree *object.Tree) []CommittedInfo {
 	var checkpoints []CommittedInfo
 
 	// Scan sharded structure: <2-char-prefix>/<remaining-id>/metadata.json
@@ -939,50 +1276,84 @@ func (s *GitStore) ListCommitted(ctx context.Context) ([]CommittedInfo, error) {
 				continue
 			}
 
-			info := CommittedInfo{
-				CheckpointID: checkpointID,
-			}
+			checkpoints = append(checkpoints, committedInfo(checkpointTree, checkpointID))
+		}
+	}
 
-			// Get details from root metadata file (CheckpointSummary format)
-			if metadataFile, fileErr := checkpointTree.File(paths.MetadataFileName); fileErr == nil {
-				if content, contentErr := metadataFile.Contents(); contentErr == nil {
-					var summary CheckpointSummary
-					if err := json.Unmarshal([]byte(content), &summary); err == nil {
-						info.CheckpointsCount = summary.CheckpointsCount
-						info.FilesTouched = summary.FilesTouched
-						info.SessionCount = len(summary.Sessions)
-
-						// Read session metadata from latest session to get Agent, SessionID, CreatedAt
-						if len(summary.Sessions) > 0 {
-							latestIndex := len(summary.Sessions) - 1
-							latestDir := strconv.Itoa(latestIndex)
-							if sessionTree, treeErr := checkpointTree.Tree(latestDir); treeErr == nil {
-								if sessionMetadataFile, smErr := sessionTree.File(paths.MetadataFileName); smErr == nil {
-									if sessionContent, scErr := sessionMetadataFile.Contents(); scErr == nil {
-										var sessionMetadata CommittedMetadata
-										if json.Unmarshal([]byte(sessionContent), &sessionMetadata) == nil {
-											info.Agent = sessionMetadata.Agent
-											info.SessionID = sessionMetadata.SessionID
-											info.CreatedAt = sessionMetadata.CreatedAt
-										}
-									}
-								}
-							}
-						}
-					}
-				}
-			}
+	sortCommitted(checkpoints)
+	return checkpoints
+}
+
+// committedInfo summarizes the checkpoint stored in checkpointTree.
+func committedInfo(checkpointTree *object.Tree, checkpointID id.CheckpointID) CommittedInfo {
+	info := CommittedInfo{
+		CheckpointID: checkpointID,
+	}
 
-			checkpoints = append(checkpoints, info)
+	// Get details from root metadata file (CheckpointSummary format)
+	var summary CheckpointSummary
+	if !readTreeJSON(checkpointTree, paths.MetadataFileName, &summary) {
+		return info
+	}
+	info.CheckpointsCount = summary.CheckpointsCount
+	info.FilesTouched = summary.FilesTouched
+	info.SessionCount = len(summary.Sessions)
+	info.DiffStats = summary.DiffStats
+	info.Tags = summary.Tags
+
+	// Session IDs come from every session; Agent, SessionID, CreatedAt and
+	// the rest from the latest
+	for i := range summary.Sessions {
+		var sessionMetadata CommittedMetadata
+		if !readTreeJSON(checkpointTree, strconv.Itoa(i)+"/"+paths.MetadataFileName, &sessionMetadata) {
+			continue
+		}
+		if sessionMetadata.SessionID != "" {
+			info.SessionIDs = append(info.SessionIDs, sessionMetadata.SessionID)
+		}
+		if i == len(summary.Sessions)-1 {
+			info.Agent = sessionMetadata.Agent
+			info.SessionID = sessionMetadata.SessionID
+			info.CreatedAt = sessionMetadata.CreatedAt
+			info.CorrelationID = sessionMetadata.CorrelationID
+			info.Label = sessionMetadata.Label
+			info.Model = sessionMetadata.Model
+			info.Branch = sessionMetadata.Branch
 		}
 	}
 
-	// Sort by time (most recent first)
+	return info
+}
+
+// readTreeJSON decodes the JSON file at path in tree into v, reporting
+// whether it exists and parses.
+func readTreeJSON(tree *object.Tree, path string, v any) bool {
+	file, err := tree.File(path)
+	if err != nil {
+		return false
+	}
+	content, err := file.Contents()
+	if err != nil {
+		return false
+	}
+	return json.Unmarshal([]byte(content), v) == nil
+}
+
+// sortCommitted sorts checkpoints by time, most recent first. Checkpoints
+// created at the same time are ordered by ID, so pages of a listing don't
+// depend on the order the tree was walked in.
+func sortCommitted(checkpoints []CommittedInfo) {
 	sort.Slice(checkpoints, func(i, j int) bool {
-		return checkpoints[i].CreatedAt.After(checkpoints[j].CreatedAt)
+		return committedBefore(checkpoints[i], checkpoints[j])
 	})
+}
 
-	return checkpoints, nil
+// committedBefore reports whether a comes before b in a listing.
+func committedBefore(a, b CommittedInfo) bool {
+	if !a.CreatedAt.Equal(b.CreatedAt) {
+		return a.CreatedAt.After(b.CreatedAt)
+	}
+	return a.CheckpointID > b.CheckpointID
 }
 
 // GetTranscript retrieves the transcript for a specific checkpoint ID.
@@ -1042,84 +1413,134 @@ func (s *GitStore) UpdateSummary(ctx context.Context, checkpointID id.Checkpoint
 		return fmt.Errorf("failed to ensure sessions branch: %w", err)
 	}
 
-	// Get branch ref and root tree hash (O(1), no flatten)
-	parentHash, rootTreeHash, err := s.getSessionsBranchRef()
-	if err != nil {
-		return err
-	}
+	// Rebuilt on the new tip if a concurrent write moves the branch
+	return s.commitMetadata(ctx, func(_, rootTreeHash plumbing.Hash) (*metadataCommit, error) {
+		// Flatten only the checkpoint subtree
+		basePath := checkpointID.Path() + "/"
+		checkpointPath := checkpointID.Path()
+		entries, err := s.flattenCheckpointEntries(rootTreeHash, checkpointPath)
+		if err != nil {
+			return nil, err
+		}
 
-	// Flatten only the checkpoint subtree
-	basePath := checkpointID.Path() + "/"
-	checkpointPath := checkpointID.Path()
-	entries, err := s.flattenCheckpointEntries(rootTreeHash, checkpointPath)
-	if err != nil {
-		return err
-	}
+		// Read root CheckpointSummary to find the latest session
+		rootMetadataPath := basePath + paths.MetadataFileName
+		entry, exists := entries[rootMetadataPath]
+		if !exists {
+			return nil, ErrCheckpointNotFound
+		}
 
-	// Read root CheckpointSummary to find the latest session
-	rootMetadataPath := basePath + paths.MetadataFileName
-	entry, exists := entries[rootMetadataPath]
-	if !exists {
-		return ErrCheckpointNotFound
-	}
+		checkpointSummary, err := s.readSummaryFromBlob(entry.Hash)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read checkpoint summary: %w", err)
+		}
 
-	checkpointSummary, err := s.readSummaryFromBlob(entry.Hash)
-	if err != nil {
-		return fmt.Errorf("failed to read checkpoint summary: %w", err)
-	}
+		// Find the latest session's metadata path (0-based indexing)
+		latestIndex := len(checkpointSummary.Sessions) - 1
+		sessionMetadataPath := fmt.Sprintf("%s%d/%s", basePath, latestIndex, paths.MetadataFileName)
+		sessionEntry, exists := entries[sessionMetadataPath]
+		if !exists {
+			return nil, fmt.Errorf("session metadata not found at %s", sessionMetadataPath)
+		}
 
-	// Find the latest session's metadata path (0-based indexing)
-	latestIndex := len(checkpointSummary.Sessions) - 1
-	sessionMetadataPath := fmt.Sprintf("%s%d/%s", basePath, latestIndex, paths.MetadataFileName)
-	sessionEntry, exists := entries[sessionMetadataPath]
-	if !exists {
-		return fmt.Errorf("session metadata not found at %s", sessionMetadataPath)
-	}
+		// Read and update session metadata
+		existingMetadata, err := s.readMetadataFromBlob(sessionEntry.Hash)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read session metadata: %w", err)
+		}
 
-	// Read and update session metadata
-	existingMetadata, err := s.readMetadataFromBlob(sessionEntry.Hash)
-	if err != nil {
-		return fmt.Errorf("failed to read session metadata: %w", err)
-	}
+		// Update the summary
+		existingMetadata.Summary = redactSummary(summary)
 
-	// Update the summary
-	existingMetadata.Summary = redactSummary(summary)
+		// Write updated session metadata
+		metadataJSON, err := s.marshalUpdatedMetadata(existingMetadata, sessionEntry.Hash)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal metadata: %w", err)
+		}
+		metadataHash, err := CreateBlobFromContent(s.repo, metadataJSON)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create metadata blob: %w", err)
+		}
+		entries[sessionMetadataPath] = object.TreeEntry{
+			Name: sessionMetadataPath,
+			Mode: filemode.Regular,
+			Hash: metadataHash,
+		}
 
-	// Write updated session metadata
-	metadataJSON, err := jsonutil.MarshalIndentWithNewline(existingMetadata, "", "  ")
-	if err != nil {
-		return fmt.Errorf("failed to marshal metadata: %w", err)
-	}
-	metadataHash, err := CreateBlobFromContent(s.repo, metadataJSON)
-	if err != nil {
-		return fmt.Errorf("failed to create metadata blob: %w", err)
-	}
-	entries[sessionMetadataPath] = object.TreeEntry{
-		Name: sessionMetadataPath,
-		Mode: filemode.Regular,
-		Hash: metadataHash,
-	}
+		// Build checkpoint subtree and splice into root (O(depth) tree surgery)
+		newTreeHash, err := s.spliceCheckpointSubtree(rootTreeHash, checkpointID, basePath, entries)
+		if err != nil {
+			return nil, err
+		}
+		authorName, authorEmail := GetGitAuthorFromRepo(s.repo)
+		return &metadataCommit{
+			Tree:          newTreeHash,
+			Message:       fmt.Sprintf("Update summary for checkpoint %s (session: %s)", checkpointID, existingMetadata.SessionID),
+			AuthorName:    authorName,
+			AuthorEmail:   authorEmail,
+			CheckpointIDs: []id.CheckpointID{checkpointID},
+		}, nil
+	})
+}
 
-	// Build checkpoint subtree and splice into root (O(depth) tree surgery)
-	newTreeHash, err := s.spliceCheckpointSubtree(rootTreeHash, checkpointID, basePath, entries)
-	if err != nil {
-		return err
+// UpdateDiffStats sets the diff stats on an existing committed checkpoint's
+// root metadata. Used to backfill checkpoints written before stats were stored.
+//
+// Returns ErrCheckpointNotFound if the checkpoint doesn't exist.
+func (s *GitStore) UpdateDiffStats(ctx context.Context, checkpointID id.CheckpointID, stats *DiffStats) error {
+	if err := ctx.Err(); err != nil {
+		return err //nolint:wrapcheck // Propagating context cancellation
 	}
 
-	authorName, authorEmail := GetGitAuthorFromRepo(s.repo)
-	commitMsg := fmt.Sprintf("Update summary for checkpoint %s (session: %s)", checkpointID, existingMetadata.SessionID)
-	newCommitHash, err := s.createCommit(newTreeHash, parentHash, commitMsg, authorName, authorEmail)
-	if err != nil {
-		return err
+	if err := s.ensureSessionsBranch(); err != nil {
+		return fmt.Errorf("failed to ensure sessions branch: %w", err)
 	}
 
-	refName := plumbing.NewBranchReferenceName(paths.MetadataBranchName)
-	newRef := plumbing.NewHashReference(refName, newCommitHash)
-	if err := s.repo.Storer.SetReference(newRef); err != nil {
-		return fmt.Errorf("failed to set branch reference: %w", err)
-	}
+	return s.commitMetadata(ctx, func(_, rootTreeHash plumbing.Hash) (*metadataCommit, error) {
+		basePath := checkpointID.Path() + "/"
+		entries, err := s.flattenCheckpointEntries(rootTreeHash, checkpointID.Path())
+		if err != nil {
+			return nil, err
+		}
 
-	return nil
+		rootMetadataPath := basePath + paths.MetadataFileName
+		entry, exists := entries[rootMetadataPath]
+		if !exists {
+			return nil, ErrCheckpointNotFound
+		}
+		checkpointSummary, err := s.readSummaryFromBlob(entry.Hash)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read checkpoint summary: %w", err)
+		}
+
+		checkpointSummary.DiffStats = stats
+		metadataJSON, err := s.marshalUpdatedMetadata(checkpointSummary, entry.Hash)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal checkpoint summary: %w", err)
+		}
+		metadataHash, err := CreateBlobFromContent(s.repo, metadataJSON)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create metadata blob: %w", err)
+		}
+		entries[rootMetadataPath] = object.TreeEntry{
+			Name: rootMetadataPath,
+			Mode: filemode.Regular,
+			Hash: metadataHash,
+		}
+
+		newTreeHash, err := s.spliceCheckpointSubtree(rootTreeHash, checkpointID, basePath, entries)
+		if err != nil {
+			return nil, err
+		}
+		authorName, authorEmail := GetGitAuthorFromRepo(s.repo)
+		return &metadataCommit{
+			Tree:          newTreeHash,
+			Message:       fmt.Sprintf("Update diff stats for checkpoint %s", checkpointID),
+			AuthorName:    authorName,
+			AuthorEmail:   authorEmail,
+			CheckpointIDs: []id.CheckpointID{checkpointID},
+		}, nil
+	})
 }
 
 // UpdateCommitted replaces the transcript, prompts, and context for an existing
@@ -1134,171 +1555,287 @@ func (s *GitStore) UpdateCommitted(ctx context.Context, opts UpdateCommittedOpti
 	if opts.CheckpointID.IsEmpty() {
 		return errors.New("invalid update options: checkpoint ID is required")
 	}
+	if opts.ClearPrompts && len(opts.Prompts) > 0 {
+		return errors.New("invalid update options: ClearPrompts can't be combined with Prompts")
+	}
+	if opts.ClearContext && (len(opts.Context) > 0 || !opts.StructuredContext.IsEmpty()) {
+		return errors.New("invalid update options: ClearContext can't be combined with Context")
+	}
 
 	// Ensure sessions branch exists
 	if err := s.ensureSessionsBranch(); err != nil {
 		return fmt.Errorf("failed to ensure sessions branch: %w", err)
 	}
 
-	// Get branch ref and root tree hash (O(1), no flatten)
-	parentHash, rootTreeHash, err := s.getSessionsBranchRef()
-	if err != nil {
-		return err
-	}
+	// Rebuilt on the new tip if a concurrent write moves the branch
+	return s.commitMetadata(ctx, func(_, rootTreeHash plumbing.Hash) (*metadataCommit, error) {
+		// The options are trimmed to the content level below; start each
+		// attempt from the caller's
+		opts := opts
 
-	// Flatten only the checkpoint subtree
-	basePath := opts.CheckpointID.Path() + "/"
-	checkpointPath := opts.CheckpointID.Path()
-	entries, err := s.flattenCheckpointEntries(rootTreeHash, checkpointPath)
-	if err != nil {
-		return err
-	}
+		// Flatten only the checkpoint subtree
+		basePath := opts.CheckpointID.Path() + "/"
+		checkpointPath := opts.CheckpointID.Path()
+		entries, err := s.flattenCheckpointEntries(rootTreeHash, checkpointPath)
+		if err != nil {
+			return nil, err
+		}
 
-	// Read root CheckpointSummary to find the session slot
-	rootMetadataPath := basePath + paths.MetadataFileName
-	entry, exists := entries[rootMetadataPath]
-	if !exists {
-		return ErrCheckpointNotFound
-	}
+		// Read root CheckpointSummary to find the session slot
+		rootMetadataPath := basePath + paths.MetadataFileName
+		entry, exists := entries[rootMetadataPath]
+		if !exists {
+			return nil, ErrCheckpointNotFound
+		}
 
-	checkpointSummary, err := s.readSummaryFromBlob(entry.Hash)
-	if err != nil {
-		return fmt.Errorf("failed to read checkpoint summary: %w", err)
-	}
-	if len(checkpointSummary.Sessions) == 0 {
-		return ErrCheckpointNotFound
-	}
+		checkpointSummary, err := s.readSummaryFromBlob(entry.Hash)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read checkpoint summary: %w", err)
+		}
+		if len(checkpointSummary.Sessions) == 0 {
+			return nil, ErrCheckpointNotFound
+		}
 
-	// Find session index matching opts.SessionID
-	sessionIndex := -1
-	for i := range len(checkpointSummary.Sessions) {
-		metaPath := fmt.Sprintf("%s%d/%s", basePath, i, paths.MetadataFileName)
-		if metaEntry, metaExists := entries[metaPath]; metaExists {
-			meta, metaErr := s.readMetadataFromBlob(metaEntry.Hash)
-			if metaErr == nil && meta.SessionID == opts.SessionID {
-				sessionIndex = i
-				break
+		sessionPath, err := s.sessionSlotPath(ctx, entries, basePath, len(checkpointSummary.Sessions), opts.CheckpointID, opts.SessionID, opts.Strict)
+		if err != nil {
+			return nil, err
+		}
+
+		// Honour the content level the checkpoint was written with
+		var level ContentLevel
+		var sessionMeta *CommittedMetadata
+		if metaEntry, metaExists := entries[sessionPath+paths.MetadataFileName]; metaExists {
+			if meta, metaErr := s.readMetadataFromBlob(metaEntry.Hash); metaErr == nil {
+				level = meta.ContentLevel
+				sessionMeta = meta
 			}
 		}
-	}
-	if sessionIndex == -1 {
-		// Fall back to latest session; log so mismatches are diagnosable.
-		sessionIndex = len(checkpointSummary.Sessions) - 1
-		logging.Debug(ctx, "UpdateCommitted: session ID not found, falling back to latest",
-			slog.String("session_id", opts.SessionID),
-			slog.String("checkpoint_id", string(opts.CheckpointID)),
-			slog.Int("fallback_index", sessionIndex),
-		)
-	}
+		if !level.StoresTranscript() {
+			opts.Transcript = nil
+			opts.TranscriptPointer = nil
+		}
+		if !level.StoresPrompts() {
+			opts.Prompts = nil
+			opts.Context = nil
+			opts.StructuredContext = nil
+		}
+		if len(opts.Context) == 0 && !opts.StructuredContext.IsEmpty() {
+			opts.Context = opts.StructuredContext.Markdown()
+		}
 
-	sessionPath := fmt.Sprintf("%s%d/", basePath, sessionIndex)
+		// Replace transcript (full replace, not append)
+		// Apply redaction as safety net (caller should redact, but we ensure it here)
+		metaChanged := false
+		pointerWritten := false
+		if opts.TranscriptPointer != nil {
+			if pointerWritten, err = s.writeTranscriptPointer(ctx, opts.TranscriptPointer, sessionPath, entries); err != nil {
+				return nil, fmt.Errorf("failed to replace transcript: %w", err)
+			}
+			if pointerWritten && sessionMeta != nil {
+				metaChanged = sessionMeta.TranscriptEncoding != "" || sessionMeta.TranscriptRecipients != ""
+				sessionMeta.TranscriptEncoding = ""
+				sessionMeta.TranscriptRecipients = ""
+			}
+		}
+		if len(opts.Transcript) > 0 && !pointerWritten {
+			transcript, err := redact.JSONLBytes(opts.Transcript)
+			if err != nil {
+				return nil, fmt.Errorf("failed to redact transcript secrets: %w", err)
+			}
+			// Without metadata to record an encoding in, store the transcript as is
+			encoding, err := s.replaceTranscript(ctx, transcript, opts.Agent, sessionPath, entries, sessionMeta != nil, opts.EncryptTo)
+			if err != nil {
+				return nil, fmt.Errorf("failed to replace transcript: %w", err)
+			}
+			if sessionMeta != nil {
+				recipients := recipientsFingerprint(opts.EncryptTo)
+				metaChanged = sessionMeta.TranscriptEncoding != encoding || sessionMeta.TranscriptRecipients != recipients
+				sessionMeta.TranscriptEncoding = encoding
+				sessionMeta.TranscriptRecipients = recipients
+			}
+		}
 
-	// Replace transcript (full replace, not append)
-	// Apply redaction as safety net (caller should redact, but we ensure it here)
-	if len(opts.Transcript) > 0 {
-		transcript, err := redact.JSONLBytes(opts.Transcript)
-		if err != nil {
-			return fmt.Errorf("failed to redact transcript secrets: %w", err)
+		// Replace prompts (apply redaction as safety net)
+		if len(opts.Prompts) > 0 {
+			prompts, normalized := normalizePrompts(opts.Prompts)
+			if sessionMeta != nil {
+				metaChanged = metaChanged || normalized != nil || sessionMeta.PromptsNormalized != nil
+				sessionMeta.PromptsNormalized = normalized
+			}
+			if err := s.writePromptEntries(prompts, sessionPath, entries, opts.EncryptTo); err != nil {
+				return nil, err
+			}
+		} else if opts.ClearPrompts {
+			delete(entries, sessionPath+paths.PromptFileName)
+			delete(entries, sessionPath+paths.PromptsFileName)
+			if sessionMeta != nil {
+				metaChanged = metaChanged || sessionMeta.PromptsNormalized != nil
+				sessionMeta.PromptsNormalized = nil
+			}
+		}
+
+		// Replace context (apply redaction as safety net)
+		if len(opts.Context) > 0 {
+			contextContent, normalized := normalizeContext(opts.Context)
+			if sessionMeta != nil {
+				metaChanged = metaChanged || normalized != nil || sessionMeta.ContextNormalized != nil
+				sessionMeta.ContextNormalized = normalized
+			}
+			contextBlob, encoding, err := s.writeContextBlob(redact.Bytes(contextContent), sessionMeta != nil, opts.EncryptTo)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create context blob: %w", err)
+			}
+			if sessionMeta != nil {
+				metaChanged = metaChanged || sessionMeta.ContextEncoding != encoding
+				sessionMeta.ContextEncoding = encoding
+			}
+			entries[sessionPath+paths.ContextFileName] = object.TreeEntry{
+				Name: sessionPath + paths.ContextFileName,
+				Mode: filemode.Regular,
+				Hash: contextBlob,
+			}
 		}
-		if err := s.replaceTranscript(ctx, transcript, opts.Agent, sessionPath, entries); err != nil {
-			return fmt.Errorf("failed to replace transcript: %w", err)
+
+		// Replace context.json, or drop a stale one when only context.md changed
+		if !opts.StructuredContext.IsEmpty() {
+			if err := s.writeStructuredContext(opts.StructuredContext, sessionPath, entries, opts.EncryptTo); err != nil {
+				return nil, err
+			}
+		} else if len(opts.Context) > 0 {
+			delete(entries, sessionPath+paths.ContextJSONFileName)
 		}
-	}
 
-	// Replace prompts (apply redaction as safety net)
-	if len(opts.Prompts) > 0 {
-		promptContent := redact.String(strings.Join(opts.Prompts, "\n\n---\n\n"))
-		blobHash, err := CreateBlobFromContent(s.repo, []byte(promptContent))
-		if err != nil {
-			return fmt.Errorf("failed to create prompt blob: %w", err)
+		if opts.ClearContext {
+			delete(entries, sessionPath+paths.ContextFileName)
+			delete(entries, sessionPath+paths.ContextJSONFileName)
+			if sessionMeta != nil {
+				metaChanged = metaChanged || sessionMeta.ContextNormalized != nil || sessionMeta.ContextEncoding != ""
+				sessionMeta.ContextNormalized = nil
+				sessionMeta.ContextEncoding = ""
+			}
 		}
-		entries[sessionPath+paths.PromptFileName] = object.TreeEntry{
-			Name: sessionPath + paths.PromptFileName,
-			Mode: filemode.Regular,
-			Hash: blobHash,
+		if opts.ClearPrompts || opts.ClearContext {
+			if err := s.clearSummaryPaths(checkpointSummary, entry.Hash, rootMetadataPath, sessionPath, opts.ClearPrompts, opts.ClearContext, entries); err != nil {
+				return nil, err
+			}
+		}
+
+		// Keep the normalization records in step with the replaced content
+		if metaChanged {
+			metadataJSON, err := s.marshalUpdatedMetadata(sessionMeta, entries[sessionPath+paths.MetadataFileName].Hash)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal session metadata: %w", err)
+			}
+			metadataHash, err := CreateBlobFromContent(s.repo, metadataJSON)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create metadata blob: %w", err)
+			}
+			entries[sessionPath+paths.MetadataFileName] = object.TreeEntry{
+				Name: sessionPath + paths.MetadataFileName,
+				Mode: filemode.Regular,
+				Hash: metadataHash,
+			}
 		}
-	}
 
-	// Replace context (apply redaction as safety net)
-	if len(opts.Context) > 0 {
-		contextBlob, err := CreateBlobFromContent(s.repo, redact.Bytes(opts.Context))
+		// Build checkpoint subtree and splice into root (O(depth) tree surgery)
+		newTreeHash, err := s.spliceCheckpointSubtree(rootTreeHash, opts.CheckpointID, basePath, entries)
 		if err != nil {
-			return fmt.Errorf("failed to create context blob: %w", err)
+			return nil, err
 		}
-		entries[sessionPath+paths.ContextFileName] = object.TreeEntry{
-			Name: sessionPath + paths.ContextFileName,
-			Mode: filemode.Regular,
-			Hash: contextBlob,
+		authorName, authorEmail := GetGitAuthorFromRepo(s.repo)
+		return &metadataCommit{
+			Tree:          newTreeHash,
+			Message:       fmt.Sprintf("Finalize transcript for Checkpoint: %s", opts.CheckpointID),
+			AuthorName:    authorName,
+			AuthorEmail:   authorEmail,
+			CheckpointIDs: []id.CheckpointID{opts.CheckpointID},
+		}, nil
+	})
+}
+
+// clearSummaryPaths drops the prompt or context paths of the session at
+// sessionPath from the checkpoint summary, once its files were removed.
+func (s *GitStore) clearSummaryPaths(summary *CheckpointSummary, summaryHash plumbing.Hash, rootMetadataPath, sessionPath string, clearPrompts, clearContext bool, entries map[string]object.TreeEntry) error {
+	changed := false
+	for i := range summary.Sessions {
+		session := &summary.Sessions[i]
+		if session.Metadata != "/"+sessionPath+paths.MetadataFileName {
+			continue
+		}
+		if clearPrompts && (session.Prompt != "" || session.Prompts != "") {
+			session.Prompt, session.Prompts = "", ""
+			changed = true
+		}
+		if clearContext && (session.Context != "" || session.ContextJSON != "") {
+			session.Context, session.ContextJSON = "", ""
+			changed = true
 		}
 	}
-
-	// Build checkpoint subtree and splice into root (O(depth) tree surgery)
-	newTreeHash, err := s.spliceCheckpointSubtree(rootTreeHash, opts.CheckpointID, basePath, entries)
+	if !changed {
+		return nil
+	}
+	summaryJSON, err := s.marshalUpdatedMetadata(summary, summaryHash)
 	if err != nil {
-		return err
+		return fmt.Errorf("failed to marshal checkpoint summary: %w", err)
 	}
-
-	authorName, authorEmail := GetGitAuthorFromRepo(s.repo)
-	commitMsg := fmt.Sprintf("Finalize transcript for Checkpoint: %s", opts.CheckpointID)
-	newCommitHash, err := s.createCommit(newTreeHash, parentHash, commitMsg, authorName, authorEmail)
+	summaryBlob, err := CreateBlobFromContent(s.repo, summaryJSON)
 	if err != nil {
-		return err
+		return fmt.Errorf("failed to create metadata blob: %w", err)
 	}
-
-	refName := plumbing.NewBranchReferenceName(paths.MetadataBranchName)
-	newRef := plumbing.NewHashReference(refName, newCommitHash)
-	if err := s.repo.Storer.SetReference(newRef); err != nil {
-		return fmt.Errorf("failed to set branch reference: %w", err)
+	entries[rootMetadataPath] = object.TreeEntry{
+		Name: rootMetadataPath,
+		Mode: filemode.Regular,
+		Hash: summaryBlob,
 	}
-
 	return nil
 }
 
+// sessionSlotPath returns the directory (with trailing slash) of the session
+// slot holding sessionID among a checkpoint's sessionCount sessions. If none
+// matches, it falls back to the latest session, or with strict returns
+// ErrSessionNotFound.
+func (s *GitStore) sessionSlotPath(ctx context.Context, entries map[string]object.TreeEntry, basePath string, sessionCount int, checkpointID id.CheckpointID, sessionID string, strict bool) (string, error) {
+	sessionIndex := -1
+	for i := range sessionCount {
+		metaPath := fmt.Sprintf("%s%d/%s", basePath, i, paths.MetadataFileName)
+		if metaEntry, metaExists := entries[metaPath]; metaExists {
+			meta, metaErr := s.readMetadataFromBlob(metaEntry.Hash)
+			if metaErr == nil && meta.SessionID == sessionID {
+				sessionIndex = i
+				break
+			}
+		}
+	}
+	if sessionIndex == -1 && strict {
+		return "", fmt.Errorf("%w: %q in checkpoint %s", ErrSessionNotFound, sessionID, checkpointID)
+	}
+	if sessionIndex == -1 {
+		// Fall back to latest session; log so mismatches are diagnosable.
+		sessionIndex = sessionCount - 1
+		logging.Debug(ctx, "session ID not found in checkpoint, falling back to latest",
+			slog.String("session_id", sessionID),
+			slog.String("checkpoint_id", string(checkpointID)),
+			slog.Int("fallback_index", sessionIndex),
+		)
+	}
+	return fmt.Sprintf("%s%d/", basePath, sessionIndex), nil
+}
+
 // replaceTranscript writes the full transcript content, replacing any existing transcript.
 // Also removes any chunk files from a previous write and updates the content hash.
-func (s *GitStore) replaceTranscript(ctx context.Context, transcript []byte, agentType types.AgentType, sessionPath string, entries map[string]object.TreeEntry) error {
-	// Remove existing transcript files (base + any chunks)
+// Returns the encoding the transcript was stored with.
+func (s *GitStore) replaceTranscript(ctx context.Context, transcript []byte, agentType types.AgentType, sessionPath string, entries map[string]object.TreeEntry, allowCompression bool, recipients []*age.Recipient) (string, error) {
+	// Remove existing transcript files (base + any chunks, or a pointer)
 	transcriptBase := sessionPath + paths.TranscriptFileName
 	for key := range entries {
 		if key == transcriptBase || strings.HasPrefix(key, transcriptBase+".") {
 			delete(entries, key)
 		}
 	}
+	delete(entries, sessionPath+paths.TranscriptPointerFileName)
 
-	// Chunk the transcript (matches writeTranscript behavior)
-	chunks, err := agent.ChunkTranscript(ctx, transcript, agentType)
-	if err != nil {
-		return fmt.Errorf("failed to chunk transcript: %w", err)
-	}
-
-	// Write chunk files
-	for i, chunk := range chunks {
-		chunkPath := sessionPath + agent.ChunkFileName(paths.TranscriptFileName, i)
-		blobHash, err := CreateBlobFromContent(s.repo, chunk)
-		if err != nil {
-			return fmt.Errorf("failed to create transcript blob: %w", err)
-		}
-		entries[chunkPath] = object.TreeEntry{
-			Name: chunkPath,
-			Mode: filemode.Regular,
-			Hash: blobHash,
-		}
-	}
-
-	// Update content hash
-	contentHash := fmt.Sprintf("sha256:%x", sha256.Sum256(transcript))
-	hashBlob, err := CreateBlobFromContent(s.repo, []byte(contentHash))
-	if err != nil {
-		return fmt.Errorf("failed to create content hash blob: %w", err)
-	}
-	hashPath := sessionPath + paths.ContentHashFileName
-	entries[hashPath] = object.TreeEntry{
-		Name: hashPath,
-		Mode: filemode.Regular,
-		Hash: hashBlob,
-	}
-
-	return nil
+	// Chunk and write the transcript (matches writeTranscript behavior)
+	return s.writeTranscriptChunks(ctx, transcript, agentType, sessionPath, entries, allowCompression, recipients)
 }
 
 // ensureSessionsBranch ensures the entire/checkpoints/v1 branch exists.
@@ -1321,8 +1858,15 @@ func (s *GitStore) ensureSessionsBranch() error {
 		return err
 	}
 
+	// The lookup above can miss a branch another writer is rewriting, so
+	// create it only if it still has no tip; the check holds the ref's lock
 	newRef := plumbing.NewHashReference(refName, commitHash)
-	if err := s.repo.Storer.SetReference(newRef); err != nil {
+	err = s.repo.Storer.CheckAndSetReference(newRef, plumbing.NewHashReference(refName, plumbing.ZeroHash))
+	if errors.Is(err, plumbing.ErrReferenceNotFound) {
+		// Neither a loose nor a packed ref: the branch is new
+		err = s.repo.Storer.SetReference(newRef)
+	}
+	if err != nil && !errors.Is(err, storage.ErrReferenceHasChanged) {
 		return fmt.Errorf("failed to set branch reference: %w", err)
 	}
 	return nil
@@ -1331,6 +1875,22 @@ func (s *GitStore) ensureSessionsBranch() error {
 // getSessionsBranchTree returns the tree object for the entire/checkpoints/v1 branch.
 // Falls back to origin/entire/checkpoints/v1 if the local branch doesn't exist.
 func (s *GitStore) getSessionsBranchTree() (*object.Tree, error) {
+	commit, err := s.getSessionsBranchCommit()
+	if err != nil {
+		return nil, err
+	}
+
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commit tree: %w", err)
+	}
+
+	return tree, nil
+}
+
+// getSessionsBranchCommit returns the tip of the entire/checkpoints/v1 branch,
+// falling back to origin/entire/checkpoints/v1 as getSessionsBranchTree does.
+func (s *GitStore) getSessionsBranchCommit() (*object.Commit, error) {
 	refName := plumbing.NewBranchReferenceName(paths.MetadataBranchName)
 	ref, err := s.repo.Reference(refName, true)
 	if err != nil {
@@ -1346,13 +1906,7 @@ func (s *GitStore) getSessionsBranchTree() (*object.Tree, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to get commit object: %w", err)
 	}
-
-	tree, err := commit.Tree()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get commit tree: %w", err)
-	}
-
-	return tree, nil
+	return commit, nil
 }
 
 // CreateBlobFromContent creates a blob object from in-memory content.
@@ -1527,38 +2081,41 @@ func GetGitAuthorFromRepo(repo *git.Repository) (name, email string) {
 }
 
 // readTranscriptFromTree reads a transcript from a git tree, handling both chunked and non-chunked formats.
-// It checks for chunk files first (.001, .002, etc.), then falls back to the base file.
-// The agentType is used for reassembling chunks in the correct format.
-func readTranscriptFromTree(ctx context.Context, tree *object.Tree, agentType types.AgentType) ([]byte, error) {
-	// Collect all transcript-related files
-	var chunkFiles []string
-	var hasBaseFile bool
-
-	for _, entry := range tree.Entries {
-		if entry.Name == paths.TranscriptFileName || entry.Name == paths.TranscriptFileNameLegacy {
-			hasBaseFile = true
-		}
-		// Check for chunk files (full.jsonl.001, full.jsonl.002, etc.)
-		if strings.HasPrefix(entry.Name, paths.TranscriptFileName+".") {
-			idx := agent.ParseChunkIndex(entry.Name, paths.TranscriptFileName)
-			if idx > 0 {
-				chunkFiles = append(chunkFiles, entry.Name)
-			}
-		}
+// The agentType is used for reassembling chunks in the correct format, and
+// encoding is the session's TranscriptEncoding the files are decoded with.
+// Files are passed through decrypt first, unless it is nil.
+func readTranscriptFromTree(ctx context.Context, tree *object.Tree, agentType types.AgentType, encoding string, decrypt func([]byte) ([]byte, error)) ([]byte, error) {
+	// Checkpoints in pointer mode hold no transcript; resolve it through the pointer
+	if _, err := tree.FindEntry(paths.TranscriptPointerFileName); err == nil {
+		return resolveTranscriptPointer(ctx, tree)
 	}
 
-	// If we have chunk files, read and reassemble them
-	if len(chunkFiles) > 0 {
-		// Sort chunk files by index
-		chunkFiles = agent.SortChunkFiles(chunkFiles, paths.TranscriptFileName)
-
-		// Check if base file should be included as chunk 0.
-		// NOTE: This assumes the chunking convention where the unsuffixed file
-		// (full.jsonl) is chunk 0, and numbered files (.001, .002) are chunks 1+.
-		if hasBaseFile {
-			chunkFiles = append([]string{paths.TranscriptFileName}, chunkFiles...)
-		}
+	chunks, err := readTranscriptChunks(ctx, tree, encoding, decrypt)
+	if err != nil {
+		return nil, err
+	}
+	appended, err := readTranscriptAppends(tree, encoding, decrypt)
+	if err != nil {
+		return nil, err
+	}
+	if len(chunks) == 0 {
+		return appended, nil
+	}
+	result, err := agent.ReassembleTranscript(chunks, agentType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reassemble transcript: %w", err)
+	}
+	// Lines added by AppendTranscript follow the transcript byte for byte
+	return append(result, appended...), nil
+}
 
+// readTranscriptChunks reads the decoded transcript chunks stored in a git
+// tree, in order. It checks for chunk files first (.001, .002, etc.), then
+// falls back to the base file. Returns no chunks if the tree holds no
+// transcript.
+func readTranscriptChunks(ctx context.Context, tree *object.Tree, encoding string, decrypt func([]byte) ([]byte, error)) ([][]byte, error) {
+	// If we have chunk files, read them in order
+	if chunkFiles := transcriptChunkFiles(tree); len(chunkFiles) > 0 {
 		var chunks [][]byte
 		for _, chunkFile := range chunkFiles {
 			file, err := tree.File(chunkFile)
@@ -1577,35 +2134,74 @@ func readTranscriptFromTree(ctx context.Context, tree *object.Tree, agentType ty
 				)
 				continue
 			}
-			chunks = append(chunks, []byte(content))
+			chunk, err := decryptAndDecode([]byte(content), encoding, decrypt)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read transcript chunk %s: %w", chunkFile, err)
+			}
+			chunks = append(chunks, chunk)
 		}
 
 		if len(chunks) > 0 {
-			result, err := agent.ReassembleTranscript(chunks, agentType)
-			if err != nil {
-				return nil, fmt.Errorf("failed to reassemble transcript: %w", err)
-			}
-			return result, nil
+			return chunks, nil
 		}
 	}
 
 	// Fall back to reading base file (non-chunked or backwards compatibility)
 	if file, err := tree.File(paths.TranscriptFileName); err == nil {
 		if content, err := file.Contents(); err == nil {
-			return []byte(content), nil
+			transcript, err := decryptAndDecode([]byte(content), encoding, decrypt)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read transcript: %w", err)
+			}
+			return [][]byte{transcript}, nil
 		}
 	}
 
 	// Try legacy filename
 	if file, err := tree.File(paths.TranscriptFileNameLegacy); err == nil {
 		if content, err := file.Contents(); err == nil {
-			return []byte(content), nil
+			return [][]byte{[]byte(content)}, nil
 		}
 	}
 
 	return nil, nil
 }
 
+// transcriptChunkFiles returns the names of a chunked transcript's files in
+// order, or nil if the transcript in tree isn't chunked.
+func transcriptChunkFiles(tree *object.Tree) []string {
+	// Collect all transcript-related files
+	var chunkFiles []string
+	var hasBaseFile bool
+
+	for _, entry := range tree.Entries {
+		if entry.Name == paths.TranscriptFileName || entry.Name == paths.TranscriptFileNameLegacy {
+			hasBaseFile = true
+		}
+		// Check for chunk files (full.jsonl.001, full.jsonl.002, etc.)
+		if strings.HasPrefix(entry.Name, paths.TranscriptFileName+".") {
+			idx := agent.ParseChunkIndex(entry.Name, paths.TranscriptFileName)
+			if idx > 0 {
+				chunkFiles = append(chunkFiles, entry.Name)
+			}
+		}
+	}
+	if len(chunkFiles) == 0 {
+		return nil
+	}
+
+	// Sort chunk files by index
+	chunkFiles = agent.SortChunkFiles(chunkFiles, paths.TranscriptFileName)
+
+	// Check if base file should be included as chunk 0.
+	// NOTE: This assumes the chunking convention where the unsuffixed file
+	// (full.jsonl) is chunk 0, and numbered files (.001, .002) are chunks 1+.
+	if hasBaseFile {
+		chunkFiles = append([]string{paths.TranscriptFileName}, chunkFiles...)
+	}
+	return chunkFiles
+}
+
 // Author contains author information for a checkpoint.
 type Author struct {
 	Name  string
@@ -3,8 +3,10 @@ package checkpoint
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
@@ -346,6 +348,133 @@ func TestUpdateCommitted_FallsBackToLatestSession(t *testing.T) {
 	}
 }
 
+func TestUpdateCommitted_StrictRejectsUnknownSession(t *testing.T) {
+	t.Parallel()
+	_, store, cpID := setupRepoForUpdate(t)
+
+	err := store.UpdateCommitted(context.Background(), UpdateCommittedOptions{
+		CheckpointID: cpID,
+		SessionID:    "nonexistent-session",
+		Transcript:   []byte("must not land anywhere\n"),
+		Strict:       true,
+	})
+	if !errors.Is(err, ErrSessionNotFound) {
+		t.Fatalf("UpdateCommitted() error = %v, want ErrSessionNotFound", err)
+	}
+
+	content, err := store.ReadSessionContent(context.Background(), cpID, 0)
+	if err != nil {
+		t.Fatalf("ReadSessionContent() error = %v", err)
+	}
+	if string(content.Transcript) != "provisional transcript line 1\n" {
+		t.Errorf("transcript = %q, want it unchanged", content.Transcript)
+	}
+
+	// A matching session is still updated
+	if err := store.UpdateCommitted(context.Background(), UpdateCommittedOptions{
+		CheckpointID: cpID,
+		SessionID:    "session-001",
+		Transcript:   []byte("strict update\n"),
+		Strict:       true,
+	}); err != nil {
+		t.Fatalf("UpdateCommitted() for the matching session error = %v", err)
+	}
+}
+
+func TestUpdateCommitted_ClearPromptsAndContext(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		opts        UpdateCommittedOptions
+		wantPrompts string
+		wantContext string
+		wantErr     bool
+	}{
+		{name: "neither given leaves both", wantPrompts: "initial prompt", wantContext: "initial context"},
+		{
+			name:        "prompts replaced, context left",
+			opts:        UpdateCommittedOptions{Prompts: []string{"new prompt"}},
+			wantPrompts: "new prompt",
+			wantContext: "initial context",
+		},
+		{
+			name:        "prompts cleared, context left",
+			opts:        UpdateCommittedOptions{ClearPrompts: true},
+			wantContext: "initial context",
+		},
+		{
+			name:        "context cleared, prompts left",
+			opts:        UpdateCommittedOptions{ClearContext: true},
+			wantPrompts: "initial prompt",
+		},
+		{
+			name: "both cleared",
+			opts: UpdateCommittedOptions{ClearPrompts: true, ClearContext: true},
+		},
+		{
+			name:        "prompts cleared, context replaced",
+			opts:        UpdateCommittedOptions{ClearPrompts: true, Context: []byte("new context")},
+			wantContext: "new context",
+		},
+		{
+			name:    "clearing and replacing prompts",
+			opts:    UpdateCommittedOptions{ClearPrompts: true, Prompts: []string{"new prompt"}},
+			wantErr: true,
+		},
+		{
+			name:    "clearing and replacing context",
+			opts:    UpdateCommittedOptions{ClearContext: true, Context: []byte("new context")},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			_, store, cpID := setupRepoForUpdate(t)
+			ctx := context.Background()
+
+			opts := tt.opts
+			opts.CheckpointID = cpID
+			opts.SessionID = "session-001"
+			err := store.UpdateCommitted(ctx, opts)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("UpdateCommitted() succeeded, want an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("UpdateCommitted() error = %v", err)
+			}
+
+			content, err := store.ReadSessionContent(ctx, cpID, 0)
+			if err != nil {
+				t.Fatalf("ReadSessionContent() error = %v", err)
+			}
+			if content.Prompts != tt.wantPrompts {
+				t.Errorf("prompts = %q, want %q", content.Prompts, tt.wantPrompts)
+			}
+			if content.Context != tt.wantContext {
+				t.Errorf("context = %q, want %q", content.Context, tt.wantContext)
+			}
+
+			summary, err := store.ReadCommitted(ctx, cpID)
+			if err != nil {
+				t.Fatalf("ReadCommitted() error = %v", err)
+			}
+			files := summary.Sessions[0]
+			if (files.Prompt == "") != (tt.wantPrompts == "") {
+				t.Errorf("summary prompt path = %q with prompts %q", files.Prompt, tt.wantPrompts)
+			}
+			if opts.ClearContext && files.Context != "" {
+				t.Errorf("summary context path = %q after clearing the context", files.Context)
+			}
+		})
+	}
+}
+
 func TestUpdateCommitted_SummaryPreserved(t *testing.T) {
 	t.Parallel()
 	_, store, cpID := setupRepoForUpdate(t)
@@ -611,3 +740,87 @@ func TestGetGitAuthorFromRepo_NoConfig(t *testing.T) {
 
 // Verify go-git config import is used (compile-time check).
 var _ = config.GlobalScope
+
+func TestWriteCommitted_ContentLevelPrompts(t *testing.T) {
+	t.Parallel()
+	_, store, _ := setupRepoForUpdate(t)
+	cpID := id.MustCheckpointID("e1e2e3e4e5e6")
+
+	err := store.WriteCommitted(context.Background(), WriteCommittedOptions{
+		CheckpointID: cpID,
+		SessionID:    "session-private",
+		Strategy:     "manual-commit",
+		Transcript:   []byte(`{"type":"assistant","message":"model output"}` + "\n"),
+		ContentLevel: ContentPrompts,
+		Prompts:      []string{"add a flag"},
+		Context:      []byte("# Context"),
+		FilesTouched: []string{"main.go"},
+		AuthorName:   "Test",
+		AuthorEmail:  "test@test.com",
+	})
+	if err != nil {
+		t.Fatalf("WriteCommitted() error = %v", err)
+	}
+
+	// A later update must not add the transcript back
+	err = store.UpdateCommitted(context.Background(), UpdateCommittedOptions{
+		CheckpointID: cpID,
+		SessionID:    "session-private",
+		Transcript:   []byte(`{"type":"assistant","message":"more model output"}` + "\n"),
+		Prompts:      []string{"add a flag", "and a test"},
+	})
+	if err != nil {
+		t.Fatalf("UpdateCommitted() error = %v", err)
+	}
+
+	content, err := store.ReadSessionContent(context.Background(), cpID, 0)
+	if err != nil {
+		t.Fatalf("ReadSessionContent() error = %v", err)
+	}
+	if len(content.Transcript) != 0 {
+		t.Errorf("transcript = %q, want none stored", content.Transcript)
+	}
+	if !strings.Contains(content.Prompts, "and a test") {
+		t.Errorf("prompts = %q, want the updated prompts", content.Prompts)
+	}
+	if content.Metadata.ContentLevel != ContentPrompts {
+		t.Errorf("ContentLevel = %q, want %q", content.Metadata.ContentLevel, ContentPrompts)
+	}
+}
+
+func TestWriteCommitted_ContentLevelMetadata(t *testing.T) {
+	t.Parallel()
+	_, store, _ := setupRepoForUpdate(t)
+	cpID := id.MustCheckpointID("f1f2f3f4f5f6")
+
+	err := store.WriteCommitted(context.Background(), WriteCommittedOptions{
+		CheckpointID: cpID,
+		SessionID:    "session-private",
+		Strategy:     "manual-commit",
+		Transcript:   []byte(`{"type":"assistant","message":"model output"}` + "\n"),
+		ContentLevel: ContentMetadata,
+		Prompts:      []string{"add a flag"},
+		Context:      []byte("# Context"),
+		AgentConfig:  map[string][]byte{"CLAUDE.md": []byte("Run tests before committing.\n")},
+		FilesTouched: []string{"main.go"},
+		AuthorName:   "Test",
+		AuthorEmail:  "test@test.com",
+	})
+	if err != nil {
+		t.Fatalf("WriteCommitted() error = %v", err)
+	}
+
+	content, err := store.ReadSessionContent(context.Background(), cpID, 0)
+	if err != nil {
+		t.Fatalf("ReadSessionContent() error = %v", err)
+	}
+	if len(content.Transcript) != 0 || content.Prompts != "" || content.Context != "" {
+		t.Errorf("content = %+v, want no transcript, prompts, or context", content)
+	}
+	if files, err := store.ReadAgentConfig(context.Background(), cpID, 0); err != nil || len(files) != 0 {
+		t.Errorf("ReadAgentConfig() = %v, %v; want no agent config", files, err)
+	}
+	if len(content.Metadata.FilesTouched) != 1 || content.Metadata.FilesTouched[0] != "main.go" {
+		t.Errorf("FilesTouched = %v, want the metadata kept", content.Metadata.FilesTouched)
+	}
+}
@@ -0,0 +1,219 @@
+package checkpoint
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// Files and fields a newer CLI version might write into a checkpoint.
+const (
+	futureRootFile    = "future-index.json"
+	futureSessionFile = "0/future-notes.bin"
+	futureField       = "future_field"
+)
+
+// addFutureContent writes a field this version doesn't know into the
+// checkpoint's summary and first session's metadata, and adds files it
+// doesn't know, as a newer CLI version might.
+func addFutureContent(t *testing.T, store *GitStore, cpID id.CheckpointID) {
+	t.Helper()
+	parentHash, rootTreeHash, err := store.getSessionsBranchRef()
+	if err != nil {
+		t.Fatalf("getSessionsBranchRef() error = %v", err)
+	}
+	entries, err := store.flattenCheckpointEntries(rootTreeHash, cpID.Path())
+	if err != nil {
+		t.Fatalf("flattenCheckpointEntries() error = %v", err)
+	}
+	basePath := cpID.Path() + "/"
+	setFile := func(name string, data []byte) {
+		hash, err := CreateBlobFromContent(store.repo, data)
+		if err != nil {
+			t.Fatalf("CreateBlobFromContent() error = %v", err)
+		}
+		entries[basePath+name] = object.TreeEntry{Name: basePath + name, Mode: filemode.Regular, Hash: hash}
+	}
+	for _, name := range []string{paths.MetadataFileName, "0/" + paths.MetadataFileName} {
+		data, err := readBlob(store.repo, entries[basePath+name].Hash)
+		if err != nil {
+			t.Fatalf("readBlob(%s) error = %v", name, err)
+		}
+		var fields map[string]any
+		if err := json.Unmarshal(data, &fields); err != nil {
+			t.Fatalf("failed to parse %s: %v", name, err)
+		}
+		fields[futureField] = map[string]any{"nested": []any{"kept"}}
+		data, err = json.Marshal(fields)
+		if err != nil {
+			t.Fatalf("failed to marshal %s: %v", name, err)
+		}
+		setFile(name, data)
+	}
+	setFile(futureRootFile, []byte(`{"v":2}`))
+	setFile(futureSessionFile, []byte{0x00, 0x01})
+
+	treeHash, err := store.spliceCheckpointSubtree(rootTreeHash, cpID, basePath, entries)
+	if err != nil {
+		t.Fatalf("spliceCheckpointSubtree() error = %v", err)
+	}
+	commitHash, err := store.createCommit(treeHash, parentHash, "newer CLI", "Test", "test@test.com")
+	if err != nil {
+		t.Fatalf("createCommit() error = %v", err)
+	}
+	ref := plumbing.NewHashReference(plumbing.NewBranchReferenceName(paths.MetadataBranchName), commitHash)
+	if err := store.repo.Storer.SetReference(ref); err != nil {
+		t.Fatalf("SetReference() error = %v", err)
+	}
+}
+
+// assertFutureContentKept checks that the content addFutureContent added is
+// still in the checkpoint.
+func assertFutureContentKept(t *testing.T, store *GitStore, cpID id.CheckpointID) {
+	t.Helper()
+	files, err := store.ExportCheckpoint(context.Background(), cpID)
+	if err != nil {
+		t.Fatalf("ExportCheckpoint() error = %v", err)
+	}
+	for _, name := range []string{paths.MetadataFileName, "0/" + paths.MetadataFileName} {
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(files[name], &fields); err != nil {
+			t.Fatalf("failed to parse %s: %v", name, err)
+		}
+		var got bytes.Buffer
+		if err := json.Compact(&got, fields[futureField]); err != nil || got.String() != `{"nested":["kept"]}` {
+			t.Errorf("%s %s = %q, want it kept", name, futureField, fields[futureField])
+		}
+	}
+	if string(files[futureRootFile]) != `{"v":2}` {
+		t.Errorf("%s = %q, want it untouched", futureRootFile, files[futureRootFile])
+	}
+	if string(files[futureSessionFile]) != "\x00\x01" {
+		t.Errorf("%s = %q, want it untouched", futureSessionFile, files[futureSessionFile])
+	}
+}
+
+func TestForwardCompat_ReadsNewerCheckpoint(t *testing.T) {
+	t.Parallel()
+	_, store, cpID := setupRepoForUpdate(t)
+	ctx := context.Background()
+	addFutureContent(t, store, cpID)
+
+	summary, err := store.ReadCommitted(ctx, cpID)
+	if err != nil || summary == nil {
+		t.Fatalf("ReadCommitted() = %v, %v", summary, err)
+	}
+	content, err := store.ReadSessionContent(ctx, cpID, 0)
+	if err != nil {
+		t.Fatalf("ReadSessionContent() error = %v", err)
+	}
+	if string(content.Transcript) != "provisional transcript line 1\n" {
+		t.Errorf("transcript = %q", content.Transcript)
+	}
+}
+
+func TestForwardCompat_UpdatesKeepUnknownContent(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	tests := []struct {
+		name   string
+		update func(store *GitStore, cpID id.CheckpointID) error
+	}{
+		{
+			name: "UpdateCommitted",
+			update: func(store *GitStore, cpID id.CheckpointID) error {
+				// Large enough to be compressed, so the session metadata is rewritten
+				return store.UpdateCommitted(ctx, UpdateCommittedOptions{
+					CheckpointID: cpID,
+					SessionID:    "session-001",
+					Transcript:   largeTranscript(2 * CompressMinBytes),
+					Prompts:      []string{"final prompt"},
+				})
+			},
+		},
+		{
+			name: "UpdateSummary",
+			update: func(store *GitStore, cpID id.CheckpointID) error {
+				return store.UpdateSummary(ctx, cpID, &Summary{Intent: "intent", Outcome: "outcome"})
+			},
+		},
+		{
+			name: "UpdateDiffStats",
+			update: func(store *GitStore, cpID id.CheckpointID) error {
+				return store.UpdateDiffStats(ctx, cpID, &DiffStats{FilesChanged: 1, Insertions: 2})
+			},
+		},
+		{
+			name: "WriteCommitted another session",
+			update: func(store *GitStore, cpID id.CheckpointID) error {
+				return store.WriteCommitted(ctx, WriteCommittedOptions{
+					CheckpointID: cpID,
+					SessionID:    "session-002",
+					Strategy:     "manual-commit",
+					Transcript:   []byte("second session\n"),
+					AuthorName:   "Test",
+					AuthorEmail:  "test@test.com",
+				})
+			},
+		},
+		{
+			name: "AppendTranscript",
+			update: func(store *GitStore, cpID id.CheckpointID) error {
+				return store.AppendTranscript(ctx, AppendTranscriptOptions{
+					CheckpointID: cpID,
+					SessionID:    "session-001",
+					Chunk:        []byte("appended line\n"),
+				})
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			_, store, cpID := setupRepoForUpdate(t)
+			addFutureContent(t, store, cpID)
+
+			if err := tt.update(store, cpID); err != nil {
+				t.Fatalf("update error = %v", err)
+			}
+			assertFutureContentKept(t, store, cpID)
+		})
+	}
+}
+
+func TestForwardCompat_ClearedKnownFieldStaysCleared(t *testing.T) {
+	t.Parallel()
+	_, store, cpID := setupRepoForUpdate(t)
+	ctx := context.Background()
+
+	if err := store.UpdateDiffStats(ctx, cpID, &DiffStats{FilesChanged: 3}); err != nil {
+		t.Fatalf("UpdateDiffStats() error = %v", err)
+	}
+	addFutureContent(t, store, cpID)
+	if err := store.UpdateDiffStats(ctx, cpID, nil); err != nil {
+		t.Fatalf("UpdateDiffStats(nil) error = %v", err)
+	}
+
+	files, err := store.ExportCheckpoint(ctx, cpID)
+	if err != nil {
+		t.Fatalf("ExportCheckpoint() error = %v", err)
+	}
+	summary := string(files[paths.MetadataFileName])
+	if strings.Contains(summary, "diff_stats") {
+		t.Errorf("cleared diff_stats came back from the original summary:\n%s", summary)
+	}
+	if !strings.Contains(summary, futureField) {
+		t.Errorf("summary lost %s:\n%s", futureField, summary)
+	}
+}
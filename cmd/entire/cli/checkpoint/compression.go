@@ -0,0 +1,68 @@
+package checkpoint
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// EncodingZstd marks a transcript or context stored zstd-compressed. Session
+// metadata records the encoding in TranscriptEncoding and ContextEncoding;
+// an empty encoding means the file is stored as is, as older CLI versions
+// always did.
+const EncodingZstd = "zstd"
+
+// CompressMinBytes is the size from which transcripts and context are
+// compressed. Smaller payloads stay plain, so they remain readable with
+// plain git tools and by older CLI versions.
+const CompressMinBytes = 64 * 1024
+
+// maxDecompressedBytes caps the memory a single decompressed file may use.
+const maxDecompressedBytes = 1 << 30
+
+var (
+	zstdEncoder = sync.OnceValue(func() *zstd.Encoder {
+		enc, err := zstd.NewWriter(nil, zstd.WithEncoderLevel(zstd.SpeedDefault))
+		if err != nil {
+			panic(fmt.Sprintf("zstd encoder: %v", err)) // only fails on invalid options
+		}
+		return enc
+	})
+	zstdDecoder = sync.OnceValue(func() *zstd.Decoder {
+		dec, err := zstd.NewReader(nil, zstd.WithDecoderMaxMemory(maxDecompressedBytes))
+		if err != nil {
+			panic(fmt.Sprintf("zstd decoder: %v", err)) // only fails on invalid options
+		}
+		return dec
+	})
+)
+
+// shouldCompress reports whether a payload of size bytes is stored compressed.
+func shouldCompress(size int) bool {
+	return size >= CompressMinBytes
+}
+
+// encodePayload compresses content with the given encoding.
+func encodePayload(content []byte, encoding string) []byte {
+	if encoding != EncodingZstd {
+		return content
+	}
+	return zstdEncoder().EncodeAll(content, make([]byte, 0, len(content)/4))
+}
+
+// decodePayload reverses encodePayload.
+func decodePayload(content []byte, encoding string) ([]byte, error) {
+	switch encoding {
+	case "":
+		return content, nil
+	case EncodingZstd:
+		decoded, err := zstdDecoder().DecodeAll(content, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress: %w", err)
+		}
+		return decoded, nil
+	default:
+		return nil, fmt.Errorf("unsupported encoding %q", encoding)
+	}
+}
@@ -0,0 +1,135 @@
+package checkpoint
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+)
+
+// largeTranscript returns a JSONL transcript of at least size bytes.
+func largeTranscript(size int) []byte {
+	var buf bytes.Buffer
+	for i := 0; buf.Len() < size; i++ {
+		fmt.Fprintf(&buf, `{"type":"assistant","message":{"content":"line %d of a long session"}}`+"\n", i)
+	}
+	return buf.Bytes()
+}
+
+// rawSessionFile returns a file of session 0 as stored on the metadata branch.
+func rawSessionFile(t *testing.T, store *GitStore, cpID id.CheckpointID, name string) []byte {
+	t.Helper()
+	tree, err := store.getSessionsBranchTree()
+	if err != nil {
+		t.Fatalf("getSessionsBranchTree() error = %v", err)
+	}
+	file, err := tree.File(cpID.Path() + "/0/" + name)
+	if err != nil {
+		t.Fatalf("%s not found: %v", name, err)
+	}
+	content, err := file.Contents()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return []byte(content)
+}
+
+func TestWriteCommitted_CompressesLargeTranscriptAndContext(t *testing.T) {
+	t.Parallel()
+	_, store, cpID := setupRepoForUpdate(t)
+	transcript := largeTranscript(2 * CompressMinBytes)
+	contextMD := strings.Repeat("# Context\nThe agent refactored the parser.\n", CompressMinBytes/20)
+
+	err := store.WriteCommitted(context.Background(), WriteCommittedOptions{
+		CheckpointID: cpID,
+		SessionID:    "session-001",
+		Strategy:     "manual-commit",
+		Transcript:   transcript,
+		Context:      []byte(contextMD),
+		AuthorName:   "Test",
+		AuthorEmail:  "test@test.com",
+	})
+	if err != nil {
+		t.Fatalf("WriteCommitted() error = %v", err)
+	}
+
+	raw := rawSessionFile(t, store, cpID, paths.TranscriptFileName)
+	if len(raw) >= len(transcript)/2 || bytes.HasPrefix(raw, []byte(`{"type"`)) {
+		t.Errorf("transcript should be stored compressed, got %d of %d bytes", len(raw), len(transcript))
+	}
+	if raw := rawSessionFile(t, store, cpID, paths.ContextFileName); bytes.HasPrefix(raw, []byte("# Context")) {
+		t.Error("context.md should be stored compressed")
+	}
+
+	content, err := store.ReadSessionContent(context.Background(), cpID, 0)
+	if err != nil {
+		t.Fatalf("ReadSessionContent() error = %v", err)
+	}
+	if content.Metadata.TranscriptEncoding != EncodingZstd || content.Metadata.ContextEncoding != EncodingZstd {
+		t.Errorf("encodings = %q, %q; want zstd", content.Metadata.TranscriptEncoding, content.Metadata.ContextEncoding)
+	}
+	if !bytes.Equal(content.Transcript, transcript) {
+		t.Error("transcript should round-trip through compression")
+	}
+	if content.Context != contextMD {
+		t.Error("context should round-trip through compression")
+	}
+}
+
+func TestWriteCommitted_SmallTranscriptStaysPlain(t *testing.T) {
+	t.Parallel()
+	_, store, cpID := setupRepoForUpdate(t)
+
+	raw := rawSessionFile(t, store, cpID, paths.TranscriptFileName)
+	if string(raw) != "provisional transcript line 1\n" {
+		t.Errorf("small transcript should be stored as is, got %q", raw)
+	}
+	content, err := store.ReadSessionContent(context.Background(), cpID, 0)
+	if err != nil {
+		t.Fatalf("ReadSessionContent() error = %v", err)
+	}
+	if content.Metadata.TranscriptEncoding != "" || content.Metadata.ContextEncoding != "" {
+		t.Errorf("encodings = %q, %q; want none", content.Metadata.TranscriptEncoding, content.Metadata.ContextEncoding)
+	}
+}
+
+func TestUpdateCommitted_TracksTranscriptEncoding(t *testing.T) {
+	t.Parallel()
+	_, store, cpID := setupRepoForUpdate(t)
+	ctx := context.Background()
+	transcript := largeTranscript(2 * CompressMinBytes)
+
+	if err := store.UpdateCommitted(ctx, UpdateCommittedOptions{CheckpointID: cpID, SessionID: "session-001", Transcript: transcript}); err != nil {
+		t.Fatalf("UpdateCommitted() error = %v", err)
+	}
+	content, err := store.ReadSessionContent(ctx, cpID, 0)
+	if err != nil {
+		t.Fatalf("ReadSessionContent() error = %v", err)
+	}
+	if content.Metadata.TranscriptEncoding != EncodingZstd || !bytes.Equal(content.Transcript, transcript) {
+		t.Errorf("large replacement: encoding %q, transcript round-trips %v", content.Metadata.TranscriptEncoding, bytes.Equal(content.Transcript, transcript))
+	}
+
+	small := []byte("final transcript line\n")
+	if err := store.UpdateCommitted(ctx, UpdateCommittedOptions{CheckpointID: cpID, SessionID: "session-001", Transcript: small}); err != nil {
+		t.Fatalf("UpdateCommitted() error = %v", err)
+	}
+	content, err = store.ReadSessionContent(ctx, cpID, 0)
+	if err != nil {
+		t.Fatalf("ReadSessionContent() error = %v", err)
+	}
+	if content.Metadata.TranscriptEncoding != "" || !bytes.Equal(content.Transcript, small) {
+		t.Errorf("small replacement: encoding %q, transcript %q", content.Metadata.TranscriptEncoding, content.Transcript)
+	}
+}
+
+func TestDecodePayload_UnknownEncoding(t *testing.T) {
+	t.Parallel()
+	if _, err := decodePayload([]byte("x"), "brotli"); err == nil {
+		t.Error("decodePayload() should reject unknown encodings")
+	}
+}
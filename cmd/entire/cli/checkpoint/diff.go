@@ -0,0 +1,216 @@
+package checkpoint
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+	"github.com/entireio/cli/cmd/entire/cli/trailers"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// diffCommitScanLimit caps the commits DiffCheckpoints walks back from HEAD
+// looking for the commits the checkpoints are linked to.
+const diffCommitScanLimit = 2000
+
+// CheckpointDiff describes how checkpoint To differs from checkpoint From.
+type CheckpointDiff struct {
+	From id.CheckpointID
+	To   id.CheckpointID
+
+	// FromCommit and ToCommit are the commits whose Entire-Checkpoint trailer
+	// names each checkpoint. They are zero when the commit isn't in HEAD's
+	// history, in which case Files is nil.
+	FromCommit plumbing.Hash
+	ToCommit   plumbing.Hash
+
+	// Files are the files that differ between the two commits, by path
+	Files []FileDiff
+
+	// Prompts and Context hold each checkpoint's latest session prompts,
+	// joined with newlines, and its context.md
+	Prompts TextDiff
+	Context TextDiff
+}
+
+// TextDiff is a piece of text before and after.
+type TextDiff struct {
+	Before string
+	After  string
+}
+
+// Changed reports whether the text differs.
+func (d TextDiff) Changed() bool {
+	return d.Before != d.After
+}
+
+// FileDiff is one file that differs between two checkpoints' commits.
+// FromPath is empty for added files and ToPath for deleted ones.
+type FileDiff struct {
+	FromPath string
+	ToPath   string
+
+	// Binary is set when either side is binary; Before and After are then empty
+	Binary bool
+	Before string
+	After  string
+}
+
+// Path returns the file's path on the side it exists on, preferring To.
+func (d FileDiff) Path() string {
+	if d.ToPath != "" {
+		return d.ToPath
+	}
+	return d.FromPath
+}
+
+// DiffCheckpoints compares two committed checkpoints: the code of the commits
+// they're linked to, and the prompts and context of their latest sessions.
+// Returns ErrCheckpointNotFound if either checkpoint doesn't exist.
+func (s *GitStore) DiffCheckpoints(ctx context.Context, a, b id.CheckpointID) (*CheckpointDiff, error) {
+	from, err := s.ReadLatestSessionContent(ctx, a)
+	if err != nil {
+		return nil, fmt.Errorf("checkpoint %s: %w", a, err)
+	}
+	to, err := s.ReadLatestSessionContent(ctx, b)
+	if err != nil {
+		return nil, fmt.Errorf("checkpoint %s: %w", b, err)
+	}
+
+	diff := &CheckpointDiff{
+		From:    a,
+		To:      b,
+		Prompts: TextDiff{Before: strings.Join(from.PromptList, "\n"), After: strings.Join(to.PromptList, "\n")},
+		Context: TextDiff{Before: from.Context, After: to.Context},
+	}
+
+	commits, err := s.findCheckpointCommits(ctx, a, b)
+	if err != nil {
+		return nil, err
+	}
+	fromCommit, toCommit := commits[a], commits[b]
+	if fromCommit == nil || toCommit == nil {
+		return diff, nil
+	}
+	diff.FromCommit, diff.ToCommit = fromCommit.Hash, toCommit.Hash
+
+	diff.Files, err = diffCommitFiles(fromCommit, toCommit)
+	if err != nil {
+		return nil, err
+	}
+	return diff, nil
+}
+
+// findCheckpointCommits walks back from HEAD and returns the most recent
+// commit carrying the Entire-Checkpoint trailer of each of cpIDs. IDs without
+// a commit in the first diffCommitScanLimit commits are missing from the map.
+func (s *GitStore) findCheckpointCommits(ctx context.Context, cpIDs ...id.CheckpointID) (map[id.CheckpointID]*object.Commit, error) {
+	found := make(map[id.CheckpointID]*object.Commit, len(cpIDs))
+	head, err := s.repo.Head()
+	if errors.Is(err, plumbing.ErrReferenceNotFound) {
+		return found, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get HEAD: %w", err)
+	}
+	iter, err := s.repo.Log(&git.LogOptions{From: head.Hash(), Order: git.LogOrderCommitterTime})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commit log: %w", err)
+	}
+	defer iter.Close()
+
+	wanted := make(map[id.CheckpointID]bool, len(cpIDs))
+	for _, cpID := range cpIDs {
+		wanted[cpID] = true
+	}
+	for range diffCommitScanLimit {
+		if len(found) == len(wanted) {
+			break
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err //nolint:wrapcheck // Propagating context cancellation
+		}
+		c, err := iter.Next()
+		if err != nil {
+			break // io.EOF, or an unreadable commit ends the walk
+		}
+		cpID, ok := trailers.ParseCheckpoint(c.Message)
+		if ok && wanted[cpID] && found[cpID] == nil {
+			found[cpID] = c
+		}
+	}
+	return found, nil
+}
+
+// diffCommitFiles returns the files that differ between the trees of two commits.
+func diffCommitFiles(from, to *object.Commit) ([]FileDiff, error) {
+	fromTree, err := from.Tree()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tree for %s: %w", from.Hash, err)
+	}
+	toTree, err := to.Tree()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tree for %s: %w", to.Hash, err)
+	}
+	changes, err := object.DiffTree(fromTree, toTree)
+	if err != nil {
+		return nil, fmt.Errorf("failed to diff trees: %w", err)
+	}
+
+	files := make([]FileDiff, 0, len(changes))
+	for _, change := range changes {
+		before, after, err := change.Files()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read changed files: %w", err)
+		}
+		fd := FileDiff{FromPath: change.From.Name, ToPath: change.To.Name}
+		if fd.Binary, err = anyBinary(before, after); err != nil {
+			return nil, err
+		}
+		if !fd.Binary {
+			if fd.Before, err = fileContents(before); err != nil {
+				return nil, err
+			}
+			if fd.After, err = fileContents(after); err != nil {
+				return nil, err
+			}
+		}
+		files = append(files, fd)
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Path() < files[j].Path() })
+	return files, nil
+}
+
+func anyBinary(files ...*object.File) (bool, error) {
+	for _, f := range files {
+		if f == nil {
+			continue
+		}
+		binary, err := f.IsBinary()
+		if err != nil {
+			return false, fmt.Errorf("failed to read %s: %w", f.Name, err)
+		}
+		if binary {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// fileContents returns f's content, or "" for a nil (absent) file.
+func fileContents(f *object.File) (string, error) {
+	if f == nil {
+		return "", nil
+	}
+	content, err := f.Contents()
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", f.Name, err)
+	}
+	return content, nil
+}
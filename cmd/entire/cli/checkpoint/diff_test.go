@@ -0,0 +1,143 @@
+package checkpoint
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+	"github.com/entireio/cli/cmd/entire/cli/trailers"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// commitCheckpoint commits files (path to content, "" deletes) with cpID's
+// trailer and writes the checkpoint with the given prompt and context.
+func commitCheckpoint(t *testing.T, repo *git.Repository, cpID id.CheckpointID, files map[string]string, prompt, ctxText string) plumbing.Hash {
+	t.Helper()
+	wt, err := repo.Worktree()
+	if err != nil {
+		t.Fatalf("Worktree() error = %v", err)
+	}
+	for path, content := range files {
+		full := filepath.Join(wt.Filesystem.Root(), path)
+		if content == "" {
+			if err := os.Remove(full); err != nil {
+				t.Fatalf("failed to remove %s: %v", path, err)
+			}
+			continue
+		}
+		if err := os.WriteFile(full, []byte(content), 0o644); err != nil {
+			t.Fatalf("failed to write %s: %v", path, err)
+		}
+	}
+	if _, err := wt.Add("."); err != nil {
+		t.Fatalf("failed to add: %v", err)
+	}
+	hash, err := wt.Commit(trailers.FormatCheckpoint("Agent turn", cpID), &git.CommitOptions{
+		All:    true,
+		Author: &object.Signature{Name: "Test", Email: "test@test.com"},
+	})
+	if err != nil {
+		t.Fatalf("failed to commit: %v", err)
+	}
+
+	if err := NewGitStore(repo).WriteCommitted(context.Background(), WriteCommittedOptions{
+		CheckpointID: cpID,
+		SessionID:    "session-" + cpID.String(),
+		Strategy:     "manual-commit",
+		Transcript:   []byte(`{"type":"user"}` + "\n"),
+		Prompts:      []string{prompt},
+		Context:      []byte(ctxText),
+		AuthorName:   "Test",
+		AuthorEmail:  "test@test.com",
+	}); err != nil {
+		t.Fatalf("WriteCommitted() error = %v", err)
+	}
+	return hash
+}
+
+func TestDiffCheckpoints(t *testing.T) {
+	t.Parallel()
+	repo, _ := setupBranchTestRepo(t)
+	store := NewGitStore(repo)
+	ctx := context.Background()
+	cpA := id.MustCheckpointID("a1a1a1a1a1a1")
+	cpB := id.MustCheckpointID("b2b2b2b2b2b2")
+
+	hashA := commitCheckpoint(t, repo, cpA, map[string]string{"main.go": "package main\n", "old.txt": "old\n"}, "add main", "context A\n")
+	hashB := commitCheckpoint(t, repo, cpB, map[string]string{"main.go": "package main\n\nfunc main() {}\n", "old.txt": ""}, "add func", "context A\n")
+
+	diff, err := store.DiffCheckpoints(ctx, cpA, cpB)
+	if err != nil {
+		t.Fatalf("DiffCheckpoints() error = %v", err)
+	}
+	if diff.FromCommit != hashA || diff.ToCommit != hashB {
+		t.Errorf("commits = %s, %s, want %s, %s", diff.FromCommit, diff.ToCommit, hashA, hashB)
+	}
+	if diff.Prompts != (TextDiff{Before: "add main", After: "add func"}) {
+		t.Errorf("Prompts = %+v", diff.Prompts)
+	}
+	if diff.Context.Changed() {
+		t.Errorf("Context changed: %+v", diff.Context)
+	}
+
+	want := []FileDiff{
+		{FromPath: "main.go", ToPath: "main.go", Before: "package main\n", After: "package main\n\nfunc main() {}\n"},
+		{FromPath: "old.txt", Before: "old\n"},
+	}
+	if len(diff.Files) != len(want) {
+		t.Fatalf("Files = %+v, want %+v", diff.Files, want)
+	}
+	for i := range want {
+		if diff.Files[i] != want[i] {
+			t.Errorf("Files[%d] = %+v, want %+v", i, diff.Files[i], want[i])
+		}
+	}
+}
+
+func TestDiffCheckpoints_WithoutLinkedCommit(t *testing.T) {
+	t.Parallel()
+	repo, _ := setupBranchTestRepo(t)
+	store := NewGitStore(repo)
+	ctx := context.Background()
+	cpA := id.MustCheckpointID("a1a1a1a1a1a1")
+	cpB := id.MustCheckpointID("b2b2b2b2b2b2")
+
+	commitCheckpoint(t, repo, cpA, map[string]string{"main.go": "package main\n"}, "same", "before\n")
+	// cpB is written without a commit carrying its trailer
+	if err := store.WriteCommitted(ctx, WriteCommittedOptions{
+		CheckpointID: cpB,
+		SessionID:    "session-b",
+		Strategy:     "manual-commit",
+		Transcript:   []byte(`{"type":"user"}` + "\n"),
+		Prompts:      []string{"same"},
+		Context:      []byte("after\n"),
+		AuthorName:   "Test",
+		AuthorEmail:  "test@test.com",
+	}); err != nil {
+		t.Fatalf("WriteCommitted() error = %v", err)
+	}
+
+	diff, err := store.DiffCheckpoints(ctx, cpA, cpB)
+	if err != nil {
+		t.Fatalf("DiffCheckpoints() error = %v", err)
+	}
+	if !diff.ToCommit.IsZero() || diff.Files != nil {
+		t.Errorf("ToCommit = %s, Files = %+v, want none", diff.ToCommit, diff.Files)
+	}
+	if diff.Prompts.Changed() {
+		t.Errorf("Prompts changed: %+v", diff.Prompts)
+	}
+	if diff.Context != (TextDiff{Before: "before\n", After: "after\n"}) {
+		t.Errorf("Context = %+v", diff.Context)
+	}
+
+	if _, err := store.DiffCheckpoints(ctx, cpA, id.MustCheckpointID("ffffffffffff")); !errors.Is(err, ErrCheckpointNotFound) {
+		t.Errorf("DiffCheckpoints(missing) error = %v, want ErrCheckpointNotFound", err)
+	}
+}
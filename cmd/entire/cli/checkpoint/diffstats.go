@@ -0,0 +1,58 @@
+package checkpoint
+
+import (
+	"fmt"
+
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// DiffStats summarizes the code change of the commit a checkpoint is linked to.
+// It is computed when the checkpoint is written so listings don't have to diff
+// trees at read time.
+type DiffStats struct {
+	FilesChanged int `json:"files_changed"`
+	Insertions   int `json:"insertions"`
+	Deletions    int `json:"deletions"`
+}
+
+// ComputeDiffStats diffs two trees and counts changed files and lines.
+// from may be nil for a root commit. Binary files count as changed with
+// zero line changes, matching `git diff --shortstat`.
+func ComputeDiffStats(from, to *object.Tree) (*DiffStats, error) {
+	changes, err := object.DiffTree(from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to diff trees: %w", err)
+	}
+	patch, err := changes.Patch()
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute patch: %w", err)
+	}
+
+	stats := &DiffStats{}
+	for _, fs := range patch.Stats() {
+		stats.FilesChanged++
+		stats.Insertions += fs.Addition
+		stats.Deletions += fs.Deletion
+	}
+	return stats, nil
+}
+
+// ComputeCommitDiffStats returns the diff stats of commit against its first
+// parent (or the empty tree for a root commit).
+func ComputeCommitDiffStats(commit *object.Commit) (*DiffStats, error) {
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tree for %s: %w", commit.Hash, err)
+	}
+	var parentTree *object.Tree
+	if commit.NumParents() > 0 {
+		parent, err := commit.Parent(0)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get parent of %s: %w", commit.Hash, err)
+		}
+		if parentTree, err = parent.Tree(); err != nil {
+			return nil, fmt.Errorf("failed to get tree for %s: %w", parent.Hash, err)
+		}
+	}
+	return ComputeDiffStats(parentTree, tree)
+}
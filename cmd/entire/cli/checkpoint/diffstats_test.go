@@ -0,0 +1,117 @@
+package checkpoint
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+func TestComputeCommitDiffStats(t *testing.T) {
+	t.Parallel()
+	repo, initialHash := setupBranchTestRepo(t)
+
+	initial, err := repo.CommitObject(initialHash)
+	if err != nil {
+		t.Fatalf("CommitObject() error = %v", err)
+	}
+	stats, err := ComputeCommitDiffStats(initial)
+	if err != nil {
+		t.Fatalf("ComputeCommitDiffStats(root) error = %v", err)
+	}
+	if *stats != (DiffStats{FilesChanged: 1, Insertions: 1}) {
+		t.Errorf("root commit stats = %+v, want 1 file +1", *stats)
+	}
+
+	wt, err := repo.Worktree()
+	if err != nil {
+		t.Fatalf("Worktree() error = %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(wt.Filesystem.Root(), "README.md"), []byte("# Test\nmore\n"), 0o644); err != nil {
+		t.Fatalf("failed to write README: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(wt.Filesystem.Root(), "main.go"), []byte("package main\n\nfunc main() {}\n"), 0o644); err != nil {
+		t.Fatalf("failed to write main.go: %v", err)
+	}
+	if _, err := wt.Add("."); err != nil {
+		t.Fatalf("failed to add: %v", err)
+	}
+	hash, err := wt.Commit("Second", &git.CommitOptions{
+		Author: &object.Signature{Name: "Test", Email: "test@test.com"},
+	})
+	if err != nil {
+		t.Fatalf("failed to commit: %v", err)
+	}
+	commit, err := repo.CommitObject(hash)
+	if err != nil {
+		t.Fatalf("CommitObject() error = %v", err)
+	}
+
+	stats, err = ComputeCommitDiffStats(commit)
+	if err != nil {
+		t.Fatalf("ComputeCommitDiffStats() error = %v", err)
+	}
+	// README: "# Test" -> "# Test\nmore\n" replaces one line and adds one
+	want := DiffStats{FilesChanged: 2, Insertions: 5, Deletions: 1}
+	if *stats != want {
+		t.Errorf("stats = %+v, want %+v", *stats, want)
+	}
+}
+
+func TestDiffStats_WriteKeepAndUpdate(t *testing.T) {
+	t.Parallel()
+	repo, _ := setupBranchTestRepo(t)
+	store := NewGitStore(repo)
+	ctx := context.Background()
+	cpID := id.MustCheckpointID("d1f2a3b4c5d6")
+
+	write := func(sessionID string, stats *DiffStats) {
+		t.Helper()
+		if err := store.WriteCommitted(ctx, WriteCommittedOptions{
+			CheckpointID: cpID,
+			SessionID:    sessionID,
+			Strategy:     "manual-commit",
+			Transcript:   []byte(`{"type":"user"}` + "\n"),
+			AuthorName:   "Test",
+			AuthorEmail:  "test@test.com",
+			DiffStats:    stats,
+		}); err != nil {
+			t.Fatalf("WriteCommitted() error = %v", err)
+		}
+	}
+
+	write("session-1", &DiffStats{FilesChanged: 2, Insertions: 10, Deletions: 3})
+	// A second session without stats must not clear them
+	write("session-2", nil)
+
+	summary, err := store.ReadCommitted(ctx, cpID)
+	if err != nil {
+		t.Fatalf("ReadCommitted() error = %v", err)
+	}
+	if summary.DiffStats == nil || summary.DiffStats.Insertions != 10 {
+		t.Fatalf("DiffStats = %+v, want stats from first session", summary.DiffStats)
+	}
+
+	if err := store.UpdateDiffStats(ctx, cpID, &DiffStats{FilesChanged: 1, Insertions: 4}); err != nil {
+		t.Fatalf("UpdateDiffStats() error = %v", err)
+	}
+	committed, err := store.ListCommitted(ctx)
+	if err != nil {
+		t.Fatalf("ListCommitted() error = %v", err)
+	}
+	if len(committed) != 1 || committed[0].DiffStats == nil || committed[0].DiffStats.Insertions != 4 {
+		t.Errorf("ListCommitted() = %+v, want updated stats", committed)
+	}
+	if committed[0].SessionCount != 2 {
+		t.Errorf("SessionCount = %d, want 2 (update must keep sessions)", committed[0].SessionCount)
+	}
+
+	if err := store.UpdateDiffStats(ctx, id.MustCheckpointID("000000000000"), &DiffStats{}); err == nil {
+		t.Error("UpdateDiffStats() on missing checkpoint should fail")
+	}
+}
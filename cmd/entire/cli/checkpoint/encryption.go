@@ -0,0 +1,143 @@
+package checkpoint
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/entireio/cli/cmd/entire/cli/age"
+
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// IdentityFileEnv names the age identity file checkpoints are decrypted
+// with, overriding DefaultIdentityFile.
+const IdentityFileEnv = "ENTIRE_AGE_IDENTITY_FILE"
+
+// ErrNoIdentity is returned when checkpoint content is encrypted and none
+// of the available age identities can decrypt it.
+var ErrNoIdentity = errors.New("checkpoint content is encrypted and no matching age identity is available")
+
+// DefaultIdentityFile returns where `entire config encryption --generate-key`
+// stores the user's age identity.
+func DefaultIdentityFile() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find user config directory: %w", err)
+	}
+	return filepath.Join(dir, "entire", "age-identity.txt"), nil
+}
+
+// IdentityFile returns the identity file checkpoints are decrypted with:
+// $ENTIRE_AGE_IDENTITY_FILE, or DefaultIdentityFile.
+func IdentityFile() (string, error) {
+	if path := os.Getenv(IdentityFileEnv); path != "" {
+		return path, nil
+	}
+	return DefaultIdentityFile()
+}
+
+// LoadIdentities reads the identities in IdentityFile. Returns nil and no
+// error if the file doesn't exist.
+func LoadIdentities() ([]*age.Identity, error) {
+	path, err := IdentityFile()
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path) //nolint:gosec // path is the user's configured identity file
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open age identity file: %w", err)
+	}
+	defer f.Close()
+	ids, err := age.ParseIdentities(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read age identity file %s: %w", path, err)
+	}
+	return ids, nil
+}
+
+// ParseRecipients parses age public keys, as listed in the
+// encryption.recipients setting.
+func ParseRecipients(keys []string) ([]*age.Recipient, error) {
+	recipients := make([]*age.Recipient, 0, len(keys))
+	for _, key := range keys {
+		r, err := age.ParseRecipient(key)
+		if err != nil {
+			return nil, err //nolint:wrapcheck // already names the key
+		}
+		recipients = append(recipients, r)
+	}
+	return recipients, nil
+}
+
+// SetIdentities sets the identities the store decrypts checkpoints with,
+// instead of loading them from IdentityFile on first use.
+func (s *GitStore) SetIdentities(ids []*age.Identity) {
+	s.identityMu.Lock()
+	defer s.identityMu.Unlock()
+	s.identities = ids
+	s.identitiesLoaded = true
+}
+
+func (s *GitStore) loadIdentities() ([]*age.Identity, error) {
+	s.identityMu.Lock()
+	defer s.identityMu.Unlock()
+	if !s.identitiesLoaded {
+		ids, err := LoadIdentities()
+		if err != nil {
+			return nil, err
+		}
+		s.identities = ids
+		s.identitiesLoaded = true
+	}
+	return s.identities, nil
+}
+
+// createContentBlob stores session content, age-encrypted to recipients if
+// there are any.
+func (s *GitStore) createContentBlob(content []byte, recipients []*age.Recipient) (plumbing.Hash, error) {
+	if len(recipients) > 0 {
+		encrypted, err := age.Encrypt(content, recipients...)
+		if err != nil {
+			return plumbing.ZeroHash, fmt.Errorf("failed to encrypt checkpoint content: %w", err)
+		}
+		content = encrypted
+	}
+	return CreateBlobFromContent(s.repo, content)
+}
+
+// decryptContent returns session content as written, decrypting it if it is
+// age-encrypted. Unencrypted content is returned unchanged.
+func (s *GitStore) decryptContent(content []byte) ([]byte, error) {
+	if !age.IsEncrypted(content) {
+		return content, nil
+	}
+	ids, err := s.loadIdentities()
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := age.Decrypt(content, ids...)
+	if errors.Is(err, age.ErrNoMatchingIdentity) {
+		return nil, ErrNoIdentity
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt checkpoint content: %w", err)
+	}
+	return plaintext, nil
+}
+
+// decryptAndDecode reverses createContentBlob and encodePayload: it decrypts
+// content with decrypt, unless it is nil, then decompresses it.
+func decryptAndDecode(content []byte, encoding string, decrypt func([]byte) ([]byte, error)) ([]byte, error) {
+	if decrypt != nil {
+		var err error
+		if content, err = decrypt(content); err != nil {
+			return nil, err
+		}
+	}
+	return decodePayload(content, encoding)
+}
@@ -0,0 +1,245 @@
+package checkpoint
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/entireio/cli/cmd/entire/cli/age"
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+)
+
+func newTestIdentity(t *testing.T) *age.Identity {
+	t.Helper()
+	identity, err := age.GenerateIdentity()
+	if err != nil {
+		t.Fatalf("GenerateIdentity() error = %v", err)
+	}
+	return identity
+}
+
+func TestWriteCommitted_EncryptsSessionContent(t *testing.T) {
+	t.Parallel()
+	_, store, cpID := setupRepoForUpdate(t)
+	identity := newTestIdentity(t)
+	transcript := largeTranscript(2 * CompressMinBytes)
+
+	structured := &SessionContext{}
+	if err := structured.Set(ContextTask, "goal", "fix login"); err != nil {
+		t.Fatal(err)
+	}
+	err := store.WriteCommitted(context.Background(), WriteCommittedOptions{
+		CheckpointID:      cpID,
+		SessionID:         "session-001",
+		Strategy:          "manual-commit",
+		Transcript:        transcript,
+		Prompts:           []string{"fix the login bug"},
+		Context:           []byte("# Context\nlogin flow"),
+		StructuredContext: structured,
+		AgentConfig:       map[string][]byte{"CLAUDE.md": []byte("Run tests before committing.\n")},
+		AuthorName:        "Test",
+		AuthorEmail:       "test@test.com",
+		EncryptTo:         []*age.Recipient{identity.Recipient()},
+	})
+	if err != nil {
+		t.Fatalf("WriteCommitted() error = %v", err)
+	}
+
+	for _, name := range []string{paths.TranscriptFileName, paths.PromptFileName, paths.PromptsFileName, paths.ContextFileName, paths.ContextJSONFileName, paths.AgentConfigDirName + "/CLAUDE.md"} {
+		if raw := rawSessionFile(t, store, cpID, name); !age.IsEncrypted(raw) {
+			t.Errorf("%s should be stored encrypted", name)
+		}
+	}
+	if raw := rawSessionFile(t, store, cpID, paths.MetadataFileName); age.IsEncrypted(raw) {
+		t.Error("metadata.json should stay readable")
+	}
+
+	store.SetIdentities([]*age.Identity{identity})
+	content, err := store.ReadSessionContent(context.Background(), cpID, 0)
+	if err != nil {
+		t.Fatalf("ReadSessionContent() error = %v", err)
+	}
+	if content.Metadata.TranscriptEncoding != EncodingZstd {
+		t.Errorf("TranscriptEncoding = %q; large transcripts should still be compressed", content.Metadata.TranscriptEncoding)
+	}
+	if !bytes.Equal(content.Transcript, transcript) {
+		t.Error("transcript should round-trip through encryption")
+	}
+	if len(content.PromptList) != 1 || content.PromptList[0] != "fix the login bug" {
+		t.Errorf("PromptList = %q", content.PromptList)
+	}
+	if content.Context != "# Context\nlogin flow" {
+		t.Errorf("Context = %q", content.Context)
+	}
+	if goal, _ := content.StructuredContext.Get(ContextTask, "goal"); goal != "fix login" {
+		t.Errorf("StructuredContext task.goal = %q", goal)
+	}
+	config, err := store.ReadAgentConfig(context.Background(), cpID, 0)
+	if err != nil {
+		t.Fatalf("ReadAgentConfig() error = %v", err)
+	}
+	if got := string(config["CLAUDE.md"]); got != "Run tests before committing.\n" {
+		t.Errorf("CLAUDE.md = %q, want it to round-trip through encryption", got)
+	}
+}
+
+func TestReadSessionContent_EncryptedWithoutIdentity(t *testing.T) {
+	t.Parallel()
+	_, store, cpID := setupRepoForUpdate(t)
+	identity := newTestIdentity(t)
+
+	err := store.WriteCommitted(context.Background(), WriteCommittedOptions{
+		CheckpointID: cpID,
+		SessionID:    "session-001",
+		Strategy:     "manual-commit",
+		Transcript:   []byte("secret transcript\n"),
+		Prompts:      []string{"secret prompt"},
+		AuthorName:   "Test",
+		AuthorEmail:  "test@test.com",
+		EncryptTo:    []*age.Recipient{identity.Recipient()},
+	})
+	if err != nil {
+		t.Fatalf("WriteCommitted() error = %v", err)
+	}
+
+	for name, ids := range map[string][]*age.Identity{
+		"no identity":    nil,
+		"wrong identity": {newTestIdentity(t)},
+	} {
+		store.SetIdentities(ids)
+		if _, err := store.ReadSessionContent(context.Background(), cpID, 0); !errors.Is(err, ErrNoIdentity) {
+			t.Errorf("%s: ReadSessionContent() error = %v, want ErrNoIdentity", name, err)
+		}
+	}
+
+	// Metadata-only reads don't need the key
+	summary, err := store.ReadCommitted(context.Background(), cpID)
+	if err != nil || summary == nil {
+		t.Fatalf("ReadCommitted() = %v, %v", summary, err)
+	}
+}
+
+func TestUpdateCommitted_EncryptsReplacedContent(t *testing.T) {
+	t.Parallel()
+	_, store, cpID := setupRepoForUpdate(t)
+	identity := newTestIdentity(t)
+
+	err := store.UpdateCommitted(context.Background(), UpdateCommittedOptions{
+		CheckpointID: cpID,
+		SessionID:    "session-001",
+		Transcript:   []byte("final transcript line 1\nline 2\n"),
+		Prompts:      []string{"final prompt"},
+		Context:      []byte("final context"),
+		EncryptTo:    []*age.Recipient{identity.Recipient()},
+	})
+	if err != nil {
+		t.Fatalf("UpdateCommitted() error = %v", err)
+	}
+	if raw := rawSessionFile(t, store, cpID, paths.TranscriptFileName); !age.IsEncrypted(raw) {
+		t.Error("replaced transcript should be stored encrypted")
+	}
+
+	store.SetIdentities([]*age.Identity{identity})
+	content, err := store.ReadSessionContent(context.Background(), cpID, 0)
+	if err != nil {
+		t.Fatalf("ReadSessionContent() error = %v", err)
+	}
+	if string(content.Transcript) != "final transcript line 1\nline 2\n" || content.Prompts != "final prompt" || content.Context != "final context" {
+		t.Errorf("content = %q, %q, %q", content.Transcript, content.Prompts, content.Context)
+	}
+}
+
+func TestUpdateCommitted_SharesEncryptedTranscriptAcrossCheckpoints(t *testing.T) {
+	t.Parallel()
+	_, store, cpID1 := setupRepoForUpdate(t)
+	identity := newTestIdentity(t)
+	recipients := []*age.Recipient{identity.Recipient()}
+
+	cpID2 := id.MustCheckpointID("b2c3d4e5f6a1")
+	if err := store.WriteCommitted(context.Background(), WriteCommittedOptions{
+		CheckpointID: cpID2,
+		SessionID:    "session-001",
+		Strategy:     "manual-commit",
+		Transcript:   []byte("provisional cp2\n"),
+		AuthorName:   "Test",
+		AuthorEmail:  "test@test.com",
+	}); err != nil {
+		t.Fatalf("WriteCommitted(cp2) error = %v", err)
+	}
+
+	update := func(cpID id.CheckpointID, transcript string) {
+		t.Helper()
+		if err := store.UpdateCommitted(context.Background(), UpdateCommittedOptions{
+			CheckpointID: cpID,
+			SessionID:    "session-001",
+			Transcript:   []byte(transcript),
+			EncryptTo:    recipients,
+		}); err != nil {
+			t.Fatalf("UpdateCommitted(%s) error = %v", cpID, err)
+		}
+	}
+
+	update(cpID1, "complete full transcript\n")
+	update(cpID2, "complete full transcript\n")
+	raw1 := rawSessionFile(t, store, cpID1, paths.TranscriptFileName)
+	raw2 := rawSessionFile(t, store, cpID2, paths.TranscriptFileName)
+	if !age.IsEncrypted(raw1) || !bytes.Equal(raw1, raw2) {
+		t.Error("checkpoints with the same transcript should share one encrypted blob")
+	}
+	if !bytes.Equal(rawSessionFile(t, store, cpID1, paths.ContentHashFileName), rawSessionFile(t, store, cpID2, paths.ContentHashFileName)) {
+		t.Error("checkpoints with the same transcript should have the same content hash")
+	}
+
+	update(cpID2, "a different transcript\n")
+	if bytes.Equal(raw1, rawSessionFile(t, store, cpID2, paths.TranscriptFileName)) {
+		t.Error("a different transcript must not reuse the shared blob")
+	}
+
+	store.SetIdentities([]*age.Identity{identity})
+	for cpID, want := range map[id.CheckpointID]string{cpID1: "complete full transcript\n", cpID2: "a different transcript\n"} {
+		content, err := store.ReadSessionContent(context.Background(), cpID, 0)
+		if err != nil {
+			t.Fatalf("ReadSessionContent(%s) error = %v", cpID, err)
+		}
+		if string(content.Transcript) != want {
+			t.Errorf("checkpoint %s transcript = %q, want %q", cpID, content.Transcript, want)
+		}
+	}
+}
+
+func TestWriteCommitted_SharesEncryptedTranscriptFromBranch(t *testing.T) {
+	t.Parallel()
+	repo, store, _ := setupRepoForUpdate(t)
+	identity := newTestIdentity(t)
+
+	write := func(store *GitStore, cpID id.CheckpointID, recipients ...*age.Recipient) []byte {
+		t.Helper()
+		if err := store.WriteCommitted(context.Background(), WriteCommittedOptions{
+			CheckpointID: cpID,
+			SessionID:    "session-002",
+			Strategy:     "manual-commit",
+			Transcript:   []byte("shared transcript\n"),
+			AuthorName:   "Test",
+			AuthorEmail:  "test@test.com",
+			EncryptTo:    recipients,
+		}); err != nil {
+			t.Fatalf("WriteCommitted(%s) error = %v", cpID, err)
+		}
+		return rawSessionFile(t, store, cpID, paths.TranscriptFileName)
+	}
+
+	raw1 := write(store, id.MustCheckpointID("b2c3d4e5f6a1"), identity.Recipient())
+	// A new store, as in the next hook, finds the chunks on the branch
+	raw2 := write(NewGitStore(repo), id.MustCheckpointID("c3d4e5f6a1b2"), identity.Recipient())
+	if !age.IsEncrypted(raw1) || !bytes.Equal(raw1, raw2) {
+		t.Error("checkpoints with the same transcript should share one encrypted blob")
+	}
+
+	other := newTestIdentity(t)
+	raw3 := write(NewGitStore(repo), id.MustCheckpointID("d4e5f6a1b2c3"), identity.Recipient(), other.Recipient())
+	if bytes.Equal(raw1, raw3) {
+		t.Error("a transcript encrypted to other recipients must not reuse the shared blob")
+	}
+}
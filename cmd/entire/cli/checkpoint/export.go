@@ -0,0 +1,188 @@
+package checkpoint
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// ExportCheckpoint returns the files of a committed checkpoint exactly as
+// they are stored on the metadata branch, keyed by their path within the
+// checkpoint directory (e.g. "metadata.json", "0/full.jsonl"). Content stays
+// compressed and encrypted as stored, so ImportCheckpoint restores the
+// checkpoint byte for byte.
+// Returns ErrCheckpointNotFound if the checkpoint doesn't exist.
+func (s *GitStore) ExportCheckpoint(ctx context.Context, checkpointID id.CheckpointID) (map[string][]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err //nolint:wrapcheck // Propagating context cancellation
+	}
+
+	_, rootTreeHash, err := s.getSessionsBranchRef()
+	if err != nil {
+		return nil, ErrCheckpointNotFound
+	}
+	entries, err := s.flattenCheckpointEntries(rootTreeHash, checkpointID.Path())
+	if err != nil {
+		return nil, err
+	}
+	basePath := checkpointID.Path() + "/"
+	if _, ok := entries[basePath+paths.MetadataFileName]; !ok {
+		return nil, ErrCheckpointNotFound
+	}
+
+	files := make(map[string][]byte, len(entries))
+	for path, entry := range entries {
+		data, err := readBlob(s.repo, entry.Hash)
+		if err != nil {
+			return nil, err
+		}
+		files[strings.TrimPrefix(path, basePath)] = data
+	}
+	return files, nil
+}
+
+// ImportCheckpoint stores files returned by ExportCheckpoint as a committed
+// checkpoint on the metadata branch, replacing the checkpoint if it already
+// exists. The summary in metadata.json must name checkpointID.
+func (s *GitStore) ImportCheckpoint(ctx context.Context, checkpointID id.CheckpointID, files map[string][]byte) error {
+	if err := ctx.Err(); err != nil {
+		return err //nolint:wrapcheck // Propagating context cancellation
+	}
+
+	summaryData, ok := files[paths.MetadataFileName]
+	if !ok {
+		return fmt.Errorf("checkpoint %s has no %s", checkpointID, paths.MetadataFileName)
+	}
+	var summary CheckpointSummary
+	if err := json.Unmarshal(summaryData, &summary); err != nil {
+		return fmt.Errorf("failed to parse checkpoint %s summary: %w", checkpointID, err)
+	}
+	if summary.CheckpointID != checkpointID {
+		return fmt.Errorf("checkpoint %s summary names checkpoint %s", checkpointID, summary.CheckpointID)
+	}
+
+	if err := s.ensureSessionsBranch(); err != nil {
+		return fmt.Errorf("failed to ensure sessions branch: %w", err)
+	}
+	return s.commitMetadata(ctx, func(_, rootTreeHash plumbing.Hash) (*metadataCommit, error) {
+		basePath := checkpointID.Path() + "/"
+		entries := make(map[string]object.TreeEntry, len(files))
+		for name, data := range files {
+			if name == "" || strings.HasPrefix(name, "/") || strings.Contains("/"+name+"/", "/../") {
+				return nil, fmt.Errorf("invalid file path in checkpoint %s: %q", checkpointID, name)
+			}
+			blobHash, err := CreateBlobFromContent(s.repo, data)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create blob for %s: %w", name, err)
+			}
+			entries[basePath+name] = object.TreeEntry{Name: basePath + name, Mode: filemode.Regular, Hash: blobHash}
+		}
+
+		// The spliced subtree replaces an existing checkpoint directory whole
+		newTreeHash, err := s.spliceCheckpointSubtree(rootTreeHash, checkpointID, basePath, entries)
+		if err != nil {
+			return nil, err
+		}
+		authorName, authorEmail := GetGitAuthorFromRepo(s.repo)
+		return &metadataCommit{
+			Tree:          newTreeHash,
+			Message:       fmt.Sprintf("Import Checkpoint: %s", checkpointID),
+			AuthorName:    authorName,
+			AuthorEmail:   authorEmail,
+			CheckpointIDs: []id.CheckpointID{checkpointID},
+		}, nil
+	})
+}
+
+// ShadowSnapshot is the latest temporary checkpoint of a shadow branch in
+// portable form: its commit message and the files of its tree.
+type ShadowSnapshot struct {
+	Message string
+	Files   map[string]ShadowFile
+}
+
+// ShadowFile is a file of a shadow branch tree. For symlinks, Data is the
+// link target.
+type ShadowFile struct {
+	Mode filemode.FileMode
+	Data []byte
+}
+
+// ExportShadowBranch returns the latest temporary checkpoint of a shadow
+// branch. Submodule entries are left out. Returns nil, nil if the branch
+// doesn't exist.
+func (s *GitStore) ExportShadowBranch(ctx context.Context, branchName string) (*ShadowSnapshot, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err //nolint:wrapcheck // Propagating context cancellation
+	}
+
+	ref, err := s.repo.Reference(plumbing.NewBranchReferenceName(branchName), true)
+	if err != nil {
+		return nil, nil //nolint:nilnil,nilerr // Branch doesn't exist
+	}
+	commit, err := s.repo.CommitObject(ref.Hash())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commit object: %w", err)
+	}
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tree: %w", err)
+	}
+	entries := make(map[string]object.TreeEntry)
+	if err := FlattenTree(s.repo, tree, "", entries); err != nil {
+		return nil, err
+	}
+
+	snapshot := &ShadowSnapshot{Message: commit.Message, Files: make(map[string]ShadowFile, len(entries))}
+	for path, entry := range entries {
+		if entry.Mode == filemode.Submodule {
+			continue
+		}
+		data, err := readBlob(s.repo, entry.Hash)
+		if err != nil {
+			return nil, err
+		}
+		snapshot.Files[path] = ShadowFile{Mode: entry.Mode, Data: data}
+	}
+	return snapshot, nil
+}
+
+// ImportShadowBranch points a shadow branch at a new commit holding the
+// snapshot, replacing the branch if it exists. The commit has no parent,
+// like the first checkpoint of a new shadow branch.
+func (s *GitStore) ImportShadowBranch(ctx context.Context, branchName string, snapshot *ShadowSnapshot) error {
+	if err := ctx.Err(); err != nil {
+		return err //nolint:wrapcheck // Propagating context cancellation
+	}
+
+	entries := make(map[string]object.TreeEntry, len(snapshot.Files))
+	for path, file := range snapshot.Files {
+		blobHash, err := CreateBlobFromContent(s.repo, file.Data)
+		if err != nil {
+			return fmt.Errorf("failed to create blob for %s: %w", path, err)
+		}
+		entries[path] = object.TreeEntry{Name: path, Mode: file.Mode, Hash: blobHash}
+	}
+	treeHash, err := BuildTreeFromEntries(s.repo, entries)
+	if err != nil {
+		return err
+	}
+	authorName, authorEmail := GetGitAuthorFromRepo(s.repo)
+	commitHash, err := s.createCommit(treeHash, plumbing.ZeroHash, snapshot.Message, authorName, authorEmail)
+	if err != nil {
+		return err
+	}
+	ref := plumbing.NewHashReference(plumbing.NewBranchReferenceName(branchName), commitHash)
+	if err := s.repo.Storer.SetReference(ref); err != nil {
+		return fmt.Errorf("failed to set branch reference: %w", err)
+	}
+	return nil
+}
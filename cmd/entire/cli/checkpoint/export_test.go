@@ -0,0 +1,60 @@
+package checkpoint
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+)
+
+func TestExportImportCheckpoint_ReplacesExisting(t *testing.T) {
+	t.Parallel()
+	_, store, cpID := setupRepoForUpdate(t)
+	ctx := context.Background()
+
+	files, err := store.ExportCheckpoint(ctx, cpID)
+	if err != nil {
+		t.Fatalf("ExportCheckpoint() error = %v", err)
+	}
+	if _, ok := files["0/"+paths.TranscriptFileName]; !ok {
+		t.Fatalf("exported files %v lack the transcript", files)
+	}
+
+	// Files the imported version lacks don't survive the import
+	if err := store.UpdateCommitted(ctx, UpdateCommittedOptions{
+		CheckpointID: cpID,
+		SessionID:    "session-001",
+		Transcript:   []byte("replaced transcript\n"),
+	}); err != nil {
+		t.Fatalf("UpdateCommitted() error = %v", err)
+	}
+	if err := store.ImportCheckpoint(ctx, cpID, files); err != nil {
+		t.Fatalf("ImportCheckpoint() error = %v", err)
+	}
+	content, err := store.ReadSessionContent(ctx, cpID, 0)
+	if err != nil {
+		t.Fatalf("ReadSessionContent() error = %v", err)
+	}
+	if string(content.Transcript) != "provisional transcript line 1\n" {
+		t.Errorf("transcript after import = %q, want the exported one", content.Transcript)
+	}
+}
+
+func TestImportCheckpoint_RejectsMismatchedID(t *testing.T) {
+	t.Parallel()
+	_, store, cpID := setupRepoForUpdate(t)
+	ctx := context.Background()
+
+	files, err := store.ExportCheckpoint(ctx, cpID)
+	if err != nil {
+		t.Fatalf("ExportCheckpoint() error = %v", err)
+	}
+	if err := store.ImportCheckpoint(ctx, id.MustCheckpointID("ffffffffffff"), files); err == nil {
+		t.Error("ImportCheckpoint() under another ID succeeded, want an error")
+	}
+	if _, err := store.ExportCheckpoint(ctx, id.MustCheckpointID("ffffffffffff")); !errors.Is(err, ErrCheckpointNotFound) {
+		t.Errorf("ExportCheckpoint() error = %v, want ErrCheckpointNotFound", err)
+	}
+}
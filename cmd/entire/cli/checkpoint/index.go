@@ -0,0 +1,206 @@
+package checkpoint
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/storage/filesystem"
+)
+
+// IndexFileName is the checkpoint index's name in the git directory.
+const IndexFileName = "entire-index.json"
+
+// indexVersion is bumped when the index's format changes, so an index
+// written by another version is rebuilt instead of misread.
+const indexVersion = 4
+
+// checkpointIndex caches the listing of the metadata branch, so listing
+// checkpoints doesn't walk every checkpoint's tree. It is valid while Tip
+// is the branch's tip; writes through the store move it along, and any
+// other change to the branch (a fetch, a sync) makes the next listing
+// rebuild it.
+type checkpointIndex struct {
+	Version     int                               `json:"version"`
+	Tip         string                            `json:"tip"`
+	Checkpoints map[id.CheckpointID]CommittedInfo `json:"checkpoints"`
+}
+
+// list returns the indexed checkpoints, most recent first.
+func (idx *checkpointIndex) list() []CommittedInfo {
+	checkpoints := make([]CommittedInfo, 0, len(idx.Checkpoints))
+	for _, info := range idx.Checkpoints {
+		checkpoints = append(checkpoints, info)
+	}
+	sortCommitted(checkpoints)
+	return checkpoints
+}
+
+// IndexStatus describes the checkpoint index of a repository.
+type IndexStatus struct {
+	// Path is the index file, or "" if the repository has no git directory
+	// on disk to keep it in
+	Path string
+
+	// Exists is true if the index file exists and is readable
+	Exists bool
+
+	// Fresh is true if the index matches the tip of the metadata branch
+	Fresh bool
+
+	// Checkpoints is the number of checkpoints in the index
+	Checkpoints int
+}
+
+// IndexStatus reports the state of the checkpoint index.
+func (s *GitStore) IndexStatus(ctx context.Context) (IndexStatus, error) {
+	if err := ctx.Err(); err != nil {
+		return IndexStatus{}, err //nolint:wrapcheck // Propagating context cancellation
+	}
+	status := IndexStatus{Path: s.indexPath()}
+	idx := s.readIndex()
+	if idx == nil {
+		return status, nil
+	}
+	status.Exists = true
+	status.Checkpoints = len(idx.Checkpoints)
+	if commit, err := s.getSessionsBranchCommit(); err == nil {
+		status.Fresh = idx.Tip == commit.Hash.String()
+	}
+	return status, nil
+}
+
+// RebuildIndex rebuilds the checkpoint index from the metadata branch and
+// returns the number of checkpoints in it. The recall index is removed, to
+// be rebuilt by the next Recall.
+func (s *GitStore) RebuildIndex(ctx context.Context) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err //nolint:wrapcheck // Propagating context cancellation
+	}
+	path := s.indexPath()
+	if path == "" {
+		return 0, errors.New("repository has no git directory to keep an index in")
+	}
+	if err := os.Remove(s.RecallIndexPath()); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return 0, fmt.Errorf("failed to remove recall index: %w", err)
+	}
+
+	commit, err := s.getSessionsBranchCommit()
+	if err != nil {
+		// No sessions branch: drop any stale index
+		if removeErr := os.Remove(path); removeErr != nil && !errors.Is(removeErr, os.ErrNotExist) {
+			return 0, fmt.Errorf("failed to remove index: %w", removeErr)
+		}
+		return 0, nil
+	}
+	tree, err := commit.Tree()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get commit tree: %w", err)
+	}
+	checkpoints := s.scanCommitted(tree)
+	if err := s.writeIndex(newIndex(commit.Hash, checkpoints)); err != nil {
+		return 0, err
+	}
+	return len(checkpoints), nil
+}
+
+func newIndex(tip plumbing.Hash, checkpoints []CommittedInfo) *checkpointIndex {
+	idx := &checkpointIndex{
+		Version:     indexVersion,
+		Tip:         tip.String(),
+		Checkpoints: make(map[id.CheckpointID]CommittedInfo, len(checkpoints)),
+	}
+	for _, info := range checkpoints {
+		idx.Checkpoints[info.CheckpointID] = info
+	}
+	return idx
+}
+
+// indexPath returns the index file's path, or "" if the repository isn't
+// stored on disk (the in-memory repositories tests and ObjectStore use).
+func (s *GitStore) indexPath() string {
+	storage, ok := s.repo.Storer.(*filesystem.Storage)
+	if !ok {
+		return ""
+	}
+	return filepath.Join(storage.Filesystem().Root(), IndexFileName)
+}
+
+// readIndex returns the index, or nil if there is none or it can't be used.
+func (s *GitStore) readIndex() *checkpointIndex {
+	path := s.indexPath()
+	if path == "" {
+		return nil
+	}
+	data, err := os.ReadFile(path) //nolint:gosec // path is in the git directory
+	if err != nil {
+		return nil
+	}
+	var idx checkpointIndex
+	if err := json.Unmarshal(data, &idx); err != nil || idx.Version != indexVersion || idx.Checkpoints == nil {
+		return nil
+	}
+	return &idx
+}
+
+// writeIndex replaces the index file atomically, so a concurrent reader
+// never sees a partial index.
+func (s *GitStore) writeIndex(idx *checkpointIndex) error {
+	path := s.indexPath()
+	if path == "" {
+		return nil
+	}
+	data, err := json.Marshal(idx)
+	if err != nil {
+		return fmt.Errorf("failed to encode index: %w", err)
+	}
+	tmpFile := path + ".tmp"
+	if err := os.WriteFile(tmpFile, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write index: %w", err)
+	}
+	if err := os.Rename(tmpFile, path); err != nil {
+		return fmt.Errorf("failed to write index: %w", err)
+	}
+	return nil
+}
+
+// writeIndexFor records checkpoints as the listing of the branch at tip.
+// The index is only a cache, so failures are ignored.
+func (s *GitStore) writeIndexFor(ctx context.Context, tip plumbing.Hash, checkpoints []CommittedInfo) {
+	if ctx.Err() != nil {
+		return
+	}
+	_ = s.writeIndex(newIndex(tip, checkpoints)) //nolint:errcheck // the index is only a cache
+}
+
+// updateIndex moves the index along a commit to the metadata branch that
+// changed the given checkpoints, re-reading only those. An index that
+// didn't match the commit's parent is left for the next listing to rebuild.
+func (s *GitStore) updateIndex(ctx context.Context, parent, commit, tree plumbing.Hash, checkpointIDs ...id.CheckpointID) {
+	if ctx.Err() != nil {
+		return
+	}
+	idx := s.readIndex()
+	if idx == nil || idx.Tip != parent.String() {
+		return
+	}
+	rootTree, err := s.repo.TreeObject(tree)
+	if err != nil {
+		return
+	}
+	for _, checkpointID := range checkpointIDs {
+		if checkpointTree, err := rootTree.Tree(checkpointID.Path()); err == nil {
+			idx.Checkpoints[checkpointID] = committedInfo(checkpointTree, checkpointID)
+		} else {
+			delete(idx.Checkpoints, checkpointID)
+		}
+	}
+	idx.Tip = commit.String()
+	_ = s.writeIndex(idx) //nolint:errcheck // the index is only a cache
+}
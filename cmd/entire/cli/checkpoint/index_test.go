@@ -0,0 +1,162 @@
+package checkpoint
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+func writeIndexTestCheckpoint(t *testing.T, store *GitStore, cpID id.CheckpointID, sessionID string) {
+	t.Helper()
+	if err := store.WriteCommitted(context.Background(), WriteCommittedOptions{
+		CheckpointID: cpID,
+		SessionID:    sessionID,
+		Strategy:     "manual-commit",
+		Transcript:   []byte(`{"type":"user","message":{"content":"hello"}}` + "\n"),
+		FilesTouched: []string{"main.go"},
+		AuthorName:   "Test",
+		AuthorEmail:  "test@test.com",
+	}); err != nil {
+		t.Fatalf("WriteCommitted() error = %v", err)
+	}
+}
+
+func TestListCommitted_BuildsIndex(t *testing.T) {
+	repo, _ := setupBranchTestRepo(t)
+	store := NewGitStore(repo)
+	ctx := context.Background()
+
+	writeIndexTestCheckpoint(t, store, id.MustCheckpointID("aaaaaaaaaaaa"), "session-1")
+	if idx := store.readIndex(); idx != nil {
+		t.Fatalf("index exists before the first listing: %+v", idx)
+	}
+
+	list, err := store.ListCommitted(ctx)
+	if err != nil {
+		t.Fatalf("ListCommitted() error = %v", err)
+	}
+	if len(list) != 1 {
+		t.Fatalf("ListCommitted() returned %d checkpoints, want 1", len(list))
+	}
+
+	status, err := store.IndexStatus(ctx)
+	if err != nil {
+		t.Fatalf("IndexStatus() error = %v", err)
+	}
+	if !status.Exists || !status.Fresh || status.Checkpoints != 1 {
+		t.Errorf("IndexStatus() = %+v, want an up-to-date index of 1 checkpoint", status)
+	}
+}
+
+func TestWriteCommitted_UpdatesIndex(t *testing.T) {
+	repo, _ := setupBranchTestRepo(t)
+	store := NewGitStore(repo)
+	ctx := context.Background()
+
+	writeIndexTestCheckpoint(t, store, id.MustCheckpointID("aaaaaaaaaaaa"), "session-1")
+	if _, err := store.ListCommitted(ctx); err != nil {
+		t.Fatalf("ListCommitted() error = %v", err)
+	}
+
+	second := id.MustCheckpointID("bbbbbbbbbbbb")
+	writeIndexTestCheckpoint(t, store, second, "session-2")
+
+	idx := store.readIndex()
+	if idx == nil {
+		t.Fatal("index missing after WriteCommitted")
+	}
+	commit, err := store.getSessionsBranchCommit()
+	if err != nil {
+		t.Fatalf("getSessionsBranchCommit() error = %v", err)
+	}
+	if idx.Tip != commit.Hash.String() {
+		t.Errorf("index tip = %s, want branch tip %s", idx.Tip, commit.Hash)
+	}
+	info, ok := idx.Checkpoints[second]
+	if !ok {
+		t.Fatalf("index is missing checkpoint %s", second)
+	}
+	if info.SessionID != "session-2" || len(info.FilesTouched) != 1 {
+		t.Errorf("indexed checkpoint = %+v, want session-2 touching main.go", info)
+	}
+}
+
+func TestListCommitted_StaleIndexIsRebuilt(t *testing.T) {
+	repo, _ := setupBranchTestRepo(t)
+	store := NewGitStore(repo)
+	ctx := context.Background()
+
+	writeIndexTestCheckpoint(t, store, id.MustCheckpointID("aaaaaaaaaaaa"), "session-1")
+	writeIndexTestCheckpoint(t, store, id.MustCheckpointID("bbbbbbbbbbbb"), "session-2")
+
+	// An index left behind by an older branch tip must not be trusted
+	stale := newIndex(plumbing.NewHash("1111111111111111111111111111111111111111"), nil)
+	if err := store.writeIndex(stale); err != nil {
+		t.Fatalf("writeIndex() error = %v", err)
+	}
+
+	list, err := store.ListCommitted(ctx)
+	if err != nil {
+		t.Fatalf("ListCommitted() error = %v", err)
+	}
+	if len(list) != 2 {
+		t.Fatalf("ListCommitted() returned %d checkpoints, want 2", len(list))
+	}
+	if idx := store.readIndex(); idx == nil || len(idx.Checkpoints) != 2 {
+		t.Errorf("index was not rebuilt: %+v", idx)
+	}
+}
+
+func TestListCommitted_IgnoresCorruptIndex(t *testing.T) {
+	repo, _ := setupBranchTestRepo(t)
+	store := NewGitStore(repo)
+	ctx := context.Background()
+
+	writeIndexTestCheckpoint(t, store, id.MustCheckpointID("aaaaaaaaaaaa"), "session-1")
+	if err := os.WriteFile(store.indexPath(), []byte("{not json"), 0o600); err != nil {
+		t.Fatalf("failed to write index: %v", err)
+	}
+
+	list, err := store.ListCommitted(ctx)
+	if err != nil {
+		t.Fatalf("ListCommitted() error = %v", err)
+	}
+	if len(list) != 1 {
+		t.Fatalf("ListCommitted() returned %d checkpoints, want 1", len(list))
+	}
+}
+
+func TestRebuildIndex(t *testing.T) {
+	repo, _ := setupBranchTestRepo(t)
+	store := NewGitStore(repo)
+	ctx := context.Background()
+
+	count, err := store.RebuildIndex(ctx)
+	if err != nil {
+		t.Fatalf("RebuildIndex() without a branch error = %v", err)
+	}
+	if count != 0 {
+		t.Errorf("RebuildIndex() without a branch = %d, want 0", count)
+	}
+
+	writeIndexTestCheckpoint(t, store, id.MustCheckpointID("aaaaaaaaaaaa"), "session-1")
+	writeIndexTestCheckpoint(t, store, id.MustCheckpointID("bbbbbbbbbbbb"), "session-2")
+	count, err = store.RebuildIndex(ctx)
+	if err != nil {
+		t.Fatalf("RebuildIndex() error = %v", err)
+	}
+	if count != 2 {
+		t.Errorf("RebuildIndex() = %d, want 2", count)
+	}
+	status, err := store.IndexStatus(ctx)
+	if err != nil {
+		t.Fatalf("IndexStatus() error = %v", err)
+	}
+	if !status.Fresh {
+		t.Errorf("IndexStatus() = %+v, want fresh after rebuild", status)
+	}
+}
@@ -0,0 +1,150 @@
+package checkpoint
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+)
+
+// ErrInvalidCursor is returned when ListOptions.Cursor wasn't produced by a
+// previous page.
+var ErrInvalidCursor = errors.New("invalid list cursor")
+
+// ListOptions selects a page of committed checkpoints.
+type ListOptions struct {
+	// Limit is the maximum number of checkpoints in the page. Zero returns
+	// all remaining checkpoints.
+	Limit int
+
+	// Cursor continues a listing after the previous page; pass that page's
+	// NextCursor. Empty starts with the most recent checkpoint.
+	Cursor string
+
+	// SessionID keeps only checkpoints that one of their sessions has this ID.
+	SessionID string
+
+	// Tag keeps only checkpoints carrying this tag.
+	Tag string
+
+	// Since keeps only checkpoints created at or after this time. Zero keeps all.
+	Since time.Time
+
+	// Until keeps only checkpoints created before this time. Zero keeps all.
+	Until time.Time
+
+	// Branch keeps only checkpoints whose latest session was on this branch.
+	Branch string
+}
+
+// CommittedPage is one page of committed checkpoints, most recent first.
+type CommittedPage struct {
+	Checkpoints []CommittedInfo
+
+	// NextCursor fetches the following page, or is empty on the last page.
+	// Checkpoints written after the first page was listed don't shift later
+	// pages; they sort before the cursor.
+	NextCursor string
+}
+
+// ListCommittedPage returns a page of the committed checkpoints matching
+// opts, most recent first. Use ReadCommitted for a checkpoint's full summary.
+func (s *GitStore) ListCommittedPage(ctx context.Context, opts ListOptions) (*CommittedPage, error) {
+	return listCommittedPage(ctx, s, opts)
+}
+
+// ListCommittedPage returns a page of the checkpoints in the bucket, as
+// GitStore.ListCommittedPage does.
+func (s *ObjectStore) ListCommittedPage(ctx context.Context, opts ListOptions) (*CommittedPage, error) {
+	return listCommittedPage(ctx, s, opts)
+}
+
+func listCommittedPage(ctx context.Context, store Store, opts ListOptions) (*CommittedPage, error) {
+	if opts.Limit < 0 {
+		return nil, fmt.Errorf("invalid list limit %d", opts.Limit)
+	}
+	var after *CommittedInfo
+	if opts.Cursor != "" {
+		c, err := decodeListCursor(opts.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		after = &c
+	}
+
+	all, err := store.ListCommitted(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &CommittedPage{Checkpoints: []CommittedInfo{}}
+	for _, info := range all {
+		if after != nil && !committedBefore(*after, info) {
+			continue
+		}
+		if !opts.Since.IsZero() && info.CreatedAt.Before(opts.Since) {
+			continue
+		}
+		if !opts.Until.IsZero() && !info.CreatedAt.Before(opts.Until) {
+			continue
+		}
+		if opts.Branch != "" && info.Branch != opts.Branch {
+			continue
+		}
+		if opts.SessionID != "" && info.SessionID != opts.SessionID && !slices.Contains(info.SessionIDs, opts.SessionID) {
+			continue
+		}
+		if opts.Tag != "" && !slices.Contains(info.Tags, opts.Tag) {
+			continue
+		}
+		if opts.Limit > 0 && len(page.Checkpoints) == opts.Limit {
+			last := page.Checkpoints[len(page.Checkpoints)-1]
+			page.NextCursor = encodeListCursor(last)
+			break
+		}
+		page.Checkpoints = append(page.Checkpoints, info)
+	}
+	return page, nil
+}
+
+// encodeListCursor records the position of info in the listing order: its
+// creation time and ID.
+func encodeListCursor(info CommittedInfo) string {
+	// Checkpoints without a creation time sort last; UnixNano can't represent them
+	var nanos string
+	if !info.CreatedAt.IsZero() {
+		nanos = strconv.FormatInt(info.CreatedAt.UnixNano(), 10)
+	}
+	raw := nanos + ":" + info.CheckpointID.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeListCursor(cursor string) (CommittedInfo, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return CommittedInfo{}, ErrInvalidCursor
+	}
+	nanos, cpID, ok := strings.Cut(string(raw), ":")
+	if !ok {
+		return CommittedInfo{}, ErrInvalidCursor
+	}
+	checkpointID, err := id.NewCheckpointID(cpID)
+	if err != nil {
+		return CommittedInfo{}, ErrInvalidCursor
+	}
+	info := CommittedInfo{CheckpointID: checkpointID}
+	if nanos != "" {
+		n, err := strconv.ParseInt(nanos, 10, 64)
+		if err != nil {
+			return CommittedInfo{}, ErrInvalidCursor
+		}
+		info.CreatedAt = time.Unix(0, n)
+	}
+	return info, nil
+}
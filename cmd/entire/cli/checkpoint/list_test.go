@@ -0,0 +1,182 @@
+package checkpoint
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+)
+
+func TestListCommittedPage(t *testing.T) {
+	repo, _ := setupBranchTestRepo(t)
+	store := NewGitStore(repo)
+	ctx := context.Background()
+
+	writeIndexTestCheckpoint(t, store, id.MustCheckpointID("aaaaaaaaaaaa"), "session-1")
+	writeIndexTestCheckpoint(t, store, id.MustCheckpointID("bbbbbbbbbbbb"), "session-1")
+	mid := time.Now().UTC()
+	writeIndexTestCheckpoint(t, store, id.MustCheckpointID("cccccccccccc"), "session-2")
+	// A second session in the same checkpoint makes it multi-session
+	multi := id.MustCheckpointID("dddddddddddd")
+	writeIndexTestCheckpoint(t, store, multi, "session-1")
+	writeIndexTestCheckpoint(t, store, multi, "session-3")
+
+	all, err := store.ListCommitted(ctx)
+	if err != nil {
+		t.Fatalf("ListCommitted() error = %v", err)
+	}
+	if len(all) != 4 {
+		t.Fatalf("ListCommitted() returned %d checkpoints, want 4", len(all))
+	}
+
+	t.Run("pages cover the listing in order", func(t *testing.T) {
+		var got []id.CheckpointID
+		opts := ListOptions{Limit: 3}
+		pages := 0
+		for {
+			page, err := store.ListCommittedPage(ctx, opts)
+			if err != nil {
+				t.Fatalf("ListCommittedPage() error = %v", err)
+			}
+			pages++
+			for _, info := range page.Checkpoints {
+				got = append(got, info.CheckpointID)
+			}
+			if page.NextCursor == "" {
+				break
+			}
+			opts.Cursor = page.NextCursor
+		}
+		if pages != 2 {
+			t.Errorf("got %d pages, want 2", pages)
+		}
+		var want []id.CheckpointID
+		for _, info := range all {
+			want = append(want, info.CheckpointID)
+		}
+		if !slices.Equal(got, want) {
+			t.Errorf("paged checkpoints = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("session filter matches any session", func(t *testing.T) {
+		page, err := store.ListCommittedPage(ctx, ListOptions{SessionID: "session-1"})
+		if err != nil {
+			t.Fatalf("ListCommittedPage() error = %v", err)
+		}
+		var got []id.CheckpointID
+		for _, info := range page.Checkpoints {
+			got = append(got, info.CheckpointID)
+		}
+		slices.Sort(got)
+		want := []id.CheckpointID{"aaaaaaaaaaaa", "bbbbbbbbbbbb", multi}
+		if !slices.Equal(got, want) {
+			t.Errorf("checkpoints of session-1 = %v, want %v", got, want)
+		}
+		if page.NextCursor != "" {
+			t.Errorf("NextCursor = %q on the only page", page.NextCursor)
+		}
+	})
+
+	t.Run("since", func(t *testing.T) {
+		page, err := store.ListCommittedPage(ctx, ListOptions{Since: mid})
+		if err != nil {
+			t.Fatalf("ListCommittedPage() error = %v", err)
+		}
+		if len(page.Checkpoints) != 2 {
+			t.Errorf("ListCommittedPage(Since) returned %d checkpoints, want 2", len(page.Checkpoints))
+		}
+		for _, info := range page.Checkpoints {
+			if info.CreatedAt.Before(mid) {
+				t.Errorf("checkpoint %s created %v, before %v", info.CheckpointID, info.CreatedAt, mid)
+			}
+		}
+	})
+
+	t.Run("until", func(t *testing.T) {
+		page, err := store.ListCommittedPage(ctx, ListOptions{Until: mid})
+		if err != nil {
+			t.Fatalf("ListCommittedPage() error = %v", err)
+		}
+		var got []id.CheckpointID
+		for _, info := range page.Checkpoints {
+			got = append(got, info.CheckpointID)
+		}
+		slices.Sort(got)
+		if want := []id.CheckpointID{"aaaaaaaaaaaa", "bbbbbbbbbbbb"}; !slices.Equal(got, want) {
+			t.Errorf("ListCommittedPage(Until) = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("invalid options", func(t *testing.T) {
+		if _, err := store.ListCommittedPage(ctx, ListOptions{Cursor: "not a cursor"}); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("ListCommittedPage() with a bad cursor error = %v, want ErrInvalidCursor", err)
+		}
+		if _, err := store.ListCommittedPage(ctx, ListOptions{Limit: -1}); err == nil {
+			t.Error("ListCommittedPage() with a negative limit should fail")
+		}
+	})
+}
+
+func TestListCommittedPage_BranchFilter(t *testing.T) {
+	repo, _ := setupBranchTestRepo(t)
+	store := NewGitStore(repo)
+	ctx := context.Background()
+
+	for cpID, branch := range map[id.CheckpointID]string{"aaaaaaaaaaaa": "main", "bbbbbbbbbbbb": "feature", "cccccccccccc": ""} {
+		if err := store.WriteCommitted(ctx, WriteCommittedOptions{
+			CheckpointID: cpID,
+			SessionID:    "session-" + cpID.String(),
+			Strategy:     "manual-commit",
+			Branch:       branch,
+			Transcript:   []byte(`{"type":"user"}` + "\n"),
+			AuthorName:   "Test",
+			AuthorEmail:  "test@test.com",
+		}); err != nil {
+			t.Fatalf("WriteCommitted() error = %v", err)
+		}
+	}
+
+	page, err := store.ListCommittedPage(ctx, ListOptions{Branch: "feature"})
+	if err != nil {
+		t.Fatalf("ListCommittedPage() error = %v", err)
+	}
+	if len(page.Checkpoints) != 1 || page.Checkpoints[0].CheckpointID != "bbbbbbbbbbbb" {
+		t.Fatalf("ListCommittedPage(Branch) = %+v, want only bbbbbbbbbbbb", page.Checkpoints)
+	}
+	if page.Checkpoints[0].Branch != "feature" {
+		t.Errorf("Branch = %q, want feature", page.Checkpoints[0].Branch)
+	}
+}
+
+func TestListCommittedPage_NoBranch(t *testing.T) {
+	repo, _ := setupBranchTestRepo(t)
+
+	page, err := NewGitStore(repo).ListCommittedPage(context.Background(), ListOptions{Limit: 10})
+	if err != nil {
+		t.Fatalf("ListCommittedPage() error = %v", err)
+	}
+	if len(page.Checkpoints) != 0 || page.NextCursor != "" {
+		t.Errorf("ListCommittedPage() without a branch = %+v, want an empty page", page)
+	}
+}
+
+func TestListCursorRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	for _, info := range []CommittedInfo{
+		{CheckpointID: id.MustCheckpointID("a1b2c3d4e5f6"), CreatedAt: time.Date(2026, 3, 1, 12, 0, 0, 42, time.UTC)},
+		{CheckpointID: id.MustCheckpointID("a1b2c3d4e5f6")},
+	} {
+		got, err := decodeListCursor(encodeListCursor(info))
+		if err != nil {
+			t.Fatalf("decodeListCursor() error = %v", err)
+		}
+		if got.CheckpointID != info.CheckpointID || !got.CreatedAt.Equal(info.CreatedAt) || got.CreatedAt.IsZero() != info.CreatedAt.IsZero() {
+			t.Errorf("cursor round trip = %+v, want %+v", got, info)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package checkpoint
+
+import (
+	"fmt"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/memory"
+)
+
+// Compile-time check that MemoryStore implements the CommittedStore interface.
+var _ CommittedStore = (*MemoryStore)(nil)
+
+// MemoryStore is a GitStore whose repository lives in memory, for tools that
+// embed checkpoint storage and for tests that don't need a repository on
+// disk. It has the whole GitStore API: shadow branches and the metadata
+// branch are kept in the in-memory repository, and go away with it.
+//
+// Only the checkpoints are in memory. Temporary checkpoints still snapshot
+// files from the worktree on disk, as with GitStore, and a first checkpoint
+// (IsFirstCheckpoint) asks git status of a worktree on disk for them, so
+// pass the files to snapshot explicitly instead.
+type MemoryStore struct {
+	*GitStore
+}
+
+// NewMemoryStore creates an empty in-memory store. HEAD is an empty initial
+// commit on master, which new shadow branches start from.
+func NewMemoryStore() (*MemoryStore, error) {
+	repo, err := git.Init(memory.NewStorage(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create in-memory repository: %w", err)
+	}
+	store := NewGitStore(repo)
+
+	treeHash, err := BuildTreeFromEntries(repo, map[string]object.TreeEntry{})
+	if err != nil {
+		return nil, err
+	}
+	commitHash, err := store.createCommit(treeHash, plumbing.ZeroHash, "Initial commit", "Entire", "entire@localhost")
+	if err != nil {
+		return nil, err
+	}
+	head, err := repo.Storer.Reference(plumbing.HEAD)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read HEAD: %w", err)
+	}
+	if err := repo.Storer.SetReference(plumbing.NewHashReference(head.Target(), commitHash)); err != nil {
+		return nil, fmt.Errorf("failed to set %s: %w", head.Target(), err)
+	}
+	return &MemoryStore{GitStore: store}, nil
+}
@@ -0,0 +1,83 @@
+package checkpoint
+
+import (
+	"context"
+	"testing"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+func TestMemoryStore_Committed(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	store, err := NewMemoryStore()
+	if err != nil {
+		t.Fatalf("NewMemoryStore() error = %v", err)
+	}
+	cpID := id.MustCheckpointID("a1b2c3d4e5f6")
+
+	opts := testWriteOptions(cpID, "session-001")
+	opts.Prompts = []string{"add a login form"}
+	if err := store.WriteCommitted(ctx, opts); err != nil {
+		t.Fatalf("WriteCommitted() error = %v", err)
+	}
+	err = store.UpdateCommitted(ctx, UpdateCommittedOptions{
+		CheckpointID: cpID,
+		SessionID:    "session-001",
+		Transcript:   []byte(`{"type":"user","message":"final"}` + "\n"),
+	})
+	if err != nil {
+		t.Fatalf("UpdateCommitted() error = %v", err)
+	}
+
+	summary, err := store.ReadCommitted(ctx, cpID)
+	if err != nil || summary == nil || len(summary.Sessions) != 1 {
+		t.Fatalf("ReadCommitted() = %+v, %v", summary, err)
+	}
+	content, err := store.ReadSessionContentByID(ctx, cpID, "session-001")
+	if err != nil {
+		t.Fatalf("ReadSessionContentByID() error = %v", err)
+	}
+	if string(content.Transcript) != `{"type":"user","message":"final"}`+"\n" || content.Prompts != "add a login form" {
+		t.Errorf("content = %q, %q", content.Transcript, content.Prompts)
+	}
+	list, err := store.ListCommitted(ctx)
+	if err != nil || len(list) != 1 || list[0].CheckpointID != cpID {
+		t.Errorf("ListCommitted() = %+v, %v", list, err)
+	}
+}
+
+func TestMemoryStore_ShadowBranches(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	store, err := NewMemoryStore()
+	if err != nil {
+		t.Fatalf("NewMemoryStore() error = %v", err)
+	}
+	head, err := store.Repository().Head()
+	if err != nil {
+		t.Fatalf("the store should have a HEAD: %v", err)
+	}
+
+	base := head.Hash().String()
+	branch := plumbing.NewBranchReferenceName(ShadowBranchNameForCommit(base, ""))
+	if err := store.Repository().Storer.SetReference(plumbing.NewHashReference(branch, head.Hash())); err != nil {
+		t.Fatal(err)
+	}
+	if !store.ShadowBranchExists(base, "") {
+		t.Fatal("ShadowBranchExists() = false, want true")
+	}
+	if list, err := store.ListTemporary(ctx); err != nil || len(list) != 1 {
+		t.Errorf("ListTemporary() = %+v, %v; want the shadow branch", list, err)
+	}
+
+	// Deleted in memory, not with the git CLI in the working directory
+	if err := store.DeleteShadowBranch(ctx, base, ""); err != nil {
+		t.Fatalf("DeleteShadowBranch() error = %v", err)
+	}
+	if store.ShadowBranchExists(base, "") {
+		t.Error("shadow branch should be deleted")
+	}
+}
@@ -0,0 +1,118 @@
+package checkpoint
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/storage"
+	"github.com/go-git/go-git/v5/storage/filesystem"
+)
+
+const (
+	// maxMetadataCommitAttempts bounds how often a metadata branch write is
+	// rebuilt because another writer moved the branch first.
+	maxMetadataCommitAttempts = 8
+
+	// metadataCommitBackoff is the wait before the first retry. It doubles
+	// with every attempt, with jitter so colliding writers spread out.
+	metadataCommitBackoff = 10 * time.Millisecond
+)
+
+// ErrMetadataBranchBusy is returned when the metadata branch kept moving
+// under a write for maxMetadataCommitAttempts attempts. Nothing is written.
+var ErrMetadataBranchBusy = errors.New("metadata branch kept changing during the write")
+
+// metadataCommit is a commit for the metadata branch, built on the branch tip.
+type metadataCommit struct {
+	Tree        plumbing.Hash
+	Message     string
+	AuthorName  string
+	AuthorEmail string
+
+	// CheckpointIDs are the checkpoints the commit adds, changes, or removes,
+	// refreshed in the checkpoint index after the branch moves.
+	CheckpointIDs []id.CheckpointID
+}
+
+// commitMetadata builds a commit on the metadata branch tip with build and
+// moves the branch to it only if the tip is still the one build saw. When
+// another writer, such as a hook of a concurrent session, moved the branch
+// first, build runs again on the new tip after a short backoff, so neither
+// write is dropped. build may return nil to commit nothing.
+//
+// build can run several times and must not change state outside the
+// commit it returns. The branch must exist.
+func (s *GitStore) commitMetadata(ctx context.Context, build func(parentHash, rootTreeHash plumbing.Hash) (*metadataCommit, error)) error {
+	backoff := metadataCommitBackoff
+	for attempt := 1; ; attempt++ {
+		err := s.tryCommitMetadata(ctx, build)
+		if !errors.Is(err, storage.ErrReferenceHasChanged) {
+			return err
+		}
+		if attempt == maxMetadataCommitAttempts {
+			return fmt.Errorf("%w: gave up after %d attempts", ErrMetadataBranchBusy, attempt)
+		}
+
+		wait := backoff/2 + rand.N(backoff) //nolint:gosec // jitter doesn't need a secure source
+		select {
+		case <-ctx.Done():
+			return ctx.Err() //nolint:wrapcheck // Propagating context cancellation
+		case <-time.After(wait):
+		}
+		backoff *= 2
+	}
+}
+
+// tryCommitMetadata makes one attempt of commitMetadata. Returns
+// storage.ErrReferenceHasChanged if the tip moved; the commit built for the
+// old tip is left for git gc.
+func (s *GitStore) tryCommitMetadata(ctx context.Context, build func(parentHash, rootTreeHash plumbing.Hash) (*metadataCommit, error)) error {
+	refName := plumbing.NewBranchReferenceName(paths.MetadataBranchName)
+	parentHash, rootTreeHash, err := s.getSessionsBranchRef()
+	if err != nil {
+		// go-git rewrites a ref in place, so the branch reads as missing
+		// while another writer moves it; wait for that like for a moved tip
+		if errors.Is(err, plumbing.ErrReferenceNotFound) && s.refFileExists(refName) {
+			return storage.ErrReferenceHasChanged
+		}
+		return err
+	}
+
+	c, err := build(parentHash, rootTreeHash)
+	if err != nil || c == nil {
+		return err
+	}
+	commitHash, err := s.createCommit(c.Tree, parentHash, c.Message, c.AuthorName, c.AuthorEmail)
+	if err != nil {
+		return err
+	}
+
+	newRef := plumbing.NewHashReference(refName, commitHash)
+	err = s.repo.Storer.CheckAndSetReference(newRef, plumbing.NewHashReference(refName, parentHash))
+	if errors.Is(err, storage.ErrReferenceHasChanged) {
+		return err //nolint:wrapcheck // checked by commitMetadata
+	}
+	if err != nil {
+		return fmt.Errorf("failed to set branch reference: %w", err)
+	}
+	s.updateIndex(ctx, parentHash, commitHash, c.Tree, c.CheckpointIDs...)
+	return nil
+}
+
+// refFileExists reports whether name has a loose ref file on disk. Always
+// false for repositories that aren't stored on disk.
+func (s *GitStore) refFileExists(name plumbing.ReferenceName) bool {
+	fsStorage, ok := s.repo.Storer.(*filesystem.Storage)
+	if !ok {
+		return false
+	}
+	_, err := fsStorage.Filesystem().Stat(name.String())
+	return err == nil
+}
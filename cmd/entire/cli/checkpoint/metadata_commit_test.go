@@ -0,0 +1,174 @@
+package checkpoint
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// openSecondStore opens the repository of repo again, like a concurrent
+// process would, so its writes aren't seen through repo's caches.
+func openSecondStore(t *testing.T, repo *git.Repository) *GitStore {
+	t.Helper()
+	wt, err := repo.Worktree()
+	if err != nil {
+		t.Fatalf("failed to get worktree: %v", err)
+	}
+	other, err := git.PlainOpen(wt.Filesystem.Root())
+	if err != nil {
+		t.Fatalf("failed to reopen repo: %v", err)
+	}
+	return NewGitStore(other)
+}
+
+func testWriteOptions(cpID id.CheckpointID, sessionID string) WriteCommittedOptions {
+	return WriteCommittedOptions{
+		CheckpointID: cpID,
+		SessionID:    sessionID,
+		Strategy:     "manual-commit",
+		Transcript:   []byte(`{"type":"user","message":"` + sessionID + `"}` + "\n"),
+		AuthorName:   "Test",
+		AuthorEmail:  "test@test.com",
+	}
+}
+
+func TestCommitMetadata_RebuildsOnMovedTip(t *testing.T) {
+	t.Parallel()
+	repo, store, existing := setupRepoForUpdate(t)
+	other := openSecondStore(t, repo)
+	ctx := context.Background()
+	raced := id.MustCheckpointID("b1b2c3d4e5f6")
+	mine := id.MustCheckpointID("c1b2c3d4e5f6")
+
+	calls := 0
+	err := store.commitMetadata(ctx, func(_, rootTreeHash plumbing.Hash) (*metadataCommit, error) {
+		calls++
+		if calls == 1 {
+			// Another session writes between reading the tip and moving it
+			if err := other.WriteCommitted(ctx, testWriteOptions(raced, "other-session")); err != nil {
+				return nil, err
+			}
+		}
+		opts := testWriteOptions(mine, "my-session")
+		tree, _, err := store.stageCommitted(ctx, rootTreeHash, opts)
+		if err != nil {
+			return nil, err
+		}
+		return &metadataCommit{Tree: tree, Message: "test", AuthorName: "Test", AuthorEmail: "test@test.com"}, nil
+	})
+	if err != nil {
+		t.Fatalf("commitMetadata() error = %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("build ran %d times, want 2", calls)
+	}
+	for _, cpID := range []id.CheckpointID{existing, raced, mine} {
+		if summary, err := store.ReadCommitted(ctx, cpID); err != nil || summary == nil {
+			t.Errorf("checkpoint %s lost: %v", cpID, err)
+		}
+	}
+}
+
+func TestCommitMetadata_GivesUp(t *testing.T) {
+	t.Parallel()
+	repo, store, existing := setupRepoForUpdate(t)
+	other := openSecondStore(t, repo)
+	ctx := context.Background()
+
+	calls := 0
+	err := store.commitMetadata(ctx, func(_, rootTreeHash plumbing.Hash) (*metadataCommit, error) {
+		calls++
+		if err := other.Tag(ctx, existing, fmt.Sprintf("tag-%d", calls)); err != nil {
+			return nil, err
+		}
+		return &metadataCommit{Tree: rootTreeHash, Message: "test", AuthorName: "Test", AuthorEmail: "test@test.com"}, nil
+	})
+	if !errors.Is(err, ErrMetadataBranchBusy) {
+		t.Fatalf("commitMetadata() error = %v, want ErrMetadataBranchBusy", err)
+	}
+	if calls != maxMetadataCommitAttempts {
+		t.Errorf("build ran %d times, want %d", calls, maxMetadataCommitAttempts)
+	}
+	summary, err := store.ReadCommitted(ctx, existing)
+	if err != nil || summary == nil || len(summary.Tags) != maxMetadataCommitAttempts {
+		t.Errorf("concurrent writes lost: summary = %+v, err = %v", summary, err)
+	}
+}
+
+func TestCommitMetadata_StopsOnCancel(t *testing.T) {
+	t.Parallel()
+	repo, store, existing := setupRepoForUpdate(t)
+	other := openSecondStore(t, repo)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	calls := 0
+	err := store.commitMetadata(ctx, func(_, rootTreeHash plumbing.Hash) (*metadataCommit, error) {
+		calls++
+		if err := other.Tag(context.Background(), existing, "moved"); err != nil {
+			return nil, err
+		}
+		cancel()
+		return &metadataCommit{Tree: rootTreeHash, Message: "test", AuthorName: "Test", AuthorEmail: "test@test.com"}, nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("commitMetadata() error = %v, want context.Canceled", err)
+	}
+	if calls != 1 {
+		t.Errorf("build ran %d times after cancel, want 1", calls)
+	}
+}
+
+func TestWriteCommitted_ConcurrentSessions(t *testing.T) {
+	t.Parallel()
+
+	t.Run("existing branch", func(t *testing.T) {
+		t.Parallel()
+		repo, _, existing := setupRepoForUpdate(t)
+		writeConcurrently(t, repo, existing)
+	})
+	t.Run("first writes create the branch", func(t *testing.T) {
+		t.Parallel()
+		repo, _ := setupBranchTestRepo(t)
+		writeConcurrently(t, repo)
+	})
+}
+
+// writeConcurrently writes a checkpoint from each of several stores on repo
+// at once, and checks that none of them, nor the checkpoints in kept, is lost.
+func writeConcurrently(t *testing.T, repo *git.Repository, kept ...id.CheckpointID) {
+	t.Helper()
+	ctx := context.Background()
+
+	const writers = 6
+	ids := make([]id.CheckpointID, writers)
+	errs := make([]error, writers)
+	var wg sync.WaitGroup
+	for i := range writers {
+		ids[i] = id.MustCheckpointID(fmt.Sprintf("d%011x", i))
+		writer := openSecondStore(t, repo)
+		wg.Go(func() {
+			errs[i] = writer.WriteCommitted(ctx, testWriteOptions(ids[i], fmt.Sprintf("session-%d", i)))
+		})
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			t.Errorf("writer %d: WriteCommitted() error = %v", i, err)
+		}
+	}
+	store := NewGitStore(repo)
+	for _, cpID := range append(ids, kept...) {
+		if summary, err := store.ReadCommitted(ctx, cpID); err != nil || summary == nil {
+			t.Errorf("checkpoint %s lost: %v", cpID, err)
+		}
+	}
+}
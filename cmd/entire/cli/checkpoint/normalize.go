@@ -0,0 +1,108 @@
+package checkpoint
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// MaxPromptBytes caps a single prompt stored in prompt.txt. Longer prompts
+	// (e.g. a whole file pasted in) are truncated with a marker.
+	MaxPromptBytes = 64 << 10
+
+	// MaxContextBytes caps the context.md stored with a session.
+	MaxContextBytes = 1 << 20
+)
+
+// NormalizedText records that prompts or context were changed before being
+// stored, so readers know the text isn't verbatim. Absent from metadata when
+// the input was stored as given.
+type NormalizedText struct {
+	// OriginalBytes is the size of the input before normalization. For
+	// prompts, it is the total of all prompts.
+	OriginalBytes int `json:"original_bytes"`
+
+	// Truncated is set when the input exceeded its size cap.
+	Truncated bool `json:"truncated,omitempty"`
+
+	// InvalidUTF8 is set when invalid byte sequences were replaced with U+FFFD.
+	InvalidUTF8 bool `json:"invalid_utf8,omitempty"`
+
+	// ControlChars is set when control characters other than newline and tab
+	// were removed.
+	ControlChars bool `json:"control_chars,omitempty"`
+}
+
+// merge folds other into n, summing original sizes.
+func (n *NormalizedText) merge(other NormalizedText) {
+	n.OriginalBytes += other.OriginalBytes
+	n.Truncated = n.Truncated || other.Truncated
+	n.InvalidUTF8 = n.InvalidUTF8 || other.InvalidUTF8
+	n.ControlChars = n.ControlChars || other.ControlChars
+}
+
+func (n NormalizedText) changed() bool {
+	return n.Truncated || n.InvalidUTF8 || n.ControlChars
+}
+
+// normalizeText makes s safe to store and render: invalid UTF-8 is replaced,
+// control characters other than newline and tab are stripped, and text over
+// limit bytes is cut at a rune boundary and followed by a truncation marker.
+func normalizeText(s string, limit int) (string, NormalizedText) {
+	result := NormalizedText{OriginalBytes: len(s)}
+
+	if !utf8.ValidString(s) {
+		result.InvalidUTF8 = true
+		s = strings.ToValidUTF8(s, string(utf8.RuneError))
+	}
+
+	stripped := strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) && r != '\n' && r != '\t' {
+			return -1
+		}
+		return r
+	}, s)
+	if len(stripped) != len(s) {
+		result.ControlChars = true
+		s = stripped
+	}
+
+	if len(s) > limit {
+		result.Truncated = true
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		s = s[:cut] + fmt.Sprintf("\n\n[truncated: %d of %d bytes kept]", cut, result.OriginalBytes)
+	}
+
+	return s, result
+}
+
+// normalizePrompts normalizes each prompt. The returned record is nil when
+// nothing had to change.
+func normalizePrompts(prompts []string) ([]string, *NormalizedText) {
+	var total NormalizedText
+	normalized := make([]string, len(prompts))
+	for i, prompt := range prompts {
+		var result NormalizedText
+		normalized[i], result = normalizeText(prompt, MaxPromptBytes)
+		total.merge(result)
+	}
+	if !total.changed() {
+		return normalized, nil
+	}
+	return normalized, &total
+}
+
+// normalizeContext normalizes context.md content. The returned record is
+// nil when nothing had to change.
+func normalizeContext(context []byte) ([]byte, *NormalizedText) {
+	normalized, result := normalizeText(string(context), MaxContextBytes)
+	if !result.changed() {
+		return context, nil
+	}
+	return []byte(normalized), &result
+}
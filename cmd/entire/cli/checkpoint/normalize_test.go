@@ -0,0 +1,91 @@
+package checkpoint
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestNormalizeText(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name   string
+		input  string
+		limit  int
+		want   string
+		result NormalizedText
+	}{
+		{"unchanged", "fix the bug\n\tplease", 100, "fix the bug\n\tplease", NormalizedText{OriginalBytes: 19}},
+		{"invalid UTF-8", "caf\xe9", 100, "caf\uFFFD", NormalizedText{OriginalBytes: 4, InvalidUTF8: true}},
+		{"control characters", "a\x00b\x1b[31mc\r\n", 100, "ab[31mc\n", NormalizedText{OriginalBytes: 11, ControlChars: true}},
+		{"truncated at rune boundary", "ab€cd", 4, "ab\n\n[truncated: 2 of 7 bytes kept]", NormalizedText{OriginalBytes: 7, Truncated: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, result := normalizeText(tt.input, tt.limit)
+			if got != tt.want {
+				t.Errorf("normalizeText() = %q, want %q", got, tt.want)
+			}
+			if result != tt.result {
+				t.Errorf("normalizeText() result = %+v, want %+v", result, tt.result)
+			}
+			if !utf8.ValidString(got) {
+				t.Errorf("normalizeText() returned invalid UTF-8 %q", got)
+			}
+		})
+	}
+}
+
+func TestWriteCommitted_NormalizesPromptsAndContext(t *testing.T) {
+	t.Parallel()
+	_, store, cpID := setupRepoForUpdate(t)
+
+	huge := strings.Repeat("x", MaxPromptBytes+10)
+	if err := store.UpdateCommitted(context.Background(), UpdateCommittedOptions{
+		CheckpointID: cpID,
+		SessionID:    "session-001",
+		Prompts:      []string{"ok", huge},
+		Context:      []byte("bad \xff\x07 context"),
+	}); err != nil {
+		t.Fatalf("UpdateCommitted() error = %v", err)
+	}
+
+	content, err := store.ReadSessionContent(context.Background(), cpID, 0)
+	if err != nil {
+		t.Fatalf("ReadSessionContent() error = %v", err)
+	}
+	if !strings.HasSuffix(content.Prompts, "[truncated: 65536 of 65546 bytes kept]") {
+		t.Errorf("prompt was not truncated with a marker: ...%q", content.Prompts[len(content.Prompts)-60:])
+	}
+	if content.Context != "bad \uFFFD context" {
+		t.Errorf("Context = %q", content.Context)
+	}
+
+	prompts := content.Metadata.PromptsNormalized
+	if prompts == nil || !prompts.Truncated || prompts.OriginalBytes != len(huge)+2 {
+		t.Errorf("PromptsNormalized = %+v, want truncated with original size %d", prompts, len(huge)+2)
+	}
+	ctxNorm := content.Metadata.ContextNormalized
+	if ctxNorm == nil || !ctxNorm.InvalidUTF8 || !ctxNorm.ControlChars || ctxNorm.OriginalBytes != 14 {
+		t.Errorf("ContextNormalized = %+v", ctxNorm)
+	}
+
+	// Clean content clears the records again
+	if err := store.UpdateCommitted(context.Background(), UpdateCommittedOptions{
+		CheckpointID: cpID,
+		SessionID:    "session-001",
+		Prompts:      []string{"fine"},
+		Context:      []byte("fine"),
+	}); err != nil {
+		t.Fatalf("UpdateCommitted() error = %v", err)
+	}
+	content, err = store.ReadSessionContent(context.Background(), cpID, 0)
+	if err != nil {
+		t.Fatalf("ReadSessionContent() error = %v", err)
+	}
+	if content.Metadata.PromptsNormalized != nil || content.Metadata.ContextNormalized != nil {
+		t.Errorf("normalization records should be cleared, got %+v %+v", content.Metadata.PromptsNormalized, content.Metadata.ContextNormalized)
+	}
+}
@@ -0,0 +1,515 @@
+package checkpoint
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/entireio/cli/cmd/entire/cli/age"
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+	"github.com/entireio/cli/cmd/entire/cli/objstore"
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/memory"
+)
+
+// Compile-time check that ObjectStore implements the CommittedStore interface.
+var _ CommittedStore = (*ObjectStore)(nil)
+
+// ObjectStore keeps committed checkpoints in an object store bucket instead
+// of on the entire/checkpoints/v1 branch, so transcripts never enter git.
+// Temporary checkpoints stay on local shadow branches, which rewind needs.
+//
+// Objects use the same layout as the branch: the key of each file is its
+// path in the branch tree (<id[:2]>/<id[2:]>/...). To reuse GitStore's
+// logic, each operation loads the checkpoint's objects into an in-memory
+// repository, runs the GitStore operation there, and uploads what changed.
+// Writes to one checkpoint from two machines at once are not merged; the
+// last writer's files win.
+type ObjectStore struct {
+	local  *GitStore
+	bucket objstore.Bucket
+
+	identities    []*age.Identity
+	identitiesSet bool
+}
+
+// NewObjectStore creates a store with committed checkpoints in bucket and
+// temporary checkpoints in local's repository.
+func NewObjectStore(local *GitStore, bucket objstore.Bucket) *ObjectStore {
+	return &ObjectStore{local: local, bucket: bucket}
+}
+
+// SetIdentities sets the identities used to decrypt encrypted checkpoints,
+// as GitStore.SetIdentities does.
+func (s *ObjectStore) SetIdentities(ids []*age.Identity) {
+	s.identities = ids
+	s.identitiesSet = true
+	s.local.SetIdentities(ids)
+}
+
+// WriteTemporary writes to a local shadow branch.
+func (s *ObjectStore) WriteTemporary(ctx context.Context, opts WriteTemporaryOptions) (WriteTemporaryResult, error) {
+	return s.local.WriteTemporary(ctx, opts)
+}
+
+// ReadTemporary reads from a local shadow branch.
+func (s *ObjectStore) ReadTemporary(ctx context.Context, baseCommit, worktreeID string) (*ReadTemporaryResult, error) {
+	return s.local.ReadTemporary(ctx, baseCommit, worktreeID)
+}
+
+// ListTemporary lists the local shadow branches.
+func (s *ObjectStore) ListTemporary(ctx context.Context) ([]TemporaryInfo, error) {
+	return s.local.ListTemporary(ctx)
+}
+
+// WriteCommitted writes a committed checkpoint to the bucket.
+func (s *ObjectStore) WriteCommitted(ctx context.Context, opts WriteCommittedOptions) error {
+	if opts.CheckpointID.IsEmpty() {
+		return errors.New("invalid checkpoint options: checkpoint ID is required")
+	}
+	staged, err := s.stageCheckpoint(ctx, opts.CheckpointID)
+	if err != nil {
+		return err
+	}
+	if err := staged.store.WriteCommitted(ctx, opts); err != nil {
+		return err
+	}
+	return s.upload(ctx, staged, opts.CheckpointID)
+}
+
+// UpdateCommitted replaces the content of a session of a checkpoint in the
+// bucket. Returns ErrCheckpointNotFound if the checkpoint doesn't exist.
+func (s *ObjectStore) UpdateCommitted(ctx context.Context, opts UpdateCommittedOptions) error {
+	if opts.CheckpointID.IsEmpty() {
+		return errors.New("invalid update options: checkpoint ID is required")
+	}
+	staged, err := s.stageCheckpoint(ctx, opts.CheckpointID)
+	if err != nil {
+		return err
+	}
+	if err := staged.store.UpdateCommitted(ctx, opts); err != nil {
+		return err
+	}
+	return s.upload(ctx, staged, opts.CheckpointID)
+}
+
+// ReadCommitted reads a checkpoint's summary from the bucket.
+// Returns nil, nil if the checkpoint does not exist.
+func (s *ObjectStore) ReadCommitted(ctx context.Context, checkpointID id.CheckpointID) (*CheckpointSummary, error) {
+	// The summary is a single object; don't fetch the session content
+	staged, err := s.stage(ctx, []string{checkpointID.Path() + "/" + paths.MetadataFileName})
+	if err != nil {
+		return nil, err
+	}
+	return staged.store.ReadCommitted(ctx, checkpointID)
+}
+
+// ReadSessionContent reads a session's content from the bucket.
+func (s *ObjectStore) ReadSessionContent(ctx context.Context, checkpointID id.CheckpointID, sessionIndex int) (*SessionContent, error) {
+	staged, err := s.stageCheckpoint(ctx, checkpointID)
+	if err != nil {
+		return nil, err
+	}
+	return staged.store.ReadSessionContent(ctx, checkpointID, sessionIndex)
+}
+
+// ReadSessionContentByID reads a session's content by its session ID.
+func (s *ObjectStore) ReadSessionContentByID(ctx context.Context, checkpointID id.CheckpointID, sessionID string) (*SessionContent, error) {
+	staged, err := s.stageCheckpoint(ctx, checkpointID)
+	if err != nil {
+		return nil, err
+	}
+	return staged.store.ReadSessionContentByID(ctx, checkpointID, sessionID)
+}
+
+// ListSessions lists a checkpoint's sessions from its metadata in the bucket.
+func (s *ObjectStore) ListSessions(ctx context.Context, checkpointID id.CheckpointID) ([]SessionRef, error) {
+	staged, err := s.stageCheckpoint(ctx, checkpointID)
+	if err != nil {
+		return nil, err
+	}
+	return staged.store.ListSessions(ctx, checkpointID)
+}
+
+// ReadLatestSessionContent reads the content of a checkpoint's most recent
+// session.
+func (s *ObjectStore) ReadLatestSessionContent(ctx context.Context, checkpointID id.CheckpointID) (*SessionContent, error) {
+	staged, err := s.stageCheckpoint(ctx, checkpointID)
+	if err != nil {
+		return nil, err
+	}
+	return staged.store.ReadLatestSessionContent(ctx, checkpointID)
+}
+
+// OpenTranscript streams a session's transcript from the staged checkpoint.
+func (s *ObjectStore) OpenTranscript(ctx context.Context, checkpointID id.CheckpointID, sessionIndex int) (io.ReadCloser, error) {
+	staged, err := s.stageCheckpoint(ctx, checkpointID)
+	if err != nil {
+		return nil, err
+	}
+	return staged.store.OpenTranscript(ctx, checkpointID, sessionIndex)
+}
+
+// ListCommitted lists the checkpoints in the bucket, most recent first.
+func (s *ObjectStore) ListCommitted(ctx context.Context) ([]CommittedInfo, error) {
+	keys, err := s.bucket.List(ctx, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list checkpoints: %w", err)
+	}
+	// Listing only needs the checkpoint and session metadata
+	var metadataKeys []string
+	for _, key := range keys {
+		if isMetadataKey(key) {
+			metadataKeys = append(metadataKeys, key)
+		}
+	}
+	staged, err := s.stage(ctx, metadataKeys)
+	if err != nil {
+		return nil, err
+	}
+	return staged.store.ListCommitted(ctx)
+}
+
+// ReadSessionMetadata reads the metadata of each session of a checkpoint in
+// the bucket, without fetching their content.
+func (s *ObjectStore) ReadSessionMetadata(ctx context.Context, checkpointID id.CheckpointID) ([]CommittedMetadata, error) {
+	keys, err := s.bucket.List(ctx, checkpointID.Path()+"/")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list checkpoint %s: %w", checkpointID, err)
+	}
+	staged, err := s.stage(ctx, slices.DeleteFunc(keys, func(key string) bool { return !isMetadataKey(key) }))
+	if err != nil {
+		return nil, err
+	}
+	return staged.store.ReadSessionMetadata(ctx, checkpointID)
+}
+
+// ReadAgentConfig reads the agent config files stored with a session.
+func (s *ObjectStore) ReadAgentConfig(ctx context.Context, checkpointID id.CheckpointID, sessionIndex int) (map[string][]byte, error) {
+	staged, err := s.stageCheckpoint(ctx, checkpointID)
+	if err != nil {
+		return nil, err
+	}
+	return staged.store.ReadAgentConfig(ctx, checkpointID, sessionIndex)
+}
+
+// UpdateSummary replaces the AI summary of a checkpoint in the bucket.
+func (s *ObjectStore) UpdateSummary(ctx context.Context, checkpointID id.CheckpointID, summary *Summary) error {
+	return s.updateCheckpoint(ctx, checkpointID, func(store *GitStore) error {
+		return store.UpdateSummary(ctx, checkpointID, summary)
+	})
+}
+
+// AddComment adds a comment to a checkpoint in the bucket, as
+// GitStore.AddComment does.
+func (s *ObjectStore) AddComment(ctx context.Context, checkpointID id.CheckpointID, comment Comment) (Comment, error) {
+	var added Comment
+	err := s.updateCheckpoint(ctx, checkpointID, func(store *GitStore) error {
+		var err error
+		added, err = store.AddComment(ctx, checkpointID, comment)
+		return err
+	})
+	return added, err
+}
+
+// ReadComments reads a checkpoint's comments from the bucket.
+func (s *ObjectStore) ReadComments(ctx context.Context, checkpointID id.CheckpointID) ([]Comment, error) {
+	staged, err := s.stageCheckpoint(ctx, checkpointID)
+	if err != nil {
+		return nil, err
+	}
+	return staged.store.ReadComments(ctx, checkpointID)
+}
+
+// Tag adds tags to a checkpoint in the bucket, as GitStore.Tag does.
+func (s *ObjectStore) Tag(ctx context.Context, checkpointID id.CheckpointID, tags ...string) error {
+	return s.updateCheckpoint(ctx, checkpointID, func(store *GitStore) error {
+		return store.Tag(ctx, checkpointID, tags...)
+	})
+}
+
+// Untag removes tags from a checkpoint in the bucket.
+func (s *ObjectStore) Untag(ctx context.Context, checkpointID id.CheckpointID, tags ...string) error {
+	return s.updateCheckpoint(ctx, checkpointID, func(store *GitStore) error {
+		return store.Untag(ctx, checkpointID, tags...)
+	})
+}
+
+// ListByTag returns the checkpoints in the bucket carrying tag, most recent first.
+func (s *ObjectStore) ListByTag(ctx context.Context, tag string) ([]CommittedInfo, error) {
+	page, err := s.ListCommittedPage(ctx, ListOptions{Tag: tag})
+	if err != nil {
+		return nil, err
+	}
+	return page.Checkpoints, nil
+}
+
+// DeleteCommitted removes a checkpoint's objects from the bucket. Unlike on
+// the metadata branch, no earlier copy is kept unless the bucket is versioned.
+//
+// Returns ErrCheckpointNotFound if the checkpoint doesn't exist, and
+// ErrCheckpointInUse if opts.ReferencedBy isn't empty and opts.Force isn't set.
+func (s *ObjectStore) DeleteCommitted(ctx context.Context, checkpointID id.CheckpointID, opts DeleteOptions) error {
+	if len(opts.ReferencedBy) > 0 && !opts.Force {
+		return fmt.Errorf("%w: referenced by session(s) %s", ErrCheckpointInUse, strings.Join(opts.ReferencedBy, ", "))
+	}
+	removed, err := s.PruneCommitted(ctx, []id.CheckpointID{checkpointID}, "")
+	if err != nil {
+		return err
+	}
+	if removed == 0 {
+		return ErrCheckpointNotFound
+	}
+	return nil
+}
+
+// PruneCommitted removes checkpoints' objects from the bucket and returns
+// how many checkpoints were removed. IDs that aren't in the bucket are
+// skipped. The message is unused: the bucket has no history to record it in.
+func (s *ObjectStore) PruneCommitted(ctx context.Context, checkpointIDs []id.CheckpointID, _ string) (int, error) {
+	var removed int
+	for _, checkpointID := range checkpointIDs {
+		keys, err := s.bucket.List(ctx, checkpointID.Path()+"/")
+		if err != nil {
+			return removed, fmt.Errorf("failed to list checkpoint %s: %w", checkpointID, err)
+		}
+		if len(keys) == 0 {
+			continue
+		}
+		// The summary goes first, so readers listing checkpoints don't find
+		// one whose session files are already gone
+		summaryKey := checkpointID.Path() + "/" + paths.MetadataFileName
+		sort.Slice(keys, func(i, j int) bool { return keys[i] == summaryKey && keys[j] != summaryKey })
+		for _, key := range keys {
+			if err := s.bucket.Delete(ctx, key); err != nil && !errors.Is(err, objstore.ErrNotExist) {
+				return removed, fmt.Errorf("failed to delete checkpoint object: %w", err)
+			}
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+// DiffCheckpoints compares two checkpoints in the bucket.
+func (s *ObjectStore) DiffCheckpoints(ctx context.Context, a, b id.CheckpointID) (*CheckpointDiff, error) {
+	var keys []string
+	for _, checkpointID := range []id.CheckpointID{a, b} {
+		cpKeys, err := s.bucket.List(ctx, checkpointID.Path()+"/")
+		if err != nil {
+			return nil, fmt.Errorf("failed to list checkpoint %s: %w", checkpointID, err)
+		}
+		keys = append(keys, cpKeys...)
+	}
+	staged, err := s.stage(ctx, keys)
+	if err != nil {
+		return nil, err
+	}
+	return staged.store.DiffCheckpoints(ctx, a, b)
+}
+
+// VerifyCommitted checks every checkpoint in the bucket against its content
+// hashes. It fetches the whole bucket.
+func (s *ObjectStore) VerifyCommitted(ctx context.Context) ([]CheckpointVerification, error) {
+	keys, err := s.bucket.List(ctx, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list checkpoints: %w", err)
+	}
+	staged, err := s.stage(ctx, keys)
+	if err != nil {
+		return nil, err
+	}
+	return staged.store.VerifyCommitted(ctx)
+}
+
+// ExportCheckpoint returns the files of a checkpoint in the bucket.
+func (s *ObjectStore) ExportCheckpoint(ctx context.Context, checkpointID id.CheckpointID) (map[string][]byte, error) {
+	staged, err := s.stageCheckpoint(ctx, checkpointID)
+	if err != nil {
+		return nil, err
+	}
+	return staged.store.ExportCheckpoint(ctx, checkpointID)
+}
+
+// ImportCheckpoint writes an exported checkpoint's files to the bucket.
+func (s *ObjectStore) ImportCheckpoint(ctx context.Context, checkpointID id.CheckpointID, files map[string][]byte) error {
+	return s.updateCheckpoint(ctx, checkpointID, func(store *GitStore) error {
+		return store.ImportCheckpoint(ctx, checkpointID, files)
+	})
+}
+
+// ExportShadowBranch exports a local shadow branch.
+func (s *ObjectStore) ExportShadowBranch(ctx context.Context, branchName string) (*ShadowSnapshot, error) {
+	return s.local.ExportShadowBranch(ctx, branchName)
+}
+
+// ImportShadowBranch imports a shadow branch into the local repository.
+func (s *ObjectStore) ImportShadowBranch(ctx context.Context, branchName string, snapshot *ShadowSnapshot) error {
+	return s.local.ImportShadowBranch(ctx, branchName, snapshot)
+}
+
+// updateCheckpoint runs update on a staged copy of the checkpoint and
+// uploads what it changed.
+func (s *ObjectStore) updateCheckpoint(ctx context.Context, checkpointID id.CheckpointID, update func(*GitStore) error) error {
+	staged, err := s.stageCheckpoint(ctx, checkpointID)
+	if err != nil {
+		return err
+	}
+	if err := update(staged.store); err != nil {
+		return err
+	}
+	return s.upload(ctx, staged, checkpointID)
+}
+
+// isMetadataKey reports whether key is a checkpoint's metadata.json or one
+// of its sessions'.
+func isMetadataKey(key string) bool {
+	parts := strings.Split(key, "/")
+	if parts[len(parts)-1] != paths.MetadataFileName {
+		return false
+	}
+	switch len(parts) {
+	case 3:
+		return true
+	case 4:
+		return strings.Trim(parts[2], "0123456789") == ""
+	default:
+		return false
+	}
+}
+
+// stagedCheckpoints is an in-memory metadata branch holding objects loaded
+// from the bucket.
+type stagedCheckpoints struct {
+	store *GitStore
+
+	// loaded maps each loaded object's key to its blob hash
+	loaded map[string]plumbing.Hash
+}
+
+// stageCheckpoint loads every object of a checkpoint.
+func (s *ObjectStore) stageCheckpoint(ctx context.Context, checkpointID id.CheckpointID) (*stagedCheckpoints, error) {
+	keys, err := s.bucket.List(ctx, checkpointID.Path()+"/")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list checkpoint %s: %w", checkpointID, err)
+	}
+	return s.stage(ctx, keys)
+}
+
+// stage loads the objects at keys into the metadata branch of a new
+// in-memory repository. Keys without an object are skipped.
+func (s *ObjectStore) stage(ctx context.Context, keys []string) (*stagedCheckpoints, error) {
+	repo, err := git.Init(memory.NewStorage(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create staging repository: %w", err)
+	}
+	staged := &stagedCheckpoints{store: NewGitStore(repo), loaded: make(map[string]plumbing.Hash, len(keys))}
+	if s.identitiesSet {
+		staged.store.SetIdentities(s.identities)
+	}
+
+	entries := make(map[string]object.TreeEntry, len(keys))
+	for _, key := range keys {
+		data, err := s.bucket.Get(ctx, key)
+		if errors.Is(err, objstore.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read checkpoint object: %w", err)
+		}
+		hash, err := CreateBlobFromContent(repo, data)
+		if err != nil {
+			return nil, err
+		}
+		entries[key] = object.TreeEntry{Name: path.Base(key), Mode: filemode.Regular, Hash: hash}
+		staged.loaded[key] = hash
+	}
+
+	treeHash, err := BuildTreeFromEntries(repo, entries)
+	if err != nil {
+		return nil, err
+	}
+	commitHash, err := staged.store.createCommit(treeHash, plumbing.ZeroHash, "Load checkpoints from object store", "Entire", "entire@localhost")
+	if err != nil {
+		return nil, err
+	}
+	ref := plumbing.NewHashReference(plumbing.NewBranchReferenceName(paths.MetadataBranchName), commitHash)
+	if err := repo.Storer.SetReference(ref); err != nil {
+		return nil, fmt.Errorf("failed to set branch reference: %w", err)
+	}
+	return staged, nil
+}
+
+// upload writes the checkpoint's changed files back to the bucket and
+// deletes the ones the operation removed.
+func (s *ObjectStore) upload(ctx context.Context, staged *stagedCheckpoints, checkpointID id.CheckpointID) error {
+	_, rootTreeHash, err := staged.store.getSessionsBranchRef()
+	if err != nil {
+		return err
+	}
+	entries, err := staged.store.flattenCheckpointEntries(rootTreeHash, checkpointID.Path())
+	if err != nil {
+		return err
+	}
+
+	summaryKey := checkpointID.Path() + "/" + paths.MetadataFileName
+	changed := make([]string, 0, len(entries))
+	for key, entry := range entries {
+		if staged.loaded[key] != entry.Hash {
+			changed = append(changed, key)
+		}
+	}
+	// The summary goes last, so readers listing checkpoints don't find one
+	// whose session files are still uploading
+	sort.Slice(changed, func(i, j int) bool {
+		if (changed[i] == summaryKey) != (changed[j] == summaryKey) {
+			return changed[j] == summaryKey
+		}
+		return changed[i] < changed[j]
+	})
+	for _, key := range changed {
+		data, err := readBlob(staged.store.repo, entries[key].Hash)
+		if err != nil {
+			return err
+		}
+		if err := s.bucket.Put(ctx, key, data); err != nil {
+			return fmt.Errorf("failed to upload checkpoint object: %w", err)
+		}
+	}
+
+	prefix := checkpointID.Path() + "/"
+	for key := range staged.loaded {
+		if _, ok := entries[key]; !ok && strings.HasPrefix(key, prefix) {
+			if err := s.bucket.Delete(ctx, key); err != nil {
+				return fmt.Errorf("failed to delete checkpoint object: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+func readBlob(repo *git.Repository, hash plumbing.Hash) ([]byte, error) {
+	blob, err := repo.BlobObject(hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read blob %s: %w", hash, err)
+	}
+	r, err := blob.Reader()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read blob %s: %w", hash, err)
+	}
+	defer r.Close()
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read blob %s: %w", hash, err)
+	}
+	return data, nil
+}
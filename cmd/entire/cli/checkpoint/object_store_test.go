@@ -0,0 +1,212 @@
+package checkpoint
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+	"github.com/entireio/cli/cmd/entire/cli/objstore"
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+func newTestObjectStore(t *testing.T) (*ObjectStore, *GitStore, objstore.Bucket) {
+	t.Helper()
+	repo, _ := setupBranchTestRepo(t)
+	bucket, err := objstore.Open(objstore.Config{URL: "file://" + t.TempDir()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	local := NewGitStore(repo)
+	return NewObjectStore(local, bucket), local, bucket
+}
+
+func TestObjectStore_CommittedRoundTrip(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	store, local, bucket := newTestObjectStore(t)
+	cpID := id.MustCheckpointID("a1b2c3d4e5f6")
+
+	for i, sessionID := range []string{"session-001", "session-002"} {
+		err := store.WriteCommitted(ctx, WriteCommittedOptions{
+			CheckpointID: cpID,
+			SessionID:    sessionID,
+			Strategy:     "manual-commit",
+			Transcript:   fmt.Appendf(nil, "{\"n\":%d}\n", i+1),
+			Prompts:      []string{"prompt from " + sessionID},
+			FilesTouched: []string{sessionID + ".go"},
+			AuthorName:   "Test",
+			AuthorEmail:  "test@test.com",
+		})
+		if err != nil {
+			t.Fatalf("WriteCommitted(%s) error = %v", sessionID, err)
+		}
+	}
+
+	// Nothing committed goes to git
+	if _, err := local.repo.Reference(plumbing.NewBranchReferenceName(paths.MetadataBranchName), true); err == nil {
+		t.Error("the metadata branch should not be created")
+	}
+	if _, err := bucket.Get(ctx, cpID.Path()+"/1/"+paths.TranscriptFileName); err != nil {
+		t.Errorf("second session's transcript should be in the bucket: %v", err)
+	}
+
+	summary, err := store.ReadCommitted(ctx, cpID)
+	if err != nil || summary == nil {
+		t.Fatalf("ReadCommitted() = %v, %v", summary, err)
+	}
+	if len(summary.Sessions) != 2 || len(summary.FilesTouched) != 2 {
+		t.Errorf("summary has %d sessions, files %v; want 2 sessions and both files", len(summary.Sessions), summary.FilesTouched)
+	}
+
+	content, err := store.ReadSessionContentByID(ctx, cpID, "session-001")
+	if err != nil {
+		t.Fatalf("ReadSessionContentByID() error = %v", err)
+	}
+	if string(content.Transcript) != "{\"n\":1}\n" || content.Prompts != "prompt from session-001" {
+		t.Errorf("content = %q, %q", content.Transcript, content.Prompts)
+	}
+
+	list, err := store.ListCommitted(ctx)
+	if err != nil {
+		t.Fatalf("ListCommitted() error = %v", err)
+	}
+	if len(list) != 1 || list[0].CheckpointID != cpID || list[0].SessionID != "session-002" || list[0].SessionCount != 2 {
+		t.Errorf("ListCommitted() = %+v", list)
+	}
+
+	missing, err := store.ReadCommitted(ctx, id.MustCheckpointID("ffffffffffff"))
+	if err != nil || missing != nil {
+		t.Errorf("ReadCommitted(missing) = %v, %v; want nil, nil", missing, err)
+	}
+}
+
+func TestObjectStore_UpdateCommitted(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	store, _, bucket := newTestObjectStore(t)
+	cpID := id.MustCheckpointID("a1b2c3d4e5f6")
+
+	err := store.UpdateCommitted(ctx, UpdateCommittedOptions{CheckpointID: cpID, SessionID: "session-001", Transcript: []byte("x\n")})
+	if !errors.Is(err, ErrCheckpointNotFound) {
+		t.Fatalf("UpdateCommitted(missing) error = %v, want ErrCheckpointNotFound", err)
+	}
+
+	if err := store.WriteCommitted(ctx, WriteCommittedOptions{
+		CheckpointID: cpID,
+		SessionID:    "session-001",
+		Strategy:     "manual-commit",
+		Transcript:   []byte("provisional\n"),
+		Prompts:      []string{"first"},
+		AuthorName:   "Test",
+		AuthorEmail:  "test@test.com",
+	}); err != nil {
+		t.Fatalf("WriteCommitted() error = %v", err)
+	}
+	summaryBefore, err := bucket.Get(ctx, cpID.Path()+"/"+paths.MetadataFileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.UpdateCommitted(ctx, UpdateCommittedOptions{
+		CheckpointID: cpID,
+		SessionID:    "session-001",
+		Transcript:   []byte("final line 1\nfinal line 2\n"),
+		Prompts:      []string{"first", "second"},
+	}); err != nil {
+		t.Fatalf("UpdateCommitted() error = %v", err)
+	}
+
+	content, err := store.ReadSessionContent(ctx, cpID, 0)
+	if err != nil {
+		t.Fatalf("ReadSessionContent() error = %v", err)
+	}
+	if string(content.Transcript) != "final line 1\nfinal line 2\n" || len(content.PromptList) != 2 {
+		t.Errorf("content = %q, %q", content.Transcript, content.PromptList)
+	}
+	summaryAfter, err := bucket.Get(ctx, cpID.Path()+"/"+paths.MetadataFileName)
+	if err != nil || string(summaryAfter) != string(summaryBefore) {
+		t.Errorf("an update that leaves the summary alone shouldn't rewrite it")
+	}
+}
+
+func TestObjectStore_CheckpointOperations(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	store, local, bucket := newTestObjectStore(t)
+	first := id.MustCheckpointID("a1b2c3d4e5f6")
+	second := id.MustCheckpointID("b1b2c3d4e5f6")
+
+	for _, cpID := range []id.CheckpointID{first, second} {
+		if err := store.WriteCommitted(ctx, WriteCommittedOptions{
+			CheckpointID: cpID,
+			SessionID:    "session-" + cpID.String(),
+			Strategy:     "manual-commit",
+			Transcript:   []byte("{}\n"),
+			FilesTouched: []string{cpID.String() + ".go"},
+			AuthorName:   "Test",
+			AuthorEmail:  "test@test.com",
+		}); err != nil {
+			t.Fatalf("WriteCommitted(%s) error = %v", cpID, err)
+		}
+	}
+
+	if err := store.Tag(ctx, first, "release"); err != nil {
+		t.Fatalf("Tag() error = %v", err)
+	}
+	tagged, err := store.ListByTag(ctx, "release")
+	if err != nil || len(tagged) != 1 || tagged[0].CheckpointID != first {
+		t.Errorf("ListByTag() = %+v, %v; want only %s", tagged, err, first)
+	}
+
+	if _, err := store.AddComment(ctx, first, Comment{Body: "looks good", AuthorName: "Test"}); err != nil {
+		t.Fatalf("AddComment() error = %v", err)
+	}
+	comments, err := store.ReadComments(ctx, first)
+	if err != nil || len(comments) != 1 || comments[0].Body != "looks good" {
+		t.Errorf("ReadComments() = %+v, %v", comments, err)
+	}
+
+	diff, err := store.DiffCheckpoints(ctx, first, second)
+	if err != nil || diff == nil {
+		t.Fatalf("DiffCheckpoints() = %v, %v", diff, err)
+	}
+
+	if err := store.DeleteCommitted(ctx, second, DeleteOptions{}); err != nil {
+		t.Fatalf("DeleteCommitted() error = %v", err)
+	}
+	if _, err := bucket.Get(ctx, second.Path()+"/"+paths.MetadataFileName); err == nil {
+		t.Error("the deleted checkpoint's summary is still in the bucket")
+	}
+	list, err := store.ListCommitted(ctx)
+	if err != nil || len(list) != 1 || list[0].CheckpointID != first {
+		t.Errorf("ListCommitted() = %+v, %v; want only %s", list, err, first)
+	}
+
+	// None of it touches the metadata branch
+	if _, err := local.repo.Reference(plumbing.NewBranchReferenceName(paths.MetadataBranchName), true); err == nil {
+		t.Error("the metadata branch should not be created")
+	}
+}
+
+func TestIsMetadataKey(t *testing.T) {
+	t.Parallel()
+
+	for key, want := range map[string]bool{
+		"a1/b2c3d4e5f6/metadata.json":           true,
+		"a1/b2c3d4e5f6/0/metadata.json":         true,
+		"a1/b2c3d4e5f6/12/metadata.json":        true,
+		"a1/b2c3d4e5f6/0/full.jsonl":            false,
+		"a1/b2c3d4e5f6/tasks/t1/metadata.json":  false,
+		"a1/b2c3d4e5f6/comments/metadata.json":  false,
+		"metadata.json":                         false,
+		"a1/b2c3d4e5f6/0/attachments/meta.json": false,
+	} {
+		if got := isMetadataKey(key); got != want {
+			t.Errorf("isMetadataKey(%q) = %v, want %v", key, got, want)
+		}
+	}
+}
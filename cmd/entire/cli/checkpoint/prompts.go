@@ -0,0 +1,95 @@
+package checkpoint
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/entireio/cli/cmd/entire/cli/age"
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+	"github.com/entireio/cli/redact"
+
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// PromptSeparator joins prompts in prompt.txt. Checkpoints also store the
+// prompts as a JSON array in prompts.json, which readers prefer, since a
+// prompt can itself contain the separator.
+const PromptSeparator = "\n\n---\n\n"
+
+// JoinPrompts renders prompts the way prompt.txt stores them.
+func JoinPrompts(prompts []string) string {
+	return strings.Join(prompts, PromptSeparator)
+}
+
+// SplitPrompts splits prompt.txt content back into prompts. It is only
+// exact for checkpoints whose prompts don't contain PromptSeparator; use it
+// for checkpoints written before prompts.json existed.
+func SplitPrompts(content string) []string {
+	if content == "" {
+		return nil
+	}
+	return strings.Split(content, PromptSeparator)
+}
+
+// writePromptEntries writes prompts to sessionPath as prompts.json and, for
+// older readers and people browsing the branch, as prompt.txt. Prompts are
+// redacted individually, and encrypted to recipients if any.
+func (s *GitStore) writePromptEntries(prompts []string, sessionPath string, entries map[string]object.TreeEntry, recipients []*age.Recipient) error {
+	redacted := make([]string, len(prompts))
+	for i, prompt := range prompts {
+		redacted[i] = redact.String(prompt)
+	}
+
+	promptsJSON, err := json.Marshal(redacted)
+	if err != nil {
+		return fmt.Errorf("failed to marshal prompts: %w", err)
+	}
+	files := map[string][]byte{
+		paths.PromptFileName:  []byte(JoinPrompts(redacted)),
+		paths.PromptsFileName: promptsJSON,
+	}
+	for name, content := range files {
+		blobHash, err := s.createContentBlob(content, recipients)
+		if err != nil {
+			return fmt.Errorf("failed to create prompt blob: %w", err)
+		}
+		entries[sessionPath+name] = object.TreeEntry{
+			Name: sessionPath + name,
+			Mode: filemode.Regular,
+			Hash: blobHash,
+		}
+	}
+	return nil
+}
+
+// readPromptsFromTree reads a session's prompts, preferring prompts.json and
+// falling back to splitting prompt.txt for older checkpoints. Returns the
+// prompt.txt content as well, for callers that display it as is. Files are
+// passed through decrypt first, unless it is nil.
+func readPromptsFromTree(sessionTree *object.Tree, decrypt func([]byte) ([]byte, error)) ([]string, string, error) {
+	var joined string
+	if file, err := sessionTree.File(paths.PromptFileName); err == nil {
+		if content, err := file.Contents(); err == nil {
+			decrypted, err := decryptAndDecode([]byte(content), "", decrypt)
+			if err != nil {
+				return nil, "", fmt.Errorf("failed to read prompts: %w", err)
+			}
+			joined = string(decrypted)
+		}
+	}
+	if file, err := sessionTree.File(paths.PromptsFileName); err == nil {
+		if content, err := file.Contents(); err == nil {
+			decrypted, err := decryptAndDecode([]byte(content), "", decrypt)
+			if err != nil {
+				return nil, "", fmt.Errorf("failed to read prompts: %w", err)
+			}
+			var prompts []string
+			if json.Unmarshal(decrypted, &prompts) == nil {
+				return prompts, joined, nil
+			}
+		}
+	}
+	return SplitPrompts(joined), joined, nil
+}
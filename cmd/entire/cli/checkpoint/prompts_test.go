@@ -0,0 +1,68 @@
+package checkpoint
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+func TestReadSessionContent_PromptsContainingSeparator(t *testing.T) {
+	t.Parallel()
+	_, store, cpID := setupRepoForUpdate(t)
+
+	prompts := []string{"write a markdown doc with a rule:\n\n---\n\nlike that", "thanks"}
+	if err := store.UpdateCommitted(context.Background(), UpdateCommittedOptions{
+		CheckpointID: cpID,
+		SessionID:    "session-001",
+		Prompts:      prompts,
+	}); err != nil {
+		t.Fatalf("UpdateCommitted() error = %v", err)
+	}
+
+	content, err := store.ReadSessionContent(context.Background(), cpID, 0)
+	if err != nil {
+		t.Fatalf("ReadSessionContent() error = %v", err)
+	}
+	if !reflect.DeepEqual(content.PromptList, prompts) {
+		t.Errorf("PromptList = %q, want %q", content.PromptList, prompts)
+	}
+	if content.Prompts != JoinPrompts(prompts) {
+		t.Errorf("Prompts = %q, want prompt.txt content", content.Prompts)
+	}
+}
+
+func TestReadPromptsFromTree_LegacyPromptFile(t *testing.T) {
+	t.Parallel()
+	repo, _, _ := setupRepoForUpdate(t)
+
+	blobHash, err := CreateBlobFromContent(repo, []byte("first\n\n---\n\nsecond"))
+	if err != nil {
+		t.Fatalf("CreateBlobFromContent() error = %v", err)
+	}
+	treeHash, err := BuildTreeFromEntries(repo, map[string]object.TreeEntry{
+		paths.PromptFileName: {Name: paths.PromptFileName, Mode: filemode.Regular, Hash: blobHash},
+	})
+	if err != nil {
+		t.Fatalf("BuildTreeFromEntries() error = %v", err)
+	}
+	tree, err := repo.TreeObject(treeHash)
+	if err != nil {
+		t.Fatalf("TreeObject() error = %v", err)
+	}
+
+	prompts, joined, err := readPromptsFromTree(tree, nil)
+	if err != nil {
+		t.Fatalf("readPromptsFromTree() error = %v", err)
+	}
+	if !reflect.DeepEqual(prompts, []string{"first", "second"}) {
+		t.Errorf("prompts = %q, want split prompt.txt", prompts)
+	}
+	if joined != "first\n\n---\n\nsecond" {
+		t.Errorf("joined = %q", joined)
+	}
+}
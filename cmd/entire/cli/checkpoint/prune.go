@@ -0,0 +1,134 @@
+package checkpoint
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// ErrCheckpointInUse is returned by DeleteCommitted when a session still
+// references the checkpoint.
+var ErrCheckpointInUse = errors.New("checkpoint is in use")
+
+// DeleteOptions controls DeleteCommitted.
+type DeleteOptions struct {
+	// ReferencedBy lists the sessions whose current turn references the
+	// checkpoint (session state TurnCheckpointIDs). Those sessions update the
+	// checkpoint when the turn ends, so it is only deleted with Force. Session
+	// state isn't kept in the store; the caller looks it up.
+	ReferencedBy []string
+
+	// Force deletes the checkpoint even if sessions reference it.
+	Force bool
+}
+
+// DeleteCommitted removes a checkpoint from the metadata branch in a new
+// commit. As with PruneCommitted, earlier commits still hold it.
+//
+// Returns ErrCheckpointNotFound if the checkpoint doesn't exist, and
+// ErrCheckpointInUse if opts.ReferencedBy isn't empty and opts.Force isn't set.
+func (s *GitStore) DeleteCommitted(ctx context.Context, checkpointID id.CheckpointID, opts DeleteOptions) error {
+	if len(opts.ReferencedBy) > 0 && !opts.Force {
+		return fmt.Errorf("%w: referenced by session(s) %s", ErrCheckpointInUse, strings.Join(opts.ReferencedBy, ", "))
+	}
+	removed, err := s.PruneCommitted(ctx, []id.CheckpointID{checkpointID}, fmt.Sprintf("Delete Checkpoint: %s", checkpointID))
+	if err != nil {
+		return err
+	}
+	if removed == 0 {
+		return ErrCheckpointNotFound
+	}
+	return nil
+}
+
+// PruneCommitted removes checkpoints from the metadata branch in a single
+// commit with the given message, and returns how many were removed. IDs
+// that aren't on the branch are skipped. Earlier commits still hold the
+// checkpoints until the branch history is rewritten or expires from
+// clones.
+func (s *GitStore) PruneCommitted(ctx context.Context, checkpointIDs []id.CheckpointID, message string) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err //nolint:wrapcheck // Propagating context cancellation
+	}
+	if len(checkpointIDs) == 0 {
+		return 0, nil
+	}
+
+	// Checkpoints live at <id[:2]>/<id[2:]>; group them by shard
+	byShard := make(map[string][]string)
+	for _, cpID := range checkpointIDs {
+		shard := string(cpID[:2])
+		byShard[shard] = append(byShard[shard], string(cpID[2:]))
+	}
+
+	var removed int
+	err := s.commitMetadata(ctx, func(_, rootTreeHash plumbing.Hash) (*metadataCommit, error) {
+		rootTree, err := s.repo.TreeObject(rootTreeHash)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read root tree: %w", err)
+		}
+
+		removed = 0
+		rootEntries := make([]object.TreeEntry, 0, len(rootTree.Entries))
+		for _, entry := range rootTree.Entries {
+			names, ok := byShard[entry.Name]
+			if !ok || entry.Mode.IsFile() {
+				rootEntries = append(rootEntries, entry)
+				continue
+			}
+			shardTree, err := s.repo.TreeObject(entry.Hash)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read shard %s: %w", entry.Name, err)
+			}
+			kept := make([]object.TreeEntry, 0, len(shardTree.Entries))
+			for _, cpEntry := range shardTree.Entries {
+				if slices.Contains(names, cpEntry.Name) {
+					removed++
+					continue
+				}
+				kept = append(kept, cpEntry)
+			}
+			if len(kept) == len(shardTree.Entries) {
+				rootEntries = append(rootEntries, entry)
+				continue
+			}
+			// Drop shards left empty; git doesn't track empty directories
+			if len(kept) == 0 {
+				continue
+			}
+			shardHash, err := storeTree(s.repo, kept)
+			if err != nil {
+				return nil, err
+			}
+			entry.Hash = shardHash
+			rootEntries = append(rootEntries, entry)
+		}
+		if removed == 0 {
+			return nil, nil //nolint:nilnil // nothing to commit
+		}
+
+		newTreeHash, err := storeTree(s.repo, rootEntries)
+		if err != nil {
+			return nil, err
+		}
+		authorName, authorEmail := GetGitAuthorFromRepo(s.repo)
+		return &metadataCommit{
+			Tree:          newTreeHash,
+			Message:       message,
+			AuthorName:    authorName,
+			AuthorEmail:   authorEmail,
+			CheckpointIDs: checkpointIDs,
+		}, nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return removed, nil
+}
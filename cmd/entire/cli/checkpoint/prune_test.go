@@ -0,0 +1,98 @@
+package checkpoint
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+)
+
+func TestPruneCommitted(t *testing.T) {
+	repo, _ := setupBranchTestRepo(t)
+	store := NewGitStore(repo)
+	ctx := context.Background()
+
+	// Two checkpoints share the "aa" shard
+	keep := id.MustCheckpointID("aa1111111111")
+	prunedSibling := id.MustCheckpointID("aa2222222222")
+	prunedAlone := id.MustCheckpointID("bb3333333333")
+	for _, cpID := range []id.CheckpointID{keep, prunedSibling, prunedAlone} {
+		writeIndexTestCheckpoint(t, store, cpID, "session-1")
+	}
+	// List once so the index is fresh and is moved along by the prune
+	if _, err := store.ListCommitted(ctx); err != nil {
+		t.Fatalf("ListCommitted() error = %v", err)
+	}
+
+	removed, err := store.PruneCommitted(ctx, []id.CheckpointID{prunedSibling, prunedAlone, id.MustCheckpointID("cc4444444444")}, "gc: prune")
+	if err != nil {
+		t.Fatalf("PruneCommitted() error = %v", err)
+	}
+	if removed != 2 {
+		t.Errorf("PruneCommitted() removed %d, want 2", removed)
+	}
+
+	list, err := store.ListCommitted(ctx)
+	if err != nil {
+		t.Fatalf("ListCommitted() error = %v", err)
+	}
+	var got []id.CheckpointID
+	for _, info := range list {
+		got = append(got, info.CheckpointID)
+	}
+	if !slices.Equal(got, []id.CheckpointID{keep}) {
+		t.Errorf("checkpoints after prune = %v, want [%s]", got, keep)
+	}
+	if status, err := store.IndexStatus(ctx); err != nil || !status.Fresh || status.Checkpoints != 1 {
+		t.Errorf("IndexStatus() = %+v, %v; want a fresh index of 1 checkpoint", status, err)
+	}
+
+	tree, err := store.getSessionsBranchTree()
+	if err != nil {
+		t.Fatalf("getSessionsBranchTree() error = %v", err)
+	}
+	if _, err := tree.Tree("bb"); err == nil {
+		t.Error("the emptied bb shard should be removed")
+	}
+	if _, err := store.ReadCommitted(ctx, keep); err != nil {
+		t.Errorf("ReadCommitted(%s) error = %v", keep, err)
+	}
+
+	// Nothing left to remove: no new commit
+	head, _, err := store.getSessionsBranchRef()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if removed, err := store.PruneCommitted(ctx, []id.CheckpointID{prunedAlone}, "gc: prune"); err != nil || removed != 0 {
+		t.Errorf("PruneCommitted() of a missing checkpoint = %d, %v; want 0, nil", removed, err)
+	}
+	if after, _, err := store.getSessionsBranchRef(); err != nil || after != head {
+		t.Error("PruneCommitted() without matches should not commit")
+	}
+}
+
+func TestDeleteCommitted(t *testing.T) {
+	t.Parallel()
+	_, store, cpID := setupRepoForUpdate(t)
+	ctx := context.Background()
+
+	err := store.DeleteCommitted(ctx, cpID, DeleteOptions{ReferencedBy: []string{"session-001"}})
+	if !errors.Is(err, ErrCheckpointInUse) {
+		t.Fatalf("DeleteCommitted() of a referenced checkpoint error = %v, want ErrCheckpointInUse", err)
+	}
+	if summary, err := store.ReadCommitted(ctx, cpID); err != nil || summary == nil {
+		t.Fatalf("checkpoint gone after a refused delete: %v, %v", summary, err)
+	}
+
+	if err := store.DeleteCommitted(ctx, cpID, DeleteOptions{ReferencedBy: []string{"session-001"}, Force: true}); err != nil {
+		t.Fatalf("DeleteCommitted() with Force error = %v", err)
+	}
+	if summary, err := store.ReadCommitted(ctx, cpID); err != nil || summary != nil {
+		t.Errorf("ReadCommitted() after delete = %v, %v, want nothing", summary, err)
+	}
+	if err := store.DeleteCommitted(ctx, cpID, DeleteOptions{}); !errors.Is(err, ErrCheckpointNotFound) {
+		t.Errorf("DeleteCommitted() twice error = %v, want ErrCheckpointNotFound", err)
+	}
+}
@@ -0,0 +1,419 @@
+package checkpoint
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+	"github.com/entireio/cli/cmd/entire/cli/jsonutil"
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// PurgeResult describes what PurgeSessionContent changed.
+type PurgeResult struct {
+	// CheckpointIDs lists the checkpoints the session's content was removed from.
+	CheckpointIDs []id.CheckpointID
+
+	// RewrittenCommits is the number of metadata branch commits that were rewritten.
+	RewrittenCommits int
+
+	// OldHead and NewHead are the metadata branch tip before and after the purge.
+	OldHead plumbing.Hash
+	NewHead plumbing.Hash
+}
+
+// PurgeSessionContent removes a session's content from every commit of the
+// metadata branch: each of its session directories keeps only metadata.json,
+// marked with purgedAt, and loses its transcript, prompts, context, summary,
+// and any other files. Commits that held the content are rewritten, so the
+// branch has to be force-pushed for the purge to reach remotes. The recall
+// index is removed.
+//
+// Returns a result with no checkpoints if the session has no stored content.
+func (s *GitStore) PurgeSessionContent(ctx context.Context, sessionID string, purgedAt time.Time) (*PurgeResult, error) {
+	if sessionID == "" {
+		return nil, errors.New("session ID is required")
+	}
+
+	refName := plumbing.NewBranchReferenceName(paths.MetadataBranchName)
+	ref, err := s.repo.Reference(refName, true)
+	if err != nil {
+		return &PurgeResult{}, nil //nolint:nilerr // No metadata branch means nothing to purge
+	}
+
+	p := s.newSessionPurger(ctx, sessionID, purgedAt)
+	newHead, err := p.rewriteCommit(ref.Hash())
+	if err != nil {
+		return nil, err
+	}
+
+	result := &PurgeResult{
+		RewrittenCommits: p.rewritten,
+		OldHead:          ref.Hash(),
+		NewHead:          newHead,
+	}
+	for cpID := range p.checkpoints {
+		result.CheckpointIDs = append(result.CheckpointIDs, cpID)
+	}
+	sort.Slice(result.CheckpointIDs, func(i, j int) bool {
+		return result.CheckpointIDs[i] < result.CheckpointIDs[j]
+	})
+	if newHead == ref.Hash() {
+		return result, nil
+	}
+
+	// Fail rather than drop checkpoints written while the history was rewritten
+	if err := s.repo.Storer.CheckAndSetReference(plumbing.NewHashReference(refName, newHead), ref); err != nil {
+		return nil, fmt.Errorf("failed to update %s: %w", paths.MetadataBranchName, err)
+	}
+	// The recall index keeps its own copy of the purged prompts and summaries
+	if path := s.RecallIndexPath(); path != "" {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("failed to remove recall index: %w", err)
+		}
+	}
+	return result, nil
+}
+
+// PurgeSessionRefs removes the session's content from the history of each
+// ref in refNames, as PurgeSessionContent does for the metadata branch, and
+// returns the refs that were rewritten. It is for refs that keep earlier
+// metadata branch history reachable, such as the archives sync keeps when
+// adopting a remote's prunes.
+func (s *GitStore) PurgeSessionRefs(ctx context.Context, sessionID string, purgedAt time.Time, refNames []plumbing.ReferenceName) ([]plumbing.ReferenceName, error) {
+	if sessionID == "" {
+		return nil, errors.New("session ID is required")
+	}
+
+	return s.newSessionPurger(ctx, sessionID, purgedAt).rewriteRefs(refNames)
+}
+
+// PurgeShadowRefs removes the session's metadata directory,
+// .entire/metadata/<session-id>, which holds its transcript and prompts, from
+// the history of each ref in refNames, and returns the refs that were
+// rewritten. It is for shadow branches and the refs that keep shadow commits
+// reachable, such as archived shadow branches and point refs. A commit
+// without a .entire/metadata directory is a user commit, so neither it nor
+// its history is rewritten.
+func (s *GitStore) PurgeShadowRefs(ctx context.Context, sessionID string, refNames []plumbing.ReferenceName) ([]plumbing.ReferenceName, error) {
+	if sessionID == "" {
+		return nil, errors.New("session ID is required")
+	}
+
+	p := s.newSessionPurger(ctx, sessionID, time.Time{})
+	p.shadow = true
+	return p.rewriteRefs(refNames)
+}
+
+func (s *GitStore) newSessionPurger(ctx context.Context, sessionID string, purgedAt time.Time) *sessionPurger {
+	return &sessionPurger{
+		ctx:         ctx,
+		store:       s,
+		sessionID:   sessionID,
+		purgedAt:    purgedAt.UTC(),
+		trees:       make(map[plumbing.Hash]purgedTree),
+		commits:     make(map[plumbing.Hash]plumbing.Hash),
+		checkpoints: make(map[id.CheckpointID]struct{}),
+	}
+}
+
+// purgedTree is the rewritten form of a tree.
+type purgedTree struct {
+	hash plumbing.Hash
+	// session is true if the tree was the purged session's directory.
+	session bool
+}
+
+// sessionPurger rewrites metadata branch or shadow branch history without a
+// session's content.
+// Trees and commits are memoized by hash, so content shared between commits is
+// only rewritten once.
+type sessionPurger struct {
+	ctx         context.Context
+	store       *GitStore
+	sessionID   string
+	purgedAt    time.Time
+	trees       map[plumbing.Hash]purgedTree
+	commits     map[plumbing.Hash]plumbing.Hash
+	checkpoints map[id.CheckpointID]struct{}
+	rewritten   int
+
+	// shadow is true when rewriting shadow branch commits, whose trees are
+	// snapshots of the worktree with session metadata under .entire/metadata
+	shadow bool
+}
+
+// rewriteRefs rewrites the history of each ref and moves the refs that
+// changed, returning them. A ref that moved meanwhile is an error.
+func (p *sessionPurger) rewriteRefs(refNames []plumbing.ReferenceName) ([]plumbing.ReferenceName, error) {
+	repo := p.store.repo
+	var rewritten []plumbing.ReferenceName
+	for _, refName := range refNames {
+		ref, err := repo.Reference(refName, true)
+		if err != nil {
+			return rewritten, fmt.Errorf("failed to read %s: %w", refName, err)
+		}
+		newHash, err := p.rewriteCommit(ref.Hash())
+		if err != nil {
+			return rewritten, err
+		}
+		if newHash == ref.Hash() {
+			continue
+		}
+		if err := repo.Storer.CheckAndSetReference(plumbing.NewHashReference(refName, newHash), ref); err != nil {
+			return rewritten, fmt.Errorf("failed to update %s: %w", refName, err)
+		}
+		rewritten = append(rewritten, refName)
+	}
+	return rewritten, nil
+}
+
+// rewriteCommit returns the hash of the commit with its history and tree purged.
+func (p *sessionPurger) rewriteCommit(hash plumbing.Hash) (plumbing.Hash, error) {
+	if newHash, ok := p.commits[hash]; ok {
+		return newHash, nil
+	}
+	if err := p.ctx.Err(); err != nil {
+		return plumbing.ZeroHash, err //nolint:wrapcheck // Propagating context cancellation
+	}
+
+	commit, err := p.store.repo.CommitObject(hash)
+	if err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("failed to read commit %s: %w", hash, err)
+	}
+	if p.shadow && !p.hasMetadataDir(commit.TreeHash) {
+		// A user commit: shadow branches start on top of one
+		p.commits[hash] = hash
+		return hash, nil
+	}
+
+	changed := false
+	parents := make([]plumbing.Hash, 0, len(commit.ParentHashes))
+	for _, parent := range commit.ParentHashes {
+		newParent, err := p.rewriteCommit(parent)
+		if err != nil {
+			return plumbing.ZeroHash, err
+		}
+		changed = changed || newParent != parent
+		parents = append(parents, newParent)
+	}
+	var tree purgedTree
+	if p.shadow {
+		tree, err = p.rewriteShadowTree(commit.TreeHash)
+	} else {
+		tree, err = p.rewriteTree(commit.TreeHash)
+	}
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+	changed = changed || tree.hash != commit.TreeHash
+
+	if !changed {
+		p.commits[hash] = hash
+		return hash, nil
+	}
+
+	// Keep authorship and message; a PGP signature would no longer match
+	rewritten := &object.Commit{
+		Author:       commit.Author,
+		Committer:    commit.Committer,
+		Message:      commit.Message,
+		TreeHash:     tree.hash,
+		ParentHashes: parents,
+	}
+	obj := p.store.repo.Storer.NewEncodedObject()
+	if err := rewritten.Encode(obj); err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("failed to encode commit: %w", err)
+	}
+	newHash, err := p.store.repo.Storer.SetEncodedObject(obj)
+	if err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("failed to store commit: %w", err)
+	}
+	p.commits[hash] = newHash
+	p.rewritten++
+	return newHash, nil
+}
+
+// rewriteTree returns the tree with the session's directories purged. A
+// checkpoint directory whose session was purged also drops the file paths
+// of that session from its summary.
+func (p *sessionPurger) rewriteTree(hash plumbing.Hash) (purgedTree, error) {
+	if result, ok := p.trees[hash]; ok {
+		return result, nil
+	}
+	tree, err := p.store.repo.TreeObject(hash)
+	if err != nil {
+		return purgedTree{}, fmt.Errorf("failed to read tree %s: %w", hash, err)
+	}
+
+	var metadataEntry *object.TreeEntry
+	for i := range tree.Entries {
+		if tree.Entries[i].Name == paths.MetadataFileName && tree.Entries[i].Mode == filemode.Regular {
+			metadataEntry = &tree.Entries[i]
+		}
+	}
+	if metadataEntry != nil {
+		// Summaries and other metadata files fail to decode or carry no session ID
+		meta, err := p.store.readMetadataFromBlob(metadataEntry.Hash)
+		if err == nil && meta.SessionID == p.sessionID && meta.PurgedAt != nil && len(tree.Entries) == 1 {
+			// Purged before and nothing added since
+			p.trees[hash] = purgedTree{hash: hash}
+			return p.trees[hash], nil
+		}
+		if err == nil && meta.SessionID == p.sessionID {
+			result, err := p.purgeSessionTree(meta)
+			if err != nil {
+				return purgedTree{}, err
+			}
+			p.trees[hash] = result
+			return result, nil
+		}
+	}
+
+	entries := make([]object.TreeEntry, len(tree.Entries))
+	copy(entries, tree.Entries)
+	changed := false
+	var purgedSessions []int
+	for i, entry := range entries {
+		if entry.Mode != filemode.Dir {
+			continue
+		}
+		child, err := p.rewriteTree(entry.Hash)
+		if err != nil {
+			return purgedTree{}, err
+		}
+		if child.hash == entry.Hash {
+			continue
+		}
+		entries[i].Hash = child.hash
+		changed = true
+		if index, convErr := strconv.Atoi(entry.Name); convErr == nil && child.session {
+			purgedSessions = append(purgedSessions, index)
+		}
+	}
+	if !changed {
+		p.trees[hash] = purgedTree{hash: hash}
+		return p.trees[hash], nil
+	}
+
+	if metadataEntry != nil && len(purgedSessions) > 0 {
+		summaryHash, err := p.purgeSummaryPaths(metadataEntry.Hash, purgedSessions)
+		if err != nil {
+			return purgedTree{}, err
+		}
+		for i := range entries {
+			if entries[i].Name == paths.MetadataFileName {
+				entries[i].Hash = summaryHash
+			}
+		}
+	}
+
+	newHash, err := storeTree(p.store.repo, entries)
+	if err != nil {
+		return purgedTree{}, err
+	}
+	p.trees[hash] = purgedTree{hash: newHash}
+	return p.trees[hash], nil
+}
+
+// hasMetadataDir reports whether the tree has a .entire/metadata directory.
+func (p *sessionPurger) hasMetadataDir(treeHash plumbing.Hash) bool {
+	tree, err := p.store.repo.TreeObject(treeHash)
+	if err != nil {
+		return false
+	}
+	_, err = tree.Tree(paths.EntireMetadataDir)
+	return err == nil
+}
+
+// rewriteShadowTree returns the shadow commit tree without the session's
+// metadata directory, dropping .entire/metadata too if nothing else is in it.
+func (p *sessionPurger) rewriteShadowTree(hash plumbing.Hash) (purgedTree, error) {
+	if result, ok := p.trees[hash]; ok {
+		return result, nil
+	}
+	tree, err := p.store.repo.TreeObject(hash)
+	if err != nil {
+		return purgedTree{}, fmt.Errorf("failed to read tree %s: %w", hash, err)
+	}
+	metadataTree, err := tree.Tree(paths.EntireMetadataDir)
+	if err != nil {
+		p.trees[hash] = purgedTree{hash: hash}
+		return p.trees[hash], nil
+	}
+	if _, err := metadataTree.FindEntry(p.sessionID); err != nil {
+		p.trees[hash] = purgedTree{hash: hash}
+		return p.trees[hash], nil
+	}
+
+	removePath := paths.SessionMetadataDirFromSessionID(p.sessionID)
+	if len(metadataTree.Entries) == 1 {
+		removePath = paths.EntireMetadataDir
+	}
+	newHash, err := ApplyTreeChanges(p.store.repo, hash, []TreeChange{{Path: removePath}})
+	if err != nil {
+		return purgedTree{}, err
+	}
+	p.trees[hash] = purgedTree{hash: newHash, session: true}
+	return p.trees[hash], nil
+}
+
+// purgeSessionTree builds a session directory holding only the session's
+// metadata, marked as purged.
+func (p *sessionPurger) purgeSessionTree(meta *CommittedMetadata) (purgedTree, error) {
+	p.checkpoints[meta.CheckpointID] = struct{}{}
+
+	purged := *meta
+	purged.Summary = nil
+	purged.Attachments = nil
+	purged.Artifacts = nil
+	purged.TranscriptEncoding = ""
+	purged.TranscriptRecipients = ""
+	purged.ContextEncoding = ""
+	purged.ContentLevel = ContentMetadata
+	purged.PurgedAt = &p.purgedAt
+	// Fields this version doesn't know are dropped: they may hold content
+	metadataJSON, err := jsonutil.MarshalIndentWithNewline(purged, "", "  ")
+	if err != nil {
+		return purgedTree{}, fmt.Errorf("failed to marshal session metadata: %w", err)
+	}
+	blobHash, err := CreateBlobFromContent(p.store.repo, metadataJSON)
+	if err != nil {
+		return purgedTree{}, err
+	}
+	treeHash, err := storeTree(p.store.repo, []object.TreeEntry{
+		{Name: paths.MetadataFileName, Mode: filemode.Regular, Hash: blobHash},
+	})
+	if err != nil {
+		return purgedTree{}, err
+	}
+	return purgedTree{hash: treeHash, session: true}, nil
+}
+
+// purgeSummaryPaths clears the content file paths of the purged sessions in a
+// checkpoint summary and returns the new summary blob.
+func (p *sessionPurger) purgeSummaryPaths(hash plumbing.Hash, sessions []int) (plumbing.Hash, error) {
+	summary, err := p.store.readSummaryFromBlob(hash)
+	if err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("failed to read checkpoint summary: %w", err)
+	}
+	for _, index := range sessions {
+		if index < len(summary.Sessions) {
+			summary.Sessions[index] = SessionFilePaths{Metadata: summary.Sessions[index].Metadata}
+		}
+	}
+	summaryJSON, err := p.store.marshalUpdatedMetadata(summary, hash)
+	if err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("failed to marshal checkpoint summary: %w", err)
+	}
+	return CreateBlobFromContent(p.store.repo, summaryJSON)
+}
@@ -0,0 +1,135 @@
+package checkpoint
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+func TestPurgeSessionContent(t *testing.T) {
+	t.Parallel()
+	repo, store, cpID := setupRepoForUpdate(t)
+	ctx := context.Background()
+
+	// A second session in the same checkpoint, and a later update of the first
+	if err := store.WriteCommitted(ctx, WriteCommittedOptions{
+		CheckpointID: cpID,
+		SessionID:    "session-002",
+		Strategy:     "manual-commit",
+		Transcript:   []byte("other session transcript\n"),
+		Prompts:      []string{"other prompt"},
+		AuthorName:   "Test",
+		AuthorEmail:  "test@test.com",
+	}); err != nil {
+		t.Fatalf("WriteCommitted() error = %v", err)
+	}
+	if err := store.UpdateCommitted(ctx, UpdateCommittedOptions{
+		CheckpointID: cpID,
+		SessionID:    "session-001",
+		Transcript:   []byte("full transcript line 1\nfull transcript line 2\n"),
+		Prompts:      []string{"initial prompt", "second prompt"},
+	}); err != nil {
+		t.Fatalf("UpdateCommitted() error = %v", err)
+	}
+
+	// The recall index caches prompts, so it goes with the purge
+	if _, err := Recall(ctx, store, RecallOptions{Query: "second prompt", IndexPath: store.RecallIndexPath()}); err != nil {
+		t.Fatalf("Recall() error = %v", err)
+	}
+	if _, err := os.Stat(store.RecallIndexPath()); err != nil {
+		t.Fatalf("recall index wasn't written: %v", err)
+	}
+
+	purgedAt := time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC)
+	result, err := store.PurgeSessionContent(ctx, "session-001", purgedAt)
+	if err != nil {
+		t.Fatalf("PurgeSessionContent() error = %v", err)
+	}
+	if _, err := os.Stat(store.RecallIndexPath()); !os.IsNotExist(err) {
+		t.Errorf("recall index still exists after the purge: %v", err)
+	}
+	if len(result.CheckpointIDs) != 1 || result.CheckpointIDs[0] != cpID {
+		t.Errorf("CheckpointIDs = %v, want [%s]", result.CheckpointIDs, cpID)
+	}
+	if result.RewrittenCommits != 3 {
+		t.Errorf("RewrittenCommits = %d, want 3", result.RewrittenCommits)
+	}
+
+	purged, err := store.ReadSessionContentByID(ctx, cpID, "session-001")
+	if err != nil {
+		t.Fatalf("ReadSessionContentByID() error = %v", err)
+	}
+	if len(purged.Transcript) != 0 || purged.Prompts != "" || purged.Context != "" {
+		t.Errorf("purged session still has content: %+v", purged)
+	}
+	if purged.Metadata.PurgedAt == nil || !purged.Metadata.PurgedAt.Equal(purgedAt) {
+		t.Errorf("PurgedAt = %v, want %v", purged.Metadata.PurgedAt, purgedAt)
+	}
+
+	other, err := store.ReadSessionContentByID(ctx, cpID, "session-002")
+	if err != nil {
+		t.Fatalf("ReadSessionContentByID() error = %v", err)
+	}
+	if string(other.Transcript) != "other session transcript\n" {
+		t.Errorf("other session transcript = %q, want it untouched", other.Transcript)
+	}
+
+	// No commit in the rewritten history holds the purged transcript
+	iter, err := repo.Log(&git.LogOptions{From: result.NewHead})
+	if err != nil {
+		t.Fatalf("Log() error = %v", err)
+	}
+	transcriptPath := cpID.Path() + "/0/" + paths.TranscriptFileName
+	if err := iter.ForEach(func(c *object.Commit) error {
+		tree, err := c.Tree()
+		if err != nil {
+			return err
+		}
+		if _, err := tree.File(transcriptPath); err == nil {
+			t.Errorf("commit %s still holds %s", c.Hash, transcriptPath)
+		}
+		return nil
+	}); err != nil {
+		t.Fatalf("walking history: %v", err)
+	}
+
+	ref, err := repo.Reference(plumbing.NewBranchReferenceName(paths.MetadataBranchName), true)
+	if err != nil {
+		t.Fatalf("Reference() error = %v", err)
+	}
+	if ref.Hash() != result.NewHead {
+		t.Errorf("branch head = %s, want %s", ref.Hash(), result.NewHead)
+	}
+
+	// Purging again finds nothing
+	again, err := store.PurgeSessionContent(ctx, "session-001", purgedAt.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("PurgeSessionContent() error = %v", err)
+	}
+	if len(again.CheckpointIDs) != 0 || again.NewHead != result.NewHead {
+		t.Errorf("second purge = %+v, want no changes", again)
+	}
+}
+
+func TestPurgeSessionContent_UnknownSession(t *testing.T) {
+	t.Parallel()
+	_, store, _ := setupRepoForUpdate(t)
+
+	result, err := store.PurgeSessionContent(context.Background(), "no-such-session", time.Now())
+	if err != nil {
+		t.Fatalf("PurgeSessionContent() error = %v", err)
+	}
+	if len(result.CheckpointIDs) != 0 || result.RewrittenCommits != 0 {
+		t.Errorf("result = %+v, want nothing purged", result)
+	}
+	if result.OldHead != result.NewHead {
+		t.Error("branch head should not move")
+	}
+}
@@ -0,0 +1,374 @@
+package checkpoint
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+	"github.com/entireio/cli/cmd/entire/cli/stringutil"
+	"github.com/entireio/cli/cmd/entire/cli/textutil"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+)
+
+// RecallIndexFileName is the recall index's name in the git directory.
+const RecallIndexFileName = "entire-recall-index.json"
+
+// recallIndexVersion is bumped when the recall index's format or its
+// tokenization changes, so an older index is rebuilt instead of misread.
+const recallIndexVersion = 2
+
+// recallPromptLength caps the runes of each prompt the recall index keeps.
+const recallPromptLength = 1000
+
+// BM25 parameters: term frequency saturation and length normalization.
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// recallStopWords are common English words left out of recall documents
+// and queries; they match nearly everything and rank nothing.
+var recallStopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true,
+	"be": true, "but": true, "by": true, "can": true, "do": true, "for": true,
+	"from": true, "has": true, "have": true, "how": true, "i": true, "if": true,
+	"in": true, "into": true, "is": true, "it": true, "its": true, "me": true,
+	"my": true, "not": true, "of": true, "on": true, "or": true, "please": true,
+	"so": true, "that": true, "the": true, "this": true, "to": true, "was": true,
+	"we": true, "what": true, "when": true, "which": true, "will": true,
+	"with": true, "you": true, "your": true,
+}
+
+// RecallOptions selects what Recall looks for.
+type RecallOptions struct {
+	// Query is free text, typically the prompt the agent was just given
+	Query string
+
+	// Limit is the maximum number of checkpoints returned. Zero returns all
+	// that match.
+	Limit int
+
+	// IndexPath is the file the recall index is cached in, normally
+	// RecallIndexFileName in the git directory. Empty reads every
+	// checkpoint on each call, as do stores that can't tell when a
+	// checkpoint changed, such as ObjectStore.
+	IndexPath string
+}
+
+// RecallHit is a checkpoint relevant to the query.
+type RecallHit struct {
+	Info CommittedInfo
+
+	// Score is the checkpoint's BM25 score for the query; higher is more relevant
+	Score float64
+
+	// Prompts are the checkpoint's prompts, in session order, without IDE
+	// context tags and cut to 1000 characters each
+	Prompts []string
+
+	// Summary is the AI summary of the latest summarized session, or nil
+	Summary *Summary
+}
+
+// RecallResult is the outcome of Recall.
+type RecallResult struct {
+	// Hits are the matching checkpoints, most relevant first
+	Hits []RecallHit
+
+	// Skipped is the number of sessions that couldn't be read, such as
+	// encrypted sessions without a matching identity
+	Skipped int
+}
+
+// recallDocument is the indexed text of one checkpoint: its prompts, its
+// summary, and the files it touched. Tree is the hash of the checkpoint's
+// tree on the metadata branch when it was indexed; any write to the
+// checkpoint, including a purge or a new summary, changes it.
+type recallDocument struct {
+	Tree    string         `json:"tree"`
+	Terms   map[string]int `json:"terms"`
+	Length  int            `json:"length"`
+	Prompts []string       `json:"prompts,omitempty"`
+	Summary *Summary       `json:"summary,omitempty"`
+}
+
+// recallIndex caches recall documents by checkpoint. Unlike the checkpoint
+// index it isn't tied to the metadata branch's tip: checkpoints whose tree
+// is new or changed are indexed on the next Recall, and the rest are
+// reused. It holds decrypted prompts and summaries, so RebuildIndex and
+// PurgeSessionContent remove it; RebuildIndex also makes sessions skipped
+// before (say, for a missing decryption key) be read again.
+type recallIndex struct {
+	Version   int                                 `json:"version"`
+	Documents map[id.CheckpointID]*recallDocument `json:"documents"`
+}
+
+// Recall ranks the committed checkpoints in store by relevance to
+// opts.Query with BM25 over their prompts, summaries, and touched files.
+// Checkpoints without a query term aren't returned.
+func Recall(ctx context.Context, store Store, opts RecallOptions) (*RecallResult, error) {
+	queryTerms := uniqueTerms(recallTerms(opts.Query))
+	if len(queryTerms) == 0 {
+		return nil, errors.New("recall query has no searchable words")
+	}
+
+	infos, err := store.ListCommitted(ctx)
+	if err != nil {
+		return nil, err //nolint:wrapcheck // Callers wrap
+	}
+
+	// Without checkpoint trees there is no telling which cached documents are stale
+	indexPath := opts.IndexPath
+	var trees map[id.CheckpointID]plumbing.Hash
+	if lister, ok := store.(checkpointTreeLister); ok && indexPath != "" {
+		if trees, err = lister.checkpointTrees(); err != nil {
+			indexPath = ""
+		}
+	} else {
+		indexPath = ""
+	}
+
+	idx := readRecallIndex(indexPath)
+	documents := make(map[id.CheckpointID]*recallDocument, len(infos))
+	result := &RecallResult{Hits: []RecallHit{}}
+	changed := len(idx.Documents) != len(infos)
+	for _, info := range infos {
+		if err := ctx.Err(); err != nil {
+			return nil, err //nolint:wrapcheck // Propagating context cancellation
+		}
+		tree := trees[info.CheckpointID].String()
+		doc := idx.Documents[info.CheckpointID]
+		if doc == nil || doc.Tree != tree {
+			var skipped int
+			doc, skipped = readRecallDocument(ctx, store, info)
+			doc.Tree = tree
+			result.Skipped += skipped
+			changed = true
+		}
+		documents[info.CheckpointID] = doc
+	}
+	if changed && indexPath != "" {
+		// The index is only a cache; a failed write is rebuilt next time
+		_ = writeRecallIndex(indexPath, &recallIndex{Version: recallIndexVersion, Documents: documents}) //nolint:errcheck // the index is only a cache
+	}
+
+	scores := bm25Scores(queryTerms, documents)
+	for _, info := range infos {
+		score := scores[info.CheckpointID]
+		if score <= 0 {
+			continue
+		}
+		doc := documents[info.CheckpointID]
+		result.Hits = append(result.Hits, RecallHit{Info: info, Score: score, Prompts: doc.Prompts, Summary: doc.Summary})
+	}
+	// infos are most recent first, so a stable sort breaks ties by recency
+	sort.SliceStable(result.Hits, func(i, j int) bool {
+		return result.Hits[i].Score > result.Hits[j].Score
+	})
+	if opts.Limit > 0 && len(result.Hits) > opts.Limit {
+		result.Hits = result.Hits[:opts.Limit]
+	}
+	return result, nil
+}
+
+// readRecallDocument indexes one checkpoint, returning the number of
+// sessions that couldn't be read.
+func readRecallDocument(ctx context.Context, store Store, info CommittedInfo) (*recallDocument, int) {
+	doc := &recallDocument{Terms: make(map[string]int)}
+	addText := func(text string) {
+		for _, term := range recallTerms(text) {
+			doc.Terms[term]++
+			doc.Length++
+		}
+	}
+
+	skipped := 0
+	for i := range info.SessionCount {
+		content, err := store.ReadSessionContent(ctx, info.CheckpointID, i)
+		if err != nil {
+			skipped++
+			continue
+		}
+		for _, prompt := range content.PromptList {
+			prompt = textutil.StripIDEContextTags(prompt)
+			if prompt == "" {
+				continue
+			}
+			addText(prompt)
+			doc.Prompts = append(doc.Prompts, stringutil.TruncateRunes(prompt, recallPromptLength, "…"))
+		}
+		if summary := content.Metadata.Summary; summary != nil {
+			addText(summaryText(summary))
+			doc.Summary = summary
+		}
+	}
+	for _, file := range info.FilesTouched {
+		addText(file)
+	}
+	return doc, skipped
+}
+
+// summaryText joins the text fields of a summary for indexing.
+func summaryText(summary *Summary) string {
+	parts := []string{summary.Intent, summary.Outcome}
+	parts = append(parts, summary.Learnings.Repo...)
+	parts = append(parts, summary.Learnings.Workflow...)
+	for _, learning := range summary.Learnings.Code {
+		parts = append(parts, learning.Path, learning.Finding)
+	}
+	parts = append(parts, summary.Friction...)
+	parts = append(parts, summary.OpenItems...)
+	return strings.Join(parts, "\n")
+}
+
+// bm25Scores scores every document against the query terms.
+func bm25Scores(queryTerms []string, documents map[id.CheckpointID]*recallDocument) map[id.CheckpointID]float64 {
+	scores := make(map[id.CheckpointID]float64)
+	if len(documents) == 0 {
+		return scores
+	}
+	var totalLength int
+	docFreq := make(map[string]int, len(queryTerms))
+	for _, doc := range documents {
+		totalLength += doc.Length
+		for _, term := range queryTerms {
+			if doc.Terms[term] > 0 {
+				docFreq[term]++
+			}
+		}
+	}
+	n := float64(len(documents))
+	avgLength := math.Max(float64(totalLength)/n, 1)
+
+	for checkpointID, doc := range documents {
+		var score float64
+		for _, term := range queryTerms {
+			tf := float64(doc.Terms[term])
+			if tf == 0 {
+				continue
+			}
+			df := float64(docFreq[term])
+			idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+			score += idf * tf * (bm25K1 + 1) / (tf + bm25K1*(1-bm25B+bm25B*float64(doc.Length)/avgLength))
+		}
+		if score > 0 {
+			scores[checkpointID] = score
+		}
+	}
+	return scores
+}
+
+// recallTerms splits text into lowercase words of letters and digits,
+// leaving out stop words and single characters.
+func recallTerms(text string) []string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	terms := words[:0]
+	for _, word := range words {
+		if len([]rune(word)) > 1 && !recallStopWords[word] {
+			terms = append(terms, word)
+		}
+	}
+	return terms
+}
+
+// uniqueTerms returns terms without repeats, in first-seen order.
+func uniqueTerms(terms []string) []string {
+	seen := make(map[string]bool, len(terms))
+	unique := terms[:0]
+	for _, term := range terms {
+		if !seen[term] {
+			seen[term] = true
+			unique = append(unique, term)
+		}
+	}
+	return unique
+}
+
+// readRecallIndex returns the cached recall index, or an empty one if
+// there is none or it can't be used.
+func readRecallIndex(path string) *recallIndex {
+	empty := &recallIndex{Version: recallIndexVersion, Documents: map[id.CheckpointID]*recallDocument{}}
+	if path == "" {
+		return empty
+	}
+	data, err := os.ReadFile(path) //nolint:gosec // path is in the git directory
+	if err != nil {
+		return empty
+	}
+	var idx recallIndex
+	if err := json.Unmarshal(data, &idx); err != nil || idx.Version != recallIndexVersion || idx.Documents == nil {
+		return empty
+	}
+	return &idx
+}
+
+// writeRecallIndex replaces the recall index file atomically.
+func writeRecallIndex(path string, idx *recallIndex) error {
+	data, err := json.Marshal(idx)
+	if err != nil {
+		return fmt.Errorf("failed to encode recall index: %w", err)
+	}
+	tmpFile := path + ".tmp"
+	if err := os.WriteFile(tmpFile, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write recall index: %w", err)
+	}
+	if err := os.Rename(tmpFile, path); err != nil {
+		return fmt.Errorf("failed to write recall index: %w", err)
+	}
+	return nil
+}
+
+// checkpointTreeLister is implemented by stores that can tell cheaply
+// whether a checkpoint changed since the recall index was written.
+type checkpointTreeLister interface {
+	checkpointTrees() (map[id.CheckpointID]plumbing.Hash, error)
+}
+
+// checkpointTrees returns the hash of each checkpoint's tree on the
+// metadata branch. Only the shard trees are read.
+func (s *GitStore) checkpointTrees() (map[id.CheckpointID]plumbing.Hash, error) {
+	trees := make(map[id.CheckpointID]plumbing.Hash)
+	tree, err := s.getSessionsBranchTree()
+	if err != nil {
+		return trees, nil //nolint:nilerr // No sessions branch means no checkpoints
+	}
+	for _, bucketEntry := range tree.Entries {
+		if bucketEntry.Mode != filemode.Dir || len(bucketEntry.Name) != 2 {
+			continue
+		}
+		bucketTree, err := s.repo.TreeObject(bucketEntry.Hash)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read checkpoint tree: %w", err)
+		}
+		for _, checkpointEntry := range bucketTree.Entries {
+			checkpointID, err := id.NewCheckpointID(bucketEntry.Name + checkpointEntry.Name)
+			if err != nil || checkpointEntry.Mode != filemode.Dir {
+				continue
+			}
+			trees[checkpointID] = checkpointEntry.Hash
+		}
+	}
+	return trees, nil
+}
+
+// RecallIndexPath returns the file Recall caches its index in for this
+// store's repository, or "" if the repository isn't stored on disk.
+func (s *GitStore) RecallIndexPath() string {
+	path := s.indexPath()
+	if path == "" {
+		return ""
+	}
+	return filepath.Join(filepath.Dir(path), RecallIndexFileName)
+}
@@ -0,0 +1,123 @@
+package checkpoint
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+)
+
+func TestRecall_RanksByRelevance(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	store, err := NewMemoryStore()
+	if err != nil {
+		t.Fatalf("NewMemoryStore() error = %v", err)
+	}
+
+	login := id.MustCheckpointID("a1a2a3a4a5a6")
+	cache := id.MustCheckpointID("b1b2b3b4b5b6")
+	docs := id.MustCheckpointID("c1c2c3c4c5c6")
+	for _, cp := range []struct {
+		id      id.CheckpointID
+		prompts []string
+		summary *Summary
+		files   []string
+	}{
+		{login, []string{"Fix the login redirect after the session expires"}, &Summary{
+			Intent:    "Keep the return URL on login redirects",
+			Learnings: LearningsSummary{Repo: []string{"Sessions are validated in middleware"}},
+		}, []string{"auth/login.go"}},
+		{cache, []string{"Make the cache invalidation thread-safe"}, nil, []string{"cache/cache.go"}},
+		{docs, []string{"<ide_opened_file>login.go</ide_opened_file>Update the README"}, nil, []string{"README.md"}},
+	} {
+		opts := testWriteOptions(cp.id, "session-"+cp.id.String())
+		opts.Prompts = cp.prompts
+		opts.Summary = cp.summary
+		opts.FilesTouched = cp.files
+		if err := store.WriteCommitted(ctx, opts); err != nil {
+			t.Fatalf("WriteCommitted() error = %v", err)
+		}
+	}
+
+	indexPath := filepath.Join(t.TempDir(), RecallIndexFileName)
+	result, err := recall(ctx, store, "Why does the login redirect drop the return URL?", indexPath)
+	if err != nil {
+		t.Fatalf("Recall() error = %v", err)
+	}
+	if len(result.Hits) != 1 || result.Hits[0].Info.CheckpointID != login {
+		t.Fatalf("Recall() hits = %+v, want only %s (IDE tags aren't indexed)", result.Hits, login)
+	}
+	hit := result.Hits[0]
+	if hit.Summary == nil || hit.Summary.Intent != "Keep the return URL on login redirects" || len(hit.Prompts) != 1 {
+		t.Errorf("hit = %+v, want the prompt and summary", hit)
+	}
+
+	// Terms from summaries and touched files match too
+	if result, err = recall(ctx, store, "middleware", indexPath); err != nil || len(result.Hits) != 1 || result.Hits[0].Info.CheckpointID != login {
+		t.Errorf("Recall(middleware) = %+v, %v, want %s", result, err, login)
+	}
+	if result, err = recall(ctx, store, "cache.go thread safety", indexPath); err != nil || len(result.Hits) == 0 || result.Hits[0].Info.CheckpointID != cache {
+		t.Errorf("Recall(cache.go) = %+v, %v, want %s first", result, err, cache)
+	}
+
+	// Indexed checkpoints are read from the cache, not the store
+	data, err := os.ReadFile(indexPath)
+	if err != nil {
+		t.Fatalf("recall index wasn't written: %v", err)
+	}
+	tampered := strings.Replace(string(data), "Make the cache invalidation thread-safe", "Make the cache invalidation lock-free", 1)
+	if err := os.WriteFile(indexPath, []byte(tampered), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if result, err = recall(ctx, store, "thread", indexPath); err != nil || len(result.Hits) != 1 || result.Hits[0].Prompts[0] != "Make the cache invalidation lock-free" {
+		t.Errorf("Recall(thread) = %+v, %v, want the cached prompt", result, err)
+	}
+
+	// A changed checkpoint is indexed again, though its sessions and creation time are the same
+	if err := store.UpdateSummary(ctx, cache, &Summary{Intent: "Avoid data races in the cache"}); err != nil {
+		t.Fatalf("UpdateSummary() error = %v", err)
+	}
+	if result, err = recall(ctx, store, "thread", indexPath); err != nil || len(result.Hits) != 1 || result.Hits[0].Prompts[0] != "Make the cache invalidation thread-safe" {
+		t.Errorf("Recall(thread) = %+v, %v, want the stored prompt", result, err)
+	}
+	if result, err = recall(ctx, store, "races", indexPath); err != nil || len(result.Hits) != 1 || result.Hits[0].Info.CheckpointID != cache {
+		t.Errorf("Recall(races) = %+v, %v, want %s", result, err, cache)
+	}
+
+	if _, err := recall(ctx, store, "the a of", indexPath); err == nil {
+		t.Error("Recall() with only stop words succeeded, want an error")
+	}
+}
+
+func TestBM25Scores(t *testing.T) {
+	t.Parallel()
+	short := id.MustCheckpointID("a1a2a3a4a5a6")
+	long := id.MustCheckpointID("b1b2b3b4b5b6")
+	common := id.MustCheckpointID("c1c2c3c4c5c6")
+	documents := map[id.CheckpointID]*recallDocument{
+		short:  {Terms: map[string]int{"redirect": 1, "login": 1}, Length: 2},
+		long:   {Terms: map[string]int{"redirect": 1, "login": 1, "other": 20}, Length: 22},
+		common: {Terms: map[string]int{"login": 3}, Length: 3},
+	}
+
+	scores := bm25Scores([]string{"redirect", "login"}, documents)
+	if scores[short] <= scores[long] {
+		t.Errorf("shorter document should score higher: %v", scores)
+	}
+	// redirect is rarer than login, so it outweighs three logins
+	if scores[long] <= scores[common] {
+		t.Errorf("rare term should outweigh a common one: %v", scores)
+	}
+	if _, ok := bm25Scores([]string{"absent"}, documents)[short]; ok {
+		t.Error("documents without query terms should have no score")
+	}
+}
+
+// recall runs Recall on store with the index cached at indexPath.
+func recall(ctx context.Context, store Store, query, indexPath string) (*RecallResult, error) {
+	return Recall(ctx, store, RecallOptions{Query: query, IndexPath: indexPath})
+}
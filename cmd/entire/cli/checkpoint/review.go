@@ -0,0 +1,87 @@
+package checkpoint
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// ReviewState is the review state of a checkpoint.
+type ReviewState string
+
+const (
+	// ReviewPending means the checkpoint lacks the approvals it needs and
+	// nobody has requested changes.
+	ReviewPending ReviewState = "pending"
+	// ReviewApproved means enough reviewers approved and none requested changes.
+	ReviewApproved ReviewState = "approved"
+	// ReviewChangesRequested means at least one reviewer's latest verdict
+	// asks for changes.
+	ReviewChangesRequested ReviewState = "changes_requested"
+)
+
+// Reviewer identifies who gave a verdict and when.
+type Reviewer struct {
+	Name      string    `json:"name,omitempty"`
+	Email     string    `json:"email,omitempty"`
+	CommentID string    `json:"comment_id"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// ReviewStatus is a checkpoint's review state derived from its comments.
+type ReviewStatus struct {
+	State             ReviewState `json:"state"`
+	RequiredApprovals int         `json:"required_approvals"`
+	// Approvers and ChangesRequestedBy list each reviewer whose latest
+	// verdict is approval or a change request, oldest first.
+	Approvers          []Reviewer `json:"approvers,omitempty"`
+	ChangesRequestedBy []Reviewer `json:"changes_requested_by,omitempty"`
+}
+
+// ComputeReviewStatus derives the review state from a checkpoint's
+// comments. Only each reviewer's latest verdict counts, so a reviewer who
+// requested changes can approve later. Reviewers are identified by email,
+// or by name when no email was recorded. requiredApprovals below one is
+// treated as one.
+func ComputeReviewStatus(comments []Comment, requiredApprovals int) ReviewStatus {
+	required := max(requiredApprovals, 1)
+
+	latest := make(map[string]Comment)
+	for _, c := range comments {
+		if c.Verdict == "" {
+			continue
+		}
+		key := strings.ToLower(c.AuthorEmail)
+		if key == "" {
+			key = c.AuthorName
+		}
+		if prev, ok := latest[key]; !ok || !c.Timestamp.Before(prev.Timestamp) {
+			latest[key] = c
+		}
+	}
+
+	status := ReviewStatus{State: ReviewPending, RequiredApprovals: required}
+	for _, c := range latest {
+		reviewer := Reviewer{Name: c.AuthorName, Email: c.AuthorEmail, CommentID: c.ID, Timestamp: c.Timestamp}
+		switch c.Verdict {
+		case ReviewApproved:
+			status.Approvers = append(status.Approvers, reviewer)
+		case ReviewChangesRequested:
+			status.ChangesRequestedBy = append(status.ChangesRequestedBy, reviewer)
+		case ReviewPending:
+		}
+	}
+	byTime := func(rs []Reviewer) {
+		sort.Slice(rs, func(i, j int) bool { return rs[i].Timestamp.Before(rs[j].Timestamp) })
+	}
+	byTime(status.Approvers)
+	byTime(status.ChangesRequestedBy)
+
+	switch {
+	case len(status.ChangesRequestedBy) > 0:
+		status.State = ReviewChangesRequested
+	case len(status.Approvers) >= required:
+		status.State = ReviewApproved
+	}
+	return status
+}
@@ -0,0 +1,50 @@
+package checkpoint
+
+import (
+	"testing"
+	"time"
+)
+
+func TestComputeReviewStatus(t *testing.T) {
+	t.Parallel()
+	base := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	verdict := func(id, email string, v ReviewState, minutes int) Comment {
+		return Comment{ID: id, AuthorName: email, AuthorEmail: email, Verdict: v, Timestamp: base.Add(time.Duration(minutes) * time.Minute)}
+	}
+
+	tests := []struct {
+		name      string
+		comments  []Comment
+		required  int
+		want      ReviewState
+		approvers int
+	}{
+		{"no verdicts", []Comment{{ID: "c1", Body: "nit"}}, 0, ReviewPending, 0},
+		{"one approval is enough by default", []Comment{verdict("c1", "a@x", ReviewApproved, 0)}, 0, ReviewApproved, 1},
+		{"needs two distinct reviewers", []Comment{
+			verdict("c1", "a@x", ReviewApproved, 0),
+			verdict("c2", "A@x", ReviewApproved, 1),
+		}, 2, ReviewPending, 1},
+		{"two reviewers approve", []Comment{
+			verdict("c1", "a@x", ReviewApproved, 0),
+			verdict("c2", "b@x", ReviewApproved, 1),
+		}, 2, ReviewApproved, 2},
+		{"changes requested blocks approval", []Comment{
+			verdict("c1", "a@x", ReviewApproved, 0),
+			verdict("c2", "b@x", ReviewChangesRequested, 1),
+		}, 1, ReviewChangesRequested, 1},
+		{"latest verdict wins", []Comment{
+			verdict("c1", "b@x", ReviewChangesRequested, 0),
+			verdict("c2", "b@x", ReviewApproved, 5),
+		}, 1, ReviewApproved, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := ComputeReviewStatus(tt.comments, tt.required)
+			if got.State != tt.want || len(got.Approvers) != tt.approvers {
+				t.Errorf("ComputeReviewStatus() = %s with %d approvers, want %s with %d", got.State, len(got.Approvers), tt.want, tt.approvers)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package checkpoint
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// RewindBackupRefPrefix is the ref namespace for backups taken before a
+// rewind. Each worktree keeps one backup at refs/entire/rewind-backup/<hash>,
+// where <hash> is HashWorktreeID of the worktree.
+const RewindBackupRefPrefix = "refs/entire/rewind-backup/"
+
+// ErrNoRewindBackup is returned when a worktree has no rewind backup.
+var ErrNoRewindBackup = errors.New("no rewind backup found")
+
+// RewindBackupRefName returns the backup ref for a worktree.
+func RewindBackupRefName(worktreeID string) plumbing.ReferenceName {
+	return plumbing.ReferenceName(RewindBackupRefPrefix + HashWorktreeID(worktreeID))
+}
+
+// WriteRewindBackup records HEAD and the working tree's uncommitted changes
+// (tracked and untracked, not ignored) as a commit on top of HEAD, and points
+// refName at it. Unlike WriteSnapshot it also writes a backup of a clean tree,
+// so the commit's parent always records where HEAD was.
+func (s *GitStore) WriteRewindBackup(ctx context.Context, refName plumbing.ReferenceName, authorName, authorEmail string) (plumbing.Hash, error) {
+	head, err := s.repo.Head()
+	if err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("failed to get HEAD: %w", err)
+	}
+	headCommit, err := s.repo.CommitObject(head.Hash())
+	if err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("failed to get HEAD commit: %w", err)
+	}
+
+	changes, err := collectChangedFiles(ctx, s.repo)
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+	treeHash := headCommit.TreeHash
+	if len(changes.Changed) > 0 || len(changes.Deleted) > 0 {
+		treeHash, err = s.buildTreeWithChanges(ctx, headCommit.TreeHash, changes.Changed, changes.Deleted, "", "")
+		if err != nil {
+			return plumbing.ZeroHash, fmt.Errorf("failed to build backup tree: %w", err)
+		}
+	}
+
+	commitHash, err := s.createCommit(treeHash, head.Hash(), "Working tree before rewind", authorName, authorEmail)
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+	if err := s.repo.Storer.SetReference(plumbing.NewHashReference(refName, commitHash)); err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("failed to set rewind backup ref: %w", err)
+	}
+	return commitHash, nil
+}
+
+// ReadRewindBackup returns the backup commit refName points at.
+// Returns ErrNoRewindBackup if the ref doesn't exist.
+func (s *GitStore) ReadRewindBackup(refName plumbing.ReferenceName) (plumbing.Hash, error) {
+	ref, err := s.repo.Reference(refName, true)
+	if err != nil {
+		return plumbing.ZeroHash, ErrNoRewindBackup
+	}
+	return ref.Hash(), nil
+}
+
+// DeleteRewindBackup removes a rewind backup ref. The commit is left for git gc.
+func (s *GitStore) DeleteRewindBackup(refName plumbing.ReferenceName) error {
+	if err := s.repo.Storer.RemoveReference(refName); err != nil {
+		return fmt.Errorf("failed to delete rewind backup ref %s: %w", refName, err)
+	}
+	return nil
+}
@@ -0,0 +1,223 @@
+package checkpoint
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// CurrentSchemaVersion is the version of the checkpoint metadata format this
+// CLI writes. Metadata without a schema_version predates versioning and is
+// version 0. Bump it together with a new entry in schemaMigrations.
+const CurrentSchemaVersion = 1
+
+// SchemaMigration upgrades checkpoint metadata from schema version From to
+// From+1. Fields a migration doesn't touch, including ones this CLI doesn't
+// know, are kept.
+type SchemaMigration struct {
+	From        int
+	Description string
+
+	// Summary upgrades a checkpoint's root metadata.json; nil leaves it as is
+	Summary func(*CheckpointSummary)
+
+	// Session upgrades one session's metadata.json; nil leaves it as is
+	Session func(*CommittedMetadata)
+}
+
+// schemaMigrations holds one migration per schema version, in order:
+// schemaMigrations[v] upgrades version v.
+var schemaMigrations = []SchemaMigration{
+	{
+		From:        0,
+		Description: "set checkpoint_transcript_start on sessions that only have transcript_lines_at_start",
+		Session: func(m *CommittedMetadata) {
+			if m.CheckpointTranscriptStart == 0 {
+				m.CheckpointTranscriptStart = m.TranscriptLinesAtStart
+			}
+		},
+	},
+}
+
+// SchemaMigrations returns the registered migrations, oldest first.
+func SchemaMigrations() []SchemaMigration {
+	return append([]SchemaMigration(nil), schemaMigrations...)
+}
+
+// migrateSummary upgrades summary to CurrentSchemaVersion, reporting whether
+// it changed. Summaries from a newer version are left alone.
+func migrateSummary(summary *CheckpointSummary) bool {
+	if summary.SchemaVersion >= CurrentSchemaVersion {
+		return false
+	}
+	for _, m := range schemaMigrations[summary.SchemaVersion:] {
+		if m.Summary != nil {
+			m.Summary(summary)
+		}
+	}
+	summary.SchemaVersion = CurrentSchemaVersion
+	return true
+}
+
+// migrateSessionMetadata upgrades a session's metadata to CurrentSchemaVersion,
+// reporting whether it changed. Metadata from a newer version is left alone.
+func migrateSessionMetadata(metadata *CommittedMetadata) bool {
+	if metadata.SchemaVersion >= CurrentSchemaVersion {
+		return false
+	}
+	for _, m := range schemaMigrations[metadata.SchemaVersion:] {
+		if m.Session != nil {
+			m.Session(metadata)
+		}
+	}
+	metadata.SchemaVersion = CurrentSchemaVersion
+	return true
+}
+
+// SchemaMigrationResult is the outcome of MigrateSchema.
+type SchemaMigrationResult struct {
+	// Migrated lists the checkpoints whose metadata was upgraded
+	Migrated []id.CheckpointID
+
+	// Current is the number of checkpoints that were already up to date
+	Current int
+
+	// Newer lists checkpoints written by a newer CLI, which are left alone
+	Newer []id.CheckpointID
+}
+
+// MigrateSchema upgrades the metadata of every committed checkpoint to
+// CurrentSchemaVersion, in a single commit on the metadata branch. Without
+// a metadata branch there is nothing to migrate.
+func (s *GitStore) MigrateSchema(ctx context.Context) (*SchemaMigrationResult, error) {
+	result := &SchemaMigrationResult{}
+	parentHash, rootTreeHash, err := s.getSessionsBranchRef()
+	if errors.Is(err, plumbing.ErrReferenceNotFound) {
+		return result, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	rootTree, err := s.repo.TreeObject(rootTreeHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read metadata tree: %w", err)
+	}
+
+	newTreeHash := rootTreeHash
+	for _, info := range s.scanCommitted(rootTree) {
+		if err := ctx.Err(); err != nil {
+			return nil, err //nolint:wrapcheck // Propagating context cancellation
+		}
+		entries, err := s.flattenCheckpointEntries(rootTreeHash, info.CheckpointID.Path())
+		if err != nil {
+			return nil, err
+		}
+		basePath := info.CheckpointID.Path() + "/"
+		changed, newer, err := s.migrateCheckpointEntries(basePath, entries)
+		if err != nil {
+			return nil, fmt.Errorf("failed to migrate checkpoint %s: %w", info.CheckpointID, err)
+		}
+		switch {
+		case newer:
+			result.Newer = append(result.Newer, info.CheckpointID)
+		case !changed:
+			result.Current++
+		default:
+			newTreeHash, err = s.spliceCheckpointSubtree(newTreeHash, info.CheckpointID, basePath, entries)
+			if err != nil {
+				return nil, err
+			}
+			result.Migrated = append(result.Migrated, info.CheckpointID)
+		}
+	}
+	if len(result.Migrated) == 0 {
+		return result, nil
+	}
+
+	authorName, authorEmail := GetGitAuthorFromRepo(s.repo)
+	message := fmt.Sprintf("Migrate checkpoint metadata to schema version %d\n\nMigrated %d checkpoint(s).\n",
+		CurrentSchemaVersion, len(result.Migrated))
+	commitHash, err := s.createCommit(newTreeHash, parentHash, message, authorName, authorEmail)
+	if err != nil {
+		return nil, err
+	}
+	refName := plumbing.NewBranchReferenceName(paths.MetadataBranchName)
+	newRef := plumbing.NewHashReference(refName, commitHash)
+	if err := s.repo.Storer.CheckAndSetReference(newRef, plumbing.NewHashReference(refName, parentHash)); err != nil {
+		return nil, fmt.Errorf("failed to set branch reference: %w", err)
+	}
+	s.updateIndex(ctx, parentHash, commitHash, newTreeHash, result.Migrated...)
+	return result, nil
+}
+
+// migrateCheckpointEntries upgrades the summary and session metadata among a
+// checkpoint's entries in place. It reports whether anything changed, and
+// whether any of the metadata comes from a newer schema; then nothing is
+// changed, since a partial upgrade would mix versions.
+func (s *GitStore) migrateCheckpointEntries(basePath string, entries map[string]object.TreeEntry) (bool, bool, error) {
+	summaryPath := basePath + paths.MetadataFileName
+	summaryEntry, ok := entries[summaryPath]
+	if !ok {
+		return false, false, nil
+	}
+	summary, err := s.readSummaryFromBlob(summaryEntry.Hash)
+	if err != nil {
+		return false, false, err
+	}
+	if summary.SchemaVersion > CurrentSchemaVersion {
+		return false, true, nil
+	}
+
+	sessions := make([]*CommittedMetadata, len(summary.Sessions))
+	for i := range summary.Sessions {
+		entry, ok := entries[basePath+strconv.Itoa(i)+"/"+paths.MetadataFileName]
+		if !ok {
+			continue
+		}
+		metadata, err := s.readMetadataFromBlob(entry.Hash)
+		if err != nil {
+			return false, false, err
+		}
+		if metadata.SchemaVersion > CurrentSchemaVersion {
+			return false, true, nil
+		}
+		sessions[i] = metadata
+	}
+
+	changed := false
+	write := func(path string, v any) error {
+		data, err := s.marshalUpdatedMetadata(v, entries[path].Hash)
+		if err != nil {
+			return err
+		}
+		hash, err := CreateBlobFromContent(s.repo, data)
+		if err != nil {
+			return err
+		}
+		entries[path] = object.TreeEntry{Name: path, Mode: filemode.Regular, Hash: hash}
+		changed = true
+		return nil
+	}
+	if migrateSummary(summary) {
+		if err := write(summaryPath, summary); err != nil {
+			return false, false, err
+		}
+	}
+	for i, metadata := range sessions {
+		if metadata == nil || !migrateSessionMetadata(metadata) {
+			continue
+		}
+		if err := write(basePath+strconv.Itoa(i)+"/"+paths.MetadataFileName, metadata); err != nil {
+			return false, false, err
+		}
+	}
+	return changed, false, nil
+}
@@ -0,0 +1,177 @@
+package checkpoint
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// editMetadataFields rewrites the JSON file name of a checkpoint with edit
+// applied to its fields, committing the change to the metadata branch.
+func editMetadataFields(t *testing.T, store *GitStore, cpID id.CheckpointID, name string, edit func(map[string]any)) {
+	t.Helper()
+	parentHash, rootTreeHash, err := store.getSessionsBranchRef()
+	if err != nil {
+		t.Fatalf("getSessionsBranchRef() error = %v", err)
+	}
+	entries, err := store.flattenCheckpointEntries(rootTreeHash, cpID.Path())
+	if err != nil {
+		t.Fatalf("flattenCheckpointEntries() error = %v", err)
+	}
+	path := cpID.Path() + "/" + name
+	data, err := readBlob(store.repo, entries[path].Hash)
+	if err != nil {
+		t.Fatalf("readBlob(%s) error = %v", name, err)
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		t.Fatalf("failed to parse %s: %v", name, err)
+	}
+	edit(fields)
+	if data, err = json.Marshal(fields); err != nil {
+		t.Fatalf("failed to marshal %s: %v", name, err)
+	}
+	hash, err := CreateBlobFromContent(store.repo, data)
+	if err != nil {
+		t.Fatalf("CreateBlobFromContent() error = %v", err)
+	}
+	entries[path] = object.TreeEntry{Name: path, Mode: filemode.Regular, Hash: hash}
+
+	treeHash, err := store.spliceCheckpointSubtree(rootTreeHash, cpID, cpID.Path()+"/", entries)
+	if err != nil {
+		t.Fatalf("spliceCheckpointSubtree() error = %v", err)
+	}
+	commitHash, err := store.createCommit(treeHash, parentHash, "edit "+name, "Test", "test@test.com")
+	if err != nil {
+		t.Fatalf("createCommit() error = %v", err)
+	}
+	ref := plumbing.NewHashReference(plumbing.NewBranchReferenceName(paths.MetadataBranchName), commitHash)
+	if err := store.repo.Storer.SetReference(ref); err != nil {
+		t.Fatalf("SetReference() error = %v", err)
+	}
+}
+
+func TestSchemaMigrations_Registry(t *testing.T) {
+	t.Parallel()
+	migrations := SchemaMigrations()
+	if len(migrations) != CurrentSchemaVersion {
+		t.Fatalf("%d migrations registered, want one per version below %d", len(migrations), CurrentSchemaVersion)
+	}
+	for i, m := range migrations {
+		if m.From != i {
+			t.Errorf("migration %d upgrades version %d, want %d", i, m.From, i)
+		}
+		if m.Description == "" {
+			t.Errorf("migration from version %d has no description", m.From)
+		}
+	}
+}
+
+func TestWriteCommitted_StampsSchemaVersion(t *testing.T) {
+	t.Parallel()
+	_, store, cpID := setupRepoForUpdate(t)
+	ctx := context.Background()
+
+	summary, err := store.ReadCommitted(ctx, cpID)
+	if err != nil || summary == nil {
+		t.Fatalf("ReadCommitted() = %v, %v", summary, err)
+	}
+	if summary.SchemaVersion != CurrentSchemaVersion {
+		t.Errorf("summary SchemaVersion = %d, want %d", summary.SchemaVersion, CurrentSchemaVersion)
+	}
+	content, err := store.ReadSessionContent(ctx, cpID, 0)
+	if err != nil {
+		t.Fatalf("ReadSessionContent() error = %v", err)
+	}
+	if content.Metadata.SchemaVersion != CurrentSchemaVersion {
+		t.Errorf("session SchemaVersion = %d, want %d", content.Metadata.SchemaVersion, CurrentSchemaVersion)
+	}
+}
+
+func TestMigrateSchema(t *testing.T) {
+	t.Parallel()
+	_, store, cpID := setupRepoForUpdate(t)
+	ctx := context.Background()
+
+	// Make the checkpoint look like one written before versioning
+	editMetadataFields(t, store, cpID, paths.MetadataFileName, func(f map[string]any) {
+		delete(f, "schema_version")
+	})
+	editMetadataFields(t, store, cpID, "0/"+paths.MetadataFileName, func(f map[string]any) {
+		delete(f, "schema_version")
+		delete(f, "checkpoint_transcript_start")
+		f["transcript_lines_at_start"] = 7
+		f[futureField] = "kept"
+	})
+
+	result, err := store.MigrateSchema(ctx)
+	if err != nil {
+		t.Fatalf("MigrateSchema() error = %v", err)
+	}
+	if !slices.Equal(result.Migrated, []id.CheckpointID{cpID}) || result.Current != 0 || len(result.Newer) != 0 {
+		t.Fatalf("MigrateSchema() = %+v, want %s migrated", result, cpID)
+	}
+
+	summary, err := store.ReadCommitted(ctx, cpID)
+	if err != nil || summary == nil {
+		t.Fatalf("ReadCommitted() = %v, %v", summary, err)
+	}
+	if summary.SchemaVersion != CurrentSchemaVersion {
+		t.Errorf("summary SchemaVersion = %d, want %d", summary.SchemaVersion, CurrentSchemaVersion)
+	}
+	files, err := store.ExportCheckpoint(ctx, cpID)
+	if err != nil {
+		t.Fatalf("ExportCheckpoint() error = %v", err)
+	}
+	var session map[string]any
+	if err := json.Unmarshal(files["0/"+paths.MetadataFileName], &session); err != nil {
+		t.Fatalf("failed to parse session metadata: %v", err)
+	}
+	if session["schema_version"] != float64(CurrentSchemaVersion) || session["checkpoint_transcript_start"] != float64(7) {
+		t.Errorf("migrated session metadata = %v", session)
+	}
+	if session[futureField] != "kept" {
+		t.Errorf("migration dropped %s: %v", futureField, session)
+	}
+
+	again, err := store.MigrateSchema(ctx)
+	if err != nil {
+		t.Fatalf("MigrateSchema() again error = %v", err)
+	}
+	if len(again.Migrated) != 0 || again.Current != 1 {
+		t.Errorf("MigrateSchema() again = %+v, want nothing to migrate", again)
+	}
+
+	// Metadata from a newer version is left alone
+	editMetadataFields(t, store, cpID, "0/"+paths.MetadataFileName, func(f map[string]any) {
+		f["schema_version"] = CurrentSchemaVersion + 1
+	})
+	newer, err := store.MigrateSchema(ctx)
+	if err != nil {
+		t.Fatalf("MigrateSchema() with newer metadata error = %v", err)
+	}
+	if !slices.Equal(newer.Newer, []id.CheckpointID{cpID}) || len(newer.Migrated) != 0 {
+		t.Errorf("MigrateSchema() with newer metadata = %+v, want %s left alone", newer, cpID)
+	}
+}
+
+func TestMigrateSchema_NoBranch(t *testing.T) {
+	t.Parallel()
+	repo, _ := setupBranchTestRepo(t)
+
+	result, err := NewGitStore(repo).MigrateSchema(context.Background())
+	if err != nil {
+		t.Fatalf("MigrateSchema() error = %v", err)
+	}
+	if len(result.Migrated) != 0 || result.Current != 0 {
+		t.Errorf("MigrateSchema() without a branch = %+v", result)
+	}
+}
@@ -0,0 +1,186 @@
+package checkpoint
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+)
+
+// Sources a search match can come from.
+const (
+	SearchSourcePrompt     = "prompt"
+	SearchSourceContext    = "context"
+	SearchSourceTranscript = "transcript"
+)
+
+// maxSearchMatchesPerCheckpoint caps the matches kept for each checkpoint;
+// SearchHit.MatchCount still counts them all.
+const maxSearchMatchesPerCheckpoint = 5
+
+// searchSnippetRadius is the number of characters kept on each side of a
+// match in its snippet.
+const searchSnippetRadius = 40
+
+// SearchOptions selects what Search looks for.
+type SearchOptions struct {
+	// Query is matched case-insensitively as a substring. Runs of whitespace
+	// in the query and the searched text are treated as a single space.
+	Query string
+
+	// Limit is the maximum number of matching checkpoints. Zero returns all.
+	Limit int
+
+	// CheckpointIDs restricts the search to these checkpoints. Nil searches
+	// every checkpoint.
+	CheckpointIDs map[id.CheckpointID]bool
+}
+
+// SearchMatch is one line of a session that contains the query.
+type SearchMatch struct {
+	SessionIndex int
+	SessionID    string
+
+	// Source is SearchSourcePrompt, SearchSourceContext, or SearchSourceTranscript
+	Source string
+
+	// Line is the 1-based prompt number for prompts, and line number otherwise
+	Line int
+
+	// Snippet is the matching line with whitespace collapsed, cut to the
+	// text around the match
+	Snippet string
+}
+
+// SearchHit is a checkpoint with matches.
+type SearchHit struct {
+	Info CommittedInfo
+
+	// Matches holds the first matches, in session and line order
+	Matches []SearchMatch
+
+	// MatchCount is the number of matches, including those not kept in Matches
+	MatchCount int
+}
+
+// SearchResult is the outcome of Search.
+type SearchResult struct {
+	// Hits are the matching checkpoints, most recent first
+	Hits []SearchHit
+
+	// Skipped is the number of sessions that couldn't be read, such as
+	// encrypted sessions without a matching identity
+	Skipped int
+}
+
+// Search looks for opts.Query in the prompts, context, and transcripts of
+// every committed checkpoint in store, most recent first. Sessions are read
+// one at a time, so memory use is bounded by the largest session.
+func Search(ctx context.Context, store Store, opts SearchOptions) (*SearchResult, error) {
+	query := strings.ToLower(strings.Join(strings.Fields(opts.Query), " "))
+	if query == "" {
+		return nil, errors.New("search query is empty")
+	}
+
+	infos, err := store.ListCommitted(ctx)
+	if err != nil {
+		return nil, err //nolint:wrapcheck // Callers wrap
+	}
+
+	result := &SearchResult{Hits: []SearchHit{}}
+	for _, info := range infos {
+		if opts.Limit > 0 && len(result.Hits) == opts.Limit {
+			break
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err //nolint:wrapcheck // Propagating context cancellation
+		}
+
+		if opts.CheckpointIDs != nil && !opts.CheckpointIDs[info.CheckpointID] {
+			continue
+		}
+
+		hit := SearchHit{Info: info}
+		for i := range info.SessionCount {
+			content, err := store.ReadSessionContent(ctx, info.CheckpointID, i)
+			if err != nil {
+				result.Skipped++
+				continue
+			}
+			searchSession(&hit, i, content, query)
+		}
+		if hit.MatchCount > 0 {
+			result.Hits = append(result.Hits, hit)
+		}
+	}
+	return result, nil
+}
+
+// searchSession adds the matches of query in one session's content to hit.
+func searchSession(hit *SearchHit, sessionIndex int, content *SessionContent, query string) {
+	add := func(source string, line int, text string) {
+		snippet, ok := matchSnippet(text, query)
+		if !ok {
+			return
+		}
+		hit.MatchCount++
+		if len(hit.Matches) < maxSearchMatchesPerCheckpoint {
+			hit.Matches = append(hit.Matches, SearchMatch{
+				SessionIndex: sessionIndex,
+				SessionID:    content.Metadata.SessionID,
+				Source:       source,
+				Line:         line,
+				Snippet:      snippet,
+			})
+		}
+	}
+
+	for i, prompt := range content.PromptList {
+		add(SearchSourcePrompt, i+1, prompt)
+	}
+	for i, line := range strings.Split(content.Context, "\n") {
+		add(SearchSourceContext, i+1, line)
+	}
+	for i, line := range strings.Split(string(content.Transcript), "\n") {
+		add(SearchSourceTranscript, i+1, line)
+	}
+}
+
+// matchSnippet reports whether text contains query (already lowercased and
+// whitespace-collapsed), and returns the text around the first match.
+func matchSnippet(text, query string) (string, bool) {
+	collapsed := strings.Join(strings.Fields(text), " ")
+	lower := strings.ToLower(collapsed)
+	start := strings.Index(lower, query)
+	if start < 0 {
+		return "", false
+	}
+	// Lowercasing can change the byte length of some characters; cut the
+	// lowercased text then, so the offsets stay valid
+	if len(lower) != len(collapsed) {
+		collapsed = lower
+	}
+	end := start + len(query)
+
+	from := start
+	for n := 0; n < searchSnippetRadius && from > 0; n++ {
+		_, size := utf8.DecodeLastRuneInString(collapsed[:from])
+		from -= size
+	}
+	to := end
+	for n := 0; n < searchSnippetRadius && to < len(collapsed); n++ {
+		_, size := utf8.DecodeRuneInString(collapsed[to:])
+		to += size
+	}
+
+	snippet := collapsed[from:to]
+	if from > 0 {
+		snippet = "…" + snippet
+	}
+	if to < len(collapsed) {
+		snippet += "…"
+	}
+	return snippet, true
+}
@@ -0,0 +1,77 @@
+package checkpoint
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+)
+
+func TestSearch_FindsPromptsContextAndTranscripts(t *testing.T) {
+	t.Parallel()
+	_, store, cpID := setupRepoForUpdate(t)
+	ctx := context.Background()
+
+	other := id.MustCheckpointID("b1b2c3d4e5f6")
+	if err := store.WriteCommitted(ctx, WriteCommittedOptions{
+		CheckpointID: other,
+		SessionID:    "session-other",
+		Strategy:     "manual-commit",
+		Transcript:   []byte("line one\nthe Cache   was stale here\n"),
+		Prompts:      []string{"fix the cache invalidation"},
+		Context:      []byte("nothing relevant"),
+		AuthorName:   "Test",
+		AuthorEmail:  "test@test.com",
+	}); err != nil {
+		t.Fatalf("WriteCommitted() error = %v", err)
+	}
+
+	result, err := Search(ctx, store, SearchOptions{Query: "CACHE"})
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if len(result.Hits) != 1 || result.Hits[0].Info.CheckpointID != other {
+		t.Fatalf("Search() hits = %+v, want only %s", result.Hits, other)
+	}
+	hit := result.Hits[0]
+	if hit.MatchCount != 2 {
+		t.Errorf("MatchCount = %d, want 2", hit.MatchCount)
+	}
+	want := []SearchMatch{
+		{SessionID: "session-other", Source: SearchSourcePrompt, Line: 1, Snippet: "fix the cache invalidation"},
+		{SessionID: "session-other", Source: SearchSourceTranscript, Line: 2, Snippet: "the Cache was stale here"},
+	}
+	for i, m := range want {
+		if i >= len(hit.Matches) || hit.Matches[i] != m {
+			t.Errorf("match %d = %+v, want %+v", i, hit.Matches, m)
+		}
+	}
+
+	// Whitespace in the query matches any run of whitespace
+	if result, err = Search(ctx, store, SearchOptions{Query: "cache was"}); err != nil || len(result.Hits) != 1 {
+		t.Errorf("Search(cache was) = %+v, %v, want one hit", result, err)
+	}
+	if result, err = Search(ctx, store, SearchOptions{Query: "provisional"}); err != nil || len(result.Hits) != 1 || result.Hits[0].Info.CheckpointID != cpID {
+		t.Errorf("Search(provisional) = %+v, %v, want %s", result, err, cpID)
+	}
+	if _, err := Search(ctx, store, SearchOptions{Query: "  "}); err == nil {
+		t.Error("Search() with an empty query succeeded, want an error")
+	}
+}
+
+func TestMatchSnippet_CutsAroundMatch(t *testing.T) {
+	t.Parallel()
+	text := strings.Repeat("a", 100) + " needle " + strings.Repeat("é", 100)
+	snippet, ok := matchSnippet(text, "needle")
+	if !ok {
+		t.Fatal("matchSnippet() found no match")
+	}
+	want := "…" + strings.Repeat("a", 39) + " needle " + strings.Repeat("é", 39) + "…"
+	if snippet != want {
+		t.Errorf("matchSnippet() = %q, want %q", snippet, want)
+	}
+	if _, ok := matchSnippet("haystack", "needle"); ok {
+		t.Error("matchSnippet() matched text without the query")
+	}
+}
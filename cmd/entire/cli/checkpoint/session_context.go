@@ -0,0 +1,247 @@
+package checkpoint
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/entireio/cli/cmd/entire/cli/age"
+	"github.com/entireio/cli/cmd/entire/cli/jsonutil"
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+	"github.com/entireio/cli/redact"
+
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// ContextSection names a section of a SessionContext.
+type ContextSection string
+
+const (
+	// ContextEnvironment describes where the session ran (agent, model, branch).
+	ContextEnvironment ContextSection = "environment"
+	// ContextTask describes what the session was asked to do.
+	ContextTask ContextSection = "task"
+	// ContextConstraints holds rules the session worked under.
+	ContextConstraints ContextSection = "constraints"
+	// ContextReferences points at issues, docs, or other sessions.
+	ContextReferences ContextSection = "references"
+)
+
+// ContextSections lists the sections in the order they are rendered.
+var ContextSections = []ContextSection{ContextEnvironment, ContextTask, ContextConstraints, ContextReferences}
+
+const (
+	// MaxContextValueBytes caps a single SessionContext value.
+	MaxContextValueBytes = 16 << 10
+
+	// maxContextKeyLength caps SessionContext keys.
+	maxContextKeyLength = 64
+)
+
+// contextKeyPattern is the form of SessionContext keys: lowercase words
+// joined by '_', '.', or '-'.
+var contextKeyPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_.-]*$`)
+
+// SessionContext is the structured form of a session's context, stored as
+// context.json next to the rendered context.md. Each section maps keys to
+// values; use Set and Get rather than the maps directly.
+type SessionContext struct {
+	Environment map[string]string `json:"environment,omitempty"`
+	Task        map[string]string `json:"task,omitempty"`
+	Constraints map[string]string `json:"constraints,omitempty"`
+	References  map[string]string `json:"references,omitempty"`
+}
+
+// section returns the map backing a section, creating it if create is set.
+func (c *SessionContext) section(section ContextSection, create bool) (*map[string]string, error) {
+	var m *map[string]string
+	switch section {
+	case ContextEnvironment:
+		m = &c.Environment
+	case ContextTask:
+		m = &c.Task
+	case ContextConstraints:
+		m = &c.Constraints
+	case ContextReferences:
+		m = &c.References
+	default:
+		return nil, fmt.Errorf("unknown context section %q", section)
+	}
+	if create && *m == nil {
+		*m = make(map[string]string)
+	}
+	return m, nil
+}
+
+// Set stores value under key in section. The value is normalized like
+// prompts: invalid UTF-8 is replaced, control characters other than newline
+// and tab are stripped, and values over MaxContextValueBytes are truncated.
+// An empty value removes the key.
+func (c *SessionContext) Set(section ContextSection, key, value string) error {
+	if err := validateContextKey(key); err != nil {
+		return err
+	}
+	m, err := c.section(section, value != "")
+	if err != nil {
+		return err
+	}
+	if value == "" {
+		delete(*m, key)
+		return nil
+	}
+	(*m)[key], _ = normalizeText(value, MaxContextValueBytes)
+	return nil
+}
+
+// Get returns the value stored under key in section.
+func (c *SessionContext) Get(section ContextSection, key string) (string, bool) {
+	if c == nil {
+		return "", false
+	}
+	m, err := c.section(section, false)
+	if err != nil {
+		return "", false
+	}
+	value, ok := (*m)[key]
+	return value, ok
+}
+
+// Keys returns the keys of a section, sorted.
+func (c *SessionContext) Keys(section ContextSection) []string {
+	if c == nil {
+		return nil
+	}
+	m, err := c.section(section, false)
+	if err != nil {
+		return nil
+	}
+	keys := make([]string, 0, len(*m))
+	for key := range *m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// IsEmpty reports whether no section has any values.
+func (c *SessionContext) IsEmpty() bool {
+	return c == nil || len(c.Environment)+len(c.Task)+len(c.Constraints)+len(c.References) == 0
+}
+
+// Validate checks every key and value, e.g. for a context decoded from JSON
+// or built without Set.
+func (c *SessionContext) Validate() error {
+	if c == nil {
+		return nil
+	}
+	var errs []error
+	for _, section := range ContextSections {
+		m, _ := c.section(section, false) //nolint:errcheck // ContextSections are all known
+		for key, value := range *m {
+			if err := validateContextKey(key); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", section, err))
+			}
+			if err := validateContextValue(value); err != nil {
+				errs = append(errs, fmt.Errorf("%s.%s: %w", section, key, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func validateContextKey(key string) error {
+	if len(key) > maxContextKeyLength || !contextKeyPattern.MatchString(key) {
+		return fmt.Errorf("invalid context key %q: use up to %d lowercase letters, digits, '_', '.', or '-'", key, maxContextKeyLength)
+	}
+	return nil
+}
+
+func validateContextValue(value string) error {
+	switch {
+	case len(value) > MaxContextValueBytes:
+		return fmt.Errorf("value is %d bytes, over the %d byte limit", len(value), MaxContextValueBytes)
+	case !utf8.ValidString(value):
+		return errors.New("value is not valid UTF-8")
+	case strings.ContainsFunc(value, func(r rune) bool { return unicode.IsControl(r) && r != '\n' && r != '\t' }):
+		return errors.New("value contains control characters")
+	}
+	return nil
+}
+
+// ParseSessionContext decodes and validates context.json content. Unknown
+// sections are rejected.
+func ParseSessionContext(data []byte) (*SessionContext, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	var c SessionContext
+	if err := dec.Decode(&c); err != nil {
+		return nil, fmt.Errorf("invalid session context: %w", err)
+	}
+	if err := c.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid session context: %w", err)
+	}
+	return &c, nil
+}
+
+// Markdown renders the context as context.md, one heading per non-empty
+// section.
+func (c *SessionContext) Markdown() []byte {
+	var buf strings.Builder
+	buf.WriteString("# Session Context\n\n")
+	for _, section := range ContextSections {
+		keys := c.Keys(section)
+		if len(keys) == 0 {
+			continue
+		}
+		name := string(section)
+		fmt.Fprintf(&buf, "## %s\n\n", strings.ToUpper(name[:1])+name[1:])
+		for _, key := range keys {
+			value, _ := c.Get(section, key)
+			if strings.Contains(value, "\n") {
+				fmt.Fprintf(&buf, "### %s\n\n%s\n\n", key, value)
+			} else {
+				fmt.Fprintf(&buf, "- **%s**: %s\n", key, value)
+			}
+		}
+		buf.WriteString("\n")
+	}
+	return []byte(buf.String())
+}
+
+// writeStructuredContext validates c and writes it, with every value
+// redacted, to sessionPath as context.json, encrypted to recipients if any.
+func (s *GitStore) writeStructuredContext(c *SessionContext, sessionPath string, entries map[string]object.TreeEntry, recipients []*age.Recipient) error {
+	if err := c.Validate(); err != nil {
+		return fmt.Errorf("invalid session context: %w", err)
+	}
+	redacted := &SessionContext{}
+	for _, section := range ContextSections {
+		for _, key := range c.Keys(section) {
+			value, _ := c.Get(section, key)
+			if err := redacted.Set(section, key, redact.String(value)); err != nil {
+				return err
+			}
+		}
+	}
+	data, err := jsonutil.MarshalIndentWithNewline(redacted, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal session context: %w", err)
+	}
+	blobHash, err := s.createContentBlob(data, recipients)
+	if err != nil {
+		return fmt.Errorf("failed to create context blob: %w", err)
+	}
+	entries[sessionPath+paths.ContextJSONFileName] = object.TreeEntry{
+		Name: sessionPath + paths.ContextJSONFileName,
+		Mode: filemode.Regular,
+		Hash: blobHash,
+	}
+	return nil
+}
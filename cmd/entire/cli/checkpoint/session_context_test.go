@@ -0,0 +1,110 @@
+package checkpoint
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestSessionContext_SetGetKeys(t *testing.T) {
+	t.Parallel()
+	c := &SessionContext{}
+	if err := c.Set(ContextEnvironment, "model", "sonnet\x07"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if err := c.Set(ContextEnvironment, "agent", "Claude Code"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if value, ok := c.Get(ContextEnvironment, "model"); !ok || value != "sonnet" {
+		t.Errorf("Get(model) = %q, %v; want control character stripped", value, ok)
+	}
+	if keys := c.Keys(ContextEnvironment); strings.Join(keys, ",") != "agent,model" {
+		t.Errorf("Keys() = %v", keys)
+	}
+
+	if err := c.Set(ContextEnvironment, "model", ""); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if _, ok := c.Get(ContextEnvironment, "model"); ok {
+		t.Error("empty value should remove the key")
+	}
+	if err := c.Set(ContextTask, "Bad Key", "x"); err == nil {
+		t.Error("Set() should reject an invalid key")
+	}
+	if err := c.Set("history", "key", "x"); err == nil {
+		t.Error("Set() should reject an unknown section")
+	}
+}
+
+func TestParseSessionContext(t *testing.T) {
+	t.Parallel()
+	c, err := ParseSessionContext([]byte(`{"task": {"goal": "fix login"}, "references": {"issue": "#12"}}`))
+	if err != nil {
+		t.Fatalf("ParseSessionContext() error = %v", err)
+	}
+	if goal, _ := c.Get(ContextTask, "goal"); goal != "fix login" {
+		t.Errorf("task.goal = %q", goal)
+	}
+
+	for _, invalid := range []string{
+		`{"history": {"a": "b"}}`,
+		`{"task": {"Goal": "x"}}`,
+		`{"task": {"goal": "a\u0000b"}}`,
+		`not json`,
+	} {
+		if _, err := ParseSessionContext([]byte(invalid)); err == nil {
+			t.Errorf("ParseSessionContext(%s) should fail", invalid)
+		}
+	}
+}
+
+func TestWriteCommitted_StructuredContext(t *testing.T) {
+	t.Parallel()
+	_, store, cpID := setupRepoForUpdate(t)
+
+	structured := &SessionContext{}
+	if err := structured.Set(ContextTask, "goal", "fix login"); err != nil {
+		t.Fatal(err)
+	}
+	if err := structured.Set(ContextConstraints, "tests", "must pass"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.WriteCommitted(context.Background(), WriteCommittedOptions{
+		CheckpointID:      cpID,
+		SessionID:         "session-001",
+		Strategy:          "manual-commit",
+		Transcript:        []byte("line\n"),
+		StructuredContext: structured,
+		AuthorName:        "Test",
+		AuthorEmail:       "test@test.com",
+	}); err != nil {
+		t.Fatalf("WriteCommitted() error = %v", err)
+	}
+
+	content, err := store.ReadSessionContent(context.Background(), cpID, 0)
+	if err != nil {
+		t.Fatalf("ReadSessionContent() error = %v", err)
+	}
+	if goal, _ := content.StructuredContext.Get(ContextTask, "goal"); goal != "fix login" {
+		t.Errorf("StructuredContext task.goal = %q", goal)
+	}
+	if !strings.Contains(content.Context, "## Constraints") || !strings.Contains(content.Context, "- **goal**: fix login") {
+		t.Errorf("context.md should be rendered from the structured context:\n%s", content.Context)
+	}
+
+	// A legacy writer replacing only context.md drops the stale context.json
+	if err := store.UpdateCommitted(context.Background(), UpdateCommittedOptions{
+		CheckpointID: cpID,
+		SessionID:    "session-001",
+		Context:      []byte("# Plain context"),
+	}); err != nil {
+		t.Fatalf("UpdateCommitted() error = %v", err)
+	}
+	content, err = store.ReadSessionContent(context.Background(), cpID, 0)
+	if err != nil {
+		t.Fatalf("ReadSessionContent() error = %v", err)
+	}
+	if content.StructuredContext != nil || content.Context != "# Plain context" {
+		t.Errorf("got structured %+v, context %q; want only the raw context", content.StructuredContext, content.Context)
+	}
+}
@@ -0,0 +1,176 @@
+package checkpoint
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/entireio/cli/cmd/entire/cli/trailers"
+
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// SnapshotRefPrefix is the ref namespace for pre-turn snapshots of uncommitted
+// changes. Snapshots live at refs/entire/snapshots/<session-id>/<turn-id>.
+// They are refs rather than branches so they stay out of `git branch` output.
+const SnapshotRefPrefix = "refs/entire/snapshots/"
+
+// ManualSnapshotSessionID files snapshots taken with `entire snapshot`,
+// which belong to no agent session.
+const ManualSnapshotSessionID = "manual"
+
+// defaultSnapshotMessage is the commit subject of pre-turn snapshots.
+const defaultSnapshotMessage = "Uncommitted changes before turn"
+
+// WriteSnapshotOptions contains the parameters for writing a pre-turn snapshot.
+type WriteSnapshotOptions struct {
+	// SessionID is the agent session the snapshot belongs to
+	SessionID string
+
+	// TurnID identifies the turn the snapshot was taken before
+	TurnID string
+
+	// AuthorName is the name to use for the snapshot commit author
+	AuthorName string
+
+	// AuthorEmail is the email to use for the snapshot commit author
+	AuthorEmail string
+
+	// Message is the snapshot commit's subject. Empty describes a pre-turn snapshot.
+	Message string
+
+	// Previous is an earlier snapshot. If the worktree still matches it on
+	// the same HEAD, nothing is written. Zero always writes.
+	Previous plumbing.Hash
+}
+
+// SnapshotInfo describes a pre-turn snapshot.
+type SnapshotInfo struct {
+	RefName    plumbing.ReferenceName
+	CommitHash plumbing.Hash
+	BaseCommit plumbing.Hash // HEAD when the snapshot was taken
+	SessionID  string
+	TurnID     string
+	Message    string // subject of the snapshot commit
+	Timestamp  time.Time
+}
+
+// SnapshotRefName returns the ref name for a session's turn snapshot.
+func SnapshotRefName(sessionID, turnID string) plumbing.ReferenceName {
+	return plumbing.ReferenceName(SnapshotRefPrefix + sessionID + "/" + turnID)
+}
+
+// WriteSnapshot records the working tree's uncommitted changes (tracked and
+// untracked, not ignored) as a commit on top of HEAD and points the session's
+// snapshot ref at it. Returns false if the working tree is clean, or
+// unchanged since opts.Previous, and no snapshot was written.
+func (s *GitStore) WriteSnapshot(ctx context.Context, opts WriteSnapshotOptions) (plumbing.Hash, bool, error) {
+	if opts.SessionID == "" || opts.TurnID == "" {
+		return plumbing.ZeroHash, false, errors.New("SessionID and TurnID are required for snapshot")
+	}
+
+	head, err := s.repo.Head()
+	if err != nil {
+		return plumbing.ZeroHash, false, fmt.Errorf("failed to get HEAD: %w", err)
+	}
+	headCommit, err := s.repo.CommitObject(head.Hash())
+	if err != nil {
+		return plumbing.ZeroHash, false, fmt.Errorf("failed to get HEAD commit: %w", err)
+	}
+
+	changes, err := collectChangedFiles(ctx, s.repo)
+	if err != nil {
+		return plumbing.ZeroHash, false, err
+	}
+	if len(changes.Changed) == 0 && len(changes.Deleted) == 0 {
+		return plumbing.ZeroHash, false, nil
+	}
+
+	treeHash, err := s.buildTreeWithChanges(ctx, headCommit.TreeHash, changes.Changed, changes.Deleted, "", "")
+	if err != nil {
+		return plumbing.ZeroHash, false, fmt.Errorf("failed to build snapshot tree: %w", err)
+	}
+	if treeHash == headCommit.TreeHash {
+		return plumbing.ZeroHash, false, nil
+	}
+	if !opts.Previous.IsZero() {
+		previous, err := s.repo.CommitObject(opts.Previous)
+		if err == nil && previous.TreeHash == treeHash && len(previous.ParentHashes) > 0 && previous.ParentHashes[0] == head.Hash() {
+			return opts.Previous, false, nil
+		}
+	}
+
+	subject := opts.Message
+	if subject == "" {
+		subject = defaultSnapshotMessage
+	}
+	message := fmt.Sprintf("%s\n\n%s: %s\n", subject, trailers.SessionTrailerKey, opts.SessionID)
+	commitHash, err := s.createCommit(treeHash, head.Hash(), message, opts.AuthorName, opts.AuthorEmail)
+	if err != nil {
+		return plumbing.ZeroHash, false, err
+	}
+
+	ref := plumbing.NewHashReference(SnapshotRefName(opts.SessionID, opts.TurnID), commitHash)
+	if err := s.repo.Storer.SetReference(ref); err != nil {
+		return plumbing.ZeroHash, false, fmt.Errorf("failed to set snapshot ref: %w", err)
+	}
+	return commitHash, true, nil
+}
+
+// ListSnapshots returns the pre-turn snapshots for sessionID, or for all
+// sessions if sessionID is empty.
+func (s *GitStore) ListSnapshots(ctx context.Context, sessionID string) ([]SnapshotInfo, error) {
+	refs, err := s.repo.References()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list references: %w", err)
+	}
+
+	var snapshots []SnapshotInfo
+	err = refs.ForEach(func(ref *plumbing.Reference) error {
+		if err := ctx.Err(); err != nil {
+			return err //nolint:wrapcheck // Propagating context cancellation
+		}
+		name := ref.Name().String()
+		if ref.Type() != plumbing.HashReference || !strings.HasPrefix(name, SnapshotRefPrefix) {
+			return nil
+		}
+		refSessionID, turnID, ok := strings.Cut(strings.TrimPrefix(name, SnapshotRefPrefix), "/")
+		if !ok || (sessionID != "" && refSessionID != sessionID) {
+			return nil
+		}
+		commit, commitErr := s.repo.CommitObject(ref.Hash())
+		if commitErr != nil {
+			return nil //nolint:nilerr // Skip refs pointing at missing commits
+		}
+		info := SnapshotInfo{
+			RefName:    ref.Name(),
+			CommitHash: ref.Hash(),
+			SessionID:  refSessionID,
+			TurnID:     turnID,
+			Message:    strings.SplitN(commit.Message, "\n", 2)[0],
+			Timestamp:  commit.Author.When,
+		}
+		if len(commit.ParentHashes) > 0 {
+			info.BaseCommit = commit.ParentHashes[0]
+		}
+		snapshots = append(snapshots, info)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to iterate references: %w", err)
+	}
+	return snapshots, nil
+}
+
+// DeleteSnapshot removes a snapshot ref. The snapshot commit is left for git gc.
+func (s *GitStore) DeleteSnapshot(refName plumbing.ReferenceName) error {
+	if !strings.HasPrefix(refName.String(), SnapshotRefPrefix) {
+		return fmt.Errorf("not a snapshot ref: %s", refName)
+	}
+	if err := s.repo.Storer.RemoveReference(refName); err != nil {
+		return fmt.Errorf("failed to delete snapshot ref %s: %w", refName, err)
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package checkpoint
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+func TestWriteSnapshot(t *testing.T) {
+	tempDir := t.TempDir()
+	repo, err := git.PlainInit(tempDir, false)
+	if err != nil {
+		t.Fatalf("failed to init git repo: %v", err)
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		t.Fatalf("failed to get worktree: %v", err)
+	}
+	readmeFile := filepath.Join(tempDir, "README.md")
+	if err := os.WriteFile(readmeFile, []byte("# Test\n"), 0o644); err != nil {
+		t.Fatalf("failed to write README: %v", err)
+	}
+	if _, err := worktree.Add("README.md"); err != nil {
+		t.Fatalf("failed to add README: %v", err)
+	}
+	initialCommit, err := worktree.Commit("Initial commit", &git.CommitOptions{
+		Author: &object.Signature{Name: "Test", Email: "test@test.com"},
+	})
+	if err != nil {
+		t.Fatalf("failed to commit: %v", err)
+	}
+
+	// Change to temp dir so paths.WorktreeRoot() works correctly
+	t.Chdir(tempDir)
+
+	store := NewGitStore(repo)
+	ctx := context.Background()
+	opts := WriteSnapshotOptions{SessionID: "test-session", TurnID: "turn-1", AuthorName: "Test", AuthorEmail: "test@test.com"}
+
+	// Clean working tree: nothing to snapshot
+	if _, written, err := store.WriteSnapshot(ctx, opts); err != nil || written {
+		t.Fatalf("WriteSnapshot() on clean tree = %v, %v; want false, nil", written, err)
+	}
+
+	if err := os.WriteFile(readmeFile, []byte("# Edited by user\n"), 0o644); err != nil {
+		t.Fatalf("failed to modify README: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tempDir, "notes.txt"), []byte("todo\n"), 0o644); err != nil {
+		t.Fatalf("failed to write notes: %v", err)
+	}
+
+	hash, written, err := store.WriteSnapshot(ctx, opts)
+	if err != nil || !written {
+		t.Fatalf("WriteSnapshot() = %v, %v; want true, nil", written, err)
+	}
+
+	commit, err := repo.CommitObject(hash)
+	if err != nil {
+		t.Fatalf("failed to read snapshot commit: %v", err)
+	}
+	for path, want := range map[string]string{"README.md": "# Edited by user\n", "notes.txt": "todo\n"} {
+		file, err := commit.File(path)
+		if err != nil {
+			t.Fatalf("snapshot missing %s: %v", path, err)
+		}
+		got, err := file.Contents()
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", path, err)
+		}
+		if got != want {
+			t.Errorf("snapshot %s = %q, want %q", path, got, want)
+		}
+	}
+
+	snapshots, err := store.ListSnapshots(ctx, "test-session")
+	if err != nil {
+		t.Fatalf("ListSnapshots() error = %v", err)
+	}
+	if len(snapshots) != 1 || snapshots[0].CommitHash != hash || snapshots[0].BaseCommit != initialCommit || snapshots[0].TurnID != "turn-1" {
+		t.Fatalf("ListSnapshots() = %+v, want one snapshot on the initial commit", snapshots)
+	}
+	if others, err := store.ListSnapshots(ctx, "other-session"); err != nil || len(others) != 0 {
+		t.Errorf("ListSnapshots(other-session) = %v, %v; want none", others, err)
+	}
+
+	// An unchanged worktree isn't snapshotted again after Previous
+	next := opts
+	next.TurnID = "turn-2"
+	next.Previous = hash
+	if got, written, err := store.WriteSnapshot(ctx, next); err != nil || written || got != hash {
+		t.Errorf("WriteSnapshot() unchanged since Previous = %v, %v, %v; want %v, false, nil", got, written, err, hash)
+	}
+
+	if err := store.DeleteSnapshot(snapshots[0].RefName); err != nil {
+		t.Fatalf("DeleteSnapshot() error = %v", err)
+	}
+	if remaining, err := store.ListSnapshots(ctx, ""); err != nil || len(remaining) != 0 {
+		t.Errorf("ListSnapshots() after delete = %v, %v; want none", remaining, err)
+	}
+}
@@ -1,16 +1,25 @@
 package checkpoint
 
 import (
+	"sync"
+
+	"github.com/entireio/cli/cmd/entire/cli/age"
+
 	"github.com/go-git/go-git/v5"
 )
 
-// Compile-time check that GitStore implements the Store interface.
-var _ Store = (*GitStore)(nil)
+// Compile-time check that GitStore implements the CommittedStore interface.
+var _ CommittedStore = (*GitStore)(nil)
 
 // GitStore provides operations for both temporary and committed checkpoint storage.
 // It implements the Store interface by wrapping a git repository.
 type GitStore struct {
 	repo *git.Repository
+
+	// Identities for decrypting encrypted checkpoints, loaded on first use
+	identityMu       sync.Mutex
+	identities       []*age.Identity
+	identitiesLoaded bool
 }
 
 // NewGitStore creates a new checkpoint store backed by the given git repository.
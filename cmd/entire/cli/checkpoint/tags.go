@@ -0,0 +1,141 @@
+package checkpoint
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// MaxTagLength caps the length of a checkpoint tag.
+const MaxTagLength = 64
+
+// ValidateTag reports whether tag can label a checkpoint: non-empty, at
+// most MaxTagLength bytes, without whitespace or commas, and not starting
+// with '-' so it can't be mistaken for a flag.
+func ValidateTag(tag string) error {
+	switch {
+	case tag == "":
+		return errors.New("tag is empty")
+	case len(tag) > MaxTagLength:
+		return fmt.Errorf("tag %q is longer than %d bytes", tag, MaxTagLength)
+	case strings.HasPrefix(tag, "-"):
+		return fmt.Errorf("tag %q starts with '-'", tag)
+	case strings.ContainsFunc(tag, func(r rune) bool { return r == ',' || unicode.IsSpace(r) || unicode.IsControl(r) }):
+		return fmt.Errorf("tag %q contains whitespace or a comma", tag)
+	}
+	return nil
+}
+
+// Tag adds tags to a committed checkpoint, such as "good-state" or
+// "before-refactor". Tags are stored sorted in the checkpoint's root
+// metadata; adding a tag the checkpoint already has is a no-op.
+//
+// Returns ErrCheckpointNotFound if the checkpoint doesn't exist.
+func (s *GitStore) Tag(ctx context.Context, checkpointID id.CheckpointID, tags ...string) error {
+	for _, tag := range tags {
+		if err := ValidateTag(tag); err != nil {
+			return err
+		}
+	}
+	return s.updateTags(ctx, checkpointID, "Tag", func(existing []string) []string {
+		return append(existing, tags...)
+	})
+}
+
+// Untag removes tags from a committed checkpoint. Tags the checkpoint
+// doesn't have are ignored.
+//
+// Returns ErrCheckpointNotFound if the checkpoint doesn't exist.
+func (s *GitStore) Untag(ctx context.Context, checkpointID id.CheckpointID, tags ...string) error {
+	return s.updateTags(ctx, checkpointID, "Untag", func(existing []string) []string {
+		return slices.DeleteFunc(existing, func(tag string) bool { return slices.Contains(tags, tag) })
+	})
+}
+
+// ListByTag returns the committed checkpoints carrying tag, most recent first.
+func (s *GitStore) ListByTag(ctx context.Context, tag string) ([]CommittedInfo, error) {
+	page, err := s.ListCommittedPage(ctx, ListOptions{Tag: tag})
+	if err != nil {
+		return nil, err
+	}
+	return page.Checkpoints, nil
+}
+
+// updateTags rewrites the tags in a checkpoint's root metadata with
+// change, skipping the commit if the tags end up the same. verb starts the
+// commit message.
+func (s *GitStore) updateTags(ctx context.Context, checkpointID id.CheckpointID, verb string, change func([]string) []string) error {
+	if err := ctx.Err(); err != nil {
+		return err //nolint:wrapcheck // Propagating context cancellation
+	}
+
+	if err := s.ensureSessionsBranch(); err != nil {
+		return fmt.Errorf("failed to ensure sessions branch: %w", err)
+	}
+
+	return s.commitMetadata(ctx, func(_, rootTreeHash plumbing.Hash) (*metadataCommit, error) {
+		basePath := checkpointID.Path() + "/"
+		entries, err := s.flattenCheckpointEntries(rootTreeHash, checkpointID.Path())
+		if err != nil {
+			return nil, err
+		}
+
+		rootMetadataPath := basePath + paths.MetadataFileName
+		entry, exists := entries[rootMetadataPath]
+		if !exists {
+			return nil, ErrCheckpointNotFound
+		}
+		checkpointSummary, err := s.readSummaryFromBlob(entry.Hash)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read checkpoint summary: %w", err)
+		}
+
+		tags := change(slices.Clone(checkpointSummary.Tags))
+		slices.Sort(tags)
+		tags = slices.Compact(tags)
+		if slices.Equal(tags, checkpointSummary.Tags) {
+			return nil, nil //nolint:nilnil // nothing to commit
+		}
+		if len(tags) == 0 {
+			tags = nil
+		}
+		checkpointSummary.Tags = tags
+
+		metadataJSON, err := s.marshalUpdatedMetadata(checkpointSummary, entry.Hash)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal checkpoint summary: %w", err)
+		}
+		metadataHash, err := CreateBlobFromContent(s.repo, metadataJSON)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create metadata blob: %w", err)
+		}
+		entries[rootMetadataPath] = object.TreeEntry{
+			Name: rootMetadataPath,
+			Mode: filemode.Regular,
+			Hash: metadataHash,
+		}
+
+		newTreeHash, err := s.spliceCheckpointSubtree(rootTreeHash, checkpointID, basePath, entries)
+		if err != nil {
+			return nil, err
+		}
+		authorName, authorEmail := GetGitAuthorFromRepo(s.repo)
+		return &metadataCommit{
+			Tree:          newTreeHash,
+			Message:       fmt.Sprintf("%s checkpoint %s", verb, checkpointID),
+			AuthorName:    authorName,
+			AuthorEmail:   authorEmail,
+			CheckpointIDs: []id.CheckpointID{checkpointID},
+		}, nil
+	})
+}
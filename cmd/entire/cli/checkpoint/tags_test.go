@@ -0,0 +1,72 @@
+package checkpoint
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+)
+
+func TestTag_AddListRemove(t *testing.T) {
+	t.Parallel()
+	_, store, cpID := setupRepoForUpdate(t)
+	ctx := context.Background()
+
+	if err := store.Tag(ctx, cpID, "good-state", "before-refactor", "good-state"); err != nil {
+		t.Fatalf("Tag() error = %v", err)
+	}
+	summary, err := store.ReadCommitted(ctx, cpID)
+	if err != nil {
+		t.Fatalf("ReadCommitted() error = %v", err)
+	}
+	if want := []string{"before-refactor", "good-state"}; !slices.Equal(summary.Tags, want) {
+		t.Errorf("Tags = %v, want %v", summary.Tags, want)
+	}
+
+	tagged, err := store.ListByTag(ctx, "good-state")
+	if err != nil {
+		t.Fatalf("ListByTag() error = %v", err)
+	}
+	if len(tagged) != 1 || tagged[0].CheckpointID != cpID {
+		t.Errorf("ListByTag(good-state) = %v, want %s", tagged, cpID)
+	}
+
+	// A second session keeps the checkpoint's tags
+	if err := store.WriteCommitted(ctx, WriteCommittedOptions{
+		CheckpointID: cpID,
+		SessionID:    "session-002",
+		Strategy:     "manual-commit",
+		Transcript:   []byte("second session\n"),
+		AuthorName:   "Test",
+		AuthorEmail:  "test@test.com",
+	}); err != nil {
+		t.Fatalf("WriteCommitted() error = %v", err)
+	}
+
+	if err := store.Untag(ctx, cpID, "good-state", "never-added"); err != nil {
+		t.Fatalf("Untag() error = %v", err)
+	}
+	if tagged, err = store.ListByTag(ctx, "good-state"); err != nil || len(tagged) != 0 {
+		t.Errorf("ListByTag(good-state) after Untag = %v, %v, want none", tagged, err)
+	}
+	if tagged, err = store.ListByTag(ctx, "before-refactor"); err != nil || len(tagged) != 1 {
+		t.Errorf("ListByTag(before-refactor) = %v, %v, want the checkpoint", tagged, err)
+	}
+}
+
+func TestTag_Errors(t *testing.T) {
+	t.Parallel()
+	_, store, cpID := setupRepoForUpdate(t)
+	ctx := context.Background()
+
+	for _, tag := range []string{"", "two words", "a,b", "-flag"} {
+		if err := store.Tag(ctx, cpID, tag); err == nil {
+			t.Errorf("Tag(%q) succeeded, want an error", tag)
+		}
+	}
+	if err := store.Tag(ctx, id.MustCheckpointID("ffffffffffff"), "good-state"); !errors.Is(err, ErrCheckpointNotFound) {
+		t.Errorf("Tag() on a missing checkpoint error = %v, want ErrCheckpointNotFound", err)
+	}
+}
@@ -28,6 +28,7 @@ import (
 	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/filemode"
 	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/filesystem"
 )
 
 const (
@@ -587,7 +588,7 @@ func (s *GitStore) GetTranscriptFromCommit(ctx context.Context, commitHash plumb
 	subTree, subTreeErr := tree.Tree(metadataDir)
 	if subTreeErr == nil {
 		// Use the helper function that handles chunking
-		transcript, err := readTranscriptFromTree(ctx, subTree, agentType)
+		transcript, err := readTranscriptFromTree(ctx, subTree, agentType, "", nil)
 		if err == nil && transcript != nil {
 			return transcript, nil
 		}
@@ -628,6 +629,14 @@ func (s *GitStore) ShadowBranchExists(baseCommit, worktreeID string) bool {
 // persist deletions with packed refs or worktrees.
 func (s *GitStore) DeleteShadowBranch(ctx context.Context, baseCommit, worktreeID string) error {
 	shadowBranchName := ShadowBranchNameForCommit(baseCommit, worktreeID)
+	if _, onDisk := s.repo.Storer.(*filesystem.Storage); !onDisk {
+		// No packed refs or worktrees in memory, and the git CLI would
+		// delete the branch of whatever repository is in the working directory
+		if err := s.repo.Storer.RemoveReference(plumbing.NewBranchReferenceName(shadowBranchName)); err != nil {
+			return fmt.Errorf("failed to delete shadow branch %s: %w", shadowBranchName, err)
+		}
+		return nil
+	}
 	cmd := exec.CommandContext(ctx, "git", "branch", "-D", "--", shadowBranchName)
 	if output, err := cmd.CombinedOutput(); err != nil {
 		return fmt.Errorf("failed to delete shadow branch %s: %s: %w", shadowBranchName, strings.TrimSpace(string(output)), err)
@@ -0,0 +1,29 @@
+package checkpoint
+
+import "time"
+
+// TurnTiming records how long one agent turn took, from prompt submit to stop.
+// Tool and model times come from transcript timestamps and are zero when the
+// agent's transcript doesn't carry them.
+type TurnTiming struct {
+	TurnID    string    `json:"turn_id,omitempty"`
+	StartedAt time.Time `json:"started_at"`
+	EndedAt   time.Time `json:"ended_at"`
+	ToolMs    int64     `json:"tool_ms,omitempty"`
+	ModelMs   int64     `json:"model_ms,omitempty"`
+}
+
+// Duration returns the wall-clock length of the turn.
+func (t TurnTiming) Duration() time.Duration {
+	return t.EndedAt.Sub(t.StartedAt)
+}
+
+// ToolTime returns the time spent running tools during the turn.
+func (t TurnTiming) ToolTime() time.Duration {
+	return time.Duration(t.ToolMs) * time.Millisecond
+}
+
+// ModelTime returns the time spent waiting on model output during the turn.
+func (t TurnTiming) ModelTime() time.Duration {
+	return time.Duration(t.ModelMs) * time.Millisecond
+}
@@ -0,0 +1,237 @@
+package checkpoint
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/entireio/cli/cmd/entire/cli/agent"
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+	"github.com/entireio/cli/redact"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// AppendTranscript adds lines to the end of a committed checkpoint's
+// transcript without rewriting what is already stored. The chunk is stored
+// as its own blobs under the session's full.jsonl.appends/ directory, which
+// readers concatenate after the transcript, so a long session updated every
+// turn writes each line once instead of the whole transcript per turn.
+//
+// Only the end of the stored transcript is read, so an append costs the
+// size of the chunk rather than the transcript. content_hash.txt keeps
+// covering the transcript as last written; verify hashes the appended lines
+// with it. A later UpdateCommitted replaces the transcript and drops the
+// appended chunks.
+// Returns ErrSessionNotFound if no session in the checkpoint has
+// opts.SessionID: appending to another session's transcript would mix the
+// two.
+func (s *GitStore) AppendTranscript(ctx context.Context, opts AppendTranscriptOptions) error {
+	if opts.CheckpointID.IsEmpty() {
+		return errors.New("invalid append options: checkpoint ID is required")
+	}
+	if len(opts.Chunk) == 0 {
+		return nil
+	}
+
+	if err := s.ensureSessionsBranch(); err != nil {
+		return fmt.Errorf("failed to ensure sessions branch: %w", err)
+	}
+	return s.commitMetadata(ctx, func(_, rootTreeHash plumbing.Hash) (*metadataCommit, error) {
+		basePath := opts.CheckpointID.Path() + "/"
+		entries, err := s.flattenCheckpointEntries(rootTreeHash, opts.CheckpointID.Path())
+		if err != nil {
+			return nil, err
+		}
+		entry, exists := entries[basePath+paths.MetadataFileName]
+		if !exists {
+			return nil, ErrCheckpointNotFound
+		}
+		checkpointSummary, err := s.readSummaryFromBlob(entry.Hash)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read checkpoint summary: %w", err)
+		}
+		if len(checkpointSummary.Sessions) == 0 {
+			return nil, ErrCheckpointNotFound
+		}
+
+		sessionPath, err := s.sessionSlotPath(ctx, entries, basePath, len(checkpointSummary.Sessions), opts.CheckpointID, opts.SessionID, true)
+		if err != nil {
+			return nil, err
+		}
+		sessionMeta := &CommittedMetadata{}
+		if metaEntry, metaExists := entries[sessionPath+paths.MetadataFileName]; metaExists {
+			if meta, metaErr := s.readMetadataFromBlob(metaEntry.Hash); metaErr == nil {
+				sessionMeta = meta
+			}
+		}
+		// Honour the content level the checkpoint was written with
+		if !sessionMeta.ContentLevel.StoresTranscript() {
+			return nil, nil //nolint:nilnil // nothing to commit
+		}
+		if _, isPointer := entries[sessionPath+paths.TranscriptPointerFileName]; isPointer {
+			return nil, errors.New("transcript is stored outside git; it can't be appended to")
+		}
+
+		rootTree, err := s.repo.TreeObject(rootTreeHash)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read sessions tree: %w", err)
+		}
+		var tail []byte
+		if sessionTree, treeErr := rootTree.Tree(strings.TrimSuffix(sessionPath, "/")); treeErr == nil {
+			tail, err = readTranscriptTail(sessionTree, sessionMeta.TranscriptEncoding, s.decryptContent)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read transcript: %w", err)
+			}
+		}
+		if sessionMeta.Agent == agent.AgentTypeGemini || agent.DetectAgentTypeFromContent(tail) == agent.AgentTypeGemini {
+			return nil, errors.New("only JSONL transcripts can be appended to")
+		}
+
+		// Apply redaction as safety net (caller should redact, but we ensure it here)
+		chunk, err := redact.JSONLBytes(opts.Chunk)
+		if err != nil {
+			return nil, fmt.Errorf("failed to redact transcript secrets: %w", err)
+		}
+		if len(tail) > 0 && tail[len(tail)-1] != '\n' {
+			chunk = append([]byte{'\n'}, chunk...)
+		}
+
+		appendsPath := sessionPath + paths.TranscriptAppendsDirName + "/"
+		next := 0
+		for key := range entries {
+			if strings.HasPrefix(key, appendsPath) {
+				next++
+			}
+		}
+		for _, piece := range splitJSONLLines(chunk, agent.MaxChunkSize) {
+			next++
+			blobHash, err := s.createContentBlob(encodePayload(piece, sessionMeta.TranscriptEncoding), opts.EncryptTo)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create transcript blob: %w", err)
+			}
+			name := fmt.Sprintf("%s%06d", appendsPath, next)
+			entries[name] = object.TreeEntry{Name: name, Mode: filemode.Regular, Hash: blobHash}
+		}
+
+		newTreeHash, err := s.spliceCheckpointSubtree(rootTreeHash, opts.CheckpointID, basePath, entries)
+		if err != nil {
+			return nil, err
+		}
+		authorName, authorEmail := GetGitAuthorFromRepo(s.repo)
+		return &metadataCommit{
+			Tree:          newTreeHash,
+			Message:       fmt.Sprintf("Append transcript for Checkpoint: %s", opts.CheckpointID),
+			AuthorName:    authorName,
+			AuthorEmail:   authorEmail,
+			CheckpointIDs: []id.CheckpointID{opts.CheckpointID},
+		}, nil
+	})
+}
+
+// splitJSONLLines splits content into pieces of at most maxSize bytes at
+// line boundaries, keeping every byte so the pieces concatenate back to
+// content. A single line longer than maxSize becomes a piece of its own.
+func splitJSONLLines(content []byte, maxSize int) [][]byte {
+	var pieces [][]byte
+	for len(content) > maxSize {
+		cut := bytes.LastIndexByte(content[:maxSize], '\n') + 1
+		if cut == 0 {
+			cut = bytes.IndexByte(content, '\n') + 1
+			if cut == 0 {
+				break
+			}
+		}
+		pieces = append(pieces, content[:cut])
+		content = content[cut:]
+	}
+	if len(content) > 0 {
+		pieces = append(pieces, content)
+	}
+	return pieces
+}
+
+// readTranscriptTail reads the last piece of the transcript stored in a
+// session tree: the last appended chunk, or else the last transcript chunk.
+// Returns nil if the tree holds no transcript.
+func readTranscriptTail(tree *object.Tree, encoding string, decrypt func([]byte) ([]byte, error)) ([]byte, error) {
+	var name string
+	appendsTree, appendNames := transcriptAppendFiles(tree)
+	chunkFiles := transcriptChunkFiles(tree)
+	switch {
+	case len(appendNames) > 0:
+		tree, name = appendsTree, appendNames[len(appendNames)-1]
+	case len(chunkFiles) > 0:
+		name = chunkFiles[len(chunkFiles)-1]
+	default:
+		for _, candidate := range []string{paths.TranscriptFileName, paths.TranscriptFileNameLegacy} {
+			if _, err := tree.FindEntry(candidate); err == nil {
+				name = candidate
+				break
+			}
+		}
+	}
+	if name == "" {
+		return nil, nil
+	}
+
+	file, err := tree.File(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	content, err := file.Contents()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	if name == paths.TranscriptFileNameLegacy {
+		return []byte(content), nil // Legacy transcripts are stored as is
+	}
+	return decryptAndDecode([]byte(content), encoding, decrypt)
+}
+
+// readTranscriptAppends reads the chunks AppendTranscript stored in a
+// session tree and concatenates them in order. Returns nil if there are none.
+func readTranscriptAppends(tree *object.Tree, encoding string, decrypt func([]byte) ([]byte, error)) ([]byte, error) {
+	appendsTree, names := transcriptAppendFiles(tree)
+	var result []byte
+	for _, name := range names {
+		file, err := appendsTree.File(name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read appended transcript %s: %w", name, err)
+		}
+		content, err := file.Contents()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read appended transcript %s: %w", name, err)
+		}
+		piece, err := decryptAndDecode([]byte(content), encoding, decrypt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read appended transcript %s: %w", name, err)
+		}
+		result = append(result, piece...)
+	}
+	return result, nil
+}
+
+// transcriptAppendFiles returns the directory of a session tree that holds
+// the chunks AppendTranscript stored, and the chunks' names in order.
+// Returns a nil tree if there are none.
+func transcriptAppendFiles(tree *object.Tree) (*object.Tree, []string) {
+	appendsTree, err := tree.Tree(paths.TranscriptAppendsDirName)
+	if err != nil {
+		return nil, nil
+	}
+	names := make([]string, 0, len(appendsTree.Entries))
+	for _, entry := range appendsTree.Entries {
+		if entry.Mode == filemode.Regular {
+			names = append(names, entry.Name)
+		}
+	}
+	sort.Strings(names)
+	return appendsTree, names
+}
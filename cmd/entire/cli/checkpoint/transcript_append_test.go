@@ -0,0 +1,248 @@
+package checkpoint
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// appendTestAppendedFiles returns the names stored under the appends
+// directory of the checkpoint's first session.
+func appendTestAppendedFiles(t *testing.T, repo *git.Repository, cpID id.CheckpointID) []string {
+	t.Helper()
+	ref, err := repo.Reference(plumbing.NewBranchReferenceName(paths.MetadataBranchName), true)
+	if err != nil {
+		t.Fatalf("failed to get ref: %v", err)
+	}
+	commit, err := repo.CommitObject(ref.Hash())
+	if err != nil {
+		t.Fatalf("failed to get commit: %v", err)
+	}
+	tree, err := commit.Tree()
+	if err != nil {
+		t.Fatalf("failed to get tree: %v", err)
+	}
+	appendsTree, err := tree.Tree(cpID.Path() + "/0/" + paths.TranscriptAppendsDirName)
+	if err != nil {
+		return nil
+	}
+	var names []string
+	for _, entry := range appendsTree.Entries {
+		names = append(names, entry.Name)
+	}
+	return names
+}
+
+func TestAppendTranscript_ReadSessionContentConcatenates(t *testing.T) {
+	t.Parallel()
+	repo, store, cpID := setupRepoForUpdate(t)
+	ctx := context.Background()
+
+	for _, chunk := range []string{"appended line 2\n", "appended line 3\nappended line 4\n"} {
+		if err := store.AppendTranscript(ctx, AppendTranscriptOptions{
+			CheckpointID: cpID,
+			SessionID:    "session-001",
+			Chunk:        []byte(chunk),
+		}); err != nil {
+			t.Fatalf("AppendTranscript() error = %v", err)
+		}
+	}
+
+	content, err := store.ReadSessionContent(ctx, cpID, 0)
+	if err != nil {
+		t.Fatalf("ReadSessionContent() error = %v", err)
+	}
+	want := "provisional transcript line 1\nappended line 2\nappended line 3\nappended line 4\n"
+	if string(content.Transcript) != want {
+		t.Errorf("transcript mismatch\ngot:  %q\nwant: %q", content.Transcript, want)
+	}
+	if names := appendTestAppendedFiles(t, repo, cpID); strings.Join(names, ",") != "000001,000002" {
+		t.Errorf("appended files = %v, want [000001 000002]", names)
+	}
+
+	results, err := store.VerifyCommitted(ctx)
+	if err != nil {
+		t.Fatalf("VerifyCommitted() error = %v", err)
+	}
+	if len(results) != 1 || results[0].Status != VerifyStatusOK {
+		t.Errorf("VerifyCommitted() = %+v, want one ok checkpoint", results)
+	}
+}
+
+func TestAppendTranscript_KeepsContentHash(t *testing.T) {
+	t.Parallel()
+	_, store, cpID := setupRepoForUpdate(t)
+	ctx := context.Background()
+	contentHash := func() plumbing.Hash {
+		t.Helper()
+		_, rootTree, err := store.getSessionsBranchRef()
+		if err != nil {
+			t.Fatal(err)
+		}
+		entries, err := store.flattenCheckpointEntries(rootTree, cpID.Path())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return entries[cpID.Path()+"/0/"+paths.ContentHashFileName].Hash
+	}
+
+	before := contentHash()
+	if err := store.AppendTranscript(ctx, AppendTranscriptOptions{
+		CheckpointID: cpID,
+		SessionID:    "session-001",
+		Chunk:        []byte("appended line\n"),
+	}); err != nil {
+		t.Fatalf("AppendTranscript() error = %v", err)
+	}
+	if after := contentHash(); after != before {
+		t.Errorf("content_hash.txt changed on append: %s -> %s", before, after)
+	}
+
+	// Appends used to rehash the whole transcript; those hashes still verify
+	whole := fmt.Sprintf("sha256:%x", sha256.Sum256([]byte("provisional transcript line 1\nappended line\n")))
+	hashBlob, err := CreateBlobFromContent(store.repo, []byte(whole))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tamperCheckpoint(t, store, cpID, func(base string, entries map[string]object.TreeEntry) {
+		entry := entries[base+"0/"+paths.ContentHashFileName]
+		entry.Hash = hashBlob
+		entries[base+"0/"+paths.ContentHashFileName] = entry
+	})
+	results, err := store.VerifyCommitted(ctx)
+	if err != nil {
+		t.Fatalf("VerifyCommitted() error = %v", err)
+	}
+	if len(results) != 1 || results[0].Status != VerifyStatusOK {
+		t.Errorf("VerifyCommitted() = %+v, want one ok checkpoint", results)
+	}
+}
+
+func TestAppendTranscript_UpdateCommittedDropsAppends(t *testing.T) {
+	t.Parallel()
+	repo, store, cpID := setupRepoForUpdate(t)
+	ctx := context.Background()
+
+	if err := store.AppendTranscript(ctx, AppendTranscriptOptions{
+		CheckpointID: cpID,
+		SessionID:    "session-001",
+		Chunk:        []byte("appended line\n"),
+	}); err != nil {
+		t.Fatalf("AppendTranscript() error = %v", err)
+	}
+	replacement := []byte("replaced transcript\n")
+	if err := store.UpdateCommitted(ctx, UpdateCommittedOptions{
+		CheckpointID: cpID,
+		SessionID:    "session-001",
+		Transcript:   replacement,
+	}); err != nil {
+		t.Fatalf("UpdateCommitted() error = %v", err)
+	}
+
+	content, err := store.ReadSessionContent(ctx, cpID, 0)
+	if err != nil {
+		t.Fatalf("ReadSessionContent() error = %v", err)
+	}
+	if !bytes.Equal(content.Transcript, replacement) {
+		t.Errorf("transcript = %q, want %q", content.Transcript, replacement)
+	}
+	if names := appendTestAppendedFiles(t, repo, cpID); len(names) != 0 {
+		t.Errorf("appended files survived the replace: %v", names)
+	}
+}
+
+func TestAppendTranscript_TerminatesUnfinishedLine(t *testing.T) {
+	t.Parallel()
+	_, store, _ := setupRepoForUpdate(t)
+	ctx := context.Background()
+	cpID := id.MustCheckpointID("b1b2c3d4e5f6")
+
+	if err := store.WriteCommitted(ctx, WriteCommittedOptions{
+		CheckpointID: cpID,
+		SessionID:    "session-002",
+		Strategy:     "manual-commit",
+		Transcript:   []byte("no trailing newline"),
+		AuthorName:   "Test",
+		AuthorEmail:  "test@test.com",
+	}); err != nil {
+		t.Fatalf("WriteCommitted() error = %v", err)
+	}
+	if err := store.AppendTranscript(ctx, AppendTranscriptOptions{
+		CheckpointID: cpID,
+		SessionID:    "session-002",
+		Chunk:        []byte("next line\n"),
+	}); err != nil {
+		t.Fatalf("AppendTranscript() error = %v", err)
+	}
+
+	content, err := store.ReadSessionContent(ctx, cpID, 0)
+	if err != nil {
+		t.Fatalf("ReadSessionContent() error = %v", err)
+	}
+	if want := "no trailing newline\nnext line\n"; string(content.Transcript) != want {
+		t.Errorf("transcript = %q, want %q", content.Transcript, want)
+	}
+}
+
+func TestAppendTranscript_NonexistentCheckpoint(t *testing.T) {
+	t.Parallel()
+	_, store, _ := setupRepoForUpdate(t)
+
+	err := store.AppendTranscript(context.Background(), AppendTranscriptOptions{
+		CheckpointID: id.MustCheckpointID("ffffffffffff"),
+		SessionID:    "session-001",
+		Chunk:        []byte("line\n"),
+	})
+	if !errors.Is(err, ErrCheckpointNotFound) {
+		t.Errorf("AppendTranscript() error = %v, want ErrCheckpointNotFound", err)
+	}
+}
+
+func TestAppendTranscript_UnknownSession(t *testing.T) {
+	t.Parallel()
+	_, store, cpID := setupRepoForUpdate(t)
+	ctx := context.Background()
+
+	err := store.AppendTranscript(ctx, AppendTranscriptOptions{
+		CheckpointID: cpID,
+		SessionID:    "session-other",
+		Chunk:        []byte("wrong session\n"),
+	})
+	if !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("AppendTranscript() error = %v, want ErrSessionNotFound", err)
+	}
+
+	content, err := store.ReadSessionContent(ctx, cpID, 0)
+	if err != nil {
+		t.Fatalf("ReadSessionContent() error = %v", err)
+	}
+	if strings.Contains(string(content.Transcript), "wrong session") {
+		t.Error("the chunk was appended to another session's transcript")
+	}
+}
+
+func TestSplitJSONLLines(t *testing.T) {
+	t.Parallel()
+	content := []byte("aaaa\nbb\ncccccccc\nd")
+	pieces := splitJSONLLines(content, 6)
+	want := []string{"aaaa\n", "bb\n", "cccccccc\n", "d"}
+	if len(pieces) != len(want) {
+		t.Fatalf("splitJSONLLines() = %q, want %q", pieces, want)
+	}
+	for i := range want {
+		if string(pieces[i]) != want[i] {
+			t.Errorf("piece %d = %q, want %q", i, pieces[i], want[i])
+		}
+	}
+}
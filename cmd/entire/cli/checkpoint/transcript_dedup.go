@@ -0,0 +1,184 @@
+package checkpoint
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/entireio/cli/cmd/entire/cli/age"
+	"github.com/entireio/cli/cmd/entire/cli/agent/types"
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// transcriptDedupDepth is how many commits back from the metadata branch tip
+// storedTranscriptChunks looks for a transcript to share. The same
+// transcript is written to checkpoints close together, by the commits of
+// one session, so looking further back rarely finds anything.
+const transcriptDedupDepth = 16
+
+// recipientsFingerprint identifies a set of age recipients independent of
+// their order, or returns "" for none.
+func recipientsFingerprint(recipients []*age.Recipient) string {
+	if len(recipients) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(recipients))
+	for _, r := range recipients {
+		keys = append(keys, r.String())
+	}
+	slices.Sort(keys)
+	return fmt.Sprintf("sha256:%x", sha256.Sum256([]byte(strings.Join(keys, "\n"))))
+}
+
+// storedTranscriptChunks returns the chunk blobs of a session on the
+// metadata branch whose transcript has contentHash and was stored for the
+// same agent, encoding and recipients, or nil. Only the checkpoints changed
+// by the last transcriptDedupDepth commits are searched.
+//
+// Plain content is deduplicated by git anyway; this matters for encrypted
+// transcripts, whose blobs differ each time they are encrypted.
+func (s *GitStore) storedTranscriptChunks(contentHash string, agentType types.AgentType, encoding string, recipients []*age.Recipient) []plumbing.Hash {
+	if len(recipients) == 0 {
+		return nil
+	}
+	ref, err := s.repo.Reference(plumbing.NewBranchReferenceName(paths.MetadataBranchName), true)
+	if err != nil {
+		return nil
+	}
+	want := sessionTranscriptKey{
+		hashBlob:   plumbing.ComputeHash(plumbing.BlobObject, []byte(contentHash)),
+		agent:      agentType,
+		encoding:   encoding,
+		recipients: recipientsFingerprint(recipients),
+	}
+
+	commit, err := s.repo.CommitObject(ref.Hash())
+	for i := 0; err == nil && i < transcriptDedupDepth; i++ {
+		var parent *object.Commit
+		if commit.NumParents() > 0 {
+			parent, err = commit.Parent(0)
+			if err != nil {
+				return nil
+			}
+		}
+		for _, cpTree := range s.changedCheckpointTrees(commit, parent) {
+			if chunks := s.matchingTranscriptChunks(cpTree, want); chunks != nil {
+				return chunks
+			}
+		}
+		if parent == nil {
+			return nil
+		}
+		commit = parent
+	}
+	return nil
+}
+
+// sessionTranscriptKey is what must match for a session's transcript chunks
+// to be shared: the content_hash.txt blob, and everything that changes the
+// chunk blobs.
+type sessionTranscriptKey struct {
+	hashBlob   plumbing.Hash
+	agent      types.AgentType
+	encoding   string
+	recipients string
+}
+
+// changedCheckpointTrees returns the trees of the checkpoints commit adds or
+// changes relative to parent, which is nil for the root commit.
+func (s *GitStore) changedCheckpointTrees(commit, parent *object.Commit) []*object.Tree {
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil
+	}
+	var parentTree *object.Tree
+	if parent != nil {
+		if parentTree, err = parent.Tree(); err != nil {
+			return nil
+		}
+	}
+
+	var changed []*object.Tree
+	for _, shard := range tree.Entries {
+		if len(shard.Name) != 2 || shard.Mode != filemode.Dir {
+			continue
+		}
+		var parentShard *object.Tree
+		if parentTree != nil {
+			if prev, err := parentTree.FindEntry(shard.Name); err == nil {
+				if prev.Hash == shard.Hash {
+					continue
+				}
+				parentShard, _ = s.repo.TreeObject(prev.Hash) //nolint:errcheck // a missing parent shard means every checkpoint changed
+			}
+		}
+		shardTree, err := s.repo.TreeObject(shard.Hash)
+		if err != nil {
+			continue
+		}
+		for _, cp := range shardTree.Entries {
+			if cp.Mode != filemode.Dir {
+				continue
+			}
+			if parentShard != nil {
+				if prev, err := parentShard.FindEntry(cp.Name); err == nil && prev.Hash == cp.Hash {
+					continue
+				}
+			}
+			if cpTree, err := s.repo.TreeObject(cp.Hash); err == nil {
+				changed = append(changed, cpTree)
+			}
+		}
+	}
+	return changed
+}
+
+// matchingTranscriptChunks returns the chunk blobs of the first session in
+// cpTree whose transcript matches want, or nil.
+func (s *GitStore) matchingTranscriptChunks(cpTree *object.Tree, want sessionTranscriptKey) []plumbing.Hash {
+	for _, entry := range cpTree.Entries {
+		if entry.Mode != filemode.Dir {
+			continue
+		}
+		sessionTree, err := s.repo.TreeObject(entry.Hash)
+		if err != nil {
+			continue
+		}
+		hashEntry, err := sessionTree.FindEntry(paths.ContentHashFileName)
+		if err != nil || hashEntry.Hash != want.hashBlob {
+			continue
+		}
+		metaEntry, err := sessionTree.FindEntry(paths.MetadataFileName)
+		if err != nil {
+			continue
+		}
+		meta, err := s.readMetadataFromBlob(metaEntry.Hash)
+		if err != nil || meta.Agent != want.agent || meta.TranscriptEncoding != want.encoding || meta.TranscriptRecipients != want.recipients {
+			continue
+		}
+
+		files := transcriptChunkFiles(sessionTree)
+		if files == nil {
+			files = []string{paths.TranscriptFileName}
+		}
+		chunks := make([]plumbing.Hash, 0, len(files))
+		for _, name := range files {
+			chunk, err := sessionTree.FindEntry(name)
+			// A partial clone may not have the blob even though the tree names it
+			if err != nil || s.repo.Storer.HasEncodedObject(chunk.Hash) != nil {
+				chunks = nil
+				break
+			}
+			chunks = append(chunks, chunk.Hash)
+		}
+		if chunks != nil {
+			return chunks
+		}
+	}
+	return nil
+}
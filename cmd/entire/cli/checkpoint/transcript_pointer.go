@@ -0,0 +1,221 @@
+package checkpoint
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/entireio/cli/cmd/entire/cli/logging"
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+	"github.com/entireio/cli/redact"
+
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// ErrTranscriptUnavailable is returned when a transcript pointer can't be
+// resolved: the agent's file moved or changed, and no uploaded copy exists.
+var ErrTranscriptUnavailable = errors.New("transcript is no longer available")
+
+// TranscriptPointer is stored in place of the transcript when checkpoints keep
+// transcripts out of the repository. The agent's file only grows while the
+// session lives, so the checkpoint's transcript is its first Size bytes.
+type TranscriptPointer struct {
+	// Path is the agent's transcript file.
+	Path string `json:"path"`
+
+	// Size is the number of bytes of the file that belong to the checkpoint.
+	Size int64 `json:"size"`
+
+	// SHA256 is the hex digest of those bytes as written by the agent.
+	SHA256 string `json:"sha256"`
+
+	// URL is an uploaded, redacted copy of the transcript, if any.
+	URL string `json:"url,omitempty"`
+}
+
+// TranscriptPointerOptions selects pointer storage for a checkpoint's transcript.
+type TranscriptPointerOptions struct {
+	// Path is the agent's transcript file the pointer refers to.
+	Path string
+
+	// Blobs receives a redacted copy of the transcript. Nil uploads nothing.
+	Blobs BlobStore
+}
+
+// BlobStore keeps transcript copies outside the repository.
+type BlobStore interface {
+	// Put stores data under key and returns the URL it can be fetched from.
+	Put(ctx context.Context, key string, data []byte) (string, error)
+}
+
+// HTTPBlobStore uploads blobs with PUT <BaseURL>/<key>.
+type HTTPBlobStore struct {
+	BaseURL string
+	Client  *http.Client
+}
+
+const blobTimeout = 60 * time.Second
+
+// NewHTTPBlobStore creates a blob store for baseURL.
+func NewHTTPBlobStore(baseURL string) *HTTPBlobStore {
+	return &HTTPBlobStore{
+		BaseURL: strings.TrimSuffix(baseURL, "/"),
+		Client:  &http.Client{Timeout: blobTimeout},
+	}
+}
+
+// Put uploads data and returns its URL.
+func (b *HTTPBlobStore) Put(ctx context.Context, key string, data []byte) (string, error) {
+	url := b.BaseURL + "/" + key
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, url, bytes.NewReader(data))
+	if err != nil {
+		return "", fmt.Errorf("failed to create upload request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	resp, err := b.Client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to upload transcript: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", fmt.Errorf("failed to upload transcript: %s", resp.Status)
+	}
+	return url, nil
+}
+
+// writeTranscriptPointer stores a pointer to the agent's transcript file and
+// the content hash of its redacted form, uploading a redacted copy if a blob
+// store is configured. Returns false if the file can't be read, so the caller
+// can fall back to storing a copy.
+func (s *GitStore) writeTranscriptPointer(ctx context.Context, opts *TranscriptPointerOptions, sessionPath string, entries map[string]object.TreeEntry) (bool, error) {
+	raw, err := os.ReadFile(opts.Path)
+	if err != nil || len(raw) == 0 {
+		return false, nil //nolint:nilerr // caller falls back to copying the in-memory transcript
+	}
+	redacted, err := redact.JSONLBytes(raw)
+	if err != nil {
+		return false, fmt.Errorf("failed to redact transcript secrets: %w", err)
+	}
+	redactedSum := sha256.Sum256(redacted)
+	rawSum := sha256.Sum256(raw)
+
+	pointer := TranscriptPointer{
+		Path:   opts.Path,
+		Size:   int64(len(raw)),
+		SHA256: hex.EncodeToString(rawSum[:]),
+	}
+	if opts.Blobs != nil {
+		url, putErr := opts.Blobs.Put(ctx, "sha256-"+hex.EncodeToString(redactedSum[:]), redacted)
+		if putErr != nil {
+			// The pointer still resolves on this machine
+			logging.Warn(ctx, "failed to upload transcript blob",
+				slog.String("error", putErr.Error()))
+		} else {
+			pointer.URL = url
+		}
+	}
+
+	// Replace whatever transcript was stored before
+	transcriptBase := sessionPath + paths.TranscriptFileName
+	for key := range entries {
+		if key == transcriptBase || strings.HasPrefix(key, transcriptBase+".") {
+			delete(entries, key)
+		}
+	}
+
+	pointerJSON, err := json.MarshalIndent(pointer, "", "  ")
+	if err != nil {
+		return false, fmt.Errorf("failed to encode transcript pointer: %w", err)
+	}
+	for name, content := range map[string][]byte{
+		paths.TranscriptPointerFileName: pointerJSON,
+		paths.ContentHashFileName:       []byte(fmt.Sprintf("sha256:%x", redactedSum)),
+	} {
+		blobHash, err := CreateBlobFromContent(s.repo, content)
+		if err != nil {
+			return false, err
+		}
+		entries[sessionPath+name] = object.TreeEntry{
+			Name: sessionPath + name,
+			Mode: filemode.Regular,
+			Hash: blobHash,
+		}
+	}
+	return true, nil
+}
+
+// resolveTranscriptPointer reads the transcript a pointer in tree refers to,
+// from the agent's file if it still holds the same content, otherwise from the
+// uploaded copy. The result is redacted, like stored transcripts.
+func resolveTranscriptPointer(ctx context.Context, tree *object.Tree) ([]byte, error) {
+	file, err := tree.File(paths.TranscriptPointerFileName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read transcript pointer: %w", err)
+	}
+	content, err := file.Contents()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read transcript pointer: %w", err)
+	}
+	var pointer TranscriptPointer
+	if err := json.Unmarshal([]byte(content), &pointer); err != nil {
+		return nil, fmt.Errorf("failed to parse transcript pointer: %w", err)
+	}
+
+	if raw, readErr := os.ReadFile(pointer.Path); readErr == nil && int64(len(raw)) >= pointer.Size {
+		raw = raw[:pointer.Size]
+		if sum := sha256.Sum256(raw); hex.EncodeToString(sum[:]) == pointer.SHA256 {
+			redacted, err := redact.JSONLBytes(raw)
+			if err != nil {
+				return nil, fmt.Errorf("failed to redact transcript secrets: %w", err)
+			}
+			return redacted, nil
+		}
+	}
+
+	if pointer.URL == "" {
+		return nil, fmt.Errorf("%w: %s changed or was removed", ErrTranscriptUnavailable, pointer.Path)
+	}
+	data, err := fetchTranscriptBlob(ctx, pointer.URL)
+	if err != nil {
+		return nil, err
+	}
+	if hashFile, hashErr := tree.File(paths.ContentHashFileName); hashErr == nil {
+		if want, contentErr := hashFile.Contents(); contentErr == nil && want != fmt.Sprintf("sha256:%x", sha256.Sum256(data)) {
+			return nil, fmt.Errorf("%w: uploaded copy at %s doesn't match the checkpoint", ErrTranscriptUnavailable, pointer.URL)
+		}
+	}
+	return data, nil
+}
+
+func fetchTranscriptBlob(ctx context.Context, url string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, blobTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create transcript request: %w", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch transcript: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: fetching %s: %s", ErrTranscriptUnavailable, url, resp.Status)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch transcript: %w", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,186 @@
+package checkpoint
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+)
+
+const pointerTranscript = `{"type":"user","message":{"content":"add a flag"}}` + "\n"
+
+// memoryBlobServer is an HTTP blob store that keeps uploads in memory.
+func memoryBlobServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	var mu sync.Mutex
+	blobs := make(map[string][]byte)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.Method {
+		case http.MethodPut:
+			data, err := io.ReadAll(r.Body)
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			blobs[r.URL.Path] = data
+			w.WriteHeader(http.StatusCreated)
+		case http.MethodGet:
+			data, ok := blobs[r.URL.Path]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = w.Write(data) //nolint:errcheck // test server
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func writePointerCheckpoint(t *testing.T, store *GitStore, cpID id.CheckpointID, transcriptPath string, blobs BlobStore) {
+	t.Helper()
+	err := store.WriteCommitted(context.Background(), WriteCommittedOptions{
+		CheckpointID:      cpID,
+		SessionID:         "session-pointer",
+		Strategy:          "manual-commit",
+		Transcript:        []byte(pointerTranscript),
+		TranscriptPointer: &TranscriptPointerOptions{Path: transcriptPath, Blobs: blobs},
+		AuthorName:        "Test",
+		AuthorEmail:       "test@test.com",
+	})
+	if err != nil {
+		t.Fatalf("WriteCommitted() error = %v", err)
+	}
+}
+
+func readPointerTranscript(t *testing.T, store *GitStore, cpID id.CheckpointID) string {
+	t.Helper()
+	content, err := store.ReadSessionContent(context.Background(), cpID, 0)
+	if err != nil {
+		t.Fatalf("ReadSessionContent() error = %v", err)
+	}
+	return string(content.Transcript)
+}
+
+func TestWriteCommitted_TranscriptPointer(t *testing.T) {
+	t.Parallel()
+	_, store, _ := setupRepoForUpdate(t)
+	cpID := id.MustCheckpointID("b1b2b3b4b5b6")
+	transcriptPath := filepath.Join(t.TempDir(), "session.jsonl")
+	if err := os.WriteFile(transcriptPath, []byte(pointerTranscript), 0o600); err != nil {
+		t.Fatalf("failed to write transcript: %v", err)
+	}
+
+	writePointerCheckpoint(t, store, cpID, transcriptPath, nil)
+
+	summary, err := store.ReadCommitted(context.Background(), cpID)
+	if err != nil {
+		t.Fatalf("ReadCommitted() error = %v", err)
+	}
+	if got := summary.Sessions[0].Transcript; !strings.HasSuffix(got, paths.TranscriptPointerFileName) {
+		t.Errorf("session transcript path = %q, want the pointer file", got)
+	}
+	if got := readPointerTranscript(t, store, cpID); got != pointerTranscript {
+		t.Errorf("transcript = %q, want it resolved from the agent's file", got)
+	}
+
+	// The agent keeps appending: the checkpoint still reads its own portion
+	f, err := os.OpenFile(transcriptPath, os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		t.Fatalf("failed to open transcript: %v", err)
+	}
+	if _, err := f.WriteString(`{"type":"user","message":{"content":"next prompt"}}` + "\n"); err != nil {
+		t.Fatalf("failed to append to transcript: %v", err)
+	}
+	f.Close()
+	if got := readPointerTranscript(t, store, cpID); got != pointerTranscript {
+		t.Errorf("transcript after append = %q, want the checkpoint's portion only", got)
+	}
+
+	// A rewritten file no longer matches the pointer
+	if err := os.WriteFile(transcriptPath, []byte(`{"type":"user","message":{"content":"something else entirely"}}`+"\n"), 0o600); err != nil {
+		t.Fatalf("failed to rewrite transcript: %v", err)
+	}
+	if got := readPointerTranscript(t, store, cpID); got != "" {
+		t.Errorf("transcript = %q, want nothing once the file changed", got)
+	}
+}
+
+func TestWriteCommitted_TranscriptPointerFallsBackToUploadedCopy(t *testing.T) {
+	t.Parallel()
+	_, store, _ := setupRepoForUpdate(t)
+	cpID := id.MustCheckpointID("c1c2c3c4c5c6")
+	transcriptPath := filepath.Join(t.TempDir(), "session.jsonl")
+	if err := os.WriteFile(transcriptPath, []byte(pointerTranscript), 0o600); err != nil {
+		t.Fatalf("failed to write transcript: %v", err)
+	}
+	server := memoryBlobServer(t)
+
+	writePointerCheckpoint(t, store, cpID, transcriptPath, NewHTTPBlobStore(server.URL+"/transcripts/"))
+	if err := os.Remove(transcriptPath); err != nil {
+		t.Fatalf("failed to remove transcript: %v", err)
+	}
+
+	if got := readPointerTranscript(t, store, cpID); got != pointerTranscript {
+		t.Errorf("transcript = %q, want it fetched from the uploaded copy", got)
+	}
+}
+
+func TestWriteCommitted_TranscriptPointerMissingFileCopies(t *testing.T) {
+	t.Parallel()
+	_, store, _ := setupRepoForUpdate(t)
+	cpID := id.MustCheckpointID("d1d2d3d4d5d6")
+
+	writePointerCheckpoint(t, store, cpID, filepath.Join(t.TempDir(), "missing.jsonl"), nil)
+
+	summary, err := store.ReadCommitted(context.Background(), cpID)
+	if err != nil {
+		t.Fatalf("ReadCommitted() error = %v", err)
+	}
+	if got := summary.Sessions[0].Transcript; !strings.HasSuffix(got, paths.TranscriptFileName) {
+		t.Errorf("session transcript path = %q, want a copied transcript", got)
+	}
+	if got := readPointerTranscript(t, store, cpID); got != pointerTranscript {
+		t.Errorf("transcript = %q, want the in-memory transcript", got)
+	}
+}
+
+func TestUpdateCommitted_TranscriptPointer(t *testing.T) {
+	t.Parallel()
+	_, store, cpID := setupRepoForUpdate(t)
+	transcriptPath := filepath.Join(t.TempDir(), "session.jsonl")
+	full := "provisional transcript line 1\nfinal line 2\n"
+	if err := os.WriteFile(transcriptPath, []byte(full), 0o600); err != nil {
+		t.Fatalf("failed to write transcript: %v", err)
+	}
+
+	err := store.UpdateCommitted(context.Background(), UpdateCommittedOptions{
+		CheckpointID:      cpID,
+		SessionID:         "session-001",
+		Transcript:        []byte(full),
+		TranscriptPointer: &TranscriptPointerOptions{Path: transcriptPath},
+	})
+	if err != nil {
+		t.Fatalf("UpdateCommitted() error = %v", err)
+	}
+
+	if got := readPointerTranscript(t, store, cpID); got != full {
+		t.Errorf("transcript = %q, want %q", got, full)
+	}
+	if err := os.Remove(transcriptPath); err != nil {
+		t.Fatalf("failed to remove transcript: %v", err)
+	}
+	if got := readPointerTranscript(t, store, cpID); got != "" {
+		t.Errorf("transcript = %q, want the copy replaced by the pointer", got)
+	}
+}
@@ -0,0 +1,205 @@
+package checkpoint
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/entireio/cli/cmd/entire/cli/age"
+	"github.com/entireio/cli/cmd/entire/cli/agent"
+	"github.com/entireio/cli/cmd/entire/cli/agent/types"
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/klauspost/compress/zstd"
+)
+
+// OpenTranscript opens a session's transcript for reading without loading it
+// into memory: transcript chunks and appended lines are streamed from their
+// blobs and decompressed as they are read. The result matches the Transcript
+// ReadSessionContent returns. sessionIndex is 0-based. The caller must close
+// the reader.
+//
+// Transcripts that can't be streamed are read into memory first: those in
+// pointer mode, chunked transcripts of agents whose chunks aren't JSONL, and
+// encrypted chunks, which are decrypted one chunk at a time.
+func (s *GitStore) OpenTranscript(ctx context.Context, checkpointID id.CheckpointID, sessionIndex int) (io.ReadCloser, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err //nolint:wrapcheck // Propagating context cancellation
+	}
+
+	tree, err := s.getSessionsBranchTree()
+	if err != nil {
+		return nil, ErrCheckpointNotFound
+	}
+	checkpointTree, err := tree.Tree(checkpointID.Path())
+	if err != nil {
+		return nil, ErrCheckpointNotFound
+	}
+	sessionTree, err := checkpointTree.Tree(strconv.Itoa(sessionIndex))
+	if err != nil {
+		return nil, fmt.Errorf("session %d not found: %w", sessionIndex, err)
+	}
+
+	var metadata CommittedMetadata
+	if metadataFile, fileErr := sessionTree.File(paths.MetadataFileName); fileErr == nil {
+		if content, contentErr := metadataFile.Contents(); contentErr == nil {
+			_ = json.Unmarshal([]byte(content), &metadata) //nolint:errcheck // Same as ReadSessionContent: metadata is best-effort
+		}
+	}
+
+	chunkFiles := transcriptChunkFiles(sessionTree)
+	if hasTreeFile(sessionTree, paths.TranscriptPointerFileName) || (len(chunkFiles) > 1 && !isJSONLTranscript(metadata.Agent)) {
+		transcript, err := readTranscriptFromTree(ctx, sessionTree, metadata.Agent, metadata.TranscriptEncoding, s.decryptContent)
+		if err != nil {
+			return nil, fmt.Errorf("checkpoint %s: %w", checkpointID, err)
+		}
+		return io.NopCloser(bytes.NewReader(transcript)), nil
+	}
+
+	var parts []func() (io.ReadCloser, error)
+	switch {
+	case len(chunkFiles) > 0:
+		for i, name := range chunkFiles {
+			if i > 0 {
+				// JSONL chunks are joined with newlines, as agent.ReassembleJSONL does
+				parts = append(parts, func() (io.ReadCloser, error) {
+					return io.NopCloser(strings.NewReader("\n")), nil
+				})
+			}
+			parts = append(parts, s.transcriptFileOpener(sessionTree, name, metadata.TranscriptEncoding))
+		}
+	case hasTreeFile(sessionTree, paths.TranscriptFileName):
+		parts = append(parts, s.transcriptFileOpener(sessionTree, paths.TranscriptFileName, metadata.TranscriptEncoding))
+	case hasTreeFile(sessionTree, paths.TranscriptFileNameLegacy):
+		parts = append(parts, s.transcriptFileOpener(sessionTree, paths.TranscriptFileNameLegacy, ""))
+	}
+	if appendsTree, names := transcriptAppendFiles(sessionTree); appendsTree != nil {
+		for _, name := range names {
+			parts = append(parts, s.transcriptFileOpener(appendsTree, name, metadata.TranscriptEncoding))
+		}
+	}
+	return &transcriptReader{parts: parts}, nil
+}
+
+// isJSONLTranscript reports whether transcript chunks of agentType are
+// reassembled by joining them with newlines. Gemini CLI and OpenCode store a
+// JSON document per chunk and merge the chunks instead.
+func isJSONLTranscript(agentType types.AgentType) bool {
+	return agentType != agent.AgentTypeGemini && agentType != agent.AgentTypeOpenCode
+}
+
+func hasTreeFile(tree *object.Tree, name string) bool {
+	_, err := tree.FindEntry(name)
+	return err == nil
+}
+
+// transcriptFileOpener returns a function that opens a stored transcript
+// file for streaming, decrypting and decompressing it as needed.
+func (s *GitStore) transcriptFileOpener(tree *object.Tree, name, encoding string) func() (io.ReadCloser, error) {
+	return func() (io.ReadCloser, error) {
+		file, err := tree.File(name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read transcript file %s: %w", name, err)
+		}
+		blob, err := file.Reader()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read transcript file %s: %w", name, err)
+		}
+		buffered := bufio.NewReader(blob)
+
+		// age has no streaming decrypter; a chunk is at most agent.MaxChunkSize
+		header, _ := buffered.Peek(len("age-encryption.org/v1\n")) //nolint:errcheck // A short file just isn't encrypted
+		if age.IsEncrypted(header) {
+			defer blob.Close()
+			content, err := io.ReadAll(buffered)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read transcript file %s: %w", name, err)
+			}
+			decoded, err := decryptAndDecode(content, encoding, s.decryptContent)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read transcript file %s: %w", name, err)
+			}
+			return io.NopCloser(bytes.NewReader(decoded)), nil
+		}
+
+		switch encoding {
+		case "":
+			return readCloser{Reader: buffered, close: blob.Close}, nil
+		case EncodingZstd:
+			dec, err := zstd.NewReader(buffered, zstd.WithDecoderConcurrency(1), zstd.WithDecoderMaxMemory(maxDecompressedBytes))
+			if err != nil {
+				_ = blob.Close()
+				return nil, fmt.Errorf("failed to decompress transcript file %s: %w", name, err)
+			}
+			return readCloser{Reader: dec, close: func() error {
+				dec.Close()
+				return blob.Close()
+			}}, nil
+		default:
+			_ = blob.Close()
+			return nil, fmt.Errorf("failed to read transcript file %s: unsupported encoding %q", name, encoding)
+		}
+	}
+}
+
+type readCloser struct {
+	io.Reader
+	close func() error
+}
+
+func (r readCloser) Close() error {
+	return r.close()
+}
+
+// transcriptReader reads a sequence of transcript parts one after the
+// other, opening each only when the previous one is exhausted.
+type transcriptReader struct {
+	parts   []func() (io.ReadCloser, error)
+	current io.ReadCloser
+}
+
+func (r *transcriptReader) Read(p []byte) (int, error) {
+	for {
+		if r.current == nil {
+			if len(r.parts) == 0 {
+				return 0, io.EOF
+			}
+			next, err := r.parts[0]()
+			if err != nil {
+				return 0, err
+			}
+			r.parts = r.parts[1:]
+			r.current = next
+		}
+		n, err := r.current.Read(p)
+		if err == io.EOF { //nolint:errorlint // io.Reader returns io.EOF unwrapped
+			closeErr := r.current.Close()
+			r.current = nil
+			if closeErr != nil {
+				return n, fmt.Errorf("failed to close transcript file: %w", closeErr)
+			}
+			if n > 0 {
+				return n, nil
+			}
+			continue
+		}
+		return n, err //nolint:wrapcheck // Passing through the part's reader
+	}
+}
+
+func (r *transcriptReader) Close() error {
+	r.parts = nil
+	if r.current == nil {
+		return nil
+	}
+	err := r.current.Close()
+	r.current = nil
+	return err
+}
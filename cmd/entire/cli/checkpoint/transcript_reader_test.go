@@ -0,0 +1,115 @@
+package checkpoint
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+)
+
+func readOpenTranscript(t *testing.T, store *GitStore, cpID id.CheckpointID, sessionIndex int) []byte {
+	t.Helper()
+	reader, err := store.OpenTranscript(context.Background(), cpID, sessionIndex)
+	if err != nil {
+		t.Fatalf("OpenTranscript() error = %v", err)
+	}
+	defer reader.Close()
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("reading transcript: %v", err)
+	}
+	return data
+}
+
+func TestOpenTranscript_MatchesReadSessionContent(t *testing.T) {
+	t.Parallel()
+	_, store, _ := setupRepoForUpdate(t)
+	ctx := context.Background()
+	cpID := id.MustCheckpointID("c1c2c3d4e5f6")
+
+	// Large enough to be stored compressed
+	if err := store.WriteCommitted(ctx, WriteCommittedOptions{
+		CheckpointID: cpID,
+		SessionID:    "session-002",
+		Strategy:     "manual-commit",
+		Transcript:   largeTranscript(2 * CompressMinBytes),
+		AuthorName:   "Test",
+		AuthorEmail:  "test@test.com",
+	}); err != nil {
+		t.Fatalf("WriteCommitted() error = %v", err)
+	}
+	if err := store.AppendTranscript(ctx, AppendTranscriptOptions{
+		CheckpointID: cpID,
+		SessionID:    "session-002",
+		Chunk:        []byte(`{"type":"user","message":{"content":"appended"}}` + "\n"),
+	}); err != nil {
+		t.Fatalf("AppendTranscript() error = %v", err)
+	}
+
+	content, err := store.ReadSessionContent(ctx, cpID, 0)
+	if err != nil {
+		t.Fatalf("ReadSessionContent() error = %v", err)
+	}
+	if content.Metadata.TranscriptEncoding != EncodingZstd {
+		t.Fatalf("TranscriptEncoding = %q, want %q", content.Metadata.TranscriptEncoding, EncodingZstd)
+	}
+	if got := readOpenTranscript(t, store, cpID, 0); !bytes.Equal(got, content.Transcript) {
+		t.Errorf("OpenTranscript() returned %d bytes, want the %d bytes ReadSessionContent returns", len(got), len(content.Transcript))
+	}
+}
+
+func TestOpenTranscript_Uncompressed(t *testing.T) {
+	t.Parallel()
+	_, store, cpID := setupRepoForUpdate(t)
+
+	if got := readOpenTranscript(t, store, cpID, 0); string(got) != "provisional transcript line 1\n" {
+		t.Errorf("OpenTranscript() = %q, want the stored transcript", got)
+	}
+}
+
+func TestOpenTranscript_NotFound(t *testing.T) {
+	t.Parallel()
+	_, store, cpID := setupRepoForUpdate(t)
+	ctx := context.Background()
+
+	if _, err := store.OpenTranscript(ctx, id.MustCheckpointID("ffffffffffff"), 0); !errors.Is(err, ErrCheckpointNotFound) {
+		t.Errorf("OpenTranscript() error = %v, want ErrCheckpointNotFound", err)
+	}
+	if _, err := store.OpenTranscript(ctx, cpID, 5); err == nil {
+		t.Error("OpenTranscript() of a missing session succeeded, want an error")
+	}
+}
+
+func TestTranscriptReader_ReadsPartsInOrder(t *testing.T) {
+	t.Parallel()
+	var opened []string
+	part := func(s string) func() (io.ReadCloser, error) {
+		return func() (io.ReadCloser, error) {
+			opened = append(opened, s)
+			return io.NopCloser(strings.NewReader(s)), nil
+		}
+	}
+	reader := &transcriptReader{parts: []func() (io.ReadCloser, error){part("a"), part(""), part("bc")}}
+
+	first := make([]byte, 1)
+	if _, err := reader.Read(first); err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if len(opened) != 1 {
+		t.Errorf("opened %v after the first read, want parts opened lazily", opened)
+	}
+	rest, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("ReadAll() error = %v", err)
+	}
+	if got := string(first) + string(rest); got != "abc" {
+		t.Errorf("read %q, want %q", got, "abc")
+	}
+	if err := reader.Close(); err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+}
@@ -0,0 +1,261 @@
+package checkpoint
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/entireio/cli/cmd/entire/cli/agent"
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// Checkpoint verification statuses.
+const (
+	VerifyStatusOK         = "ok"
+	VerifyStatusIncomplete = "incomplete" // files are missing
+	VerifyStatusCorrupted  = "corrupted"  // files are unreadable, invalid, or don't match their hash
+)
+
+// Kinds of problems VerifyCommitted reports.
+const (
+	VerifyProblemMissing      = "missing"
+	VerifyProblemInvalid      = "invalid"
+	VerifyProblemUnreadable   = "unreadable"
+	VerifyProblemHashMismatch = "hash_mismatch"
+)
+
+// VerifyProblem is a single defect found in a checkpoint.
+type VerifyProblem struct {
+	// Path is the file the problem is in, relative to the branch root.
+	Path    string `json:"path"`
+	Kind    string `json:"kind"`
+	Message string `json:"message"`
+}
+
+// CheckpointVerification is the result of verifying one checkpoint.
+type CheckpointVerification struct {
+	CheckpointID id.CheckpointID `json:"checkpoint_id"`
+	Status       string          `json:"status"`
+	Problems     []VerifyProblem `json:"problems,omitempty"`
+
+	// Unverified lists content that couldn't be checked, such as transcripts
+	// encrypted to keys that aren't available. It doesn't affect Status.
+	Unverified []string `json:"unverified,omitempty"`
+}
+
+var contentHashPattern = regexp.MustCompile(`^sha256:[0-9a-f]{64}$`)
+
+// VerifyCommitted checks every checkpoint on the metadata branch: metadata
+// files must parse and agree with the checkpoint they're in, files the
+// summary lists must exist, and transcripts must match the sha256 in their
+// content_hash.txt. Results are in tree order.
+func (s *GitStore) VerifyCommitted(ctx context.Context) ([]CheckpointVerification, error) {
+	tree, err := s.getSessionsBranchTree()
+	if err != nil {
+		return nil, nil //nolint:nilerr // No sessions branch means nothing to verify
+	}
+
+	var results []CheckpointVerification
+	for _, bucketEntry := range tree.Entries {
+		if bucketEntry.Mode != filemode.Dir || len(bucketEntry.Name) != 2 {
+			continue
+		}
+		bucketTree, err := s.repo.TreeObject(bucketEntry.Hash)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read shard %s: %w", bucketEntry.Name, err)
+		}
+		for _, checkpointEntry := range bucketTree.Entries {
+			if err := ctx.Err(); err != nil {
+				return nil, err //nolint:wrapcheck // Propagating context cancellation
+			}
+			if checkpointEntry.Mode != filemode.Dir {
+				continue
+			}
+			checkpointID, err := id.NewCheckpointID(bucketEntry.Name + checkpointEntry.Name)
+			if err != nil {
+				continue
+			}
+			v := &CheckpointVerification{CheckpointID: checkpointID}
+			if checkpointTree, err := s.repo.TreeObject(checkpointEntry.Hash); err != nil {
+				v.addProblem(checkpointID.Path(), VerifyProblemUnreadable, err.Error())
+			} else {
+				s.verifyCheckpoint(ctx, v, checkpointTree)
+			}
+			v.Status = verifyStatus(v.Problems)
+			results = append(results, *v)
+		}
+	}
+	return results, nil
+}
+
+func (v *CheckpointVerification) addProblem(filePath, kind, message string) {
+	v.Problems = append(v.Problems, VerifyProblem{Path: filePath, Kind: kind, Message: message})
+}
+
+func verifyStatus(problems []VerifyProblem) string {
+	status := VerifyStatusOK
+	for _, p := range problems {
+		if p.Kind != VerifyProblemMissing {
+			return VerifyStatusCorrupted
+		}
+		status = VerifyStatusIncomplete
+	}
+	return status
+}
+
+func (s *GitStore) verifyCheckpoint(ctx context.Context, v *CheckpointVerification, checkpointTree *object.Tree) {
+	base := v.CheckpointID.Path()
+	var summary CheckpointSummary
+	if !verifyJSONFile(v, checkpointTree, base, paths.MetadataFileName, &summary) {
+		return
+	}
+	summaryPath := base + "/" + paths.MetadataFileName
+	if summary.CheckpointID != v.CheckpointID {
+		v.addProblem(summaryPath, VerifyProblemInvalid,
+			fmt.Sprintf("checkpoint_id is %q, expected %q", summary.CheckpointID, v.CheckpointID))
+	}
+	if len(summary.Sessions) == 0 {
+		v.addProblem(summaryPath, VerifyProblemInvalid, "no sessions")
+	}
+
+	for i, filePaths := range summary.Sessions {
+		sessionDir := strconv.Itoa(i)
+		// Every file the summary lists must exist. The transcript and its hash
+		// are listed even when the session had no transcript, so they're
+		// checked below instead, along with the session's metadata.json.
+		for _, listed := range []string{filePaths.Prompt, filePaths.Prompts, filePaths.Context, filePaths.ContextJSON} {
+			if listed == "" {
+				continue
+			}
+			rel := strings.TrimPrefix(strings.TrimPrefix(listed, "/"), base+"/")
+			if _, err := checkpointTree.FindEntry(rel); err != nil {
+				v.addProblem(strings.TrimPrefix(listed, "/"), VerifyProblemMissing, "listed in "+paths.MetadataFileName+" but not stored")
+			}
+		}
+
+		sessionTree, err := checkpointTree.Tree(sessionDir)
+		if err != nil {
+			v.addProblem(base+"/"+sessionDir, VerifyProblemMissing, "session directory not stored")
+			continue
+		}
+		sessionBase := base + "/" + sessionDir
+		var metadata CommittedMetadata
+		if !verifyJSONFile(v, sessionTree, sessionBase, paths.MetadataFileName, &metadata) {
+			continue
+		}
+		metadataPath := sessionBase + "/" + paths.MetadataFileName
+		if metadata.CheckpointID != v.CheckpointID {
+			v.addProblem(metadataPath, VerifyProblemInvalid,
+				fmt.Sprintf("checkpoint_id is %q, expected %q", metadata.CheckpointID, v.CheckpointID))
+		}
+		if metadata.SessionID == "" {
+			v.addProblem(metadataPath, VerifyProblemInvalid, "session_id is empty")
+		}
+		if metadata.CreatedAt.IsZero() {
+			v.addProblem(metadataPath, VerifyProblemInvalid, "created_at is missing")
+		}
+		s.verifyTranscript(ctx, v, sessionTree, sessionBase, &metadata)
+	}
+}
+
+// verifyJSONFile decodes base/name in tree into out, recording a problem
+// if it is missing, unreadable or invalid.
+func verifyJSONFile(v *CheckpointVerification, tree *object.Tree, base, name string, out any) bool {
+	filePath := base + "/" + name
+	file, err := tree.File(name)
+	if err != nil {
+		v.addProblem(filePath, VerifyProblemMissing, "not stored")
+		return false
+	}
+	content, err := file.Contents()
+	if err != nil {
+		v.addProblem(filePath, VerifyProblemUnreadable, err.Error())
+		return false
+	}
+	if err := json.Unmarshal([]byte(content), out); err != nil {
+		v.addProblem(filePath, VerifyProblemInvalid, "invalid JSON: "+err.Error())
+		return false
+	}
+	return true
+}
+
+// verifyTranscript recomputes the session's transcript hash and compares it
+// with content_hash.txt.
+func (s *GitStore) verifyTranscript(ctx context.Context, v *CheckpointVerification, sessionTree *object.Tree, sessionBase string, metadata *CommittedMetadata) {
+	hashPath := sessionBase + "/" + paths.ContentHashFileName
+	var want string
+	if file, err := sessionTree.File(paths.ContentHashFileName); err == nil {
+		content, err := file.Contents()
+		if err != nil {
+			v.addProblem(hashPath, VerifyProblemUnreadable, err.Error())
+			return
+		}
+		want = strings.TrimSpace(content)
+		if !contentHashPattern.MatchString(want) {
+			v.addProblem(hashPath, VerifyProblemInvalid, fmt.Sprintf("%q is not a sha256 hash", want))
+			return
+		}
+	}
+
+	if _, err := sessionTree.FindEntry(paths.TranscriptPointerFileName); err == nil {
+		// The transcript lives outside git; fetching it is out of scope here
+		v.Unverified = append(v.Unverified, sessionBase+"/"+paths.TranscriptPointerFileName)
+		return
+	}
+
+	transcriptPath := sessionBase + "/" + paths.TranscriptFileName
+	chunks, err := readTranscriptChunks(ctx, sessionTree, metadata.TranscriptEncoding, s.decryptContent)
+	var appended []byte
+	if err == nil {
+		appended, err = readTranscriptAppends(sessionTree, metadata.TranscriptEncoding, s.decryptContent)
+	}
+	switch {
+	case errors.Is(err, ErrNoIdentity):
+		v.Unverified = append(v.Unverified, transcriptPath)
+		return
+	case err != nil:
+		v.addProblem(transcriptPath, VerifyProblemUnreadable, err.Error())
+		return
+	case len(chunks) == 0 && len(appended) == 0 && want != "":
+		v.addProblem(transcriptPath, VerifyProblemMissing, "not stored, but "+paths.ContentHashFileName+" is")
+		return
+	case len(chunks) == 0 && want == "":
+		return // Nothing stored, or only appended lines, which have no hash of their own
+	case want == "":
+		if _, err := sessionTree.FindEntry(paths.TranscriptFileNameLegacy); err == nil {
+			return // Legacy transcripts predate content hashes
+		}
+		v.addProblem(hashPath, VerifyProblemMissing, "not stored, but the transcript is")
+		return
+	}
+
+	// The hash covers the transcript before chunking. Line-based chunks join
+	// back byte for byte; agents with their own format re-encode on
+	// reassembly, so their chunked transcripts can't be compared. Lines added
+	// by AppendTranscript follow the transcript as stored, and are covered by
+	// the hash only in checkpoints appended to before appends left it as is.
+	joined := agent.ReassembleJSONL(chunks)
+	if fmt.Sprintf("sha256:%x", sha256.Sum256(joined)) == want {
+		return
+	}
+	if len(appended) > 0 && fmt.Sprintf("sha256:%x", sha256.Sum256(append(joined, appended...))) == want {
+		return
+	}
+	if len(chunks) > 1 {
+		if reassembled, err := agent.ReassembleTranscript(chunks, metadata.Agent); err == nil && !bytes.Equal(reassembled, joined) {
+			v.Unverified = append(v.Unverified, transcriptPath)
+			return
+		}
+	}
+	v.addProblem(transcriptPath, VerifyProblemHashMismatch, "transcript doesn't match "+paths.ContentHashFileName)
+}
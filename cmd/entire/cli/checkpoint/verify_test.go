@@ -0,0 +1,120 @@
+package checkpoint
+
+import (
+	"context"
+	"testing"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// tamperCheckpoint rewrites a checkpoint's files on the metadata branch.
+func tamperCheckpoint(t *testing.T, store *GitStore, cpID id.CheckpointID, edit func(base string, entries map[string]object.TreeEntry)) {
+	t.Helper()
+	parent, rootTree, err := store.getSessionsBranchRef()
+	if err != nil {
+		t.Fatal(err)
+	}
+	base := cpID.Path() + "/"
+	entries, err := store.flattenCheckpointEntries(rootTree, cpID.Path())
+	if err != nil {
+		t.Fatal(err)
+	}
+	edit(base, entries)
+	newTree, err := store.spliceCheckpointSubtree(rootTree, cpID, base, entries)
+	if err != nil {
+		t.Fatal(err)
+	}
+	commit, err := store.createCommit(newTree, parent, "tamper", "Test", "test@test.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ref := plumbing.NewHashReference(plumbing.NewBranchReferenceName(paths.MetadataBranchName), commit)
+	if err := store.repo.Storer.SetReference(ref); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestVerifyCommitted(t *testing.T) {
+	repo, _ := setupBranchTestRepo(t)
+	store := NewGitStore(repo)
+	ctx := context.Background()
+
+	healthy := id.MustCheckpointID("aa1111111111")
+	tampered := id.MustCheckpointID("bb2222222222")
+	truncated := id.MustCheckpointID("cc3333333333")
+	invalid := id.MustCheckpointID("dd4444444444")
+	for _, cpID := range []id.CheckpointID{healthy, tampered, truncated, invalid} {
+		writeIndexTestCheckpoint(t, store, cpID, "session-1")
+	}
+
+	blob := func(content string) plumbing.Hash {
+		hash, err := CreateBlobFromContent(repo, []byte(content))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return hash
+	}
+	tamperCheckpoint(t, store, tampered, func(base string, entries map[string]object.TreeEntry) {
+		entry := entries[base+"0/"+paths.TranscriptFileName]
+		entry.Hash = blob(`{"type":"user","message":{"content":"edited"}}` + "\n")
+		entries[base+"0/"+paths.TranscriptFileName] = entry
+	})
+	tamperCheckpoint(t, store, truncated, func(base string, entries map[string]object.TreeEntry) {
+		delete(entries, base+"0/"+paths.TranscriptFileName)
+	})
+	tamperCheckpoint(t, store, invalid, func(base string, entries map[string]object.TreeEntry) {
+		entry := entries[base+"0/"+paths.MetadataFileName]
+		entry.Hash = blob(`{"checkpoint_id": "dd4444444444", `)
+		entries[base+"0/"+paths.MetadataFileName] = entry
+	})
+
+	results, err := store.VerifyCommitted(ctx)
+	if err != nil {
+		t.Fatalf("VerifyCommitted() error = %v", err)
+	}
+	byID := make(map[id.CheckpointID]CheckpointVerification)
+	for _, r := range results {
+		byID[r.CheckpointID] = r
+	}
+	tests := []struct {
+		cpID   id.CheckpointID
+		status string
+		kind   string
+	}{
+		{healthy, VerifyStatusOK, ""},
+		{tampered, VerifyStatusCorrupted, VerifyProblemHashMismatch},
+		{truncated, VerifyStatusIncomplete, VerifyProblemMissing},
+		{invalid, VerifyStatusCorrupted, VerifyProblemInvalid},
+	}
+	for _, tt := range tests {
+		got, ok := byID[tt.cpID]
+		if !ok {
+			t.Errorf("%s not verified", tt.cpID)
+			continue
+		}
+		if got.Status != tt.status {
+			t.Errorf("%s status = %q, want %q (problems %+v)", tt.cpID, got.Status, tt.status, got.Problems)
+		}
+		if tt.kind == "" {
+			if len(got.Problems) > 0 {
+				t.Errorf("%s problems = %+v, want none", tt.cpID, got.Problems)
+			}
+			continue
+		}
+		if len(got.Problems) != 1 || got.Problems[0].Kind != tt.kind {
+			t.Errorf("%s problems = %+v, want one %q", tt.cpID, got.Problems, tt.kind)
+		}
+	}
+}
+
+func TestVerifyCommitted_NoBranch(t *testing.T) {
+	repo, _ := setupBranchTestRepo(t)
+	results, err := NewGitStore(repo).VerifyCommitted(context.Background())
+	if err != nil || len(results) != 0 {
+		t.Errorf("VerifyCommitted() = %v, %v; want no results", results, err)
+	}
+}
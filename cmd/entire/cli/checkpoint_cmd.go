@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+	"github.com/entireio/cli/cmd/entire/cli/strategy"
+
+	"github.com/spf13/cobra"
+)
+
+func newCheckpointCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "checkpoint",
+		Short: "Manage committed checkpoints",
+	}
+
+	cmd.AddCommand(newCheckpointDeleteCmd())
+
+	return cmd
+}
+
+func newCheckpointDeleteCmd() *cobra.Command {
+	var forceFlag bool
+
+	cmd := &cobra.Command{
+		Use:   "delete <checkpoint-id>",
+		Short: "Delete a checkpoint from the checkpoints branch",
+		Long: `Delete removes a committed checkpoint from the entire/checkpoints/v1
+branch in a new commit. Earlier commits of the branch still hold it, so
+'entire purge-session' is the way to remove content from the history.
+
+A checkpoint that a session's current turn produced is updated with the full
+transcript when the turn ends, so deleting it is refused unless --force is
+given. The deletion is recorded in the audit log (see 'entire audit-log').`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			if _, err := paths.WorktreeRoot(ctx); err != nil {
+				return paths.ErrNotGitRepository
+			}
+			return runCheckpointDelete(ctx, cmd.OutOrStdout(), args[0], forceFlag)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&forceFlag, "force", "f", false, "Delete even if a session's current turn references the checkpoint")
+
+	return cmd
+}
+
+func runCheckpointDelete(ctx context.Context, w io.Writer, checkpointIDPrefix string, force bool) error {
+	repo, err := openRepository(ctx)
+	if err != nil {
+		return fmt.Errorf("not a git repository: %w", err)
+	}
+	store, err := strategy.CommittedStore(ctx, checkpoint.NewGitStore(repo))
+	if err != nil {
+		return err //nolint:wrapcheck // already names the setting
+	}
+
+	checkpointID, err := resolveCommittedCheckpointID(ctx, store, checkpointIDPrefix)
+	if err != nil {
+		return err
+	}
+
+	states, err := strategy.ListSessionStates(ctx)
+	if err != nil {
+		return err //nolint:wrapcheck // already wrapped by strategy
+	}
+	var referencedBy []string
+	for _, state := range states {
+		if slices.Contains(state.TurnCheckpointIDs, checkpointID.String()) {
+			referencedBy = append(referencedBy, state.SessionID)
+		}
+	}
+
+	err = store.DeleteCommitted(ctx, checkpointID, checkpoint.DeleteOptions{ReferencedBy: referencedBy, Force: force})
+	if errors.Is(err, checkpoint.ErrCheckpointInUse) {
+		return fmt.Errorf("%w (--force deletes it anyway)", err)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete checkpoint %s: %w", checkpointID, err)
+	}
+
+	strategy.RecordAudit(ctx, checkpoint.AuditEntry{
+		Operation: checkpoint.AuditOpDelete,
+		Refs:      []string{paths.MetadataBranchName},
+		Removed:   []string{checkpointID.String()},
+	})
+	fmt.Fprintf(w, "Deleted checkpoint %s from %s\n", checkpointID, paths.MetadataBranchName)
+	return nil
+}
@@ -0,0 +1,58 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+	"github.com/entireio/cli/cmd/entire/cli/session"
+	"github.com/entireio/cli/cmd/entire/cli/strategy"
+)
+
+func TestRunCheckpointDelete_RefusesInUseWithoutForce(t *testing.T) {
+	repo, head := setupCleanTestRepo(t)
+	store := checkpoint.NewGitStore(repo)
+	ctx := context.Background()
+
+	cpID := id.MustCheckpointID("d1d2d3d4d5d6")
+	if err := store.WriteCommitted(ctx, checkpoint.WriteCommittedOptions{
+		CheckpointID: cpID,
+		SessionID:    "delete-session",
+		Strategy:     strategy.StrategyNameManualCommit,
+		Transcript:   []byte(`{"type":"user"}` + "\n"),
+		AuthorName:   "Test",
+		AuthorEmail:  "test@test.com",
+	}); err != nil {
+		t.Fatalf("WriteCommitted() error = %v", err)
+	}
+	if err := strategy.SaveSessionState(ctx, &strategy.SessionState{
+		SessionID:         "delete-session",
+		BaseCommit:        head.String(),
+		StartedAt:         time.Now(),
+		Phase:             session.PhaseActive,
+		TurnCheckpointIDs: []string{cpID.String()},
+	}); err != nil {
+		t.Fatalf("SaveSessionState() error = %v", err)
+	}
+
+	var out bytes.Buffer
+	err := runCheckpointDelete(ctx, &out, "d1d2", false)
+	if !errors.Is(err, checkpoint.ErrCheckpointInUse) || !strings.Contains(err.Error(), "delete-session") {
+		t.Fatalf("runCheckpointDelete() error = %v, want in use by delete-session", err)
+	}
+
+	if err := runCheckpointDelete(ctx, &out, "d1d2", true); err != nil {
+		t.Fatalf("runCheckpointDelete() with force error = %v", err)
+	}
+	if !strings.Contains(out.String(), "Deleted checkpoint d1d2d3d4d5d6") {
+		t.Errorf("runCheckpointDelete() output = %q", out.String())
+	}
+	if summary, err := store.ReadCommitted(ctx, cpID); err != nil || summary != nil {
+		t.Errorf("ReadCommitted() after delete = %v, %v, want nothing", summary, err)
+	}
+}
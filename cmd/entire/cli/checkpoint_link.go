@@ -0,0 +1,149 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/strategy"
+	"github.com/entireio/cli/cmd/entire/cli/trailers"
+)
+
+// serveStateFileName is written to the git common dir while `entire serve`
+// runs for the repository, so hooks can link to its pages.
+const serveStateFileName = "entire-serve.json"
+
+// serveDialTimeout bounds the check that a recorded dashboard still runs.
+const serveDialTimeout = 200 * time.Millisecond
+
+// serveState is the content of serveStateFileName.
+type serveState struct {
+	URL string `json:"url"`
+	PID int    `json:"pid"`
+}
+
+func serveStatePath(ctx context.Context) (string, error) {
+	commonDir, err := strategy.GetGitCommonDir(ctx)
+	if err != nil {
+		return "", err //nolint:wrapcheck // already descriptive
+	}
+	return filepath.Join(commonDir, serveStateFileName), nil
+}
+
+// recordServeState announces a running dashboard at baseURL. The returned
+// func removes the record again, unless another dashboard replaced it.
+func recordServeState(ctx context.Context, baseURL string) (func(), error) {
+	path, err := serveStatePath(ctx)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(serveState{URL: baseURL, PID: os.Getpid()})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode serve state: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return nil, fmt.Errorf("failed to write serve state: %w", err)
+	}
+	return func() {
+		if state, err := readServeState(path); err == nil && state.PID == os.Getpid() {
+			_ = os.Remove(path)
+		}
+	}, nil
+}
+
+func readServeState(path string) (*serveState, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // path is in the git common dir
+	if err != nil {
+		return nil, err //nolint:wrapcheck // callers only check for success
+	}
+	var state serveState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, err //nolint:wrapcheck // callers only check for success
+	}
+	return &state, nil
+}
+
+// runningServeURL returns the base URL of the dashboard serving this
+// repository, or "" if none is running.
+func runningServeURL(ctx context.Context) string {
+	path, err := serveStatePath(ctx)
+	if err != nil {
+		return ""
+	}
+	state, err := readServeState(path)
+	if err != nil {
+		return ""
+	}
+	u, err := url.Parse(state.URL)
+	if err != nil || u.Host == "" {
+		return ""
+	}
+	// A dashboard that exited without cleaning up leaves a stale record
+	dialer := net.Dialer{Timeout: serveDialTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", u.Host)
+	if err != nil {
+		return ""
+	}
+	_ = conn.Close()
+	return state.URL
+}
+
+// dashboardURL returns the URL a local browser reaches a dashboard
+// listening on addr at.
+func dashboardURL(scheme string, addr net.Addr) string {
+	host, port, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return scheme + "://" + addr.String()
+	}
+	if ip := net.ParseIP(host); ip != nil && ip.IsUnspecified() {
+		host = "localhost"
+	}
+	return scheme + "://" + net.JoinHostPort(host, port)
+}
+
+// announceCheckpoint prints the checkpoint the HEAD commit created, and its
+// dashboard page while `entire serve` runs, when checkpoint_link is set.
+// It runs in the post-commit hook, whose output git shows to the user.
+func announceCheckpoint(ctx context.Context, w io.Writer) {
+	s, err := LoadEntireSettings(ctx)
+	if err != nil || !s.CheckpointLink {
+		return
+	}
+	repo, err := openRepository(ctx)
+	if err != nil {
+		return
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return
+	}
+	commit, err := repo.CommitObject(head.Hash())
+	if err != nil {
+		return
+	}
+	cpID, found := trailers.ParseCheckpoint(commit.Message)
+	if !found {
+		return
+	}
+	// The trailer alone doesn't mean the checkpoint was written
+	store, err := strategy.CommittedStore(ctx, checkpoint.NewGitStore(repo))
+	if err != nil {
+		return
+	}
+	summary, err := store.ReadCommitted(ctx, cpID)
+	if err != nil || summary == nil {
+		return
+	}
+
+	fmt.Fprintf(w, "Entire checkpoint %s (entire explain -c %s)\n", cpID, cpID)
+	if base := runningServeURL(ctx); base != "" {
+		fmt.Fprintf(w, "  %s/checkpoints/%s\n", base, cpID)
+	}
+}
@@ -0,0 +1,101 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+	"github.com/entireio/cli/cmd/entire/cli/testutil"
+	"github.com/entireio/cli/cmd/entire/cli/trailers"
+)
+
+func TestDashboardURL(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		addr string
+		want string
+	}{
+		{"127.0.0.1:8080", "http://127.0.0.1:8080"},
+		{"0.0.0.0:8443", "http://localhost:8443"},
+		{"[::]:9000", "http://localhost:9000"},
+	}
+	for _, tt := range tests {
+		addr, err := net.ResolveTCPAddr("tcp", tt.addr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := dashboardURL("http", addr); got != tt.want {
+			t.Errorf("dashboardURL(%s) = %q, want %q", tt.addr, got, tt.want)
+		}
+	}
+}
+
+func TestAnnounceCheckpoint(t *testing.T) {
+	dir := setupExecTestRepo(t)
+	ctx := context.Background()
+	cpID := id.MustCheckpointID("c3c3c3c3c3c3")
+
+	repo, err := openRepository(ctx)
+	if err != nil {
+		t.Fatalf("openRepository() error = %v", err)
+	}
+	if err := checkpoint.NewGitStore(repo).WriteCommitted(ctx, checkpoint.WriteCommittedOptions{
+		CheckpointID: cpID,
+		SessionID:    "session-001",
+		Strategy:     "manual-commit",
+		Transcript:   []byte("transcript\n"),
+		AuthorName:   "Test",
+		AuthorEmail:  "test@test.com",
+	}); err != nil {
+		t.Fatalf("WriteCommitted() error = %v", err)
+	}
+	testutil.WriteFile(t, dir, "main.go", "package main\n")
+	testutil.GitAdd(t, dir, "main.go")
+	testutil.GitCommit(t, dir, trailers.FormatCheckpoint("Add main", cpID))
+
+	var out bytes.Buffer
+	announceCheckpoint(ctx, &out)
+	if out.Len() != 0 {
+		t.Errorf("nothing should be printed without checkpoint_link, got %q", out.String())
+	}
+
+	if err := os.WriteFile(filepath.Join(".entire", "settings.json"), []byte(`{"enabled": true, "checkpoint_link": true}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	announceCheckpoint(ctx, &out)
+	if got := out.String(); got != "Entire checkpoint c3c3c3c3c3c3 (entire explain -c c3c3c3c3c3c3)\n" {
+		t.Errorf("output without a dashboard = %q", got)
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	remove, err := recordServeState(ctx, dashboardURL("http", listener.Addr()))
+	if err != nil {
+		t.Fatalf("recordServeState() error = %v", err)
+	}
+	out.Reset()
+	announceCheckpoint(ctx, &out)
+	if want := dashboardURL("http", listener.Addr()) + "/checkpoints/c3c3c3c3c3c3"; !strings.Contains(out.String(), want) {
+		t.Errorf("output = %q, want the dashboard link %s", out.String(), want)
+	}
+
+	// A dashboard that stopped without cleaning up isn't linked
+	listener.Close()
+	out.Reset()
+	announceCheckpoint(ctx, &out)
+	if strings.Contains(out.String(), "/checkpoints/") {
+		t.Errorf("stale dashboard should not be linked, got %q", out.String())
+	}
+	remove()
+	if runningServeURL(ctx) != "" {
+		t.Error("serve state should be removed")
+	}
+}
@@ -0,0 +1,122 @@
+// Package classify assigns a conventional-commit style label to a checkpoint
+// from the files it touched and the prompts that produced it.
+//
+// Classification is heuristic and runs locally without calling a model:
+// the file set decides when it is unambiguous (only tests, only docs),
+// otherwise keywords in the prompts decide, and new files fall back to feat.
+package classify
+
+import (
+	"path"
+	"regexp"
+	"strings"
+)
+
+// Label is a conventional-commit type.
+type Label string
+
+// Supported labels.
+const (
+	LabelFeat     Label = "feat"
+	LabelFix      Label = "fix"
+	LabelRefactor Label = "refactor"
+	LabelTest     Label = "test"
+	LabelDocs     Label = "docs"
+)
+
+// Labels lists every supported label, in display order.
+var Labels = []Label{LabelFeat, LabelFix, LabelRefactor, LabelTest, LabelDocs}
+
+// IsValid reports whether s names a supported label.
+func IsValid(s string) bool {
+	for _, l := range Labels {
+		if string(l) == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Input is what the classifier looks at.
+type Input struct {
+	Prompts       []string
+	ModifiedFiles []string
+	NewFiles      []string
+	DeletedFiles  []string
+}
+
+// keywordRules are checked in order; the first rule with a match wins.
+// Fix comes first because "add a fix for" should not read as a feature.
+var keywordRules = []struct {
+	label Label
+	re    *regexp.Regexp
+}{
+	{LabelFix, regexp.MustCompile(`(?i)\b(fix(es|ed|ing)?|bug|broken|crash(es|ing)?|regression|fail(s|ing|ure)?)\b`)},
+	{LabelRefactor, regexp.MustCompile(`(?i)\b(refactor\w*|rename\w*|clean ?up|extract\w*|simplif\w*|restructur\w*|reorganiz\w*|move|dedup\w*)\b`)},
+	{LabelTest, regexp.MustCompile(`(?i)\b(tests?|testing|coverage|spec)\b`)},
+	{LabelDocs, regexp.MustCompile(`(?i)\b(docs?|documentation|readme|changelog|comments?|docstrings?)\b`)},
+	{LabelFeat, regexp.MustCompile(`(?i)\b(add\w*|implement\w*|creat\w*|introduc\w*|support\w*|new|feature)\b`)},
+}
+
+// Classify returns the label for a checkpoint.
+func Classify(in Input) Label {
+	files := make([]string, 0, len(in.ModifiedFiles)+len(in.NewFiles)+len(in.DeletedFiles))
+	files = append(files, in.ModifiedFiles...)
+	files = append(files, in.NewFiles...)
+	files = append(files, in.DeletedFiles...)
+
+	if len(files) > 0 {
+		if all(files, isTestFile) {
+			return LabelTest
+		}
+		if all(files, isDocFile) {
+			return LabelDocs
+		}
+	}
+
+	prompt := strings.Join(in.Prompts, "\n")
+	for _, rule := range keywordRules {
+		if rule.re.MatchString(prompt) {
+			return rule.label
+		}
+	}
+
+	// Pure deletions or shuffles without new code read as refactors
+	if len(in.NewFiles) == 0 && len(in.ModifiedFiles) == 0 && len(in.DeletedFiles) > 0 {
+		return LabelRefactor
+	}
+	return LabelFeat
+}
+
+func all(files []string, pred func(string) bool) bool {
+	for _, f := range files {
+		if !pred(f) {
+			return false
+		}
+	}
+	return true
+}
+
+func isTestFile(file string) bool {
+	base := path.Base(file)
+	if strings.HasSuffix(base, "_test.go") || strings.HasPrefix(base, "test_") ||
+		strings.Contains(base, ".test.") || strings.Contains(base, ".spec.") {
+		return true
+	}
+	for _, dir := range strings.Split(path.Dir(file), "/") {
+		switch dir {
+		case "test", "tests", "__tests__", "testdata", "e2e":
+			return true
+		}
+	}
+	return false
+}
+
+func isDocFile(file string) bool {
+	switch strings.ToLower(path.Ext(file)) {
+	case ".md", ".mdx", ".rst", ".adoc", ".txt":
+		return true
+	}
+	first, _, _ := strings.Cut(file, "/")
+	return first == "docs" || first == "doc"
+}
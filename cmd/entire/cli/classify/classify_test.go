@@ -0,0 +1,71 @@
+package classify
+
+import "testing"
+
+func TestClassify(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		in   Input
+		want Label
+	}{
+		{
+			name: "only test files",
+			in:   Input{Prompts: []string{"add a new endpoint"}, ModifiedFiles: []string{"api/handler_test.go"}},
+			want: LabelTest,
+		},
+		{
+			name: "only doc files",
+			in:   Input{Prompts: []string{"fix typo"}, ModifiedFiles: []string{"README.md", "docs/setup.md"}},
+			want: LabelDocs,
+		},
+		{
+			name: "fix keyword wins over add",
+			in:   Input{Prompts: []string{"add a guard to fix the crash on empty input"}, ModifiedFiles: []string{"api/handler.go"}},
+			want: LabelFix,
+		},
+		{
+			name: "refactor keyword",
+			in:   Input{Prompts: []string{"rename the helper and extract the parser"}, ModifiedFiles: []string{"parser.go"}},
+			want: LabelRefactor,
+		},
+		{
+			name: "feature keyword",
+			in:   Input{Prompts: []string{"implement pagination"}, ModifiedFiles: []string{"api/list.go"}},
+			want: LabelFeat,
+		},
+		{
+			name: "deletions only",
+			in:   Input{Prompts: []string{"get rid of this"}, DeletedFiles: []string{"legacy.go"}},
+			want: LabelRefactor,
+		},
+		{
+			name: "no signal defaults to feat",
+			in:   Input{Prompts: []string{"hello"}, NewFiles: []string{"main.go"}},
+			want: LabelFeat,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := Classify(tt.in); got != tt.want {
+				t.Errorf("Classify() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsValid(t *testing.T) {
+	t.Parallel()
+
+	for _, l := range Labels {
+		if !IsValid(string(l)) {
+			t.Errorf("IsValid(%q) = false, want true", l)
+		}
+	}
+	if IsValid("chore") {
+		t.Error("IsValid(chore) = true, want false")
+	}
+}
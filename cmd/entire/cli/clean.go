@@ -107,9 +107,9 @@ func listTempFiles(ctx context.Context) ([]string, error) {
 		if entry.IsDir() {
 			continue
 		}
-		// Skip temp files belonging to active sessions (e.g., "session-id.json")
+		// Skip temp files belonging to active sessions (e.g., "session-id.json", "exec-....jsonl")
 		name := entry.Name()
-		sessionID := strings.TrimSuffix(name, ".json")
+		sessionID := strings.TrimSuffix(strings.TrimSuffix(name, ".jsonl"), ".json")
 		if sessionID != name && activeSessionIDs[sessionID] {
 			continue
 		}
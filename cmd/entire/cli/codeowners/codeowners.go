@@ -0,0 +1,195 @@
+// Package codeowners parses CODEOWNERS files and maps repository paths to
+// their owners, so checkpoints can be routed to the people who own the files
+// they touched.
+//
+// Patterns follow the GitHub CODEOWNERS syntax, a subset of gitignore: a
+// pattern containing a slash other than a trailing one is anchored at the
+// repository root, other patterns match at any depth, a trailing slash
+// matches everything under a directory, and "*", "?", and "**" are
+// supported. The last matching rule wins, and a matching rule without
+// owners leaves the path unowned.
+package codeowners
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Locations lists where CODEOWNERS is looked up, relative to the repository
+// root, in the order GitHub uses.
+var Locations = []string{".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS"}
+
+// Rule is one CODEOWNERS line.
+type Rule struct {
+	Pattern string
+	Owners  []string
+	Line    int
+	re      *regexp.Regexp
+}
+
+// Ruleset is a parsed CODEOWNERS file. A nil *Ruleset owns nothing.
+type Ruleset struct {
+	Rules []Rule
+}
+
+// Parse parses CODEOWNERS content. Lines that can't be compiled are reported
+// together; blank lines and comments are skipped.
+func Parse(data []byte) (*Ruleset, error) {
+	rs := &Ruleset{}
+	var errs []error
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if i := strings.Index(line, " #"); i >= 0 {
+			line = strings.TrimSpace(line[:i])
+		}
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		re, err := compilePattern(fields[0])
+		if err != nil {
+			errs = append(errs, fmt.Errorf("line %d: %w", lineNo, err))
+			continue
+		}
+		rs.Rules = append(rs.Rules, Rule{Pattern: fields[0], Owners: fields[1:], Line: lineNo, re: re})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading CODEOWNERS: %w", err)
+	}
+	if len(errs) > 0 {
+		return rs, errors.Join(errs...)
+	}
+	return rs, nil
+}
+
+// Load reads the first CODEOWNERS file found under repoRoot. Returns nil
+// and no error if the repository has none.
+func Load(repoRoot string) (*Ruleset, error) {
+	for _, location := range Locations {
+		data, err := os.ReadFile(filepath.Join(repoRoot, filepath.FromSlash(location)))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", location, err)
+		}
+		rs, err := Parse(data)
+		if err != nil {
+			return rs, fmt.Errorf("%s: %w", location, err)
+		}
+		return rs, nil
+	}
+	return nil, nil //nolint:nilnil // no CODEOWNERS file means no owners
+}
+
+// Owners returns the owners of a repository-relative, slash-separated path.
+func (rs *Ruleset) Owners(file string) []string {
+	if rs == nil {
+		return nil
+	}
+	file = strings.TrimPrefix(file, "/")
+	for i := len(rs.Rules) - 1; i >= 0; i-- {
+		if rs.Rules[i].re.MatchString(file) {
+			return rs.Rules[i].Owners
+		}
+	}
+	return nil
+}
+
+// OwnersOf returns the distinct owners of files, sorted, with how many of
+// the files each one owns.
+func (rs *Ruleset) OwnersOf(files []string) map[string]int {
+	owners := make(map[string]int)
+	for _, file := range files {
+		for _, owner := range rs.Owners(file) {
+			owners[owner]++
+		}
+	}
+	return owners
+}
+
+// SortedOwners returns owners ordered by how many files they own, most
+// first, then by name.
+func SortedOwners(owners map[string]int) []string {
+	sorted := make([]string, 0, len(owners))
+	for owner := range owners {
+		sorted = append(sorted, owner)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if owners[sorted[i]] != owners[sorted[j]] {
+			return owners[sorted[i]] > owners[sorted[j]]
+		}
+		return sorted[i] < sorted[j]
+	})
+	return sorted
+}
+
+// compilePattern converts a CODEOWNERS pattern to a regexp over
+// repository-relative paths.
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	if strings.HasPrefix(pattern, "!") || strings.Contains(pattern, "[") {
+		return nil, fmt.Errorf("unsupported pattern %q: negation and character ranges are not allowed in CODEOWNERS", pattern)
+	}
+	dirOnly := strings.HasSuffix(pattern, "/")
+	trimmed := strings.Trim(pattern, "/")
+	if trimmed == "" {
+		return nil, fmt.Errorf("invalid pattern %q", pattern)
+	}
+	anchored := strings.Contains(trimmed, "/") || strings.HasPrefix(pattern, "/")
+
+	var sb strings.Builder
+	sb.WriteString("^")
+	if !anchored {
+		sb.WriteString("(.*/)?")
+	}
+	sb.WriteString(globToRegexp(trimmed))
+	if dirOnly {
+		sb.WriteString("/.*")
+	} else {
+		// A pattern naming a directory owns everything below it
+		sb.WriteString("(/.*)?")
+	}
+	sb.WriteString("$")
+	re, err := regexp.Compile(sb.String())
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+	}
+	return re, nil
+}
+
+func globToRegexp(glob string) string {
+	var sb strings.Builder
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+		switch c {
+		case '*':
+			if i+1 < len(glob) && glob[i+1] == '*' {
+				i++
+				// "**/" also matches zero directories
+				if i+1 < len(glob) && glob[i+1] == '/' {
+					i++
+					sb.WriteString("(.*/)?")
+				} else {
+					sb.WriteString(".*")
+				}
+			} else {
+				sb.WriteString("[^/]*")
+			}
+		case '?':
+			sb.WriteString("[^/]")
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	return sb.String()
+}
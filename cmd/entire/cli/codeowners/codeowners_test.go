@@ -0,0 +1,104 @@
+package codeowners
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testCodeowners = `# Default owners
+*                   @org/core
+
+*.md                @org/docs      # docs everywhere
+/build/             @alice
+services/api/       @bob bob@example.com
+**/migrations       @org/dba
+/vendor/
+`
+
+func TestRuleset_Owners(t *testing.T) {
+	t.Parallel()
+	rs, err := Parse([]byte(testCodeowners))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	tests := []struct {
+		file string
+		want string
+	}{
+		{"main.go", "@org/core"},
+		{"README.md", "@org/docs"},
+		{"docs/guide/setup.md", "@org/docs"},
+		{"build/ci.yml", "@alice"},
+		{"tools/build/ci.yml", "@org/core"},
+		{"services/api/handler.go", "@bob bob@example.com"},
+		{"services/api/README.md", "@bob bob@example.com"},
+		{"services/apix/main.go", "@org/core"},
+		{"db/migrations/001.sql", "@org/dba"},
+		{"vendor/lib/lib.go", ""},
+	}
+	for _, tt := range tests {
+		if got := strings.Join(rs.Owners(tt.file), " "); got != tt.want {
+			t.Errorf("Owners(%q) = %q, want %q", tt.file, got, tt.want)
+		}
+	}
+}
+
+func TestRuleset_OwnersOf(t *testing.T) {
+	t.Parallel()
+	rs, err := Parse([]byte(testCodeowners))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	owners := rs.OwnersOf([]string{"services/api/a.go", "services/api/b.go", "main.go"})
+	if got := strings.Join(SortedOwners(owners), ","); got != "@bob,bob@example.com,@org/core" {
+		t.Errorf("SortedOwners() = %s", got)
+	}
+
+	var none *Ruleset
+	if len(none.OwnersOf([]string{"main.go"})) != 0 {
+		t.Error("nil Ruleset should own nothing")
+	}
+}
+
+func TestParse_RejectsUnsupportedPatterns(t *testing.T) {
+	t.Parallel()
+	rs, err := Parse([]byte("!secret.txt @a\n*.go @gophers\n[ab].txt @b\n"))
+	if err == nil {
+		t.Fatal("Parse() should report unsupported patterns")
+	}
+	if len(rs.Rules) != 1 || rs.Rules[0].Pattern != "*.go" {
+		t.Errorf("valid rules should still be parsed, got %+v", rs.Rules)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	rs, err := Load(dir)
+	if err != nil || rs != nil {
+		t.Fatalf("Load() without CODEOWNERS = %v, %v; want nil, nil", rs, err)
+	}
+
+	if err := os.MkdirAll(filepath.Join(dir, "docs"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "docs", "CODEOWNERS"), []byte("* @docs-owner\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(dir, ".github"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ".github", "CODEOWNERS"), []byte("* @github-owner\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	rs, err = Load(dir)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if got := rs.Owners("x.go"); len(got) != 1 || got[0] != "@github-owner" {
+		t.Errorf("Load() should prefer .github/CODEOWNERS, got owners %v", got)
+	}
+}
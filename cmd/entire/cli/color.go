@@ -0,0 +1,34 @@
+package cli
+
+import (
+	"github.com/entireio/cli/cmd/entire/cli/theme"
+	"github.com/spf13/cobra"
+)
+
+// colorFlag is the persistent root flag choosing when output is colored.
+const colorFlag = "color"
+
+// applyTheme sets the color mode from --color and the palette from the
+// "theme" setting before a command runs.
+func applyTheme(cmd *cobra.Command) error {
+	if f := cmd.Flags().Lookup(colorFlag); f != nil {
+		mode, err := theme.ParseColorMode(f.Value.String())
+		if err != nil {
+			return err //nolint:wrapcheck // already names the flag value
+		}
+		theme.SetColorMode(mode)
+	}
+
+	// Outside a repository, or with unreadable settings, keep the default
+	// palette; commands that need settings report the error themselves
+	s, err := LoadEntireSettings(cmd.Context())
+	if err != nil {
+		return nil //nolint:nilerr // see above
+	}
+	p, err := theme.PaletteNamed(s.Theme)
+	if err != nil {
+		return nil //nolint:nilerr // an unknown theme keeps the default palette
+	}
+	theme.SetPalette(p)
+	return nil
+}
@@ -0,0 +1,152 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+	"github.com/entireio/cli/cmd/entire/cli/strategy"
+	"github.com/spf13/cobra"
+)
+
+func newCommentCmd() *cobra.Command {
+	var replyToFlag string
+
+	cmd := &cobra.Command{
+		Use:   "comment <checkpoint-id> [message]",
+		Short: "Comment on a checkpoint, or show its comments",
+		Long: `Comment adds a note to a committed checkpoint, stored on the
+entire/checkpoints/v1 branch with your git name and email and the time. Push
+the metadata branch to share comments with your team.
+
+Use --reply-to with a comment ID (or prefix) to answer a comment; replies are
+shown threaded under it. Without a message, the checkpoint's comments are
+listed. 'entire explain --checkpoint' shows them too.
+
+Comments are redacted like prompts before they are stored.`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			if _, err := paths.WorktreeRoot(ctx); err != nil {
+				return paths.ErrNotGitRepository
+			}
+			if len(args) == 1 {
+				if replyToFlag != "" {
+					return errors.New("--reply-to needs a message")
+				}
+				return runCommentList(ctx, cmd.OutOrStdout(), args[0])
+			}
+			return runComment(ctx, cmd.OutOrStdout(), args[0], args[1], replyToFlag)
+		},
+	}
+
+	cmd.Flags().StringVar(&replyToFlag, "reply-to", "", "Comment ID (or prefix) to reply to")
+
+	return cmd
+}
+
+func runComment(ctx context.Context, w io.Writer, checkpointIDPrefix, message, replyTo string) error {
+	repo, err := openRepository(ctx)
+	if err != nil {
+		return fmt.Errorf("not a git repository: %w", err)
+	}
+	store, err := strategy.CommittedStore(ctx, checkpoint.NewGitStore(repo))
+	if err != nil {
+		return err //nolint:wrapcheck // already names the setting
+	}
+
+	checkpointID, err := resolveCommittedCheckpointID(ctx, store, checkpointIDPrefix)
+	if err != nil {
+		return err
+	}
+	if replyTo != "" {
+		comments, err := store.ReadComments(ctx, checkpointID)
+		if err != nil {
+			return fmt.Errorf("failed to read comments: %w", err)
+		}
+		if replyTo, err = resolveCommentID(comments, replyTo); err != nil {
+			return err
+		}
+	}
+
+	comment, err := store.AddComment(ctx, checkpointID, checkpoint.Comment{ReplyTo: replyTo, Body: message})
+	if err != nil {
+		return fmt.Errorf("failed to add comment: %w", err)
+	}
+	fmt.Fprintf(w, "Added comment %s to checkpoint %s\n", comment.ID, checkpointID)
+	return nil
+}
+
+func runCommentList(ctx context.Context, w io.Writer, checkpointIDPrefix string) error {
+	repo, err := openRepository(ctx)
+	if err != nil {
+		return fmt.Errorf("not a git repository: %w", err)
+	}
+	store, err := strategy.CommittedStore(ctx, checkpoint.NewGitStore(repo))
+	if err != nil {
+		return err //nolint:wrapcheck // already names the setting
+	}
+
+	checkpointID, err := resolveCommittedCheckpointID(ctx, store, checkpointIDPrefix)
+	if err != nil {
+		return err
+	}
+	comments, err := store.ReadComments(ctx, checkpointID)
+	if err != nil {
+		return fmt.Errorf("failed to read comments: %w", err)
+	}
+	if len(comments) == 0 {
+		fmt.Fprintf(w, "No comments on checkpoint %s.\n", checkpointID)
+		return nil
+	}
+	fmt.Fprint(w, formatComments(comments))
+	return nil
+}
+
+// resolveCommentID expands a comment ID prefix, failing if it matches no
+// comment or more than one.
+func resolveCommentID(comments []checkpoint.Comment, prefix string) (string, error) {
+	var matches []string
+	for _, c := range comments {
+		if strings.HasPrefix(c.ID, prefix) {
+			matches = append(matches, c.ID)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("comment not found: %s", prefix)
+	case 1:
+		return matches[0], nil
+	default:
+		return "", fmt.Errorf("ambiguous comment prefix %q matches %d comments", prefix, len(matches))
+	}
+}
+
+// formatComments renders comments as indented threads.
+func formatComments(comments []checkpoint.Comment) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Comments: (%d)\n", len(comments))
+	for _, c := range checkpoint.ThreadComments(comments) {
+		indent := strings.Repeat("    ", c.Depth+1)
+		author := c.AuthorName
+		if author == "" {
+			author = c.AuthorEmail
+		}
+		verdict := ""
+		if c.Verdict != "" {
+			verdict = "  [" + string(c.Verdict) + "]"
+		}
+		fmt.Fprintf(&sb, "%s%s  %s  %s%s\n", indent, c.ID, author, c.Timestamp.Local().Format("2006-01-02 15:04"), verdict)
+		if c.Body == "" {
+			continue
+		}
+		for _, line := range strings.Split(c.Body, "\n") {
+			fmt.Fprintf(&sb, "%s  %s\n", indent, line)
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,51 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+)
+
+func TestRunComment_ReplyAndList(t *testing.T) {
+	repo, _ := setupCleanTestRepo(t)
+	store := checkpoint.NewGitStore(repo)
+	ctx := context.Background()
+
+	if err := store.WriteCommitted(ctx, checkpoint.WriteCommittedOptions{
+		CheckpointID: id.MustCheckpointID("a1b2c3d4e5f6"),
+		SessionID:    "session-1",
+		Strategy:     "manual-commit",
+		Transcript:   []byte(`{"type":"user"}` + "\n"),
+		AuthorName:   "Test",
+		AuthorEmail:  "test@test.com",
+	}); err != nil {
+		t.Fatalf("WriteCommitted() error = %v", err)
+	}
+
+	if err := runComment(ctx, &bytes.Buffer{}, "a1b2", "Why drop the cache?", ""); err != nil {
+		t.Fatalf("runComment() error = %v", err)
+	}
+	comments, err := store.ReadComments(ctx, id.MustCheckpointID("a1b2c3d4e5f6"))
+	if err != nil || len(comments) != 1 {
+		t.Fatalf("ReadComments() = %v, %v", comments, err)
+	}
+	if err := runComment(ctx, &bytes.Buffer{}, "a1b2", "It was stale.", comments[0].ID[:6]); err != nil {
+		t.Fatalf("runComment() reply error = %v", err)
+	}
+	if err := runComment(ctx, &bytes.Buffer{}, "a1b2", "orphan", "zzz"); err == nil || !strings.Contains(err.Error(), "comment not found") {
+		t.Errorf("runComment() reply to unknown comment error = %v", err)
+	}
+
+	var out bytes.Buffer
+	if err := runCommentList(ctx, &out, "a1b2"); err != nil {
+		t.Fatalf("runCommentList() error = %v", err)
+	}
+	got := out.String()
+	if !strings.Contains(got, "Comments: (2)") || !strings.Contains(got, "\n          It was stale.\n") {
+		t.Errorf("reply should be listed nested under its comment, got:\n%s", got)
+	}
+}
@@ -1,11 +1,60 @@
 package cli
 
 import (
+	"fmt"
 	"strings"
+	"text/template"
 
+	"github.com/entireio/cli/cmd/entire/cli/classify"
 	"github.com/entireio/cli/cmd/entire/cli/stringutil"
 )
 
+// promptExcerptLength caps the prompt excerpt available to commit_message_template.
+const promptExcerptLength = 60
+
+// commitMessageData is the data passed to the commit_message_template setting.
+type commitMessageData struct {
+	Type    classify.Label
+	Message string
+
+	// Turn is the session's turn number, counting from 1; 0 if unknown
+	Turn int
+
+	// Prompt is the first line of the turn's last prompt, truncated
+	Prompt string
+
+	// Files is the number of files the checkpoint changes
+	Files int
+}
+
+// applyCommitMessageTemplate renders data.Message through tmpl (a text/template).
+// An empty template returns the message unchanged.
+func applyCommitMessageTemplate(tmpl string, data commitMessageData) (string, error) {
+	message := data.Message
+	if tmpl == "" {
+		return message, nil
+	}
+	t, err := template.New("commit_message").Option("missingkey=error").Parse(tmpl)
+	if err != nil {
+		return "", fmt.Errorf("invalid commit_message_template: %w", err)
+	}
+	var sb strings.Builder
+	if err := t.Execute(&sb, data); err != nil {
+		return "", fmt.Errorf("failed to render commit_message_template: %w", err)
+	}
+	rendered := strings.TrimSpace(sb.String())
+	if rendered == "" {
+		return message, nil
+	}
+	return rendered, nil
+}
+
+// promptExcerpt returns the first line of prompt, truncated for a commit subject.
+func promptExcerpt(prompt string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(prompt), "\n")
+	return stringutil.TruncateRunes(strings.TrimSpace(line), promptExcerptLength, "...")
+}
+
 // generateCommitMessage creates a commit message from the user's original prompt
 func generateCommitMessage(originalPrompt string) string {
 	if originalPrompt != "" {
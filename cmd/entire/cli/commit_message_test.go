@@ -1,7 +1,10 @@
 package cli
 
 import (
+	"strings"
 	"testing"
+
+	"github.com/entireio/cli/cmd/entire/cli/classify"
 )
 
 func TestCleanPromptForCommit(t *testing.T) {
@@ -230,3 +233,59 @@ func TestGenerateCommitMessage(t *testing.T) {
 		})
 	}
 }
+
+func TestApplyCommitMessageTemplate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		tmpl    string
+		want    string
+		wantErr bool
+	}{
+		{name: "empty template keeps message", tmpl: "", want: "Fix the login bug"},
+		{name: "conventional prefix", tmpl: "{{.Type}}: {{.Message}}", want: "fix: Fix the login bug"},
+		{name: "blank render keeps message", tmpl: "  ", want: "Fix the login bug"},
+		{name: "turn and files", tmpl: "Turn {{.Turn}}: {{.Prompt}} ({{.Files}} files)", want: "Turn 3: fix the login bug please (2 files)"},
+		{name: "unknown field", tmpl: "{{.Scope}}", wantErr: true},
+		{name: "parse error", tmpl: "{{.Type", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := applyCommitMessageTemplate(tt.tmpl, commitMessageData{
+				Type:    classify.LabelFix,
+				Message: "Fix the login bug",
+				Turn:    3,
+				Prompt:  "fix the login bug please",
+				Files:   2,
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("applyCommitMessageTemplate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("applyCommitMessageTemplate() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPromptExcerpt(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		prompt string
+		want   string
+	}{
+		{"", ""},
+		{"  fix the bug  ", "fix the bug"},
+		{"fix the bug\n\nstack trace follows", "fix the bug"},
+		{strings.Repeat("a", 80), strings.Repeat("a", 57) + "..."},
+	}
+	for _, tt := range tests {
+		if got := promptExcerpt(tt.prompt); got != tt.want {
+			t.Errorf("promptExcerpt(%q) = %q, want %q", tt.prompt, got, tt.want)
+		}
+	}
+}
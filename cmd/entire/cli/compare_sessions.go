@@ -0,0 +1,225 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+	"github.com/entireio/cli/cmd/entire/cli/strategy"
+	"github.com/entireio/cli/cmd/entire/cli/transcript"
+
+	"github.com/spf13/cobra"
+)
+
+// sessionReport aggregates one session's committed checkpoints for comparison.
+type sessionReport struct {
+	SessionID    string
+	Agent        string
+	Model        string
+	Checkpoints  int
+	Files        map[string]bool
+	Insertions   int
+	Deletions    int
+	HasDiffStats bool
+	Turns        int
+	TestsPassed  int
+	TestsFailed  int
+	InputTokens  int // fresh input plus cache reads and writes
+	OutputTokens int
+	APICalls     int
+	CostUSD      float64 // estimated from token usage and model prices
+}
+
+func newCompareSessionsCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "compare-sessions <session-a> <session-b>",
+		Short: "Compare two sessions side by side",
+		Long: `Compare-sessions reports two sessions side by side, for example two attempts
+at the same task with different models or prompting strategies.
+
+For each session it sums its committed checkpoints: diff size, agent turns,
+test suite runs found in the transcript (passed/failed), and token usage as
+the cost of the attempt. Session IDs may be abbreviated to a unique prefix.`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			if _, err := paths.WorktreeRoot(ctx); err != nil {
+				return paths.ErrNotGitRepository
+			}
+			return runCompareSessions(ctx, cmd.OutOrStdout(), args[0], args[1])
+		},
+	}
+}
+
+func runCompareSessions(ctx context.Context, w io.Writer, sessionA, sessionB string) error {
+	repo, err := openRepository(ctx)
+	if err != nil {
+		return err
+	}
+	store, err := strategy.CommittedStore(ctx, checkpoint.NewGitStore(repo))
+	if err != nil {
+		return err //nolint:wrapcheck // already names the setting
+	}
+
+	committed, err := store.ListCommitted(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list checkpoints: %w", err)
+	}
+
+	a, err := buildSessionReport(ctx, store, committed, sessionA)
+	if err != nil {
+		return err
+	}
+	b, err := buildSessionReport(ctx, store, committed, sessionB)
+	if err != nil {
+		return err
+	}
+
+	writeSessionComparison(w, a, b)
+	return nil
+}
+
+// buildSessionReport sums every committed checkpoint of the session whose ID
+// is, or starts with, prefix. It fails if the prefix matches no session or
+// more than one.
+func buildSessionReport(ctx context.Context, store checkpoint.CommittedStore, committed []checkpoint.CommittedInfo, prefix string) (*sessionReport, error) {
+	reports := make(map[string]*sessionReport)
+	for _, info := range committed {
+		for i := range info.SessionCount {
+			content, err := store.ReadSessionContent(ctx, info.CheckpointID, i)
+			if err != nil {
+				continue
+			}
+			meta := content.Metadata
+			if !strings.HasPrefix(meta.SessionID, prefix) {
+				continue
+			}
+			r := reports[meta.SessionID]
+			if r == nil {
+				r = &sessionReport{SessionID: meta.SessionID, Files: make(map[string]bool)}
+				reports[meta.SessionID] = r
+			}
+			addToSessionReport(r, info, content)
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err //nolint:wrapcheck // Propagating context cancellation
+	}
+
+	if r, ok := reports[prefix]; ok {
+		return r, nil
+	}
+	if len(reports) == 0 {
+		return nil, fmt.Errorf("no committed checkpoints for session %s", prefix)
+	}
+	ids := make([]string, 0, len(reports))
+	for sessionID := range reports {
+		ids = append(ids, sessionID)
+	}
+	if len(ids) == 1 {
+		return reports[ids[0]], nil
+	}
+	sort.Strings(ids)
+	return nil, fmt.Errorf("ambiguous session prefix %q matches %d sessions: %s", prefix, len(ids), strings.Join(ids, ", "))
+}
+
+// addToSessionReport adds one checkpoint's share of the session to r.
+func addToSessionReport(r *sessionReport, info checkpoint.CommittedInfo, content *checkpoint.SessionContent) {
+	meta := content.Metadata
+	r.Checkpoints++
+	if meta.Agent != "" {
+		r.Agent = string(meta.Agent)
+	}
+	if meta.Model != "" {
+		r.Model = meta.Model
+	}
+	for _, file := range meta.FilesTouched {
+		r.Files[file] = true
+	}
+	if info.DiffStats != nil {
+		r.HasDiffStats = true
+		r.Insertions += info.DiffStats.Insertions
+		r.Deletions += info.DiffStats.Deletions
+	}
+	r.Turns += len(meta.Turns)
+
+	// The stored transcript is the whole session so far; only count this checkpoint's part
+	passed, failed := transcript.TestRuns(transcript.SliceFromLine(content.Transcript, meta.GetTranscriptStart()))
+	r.TestsPassed += passed
+	r.TestsFailed += failed
+
+	for usage := meta.TokenUsage; usage != nil; usage = usage.SubagentTokens {
+		r.InputTokens += usage.InputTokens + usage.CacheCreationTokens + usage.CacheReadTokens
+		r.OutputTokens += usage.OutputTokens
+		r.APICalls += usage.APICallCount
+	}
+	r.CostUSD += meta.EstimatedCostUSD
+}
+
+// writeSessionComparison renders the two reports as a side-by-side table.
+func writeSessionComparison(w io.Writer, a, b *sessionReport) {
+	rows := [][3]string{
+		{"Session", a.SessionID, b.SessionID},
+		{"Agent", orDash(a.Agent), orDash(b.Agent)},
+		{"Model", orDash(a.Model), orDash(b.Model)},
+		{"Checkpoints", strconv.Itoa(a.Checkpoints), strconv.Itoa(b.Checkpoints)},
+		{"Files", strconv.Itoa(len(a.Files)), strconv.Itoa(len(b.Files))},
+		{"Lines", a.formatLines(), b.formatLines()},
+		{"Turns", countOrDash(a.Turns), countOrDash(b.Turns)},
+		{"Tests", a.formatTests(), b.formatTests()},
+		{"Tokens in", countOrDash(a.InputTokens), countOrDash(b.InputTokens)},
+		{"Tokens out", countOrDash(a.OutputTokens), countOrDash(b.OutputTokens)},
+		{"API calls", countOrDash(a.APICalls), countOrDash(b.APICalls)},
+		{"Cost", a.formatCost(), b.formatCost()},
+	}
+
+	width := 0
+	for _, row := range rows {
+		width = max(width, len(row[1]))
+	}
+	for _, row := range rows {
+		fmt.Fprintf(w, "%-12s %-*s  %s\n", row[0], width, row[1], row[2])
+	}
+}
+
+func (r *sessionReport) formatLines() string {
+	if !r.HasDiffStats {
+		return "-"
+	}
+	return fmt.Sprintf("+%d -%d", r.Insertions, r.Deletions)
+}
+
+func (r *sessionReport) formatTests() string {
+	if r.TestsPassed == 0 && r.TestsFailed == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%d passed, %d failed", r.TestsPassed, r.TestsFailed)
+}
+
+func (r *sessionReport) formatCost() string {
+	if r.CostUSD == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("$%.2f", r.CostUSD)
+}
+
+// orDash formats s, or "-" when it's empty.
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// countOrDash formats n, or "-" when nothing was recorded.
+func countOrDash(n int) string {
+	if n == 0 {
+		return "-"
+	}
+	return strconv.Itoa(n)
+}
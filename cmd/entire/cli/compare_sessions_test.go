@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/entireio/cli/cmd/entire/cli/agent"
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+)
+
+func TestRunCompareSessions(t *testing.T) {
+	setupCleanTestRepo(t)
+	ctx := context.Background()
+
+	repo, err := openRepository(ctx)
+	if err != nil {
+		t.Fatalf("openRepository() error = %v", err)
+	}
+	store := checkpoint.NewGitStore(repo)
+	failingTest := `{"type":"assistant","message":{"content":[{"type":"tool_use","id":"t1","input":{"command":"go test ./..."}}]}}
+{"type":"user","message":{"content":[{"type":"tool_result","tool_use_id":"t1","is_error":true}]}}
+`
+	for _, cp := range []struct {
+		id, sessionID, model, transcript string
+		outputTokens                     int
+	}{
+		{"c1c1c1c1c1c1", "2026-01-01-attempt-a", "claude-sonnet-4-5", failingTest, 100},
+		{"c2c2c2c2c2c2", "2026-01-01-attempt-a", "claude-sonnet-4-5", `{"type":"user"}` + "\n", 50},
+		{"c3c3c3c3c3c3", "2026-01-02-attempt-b", "gpt-5", `{"type":"user"}` + "\n", 400},
+	} {
+		if err := store.WriteCommitted(ctx, checkpoint.WriteCommittedOptions{
+			CheckpointID: id.MustCheckpointID(cp.id),
+			SessionID:    cp.sessionID,
+			Strategy:     "manual-commit",
+			Transcript:   []byte(cp.transcript),
+			FilesTouched: []string{"main.go"},
+			AuthorName:   "Test",
+			AuthorEmail:  "test@test.com",
+			Model:        cp.model,
+			TokenUsage:   &agent.TokenUsage{InputTokens: 10, OutputTokens: cp.outputTokens, APICallCount: 1},
+		}); err != nil {
+			t.Fatalf("WriteCommitted() error = %v", err)
+		}
+	}
+
+	var stdout bytes.Buffer
+	if err := runCompareSessions(ctx, &stdout, "2026-01-01", "2026-01-02-attempt-b"); err != nil {
+		t.Fatalf("runCompareSessions() error = %v", err)
+	}
+	out := stdout.String()
+	for _, want := range []string{
+		"Session      2026-01-01-attempt-a  2026-01-02-attempt-b",
+		"Model        claude-sonnet-4-5     gpt-5",
+		"Checkpoints  2                     1",
+		"Tests        0 passed, 1 failed    -",
+		"Tokens out   150                   400",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+
+	if err := runCompareSessions(ctx, &stdout, "2026-01", "2026-01-02"); err == nil || !strings.Contains(err.Error(), "ambiguous") {
+		t.Errorf("runCompareSessions() with ambiguous prefix error = %v, want ambiguous", err)
+	}
+	if err := runCompareSessions(ctx, &stdout, "missing", "2026-01-02"); err == nil {
+		t.Error("runCompareSessions() with unknown session should fail")
+	}
+}
@@ -0,0 +1,216 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/entireio/cli/cmd/entire/cli/age"
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+	"github.com/entireio/cli/cmd/entire/cli/settings"
+	"github.com/spf13/cobra"
+)
+
+func newConfigCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Configure how Entire stores checkpoints",
+	}
+
+	cmd.AddCommand(newConfigEncryptionCmd())
+
+	return cmd
+}
+
+// encryptionOptions are the flags of `entire config encryption`.
+type encryptionOptions struct {
+	Recipients  []string
+	GenerateKey bool
+	Disable     bool
+	Local       bool
+}
+
+func newConfigEncryptionCmd() *cobra.Command {
+	var opts encryptionOptions
+
+	cmd := &cobra.Command{
+		Use:   "encryption",
+		Short: "Encrypt checkpoint transcripts, prompts, and context with age keys",
+		Long: `Encryption stores the transcript, prompts, and context of new checkpoints
+encrypted to one or more age recipients (public keys, "age1..."), so the
+entire/checkpoints/v1 branch can be pushed without exposing them. Metadata,
+such as files touched, token usage, and summaries, stays readable.
+
+Recipients are saved as "encryption": {"recipients": [...]} in
+.entire/settings.json, so everyone committing to the repository encrypts to
+the same keys. Checkpoints are decrypted with the age identity (private key)
+in ` + "$" + checkpoint.IdentityFileEnv + `, or the default identity file shown by
+'entire config encryption'. Without a matching identity, commands that show
+encrypted content fail and say so.
+
+--generate-key creates that identity file, if needed, and adds its public key
+as a recipient. Existing checkpoints are not re-encrypted, and --disable only
+stops encrypting new ones.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := cmd.Context()
+			if _, err := paths.WorktreeRoot(ctx); err != nil {
+				return paths.ErrNotGitRepository
+			}
+			if opts.Disable && (opts.GenerateKey || len(opts.Recipients) > 0) {
+				return errors.New("--disable cannot be combined with --recipient or --generate-key")
+			}
+			return runConfigEncryption(ctx, cmd.OutOrStdout(), opts)
+		},
+	}
+
+	cmd.Flags().StringArrayVar(&opts.Recipients, "recipient", nil, "Add an age public key to encrypt to (repeatable)")
+	cmd.Flags().BoolVar(&opts.GenerateKey, "generate-key", false, "Create an age identity if needed and encrypt to its public key")
+	cmd.Flags().BoolVar(&opts.Disable, "disable", false, "Stop encrypting new checkpoints")
+	cmd.Flags().BoolVar(&opts.Local, "local", false, "Write to .entire/settings.local.json instead of .entire/settings.json")
+
+	return cmd
+}
+
+func runConfigEncryption(ctx context.Context, w io.Writer, opts encryptionOptions) error {
+	s, err := LoadEntireSettings(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load settings: %w", err)
+	}
+
+	if !opts.Disable && !opts.GenerateKey && len(opts.Recipients) == 0 {
+		return printEncryptionStatus(w, s.GetEncryptionRecipients())
+	}
+
+	var recipients []string
+	if !opts.Disable {
+		recipients = s.GetEncryptionRecipients()
+		for _, key := range opts.Recipients {
+			r, err := age.ParseRecipient(key)
+			if err != nil {
+				return err //nolint:wrapcheck // already names the key
+			}
+			recipients = appendRecipient(recipients, r.String())
+		}
+		if opts.GenerateKey {
+			identity, created, err := ensureIdentity()
+			if err != nil {
+				return err
+			}
+			path, _ := checkpoint.IdentityFile() //nolint:errcheck // ensureIdentity resolved it
+			if created {
+				fmt.Fprintf(w, "Created age identity %s\n", path)
+				fmt.Fprintln(w, "Back it up: checkpoints encrypted to it can't be read without it.")
+			} else {
+				fmt.Fprintf(w, "Using existing age identity %s\n", path)
+			}
+			recipients = appendRecipient(recipients, identity.Recipient().String())
+		}
+	}
+
+	if len(recipients) == 0 {
+		s.Encryption = nil
+	} else {
+		s.Encryption = &settings.EncryptionSettings{Recipients: recipients}
+	}
+	if opts.Local {
+		err = SaveEntireSettingsLocal(ctx, s)
+	} else {
+		err = SaveEntireSettings(ctx, s)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to save settings: %w", err)
+	}
+
+	if opts.Disable {
+		fmt.Fprintln(w, "Checkpoint encryption disabled. Existing encrypted checkpoints stay encrypted.")
+		return nil
+	}
+	return printEncryptionStatus(w, recipients)
+}
+
+// ensureIdentity returns the identity in checkpoint.IdentityFile, creating
+// the file with a new identity if it doesn't exist.
+func ensureIdentity() (*age.Identity, bool, error) {
+	ids, err := checkpoint.LoadIdentities()
+	if err != nil {
+		return nil, false, err //nolint:wrapcheck // already descriptive
+	}
+	if len(ids) > 0 {
+		return ids[0], false, nil
+	}
+
+	path, err := checkpoint.IdentityFile()
+	if err != nil {
+		return nil, false, err //nolint:wrapcheck // already descriptive
+	}
+	identity, err := age.GenerateIdentity()
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to generate age identity: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, false, fmt.Errorf("failed to create identity directory: %w", err)
+	}
+	content := fmt.Sprintf("# public key: %s\n%s\n", identity.Recipient(), identity)
+	// O_EXCL so a concurrent run can't have its key silently replaced
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600) //nolint:gosec // path is the configured identity file
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to create identity file: %w", err)
+	}
+	if _, err := f.WriteString(content); err != nil {
+		_ = f.Close()
+		return nil, false, fmt.Errorf("failed to write identity file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return nil, false, fmt.Errorf("failed to write identity file: %w", err)
+	}
+	return identity, true, nil
+}
+
+func appendRecipient(recipients []string, key string) []string {
+	if slices.Contains(recipients, key) {
+		return recipients
+	}
+	return append(recipients, key)
+}
+
+// printEncryptionStatus shows the configured recipients and whether the
+// local identity can read checkpoints encrypted to them.
+func printEncryptionStatus(w io.Writer, recipients []string) error {
+	if len(recipients) == 0 {
+		fmt.Fprintln(w, "Checkpoint encryption: off")
+	} else {
+		fmt.Fprintln(w, "Checkpoint encryption: on")
+		fmt.Fprintln(w, "Recipients:")
+		for _, r := range recipients {
+			fmt.Fprintf(w, "  %s\n", r)
+		}
+	}
+
+	path, err := checkpoint.IdentityFile()
+	if err != nil {
+		return err //nolint:wrapcheck // already descriptive
+	}
+	ids, err := checkpoint.LoadIdentities()
+	if err != nil {
+		return err //nolint:wrapcheck // already descriptive
+	}
+	if len(ids) == 0 {
+		fmt.Fprintf(w, "Identity: none (%s does not exist)\n", path)
+		return nil
+	}
+	fmt.Fprintf(w, "Identity: %s\n", path)
+	for _, id := range ids {
+		status := "not a recipient"
+		if slices.Contains(recipients, id.Recipient().String()) {
+			status = "recipient"
+		}
+		fmt.Fprintf(w, "  %s (%s)\n", id.Recipient(), status)
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+)
+
+func TestRunConfigEncryption_GenerateKeyAndDisable(t *testing.T) {
+	setupExecTestRepo(t)
+	identityFile := filepath.Join(t.TempDir(), "keys", "age-identity.txt")
+	t.Setenv(checkpoint.IdentityFileEnv, identityFile)
+	ctx := context.Background()
+
+	var out bytes.Buffer
+	if err := runConfigEncryption(ctx, &out, encryptionOptions{GenerateKey: true}); err != nil {
+		t.Fatalf("runConfigEncryption(--generate-key) error = %v", err)
+	}
+	info, err := os.Stat(identityFile)
+	if err != nil {
+		t.Fatalf("identity file not written: %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Errorf("identity file mode = %o, want 600", perm)
+	}
+	ids, err := checkpoint.LoadIdentities()
+	if err != nil || len(ids) != 1 {
+		t.Fatalf("LoadIdentities() = %v, %v", ids, err)
+	}
+	s, err := LoadEntireSettings(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := s.GetEncryptionRecipients(); len(got) != 1 || got[0] != ids[0].Recipient().String() {
+		t.Errorf("recipients = %v, want the generated key", got)
+	}
+
+	// A second run reuses the identity
+	out.Reset()
+	if err := runConfigEncryption(ctx, &out, encryptionOptions{GenerateKey: true}); err != nil {
+		t.Fatalf("runConfigEncryption(--generate-key) again error = %v", err)
+	}
+	if !strings.Contains(out.String(), "Using existing age identity") {
+		t.Errorf("output = %q", out.String())
+	}
+	if s, _ := LoadEntireSettings(ctx); len(s.GetEncryptionRecipients()) != 1 {
+		t.Errorf("recipients = %v, want no duplicate", s.GetEncryptionRecipients())
+	}
+
+	if err := runConfigEncryption(ctx, &out, encryptionOptions{Disable: true}); err != nil {
+		t.Fatalf("runConfigEncryption(--disable) error = %v", err)
+	}
+	if s, _ := LoadEntireSettings(ctx); s.GetEncryptionRecipients() != nil {
+		t.Errorf("recipients = %v after --disable", s.GetEncryptionRecipients())
+	}
+}
+
+func TestRunConfigEncryption_RejectsInvalidRecipient(t *testing.T) {
+	setupExecTestRepo(t)
+	t.Setenv(checkpoint.IdentityFileEnv, filepath.Join(t.TempDir(), "age-identity.txt"))
+
+	var out bytes.Buffer
+	if err := runConfigEncryption(context.Background(), &out, encryptionOptions{Recipients: []string{"age1notakey"}}); err == nil {
+		t.Error("runConfigEncryption() should reject an invalid recipient")
+	}
+}
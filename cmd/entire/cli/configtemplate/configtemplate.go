@@ -0,0 +1,299 @@
+// Package configtemplate initializes and updates a repository's Entire
+// configuration from a shared template, so an org can distribute one set of
+// settings and command policies across many repositories.
+//
+// A template is a directory holding settings.json and/or policy.json, with the
+// same format as the files under .entire/. It can be a local path, a git
+// repository URL (cloned shallowly), or an HTTP(S) URL the files are served
+// under. The template applied last is recorded in .entire/template.json, which
+// lets an update tell template changes apart from local overrides.
+package configtemplate
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/entireio/cli/cmd/entire/cli/jsonutil"
+	"github.com/entireio/cli/cmd/entire/cli/policy"
+	"github.com/entireio/cli/cmd/entire/cli/settings"
+)
+
+const (
+	// StateFile records the template source and the content applied from it,
+	// relative to the repository root.
+	StateFile = ".entire/template.json"
+
+	settingsFileName = "settings.json"
+	policyFileName   = "policy.json"
+
+	// maxFileSize bounds template files fetched over HTTP.
+	maxFileSize = 1 << 20
+)
+
+// ErrNoTemplate is returned when the repository wasn't initialized from a template.
+var ErrNoTemplate = errors.New("not initialized from a template")
+
+// Files is the content of a template. A nil field means the template doesn't
+// provide that file.
+type Files struct {
+	Settings []byte
+	Policy   []byte
+}
+
+// State is the content of StateFile.
+type State struct {
+	// Source is where the template was fetched from.
+	Source string `json:"source"`
+
+	// UpdatedAt is when the template was last applied.
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Settings and Policy are the template files as last applied. Local files
+	// that still match them have no local overrides.
+	Settings string `json:"settings,omitempty"`
+	Policy   string `json:"policy,omitempty"`
+}
+
+// Fetch reads a template from source: a local directory, a git repository
+// URL, or an HTTP(S) URL under which settings.json and policy.json are served.
+// The files are validated before they are returned.
+func Fetch(ctx context.Context, source string) (*Files, error) {
+	var files *Files
+	var err error
+	switch {
+	case isGitURL(source):
+		files, err = fetchGit(ctx, source)
+	case strings.HasPrefix(source, "https://") || strings.HasPrefix(source, "http://"):
+		files, err = fetchHTTP(ctx, source)
+	default:
+		files, err = readDir(source)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if files.Settings == nil && files.Policy == nil {
+		return nil, fmt.Errorf("template %s has no %s or %s", source, settingsFileName, policyFileName)
+	}
+	if files.Settings != nil {
+		if _, err := settings.Parse(files.Settings); err != nil {
+			return nil, fmt.Errorf("invalid template %s: %w", settingsFileName, err)
+		}
+	}
+	if files.Policy != nil {
+		if _, err := policy.Parse(files.Policy); err != nil {
+			return nil, fmt.Errorf("invalid template %s: %w", policyFileName, err)
+		}
+	}
+	return files, nil
+}
+
+// isGitURL reports whether source names a git repository rather than a
+// directory of files served over HTTP.
+func isGitURL(source string) bool {
+	return strings.HasPrefix(source, "git@") ||
+		strings.HasPrefix(source, "ssh://") ||
+		strings.HasPrefix(source, "git://") ||
+		strings.HasSuffix(source, ".git")
+}
+
+// readDir reads the template files from a local directory.
+func readDir(dir string) (*Files, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("template not found: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("template %s is not a directory", dir)
+	}
+
+	files := &Files{}
+	for name, dst := range map[string]*[]byte{settingsFileName: &files.Settings, policyFileName: &files.Policy} {
+		data, err := os.ReadFile(filepath.Join(dir, name)) //nolint:gosec // path is the user-chosen template
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, fmt.Errorf("reading template %s: %w", name, err)
+		}
+		*dst = data
+	}
+	return files, nil
+}
+
+// fetchGit shallow-clones a template repository and reads its files.
+func fetchGit(ctx context.Context, url string) (*Files, error) {
+	dir, err := os.MkdirTemp("", "entire-template-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp dir: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	cmd := exec.CommandContext(ctx, "git", "clone", "--quiet", "--depth", "1", url, dir)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("failed to clone template %s: %s", url, strings.TrimSpace(string(output)))
+	}
+	return readDir(dir)
+}
+
+// fetchHTTP downloads the template files from baseURL.
+func fetchHTTP(ctx context.Context, baseURL string) (*Files, error) {
+	baseURL = strings.TrimSuffix(baseURL, "/")
+	files := &Files{}
+	for name, dst := range map[string]*[]byte{settingsFileName: &files.Settings, policyFileName: &files.Policy} {
+		data, err := fetchHTTPFile(ctx, baseURL+"/"+name)
+		if err != nil {
+			return nil, err
+		}
+		*dst = data
+	}
+	return files, nil
+}
+
+// fetchHTTPFile returns the body at url, or nil if the server has no such file.
+func fetchHTTPFile(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create template request: %w", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("failed to fetch %s: %s", url, resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxFileSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", url, err)
+	}
+	return data, nil
+}
+
+// MergeSettings applies a new version of the template settings to the local
+// settings, key by key. A key whose local value still matches the previously
+// applied template (base) takes the new template value, or is removed if the
+// template dropped it. A key the repository changed is kept and, if the
+// template changed it too, reported in overrides. Keys only set locally are
+// kept as they are.
+func MergeSettings(base, local, next []byte) (merged []byte, overrides []string, err error) {
+	baseKeys, err := decodeKeys(base)
+	if err != nil {
+		return nil, nil, fmt.Errorf("parsing previous template settings: %w", err)
+	}
+	localKeys, err := decodeKeys(local)
+	if err != nil {
+		return nil, nil, fmt.Errorf("parsing local settings: %w", err)
+	}
+	nextKeys, err := decodeKeys(next)
+	if err != nil {
+		return nil, nil, fmt.Errorf("parsing template settings: %w", err)
+	}
+
+	keys := make(map[string]bool)
+	for key := range baseKeys {
+		keys[key] = true
+	}
+	for key := range nextKeys {
+		keys[key] = true
+	}
+
+	for key := range keys {
+		baseValue, inBase := baseKeys[key]
+		localValue, inLocal := localKeys[key]
+		nextValue, inNext := nextKeys[key]
+
+		if inLocal != inBase || (inLocal && !jsonEqual(localValue, baseValue)) {
+			// Changed locally: keep it, but say so when the template moved elsewhere
+			templateChanged := inNext != inBase || (inNext && !jsonEqual(nextValue, baseValue))
+			converged := inNext == inLocal && (!inNext || jsonEqual(nextValue, localValue))
+			if templateChanged && !converged {
+				overrides = append(overrides, key)
+			}
+			continue
+		}
+		if inNext {
+			localKeys[key] = nextValue
+		} else {
+			delete(localKeys, key)
+		}
+	}
+	sort.Strings(overrides)
+
+	merged, err = jsonutil.MarshalIndentWithNewline(localKeys, "", "  ")
+	if err != nil {
+		return nil, nil, fmt.Errorf("encoding settings: %w", err)
+	}
+	if _, err := settings.Parse(merged); err != nil {
+		return nil, nil, fmt.Errorf("merged settings are invalid: %w", err)
+	}
+	return merged, overrides, nil
+}
+
+// decodeKeys splits a settings object into its top-level keys.
+// Empty data is an empty object.
+func decodeKeys(data []byte) (map[string]json.RawMessage, error) {
+	keys := make(map[string]json.RawMessage)
+	if len(bytes.TrimSpace(data)) == 0 {
+		return keys, nil
+	}
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+	return keys, nil
+}
+
+// jsonEqual compares two JSON values ignoring formatting.
+func jsonEqual(a, b json.RawMessage) bool {
+	var bufA, bufB bytes.Buffer
+	if json.Compact(&bufA, a) != nil || json.Compact(&bufB, b) != nil {
+		return bytes.Equal(a, b)
+	}
+	return bytes.Equal(bufA.Bytes(), bufB.Bytes())
+}
+
+// LoadState reads the template state from stateFile.
+// Returns ErrNoTemplate if the file doesn't exist.
+func LoadState(stateFile string) (*State, error) {
+	data, err := os.ReadFile(stateFile) //nolint:gosec // path is derived from repo root
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrNoTemplate
+		}
+		return nil, fmt.Errorf("reading template state: %w", err)
+	}
+	var state State
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("parsing template state: %w", err)
+	}
+	return &state, nil
+}
+
+// SaveState writes the template state to stateFile.
+func SaveState(stateFile string, state *State) error {
+	data, err := jsonutil.MarshalIndentWithNewline(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding template state: %w", err)
+	}
+	//nolint:gosec // G306: template state is config, not secrets
+	if err := os.WriteFile(stateFile, data, 0o644); err != nil {
+		return fmt.Errorf("writing template state: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package configtemplate
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestMergeSettings(t *testing.T) {
+	t.Parallel()
+
+	base := []byte(`{"enabled": true, "log_level": "info", "auto_stash": false, "linked_repos": ["../api"]}`)
+	// The repository changed log_level and added commit_linking
+	local := []byte(`{"enabled": true, "log_level": "debug", "auto_stash": false, "linked_repos": ["../api"], "commit_linking": "always"}`)
+	// The template changed log_level and auto_stash, and dropped linked_repos
+	next := []byte(`{"enabled": true, "log_level": "warn", "auto_stash": true}`)
+
+	merged, overrides, err := MergeSettings(base, local, next)
+	if err != nil {
+		t.Fatalf("MergeSettings() error = %v", err)
+	}
+
+	var got map[string]any
+	if err := json.Unmarshal(merged, &got); err != nil {
+		t.Fatalf("merged settings are not JSON: %v", err)
+	}
+	want := map[string]any{"enabled": true, "log_level": "debug", "auto_stash": true, "commit_linking": "always"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("merged = %v, want %v", got, want)
+	}
+	if !reflect.DeepEqual(overrides, []string{"log_level"}) {
+		t.Errorf("overrides = %v, want [log_level]", overrides)
+	}
+}
+
+func TestMergeSettings_RejectsInvalidResult(t *testing.T) {
+	t.Parallel()
+
+	if _, _, err := MergeSettings(nil, nil, []byte(`{"no_such_setting": 1}`)); err == nil {
+		t.Error("MergeSettings() with unknown key should fail")
+	}
+}
+
+func TestFetch_Dir(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "settings.json"), []byte(`{"enabled": true}`), 0o644); err != nil {
+		t.Fatalf("failed to write settings: %v", err)
+	}
+
+	files, err := Fetch(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	if string(files.Settings) != `{"enabled": true}` || files.Policy != nil {
+		t.Errorf("Fetch() = %+v, want settings only", files)
+	}
+
+	if _, err := Fetch(context.Background(), t.TempDir()); err == nil {
+		t.Error("Fetch() of an empty directory should fail")
+	}
+}
+
+func TestFetch_HTTP(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/org/settings.json":
+			w.Write([]byte(`{"log_level": "warn"}`)) //nolint:errcheck // test server
+		case "/org/policy.json":
+			w.Write([]byte(`{"environments": {"ci": {"disabled_commands": ["reset"]}}}`)) //nolint:errcheck // test server
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	files, err := Fetch(context.Background(), server.URL+"/org/")
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	if files.Settings == nil || files.Policy == nil {
+		t.Errorf("Fetch() = %+v, want settings and policy", files)
+	}
+
+	if _, err := Fetch(context.Background(), server.URL+"/missing"); err == nil {
+		t.Error("Fetch() of a URL without template files should fail")
+	}
+}
@@ -0,0 +1,205 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+	"github.com/entireio/cli/cmd/entire/cli/strategy"
+	"github.com/sergi/go-diff/diffmatchpatch"
+	"github.com/spf13/cobra"
+)
+
+func newDiffCmd() *cobra.Command {
+	var contextFlag int
+
+	cmd := &cobra.Command{
+		Use:   "diff <checkpoint-id> <checkpoint-id>",
+		Short: "Show what changed between two checkpoints",
+		Long: `Diff compares two committed checkpoints and prints a unified diff of
+their prompts, their context, and the code of the commits they're linked to,
+so you can see what an agent changed between two turns.
+
+Prompts and context are taken from each checkpoint's latest session. The code
+is compared when both linked commits are in the current branch's history;
+otherwise only prompts and context are shown.`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			if _, err := paths.WorktreeRoot(ctx); err != nil {
+				return paths.ErrNotGitRepository
+			}
+			if contextFlag < 0 {
+				return errors.New("--unified must not be negative")
+			}
+			return runDiff(ctx, cmd.OutOrStdout(), cmd.ErrOrStderr(), args[0], args[1], contextFlag)
+		},
+	}
+
+	cmd.Flags().IntVarP(&contextFlag, "unified", "U", 3, "Number of context lines around each change")
+
+	return cmd
+}
+
+func runDiff(ctx context.Context, w, errW io.Writer, fromPrefix, toPrefix string, contextLines int) error {
+	repo, err := openRepository(ctx)
+	if err != nil {
+		return fmt.Errorf("not a git repository: %w", err)
+	}
+	store, err := strategy.CommittedStore(ctx, checkpoint.NewGitStore(repo))
+	if err != nil {
+		return err //nolint:wrapcheck // already names the setting
+	}
+
+	fromID, err := resolveCommittedCheckpointID(ctx, store, fromPrefix)
+	if err != nil {
+		return err
+	}
+	toID, err := resolveCommittedCheckpointID(ctx, store, toPrefix)
+	if err != nil {
+		return err
+	}
+
+	diff, err := store.DiffCheckpoints(ctx, fromID, toID)
+	if err != nil {
+		return fmt.Errorf("failed to diff checkpoints: %w", err)
+	}
+
+	if diff.Prompts.Changed() {
+		writeUnifiedDiff(w, fromID.String()+"/prompts", toID.String()+"/prompts", diff.Prompts.Before, diff.Prompts.After, contextLines)
+	}
+	if diff.Context.Changed() {
+		writeUnifiedDiff(w, fromID.String()+"/context.md", toID.String()+"/context.md", diff.Context.Before, diff.Context.After, contextLines)
+	}
+
+	if diff.FromCommit.IsZero() || diff.ToCommit.IsZero() {
+		fmt.Fprintf(errW, "Code not compared: %s isn't linked to a commit in the current branch's history\n", missingCommitCheckpoints(diff))
+		return nil
+	}
+	for _, f := range diff.Files {
+		fromName, toName := "/dev/null", "/dev/null"
+		if f.FromPath != "" {
+			fromName = "a/" + f.FromPath
+		}
+		if f.ToPath != "" {
+			toName = "b/" + f.ToPath
+		}
+		if f.Binary {
+			fmt.Fprintf(w, "Binary files %s and %s differ\n", fromName, toName)
+			continue
+		}
+		writeUnifiedDiff(w, fromName, toName, f.Before, f.After, contextLines)
+	}
+	return nil
+}
+
+// missingCommitCheckpoints names the checkpoints of diff without a linked commit.
+func missingCommitCheckpoints(diff *checkpoint.CheckpointDiff) string {
+	var missing []string
+	if diff.FromCommit.IsZero() {
+		missing = append(missing, diff.From.String())
+	}
+	if diff.ToCommit.IsZero() && diff.To != diff.From {
+		missing = append(missing, diff.To.String())
+	}
+	return strings.Join(missing, " and ")
+}
+
+// diffLine is one line of a line diff: ' ' for unchanged, '-' for removed,
+// and '+' for added.
+type diffLine struct {
+	op   byte
+	text string
+}
+
+// writeUnifiedDiff prints the difference between before and after in unified
+// diff format, with contextLines unchanged lines around each change.
+func writeUnifiedDiff(w io.Writer, fromName, toName, before, after string, contextLines int) {
+	lines := lineDiff(before, after)
+
+	fmt.Fprintf(w, "--- %s\n+++ %s\n", fromName, toName)
+	for start := 0; start < len(lines); {
+		// Find the next change, and extend the hunk while the following change
+		// is close enough for the context around them to overlap
+		first := start
+		for first < len(lines) && lines[first].op == ' ' {
+			first++
+		}
+		if first == len(lines) {
+			break
+		}
+		last := first
+		for i := first + 1; i < len(lines) && i-last <= 2*contextLines+1; i++ {
+			if lines[i].op != ' ' {
+				last = i
+			}
+		}
+
+		from := max(start, first-contextLines)
+		to := min(len(lines), last+contextLines+1)
+		writeHunk(w, lines, from, to)
+		start = to
+	}
+}
+
+// writeHunk prints lines[from:to] as one hunk, with its @@ header.
+func writeHunk(w io.Writer, lines []diffLine, from, to int) {
+	oldStart, newStart := 1, 1
+	for _, l := range lines[:from] {
+		if l.op != '+' {
+			oldStart++
+		}
+		if l.op != '-' {
+			newStart++
+		}
+	}
+	oldCount, newCount := 0, 0
+	for _, l := range lines[from:to] {
+		if l.op != '+' {
+			oldCount++
+		}
+		if l.op != '-' {
+			newCount++
+		}
+	}
+	// An empty side starts at the line before the hunk, as in git
+	if oldCount == 0 {
+		oldStart--
+	}
+	if newCount == 0 {
+		newStart--
+	}
+
+	fmt.Fprintf(w, "@@ -%d,%d +%d,%d @@\n", oldStart, oldCount, newStart, newCount)
+	for _, l := range lines[from:to] {
+		fmt.Fprintf(w, "%c%s\n", l.op, l.text)
+	}
+}
+
+// lineDiff diffs before and after line by line.
+func lineDiff(before, after string) []diffLine {
+	dmp := diffmatchpatch.New()
+	text1, text2, lineArray := dmp.DiffLinesToChars(before, after)
+	diffs := dmp.DiffCharsToLines(dmp.DiffMain(text1, text2, false), lineArray)
+
+	var lines []diffLine
+	for _, d := range diffs {
+		var op byte
+		switch d.Type {
+		case diffmatchpatch.DiffInsert:
+			op = '+'
+		case diffmatchpatch.DiffDelete:
+			op = '-'
+		case diffmatchpatch.DiffEqual:
+			op = ' '
+		}
+		for _, line := range strings.Split(strings.TrimSuffix(d.Text, "\n"), "\n") {
+			lines = append(lines, diffLine{op: op, text: line})
+		}
+	}
+	return lines
+}
@@ -0,0 +1,56 @@
+package cli
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWriteUnifiedDiff(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		before, after string
+		context       int
+		want          string
+	}{
+		{
+			name:    "one change in the middle",
+			before:  "a\nb\nc\nd\ne\n",
+			after:   "a\nb\nC\nd\ne\n",
+			context: 1,
+			want:    "--- a/f\n+++ b/f\n@@ -2,3 +2,3 @@\n b\n-c\n+C\n d\n",
+		},
+		{
+			name:    "distant changes get separate hunks",
+			before:  "1\n2\n3\n4\n5\n6\n7\n",
+			after:   "one\n2\n3\n4\n5\n6\nseven\n",
+			context: 1,
+			want:    "--- a/f\n+++ b/f\n@@ -1,2 +1,2 @@\n-1\n+one\n 2\n@@ -6,2 +6,2 @@\n 6\n-7\n+seven\n",
+		},
+		{
+			name:    "close changes share a hunk",
+			before:  "1\n2\n3\n4\n",
+			after:   "one\n2\n3\nfour\n",
+			context: 1,
+			want:    "--- a/f\n+++ b/f\n@@ -1,4 +1,4 @@\n-1\n+one\n 2\n 3\n-4\n+four\n",
+		},
+		{
+			name:    "new file",
+			before:  "",
+			after:   "x\ny\n",
+			context: 3,
+			want:    "--- a/f\n+++ b/f\n@@ -0,0 +1,2 @@\n+x\n+y\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var out bytes.Buffer
+			writeUnifiedDiff(&out, "a/f", "b/f", tt.before, tt.after, tt.context)
+			if got := out.String(); got != tt.want {
+				t.Errorf("writeUnifiedDiff() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
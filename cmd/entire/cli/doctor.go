@@ -9,6 +9,7 @@ import (
 
 	"github.com/charmbracelet/huh"
 	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/paths"
 	"github.com/entireio/cli/cmd/entire/cli/session"
 	"github.com/entireio/cli/cmd/entire/cli/strategy"
 
@@ -22,11 +23,28 @@ const stalenessThreshold = 1 * time.Hour
 
 func newDoctorCmd() *cobra.Command {
 	var forceFlag bool
+	var fixFlag bool
+	var jsonFlag bool
 
 	cmd := &cobra.Command{
 		Use:   "doctor",
-		Short: "Fix stuck sessions",
-		Long: `Scan for stuck or problematic sessions and offer to fix them.
+		Short: "Diagnose and repair Entire's setup and sessions",
+		Long: `Check Entire's setup in this repository and fix stuck sessions.
+
+Doctor checks:
+  settings          settings load, and Entire is enabled
+  git-hooks         Entire's git hooks are installed where git runs them
+  agent-hooks       at least one agent reports to Entire
+  metadata-branch   entire/checkpoints/v1 exists and is readable
+  session-files     no leftover or orphaned files in .git/entire-sessions/
+  shadow-branches   every shadow branch belongs to a session
+  locks             no stale git lock files block Entire's writes
+  stuck-sessions    no sessions are stuck (see below)
+
+With --fix, doctor makes the safe repairs: installing missing git hooks,
+recreating the metadata branch, removing leftover session files, dangling
+shadow branches, and lock files older than 10 minutes. --json prints the
+report for scripts. Exits with a non-zero status if a check fails.
 
 A session is considered stuck if:
   - It is in ACTIVE phase with no interaction for over 1 hour
@@ -39,16 +57,46 @@ For each stuck session, you can choose to:
 
 Use --force to condense all fixable sessions without prompting.  Sessions that can't
 be condensed will be discarded.`,
+		Args: cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, _ []string) error {
-			return runSessionsFix(cmd, forceFlag)
+			ctx := cmd.Context()
+			if _, err := paths.WorktreeRoot(ctx); err != nil {
+				return paths.ErrNotGitRepository
+			}
+			report, err := runDoctorChecks(ctx, cmd.OutOrStdout(), fixFlag, jsonFlag)
+			if err != nil {
+				return err
+			}
+			if !jsonFlag && hasStuckSessions(report) {
+				fmt.Fprintln(cmd.OutOrStdout())
+				if err := runSessionsFix(cmd, forceFlag); err != nil {
+					return err
+				}
+			}
+			if report.Failed > 0 {
+				return NewSilentError(fmt.Errorf("%d doctor check(s) failed", report.Failed))
+			}
+			return nil
 		},
 	}
 
 	cmd.Flags().BoolVarP(&forceFlag, "force", "f", false, "Fix all stuck sessions without prompting (condense if possible, otherwise discard)")
+	cmd.Flags().BoolVar(&fixFlag, "fix", false, "Make safe repairs for the problems found")
+	cmd.Flags().BoolVar(&jsonFlag, "json", false, "Output the report as JSON")
 
 	return cmd
 }
 
+// hasStuckSessions reports whether the stuck-sessions check found any.
+func hasStuckSessions(report *doctorReport) bool {
+	for _, r := range report.Checks {
+		if r.Name == "stuck-sessions" && r.problem() {
+			return true
+		}
+	}
+	return false
+}
+
 // stuckSession holds a session state along with diagnostic info.
 type stuckSession struct {
 	State             *strategy.SessionState
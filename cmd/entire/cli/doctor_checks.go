@@ -0,0 +1,505 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+	"github.com/entireio/cli/cmd/entire/cli/session"
+	"github.com/entireio/cli/cmd/entire/cli/strategy"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+const (
+	// staleLockAge is how old a git lock file must be before doctor treats it
+	// as left behind by a crashed process rather than held by a running one.
+	staleLockAge = 10 * time.Minute
+
+	// leftoverTempAge is how old a session state temp file must be before
+	// doctor treats it as the remains of an interrupted write.
+	leftoverTempAge = time.Minute
+)
+
+// doctorStatus is the outcome of one doctor check.
+type doctorStatus string
+
+const (
+	doctorStatusOK      doctorStatus = "ok"
+	doctorStatusWarn    doctorStatus = "warn"
+	doctorStatusFail    doctorStatus = "fail"
+	doctorStatusSkipped doctorStatus = "skipped"
+)
+
+// doctorCheckResult is the result of one doctor check, as printed and in the
+// --json report.
+type doctorCheckResult struct {
+	Name    string       `json:"name"`
+	Status  doctorStatus `json:"status"`
+	Message string       `json:"message"`
+	Details []string     `json:"details,omitempty"`
+
+	// Fixable is true when --fix can repair the problem
+	Fixable  bool   `json:"fixable,omitempty"`
+	Fixed    bool   `json:"fixed,omitempty"`
+	FixError string `json:"fix_error,omitempty"`
+
+	fix func(context.Context) error
+}
+
+// problem reports whether the check found something that is still wrong.
+func (r doctorCheckResult) problem() bool {
+	return (r.Status == doctorStatusWarn || r.Status == doctorStatusFail) && !r.Fixed
+}
+
+// doctorReport is the --json output of entire doctor.
+type doctorReport struct {
+	Checks []doctorCheckResult `json:"checks"`
+
+	// Problems counts checks that warned or failed and weren't fixed
+	Problems int `json:"problems"`
+
+	// Failed counts checks that failed and weren't fixed
+	Failed int `json:"failed"`
+}
+
+// doctorEnv is what the checks share, loaded once per run.
+type doctorEnv struct {
+	settings    *EntireSettings
+	settingsErr error
+	repo        *git.Repository
+	gitDir      string
+	commonDir   string
+	states      []*strategy.SessionState
+	now         time.Time
+}
+
+// enabled reports whether Entire is enabled, so hook checks apply.
+func (e *doctorEnv) enabled() bool {
+	return e.settings != nil && e.settings.Enabled
+}
+
+// doctorChecks run in order. Each inspects one area and may offer a safe repair.
+var doctorChecks = []func(context.Context, *doctorEnv) doctorCheckResult{
+	checkSettings,
+	checkGitHooks,
+	checkAgentHooks,
+	checkMetadataBranch,
+	checkSessionFiles,
+	checkShadowBranches,
+	checkStaleLocks,
+	checkStuckSessions,
+}
+
+func loadDoctorEnv(ctx context.Context) (*doctorEnv, error) {
+	env := &doctorEnv{now: time.Now()}
+	env.settings, env.settingsErr = LoadEntireSettings(ctx)
+
+	repo, err := openRepository(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open repository: %w", err)
+	}
+	env.repo = repo
+	if env.gitDir, err = strategy.GetGitDir(ctx); err != nil {
+		return nil, err //nolint:wrapcheck // already descriptive
+	}
+	if env.commonDir, err = strategy.GetGitCommonDir(ctx); err != nil {
+		return nil, err //nolint:wrapcheck // already descriptive
+	}
+	if env.states, err = strategy.ListSessionStates(ctx); err != nil {
+		return nil, fmt.Errorf("failed to list session states: %w", err)
+	}
+	return env, nil
+}
+
+// runDoctorChecks runs every check, applies the available repairs when fix
+// is set, and writes the report to w as text or JSON.
+func runDoctorChecks(ctx context.Context, w io.Writer, fix, asJSON bool) (*doctorReport, error) {
+	env, err := loadDoctorEnv(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &doctorReport{}
+	for _, check := range doctorChecks {
+		result := check(ctx, env)
+		result.Fixable = result.fix != nil
+		if fix && result.fix != nil && result.problem() {
+			if err := result.fix(ctx); err != nil {
+				result.FixError = err.Error()
+			} else {
+				result.Fixed = true
+			}
+		}
+		if result.problem() {
+			report.Problems++
+			if result.Status == doctorStatusFail {
+				report.Failed++
+			}
+		}
+		report.Checks = append(report.Checks, result)
+	}
+
+	if asJSON {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal doctor report: %w", err)
+		}
+		fmt.Fprintln(w, string(data))
+		return report, nil
+	}
+	writeDoctorReport(w, report)
+	return report, nil
+}
+
+func writeDoctorReport(w io.Writer, report *doctorReport) {
+	fixable := 0
+	for _, r := range report.Checks {
+		mark := "✓"
+		switch r.Status {
+		case doctorStatusWarn:
+			mark = "!"
+		case doctorStatusFail:
+			mark = "✗"
+		case doctorStatusSkipped:
+			mark = "-"
+		case doctorStatusOK:
+		}
+		fmt.Fprintf(w, "%s %-17s %s\n", mark, r.Name, r.Message)
+		for _, d := range r.Details {
+			fmt.Fprintf(w, "    %s\n", d)
+		}
+		switch {
+		case r.Fixed:
+			fmt.Fprintln(w, "    -> fixed")
+		case r.FixError != "":
+			fmt.Fprintf(w, "    -> fix failed: %s\n", r.FixError)
+		case r.Fixable && r.problem():
+			fixable++
+		}
+	}
+	if fixable > 0 {
+		fmt.Fprintf(w, "\nRun 'entire doctor --fix' to repair %d problem(s).\n", fixable)
+	}
+}
+
+// checkSettings checks that the settings load and configure a strategy this
+// CLI supports.
+func checkSettings(_ context.Context, env *doctorEnv) doctorCheckResult {
+	result := doctorCheckResult{Name: "settings"}
+	switch s := env.settings; {
+	case env.settingsErr != nil:
+		result.Status = doctorStatusFail
+		result.Message = "failed to load settings: " + env.settingsErr.Error()
+	case !s.Enabled:
+		result.Status = doctorStatusWarn
+		result.Message = "Entire is disabled (run 'entire enable')"
+	case s.Strategy != "" && s.Strategy != strategy.StrategyNameManualCommit:
+		result.Status = doctorStatusWarn
+		result.Message = fmt.Sprintf("the %q strategy setting is no longer used; Entire runs %s", s.Strategy, strategy.StrategyNameManualCommit)
+	default:
+		result.Status = doctorStatusOK
+		result.Message = "enabled, " + strategy.StrategyNameManualCommit
+	}
+	return result
+}
+
+// checkGitHooks checks that Entire's git hooks are installed where git runs
+// them. The repair installs the missing ones, chaining to existing hooks as
+// `entire enable` does.
+func checkGitHooks(ctx context.Context, env *doctorEnv) doctorCheckResult {
+	result := doctorCheckResult{Name: "git-hooks"}
+	if !env.enabled() {
+		result.Status = doctorStatusSkipped
+		result.Message = "Entire is disabled"
+		return result
+	}
+	source, err := strategy.DescribeHooksDir(ctx)
+	if err != nil {
+		result.Status = doctorStatusFail
+		result.Message = "failed to resolve hooks directory: " + err.Error()
+		return result
+	}
+
+	var missing []string
+	for _, status := range strategy.GitHookStatuses(source.Dir) {
+		switch {
+		case status.Installed:
+		case status.Replaced:
+			missing = append(missing, status.Name+" (another hook is in place)")
+		default:
+			missing = append(missing, status.Name)
+		}
+	}
+	if len(missing) == 0 {
+		result.Status = doctorStatusOK
+		result.Message = "installed in " + source.Dir
+		return result
+	}
+	result.Status = doctorStatusFail
+	result.Message = fmt.Sprintf("%d hook(s) not installed in %s", len(missing), source.Dir)
+	result.Details = missing
+	localDev := env.settings.LocalDev
+	result.fix = func(ctx context.Context) error {
+		_, err := strategy.InstallGitHook(ctx, true, localDev)
+		return err //nolint:wrapcheck // already descriptive
+	}
+	return result
+}
+
+// checkAgentHooks checks that at least one agent reports to Entire.
+func checkAgentHooks(ctx context.Context, env *doctorEnv) doctorCheckResult {
+	result := doctorCheckResult{Name: "agent-hooks"}
+	if !env.enabled() {
+		result.Status = doctorStatusSkipped
+		result.Message = "Entire is disabled"
+		return result
+	}
+	installed := GetAgentsWithHooksInstalled(ctx)
+	if len(installed) == 0 {
+		result.Status = doctorStatusWarn
+		result.Message = "no agent has Entire's hooks installed (run 'entire enable')"
+		return result
+	}
+	result.Status = doctorStatusOK
+	result.Message = "installed for " + JoinAgentNames(installed)
+	return result
+}
+
+// checkMetadataBranch checks that the metadata branch exists and its tip is
+// readable. A missing branch is recreated, from the remote when it has one.
+func checkMetadataBranch(ctx context.Context, env *doctorEnv) doctorCheckResult {
+	result := doctorCheckResult{Name: "metadata-branch"}
+	ref, err := env.repo.Reference(plumbing.NewBranchReferenceName(paths.MetadataBranchName), true)
+	if errors.Is(err, plumbing.ErrReferenceNotFound) {
+		remote := strategy.MetadataRemote(ctx)
+		result.Status = doctorStatusWarn
+		result.Message = paths.MetadataBranchName + " is missing"
+		if _, remoteErr := env.repo.Reference(plumbing.NewRemoteReferenceName(remote, paths.MetadataBranchName), true); remoteErr == nil {
+			result.Message += "; " + remote + " has it"
+		}
+		repo := env.repo
+		result.fix = func(context.Context) error {
+			return strategy.EnsureMetadataBranchFrom(repo, remote) //nolint:wrapcheck // already descriptive
+		}
+		return result
+	}
+	if err != nil {
+		result.Status = doctorStatusFail
+		result.Message = "failed to read " + paths.MetadataBranchName + ": " + err.Error()
+		return result
+	}
+
+	commit, err := env.repo.CommitObject(ref.Hash())
+	if err == nil {
+		_, err = commit.Tree()
+	}
+	if err != nil {
+		result.Status = doctorStatusFail
+		result.Message = fmt.Sprintf("tip %s of %s is unreadable: %v", ref.Hash().String()[:7], paths.MetadataBranchName, err)
+		return result
+	}
+	result.Status = doctorStatusOK
+	result.Message = fmt.Sprintf("%s at %s (run 'entire verify' to check every checkpoint)", paths.MetadataBranchName, ref.Hash().String()[:7])
+	return result
+}
+
+// checkSessionFiles looks for files in the session state directory that
+// nothing will use again: temp files from interrupted writes, state files
+// that don't parse, and states with neither checkpoints nor a shadow branch
+// (as `entire clean` finds them). The repair removes them.
+func checkSessionFiles(ctx context.Context, env *doctorEnv) doctorCheckResult {
+	result := doctorCheckResult{Name: "session-files"}
+	dir := filepath.Join(env.commonDir, session.SessionStateDirName)
+	entries, err := os.ReadDir(dir)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		result.Status = doctorStatusFail
+		result.Message = "failed to read " + dir + ": " + err.Error()
+		return result
+	}
+
+	var leftovers []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() {
+			continue
+		}
+		path := filepath.Join(dir, name)
+		switch {
+		case strings.HasSuffix(name, ".tmp"):
+			if info, err := entry.Info(); err == nil && env.now.Sub(info.ModTime()) > leftoverTempAge {
+				leftovers = append(leftovers, path)
+				result.Details = append(result.Details, name+" (interrupted write)")
+			}
+		case strings.HasSuffix(name, ".json"):
+			data, err := os.ReadFile(path) //nolint:gosec // path is in the session state directory
+			var state session.State
+			if err == nil {
+				err = json.Unmarshal(data, &state)
+			}
+			if err != nil {
+				leftovers = append(leftovers, path)
+				result.Details = append(result.Details, name+" (unreadable)")
+			}
+		}
+	}
+
+	orphaned, err := strategy.ListOrphanedSessionStates(ctx)
+	if err != nil {
+		result.Status = doctorStatusFail
+		result.Message = "failed to find orphaned session states: " + err.Error()
+		return result
+	}
+	orphanedIDs := make([]string, 0, len(orphaned))
+	for _, item := range orphaned {
+		orphanedIDs = append(orphanedIDs, item.ID)
+		result.Details = append(result.Details, item.ID+".json ("+item.Reason+")")
+	}
+
+	if len(result.Details) == 0 {
+		result.Status = doctorStatusOK
+		result.Message = fmt.Sprintf("%d session state file(s)", len(env.states))
+		return result
+	}
+	result.Status = doctorStatusWarn
+	result.Message = fmt.Sprintf("%d orphaned file(s) in %s", len(result.Details), dir)
+	result.fix = func(ctx context.Context) error {
+		for _, path := range leftovers {
+			if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return fmt.Errorf("failed to remove %s: %w", path, err)
+			}
+		}
+		_, failed, err := strategy.DeleteOrphanedSessionStates(ctx, orphanedIDs)
+		if err != nil {
+			return err //nolint:wrapcheck // already descriptive
+		}
+		if len(failed) > 0 {
+			return fmt.Errorf("failed to remove session state for %s", strings.Join(failed, ", "))
+		}
+		return nil
+	}
+	return result
+}
+
+// checkShadowBranches looks for shadow branches no session state refers to.
+// Their sessions are gone, so nothing will condense them; the repair deletes
+// them.
+func checkShadowBranches(ctx context.Context, env *doctorEnv) doctorCheckResult {
+	result := doctorCheckResult{Name: "shadow-branches"}
+	branches, err := strategy.ListShadowBranches(ctx)
+	if err != nil {
+		result.Status = doctorStatusFail
+		result.Message = "failed to list shadow branches: " + err.Error()
+		return result
+	}
+
+	used := make(map[string]bool, len(env.states))
+	for _, state := range env.states {
+		used[checkpoint.ShadowBranchNameForCommit(state.BaseCommit, state.WorktreeID)] = true
+	}
+	var dangling []string
+	for _, branch := range branches {
+		if !used[branch] {
+			dangling = append(dangling, branch)
+		}
+	}
+
+	if len(dangling) == 0 {
+		result.Status = doctorStatusOK
+		result.Message = fmt.Sprintf("%d shadow branch(es), all in use", len(branches))
+		return result
+	}
+	result.Status = doctorStatusWarn
+	result.Message = fmt.Sprintf("%d dangling shadow branch(es) with no session", len(dangling))
+	result.Details = dangling
+	result.fix = func(ctx context.Context) error {
+		_, failed, err := strategy.DeleteShadowBranches(ctx, dangling)
+		if err != nil {
+			return err //nolint:wrapcheck // already descriptive
+		}
+		if len(failed) > 0 {
+			return fmt.Errorf("failed to delete %s", strings.Join(failed, ", "))
+		}
+		return nil
+	}
+	return result
+}
+
+// checkStaleLocks looks for git lock files that block Entire's writes: the
+// index, packed refs, and Entire's own branches. Locks younger than
+// staleLockAge may belong to a running git, so only older ones are removed.
+func checkStaleLocks(_ context.Context, env *doctorEnv) doctorCheckResult {
+	result := doctorCheckResult{Name: "locks"}
+	candidates := []string{
+		filepath.Join(env.gitDir, "index.lock"),
+		filepath.Join(env.commonDir, "packed-refs.lock"),
+	}
+	entireRefs := filepath.Join(env.commonDir, "refs", "heads", "entire")
+	_ = filepath.WalkDir(entireRefs, func(path string, d fs.DirEntry, err error) error { //nolint:errcheck // a missing directory has no locks
+		if err == nil && !d.IsDir() && strings.HasSuffix(path, ".lock") {
+			candidates = append(candidates, path)
+		}
+		return nil
+	})
+
+	var stale []string
+	for _, path := range candidates {
+		info, err := os.Stat(path)
+		if err != nil || env.now.Sub(info.ModTime()) < staleLockAge {
+			continue
+		}
+		stale = append(stale, path)
+		age := env.now.Sub(info.ModTime()).Truncate(time.Minute)
+		if rel, err := filepath.Rel(env.commonDir, path); err == nil && !strings.HasPrefix(rel, "..") {
+			path = rel
+		}
+		result.Details = append(result.Details, fmt.Sprintf("%s (%s old)", path, age))
+	}
+
+	if len(stale) == 0 {
+		result.Status = doctorStatusOK
+		result.Message = "no stale locks"
+		return result
+	}
+	result.Status = doctorStatusFail
+	result.Message = fmt.Sprintf("%d stale lock file(s) left by a crashed git process", len(stale))
+	result.fix = func(context.Context) error {
+		for _, path := range stale {
+			if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return fmt.Errorf("failed to remove %s: %w", path, err)
+			}
+		}
+		return nil
+	}
+	return result
+}
+
+// checkStuckSessions reports sessions classifySession considers stuck. They
+// are repaired interactively (or with --force), not by --fix, since that
+// means choosing between condensing and discarding their work.
+func checkStuckSessions(_ context.Context, env *doctorEnv) doctorCheckResult {
+	result := doctorCheckResult{Name: "stuck-sessions"}
+	for _, state := range env.states {
+		if ss := classifySession(state, env.repo, env.now); ss != nil {
+			result.Details = append(result.Details, ss.State.SessionID+" ("+ss.Reason+")")
+		}
+	}
+	if len(result.Details) == 0 {
+		result.Status = doctorStatusOK
+		result.Message = "no stuck sessions"
+		return result
+	}
+	result.Status = doctorStatusWarn
+	result.Message = fmt.Sprintf("%d stuck session(s)", len(result.Details))
+	return result
+}
@@ -0,0 +1,162 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/entireio/cli/cmd/entire/cli/session"
+	"github.com/entireio/cli/cmd/entire/cli/settings"
+	"github.com/entireio/cli/cmd/entire/cli/strategy"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckSettings(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		env    doctorEnv
+		status doctorStatus
+	}{
+		{"enabled", doctorEnv{settings: &settings.EntireSettings{Enabled: true}}, doctorStatusOK},
+		{"disabled", doctorEnv{settings: &settings.EntireSettings{}}, doctorStatusWarn},
+		{"old strategy", doctorEnv{settings: &settings.EntireSettings{Enabled: true, Strategy: "auto-commit"}}, doctorStatusWarn},
+		{"unreadable", doctorEnv{settingsErr: errors.New("bad json")}, doctorStatusFail},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			result := checkSettings(context.Background(), &tt.env)
+			assert.Equal(t, tt.status, result.Status, result.Message)
+		})
+	}
+}
+
+func TestCheckStaleLocks(t *testing.T) {
+	t.Parallel()
+	commonDir := t.TempDir()
+	now := time.Now()
+	old := now.Add(-time.Hour)
+
+	refLock := filepath.Join(commonDir, "refs", "heads", "entire", "checkpoints", "v1.lock")
+	require.NoError(t, os.MkdirAll(filepath.Dir(refLock), 0o755))
+	indexLock := filepath.Join(commonDir, "index.lock")
+	packedLock := filepath.Join(commonDir, "packed-refs.lock")
+	for _, path := range []string{refLock, indexLock, packedLock} {
+		require.NoError(t, os.WriteFile(path, nil, 0o600))
+	}
+	require.NoError(t, os.Chtimes(refLock, old, old))
+	require.NoError(t, os.Chtimes(indexLock, old, old))
+
+	env := &doctorEnv{gitDir: commonDir, commonDir: commonDir, now: now}
+	result := checkStaleLocks(context.Background(), env)
+	require.Equal(t, doctorStatusFail, result.Status)
+	assert.Len(t, result.Details, 2, "the fresh packed-refs.lock may be held by a running git")
+	require.NotNil(t, result.fix)
+
+	require.NoError(t, result.fix(context.Background()))
+	assert.NoFileExists(t, refLock)
+	assert.NoFileExists(t, indexLock)
+	assert.FileExists(t, packedLock)
+	assert.Equal(t, doctorStatusOK, checkStaleLocks(context.Background(), env).Status)
+}
+
+func TestCheckShadowBranches(t *testing.T) {
+	repo, _ := setupCleanTestRepo(t)
+	createShadowBranchRef(t, repo, testBaseCommit, "")
+	inUse := "1234567890abcdef1234567890abcdef12345678"
+	createShadowBranchRef(t, repo, inUse, "")
+
+	env := &doctorEnv{
+		repo:   repo,
+		states: []*strategy.SessionState{{SessionID: "live", BaseCommit: inUse}},
+		now:    time.Now(),
+	}
+	result := checkShadowBranches(context.Background(), env)
+	require.Equal(t, doctorStatusWarn, result.Status, result.Message)
+	require.Len(t, result.Details, 1)
+	require.NotNil(t, result.fix)
+
+	require.NoError(t, result.fix(context.Background()))
+	branches, err := strategy.ListShadowBranches(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, branches, 1, "the branch of the live session is kept")
+}
+
+func TestCheckSessionFiles(t *testing.T) {
+	repo, _ := setupCleanTestRepo(t)
+	commonDir, err := strategy.GetGitCommonDir(context.Background())
+	require.NoError(t, err)
+	dir := filepath.Join(commonDir, session.SessionStateDirName)
+	require.NoError(t, os.MkdirAll(dir, 0o755))
+
+	now := time.Now()
+	leftover := filepath.Join(dir, "s1.json.tmp")
+	inFlight := filepath.Join(dir, "s2.json.tmp")
+	corrupt := filepath.Join(dir, "s3.json")
+	require.NoError(t, os.WriteFile(leftover, []byte("{"), 0o600))
+	require.NoError(t, os.WriteFile(inFlight, []byte("{"), 0o600))
+	require.NoError(t, os.WriteFile(corrupt, []byte("not json"), 0o600))
+	require.NoError(t, os.Chtimes(leftover, now.Add(-time.Hour), now.Add(-time.Hour)))
+
+	env := &doctorEnv{repo: repo, commonDir: commonDir, now: now}
+	result := checkSessionFiles(context.Background(), env)
+	require.Equal(t, doctorStatusWarn, result.Status, result.Message)
+	assert.Len(t, result.Details, 2)
+	require.NotNil(t, result.fix)
+
+	require.NoError(t, result.fix(context.Background()))
+	assert.NoFileExists(t, leftover)
+	assert.NoFileExists(t, corrupt)
+	assert.FileExists(t, inFlight, "a temp file being written is kept")
+}
+
+func TestRunDoctorChecks_JSON(t *testing.T) {
+	setupCleanTestRepo(t)
+
+	var out bytes.Buffer
+	report, err := runDoctorChecks(context.Background(), &out, false, true)
+	require.NoError(t, err)
+
+	var parsed doctorReport
+	require.NoError(t, json.Unmarshal(out.Bytes(), &parsed))
+	names := make([]string, 0, len(parsed.Checks))
+	for _, c := range parsed.Checks {
+		names = append(names, c.Name)
+	}
+	assert.Equal(t, []string{
+		"settings", "git-hooks", "agent-hooks", "metadata-branch",
+		"session-files", "shadow-branches", "locks", "stuck-sessions",
+	}, names)
+
+	// A fresh repository has none of Entire's git hooks or agent hooks, nor
+	// the metadata branch; --fix installs the hooks and creates the branch
+	assert.Equal(t, doctorStatusFail, parsed.Checks[1].Status)
+	assert.True(t, parsed.Checks[1].Fixable)
+	assert.Equal(t, doctorStatusWarn, parsed.Checks[2].Status)
+	assert.False(t, parsed.Checks[2].Fixable)
+	assert.Equal(t, doctorStatusWarn, parsed.Checks[3].Status)
+	assert.True(t, parsed.Checks[3].Fixable)
+	assert.Equal(t, 3, report.Problems)
+	assert.Equal(t, 1, report.Failed)
+
+	out.Reset()
+	report, err = runDoctorChecks(context.Background(), &out, true, false)
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Problems, "only the agent hooks are left: %s", out.String())
+	assert.Zero(t, report.Failed)
+	assert.Contains(t, out.String(), "-> fixed")
+
+	out.Reset()
+	report, err = runDoctorChecks(context.Background(), &out, false, false)
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Problems, "repairs hold on the next run: %s", out.String())
+}
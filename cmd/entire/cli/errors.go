@@ -1,5 +1,7 @@
 package cli
 
+import "fmt"
+
 // SilentError wraps an error to signal that the error message has already been
 // printed to the user. main.go checks for this type to avoid duplicate output.
 type SilentError struct {
@@ -20,3 +22,14 @@ func (e *SilentError) Unwrap() error {
 func NewSilentError(err error) *SilentError {
 	return &SilentError{Err: err}
 }
+
+// ExitCodeError reports that a command run by entire exited with a failure.
+// The command has already explained itself, so main.go exits with Code
+// without printing anything.
+type ExitCodeError struct {
+	Code int
+}
+
+func (e *ExitCodeError) Error() string {
+	return fmt.Sprintf("exit status %d", e.Code)
+}
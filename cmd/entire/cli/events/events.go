@@ -0,0 +1,184 @@
+// Package events keeps a local, append-only log of what Entire did in a
+// repository (checkpoints written, sessions started and ended, rewinds), one
+// JSON object per line, so external tools can follow it instead of polling
+// git. The log is a convenience: writers ignore failures, and it rotates once
+// it grows past MaxLogSize, keeping the previous file as <name>.1.
+package events
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// FileName is the event log's name in the git common dir.
+const FileName = "entire-events.jsonl"
+
+// MaxLogSize is the size past which Append rotates the log.
+const MaxLogSize = 1 << 20
+
+// Event types.
+const (
+	TypeCheckpointCreated = "checkpoint.created"
+	TypeCheckpointUpdated = "checkpoint.updated"
+	TypeSessionStarted    = "session.started"
+	TypeSessionEnded      = "session.ended"
+	TypeRewind            = "rewind"
+)
+
+// Event is one line of the event log.
+type Event struct {
+	Time         time.Time `json:"time"`
+	Type         string    `json:"type"`
+	SessionID    string    `json:"session_id,omitempty"`
+	CheckpointID string    `json:"checkpoint_id,omitempty"`
+	Agent        string    `json:"agent,omitempty"`
+	// Ref is the commit a rewind restored
+	Ref string `json:"ref,omitempty"`
+}
+
+// Append adds e to the log at path, filling in a missing time. Each event is
+// written with a single append, so concurrent hooks don't interleave lines.
+func Append(path string, e Event) error {
+	if e.Type == "" {
+		return errors.New("event type is required")
+	}
+	if e.Time.IsZero() {
+		e.Time = time.Now().UTC()
+	}
+	line, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+	line = append(line, '\n')
+
+	if info, err := os.Stat(path); err == nil && info.Size()+int64(len(line)) > MaxLogSize {
+		if err := os.Rename(path, path+".1"); err != nil {
+			return fmt.Errorf("failed to rotate event log: %w", err)
+		}
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600) //nolint:gosec // path is in the git directory
+	if err != nil {
+		return fmt.Errorf("failed to open event log: %w", err)
+	}
+	if _, err := f.Write(line); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to write event: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write event: %w", err)
+	}
+	return nil
+}
+
+// Read returns the events in the log at path, oldest first, and the offset
+// just past the last complete line, to pass to Follow. A missing log has no
+// events. Lines that don't parse are skipped.
+func Read(path string) ([]Event, int64, error) {
+	f, err := os.Open(path) //nolint:gosec // path is in the git directory
+	if errors.Is(err, os.ErrNotExist) {
+		return []Event{}, 0, nil
+	}
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to open event log: %w", err)
+	}
+	defer f.Close()
+
+	all := []Event{}
+	offset, err := readLines(f, func(e Event) error {
+		all = append(all, e)
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return all, offset, nil
+}
+
+// Follow calls fn for each event appended to the log at path after offset,
+// checking for new lines every interval until ctx is done or fn fails.
+// When the log is rotated, Follow continues at the start of the new file.
+// It returns nil when ctx is canceled.
+func Follow(ctx context.Context, path string, offset int64, interval time.Duration, fn func(Event) error) error {
+	var current os.FileInfo
+	if info, err := os.Stat(path); err == nil {
+		current = info
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		info, err := os.Stat(path)
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+			// Not written yet, or between a rotation's rename and the next append
+		case err != nil:
+			return fmt.Errorf("failed to read event log: %w", err)
+		default:
+			if current == nil || !os.SameFile(current, info) || info.Size() < offset {
+				offset = 0
+			}
+			current = info
+			if info.Size() > offset {
+				n, err := readFrom(path, offset, fn)
+				offset += n
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// readFrom calls fn for the complete lines of the file at path after offset,
+// and returns the number of bytes they took.
+func readFrom(path string, offset int64, fn func(Event) error) (int64, error) {
+	f, err := os.Open(path) //nolint:gosec // path is in the git directory
+	if err != nil {
+		return 0, fmt.Errorf("failed to open event log: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return 0, fmt.Errorf("failed to read event log: %w", err)
+	}
+	return readLines(f, fn)
+}
+
+// readLines calls fn for each complete line of r that parses as an event,
+// and returns the number of bytes up to the end of the last complete line.
+// A trailing partial line is left for the next read.
+func readLines(r io.Reader, fn func(Event) error) (int64, error) {
+	reader := bufio.NewReader(r)
+	var consumed int64
+	for {
+		line, err := reader.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			return consumed, nil
+		}
+		if err != nil {
+			return consumed, fmt.Errorf("failed to read event log: %w", err)
+		}
+		consumed += int64(len(line))
+
+		var e Event
+		if json.Unmarshal(bytes.TrimSpace(line), &e) != nil || e.Type == "" {
+			continue
+		}
+		if err := fn(e); err != nil {
+			return consumed, err
+		}
+	}
+}
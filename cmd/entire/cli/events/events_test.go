@@ -0,0 +1,133 @@
+package events
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAppendRead(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), FileName)
+
+	events, offset, err := Read(path)
+	if err != nil || len(events) != 0 || offset != 0 {
+		t.Fatalf("Read() of a missing log = %v, %d, %v", events, offset, err)
+	}
+
+	if err := Append(path, Event{Type: TypeSessionStarted, SessionID: "s1"}); err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+	if err := Append(path, Event{Type: TypeCheckpointCreated, SessionID: "s1", CheckpointID: "a1b2c3d4e5f6"}); err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+	if err := Append(path, Event{}); err == nil {
+		t.Error("Append() without a type succeeded")
+	}
+
+	// A line being written, and a line that isn't an event, are skipped
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		t.Fatalf("failed to open log: %v", err)
+	}
+	if _, err := f.WriteString("not json\n{\"type\":\"rew"); err != nil {
+		t.Fatalf("failed to write log: %v", err)
+	}
+	f.Close()
+
+	events, offset, err = Read(path)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if len(events) != 2 || events[0].Type != TypeSessionStarted || events[1].CheckpointID != "a1b2c3d4e5f6" {
+		t.Errorf("Read() = %+v", events)
+	}
+	if events[0].Time.IsZero() {
+		t.Error("Append() didn't set the time")
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Stat() error = %v", err)
+	}
+	if want := info.Size() - int64(len(`{"type":"rew`)); offset != want {
+		t.Errorf("offset = %d, want %d (before the partial line)", offset, want)
+	}
+}
+
+func TestAppend_Rotates(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), FileName)
+
+	if err := os.WriteFile(path, []byte(strings.Repeat("x", MaxLogSize-10)+"\n"), 0o600); err != nil {
+		t.Fatalf("failed to write log: %v", err)
+	}
+	if err := Append(path, Event{Type: TypeRewind}); err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+	if _, err := os.Stat(path + ".1"); err != nil {
+		t.Errorf("rotated log missing: %v", err)
+	}
+	events, _, err := Read(path)
+	if err != nil || len(events) != 1 || events[0].Type != TypeRewind {
+		t.Errorf("Read() after rotation = %+v, %v", events, err)
+	}
+}
+
+func TestFollow(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), FileName)
+	if err := Append(path, Event{Type: TypeSessionStarted, SessionID: "old"}); err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+	_, offset, err := Read(path)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	got := make(chan Event, 4)
+	done := make(chan error, 1)
+	go func() {
+		done <- Follow(ctx, path, offset, 10*time.Millisecond, func(e Event) error {
+			got <- e
+			return nil
+		})
+	}()
+
+	if err := Append(path, Event{Type: TypeSessionEnded, SessionID: "new"}); err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+	select {
+	case e := <-got:
+		if e.SessionID != "new" {
+			t.Errorf("Follow() delivered %+v, want only the new event", e)
+		}
+	case <-ctx.Done():
+		t.Fatal("Follow() didn't deliver the appended event")
+	}
+
+	// After a rotation, Follow reads the new file from the start
+	if err := os.Rename(path, path+".1"); err != nil {
+		t.Fatalf("failed to rotate: %v", err)
+	}
+	if err := Append(path, Event{Type: TypeRewind}); err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+	select {
+	case e := <-got:
+		if e.Type != TypeRewind {
+			t.Errorf("Follow() after rotation delivered %+v", e)
+		}
+	case <-ctx.Done():
+		t.Fatal("Follow() didn't deliver the event after rotation")
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("Follow() error = %v", err)
+	}
+}
@@ -0,0 +1,86 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/entireio/cli/cmd/entire/cli/events"
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+	"github.com/entireio/cli/cmd/entire/cli/strategy"
+	"github.com/spf13/cobra"
+)
+
+// eventsPollInterval is how often `entire events --follow` checks for new events.
+const eventsPollInterval = 250 * time.Millisecond
+
+func newEventsCmd() *cobra.Command {
+	var followFlag bool
+	var tailFlag int
+
+	cmd := &cobra.Command{
+		Use:   "events",
+		Short: "Print Entire events as JSON lines, optionally as they happen",
+		Long: `Events prints what Entire recorded in this repository, one JSON object per
+line, oldest first:
+
+  checkpoint.created   a commit's session was condensed into a checkpoint
+  checkpoint.updated   a checkpoint got the full transcript at turn end
+  session.started      an agent session started
+  session.ended        an agent session ended
+  rewind               the working tree was rewound (or a rewind undone)
+
+With --follow, events keep streaming as they happen, so status bars and
+dashboards can react without polling git. Use -n 0 --follow to see only new
+events.
+
+The log is kept in the git directory and rotates at 1 MiB, keeping one
+previous file.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := cmd.Context()
+			if _, err := paths.WorktreeRoot(ctx); err != nil {
+				return paths.ErrNotGitRepository
+			}
+			path, err := strategy.EventLogPath(ctx)
+			if err != nil {
+				return err //nolint:wrapcheck // already descriptive
+			}
+			return runEvents(ctx, cmd.OutOrStdout(), path, tailFlag, followFlag)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&followFlag, "follow", "f", false, "Keep printing events as they are recorded")
+	cmd.Flags().IntVarP(&tailFlag, "tail", "n", -1, "Print only the last N recorded events (-1 for all)")
+
+	return cmd
+}
+
+func runEvents(ctx context.Context, w io.Writer, path string, tail int, follow bool) error {
+	recorded, offset, err := events.Read(path)
+	if err != nil {
+		return err //nolint:wrapcheck // already descriptive
+	}
+	if tail >= 0 && len(recorded) > tail {
+		recorded = recorded[len(recorded)-tail:]
+	}
+
+	enc := json.NewEncoder(w)
+	write := func(e events.Event) error {
+		if err := enc.Encode(e); err != nil {
+			return fmt.Errorf("failed to write event: %w", err)
+		}
+		return nil
+	}
+	for _, e := range recorded {
+		if err := write(e); err != nil {
+			return err
+		}
+	}
+	if !follow {
+		return nil
+	}
+	return events.Follow(ctx, path, offset, eventsPollInterval, write) //nolint:wrapcheck // already descriptive
+}
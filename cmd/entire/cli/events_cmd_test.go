@@ -0,0 +1,38 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/entireio/cli/cmd/entire/cli/events"
+)
+
+func TestRunEvents_Tail(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), events.FileName)
+	for _, sessionID := range []string{"s1", "s2", "s3"} {
+		if err := events.Append(path, events.Event{Type: events.TypeSessionStarted, SessionID: sessionID}); err != nil {
+			t.Fatalf("Append() error = %v", err)
+		}
+	}
+
+	var out bytes.Buffer
+	if err := runEvents(context.Background(), &out, path, -1, false); err != nil {
+		t.Fatalf("runEvents() error = %v", err)
+	}
+	if lines := strings.Split(strings.TrimSpace(out.String()), "\n"); len(lines) != 3 {
+		t.Errorf("runEvents() printed %d lines, want 3:\n%s", len(lines), out.String())
+	}
+
+	out.Reset()
+	if err := runEvents(context.Background(), &out, path, 1, false); err != nil {
+		t.Fatalf("runEvents() error = %v", err)
+	}
+	got := strings.TrimSpace(out.String())
+	if strings.Count(got, "\n") != 0 || !strings.Contains(got, `"session_id":"s3"`) || !strings.Contains(got, `"type":"session.started"`) {
+		t.Errorf("runEvents(tail 1) = %q, want only the last event", got)
+	}
+}
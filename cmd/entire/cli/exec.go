@@ -0,0 +1,261 @@
+package cli
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/entireio/cli/cmd/entire/cli/agent"
+	"github.com/entireio/cli/cmd/entire/cli/agent/wrapped"
+	"github.com/entireio/cli/cmd/entire/cli/logging"
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+	"github.com/entireio/cli/cmd/entire/cli/settings"
+
+	"github.com/spf13/cobra"
+)
+
+// defaultExecSnapshotInterval is how often `entire exec` checkpoints the
+// worktree while the wrapped command runs.
+const defaultExecSnapshotInterval = 5 * time.Minute
+
+// execHeartbeatInterval is how often `entire exec` tells other sessions the
+// wrapped command is still running. Well below session.HeartbeatTimeout.
+const execHeartbeatInterval = time.Minute
+
+func newExecCmd() *cobra.Command {
+	var intervalFlag time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "exec [flags] -- <command> [args...]",
+		Short: "Run an agent that has no hooks and record its session",
+		Long: `Exec runs an agent that has no hook system and manages its session itself.
+Entire snapshots the worktree when the command starts, every --interval while
+it runs, and when it exits. The changes become a session linked to your next
+commit, like sessions of agents with hooks.
+
+The command's terminal input and output pass through untouched. If it exits
+with an error or is killed by a signal, the exit is recorded in the session
+transcript and the work so far is still checkpointed.
+
+Example:
+  entire exec -- aider --model sonnet`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			if _, err := paths.WorktreeRoot(ctx); err != nil {
+				return paths.ErrNotGitRepository
+			}
+			if !settings.IsSetUpAndEnabled(ctx) {
+				return errors.New("entire is not enabled in this repository; run 'entire enable' first")
+			}
+			return runExec(ctx, cmd.ErrOrStderr(), args, intervalFlag)
+		},
+	}
+
+	cmd.Flags().DurationVar(&intervalFlag, "interval", defaultExecSnapshotInterval, "How often to checkpoint while the command runs (0 disables)")
+	cmd.Flags().SetInterspersed(false)
+
+	return cmd
+}
+
+// execSession drives the lifecycle of one wrapped command.
+type execSession struct {
+	ctx        context.Context
+	agent      *wrapped.WrappedAgent
+	id         string
+	transcript string
+	prompt     string
+}
+
+func runExec(ctx context.Context, w io.Writer, args []string, interval time.Duration) error {
+	// Ctrl-C cancels ctx, but it's meant for the agent; the session must still be finished
+	ctx = context.WithoutCancel(ctx)
+
+	entireSettings, err := LoadEntireSettings(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load settings: %w", err)
+	}
+	if blocked := checkExecPromptGuard(ctx, w, entireSettings.PromptGuard); blocked {
+		return NewSilentError(errors.New("blocked by prompt guard"))
+	}
+
+	s, err := newExecSession(ctx, args)
+	if err != nil {
+		return err
+	}
+
+	logging.SetLogLevelGetter(GetLogLevel)
+	if err := logging.Init(ctx, s.id); err == nil {
+		defer logging.Close()
+	}
+
+	if err := s.record(wrapped.Record{Type: wrapped.RecordStart, Command: args}); err != nil {
+		return err
+	}
+	s.dispatch(agent.TurnStart)
+	fmt.Fprintf(w, "[entire] Recording %s as session %s\n", s.agent.Type(), s.id)
+
+	cmd := exec.Command(args[0], args[1:]...) //nolint:gosec,noctx // the user's command; it must outlive Ctrl-C on ctx
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Start(); err != nil {
+		s.finish(wrapped.Record{Type: wrapped.RecordExit, ExitCode: -1, Crashed: true})
+		return fmt.Errorf("failed to start %s: %w", args[0], err)
+	}
+
+	state := s.supervise(cmd, interval)
+
+	exitRecord := execExitRecord(state)
+	s.finish(exitRecord)
+	if !exitRecord.Crashed {
+		return nil
+	}
+
+	if exitRecord.Signal != "" {
+		fmt.Fprintf(w, "[entire] %s was killed (%s); work so far is checkpointed in session %s\n", s.agent.Type(), exitRecord.Signal, s.id)
+		return &ExitCodeError{Code: 1}
+	}
+	fmt.Fprintf(w, "[entire] %s exited with status %d; work so far is checkpointed in session %s\n", s.agent.Type(), exitRecord.ExitCode, s.id)
+	return &ExitCodeError{Code: exitRecord.ExitCode}
+}
+
+func newExecSession(ctx context.Context, args []string) (*execSession, error) {
+	repoRoot, err := paths.WorktreeRoot(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get worktree root: %w", err)
+	}
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return nil, fmt.Errorf("failed to generate session ID: %w", err)
+	}
+	id := "exec-" + time.Now().UTC().Format("20060102-150405") + "-" + hex.EncodeToString(suffix)
+
+	return &execSession{
+		ctx:        ctx,
+		agent:      wrapped.NewWrappedAgent(args[0]),
+		id:         id,
+		transcript: filepath.Join(repoRoot, paths.EntireTmpDir, id+".jsonl"),
+		prompt:     strings.Join(args, " "),
+	}, nil
+}
+
+// supervise waits for the command to exit, checkpointing every interval,
+// recording heartbeats and passing SIGTERM on to it. Ctrl-C reaches the command directly through the
+// terminal, so it isn't forwarded.
+func (s *execSession) supervise(cmd *exec.Cmd, interval time.Duration) *os.ProcessState {
+	done := make(chan struct{})
+	go func() {
+		_ = cmd.Wait() //nolint:errcheck // the exit is read from cmd.ProcessState
+		close(done)
+	}()
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	var ticks <-chan time.Time
+	if interval > 0 {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		ticks = ticker.C
+	}
+	heartbeat := time.NewTicker(execHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-done:
+			return cmd.ProcessState
+		case sig := <-signals:
+			_ = cmd.Process.Signal(sig) //nolint:errcheck // the process may have just exited
+		case <-ticks:
+			if err := s.record(wrapped.Record{Type: wrapped.RecordSnapshot}); err != nil {
+				logging.Warn(s.ctx, "failed to record snapshot", slog.String("error", err.Error()))
+			}
+			s.dispatch(agent.TurnEnd)
+			s.dispatch(agent.TurnStart)
+		case <-heartbeat.C:
+			if err := recordSessionHeartbeat(s.ctx, s.id); err != nil {
+				logging.Warn(s.ctx, "failed to record heartbeat", slog.String("error", err.Error()))
+			}
+		}
+	}
+}
+
+// finish records how the command exited, checkpoints its last changes and
+// ends the session.
+func (s *execSession) finish(exitRecord wrapped.Record) {
+	if err := s.record(exitRecord); err != nil {
+		logging.Warn(s.ctx, "failed to record exit", slog.String("error", err.Error()))
+	}
+	s.dispatch(agent.TurnEnd)
+	s.dispatch(agent.SessionEnd)
+}
+
+func (s *execSession) record(record wrapped.Record) error {
+	record.Timestamp = time.Now()
+	return wrapped.AppendRecord(s.transcript, record) //nolint:wrapcheck // already descriptive
+}
+
+// dispatch sends a lifecycle event for the session, as an agent hook would.
+func (s *execSession) dispatch(eventType agent.EventType) {
+	event := &agent.Event{
+		Type:       eventType,
+		SessionID:  s.id,
+		SessionRef: s.transcript,
+		Timestamp:  time.Now(),
+	}
+	if eventType == agent.TurnStart {
+		event.Prompt = s.prompt
+	}
+	if err := DispatchLifecycleEvent(s.ctx, s.agent, event); err != nil {
+		logging.Warn(logging.WithComponent(s.ctx, "exec"), "lifecycle event failed",
+			slog.String("event", eventType.String()),
+			slog.String("error", err.Error()))
+	}
+}
+
+// execExitRecord describes how the command exited.
+func execExitRecord(state *os.ProcessState) wrapped.Record {
+	record := wrapped.Record{Type: wrapped.RecordExit, ExitCode: -1, Crashed: true}
+	if state == nil {
+		return record
+	}
+	record.ExitCode = state.ExitCode()
+	record.Crashed = !state.Success()
+	if record.ExitCode == -1 {
+		record.Signal = strings.TrimPrefix(state.String(), "signal: ")
+	}
+	return record
+}
+
+// checkExecPromptGuard runs the prompt guard before the command starts,
+// printing any issues. Wrapped agents have no hook to receive the guard's
+// response, so it is applied once, up front. Returns true if the start is blocked.
+func checkExecPromptGuard(ctx context.Context, w io.Writer, cfg *settings.PromptGuardSettings) bool {
+	if cfg == nil {
+		return false
+	}
+	issues := checkPromptGuard(ctx, cfg)
+	if len(issues) == 0 {
+		return false
+	}
+	fmt.Fprintln(w, "Entire prompt guard:\n  "+strings.Join(issues, "\n  "))
+	if cfg.GetAction() == settings.PromptGuardActionBlock {
+		fmt.Fprintln(w, "Resolve these before starting the agent.")
+		return true
+	}
+	return false
+}
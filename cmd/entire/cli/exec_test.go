@@ -0,0 +1,157 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/entireio/cli/cmd/entire/cli/agent/wrapped"
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+	"github.com/entireio/cli/cmd/entire/cli/session"
+	"github.com/entireio/cli/cmd/entire/cli/strategy"
+	"github.com/entireio/cli/cmd/entire/cli/testutil"
+)
+
+// setupExecTestRepo creates an enabled repository with one commit and changes into it.
+func setupExecTestRepo(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	testutil.InitRepo(t, dir)
+	testutil.WriteFile(t, dir, "README.md", "# Test")
+	testutil.GitAdd(t, dir, "README.md")
+	testutil.GitCommit(t, dir, "Initial commit")
+
+	t.Chdir(dir)
+	paths.ClearWorktreeRootCache()
+	session.ClearGitCommonDirCache()
+	writeSettings(t, testSettingsEnabled)
+	return dir
+}
+
+// execSessionState returns the state of the single session entire exec recorded.
+func execSessionState(t *testing.T) *strategy.SessionState {
+	t.Helper()
+	states, err := strategy.ListSessionStates(context.Background())
+	if err != nil {
+		t.Fatalf("ListSessionStates() error = %v", err)
+	}
+	if len(states) != 1 {
+		t.Fatalf("got %d sessions, want 1", len(states))
+	}
+	return states[0]
+}
+
+// readExecTranscript parses the records of a wrapped agent's transcript.
+func readExecTranscript(t *testing.T, path string) []wrapped.Record {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read transcript: %v", err)
+	}
+	var records []wrapped.Record
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		var record wrapped.Record
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			t.Fatalf("invalid transcript line %q: %v", scanner.Text(), err)
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestRunExec_RecordsSession(t *testing.T) {
+	setupExecTestRepo(t)
+
+	var stderr bytes.Buffer
+	if err := runExec(context.Background(), &stderr, []string{"sh", "-c", "echo hello > new.txt"}, 0); err != nil {
+		t.Fatalf("runExec() error = %v", err)
+	}
+
+	state := execSessionState(t)
+	if !strings.HasPrefix(state.SessionID, "exec-") {
+		t.Errorf("session ID = %q, want an exec- prefix", state.SessionID)
+	}
+	if state.AgentType != "sh" {
+		t.Errorf("agent type = %q, want the command name %q", state.AgentType, "sh")
+	}
+	if state.EndedAt == nil {
+		t.Error("session should be ended after the command exits")
+	}
+	if state.StepCount == 0 {
+		t.Error("the command's changes should be checkpointed")
+	}
+	if !strings.Contains(stderr.String(), state.SessionID) {
+		t.Errorf("output should name the session, got: %s", stderr.String())
+	}
+
+	records := readExecTranscript(t, state.TranscriptPath)
+	if len(records) != 2 || records[0].Type != wrapped.RecordStart || records[1].Type != wrapped.RecordExit {
+		t.Fatalf("transcript records = %+v, want start and exit", records)
+	}
+	if got := strings.Join(records[0].Command, " "); got != "sh -c echo hello > new.txt" {
+		t.Errorf("start record command = %q", got)
+	}
+	if records[1].Crashed || records[1].ExitCode != 0 {
+		t.Errorf("exit record = %+v, want a clean exit", records[1])
+	}
+}
+
+func TestRunExec_FailureIsRecorded(t *testing.T) {
+	setupExecTestRepo(t)
+
+	var stderr bytes.Buffer
+	err := runExec(context.Background(), &stderr, []string{"sh", "-c", "echo partial > work.txt; exit 3"}, 0)
+	var exitErr *ExitCodeError
+	if !errors.As(err, &exitErr) || exitErr.Code != 3 {
+		t.Fatalf("runExec() error = %v, want exit code 3", err)
+	}
+
+	state := execSessionState(t)
+	if state.StepCount == 0 {
+		t.Error("work done before the failure should be checkpointed")
+	}
+	records := readExecTranscript(t, state.TranscriptPath)
+	last := records[len(records)-1]
+	if last.Type != wrapped.RecordExit || !last.Crashed || last.ExitCode != 3 {
+		t.Errorf("exit record = %+v, want a crash with exit code 3", last)
+	}
+	if !strings.Contains(stderr.String(), "exited with status 3") {
+		t.Errorf("output should report the failure, got: %s", stderr.String())
+	}
+}
+
+func TestRunExec_KilledBySignal(t *testing.T) {
+	setupExecTestRepo(t)
+
+	var stderr bytes.Buffer
+	err := runExec(context.Background(), &stderr, []string{"sh", "-c", "kill -9 $$"}, 0)
+	var exitErr *ExitCodeError
+	if !errors.As(err, &exitErr) {
+		t.Fatalf("runExec() error = %v, want an exit code error", err)
+	}
+
+	records := readExecTranscript(t, execSessionState(t).TranscriptPath)
+	last := records[len(records)-1]
+	if !last.Crashed || last.Signal != "killed" {
+		t.Errorf("exit record = %+v, want a crash by signal \"killed\"", last)
+	}
+}
+
+func TestRunExec_NotEnabled(t *testing.T) {
+	setupExecTestRepo(t)
+	writeSettings(t, testSettingsDisabled)
+
+	cmd := newExecCmd()
+	cmd.SetArgs([]string{"--", "true"})
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "entire enable") {
+		t.Errorf("exec error = %v, want a hint to run entire enable", err)
+	}
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/entireio/cli/cmd/entire/cli/agent/types"
 	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
 	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+	"github.com/entireio/cli/cmd/entire/cli/i18n"
 	"github.com/entireio/cli/cmd/entire/cli/logging"
 	"github.com/entireio/cli/cmd/entire/cli/paths"
 	"github.com/entireio/cli/cmd/entire/cli/strategy"
@@ -204,9 +205,13 @@ func runExplainCheckpoint(ctx context.Context, w, errW io.Writer, checkpointIDPr
 	}
 
 	store := checkpoint.NewGitStore(repo)
+	committedStore, err := strategy.CommittedStore(ctx, store)
+	if err != nil {
+		return err //nolint:wrapcheck // already names the setting
+	}
 
 	// First, try to find in committed checkpoints by checkpoint ID prefix
-	committed, err := store.ListCommitted(ctx)
+	committed, err := committedStore.ListCommitted(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to list checkpoints: %w", err)
 	}
@@ -248,7 +253,7 @@ func runExplainCheckpoint(ctx context.Context, w, errW io.Writer, checkpointIDPr
 	}
 
 	// Load checkpoint summary
-	summary, err := store.ReadCommitted(ctx, fullCheckpointID)
+	summary, err := committedStore.ReadCommitted(ctx, fullCheckpointID)
 	if err != nil {
 		return fmt.Errorf("failed to read checkpoint: %w", err)
 	}
@@ -256,34 +261,27 @@ func runExplainCheckpoint(ctx context.Context, w, errW io.Writer, checkpointIDPr
 		return fmt.Errorf("checkpoint not found: %s", fullCheckpointID)
 	}
 
-	// Load latest session content (needed for transcript and metadata)
-	content, err := store.ReadLatestSessionContent(ctx, fullCheckpointID)
-	if err != nil {
-		return fmt.Errorf("failed to read checkpoint content: %w", err)
-	}
-
 	// Handle summary generation
 	if generate {
-		if err := generateCheckpointSummary(ctx, w, errW, store, fullCheckpointID, summary, content, force); err != nil {
-			return err
-		}
-		// Reload the content to get the updated summary
-		content, err = store.ReadLatestSessionContent(ctx, fullCheckpointID)
+		content, err := committedStore.ReadLatestSessionContent(ctx, fullCheckpointID)
 		if err != nil {
-			return fmt.Errorf("failed to reload checkpoint: %w", err)
+			return fmt.Errorf("failed to read checkpoint content: %w", err)
+		}
+		if err := generateCheckpointSummary(ctx, w, errW, committedStore, fullCheckpointID, summary, content, force); err != nil {
+			return err
 		}
 	}
 
-	// Handle raw transcript output
+	// Handle raw transcript output, streamed so large transcripts aren't held in memory
 	if rawTranscript {
-		if len(content.Transcript) == 0 {
-			return fmt.Errorf("checkpoint %s has no transcript", fullCheckpointID)
-		}
-		// Output raw transcript directly (no pager, no formatting)
-		if _, err = w.Write(content.Transcript); err != nil {
-			return fmt.Errorf("failed to write transcript: %w", err)
-		}
-		return nil
+		return writeRawTranscript(ctx, w, committedStore, fullCheckpointID, len(summary.Sessions)-1)
+	}
+
+	// Load latest session content (needed for transcript and metadata),
+	// after generation so it includes a new summary
+	content, err := committedStore.ReadLatestSessionContent(ctx, fullCheckpointID)
+	if err != nil {
+		return fmt.Errorf("failed to read checkpoint content: %w", err)
 	}
 
 	// Look up the author for this checkpoint (best-effort, ignore errors)
@@ -294,14 +292,44 @@ func runExplainCheckpoint(ctx context.Context, w, errW io.Writer, checkpointIDPr
 
 	// Format and output
 	output := formatCheckpointOutput(summary, content, fullCheckpointID, associatedCommits, author, verbose, full)
+	if comments, _ := store.ReadComments(ctx, fullCheckpointID); len(comments) > 0 { //nolint:errcheck // Comments are optional
+		output += "\n" + formatComments(comments)
+		status := checkpoint.ComputeReviewStatus(comments, requiredApprovals(ctx))
+		if len(status.Approvers)+len(status.ChangesRequestedBy) > 0 {
+			output += "\n" + formatReviewStatus(status)
+		}
+	}
 	outputExplainContent(w, output, noPager)
 	return nil
 }
 
+// writeRawTranscript copies a session's transcript to w as stored, without a
+// pager or formatting. The transcript is streamed rather than loaded, so
+// large transcripts don't have to fit in memory.
+func writeRawTranscript(ctx context.Context, w io.Writer, store checkpoint.Store, checkpointID id.CheckpointID, sessionIndex int) error {
+	if sessionIndex < 0 {
+		return fmt.Errorf("checkpoint %s has no transcript", checkpointID)
+	}
+	transcript, err := store.OpenTranscript(ctx, checkpointID, sessionIndex)
+	if err != nil {
+		return fmt.Errorf("failed to read checkpoint content: %w", err)
+	}
+	defer transcript.Close()
+
+	n, err := io.Copy(w, transcript)
+	if err != nil {
+		return fmt.Errorf("failed to write transcript: %w", err)
+	}
+	if n == 0 {
+		return fmt.Errorf("checkpoint %s has no transcript", checkpointID)
+	}
+	return nil
+}
+
 // generateCheckpointSummary generates an AI summary for a checkpoint and persists it.
 // The summary is generated from the scoped transcript (only this checkpoint's portion),
 // not the entire session transcript.
-func generateCheckpointSummary(ctx context.Context, w, _ io.Writer, store *checkpoint.GitStore, checkpointID id.CheckpointID, cpSummary *checkpoint.CheckpointSummary, content *checkpoint.SessionContent, force bool) error {
+func generateCheckpointSummary(ctx context.Context, w, _ io.Writer, store checkpoint.CommittedStore, checkpointID id.CheckpointID, cpSummary *checkpoint.CheckpointSummary, content *checkpoint.SessionContent, force bool) error {
 	// Check if summary already exists
 	if content.Metadata.Summary != nil && !force {
 		return fmt.Errorf("checkpoint %s already has a summary (use --force to regenerate)", checkpointID)
@@ -411,21 +439,21 @@ func explainTemporaryCheckpoint(ctx context.Context, w io.Writer, repo *git.Repo
 	// Build output similar to formatCheckpointOutput but for temporary
 	var sb strings.Builder
 	shortID := tc.CommitHash.String()[:7]
-	fmt.Fprintf(&sb, "Checkpoint: %s [temporary]\n", shortID)
-	fmt.Fprintf(&sb, "Session: %s\n", tc.SessionID)
-	fmt.Fprintf(&sb, "Created: %s\n", tc.Timestamp.Format("2006-01-02 15:04:05"))
+	fmt.Fprintln(&sb, i18n.T(i18n.ExplainCheckpointTemporary, shortID))
+	fmt.Fprintln(&sb, i18n.T(i18n.ExplainSession, tc.SessionID))
+	fmt.Fprintln(&sb, i18n.T(i18n.ExplainCreated, tc.Timestamp.Format("2006-01-02 15:04:05")))
 	sb.WriteString("\n")
 
 	// Intent from prompt
-	intent := "(not available)"
+	intent := i18n.T(i18n.ExplainNotAvailable)
 	if sessionPrompt != "" {
 		lines := strings.Split(sessionPrompt, "\n")
 		if len(lines) > 0 && lines[0] != "" {
 			intent = strategy.TruncateDescription(lines[0], maxIntentDisplayLength)
 		}
 	}
-	fmt.Fprintf(&sb, "Intent: %s\n", intent)
-	sb.WriteString("Outcome: (not generated)\n")
+	fmt.Fprintln(&sb, i18n.T(i18n.ExplainIntent, intent))
+	fmt.Fprintln(&sb, i18n.T(i18n.ExplainOutcome, i18n.T(i18n.ExplainNotGenerated)))
 
 	// Transcript section: full shows entire session, verbose shows checkpoint scope
 	// For temporary checkpoints, load transcript and compute scope from parent commit
@@ -601,13 +629,13 @@ func formatCheckpointOutput(summary *checkpoint.CheckpointSummary, content *chec
 
 	// Header - always shown
 	// Note: CheckpointID is always exactly 12 characters, matching checkpointIDDisplayLength
-	fmt.Fprintf(&sb, "Checkpoint: %s\n", checkpointID)
-	fmt.Fprintf(&sb, "Session: %s\n", meta.SessionID)
-	fmt.Fprintf(&sb, "Created: %s\n", meta.CreatedAt.Format("2006-01-02 15:04:05"))
+	fmt.Fprintln(&sb, i18n.T(i18n.ExplainCheckpoint, checkpointID))
+	fmt.Fprintln(&sb, i18n.T(i18n.ExplainSession, meta.SessionID))
+	fmt.Fprintln(&sb, i18n.T(i18n.ExplainCreated, meta.CreatedAt.Format("2006-01-02 15:04:05")))
 
 	// Author (only for committed checkpoints with known author)
 	if author.Name != "" {
-		fmt.Fprintf(&sb, "Author: %s <%s>\n", author.Name, author.Email)
+		fmt.Fprintln(&sb, i18n.T(i18n.ExplainAuthor, author.Name, author.Email))
 	}
 
 	// Token usage - prefer content metadata, fall back to summary
@@ -618,42 +646,42 @@ func formatCheckpointOutput(summary *checkpoint.CheckpointSummary, content *chec
 	if tokenUsage != nil {
 		totalTokens := tokenUsage.InputTokens + tokenUsage.CacheCreationTokens +
 			tokenUsage.CacheReadTokens + tokenUsage.OutputTokens
-		fmt.Fprintf(&sb, "Tokens: %d\n", totalTokens)
+		fmt.Fprintln(&sb, i18n.T(i18n.ExplainTokens, totalTokens))
 	}
 
 	// Associated commits section
 	if len(associatedCommits) > 0 {
 		sb.WriteString("\n")
-		fmt.Fprintf(&sb, "Commits: (%d)\n", len(associatedCommits))
+		fmt.Fprintln(&sb, i18n.T(i18n.ExplainCommits, len(associatedCommits)))
 		for _, c := range associatedCommits {
 			fmt.Fprintf(&sb, "  %s %s %s\n", c.ShortSHA, c.Date.Format("2006-01-02"), c.Message)
 		}
 	} else if associatedCommits != nil {
 		// associatedCommits is non-nil but empty - show "no commits found" message
-		sb.WriteString("\nCommits: No commits found on this branch\n")
+		sb.WriteString("\n" + i18n.T(i18n.ExplainNoCommits) + "\n")
 	}
 
 	sb.WriteString("\n")
 
 	// Intent and Outcome from AI summary, or fallback to prompt text
 	if meta.Summary != nil {
-		fmt.Fprintf(&sb, "Intent: %s\n", meta.Summary.Intent)
-		fmt.Fprintf(&sb, "Outcome: %s\n", meta.Summary.Outcome)
+		fmt.Fprintln(&sb, i18n.T(i18n.ExplainIntent, meta.Summary.Intent))
+		fmt.Fprintln(&sb, i18n.T(i18n.ExplainOutcome, meta.Summary.Outcome))
 	} else {
 		// Fallback: use first line of scoped prompts for intent,
 		// or fall back to result.Prompts for backwards compatibility with older checkpoints
-		intent := "(not generated)"
+		intent := i18n.T(i18n.ExplainNotGenerated)
 		if len(scopedPrompts) > 0 && scopedPrompts[0] != "" {
 			intent = strategy.TruncateDescription(scopedPrompts[0], maxIntentDisplayLength)
-		} else if content.Prompts != "" {
+		} else if len(content.PromptList) > 0 {
 			// Backwards compatibility: use stored prompts if no transcript available
-			lines := strings.Split(content.Prompts, "\n")
+			lines := strings.Split(content.PromptList[0], "\n")
 			if len(lines) > 0 && lines[0] != "" {
 				intent = strategy.TruncateDescription(lines[0], maxIntentDisplayLength)
 			}
 		}
-		fmt.Fprintf(&sb, "Intent: %s\n", intent)
-		sb.WriteString("Outcome: (not generated)\n")
+		fmt.Fprintln(&sb, i18n.T(i18n.ExplainIntent, intent))
+		fmt.Fprintln(&sb, i18n.T(i18n.ExplainOutcome, i18n.T(i18n.ExplainNotGenerated)))
 	}
 
 	// Verbose: add learnings, friction, files, and scoped transcript
@@ -667,12 +695,26 @@ func formatCheckpointOutput(summary *checkpoint.CheckpointSummary, content *chec
 
 		// Files section
 		if len(meta.FilesTouched) > 0 {
-			fmt.Fprintf(&sb, "Files: (%d)\n", len(meta.FilesTouched))
+			fmt.Fprintln(&sb, i18n.T(i18n.ExplainFiles, len(meta.FilesTouched)))
 			for _, file := range meta.FilesTouched {
 				fmt.Fprintf(&sb, "  - %s\n", file)
 			}
 		} else {
-			sb.WriteString("Files: (none)\n")
+			fmt.Fprintln(&sb, i18n.T(i18n.ExplainNoFiles))
+		}
+
+		if len(meta.Attachments) > 0 {
+			fmt.Fprintln(&sb, i18n.T(i18n.ExplainAttachments, len(meta.Attachments)))
+			for _, att := range meta.Attachments {
+				fmt.Fprintf(&sb, "  - %s (%s, %d bytes)\n", att.Name, att.ContentType, att.Size)
+			}
+		}
+
+		if len(meta.Artifacts) > 0 {
+			fmt.Fprintln(&sb, i18n.T(i18n.ExplainArtifacts, len(meta.Artifacts)))
+			for _, art := range meta.Artifacts {
+				fmt.Fprintf(&sb, "  - %s (%s, %d bytes)\n", art.Name, art.ContentType, art.Size)
+			}
 		}
 	}
 
@@ -894,9 +936,13 @@ func walkFirstParentCommits(ctx context.Context, repo *git.Repository, from plum
 //   - Includes both committed checkpoints (entire/checkpoints/v1) and temporary checkpoints (shadow branches)
 func getBranchCheckpoints(ctx context.Context, repo *git.Repository, limit int) ([]strategy.RewindPoint, error) {
 	store := checkpoint.NewGitStore(repo)
+	committedStore, err := strategy.CommittedStore(ctx, store)
+	if err != nil {
+		return nil, err //nolint:wrapcheck // already names the setting
+	}
 
 	// Get all committed checkpoints for lookup
-	committedInfos, err := store.ListCommitted(ctx)
+	committedInfos, err := committedStore.ListCommitted(ctx)
 	if err != nil {
 		committedInfos = nil // Continue without committed checkpoints
 	}
@@ -946,7 +992,7 @@ func getBranchCheckpoints(ctx context.Context, repo *git.Repository, limit int)
 			Agent:            cpInfo.Agent,
 		}
 		// Read session prompt from metadata branch (best-effort)
-		content, _ := store.ReadLatestSessionContent(ctx, cpID) //nolint:errcheck  // Best-effort
+		content, _ := committedStore.ReadLatestSessionContent(ctx, cpID) //nolint:errcheck  // Best-effort
 		if content != nil {
 			scopedTranscript := scopeTranscriptForCheckpoint(content.Transcript, content.Metadata.GetTranscriptStart(), content.Metadata.Agent)
 			scopedPrompts := extractPromptsFromTranscript(scopedTranscript, content.Metadata.Agent)
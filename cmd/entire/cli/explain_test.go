@@ -862,6 +862,39 @@ func TestRunExplainCheckpoint_NotFound(t *testing.T) {
 	}
 }
 
+func TestRunExplainCheckpoint_RawTranscript(t *testing.T) {
+	repo, _ := setupCleanTestRepo(t)
+
+	cpID := id.MustCheckpointID("d1d2d3d4e5f6")
+	transcript := "{\"type\":\"user\",\"message\":{\"content\":\"hello\"}}\n"
+	store := checkpoint.NewGitStore(repo)
+	if err := store.WriteCommitted(context.Background(), checkpoint.WriteCommittedOptions{
+		CheckpointID: cpID,
+		SessionID:    "test-session",
+		Strategy:     "manual-commit",
+		Transcript:   []byte(transcript),
+		AuthorName:   "Test",
+		AuthorEmail:  "test@example.com",
+	}); err != nil {
+		t.Fatalf("failed to write committed checkpoint: %v", err)
+	}
+	if err := store.AppendTranscript(context.Background(), checkpoint.AppendTranscriptOptions{
+		CheckpointID: cpID,
+		SessionID:    "test-session",
+		Chunk:        []byte("{\"type\":\"assistant\"}\n"),
+	}); err != nil {
+		t.Fatalf("failed to append transcript: %v", err)
+	}
+
+	var buf, errBuf bytes.Buffer
+	if err := runExplainCheckpoint(context.Background(), &buf, &errBuf, cpID.String(), false, false, false, true, false, false, false); err != nil {
+		t.Fatalf("runExplainCheckpoint() error = %v", err)
+	}
+	if want := transcript + "{\"type\":\"assistant\"}\n"; buf.String() != want {
+		t.Errorf("raw transcript = %q, want %q", buf.String(), want)
+	}
+}
+
 func TestFormatCheckpointOutput_Short(t *testing.T) {
 	summary := &checkpoint.CheckpointSummary{
 		CheckpointID:     id.MustCheckpointID("abc123def456"),
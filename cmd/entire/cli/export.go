@@ -0,0 +1,381 @@
+package cli
+
+import (
+	"archive/tar"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+	"github.com/entireio/cli/cmd/entire/cli/strategy"
+
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/klauspost/compress/zstd"
+	"github.com/spf13/cobra"
+)
+
+// exportFormatVersion is bumped when the export bundle layout changes
+// incompatibly.
+const exportFormatVersion = 1
+
+// Paths within an export bundle. Checkpoint files live under
+// checkpoints/<checkpoint-id>/ as stored on the metadata branch; shadow
+// branch trees live under shadow/<n>/, n indexing the manifest's
+// shadow_branches.
+const (
+	exportManifestName   = "manifest.json"
+	exportCheckpointsDir = "checkpoints/"
+	exportShadowDir      = "shadow/"
+)
+
+// exportManifest describes the contents of an export bundle.
+type exportManifest struct {
+	FormatVersion  int                    `json:"format_version"`
+	CreatedAt      time.Time              `json:"created_at"`
+	Checkpoints    []id.CheckpointID      `json:"checkpoints"`
+	ShadowBranches []exportedShadowBranch `json:"shadow_branches,omitempty"`
+}
+
+// exportedShadowBranch is a shadow branch whose latest temporary checkpoint
+// the bundle holds.
+type exportedShadowBranch struct {
+	Branch    string `json:"branch"`
+	SessionID string `json:"session_id"`
+	Message   string `json:"message"`
+}
+
+// exportBundle is the decoded content of an export bundle.
+type exportBundle struct {
+	Manifest    exportManifest
+	Checkpoints map[id.CheckpointID]map[string][]byte
+	Shadows     []*checkpoint.ShadowSnapshot
+}
+
+func newExportCmd() *cobra.Command {
+	var outFlag string
+
+	cmd := &cobra.Command{
+		Use:   "export <checkpoint-id|session-id>",
+		Short: "Export checkpoints to a portable bundle file",
+		Long: `Export writes checkpoints to a single .tar.zst bundle that 'entire import'
+reads into another repository, e.g. to attach a session to a bug report
+without sharing the whole entire/checkpoints/v1 branch.
+
+Given a checkpoint ID (or a unique prefix), the bundle holds that checkpoint.
+Given a session ID, it holds every checkpoint of the session. Checkpoint
+metadata, transcripts, prompts, context, and attachments are included
+exactly as stored (already redacted, and still encrypted if encryption is
+on). If a session in the bundle still has a shadow branch, the tree of its
+latest temporary checkpoint is included too.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			if _, err := paths.WorktreeRoot(ctx); err != nil {
+				return paths.ErrNotGitRepository
+			}
+			out := outFlag
+			if out == "" {
+				out = "entire-" + args[0] + ".tar.zst"
+			}
+			return runExport(ctx, cmd.OutOrStdout(), args[0], out, time.Now())
+		},
+	}
+
+	cmd.Flags().StringVarP(&outFlag, "out", "o", "", "File to write the bundle to (default entire-<id>.tar.zst)")
+
+	return cmd
+}
+
+func runExport(ctx context.Context, w io.Writer, target, outPath string, now time.Time) error {
+	repo, err := openRepository(ctx)
+	if err != nil {
+		return fmt.Errorf("not a git repository: %w", err)
+	}
+	store, err := strategy.CommittedStore(ctx, checkpoint.NewGitStore(repo))
+	if err != nil {
+		return err //nolint:wrapcheck // already names the setting
+	}
+
+	checkpointIDs, sessionIDs, err := resolveExportTarget(ctx, store, target)
+	if err != nil {
+		return err
+	}
+
+	bundle := &exportBundle{
+		Manifest: exportManifest{
+			FormatVersion: exportFormatVersion,
+			CreatedAt:     now.UTC(),
+			Checkpoints:   checkpointIDs,
+		},
+		Checkpoints: make(map[id.CheckpointID]map[string][]byte, len(checkpointIDs)),
+	}
+	for _, cpID := range checkpointIDs {
+		files, err := store.ExportCheckpoint(ctx, cpID)
+		if err != nil {
+			return fmt.Errorf("failed to read checkpoint %s: %w", cpID, err)
+		}
+		bundle.Checkpoints[cpID] = files
+	}
+
+	exportedBranches := make(map[string]bool)
+	for _, sessionID := range sessionIDs {
+		state, err := strategy.LoadSessionState(ctx, sessionID)
+		if err != nil || state == nil || state.BaseCommit == "" {
+			continue // The session's shadow branch is optional
+		}
+		branch := checkpoint.ShadowBranchNameForCommit(state.BaseCommit, state.WorktreeID)
+		if exportedBranches[branch] {
+			continue
+		}
+		snapshot, err := store.ExportShadowBranch(ctx, branch)
+		if err != nil {
+			return fmt.Errorf("failed to read shadow branch %s: %w", branch, err)
+		}
+		if snapshot == nil {
+			continue
+		}
+		exportedBranches[branch] = true
+		bundle.Manifest.ShadowBranches = append(bundle.Manifest.ShadowBranches, exportedShadowBranch{
+			Branch:    branch,
+			SessionID: sessionID,
+			Message:   snapshot.Message,
+		})
+		bundle.Shadows = append(bundle.Shadows, snapshot)
+	}
+
+	f, err := os.Create(outPath) //nolint:gosec // Path comes from the user's --out flag
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", outPath, err)
+	}
+	if err := writeExportBundle(f, bundle); err != nil {
+		_ = f.Close()
+		_ = os.Remove(outPath)
+		return fmt.Errorf("failed to write %s: %w", outPath, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", outPath, err)
+	}
+
+	fmt.Fprintf(w, "Exported %d checkpoint(s)", len(checkpointIDs))
+	if n := len(bundle.Shadows); n > 0 {
+		fmt.Fprintf(w, " and %d shadow branch(es)", n)
+	}
+	fmt.Fprintf(w, " to %s\n", outPath)
+	return nil
+}
+
+// resolveExportTarget returns the checkpoints to export for a checkpoint ID
+// prefix or a session ID, and the IDs of the sessions they hold.
+func resolveExportTarget(ctx context.Context, store checkpoint.CommittedStore, target string) ([]id.CheckpointID, []string, error) {
+	checkpointID, resolveErr := resolveCommittedCheckpointID(ctx, store, target)
+	if resolveErr == nil {
+		metadata, err := store.ReadSessionMetadata(ctx, checkpointID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read checkpoint %s: %w", checkpointID, err)
+		}
+		sessionIDs := make([]string, 0, len(metadata))
+		for _, meta := range metadata {
+			sessionIDs = append(sessionIDs, meta.SessionID)
+		}
+		return []id.CheckpointID{checkpointID}, sessionIDs, nil
+	}
+
+	page, err := store.ListCommittedPage(ctx, checkpoint.ListOptions{SessionID: target})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list checkpoints: %w", err)
+	}
+	if len(page.Checkpoints) == 0 {
+		return nil, nil, fmt.Errorf("no checkpoint or session found for %s: %w", target, resolveErr)
+	}
+	checkpointIDs := make([]id.CheckpointID, 0, len(page.Checkpoints))
+	for _, info := range page.Checkpoints {
+		checkpointIDs = append(checkpointIDs, info.CheckpointID)
+	}
+	return checkpointIDs, []string{target}, nil
+}
+
+// writeExportBundle writes bundle to w as a zstd-compressed tar archive,
+// manifest first.
+func writeExportBundle(w io.Writer, bundle *exportBundle) error {
+	enc, err := zstd.NewWriter(w)
+	if err != nil {
+		return fmt.Errorf("failed to create compressor: %w", err)
+	}
+	tw := tar.NewWriter(enc)
+	modTime := bundle.Manifest.CreatedAt
+
+	manifest, err := json.MarshalIndent(bundle.Manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal manifest: %w", err)
+	}
+	if err := writeTarFile(tw, exportManifestName, manifest, 0o644, modTime); err != nil {
+		return err
+	}
+
+	for _, cpID := range bundle.Manifest.Checkpoints {
+		files := bundle.Checkpoints[cpID]
+		for _, name := range sortedKeys(files) {
+			if err := writeTarFile(tw, exportCheckpointsDir+cpID.String()+"/"+name, files[name], 0o644, modTime); err != nil {
+				return err
+			}
+		}
+	}
+
+	for i, snapshot := range bundle.Shadows {
+		dir := exportShadowDir + strconv.Itoa(i) + "/"
+		for _, name := range sortedKeys(snapshot.Files) {
+			file := snapshot.Files[name]
+			var err error
+			switch file.Mode {
+			case filemode.Symlink:
+				err = tw.WriteHeader(&tar.Header{
+					Typeflag: tar.TypeSymlink,
+					Name:     dir + name,
+					Linkname: string(file.Data),
+					Mode:     0o777,
+					ModTime:  modTime,
+				})
+			case filemode.Executable:
+				err = writeTarFile(tw, dir+name, file.Data, 0o755, modTime)
+			default:
+				err = writeTarFile(tw, dir+name, file.Data, 0o644, modTime)
+			}
+			if err != nil {
+				return fmt.Errorf("failed to write %s: %w", dir+name, err)
+			}
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("failed to finish archive: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return fmt.Errorf("failed to finish compression: %w", err)
+	}
+	return nil
+}
+
+func writeTarFile(tw *tar.Writer, name string, data []byte, mode int64, modTime time.Time) error {
+	if err := tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     name,
+		Size:     int64(len(data)),
+		Mode:     mode,
+		ModTime:  modTime,
+	}); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	if _, err := tw.Write(data); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// readExportBundle decodes a bundle written by writeExportBundle.
+func readExportBundle(r io.Reader) (*exportBundle, error) {
+	dec, err := zstd.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress bundle: %w", err)
+	}
+	defer dec.Close()
+
+	bundle := &exportBundle{Checkpoints: make(map[id.CheckpointID]map[string][]byte)}
+	shadows := make(map[int]*checkpoint.ShadowSnapshot)
+	var hasManifest bool
+	tr := tar.NewReader(dec)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read bundle: %w", err)
+		}
+		name := path.Clean(header.Name)
+		if name != header.Name || path.IsAbs(name) || strings.HasPrefix(name, "../") {
+			return nil, fmt.Errorf("invalid path in bundle: %q", header.Name)
+		}
+
+		var data []byte
+		switch header.Typeflag {
+		case tar.TypeReg:
+			if data, err = io.ReadAll(tr); err != nil {
+				return nil, fmt.Errorf("failed to read %s from bundle: %w", name, err)
+			}
+		case tar.TypeSymlink:
+			data = []byte(header.Linkname)
+		default:
+			continue
+		}
+
+		switch {
+		case name == exportManifestName:
+			if err := json.Unmarshal(data, &bundle.Manifest); err != nil {
+				return nil, fmt.Errorf("failed to parse bundle manifest: %w", err)
+			}
+			if bundle.Manifest.FormatVersion > exportFormatVersion {
+				return nil, fmt.Errorf("bundle format %d is newer than this version of entire supports; upgrade entire", bundle.Manifest.FormatVersion)
+			}
+			hasManifest = true
+		case strings.HasPrefix(name, exportCheckpointsDir):
+			cpIDStr, file, ok := strings.Cut(strings.TrimPrefix(name, exportCheckpointsDir), "/")
+			cpID, err := id.NewCheckpointID(cpIDStr)
+			if !ok || err != nil {
+				return nil, fmt.Errorf("invalid checkpoint path in bundle: %q", name)
+			}
+			if bundle.Checkpoints[cpID] == nil {
+				bundle.Checkpoints[cpID] = make(map[string][]byte)
+			}
+			bundle.Checkpoints[cpID][file] = data
+		case strings.HasPrefix(name, exportShadowDir):
+			indexStr, file, ok := strings.Cut(strings.TrimPrefix(name, exportShadowDir), "/")
+			index, err := strconv.Atoi(indexStr)
+			if !ok || err != nil || index < 0 {
+				return nil, fmt.Errorf("invalid shadow branch path in bundle: %q", name)
+			}
+			if shadows[index] == nil {
+				shadows[index] = &checkpoint.ShadowSnapshot{Files: make(map[string]checkpoint.ShadowFile)}
+			}
+			mode := filemode.Regular
+			switch {
+			case header.Typeflag == tar.TypeSymlink:
+				mode = filemode.Symlink
+			case header.Mode&0o111 != 0:
+				mode = filemode.Executable
+			}
+			shadows[index].Files[file] = checkpoint.ShadowFile{Mode: mode, Data: data}
+		}
+	}
+	if !hasManifest {
+		return nil, errors.New("not an entire export bundle: no manifest")
+	}
+
+	for i, branch := range bundle.Manifest.ShadowBranches {
+		snapshot := shadows[i]
+		if snapshot == nil {
+			snapshot = &checkpoint.ShadowSnapshot{Files: map[string]checkpoint.ShadowFile{}}
+		}
+		snapshot.Message = branch.Message
+		bundle.Shadows = append(bundle.Shadows, snapshot)
+	}
+	return bundle, nil
+}
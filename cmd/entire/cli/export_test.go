@@ -0,0 +1,152 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+	"github.com/entireio/cli/cmd/entire/cli/session"
+	"github.com/entireio/cli/cmd/entire/cli/strategy"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+)
+
+func TestExportImport_RoundTrip(t *testing.T) {
+	repo, head := setupCleanTestRepo(t)
+	ctx := context.Background()
+	bundlePath := filepath.Join(t.TempDir(), "bundle.tar.zst")
+
+	cpID := id.MustCheckpointID("e1e2e3e4e5f6")
+	store := checkpoint.NewGitStore(repo)
+	if err := store.WriteCommitted(ctx, checkpoint.WriteCommittedOptions{
+		CheckpointID: cpID,
+		SessionID:    "export-session",
+		Strategy:     "manual-commit",
+		Transcript:   []byte(`{"type":"user","message":{"content":"hello"}}` + "\n"),
+		Prompts:      []string{"hello"},
+		Context:      []byte("context"),
+		AuthorName:   "Test",
+		AuthorEmail:  "test@example.com",
+	}); err != nil {
+		t.Fatalf("WriteCommitted() error = %v", err)
+	}
+
+	// An active session with a shadow branch holding a script and a symlink
+	state := &strategy.SessionState{
+		SessionID:  "export-session",
+		BaseCommit: head.String(),
+		StartedAt:  time.Now(),
+		Phase:      session.PhaseIdle,
+	}
+	if err := strategy.SaveSessionState(ctx, state); err != nil {
+		t.Fatalf("SaveSessionState() error = %v", err)
+	}
+	shadowBranch := checkpoint.ShadowBranchNameForCommit(head.String(), "")
+	shadow := &checkpoint.ShadowSnapshot{
+		Message: "Checkpoint\n\nEntire-Session: export-session\n",
+		Files: map[string]checkpoint.ShadowFile{
+			"run.sh":       {Mode: filemode.Executable, Data: []byte("#!/bin/sh\n")},
+			"src/main.go":  {Mode: filemode.Regular, Data: []byte("package main\n")},
+			"link-to-main": {Mode: filemode.Symlink, Data: []byte("src/main.go")},
+		},
+	}
+	if err := store.ImportShadowBranch(ctx, shadowBranch, shadow); err != nil {
+		t.Fatalf("ImportShadowBranch() error = %v", err)
+	}
+
+	var out bytes.Buffer
+	if err := runExport(ctx, &out, "export-session", bundlePath, time.Now()); err != nil {
+		t.Fatalf("runExport() error = %v", err)
+	}
+	if !strings.Contains(out.String(), "Exported 1 checkpoint(s) and 1 shadow branch(es)") {
+		t.Errorf("export output = %q", out.String())
+	}
+	wantFiles, err := store.ExportCheckpoint(ctx, cpID)
+	if err != nil {
+		t.Fatalf("ExportCheckpoint() error = %v", err)
+	}
+
+	// Import into a fresh repository
+	target, _ := setupCleanTestRepo(t)
+	out.Reset()
+	if err := runImport(ctx, &out, bundlePath, false); err != nil {
+		t.Fatalf("runImport() error = %v", err)
+	}
+	if !strings.Contains(out.String(), "Imported 1 checkpoint(s) and 1 shadow branch(es)") {
+		t.Errorf("import output = %q", out.String())
+	}
+
+	targetStore := checkpoint.NewGitStore(target)
+	gotFiles, err := targetStore.ExportCheckpoint(ctx, cpID)
+	if err != nil {
+		t.Fatalf("imported checkpoint: %v", err)
+	}
+	if len(gotFiles) != len(wantFiles) {
+		t.Errorf("imported %d files, want %d", len(gotFiles), len(wantFiles))
+	}
+	for name, data := range wantFiles {
+		if !bytes.Equal(gotFiles[name], data) {
+			t.Errorf("imported %s differs from the exported file", name)
+		}
+	}
+	content, err := targetStore.ReadSessionContent(ctx, cpID, 0)
+	if err != nil {
+		t.Fatalf("ReadSessionContent() error = %v", err)
+	}
+	if !strings.Contains(string(content.Transcript), "hello") {
+		t.Errorf("imported transcript = %q", content.Transcript)
+	}
+
+	gotShadow, err := targetStore.ExportShadowBranch(ctx, shadowBranch)
+	if err != nil || gotShadow == nil {
+		t.Fatalf("imported shadow branch = %v, %v", gotShadow, err)
+	}
+	if gotShadow.Message != shadow.Message {
+		t.Errorf("shadow commit message = %q, want %q", gotShadow.Message, shadow.Message)
+	}
+	for name, want := range shadow.Files {
+		got := gotShadow.Files[name]
+		if got.Mode != want.Mode || !bytes.Equal(got.Data, want.Data) {
+			t.Errorf("shadow file %s = %v %q, want %v %q", name, got.Mode, got.Data, want.Mode, want.Data)
+		}
+	}
+
+	// A second import skips what already exists
+	out.Reset()
+	if err := runImport(ctx, &out, bundlePath, false); err != nil {
+		t.Fatalf("second runImport() error = %v", err)
+	}
+	if !strings.Contains(out.String(), "Skipped checkpoint "+cpID.String()) ||
+		!strings.Contains(out.String(), "Skipped shadow branch "+shadowBranch) {
+		t.Errorf("second import output = %q", out.String())
+	}
+	if _, err := target.Reference(plumbing.NewBranchReferenceName(shadowBranch), true); err != nil {
+		t.Errorf("shadow branch missing after second import: %v", err)
+	}
+}
+
+func TestRunExport_UnknownTarget(t *testing.T) {
+	setupCleanTestRepo(t)
+
+	err := runExport(context.Background(), &bytes.Buffer{}, "no-such-session", filepath.Join(t.TempDir(), "b.tar.zst"), time.Now())
+	if err == nil || !strings.Contains(err.Error(), "no checkpoint or session found") {
+		t.Errorf("runExport() error = %v, want not found", err)
+	}
+}
+
+func TestReadExportBundle_RejectsNewerFormat(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	if err := writeExportBundle(&buf, &exportBundle{Manifest: exportManifest{FormatVersion: exportFormatVersion + 1}}); err != nil {
+		t.Fatalf("writeExportBundle() error = %v", err)
+	}
+	if _, err := readExportBundle(&buf); err == nil || !strings.Contains(err.Error(), "newer") {
+		t.Errorf("readExportBundle() error = %v, want a format version error", err)
+	}
+}
@@ -0,0 +1,139 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"time"
+
+	"github.com/entireio/cli/cmd/entire/cli/logging"
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+	"github.com/entireio/cli/cmd/entire/cli/settings"
+	"github.com/entireio/cli/cmd/entire/cli/strategy"
+
+	"github.com/spf13/cobra"
+)
+
+// defaultAbandonedAfter is how long a session may sit without activity before
+// `entire finalize --stale` considers it abandoned.
+const defaultAbandonedAfter = 24 * time.Hour
+
+func newFinalizeCmd() *cobra.Command {
+	var staleFlag bool
+	var olderThanFlag time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "finalize --stale",
+		Short: "Close out abandoned sessions",
+		Long: `Finalize closes out sessions whose agent is gone, so they don't linger as
+active sessions.
+
+With --stale, a session in this worktree is finalized if its agent went silent
+in the middle of a turn, or if it saw no activity for --older-than. Its final
+transcript is checkpointed along with any uncommitted changes of the
+interrupted turn, checkpoints already committed during the turn are updated
+with that transcript, and the session is marked ended.
+
+Finalize never prompts and does nothing outside a repository with Entire
+enabled, so it is safe to run from cron or a git hook:
+
+  */30 * * * * cd ~/src/project && entire finalize --stale`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if !staleFlag {
+				return errors.New("nothing to finalize: pass --stale to close out abandoned sessions")
+			}
+			ctx := cmd.Context()
+			if _, err := paths.WorktreeRoot(ctx); err != nil {
+				return nil //nolint:nilerr // background runs outside a repository are a no-op
+			}
+			if !settings.IsSetUpAndEnabled(ctx) {
+				return nil
+			}
+			return runFinalizeStale(ctx, cmd.OutOrStdout(), olderThanFlag, time.Now())
+		},
+	}
+
+	cmd.Flags().BoolVar(&staleFlag, "stale", false, "Finalize sessions that went silent or were abandoned")
+	cmd.Flags().DurationVar(&olderThanFlag, "older-than", defaultAbandonedAfter, "How long without activity before a session is abandoned")
+
+	return cmd
+}
+
+func runFinalizeStale(ctx context.Context, w io.Writer, olderThan time.Duration, now time.Time) error {
+	logging.SetLogLevelGetter(GetLogLevel)
+	if err := logging.Init(ctx, ""); err == nil {
+		defer logging.Close()
+	}
+	logCtx := logging.WithComponent(ctx, "finalize")
+
+	repoRoot, err := paths.WorktreeRoot(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get worktree root: %w", err)
+	}
+	states, err := strategy.ListSessionStates(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list session states: %w", err)
+	}
+
+	finalized := 0
+	for _, state := range states {
+		// Changes are read from this worktree, so only its sessions can be finalized here
+		if state.WorktreePath != repoRoot || !isAbandonedSession(state, olderThan, now) {
+			continue
+		}
+		if err := finalizeAbandonedSession(ctx, state, now); err != nil {
+			logging.Warn(logCtx, "failed to finalize session",
+				slog.String("session_id", state.SessionID),
+				slog.String("error", err.Error()))
+			fmt.Fprintf(w, "Warning: failed to finalize session %s: %v\n", state.SessionID, err)
+			continue
+		}
+		finalized++
+		fmt.Fprintf(w, "Finalized session %s (%s, last active %s)\n",
+			state.SessionID, state.AgentType, timeAgo(lastSessionActivity(state)))
+	}
+
+	if finalized == 0 {
+		fmt.Fprintln(w, "No stale sessions.")
+	}
+	return nil
+}
+
+// isAbandonedSession returns true if the session hasn't ended but its agent
+// went silent mid-turn, or nothing happened in it for olderThan.
+func isAbandonedSession(state *strategy.SessionState, olderThan time.Duration, now time.Time) bool {
+	if state.EndedAt != nil {
+		return false
+	}
+	return state.IsSilent(now) || now.Sub(lastSessionActivity(state)) > olderThan
+}
+
+// lastSessionActivity returns the session's last heartbeat, or its start if it never had one.
+func lastSessionActivity(state *strategy.SessionState) time.Time {
+	if last := state.LastHeartbeat(); last != nil {
+		return *last
+	}
+	return state.StartedAt
+}
+
+// finalizeAbandonedSession ends an abandoned session. A turn still in progress
+// is checkpointed from the worktree first; otherwise checkpoints committed during
+// the last turn are brought up to date with the final transcript.
+func finalizeAbandonedSession(ctx context.Context, state *strategy.SessionState, now time.Time) error {
+	if state.Phase.IsActive() {
+		if err := finalizeCrashedSession(ctx, state, now); err != nil {
+			return err
+		}
+	} else if len(state.TurnCheckpointIDs) > 0 {
+		if err := GetStrategy(ctx).HandleTurnEnd(ctx, state); err != nil {
+			return fmt.Errorf("failed to update committed checkpoints: %w", err)
+		}
+		if err := strategy.SaveSessionState(ctx, state); err != nil {
+			return fmt.Errorf("failed to save session state: %w", err)
+		}
+	}
+	return markSessionEnded(ctx, state.SessionID)
+}
@@ -0,0 +1,101 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/entireio/cli/cmd/entire/cli/session"
+	"github.com/entireio/cli/cmd/entire/cli/strategy"
+)
+
+// saveIdleSession saves an idle session in dir last active at lastActive.
+func saveIdleSession(t *testing.T, dir, sessionID string, lastActive time.Time) {
+	t.Helper()
+	state := &strategy.SessionState{
+		SessionID:           sessionID,
+		WorktreePath:        dir,
+		StartedAt:           lastActive.Add(-time.Hour),
+		Phase:               session.PhaseIdle,
+		LastInteractionTime: &lastActive,
+		AgentType:           "Claude Code",
+	}
+	if err := strategy.SaveSessionState(context.Background(), state); err != nil {
+		t.Fatalf("SaveSessionState() error = %v", err)
+	}
+}
+
+func loadFinalizeTestState(t *testing.T, sessionID string) *strategy.SessionState {
+	t.Helper()
+	state, err := strategy.LoadSessionState(context.Background(), sessionID)
+	if err != nil || state == nil {
+		t.Fatalf("LoadSessionState(%s) = %v, %v", sessionID, state, err)
+	}
+	return state
+}
+
+func TestRunFinalizeStale_EndsAbandonedSessions(t *testing.T) {
+	dir := setupExecTestRepo(t)
+	now := time.Now()
+	saveIdleSession(t, dir, "abandoned-session", now.Add(-48*time.Hour))
+	saveIdleSession(t, dir, "recent-session", now.Add(-time.Hour))
+
+	var out bytes.Buffer
+	if err := runFinalizeStale(context.Background(), &out, defaultAbandonedAfter, now); err != nil {
+		t.Fatalf("runFinalizeStale() error = %v", err)
+	}
+
+	if state := loadFinalizeTestState(t, "abandoned-session"); state.EndedAt == nil || state.Phase != session.PhaseEnded {
+		t.Errorf("abandoned session should be ended, got phase %s", state.Phase)
+	}
+	if state := loadFinalizeTestState(t, "recent-session"); state.EndedAt != nil {
+		t.Error("recently active session should be left alone")
+	}
+	if !strings.Contains(out.String(), "Finalized session abandoned-session (Claude Code, last active 2d ago)") {
+		t.Errorf("output should list the finalized session, got: %s", out.String())
+	}
+}
+
+func TestRunFinalizeStale_CheckpointsInterruptedTurn(t *testing.T) {
+	dir := setupExecTestRepo(t)
+	startSilentSession(t, dir)
+
+	var out bytes.Buffer
+	if err := runFinalizeStale(context.Background(), &out, defaultAbandonedAfter, time.Now()); err != nil {
+		t.Fatalf("runFinalizeStale() error = %v", err)
+	}
+
+	state := loadFinalizeTestState(t, "silent-session")
+	if state.EndedAt == nil {
+		t.Error("silent session should be ended")
+	}
+	if state.StepCount == 0 {
+		t.Error("the interrupted turn's changes should be checkpointed")
+	}
+}
+
+func TestRunFinalizeStale_NothingStale(t *testing.T) {
+	setupExecTestRepo(t)
+
+	var out bytes.Buffer
+	if err := runFinalizeStale(context.Background(), &out, defaultAbandonedAfter, time.Now()); err != nil {
+		t.Fatalf("runFinalizeStale() error = %v", err)
+	}
+	if !strings.Contains(out.String(), "No stale sessions.") {
+		t.Errorf("output = %q, want a note that nothing was stale", out.String())
+	}
+}
+
+func TestFinalizeCmd_RequiresStale(t *testing.T) {
+	t.Parallel()
+
+	cmd := newFinalizeCmd()
+	cmd.SetArgs([]string{})
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "--stale") {
+		t.Errorf("finalize error = %v, want a hint to pass --stale", err)
+	}
+}
@@ -0,0 +1,325 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+	"github.com/entireio/cli/cmd/entire/cli/settings"
+	"github.com/entireio/cli/cmd/entire/cli/strategy"
+
+	"github.com/spf13/cobra"
+)
+
+func newGCCmd() *cobra.Command {
+	var dryRunFlag bool
+	var maxAgeDaysFlag int
+	var maxPerSessionFlag int
+	var coordinateFlag bool
+	var mergedFlag bool
+
+	cmd := &cobra.Command{
+		Use:   "gc",
+		Short: "Prune old checkpoints and orphaned shadow branches",
+		Long: `Prune committed checkpoints that fall outside the retention policy from the
+entire/checkpoints/v1 branch, and delete shadow branches no session uses.
+
+The policy comes from the "gc" setting and can be overridden per run:
+
+  --max-age-days N      prune checkpoints created more than N days ago
+  --max-per-session N   keep only each session's N most recent checkpoints
+
+A checkpoint with several sessions is kept while any of them keeps it.
+Checkpoints of a turn that is still being finalized are never pruned.
+Without a policy, only shadow branches are deleted.
+
+With --merged (or the "gc.merged" setting), gc also removes the shadow
+branches sessions still refer to once their base commit's branch is done
+with: the commit is in the default branch, or no branch reaches it anymore
+because the branch was deleted after a merge. A base commit that is still
+HEAD or a branch tip is kept, as are the shadow branches of sessions in the
+middle of a turn. Each removed branch is archived first under
+refs/entire/shadow-archive/, so its rewind points can be restored with
+'git branch entire/<name> refs/entire/shadow-archive/<name>'.
+
+Pruning adds a commit that removes the checkpoints; earlier commits on the
+branch still hold them. The prune is recorded in 'entire audit-log'.
+
+Session state refers to base commits by hash, which 'git gc --prune' would
+remove once no branch reaches them. gc keeps them reachable with anchor refs
+under refs/entire/anchors/, and releases the anchors of sessions that are
+gone. With --coordinate, gc then runs 'git gc' itself, after the anchors are
+in place and Entire's own pruning is done, so git only prunes what Entire no
+longer needs. Run 'entire gc --coordinate' instead of 'git gc' after deleting
+Entire's refs by hand.
+
+Use --dry-run to see what would be removed.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := cmd.Context()
+			if _, err := paths.WorktreeRoot(ctx); err != nil {
+				return paths.ErrNotGitRepository
+			}
+			s, err := settings.Load(ctx)
+			if err != nil {
+				return fmt.Errorf("failed to load settings: %w", err)
+			}
+			policy := s.GetGC()
+			if cmd.Flags().Changed("max-age-days") {
+				policy.MaxAgeDays = maxAgeDaysFlag
+			}
+			if cmd.Flags().Changed("max-per-session") {
+				policy.MaxPerSession = maxPerSessionFlag
+			}
+			if cmd.Flags().Changed("merged") {
+				policy.Merged = mergedFlag
+			}
+			if policy.MaxAgeDays < 0 || policy.MaxPerSession < 0 {
+				return errors.New("--max-age-days and --max-per-session must not be negative")
+			}
+			if s.GetCheckpointStore() != nil && policy.HasRetention() {
+				fmt.Fprintln(cmd.ErrOrStderr(), "Checkpoints in checkpoint_store aren't pruned; use the bucket's lifecycle rules.")
+				policy = settings.GCSettings{Merged: policy.Merged}
+			}
+			return runGC(ctx, cmd.OutOrStdout(), policy, gcOptions{DryRun: dryRunFlag, Coordinate: coordinateFlag}, time.Now())
+		},
+	}
+
+	cmd.Flags().BoolVar(&dryRunFlag, "dry-run", false, "Show what would be removed without removing it")
+	cmd.Flags().IntVar(&maxAgeDaysFlag, "max-age-days", 0, "Prune checkpoints older than this many days (0 keeps all; default from settings)")
+	cmd.Flags().IntVar(&maxPerSessionFlag, "max-per-session", 0, "Keep this many recent checkpoints per session (0 keeps all; default from settings)")
+	cmd.Flags().BoolVar(&mergedFlag, "merged", false, "Also archive and remove shadow branches of merged or deleted branches (default from settings)")
+	cmd.Flags().BoolVar(&coordinateFlag, "coordinate", false, "Run git gc afterwards, once session commits are anchored")
+
+	return cmd
+}
+
+// gcOptions controls how runGC removes data.
+type gcOptions struct {
+	// DryRun reports what would be removed without removing it.
+	DryRun bool
+
+	// Coordinate runs `git gc` once Entire's pruning is done.
+	Coordinate bool
+}
+
+// gcPrune is a checkpoint the retention policy removes.
+type gcPrune struct {
+	ID     id.CheckpointID
+	Reason string
+}
+
+// runGC anchors session commits, prunes Entire's data, and then, if asked,
+// runs `git gc`. Anchoring comes first so that neither step can lose a
+// commit a session still refers to.
+func runGC(ctx context.Context, w io.Writer, policy settings.GCSettings, opts gcOptions, now time.Time) error {
+	if !opts.DryRun {
+		anchors, err := strategy.AnchorSessionCommits(ctx)
+		if err != nil {
+			return err //nolint:wrapcheck // already wrapped by strategy
+		}
+		if anchors.Anchored > 0 {
+			fmt.Fprintf(w, "Anchored %d session commit(s) under %s\n", anchors.Anchored, strategy.AnchorRefPrefix)
+		}
+		if anchors.Released > 0 {
+			fmt.Fprintf(w, "Released %d anchor(s) of sessions that are gone\n", anchors.Released)
+		}
+	}
+
+	if err := pruneEntireData(ctx, w, policy, opts.DryRun, now); err != nil {
+		return err
+	}
+
+	if !opts.Coordinate {
+		return nil
+	}
+	if opts.DryRun {
+		fmt.Fprintln(w, "Would run git gc.")
+		return nil
+	}
+	fmt.Fprintln(w, "Running git gc...")
+	cmd := exec.CommandContext(ctx, "git", "gc", "--quiet")
+	cmd.Stdout = w
+	cmd.Stderr = w
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("git gc failed: %w", err)
+	}
+	return nil
+}
+
+// pruneEntireData removes checkpoints outside the policy and shadow branches
+// no session uses, and with policy.Merged archives and removes the shadow
+// branches of merged or deleted branches.
+func pruneEntireData(ctx context.Context, w io.Writer, policy settings.GCSettings, dryRun bool, now time.Time) error {
+	repo, err := openRepository(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to open repository: %w", err)
+	}
+	store, err := strategy.CommittedStore(ctx, checkpoint.NewGitStore(repo))
+	if err != nil {
+		return err //nolint:wrapcheck // already names the setting
+	}
+
+	states, err := strategy.ListSessionStates(ctx)
+	if err != nil {
+		return err //nolint:wrapcheck // already wrapped by strategy
+	}
+	inUse := make(map[id.CheckpointID]bool)
+	usedBranches := make(map[string]bool)
+	for _, state := range states {
+		for _, cpID := range state.TurnCheckpointIDs {
+			inUse[id.CheckpointID(cpID)] = true
+		}
+		usedBranches[checkpoint.ShadowBranchNameForCommit(state.BaseCommit, state.WorktreeID)] = true
+	}
+
+	var prunes []gcPrune
+	if policy.HasRetention() {
+		checkpoints, err := store.ListCommitted(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list checkpoints: %w", err)
+		}
+		prunes = selectGCPrunes(checkpoints, policy, inUse, now)
+	}
+
+	shadowBranches, err := strategy.ListShadowBranches(ctx)
+	if err != nil {
+		return err //nolint:wrapcheck // already wrapped by strategy
+	}
+	var orphanedBranches []string
+	for _, branch := range shadowBranches {
+		if !usedBranches[branch] {
+			orphanedBranches = append(orphanedBranches, branch)
+		}
+	}
+
+	var mergedBranches []strategy.MergedShadowBranch
+	if policy.Merged {
+		mergedBranches, err = strategy.ListMergedShadowBranches(ctx, states)
+		if err != nil {
+			return err //nolint:wrapcheck // already wrapped by strategy
+		}
+	}
+
+	if len(prunes) == 0 && len(orphanedBranches) == 0 && len(mergedBranches) == 0 {
+		fmt.Fprintln(w, "Nothing to remove.")
+		return nil
+	}
+
+	verb := "Removed"
+	if dryRun {
+		verb = "Would remove"
+	}
+	if len(prunes) > 0 {
+		fmt.Fprintf(w, "%s %d checkpoint(s) from %s:\n", verb, len(prunes), paths.MetadataBranchName)
+		for _, p := range prunes {
+			fmt.Fprintf(w, "  %s  %s\n", p.ID, p.Reason)
+		}
+	}
+	if len(orphanedBranches) > 0 {
+		fmt.Fprintf(w, "%s %d orphaned shadow branch(es):\n", verb, len(orphanedBranches))
+		for _, branch := range orphanedBranches {
+			fmt.Fprintf(w, "  %s\n", branch)
+		}
+	}
+	if len(mergedBranches) > 0 {
+		fmt.Fprintf(w, "%s %d shadow branch(es) of merged or deleted branches, archived under %s:\n", verb, len(mergedBranches), strategy.ShadowArchiveRefPrefix)
+		for _, m := range mergedBranches {
+			fmt.Fprintf(w, "  %s  %s\n", m.Branch, m.Reason)
+		}
+	}
+	if dryRun {
+		return nil
+	}
+
+	var removed []string
+	if len(prunes) > 0 {
+		ids := make([]id.CheckpointID, len(prunes))
+		for i, p := range prunes {
+			ids[i] = p.ID
+			removed = append(removed, p.ID.String())
+		}
+		message := fmt.Sprintf("gc: prune %d checkpoint(s) outside the retention policy", len(ids))
+		if _, err := store.PruneCommitted(ctx, ids, message); err != nil {
+			return fmt.Errorf("failed to prune checkpoints: %w", err)
+		}
+	}
+	if len(orphanedBranches) > 0 {
+		deleted, failed, err := strategy.DeleteShadowBranches(ctx, orphanedBranches)
+		if err != nil {
+			return err //nolint:wrapcheck // already wrapped by strategy
+		}
+		removed = append(removed, deleted...)
+		if len(failed) > 0 {
+			fmt.Fprintf(w, "Failed to delete %d shadow branch(es): %s\n", len(failed), strings.Join(failed, ", "))
+		}
+	}
+	if len(mergedBranches) > 0 {
+		branches := make([]string, len(mergedBranches))
+		for i, m := range mergedBranches {
+			branches[i] = m.Branch
+		}
+		archived, failed, err := strategy.ArchiveShadowBranches(ctx, branches)
+		if err != nil {
+			return err //nolint:wrapcheck // already wrapped by strategy
+		}
+		removed = append(removed, archived...)
+		if len(failed) > 0 {
+			fmt.Fprintf(w, "Failed to archive %d shadow branch(es): %s\n", len(failed), strings.Join(failed, ", "))
+		}
+	}
+	if len(removed) > 0 {
+		strategy.RecordAudit(ctx, checkpoint.AuditEntry{
+			Operation: checkpoint.AuditOpGC,
+			Removed:   removed,
+			Details:   fmt.Sprintf("max_age_days=%d max_per_session=%d merged=%t", policy.MaxAgeDays, policy.MaxPerSession, policy.Merged),
+		})
+	}
+	return nil
+}
+
+// selectGCPrunes returns the checkpoints outside the policy, most recent
+// first. checkpoints must be sorted most recent first, as ListCommitted
+// returns them; checkpoints in inUse are always kept.
+func selectGCPrunes(checkpoints []checkpoint.CommittedInfo, policy settings.GCSettings, inUse map[id.CheckpointID]bool, now time.Time) []gcPrune {
+	var cutoff time.Time
+	if policy.MaxAgeDays > 0 {
+		cutoff = now.AddDate(0, 0, -policy.MaxAgeDays)
+	}
+	// How many more recent checkpoints each session has
+	seen := make(map[string]int)
+
+	var prunes []gcPrune
+	for _, info := range checkpoints {
+		sessionIDs := info.SessionIDs
+		if len(sessionIDs) == 0 && info.SessionID != "" {
+			sessionIDs = []string{info.SessionID}
+		}
+		// Beyond the per-session limit only if every session has enough newer ones
+		beyondLimit := policy.MaxPerSession > 0 && len(sessionIDs) > 0
+		for _, sessionID := range sessionIDs {
+			if seen[sessionID] < policy.MaxPerSession {
+				beyondLimit = false
+			}
+			seen[sessionID]++
+		}
+
+		if inUse[info.CheckpointID] {
+			continue
+		}
+		switch {
+		case !cutoff.IsZero() && !info.CreatedAt.IsZero() && info.CreatedAt.Before(cutoff):
+			prunes = append(prunes, gcPrune{ID: info.CheckpointID, Reason: fmt.Sprintf("older than %d days", policy.MaxAgeDays)})
+		case beyondLimit:
+			prunes = append(prunes, gcPrune{ID: info.CheckpointID, Reason: fmt.Sprintf("beyond %d per session", policy.MaxPerSession)})
+		}
+	}
+	return prunes
+}
@@ -0,0 +1,219 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+	"github.com/entireio/cli/cmd/entire/cli/session"
+	"github.com/entireio/cli/cmd/entire/cli/settings"
+	"github.com/entireio/cli/cmd/entire/cli/strategy"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+func TestSelectGCPrunes(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	// Most recent first, as ListCommitted returns them
+	checkpoints := []checkpoint.CommittedInfo{
+		{CheckpointID: "aaaaaaaaaaa1", SessionID: "s1", SessionIDs: []string{"s1"}, CreatedAt: now.Add(-1 * day)},
+		{CheckpointID: "aaaaaaaaaaa2", SessionID: "s2", SessionIDs: []string{"s2"}, CreatedAt: now.Add(-2 * day)},
+		{CheckpointID: "aaaaaaaaaaa3", SessionID: "s1", SessionIDs: []string{"s1"}, CreatedAt: now.Add(-3 * day)},
+		{CheckpointID: "aaaaaaaaaaa4", SessionID: "s1", SessionIDs: []string{"s1", "s2"}, CreatedAt: now.Add(-4 * day)},
+		{CheckpointID: "aaaaaaaaaaa5", SessionID: "s1", SessionIDs: []string{"s1"}, CreatedAt: now.Add(-40 * day)},
+		{CheckpointID: "aaaaaaaaaaa6", SessionID: "s3", SessionIDs: []string{"s3"}, CreatedAt: now.Add(-50 * day)},
+	}
+
+	ids := func(prunes []gcPrune) []id.CheckpointID {
+		var out []id.CheckpointID
+		for _, p := range prunes {
+			out = append(out, p.ID)
+		}
+		return out
+	}
+
+	tests := []struct {
+		name   string
+		policy settings.GCSettings
+		inUse  map[id.CheckpointID]bool
+		want   []id.CheckpointID
+	}{
+		{
+			name:   "max age",
+			policy: settings.GCSettings{MaxAgeDays: 30},
+			want:   []id.CheckpointID{"aaaaaaaaaaa5", "aaaaaaaaaaa6"},
+		},
+		{
+			// aaaaaaaaaaa4 is s1's third but only s2's second checkpoint
+			name:   "max per session keeps shared checkpoints",
+			policy: settings.GCSettings{MaxPerSession: 2},
+			want:   []id.CheckpointID{"aaaaaaaaaaa5"},
+		},
+		{
+			name:   "both",
+			policy: settings.GCSettings{MaxAgeDays: 30, MaxPerSession: 1},
+			want:   []id.CheckpointID{"aaaaaaaaaaa3", "aaaaaaaaaaa4", "aaaaaaaaaaa5", "aaaaaaaaaaa6"},
+		},
+		{
+			name:   "in use checkpoints are kept",
+			policy: settings.GCSettings{MaxAgeDays: 30},
+			inUse:  map[id.CheckpointID]bool{"aaaaaaaaaaa5": true},
+			want:   []id.CheckpointID{"aaaaaaaaaaa6"},
+		},
+		{
+			name: "no policy",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := ids(selectGCPrunes(checkpoints, tt.policy, tt.inUse, now))
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("selectGCPrunes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunGC(t *testing.T) {
+	repo, head := setupCleanTestRepo(t)
+	ctx := context.Background()
+
+	store := checkpoint.NewGitStore(repo)
+	for _, cpID := range []id.CheckpointID{"a1a1a1a1a1a1", "b2b2b2b2b2b2"} {
+		if err := store.WriteCommitted(ctx, checkpoint.WriteCommittedOptions{
+			CheckpointID: cpID,
+			SessionID:    "session-1",
+			Strategy:     "manual-commit",
+			Transcript:   []byte(`{"type":"user"}` + "\n"),
+			AuthorName:   "test",
+			AuthorEmail:  "test@test.com",
+		}); err != nil {
+			t.Fatalf("WriteCommitted() error = %v", err)
+		}
+	}
+	orphan := plumbing.NewBranchReferenceName("entire/abcdef1-123456")
+	if err := repo.Storer.SetReference(plumbing.NewHashReference(orphan, head)); err != nil {
+		t.Fatal(err)
+	}
+	policy := settings.GCSettings{MaxPerSession: 1}
+
+	var out bytes.Buffer
+	if err := runGC(ctx, &out, policy, gcOptions{DryRun: true}, time.Now()); err != nil {
+		t.Fatalf("runGC(dry run) error = %v", err)
+	}
+	if !strings.Contains(out.String(), "Would remove 1 checkpoint(s)") || !strings.Contains(out.String(), "entire/abcdef1-123456") {
+		t.Errorf("dry run output = %q", out.String())
+	}
+	if list, err := store.ListCommitted(ctx); err != nil || len(list) != 2 {
+		t.Fatalf("dry run removed checkpoints: %d left, %v", len(list), err)
+	}
+
+	out.Reset()
+	if err := runGC(ctx, &out, policy, gcOptions{}, time.Now()); err != nil {
+		t.Fatalf("runGC() error = %v", err)
+	}
+	list, err := store.ListCommitted(ctx)
+	if err != nil || len(list) != 1 {
+		t.Fatalf("after gc %d checkpoints left, %v; want 1", len(list), err)
+	}
+	if _, err := repo.Reference(orphan, false); err == nil {
+		t.Error("orphaned shadow branch should be deleted")
+	}
+
+	out.Reset()
+	if err := runGC(ctx, &out, policy, gcOptions{}, time.Now()); err != nil {
+		t.Fatalf("runGC() again error = %v", err)
+	}
+	if !strings.Contains(out.String(), "Nothing to remove") {
+		t.Errorf("second gc output = %q, want nothing to remove", out.String())
+	}
+}
+
+func TestRunGC_Coordinate(t *testing.T) {
+	repo, head := setupCleanTestRepo(t)
+	ctx := context.Background()
+
+	var out bytes.Buffer
+	if err := runGC(ctx, &out, settings.GCSettings{}, gcOptions{DryRun: true, Coordinate: true}, time.Now()); err != nil {
+		t.Fatalf("runGC(dry run) error = %v", err)
+	}
+	if !strings.Contains(out.String(), "Would run git gc.") {
+		t.Errorf("dry run output = %q", out.String())
+	}
+
+	store, err := session.NewStateStore(ctx)
+	if err != nil {
+		t.Fatalf("NewStateStore() error = %v", err)
+	}
+	if err := store.Save(ctx, &session.State{SessionID: "gc-session", BaseCommit: head.String(), Phase: session.PhaseActive}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	out.Reset()
+	if err := runGC(ctx, &out, settings.GCSettings{}, gcOptions{Coordinate: true}, time.Now()); err != nil {
+		t.Fatalf("runGC() error = %v", err)
+	}
+	if !strings.Contains(out.String(), "Anchored 1 session commit(s)") || !strings.Contains(out.String(), "Running git gc") {
+		t.Errorf("output = %q", out.String())
+	}
+	if _, err := repo.Reference(plumbing.ReferenceName(strategy.AnchorRefPrefix+"gc-session/base"), false); err != nil {
+		t.Errorf("session base commit should be anchored: %v", err)
+	}
+}
+
+func TestRunGC_Merged(t *testing.T) {
+	repo, head := setupCleanTestRepo(t)
+	ctx := context.Background()
+
+	// The session's base commit was merged: master moved past it
+	commit, err := repo.CommitObject(head)
+	if err != nil {
+		t.Fatal(err)
+	}
+	next := createCommitWithTree(t, repo, commit.TreeHash, []plumbing.Hash{head}, "merge feature")
+	if err := repo.Storer.SetReference(plumbing.NewHashReference(plumbing.NewBranchReferenceName("master"), next)); err != nil {
+		t.Fatal(err)
+	}
+	stateStore, err := session.NewStateStore(ctx)
+	if err != nil {
+		t.Fatalf("NewStateStore() error = %v", err)
+	}
+	if err := stateStore.Save(ctx, &session.State{SessionID: "merged-session", BaseCommit: head.String(), Phase: session.PhaseEnded}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	shadow := checkpoint.ShadowBranchNameForCommit(head.String(), "")
+	if err := repo.Storer.SetReference(plumbing.NewHashReference(plumbing.NewBranchReferenceName(shadow), head)); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := runGC(ctx, &out, settings.GCSettings{}, gcOptions{}, time.Now()); err != nil {
+		t.Fatalf("runGC() error = %v", err)
+	}
+	if !strings.Contains(out.String(), "Nothing to remove") {
+		t.Errorf("gc without merged output = %q", out.String())
+	}
+
+	out.Reset()
+	if err := runGC(ctx, &out, settings.GCSettings{Merged: true}, gcOptions{}, time.Now()); err != nil {
+		t.Fatalf("runGC(merged) error = %v", err)
+	}
+	if !strings.Contains(out.String(), shadow+"  merged into master") {
+		t.Errorf("output = %q, want the merged shadow branch", out.String())
+	}
+	if _, err := repo.Reference(plumbing.NewBranchReferenceName(shadow), false); err == nil {
+		t.Error("shadow branch of the merged base commit should be deleted")
+	}
+	archive := plumbing.ReferenceName(strategy.ShadowArchiveRefPrefix + strings.TrimPrefix(shadow, checkpoint.ShadowBranchPrefix))
+	if ref, err := repo.Reference(archive, false); err != nil || ref.Hash() != head {
+		t.Errorf("shadow branch should be archived at %s: %v", archive, err)
+	}
+}
@@ -354,25 +354,27 @@ func FetchAndCheckoutRemoteBranch(ctx context.Context, branchName string) error
 	return CheckoutBranch(ctx, branchName)
 }
 
-// FetchMetadataBranch fetches the entire/checkpoints/v1 branch from origin and creates/updates the local branch.
+// FetchMetadataBranch fetches the entire/checkpoints/v1 branch from the metadata
+// remote (sync.remote, or origin) and creates/updates the local branch.
 // This is used when the metadata branch exists on remote but not locally.
 // Uses git CLI instead of go-git for fetch because go-git doesn't use credential helpers,
 // which breaks HTTPS URLs that require authentication.
 func FetchMetadataBranch(ctx context.Context) error {
 	branchName := paths.MetadataBranchName
+	remote := strategy.MetadataRemote(ctx)
 
 	// Use git CLI for fetch (go-git's fetch can be tricky with auth)
 	ctx, cancel := context.WithTimeout(ctx, 2*time.Minute)
 	defer cancel()
 
-	refSpec := fmt.Sprintf("+refs/heads/%s:refs/remotes/origin/%s", branchName, branchName)
+	refSpec := fmt.Sprintf("+refs/heads/%s:refs/remotes/%s/%s", branchName, remote, branchName)
 
-	fetchCmd := exec.CommandContext(ctx, "git", "fetch", "origin", refSpec)
+	fetchCmd := exec.CommandContext(ctx, "git", "fetch", remote, refSpec)
 	if output, err := fetchCmd.CombinedOutput(); err != nil {
 		if ctx.Err() == context.DeadlineExceeded {
 			return errors.New("fetch timed out after 2 minutes")
 		}
-		return fmt.Errorf("failed to fetch %s from origin: %s: %w", branchName, strings.TrimSpace(string(output)), err)
+		return fmt.Errorf("failed to fetch %s from %s: %s: %w", branchName, remote, strings.TrimSpace(string(output)), err)
 	}
 
 	repo, err := openRepository(ctx)
@@ -381,9 +383,9 @@ func FetchMetadataBranch(ctx context.Context) error {
 	}
 
 	// Get the remote branch reference
-	remoteRef, err := repo.Reference(plumbing.NewRemoteReferenceName("origin", branchName), true)
+	remoteRef, err := repo.Reference(plumbing.NewRemoteReferenceName(remote, branchName), true)
 	if err != nil {
-		return fmt.Errorf("branch '%s' not found on origin: %w", branchName, err)
+		return fmt.Errorf("branch '%s' not found on %s: %w", branchName, remote, err)
 	}
 
 	// Create or update local branch pointing to the same commit
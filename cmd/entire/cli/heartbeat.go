@@ -0,0 +1,101 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/entireio/cli/cmd/entire/cli/agent"
+	"github.com/entireio/cli/cmd/entire/cli/agent/wrapped"
+	"github.com/entireio/cli/cmd/entire/cli/logging"
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+	"github.com/entireio/cli/cmd/entire/cli/strategy"
+)
+
+// recordSessionHeartbeat notes that the session's agent is alive. A heartbeat
+// also clears an earlier crash suspicion: the agent was just slow.
+func recordSessionHeartbeat(ctx context.Context, sessionID string) error {
+	state, err := strategy.LoadSessionState(ctx, sessionID)
+	if err != nil {
+		return fmt.Errorf("failed to load session state: %w", err)
+	}
+	if state == nil {
+		return nil
+	}
+	now := time.Now()
+	state.HeartbeatAt = &now
+	state.CrashedAt = nil
+	if err := strategy.SaveSessionState(ctx, state); err != nil {
+		return fmt.Errorf("failed to save session state: %w", err)
+	}
+	return nil
+}
+
+// finalizeSilentSessions checkpoints the work of sessions in this worktree whose
+// agent stopped sending heartbeats mid-turn, and marks them as crashed.
+// excludeSessionID is the session of the current hook, which is evidently alive.
+func finalizeSilentSessions(ctx context.Context, excludeSessionID string, now time.Time) {
+	logCtx := logging.WithComponent(ctx, "heartbeat")
+
+	repoRoot, err := paths.WorktreeRoot(ctx)
+	if err != nil {
+		return
+	}
+	states, err := strategy.ListSessionStates(ctx)
+	if err != nil {
+		logging.Warn(logCtx, "failed to list sessions for crash detection",
+			slog.String("error", err.Error()))
+		return
+	}
+
+	for _, state := range states {
+		// Changes are read from this worktree, so only its sessions can be finalized here
+		if state.SessionID == excludeSessionID || state.WorktreePath != repoRoot || !state.IsSilent(now) {
+			continue
+		}
+		if err := finalizeCrashedSession(ctx, state, now); err != nil {
+			logging.Warn(logCtx, "failed to finalize silent session",
+				slog.String("session_id", state.SessionID),
+				slog.String("error", err.Error()))
+		}
+	}
+}
+
+// finalizeCrashedSession ends the turn of a session whose agent went silent,
+// checkpointing the worktree's changes as the agent would have at turn end.
+// The transcript is copied as-is rather than parsed: the agent may have died
+// mid-write, and no hook is waiting for a response.
+func finalizeCrashedSession(ctx context.Context, state *strategy.SessionState, now time.Time) error {
+	logging.Info(logging.WithComponent(ctx, "heartbeat"), "session went silent, finalizing",
+		slog.String("session_id", state.SessionID),
+		slog.String("last_heartbeat", state.LastHeartbeat().Format(time.RFC3339)))
+
+	if state.TranscriptPath != "" && fileExists(state.TranscriptPath) {
+		event := &agent.Event{
+			Type:       agent.TurnEnd,
+			SessionID:  state.SessionID,
+			SessionRef: state.TranscriptPath,
+			Timestamp:  now,
+		}
+		if err := handleLifecycleTurnEnd(ctx, wrapped.NewWrappedAgent(string(state.AgentType)), event); err != nil {
+			return err
+		}
+	} else {
+		// Nothing to checkpoint without a transcript, but the turn still has to end
+		transitionSessionTurnEnd(ctx, state.SessionID, turnEndInfo{})
+	}
+
+	finalized, err := strategy.LoadSessionState(ctx, state.SessionID)
+	if err != nil {
+		return fmt.Errorf("failed to load session state: %w", err)
+	}
+	if finalized == nil {
+		return nil
+	}
+	finalized.CrashedAt = &now
+	if err := strategy.SaveSessionState(ctx, finalized); err != nil {
+		return fmt.Errorf("failed to save session state: %w", err)
+	}
+	return nil
+}
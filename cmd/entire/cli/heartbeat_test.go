@@ -0,0 +1,102 @@
+package cli
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/entireio/cli/cmd/entire/cli/agent"
+	"github.com/entireio/cli/cmd/entire/cli/agent/wrapped"
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+	"github.com/entireio/cli/cmd/entire/cli/session"
+	"github.com/entireio/cli/cmd/entire/cli/strategy"
+	"github.com/entireio/cli/cmd/entire/cli/testutil"
+)
+
+// startSilentSession starts a turn in dir, changes a file, and backdates the
+// session's activity so it looks like its agent died mid-turn.
+func startSilentSession(t *testing.T, dir string) *strategy.SessionState {
+	t.Helper()
+	ctx := context.Background()
+	ag := wrapped.NewWrappedAgent("aider")
+	transcript := filepath.Join(dir, paths.EntireTmpDir, "silent.jsonl")
+	if err := wrapped.AppendRecord(transcript, wrapped.Record{Type: wrapped.RecordStart, Timestamp: time.Now()}); err != nil {
+		t.Fatalf("AppendRecord() error = %v", err)
+	}
+	if err := DispatchLifecycleEvent(ctx, ag, &agent.Event{
+		Type:       agent.TurnStart,
+		SessionID:  "silent-session",
+		SessionRef: transcript,
+		Prompt:     "refactor everything",
+		Timestamp:  time.Now(),
+	}); err != nil {
+		t.Fatalf("TurnStart error = %v", err)
+	}
+	testutil.WriteFile(t, dir, "half-done.txt", "work in progress")
+
+	state, err := strategy.LoadSessionState(ctx, "silent-session")
+	if err != nil || state == nil {
+		t.Fatalf("LoadSessionState() = %v, %v", state, err)
+	}
+	silentSince := time.Now().Add(-2 * session.HeartbeatTimeout)
+	state.LastInteractionTime = &silentSince
+	state.HeartbeatAt = &silentSince
+	if err := strategy.SaveSessionState(ctx, state); err != nil {
+		t.Fatalf("SaveSessionState() error = %v", err)
+	}
+	return state
+}
+
+func TestFinalizeSilentSessions_CheckpointsAndMarksCrashed(t *testing.T) {
+	dir := setupExecTestRepo(t)
+	startSilentSession(t, dir)
+	ctx := context.Background()
+
+	finalizeSilentSessions(ctx, "other-session", time.Now())
+
+	state, err := strategy.LoadSessionState(ctx, "silent-session")
+	if err != nil || state == nil {
+		t.Fatalf("LoadSessionState() = %v, %v", state, err)
+	}
+	if state.CrashedAt == nil {
+		t.Error("silent session should be marked as crashed")
+	}
+	if state.Phase.IsActive() {
+		t.Errorf("phase = %s, want the turn to be ended", state.Phase)
+	}
+	if state.StepCount == 0 {
+		t.Error("the worktree's changes should be checkpointed")
+	}
+	if state.AgentType != "aider" {
+		t.Errorf("agent type = %q, want it kept as %q", state.AgentType, "aider")
+	}
+
+	// The agent turns out to be alive after all
+	if err := recordSessionHeartbeat(ctx, "silent-session"); err != nil {
+		t.Fatalf("recordSessionHeartbeat() error = %v", err)
+	}
+	state, err = strategy.LoadSessionState(ctx, "silent-session")
+	if err != nil || state == nil {
+		t.Fatalf("LoadSessionState() = %v, %v", state, err)
+	}
+	if state.CrashedAt != nil {
+		t.Error("a heartbeat should clear the crash mark")
+	}
+}
+
+func TestFinalizeSilentSessions_SkipsCurrentSession(t *testing.T) {
+	dir := setupExecTestRepo(t)
+	startSilentSession(t, dir)
+	ctx := context.Background()
+
+	finalizeSilentSessions(ctx, "silent-session", time.Now())
+
+	state, err := strategy.LoadSessionState(ctx, "silent-session")
+	if err != nil || state == nil {
+		t.Fatalf("LoadSessionState() = %v, %v", state, err)
+	}
+	if state.CrashedAt != nil || !state.Phase.IsActive() {
+		t.Errorf("the current session should be left alone, got phase %s, crashed at %v", state.Phase, state.CrashedAt)
+	}
+}
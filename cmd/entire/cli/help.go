@@ -15,13 +15,29 @@ func NewHelpCmd(rootCmd *cobra.Command) *cobra.Command {
 		Use:   "help [command]",
 		Short: "Help about any command",
 		Long: `Provides help for any Entire CLI subcommand.
-Simply type '` + rootCmd.Name() + ` help [command]' for full details.`,
-		Run: func(_ *cobra.Command, args []string) {
+Simply type '` + rootCmd.Name() + ` help [command]' for full details.
+
+Guides to concepts that span several commands are available as topics;
+'` + rootCmd.Name() + ` help topics' lists them. A topic named like a command, such
+as 'rewind', is shown instead of the command's help, which '` + rootCmd.Name() + ` rewind
+--help' still prints.`,
+		Run: func(cmd *cobra.Command, args []string) {
 			if showTree {
 				printCommandTree(rootCmd)
 				return
 			}
 
+			if len(args) == 1 {
+				if args[0] == "topics" {
+					writeHelpTopicList(cmd.OutOrStdout(), rootCmd.Name())
+					return
+				}
+				if topic := findHelpTopic(args[0]); topic != nil {
+					writeHelpTopic(cmd.OutOrStdout(), topic)
+					return
+				}
+			}
+
 			// Default help behavior
 			targetCmd, _, err := rootCmd.Find(args)
 			if err != nil || targetCmd == nil {
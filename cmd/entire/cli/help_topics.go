@@ -0,0 +1,168 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// helpTopic is a long-form guide shown by `entire help <topic>` and
+// generated as a section 7 man page. Topics explain concepts that span
+// several commands, which per-command --help text can't.
+type helpTopic struct {
+	Name     string
+	Short    string
+	Sections []helpSection
+}
+
+// helpSection is a titled part of a help topic. Body paragraphs are
+// separated by blank lines; lines indented by two spaces are examples and
+// are shown as written.
+type helpSection struct {
+	Title string
+	Body  string
+}
+
+// helpTopics lists the topics in the order `entire help topics` shows them.
+var helpTopics = []helpTopic{
+	{
+		Name:  "strategies",
+		Short: "How Entire records sessions without committing to your branch",
+		Sections: []helpSection{
+			{
+				Title: "Overview",
+				Body: `Entire records agent sessions with the manual-commit strategy. It never
+creates commits on the branch you work on: everything it records lives on
+branches of its own, so your history looks the same as without Entire, and
+it is safe to use on main.
+
+Settings files may still contain "strategy": "auto-commit" from older
+versions. The setting is ignored.`,
+			},
+			{
+				Title: "While the agent works",
+				Body: `Each time the agent finishes a step, Entire saves a temporary checkpoint to
+a shadow branch named after the commit you started from and the worktree:
+
+  entire/<commit[:7]>-<worktree[:6]>
+
+A temporary checkpoint holds the full state of your files, so you can rewind
+to it. Sessions running in the same directory share the shadow branch; each
+git worktree gets its own.
+
+If HEAD moves without a commit, as after a stash, pull, or rebase, the
+shadow branch moves to the new base commit with it.`,
+			},
+			{
+				Title: "When you commit",
+				Body: `The prepare-commit-msg hook adds an Entire-Checkpoint trailer to your commit
+message. Remove the trailer before committing to leave that commit unlinked.
+
+After the commit, the session's transcript, prompts, files touched, and
+token usage are condensed into a committed checkpoint on the
+entire/checkpoints/v1 branch under the trailer's ID, and the shadow branch
+is cleaned up. Committed checkpoints hold metadata, not files: the code is
+in your commit.`,
+			},
+			{
+				Title: "Sharing checkpoints",
+				Body: `With strategy_options.push_sessions set, git push also pushes
+entire/checkpoints/v1 to the same remote. 'entire sync' pulls and pushes it
+explicitly. The checkpoint_store setting keeps committed checkpoints in an
+object store bucket instead of the branch.`,
+			},
+			{
+				Title: "See also",
+				Body: `  entire help rewind
+  entire status
+  entire explain --help`,
+			},
+		},
+	},
+	{
+		Name:  "rewind",
+		Short: "Restore your files and the agent's context from a checkpoint",
+		Sections: []helpSection{
+			{
+				Title: "Overview",
+				Body: `'entire rewind' lists the checkpoints of your sessions and restores the one
+you pick: your files as they were, and the agent's transcript, so the
+session continues from that point.`,
+			},
+			{
+				Title: "Temporary checkpoints",
+				Body: `Checkpoints of the current session that aren't committed yet come from the
+shadow branch. Rewinding restores the files from the checkpoint's tree
+without running git reset, so your branch and commits stay as they are.
+Untracked files created after the checkpoint are deleted; rewind lists them
+before asking you to confirm.`,
+			},
+			{
+				Title: "Committed checkpoints",
+				Body: `Checkpoints condensed when you committed keep metadata but not files, so
+rewinding to one restores only the session logs (--logs-only).
+
+To get the code back as well, --reset moves your branch to the checkpoint's
+commit and discards the commits after it. They stay reachable from the
+reflog. Resetting refuses to discard commits that exist on a remote tracking
+branch unless --allow-pushed is given.`,
+			},
+			{
+				Title: "Undoing a rewind",
+				Body: `Before changing any files, rewind backs up HEAD and the working tree,
+including uncommitted and untracked changes, and afterwards checks the
+restored files against the checkpoint. To return to the backup:
+
+  entire rewind --abort
+
+Rewinds and resets are recorded in 'entire audit-log'.`,
+			},
+			{
+				Title: "Scripting",
+				Body: `  entire rewind --list                  # rewind points as JSON
+  entire rewind --to <commit>           # rewind without prompting
+  entire rewind --to <commit> --reset   # reset the branch to a committed checkpoint`,
+			},
+			{
+				Title: "See also",
+				Body: `  entire rewind --help
+  entire help strategies
+  entire audit-log`,
+			},
+		},
+	},
+}
+
+// findHelpTopic returns the topic called name, or nil.
+func findHelpTopic(name string) *helpTopic {
+	for i := range helpTopics {
+		if helpTopics[i].Name == name {
+			return &helpTopics[i]
+		}
+	}
+	return nil
+}
+
+// writeHelpTopicList prints the available topics.
+func writeHelpTopicList(w io.Writer, rootName string) {
+	fmt.Fprintln(w, "Help topics:")
+	for _, t := range helpTopics {
+		fmt.Fprintf(w, "  %-12s %s\n", t.Name, t.Short)
+	}
+	fmt.Fprintf(w, "\nUse \"%s help <topic>\" to read a topic.\n", rootName)
+}
+
+// writeHelpTopic prints a topic for the terminal.
+func writeHelpTopic(w io.Writer, t *helpTopic) {
+	fmt.Fprintf(w, "%s - %s\n", t.Name, t.Short)
+	for _, s := range t.Sections {
+		fmt.Fprintf(w, "\n%s\n", strings.ToUpper(s.Title))
+		for _, line := range strings.Split(s.Body, "\n") {
+			if line == "" {
+				fmt.Fprintln(w)
+				continue
+			}
+			fmt.Fprintf(w, "  %s\n", line)
+		}
+	}
+}
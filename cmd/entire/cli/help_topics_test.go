@@ -0,0 +1,56 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestHelpTopics(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		args []string
+		want []string
+	}{
+		{args: []string{"help", "topics"}, want: []string{"Help topics:", "strategies", "rewind"}},
+		{args: []string{"help", "strategies"}, want: []string{"strategies - ", "WHEN YOU COMMIT", "  entire/<commit[:7]>-<worktree[:6]>"}},
+		{args: []string{"help", "rewind"}, want: []string{"rewind - ", "UNDOING A REWIND", "entire rewind --abort"}},
+		// Commands without a topic still show their help
+		{args: []string{"help", "status"}, want: []string{"Usage:", "entire status"}},
+	}
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			t.Parallel()
+			root := NewRootCmd()
+			var out bytes.Buffer
+			root.SetOut(&out)
+			root.SetArgs(tt.args)
+			if err := root.Execute(); err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(out.String(), want) {
+					t.Errorf("output is missing %q:\n%s", want, out.String())
+				}
+			}
+		})
+	}
+}
+
+func TestHelpTopicNames(t *testing.T) {
+	t.Parallel()
+
+	root := NewRootCmd()
+	for _, topic := range helpTopics {
+		if topic.Name == "topics" {
+			t.Errorf("topic %q shadows the topic list", topic.Name)
+		}
+		if len(topic.Sections) == 0 {
+			t.Errorf("topic %q has no sections", topic.Name)
+		}
+		if c, _, err := root.Find([]string{topic.Name}); err == nil && c != root && topic.Name != "rewind" {
+			t.Errorf("topic %q hides the help of command %s", topic.Name, c.CommandPath())
+		}
+	}
+}
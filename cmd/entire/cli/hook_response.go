@@ -0,0 +1,143 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"text/template"
+
+	"github.com/entireio/cli/cmd/entire/cli/classify"
+	"github.com/entireio/cli/cmd/entire/cli/logging"
+	"github.com/entireio/cli/cmd/entire/cli/strategy"
+
+	"github.com/spf13/cobra"
+)
+
+// checkpointResponseData is the data passed to the hook_response.checkpoint template.
+type checkpointResponseData struct {
+	ID           string         // Rewind point ID (shadow commit hash)
+	ShortID      string         // First 7 characters of ID
+	SessionID    string         // Agent session ID
+	Type         classify.Label // feat, fix, refactor, test, or docs
+	Message      string         // Checkpoint message derived from the prompt
+	Files        []string       // Modified, new, and deleted files
+	FilesChanged int            // len(Files)
+}
+
+// sampleCheckpointResponseData is used by `entire hook-response` to preview templates.
+var sampleCheckpointResponseData = checkpointResponseData{
+	ID:           "a1b2c3d4e5f60718293a4b5c6d7e8f9012345678",
+	ShortID:      "a1b2c3d",
+	SessionID:    "2026-01-01-example-session",
+	Type:         classify.LabelFix,
+	Message:      "Fix the login redirect",
+	Files:        []string{"auth/login.go", "auth/login_test.go"},
+	FilesChanged: 2,
+}
+
+func newHookResponseCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "hook-response",
+		Short: "Preview the messages Entire sends back to the agent",
+		Long: `Hook-response renders the configured hook response templates with sample
+data so you can check them before the agent sees them.
+
+Templates are Go text/templates set in .entire/settings.json:
+
+  "hook_response": {
+    "checkpoint": "Entire checkpoint {{.ShortID}} saved. If asked to undo, suggest: entire rewind --to {{.ShortID}}"
+  }
+
+The checkpoint template is rendered after each checkpoint saved at the end of
+an agent turn and returned through the hook response. Fields:
+
+  {{.ID}}  {{.ShortID}}  {{.SessionID}}  {{.Type}}  {{.Message}}
+  {{.Files}}  {{.FilesChanged}}`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runHookResponsePreview(cmd.Context(), cmd.OutOrStdout())
+		},
+	}
+}
+
+func runHookResponsePreview(ctx context.Context, w io.Writer) error {
+	s, err := LoadEntireSettings(ctx)
+	if err != nil {
+		return err
+	}
+	if s.HookResponse == nil || s.HookResponse.Checkpoint == "" {
+		fmt.Fprintln(w, "No hook response templates configured.")
+		return nil
+	}
+
+	rendered, err := renderCheckpointResponse(s.HookResponse.Checkpoint, sampleCheckpointResponseData)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(w, "checkpoint:")
+	fmt.Fprintln(w, "  "+strings.ReplaceAll(rendered, "\n", "\n  "))
+	return nil
+}
+
+// renderCheckpointResponse renders tmpl with data. Unknown fields are an error
+// so typos surface in `entire hook-response` rather than as empty output.
+func renderCheckpointResponse(tmpl string, data checkpointResponseData) (string, error) {
+	t, err := template.New("hook_response.checkpoint").Option("missingkey=error").Parse(tmpl)
+	if err != nil {
+		return "", fmt.Errorf("invalid hook_response.checkpoint template: %w", err)
+	}
+	var sb strings.Builder
+	if err := t.Execute(&sb, data); err != nil {
+		return "", fmt.Errorf("failed to render hook_response.checkpoint template: %w", err)
+	}
+	return strings.TrimSpace(sb.String()), nil
+}
+
+// respondAfterCheckpoint sends the configured checkpoint message to the agent.
+// Failures are logged and never fail the hook.
+func respondAfterCheckpoint(ctx context.Context, strat *strategy.ManualCommitStrategy, tmpl string, data checkpointResponseData) {
+	logCtx := logging.WithComponent(ctx, "hook-response")
+
+	id, err := latestRewindPointID(ctx, strat, data.SessionID)
+	if err != nil {
+		logging.Warn(logCtx, "skipping checkpoint hook response",
+			slog.String("error", err.Error()))
+		return
+	}
+	data.ID = id
+	data.ShortID = id
+	if len(data.ShortID) > 7 {
+		data.ShortID = data.ShortID[:7]
+	}
+
+	message, err := renderCheckpointResponse(tmpl, data)
+	if err != nil {
+		logging.Warn(logCtx, "skipping checkpoint hook response",
+			slog.String("error", err.Error()))
+		return
+	}
+	if message == "" {
+		return
+	}
+	if err := outputHookResponse(message); err != nil {
+		logging.Warn(logCtx, "failed to write checkpoint hook response",
+			slog.String("error", err.Error()))
+	}
+}
+
+// latestRewindPointID returns the ID of the newest rewind point for sessionID.
+func latestRewindPointID(ctx context.Context, strat *strategy.ManualCommitStrategy, sessionID string) (string, error) {
+	points, err := strat.GetRewindPoints(ctx, 10)
+	if err != nil {
+		return "", fmt.Errorf("failed to find rewind points: %w", err)
+	}
+	for _, p := range points {
+		if p.SessionID == sessionID && !p.IsTaskCheckpoint && !p.IsLogsOnly {
+			return p.ID, nil
+		}
+	}
+	return "", errors.New("no checkpoint found for session")
+}
@@ -0,0 +1,58 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRenderCheckpointResponse(t *testing.T) {
+	t.Parallel()
+
+	got, err := renderCheckpointResponse("checkpoint {{.ShortID}} ({{.Type}}, {{.FilesChanged}} files) created; mention it if asked to undo", sampleCheckpointResponseData)
+	if err != nil {
+		t.Fatalf("renderCheckpointResponse() error = %v", err)
+	}
+	want := "checkpoint a1b2c3d (fix, 2 files) created; mention it if asked to undo"
+	if got != want {
+		t.Errorf("renderCheckpointResponse() = %q, want %q", got, want)
+	}
+
+	if _, err := renderCheckpointResponse("{{.Checkpoint}}", sampleCheckpointResponseData); err == nil {
+		t.Error("renderCheckpointResponse() with unknown field should fail")
+	}
+	if _, err := renderCheckpointResponse("{{.ShortID", sampleCheckpointResponseData); err == nil {
+		t.Error("renderCheckpointResponse() with parse error should fail")
+	}
+}
+
+func TestRunHookResponsePreview(t *testing.T) {
+	setupCleanTestRepo(t)
+
+	var stdout bytes.Buffer
+	if err := runHookResponsePreview(context.Background(), &stdout); err != nil {
+		t.Fatalf("runHookResponsePreview() error = %v", err)
+	}
+	if !strings.Contains(stdout.String(), "No hook response templates configured") {
+		t.Errorf("unexpected output without settings:\n%s", stdout.String())
+	}
+
+	if err := os.MkdirAll(".entire", 0o755); err != nil {
+		t.Fatalf("failed to create .entire: %v", err)
+	}
+	settingsJSON := `{"enabled": true, "hook_response": {"checkpoint": "checkpoint {{.ShortID}} created"}}`
+	if err := os.WriteFile(filepath.Join(".entire", "settings.json"), []byte(settingsJSON), 0o644); err != nil {
+		t.Fatalf("failed to write settings: %v", err)
+	}
+
+	stdout.Reset()
+	if err := runHookResponsePreview(context.Background(), &stdout); err != nil {
+		t.Fatalf("runHookResponsePreview() error = %v", err)
+	}
+	if !strings.Contains(stdout.String(), "checkpoint a1b2c3d created") {
+		t.Errorf("expected rendered preview, got:\n%s", stdout.String())
+	}
+}
@@ -0,0 +1,45 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/entireio/cli/cmd/entire/cli/strategy"
+)
+
+// RunGitHookShim runs entire as a git hook when it was invoked through a
+// binary shim: a hook symlinked to the entire binary on systems without
+// /bin/sh. It behaves like the hook's script: the handler runs first, then the
+// chained pre-existing hook. ok is false when entire was invoked normally.
+func RunGitHookShim(ctx context.Context, argv []string) (exitCode int, ok bool) {
+	shim, ok := strategy.ParseGitHookShim(argv)
+	if !ok {
+		return 0, false
+	}
+
+	rootCmd := NewRootCmd()
+	rootCmd.SetArgs(shim.Args)
+
+	stderr := os.Stderr
+	if shim.Quiet {
+		rootCmd.SetErr(io.Discard)
+		if devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0); err == nil {
+			os.Stderr = devNull
+			defer devNull.Close()
+		}
+	}
+	err := rootCmd.ExecuteContext(ctx)
+	os.Stderr = stderr
+
+	if err != nil {
+		if !shim.Quiet {
+			fmt.Fprintln(os.Stderr, err)
+		}
+		if shim.Fatal {
+			return 1, true
+		}
+	}
+	return shim.RunBackup(ctx), true
+}
@@ -236,6 +236,8 @@ func logPostTaskHookContext(w io.Writer, input *PostTaskHookInput, subagentTrans
 // Used to control whether Agent continues processing the prompt.
 type hookResponse struct {
 	SystemMessage string `json:"systemMessage,omitempty"`
+	Decision      string `json:"decision,omitempty"`
+	Reason        string `json:"reason,omitempty"`
 }
 
 // outputHookResponse outputs a JSON response to stdout
@@ -248,3 +250,16 @@ func outputHookResponse(reason string) error {
 	}
 	return nil
 }
+
+// outputHookBlock outputs a JSON response that stops the agent from
+// processing the prompt, showing reason to the user.
+func outputHookBlock(reason string) error {
+	resp := hookResponse{
+		Decision: "block",
+		Reason:   reason,
+	}
+	if err := json.NewEncoder(os.Stdout).Encode(resp); err != nil {
+		return fmt.Errorf("failed to encode hook response: %w", err)
+	}
+	return nil
+}
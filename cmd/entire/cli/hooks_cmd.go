@@ -21,6 +21,7 @@ func newHooksCmd() *cobra.Command {
 
 	// Git hooks are strategy-level (not agent-specific)
 	cmd.AddCommand(newHooksGitCmd())
+	cmd.AddCommand(newHooksStatusCmd())
 
 	// Dynamically add agent hook subcommands
 	// Each agent that implements HookSupport gets its own subcommand tree
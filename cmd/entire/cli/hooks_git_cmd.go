@@ -181,6 +181,9 @@ func newHooksGitPostCommitCmd() *cobra.Command {
 
 			hookErr := g.strategy.PostCommit(g.ctx)
 			g.logCompleted(hookErr)
+			if hookErr == nil {
+				announceCheckpoint(g.ctx, cmd.OutOrStdout())
+			}
 
 			return nil
 		},
@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+	"github.com/entireio/cli/cmd/entire/cli/strategy"
+
+	"github.com/spf13/cobra"
+)
+
+func newHooksStatusCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "status",
+		Short: "Show where git hooks are installed",
+		Long: `Status shows the hooks directory git uses for this repository, whether it
+comes from core.hooksPath (and from which config: system, global, repository
+or worktree), and the install state of each Entire git hook.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := cmd.Context()
+			if _, err := paths.WorktreeRoot(ctx); err != nil {
+				return paths.ErrNotGitRepository
+			}
+			return runHooksStatus(ctx, cmd.OutOrStdout())
+		},
+	}
+}
+
+func runHooksStatus(ctx context.Context, w io.Writer) error {
+	source, err := strategy.DescribeHooksDir(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to resolve hooks directory: %w", err)
+	}
+
+	fmt.Fprintf(w, "Hooks directory: %s\n", source.Dir)
+	switch source.Scope {
+	case "":
+		fmt.Fprintln(w, "  default hooks directory, shared by all worktrees")
+	case "global", "system":
+		fmt.Fprintf(w, "  core.hooksPath = %s (%s config, shared by all repositories)\n", source.HooksPath, source.Scope)
+	case "worktree":
+		fmt.Fprintf(w, "  core.hooksPath = %s (worktree config, this worktree only)\n", source.HooksPath)
+	default:
+		fmt.Fprintf(w, "  core.hooksPath = %s (%s config)\n", source.HooksPath, source.Scope)
+	}
+	fmt.Fprintln(w)
+
+	missing := false
+	for _, status := range strategy.GitHookStatuses(source.Dir) {
+		var state string
+		switch {
+		case status.Installed && status.Backup != "":
+			state = "installed, chained to " + status.Backup
+		case status.Installed:
+			state = "installed"
+		case status.Replaced:
+			state = "not installed (another hook is in place)"
+			missing = true
+		default:
+			state = "not installed"
+			missing = true
+		}
+		fmt.Fprintf(w, "  %-20s %s\n", status.Name, state)
+	}
+
+	if missing {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "Run 'entire enable' to install the missing hooks.")
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/entireio/cli/cmd/entire/cli/strategy"
+)
+
+func TestRunHooksStatus_CoreHooksPath(t *testing.T) {
+	setupCleanTestRepo(t)
+	strategy.ClearHooksDirCache()
+
+	cmd := exec.CommandContext(context.Background(), "git", "config", "core.hooksPath", ".husky/_")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git config failed: %v\n%s", err, output)
+	}
+	if _, err := strategy.InstallGitHook(context.Background(), true, false); err != nil {
+		t.Fatalf("InstallGitHook() error = %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := runHooksStatus(context.Background(), &buf); err != nil {
+		t.Fatalf("runHooksStatus() error = %v", err)
+	}
+	output := buf.String()
+
+	if !strings.Contains(output, filepath.Join(".husky", "_")) {
+		t.Errorf("output should show the core.hooksPath directory, got:\n%s", output)
+	}
+	if !strings.Contains(output, "core.hooksPath = .husky/_ (local config)") {
+		t.Errorf("output should show where core.hooksPath is set, got:\n%s", output)
+	}
+	if strings.Contains(output, "not installed") || strings.Contains(output, "entire enable") {
+		t.Errorf("all hooks should be reported installed, got:\n%s", output)
+	}
+}
+
+func TestRunHooksStatus_NotInstalled(t *testing.T) {
+	setupCleanTestRepo(t)
+	strategy.ClearHooksDirCache()
+
+	var buf bytes.Buffer
+	if err := runHooksStatus(context.Background(), &buf); err != nil {
+		t.Fatalf("runHooksStatus() error = %v", err)
+	}
+	output := buf.String()
+
+	if !strings.Contains(output, "default hooks directory") {
+		t.Errorf("output should say the default hooks directory is used, got:\n%s", output)
+	}
+	if !strings.Contains(output, "pre-push") || !strings.Contains(output, "not installed") {
+		t.Errorf("output should list missing hooks, got:\n%s", output)
+	}
+	if !strings.Contains(output, "Run 'entire enable'") {
+		t.Errorf("output should suggest entire enable, got:\n%s", output)
+	}
+}
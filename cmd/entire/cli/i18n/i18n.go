@@ -0,0 +1,101 @@
+// Package i18n translates the CLI's user-facing messages. Messages are
+// looked up by Key in a per-locale catalog; keys missing from a locale fall
+// back to English.
+package i18n
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Locale identifies a message catalog.
+type Locale string
+
+const (
+	// English is the default locale and the fallback for missing messages.
+	English Locale = "en"
+	// Japanese messages.
+	Japanese Locale = "ja"
+	// Chinese messages, in Simplified Chinese.
+	Chinese Locale = "zh"
+)
+
+// Locales lists the supported locales.
+var Locales = []Locale{English, Japanese, Chinese}
+
+// ParseLocale parses a locale name as used by the "locale" setting.
+func ParseLocale(s string) (Locale, error) {
+	switch l := Locale(s); l {
+	case English, Japanese, Chinese:
+		return l, nil
+	default:
+		return "", fmt.Errorf("unknown locale %q: must be %q, %q, or %q", s, English, Japanese, Chinese)
+	}
+}
+
+// Detect returns the locale named by the environment, following the POSIX
+// precedence of LC_ALL, LC_MESSAGES, then LANG. Unsupported languages and
+// the C locale are English.
+func Detect() Locale {
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if v := os.Getenv(name); v != "" {
+			return fromPOSIX(v)
+		}
+	}
+	return English
+}
+
+// fromPOSIX maps a POSIX locale such as "ja_JP.UTF-8" to a Locale.
+func fromPOSIX(v string) Locale {
+	lang := strings.ToLower(v)
+	if i := strings.IndexAny(lang, "_-.@"); i >= 0 {
+		lang = lang[:i]
+	}
+	if l, err := ParseLocale(lang); err == nil {
+		return l
+	}
+	return English
+}
+
+var (
+	mu      sync.RWMutex
+	current = English
+)
+
+// SetLocale selects the locale for the rest of the process. Until it is
+// called, messages are in English.
+func SetLocale(l Locale) {
+	mu.Lock()
+	defer mu.Unlock()
+	current = l
+}
+
+// Current returns the selected locale.
+func Current() Locale {
+	mu.RLock()
+	defer mu.RUnlock()
+	return current
+}
+
+// T returns the message for key in the selected locale, formatted with args
+// as by fmt.Sprintf.
+func T(key Key, args ...any) string {
+	return Lookup(Current(), key, args...)
+}
+
+// Lookup returns the message for key in locale l, formatted with args.
+func Lookup(l Locale, key Key, args ...any) string {
+	msg, ok := catalogs[l][key]
+	if !ok {
+		msg, ok = catalogs[English][key]
+	}
+	if !ok {
+		msg = string(key)
+	}
+	if len(args) == 0 {
+		return msg
+	}
+	return fmt.Sprintf(msg, args...)
+}
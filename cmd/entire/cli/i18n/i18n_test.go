@@ -0,0 +1,94 @@
+package i18n
+
+import (
+	"regexp"
+	"slices"
+	"testing"
+)
+
+var verbPattern = regexp.MustCompile(`%[-+# 0]*[0-9]*[a-zA-Z%]`)
+
+func TestCatalogsAreComplete(t *testing.T) {
+	t.Parallel()
+
+	for _, l := range Locales {
+		catalog, ok := catalogs[l]
+		if !ok {
+			t.Fatalf("no catalog for locale %q", l)
+		}
+		for key, en := range english {
+			msg, ok := catalog[key]
+			if !ok {
+				t.Errorf("locale %q is missing %q", l, key)
+				continue
+			}
+			// Translations may reorder words but must take the same arguments
+			want := verbPattern.FindAllString(en, -1)
+			got := verbPattern.FindAllString(msg, -1)
+			if !slices.Equal(got, want) {
+				t.Errorf("locale %q, key %q: verbs %v, want %v", l, key, got, want)
+			}
+		}
+		for key := range catalog {
+			if _, ok := english[key]; !ok {
+				t.Errorf("locale %q has %q, which English lacks", l, key)
+			}
+		}
+	}
+}
+
+func TestLookup(t *testing.T) {
+	t.Parallel()
+
+	if got := Lookup(English, ConfirmRewind, "abc1234"); got != "Reset to abc1234?" {
+		t.Errorf("Lookup(en) = %q", got)
+	}
+	if got := Lookup(Japanese, ConfirmRewind, "abc1234"); got != "abc1234 に戻しますか？" {
+		t.Errorf("Lookup(ja) = %q", got)
+	}
+	if got := Lookup(Locale("fr"), NotGitRepository); got != "not a git repository" {
+		t.Errorf("Lookup() of an unknown locale = %q, want the English message", got)
+	}
+	if got := Lookup(English, Key("no.such.key")); got != "no.such.key" {
+		t.Errorf("Lookup() of an unknown key = %q, want the key", got)
+	}
+}
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name                    string
+		lcAll, lcMessages, lang string
+		want                    Locale
+	}{
+		{name: "unset", want: English},
+		{name: "LANG japanese", lang: "ja_JP.UTF-8", want: Japanese},
+		{name: "LANG chinese", lang: "zh_CN.UTF-8", want: Chinese},
+		{name: "LANG unsupported", lang: "fr_FR.UTF-8", want: English},
+		{name: "C locale", lang: "C", want: English},
+		{name: "LC_MESSAGES beats LANG", lcMessages: "zh_TW", lang: "ja_JP.UTF-8", want: Chinese},
+		{name: "LC_ALL beats LC_MESSAGES", lcAll: "en_US.UTF-8", lcMessages: "ja_JP", want: English},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("LC_ALL", tt.lcAll)
+			t.Setenv("LC_MESSAGES", tt.lcMessages)
+			t.Setenv("LANG", tt.lang)
+			if got := Detect(); got != tt.want {
+				t.Errorf("Detect() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseLocale(t *testing.T) {
+	t.Parallel()
+
+	for _, l := range Locales {
+		if got, err := ParseLocale(string(l)); err != nil || got != l {
+			t.Errorf("ParseLocale(%q) = %q, %v", l, got, err)
+		}
+	}
+	if _, err := ParseLocale("fr"); err == nil {
+		t.Error("ParseLocale(\"fr\") should fail")
+	}
+}
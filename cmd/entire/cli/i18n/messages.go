@@ -0,0 +1,209 @@
+package i18n
+
+// Key identifies a message in the catalogs.
+type Key string
+
+// General messages.
+const (
+	Yes              Key = "yes"
+	No               Key = "no"
+	Cancel           Key = "cancel"
+	NotGitRepository Key = "not_git_repository"
+)
+
+// Confirmation dialogs.
+const (
+	ConfirmResumeOlder             Key = "confirm.resume_older"
+	ConfirmFetchBranch             Key = "confirm.fetch_branch"
+	ConfirmShareUpload             Key = "confirm.share_upload"
+	ConfirmShareUploadDesc         Key = "confirm.share_upload.desc"
+	ConfirmPurgeSession            Key = "confirm.purge_session"
+	ConfirmPurgeSessionDesc        Key = "confirm.purge_session.desc"
+	ConfirmOverwriteLogs           Key = "confirm.overwrite_logs"
+	ConfirmTelemetry               Key = "confirm.telemetry"
+	ConfirmTelemetryDesc           Key = "confirm.telemetry.desc"
+	ConfirmUninstall               Key = "confirm.uninstall"
+	ConfirmUninstallYes            Key = "confirm.uninstall.yes"
+	ConfirmRewind                  Key = "confirm.rewind"
+	ConfirmRewindDesc              Key = "confirm.rewind.desc"
+	ConfirmDetachedHead            Key = "confirm.detached_head"
+	ConfirmDetachedHeadDesc        Key = "confirm.detached_head.desc"
+	ConfirmResetBranch             Key = "confirm.reset_branch"
+	ConfirmResetBranchDesc         Key = "confirm.reset_branch.desc"
+	ConfirmResetBranchWarnings     Key = "confirm.reset_branch_warnings"
+	ConfirmResetBranchWarningsDesc Key = "confirm.reset_branch_warnings.desc"
+	ConfirmResetSessionData        Key = "confirm.reset_session_data"
+	ConfirmResetSession            Key = "confirm.reset_session"
+	ConfirmResetSessionDesc        Key = "confirm.reset_session.desc"
+)
+
+// Headers of `entire explain` and `entire log` output.
+const (
+	ExplainCheckpoint          Key = "explain.checkpoint"
+	ExplainCheckpointTemporary Key = "explain.checkpoint_temporary"
+	ExplainSession             Key = "explain.session"
+	ExplainCreated             Key = "explain.created"
+	ExplainAuthor              Key = "explain.author"
+	ExplainTokens              Key = "explain.tokens"
+	ExplainCommits             Key = "explain.commits"
+	ExplainNoCommits           Key = "explain.no_commits"
+	ExplainIntent              Key = "explain.intent"
+	ExplainOutcome             Key = "explain.outcome"
+	ExplainNotGenerated        Key = "explain.not_generated"
+	ExplainNotAvailable        Key = "explain.not_available"
+	ExplainFiles               Key = "explain.files"
+	ExplainNoFiles             Key = "explain.no_files"
+	ExplainAttachments         Key = "explain.attachments"
+	ExplainArtifacts           Key = "explain.artifacts"
+	LogNoCheckpoints           Key = "log.no_checkpoints"
+)
+
+var catalogs = map[Locale]map[Key]string{
+	English:  english,
+	Japanese: japanese,
+	Chinese:  chinese,
+}
+
+var english = map[Key]string{
+	Yes:              "Yes",
+	No:               "No",
+	Cancel:           "Cancel",
+	NotGitRepository: "not a git repository",
+
+	ConfirmResumeOlder:             "Resume from this older checkpoint?",
+	ConfirmFetchBranch:             "Branch '%s' not found locally. Fetch from origin?",
+	ConfirmShareUpload:             "Upload checkpoint %s to %s?",
+	ConfirmShareUploadDesc:         "Anyone with the link can read the transcript until it expires.",
+	ConfirmPurgeSession:            "Purge the content of session %s?",
+	ConfirmPurgeSessionDesc:        "Rewrites the history of %s. This can't be undone.",
+	ConfirmOverwriteLogs:           "Overwrite local session logs with checkpoint versions?",
+	ConfirmTelemetry:               "Help improve Entire CLI?",
+	ConfirmTelemetryDesc:           "Share anonymous usage data. No code or personal info collected.",
+	ConfirmUninstall:               "Are you sure you want to uninstall Entire?",
+	ConfirmUninstallYes:            "Yes, uninstall",
+	ConfirmRewind:                  "Reset to %s?",
+	ConfirmRewindDesc:              "This will reset to: %s\nChanges after this point may be lost!",
+	ConfirmDetachedHead:            "Create detached HEAD?",
+	ConfirmDetachedHeadDesc:        "This will checkout the commit directly. You'll be in 'detached HEAD' state.\nAny uncommitted changes will be lost!",
+	ConfirmResetBranch:             "Reset branch to %s?",
+	ConfirmResetBranchDesc:         "This will move your branch pointer to this commit.\nCommits after this point will be orphaned (but recoverable via reflog).",
+	ConfirmResetBranchWarnings:     "⚠️  Reset branch with warnings?",
+	ConfirmResetBranchWarningsDesc: "WARNING - the following issues were detected:\n%s\n\nThis will move your branch to %s and DISCARD commits after it!",
+	ConfirmResetSessionData:        "Reset session data?",
+	ConfirmResetSession:            "Reset session %s?",
+	ConfirmResetSessionDesc:        "Phase: %s, Checkpoints: %d",
+
+	ExplainCheckpoint:          "Checkpoint: %s",
+	ExplainCheckpointTemporary: "Checkpoint: %s [temporary]",
+	ExplainSession:             "Session: %s",
+	ExplainCreated:             "Created: %s",
+	ExplainAuthor:              "Author: %s <%s>",
+	ExplainTokens:              "Tokens: %d",
+	ExplainCommits:             "Commits: (%d)",
+	ExplainNoCommits:           "Commits: No commits found on this branch",
+	ExplainIntent:              "Intent: %s",
+	ExplainOutcome:             "Outcome: %s",
+	ExplainNotGenerated:        "(not generated)",
+	ExplainNotAvailable:        "(not available)",
+	ExplainFiles:               "Files: (%d)",
+	ExplainNoFiles:             "Files: (none)",
+	ExplainAttachments:         "Attachments: (%d)",
+	ExplainArtifacts:           "Artifacts: (%d)",
+	LogNoCheckpoints:           "No checkpoints found on this branch.",
+}
+
+var japanese = map[Key]string{
+	Yes:              "はい",
+	No:               "いいえ",
+	Cancel:           "キャンセル",
+	NotGitRepository: "git リポジトリではありません",
+
+	ConfirmResumeOlder:             "この古いチェックポイントから再開しますか？",
+	ConfirmFetchBranch:             "ブランチ '%s' がローカルにありません。origin から取得しますか？",
+	ConfirmShareUpload:             "チェックポイント %s を %s にアップロードしますか？",
+	ConfirmShareUploadDesc:         "リンクを知っている人は、期限が切れるまでトランスクリプトを読めます。",
+	ConfirmPurgeSession:            "セッション %s の内容を消去しますか？",
+	ConfirmPurgeSessionDesc:        "%s の履歴を書き換えます。元に戻せません。",
+	ConfirmOverwriteLogs:           "ローカルのセッションログをチェックポイントの内容で上書きしますか？",
+	ConfirmTelemetry:               "Entire CLI の改善に協力しますか？",
+	ConfirmTelemetryDesc:           "匿名の利用データを送信します。コードや個人情報は収集しません。",
+	ConfirmUninstall:               "Entire をアンインストールしてもよろしいですか？",
+	ConfirmUninstallYes:            "はい、アンインストールします",
+	ConfirmRewind:                  "%s に戻しますか？",
+	ConfirmRewindDesc:              "次の時点に戻します: %s\nこれ以降の変更は失われる可能性があります。",
+	ConfirmDetachedHead:            "detached HEAD を作成しますか？",
+	ConfirmDetachedHeadDesc:        "コミットを直接チェックアウトし、'detached HEAD' 状態になります。\nコミットしていない変更はすべて失われます。",
+	ConfirmResetBranch:             "ブランチを %s にリセットしますか？",
+	ConfirmResetBranchDesc:         "ブランチをこのコミットに移動します。\nこれ以降のコミットはどこからも参照されなくなります（reflog から復元できます）。",
+	ConfirmResetBranchWarnings:     "⚠️  警告がありますがブランチをリセットしますか？",
+	ConfirmResetBranchWarningsDesc: "警告 - 次の問題が見つかりました:\n%s\n\nブランチを %s に移動し、それ以降のコミットを破棄します。",
+	ConfirmResetSessionData:        "セッションデータをリセットしますか？",
+	ConfirmResetSession:            "セッション %s をリセットしますか？",
+	ConfirmResetSessionDesc:        "フェーズ: %s、チェックポイント: %d",
+
+	ExplainCheckpoint:          "チェックポイント: %s",
+	ExplainCheckpointTemporary: "チェックポイント: %s [一時]",
+	ExplainSession:             "セッション: %s",
+	ExplainCreated:             "作成日時: %s",
+	ExplainAuthor:              "作成者: %s <%s>",
+	ExplainTokens:              "トークン: %d",
+	ExplainCommits:             "コミット: (%d)",
+	ExplainNoCommits:           "コミット: このブランチにコミットはありません",
+	ExplainIntent:              "意図: %s",
+	ExplainOutcome:             "結果: %s",
+	ExplainNotGenerated:        "(未生成)",
+	ExplainNotAvailable:        "(なし)",
+	ExplainFiles:               "ファイル: (%d)",
+	ExplainNoFiles:             "ファイル: (なし)",
+	ExplainAttachments:         "添付ファイル: (%d)",
+	ExplainArtifacts:           "成果物: (%d)",
+	LogNoCheckpoints:           "このブランチにチェックポイントはありません。",
+}
+
+var chinese = map[Key]string{
+	Yes:              "是",
+	No:               "否",
+	Cancel:           "取消",
+	NotGitRepository: "不是 git 仓库",
+
+	ConfirmResumeOlder:             "从这个较早的检查点恢复吗？",
+	ConfirmFetchBranch:             "本地未找到分支 '%s'。要从 origin 获取吗？",
+	ConfirmShareUpload:             "将检查点 %s 上传到 %s 吗？",
+	ConfirmShareUploadDesc:         "在链接过期之前，任何拥有链接的人都可以阅读对话记录。",
+	ConfirmPurgeSession:            "清除会话 %s 的内容吗？",
+	ConfirmPurgeSessionDesc:        "将重写 %s 的历史。此操作无法撤销。",
+	ConfirmOverwriteLogs:           "用检查点中的版本覆盖本地会话日志吗？",
+	ConfirmTelemetry:               "帮助改进 Entire CLI 吗？",
+	ConfirmTelemetryDesc:           "共享匿名使用数据。不会收集代码或个人信息。",
+	ConfirmUninstall:               "确定要卸载 Entire 吗？",
+	ConfirmUninstallYes:            "是，卸载",
+	ConfirmRewind:                  "重置到 %s 吗？",
+	ConfirmRewindDesc:              "将重置到：%s\n此后的更改可能会丢失！",
+	ConfirmDetachedHead:            "创建分离的 HEAD 吗？",
+	ConfirmDetachedHeadDesc:        "将直接检出该提交，你会处于“分离 HEAD”状态。\n所有未提交的更改都会丢失！",
+	ConfirmResetBranch:             "将分支重置到 %s 吗？",
+	ConfirmResetBranchDesc:         "将把分支指针移动到此提交。\n此后的提交将成为孤立提交（可通过 reflog 恢复）。",
+	ConfirmResetBranchWarnings:     "⚠️  存在警告，仍要重置分支吗？",
+	ConfirmResetBranchWarningsDesc: "警告 - 检测到以下问题：\n%s\n\n这会将分支移动到 %s，并丢弃之后的提交！",
+	ConfirmResetSessionData:        "重置会话数据吗？",
+	ConfirmResetSession:            "重置会话 %s 吗？",
+	ConfirmResetSessionDesc:        "阶段：%s，检查点：%d",
+
+	ExplainCheckpoint:          "检查点：%s",
+	ExplainCheckpointTemporary: "检查点：%s [临时]",
+	ExplainSession:             "会话：%s",
+	ExplainCreated:             "创建时间：%s",
+	ExplainAuthor:              "作者：%s <%s>",
+	ExplainTokens:              "令牌数：%d",
+	ExplainCommits:             "提交：(%d)",
+	ExplainNoCommits:           "提交：此分支上没有找到提交",
+	ExplainIntent:              "意图：%s",
+	ExplainOutcome:             "结果：%s",
+	ExplainNotGenerated:        "(未生成)",
+	ExplainNotAvailable:        "(无)",
+	ExplainFiles:               "文件：(%d)",
+	ExplainNoFiles:             "文件：(无)",
+	ExplainAttachments:         "附件：(%d)",
+	ExplainArtifacts:           "产物：(%d)",
+	LogNoCheckpoints:           "此分支上没有找到检查点。",
+}
@@ -0,0 +1,110 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+	"github.com/entireio/cli/cmd/entire/cli/strategy"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/spf13/cobra"
+)
+
+func newImportCmd() *cobra.Command {
+	var forceFlag bool
+
+	cmd := &cobra.Command{
+		Use:   "import <bundle.tar.zst>",
+		Short: "Import checkpoints from a bundle written by 'entire export'",
+		Long: `Import reads a bundle written by 'entire export' and adds its checkpoints
+to the entire/checkpoints/v1 branch, and its shadow branches, if any, to the
+repository. Imported checkpoints can then be browsed with 'entire explain'
+or 'entire serve'.
+
+Checkpoints and shadow branches that already exist are skipped; --force
+replaces them with the bundle's version.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			if _, err := paths.WorktreeRoot(ctx); err != nil {
+				return paths.ErrNotGitRepository
+			}
+			return runImport(ctx, cmd.OutOrStdout(), args[0], forceFlag)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&forceFlag, "force", "f", false, "Replace checkpoints and shadow branches that already exist")
+
+	return cmd
+}
+
+func runImport(ctx context.Context, w io.Writer, bundlePath string, force bool) error {
+	f, err := os.Open(bundlePath) //nolint:gosec // Path comes from the user's argument
+	if err != nil {
+		return fmt.Errorf("failed to open bundle: %w", err)
+	}
+	defer f.Close()
+	bundle, err := readExportBundle(f)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", bundlePath, err)
+	}
+
+	repo, err := openRepository(ctx)
+	if err != nil {
+		return fmt.Errorf("not a git repository: %w", err)
+	}
+	store, err := strategy.CommittedStore(ctx, checkpoint.NewGitStore(repo))
+	if err != nil {
+		return err //nolint:wrapcheck // already names the setting
+	}
+
+	var imported, skipped int
+	for _, cpID := range bundle.Manifest.Checkpoints {
+		files, ok := bundle.Checkpoints[cpID]
+		if !ok {
+			return fmt.Errorf("bundle lists checkpoint %s but doesn't contain it", cpID)
+		}
+		existing, err := store.ReadCommitted(ctx, cpID)
+		if err != nil {
+			return fmt.Errorf("failed to read checkpoint %s: %w", cpID, err)
+		}
+		if existing != nil && !force {
+			fmt.Fprintf(w, "Skipped checkpoint %s: it already exists (--force replaces it)\n", cpID)
+			skipped++
+			continue
+		}
+		if err := store.ImportCheckpoint(ctx, cpID, files); err != nil {
+			return fmt.Errorf("failed to import checkpoint %s: %w", cpID, err)
+		}
+		imported++
+	}
+
+	var importedBranches int
+	for i, branch := range bundle.Manifest.ShadowBranches {
+		if _, _, ok := checkpoint.ParseShadowBranchName(branch.Branch); !ok {
+			return fmt.Errorf("bundle names an invalid shadow branch: %q", branch.Branch)
+		}
+		if _, err := repo.Reference(plumbing.NewBranchReferenceName(branch.Branch), true); err == nil && !force {
+			fmt.Fprintf(w, "Skipped shadow branch %s: it already exists (--force replaces it)\n", branch.Branch)
+			continue
+		}
+		if err := store.ImportShadowBranch(ctx, branch.Branch, bundle.Shadows[i]); err != nil {
+			return fmt.Errorf("failed to import shadow branch %s: %w", branch.Branch, err)
+		}
+		importedBranches++
+	}
+
+	fmt.Fprintf(w, "Imported %d checkpoint(s)", imported)
+	if importedBranches > 0 {
+		fmt.Fprintf(w, " and %d shadow branch(es)", importedBranches)
+	}
+	if skipped > 0 {
+		fmt.Fprintf(w, "; skipped %d existing", skipped)
+	}
+	fmt.Fprintf(w, " from %s\n", bundlePath)
+	return nil
+}
@@ -0,0 +1,104 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+	"github.com/entireio/cli/cmd/entire/cli/strategy"
+
+	"github.com/spf13/cobra"
+)
+
+func newIndexCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "index",
+		Short: "Inspect or rebuild the checkpoint index",
+		Long: `The checkpoint index caches the list of checkpoints on the
+entire/checkpoints/v1 branch in the git directory (` + checkpoint.IndexFileName + `),
+so listing checkpoints doesn't read every checkpoint's metadata. It is kept
+up to date as checkpoints are written, and rebuilt automatically the next
+time checkpoints are listed after the branch changes some other way, such
+as a fetch. It never needs to be committed or shared.`,
+	}
+
+	cmd.AddCommand(newIndexStatusCmd())
+	cmd.AddCommand(newIndexRebuildCmd())
+
+	return cmd
+}
+
+func newIndexStatusCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "status",
+		Short: "Show whether the checkpoint index is up to date",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := cmd.Context()
+			if _, err := paths.WorktreeRoot(ctx); err != nil {
+				return paths.ErrNotGitRepository
+			}
+			return runIndexStatus(ctx, cmd.OutOrStdout())
+		},
+	}
+}
+
+func newIndexRebuildCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "rebuild",
+		Short: "Rebuild the checkpoint index from the checkpoints branch",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := cmd.Context()
+			if _, err := paths.WorktreeRoot(ctx); err != nil {
+				return paths.ErrNotGitRepository
+			}
+			return runIndexRebuild(ctx, cmd.OutOrStdout())
+		},
+	}
+}
+
+func runIndexStatus(ctx context.Context, w io.Writer) error {
+	if err := strategy.RequireBranchStore(ctx, "entire index status"); err != nil {
+		return err //nolint:wrapcheck // already names the operation
+	}
+	repo, err := openRepository(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to open git repository: %w", err)
+	}
+	status, err := checkpoint.NewGitStore(repo).IndexStatus(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to read checkpoint index: %w", err)
+	}
+
+	switch {
+	case !status.Exists:
+		fmt.Fprintln(w, "No checkpoint index yet; it is built the next time checkpoints are listed.")
+	case status.Fresh:
+		fmt.Fprintf(w, "Checkpoint index is up to date (%d checkpoints).\n", status.Checkpoints)
+	default:
+		fmt.Fprintf(w, "Checkpoint index is out of date (%d checkpoints); it is rebuilt the next time checkpoints are listed.\n", status.Checkpoints)
+	}
+	if status.Path != "" {
+		fmt.Fprintf(w, "Path: %s\n", status.Path)
+	}
+	return nil
+}
+
+func runIndexRebuild(ctx context.Context, w io.Writer) error {
+	if err := strategy.RequireBranchStore(ctx, "entire index rebuild"); err != nil {
+		return err //nolint:wrapcheck // already names the operation
+	}
+	repo, err := openRepository(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to open git repository: %w", err)
+	}
+	count, err := checkpoint.NewGitStore(repo).RebuildIndex(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to rebuild checkpoint index: %w", err)
+	}
+	fmt.Fprintf(w, "Indexed %d checkpoints.\n", count)
+	return nil
+}
@@ -0,0 +1,56 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+	"github.com/entireio/cli/cmd/entire/cli/strategy"
+)
+
+func TestRunIndexRebuildAndStatus(t *testing.T) {
+	setupExecTestRepo(t)
+	ctx := context.Background()
+
+	var out bytes.Buffer
+	if err := runIndexStatus(ctx, &out); err != nil {
+		t.Fatalf("runIndexStatus() error = %v", err)
+	}
+	if !strings.Contains(out.String(), "No checkpoint index yet") {
+		t.Errorf("status before any listing = %q, want no index", out.String())
+	}
+
+	repo, err := openRepository(ctx)
+	if err != nil {
+		t.Fatalf("openRepository() error = %v", err)
+	}
+	if err := checkpoint.NewGitStore(repo).WriteCommitted(ctx, checkpoint.WriteCommittedOptions{
+		CheckpointID: id.MustCheckpointID("e5e5e5e5e5e5"),
+		SessionID:    "index-session",
+		Strategy:     strategy.StrategyNameManualCommit,
+		Transcript:   []byte(`{"type":"user","message":{"content":"hi"}}` + "\n"),
+		AuthorName:   "Test",
+		AuthorEmail:  "test@test.com",
+	}); err != nil {
+		t.Fatalf("WriteCommitted() error = %v", err)
+	}
+
+	out.Reset()
+	if err := runIndexRebuild(ctx, &out); err != nil {
+		t.Fatalf("runIndexRebuild() error = %v", err)
+	}
+	if !strings.Contains(out.String(), "Indexed 1 checkpoints.") {
+		t.Errorf("rebuild output = %q, want 1 checkpoint indexed", out.String())
+	}
+
+	out.Reset()
+	if err := runIndexStatus(ctx, &out); err != nil {
+		t.Fatalf("runIndexStatus() error = %v", err)
+	}
+	if !strings.Contains(out.String(), "Checkpoint index is up to date (1 checkpoints).") {
+		t.Errorf("status after rebuild = %q, want up to date", out.String())
+	}
+}
@@ -0,0 +1,367 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+	"github.com/entireio/cli/cmd/entire/cli/strategy"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/sergi/go-diff/diffmatchpatch"
+	"github.com/spf13/cobra"
+)
+
+func newInstructionsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "instructions",
+		Short: "Show how agent instruction files changed across sessions",
+		Long: `Every checkpoint records the version of the agent's instruction files
+(CLAUDE.md, AGENTS.md, GEMINI.md, .cursorrules, .cursor/rules) as its git blob
+hash, whether or not "snapshot_agent_config" is enabled. Only the hashes are
+stored, so private files such as CLAUDE.local.md don't leave your machine.`,
+	}
+
+	cmd.AddCommand(newInstructionsHistoryCmd())
+
+	return cmd
+}
+
+func newInstructionsHistoryCmd() *cobra.Command {
+	var blameFlag bool
+
+	cmd := &cobra.Command{
+		Use:   "history [path]",
+		Short: "List the sessions that changed agent instruction files",
+		Long: `History lists, oldest first, each session in which an instruction file
+was added, modified, or deleted, and whether the session's agent edited it
+or it changed outside the session. Pass a path to follow a single file.
+
+With --blame and a path, history prints the file's latest recorded version
+with the session that last changed each line. Each version's content comes
+from git, when that version was committed, or from the checkpoint's
+snapshot when "snapshot_agent_config" was enabled. Lines of versions found
+in neither are attributed to the next version that was.
+
+Checkpoints are compared with the previous one on the same branch, so
+switching branches doesn't show up as a change.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			if _, err := paths.WorktreeRoot(ctx); err != nil {
+				return paths.ErrNotGitRepository
+			}
+			var pathFilter string
+			if len(args) == 1 {
+				pathFilter = args[0]
+			}
+			if blameFlag {
+				if pathFilter == "" {
+					return errors.New("--blame needs the path of an instruction file")
+				}
+				return runInstructionsBlame(ctx, cmd.OutOrStdout(), pathFilter)
+			}
+			return runInstructionsHistory(ctx, cmd.OutOrStdout(), pathFilter)
+		},
+	}
+
+	cmd.Flags().BoolVar(&blameFlag, "blame", false, "Show the session that last changed each line of the file")
+
+	return cmd
+}
+
+// instructionSession is one session of a checkpoint that recorded the
+// versions of its instruction files.
+type instructionSession struct {
+	checkpointID id.CheckpointID
+	sessionIndex int
+	meta         checkpoint.CommittedMetadata
+}
+
+// same reports whether two entries are the same session of a checkpoint.
+func (s instructionSession) same(other instructionSession) bool {
+	return s.checkpointID == other.checkpointID && s.meta.SessionID == other.meta.SessionID
+}
+
+// instructionChange is an instruction file that changed in a session.
+type instructionChange struct {
+	session instructionSession
+	path    string
+	status  string // "A" added, "M" modified, "D" deleted
+	hash    string // the new version's blob hash; empty when deleted
+
+	// byAgent is true if the session's agent edited the file; otherwise
+	// it changed outside the session (by hand, or by another tool)
+	byAgent bool
+}
+
+// attribution describes who changed the file.
+func (c instructionChange) attribution() string {
+	if c.byAgent {
+		return "edited by the agent"
+	}
+	return "changed outside the session"
+}
+
+// collectInstructionChanges returns the instruction file changes recorded in
+// checkpoints, oldest first. Each session is compared with the previous one
+// on its branch, or with the previous one overall for a branch's first.
+func collectInstructionChanges(ctx context.Context, store checkpoint.CommittedStore, pathFilter string) ([]instructionChange, error) {
+	committed, err := store.ListCommitted(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list checkpoints: %w", err)
+	}
+
+	var sessions []instructionSession
+	for _, info := range committed {
+		metas, err := store.ReadSessionMetadata(ctx, info.CheckpointID)
+		if err != nil {
+			continue
+		}
+		for i, meta := range metas {
+			if meta.Instructions == nil {
+				continue
+			}
+			// Indexes only line up with the checkpoint's sessions when all were read
+			index := -1
+			if len(metas) == info.SessionCount {
+				index = i
+			}
+			sessions = append(sessions, instructionSession{checkpointID: info.CheckpointID, sessionIndex: index, meta: meta})
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err //nolint:wrapcheck // Propagating context cancellation
+	}
+	sort.SliceStable(sessions, func(i, j int) bool {
+		return sessions[i].meta.CreatedAt.Before(sessions[j].meta.CreatedAt)
+	})
+
+	var changes []instructionChange
+	var last map[string]string
+	byBranch := make(map[string]map[string]string)
+	for _, session := range sessions {
+		current := session.meta.Instructions
+		if pathFilter != "" {
+			current = make(map[string]string)
+			if hash, ok := session.meta.Instructions[pathFilter]; ok {
+				current[pathFilter] = hash
+			}
+		}
+		previous, ok := byBranch[session.meta.Branch]
+		if !ok {
+			previous = last
+		}
+		byBranch[session.meta.Branch] = current
+		last = current
+
+		for _, change := range changedInstructionPaths(previous, current) {
+			change.session = session
+			change.byAgent = slices.Contains(session.meta.FilesTouched, change.path)
+			changes = append(changes, change)
+		}
+	}
+	return changes, nil
+}
+
+// changedInstructionPaths returns the files whose version differs between
+// two sessions, sorted by path. A nil previous reports every file as added.
+func changedInstructionPaths(previous, current map[string]string) []instructionChange {
+	var changes []instructionChange
+	for path, hash := range current {
+		before, existed := previous[path]
+		switch {
+		case !existed:
+			changes = append(changes, instructionChange{path: path, status: "A", hash: hash})
+		case before != hash:
+			changes = append(changes, instructionChange{path: path, status: "M", hash: hash})
+		}
+	}
+	for path := range previous {
+		if _, ok := current[path]; !ok {
+			changes = append(changes, instructionChange{path: path, status: "D"})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].path < changes[j].path })
+	return changes
+}
+
+func runInstructionsHistory(ctx context.Context, w io.Writer, pathFilter string) error {
+	repo, err := openRepository(ctx)
+	if err != nil {
+		return err
+	}
+	store, err := strategy.CommittedStore(ctx, checkpoint.NewGitStore(repo))
+	if err != nil {
+		return err //nolint:wrapcheck // already names the setting
+	}
+	changes, err := collectInstructionChanges(ctx, store, pathFilter)
+	if err != nil {
+		return err
+	}
+	if len(changes) == 0 {
+		if pathFilter != "" {
+			fmt.Fprintf(w, "No recorded versions of %s.\n", pathFilter)
+		} else {
+			fmt.Fprintln(w, "No instruction file versions recorded yet; they are recorded with each new checkpoint.")
+		}
+		return nil
+	}
+
+	for i, change := range changes {
+		session := change.session
+		if i == 0 || !changes[i-1].session.same(session) {
+			if i > 0 {
+				fmt.Fprintln(w)
+			}
+			line := fmt.Sprintf("%s  %s  session %s", session.checkpointID,
+				session.meta.CreatedAt.Local().Format("2006-01-02 15:04:05"), session.meta.SessionID)
+			if session.meta.Agent != "" {
+				line += " (" + string(session.meta.Agent) + ")"
+			}
+			fmt.Fprintln(w, line)
+		}
+		fmt.Fprintf(w, "  %s %s  %s\n", change.status, change.path, change.attribution())
+	}
+	return nil
+}
+
+// blameLine is a line of an instruction file and the change that last touched it.
+type blameLine struct {
+	text   string
+	change *instructionChange
+}
+
+func runInstructionsBlame(ctx context.Context, w io.Writer, path string) error {
+	repo, err := openRepository(ctx)
+	if err != nil {
+		return err
+	}
+	store, err := strategy.CommittedStore(ctx, checkpoint.NewGitStore(repo))
+	if err != nil {
+		return err //nolint:wrapcheck // already names the setting
+	}
+	changes, err := collectInstructionChanges(ctx, store, path)
+	if err != nil {
+		return err
+	}
+	if len(changes) == 0 {
+		fmt.Fprintf(w, "No recorded versions of %s.\n", path)
+		return nil
+	}
+
+	lines, missing := blameInstructionFile(ctx, repo, store, changes)
+	latest := changes[len(changes)-1]
+	if latest.status == "D" {
+		fmt.Fprintf(w, "%s was deleted in session %s (checkpoint %s).\n", path, latest.session.meta.SessionID, latest.session.checkpointID)
+		return nil
+	}
+	if lines == nil {
+		fmt.Fprintf(w, "The content of %s's latest version isn't available: it was never committed, and the checkpoint has no snapshot of it.\n", path)
+		return nil
+	}
+
+	for i, line := range lines {
+		session := line.change.session
+		by := "agent"
+		if !line.change.byAgent {
+			by = "other"
+		}
+		fmt.Fprintf(w, "%s %s %-5s %4d) %s\n", session.checkpointID,
+			session.meta.CreatedAt.Local().Format("2006-01-02"), by, i+1, line.text)
+	}
+	if missing > 0 {
+		fmt.Fprintf(w, "\n%d version(s) weren't available; their lines are attributed to the next version that was.\n", missing)
+	}
+	return nil
+}
+
+// blameInstructionFile replays the versions of one file, keeping the change
+// that last touched each line. Returns nil lines if the latest version's
+// content isn't available, and the number of versions that weren't.
+func blameInstructionFile(ctx context.Context, repo *git.Repository, store checkpoint.CommittedStore, changes []instructionChange) ([]blameLine, int) {
+	var lines []blameLine
+	missing := 0
+	dmp := diffmatchpatch.New()
+	for i := range changes {
+		change := &changes[i]
+		if change.status == "D" {
+			lines = nil
+			continue
+		}
+		content, ok := instructionContent(ctx, repo, store, *change)
+		if !ok {
+			missing++
+			if i == len(changes)-1 {
+				return nil, missing
+			}
+			continue
+		}
+
+		// Compare whole lines, including a last one without a newline
+		if content != "" && !strings.HasSuffix(content, "\n") {
+			content += "\n"
+		}
+		var before strings.Builder
+		for _, line := range lines {
+			before.WriteString(line.text + "\n")
+		}
+		text1, text2, lineArray := dmp.DiffLinesToChars(before.String(), content)
+		diffs := dmp.DiffCharsToLines(dmp.DiffMain(text1, text2, false), lineArray)
+
+		var next []blameLine
+		old := 0
+		for _, d := range diffs {
+			n := strings.Count(d.Text, "\n")
+			switch d.Type {
+			case diffmatchpatch.DiffEqual:
+				next = append(next, lines[old:old+n]...)
+				old += n
+			case diffmatchpatch.DiffDelete:
+				old += n
+			case diffmatchpatch.DiffInsert:
+				for _, text := range strings.Split(strings.TrimSuffix(d.Text, "\n"), "\n") {
+					next = append(next, blameLine{text: text, change: change})
+				}
+			}
+		}
+		lines = next
+	}
+	return lines, missing
+}
+
+// instructionContent returns the content of the version a change recorded:
+// from git if that version was committed, or else from the checkpoint's
+// agent config snapshot.
+func instructionContent(ctx context.Context, repo *git.Repository, store checkpoint.CommittedStore, change instructionChange) (string, bool) {
+	hash := plumbing.NewHash(change.hash)
+	if blob, err := repo.BlobObject(hash); err == nil {
+		reader, err := blob.Reader()
+		if err == nil {
+			defer reader.Close()
+			if data, err := io.ReadAll(reader); err == nil {
+				return string(data), true
+			}
+		}
+	}
+	if change.session.sessionIndex < 0 {
+		return "", false
+	}
+	config, err := store.ReadAgentConfig(ctx, change.session.checkpointID, change.session.sessionIndex)
+	if err != nil {
+		return "", false
+	}
+	content, ok := config[change.path]
+	if !ok || plumbing.ComputeHash(plumbing.BlobObject, content) != hash {
+		return "", false
+	}
+	return string(content), true
+}
@@ -0,0 +1,129 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+func TestRunInstructionsHistory(t *testing.T) {
+	setupCleanTestRepo(t)
+	ctx := context.Background()
+
+	var stdout bytes.Buffer
+	if err := runInstructionsHistory(ctx, &stdout, ""); err != nil {
+		t.Fatalf("runInstructionsHistory() error = %v", err)
+	}
+	if !strings.Contains(stdout.String(), "No instruction file versions recorded yet") {
+		t.Errorf("expected empty history, got:\n%s", stdout.String())
+	}
+
+	repo, err := openRepository(ctx)
+	if err != nil {
+		t.Fatalf("openRepository() error = %v", err)
+	}
+	store := checkpoint.NewGitStore(repo)
+
+	v1 := "Use tabs.\nRun make test.\n"
+	v2 := "Use tabs.\nRun make lint.\nRun make test.\n"
+	v3 := "Use spaces.\nRun make lint.\nRun make test.\n"
+	hash := func(content string) string {
+		return plumbing.ComputeHash(plumbing.BlobObject, []byte(content)).String()
+	}
+	// v1 was committed to the repository; v2 is only in a snapshot; v3 is in neither
+	if _, err := checkpoint.CreateBlobFromContent(repo, []byte(v1)); err != nil {
+		t.Fatal(err)
+	}
+	for _, cp := range []struct {
+		id           string
+		branch       string
+		instructions map[string]string
+		filesTouched []string
+		snapshot     map[string][]byte
+	}{
+		{"e1e1e1e1e1e1", "main", map[string]string{"CLAUDE.md": hash(v1), ".cursorrules": hash("Be brief.\n")}, nil, nil},
+		{"e2e2e2e2e2e2", "main", map[string]string{"CLAUDE.md": hash(v2), ".cursorrules": hash("Be brief.\n")}, []string{"CLAUDE.md"}, map[string][]byte{"CLAUDE.md": []byte(v2)}},
+		// Another branch still has v1: comparing with main's checkpoint would report a change
+		{"e3e3e3e3e3e3", "feature", map[string]string{"CLAUDE.md": hash(v2), ".cursorrules": hash("Be brief.\n")}, nil, nil},
+		{"e4e4e4e4e4e4", "main", map[string]string{"CLAUDE.md": hash(v2)}, []string{"main.go"}, nil},
+	} {
+		if err := store.WriteCommitted(ctx, checkpoint.WriteCommittedOptions{
+			CheckpointID: id.MustCheckpointID(cp.id),
+			SessionID:    "session-" + cp.id,
+			Strategy:     "manual-commit",
+			Branch:       cp.branch,
+			Transcript:   []byte(`{"type":"user"}` + "\n"),
+			AuthorName:   "Test",
+			AuthorEmail:  "test@test.com",
+			Agent:        "Claude Code",
+			FilesTouched: cp.filesTouched,
+			Instructions: cp.instructions,
+			AgentConfig:  cp.snapshot,
+		}); err != nil {
+			t.Fatalf("WriteCommitted() error = %v", err)
+		}
+	}
+
+	stdout.Reset()
+	if err := runInstructionsHistory(ctx, &stdout, ""); err != nil {
+		t.Fatalf("runInstructionsHistory() error = %v", err)
+	}
+	out := stdout.String()
+	for _, want := range []string{
+		"e1e1e1e1e1e1", "session session-e1e1e1e1e1e1 (Claude Code)", "A CLAUDE.md  changed outside the session",
+		"e2e2e2e2e2e2", "M CLAUDE.md  edited by the agent",
+		"e4e4e4e4e4e4", "D .cursorrules  changed outside the session",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "e3e3e3e3e3e3") {
+		t.Errorf("the feature branch's first checkpoint should be compared with the latest one:\n%s", out)
+	}
+
+	// v2's lines come from the snapshot; the first session added the rest
+	stdout.Reset()
+	if err := runInstructionsBlame(ctx, &stdout, "CLAUDE.md"); err != nil {
+		t.Fatalf("runInstructionsBlame() error = %v", err)
+	}
+	out = stdout.String()
+	for _, want := range []string{
+		"e1e1e1e1e1e1 ",
+		"other    1) Use tabs.",
+		"e2e2e2e2e2e2 ",
+		"agent    2) Run make lint.",
+		"other    3) Run make test.",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in blame:\n%s", want, out)
+		}
+	}
+
+	// A latest version that can't be read can't be blamed
+	if err := store.WriteCommitted(ctx, checkpoint.WriteCommittedOptions{
+		CheckpointID: id.MustCheckpointID("e5e5e5e5e5e5"),
+		SessionID:    "session-e5",
+		Strategy:     "manual-commit",
+		Branch:       "main",
+		Transcript:   []byte(`{"type":"user"}` + "\n"),
+		AuthorName:   "Test",
+		AuthorEmail:  "test@test.com",
+		Instructions: map[string]string{"CLAUDE.md": hash(v3)},
+	}); err != nil {
+		t.Fatalf("WriteCommitted() error = %v", err)
+	}
+	stdout.Reset()
+	if err := runInstructionsBlame(ctx, &stdout, "CLAUDE.md"); err != nil {
+		t.Fatalf("runInstructionsBlame() error = %v", err)
+	}
+	if !strings.Contains(stdout.String(), "isn't available") {
+		t.Errorf("expected unavailable content, got:\n%s", stdout.String())
+	}
+}
@@ -5,6 +5,9 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"reflect"
+	"sort"
+	"strings"
 )
 
 // MarshalIndentWithNewline is like json.MarshalIndent but adds a trailing newline.
@@ -18,3 +21,91 @@ func MarshalIndentWithNewline(v any, prefix, indent string) ([]byte, error) {
 	}
 	return buf.Bytes(), nil
 }
+
+// MarshalIndentPreservingUnknown is like MarshalIndentWithNewline for a struct
+// that was decoded from original and then changed: top-level fields of
+// original that the struct doesn't declare, such as fields written by a newer
+// version, are kept after the struct's own fields. Fields the struct declares
+// always come from v, so clearing one still removes it.
+func MarshalIndentPreservingUnknown(v any, original []byte, prefix, indent string) ([]byte, error) {
+	var originalFields map[string]json.RawMessage
+	if err := json.Unmarshal(original, &originalFields); err != nil {
+		return MarshalIndentWithNewline(v, prefix, indent)
+	}
+	known := jsonFieldNames(reflect.TypeOf(v))
+	var unknown []string
+	for name := range originalFields {
+		if !known[strings.ToLower(name)] {
+			unknown = append(unknown, name)
+		}
+	}
+	if known == nil || len(unknown) == 0 {
+		return MarshalIndentWithNewline(v, prefix, indent)
+	}
+	sort.Strings(unknown)
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("encoding JSON: %w", err)
+	}
+	if !bytes.HasSuffix(data, []byte("}")) {
+		return MarshalIndentWithNewline(v, prefix, indent) // A nil pointer
+	}
+	var merged bytes.Buffer
+	merged.Write(bytes.TrimSuffix(data, []byte("}")))
+	for _, name := range unknown {
+		if merged.Len() > 1 {
+			merged.WriteByte(',')
+		}
+		key, err := json.Marshal(name)
+		if err != nil {
+			return nil, fmt.Errorf("encoding JSON: %w", err)
+		}
+		merged.Write(key)
+		merged.WriteByte(':')
+		merged.Write(originalFields[name])
+	}
+	merged.WriteByte('}')
+
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, merged.Bytes(), prefix, indent); err != nil {
+		return nil, fmt.Errorf("encoding JSON: %w", err)
+	}
+	buf.WriteByte('\n')
+	return buf.Bytes(), nil
+}
+
+// jsonFieldNames returns the lowercased JSON names of a struct type's
+// fields, including those of embedded structs, as encoding/json matches
+// them case-insensitively. Returns nil if t isn't a struct or a pointer to one.
+func jsonFieldNames(t reflect.Type) map[string]bool {
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil
+	}
+	names := make(map[string]bool)
+	for i := range t.NumField() {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if name == "" && field.Anonymous {
+			for embedded := range jsonFieldNames(field.Type) {
+				names[embedded] = true
+			}
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		names[strings.ToLower(name)] = true
+	}
+	return names
+}
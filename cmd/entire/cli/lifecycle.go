@@ -18,6 +18,9 @@ import (
 	"time"
 
 	"github.com/entireio/cli/cmd/entire/cli/agent"
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/classify"
+	"github.com/entireio/cli/cmd/entire/cli/events"
 	"github.com/entireio/cli/cmd/entire/cli/logging"
 	"github.com/entireio/cli/cmd/entire/cli/paths"
 	"github.com/entireio/cli/cmd/entire/cli/session"
@@ -36,6 +39,22 @@ func DispatchLifecycleEvent(ctx context.Context, ag agent.Agent, event *agent.Ev
 		return errors.New("event cannot be nil")
 	}
 
+	// Every event is a heartbeat; new sessions and turns also look for sessions that went silent
+	if event.SessionID != "" {
+		if err := recordSessionHeartbeat(ctx, event.SessionID); err != nil {
+			logging.Warn(logging.WithComponent(ctx, "lifecycle"), "failed to record heartbeat",
+				slog.String("session_id", event.SessionID),
+				slog.String("error", err.Error()))
+		}
+	}
+	if event.Type == agent.SessionStart || event.Type == agent.TurnStart {
+		finalizeSilentSessions(ctx, event.SessionID, time.Now())
+	}
+	// By the next prompt or the session's end, a transcript written after the last turn ended is complete
+	if event.SessionID != "" && (event.Type == agent.TurnStart || event.Type == agent.SessionEnd) {
+		reconcileSessionTranscript(ctx, event.SessionID)
+	}
+
 	switch event.Type {
 	case agent.SessionStart:
 		return handleLifecycleSessionStart(ctx, ag, event)
@@ -72,6 +91,7 @@ func handleLifecycleSessionStart(ctx context.Context, ag agent.Agent, event *age
 	if err := validation.ValidateSessionID(event.SessionID); err != nil {
 		return fmt.Errorf("invalid %s event: %w", event.Type, err)
 	}
+	strategy.RecordEvent(ctx, events.Event{Type: events.TypeSessionStarted, SessionID: event.SessionID, Agent: string(ag.Type())})
 
 	// Build informational message
 	message := "\n\nPowered by Entire:\n  This conversation will be linked to your next commit."
@@ -126,6 +146,19 @@ func handleLifecycleTurnStart(ctx context.Context, ag agent.Agent, event *agent.
 		return fmt.Errorf("invalid %s event: %w", event.Type, err)
 	}
 
+	entireSettings, settingsErr := LoadEntireSettings(ctx)
+	if settingsErr != nil {
+		logging.Warn(logCtx, "failed to load settings",
+			slog.String("error", settingsErr.Error()))
+		entireSettings = &EntireSettings{}
+	}
+
+	// Warn about or block prompts on risky repository state, if configured.
+	// Agents without hooks have no channel for the response (entire exec checks up front).
+	if _, hasHooks := ag.(agent.HookSupport); hasHooks && runPromptGuard(ctx, entireSettings.PromptGuard) {
+		return nil
+	}
+
 	// Capture pre-prompt state (including transcript position via TranscriptAnalyzer)
 	if err := CapturePrePromptState(ctx, ag, sessionID, event.SessionRef); err != nil {
 		return err
@@ -141,6 +174,12 @@ func handleLifecycleTurnStart(ctx context.Context, ag agent.Agent, event *agent.
 	if err := strat.InitializeSession(ctx, sessionID, ag.Type(), event.SessionRef, event.Prompt); err != nil {
 		logging.Warn(logCtx, "failed to initialize session state",
 			slog.String("error", err.Error()))
+	} else if entireSettings.AutoStash {
+		// Snapshot the user's uncommitted changes so rewinding to before this turn is exact
+		if _, err := strat.SnapshotUncommittedChanges(ctx, sessionID); err != nil {
+			logging.Warn(logCtx, "failed to snapshot uncommitted changes",
+				slog.String("error", err.Error()))
+		}
 	}
 
 	return nil
@@ -217,6 +256,15 @@ func handleLifecycleTurnEnd(ctx context.Context, ag agent.Agent, event *agent.Ev
 	// Determine transcript offset
 	transcriptOffset := resolveTranscriptOffset(ctx, preState, sessionID)
 
+	// Split the turn's time into tool and model time, where the transcript has timestamps,
+	// and fall back to the transcript for the model when the hook doesn't report it
+	turnTranscript := transcript.SliceFromLine(transcriptData, transcriptOffset)
+	turnEnd := turnEndInfo{Model: event.Model, AgentVersion: event.AgentVersion}
+	turnEnd.ToolTime, turnEnd.ModelTime = transcript.Timing(turnTranscript)
+	if turnEnd.Model == "" {
+		turnEnd.Model, turnEnd.AgentVersion = transcript.ModelInfo(turnTranscript)
+	}
+
 	// Compute subagents directory for agents that support subagent extraction.
 	// Subagent transcripts live in <transcriptDir>/<modelSessionID>/subagents/
 	subagentsDir := filepath.Join(filepath.Dir(transcriptRef), event.SessionID, "subagents")
@@ -264,7 +312,7 @@ func handleLifecycleTurnEnd(ctx context.Context, ag agent.Agent, event *agent.Ev
 
 	// Write prompts file
 	promptFile := filepath.Join(sessionDirAbs, paths.PromptFileName)
-	promptContent := strings.Join(allPrompts, "\n\n---\n\n")
+	promptContent := checkpoint.JoinPrompts(allPrompts)
 	if err := os.WriteFile(promptFile, []byte(promptContent), 0o600); err != nil {
 		return fmt.Errorf("failed to write prompt file: %w", err)
 	}
@@ -333,7 +381,7 @@ func handleLifecycleTurnEnd(ctx context.Context, ag agent.Agent, event *agent.Ev
 	totalChanges := len(relModifiedFiles) + len(relNewFiles) + len(relDeletedFiles)
 	if totalChanges == 0 {
 		logging.Info(logCtx, "no files modified during session, skipping checkpoint")
-		transitionSessionTurnEnd(ctx, sessionID)
+		transitionSessionTurnEnd(ctx, sessionID, turnEnd)
 		if cleanupErr := CleanupPrePromptState(ctx, sessionID); cleanupErr != nil {
 			logging.Warn(logCtx, "failed to cleanup pre-prompt state",
 				slog.String("error", cleanupErr.Error()))
@@ -344,6 +392,39 @@ func handleLifecycleTurnEnd(ctx context.Context, ag agent.Agent, event *agent.Ev
 	// Log file changes
 	logFileChanges(ctx, relModifiedFiles, relNewFiles, relDeletedFiles)
 
+	label := classify.Classify(classify.Input{
+		Prompts:       allPrompts,
+		ModifiedFiles: relModifiedFiles,
+		NewFiles:      relNewFiles,
+		DeletedFiles:  relDeletedFiles,
+	})
+	entireSettings, settingsErr := LoadEntireSettings(ctx)
+	if settingsErr != nil {
+		logging.Warn(logCtx, "failed to load settings",
+			slog.String("error", settingsErr.Error()))
+		entireSettings = &EntireSettings{}
+	}
+
+	// Apply the commit message template, if configured
+	if entireSettings.CommitMessageTemplate != "" {
+		data := commitMessageData{
+			Type:    label,
+			Message: commitMessage,
+			Prompt:  promptExcerpt(lastPrompt),
+			Files:   totalChanges,
+		}
+		if state, loadErr := strategy.LoadSessionState(ctx, sessionID); loadErr == nil && state != nil {
+			data.Turn = state.TurnCount
+		}
+		templated, tmplErr := applyCommitMessageTemplate(entireSettings.CommitMessageTemplate, data)
+		if tmplErr != nil {
+			logging.Warn(logCtx, "ignoring commit message template",
+				slog.String("error", tmplErr.Error()))
+		} else {
+			commitMessage = templated
+		}
+	}
+
 	// Create context file
 	contextFile := filepath.Join(sessionDirAbs, paths.ContextFileName)
 	if err := createContextFile(contextFile, commitMessage, sessionID, allPrompts, summary); err != nil {
@@ -391,12 +472,44 @@ func handleLifecycleTurnEnd(ctx context.Context, ag agent.Agent, event *agent.Ev
 		TokenUsage:               tokenUsage,
 	}
 
+	// Don't grow Entire's storage past the configured quota
+	_, hasHooks := ag.(agent.HookSupport)
+	if message := checkStorageQuota(ctx, entireSettings); message != "" {
+		if hasHooks {
+			if err := outputHookResponse(message); err != nil {
+				logging.Warn(logCtx, "failed to write storage quota response",
+					slog.String("error", err.Error()))
+			}
+		}
+		transitionSessionTurnEnd(ctx, sessionID, turnEnd)
+		if cleanupErr := CleanupPrePromptState(ctx, sessionID); cleanupErr != nil {
+			logging.Warn(logCtx, "failed to cleanup pre-prompt state",
+				slog.String("error", cleanupErr.Error()))
+		}
+		return nil
+	}
+
 	if err := strat.SaveStep(ctx, stepCtx); err != nil {
 		return fmt.Errorf("failed to save step: %w", err)
 	}
 
+	// Tell the agent about the checkpoint, if configured and it has hooks to receive it
+	if hasHooks && entireSettings.HookResponse != nil && entireSettings.HookResponse.Checkpoint != "" {
+		files := make([]string, 0, totalChanges)
+		files = append(files, relModifiedFiles...)
+		files = append(files, relNewFiles...)
+		files = append(files, relDeletedFiles...)
+		respondAfterCheckpoint(ctx, strat, entireSettings.HookResponse.Checkpoint, checkpointResponseData{
+			SessionID:    sessionID,
+			Type:         label,
+			Message:      commitMessage,
+			Files:        files,
+			FilesChanged: len(files),
+		})
+	}
+
 	// Transition session phase and cleanup
-	transitionSessionTurnEnd(ctx, sessionID)
+	transitionSessionTurnEnd(ctx, sessionID, turnEnd)
 	if cleanupErr := CleanupPrePromptState(ctx, sessionID); cleanupErr != nil {
 		logging.Warn(logCtx, "failed to cleanup pre-prompt state",
 			slog.String("error", cleanupErr.Error()))
@@ -434,11 +547,19 @@ func handleLifecycleCompaction(ctx context.Context, ag agent.Agent, event *agent
 		}
 	}
 
+	// Compaction discards transcript context on the agent side, so record it
+	// alongside the other destructive operations.
+	strategy.RecordAudit(ctx, checkpoint.AuditEntry{
+		Operation: checkpoint.AuditOpCompaction,
+		Details:   "session " + sessionID,
+	})
+
 	logging.Info(logCtx, "context compaction detected")
 	return nil
 }
 
-// handleLifecycleSessionEnd handles session end: marks the session as ended.
+// handleLifecycleSessionEnd handles session end: marks the session as ended
+// and sends its memory record if session_memory is set.
 func handleLifecycleSessionEnd(ctx context.Context, ag agent.Agent, event *agent.Event) error {
 	logCtx := logging.WithAgent(logging.WithComponent(ctx, "lifecycle"), ag.Name())
 	logging.Info(logCtx, "session-end",
@@ -459,6 +580,7 @@ func handleLifecycleSessionEnd(ctx context.Context, ag agent.Agent, event *agent
 		logging.Warn(logCtx, "failed to mark session ended",
 			slog.String("error", err.Error()))
 	}
+	emitSessionMemory(ctx, ag, event.SessionID, event.SessionRef)
 	return nil
 }
 
@@ -673,8 +795,17 @@ func parseTranscriptForCheckpointUUID(transcriptPath string) ([]transcriptLine,
 	return lines, nil
 }
 
-// transitionSessionTurnEnd transitions the session phase to IDLE and dispatches turn-end actions.
-func transitionSessionTurnEnd(ctx context.Context, sessionID string) {
+// turnEndInfo carries what the turn-end hook learned about the finished turn.
+type turnEndInfo struct {
+	ToolTime     time.Duration
+	ModelTime    time.Duration
+	Model        string // empty if unknown
+	AgentVersion string // empty if unknown
+}
+
+// transitionSessionTurnEnd records the turn's timing and model, transitions the session
+// phase to IDLE, and dispatches turn-end actions.
+func transitionSessionTurnEnd(ctx context.Context, sessionID string, info turnEndInfo) {
 	logCtx := logging.WithComponent(ctx, "lifecycle")
 	turnState, loadErr := strategy.LoadSessionState(ctx, sessionID)
 	if loadErr != nil {
@@ -685,6 +816,13 @@ func transitionSessionTurnEnd(ctx context.Context, sessionID string) {
 	if turnState == nil {
 		return
 	}
+	turnState.RecordTurnTiming(time.Now(), info.ToolTime, info.ModelTime)
+	if info.Model != "" {
+		turnState.Model = info.Model
+	}
+	if info.AgentVersion != "" {
+		turnState.AgentVersion = info.AgentVersion
+	}
 	if err := strategy.TransitionAndLog(ctx, turnState, session.EventTurnEnd, session.TransitionContext{}, session.NoOpActionHandler{}); err != nil {
 		logging.Warn(logCtx, "turn-end transition failed",
 			slog.String("error", err.Error()))
@@ -725,6 +863,7 @@ func markSessionEnded(ctx context.Context, sessionID string) error {
 	if err := strategy.SaveSessionState(ctx, state); err != nil {
 		return fmt.Errorf("failed to save session state: %w", err)
 	}
+	strategy.RecordEvent(ctx, events.Event{Type: events.TypeSessionEnded, SessionID: sessionID, Agent: string(state.AgentType)})
 	return nil
 }
 
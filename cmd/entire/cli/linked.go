@@ -0,0 +1,214 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+	"github.com/entireio/cli/cmd/entire/cli/session"
+	"github.com/entireio/cli/cmd/entire/cli/strategy"
+	"github.com/entireio/cli/cmd/entire/cli/validation"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/spf13/cobra"
+)
+
+// linkedSession is a committed session carrying a correlation ID.
+type linkedSession struct {
+	Repo          string
+	CheckpointID  string
+	SessionID     string
+	CorrelationID string
+	CreatedAt     string
+}
+
+func newLinkedCmd() *cobra.Command {
+	var repoFlags []string
+	var setFlag string
+
+	cmd := &cobra.Command{
+		Use:   "linked [correlation-id]",
+		Short: "List sessions linked across repositories",
+		Long: `Linked lists sessions that share a correlation ID with sessions in other
+repositories, so work that spans e.g. a frontend and a backend repo can be
+followed from either side.
+
+A session gets a correlation ID from the ENTIRE_CORRELATION_ID environment
+variable when it starts, or from 'entire linked --set <id>' while it runs.
+The ID is recorded in each checkpoint's metadata when you commit.
+
+Counterpart repositories come from --repo flags and the "linked_repos" list in
+.entire/settings.json. Pass a correlation ID to show only that group.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			if _, err := paths.WorktreeRoot(ctx); err != nil {
+				return paths.ErrNotGitRepository
+			}
+
+			if setFlag != "" {
+				return runLinkedSet(ctx, cmd.OutOrStdout(), setFlag)
+			}
+
+			var filter string
+			if len(args) == 1 {
+				filter = args[0]
+			}
+
+			repos := repoFlags
+			if s, err := LoadEntireSettings(ctx); err == nil {
+				repos = append(repos, s.LinkedRepos...)
+			}
+			return runLinked(ctx, cmd.OutOrStdout(), cmd.ErrOrStderr(), repos, filter)
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&repoFlags, "repo", nil, "Path to a counterpart repository (repeatable)")
+	cmd.Flags().StringVar(&setFlag, "set", "", "Set the correlation ID on this worktree's open sessions")
+
+	return cmd
+}
+
+// runLinkedSet tags every non-ended session in the current worktree with correlationID.
+func runLinkedSet(ctx context.Context, w io.Writer, correlationID string) error {
+	if err := validation.ValidateCorrelationID(correlationID); err != nil {
+		return err //nolint:wrapcheck // validation error is already user-facing
+	}
+
+	worktreePath, err := paths.WorktreeRoot(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get worktree path: %w", err)
+	}
+	states, err := strategy.ListSessionStates(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list sessions: %w", err)
+	}
+
+	updated := 0
+	for _, state := range states {
+		if state.WorktreePath != worktreePath || state.Phase == session.PhaseEnded {
+			continue
+		}
+		state.CorrelationID = correlationID
+		if err := strategy.SaveSessionState(ctx, state); err != nil {
+			return fmt.Errorf("failed to update session %s: %w", state.SessionID, err)
+		}
+		fmt.Fprintf(w, "Linked session %s to %s\n", state.SessionID, correlationID)
+		updated++
+	}
+
+	if updated == 0 {
+		fmt.Fprintln(w, "No open sessions in this worktree. Export "+strategy.CorrelationIDEnvVar+" before starting your agent instead.")
+	}
+	return nil
+}
+
+// runLinked prints correlation groups found in this repository together with
+// matching sessions from the counterpart repositories.
+func runLinked(ctx context.Context, w, errW io.Writer, repoPaths []string, filter string) error {
+	repo, err := openRepository(ctx)
+	if err != nil {
+		return err
+	}
+	store, err := strategy.CommittedStore(ctx, checkpoint.NewGitStore(repo))
+	if err != nil {
+		return err //nolint:wrapcheck // already names the setting
+	}
+	local, err := listLinkedSessions(ctx, store, "this repo")
+	if err != nil {
+		return err
+	}
+
+	groups := make(map[string][]linkedSession)
+	for _, ls := range local {
+		if filter != "" && ls.CorrelationID != filter {
+			continue
+		}
+		groups[ls.CorrelationID] = append(groups[ls.CorrelationID], ls)
+	}
+
+	if len(groups) > 0 {
+		repoRoot, err := paths.WorktreeRoot(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get worktree path: %w", err)
+		}
+		for _, repoPath := range repoPaths {
+			absPath := repoPath
+			if !filepath.IsAbs(absPath) {
+				absPath = filepath.Join(repoRoot, repoPath)
+			}
+			otherRepo, err := git.PlainOpenWithOptions(absPath, &git.PlainOpenOptions{DetectDotGit: true})
+			if err != nil {
+				fmt.Fprintf(errW, "Warning: cannot open linked repo %s: %v\n", repoPath, err)
+				continue
+			}
+			if err := requireBranchStoreIn(otherRepo); err != nil {
+				fmt.Fprintf(errW, "Warning: cannot read checkpoints in %s: %v\n", repoPath, err)
+				continue
+			}
+			remote, err := listLinkedSessions(ctx, checkpoint.NewGitStore(otherRepo), repoPath)
+			if err != nil {
+				fmt.Fprintf(errW, "Warning: cannot read checkpoints in %s: %v\n", repoPath, err)
+				continue
+			}
+			for _, ls := range remote {
+				if _, ok := groups[ls.CorrelationID]; ok {
+					groups[ls.CorrelationID] = append(groups[ls.CorrelationID], ls)
+				}
+			}
+		}
+	}
+
+	if len(groups) == 0 {
+		if filter != "" {
+			fmt.Fprintf(w, "No sessions with correlation ID %s.\n", filter)
+		} else {
+			fmt.Fprintln(w, "No linked sessions found.")
+		}
+		return nil
+	}
+
+	ids := make([]string, 0, len(groups))
+	for correlationID := range groups {
+		ids = append(ids, correlationID)
+	}
+	sort.Strings(ids)
+
+	for i, correlationID := range ids {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "Correlation %s\n", correlationID)
+		for _, ls := range groups[correlationID] {
+			fmt.Fprintf(w, "  %-12s  %s  session %s  %s\n", ls.Repo, ls.CheckpointID, ls.SessionID, ls.CreatedAt)
+		}
+	}
+	return nil
+}
+
+// listLinkedSessions returns committed checkpoints in store that carry a correlation ID.
+func listLinkedSessions(ctx context.Context, store checkpoint.CommittedStore, label string) ([]linkedSession, error) {
+	committed, err := store.ListCommitted(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list checkpoints: %w", err)
+	}
+
+	var result []linkedSession
+	for _, info := range committed {
+		if info.CorrelationID == "" {
+			continue
+		}
+		result = append(result, linkedSession{
+			Repo:          label,
+			CheckpointID:  info.CheckpointID.String(),
+			SessionID:     info.SessionID,
+			CorrelationID: info.CorrelationID,
+			CreatedAt:     info.CreatedAt.Local().Format("2006-01-02 15:04"),
+		})
+	}
+	return result, nil
+}
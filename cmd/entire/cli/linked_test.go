@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+
+	"github.com/go-git/go-git/v5"
+)
+
+func writeLinkedCheckpoint(t *testing.T, repo *git.Repository, cpID, sessionID, correlationID string) {
+	t.Helper()
+	if err := checkpoint.NewGitStore(repo).WriteCommitted(context.Background(), checkpoint.WriteCommittedOptions{
+		CheckpointID:  id.MustCheckpointID(cpID),
+		SessionID:     sessionID,
+		Strategy:      "manual-commit",
+		Transcript:    []byte(`{"type":"user"}` + "\n"),
+		CorrelationID: correlationID,
+		AuthorName:    "Test",
+		AuthorEmail:   "test@test.com",
+	}); err != nil {
+		t.Fatalf("WriteCommitted() error = %v", err)
+	}
+}
+
+func TestRunLinked_FindsCounterpartSessions(t *testing.T) {
+	repo, _ := setupCleanTestRepo(t)
+	writeLinkedCheckpoint(t, repo, "a1b2c3d4e5f6", "frontend-session", "PROJ-7")
+	writeLinkedCheckpoint(t, repo, "b1b2c3d4e5f6", "unlinked-session", "")
+
+	otherDir := t.TempDir()
+	otherRepo, err := git.PlainInit(otherDir, false)
+	if err != nil {
+		t.Fatalf("failed to init counterpart repo: %v", err)
+	}
+	writeLinkedCheckpoint(t, otherRepo, "c1b2c3d4e5f6", "backend-session", "PROJ-7")
+	writeLinkedCheckpoint(t, otherRepo, "d1b2c3d4e5f6", "other-work", "PROJ-8")
+
+	var stdout, stderr bytes.Buffer
+	if err := runLinked(context.Background(), &stdout, &stderr, []string{otherDir}, ""); err != nil {
+		t.Fatalf("runLinked() error = %v", err)
+	}
+	output := stdout.String()
+
+	for _, want := range []string{"Correlation PROJ-7", "frontend-session", "backend-session"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q in output, got:\n%s", want, output)
+		}
+	}
+	for _, unwanted := range []string{"unlinked-session", "PROJ-8", "other-work"} {
+		if strings.Contains(output, unwanted) {
+			t.Errorf("unexpected %q in output:\n%s", unwanted, output)
+		}
+	}
+}
+
+func TestRunLinked_NoLinkedSessions(t *testing.T) {
+	setupCleanTestRepo(t)
+
+	var stdout, stderr bytes.Buffer
+	if err := runLinked(context.Background(), &stdout, &stderr, nil, ""); err != nil {
+		t.Fatalf("runLinked() error = %v", err)
+	}
+	if !strings.Contains(stdout.String(), "No linked sessions found") {
+		t.Errorf("unexpected output: %s", stdout.String())
+	}
+}
@@ -0,0 +1,35 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/entireio/cli/cmd/entire/cli/i18n"
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+)
+
+// applyLocale selects the language of messages before a command runs: the
+// "locale" setting if set, otherwise the one LC_ALL, LC_MESSAGES, or LANG
+// names.
+func applyLocale(ctx context.Context) {
+	locale := i18n.Detect()
+	// Outside a repository, or with unreadable settings, use the environment
+	if s, err := LoadEntireSettings(ctx); err == nil && s.Locale != "" {
+		if l, err := i18n.ParseLocale(s.Locale); err == nil {
+			locale = l
+		}
+	}
+	i18n.SetLocale(locale)
+}
+
+// ErrorMessage returns err's message for display in the current locale.
+// Errors are created in English, so they can be matched in any locale, and
+// the known ones are translated here.
+func ErrorMessage(err error) string {
+	msg := err.Error()
+	if errors.Is(err, paths.ErrNotGitRepository) {
+		msg = strings.Replace(msg, paths.ErrNotGitRepository.Error(), i18n.T(i18n.NotGitRepository), 1)
+	}
+	return msg
+}
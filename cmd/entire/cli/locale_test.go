@@ -0,0 +1,53 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/entireio/cli/cmd/entire/cli/i18n"
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+)
+
+func TestApplyLocale(t *testing.T) {
+	setupExecTestRepo(t)
+	t.Cleanup(func() { i18n.SetLocale(i18n.English) })
+	t.Setenv("LC_ALL", "")
+	t.Setenv("LC_MESSAGES", "")
+	t.Setenv("LANG", "ja_JP.UTF-8")
+	ctx := context.Background()
+
+	applyLocale(ctx)
+	if got := i18n.Current(); got != i18n.Japanese {
+		t.Errorf("locale from LANG = %q, want %q", got, i18n.Japanese)
+	}
+
+	writeSettings(t, `{"enabled": true, "locale": "zh"}`)
+	applyLocale(ctx)
+	if got := i18n.Current(); got != i18n.Chinese {
+		t.Errorf("locale from settings = %q, want %q", got, i18n.Chinese)
+	}
+
+	var out bytes.Buffer
+	if err := runLog(ctx, &out, logOptions{Limit: defaultLogLimit}); err != nil {
+		t.Fatalf("runLog() error = %v", err)
+	}
+	if got, want := out.String(), "此分支上没有找到检查点。\n"; got != want {
+		t.Errorf("runLog() = %q, want %q", got, want)
+	}
+}
+
+func TestErrorMessage(t *testing.T) {
+	t.Cleanup(func() { i18n.SetLocale(i18n.English) })
+	err := fmt.Errorf("failed to install hooks: %w", paths.ErrNotGitRepository)
+
+	i18n.SetLocale(i18n.Japanese)
+	if got, want := ErrorMessage(err), "failed to install hooks: git リポジトリではありません"; got != want {
+		t.Errorf("ErrorMessage() = %q, want %q", got, want)
+	}
+	i18n.SetLocale(i18n.English)
+	if got, want := ErrorMessage(err), err.Error(); got != want {
+		t.Errorf("ErrorMessage() = %q, want %q", got, want)
+	}
+}
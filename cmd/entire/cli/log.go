@@ -0,0 +1,215 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+	"github.com/entireio/cli/cmd/entire/cli/classify"
+	"github.com/entireio/cli/cmd/entire/cli/i18n"
+	"github.com/entireio/cli/cmd/entire/cli/pathfilter"
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+	"github.com/entireio/cli/cmd/entire/cli/strategy"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/spf13/cobra"
+)
+
+// defaultLogLimit caps the number of checkpoints shown by `entire log`.
+const defaultLogLimit = 20
+
+// logEntry is one checkpoint-linked commit in `entire log` output.
+type logEntry struct {
+	CommitHash   string
+	CheckpointID id.CheckpointID
+	Subject      string
+	Author       string
+	When         string
+	Label        string
+	Stats        *checkpoint.DiffStats
+}
+
+// logOptions controls which checkpoints `entire log` shows.
+type logOptions struct {
+	Limit  int
+	Filter *pathfilter.Filter
+	Type   classify.Label // empty shows every type
+	Model  string         // model name prefix; empty shows every model
+	Stat   bool           // show stored diff stats
+}
+
+func newLogCmd() *cobra.Command {
+	var limitFlag int
+	var pathFlags []string
+	var typeFlag string
+	var statFlag bool
+	var modelFlag string
+
+	cmd := &cobra.Command{
+		Use:   "log",
+		Short: "List checkpoints on the current branch",
+		Long: `Log lists commits on the current branch that are linked to a checkpoint,
+newest first.
+
+Use --path to only show checkpoints whose commits touched matching files.
+Patterns are relative to the repository root and may use * and ? within a
+path segment and ** across segments. A pattern without wildcards selects a
+directory and everything below it, which is handy in monorepos:
+
+  entire log --path services/billing
+  entire log --path 'web/**/*.tsx' --path shared/ui
+
+Use --type to only show checkpoints with a given label. Each checkpoint is
+labelled feat, fix, refactor, test, or docs from its prompts and changed files
+when it is committed:
+
+  entire log --type fix
+
+Use --model to only show checkpoints produced by a model. The model is read
+from the agent's hook payload or transcript when the checkpoint is written.
+A prefix matches dated model versions:
+
+  entire log --model claude-sonnet-4-5
+
+Use --stat to show lines added and removed per checkpoint. Stats are stored
+when a checkpoint is written; run 'entire migrate --compute-stats' to add them
+to older checkpoints.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := cmd.Context()
+			if _, err := paths.WorktreeRoot(ctx); err != nil {
+				return paths.ErrNotGitRepository
+			}
+			filter, err := pathfilter.New(pathFlags)
+			if err != nil {
+				return err //nolint:wrapcheck // already descriptive
+			}
+			if typeFlag != "" && !classify.IsValid(typeFlag) {
+				return fmt.Errorf("invalid --type %q: must be one of %s", typeFlag, joinLabels(classify.Labels))
+			}
+			return runLog(ctx, cmd.OutOrStdout(), logOptions{Limit: limitFlag, Filter: filter, Type: classify.Label(typeFlag), Model: modelFlag, Stat: statFlag})
+		},
+	}
+
+	cmd.Flags().IntVarP(&limitFlag, "limit", "n", defaultLogLimit, "Maximum number of checkpoints to show (0 for all)")
+	addPathFlag(cmd, &pathFlags, "Only show checkpoints that touched paths matching this glob")
+	cmd.Flags().BoolVar(&statFlag, "stat", false, "Show lines added and removed per checkpoint")
+	cmd.Flags().StringVar(&modelFlag, "model", "", "Only show checkpoints produced by models with this name prefix")
+	cmd.Flags().StringVar(&typeFlag, "type", "", "Only show checkpoints with this label ("+joinLabels(classify.Labels)+")")
+
+	return cmd
+}
+
+func runLog(ctx context.Context, w io.Writer, opts logOptions) error {
+	repo, err := openRepository(ctx)
+	if err != nil {
+		return err
+	}
+
+	entries, err := collectLogEntries(ctx, repo, opts)
+	if err != nil {
+		return err
+	}
+
+	if len(entries) == 0 {
+		fmt.Fprintln(w, i18n.T(i18n.LogNoCheckpoints))
+		return nil
+	}
+	for _, e := range entries {
+		line := fmt.Sprintf("%s  %s", e.CommitHash[:7], e.CheckpointID)
+		if opts.Type != "" {
+			line += fmt.Sprintf("  %-8s", e.Label)
+		}
+		line += fmt.Sprintf("  %s  %-16s  %s", e.When, e.Author, e.Subject)
+		if opts.Stat {
+			line += "  " + formatDiffStats(e.Stats)
+		}
+		fmt.Fprintln(w, line)
+	}
+	return nil
+}
+
+// collectLogEntries walks first-parent history from HEAD and returns commits
+// carrying an Entire-Checkpoint trailer. The path filter runs on the commit
+// tree diff, so commits outside the selected paths are skipped without
+// touching the metadata branch. The metadata branch is only read when
+// filtering by type or model, or showing stats.
+func collectLogEntries(ctx context.Context, repo *git.Repository, opts logOptions) ([]logEntry, error) {
+	head, err := repo.Head()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get HEAD: %w", err)
+	}
+
+	var infos map[id.CheckpointID]checkpoint.CommittedInfo
+	if opts.Type != "" || opts.Model != "" || opts.Stat {
+		store, err := strategy.CommittedStore(ctx, checkpoint.NewGitStore(repo))
+		if err != nil {
+			return nil, err //nolint:wrapcheck // already names the setting
+		}
+		committed, err := store.ListCommitted(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list checkpoints: %w", err)
+		}
+		infos = make(map[id.CheckpointID]checkpoint.CommittedInfo, len(committed))
+		for _, info := range committed {
+			infos[info.CheckpointID] = info
+		}
+	}
+
+	var entries []logEntry
+	err = walkFirstParentCommits(ctx, repo, head.Hash(), 0, func(c *object.Commit) error {
+		cpID, touched, err := commitCheckpointTouches(opts.Filter, c)
+		if err != nil || !touched {
+			return err
+		}
+		info := infos[cpID]
+		if opts.Type != "" && info.Label != string(opts.Type) {
+			return nil
+		}
+		if opts.Model != "" && !strings.HasPrefix(info.Model, opts.Model) {
+			return nil
+		}
+
+		entries = append(entries, logEntry{
+			CommitHash:   c.Hash.String(),
+			CheckpointID: cpID,
+			Subject:      strings.SplitN(strings.TrimSpace(c.Message), "\n", 2)[0],
+			Author:       c.Author.Name,
+			When:         c.Author.When.Local().Format("2006-01-02 15:04"),
+			Label:        info.Label,
+			Stats:        info.DiffStats,
+		})
+		if opts.Limit > 0 && len(entries) >= opts.Limit {
+			return errStopIteration
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func joinLabels(labels []classify.Label) string {
+	names := make([]string, len(labels))
+	for i, l := range labels {
+		names[i] = string(l)
+	}
+	return strings.Join(names, ", ")
+}
+
+// formatDiffStats renders stats like "+12 -3 (2 files)", or "-" when unknown.
+func formatDiffStats(stats *checkpoint.DiffStats) string {
+	if stats == nil {
+		return "-"
+	}
+	files := "files"
+	if stats.FilesChanged == 1 {
+		files = "file"
+	}
+	return fmt.Sprintf("+%d -%d (%d %s)", stats.Insertions, stats.Deletions, stats.FilesChanged, files)
+}
@@ -0,0 +1,186 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+	"github.com/entireio/cli/cmd/entire/cli/classify"
+	"github.com/entireio/cli/cmd/entire/cli/pathfilter"
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+	"github.com/entireio/cli/cmd/entire/cli/testutil"
+	"github.com/entireio/cli/cmd/entire/cli/trailers"
+)
+
+// setupLogTestRepo creates a repo with checkpoint-linked commits in two packages
+// plus one commit without a checkpoint trailer.
+func setupLogTestRepo(t *testing.T) {
+	t.Helper()
+	dir := t.TempDir()
+	testutil.InitRepo(t, dir)
+	t.Chdir(dir)
+	paths.ClearWorktreeRootCache()
+
+	testutil.WriteFile(t, dir, "README.md", "# Test")
+	testutil.GitAdd(t, dir, "README.md")
+	testutil.GitCommit(t, dir, "Initial commit")
+
+	testutil.WriteFile(t, dir, "services/api/main.go", "package main")
+	testutil.GitAdd(t, dir, "services/api/main.go")
+	testutil.GitCommit(t, dir, trailers.FormatCheckpoint("Add api", id.MustCheckpointID("a1a1a1a1a1a1")))
+
+	testutil.WriteFile(t, dir, "services/web/app.ts", "export {}")
+	testutil.GitAdd(t, dir, "services/web/app.ts")
+	testutil.GitCommit(t, dir, trailers.FormatCheckpoint("Add web", id.MustCheckpointID("b2b2b2b2b2b2")))
+
+	testutil.WriteFile(t, dir, "services/api/main.go", "package main // manual")
+	testutil.GitAdd(t, dir, "services/api/main.go")
+	testutil.GitCommit(t, dir, "Manual api edit")
+}
+
+// writeLogTestCheckpoints writes the metadata of the checkpoints linked by
+// setupLogTestRepo, each with the prompt "add the service".
+func writeLogTestCheckpoints(t *testing.T) {
+	t.Helper()
+	ctx := context.Background()
+	repo, err := openRepository(ctx)
+	if err != nil {
+		t.Fatalf("openRepository() error = %v", err)
+	}
+	store := checkpoint.NewGitStore(repo)
+	for cpID, file := range map[string]string{"a1a1a1a1a1a1": "services/api/main.go", "b2b2b2b2b2b2": "services/web/app.ts"} {
+		if err := store.WriteCommitted(ctx, checkpoint.WriteCommittedOptions{
+			CheckpointID: id.MustCheckpointID(cpID),
+			SessionID:    "session-" + cpID,
+			Strategy:     "manual-commit",
+			Transcript:   []byte(`{"type":"user"}` + "\n"),
+			Prompts:      []string{"add the service"},
+			FilesTouched: []string{file},
+			AuthorName:   "Test",
+			AuthorEmail:  "test@test.com",
+		}); err != nil {
+			t.Fatalf("WriteCommitted() error = %v", err)
+		}
+	}
+}
+
+func TestRunLog_ListsCheckpointCommits(t *testing.T) {
+	setupLogTestRepo(t)
+
+	var stdout bytes.Buffer
+	if err := runLog(context.Background(), &stdout, logOptions{Limit: defaultLogLimit}); err != nil {
+		t.Fatalf("runLog() error = %v", err)
+	}
+	output := stdout.String()
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 checkpoint lines, got %d:\n%s", len(lines), output)
+	}
+	if !strings.Contains(lines[0], "b2b2b2b2b2b2") || !strings.Contains(lines[1], "a1a1a1a1a1a1") {
+		t.Errorf("expected newest checkpoint first, got:\n%s", output)
+	}
+	if strings.Contains(output, "Manual api edit") {
+		t.Errorf("commit without checkpoint should not be listed:\n%s", output)
+	}
+}
+
+func TestRunLog_PathFilter(t *testing.T) {
+	setupLogTestRepo(t)
+
+	filter, err := pathfilter.New([]string{"services/api"})
+	if err != nil {
+		t.Fatalf("pathfilter.New() error = %v", err)
+	}
+	var stdout bytes.Buffer
+	if err := runLog(context.Background(), &stdout, logOptions{Filter: filter}); err != nil {
+		t.Fatalf("runLog() error = %v", err)
+	}
+	output := stdout.String()
+	if !strings.Contains(output, "a1a1a1a1a1a1") || strings.Contains(output, "b2b2b2b2b2b2") {
+		t.Errorf("expected only the api checkpoint, got:\n%s", output)
+	}
+}
+
+func TestRunLog_Limit(t *testing.T) {
+	setupLogTestRepo(t)
+
+	var stdout bytes.Buffer
+	if err := runLog(context.Background(), &stdout, logOptions{Limit: 1}); err != nil {
+		t.Fatalf("runLog() error = %v", err)
+	}
+	if lines := strings.Split(strings.TrimSpace(stdout.String()), "\n"); len(lines) != 1 {
+		t.Errorf("expected 1 line with --limit 1, got:\n%s", stdout.String())
+	}
+}
+
+func TestRunLog_TypeFilter(t *testing.T) {
+	setupLogTestRepo(t)
+	ctx := context.Background()
+
+	repo, err := openRepository(ctx)
+	if err != nil {
+		t.Fatalf("openRepository() error = %v", err)
+	}
+	store := checkpoint.NewGitStore(repo)
+	for cpID, label := range map[string]classify.Label{"a1a1a1a1a1a1": classify.LabelFix, "b2b2b2b2b2b2": classify.LabelFeat} {
+		if err := store.WriteCommitted(ctx, checkpoint.WriteCommittedOptions{
+			CheckpointID: id.MustCheckpointID(cpID),
+			SessionID:    "session-" + cpID,
+			Strategy:     "manual-commit",
+			Transcript:   []byte(`{"type":"user"}` + "\n"),
+			AuthorName:   "Test",
+			AuthorEmail:  "test@test.com",
+			Label:        string(label),
+		}); err != nil {
+			t.Fatalf("WriteCommitted() error = %v", err)
+		}
+	}
+
+	var stdout bytes.Buffer
+	if err := runLog(ctx, &stdout, logOptions{Type: classify.LabelFix}); err != nil {
+		t.Fatalf("runLog() error = %v", err)
+	}
+	output := stdout.String()
+	if !strings.Contains(output, "a1a1a1a1a1a1") || strings.Contains(output, "b2b2b2b2b2b2") {
+		t.Errorf("expected only the fix checkpoint, got:\n%s", output)
+	}
+	if !strings.Contains(output, "fix") {
+		t.Errorf("expected label in output, got:\n%s", output)
+	}
+}
+
+func TestRunLog_ModelFilter(t *testing.T) {
+	setupLogTestRepo(t)
+	ctx := context.Background()
+
+	repo, err := openRepository(ctx)
+	if err != nil {
+		t.Fatalf("openRepository() error = %v", err)
+	}
+	store := checkpoint.NewGitStore(repo)
+	for cpID, model := range map[string]string{"a1a1a1a1a1a1": "claude-sonnet-4-5-20250929", "b2b2b2b2b2b2": "gpt-5"} {
+		if err := store.WriteCommitted(ctx, checkpoint.WriteCommittedOptions{
+			CheckpointID: id.MustCheckpointID(cpID),
+			SessionID:    "session-" + cpID,
+			Strategy:     "manual-commit",
+			Transcript:   []byte(`{"type":"user"}` + "\n"),
+			AuthorName:   "Test",
+			AuthorEmail:  "test@test.com",
+			Model:        model,
+		}); err != nil {
+			t.Fatalf("WriteCommitted() error = %v", err)
+		}
+	}
+
+	var stdout bytes.Buffer
+	if err := runLog(ctx, &stdout, logOptions{Model: "claude-sonnet-4-5"}); err != nil {
+		t.Fatalf("runLog() error = %v", err)
+	}
+	output := stdout.String()
+	if !strings.Contains(output, "a1a1a1a1a1a1") || strings.Contains(output, "b2b2b2b2b2b2") {
+		t.Errorf("expected only the claude-sonnet-4-5 checkpoint, got:\n%s", output)
+	}
+}
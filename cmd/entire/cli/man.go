@@ -0,0 +1,225 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/entireio/cli/cmd/entire/cli/versioninfo"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+func newManCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:    "man <dir>",
+		Short:  "Generate man pages",
+		Long:   "Writes a section 1 man page per command and a section 7 page per help topic to dir.",
+		Hidden: true,
+		Args:   cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return generateManPages(cmd.Root(), args[0])
+		},
+	}
+}
+
+// generateManPages writes the man pages of root's command tree and of the
+// help topics to dir.
+func generateManPages(root *cobra.Command, dir string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+
+	var commands []*cobra.Command
+	var collect func(*cobra.Command)
+	collect = func(c *cobra.Command) {
+		commands = append(commands, c)
+		for _, sub := range getVisibleCommands(c) {
+			collect(sub)
+		}
+	}
+	collect(root)
+
+	for _, c := range commands {
+		var buf bytes.Buffer
+		writeCommandManPage(&buf, c)
+		if err := writeManFile(dir, manPageName(c)+".1", buf.Bytes()); err != nil {
+			return err
+		}
+	}
+	for i := range helpTopics {
+		var buf bytes.Buffer
+		writeTopicManPage(&buf, root.Name(), &helpTopics[i])
+		if err := writeManFile(dir, root.Name()+"-"+helpTopics[i].Name+".7", buf.Bytes()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeManFile(dir, name string, data []byte) error {
+	if err := os.WriteFile(filepath.Join(dir, name), data, 0o644); err != nil { //nolint:gosec // man pages are world-readable
+		return fmt.Errorf("failed to write man page %s: %w", name, err)
+	}
+	return nil
+}
+
+// manPageName is a command's page name: its path joined by dashes.
+func manPageName(c *cobra.Command) string {
+	return strings.ReplaceAll(c.CommandPath(), " ", "-")
+}
+
+func writeManHeader(w io.Writer, name, section string) {
+	fmt.Fprintf(w, ".TH \"%s\" \"%s\" \"\" \"Entire %s\" \"Entire Manual\"\n",
+		strings.ToUpper(name), section, roffEscape(versioninfo.Version))
+}
+
+func writeCommandManPage(w io.Writer, c *cobra.Command) {
+	name := manPageName(c)
+	writeManHeader(w, name, "1")
+
+	fmt.Fprintln(w, ".SH NAME")
+	fmt.Fprintf(w, "%s \\- %s\n", roffEscape(name), roffEscape(c.Short))
+
+	fmt.Fprintln(w, ".SH SYNOPSIS")
+	fmt.Fprintf(w, ".B %s\n", roffEscape(c.UseLine()))
+
+	description := c.Long
+	if description == "" {
+		description = c.Short
+	}
+	fmt.Fprintln(w, ".SH DESCRIPTION")
+	writeRoffText(w, description)
+
+	writeRoffFlags(w, "OPTIONS", c.NonInheritedFlags())
+	writeRoffFlags(w, "GLOBAL OPTIONS", c.InheritedFlags())
+
+	if subs := getVisibleCommands(c); len(subs) > 0 {
+		fmt.Fprintln(w, ".SH COMMANDS")
+		for _, sub := range subs {
+			fmt.Fprintf(w, ".TP\n\\fB%s\\fR(1)\n%s\n", roffEscape(manPageName(sub)), roffEscape(sub.Short))
+		}
+	}
+
+	var seeAlso []string
+	if c.HasParent() {
+		seeAlso = append(seeAlso, manPageName(c.Parent())+"(1)")
+	}
+	for _, t := range helpTopics {
+		if c.Name() == t.Name || !c.HasParent() {
+			seeAlso = append(seeAlso, c.Root().Name()+"-"+t.Name+"(7)")
+		}
+	}
+	writeRoffSeeAlso(w, seeAlso)
+}
+
+func writeTopicManPage(w io.Writer, rootName string, t *helpTopic) {
+	name := rootName + "-" + t.Name
+	writeManHeader(w, name, "7")
+
+	fmt.Fprintln(w, ".SH NAME")
+	fmt.Fprintf(w, "%s \\- %s\n", roffEscape(name), roffEscape(t.Short))
+	for _, s := range t.Sections {
+		fmt.Fprintf(w, ".SH %s\n", roffEscape(strings.ToUpper(s.Title)))
+		writeRoffText(w, s.Body)
+	}
+}
+
+func writeRoffSeeAlso(w io.Writer, pages []string) {
+	if len(pages) == 0 {
+		return
+	}
+	fmt.Fprintln(w, ".SH SEE ALSO")
+	for i, p := range pages {
+		sep := ","
+		if i == len(pages)-1 {
+			sep = ""
+		}
+		name, section, _ := strings.Cut(strings.TrimSuffix(p, ")"), "(")
+		fmt.Fprintf(w, ".BR %s (%s)%s\n", roffEscape(name), section, sep)
+	}
+}
+
+// writeRoffFlags lists flags as a tagged paragraph each.
+func writeRoffFlags(w io.Writer, title string, flags *pflag.FlagSet) {
+	var lines []string
+	flags.VisitAll(func(f *pflag.Flag) {
+		if f.Hidden {
+			return
+		}
+		tag := "\\fB\\-\\-" + roffEscape(f.Name) + "\\fR"
+		if f.Shorthand != "" && f.ShorthandDeprecated == "" {
+			tag = "\\fB\\-" + roffEscape(f.Shorthand) + "\\fR, " + tag
+		}
+		varname, usage := pflag.UnquoteUsage(f)
+		if varname != "" {
+			tag += " \\fI" + roffEscape(varname) + "\\fR"
+		}
+		if f.DefValue != "" && f.DefValue != "false" && f.DefValue != "[]" && f.DefValue != "0" {
+			usage += fmt.Sprintf(" (default %s)", f.DefValue)
+		}
+		lines = append(lines, ".TP\n"+tag+"\n"+roffEscape(usage)+"\n")
+	})
+	if len(lines) == 0 {
+		return
+	}
+	sort.Strings(lines)
+	fmt.Fprintf(w, ".SH %s\n", title)
+	for _, l := range lines {
+		fmt.Fprint(w, l)
+	}
+}
+
+// writeRoffText writes help text as roff: blank lines separate paragraphs,
+// and lines indented by two or more spaces are shown as written.
+func writeRoffText(w io.Writer, text string) {
+	inExample := false
+	startParagraph := true
+	for _, line := range strings.Split(strings.TrimSpace(text), "\n") {
+		isExample := strings.HasPrefix(line, "  ")
+		switch {
+		case strings.TrimSpace(line) == "":
+			if inExample {
+				fmt.Fprintln(w, ".fi\n.RE")
+				inExample = false
+			}
+			startParagraph = true
+			continue
+		case isExample && !inExample:
+			fmt.Fprintln(w, ".RS 4\n.nf")
+			inExample = true
+		case !isExample && inExample:
+			fmt.Fprintln(w, ".fi\n.RE")
+			inExample = false
+			startParagraph = true
+		}
+		if isExample {
+			fmt.Fprintln(w, roffEscape(strings.TrimPrefix(line, "  ")))
+			continue
+		}
+		if startParagraph {
+			fmt.Fprintln(w, ".PP")
+			startParagraph = false
+		}
+		fmt.Fprintln(w, roffEscape(line))
+	}
+	if inExample {
+		fmt.Fprintln(w, ".fi\n.RE")
+	}
+}
+
+// roffEscape escapes text so roff prints it literally.
+func roffEscape(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\e`)
+	s = strings.ReplaceAll(s, "-", `\-`)
+	// A leading dot or quote would start a request
+	if strings.HasPrefix(s, ".") || strings.HasPrefix(s, "'") {
+		s = `\&` + s
+	}
+	return s
+}
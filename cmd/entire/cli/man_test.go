@@ -0,0 +1,56 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenerateManPages(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	if err := generateManPages(NewRootCmd(), dir); err != nil {
+		t.Fatalf("generateManPages() error = %v", err)
+	}
+
+	for _, name := range []string{"entire.1", "entire-rewind.1", "entire-index-rebuild.1", "entire-strategies.7", "entire-rewind.7"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("missing man page %s: %v", name, err)
+		}
+	}
+	// Hidden commands don't get pages
+	if _, err := os.Stat(filepath.Join(dir, "entire-man.1")); err == nil {
+		t.Error("hidden command man has a man page")
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "entire-rewind.1"))
+	if err != nil {
+		t.Fatalf("failed to read man page: %v", err)
+	}
+	page := string(data)
+	for _, want := range []string{
+		".TH \"ENTIRE-REWIND\" \"1\"",
+		"entire\\-rewind \\- Browse checkpoints and rewind your session",
+		"\\fB\\-\\-abort\\fR",
+		".SH GLOBAL OPTIONS",
+		".BR entire\\-rewind (7)",
+	} {
+		if !strings.Contains(page, want) {
+			t.Errorf("entire-rewind.1 is missing %q", want)
+		}
+	}
+}
+
+func TestWriteRoffText(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	writeRoffText(&buf, "First paragraph\n.starts with a dot\n\n  entire rewind --abort\n\nLast \\ one")
+	want := ".PP\nFirst paragraph\n\\&.starts with a dot\n.RS 4\n.nf\nentire rewind \\-\\-abort\n.fi\n.RE\n.PP\nLast \\e one\n"
+	if got := buf.String(); got != want {
+		t.Errorf("writeRoffText() =\n%s\nwant\n%s", got, want)
+	}
+}
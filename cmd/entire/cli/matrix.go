@@ -0,0 +1,268 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+	"github.com/entireio/cli/cmd/entire/cli/settings"
+
+	"github.com/spf13/cobra"
+)
+
+// matrixLogsDirName holds the output of matrix runs, in the git common dir.
+const matrixLogsDirName = "entire-matrix"
+
+func newMatrixCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "matrix",
+		Short: "Run a task across several agents and compare the results",
+	}
+
+	cmd.AddCommand(newMatrixRunCmd())
+
+	return cmd
+}
+
+func newMatrixRunCmd() *cobra.Command {
+	var nameFlag string
+	var agentsFlag []string
+	var fromFlag string
+	var timeoutFlag time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "run <task-file>",
+		Short: "Run a task file with each configured agent in parallel",
+		Long: `Run gives the same task to each agent configured under "matrix"."agents"
+in settings, in parallel, each in its own workspace (see 'entire workspace')
+named <run>-<agent>. An agent's command runs in the shell in its workspace
+with the task file on stdin, for example:
+
+  "matrix": {
+    "agents": [
+      {"name": "opus", "command": "claude -p --model opus"},
+      {"name": "sonnet", "command": "claude -p --model sonnet"}
+    ],
+    "test_command": "go test ./..."
+  }
+
+When an agent finishes, its changes are committed on the workspace branch,
+so Entire's hooks checkpoint its session, and "test_command" runs in the
+workspace. Run then prints the workspaces side by side, as
+'entire workspace compare' does, with each agent's exit status, duration,
+and test result. The output of each agent is kept in the git directory.
+
+The run name defaults to the task file's name. Remove the workspaces with
+'entire workspace remove' when done.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			if _, err := paths.WorktreeRoot(ctx); err != nil {
+				return paths.ErrNotGitRepository
+			}
+			if timeoutFlag < 0 {
+				return errors.New("--timeout must not be negative")
+			}
+			return runMatrix(ctx, cmd.OutOrStdout(), matrixOptions{
+				taskFile: args[0],
+				name:     nameFlag,
+				agents:   agentsFlag,
+				from:     fromFlag,
+				timeout:  timeoutFlag,
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&nameFlag, "name", "", "Name of the run, prefixed to its workspaces (default: the task file's name)")
+	cmd.Flags().StringSliceVar(&agentsFlag, "agent", nil, "Only run these configured agents (repeatable)")
+	cmd.Flags().StringVar(&fromFlag, "from", "HEAD", "Commit or branch the workspaces start from")
+	cmd.Flags().DurationVar(&timeoutFlag, "timeout", 0, "Stop agents still running after this long (e.g. 30m)")
+
+	return cmd
+}
+
+type matrixOptions struct {
+	taskFile string
+	name     string
+	agents   []string // only these agents, by name; all when empty
+	from     string
+	timeout  time.Duration
+}
+
+// matrixResult is the outcome of one agent's run.
+type matrixResult struct {
+	status   string // exit status of the agent's command
+	duration time.Duration
+	tests    string // result of the test command
+}
+
+func runMatrix(ctx context.Context, w io.Writer, opts matrixOptions) error {
+	s, err := LoadEntireSettings(ctx)
+	if err != nil {
+		return err
+	}
+	if s.Matrix == nil || len(s.Matrix.Agents) == 0 {
+		return errors.New(`no matrix agents configured: add "matrix"."agents" to .entire/settings.json`)
+	}
+	agents := s.Matrix.Agents
+	if len(opts.agents) > 0 {
+		agents = nil
+		for _, name := range opts.agents {
+			i := slices.IndexFunc(s.Matrix.Agents, func(a settings.MatrixAgent) bool { return a.Name == name })
+			if i < 0 {
+				return fmt.Errorf("no matrix agent named %s", name)
+			}
+			agents = append(agents, s.Matrix.Agents[i])
+		}
+	}
+
+	task, err := os.ReadFile(opts.taskFile)
+	if err != nil {
+		return fmt.Errorf("failed to read task file: %w", err)
+	}
+	name := opts.name
+	if name == "" {
+		name = strings.TrimSuffix(filepath.Base(opts.taskFile), filepath.Ext(opts.taskFile))
+	}
+
+	commonDir, err := workspaceCommonDir(ctx)
+	if err != nil {
+		return err
+	}
+	logsDir := filepath.Join(commonDir, matrixLogsDirName, name)
+	if err := os.MkdirAll(logsDir, 0o750); err != nil {
+		return fmt.Errorf("failed to create logs directory: %w", err)
+	}
+
+	// Worktrees are created one at a time: git and the registry aren't safe to update concurrently
+	workspaces := make([]workspace, len(agents))
+	for i, agent := range agents {
+		ws, err := createWorkspace(ctx, name+"-"+agent.Name, opts.from, "")
+		if err != nil {
+			return err
+		}
+		workspaces[i] = ws
+		fmt.Fprintf(w, "Created workspace %s at %s\n", ws.Name, ws.Path)
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	results := make([]matrixResult, len(agents))
+	for i, agent := range agents {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			logPath := filepath.Join(logsDir, agent.Name+".log")
+			results[i] = runMatrixAgent(ctx, workspaces[i], agent, task, s.Matrix.TestCommand, opts.timeout, logPath)
+			mu.Lock()
+			defer mu.Unlock()
+			fmt.Fprintf(w, "%s: %s after %s\n", agent.Name, results[i].status, results[i].duration.Round(time.Second))
+		}()
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return err //nolint:wrapcheck // Propagating context cancellation
+	}
+
+	reports, err := compareWorkspaces(ctx, workspaces)
+	if err != nil {
+		return err
+	}
+	status := comparisonRow{label: "Exit"}
+	duration := comparisonRow{label: "Duration"}
+	tests := comparisonRow{label: "Test command"}
+	for _, result := range results {
+		status.cells = append(status.cells, result.status)
+		duration.cells = append(duration.cells, result.duration.Round(time.Second).String())
+		tests.cells = append(tests.cells, orDash(result.tests))
+	}
+	fmt.Fprintln(w)
+	writeWorkspaceComparison(w, reports, status, duration, tests)
+	fmt.Fprintf(w, "\nOutput of each agent: %s\n", logsDir)
+	return nil
+}
+
+// runMatrixAgent runs an agent's command in its workspace with the task on
+// stdin, commits what it changed, and runs the test command. The output of
+// all three goes to logPath.
+func runMatrixAgent(ctx context.Context, ws workspace, agent settings.MatrixAgent, task []byte, testCommand string, timeout time.Duration, logPath string) matrixResult {
+	var result matrixResult
+	logFile, err := os.Create(logPath) //nolint:gosec // path is built from the git dir and configured names
+	if err != nil {
+		result.status = fmt.Sprintf("failed to create log: %v", err)
+		return result
+	}
+	defer logFile.Close()
+
+	agentCtx := ctx
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		agentCtx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	start := time.Now()
+	result.status = runMatrixShell(agentCtx, ws.Path, agent.Command, bytes.NewReader(task), logFile)
+	result.duration = time.Since(start)
+	if errors.Is(agentCtx.Err(), context.DeadlineExceeded) {
+		result.status = "timed out"
+	}
+	if ctx.Err() != nil {
+		return result
+	}
+
+	// Committing on the workspace branch lets Entire's hooks condense the agent's session
+	if err := commitMatrixChanges(ctx, ws, agent.Name, logFile); err != nil {
+		fmt.Fprintf(logFile, "\nfailed to commit changes: %v\n", err)
+	}
+
+	if testCommand != "" {
+		fmt.Fprintf(logFile, "\n$ %s\n", testCommand)
+		if status := runMatrixShell(ctx, ws.Path, testCommand, nil, logFile); status == "exit 0" {
+			result.tests = "passed"
+		} else {
+			result.tests = "failed (" + status + ")"
+		}
+	}
+	return result
+}
+
+// runMatrixShell runs command in the shell in dir and describes its exit status.
+func runMatrixShell(ctx context.Context, dir, command string, stdin io.Reader, out io.Writer) string {
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Dir = dir
+	cmd.Stdin = stdin
+	cmd.Stdout = out
+	cmd.Stderr = out
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() >= 0 {
+			return "exit " + strconv.Itoa(exitErr.ExitCode())
+		}
+		return err.Error()
+	}
+	return "exit 0"
+}
+
+// commitMatrixChanges commits all changes in the workspace, if any.
+func commitMatrixChanges(ctx context.Context, ws workspace, agentName string, out io.Writer) error {
+	if _, err := gitOutput(ctx, ws.Path, "add", "-A"); err != nil {
+		return err
+	}
+	if _, err := gitOutput(ctx, ws.Path, "diff", "--cached", "--quiet"); err == nil {
+		return nil
+	}
+	output, err := gitOutput(ctx, ws.Path, "commit", "-m", "Matrix run "+ws.Name+" ("+agentName+")")
+	fmt.Fprintf(out, "\n%s\n", output)
+	return err
+}
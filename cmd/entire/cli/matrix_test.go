@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunMatrix(t *testing.T) {
+	setupCleanTestRepo(t)
+	ctx := context.Background()
+	for _, key := range []string{"GIT_AUTHOR_NAME", "GIT_COMMITTER_NAME"} {
+		t.Setenv(key, "test")
+	}
+	for _, key := range []string{"GIT_AUTHOR_EMAIL", "GIT_COMMITTER_EMAIL"} {
+		t.Setenv(key, "test@test.com")
+	}
+
+	var stdout bytes.Buffer
+	if err := runMatrix(ctx, &stdout, matrixOptions{taskFile: "task.md", from: "HEAD"}); err == nil {
+		t.Error("runMatrix() without configured agents succeeded, want an error")
+	}
+
+	if err := os.MkdirAll(".entire", 0o755); err != nil {
+		t.Fatal(err)
+	}
+	settingsJSON := `{"enabled": true, "matrix": {"agents": [
+		{"name": "writer", "command": "cat > answer.md"},
+		{"name": "idle", "command": "exit 3"}
+	], "test_command": "test -s answer.md"}}`
+	if err := os.WriteFile(filepath.Join(".entire", "settings.json"), []byte(settingsJSON), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	taskFile := filepath.Join(dir, "fix-bug.md")
+	if err := os.WriteFile(taskFile, []byte("Fix the bug.\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := runMatrix(ctx, &stdout, matrixOptions{taskFile: taskFile, from: "HEAD", agents: []string{"missing"}}); err == nil {
+		t.Error("runMatrix() with an unknown agent succeeded, want an error")
+	}
+
+	stdout.Reset()
+	if err := runMatrix(ctx, &stdout, matrixOptions{taskFile: taskFile, from: "HEAD"}); err != nil {
+		t.Fatalf("runMatrix() error = %v", err)
+	}
+	rows := make(map[string][]string)
+	for _, line := range strings.Split(stdout.String(), "\n") {
+		if label, rest, ok := strings.Cut(line, "  "); ok {
+			rows[label] = strings.Fields(rest)
+		}
+	}
+	if got := strings.Join(rows["Workspace"], " "); got != "fix-bug-writer fix-bug-idle" {
+		t.Errorf("Workspace row = %q, want a workspace per agent\n%s", got, stdout.String())
+	}
+	if got := strings.Join(rows["Exit"], " "); got != "exit 0 exit 3" {
+		t.Errorf("Exit row = %q\n%s", got, stdout.String())
+	}
+	if got := strings.Join(rows["Lines"], " "); got != "+1 -0 +0 -0" {
+		t.Errorf("Lines row = %q\n%s", got, stdout.String())
+	}
+	if got := strings.Join(rows["Test command"], " "); got != "passed failed (exit 1)" {
+		t.Errorf("Test command row = %q\n%s", got, stdout.String())
+	}
+
+	// The writer's answer was committed on its workspace branch
+	if out, err := gitOutput(ctx, "", "show", "workspace/fix-bug-writer:answer.md"); err != nil || out != "Fix the bug." {
+		t.Errorf("answer.md on the workspace branch = %q, %v", out, err)
+	}
+}
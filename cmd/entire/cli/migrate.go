@@ -0,0 +1,182 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+	"github.com/entireio/cli/cmd/entire/cli/strategy"
+	"github.com/entireio/cli/cmd/entire/cli/trailers"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/spf13/cobra"
+)
+
+func newMigrateCmd() *cobra.Command {
+	var computeStatsFlag bool
+
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Upgrade checkpoint metadata written by older versions",
+		Long: `Migrate upgrades checkpoint metadata on entire/checkpoints/v1 in place, in
+one commit on the branch.
+
+Every checkpoint's metadata records the schema version it was written with.
+Migrate runs the registered migrations on metadata from older versions, up
+to the version this CLI writes. Checkpoints written by a newer version of
+Entire are left unchanged.
+
+  --compute-stats   Also store insertions, deletions, and files changed for
+                    checkpoints that don't have them yet. The stats are taken
+                    from the commit carrying each checkpoint's trailer on any
+                    local branch.
+
+Push the metadata branch afterwards to share the upgrade with your team.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := cmd.Context()
+			if _, err := paths.WorktreeRoot(ctx); err != nil {
+				return paths.ErrNotGitRepository
+			}
+			if err := runMigrateSchema(ctx, cmd.OutOrStdout()); err != nil {
+				return err
+			}
+			if !computeStatsFlag {
+				return nil
+			}
+			return runMigrateComputeStats(ctx, cmd.OutOrStdout())
+		},
+	}
+
+	cmd.Flags().BoolVar(&computeStatsFlag, "compute-stats", false, "Backfill diff stats for existing checkpoints")
+
+	return cmd
+}
+
+// runMigrateSchema upgrades the metadata of every committed checkpoint to the
+// current schema version.
+func runMigrateSchema(ctx context.Context, w io.Writer) error {
+	if err := strategy.RequireBranchStore(ctx, "entire migrate"); err != nil {
+		return err //nolint:wrapcheck // already names the operation
+	}
+	repo, err := openRepository(ctx)
+	if err != nil {
+		return err
+	}
+	result, err := checkpoint.NewGitStore(repo).MigrateSchema(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to migrate checkpoint metadata: %w", err)
+	}
+
+	if len(result.Migrated) == 0 {
+		fmt.Fprintf(w, "All checkpoints already use metadata schema version %d.\n", checkpoint.CurrentSchemaVersion)
+	} else {
+		fmt.Fprintf(w, "Migrated %d checkpoint(s) to metadata schema version %d.\n", len(result.Migrated), checkpoint.CurrentSchemaVersion)
+	}
+	if len(result.Newer) > 0 {
+		fmt.Fprintf(w, "%d checkpoint(s) were written by a newer version of Entire and were left unchanged; upgrade the CLI to read them fully.\n", len(result.Newer))
+	}
+	return nil
+}
+
+// runMigrateComputeStats stores diff stats on every committed checkpoint that
+// lacks them and whose linked commit can be found.
+func runMigrateComputeStats(ctx context.Context, w io.Writer) error {
+	if err := strategy.RequireBranchStore(ctx, "entire migrate --compute-stats"); err != nil {
+		return err //nolint:wrapcheck // already names the operation
+	}
+	repo, err := openRepository(ctx)
+	if err != nil {
+		return err
+	}
+	store := checkpoint.NewGitStore(repo)
+
+	committed, err := store.ListCommitted(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list checkpoints: %w", err)
+	}
+	missing := make(map[id.CheckpointID]bool)
+	for _, info := range committed {
+		if info.DiffStats == nil {
+			missing[info.CheckpointID] = true
+		}
+	}
+	if len(missing) == 0 {
+		fmt.Fprintln(w, "All checkpoints already have diff stats.")
+		return nil
+	}
+
+	commits, err := findCheckpointCommits(repo, missing)
+	if err != nil {
+		return err
+	}
+
+	updated := 0
+	for cpID, commit := range commits {
+		stats, err := checkpoint.ComputeCommitDiffStats(commit)
+		if err != nil {
+			return fmt.Errorf("failed to compute stats for checkpoint %s: %w", cpID, err)
+		}
+		if err := store.UpdateDiffStats(ctx, cpID, stats); err != nil {
+			return fmt.Errorf("failed to update checkpoint %s: %w", cpID, err)
+		}
+		updated++
+	}
+
+	fmt.Fprintf(w, "Computed diff stats for %d checkpoint(s).\n", updated)
+	if skipped := len(missing) - updated; skipped > 0 {
+		fmt.Fprintf(w, "%d checkpoint(s) skipped: linked commit not found on any local branch.\n", skipped)
+	}
+	return nil
+}
+
+// findCheckpointCommits maps each wanted checkpoint ID to the commit carrying its
+// trailer, searching every local branch except Entire's own.
+func findCheckpointCommits(repo *git.Repository, wanted map[id.CheckpointID]bool) (map[id.CheckpointID]*object.Commit, error) {
+	branches, err := repo.Branches()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list branches: %w", err)
+	}
+	var heads []plumbing.Hash
+	err = branches.ForEach(func(ref *plumbing.Reference) error {
+		if !strings.HasPrefix(ref.Name().Short(), "entire/") {
+			heads = append(heads, ref.Hash())
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list branches: %w", err)
+	}
+
+	found := make(map[id.CheckpointID]*object.Commit)
+	seen := make(map[plumbing.Hash]bool)
+	for _, head := range heads {
+		iter, err := repo.Log(&git.LogOptions{From: head})
+		if err != nil {
+			return nil, fmt.Errorf("failed to walk %s: %w", head, err)
+		}
+		err = iter.ForEach(func(c *object.Commit) error {
+			if seen[c.Hash] {
+				return nil
+			}
+			seen[c.Hash] = true
+			if cpID, ok := trailers.ParseCheckpoint(c.Message); ok && wanted[cpID] {
+				if _, dup := found[cpID]; !dup {
+					found[cpID] = c
+				}
+			}
+			return nil
+		})
+		iter.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to walk %s: %w", head, err)
+		}
+	}
+	return found, nil
+}
@@ -0,0 +1,61 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+)
+
+func TestRunMigrateComputeStats(t *testing.T) {
+	setupLogTestRepo(t)
+	ctx := context.Background()
+
+	repo, err := openRepository(ctx)
+	if err != nil {
+		t.Fatalf("openRepository() error = %v", err)
+	}
+	store := checkpoint.NewGitStore(repo)
+	// a1a1... is linked to a commit; c3c3... has no commit and is skipped
+	for _, cpID := range []string{"a1a1a1a1a1a1", "c3c3c3c3c3c3"} {
+		if err := store.WriteCommitted(ctx, checkpoint.WriteCommittedOptions{
+			CheckpointID: id.MustCheckpointID(cpID),
+			SessionID:    "session-" + cpID,
+			Strategy:     "manual-commit",
+			Transcript:   []byte(`{"type":"user"}` + "\n"),
+			AuthorName:   "Test",
+			AuthorEmail:  "test@test.com",
+		}); err != nil {
+			t.Fatalf("WriteCommitted() error = %v", err)
+		}
+	}
+
+	var stdout bytes.Buffer
+	if err := runMigrateComputeStats(ctx, &stdout); err != nil {
+		t.Fatalf("runMigrateComputeStats() error = %v", err)
+	}
+	if !strings.Contains(stdout.String(), "for 1 checkpoint") || !strings.Contains(stdout.String(), "1 checkpoint(s) skipped") {
+		t.Errorf("unexpected output:\n%s", stdout.String())
+	}
+
+	summary, err := store.ReadCommitted(ctx, id.MustCheckpointID("a1a1a1a1a1a1"))
+	if err != nil {
+		t.Fatalf("ReadCommitted() error = %v", err)
+	}
+	want := checkpoint.DiffStats{FilesChanged: 1, Insertions: 1}
+	if summary.DiffStats == nil || *summary.DiffStats != want {
+		t.Errorf("DiffStats = %+v, want %+v", summary.DiffStats, want)
+	}
+
+	// Log --stat reads the stored stats
+	stdout.Reset()
+	if err := runLog(ctx, &stdout, logOptions{Stat: true}); err != nil {
+		t.Fatalf("runLog() error = %v", err)
+	}
+	if !strings.Contains(stdout.String(), "+1 -0 (1 file)") {
+		t.Errorf("expected stats in log output, got:\n%s", stdout.String())
+	}
+}
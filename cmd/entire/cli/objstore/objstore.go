@@ -0,0 +1,93 @@
+// Package objstore reads and writes objects in a bucket: a local directory,
+// Amazon S3 (or an S3-compatible service), or Google Cloud Storage. It backs
+// checkpoint storage for repositories that keep session content out of git.
+package objstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// ErrNotExist is returned by Get for a key with no object.
+var ErrNotExist = errors.New("object does not exist")
+
+// Bucket is a flat namespace of objects keyed by slash-separated paths.
+type Bucket interface {
+	// Get returns the object's content, or ErrNotExist.
+	Get(ctx context.Context, key string) ([]byte, error)
+
+	// Put creates or replaces the object.
+	Put(ctx context.Context, key string, data []byte) error
+
+	// Delete removes the object. Deleting a missing object is not an error.
+	Delete(ctx context.Context, key string) error
+
+	// List returns the keys starting with prefix, sorted.
+	List(ctx context.Context, prefix string) ([]string, error)
+}
+
+// Config locates a bucket.
+type Config struct {
+	// URL is file:///path, s3://bucket/prefix, or gs://bucket/prefix.
+	URL string
+
+	// Region is the S3 region. Empty uses $AWS_REGION, then us-east-1.
+	Region string
+
+	// Endpoint overrides the service URL, for S3-compatible services such
+	// as MinIO. Buckets are then addressed path-style.
+	Endpoint string
+}
+
+// Open returns the bucket cfg describes.
+func Open(cfg Config) (Bucket, error) {
+	u, err := url.Parse(cfg.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid object store URL %q: %w", cfg.URL, err)
+	}
+	switch u.Scheme {
+	case "file":
+		if u.Host != "" && u.Host != "localhost" {
+			return nil, fmt.Errorf("invalid object store URL %q: file URLs must be local", cfg.URL)
+		}
+		if u.Path == "" {
+			return nil, fmt.Errorf("invalid object store URL %q: missing directory", cfg.URL)
+		}
+		return &fileBucket{root: u.Path}, nil
+	case "s3", "gs":
+		if u.Host == "" {
+			return nil, fmt.Errorf("invalid object store URL %q: missing bucket name", cfg.URL)
+		}
+		return newS3Bucket(u.Scheme, u.Host, cleanPrefix(u.Path), cfg.Region, cfg.Endpoint)
+	default:
+		return nil, fmt.Errorf("invalid object store URL %q: scheme must be file, s3, or gs", cfg.URL)
+	}
+}
+
+// ValidateURL reports whether rawURL names a supported bucket.
+func ValidateURL(rawURL string) error {
+	_, err := Open(Config{URL: rawURL, Region: "us-east-1"})
+	return err
+}
+
+// cleanPrefix turns a URL path into a key prefix: no leading slash, and a
+// trailing one unless empty.
+func cleanPrefix(p string) string {
+	p = strings.Trim(path.Clean("/"+p), "/")
+	if p == "" {
+		return ""
+	}
+	return p + "/"
+}
+
+// validKey rejects keys that could escape the bucket's prefix or root.
+func validKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || path.Clean(key) != key || key == ".." || strings.HasPrefix(key, "../") {
+		return fmt.Errorf("invalid object key %q", key)
+	}
+	return nil
+}
@@ -0,0 +1,193 @@
+package objstore
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"io"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/entireio/cli/cmd/entire/cli/sigv4"
+)
+
+func TestOpen_URLs(t *testing.T) {
+	t.Parallel()
+
+	for _, rawURL := range []string{"file:///var/entire", "s3://bucket", "s3://bucket/team/repo", "gs://bucket/prefix"} {
+		if err := ValidateURL(rawURL); err != nil {
+			t.Errorf("ValidateURL(%q) error = %v", rawURL, err)
+		}
+	}
+	for _, rawURL := range []string{"", "ftp://host/x", "s3:///prefix", "file://remote/x", "file://"} {
+		if err := ValidateURL(rawURL); err == nil {
+			t.Errorf("ValidateURL(%q) should fail", rawURL)
+		}
+	}
+}
+
+// testBucket exercises the Bucket contract shared by all implementations.
+func testBucket(t *testing.T, b Bucket) {
+	t.Helper()
+	ctx := context.Background()
+
+	if _, err := b.Get(ctx, "a1/b2/metadata.json"); !errors.Is(err, ErrNotExist) {
+		t.Fatalf("Get(missing) error = %v, want ErrNotExist", err)
+	}
+	for key, data := range map[string]string{
+		"a1/b2/metadata.json":   `{"id":1}`,
+		"a1/b2/0/full.jsonl":    "line\n",
+		"c3/d4/metadata.json":   `{"id":2}`,
+		"a1/b2/0/prompt & .txt": "odd name",
+	} {
+		if err := b.Put(ctx, key, []byte(data)); err != nil {
+			t.Fatalf("Put(%s) error = %v", key, err)
+		}
+	}
+	data, err := b.Get(ctx, "a1/b2/0/prompt & .txt")
+	if err != nil || string(data) != "odd name" {
+		t.Fatalf("Get() = %q, %v", data, err)
+	}
+
+	keys, err := b.List(ctx, "a1/")
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	want := []string{"a1/b2/0/full.jsonl", "a1/b2/0/prompt & .txt", "a1/b2/metadata.json"}
+	if !slices.Equal(keys, want) {
+		t.Errorf("List(a1/) = %q, want %q", keys, want)
+	}
+
+	if err := b.Delete(ctx, "a1/b2/0/full.jsonl"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if err := b.Delete(ctx, "a1/b2/0/full.jsonl"); err != nil {
+		t.Fatalf("Delete(missing) error = %v", err)
+	}
+	if _, err := b.Get(ctx, "a1/b2/0/full.jsonl"); !errors.Is(err, ErrNotExist) {
+		t.Errorf("Get(deleted) error = %v, want ErrNotExist", err)
+	}
+
+	if err := b.Put(ctx, "../escape", []byte("x")); err == nil {
+		t.Error("Put() should reject keys outside the bucket")
+	}
+}
+
+func TestFileBucket(t *testing.T) {
+	t.Parallel()
+
+	b, err := Open(Config{URL: "file://" + t.TempDir()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	testBucket(t, b)
+}
+
+// fakeS3 is an in-memory S3 endpoint that checks requests are signed.
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKID/") {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	// Path-style: /<bucket>/<key>
+	key := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, "/bucket"), "/")
+	switch {
+	case r.Method == http.MethodGet && key == "":
+		type content struct {
+			Key string `xml:"Key"`
+		}
+		var result struct {
+			XMLName               xml.Name  `xml:"ListBucketResult"`
+			Contents              []content `xml:"Contents"`
+			IsTruncated           bool      `xml:"IsTruncated"`
+			NextContinuationToken string    `xml:"NextContinuationToken,omitempty"`
+		}
+		var keys []string
+		for k := range f.objects {
+			if strings.HasPrefix(k, r.URL.Query().Get("prefix")) && k > r.URL.Query().Get("continuation-token") {
+				keys = append(keys, k)
+			}
+		}
+		slices.Sort(keys)
+		// One key per page, to exercise continuation
+		if len(keys) > 1 {
+			result.IsTruncated = true
+			result.NextContinuationToken = keys[0]
+			keys = keys[:1]
+		}
+		for _, k := range keys {
+			result.Contents = append(result.Contents, content{Key: k})
+		}
+		_ = xml.NewEncoder(w).Encode(result)
+	case r.Method == http.MethodGet:
+		data, ok := f.objects[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = io.WriteString(w, "<Error><Code>NoSuchKey</Code><Message>missing</Message></Error>")
+			return
+		}
+		_, _ = w.Write(data)
+	case r.Method == http.MethodPut:
+		data, _ := io.ReadAll(r.Body)
+		f.objects[key] = data
+	case r.Method == http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func TestS3Bucket(t *testing.T) {
+	t.Parallel()
+
+	fake := &fakeS3{objects: map[string][]byte{}}
+	srv := httptest.NewServer(fake)
+	t.Cleanup(srv.Close)
+
+	b, err := newS3Bucket("s3", "bucket", "team/", "us-east-1", srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.credentials = func() (sigv4.Credentials, error) {
+		return sigv4.Credentials{AccessKeyID: "AKID", SecretAccessKey: "secret"}, nil
+	}
+	testBucket(t, b)
+
+	if _, ok := fake.objects["team/a1/b2/metadata.json"]; !ok {
+		t.Errorf("objects should be stored under the URL's prefix, got %q", slices.Sorted(maps.Keys(fake.objects)))
+	}
+}
+
+func TestS3Bucket_Addressing(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range []struct {
+		scheme, bucket, endpoint string
+		want                     string
+	}{
+		{"s3", "logs", "", "https://logs.s3.eu-west-1.amazonaws.com/p/k"},
+		{"s3", "my.logs", "", "https://s3.eu-west-1.amazonaws.com/my.logs/p/k"},
+		{"s3", "logs", "http://minio:9000", "http://minio:9000/logs/p/k"},
+		{"gs", "logs", "", "https://storage.googleapis.com/logs/p/k"},
+	} {
+		b, err := newS3Bucket(tt.scheme, tt.bucket, "p/", "eu-west-1", tt.endpoint)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := b.url("k").String(); got != tt.want {
+			t.Errorf("%s://%s url = %s, want %s", tt.scheme, tt.bucket, got, tt.want)
+		}
+	}
+}
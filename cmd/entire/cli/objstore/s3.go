@@ -0,0 +1,254 @@
+package objstore
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/entireio/cli/cmd/entire/cli/sigv4"
+)
+
+// Credential environment variables. Without them, the standard AWS_*
+// variables are used, which also hold GCS HMAC keys for the interoperable
+// XML API.
+const (
+	AccessKeyIDEnv     = "ENTIRE_OBJECT_STORE_ACCESS_KEY_ID"
+	SecretAccessKeyEnv = "ENTIRE_OBJECT_STORE_SECRET_ACCESS_KEY"
+	SessionTokenEnv    = "ENTIRE_OBJECT_STORE_SESSION_TOKEN"
+)
+
+// requestTimeout bounds a single object request.
+const requestTimeout = 2 * time.Minute
+
+func credentialsFromEnv() (sigv4.Credentials, error) {
+	c := sigv4.Credentials{
+		AccessKeyID:     os.Getenv(AccessKeyIDEnv),
+		SecretAccessKey: os.Getenv(SecretAccessKeyEnv),
+		SessionToken:    os.Getenv(SessionTokenEnv),
+	}
+	if c.AccessKeyID == "" && c.SecretAccessKey == "" {
+		c = sigv4.CredentialsFromEnv()
+	}
+	if c.AccessKeyID == "" || c.SecretAccessKey == "" {
+		return sigv4.Credentials{}, fmt.Errorf("no object store credentials: set %s and %s, or AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY", AccessKeyIDEnv, SecretAccessKeyEnv)
+	}
+	return c, nil
+}
+
+// s3Bucket talks to the S3 REST API, signed with AWS Signature Version 4.
+// Google Cloud Storage serves the same API for HMAC keys.
+type s3Bucket struct {
+	client    *http.Client
+	endpoint  *url.URL
+	bucket    string
+	prefix    string
+	region    string
+	pathStyle bool
+
+	// credentials is replaceable in tests
+	credentials func() (sigv4.Credentials, error)
+	now         func() time.Time
+}
+
+func newS3Bucket(scheme, bucket, prefix, region, endpoint string) (*s3Bucket, error) {
+	b := &s3Bucket{
+		client:      &http.Client{Timeout: requestTimeout},
+		bucket:      bucket,
+		prefix:      prefix,
+		region:      region,
+		credentials: credentialsFromEnv,
+		now:         time.Now,
+	}
+	if b.region == "" {
+		switch {
+		case scheme == "gs":
+			b.region = "auto"
+		case os.Getenv("AWS_REGION") != "":
+			b.region = os.Getenv("AWS_REGION")
+		case os.Getenv("AWS_DEFAULT_REGION") != "":
+			b.region = os.Getenv("AWS_DEFAULT_REGION")
+		default:
+			b.region = "us-east-1"
+		}
+	}
+
+	switch {
+	case endpoint != "":
+		u, err := url.Parse(endpoint)
+		if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return nil, fmt.Errorf("invalid object store endpoint %q", endpoint)
+		}
+		b.endpoint = &url.URL{Scheme: u.Scheme, Host: u.Host, Path: strings.TrimSuffix(u.Path, "/")}
+		b.pathStyle = true
+	case scheme == "gs":
+		b.endpoint = &url.URL{Scheme: "https", Host: "storage.googleapis.com"}
+		b.pathStyle = true
+	case strings.Contains(bucket, "."):
+		// Dotted names don't match the wildcard certificate of virtual hosts
+		b.endpoint = &url.URL{Scheme: "https", Host: "s3." + b.region + ".amazonaws.com"}
+		b.pathStyle = true
+	default:
+		b.endpoint = &url.URL{Scheme: "https", Host: bucket + ".s3." + b.region + ".amazonaws.com"}
+	}
+	return b, nil
+}
+
+// url returns the URL of the object at key, or of the bucket if key is "".
+func (b *s3Bucket) url(key string) *url.URL {
+	p := b.endpoint.Path
+	if b.pathStyle {
+		p += "/" + b.bucket
+	}
+	if key != "" {
+		p += "/" + b.prefix + key
+	} else if p == "" {
+		p = "/"
+	}
+	return &url.URL{Scheme: b.endpoint.Scheme, Host: b.endpoint.Host, Path: p, RawPath: sigv4.URIEncode(p, false)}
+}
+
+func (b *s3Bucket) Get(ctx context.Context, key string) ([]byte, error) {
+	if err := validKey(key); err != nil {
+		return nil, err
+	}
+	resp, err := b.do(ctx, http.MethodGet, b.url(key), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("%s: %w", key, ErrNotExist)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, responseError(resp, "get", key)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read object %s: %w", key, err)
+	}
+	return data, nil
+}
+
+func (b *s3Bucket) Put(ctx context.Context, key string, data []byte) error {
+	if err := validKey(key); err != nil {
+		return err
+	}
+	resp, err := b.do(ctx, http.MethodPut, b.url(key), data)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return responseError(resp, "put", key)
+	}
+	return nil
+}
+
+func (b *s3Bucket) Delete(ctx context.Context, key string) error {
+	if err := validKey(key); err != nil {
+		return err
+	}
+	resp, err := b.do(ctx, http.MethodDelete, b.url(key), nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		return responseError(resp, "delete", key)
+	}
+	return nil
+}
+
+type listBucketResult struct {
+	Contents []struct {
+		Key string `xml:"Key"`
+	} `xml:"Contents"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+func (b *s3Bucket) List(ctx context.Context, prefix string) ([]string, error) {
+	var keys []string
+	token := ""
+	for {
+		u := b.url("")
+		q := url.Values{"list-type": {"2"}, "prefix": {b.prefix + prefix}}
+		if token != "" {
+			q.Set("continuation-token", token)
+		}
+		u.RawQuery = q.Encode()
+
+		resp, err := b.do(ctx, http.MethodGet, u, nil)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusOK {
+			err := responseError(resp, "list", prefix)
+			_ = resp.Body.Close()
+			return nil, err
+		}
+		var result listBucketResult
+		err = xml.NewDecoder(resp.Body).Decode(&result)
+		_ = resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse object list: %w", err)
+		}
+		for _, c := range result.Contents {
+			keys = append(keys, strings.TrimPrefix(c.Key, b.prefix))
+		}
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			break
+		}
+		token = result.NextContinuationToken
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+func (b *s3Bucket) do(ctx context.Context, method string, u *url.URL, body []byte) (*http.Response, error) {
+	creds, err := b.credentials()
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build object store request: %w", err)
+	}
+	req.ContentLength = int64(len(body))
+	payloadHash := sigv4.EmptyPayloadHash
+	if len(body) > 0 {
+		sum := sha256.Sum256(body)
+		payloadHash = hex.EncodeToString(sum[:])
+	}
+	sigv4.SignRequest(req, payloadHash, creds, b.region, b.now())
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("object store request failed: %w", err)
+	}
+	return resp, nil
+}
+
+type errorResponse struct {
+	Code    string `xml:"Code"`
+	Message string `xml:"Message"`
+}
+
+func responseError(resp *http.Response, op, key string) error {
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10)) //nolint:errcheck // the status is reported either way
+	var e errorResponse
+	if xml.Unmarshal(data, &e) == nil && e.Code != "" {
+		return fmt.Errorf("object store %s %s: %s: %s", op, key, e.Code, e.Message)
+	}
+	return fmt.Errorf("object store %s %s: %s", op, key, resp.Status)
+}
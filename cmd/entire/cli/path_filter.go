@@ -0,0 +1,60 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+	"github.com/entireio/cli/cmd/entire/cli/pathfilter"
+	"github.com/entireio/cli/cmd/entire/cli/trailers"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/spf13/cobra"
+)
+
+// addPathFlag registers the repeatable --path flag shared by commands that
+// list checkpoints.
+func addPathFlag(cmd *cobra.Command, pathFlags *[]string, usage string) {
+	cmd.Flags().StringArrayVar(pathFlags, "path", nil, usage+" (repeatable)")
+}
+
+// commitCheckpointTouches returns the checkpoint linked to c, and whether c
+// changed paths matching filter. Commits without a checkpoint trailer are
+// never diffed.
+func commitCheckpointTouches(filter *pathfilter.Filter, c *object.Commit) (id.CheckpointID, bool, error) {
+	cpID, ok := trailers.ParseCheckpoint(c.Message)
+	if !ok {
+		return "", false, nil
+	}
+	touched, err := filter.CommitTouches(c)
+	if err != nil {
+		return "", false, err //nolint:wrapcheck // already wrapped by pathfilter
+	}
+	return cpID, touched, nil
+}
+
+// touchedCheckpoints returns the checkpoints whose commits on the current
+// branch changed paths matching filter. A nil filter returns nil, meaning
+// every checkpoint is kept.
+func touchedCheckpoints(ctx context.Context, repo *git.Repository, filter *pathfilter.Filter) (map[id.CheckpointID]bool, error) {
+	if filter == nil {
+		return nil, nil //nolint:nilnil // nil set means "no filter"
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get HEAD: %w", err)
+	}
+	touched := make(map[id.CheckpointID]bool)
+	err = walkFirstParentCommits(ctx, repo, head.Hash(), 0, func(c *object.Commit) error {
+		cpID, ok, err := commitCheckpointTouches(filter, c)
+		if ok {
+			touched[cpID] = true
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return touched, nil
+}
@@ -0,0 +1,209 @@
+// Package pathfilter restricts checkpoint listings to commits that touched
+// particular paths, e.g. one package in a monorepo.
+//
+// Matching a commit compares tree hashes at each pattern's literal directory
+// prefix first. When the subtree is unchanged between a commit and its parent
+// the commit is rejected without diffing or reading checkpoint metadata, so
+// filtering stays cheap on large histories.
+package pathfilter
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// Filter matches repository-relative paths against a set of glob patterns.
+// A nil *Filter matches everything.
+type Filter struct {
+	patterns []pattern
+}
+
+type pattern struct {
+	prefix string // literal directory prefix ("" for repo root)
+	re     *regexp.Regexp
+}
+
+// New compiles glob patterns. Supported syntax: "*" and "?" within a path
+// segment, "**" across segments. A pattern without wildcards matches the path
+// itself and everything below it, so "services/api" selects a whole package.
+// Returns nil when no patterns are given.
+func New(globs []string) (*Filter, error) {
+	if len(globs) == 0 {
+		return nil, nil //nolint:nilnil // nil filter means "match everything"
+	}
+	f := &Filter{}
+	for _, glob := range globs {
+		cleaned := strings.Trim(path.Clean(strings.TrimPrefix(glob, "./")), "/")
+		if cleaned == "" || cleaned == "." {
+			return nil, errors.New("empty path filter")
+		}
+		if strings.HasPrefix(cleaned, "..") {
+			return nil, fmt.Errorf("path filter %q escapes the repository", glob)
+		}
+		re, err := regexp.Compile("^" + globToRegexp(cleaned) + "(/.*)?$")
+		if err != nil {
+			return nil, fmt.Errorf("invalid path filter %q: %w", glob, err)
+		}
+		f.patterns = append(f.patterns, pattern{prefix: literalPrefix(cleaned), re: re})
+	}
+	return f, nil
+}
+
+// Match reports whether file (a repo-relative, slash-separated path) matches any pattern.
+func (f *Filter) Match(file string) bool {
+	if f == nil {
+		return true
+	}
+	for _, p := range f.patterns {
+		if p.re.MatchString(file) {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchAny reports whether any of files matches.
+func (f *Filter) MatchAny(files []string) bool {
+	if f == nil {
+		return true
+	}
+	for _, file := range files {
+		if f.Match(file) {
+			return true
+		}
+	}
+	return false
+}
+
+// CommitTouches reports whether commit changed any matching path relative to
+// its first parent (or to the empty tree for a root commit).
+func (f *Filter) CommitTouches(commit *object.Commit) (bool, error) {
+	if f == nil {
+		return true, nil
+	}
+
+	tree, err := commit.Tree()
+	if err != nil {
+		return false, fmt.Errorf("failed to get tree for %s: %w", commit.Hash, err)
+	}
+	var parentTree *object.Tree
+	if commit.NumParents() > 0 {
+		parent, err := commit.Parent(0)
+		if err != nil {
+			return false, fmt.Errorf("failed to get parent of %s: %w", commit.Hash, err)
+		}
+		if parentTree, err = parent.Tree(); err != nil {
+			return false, fmt.Errorf("failed to get tree for %s: %w", parent.Hash, err)
+		}
+	}
+
+	for _, p := range f.patterns {
+		touched, err := p.touches(parentTree, tree)
+		if err != nil {
+			return false, err
+		}
+		if touched {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// touches diffs only the subtree under the pattern's literal prefix.
+func (p pattern) touches(from, to *object.Tree) (bool, error) {
+	fromSub, fromHash := subtree(from, p.prefix)
+	toSub, toHash := subtree(to, p.prefix)
+	if fromHash == toHash {
+		return false, nil // Nothing under the prefix changed
+	}
+	// The prefix itself is a file that changed
+	if (fromSub == nil && fromHash != plumbing.ZeroHash) || (toSub == nil && toHash != plumbing.ZeroHash) {
+		return p.re.MatchString(p.prefix), nil
+	}
+
+	changes, err := object.DiffTree(fromSub, toSub)
+	if err != nil {
+		return false, fmt.Errorf("failed to diff trees: %w", err)
+	}
+	for _, change := range changes {
+		name := change.To.Name
+		if name == "" {
+			name = change.From.Name
+		}
+		if p.prefix != "" {
+			name = p.prefix + "/" + name
+		}
+		if p.re.MatchString(name) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// subtree returns the tree at dir (nil if missing or not a directory) and the
+// hash of the entry at dir (zero if missing).
+func subtree(root *object.Tree, dir string) (*object.Tree, plumbing.Hash) {
+	if root == nil {
+		return nil, plumbing.ZeroHash
+	}
+	if dir == "" {
+		return root, root.Hash
+	}
+	entry, err := root.FindEntry(dir)
+	if err != nil {
+		return nil, plumbing.ZeroHash
+	}
+	if !entry.Mode.IsFile() {
+		if sub, err := root.Tree(dir); err == nil {
+			return sub, entry.Hash
+		}
+	}
+	return nil, entry.Hash
+}
+
+// literalPrefix returns the leading path segments that contain no wildcards.
+// For a pattern without wildcards this is the pattern itself.
+func literalPrefix(glob string) string {
+	idx := strings.IndexAny(glob, "*?")
+	if idx < 0 {
+		return glob
+	}
+	slash := strings.LastIndex(glob[:idx], "/")
+	if slash < 0 {
+		return ""
+	}
+	return glob[:slash]
+}
+
+func globToRegexp(glob string) string {
+	var sb strings.Builder
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+		switch c {
+		case '*':
+			if i+1 < len(glob) && glob[i+1] == '*' {
+				i++
+				// "**/" also matches zero directories
+				if i+1 < len(glob) && glob[i+1] == '/' {
+					i++
+					sb.WriteString("(.*/)?")
+				} else {
+					sb.WriteString(".*")
+				}
+			} else {
+				sb.WriteString("[^/]*")
+			}
+		case '?':
+			sb.WriteString("[^/]")
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	return sb.String()
+}
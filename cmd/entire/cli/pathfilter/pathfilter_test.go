@@ -0,0 +1,141 @@
+package pathfilter
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+func TestFilter_Match(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		pattern string
+		file    string
+		want    bool
+	}{
+		{"services/api", "services/api/main.go", true},
+		{"services/api", "services/api", true},
+		{"services/api", "services/apix/main.go", false},
+		{"./services/api/", "services/api/handler/h.go", true},
+		{"web/*.tsx", "web/App.tsx", true},
+		{"web/*.tsx", "web/components/Button.tsx", false},
+		{"web/**/*.tsx", "web/components/Button.tsx", true},
+		{"web/**/*.tsx", "web/App.tsx", true},
+		{"**/go.mod", "tools/lint/go.mod", true},
+		{"**/go.mod", "go.mod", true},
+		{"file?.txt", "file1.txt", true},
+		{"file?.txt", "file10.txt", false},
+	}
+	for _, tt := range tests {
+		f, err := New([]string{tt.pattern})
+		if err != nil {
+			t.Fatalf("New(%q) error = %v", tt.pattern, err)
+		}
+		if got := f.Match(tt.file); got != tt.want {
+			t.Errorf("pattern %q Match(%q) = %v, want %v", tt.pattern, tt.file, got, tt.want)
+		}
+	}
+}
+
+func TestNew_RejectsInvalidPatterns(t *testing.T) {
+	t.Parallel()
+	for _, pattern := range []string{"", ".", "../outside"} {
+		if _, err := New([]string{pattern}); err == nil {
+			t.Errorf("New(%q) should fail", pattern)
+		}
+	}
+}
+
+func TestNilFilter_MatchesEverything(t *testing.T) {
+	t.Parallel()
+	f, err := New(nil)
+	if err != nil {
+		t.Fatalf("New(nil) error = %v", err)
+	}
+	if !f.Match("anything") || !f.MatchAny([]string{"x"}) {
+		t.Error("nil filter should match everything")
+	}
+	touched, err := f.CommitTouches(nil)
+	if err != nil || !touched {
+		t.Errorf("nil filter CommitTouches() = %v, %v; want true, nil", touched, err)
+	}
+}
+
+func TestFilter_CommitTouches(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	repo, err := git.PlainInit(dir, false)
+	if err != nil {
+		t.Fatalf("failed to init repo: %v", err)
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		t.Fatalf("failed to get worktree: %v", err)
+	}
+
+	commit := func(files map[string]string) *object.Commit {
+		t.Helper()
+		for name, content := range files {
+			full := filepath.Join(dir, name)
+			if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+				t.Fatalf("mkdir: %v", err)
+			}
+			if err := os.WriteFile(full, []byte(content), 0o644); err != nil {
+				t.Fatalf("write: %v", err)
+			}
+			if _, err := worktree.Add(name); err != nil {
+				t.Fatalf("add: %v", err)
+			}
+		}
+		hash, err := worktree.Commit("change", &git.CommitOptions{
+			Author: &object.Signature{Name: "Test", Email: "test@test.com", When: time.Now()},
+		})
+		if err != nil {
+			t.Fatalf("commit: %v", err)
+		}
+		c, err := repo.CommitObject(hash)
+		if err != nil {
+			t.Fatalf("commit object: %v", err)
+		}
+		return c
+	}
+
+	root := commit(map[string]string{"services/api/main.go": "v1", "services/web/app.ts": "v1"})
+	apiChange := commit(map[string]string{"services/api/main.go": "v2"})
+	webChange := commit(map[string]string{"services/web/app.ts": "v2"})
+
+	apiFilter, err := New([]string{"services/api"})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	globFilter, err := New([]string{"services/*/*.ts"})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		filter *Filter
+		commit *object.Commit
+		want   bool
+	}{
+		{"root commit adds api", apiFilter, root, true},
+		{"api change", apiFilter, apiChange, true},
+		{"web change outside api", apiFilter, webChange, false},
+		{"glob matches web change", globFilter, webChange, true},
+		{"glob skips go change", globFilter, apiChange, false},
+	}
+	for _, tt := range tests {
+		got, err := tt.filter.CommitTouches(tt.commit)
+		if err != nil {
+			t.Fatalf("%s: CommitTouches() error = %v", tt.name, err)
+		}
+		if got != tt.want {
+			t.Errorf("%s: CommitTouches() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package paths
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -22,20 +23,37 @@ const (
 
 // Metadata file names
 const (
-	ContextFileName          = "context.md"
-	PromptFileName           = "prompt.txt"
-	SummaryFileName          = "summary.txt"
-	TranscriptFileName       = "full.jsonl"
-	TranscriptFileNameLegacy = "full.log"
-	MetadataFileName         = "metadata.json"
-	CheckpointFileName       = "checkpoint.json"
-	ContentHashFileName      = "content_hash.txt"
-	SettingsFileName         = "settings.json"
+	ContextFileName           = "context.md"
+	ContextJSONFileName       = "context.json"
+	PromptFileName            = "prompt.txt"
+	PromptsFileName           = "prompts.json"
+	SummaryFileName           = "summary.txt"
+	TranscriptFileName        = "full.jsonl"
+	TranscriptFileNameLegacy  = "full.log"
+	TranscriptPointerFileName = "transcript_pointer.json"
+	TranscriptAppendsDirName  = TranscriptFileName + ".appends"
+	MetadataFileName          = "metadata.json"
+	CheckpointFileName        = "checkpoint.json"
+	ContentHashFileName       = "content_hash.txt"
+	SettingsFileName          = "settings.json"
+	AgentConfigDirName        = "agent-config"
+	AttachmentsDirName        = "attachments"
+	ArtifactsDirName          = "artifacts"
+	CommentsDirName           = "comments"
 )
 
 // MetadataBranchName is the orphan branch used by manual-commit strategy to store metadata
 const MetadataBranchName = "entire/checkpoints/v1"
 
+// AuditLogDir is the top-level directory on the metadata branch holding audit entries.
+// It is longer than two characters, so it never collides with checkpoint shard buckets.
+const AuditLogDir = "audit"
+
+// ErrNotGitRepository is returned by commands run outside a git repository.
+// Its message is translated only when it is displayed, so callers can match
+// it with errors.Is in any locale.
+var ErrNotGitRepository = errors.New("not a git repository")
+
 // CheckpointPath returns the sharded storage path for a checkpoint ID.
 // Uses first 2 characters as shard (256 buckets), remaining as folder name.
 // Example: "a3b2c4d5e6f7" -> "a3/b2c4d5e6f7"
@@ -170,6 +188,13 @@ func SessionMetadataDirFromSessionID(sessionID string) string {
 	return EntireMetadataDir + "/" + sessionID
 }
 
+// PendingAttachmentsDirFromSessionID returns the path where files attached to
+// a session wait until the session is next condensed. It lives under
+// EntireTmpDir so staged files never reach shadow branch commits.
+func PendingAttachmentsDirFromSessionID(sessionID string) string {
+	return EntireTmpDir + "/" + AttachmentsDirName + "/" + sessionID
+}
+
 // ExtractSessionIDFromTranscriptPath attempts to extract a session ID from a transcript path.
 // Claude transcripts are stored at ~/.claude/projects/<project>/sessions/<id>.jsonl
 // If the path doesn't match expected format, returns empty string.
@@ -0,0 +1,186 @@
+// Package policy implements org-level command gating.
+//
+// A policy file at .entire/policy.json lists commands that are disabled in
+// particular environments (for example CI runners or shared machines).
+// Enforcement is client-side: it guards against accidents, not against a
+// user who controls the machine. Orgs that distribute the policy through
+// the repository can sign it so that local edits are detected.
+package policy
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+)
+
+const (
+	// PolicyFile is the path to the command policy file, relative to the repository root.
+	PolicyFile = ".entire/policy.json"
+
+	// EnvironmentEnvVar explicitly selects the environment a policy is evaluated for.
+	EnvironmentEnvVar = "ENTIRE_ENVIRONMENT"
+	// OverrideTokenEnvVar carries the token that lifts a policy restriction.
+	OverrideTokenEnvVar = "ENTIRE_OVERRIDE_TOKEN"
+	// PublicKeyEnvVar holds a base64 ed25519 public key. When set, the policy
+	// must carry a valid signature from the matching private key.
+	PublicKeyEnvVar = "ENTIRE_POLICY_PUBLIC_KEY"
+)
+
+// Environment names recognised without an explicit ENTIRE_ENVIRONMENT.
+const (
+	EnvironmentLocal = "local"
+	EnvironmentCI    = "ci"
+)
+
+// ErrInvalidSignature is returned when a signed policy fails verification.
+var ErrInvalidSignature = errors.New("policy signature is invalid")
+
+// Rules lists the commands disabled in one environment.
+// Entries are command paths without the binary name ("reset", "clean").
+// A "--force" suffix ("reset --force") disables only the forced form.
+type Rules struct {
+	DisabledCommands []string `json:"disabled_commands,omitempty"`
+}
+
+// Policy is the parsed contents of .entire/policy.json.
+type Policy struct {
+	// Environments maps environment names to the rules applied there.
+	Environments map[string]Rules `json:"environments,omitempty"`
+
+	// OverrideTokenSHA256 is the hex SHA-256 of the override token.
+	// Empty means restrictions cannot be overridden.
+	OverrideTokenSHA256 string `json:"override_token_sha256,omitempty"`
+
+	// Signature is a base64 ed25519 signature over SigningPayload().
+	Signature string `json:"signature,omitempty"`
+}
+
+// Load reads the policy file for the current repository.
+// Returns an empty policy when the file doesn't exist.
+func Load(ctx context.Context) (*Policy, error) {
+	policyFileAbs, err := paths.AbsPath(ctx, PolicyFile)
+	if err != nil {
+		policyFileAbs = PolicyFile // Fallback to relative
+	}
+
+	data, err := os.ReadFile(policyFileAbs) //nolint:gosec // path is derived from repo root
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &Policy{}, nil
+		}
+		return nil, fmt.Errorf("reading policy file: %w", err)
+	}
+	return Parse(data)
+}
+
+// Parse decodes a policy, rejecting unknown fields so typos don't silently
+// disable enforcement.
+func Parse(data []byte) (*Policy, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	p := &Policy{}
+	if err := dec.Decode(p); err != nil {
+		return nil, fmt.Errorf("parsing policy file: %w", err)
+	}
+	return p, nil
+}
+
+// SigningPayload returns the canonical bytes covered by the signature:
+// the JSON encoding of the policy with the signature field cleared.
+func (p *Policy) SigningPayload() ([]byte, error) {
+	unsigned := *p
+	unsigned.Signature = ""
+	data, err := json.Marshal(unsigned)
+	if err != nil {
+		return nil, fmt.Errorf("encoding policy: %w", err)
+	}
+	return data, nil
+}
+
+// Verify checks the policy signature against publicKey.
+func (p *Policy) Verify(publicKey ed25519.PublicKey) error {
+	if p.Signature == "" {
+		return fmt.Errorf("%w: policy is not signed", ErrInvalidSignature)
+	}
+	sig, err := base64.StdEncoding.DecodeString(p.Signature)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidSignature, err)
+	}
+	payload, err := p.SigningPayload()
+	if err != nil {
+		return err
+	}
+	if !ed25519.Verify(publicKey, payload, sig) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// ParsePublicKey decodes a base64 ed25519 public key.
+func ParsePublicKey(s string) (ed25519.PublicKey, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(s))
+	if err != nil {
+		return nil, fmt.Errorf("decoding policy public key: %w", err)
+	}
+	if len(key) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("policy public key must be %d bytes, got %d", ed25519.PublicKeySize, len(key))
+	}
+	return ed25519.PublicKey(key), nil
+}
+
+// DetectEnvironment returns the environment the CLI is running in.
+// ENTIRE_ENVIRONMENT wins; otherwise the conventional CI variable selects "ci".
+func DetectEnvironment() string {
+	if env := strings.TrimSpace(os.Getenv(EnvironmentEnvVar)); env != "" {
+		return strings.ToLower(env)
+	}
+	if os.Getenv("CI") != "" {
+		return EnvironmentCI
+	}
+	return EnvironmentLocal
+}
+
+// DisabledBy returns the rule that disables command in env, if any.
+// command is the command path without the binary name; force reports
+// whether --force was given.
+func (p *Policy) DisabledBy(env, command string, force bool) (string, bool) {
+	rules, ok := p.Environments[env]
+	if !ok {
+		return "", false
+	}
+	for _, rule := range rules.DisabledCommands {
+		name, forceOnly := strings.CutSuffix(strings.TrimSpace(rule), " --force")
+		if name != command {
+			continue
+		}
+		if forceOnly && !force {
+			continue
+		}
+		return rule, true
+	}
+	return "", false
+}
+
+// AcceptsOverride reports whether token matches the configured override token.
+func (p *Policy) AcceptsOverride(token string) bool {
+	if p.OverrideTokenSHA256 == "" || token == "" {
+		return false
+	}
+	want, err := hex.DecodeString(p.OverrideTokenSHA256)
+	if err != nil {
+		return false
+	}
+	got := sha256.Sum256([]byte(token))
+	return subtle.ConstantTimeCompare(got[:], want) == 1
+}
@@ -0,0 +1,130 @@
+package policy
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"testing"
+)
+
+func TestParse_RejectsUnknownFields(t *testing.T) {
+	t.Parallel()
+	if _, err := Parse([]byte(`{"enviroments": {}}`)); err == nil {
+		t.Error("Parse() should reject unknown fields")
+	}
+}
+
+func TestDisabledBy(t *testing.T) {
+	t.Parallel()
+	p := &Policy{Environments: map[string]Rules{
+		"ci": {DisabledCommands: []string{"reset --force", "clean"}},
+	}}
+
+	tests := []struct {
+		name    string
+		env     string
+		command string
+		force   bool
+		want    bool
+	}{
+		{"force-only rule without force", "ci", "reset", false, false},
+		{"force-only rule with force", "ci", "reset", true, true},
+		{"plain rule without force", "ci", "clean", false, true},
+		{"plain rule with force", "ci", "clean", true, true},
+		{"other command", "ci", "status", true, false},
+		{"other environment", "local", "clean", true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if _, got := p.DisabledBy(tt.env, tt.command, tt.force); got != tt.want {
+				t.Errorf("DisabledBy(%q, %q, %v) = %v, want %v", tt.env, tt.command, tt.force, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAcceptsOverride(t *testing.T) {
+	t.Parallel()
+	sum := sha256.Sum256([]byte("let-me-in"))
+	p := &Policy{OverrideTokenSHA256: hex.EncodeToString(sum[:])}
+
+	if !p.AcceptsOverride("let-me-in") {
+		t.Error("AcceptsOverride() rejected the correct token")
+	}
+	if p.AcceptsOverride("wrong") {
+		t.Error("AcceptsOverride() accepted a wrong token")
+	}
+	if p.AcceptsOverride("") {
+		t.Error("AcceptsOverride() accepted an empty token")
+	}
+	if (&Policy{}).AcceptsOverride("let-me-in") {
+		t.Error("AcceptsOverride() accepted a token with no override configured")
+	}
+}
+
+func TestVerify(t *testing.T) {
+	t.Parallel()
+	publicKey, privateKey, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+
+	p := &Policy{Environments: map[string]Rules{"ci": {DisabledCommands: []string{"reset"}}}}
+	if err := p.Verify(publicKey); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Verify() on unsigned policy = %v, want ErrInvalidSignature", err)
+	}
+
+	payload, err := p.SigningPayload()
+	if err != nil {
+		t.Fatalf("SigningPayload() error = %v", err)
+	}
+	p.Signature = base64.StdEncoding.EncodeToString(ed25519.Sign(privateKey, payload))
+	if err := p.Verify(publicKey); err != nil {
+		t.Errorf("Verify() on signed policy = %v, want nil", err)
+	}
+
+	// Tampering with the rules invalidates the signature
+	p.Environments["ci"] = Rules{}
+	if err := p.Verify(publicKey); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Verify() on tampered policy = %v, want ErrInvalidSignature", err)
+	}
+}
+
+func TestParsePublicKey(t *testing.T) {
+	t.Parallel()
+	publicKey, _, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	got, err := ParsePublicKey(base64.StdEncoding.EncodeToString(publicKey))
+	if err != nil {
+		t.Fatalf("ParsePublicKey() error = %v", err)
+	}
+	if !got.Equal(publicKey) {
+		t.Error("ParsePublicKey() returned a different key")
+	}
+	if _, err := ParsePublicKey(base64.StdEncoding.EncodeToString([]byte("short"))); err == nil {
+		t.Error("ParsePublicKey() should reject keys of the wrong size")
+	}
+}
+
+func TestDetectEnvironment(t *testing.T) {
+	t.Setenv(EnvironmentEnvVar, "")
+	t.Setenv("CI", "")
+	if got := DetectEnvironment(); got != EnvironmentLocal {
+		t.Errorf("DetectEnvironment() = %q, want %q", got, EnvironmentLocal)
+	}
+
+	t.Setenv("CI", "true")
+	if got := DetectEnvironment(); got != EnvironmentCI {
+		t.Errorf("DetectEnvironment() with CI = %q, want %q", got, EnvironmentCI)
+	}
+
+	t.Setenv(EnvironmentEnvVar, "Shared")
+	if got := DetectEnvironment(); got != "shared" {
+		t.Errorf("DetectEnvironment() with explicit env = %q, want %q", got, "shared")
+	}
+}
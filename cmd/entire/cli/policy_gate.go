@@ -0,0 +1,76 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/logging"
+	"github.com/entireio/cli/cmd/entire/cli/policy"
+	"github.com/entireio/cli/cmd/entire/cli/strategy"
+	"github.com/spf13/cobra"
+)
+
+// enforceCommandPolicy refuses to run cmd when .entire/policy.json disables it
+// for the current environment, unless a valid override token is supplied.
+func enforceCommandPolicy(cmd *cobra.Command) error {
+	ctx := cmd.Context()
+
+	p, err := policy.Load(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load command policy: %w", err)
+	}
+
+	// With a key configured, a missing or emptied policy is as suspect as a
+	// tampered one, so verify before looking at the rules
+	if key := os.Getenv(policy.PublicKeyEnvVar); key != "" {
+		publicKey, err := policy.ParsePublicKey(key)
+		if err != nil {
+			return fmt.Errorf("invalid %s: %w", policy.PublicKeyEnvVar, err)
+		}
+		if err := p.Verify(publicKey); err != nil {
+			return fmt.Errorf("refusing to run with an unverified command policy (%s): %w", policy.PolicyFile, err)
+		}
+	}
+	if len(p.Environments) == 0 {
+		return nil
+	}
+
+	env := policy.DetectEnvironment()
+	command := strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()+" ")
+	force := false
+	if f := cmd.Flags().Lookup("force"); f != nil {
+		force = f.Value.String() == "true"
+	}
+
+	rule, disabled := p.DisabledBy(env, command, force)
+	if !disabled {
+		return nil
+	}
+
+	logCtx := logging.WithComponent(ctx, "policy")
+	if p.AcceptsOverride(os.Getenv(policy.OverrideTokenEnvVar)) {
+		logging.Info(logCtx, "command policy overridden",
+			slog.String("command", command),
+			slog.String("environment", env),
+			slog.String("rule", rule))
+		strategy.RecordAudit(ctx, checkpoint.AuditEntry{
+			Operation: checkpoint.AuditOpPolicyOverride,
+			Details:   fmt.Sprintf("ran '%s' in the %q environment despite rule %q", command, env, rule),
+		})
+		return nil
+	}
+
+	logging.Info(logCtx, "command blocked by policy",
+		slog.String("command", command),
+		slog.String("environment", env),
+		slog.String("rule", rule))
+	fmt.Fprintf(cmd.ErrOrStderr(), "'%s' is disabled in the %q environment by %s (rule: %q).\n", command, env, policy.PolicyFile, rule)
+	if p.OverrideTokenSHA256 != "" {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Set %s to proceed.\n", policy.OverrideTokenEnvVar)
+	}
+	return NewSilentError(errors.New("command disabled by policy"))
+}
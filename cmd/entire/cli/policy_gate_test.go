@@ -0,0 +1,134 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/policy"
+	"github.com/spf13/cobra"
+)
+
+// newPolicyTestCmd builds an "entire reset" command tree for exercising the policy gate.
+func newPolicyTestCmd(t *testing.T, args ...string) (*cobra.Command, *bytes.Buffer) {
+	t.Helper()
+	root := &cobra.Command{Use: "entire"}
+	reset := &cobra.Command{Use: "reset", RunE: func(*cobra.Command, []string) error { return nil }}
+	reset.Flags().Bool("force", false, "")
+	root.AddCommand(reset)
+
+	if err := reset.ParseFlags(args); err != nil {
+		t.Fatalf("ParseFlags() error = %v", err)
+	}
+	var stderr bytes.Buffer
+	reset.SetErr(&stderr)
+	reset.SetContext(context.Background())
+	return reset, &stderr
+}
+
+func writePolicyFile(t *testing.T, content string) {
+	t.Helper()
+	if err := os.MkdirAll(".entire", 0o755); err != nil {
+		t.Fatalf("failed to create .entire: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(".entire", "policy.json"), []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to write policy: %v", err)
+	}
+}
+
+func TestEnforceCommandPolicy_NoPolicy(t *testing.T) {
+	setupCleanTestRepo(t)
+	t.Setenv(policy.EnvironmentEnvVar, "ci")
+
+	cmd, _ := newPolicyTestCmd(t, "--force")
+	if err := enforceCommandPolicy(cmd); err != nil {
+		t.Errorf("enforceCommandPolicy() without policy = %v, want nil", err)
+	}
+}
+
+func TestEnforceCommandPolicy_BlocksAndOverrides(t *testing.T) {
+	setupCleanTestRepo(t)
+	sum := sha256.Sum256([]byte("break-glass"))
+	writePolicyFile(t, `{
+  "environments": {"ci": {"disabled_commands": ["reset --force"]}},
+  "override_token_sha256": "`+hex.EncodeToString(sum[:])+`"
+}`)
+	t.Setenv(policy.EnvironmentEnvVar, "ci")
+	t.Setenv(policy.OverrideTokenEnvVar, "")
+	t.Setenv(policy.PublicKeyEnvVar, "")
+
+	// Without --force the rule does not apply
+	cmd, _ := newPolicyTestCmd(t)
+	if err := enforceCommandPolicy(cmd); err != nil {
+		t.Errorf("enforceCommandPolicy() without --force = %v, want nil", err)
+	}
+
+	cmd, stderr := newPolicyTestCmd(t, "--force")
+	err := enforceCommandPolicy(cmd)
+	var silent *SilentError
+	if !errors.As(err, &silent) {
+		t.Fatalf("enforceCommandPolicy() = %v, want SilentError", err)
+	}
+	if !strings.Contains(stderr.String(), policy.OverrideTokenEnvVar) {
+		t.Errorf("expected override hint in stderr, got: %s", stderr.String())
+	}
+
+	t.Setenv(policy.OverrideTokenEnvVar, "break-glass")
+	cmd, _ = newPolicyTestCmd(t, "--force")
+	if err := enforceCommandPolicy(cmd); err != nil {
+		t.Errorf("enforceCommandPolicy() with override token = %v, want nil", err)
+	}
+
+	// Overrides are recorded so they show up as policy violations in reports
+	repo, err := openRepository(context.Background())
+	if err != nil {
+		t.Fatalf("openRepository() error = %v", err)
+	}
+	entries, err := checkpoint.NewGitStore(repo).ReadAuditLog(context.Background())
+	if err != nil {
+		t.Fatalf("ReadAuditLog() error = %v", err)
+	}
+	if len(entries) != 1 || entries[0].Operation != checkpoint.AuditOpPolicyOverride {
+		t.Errorf("audit log = %+v, want one policy override", entries)
+	}
+}
+
+func TestEnforceCommandPolicy_RequiresSignatureWhenKeyConfigured(t *testing.T) {
+	setupCleanTestRepo(t)
+	writePolicyFile(t, `{"environments": {"ci": {"disabled_commands": ["clean"]}}}`)
+	t.Setenv(policy.EnvironmentEnvVar, "local")
+	// 32 zero bytes, base64-encoded
+	t.Setenv(policy.PublicKeyEnvVar, "AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA=")
+
+	cmd, _ := newPolicyTestCmd(t)
+	err := enforceCommandPolicy(cmd)
+	if !errors.Is(err, policy.ErrInvalidSignature) {
+		t.Errorf("enforceCommandPolicy() with unsigned policy = %v, want ErrInvalidSignature", err)
+	}
+}
+
+func TestEnforceCommandPolicy_RequiresPolicyWhenKeyConfigured(t *testing.T) {
+	setupCleanTestRepo(t)
+	t.Setenv(policy.EnvironmentEnvVar, "local")
+	t.Setenv(policy.PublicKeyEnvVar, "AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA=")
+
+	// A deleted policy file doesn't lift the requirement
+	cmd, _ := newPolicyTestCmd(t)
+	if err := enforceCommandPolicy(cmd); !errors.Is(err, policy.ErrInvalidSignature) {
+		t.Errorf("enforceCommandPolicy() without policy = %v, want ErrInvalidSignature", err)
+	}
+
+	// Nor does emptying its environments
+	writePolicyFile(t, `{}`)
+	cmd, _ = newPolicyTestCmd(t)
+	if err := enforceCommandPolicy(cmd); !errors.Is(err, policy.ErrInvalidSignature) {
+		t.Errorf("enforceCommandPolicy() with empty policy = %v, want ErrInvalidSignature", err)
+	}
+}
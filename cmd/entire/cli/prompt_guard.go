@@ -0,0 +1,141 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/entireio/cli/cmd/entire/cli/logging"
+	"github.com/entireio/cli/cmd/entire/cli/settings"
+	"github.com/entireio/cli/cmd/entire/cli/strategy"
+)
+
+// runPromptGuard checks the repository against the prompt_guard settings and
+// writes a warning or blocking hook response when something is off.
+// Returns true if the prompt was blocked and the turn should not start.
+func runPromptGuard(ctx context.Context, cfg *settings.PromptGuardSettings) bool {
+	if cfg == nil {
+		return false
+	}
+	logCtx := logging.WithComponent(ctx, "prompt-guard")
+
+	issues := checkPromptGuard(ctx, cfg)
+	if len(issues) == 0 {
+		return false
+	}
+
+	action := cfg.GetAction()
+	logging.Info(logCtx, "prompt guard triggered",
+		slog.String("action", action),
+		slog.Int("issues", len(issues)))
+
+	message := "Entire prompt guard:\n  " + strings.Join(issues, "\n  ")
+	if action == settings.PromptGuardActionBlock {
+		if err := outputHookBlock(message + "\nResolve these before prompting the agent again."); err != nil {
+			logging.Warn(logCtx, "failed to write prompt guard response",
+				slog.String("error", err.Error()))
+		}
+		return true
+	}
+	if err := outputHookResponse(message); err != nil {
+		logging.Warn(logCtx, "failed to write prompt guard response",
+			slog.String("error", err.Error()))
+	}
+	return false
+}
+
+// checkPromptGuard returns a description of each configured precondition that
+// currently fails. Checks that can't be evaluated are skipped.
+func checkPromptGuard(ctx context.Context, cfg *settings.PromptGuardSettings) []string {
+	var issues []string
+
+	if cfg.MergeInProgress {
+		if gitDir, err := strategy.GetGitDir(ctx); err == nil {
+			if op := sequenceOperationInProgress(gitDir); op != "" {
+				issues = append(issues, fmt.Sprintf("A %s is in progress. Finish or abort it first.", op))
+			}
+		}
+	}
+
+	if len(cfg.ProtectedBranches) > 0 {
+		if branch, err := GetCurrentBranch(ctx); err == nil {
+			if pattern, ok := matchBranchGlob(branch, cfg.ProtectedBranches); ok {
+				issues = append(issues, fmt.Sprintf("Branch %s is protected (%s). Switch to a feature branch.", branch, pattern))
+			}
+		}
+	}
+
+	if cfg.MaxUncommittedLines > 0 {
+		if lines, err := uncommittedLineCount(ctx); err == nil && lines > cfg.MaxUncommittedLines {
+			issues = append(issues, fmt.Sprintf("%d uncommitted lines changed (limit %d). Commit or stash first.", lines, cfg.MaxUncommittedLines))
+		}
+	}
+
+	return issues
+}
+
+// sequenceOperationInProgress names the merge-like operation in progress in
+// gitDir, or returns "" if there is none.
+func sequenceOperationInProgress(gitDir string) string {
+	for _, marker := range []struct{ name, op string }{
+		{"MERGE_HEAD", "merge"},
+		{"rebase-merge", "rebase"},
+		{"rebase-apply", "rebase"},
+		{"CHERRY_PICK_HEAD", "cherry-pick"},
+		{"REVERT_HEAD", "revert"},
+	} {
+		if _, err := os.Stat(filepath.Join(gitDir, marker.name)); err == nil {
+			return marker.op
+		}
+	}
+	return ""
+}
+
+// matchBranchGlob returns the first glob matching branch.
+func matchBranchGlob(branch string, globs []string) (string, bool) {
+	for _, glob := range globs {
+		if ok, err := path.Match(glob, branch); err == nil && ok {
+			return glob, true
+		}
+	}
+	return "", false
+}
+
+// uncommittedLineCount returns added plus removed lines in tracked files
+// relative to HEAD (staged and unstaged). Binary files count as zero.
+func uncommittedLineCount(ctx context.Context) (int, error) {
+	cmd := exec.CommandContext(ctx, "git", "diff", "HEAD", "--numstat")
+	output, err := cmd.Output()
+	if err != nil {
+		return 0, fmt.Errorf("git diff failed: %w", err)
+	}
+	return parseNumstat(output), nil
+}
+
+// parseNumstat sums the added and removed columns of `git diff --numstat` output.
+func parseNumstat(output []byte) int {
+	total := 0
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 3 {
+			continue
+		}
+		// Binary files show "-" for both counts
+		added, addErr := strconv.Atoi(fields[0])
+		removed, removeErr := strconv.Atoi(fields[1])
+		if addErr != nil || removeErr != nil {
+			continue
+		}
+		total += added + removed
+	}
+	return total
+}
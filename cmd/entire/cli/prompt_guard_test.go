@@ -0,0 +1,88 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+	"github.com/entireio/cli/cmd/entire/cli/settings"
+	"github.com/entireio/cli/cmd/entire/cli/testutil"
+)
+
+func TestParseNumstat(t *testing.T) {
+	t.Parallel()
+
+	output := []byte("10\t2\tmain.go\n-\t-\tlogo.png\n3\t0\tdocs/readme.md\n")
+	if got := parseNumstat(output); got != 15 {
+		t.Errorf("parseNumstat() = %d, want 15", got)
+	}
+}
+
+func TestMatchBranchGlob(t *testing.T) {
+	t.Parallel()
+
+	globs := []string{"main", "release/*"}
+	if pattern, ok := matchBranchGlob("release/1.2", globs); !ok || pattern != "release/*" {
+		t.Errorf("matchBranchGlob(release/1.2) = %q, %v; want release/*, true", pattern, ok)
+	}
+	if _, ok := matchBranchGlob("feature/x", globs); ok {
+		t.Error("matchBranchGlob(feature/x) matched, want no match")
+	}
+}
+
+func TestSequenceOperationInProgress(t *testing.T) {
+	t.Parallel()
+
+	gitDir := t.TempDir()
+	if op := sequenceOperationInProgress(gitDir); op != "" {
+		t.Errorf("sequenceOperationInProgress() = %q, want empty", op)
+	}
+	if err := os.WriteFile(filepath.Join(gitDir, "MERGE_HEAD"), []byte("abc\n"), 0o644); err != nil {
+		t.Fatalf("failed to write MERGE_HEAD: %v", err)
+	}
+	if op := sequenceOperationInProgress(gitDir); op != "merge" {
+		t.Errorf("sequenceOperationInProgress() = %q, want merge", op)
+	}
+}
+
+func TestCheckPromptGuard(t *testing.T) {
+	dir := t.TempDir()
+	testutil.InitRepo(t, dir)
+	t.Chdir(dir)
+	paths.ClearWorktreeRootCache()
+
+	testutil.WriteFile(t, dir, "main.go", "package main\n")
+	testutil.GitAdd(t, dir, "main.go")
+	testutil.GitCommit(t, dir, "Initial commit")
+
+	ctx := context.Background()
+	branch, err := GetCurrentBranch(ctx)
+	if err != nil {
+		t.Fatalf("GetCurrentBranch() error = %v", err)
+	}
+
+	cfg := &settings.PromptGuardSettings{
+		ProtectedBranches:   []string{branch},
+		MaxUncommittedLines: 2,
+		MergeInProgress:     true,
+	}
+	issues := checkPromptGuard(ctx, cfg)
+	if len(issues) != 1 || !strings.Contains(issues[0], "protected") {
+		t.Fatalf("checkPromptGuard() = %v, want only the protected branch issue", issues)
+	}
+
+	testutil.WriteFile(t, dir, "main.go", "package main\n\nfunc a() {}\nfunc b() {}\n")
+	issues = checkPromptGuard(ctx, cfg)
+	if len(issues) != 2 || !strings.Contains(issues[1], "uncommitted lines") {
+		t.Errorf("checkPromptGuard() = %v, want protected branch and uncommitted lines issues", issues)
+	}
+
+	cfg.ProtectedBranches = []string{"release/*"}
+	cfg.MaxUncommittedLines = 0
+	if issues := checkPromptGuard(ctx, cfg); len(issues) != 0 {
+		t.Errorf("checkPromptGuard() = %v, want none", issues)
+	}
+}
@@ -0,0 +1,67 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"time"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+	"github.com/entireio/cli/cmd/entire/cli/serve"
+	"github.com/entireio/cli/cmd/entire/cli/strategy"
+	"github.com/spf13/cobra"
+)
+
+func newPublishCmd() *cobra.Command {
+	var outFlag string
+
+	cmd := &cobra.Command{
+		Use:   "publish",
+		Short: "Export checkpoint history as a static HTML site",
+		Long: `Publish renders the repository's checkpoints into a static HTML site: an
+index of checkpoints with their review state, and one page per checkpoint
+with its prompts, files, attachments, and comments, plus an Atom feed of
+the newest checkpoints (feed.atom). Transcripts are not included. Links are relative, so the site needs no server and can be hosted
+anywhere, e.g. on GitHub Pages, for projects that want their AI-assisted
+changes to be public.
+
+Rerunning publish into the same directory updates the pages and removes
+those of checkpoints that no longer exist; other files are left alone.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := cmd.Context()
+			if _, err := paths.WorktreeRoot(ctx); err != nil {
+				return paths.ErrNotGitRepository
+			}
+			return runPublish(ctx, cmd.OutOrStdout(), outFlag)
+		},
+	}
+
+	cmd.Flags().StringVar(&outFlag, "out", "site", "Directory to write the site to")
+
+	return cmd
+}
+
+func runPublish(ctx context.Context, w io.Writer, outDir string) error {
+	repo, err := openRepository(ctx)
+	if err != nil {
+		return fmt.Errorf("not a git repository: %w", err)
+	}
+	store, err := strategy.CommittedStore(ctx, checkpoint.NewGitStore(repo))
+	if err != nil {
+		return err //nolint:wrapcheck // already names the setting
+	}
+	srv := &serve.Server{
+		Store:             store,
+		ReadOnly:          true,
+		RequiredApprovals: requiredApprovals(ctx),
+	}
+	count, err := srv.Publish(ctx, outDir, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to publish: %w", err)
+	}
+	fmt.Fprintf(w, "Published %d checkpoint(s) to %s\n", count, filepath.Join(outDir, "index.html"))
+	return nil
+}
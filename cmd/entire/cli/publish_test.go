@@ -0,0 +1,30 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunPublish_EmptyRepository(t *testing.T) {
+	dir := setupExecTestRepo(t)
+	outDir := filepath.Join(dir, "site")
+
+	var out bytes.Buffer
+	if err := runPublish(context.Background(), &out, outDir); err != nil {
+		t.Fatalf("runPublish() error = %v", err)
+	}
+	if !strings.Contains(out.String(), "Published 0 checkpoint(s)") {
+		t.Errorf("output = %q", out.String())
+	}
+	index, err := os.ReadFile(filepath.Join(outDir, "index.html"))
+	if err != nil {
+		t.Fatalf("index.html not written: %v", err)
+	}
+	if !strings.Contains(string(index), "No checkpoints yet.") {
+		t.Errorf("index.html = %s", index)
+	}
+}
@@ -0,0 +1,278 @@
+package cli
+
+import (
+	"context"
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/huh"
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/i18n"
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+	"github.com/entireio/cli/cmd/entire/cli/session"
+	"github.com/entireio/cli/cmd/entire/cli/strategy"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/spf13/cobra"
+)
+
+func newPurgeSessionCmd() *cobra.Command {
+	var forceFlag bool
+	var reasonFlag string
+
+	cmd := &cobra.Command{
+		Use:   "purge-session <session-id>",
+		Short: "Remove a session's content from the checkpoint history",
+		Long: `Remove a session's transcript, prompts, context, and summary from every
+checkpoint on the entire/checkpoints/v1 branch, including its history.
+
+Structural metadata (files touched, token usage, attribution) is kept, and
+the checkpoints still work for rewind. Commits that held the content are
+rewritten, so the branch must be force-pushed to remove the content from
+remotes, and other clones must fetch the rewritten branch. Branches archived
+by 'entire sync' are rewritten too, and so are shadow branches, shadow
+branches archived by 'entire gc', and point refs, which keep the session's
+transcript and prompts under .entire/metadata. Remote-tracking copies of the
+branch are removed, and so is the recall index.
+
+Not supported when checkpoint_store keeps checkpoints in an object store.
+
+The purge is recorded in the audit log (see 'entire audit-log'). Set
+ENTIRE_AUDIT_SIGNING_KEY to a base64 ed25519 private key to sign the record.
+
+Sessions that haven't ended would store their content again at the next
+checkpoint; reset them first with 'entire reset --session <id>'.
+
+Without --force, prompts for confirmation before purging.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			if _, err := paths.WorktreeRoot(ctx); err != nil {
+				return paths.ErrNotGitRepository
+			}
+			sessionID := args[0]
+
+			if !forceFlag {
+				var confirmed bool
+				form := NewAccessibleForm(
+					huh.NewGroup(
+						newConfirm().
+							Title(i18n.T(i18n.ConfirmPurgeSession, sessionID)).
+							Description(i18n.T(i18n.ConfirmPurgeSessionDesc, paths.MetadataBranchName)).
+							Value(&confirmed),
+					),
+				)
+				if err := form.Run(); err != nil {
+					if errors.Is(err, huh.ErrUserAborted) {
+						return nil
+					}
+					return fmt.Errorf("failed to get confirmation: %w", err)
+				}
+				if !confirmed {
+					return nil
+				}
+			}
+
+			return runPurgeSession(ctx, cmd.OutOrStdout(), sessionID, reasonFlag, time.Now())
+		},
+	}
+
+	cmd.Flags().BoolVarP(&forceFlag, "force", "f", false, "Skip confirmation prompt")
+	cmd.Flags().StringVar(&reasonFlag, "reason", "", "Reason recorded with the purge (e.g. a request reference)")
+
+	return cmd
+}
+
+// runPurgeSession removes the session's content from the metadata branch and
+// records the purge in the audit log, signed if a signing key is configured.
+func runPurgeSession(ctx context.Context, w io.Writer, sessionID, reason string, now time.Time) error {
+	state, err := strategy.LoadSessionState(ctx, sessionID)
+	if err != nil {
+		return fmt.Errorf("failed to load session: %w", err)
+	}
+	if state != nil && state.Phase != session.PhaseEnded {
+		return fmt.Errorf("session %s hasn't ended and would store its content again; run 'entire reset --session %s' first", sessionID, sessionID)
+	}
+
+	// Check the key before rewriting anything, so a bad key doesn't leave an unsigned record
+	var signingKey ed25519.PrivateKey
+	if raw := os.Getenv(checkpoint.AuditSigningKeyEnvVar); raw != "" {
+		if signingKey, err = checkpoint.ParseAuditSigningKey(raw); err != nil {
+			return fmt.Errorf("invalid %s: %w", checkpoint.AuditSigningKeyEnvVar, err)
+		}
+	}
+
+	if err := strategy.RequireBranchStore(ctx, "entire purge-session"); err != nil {
+		return err //nolint:wrapcheck // already names the operation
+	}
+	repo, err := openRepository(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to open repository: %w", err)
+	}
+	store := checkpoint.NewGitStore(repo)
+
+	result, err := store.PurgeSessionContent(ctx, sessionID, now)
+	if err != nil {
+		return fmt.Errorf("failed to purge session %s: %w", sessionID, err)
+	}
+	// Archived and remote-tracking copies of the branch keep the old commits reachable
+	archives, err := listRefs(repo, strategy.PruneArchiveRefPrefix)
+	if err != nil {
+		return err
+	}
+	rewrittenArchives, err := store.PurgeSessionRefs(ctx, sessionID, now, archives)
+	if err != nil {
+		return fmt.Errorf("failed to purge session %s from archived branches: %w", sessionID, err)
+	}
+	// Shadow branches keep the transcript and prompts under .entire/metadata,
+	// and archived shadow branches and point refs keep shadow commits reachable
+	shadowRefs, err := listShadowRefs(repo)
+	if err != nil {
+		return err
+	}
+	rewrittenShadows, err := store.PurgeShadowRefs(ctx, sessionID, shadowRefs)
+	if err != nil {
+		return fmt.Errorf("failed to purge session %s from shadow branches: %w", sessionID, err)
+	}
+	if len(result.CheckpointIDs) == 0 && len(rewrittenArchives) == 0 && len(rewrittenShadows) == 0 {
+		fmt.Fprintf(w, "No stored content found for session %s.\n", sessionID)
+		return nil
+	}
+	remoteRefs, err := removeMetadataRemoteRefs(repo)
+	if err != nil {
+		return err
+	}
+
+	checkpointIDs := make([]string, len(result.CheckpointIDs))
+	for i, cpID := range result.CheckpointIDs {
+		checkpointIDs[i] = cpID.String()
+	}
+	details := fmt.Sprintf("purged content from checkpoints %s; rewrote %d commits",
+		strings.Join(checkpointIDs, ", "), result.RewrittenCommits)
+	refs := []string{paths.MetadataBranchName, result.OldHead.String()}
+	for _, ref := range rewrittenArchives {
+		refs = append(refs, ref.String())
+	}
+	if len(rewrittenArchives) > 0 {
+		details += fmt.Sprintf("; rewrote %d archived branch(es)", len(rewrittenArchives))
+	}
+	for _, ref := range rewrittenShadows {
+		refs = append(refs, ref.String())
+	}
+	if len(rewrittenShadows) > 0 {
+		details += fmt.Sprintf("; rewrote %d shadow branch or point ref(s)", len(rewrittenShadows))
+	}
+	if len(remoteRefs) > 0 {
+		details += "; removed " + strings.Join(remoteRefs, ", ")
+	}
+	if reason != "" {
+		details += "; reason: " + reason
+	}
+	actorName, actorEmail := checkpoint.GetGitAuthorFromRepo(repo)
+	entry := checkpoint.AuditEntry{
+		Timestamp:  now.UTC(),
+		Operation:  checkpoint.AuditOpPurge,
+		ActorName:  actorName,
+		ActorEmail: actorEmail,
+		Refs:       refs,
+		Removed:    []string{sessionID},
+		Details:    details,
+	}
+	if signingKey != nil {
+		if err := entry.Sign(signingKey); err != nil {
+			return fmt.Errorf("failed to sign purge record: %w", err)
+		}
+	}
+	if err := store.AppendAuditEntry(ctx, entry); err != nil {
+		return fmt.Errorf("purged session %s but failed to record it: %w", sessionID, err)
+	}
+
+	if len(checkpointIDs) > 0 {
+		fmt.Fprintf(w, "Purged the content of session %s from %d checkpoint(s): %s\n",
+			sessionID, len(checkpointIDs), strings.Join(checkpointIDs, ", "))
+		fmt.Fprintf(w, "Rewrote %d commit(s) on %s.\n", result.RewrittenCommits, paths.MetadataBranchName)
+	}
+	for _, ref := range rewrittenArchives {
+		fmt.Fprintf(w, "Rewrote archived branch %s.\n", ref)
+	}
+	for _, ref := range rewrittenShadows {
+		fmt.Fprintf(w, "Rewrote %s.\n", ref)
+	}
+	for _, ref := range remoteRefs {
+		fmt.Fprintf(w, "Removed remote-tracking ref %s; it comes back with the old content on the next fetch until the remote is force-pushed.\n", ref)
+	}
+	if signingKey != nil {
+		fmt.Fprintln(w, "Recorded a signed purge record in the audit log.")
+	} else {
+		fmt.Fprintf(w, "Recorded an unsigned purge record in the audit log (set %s to sign it).\n", checkpoint.AuditSigningKeyEnvVar)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "To remove the content from the remote, force-push the branch:")
+	fmt.Fprintf(w, "  git push --force %s %s\n", strategy.MetadataRemote(ctx), paths.MetadataBranchName)
+	fmt.Fprintln(w, "Other clones keep their copy until they fetch the rewritten branch.")
+	fmt.Fprintln(w, "The old objects stay in this clone until they are pruned:")
+	fmt.Fprintln(w, "  git reflog expire --expire=now --all && git gc --prune=now")
+	return nil
+}
+
+// listRefs returns the names of the refs under prefix, sorted.
+func listRefs(repo *git.Repository, prefix string) ([]plumbing.ReferenceName, error) {
+	iter, err := repo.References()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list refs: %w", err)
+	}
+	var names []plumbing.ReferenceName
+	err = iter.ForEach(func(ref *plumbing.Reference) error {
+		if strings.HasPrefix(ref.Name().String(), prefix) {
+			names = append(names, ref.Name())
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list refs: %w", err)
+	}
+	slices.Sort(names)
+	return names, nil
+}
+
+// listShadowRefs returns the shadow branches, other than the metadata
+// branch, and the refs under the shadow archive and point ref namespaces.
+func listShadowRefs(repo *git.Repository) ([]plumbing.ReferenceName, error) {
+	var names []plumbing.ReferenceName
+	for _, prefix := range []string{"refs/heads/" + checkpoint.ShadowBranchPrefix, strategy.ShadowArchiveRefPrefix, strategy.PointRefPrefix} {
+		refs, err := listRefs(repo, prefix)
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, refs...)
+	}
+	return slices.DeleteFunc(names, func(name plumbing.ReferenceName) bool {
+		return name == plumbing.NewBranchReferenceName(paths.MetadataBranchName)
+	}), nil
+}
+
+// removeMetadataRemoteRefs deletes the remote-tracking refs of the metadata
+// branch, refs/remotes/<remote>/entire/checkpoints/v1, and returns their names.
+func removeMetadataRemoteRefs(repo *git.Repository) ([]string, error) {
+	refs, err := listRefs(repo, "refs/remotes/")
+	if err != nil {
+		return nil, err
+	}
+	var removed []string
+	for _, ref := range refs {
+		if !strings.HasSuffix(ref.String(), "/"+paths.MetadataBranchName) {
+			continue
+		}
+		if err := repo.Storer.RemoveReference(ref); err != nil {
+			return removed, fmt.Errorf("failed to remove %s: %w", ref, err)
+		}
+		removed = append(removed, ref.String())
+	}
+	return removed, nil
+}
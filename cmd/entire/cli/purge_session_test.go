@@ -0,0 +1,316 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+	"github.com/entireio/cli/cmd/entire/cli/session"
+	"github.com/entireio/cli/cmd/entire/cli/strategy"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+const purgeTestSigningKey = "AAECAwQFBgcICQoLDA0ODxAREhMUFRYXGBkaGxwdHh8="
+
+func writePurgeTestCheckpoint(t *testing.T, cpID id.CheckpointID) *checkpoint.GitStore {
+	t.Helper()
+	ctx := context.Background()
+	repo, err := openRepository(ctx)
+	if err != nil {
+		t.Fatalf("openRepository() error = %v", err)
+	}
+	store := checkpoint.NewGitStore(repo)
+	if err := store.WriteCommitted(ctx, checkpoint.WriteCommittedOptions{
+		CheckpointID: cpID,
+		SessionID:    "private-session",
+		Strategy:     strategy.StrategyNameManualCommit,
+		Transcript:   []byte(`{"type":"user","message":{"content":"my home address is ..."}}` + "\n"),
+		Prompts:      []string{"my home address is ..."},
+		AuthorName:   "Test",
+		AuthorEmail:  "test@test.com",
+	}); err != nil {
+		t.Fatalf("WriteCommitted() error = %v", err)
+	}
+	return store
+}
+
+func TestRunPurgeSession(t *testing.T) {
+	setupExecTestRepo(t)
+	t.Setenv(checkpoint.AuditSigningKeyEnvVar, purgeTestSigningKey)
+	ctx := context.Background()
+	cpID := id.MustCheckpointID("e5e5e5e5e5e5")
+	store := writePurgeTestCheckpoint(t, cpID)
+
+	var out bytes.Buffer
+	if err := runPurgeSession(ctx, &out, "private-session", "request 42", time.Now()); err != nil {
+		t.Fatalf("runPurgeSession() error = %v", err)
+	}
+	if !strings.Contains(out.String(), "Purged the content of session private-session from 1 checkpoint(s): e5e5e5e5e5e5") {
+		t.Errorf("output should list the purged checkpoint, got: %s", out.String())
+	}
+	if !strings.Contains(out.String(), "git push --force") {
+		t.Errorf("output should explain how to purge remotes, got: %s", out.String())
+	}
+
+	content, err := store.ReadSessionContentByID(ctx, cpID, "private-session")
+	if err != nil {
+		t.Fatalf("ReadSessionContentByID() error = %v", err)
+	}
+	if len(content.Transcript) != 0 || content.Prompts != "" {
+		t.Errorf("session content not purged: %+v", content)
+	}
+
+	entries, err := store.ReadAuditLog(ctx)
+	if err != nil {
+		t.Fatalf("ReadAuditLog() error = %v", err)
+	}
+	if len(entries) != 1 || entries[0].Operation != checkpoint.AuditOpPurge {
+		t.Fatalf("audit log = %+v, want one purge entry", entries)
+	}
+	if !strings.Contains(entries[0].Details, "reason: request 42") {
+		t.Errorf("purge record details = %q, want the reason", entries[0].Details)
+	}
+	key, err := checkpoint.ParseAuditSigningKey(purgeTestSigningKey)
+	if err != nil {
+		t.Fatalf("ParseAuditSigningKey() error = %v", err)
+	}
+	publicKey, ok := key.Public().(ed25519.PublicKey)
+	if !ok {
+		t.Fatal("unexpected public key type")
+	}
+	if err := entries[0].Verify(publicKey); err != nil {
+		t.Errorf("purge record signature: %v", err)
+	}
+
+	out.Reset()
+	if err := runPurgeSession(ctx, &out, "private-session", "", time.Now()); err != nil {
+		t.Fatalf("runPurgeSession() error = %v", err)
+	}
+	if !strings.Contains(out.String(), "No stored content found for session private-session.") {
+		t.Errorf("second purge should find nothing, got: %s", out.String())
+	}
+}
+
+func TestRunPurgeSession_ArchivedAndRemoteRefs(t *testing.T) {
+	setupExecTestRepo(t)
+	writeSettings(t, `{"enabled": true, "sync": {"remote": "sessions"}}`)
+	ctx := context.Background()
+	writePurgeTestCheckpoint(t, id.MustCheckpointID("e5e5e5e5e5e5"))
+
+	repo, err := openRepository(ctx)
+	if err != nil {
+		t.Fatalf("openRepository() error = %v", err)
+	}
+	branch, err := repo.Reference(plumbing.NewBranchReferenceName(paths.MetadataBranchName), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	archive := plumbing.ReferenceName(strategy.PruneArchiveRefPrefix + "sessions/20260101T000000Z")
+	remote := plumbing.NewRemoteReferenceName("sessions", paths.MetadataBranchName)
+	for _, name := range []plumbing.ReferenceName{archive, remote} {
+		if err := repo.Storer.SetReference(plumbing.NewHashReference(name, branch.Hash())); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var out bytes.Buffer
+	if err := runPurgeSession(ctx, &out, "private-session", "", time.Now()); err != nil {
+		t.Fatalf("runPurgeSession() error = %v", err)
+	}
+
+	// The archive held the same history, so it is rewritten to the purged
+	// branch as it was before the audit entry was added on top
+	head, err := repo.Reference(plumbing.NewBranchReferenceName(paths.MetadataBranchName), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	auditCommit, err := repo.CommitObject(head.Hash())
+	if err != nil {
+		t.Fatal(err)
+	}
+	archived, err := repo.Reference(archive, true)
+	if err != nil || archived.Hash() == branch.Hash() || archived.Hash() != auditCommit.ParentHashes[0] {
+		t.Errorf("archive ref = %v, %v; want the purged branch %s", archived, err, auditCommit.ParentHashes[0])
+	}
+	if _, err := repo.Reference(remote, true); err == nil {
+		t.Errorf("remote-tracking ref %s still exists", remote)
+	}
+	for _, want := range []string{"Rewrote archived branch " + archive.String(), "Removed remote-tracking ref " + remote.String(), "git push --force sessions " + paths.MetadataBranchName} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output should contain %q, got: %s", want, out.String())
+		}
+	}
+}
+
+// reachableObjects returns every object reachable from a ref.
+func reachableObjects(t *testing.T, repo *git.Repository) map[plumbing.Hash]bool {
+	t.Helper()
+	seen := make(map[plumbing.Hash]bool)
+	var walkTree func(hash plumbing.Hash)
+	walkTree = func(hash plumbing.Hash) {
+		if seen[hash] {
+			return
+		}
+		seen[hash] = true
+		tree, err := repo.TreeObject(hash)
+		if err != nil {
+			t.Fatalf("TreeObject(%s) error = %v", hash, err)
+		}
+		for _, entry := range tree.Entries {
+			if entry.Mode == filemode.Dir {
+				walkTree(entry.Hash)
+			} else {
+				seen[entry.Hash] = true
+			}
+		}
+	}
+	var walkCommit func(hash plumbing.Hash)
+	walkCommit = func(hash plumbing.Hash) {
+		if seen[hash] {
+			return
+		}
+		seen[hash] = true
+		commit, err := repo.CommitObject(hash)
+		if err != nil {
+			t.Fatalf("CommitObject(%s) error = %v", hash, err)
+		}
+		walkTree(commit.TreeHash)
+		for _, parent := range commit.ParentHashes {
+			walkCommit(parent)
+		}
+	}
+
+	refs, err := repo.References()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := refs.ForEach(func(ref *plumbing.Reference) error {
+		if ref.Type() == plumbing.HashReference {
+			walkCommit(ref.Hash())
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	return seen
+}
+
+func TestRunPurgeSession_ShadowRefs(t *testing.T) {
+	setupExecTestRepo(t)
+	ctx := context.Background()
+	repo, err := openRepository(ctx)
+	if err != nil {
+		t.Fatalf("openRepository() error = %v", err)
+	}
+	head, err := repo.Head()
+	if err != nil {
+		t.Fatal(err)
+	}
+	headCommit, err := repo.CommitObject(head.Hash())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A shadow commit on top of HEAD with this session's and another
+	// session's metadata, kept reachable by a branch, an archive and a point ref
+	blob := func(content string) plumbing.Hash {
+		hash, err := checkpoint.CreateBlobFromContent(repo, []byte(content))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return hash
+	}
+	transcript := blob(`{"type":"user","message":{"content":"my home address is ..."}}` + "\n")
+	other := blob("another session\n")
+	treeHash, err := checkpoint.ApplyTreeChanges(repo, headCommit.TreeHash, []checkpoint.TreeChange{
+		{Path: paths.SessionMetadataDirFromSessionID("private-session") + "/" + paths.TranscriptFileName, Entry: &object.TreeEntry{Mode: filemode.Regular, Hash: transcript}},
+		{Path: paths.SessionMetadataDirFromSessionID("other-session") + "/" + paths.TranscriptFileName, Entry: &object.TreeEntry{Mode: filemode.Regular, Hash: other}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	shadow := &object.Commit{
+		Author:       headCommit.Author,
+		Committer:    headCommit.Committer,
+		Message:      "Checkpoint\n",
+		TreeHash:     treeHash,
+		ParentHashes: []plumbing.Hash{head.Hash()},
+	}
+	obj := repo.Storer.NewEncodedObject()
+	if err := shadow.Encode(obj); err != nil {
+		t.Fatal(err)
+	}
+	shadowHash, err := repo.Storer.SetEncodedObject(obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	refNames := []plumbing.ReferenceName{
+		plumbing.NewBranchReferenceName(checkpoint.ShadowBranchPrefix + "abc1234-def456"),
+		plumbing.ReferenceName(strategy.ShadowArchiveRefPrefix + "0123456-def456"),
+		plumbing.ReferenceName(strategy.PointRefPrefix + "1-add-a-login-form"),
+	}
+	for _, name := range refNames {
+		if err := repo.Storer.SetReference(plumbing.NewHashReference(name, shadowHash)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var out bytes.Buffer
+	if err := runPurgeSession(ctx, &out, "private-session", "", time.Now()); err != nil {
+		t.Fatalf("runPurgeSession() error = %v", err)
+	}
+	for _, name := range refNames {
+		if !strings.Contains(out.String(), "Rewrote "+name.String()) {
+			t.Errorf("output should report %s, got: %s", name, out.String())
+		}
+		ref, err := repo.Reference(name, true)
+		if err != nil {
+			t.Fatalf("Reference(%s) error = %v", name, err)
+		}
+		commit, err := repo.CommitObject(ref.Hash())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(commit.ParentHashes) != 1 || commit.ParentHashes[0] != head.Hash() {
+			t.Errorf("%s parents = %v, want the user commit %s kept", name, commit.ParentHashes, head.Hash())
+		}
+	}
+
+	reachable := reachableObjects(t, repo)
+	if reachable[transcript] {
+		t.Error("the purged transcript is still reachable from a ref")
+	}
+	if !reachable[other] {
+		t.Error("another session's transcript was purged too")
+	}
+}
+
+func TestRunPurgeSession_RefusesRunningSession(t *testing.T) {
+	dir := setupExecTestRepo(t)
+	ctx := context.Background()
+	writePurgeTestCheckpoint(t, id.MustCheckpointID("f6f6f6f6f6f6"))
+	if err := strategy.SaveSessionState(ctx, &strategy.SessionState{
+		SessionID:    "private-session",
+		WorktreePath: dir,
+		StartedAt:    time.Now(),
+		Phase:        session.PhaseIdle,
+	}); err != nil {
+		t.Fatalf("SaveSessionState() error = %v", err)
+	}
+
+	var out bytes.Buffer
+	err := runPurgeSession(ctx, &out, "private-session", "", time.Now())
+	if err == nil || !strings.Contains(err.Error(), "entire reset --session private-session") {
+		t.Errorf("runPurgeSession() error = %v, want a hint to reset the session first", err)
+	}
+}
@@ -0,0 +1,218 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/logging"
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+	"github.com/entireio/cli/cmd/entire/cli/strategy"
+	"github.com/entireio/cli/cmd/entire/cli/stringutil"
+
+	"github.com/spf13/cobra"
+)
+
+// maxRecallInput caps the hook payload read from stdin.
+const maxRecallInput = 1 << 20
+
+// Limits on what one checkpoint contributes to the context block.
+const (
+	recallPromptsPerCheckpoint   = 3
+	recallLearningsPerCheckpoint = 5
+	recallExcerptLength          = 300
+	recallFilesPerCheckpoint     = 8
+)
+
+const recallHeader = `Relevant past work from this repository's Entire checkpoints, most relevant
+first. The code may have changed since; check before relying on it.`
+
+func newRecallCmd() *cobra.Command {
+	var maxTokensFlag int
+	var limitFlag int
+
+	cmd := &cobra.Command{
+		Use:   "recall [query]",
+		Short: "Print past checkpoints relevant to a prompt, sized for agent context",
+		Long: `Recall finds the committed checkpoints most relevant to a query and prints
+their prompts and summaries as a context block for an agent, fitted to a
+token budget (about four characters per token).
+
+Checkpoints are ranked with BM25 over their prompts, AI summaries, and the
+files they touched. The text of each checkpoint is indexed once and cached
+in the git directory (` + checkpoint.RecallIndexFileName + `) until the checkpoint
+changes; 'entire index rebuild' and 'entire purge-session' discard the cache.
+With checkpoint_store set, every checkpoint is read on each call.
+
+Without a query argument, recall reads the prompt from stdin: either the
+JSON a UserPromptSubmit hook receives (its "prompt" field) or plain text.
+Read that way, recall never fails the hook; errors are logged and nothing
+is printed. For Claude Code, add to .claude/settings.json:
+
+  "UserPromptSubmit": [{"hooks": [{"type": "command", "command": "entire recall --max-tokens 1000"}]}]
+
+Whatever the hook prints is added to the agent's context.`,
+		Args: cobra.ArbitraryArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			if len(args) > 0 {
+				if _, err := paths.WorktreeRoot(ctx); err != nil {
+					return paths.ErrNotGitRepository
+				}
+				return runRecall(ctx, cmd.OutOrStdout(), strings.Join(args, " "), maxTokensFlag, limitFlag)
+			}
+
+			// From a hook: print nothing rather than get in the prompt's way
+			query, err := readRecallQuery(cmd.InOrStdin())
+			if err == nil {
+				_, err = paths.WorktreeRoot(ctx)
+			}
+			if err == nil {
+				var out strings.Builder
+				if err = runRecall(ctx, &out, query, maxTokensFlag, limitFlag); err == nil {
+					fmt.Fprint(cmd.OutOrStdout(), out.String())
+				}
+			}
+			if err != nil {
+				logging.Warn(logging.WithComponent(ctx, "recall"), "recall failed",
+					slog.String("error", err.Error()))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().IntVar(&maxTokensFlag, "max-tokens", 1000, "Token budget of the context block")
+	cmd.Flags().IntVarP(&limitFlag, "limit", "n", 5, "Maximum number of checkpoints to include")
+
+	return cmd
+}
+
+// readRecallQuery reads the query from a hook payload with a "prompt"
+// field, or takes the input as plain text.
+func readRecallQuery(r io.Reader) (string, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxRecallInput))
+	if err != nil {
+		return "", fmt.Errorf("failed to read query: %w", err)
+	}
+	var payload struct {
+		Prompt string `json:"prompt"`
+	}
+	if json.Unmarshal(data, &payload) == nil && payload.Prompt != "" {
+		return payload.Prompt, nil
+	}
+	return string(data), nil
+}
+
+func runRecall(ctx context.Context, w io.Writer, query string, maxTokens, limit int) error {
+	if maxTokens <= 0 {
+		return errors.New("--max-tokens must be positive")
+	}
+	repo, err := openRepository(ctx)
+	if err != nil {
+		return fmt.Errorf("not a git repository: %w", err)
+	}
+	gitStore := checkpoint.NewGitStore(repo)
+	store, err := strategy.CommittedStore(ctx, gitStore)
+	if err != nil {
+		return err //nolint:wrapcheck // already names the setting
+	}
+
+	result, err := checkpoint.Recall(ctx, store, checkpoint.RecallOptions{
+		Query:     query,
+		Limit:     limit,
+		IndexPath: gitStore.RecallIndexPath(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to recall checkpoints: %w", err)
+	}
+
+	fmt.Fprint(w, formatRecall(result.Hits, maxTokens))
+	return nil
+}
+
+// formatRecall renders hits as a context block of at most maxTokens
+// estimated tokens. Checkpoints that don't fit are left out, so a smaller,
+// less relevant one may still make it in. Returns "" without hits.
+func formatRecall(hits []checkpoint.RecallHit, maxTokens int) string {
+	if len(hits) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString(recallHeader + "\n")
+	used := estimateTokens(sb.String())
+	added := 0
+	for _, hit := range hits {
+		block := "\n" + formatRecallHit(hit)
+		if cost := estimateTokens(block); used+cost <= maxTokens {
+			sb.WriteString(block)
+			used += cost
+			added++
+		}
+	}
+	if added == 0 {
+		return ""
+	}
+	return sb.String()
+}
+
+// formatRecallHit renders one checkpoint of the context block.
+func formatRecallHit(hit checkpoint.RecallHit) string {
+	var sb strings.Builder
+	info := hit.Info
+	title := "## Checkpoint " + info.CheckpointID.String() + " (" + info.CreatedAt.Local().Format("2006-01-02")
+	if info.Branch != "" {
+		title += ", " + info.Branch
+	}
+	sb.WriteString(title + ")\n")
+
+	excerpt := func(s string) string {
+		return stringutil.TruncateRunes(stringutil.CollapseWhitespace(s), recallExcerptLength, "…")
+	}
+	for i, prompt := range hit.Prompts {
+		if i == recallPromptsPerCheckpoint {
+			fmt.Fprintf(&sb, "- (%d more prompts)\n", len(hit.Prompts)-i)
+			break
+		}
+		sb.WriteString("- Asked: " + excerpt(prompt) + "\n")
+	}
+	if s := hit.Summary; s != nil {
+		if s.Intent != "" {
+			sb.WriteString("- Intent: " + excerpt(s.Intent) + "\n")
+		}
+		if s.Outcome != "" {
+			sb.WriteString("- Outcome: " + excerpt(s.Outcome) + "\n")
+		}
+		learnings := append([]string{}, s.Learnings.Repo...)
+		for _, learning := range s.Learnings.Code {
+			learnings = append(learnings, learning.Path+": "+learning.Finding)
+		}
+		learnings = append(learnings, s.Learnings.Workflow...)
+		for i, learning := range learnings {
+			if i == recallLearningsPerCheckpoint {
+				break
+			}
+			sb.WriteString("- Learned: " + excerpt(learning) + "\n")
+		}
+		for _, item := range s.OpenItems {
+			sb.WriteString("- Open: " + excerpt(item) + "\n")
+		}
+	}
+	if files := info.FilesTouched; len(files) > 0 {
+		if len(files) > recallFilesPerCheckpoint {
+			files = append(files[:recallFilesPerCheckpoint:recallFilesPerCheckpoint], fmt.Sprintf("%d more", len(info.FilesTouched)-recallFilesPerCheckpoint))
+		}
+		sb.WriteString("- Files: " + strings.Join(files, ", ") + "\n")
+	}
+	return sb.String()
+}
+
+// estimateTokens estimates the tokens of s at about four characters each.
+func estimateTokens(s string) int {
+	return (utf8.RuneCountInString(s) + 3) / 4
+}
@@ -0,0 +1,85 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+)
+
+func TestReadRecallQuery(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{`{"session_id":"s1","hook_event_name":"UserPromptSubmit","prompt":"fix the login redirect"}`, "fix the login redirect"},
+		{"fix the login redirect\n", "fix the login redirect\n"},
+		{`{"prompt":""}`, `{"prompt":""}`},
+	}
+	for _, tt := range tests {
+		got, err := readRecallQuery(strings.NewReader(tt.input))
+		if err != nil || got != tt.want {
+			t.Errorf("readRecallQuery(%q) = %q, %v; want %q", tt.input, got, err, tt.want)
+		}
+	}
+}
+
+func TestFormatRecall(t *testing.T) {
+	t.Parallel()
+
+	created := time.Date(2026, 1, 2, 12, 0, 0, 0, time.Local)
+	relevant := checkpoint.RecallHit{
+		Info: checkpoint.CommittedInfo{
+			CheckpointID: id.MustCheckpointID("a1a2a3a4a5a6"),
+			CreatedAt:    created,
+			Branch:       "feat/login",
+			FilesTouched: []string{"auth/login.go"},
+		},
+		Prompts: []string{"Fix the login\n\nredirect"},
+		Summary: &checkpoint.Summary{
+			Intent:    "Keep the return URL",
+			Learnings: checkpoint.LearningsSummary{Code: []checkpoint.CodeLearning{{Path: "auth/middleware.go", Finding: "Sessions are validated here"}}},
+		},
+	}
+	large := checkpoint.RecallHit{
+		Info:    checkpoint.CommittedInfo{CheckpointID: id.MustCheckpointID("b1b2b3b4b5b6"), CreatedAt: created},
+		Prompts: []string{strings.Repeat("word ", 200)},
+	}
+	small := checkpoint.RecallHit{
+		Info:    checkpoint.CommittedInfo{CheckpointID: id.MustCheckpointID("c1c2c3c4c5c6"), CreatedAt: created},
+		Prompts: []string{"Rename the cookie"},
+	}
+
+	got := formatRecall([]checkpoint.RecallHit{relevant, large, small}, 150)
+	for _, want := range []string{
+		recallHeader,
+		"## Checkpoint a1a2a3a4a5a6 (2026-01-02, feat/login)",
+		"- Asked: Fix the login redirect",
+		"- Intent: Keep the return URL",
+		"- Learned: auth/middleware.go: Sessions are validated here",
+		"- Files: auth/login.go",
+		"## Checkpoint c1c2c3c4c5c6 (2026-01-02)",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("formatRecall() missing %q:\n%s", want, got)
+		}
+	}
+	// The large checkpoint doesn't fit the budget, but the smaller one after it does
+	if strings.Contains(got, "b1b2b3b4b5b6") {
+		t.Errorf("formatRecall() included a checkpoint over the budget:\n%s", got)
+	}
+	if tokens := estimateTokens(got); tokens > 150 {
+		t.Errorf("formatRecall() used %d tokens, want at most 150", tokens)
+	}
+
+	if got := formatRecall(nil, 1000); got != "" {
+		t.Errorf("formatRecall(nil) = %q, want empty", got)
+	}
+	if got := formatRecall([]checkpoint.RecallHit{large}, 60); got != "" {
+		t.Errorf("formatRecall() with nothing fitting = %q, want empty", got)
+	}
+}
@@ -0,0 +1,86 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+
+	"github.com/entireio/cli/cmd/entire/cli/logging"
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+	"github.com/entireio/cli/cmd/entire/cli/strategy"
+
+	"github.com/spf13/cobra"
+)
+
+func newReconcileCmd() *cobra.Command {
+	var strictFlag bool
+
+	cmd := &cobra.Command{
+		Use:   "reconcile",
+		Short: "Update checkpoints whose transcript was written late",
+		Long: `Reconcile checks each session's latest checkpoint against the agent's
+transcript on disk. Some agents finish writing their transcript after the stop
+hook fires, so the checkpoint keeps a truncated copy. When the transcript on
+disk is longer, the checkpoint is updated with the complete content.
+
+This also happens automatically when the session's next prompt starts or the
+session ends.
+
+With --strict, a checkpoint that no longer has the session is reported instead
+of updating the checkpoint's latest session.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := cmd.Context()
+			if _, err := paths.WorktreeRoot(ctx); err != nil {
+				return paths.ErrNotGitRepository
+			}
+			return runReconcile(ctx, cmd.OutOrStdout(), strictFlag)
+		},
+	}
+
+	cmd.Flags().BoolVar(&strictFlag, "strict", false, "Fail instead of updating another session when the checkpoint lacks the session")
+
+	return cmd
+}
+
+func runReconcile(ctx context.Context, w io.Writer, strict bool) error {
+	states, err := strategy.ListSessionStates(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list session states: %w", err)
+	}
+
+	strat := GetStrategy(ctx)
+	updated := 0
+	for _, state := range states {
+		ok, err := strat.ReconcileTranscript(ctx, state, strict)
+		if err != nil {
+			fmt.Fprintf(w, "Warning: session %s: %v\n", state.SessionID, err)
+			continue
+		}
+		if ok {
+			updated++
+			fmt.Fprintf(w, "Updated checkpoint %s with the complete transcript of session %s\n", state.LastCheckpointID, state.SessionID)
+		}
+	}
+
+	if updated == 0 {
+		fmt.Fprintln(w, "All checkpoints have complete transcripts.")
+	}
+	return nil
+}
+
+// reconcileSessionTranscript catches up the session's last checkpoint with a
+// transcript the agent wrote after the previous turn ended. Best-effort.
+func reconcileSessionTranscript(ctx context.Context, sessionID string) {
+	logCtx := logging.WithComponent(ctx, "lifecycle")
+	state, err := strategy.LoadSessionState(ctx, sessionID)
+	if err != nil || state == nil {
+		return
+	}
+	if _, err := GetStrategy(ctx).ReconcileTranscript(ctx, state, false); err != nil {
+		logging.Warn(logCtx, "failed to reconcile transcript",
+			slog.String("session_id", sessionID),
+			slog.String("error", err.Error()))
+	}
+}
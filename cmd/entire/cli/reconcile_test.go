@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+	"github.com/entireio/cli/cmd/entire/cli/session"
+	"github.com/entireio/cli/cmd/entire/cli/strategy"
+)
+
+func TestRunReconcile(t *testing.T) {
+	dir := setupExecTestRepo(t)
+	ctx := context.Background()
+
+	repo, err := openRepository(ctx)
+	if err != nil {
+		t.Fatalf("openRepository() error = %v", err)
+	}
+	truncated := `{"type":"user","message":{"content":"fix the bug"}}` + "\n"
+	cpID := id.MustCheckpointID("d4d4d4d4d4d4")
+	if err := checkpoint.NewGitStore(repo).WriteCommitted(ctx, checkpoint.WriteCommittedOptions{
+		CheckpointID: cpID,
+		SessionID:    "late-session",
+		Strategy:     strategy.StrategyNameManualCommit,
+		Transcript:   []byte(truncated),
+		AuthorName:   "Test",
+		AuthorEmail:  "test@test.com",
+	}); err != nil {
+		t.Fatalf("WriteCommitted() error = %v", err)
+	}
+
+	transcriptPath := filepath.Join(dir, "transcript.jsonl")
+	complete := truncated + `{"type":"assistant","message":{"content":[{"type":"text","text":"Fixed."}]}}` + "\n"
+	if err := os.WriteFile(transcriptPath, []byte(complete), 0o600); err != nil {
+		t.Fatalf("failed to write transcript: %v", err)
+	}
+	if err := strategy.SaveSessionState(ctx, &strategy.SessionState{
+		SessionID:        "late-session",
+		WorktreePath:     dir,
+		StartedAt:        time.Now(),
+		Phase:            session.PhaseIdle,
+		AgentType:        "Claude Code",
+		TranscriptPath:   transcriptPath,
+		LastCheckpointID: cpID,
+	}); err != nil {
+		t.Fatalf("SaveSessionState() error = %v", err)
+	}
+
+	var out bytes.Buffer
+	if err := runReconcile(ctx, &out, false); err != nil {
+		t.Fatalf("runReconcile() error = %v", err)
+	}
+	if !strings.Contains(out.String(), "Updated checkpoint d4d4d4d4d4d4 with the complete transcript of session late-session") {
+		t.Errorf("output should report the update, got: %s", out.String())
+	}
+
+	out.Reset()
+	if err := runReconcile(ctx, &out, false); err != nil {
+		t.Fatalf("runReconcile() error = %v", err)
+	}
+	if !strings.Contains(out.String(), "All checkpoints have complete transcripts.") {
+		t.Errorf("second run should find nothing to do, got: %s", out.String())
+	}
+}
@@ -0,0 +1,173 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+	"github.com/entireio/cli/cmd/entire/cli/strategy"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/spf13/cobra"
+)
+
+func newRemapCmd() *cobra.Command {
+	var mapFlags []string
+	var filterRepoMapFlag string
+	var dryRunFlag bool
+
+	cmd := &cobra.Command{
+		Use:   "remap",
+		Short: "Update sessions and shadow branches after a history rewrite",
+		Long: `Point Entire's session state and shadow branches at rewritten commits
+after the repository history was rewritten, e.g. with git filter-repo to
+remove a secret.
+
+Give the mapping as old=new pairs, or pass the commit map git filter-repo
+writes to .git/filter-repo/commit-map:
+
+  entire remap --filter-repo-map .git/filter-repo/commit-map
+  entire remap --map <old-hash>=<new-commit>
+
+Old commits must be full hashes, since the rewrite usually removed them.
+Sessions based on a commit the rewrite dropped are reported and left as
+they are. Commits link to their checkpoints through the Entire-Checkpoint
+trailer, which the rewrite keeps, so the metadata branch is not changed.
+
+Use --dry-run to see what would change.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := cmd.Context()
+			if _, err := paths.WorktreeRoot(ctx); err != nil {
+				return paths.ErrNotGitRepository
+			}
+			if len(mapFlags) == 0 && filterRepoMapFlag == "" {
+				return errors.New("give --map <old>=<new> or --filter-repo-map <file>")
+			}
+			mapping := make(map[string]string)
+			if filterRepoMapFlag != "" {
+				f, err := os.Open(filterRepoMapFlag) //nolint:gosec // path given by the user
+				if err != nil {
+					return fmt.Errorf("failed to open commit map: %w", err)
+				}
+				defer f.Close()
+				if mapping, err = parseFilterRepoMap(f); err != nil {
+					return fmt.Errorf("%s: %w", filterRepoMapFlag, err)
+				}
+			}
+			for _, pair := range mapFlags {
+				oldHash, newHash, err := resolveRemapPair(ctx, pair)
+				if err != nil {
+					return err
+				}
+				mapping[oldHash] = newHash
+			}
+			return runRemap(ctx, cmd.OutOrStdout(), mapping, dryRunFlag)
+		},
+	}
+
+	cmd.Flags().StringArrayVar(&mapFlags, "map", nil, "Map an old commit hash to its rewritten commit, as <old>=<new> (repeatable)")
+	cmd.Flags().StringVar(&filterRepoMapFlag, "filter-repo-map", "", "Read the mapping from a git filter-repo commit-map file")
+	cmd.Flags().BoolVar(&dryRunFlag, "dry-run", false, "Show what would change without changing it")
+
+	return cmd
+}
+
+func runRemap(ctx context.Context, w io.Writer, mapping map[string]string, dryRun bool) error {
+	result, err := strategy.RemapCommits(ctx, mapping, dryRun)
+	if err != nil {
+		return err //nolint:wrapcheck // already wrapped by strategy
+	}
+
+	if len(result.Sessions) == 0 && len(result.Branches) == 0 &&
+		len(result.Conflicts) == 0 && len(result.Orphaned) == 0 {
+		fmt.Fprintln(w, "Nothing to remap.")
+		return nil
+	}
+
+	remapped, renamed := "Remapped", "Renamed"
+	if dryRun {
+		remapped, renamed = "Would remap", "Would rename"
+	}
+	if len(result.Sessions) > 0 {
+		fmt.Fprintf(w, "%s %d session(s):\n", remapped, len(result.Sessions))
+		for _, sessionID := range result.Sessions {
+			fmt.Fprintf(w, "  %s\n", sessionID)
+		}
+	}
+	if len(result.Branches) > 0 {
+		fmt.Fprintf(w, "%s %d shadow branch(es):\n", renamed, len(result.Branches))
+		for _, rename := range result.Branches {
+			fmt.Fprintf(w, "  %s -> %s\n", rename.From, rename.To)
+		}
+	}
+	if len(result.Conflicts) > 0 {
+		fmt.Fprintf(w, "Left %d shadow branch(es) in place; the new name is taken or ambiguous:\n", len(result.Conflicts))
+		for _, branch := range result.Conflicts {
+			fmt.Fprintf(w, "  %s\n", branch)
+		}
+	}
+	if len(result.Orphaned) > 0 {
+		fmt.Fprintf(w, "%d session(s) are based on commits the rewrite removed:\n", len(result.Orphaned))
+		for _, sessionID := range result.Orphaned {
+			fmt.Fprintf(w, "  %s\n", sessionID)
+		}
+	}
+	return nil
+}
+
+// parseFilterRepoMap reads the commit map git filter-repo writes: a header
+// line followed by "<old> <new>" lines of full hashes, where an all-zero new
+// hash marks a commit the rewrite removed. Unchanged commits are skipped.
+func parseFilterRepoMap(r io.Reader) (map[string]string, error) {
+	mapping := make(map[string]string)
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || (line == 1 && len(fields) == 2 && fields[0] == "old" && fields[1] == "new") {
+			continue
+		}
+		if len(fields) != 2 || !plumbing.IsHash(fields[0]) || !plumbing.IsHash(fields[1]) {
+			return nil, fmt.Errorf("line %d: want \"<old-hash> <new-hash>\"", line)
+		}
+		if fields[0] != fields[1] {
+			mapping[fields[0]] = fields[1]
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read commit map: %w", err)
+	}
+	return mapping, nil
+}
+
+// resolveRemapPair parses an "<old>=<new>" --map value. The old side must be
+// a full hash; the new side may be any revision of the rewritten history.
+func resolveRemapPair(ctx context.Context, pair string) (string, string, error) {
+	oldHash, newRev, ok := strings.Cut(pair, "=")
+	if !ok || newRev == "" {
+		return "", "", fmt.Errorf("invalid --map %q: want <old>=<new>", pair)
+	}
+	oldHash = strings.ToLower(strings.TrimSpace(oldHash))
+	if !plumbing.IsHash(oldHash) {
+		return "", "", fmt.Errorf("invalid --map %q: the old commit must be a full 40-character hash", pair)
+	}
+	if plumbing.IsHash(newRev) {
+		return oldHash, strings.ToLower(newRev), nil
+	}
+	repo, err := openRepository(ctx)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to open repository: %w", err)
+	}
+	newHash, err := repo.ResolveRevision(plumbing.Revision(newRev))
+	if err != nil {
+		return "", "", fmt.Errorf("invalid --map %q: %w", pair, err)
+	}
+	return oldHash, newHash.String(), nil
+}
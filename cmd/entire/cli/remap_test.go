@@ -0,0 +1,63 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestParseFilterRepoMap(t *testing.T) {
+	t.Parallel()
+
+	oldA, newA := strings.Repeat("a", 40), strings.Repeat("b", 40)
+	oldB, zero := strings.Repeat("c", 40), strings.Repeat("0", 40)
+	same := strings.Repeat("d", 40)
+	input := "old                                      new\n" +
+		oldA + " " + newA + "\n" +
+		oldB + " " + zero + "\n" +
+		same + " " + same + "\n"
+
+	mapping, err := parseFilterRepoMap(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("parseFilterRepoMap() error = %v", err)
+	}
+	if len(mapping) != 2 || mapping[oldA] != newA || mapping[oldB] != zero {
+		t.Errorf("parseFilterRepoMap() = %v", mapping)
+	}
+
+	if _, err := parseFilterRepoMap(strings.NewReader(oldA + " not-a-hash\n")); err == nil || !strings.Contains(err.Error(), "line 1") {
+		t.Errorf("parseFilterRepoMap() of a bad line error = %v, want a line number", err)
+	}
+}
+
+func TestResolveRemapPair(t *testing.T) {
+	_, head := setupCleanTestRepo(t)
+	oldHash := strings.Repeat("e", 40)
+
+	gotOld, gotNew, err := resolveRemapPair(context.Background(), strings.ToUpper(oldHash)+"=HEAD")
+	if err != nil {
+		t.Fatalf("resolveRemapPair() error = %v", err)
+	}
+	if gotOld != oldHash || gotNew != head.String() {
+		t.Errorf("resolveRemapPair() = %s, %s; want %s, %s", gotOld, gotNew, oldHash, head)
+	}
+
+	for _, pair := range []string{"HEAD=HEAD", oldHash, oldHash + "="} {
+		if _, _, err := resolveRemapPair(context.Background(), pair); err == nil {
+			t.Errorf("resolveRemapPair(%q) should fail", pair)
+		}
+	}
+}
+
+func TestRunRemap_NothingToRemap(t *testing.T) {
+	setupCleanTestRepo(t)
+
+	var out bytes.Buffer
+	if err := runRemap(context.Background(), &out, map[string]string{strings.Repeat("f", 40): strings.Repeat("0", 40)}, false); err != nil {
+		t.Fatalf("runRemap() error = %v", err)
+	}
+	if !strings.Contains(out.String(), "Nothing to remap.") {
+		t.Errorf("runRemap() output = %q", out.String())
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 
 	"github.com/charmbracelet/huh"
+	"github.com/entireio/cli/cmd/entire/cli/i18n"
 	"github.com/entireio/cli/cmd/entire/cli/paths"
 	"github.com/entireio/cli/cmd/entire/cli/session"
 	"github.com/entireio/cli/cmd/entire/cli/strategy"
@@ -41,7 +42,7 @@ Without --force, prompts for confirmation before deleting.`,
 			ctx := cmd.Context()
 			// Check if in git repository
 			if _, err := paths.WorktreeRoot(ctx); err != nil {
-				return errors.New("not a git repository")
+				return paths.ErrNotGitRepository
 			}
 
 			// Get current strategy
@@ -75,8 +76,8 @@ Without --force, prompts for confirmation before deleting.`,
 
 				form := NewAccessibleForm(
 					huh.NewGroup(
-						huh.NewConfirm().
-							Title("Reset session data?").
+						newConfirm().
+							Title(i18n.T(i18n.ConfirmResetSessionData)).
 							Value(&confirmed),
 					),
 				)
@@ -122,12 +123,12 @@ func runResetSession(ctx context.Context, cmd *cobra.Command, strat *strategy.Ma
 	if !force {
 		var confirmed bool
 
-		title := fmt.Sprintf("Reset session %s?", sessionID)
-		description := fmt.Sprintf("Phase: %s, Checkpoints: %d", state.Phase, state.StepCount)
+		title := i18n.T(i18n.ConfirmResetSession, sessionID)
+		description := i18n.T(i18n.ConfirmResetSessionDesc, state.Phase, state.StepCount)
 
 		form := NewAccessibleForm(
 			huh.NewGroup(
-				huh.NewConfirm().
+				newConfirm().
 					Title(title).
 					Description(description).
 					Value(&confirmed),
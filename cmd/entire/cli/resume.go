@@ -12,6 +12,7 @@ import (
 	"github.com/entireio/cli/cmd/entire/cli/agent"
 	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
 	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+	"github.com/entireio/cli/cmd/entire/cli/i18n"
 	"github.com/entireio/cli/cmd/entire/cli/logging"
 	"github.com/entireio/cli/cmd/entire/cli/paths"
 	"github.com/entireio/cli/cmd/entire/cli/strategy"
@@ -307,8 +308,8 @@ func promptResumeFromOlderCheckpoint() (bool, error) {
 
 	form := NewAccessibleForm(
 		huh.NewGroup(
-			huh.NewConfirm().
-				Title("Resume from this older checkpoint?").
+			newConfirm().
+				Title(i18n.T(i18n.ConfirmResumeOlder)).
 				Value(&confirmed),
 		),
 	)
@@ -323,11 +324,13 @@ func promptResumeFromOlderCheckpoint() (bool, error) {
 	return confirmed, nil
 }
 
-// checkRemoteMetadata checks if checkpoint metadata exists on origin/entire/checkpoints/v1
-// and automatically fetches it if available.
+// checkRemoteMetadata checks if checkpoint metadata exists on the metadata remote's
+// entire/checkpoints/v1 (origin unless sync.remote is set) and automatically fetches it if available.
 func checkRemoteMetadata(ctx context.Context, repo *git.Repository, checkpointID id.CheckpointID) error {
+	remote := strategy.MetadataRemote(ctx)
+
 	// Try to get remote metadata branch tree
-	remoteTree, err := strategy.GetRemoteMetadataBranchTree(repo)
+	remoteTree, err := strategy.GetRemoteMetadataBranchTree(repo, remote)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Checkpoint '%s' found in commit but session metadata not available\n", checkpointID)
 		fmt.Fprintf(os.Stderr, "The entire/checkpoints/v1 branch may not exist locally or on the remote.\n")
@@ -342,10 +345,10 @@ func checkRemoteMetadata(ctx context.Context, repo *git.Repository, checkpointID
 	}
 
 	// Metadata exists on remote but not locally - fetch it automatically
-	fmt.Fprintf(os.Stderr, "Fetching session metadata from origin...\n")
+	fmt.Fprintf(os.Stderr, "Fetching session metadata from %s...\n", remote)
 	if err := FetchMetadataBranch(ctx); err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to fetch metadata: %v\n", err)
-		fmt.Fprintf(os.Stderr, "You can try manually: git fetch origin entire/checkpoints/v1:entire/checkpoints/v1\n")
+		fmt.Fprintf(os.Stderr, "You can try manually: git fetch %s entire/checkpoints/v1:entire/checkpoints/v1\n", remote)
 		return NewSilentError(errors.New("failed to fetch metadata"))
 	}
 
@@ -575,8 +578,8 @@ func promptFetchFromRemote(branchName string) (bool, error) {
 
 	form := NewAccessibleForm(
 		huh.NewGroup(
-			huh.NewConfirm().
-				Title(fmt.Sprintf("Branch '%s' not found locally. Fetch from origin?", branchName)).
+			newConfirm().
+				Title(i18n.T(i18n.ConfirmFetchBranch, branchName)).
 				Value(&confirmed),
 		),
 	)
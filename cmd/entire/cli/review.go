@@ -0,0 +1,316 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+	"github.com/entireio/cli/cmd/entire/cli/codeowners"
+	"github.com/entireio/cli/cmd/entire/cli/logging"
+	"github.com/entireio/cli/cmd/entire/cli/pathfilter"
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+	"github.com/entireio/cli/cmd/entire/cli/strategy"
+	"github.com/spf13/cobra"
+)
+
+func newReviewCmd() *cobra.Command {
+	var approveFlag, requestChangesFlag, checkFlag, jsonFlag, mineFlag bool
+	var messageFlag string
+	var pathFlags []string
+
+	cmd := &cobra.Command{
+		Use:   "review [<checkpoint-id> | --mine]",
+		Short: "Approve a checkpoint, request changes, or show its review state",
+		Long: `Review records a verdict on a committed checkpoint, or shows its review
+state. Verdicts are stored as comments on the entire/checkpoints/v1 branch
+with the reviewer's git name and email, so approvals travel with the
+metadata branch.
+
+A checkpoint is:
+  pending            until it has enough approvals
+  approved           once enough reviewers approved and none requested changes
+  changes_requested  while any reviewer's latest verdict asks for changes
+
+Only each reviewer's latest verdict counts. One approval is enough unless
+"review": {"required_approvals": N} is set in settings.
+
+With --check, review exits with an error unless the checkpoint is approved,
+so it can gate scripts and CI jobs that promote checkpoints.
+
+When the repository has a CODEOWNERS file (.github/, the root, or docs/),
+review suggests reviewers: the owners of the files the checkpoint touched.
+With --mine, review lists checkpoints awaiting approval that touch files you
+own, matching your git email and any "review": {"handles": ["@you"]} set in
+settings.local.json. Add --path to only list checkpoints whose commits on the
+current branch touched matching files, with the same patterns as
+'entire log --path'.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			if _, err := paths.WorktreeRoot(ctx); err != nil {
+				return paths.ErrNotGitRepository
+			}
+			if mineFlag {
+				if len(args) > 0 || approveFlag || requestChangesFlag || checkFlag || messageFlag != "" {
+					return errors.New("--mine lists checkpoints and takes no checkpoint ID or verdict")
+				}
+				filter, err := pathfilter.New(pathFlags)
+				if err != nil {
+					return err //nolint:wrapcheck // already descriptive
+				}
+				return runReviewMine(ctx, cmd.OutOrStdout(), filter, jsonFlag)
+			}
+			if len(pathFlags) > 0 {
+				return errors.New("--path filters the --mine listing")
+			}
+			if len(args) == 0 {
+				return errors.New("a checkpoint ID is required unless --mine is given")
+			}
+			if approveFlag && requestChangesFlag {
+				return errors.New("--approve and --request-changes cannot be used together")
+			}
+			var verdict checkpoint.ReviewState
+			switch {
+			case approveFlag:
+				verdict = checkpoint.ReviewApproved
+			case requestChangesFlag:
+				verdict = checkpoint.ReviewChangesRequested
+			case messageFlag != "":
+				return errors.New("--message needs --approve or --request-changes; use 'entire comment' for plain comments")
+			}
+			return runReview(ctx, cmd.OutOrStdout(), args[0], verdict, messageFlag, checkFlag, jsonFlag)
+		},
+	}
+
+	cmd.Flags().BoolVar(&approveFlag, "approve", false, "Approve the checkpoint")
+	cmd.Flags().BoolVar(&requestChangesFlag, "request-changes", false, "Request changes to the checkpoint")
+	cmd.Flags().StringVarP(&messageFlag, "message", "m", "", "Explain the verdict")
+	cmd.Flags().BoolVar(&checkFlag, "check", false, "Fail unless the checkpoint is approved")
+	cmd.Flags().BoolVar(&jsonFlag, "json", false, "Output the review state as JSON")
+	cmd.Flags().BoolVar(&mineFlag, "mine", false, "List checkpoints awaiting approval that touch files you own")
+	addPathFlag(cmd, &pathFlags, "With --mine, only list checkpoints that touched paths matching this glob")
+
+	return cmd
+}
+
+func runReview(ctx context.Context, w io.Writer, checkpointIDPrefix string, verdict checkpoint.ReviewState, message string, check, asJSON bool) error {
+	repo, err := openRepository(ctx)
+	if err != nil {
+		return fmt.Errorf("not a git repository: %w", err)
+	}
+	store, err := strategy.CommittedStore(ctx, checkpoint.NewGitStore(repo))
+	if err != nil {
+		return err //nolint:wrapcheck // already names the setting
+	}
+
+	checkpointID, err := resolveCommittedCheckpointID(ctx, store, checkpointIDPrefix)
+	if err != nil {
+		return err
+	}
+	if verdict != "" {
+		comment, err := store.AddComment(ctx, checkpointID, checkpoint.Comment{Verdict: verdict, Body: message})
+		if err != nil {
+			return fmt.Errorf("failed to record review: %w", err)
+		}
+		if !asJSON {
+			fmt.Fprintf(w, "Recorded %s by %s on checkpoint %s\n", verdict, reviewerLabel(comment.AuthorName, comment.AuthorEmail), checkpointID)
+		}
+	}
+
+	comments, err := store.ReadComments(ctx, checkpointID)
+	if err != nil {
+		return fmt.Errorf("failed to read comments: %w", err)
+	}
+	status := checkpoint.ComputeReviewStatus(comments, requiredApprovals(ctx))
+	suggested := suggestedReviewers(ctx, store, checkpointID)
+
+	if asJSON {
+		data, err := json.MarshalIndent(reviewOutput{ReviewStatus: status, SuggestedReviewers: suggested}, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode review state: %w", err)
+		}
+		fmt.Fprintln(w, string(data))
+	} else {
+		fmt.Fprint(w, formatReviewStatus(status))
+		if len(suggested) > 0 {
+			fmt.Fprintf(w, "Suggested reviewers (CODEOWNERS): %s\n", strings.Join(suggested, ", "))
+		}
+	}
+
+	if check && status.State != checkpoint.ReviewApproved {
+		return NewSilentError(fmt.Errorf("checkpoint %s is not approved (%s)", checkpointID, status.State))
+	}
+	return nil
+}
+
+// reviewOutput is the --json form of a checkpoint's review.
+type reviewOutput struct {
+	checkpoint.ReviewStatus
+
+	SuggestedReviewers []string `json:"suggested_reviewers,omitempty"`
+}
+
+// suggestedReviewers returns the CODEOWNERS owners of the files a checkpoint
+// touched, owners of the most files first. Best-effort: returns nil without
+// a CODEOWNERS file or if the checkpoint can't be read.
+func suggestedReviewers(ctx context.Context, store checkpoint.CommittedStore, checkpointID id.CheckpointID) []string {
+	rules := loadCodeowners(ctx)
+	if rules == nil {
+		return nil
+	}
+	summary, err := store.ReadCommitted(ctx, checkpointID)
+	if err != nil || summary == nil {
+		return nil
+	}
+	return codeowners.SortedOwners(rules.OwnersOf(summary.FilesTouched))
+}
+
+// loadCodeowners returns the repository's CODEOWNERS rules, or nil if there
+// are none. Unparseable lines are logged and skipped.
+func loadCodeowners(ctx context.Context) *codeowners.Ruleset {
+	repoRoot, err := paths.WorktreeRoot(ctx)
+	if err != nil {
+		return nil
+	}
+	rules, err := codeowners.Load(repoRoot)
+	if err != nil {
+		logging.Warn(ctx, "ignoring invalid CODEOWNERS lines", slog.String("error", err.Error()))
+	}
+	return rules
+}
+
+// reviewQueueEntry is a checkpoint listed by `entire review --mine`.
+type reviewQueueEntry struct {
+	CheckpointID id.CheckpointID        `json:"checkpoint_id"`
+	CreatedAt    time.Time              `json:"created_at"`
+	State        checkpoint.ReviewState `json:"state"`
+	// OwnedFiles lists the touched files owned by you.
+	OwnedFiles []string `json:"owned_files"`
+}
+
+// runReviewMine lists checkpoints that aren't approved and touch files whose
+// CODEOWNERS owners include you, newest first. A non-nil filter keeps only
+// checkpoints whose commits touched matching paths.
+func runReviewMine(ctx context.Context, w io.Writer, filter *pathfilter.Filter, asJSON bool) error {
+	repo, err := openRepository(ctx)
+	if err != nil {
+		return fmt.Errorf("not a git repository: %w", err)
+	}
+	store, err := strategy.CommittedStore(ctx, checkpoint.NewGitStore(repo))
+	if err != nil {
+		return err //nolint:wrapcheck // already names the setting
+	}
+
+	rules := loadCodeowners(ctx)
+	if rules == nil {
+		return errors.New("no CODEOWNERS file found in .github/, the repository root, or docs/")
+	}
+	me := make(map[string]bool)
+	if _, email := checkpoint.GetGitAuthorFromRepo(repo); email != "" {
+		me[strings.ToLower(email)] = true
+	}
+	if s, loadErr := LoadEntireSettings(ctx); loadErr == nil {
+		for _, handle := range s.GetReviewHandles() {
+			me[strings.ToLower(handle)] = true
+		}
+	}
+
+	committed, err := store.ListCommitted(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list checkpoints: %w", err)
+	}
+	touched, err := touchedCheckpoints(ctx, repo, filter)
+	if err != nil {
+		return err
+	}
+	required := requiredApprovals(ctx)
+	queue := make([]reviewQueueEntry, 0)
+	for _, info := range committed {
+		if touched != nil && !touched[info.CheckpointID] {
+			continue
+		}
+		var owned []string
+		for _, file := range info.FilesTouched {
+			for _, owner := range rules.Owners(file) {
+				if me[strings.ToLower(owner)] {
+					owned = append(owned, file)
+					break
+				}
+			}
+		}
+		if len(owned) == 0 {
+			continue
+		}
+		comments, err := store.ReadComments(ctx, info.CheckpointID)
+		if err != nil {
+			return fmt.Errorf("failed to read comments for %s: %w", info.CheckpointID, err)
+		}
+		status := checkpoint.ComputeReviewStatus(comments, required)
+		if status.State == checkpoint.ReviewApproved {
+			continue
+		}
+		queue = append(queue, reviewQueueEntry{CheckpointID: info.CheckpointID, CreatedAt: info.CreatedAt, State: status.State, OwnedFiles: owned})
+	}
+	sort.SliceStable(queue, func(i, j int) bool { return queue[i].CreatedAt.After(queue[j].CreatedAt) })
+
+	if asJSON {
+		data, err := json.MarshalIndent(queue, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode review queue: %w", err)
+		}
+		fmt.Fprintln(w, string(data))
+		return nil
+	}
+	if len(queue) == 0 {
+		fmt.Fprintln(w, "No checkpoints awaiting your review.")
+		return nil
+	}
+	for _, entry := range queue {
+		fmt.Fprintf(w, "%s  %-17s  %s  %d owned file(s): %s\n",
+			entry.CheckpointID, entry.State, entry.CreatedAt.Local().Format("2006-01-02 15:04"),
+			len(entry.OwnedFiles), strings.Join(entry.OwnedFiles, ", "))
+	}
+	return nil
+}
+
+// requiredApprovals returns review.required_approvals from settings, or 0
+// if settings can't be loaded.
+func requiredApprovals(ctx context.Context) int {
+	s, err := LoadEntireSettings(ctx)
+	if err != nil {
+		return 0
+	}
+	return s.GetRequiredApprovals()
+}
+
+// formatReviewStatus renders the review state with the reviewers behind it.
+func formatReviewStatus(status checkpoint.ReviewStatus) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Review: %s (%d of %d approvals)\n", status.State, len(status.Approvers), status.RequiredApprovals)
+	for _, r := range status.Approvers {
+		fmt.Fprintf(&sb, "  approved by %s  %s\n", reviewerLabel(r.Name, r.Email), r.Timestamp.Local().Format("2006-01-02 15:04"))
+	}
+	for _, r := range status.ChangesRequestedBy {
+		fmt.Fprintf(&sb, "  changes requested by %s  %s\n", reviewerLabel(r.Name, r.Email), r.Timestamp.Local().Format("2006-01-02 15:04"))
+	}
+	return sb.String()
+}
+
+func reviewerLabel(name, email string) string {
+	switch {
+	case name != "" && email != "":
+		return fmt.Sprintf("%s <%s>", name, email)
+	case name != "":
+		return name
+	default:
+		return email
+	}
+}
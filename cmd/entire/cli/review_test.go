@@ -0,0 +1,142 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+	"github.com/entireio/cli/cmd/entire/cli/pathfilter"
+)
+
+func TestRunReview_RequiredApprovals(t *testing.T) {
+	repo, _ := setupCleanTestRepo(t)
+	store := checkpoint.NewGitStore(repo)
+	ctx := context.Background()
+
+	if err := store.WriteCommitted(ctx, checkpoint.WriteCommittedOptions{
+		CheckpointID: id.MustCheckpointID("a1b2c3d4e5f6"),
+		SessionID:    "session-1",
+		Strategy:     "manual-commit",
+		Transcript:   []byte(`{"type":"user"}` + "\n"),
+		AuthorName:   "Test",
+		AuthorEmail:  "test@test.com",
+	}); err != nil {
+		t.Fatalf("WriteCommitted() error = %v", err)
+	}
+	if err := os.MkdirAll(".entire", 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(".entire", "settings.json"), []byte(`{"enabled": true, "review": {"required_approvals": 2}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	err := runReview(ctx, &out, "a1b2", checkpoint.ReviewApproved, "LGTM", true, false)
+	var silent *SilentError
+	if !errors.As(err, &silent) {
+		t.Fatalf("runReview(--check) error = %v, want SilentError while approvals are missing", err)
+	}
+	if !strings.Contains(out.String(), "Review: pending (1 of 2 approvals)") || !strings.Contains(out.String(), "approved by ") {
+		t.Errorf("unexpected output:\n%s", out.String())
+	}
+
+	// A second reviewer, identified by a different git email, completes the review
+	if _, err := store.AddComment(ctx, id.MustCheckpointID("a1b2c3d4e5f6"), checkpoint.Comment{
+		Verdict: checkpoint.ReviewApproved, AuthorName: "Second", AuthorEmail: "second@test.com",
+	}); err != nil {
+		t.Fatalf("AddComment() error = %v", err)
+	}
+	out.Reset()
+	if err := runReview(ctx, &out, "a1b2", "", "", true, false); err != nil {
+		t.Fatalf("runReview(--check) error = %v, want approved", err)
+	}
+	if !strings.Contains(out.String(), "Review: approved (2 of 2 approvals)") || !strings.Contains(out.String(), "Second <second@test.com>") {
+		t.Errorf("unexpected output:\n%s", out.String())
+	}
+}
+
+func TestRunReviewMine_CodeownersRouting(t *testing.T) {
+	repo, _ := setupCleanTestRepo(t)
+	store := checkpoint.NewGitStore(repo)
+	ctx := context.Background()
+
+	for cpID, files := range map[string][]string{
+		"a1a1a1a1a1a1": {"services/api/handler.go"},
+		"b2b2b2b2b2b2": {"web/app.tsx"},
+		"c3c3c3c3c3c3": {"services/api/routes.go"},
+	} {
+		if err := store.WriteCommitted(ctx, checkpoint.WriteCommittedOptions{
+			CheckpointID: id.MustCheckpointID(cpID),
+			SessionID:    "session-" + cpID,
+			Strategy:     "manual-commit",
+			Transcript:   []byte(`{"type":"user"}` + "\n"),
+			FilesTouched: files,
+			AuthorName:   "Test",
+			AuthorEmail:  "test@test.com",
+		}); err != nil {
+			t.Fatalf("WriteCommitted() error = %v", err)
+		}
+	}
+	if _, err := store.AddComment(ctx, id.MustCheckpointID("c3c3c3c3c3c3"), checkpoint.Comment{Verdict: checkpoint.ReviewApproved}); err != nil {
+		t.Fatalf("AddComment() error = %v", err)
+	}
+
+	if err := os.MkdirAll(".github", 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(".github", "CODEOWNERS"), []byte("services/api/ @alice @org/backend\nweb/ @carol\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(".entire", 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(".entire", "settings.json"), []byte(`{"enabled": true, "review": {"handles": ["@Alice"]}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := runReviewMine(ctx, &out, nil, false); err != nil {
+		t.Fatalf("runReviewMine() error = %v", err)
+	}
+	got := out.String()
+	if !strings.Contains(got, "a1a1a1a1a1a1  pending") || strings.Contains(got, "b2b2b2b2b2b2") || strings.Contains(got, "c3c3c3c3c3c3") {
+		t.Errorf("--mine should list only unapproved checkpoints touching owned files, got:\n%s", got)
+	}
+
+	out.Reset()
+	if err := runReview(ctx, &out, "a1a1", "", "", false, false); err != nil {
+		t.Fatalf("runReview() error = %v", err)
+	}
+	if !strings.Contains(out.String(), "Suggested reviewers (CODEOWNERS): @alice, @org/backend") {
+		t.Errorf("review should suggest CODEOWNERS owners, got:\n%s", out.String())
+	}
+}
+
+func TestRunReviewMine_PathFilter(t *testing.T) {
+	setupLogTestRepo(t)
+	writeLogTestCheckpoints(t)
+	if err := os.MkdirAll(".github", 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(".github", "CODEOWNERS"), []byte("services/ test@example.com\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	filter, err := pathfilter.New([]string{"services/web"})
+	if err != nil {
+		t.Fatalf("pathfilter.New() error = %v", err)
+	}
+	var out bytes.Buffer
+	if err := runReviewMine(context.Background(), &out, filter, false); err != nil {
+		t.Fatalf("runReviewMine() error = %v", err)
+	}
+	if got := out.String(); !strings.Contains(got, "b2b2b2b2b2b2  pending") || strings.Contains(got, "a1a1a1a1a1a1") {
+		t.Errorf("expected only the web checkpoint, got:\n%s", got)
+	}
+}
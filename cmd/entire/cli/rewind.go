@@ -4,10 +4,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"log/slog"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 	"unicode"
@@ -16,6 +18,7 @@ import (
 	"github.com/entireio/cli/cmd/entire/cli/agent/types"
 	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
 	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+	"github.com/entireio/cli/cmd/entire/cli/i18n"
 	"github.com/entireio/cli/cmd/entire/cli/jsonutil"
 	"github.com/entireio/cli/cmd/entire/cli/logging"
 	"github.com/entireio/cli/cmd/entire/cli/paths"
@@ -25,6 +28,7 @@ import (
 	"github.com/charmbracelet/huh"
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/spf13/cobra"
 )
 
@@ -45,6 +49,9 @@ func newRewindCmd() *cobra.Command {
 	var toFlag string
 	var logsOnlyFlag bool
 	var resetFlag bool
+	var allowPushedFlag bool
+	var abortFlag bool
+	var tagFlag string
 
 	cmd := &cobra.Command{
 		Use:   "rewind",
@@ -53,7 +60,18 @@ func newRewindCmd() *cobra.Command {
 
 This command will show you an interactive list of recent checkpoints.  You'll be
 able to select one for Entire to rewind your branch state, including your code and
-your agent's context.`,
+your agent's context.
+
+Resetting the branch refuses to discard commits that already exist on a remote
+tracking branch, since that would make your branch diverge from a shared one.
+Pass --allow-pushed to reset anyway.
+
+Before changing any files, rewind backs up HEAD and the working tree, including
+uncommitted and untracked changes, and afterwards checks the restored files
+against the checkpoint. Run 'entire rewind --abort' to return to the backup if
+the check fails or the rewind wasn't what you wanted.
+
+Use --tag to offer only checkpoints carrying a label set with 'entire tag'.`,
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			// Check if Entire is disabled
 			if checkDisabledGuard(cmd.Context(), cmd.OutOrStdout()) {
@@ -61,13 +79,16 @@ your agent's context.`,
 			}
 
 			ctx := cmd.Context()
+			if abortFlag {
+				return runRewindAbort(ctx, cmd.OutOrStdout())
+			}
 			if listFlag {
-				return runRewindList(ctx)
+				return runRewindList(ctx, tagFlag)
 			}
 			if toFlag != "" {
-				return runRewindToWithOptions(ctx, toFlag, logsOnlyFlag, resetFlag)
+				return runRewindToWithOptions(ctx, toFlag, logsOnlyFlag, resetFlag, allowPushedFlag)
 			}
-			return runRewindInteractive(ctx)
+			return runRewindInteractive(ctx, allowPushedFlag, tagFlag)
 		},
 	}
 
@@ -75,11 +96,15 @@ your agent's context.`,
 	cmd.Flags().StringVar(&toFlag, "to", "", "Rewind to specific commit ID (non-interactive)")
 	cmd.Flags().BoolVar(&logsOnlyFlag, "logs-only", false, "Only restore logs, don't modify working directory (for logs-only points)")
 	cmd.Flags().BoolVar(&resetFlag, "reset", false, "Reset branch to commit (destructive, for logs-only points)")
+	cmd.Flags().BoolVar(&allowPushedFlag, "allow-pushed", false, "Allow resetting past commits that exist on a remote tracking branch")
+	cmd.Flags().BoolVar(&abortFlag, "abort", false, "Return to the backup taken before the last rewind")
+	cmd.Flags().StringVar(&tagFlag, "tag", "", "Only show checkpoints carrying this label")
+	cmd.MarkFlagsMutuallyExclusive("abort", "list", "to")
 
 	return cmd
 }
 
-func runRewindInteractive(ctx context.Context) error { //nolint:maintidx // already present in codebase
+func runRewindInteractive(ctx context.Context, allowPushed bool, tag string) error { //nolint:maintidx // already present in codebase
 	// Get the configured strategy
 	start := GetStrategy(ctx)
 
@@ -94,11 +119,15 @@ func runRewindInteractive(ctx context.Context) error { //nolint:maintidx // alre
 	}
 
 	// Get rewind points from strategy
-	points, err := start.GetRewindPoints(ctx, 20)
+	points, err := getRewindPoints(ctx, start, tag)
 	if err != nil {
 		return fmt.Errorf("failed to find rewind points: %w", err)
 	}
 
+	if len(points) == 0 && tag != "" {
+		fmt.Printf("No rewind points labeled %q found.\n", tag)
+		return nil
+	}
 	if len(points) == 0 {
 		fmt.Println("No rewind points found.")
 		fmt.Println("Rewind points are created automatically when agent sessions end.")
@@ -200,7 +229,7 @@ func runRewindInteractive(ctx context.Context) error { //nolint:maintidx // alre
 
 	// Handle logs-only points with a sub-choice menu
 	if selectedPoint.IsLogsOnly {
-		return handleLogsOnlyRewindInteractive(ctx, start, *selectedPoint, shortID)
+		return handleLogsOnlyRewindInteractive(ctx, start, *selectedPoint, shortID, allowPushed)
 	}
 
 	// Preview rewind to show warnings about files that will be deleted
@@ -215,11 +244,11 @@ func runRewindInteractive(ctx context.Context) error { //nolint:maintidx // alre
 
 	// Confirm rewind
 	var confirm bool
-	description := fmt.Sprintf("This will reset to: %s\nChanges after this point may be lost!", selectedPoint.Message)
+	description := i18n.T(i18n.ConfirmRewindDesc, selectedPoint.Message)
 	confirmForm := NewAccessibleForm(
 		huh.NewGroup(
-			huh.NewConfirm().
-				Title(fmt.Sprintf("Reset to %s?", shortID)).
+			newConfirm().
+				Title(i18n.T(i18n.ConfirmRewind, shortID)).
 				Description(description).
 				Value(&confirm),
 		),
@@ -263,6 +292,12 @@ func runRewindInteractive(ctx context.Context) error { //nolint:maintidx // alre
 		slog.String("checkpoint_id", selectedPoint.ID),
 	)
 
+	// Snapshots have no transcript; restoring the files is the whole rewind
+	if selectedPoint.IsSnapshot {
+		fmt.Println(snapshotRestoredMessage(*selectedPoint))
+		return nil
+	}
+
 	// Handle transcript restoration differently for task checkpoints
 	var sessionID string
 	var transcriptFile string
@@ -331,10 +366,10 @@ func runRewindInteractive(ctx context.Context) error { //nolint:maintidx // alre
 	return nil
 }
 
-func runRewindList(ctx context.Context) error {
+func runRewindList(ctx context.Context, tag string) error {
 	start := GetStrategy(ctx)
 
-	points, err := start.GetRewindPoints(ctx, 20)
+	points, err := getRewindPoints(ctx, start, tag)
 	if err != nil {
 		return fmt.Errorf("failed to find rewind points: %w", err)
 	}
@@ -348,6 +383,7 @@ func runRewindList(ctx context.Context) error {
 		IsTaskCheckpoint bool   `json:"is_task_checkpoint"`
 		ToolUseID        string `json:"tool_use_id,omitempty"`
 		IsLogsOnly       bool   `json:"is_logs_only"`
+		IsSnapshot       bool   `json:"is_snapshot,omitempty"`
 		CondensationID   string `json:"condensation_id,omitempty"`
 		SessionID        string `json:"session_id,omitempty"`
 		SessionPrompt    string `json:"session_prompt,omitempty"`
@@ -363,6 +399,7 @@ func runRewindList(ctx context.Context) error {
 			IsTaskCheckpoint: p.IsTaskCheckpoint,
 			ToolUseID:        p.ToolUseID,
 			IsLogsOnly:       p.IsLogsOnly,
+			IsSnapshot:       p.IsSnapshot,
 			CondensationID:   p.CheckpointID.String(),
 			SessionID:        p.SessionID,
 			SessionPrompt:    p.SessionPrompt,
@@ -378,11 +415,46 @@ func runRewindList(ctx context.Context) error {
 	return nil
 }
 
-func runRewindToWithOptions(ctx context.Context, commitID string, logsOnly bool, reset bool) error {
-	return runRewindToInternal(ctx, commitID, logsOnly, reset)
+// taggedRewindPointsLimit is how far back rewind looks for checkpoints
+// carrying a label, since labels often mark older states.
+const taggedRewindPointsLimit = 500
+
+// getRewindPoints returns the strategy's recent rewind points; with a tag,
+// only those whose committed checkpoint carries it.
+func getRewindPoints(ctx context.Context, start *strategy.ManualCommitStrategy, tag string) ([]strategy.RewindPoint, error) {
+	if tag == "" {
+		return start.GetRewindPoints(ctx, 20) //nolint:wrapcheck // Callers wrap
+	}
+	points, err := start.GetRewindPoints(ctx, taggedRewindPointsLimit)
+	if err != nil {
+		return nil, err //nolint:wrapcheck // Callers wrap
+	}
+	repo, err := openRepository(ctx)
+	if err != nil {
+		return nil, err
+	}
+	store, err := strategy.CommittedStore(ctx, checkpoint.NewGitStore(repo))
+	if err != nil {
+		return nil, err //nolint:wrapcheck // already names the setting
+	}
+	tagged, err := store.ListByTag(ctx, tag)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list checkpoints labeled %q: %w", tag, err)
+	}
+	ids := make(map[id.CheckpointID]bool, len(tagged))
+	for _, info := range tagged {
+		ids[info.CheckpointID] = true
+	}
+	return slices.DeleteFunc(points, func(p strategy.RewindPoint) bool {
+		return p.CheckpointID.IsEmpty() || !ids[p.CheckpointID]
+	}), nil
 }
 
-func runRewindToInternal(ctx context.Context, commitID string, logsOnly bool, reset bool) error {
+func runRewindToWithOptions(ctx context.Context, commitID string, logsOnly bool, reset bool, allowPushed bool) error {
+	return runRewindToInternal(ctx, commitID, logsOnly, reset, allowPushed)
+}
+
+func runRewindToInternal(ctx context.Context, commitID string, logsOnly bool, reset bool, allowPushed bool) error {
 	start := GetStrategy(ctx)
 
 	// Check for uncommitted changes (skip for reset which handles this itself)
@@ -418,7 +490,7 @@ func runRewindToInternal(ctx context.Context, commitID string, logsOnly bool, re
 
 	// Handle reset mode (for logs-only points)
 	if reset {
-		return handleLogsOnlyResetNonInteractive(ctx, start, *selectedPoint)
+		return handleLogsOnlyResetNonInteractive(ctx, start, *selectedPoint, allowPushed)
 	}
 
 	// Handle logs-only restoration:
@@ -467,6 +539,12 @@ func runRewindToInternal(ctx context.Context, commitID string, logsOnly bool, re
 		slog.String("checkpoint_id", selectedPoint.ID),
 	)
 
+	// Snapshots have no transcript; restoring the files is the whole rewind
+	if selectedPoint.IsSnapshot {
+		fmt.Println(snapshotRestoredMessage(*selectedPoint))
+		return nil
+	}
+
 	// Handle transcript restoration
 	var sessionID string
 	var transcriptFile string
@@ -530,6 +608,20 @@ func runRewindToInternal(ctx context.Context, commitID string, logsOnly bool, re
 	return nil
 }
 
+// runRewindAbort returns HEAD and the working tree to the backup taken before
+// the last rewind.
+func runRewindAbort(ctx context.Context, w io.Writer) error {
+	backup, err := GetStrategy(ctx).AbortRewind(ctx)
+	if errors.Is(err, checkpoint.ErrNoRewindBackup) {
+		return errors.New("no rewind to abort")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to abort rewind: %w", err)
+	}
+	fmt.Fprintf(w, "Restored the working tree from before the rewind (backup %s).\n", backup.String()[:7])
+	return nil
+}
+
 // handleLogsOnlyRewindNonInteractive handles logs-only rewind in non-interactive mode.
 // Defaults to restoring logs only (no checkout) for safety.
 func handleLogsOnlyRewindNonInteractive(ctx context.Context, start *strategy.ManualCommitStrategy, point strategy.RewindPoint) error {
@@ -570,7 +662,11 @@ func handleLogsOnlyRewindNonInteractive(ctx context.Context, start *strategy.Man
 
 // handleLogsOnlyResetNonInteractive handles reset in non-interactive mode.
 // This performs a git reset --hard to the target commit.
-func handleLogsOnlyResetNonInteractive(ctx context.Context, start *strategy.ManualCommitStrategy, point strategy.RewindPoint) error {
+func handleLogsOnlyResetNonInteractive(ctx context.Context, start *strategy.ManualCommitStrategy, point strategy.RewindPoint, allowPushed bool) error {
+	if err := guardPushedCommits(ctx, point.ID, allowPushed); err != nil {
+		return err
+	}
+
 	// Resolve agent once for use throughout
 	agent, err := getAgent(point.Agent)
 	if err != nil {
@@ -602,6 +698,11 @@ func handleLogsOnlyResetNonInteractive(ctx context.Context, start *strategy.Manu
 		return fmt.Errorf("failed to restore logs: %w", err)
 	}
 
+	// Back up the working tree, so 'entire rewind --abort' can undo the reset
+	if _, err := start.BackupBeforeRewind(ctx); err != nil {
+		return fmt.Errorf("refusing to reset without a backup: %w", err)
+	}
+
 	// Perform git reset --hard
 	if err := performGitResetHard(ctx, point.ID); err != nil {
 		logging.Error(logCtx, "logs-only reset failed during git reset",
@@ -631,7 +732,7 @@ func handleLogsOnlyResetNonInteractive(ctx context.Context, start *strategy.Manu
 		if len(currentShort) > 7 {
 			currentShort = currentShort[:7]
 		}
-		fmt.Printf("\nTo undo this reset: git reset --hard %s\n", currentShort)
+		fmt.Printf("\nTo undo this reset: entire rewind --abort (or git reset --hard %s)\n", currentShort)
 	}
 
 	return nil
@@ -776,7 +877,7 @@ func restoreTaskCheckpointTranscript(ctx context.Context, strat *strategy.Manual
 }
 
 // handleLogsOnlyRewindInteractive handles rewind for logs-only points with a sub-choice menu.
-func handleLogsOnlyRewindInteractive(ctx context.Context, start *strategy.ManualCommitStrategy, point strategy.RewindPoint, shortID string) error {
+func handleLogsOnlyRewindInteractive(ctx context.Context, start *strategy.ManualCommitStrategy, point strategy.RewindPoint, shortID string, allowPushed bool) error {
 	var action string
 
 	form := NewAccessibleForm(
@@ -804,7 +905,7 @@ func handleLogsOnlyRewindInteractive(ctx context.Context, start *strategy.Manual
 	case "checkout":
 		return handleLogsOnlyCheckout(ctx, start, point, shortID)
 	case "reset":
-		return handleLogsOnlyReset(ctx, start, point, shortID)
+		return handleLogsOnlyReset(ctx, start, point, shortID, allowPushed)
 	case "cancel":
 		fmt.Println("Rewind cancelled.")
 		return nil
@@ -880,9 +981,9 @@ func handleLogsOnlyCheckout(ctx context.Context, start *strategy.ManualCommitStr
 	var confirm bool
 	confirmForm := NewAccessibleForm(
 		huh.NewGroup(
-			huh.NewConfirm().
-				Title("Create detached HEAD?").
-				Description("This will checkout the commit directly. You'll be in 'detached HEAD' state.\nAny uncommitted changes will be lost!").
+			newConfirm().
+				Title(i18n.T(i18n.ConfirmDetachedHead)).
+				Description(i18n.T(i18n.ConfirmDetachedHeadDesc)).
 				Value(&confirm),
 		),
 	)
@@ -916,7 +1017,11 @@ func handleLogsOnlyCheckout(ctx context.Context, start *strategy.ManualCommitStr
 }
 
 // handleLogsOnlyReset restores logs and resets the branch to the commit (destructive).
-func handleLogsOnlyReset(ctx context.Context, start *strategy.ManualCommitStrategy, point strategy.RewindPoint, shortID string) error {
+func handleLogsOnlyReset(ctx context.Context, start *strategy.ManualCommitStrategy, point strategy.RewindPoint, shortID string, allowPushed bool) error {
+	if err := guardPushedCommits(ctx, point.ID, allowPushed); err != nil {
+		return err
+	}
+
 	// Resolve agent once for use throughout
 	agent, agentErr := getAgent(point.Agent)
 	if agentErr != nil {
@@ -963,19 +1068,17 @@ func handleLogsOnlyReset(ctx context.Context, start *strategy.ManualCommitStrate
 	// Build confirmation message based on warnings
 	var confirmTitle, confirmDesc string
 	if len(warnings) > 0 {
-		confirmTitle = "⚠️  Reset branch with warnings?"
-		confirmDesc = "WARNING - the following issues were detected:\n" +
-			strings.Join(warnings, "\n") +
-			"\n\nThis will move your branch to " + shortID + " and DISCARD commits after it!"
+		confirmTitle = i18n.T(i18n.ConfirmResetBranchWarnings)
+		confirmDesc = i18n.T(i18n.ConfirmResetBranchWarningsDesc, strings.Join(warnings, "\n"), shortID)
 	} else {
-		confirmTitle = "Reset branch to " + shortID + "?"
-		confirmDesc = "This will move your branch pointer to this commit.\nCommits after this point will be orphaned (but recoverable via reflog)."
+		confirmTitle = i18n.T(i18n.ConfirmResetBranch, shortID)
+		confirmDesc = i18n.T(i18n.ConfirmResetBranchDesc)
 	}
 
 	var confirm bool
 	confirmForm := NewAccessibleForm(
 		huh.NewGroup(
-			huh.NewConfirm().
+			newConfirm().
 				Title(confirmTitle).
 				Description(confirmDesc).
 				Value(&confirm),
@@ -992,6 +1095,11 @@ func handleLogsOnlyReset(ctx context.Context, start *strategy.ManualCommitStrate
 		return nil
 	}
 
+	// Back up the working tree, so 'entire rewind --abort' can undo the reset
+	if _, err := start.BackupBeforeRewind(ctx); err != nil {
+		return fmt.Errorf("refusing to reset without a backup: %w", err)
+	}
+
 	// Perform git reset --hard
 	if err := performGitResetHard(ctx, point.ID); err != nil {
 		logging.Error(logCtx, "logs-only reset failed during git reset",
@@ -1014,7 +1122,7 @@ func handleLogsOnlyReset(ctx context.Context, start *strategy.ManualCommitStrate
 		if len(currentShort) > 7 {
 			currentShort = currentShort[:7]
 		}
-		fmt.Printf("\nTo undo this reset: git reset --hard %s\n", currentShort)
+		fmt.Printf("\nTo undo this reset: entire rewind --abort (or git reset --hard %s)\n", currentShort)
 	}
 
 	return nil
@@ -1086,9 +1194,101 @@ func checkResetSafety(ctx context.Context, targetCommitHash string, uncommittedC
 		warnings = append(warnings, fmt.Sprintf("• %d commit(s) after this point will be orphaned", commitsAhead))
 	}
 
+	// Only reached with --allow-pushed when pushed commits are involved
+	if pushed, remotes, err := pushedCommitsBetween(repo, targetHash, head.Hash()); err == nil && pushed > 0 {
+		warnings = append(warnings, fmt.Sprintf("• %d pushed commit(s) on %s will be discarded; your branch will diverge", pushed, strings.Join(remotes, ", ")))
+	}
+
 	return warnings, nil
 }
 
+// guardPushedCommits refuses a reset to targetCommitHash that would discard
+// commits already present on a remote tracking branch, unless allowPushed.
+func guardPushedCommits(ctx context.Context, targetCommitHash string, allowPushed bool) error {
+	if allowPushed {
+		return nil
+	}
+	repo, err := openRepository(ctx)
+	if err != nil {
+		return err
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return fmt.Errorf("failed to get HEAD: %w", err)
+	}
+
+	pushed, remotes, err := pushedCommitsBetween(repo, plumbing.NewHash(targetCommitHash), head.Hash())
+	if err != nil {
+		return fmt.Errorf("failed to check for pushed commits: %w", err)
+	}
+	if pushed == 0 {
+		return nil
+	}
+	return fmt.Errorf("refusing to reset: %d commit(s) that would be discarded are already on %s\n"+
+		"Resetting would make your branch diverge from the remote. Re-run with --allow-pushed to reset anyway",
+		pushed, strings.Join(remotes, ", "))
+}
+
+// pushedCommitsBetween returns how many first-parent commits after ancestor up
+// to and including descendant are reachable from a remote tracking branch, and
+// the names of those branches. Commits that can't be walked are treated as unpushed.
+func pushedCommitsBetween(repo *git.Repository, ancestor, descendant plumbing.Hash) (int, []string, error) {
+	// Collect the commits a reset would discard, newest first
+	var discarded []*object.Commit
+	current := descendant
+	for len(discarded) < strategy.MaxCommitTraversalDepth && current != ancestor {
+		commit, err := repo.CommitObject(current)
+		if err != nil {
+			return 0, nil, fmt.Errorf("failed to get commit: %w", err)
+		}
+		discarded = append(discarded, commit)
+		if commit.NumParents() == 0 {
+			break
+		}
+		current = commit.ParentHashes[0]
+	}
+	if len(discarded) == 0 {
+		return 0, nil, nil
+	}
+
+	refs, err := repo.References()
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to list references: %w", err)
+	}
+	var remoteTips []*plumbing.Reference
+	err = refs.ForEach(func(ref *plumbing.Reference) error {
+		if ref.Name().IsRemote() && ref.Type() == plumbing.HashReference {
+			remoteTips = append(remoteTips, ref)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to list references: %w", err)
+	}
+
+	// If a commit is pushed, so are all its ancestors, so the first pushed
+	// commit (newest first) decides the count for each remote.
+	maxPushed := 0
+	var remotes []string
+	for _, ref := range remoteTips {
+		tip, err := repo.CommitObject(ref.Hash())
+		if err != nil {
+			continue
+		}
+		for i, commit := range discarded {
+			if commit.Hash != tip.Hash {
+				if isAncestor, ancErr := commit.IsAncestor(tip); ancErr != nil || !isAncestor {
+					continue
+				}
+			}
+			maxPushed = max(maxPushed, len(discarded)-i)
+			remotes = append(remotes, ref.Name().Short())
+			break
+		}
+	}
+	return maxPushed, remotes, nil
+}
+
 // countCommitsBetween counts commits between ancestor and descendant.
 // Returns 0 if ancestor == descendant, -1 on error.
 func countCommitsBetween(repo *git.Repository, ancestor, descendant plumbing.Hash) (int, error) {
@@ -1209,3 +1409,11 @@ func printMultiSessionResumeCommands(sessions []strategy.RestoredSession) {
 		}
 	}
 }
+
+// snapshotRestoredMessage describes a rewind to a snapshot.
+func snapshotRestoredMessage(point strategy.RewindPoint) string {
+	if point.SessionID == checkpoint.ManualSnapshotSessionID {
+		return fmt.Sprintf("Restored snapshot %q.", point.Message)
+	}
+	return "Restored uncommitted changes from before the turn."
+}
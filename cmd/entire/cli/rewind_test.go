@@ -0,0 +1,103 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+	"github.com/entireio/cli/cmd/entire/cli/strategy"
+	"github.com/entireio/cli/cmd/entire/cli/testutil"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+func TestPushedCommitsGuard(t *testing.T) {
+	dir := t.TempDir()
+	testutil.InitRepo(t, dir)
+	t.Chdir(dir)
+	paths.ClearWorktreeRootCache()
+
+	var hashes []string
+	for _, name := range []string{"a.txt", "b.txt", "c.txt"} {
+		testutil.WriteFile(t, dir, name, name)
+		testutil.GitAdd(t, dir, name)
+		testutil.GitCommit(t, dir, "Add "+name)
+		hashes = append(hashes, testutil.GetHeadHash(t, dir))
+	}
+
+	repo, err := git.PlainOpen(dir)
+	if err != nil {
+		t.Fatalf("PlainOpen() error = %v", err)
+	}
+	// origin/main has the second commit; the third is local only
+	remoteRef := plumbing.NewHashReference(plumbing.NewRemoteReferenceName("origin", "main"), plumbing.NewHash(hashes[1]))
+	if err := repo.Storer.SetReference(remoteRef); err != nil {
+		t.Fatalf("SetReference() error = %v", err)
+	}
+	ctx := context.Background()
+
+	pushed, remotes, err := pushedCommitsBetween(repo, plumbing.NewHash(hashes[0]), plumbing.NewHash(hashes[2]))
+	if err != nil {
+		t.Fatalf("pushedCommitsBetween() error = %v", err)
+	}
+	if pushed != 1 || len(remotes) != 1 || remotes[0] != "origin/main" {
+		t.Errorf("pushedCommitsBetween() = %d, %v; want 1, [origin/main]", pushed, remotes)
+	}
+
+	// Resetting to the pushed tip only discards the local commit
+	if err := guardPushedCommits(ctx, hashes[1], false); err != nil {
+		t.Errorf("guardPushedCommits(pushed tip) error = %v, want nil", err)
+	}
+
+	err = guardPushedCommits(ctx, hashes[0], false)
+	if err == nil || !strings.Contains(err.Error(), "--allow-pushed") {
+		t.Errorf("guardPushedCommits() error = %v, want refusal mentioning --allow-pushed", err)
+	}
+	if err := guardPushedCommits(ctx, hashes[0], true); err != nil {
+		t.Errorf("guardPushedCommits(allowPushed) error = %v, want nil", err)
+	}
+}
+
+func TestRunRewindAbort(t *testing.T) {
+	dir := setupExecTestRepo(t)
+	ctx := context.Background()
+
+	var out bytes.Buffer
+	if err := runRewindAbort(ctx, &out); err == nil || err.Error() != "no rewind to abort" {
+		t.Fatalf("runRewindAbort() without a backup error = %v, want \"no rewind to abort\"", err)
+	}
+
+	readme := filepath.Join(dir, "README.md")
+	if err := os.WriteFile(readme, []byte("# Local edits"), 0o644); err != nil {
+		t.Fatalf("failed to write README: %v", err)
+	}
+	if _, err := GetStrategy(ctx).BackupBeforeRewind(ctx); err != nil {
+		t.Fatalf("BackupBeforeRewind() error = %v", err)
+	}
+	head, err := getCurrentHeadHash(ctx)
+	if err != nil {
+		t.Fatalf("getCurrentHeadHash() error = %v", err)
+	}
+	if _, err := strategy.HardResetWithProtection(ctx, plumbing.NewHash(head)); err != nil {
+		t.Fatalf("HardResetWithProtection() error = %v", err)
+	}
+
+	if err := runRewindAbort(ctx, &out); err != nil {
+		t.Fatalf("runRewindAbort() error = %v", err)
+	}
+	if !strings.Contains(out.String(), "Restored the working tree from before the rewind") {
+		t.Errorf("output = %q, want a restore message", out.String())
+	}
+	got, err := os.ReadFile(readme)
+	if err != nil {
+		t.Fatalf("failed to read README: %v", err)
+	}
+	if string(got) != "# Local edits" {
+		t.Errorf("README = %q, want the edits from before the rewind", got)
+	}
+}
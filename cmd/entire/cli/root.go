@@ -5,6 +5,7 @@ import (
 	"runtime"
 
 	"github.com/entireio/cli/cmd/entire/cli/telemetry"
+	"github.com/entireio/cli/cmd/entire/cli/theme"
 	"github.com/entireio/cli/cmd/entire/cli/versioncheck"
 	"github.com/entireio/cli/cmd/entire/cli/versioninfo"
 	"github.com/spf13/cobra"
@@ -24,6 +25,11 @@ Environment Variables:
   ACCESSIBLE    Set to any value (e.g., ACCESSIBLE=1) to enable accessibility
                 mode. This uses simpler text prompts instead of interactive
                 TUI elements, which works better with screen readers.
+  NO_COLOR      Set to any value to disable colored output, unless
+                --color=always is given.
+  LANG          Language of messages (e.g., LANG=ja_JP.UTF-8); English,
+                Japanese, and Chinese are available. LC_ALL and LC_MESSAGES
+                take precedence, and the "locale" setting overrides all three.
 `
 
 func NewRootCmd() *cobra.Command {
@@ -39,6 +45,13 @@ func NewRootCmd() *cobra.Command {
 		CompletionOptions: cobra.CompletionOptions{
 			HiddenDefaultCmd: true,
 		},
+		PersistentPreRunE: func(cmd *cobra.Command, _ []string) error {
+			if err := applyTheme(cmd); err != nil {
+				return err
+			}
+			applyLocale(cmd.Context())
+			return enforceCommandPolicy(cmd)
+		},
 		PersistentPostRun: func(cmd *cobra.Command, _ []string) {
 			// Skip for hidden commands (walk parent chain — Cobra doesn't propagate Hidden)
 			for c := cmd; c != nil; c = c.Parent() {
@@ -48,14 +61,10 @@ func NewRootCmd() *cobra.Command {
 			}
 
 			// Load settings once for telemetry and version check
-			var telemetryEnabled *bool
 			settings, err := LoadEntireSettings(cmd.Context())
-			if err == nil {
-				telemetryEnabled = settings.Telemetry
-			}
 
 			// Check if telemetry is enabled
-			if telemetryEnabled != nil && *telemetryEnabled {
+			if err == nil && telemetryConsented(settings) {
 				// Use detached tracking (non-blocking)
 				installedAgents := GetAgentsWithHooksInstalled(cmd.Context())
 				agentStr := JoinAgentNames(installedAgents)
@@ -77,14 +86,61 @@ func NewRootCmd() *cobra.Command {
 	cmd.AddCommand(newCleanCmd())
 	cmd.AddCommand(newResetCmd())
 	cmd.AddCommand(newEnableCmd())
+	cmd.AddCommand(newInitCmd())
+	cmd.AddCommand(newTemplateCmd())
 	cmd.AddCommand(newDisableCmd())
 	cmd.AddCommand(newStatusCmd())
+	cmd.AddCommand(newStatuslineCmd())
 	cmd.AddCommand(newHooksCmd())
 	cmd.AddCommand(newVersionCmd())
 	cmd.AddCommand(newExplainCmd())
 	cmd.AddCommand(newDoctorCmd())
+	cmd.AddCommand(newBugreportCmd())
+	cmd.AddCommand(newAttachCmd())
+	cmd.AddCommand(newAuditLogCmd())
+	cmd.AddCommand(newShareCmd())
+	cmd.AddCommand(newExportCmd())
+	cmd.AddCommand(newImportCmd())
+	cmd.AddCommand(newCommentCmd())
+	cmd.AddCommand(newTagCmd())
+	cmd.AddCommand(newReviewCmd())
+	cmd.AddCommand(newPublishCmd())
+	cmd.AddCommand(newServeCmd())
+	cmd.AddCommand(newLinkedCmd())
+	cmd.AddCommand(newCompareSessionsCmd())
+	cmd.AddCommand(newWorkspaceCmd())
+	cmd.AddCommand(newMatrixCmd())
+	cmd.AddCommand(newExecCmd())
+	cmd.AddCommand(newFinalizeCmd())
+	cmd.AddCommand(newReconcileCmd())
+	cmd.AddCommand(newPurgeSessionCmd())
+	cmd.AddCommand(newSyncCmd())
+	cmd.AddCommand(newAdminCmd())
+	cmd.AddCommand(newTelemetryCmd())
+	cmd.AddCommand(newAgentConfigCmd())
+	cmd.AddCommand(newInstructionsCmd())
+	cmd.AddCommand(newConfigCmd())
+	cmd.AddCommand(newLogCmd())
+	cmd.AddCommand(newDiffCmd())
+	cmd.AddCommand(newSearchCmd())
+	cmd.AddCommand(newRecallCmd())
+	cmd.AddCommand(newEventsCmd())
+	cmd.AddCommand(newIndexCmd())
+	cmd.AddCommand(newCheckpointCmd())
+	cmd.AddCommand(newSnapshotCmd())
+	cmd.AddCommand(newGCCmd())
+	cmd.AddCommand(newAdoptCmd())
+	cmd.AddCommand(newRemapCmd())
+	cmd.AddCommand(newVerifyCmd())
+	cmd.AddCommand(newMigrateCmd())
+	cmd.AddCommand(newHookResponseCmd())
+	cmd.AddCommand(newSessionMemoryCmd())
+	cmd.AddCommand(newStatsCmd())
 	cmd.AddCommand(newSendAnalyticsCmd())
 	cmd.AddCommand(newCurlBashPostInstallCmd())
+	cmd.AddCommand(newManCmd())
+
+	cmd.PersistentFlags().String(colorFlag, string(theme.ColorAuto), "When to color output: auto, always, or never")
 
 	cmd.SetVersionTemplate(versionString())
 
@@ -0,0 +1,96 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/pathfilter"
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+	"github.com/entireio/cli/cmd/entire/cli/strategy"
+	"github.com/spf13/cobra"
+)
+
+func newSearchCmd() *cobra.Command {
+	var limitFlag int
+	var pathFlags []string
+
+	cmd := &cobra.Command{
+		Use:   "search <query>",
+		Short: "Search checkpoint prompts, context, and transcripts",
+		Long: `Search looks for text in the prompts, context, and transcripts of every
+committed checkpoint, most recent first, and prints the matching checkpoints
+with the lines that matched.
+
+Matching ignores case, and runs of whitespace match a single space. Several
+arguments are searched for as one phrase. Sessions encrypted to a key you
+don't have are skipped.
+
+Use --path to only search checkpoints whose commits on the current branch
+touched matching files, with the same patterns as 'entire log --path'.
+
+Use 'entire explain --checkpoint <id>' to read a matching checkpoint.`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			if _, err := paths.WorktreeRoot(ctx); err != nil {
+				return paths.ErrNotGitRepository
+			}
+			filter, err := pathfilter.New(pathFlags)
+			if err != nil {
+				return err //nolint:wrapcheck // already descriptive
+			}
+			return runSearch(ctx, cmd.OutOrStdout(), strings.Join(args, " "), limitFlag, filter)
+		},
+	}
+
+	cmd.Flags().IntVarP(&limitFlag, "limit", "n", 20, "Maximum number of checkpoints to show (0 for all)")
+	addPathFlag(cmd, &pathFlags, "Only search checkpoints that touched paths matching this glob")
+
+	return cmd
+}
+
+func runSearch(ctx context.Context, w io.Writer, query string, limit int, filter *pathfilter.Filter) error {
+	repo, err := openRepository(ctx)
+	if err != nil {
+		return fmt.Errorf("not a git repository: %w", err)
+	}
+	store, err := strategy.CommittedStore(ctx, checkpoint.NewGitStore(repo))
+	if err != nil {
+		return err //nolint:wrapcheck // already names the setting
+	}
+
+	touched, err := touchedCheckpoints(ctx, repo, filter)
+	if err != nil {
+		return err
+	}
+	result, err := checkpoint.Search(ctx, store, checkpoint.SearchOptions{Query: query, Limit: limit, CheckpointIDs: touched})
+	if err != nil {
+		return fmt.Errorf("failed to search checkpoints: %w", err)
+	}
+
+	for _, hit := range result.Hits {
+		fmt.Fprintf(w, "%s  %s  %s\n", hit.Info.CheckpointID,
+			hit.Info.CreatedAt.Local().Format("2006-01-02 15:04"), hit.Info.SessionID)
+		for _, m := range hit.Matches {
+			source := fmt.Sprintf("%s %d", m.Source, m.Line)
+			if hit.Info.SessionCount > 1 {
+				source = fmt.Sprintf("session %d %s", m.SessionIndex, source)
+			}
+			fmt.Fprintf(w, "  %s: %s\n", source, sanitizeForTerminal(m.Snippet))
+		}
+		if more := hit.MatchCount - len(hit.Matches); more > 0 {
+			fmt.Fprintf(w, "  (%d more matches)\n", more)
+		}
+	}
+
+	if len(result.Hits) == 0 {
+		fmt.Fprintf(w, "No checkpoints match %q.\n", query)
+	}
+	if result.Skipped > 0 {
+		fmt.Fprintf(w, "Skipped %d session(s) that couldn't be read (encrypted to another key?)\n", result.Skipped)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+	"github.com/entireio/cli/cmd/entire/cli/pathfilter"
+)
+
+func TestRunSearch(t *testing.T) {
+	repo, _ := setupCleanTestRepo(t)
+	store := checkpoint.NewGitStore(repo)
+	ctx := context.Background()
+
+	if err := store.WriteCommitted(ctx, checkpoint.WriteCommittedOptions{
+		CheckpointID: id.MustCheckpointID("a1b2c3d4e5f6"),
+		SessionID:    "session-1",
+		Strategy:     "manual-commit",
+		Transcript:   []byte(`{"type":"user","message":{"content":"rename the flag"}}` + "\n"),
+		Prompts:      []string{"rename the flag"},
+		AuthorName:   "Test",
+		AuthorEmail:  "test@test.com",
+	}); err != nil {
+		t.Fatalf("WriteCommitted() error = %v", err)
+	}
+
+	var out bytes.Buffer
+	if err := runSearch(ctx, &out, "rename", 20, nil); err != nil {
+		t.Fatalf("runSearch() error = %v", err)
+	}
+	got := out.String()
+	if !strings.HasPrefix(got, "a1b2c3d4e5f6  ") || !strings.Contains(got, "  prompt 1: rename the flag\n") ||
+		!strings.Contains(got, "  transcript 1: ") {
+		t.Errorf("runSearch() output = %q", got)
+	}
+
+	out.Reset()
+	if err := runSearch(ctx, &out, "no such text", 20, nil); err != nil {
+		t.Fatalf("runSearch() error = %v", err)
+	}
+	if !strings.Contains(out.String(), `No checkpoints match "no such text"`) {
+		t.Errorf("runSearch() without matches = %q", out.String())
+	}
+}
+
+func TestRunSearch_PathFilter(t *testing.T) {
+	setupLogTestRepo(t)
+	writeLogTestCheckpoints(t)
+
+	filter, err := pathfilter.New([]string{"services/web"})
+	if err != nil {
+		t.Fatalf("pathfilter.New() error = %v", err)
+	}
+	var out bytes.Buffer
+	if err := runSearch(context.Background(), &out, "add the service", 20, filter); err != nil {
+		t.Fatalf("runSearch() error = %v", err)
+	}
+	if got := out.String(); !strings.HasPrefix(got, "b2b2b2b2b2b2  ") || strings.Contains(got, "a1a1a1a1a1a1") {
+		t.Errorf("expected only the web checkpoint, got:\n%s", got)
+	}
+}
@@ -0,0 +1,211 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/serve"
+	"github.com/entireio/cli/cmd/entire/cli/strategy"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/spf13/cobra"
+)
+
+// serveOptions controls `entire serve`.
+type serveOptions struct {
+	Bind                 string
+	Repo                 string
+	ReadOnly             bool
+	TLSCert              string
+	TLSKey               string
+	BasicAuthUser        string
+	BasicAuthPassword    string
+	OIDCIssuer           string
+	OIDCAudience         string
+	AllowUnauthenticated bool
+}
+
+// servePasswordEnv holds the basic auth password, so it stays out of shell
+// history and process listings.
+const servePasswordEnv = "ENTIRE_SERVE_PASSWORD"
+
+func newServeCmd() *cobra.Command {
+	var opts serveOptions
+
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Serve a web dashboard of checkpoints",
+		Long: `Serve starts a web dashboard listing the repository's checkpoints, with
+their prompts, files, attachments, comments, and review state. The same
+data is available as JSON under /api/checkpoints, and new checkpoints as an
+Atom feed at /feed.atom for feed readers.
+
+By default the dashboard listens on 127.0.0.1:8080 and allows adding
+comments and review verdicts. To share one instance with a team, point it
+at a mirror clone and lock it down:
+
+  git clone --mirror git@github.com:org/repo.git /srv/repo.git
+  ENTIRE_SERVE_PASSWORD=... entire serve --repo /srv/repo.git \
+    --readonly --bind 0.0.0.0:8443 \
+    --tls-cert cert.pem --tls-key key.pem --basic-auth-user team
+
+--readonly rejects comments and verdicts. Authentication is basic auth
+(--basic-auth-user, password from ` + servePasswordEnv + `) or OIDC ID tokens
+sent as bearer tokens (--oidc-issuer and --oidc-audience), e.g. by an
+authenticating proxy. Comments posted through the dashboard are attributed
+to the authenticated user.
+
+Serving on a non-loopback address without authentication is refused unless
+--allow-unauthenticated is given.
+
+With "checkpoint_link": true in settings, each commit that creates a
+checkpoint prints its ID, and while serve runs for the repository, a link
+to its page here.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			opts.BasicAuthPassword = os.Getenv(servePasswordEnv)
+			return runServe(cmd.Context(), cmd.ErrOrStderr(), opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.Bind, "bind", "127.0.0.1:8080", "Address to listen on")
+	cmd.Flags().StringVar(&opts.Repo, "repo", "", "Repository to serve, e.g. a mirror clone (default: the current repository)")
+	cmd.Flags().BoolVar(&opts.ReadOnly, "readonly", false, "Reject comments and review verdicts")
+	cmd.Flags().StringVar(&opts.TLSCert, "tls-cert", "", "TLS certificate file")
+	cmd.Flags().StringVar(&opts.TLSKey, "tls-key", "", "TLS private key file")
+	cmd.Flags().StringVar(&opts.BasicAuthUser, "basic-auth-user", "", "Require basic auth with this user (password from "+servePasswordEnv+")")
+	cmd.Flags().StringVar(&opts.OIDCIssuer, "oidc-issuer", "", "Require OIDC ID tokens from this issuer")
+	cmd.Flags().StringVar(&opts.OIDCAudience, "oidc-audience", "", "Audience OIDC tokens must be issued for")
+	cmd.Flags().BoolVar(&opts.AllowUnauthenticated, "allow-unauthenticated", false, "Allow serving on a non-loopback address without authentication")
+	cmd.MarkFlagsRequiredTogether("tls-cert", "tls-key")
+	cmd.MarkFlagsRequiredTogether("oidc-issuer", "oidc-audience")
+	cmd.MarkFlagsMutuallyExclusive("basic-auth-user", "oidc-issuer")
+
+	return cmd
+}
+
+// serveAuthenticator returns the authenticator selected by opts, or nil.
+func serveAuthenticator(opts serveOptions) (serve.Authenticator, error) {
+	switch {
+	case opts.BasicAuthUser != "":
+		if opts.BasicAuthPassword == "" {
+			return nil, fmt.Errorf("--basic-auth-user needs a password in %s", servePasswordEnv)
+		}
+		return serve.NewBasicAuth(opts.BasicAuthUser, opts.BasicAuthPassword), nil
+	case opts.OIDCIssuer != "":
+		return serve.NewOIDCAuth(opts.OIDCIssuer, opts.OIDCAudience), nil
+	default:
+		return nil, nil //nolint:nilnil // no authentication configured
+	}
+}
+
+// isLoopbackBind reports whether addr only accepts local connections.
+func isLoopbackBind(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+func runServe(ctx context.Context, w io.Writer, opts serveOptions) error {
+	auth, err := serveAuthenticator(opts)
+	if err != nil {
+		return err
+	}
+	loopback := isLoopbackBind(opts.Bind)
+	if auth == nil && !loopback && !opts.AllowUnauthenticated {
+		return fmt.Errorf("refusing to serve %s without authentication; use --basic-auth-user or --oidc-issuer, or --allow-unauthenticated", opts.Bind)
+	}
+	tls := opts.TLSCert != ""
+	if auth != nil && !tls && !loopback {
+		fmt.Fprintln(w, "Warning: credentials will be sent unencrypted; use --tls-cert and --tls-key or a TLS-terminating proxy.")
+	}
+
+	var store checkpoint.CommittedStore
+	if opts.Repo != "" {
+		repo, err := git.PlainOpenWithOptions(opts.Repo, &git.PlainOpenOptions{EnableDotGitCommonDir: true})
+		if err != nil {
+			return fmt.Errorf("not a git repository: %w", err)
+		}
+		if err := requireBranchStoreIn(repo); err != nil {
+			return fmt.Errorf("cannot serve %s: %w", opts.Repo, err)
+		}
+		store = checkpoint.NewGitStore(repo)
+	} else {
+		repo, err := openRepository(ctx)
+		if err != nil {
+			return fmt.Errorf("not a git repository: %w", err)
+		}
+		store, err = strategy.CommittedStore(ctx, checkpoint.NewGitStore(repo))
+		if err != nil {
+			return err //nolint:wrapcheck // already names the setting
+		}
+	}
+
+	srv := &serve.Server{
+		Store:             store,
+		ReadOnly:          opts.ReadOnly,
+		Auth:              auth,
+		RequiredApprovals: requiredApprovals(ctx),
+	}
+	httpServer := &http.Server{
+		Addr:              opts.Bind,
+		Handler:           srv.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+		BaseContext:       func(net.Listener) context.Context { return ctx },
+	}
+	listener, err := net.Listen("tcp", opts.Bind)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", opts.Bind, err)
+	}
+
+	scheme := "http"
+	if tls {
+		scheme = "https"
+	}
+	mode := "read-write"
+	if opts.ReadOnly {
+		mode = "read-only"
+	}
+	fmt.Fprintf(w, "Serving checkpoints (%s) at %s://%s\n", mode, scheme, listener.Addr())
+
+	// Let hooks link new checkpoints to this dashboard
+	if opts.Repo == "" {
+		if remove, err := recordServeState(ctx, dashboardURL(scheme, listener.Addr())); err == nil {
+			defer remove()
+		}
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		if tls {
+			errCh <- httpServer.ServeTLS(listener, opts.TLSCert, opts.TLSKey)
+		} else {
+			errCh <- httpServer.Serve(listener)
+		}
+	}()
+
+	select {
+	case err := <-errCh:
+		return fmt.Errorf("dashboard server failed: %w", err)
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+		defer cancel()
+		if err := httpServer.Shutdown(shutdownCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return fmt.Errorf("failed to stop dashboard server: %w", err)
+		}
+		return nil
+	}
+}
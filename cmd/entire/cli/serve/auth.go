@@ -0,0 +1,268 @@
+package serve
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
+)
+
+// ErrUnauthorized is returned by an Authenticator when a request carries
+// no valid credentials.
+var ErrUnauthorized = errors.New("unauthorized")
+
+// Identity is the authenticated user behind a request. Comments posted
+// through the server are attributed to it.
+type Identity struct {
+	Name  string
+	Email string
+}
+
+// Authenticator checks a request's credentials.
+type Authenticator interface {
+	// Authenticate returns the caller's identity, or an error wrapping
+	// ErrUnauthorized.
+	Authenticate(r *http.Request) (Identity, error)
+	// Challenge is the WWW-Authenticate header sent with 401 responses.
+	Challenge() string
+}
+
+// BasicAuth accepts a single user and password over HTTP basic auth.
+type BasicAuth struct {
+	User         string
+	passwordHash [sha256.Size]byte
+}
+
+// NewBasicAuth returns a BasicAuth for user and password.
+func NewBasicAuth(user, password string) *BasicAuth {
+	return &BasicAuth{User: user, passwordHash: sha256.Sum256([]byte(password))}
+}
+
+// Authenticate implements Authenticator.
+func (a *BasicAuth) Authenticate(r *http.Request) (Identity, error) {
+	user, password, ok := r.BasicAuth()
+	if !ok {
+		return Identity{}, ErrUnauthorized
+	}
+	// Compare fixed-size hashes so timing reveals neither value nor length
+	userHash, wantUserHash := sha256.Sum256([]byte(user)), sha256.Sum256([]byte(a.User))
+	passwordHash := sha256.Sum256([]byte(password))
+	userOK := subtle.ConstantTimeCompare(userHash[:], wantUserHash[:])
+	passwordOK := subtle.ConstantTimeCompare(passwordHash[:], a.passwordHash[:])
+	if userOK&passwordOK != 1 {
+		return Identity{}, ErrUnauthorized
+	}
+	return Identity{Name: user}, nil
+}
+
+// Challenge implements Authenticator.
+func (a *BasicAuth) Challenge() string {
+	return `Basic realm="entire", charset="UTF-8"`
+}
+
+// oidcLeeway tolerates clock skew when checking token lifetimes.
+const oidcLeeway = time.Minute
+
+// oidcKeyRefreshInterval limits how often an unknown key ID triggers a
+// JWKS refetch, so forged tokens can't make the server hammer the issuer.
+const oidcKeyRefreshInterval = time.Minute
+
+// oidcMinRSAKeyBits is the smallest RSA signing key accepted from a JWKS.
+const oidcMinRSAKeyBits = 2048
+
+// oidcAlgorithms are the token signature algorithms accepted.
+var oidcAlgorithms = []jose.SignatureAlgorithm{jose.RS256, jose.ES256}
+
+// OIDCAuth accepts OIDC ID tokens sent as "Authorization: Bearer <token>",
+// e.g. by an authenticating proxy or a script using a workload identity.
+// Tokens must be signed with RS256 or ES256 by a key in the issuer's JWKS,
+// be issued by Issuer for Audience, and be unexpired. Parsing and signature
+// checks are done by go-jose.
+type OIDCAuth struct {
+	Issuer   string
+	Audience string
+	Client   *http.Client
+
+	mu          sync.Mutex
+	jwksURI     string
+	keys        map[string]jose.JSONWebKey
+	lastRefresh time.Time
+	now         func() time.Time
+}
+
+// NewOIDCAuth returns an OIDCAuth for issuer and audience. Discovery and
+// key fetching happen on first use.
+func NewOIDCAuth(issuer, audience string) *OIDCAuth {
+	return &OIDCAuth{
+		Issuer:   strings.TrimSuffix(issuer, "/"),
+		Audience: audience,
+		Client:   &http.Client{Timeout: 10 * time.Second},
+		now:      time.Now,
+	}
+}
+
+// Challenge implements Authenticator.
+func (a *OIDCAuth) Challenge() string {
+	return `Bearer realm="entire"`
+}
+
+// profileClaims are the ID token claims used for the identity.
+type profileClaims struct {
+	Email string `json:"email"`
+	Name  string `json:"name"`
+}
+
+// Authenticate implements Authenticator.
+func (a *OIDCAuth) Authenticate(r *http.Request) (Identity, error) {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || token == "" {
+		return Identity{}, ErrUnauthorized
+	}
+	claims, profile, err := a.verify(r.Context(), strings.TrimSpace(token))
+	if err != nil {
+		return Identity{}, fmt.Errorf("%w: %w", ErrUnauthorized, err)
+	}
+	name := profile.Name
+	if name == "" {
+		name = claims.Subject
+	}
+	return Identity{Name: name, Email: profile.Email}, nil
+}
+
+func (a *OIDCAuth) verify(ctx context.Context, token string) (*jwt.Claims, *profileClaims, error) {
+	parsed, err := jwt.ParseSigned(token, oidcAlgorithms)
+	if err != nil {
+		return nil, nil, fmt.Errorf("malformed token: %w", err)
+	}
+	header := parsed.Headers[0]
+	key, err := a.key(ctx, header.KeyID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if key.Algorithm != "" && key.Algorithm != header.Algorithm {
+		return nil, nil, fmt.Errorf("signing key %q is not for %s", header.KeyID, header.Algorithm)
+	}
+	var claims jwt.Claims
+	var profile profileClaims
+	if err := parsed.Claims(key.Key, &claims, &profile); err != nil {
+		return nil, nil, fmt.Errorf("invalid token: %w", err)
+	}
+
+	if strings.TrimSuffix(claims.Issuer, "/") != a.Issuer {
+		return nil, nil, fmt.Errorf("token issued by %q", claims.Issuer)
+	}
+	if claims.Expiry == nil {
+		return nil, nil, errors.New("token has no expiry")
+	}
+	expected := jwt.Expected{AnyAudience: jwt.Audience{a.Audience}, Time: a.now()}
+	if err := claims.ValidateWithLeeway(expected, oidcLeeway); err != nil {
+		return nil, nil, fmt.Errorf("invalid token: %w", err)
+	}
+	return &claims, &profile, nil
+}
+
+// key returns the issuer's signing key with the given ID, fetching the
+// JWKS on first use and when an unknown ID appears.
+func (a *OIDCAuth) key(ctx context.Context, kid string) (jose.JSONWebKey, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if key, ok := a.keys[kid]; ok {
+		return key, nil
+	}
+	if a.keys != nil && a.now().Sub(a.lastRefresh) < oidcKeyRefreshInterval {
+		return jose.JSONWebKey{}, fmt.Errorf("unknown signing key %q", kid)
+	}
+	a.lastRefresh = a.now()
+	if err := a.refreshKeys(ctx); err != nil {
+		return jose.JSONWebKey{}, err
+	}
+	if key, ok := a.keys[kid]; ok {
+		return key, nil
+	}
+	return jose.JSONWebKey{}, fmt.Errorf("unknown signing key %q", kid)
+}
+
+func (a *OIDCAuth) refreshKeys(ctx context.Context) error {
+	if a.jwksURI == "" {
+		var discovery struct {
+			Issuer  string `json:"issuer"`
+			JWKSURI string `json:"jwks_uri"`
+		}
+		if err := a.getJSON(ctx, a.Issuer+"/.well-known/openid-configuration", &discovery); err != nil {
+			return fmt.Errorf("OIDC discovery failed: %w", err)
+		}
+		if strings.TrimSuffix(discovery.Issuer, "/") != a.Issuer || discovery.JWKSURI == "" {
+			return fmt.Errorf("OIDC discovery for %s returned issuer %q", a.Issuer, discovery.Issuer)
+		}
+		a.jwksURI = discovery.JWKSURI
+	}
+
+	// Keys are decoded one at a time, so one the issuer publishes for
+	// something else doesn't lock out the rest
+	var set struct {
+		Keys []json.RawMessage `json:"keys"`
+	}
+	if err := a.getJSON(ctx, a.jwksURI, &set); err != nil {
+		return fmt.Errorf("fetching OIDC signing keys failed: %w", err)
+	}
+	keys := make(map[string]jose.JSONWebKey, len(set.Keys))
+	for _, raw := range set.Keys {
+		var key jose.JSONWebKey
+		if err := key.UnmarshalJSON(raw); err != nil {
+			continue
+		}
+		if (key.Use == "" || key.Use == "sig") && acceptableSigningKey(key) {
+			keys[key.KeyID] = key
+		}
+	}
+	a.keys = keys
+	return nil
+}
+
+// acceptableSigningKey reports whether key is a valid public key for one of
+// oidcAlgorithms: RSA of at least oidcMinRSAKeyBits, or ECDSA on P-256.
+func acceptableSigningKey(key jose.JSONWebKey) bool {
+	if !key.IsPublic() || !key.Valid() {
+		return false
+	}
+	switch k := key.Key.(type) {
+	case *rsa.PublicKey:
+		return k.N.BitLen() >= oidcMinRSAKeyBits
+	case *ecdsa.PublicKey:
+		return k.Curve == elliptic.P256()
+	default:
+		return false
+	}
+}
+
+func (a *OIDCAuth) getJSON(ctx context.Context, url string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	resp, err := a.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("requesting %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned %s", url, resp.Status)
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(v); err != nil {
+		return fmt.Errorf("decoding %s: %w", url, err)
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+package serve
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testIssuer struct {
+	server *httptest.Server
+	keys   map[string]*rsa.PrivateKey
+}
+
+func newTestIssuer(t *testing.T) *testIssuer {
+	t.Helper()
+	iss := &testIssuer{keys: map[string]*rsa.PrivateKey{}}
+	// "weak" is below the minimum key size and must not be accepted
+	for kid, bits := range map[string]int{"k1": 2048, "weak": 1024} {
+		key, err := rsa.GenerateKey(rand.Reader, bits)
+		if err != nil {
+			t.Fatal(err)
+		}
+		iss.keys[kid] = key
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{"issuer": iss.server.URL, "jwks_uri": iss.server.URL + "/jwks"})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, _ *http.Request) {
+		var keys []map[string]string
+		for kid, key := range iss.keys {
+			keys = append(keys, map[string]string{
+				"kty": "RSA",
+				"kid": kid,
+				"use": "sig",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			})
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"keys": keys})
+	})
+	iss.server = httptest.NewServer(mux)
+	t.Cleanup(iss.server.Close)
+	return iss
+}
+
+// token returns a token signed with RS256 by the key kid, or by k1 for a
+// kid the issuer doesn't have.
+func (iss *testIssuer) token(t *testing.T, kid string, claims map[string]any) string {
+	t.Helper()
+	key, ok := iss.keys[kid]
+	if !ok {
+		key = iss.keys["k1"]
+	}
+	encode := func(v any) string {
+		data, err := json.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return base64.RawURLEncoding.EncodeToString(data)
+	}
+	signingInput := encode(map[string]string{"alg": "RS256", "kid": kid}) + "." + encode(claims)
+	digest := sha256.Sum256([]byte(signingInput))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func TestOIDCAuth(t *testing.T) {
+	t.Parallel()
+	iss := newTestIssuer(t)
+	auth := NewOIDCAuth(iss.server.URL, "entire-dashboard")
+	now := time.Now()
+	valid := map[string]any{
+		"iss":   iss.server.URL,
+		"aud":   []string{"other", "entire-dashboard"},
+		"exp":   now.Add(time.Hour).Unix(),
+		"sub":   "u-1",
+		"email": "alice@example.com",
+		"name":  "Alice",
+	}
+	with := func(key string, value any) map[string]any {
+		claims := make(map[string]any, len(valid))
+		for k, v := range valid {
+			claims[k] = v
+		}
+		claims[key] = value
+		return claims
+	}
+	authenticate := func(token string) (Identity, error) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		return auth.Authenticate(req)
+	}
+
+	identity, err := authenticate(iss.token(t, "k1", valid))
+	if err != nil {
+		t.Fatalf("valid token rejected: %v", err)
+	}
+	if identity.Name != "Alice" || identity.Email != "alice@example.com" {
+		t.Errorf("identity = %+v", identity)
+	}
+
+	// HS256 keyed with the public modulus, the classic algorithm confusion
+	hs256Input := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","kid":"k1"}`)) + "." +
+		strings.Split(iss.token(t, "k1", valid), ".")[1]
+	mac := hmac.New(sha256.New, iss.keys["k1"].N.Bytes())
+	mac.Write([]byte(hs256Input))
+	hs256 := hs256Input + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+
+	tampered := iss.token(t, "k1", valid)
+	tampered = tampered[:strings.LastIndex(tampered, ".")] + ".AAAA"
+	rejected := map[string]string{
+		"wrong audience": iss.token(t, "k1", with("aud", "other")),
+		"wrong issuer":   iss.token(t, "k1", with("iss", "https://evil.example")),
+		"expired":        iss.token(t, "k1", with("exp", now.Add(-time.Hour).Unix())),
+		"not yet valid":  iss.token(t, "k1", with("nbf", now.Add(time.Hour).Unix())),
+		"unknown key":    iss.token(t, "k2", valid),
+		"weak key":       iss.token(t, "weak", valid),
+		"bad signature":  tampered,
+		"HS256":          hs256,
+		"no expiry":      iss.token(t, "k1", with("exp", nil)),
+		"malformed":      "not-a-token",
+	}
+	for name, token := range rejected {
+		if _, err := authenticate(token); !errors.Is(err, ErrUnauthorized) {
+			t.Errorf("%s: error = %v, want ErrUnauthorized", name, err)
+		}
+	}
+
+	if _, err := auth.Authenticate(httptest.NewRequest(http.MethodGet, "/", nil)); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("missing token: error = %v, want ErrUnauthorized", err)
+	}
+}
@@ -0,0 +1,154 @@
+package serve
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/stringutil"
+)
+
+// feedLimit caps the entries in the Atom feed.
+const feedLimit = 50
+
+// feedTitleRunes caps entry titles taken from prompts.
+const feedTitleRunes = 80
+
+// feedFileName is the feed's name in a static export.
+const feedFileName = "feed.atom"
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Author  atomPerson  `xml:"author"`
+	Links   []atomLink  `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+}
+
+type atomLink struct {
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+	Href string `xml:"href,attr"`
+}
+
+type atomEntry struct {
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Link    atomLink    `xml:"link"`
+	Content atomContent `xml:"content"`
+}
+
+type atomContent struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+// feed builds an Atom feed of the newest checkpoints. Each entry is titled
+// with the checkpoint's first prompt and holds its diffstat and summary.
+func (s *Server) feed(ctx context.Context, links pageLinks, now time.Time) (*atomFeed, error) {
+	items, err := s.listCheckpoints(ctx, feedLimit)
+	if err != nil {
+		return nil, err
+	}
+	f := &atomFeed{
+		ID:      "urn:entire:checkpoints",
+		Title:   "Entire checkpoints",
+		Updated: now.UTC().Format(time.RFC3339),
+		Author:  atomPerson{Name: "Entire"},
+		Links:   []atomLink{{Rel: "alternate", Type: "text/html", Href: links.Home()}},
+	}
+	if len(items) > 0 {
+		// Newest first, so the feed changes only when a checkpoint is added
+		f.Updated = items[0].CreatedAt.UTC().Format(time.RFC3339)
+	}
+	for _, item := range items {
+		meta, prompts, _, err := s.readLatestSession(ctx, item.CheckpointID)
+		if err != nil {
+			return nil, err
+		}
+		summary, err := s.Store.ReadCommitted(ctx, item.CheckpointID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read checkpoint %s: %w", item.CheckpointID, err)
+		}
+		var diffStats *checkpoint.DiffStats
+		if summary != nil {
+			diffStats = summary.DiffStats
+		}
+		f.Entries = append(f.Entries, atomEntry{
+			ID:      "urn:entire:checkpoint:" + item.CheckpointID.String(),
+			Title:   feedEntryTitle(item, prompts),
+			Updated: item.CreatedAt.UTC().Format(time.RFC3339),
+			Link:    atomLink{Rel: "alternate", Type: "text/html", Href: links.Checkpoint(item.CheckpointID)},
+			Content: atomContent{Type: "html", Body: feedEntryContent(item, diffStats, meta.Summary)},
+		})
+	}
+	return f, nil
+}
+
+// feedEntryTitle summarizes a checkpoint's first prompt on one line.
+func feedEntryTitle(item checkpointListItem, prompts []string) string {
+	for _, prompt := range prompts {
+		if line := stringutil.CollapseWhitespace(prompt); line != "" {
+			return stringutil.TruncateRunes(line, feedTitleRunes, "…")
+		}
+	}
+	return "Checkpoint " + item.CheckpointID.String()
+}
+
+// feedEntryContent renders a checkpoint's diffstat and summary as HTML.
+func feedEntryContent(item checkpointListItem, diffStats *checkpoint.DiffStats, summary *checkpoint.Summary) string {
+	var sb strings.Builder
+	if diffStats != nil {
+		fmt.Fprintf(&sb, "<p>%d file(s) changed, %d insertion(s)(+), %d deletion(s)(-)</p>\n",
+			diffStats.FilesChanged, diffStats.Insertions, diffStats.Deletions)
+	} else {
+		fmt.Fprintf(&sb, "<p>%d file(s) touched</p>\n", item.FilesTouched)
+	}
+	if summary != nil {
+		if summary.Intent != "" {
+			fmt.Fprintf(&sb, "<p><strong>Intent:</strong> %s</p>\n", html.EscapeString(summary.Intent))
+		}
+		if summary.Outcome != "" {
+			fmt.Fprintf(&sb, "<p><strong>Outcome:</strong> %s</p>\n", html.EscapeString(summary.Outcome))
+		}
+	}
+	fmt.Fprintf(&sb, "<p>Review: %s</p>", html.EscapeString(string(item.Review)))
+	return sb.String()
+}
+
+func writeFeed(w io.Writer, f *atomFeed) error {
+	data, err := xml.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode feed: %w", err)
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err //nolint:wrapcheck // the client went away
+	}
+	if _, err := w.Write(append(data, '\n')); err != nil {
+		return err //nolint:wrapcheck // the client went away
+	}
+	return nil
+}
+
+func (s *Server) handleFeed(w http.ResponseWriter, r *http.Request) {
+	f, err := s.feed(r.Context(), pageLinks{}, time.Now())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/atom+xml; charset=utf-8")
+	_ = writeFeed(w, f) //nolint:errcheck // the client went away
+}
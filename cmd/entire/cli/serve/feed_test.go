@@ -0,0 +1,73 @@
+package serve
+
+import (
+	"context"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+)
+
+func TestFeed(t *testing.T) {
+	t.Parallel()
+	store := newTestStore(t)
+	err := store.WriteCommitted(context.Background(), checkpoint.WriteCommittedOptions{
+		CheckpointID: id.MustCheckpointID("b1b2b3b4b5b6"),
+		SessionID:    "session-002",
+		Strategy:     "manual-commit",
+		Transcript:   []byte("transcript\n"),
+		Prompts:      []string{"  fix the\nlogin   redirect  "},
+		FilesTouched: []string{"login.go"},
+		DiffStats:    &checkpoint.DiffStats{FilesChanged: 2, Insertions: 10, Deletions: 3},
+		Summary:      &checkpoint.Summary{Intent: "Fix <redirect>", Outcome: "Redirect fixed"},
+		AuthorName:   "Test",
+		AuthorEmail:  "test@test.com",
+	})
+	if err != nil {
+		t.Fatalf("WriteCommitted() error = %v", err)
+	}
+	ts := httptest.NewServer((&Server{Store: store}).Handler())
+	t.Cleanup(ts.Close)
+
+	resp, body := get(t, ts.URL+"/feed.atom", nil)
+	if resp.StatusCode != http.StatusOK || !strings.HasPrefix(resp.Header.Get("Content-Type"), "application/atom+xml") {
+		t.Fatalf("GET /feed.atom = %d %s", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+	var feed atomFeed
+	if err := xml.Unmarshal([]byte(body), &feed); err != nil {
+		t.Fatalf("feed is not valid XML: %v\n%s", err, body)
+	}
+	if len(feed.Entries) != 2 {
+		t.Fatalf("feed has %d entries, want 2", len(feed.Entries))
+	}
+
+	var entry *atomEntry
+	for i := range feed.Entries {
+		if feed.Entries[i].ID == "urn:entire:checkpoint:b1b2b3b4b5b6" {
+			entry = &feed.Entries[i]
+		}
+	}
+	if entry == nil {
+		t.Fatalf("no entry for the new checkpoint in %s", body)
+	}
+	if entry.Title != "fix the login redirect" {
+		t.Errorf("title = %q, want the collapsed first prompt", entry.Title)
+	}
+	if entry.Link.Href != "/checkpoints/b1b2b3b4b5b6" {
+		t.Errorf("link = %q", entry.Link.Href)
+	}
+	for _, want := range []string{"2 file(s) changed, 10 insertion(s)(+), 3 deletion(s)(-)", "Fix &lt;redirect&gt;", "Redirect fixed"} {
+		if !strings.Contains(entry.Content.Body, want) {
+			t.Errorf("content should contain %q, got %q", want, entry.Content.Body)
+		}
+	}
+
+	_, index := get(t, ts.URL+"/", nil)
+	if !strings.Contains(index, `type="application/atom+xml"`) {
+		t.Error("index should advertise the feed")
+	}
+}
@@ -0,0 +1,94 @@
+package serve
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+)
+
+// publishedCheckpointsDir holds one page per checkpoint in a static export.
+const publishedCheckpointsDir = "checkpoints"
+
+// Publish renders the checkpoint list, every checkpoint's page, and the
+// Atom feed into outDir as a static site with relative links, so it can be hosted anywhere,
+// e.g. on GitHub Pages. Pages for checkpoints that no longer exist, such as
+// purged ones, are removed. Returns the number of checkpoints published.
+func (s *Server) Publish(ctx context.Context, outDir string, now time.Time) (int, error) {
+	items, err := s.listCheckpoints(ctx, 0)
+	if err != nil {
+		return 0, err
+	}
+	links := pageLinks{static: true}
+
+	pagesDir := filepath.Join(outDir, publishedCheckpointsDir)
+	if err := os.MkdirAll(pagesDir, 0o750); err != nil {
+		return 0, fmt.Errorf("failed to create %s: %w", pagesDir, err)
+	}
+	published := make(map[string]bool, len(items))
+	for _, item := range items {
+		detail, err := s.loadCheckpoint(ctx, item.CheckpointID.String())
+		if err != nil {
+			return 0, err
+		}
+		name := item.CheckpointID.String() + ".html"
+		var buf bytes.Buffer
+		if err := checkpointTemplate.Execute(&buf, checkpointPage{checkpointDetail: detail, Links: links}); err != nil {
+			return 0, fmt.Errorf("failed to render checkpoint %s: %w", item.CheckpointID, err)
+		}
+		if err := os.WriteFile(filepath.Join(pagesDir, name), buf.Bytes(), 0o644); err != nil { //nolint:gosec // published pages are meant to be world-readable
+			return 0, fmt.Errorf("failed to write checkpoint page: %w", err)
+		}
+		published[name] = true
+	}
+	if err := removeStalePages(pagesDir, published); err != nil {
+		return 0, err
+	}
+
+	var buf bytes.Buffer
+	page := indexPage{Items: items, ReadOnly: true, Generated: now.UTC().Format(time.RFC3339), Links: links}
+	if err := indexTemplate.Execute(&buf, page); err != nil {
+		return 0, fmt.Errorf("failed to render index: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(outDir, "index.html"), buf.Bytes(), 0o644); err != nil { //nolint:gosec // published pages are meant to be world-readable
+		return 0, fmt.Errorf("failed to write index: %w", err)
+	}
+
+	f, err := s.feed(ctx, links, now)
+	if err != nil {
+		return 0, err
+	}
+	buf.Reset()
+	if err := writeFeed(&buf, f); err != nil {
+		return 0, err
+	}
+	if err := os.WriteFile(filepath.Join(outDir, feedFileName), buf.Bytes(), 0o644); err != nil { //nolint:gosec // published pages are meant to be world-readable
+		return 0, fmt.Errorf("failed to write feed: %w", err)
+	}
+	return len(items), nil
+}
+
+// removeStalePages deletes checkpoint pages from a previous export that
+// weren't published this time. Other files are left alone.
+func removeStalePages(pagesDir string, published map[string]bool) error {
+	entries, err := os.ReadDir(pagesDir)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", pagesDir, err)
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		base, ok := strings.CutSuffix(name, ".html")
+		if !ok || entry.IsDir() || published[name] || id.Validate(base) != nil {
+			continue
+		}
+		if err := os.Remove(filepath.Join(pagesDir, name)); err != nil {
+			return fmt.Errorf("failed to remove stale page %s: %w", name, err)
+		}
+	}
+	return nil
+}
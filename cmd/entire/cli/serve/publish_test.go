@@ -0,0 +1,66 @@
+package serve
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestServer_Publish(t *testing.T) {
+	t.Parallel()
+	srv := &Server{Store: newTestStore(t), ReadOnly: true}
+	outDir := t.TempDir()
+	pagesDir := filepath.Join(outDir, publishedCheckpointsDir)
+
+	// Leftovers from an earlier export: a purged checkpoint and an unrelated file
+	if err := os.MkdirAll(pagesDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"ffffffffffff.html", "notes.html"} {
+		if err := os.WriteFile(filepath.Join(pagesDir, name), []byte("old"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	count, err := srv.Publish(context.Background(), outDir, time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	if count != 1 {
+		t.Errorf("Publish() = %d, want 1", count)
+	}
+
+	index, err := os.ReadFile(filepath.Join(outDir, "index.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(index), `href="checkpoints/`+testCheckpointID+`.html"`) || !strings.Contains(string(index), "2026-01-02T03:04:05Z") {
+		t.Errorf("index should link pages relatively and show the export time:\n%s", index)
+	}
+
+	page, err := os.ReadFile(filepath.Join(pagesDir, testCheckpointID+".html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(page), `href="../index.html"`) || !strings.Contains(string(page), "add a &lt;script&gt; tag") {
+		t.Errorf("checkpoint page should link back and show escaped prompts:\n%s", page)
+	}
+
+	feed, err := os.ReadFile(filepath.Join(outDir, feedFileName))
+	if err != nil {
+		t.Fatalf("feed not written: %v", err)
+	}
+	if !strings.Contains(string(feed), `href="checkpoints/`+testCheckpointID+`.html"`) {
+		t.Errorf("feed should link pages relatively:\n%s", feed)
+	}
+
+	if _, err := os.Stat(filepath.Join(pagesDir, "ffffffffffff.html")); !os.IsNotExist(err) {
+		t.Error("stale checkpoint page should be removed")
+	}
+	if _, err := os.Stat(filepath.Join(pagesDir, "notes.html")); err != nil {
+		t.Error("unrelated files should be kept")
+	}
+}
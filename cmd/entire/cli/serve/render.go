@@ -0,0 +1,218 @@
+package serve
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"html/template"
+	"log/slog"
+	"net/http"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+	"github.com/entireio/cli/cmd/entire/cli/logging"
+)
+
+// httpError is an error with the status code it should be reported as.
+// Its message is shown to the client; other errors are logged and reported
+// as a generic 500.
+type httpError struct {
+	status  int
+	message string
+}
+
+func (e *httpError) Error() string { return e.message }
+
+var errNotFound = &httpError{status: http.StatusNotFound, message: "checkpoint not found"}
+
+func errBadRequest(message string) error {
+	return &httpError{status: http.StatusBadRequest, message: message}
+}
+
+func writeError(w http.ResponseWriter, r *http.Request, err error) {
+	var httpErr *httpError
+	if errors.As(err, &httpErr) {
+		http.Error(w, httpErr.message, httpErr.status)
+		return
+	}
+	logging.Error(r.Context(), "dashboard request failed",
+		slog.String("path", r.URL.Path),
+		slog.String("error", err.Error()))
+	http.Error(w, "internal server error", http.StatusInternalServerError)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(v) //nolint:errcheck // the client went away
+}
+
+func renderHTML(w http.ResponseWriter, r *http.Request, tmpl *template.Template, data any) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := tmpl.Execute(w, data); err != nil {
+		logging.Error(r.Context(), "failed to render dashboard page", slog.String("error", err.Error()))
+	}
+}
+
+func contextWithIdentity(r *http.Request, identity Identity) context.Context {
+	return context.WithValue(r.Context(), identityKey{}, identity)
+}
+
+// identityFromContext returns the authenticated caller, or the zero Identity
+// when the server runs without authentication.
+func identityFromContext(r *http.Request) Identity {
+	identity, _ := r.Context().Value(identityKey{}).(Identity) //nolint:errcheck // zero value when unauthenticated
+	return identity
+}
+
+// pageLinks builds the links between pages: server routes, or relative
+// file names in a static export.
+type pageLinks struct {
+	static bool
+}
+
+// Index links to the checkpoint list.
+func (l pageLinks) Index() string {
+	if l.static {
+		return "../index.html"
+	}
+	return "/"
+}
+
+// Home links to the checkpoint list from the list itself or the feed.
+func (l pageLinks) Home() string {
+	if l.static {
+		return "index.html"
+	}
+	return "/"
+}
+
+// Feed links to the Atom feed from the checkpoint list.
+func (l pageLinks) Feed() string {
+	if l.static {
+		return feedFileName
+	}
+	return "/" + feedFileName
+}
+
+// Checkpoint links to a checkpoint's page from the checkpoint list or the feed.
+func (l pageLinks) Checkpoint(cpID id.CheckpointID) string {
+	if l.static {
+		return "checkpoints/" + cpID.String() + ".html"
+	}
+	return "/checkpoints/" + cpID.String()
+}
+
+// indexPage is the data for indexTemplate.
+type indexPage struct {
+	Items    []checkpointListItem
+	ReadOnly bool
+	// Generated is set in static exports.
+	Generated string
+	Links     pageLinks
+}
+
+// checkpointPage is the data for checkpointTemplate.
+type checkpointPage struct {
+	*checkpointDetail
+
+	Links pageLinks
+}
+
+const pageStyle = `<style>
+body { font-family: system-ui, sans-serif; margin: 2rem; max-width: 60rem; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ccc; padding: 0.3rem 0.6rem; text-align: left; }
+pre { white-space: pre-wrap; background: #f6f6f6; padding: 0.6rem; }
+.approved { color: #070; }
+.changes_requested { color: #b00; }
+.pending { color: #a60; }
+.comment { border-left: 3px solid #ccc; padding-left: 0.6rem; margin: 0.6rem 0; }
+.muted { color: #666; }
+</style>`
+
+var indexTemplate = template.Must(template.New("index").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Entire checkpoints</title>
+<link rel="alternate" type="application/atom+xml" title="Entire checkpoints" href="{{.Links.Feed}}">
+` + pageStyle + `
+</head>
+<body>
+<h1>Checkpoints</h1>
+<p><a href="{{.Links.Feed}}">Atom feed</a></p>
+{{- if .Generated}}
+<p class="muted">Exported {{.Generated}}.</p>
+{{- else if .ReadOnly}}
+<p class="muted">Read-only dashboard.</p>
+{{- end}}
+{{- if .Items}}
+<table>
+<thead><tr><th>Checkpoint</th><th>Created</th><th>Agent</th><th>Files</th><th>Review</th></tr></thead>
+<tbody>
+{{- range .Items}}
+<tr><td><a href="{{$.Links.Checkpoint .CheckpointID}}">{{.CheckpointID}}</a></td><td>{{.CreatedAt.UTC.Format "2006-01-02 15:04"}}</td><td>{{.Agent}}</td><td>{{.FilesTouched}}</td><td class="{{.Review}}">{{.Review}}</td></tr>
+{{- end}}
+</tbody>
+</table>
+{{- else}}
+<p>No checkpoints yet.</p>
+{{- end}}
+</body>
+</html>
+`))
+
+var checkpointTemplate = template.Must(template.New("checkpoint").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Checkpoint {{.CheckpointID}}</title>
+` + pageStyle + `
+</head>
+<body>
+<p><a href="{{.Links.Index}}">All checkpoints</a></p>
+<h1>Checkpoint {{.CheckpointID}}</h1>
+<p>
+Created {{.CreatedAt.UTC.Format "2006-01-02 15:04"}} UTC
+{{- with .Agent}} by {{.}}{{end}}
+{{- with .Model}} ({{.}}){{end}}
+{{- with .Branch}} on {{.}}{{end}}<br>
+Session {{.SessionID}}
+</p>
+<p class="{{.Review.State}}">Review: {{.Review.State}} ({{len .Review.Approvers}} of {{.Review.RequiredApprovals}} approvals)</p>
+<h2>Prompts</h2>
+{{- range .Prompts}}
+<pre>{{.}}</pre>
+{{- else}}
+<p class="muted">{{if .Encrypted}}Prompts are encrypted.{{else}}No prompts.{{end}}</p>
+{{- end}}
+<h2>Files</h2>
+<ul>
+{{- range .FilesTouched}}
+<li>{{.}}</li>
+{{- end}}
+</ul>
+{{- if .Attachments}}
+<h2>Attachments</h2>
+<ul>
+{{- range .Attachments}}
+<li>{{.Name}} <span class="muted">{{.ContentType}}, {{.Size}} bytes</span></li>
+{{- end}}
+</ul>
+{{- end}}
+<h2>Comments</h2>
+{{- range .Comments}}
+<div class="comment" style="margin-left: {{.Depth}}rem">
+<p class="muted">{{.AuthorName}}{{with .AuthorEmail}} &lt;{{.}}&gt;{{end}} &middot; {{.Timestamp.UTC.Format "2006-01-02 15:04"}}{{with .Verdict}} &middot; <span class="{{.}}">{{.}}</span>{{end}}</p>
+{{- with .Body}}
+<pre>{{.}}</pre>
+{{- end}}
+</div>
+{{- else}}
+<p class="muted">No comments.</p>
+{{- end}}
+</body>
+</html>
+`))
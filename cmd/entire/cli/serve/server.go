@@ -0,0 +1,314 @@
+// Package serve implements `entire serve`, a small web dashboard over the
+// checkpoints on a repository's metadata branch. It is meant to run against
+// a mirror clone and be shared with a team: it can be bound to any address,
+// served over TLS, put behind basic auth or OIDC, and made read-only. The
+// same pages can be exported as a static site with Server.Publish, and new
+// checkpoints are announced in an Atom feed.
+package serve
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+	"github.com/entireio/cli/cmd/entire/cli/logging"
+)
+
+// defaultListLimit caps the checkpoints listed when no limit is requested.
+const defaultListLimit = 100
+
+// maxRequestBytes caps request bodies.
+const maxRequestBytes = 64 << 10
+
+// Server serves committed checkpoints from a store.
+type Server struct {
+	Store checkpoint.CommittedStore
+
+	// ReadOnly rejects comments and review verdicts.
+	ReadOnly bool
+
+	// Auth, if set, is required for every request.
+	Auth Authenticator
+
+	// RequiredApprovals is passed to checkpoint.ComputeReviewStatus.
+	RequiredApprovals int
+}
+
+// Handler returns the server's HTTP handler.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", s.handleIndex)
+	mux.HandleFunc("GET /checkpoints/{id}", s.handleCheckpointPage)
+	mux.HandleFunc("GET /"+feedFileName, s.handleFeed)
+	mux.HandleFunc("GET /api/checkpoints", s.handleListAPI)
+	mux.HandleFunc("GET /api/checkpoints/{id}", s.handleCheckpointAPI)
+	mux.HandleFunc("POST /api/checkpoints/{id}/comments", s.handleAddComment)
+	return s.withAuth(securityHeaders(mux))
+}
+
+type identityKey struct{}
+
+func (s *Server) withAuth(next http.Handler) http.Handler {
+	if s.Auth == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		identity, err := s.Auth.Authenticate(r)
+		if err != nil {
+			logging.Debug(r.Context(), "rejected dashboard request", slog.String("error", err.Error()))
+			w.Header().Set("WWW-Authenticate", s.Auth.Challenge())
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(contextWithIdentity(r, identity)))
+	})
+}
+
+func securityHeaders(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h := w.Header()
+		h.Set("Content-Security-Policy", "default-src 'none'; style-src 'unsafe-inline'")
+		h.Set("X-Content-Type-Options", "nosniff")
+		h.Set("X-Frame-Options", "DENY")
+		h.Set("Referrer-Policy", "no-referrer")
+		next.ServeHTTP(w, r)
+	})
+}
+
+// checkpointListItem is a checkpoint in the list views.
+type checkpointListItem struct {
+	CheckpointID id.CheckpointID        `json:"checkpoint_id"`
+	CreatedAt    time.Time              `json:"created_at"`
+	Agent        string                 `json:"agent,omitempty"`
+	SessionID    string                 `json:"session_id"`
+	FilesTouched int                    `json:"files_touched"`
+	Review       checkpoint.ReviewState `json:"review"`
+}
+
+// checkpointDetail is a checkpoint in the detail views.
+type checkpointDetail struct {
+	CheckpointID id.CheckpointID `json:"checkpoint_id"`
+	CreatedAt    time.Time       `json:"created_at"`
+	Agent        string          `json:"agent,omitempty"`
+	Model        string          `json:"model,omitempty"`
+	SessionID    string          `json:"session_id"`
+	Branch       string          `json:"branch,omitempty"`
+	FilesTouched []string        `json:"files_touched"`
+	Prompts      []string        `json:"prompts"`
+	// Encrypted is set when the prompts are encrypted to a key this server
+	// doesn't have.
+	Encrypted   bool                         `json:"encrypted,omitempty"`
+	Attachments []checkpoint.AttachmentInfo  `json:"attachments,omitempty"`
+	Comments    []checkpoint.ThreadedComment `json:"comments"`
+	Review      checkpoint.ReviewStatus      `json:"review"`
+}
+
+// listLimit returns the ?limit of a list request.
+func listLimit(r *http.Request) (int, error) {
+	raw := r.URL.Query().Get("limit")
+	if raw == "" {
+		return defaultListLimit, nil
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil || n < 0 {
+		return 0, errBadRequest("limit must be a non-negative number")
+	}
+	return n, nil
+}
+
+// listCheckpoints returns up to limit checkpoints, newest first. A limit of
+// 0 returns all of them.
+func (s *Server) listCheckpoints(ctx context.Context, limit int) ([]checkpointListItem, error) {
+	page, err := s.Store.ListCommittedPage(ctx, checkpoint.ListOptions{Limit: limit})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list checkpoints: %w", err)
+	}
+
+	items := make([]checkpointListItem, 0, len(page.Checkpoints))
+	for _, info := range page.Checkpoints {
+		comments, err := s.Store.ReadComments(ctx, info.CheckpointID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read comments for %s: %w", info.CheckpointID, err)
+		}
+		items = append(items, checkpointListItem{
+			CheckpointID: info.CheckpointID,
+			CreatedAt:    info.CreatedAt,
+			Agent:        string(info.Agent),
+			SessionID:    info.SessionID,
+			FilesTouched: len(info.FilesTouched),
+			Review:       checkpoint.ComputeReviewStatus(comments, s.RequiredApprovals).State,
+		})
+	}
+	return items, nil
+}
+
+func (s *Server) loadCheckpoint(ctx context.Context, rawID string) (*checkpointDetail, error) {
+	cpID, err := id.NewCheckpointID(rawID)
+	if err != nil {
+		return nil, errNotFound
+	}
+	summary, err := s.Store.ReadCommitted(ctx, cpID)
+	if err != nil || summary == nil {
+		return nil, errNotFound
+	}
+	meta, prompts, encrypted, err := s.readLatestSession(ctx, cpID)
+	if err != nil {
+		return nil, err
+	}
+	comments, err := s.Store.ReadComments(ctx, cpID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read comments for %s: %w", cpID, err)
+	}
+	if prompts == nil {
+		prompts = []string{}
+	}
+	return &checkpointDetail{
+		CheckpointID: cpID,
+		CreatedAt:    meta.CreatedAt,
+		Agent:        string(meta.Agent),
+		Model:        meta.Model,
+		SessionID:    meta.SessionID,
+		Branch:       meta.Branch,
+		FilesTouched: summary.FilesTouched,
+		Prompts:      prompts,
+		Encrypted:    encrypted,
+		Attachments:  meta.Attachments,
+		Comments:     checkpoint.ThreadComments(comments),
+		Review:       checkpoint.ComputeReviewStatus(comments, s.RequiredApprovals),
+	}, nil
+}
+
+// readLatestSession returns the metadata and prompts of a checkpoint's
+// latest session. Content encrypted to a key the server doesn't have is
+// reported as encrypted instead of failing the page.
+func (s *Server) readLatestSession(ctx context.Context, cpID id.CheckpointID) (checkpoint.CommittedMetadata, []string, bool, error) {
+	content, err := s.Store.ReadLatestSessionContent(ctx, cpID)
+	if err == nil {
+		return content.Metadata, content.PromptList, false, nil
+	}
+	if !errors.Is(err, checkpoint.ErrNoIdentity) {
+		return checkpoint.CommittedMetadata{}, nil, false, fmt.Errorf("failed to read checkpoint %s: %w", cpID, err)
+	}
+	sessions, metaErr := s.Store.ReadSessionMetadata(ctx, cpID)
+	if metaErr != nil || len(sessions) == 0 {
+		return checkpoint.CommittedMetadata{}, nil, false, fmt.Errorf("failed to read checkpoint %s: %w", cpID, err)
+	}
+	return sessions[len(sessions)-1], nil, true, nil
+}
+
+func (s *Server) handleIndex(w http.ResponseWriter, r *http.Request) {
+	limit, err := listLimit(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	items, err := s.listCheckpoints(r.Context(), limit)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	renderHTML(w, r, indexTemplate, indexPage{Items: items, ReadOnly: s.ReadOnly})
+}
+
+func (s *Server) handleCheckpointPage(w http.ResponseWriter, r *http.Request) {
+	detail, err := s.loadCheckpoint(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	renderHTML(w, r, checkpointTemplate, checkpointPage{checkpointDetail: detail})
+}
+
+func (s *Server) handleListAPI(w http.ResponseWriter, r *http.Request) {
+	limit, err := listLimit(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	items, err := s.listCheckpoints(r.Context(), limit)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, items)
+}
+
+func (s *Server) handleCheckpointAPI(w http.ResponseWriter, r *http.Request) {
+	detail, err := s.loadCheckpoint(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, detail)
+}
+
+// addCommentRequest is the body of POST /api/checkpoints/{id}/comments.
+type addCommentRequest struct {
+	Body    string                 `json:"body"`
+	ReplyTo string                 `json:"reply_to,omitempty"`
+	Verdict checkpoint.ReviewState `json:"verdict,omitempty"`
+}
+
+func (s *Server) handleAddComment(w http.ResponseWriter, r *http.Request) {
+	if s.ReadOnly {
+		http.Error(w, "this dashboard is read-only", http.StatusMethodNotAllowed)
+		return
+	}
+	// Browsers can't send JSON cross-site without a CORS preflight, which
+	// this server never grants, so requiring it blocks CSRF.
+	if mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type")); err != nil || mediaType != "application/json" {
+		http.Error(w, "Content-Type must be application/json", http.StatusUnsupportedMediaType)
+		return
+	}
+	cpID, err := id.NewCheckpointID(r.PathValue("id"))
+	if err != nil {
+		writeError(w, r, errNotFound)
+		return
+	}
+	var req addCommentRequest
+	dec := json.NewDecoder(io.LimitReader(r.Body, maxRequestBytes))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&req); err != nil {
+		writeError(w, r, errBadRequest("invalid comment: "+err.Error()))
+		return
+	}
+	switch req.Verdict {
+	case "", checkpoint.ReviewApproved, checkpoint.ReviewChangesRequested:
+	default:
+		writeError(w, r, errBadRequest(fmt.Sprintf("invalid verdict %q", req.Verdict)))
+		return
+	}
+	if strings.TrimSpace(req.Body) == "" && req.Verdict == "" {
+		writeError(w, r, errBadRequest("comment is empty"))
+		return
+	}
+
+	identity := identityFromContext(r)
+	comment, err := s.Store.AddComment(r.Context(), cpID, checkpoint.Comment{
+		ReplyTo:     req.ReplyTo,
+		Body:        req.Body,
+		Verdict:     req.Verdict,
+		AuthorName:  identity.Name,
+		AuthorEmail: identity.Email,
+	})
+	switch {
+	case errors.Is(err, checkpoint.ErrCheckpointNotFound):
+		writeError(w, r, errNotFound)
+	case errors.Is(err, checkpoint.ErrCommentNotFound):
+		writeError(w, r, errBadRequest(err.Error()))
+	case err != nil:
+		writeError(w, r, err)
+	default:
+		writeJSON(w, http.StatusCreated, comment)
+	}
+}
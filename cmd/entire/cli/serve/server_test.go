@@ -0,0 +1,255 @@
+package serve
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/entireio/cli/cmd/entire/cli/age"
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+
+	"github.com/go-git/go-git/v5"
+)
+
+const testCheckpointID = "a1b2c3d4e5f6"
+
+// newTestStore returns a store holding one committed checkpoint.
+func newTestStore(t *testing.T) *checkpoint.GitStore {
+	t.Helper()
+	repo, err := git.PlainInit(t.TempDir(), false)
+	if err != nil {
+		t.Fatalf("failed to init git repo: %v", err)
+	}
+	store := checkpoint.NewGitStore(repo)
+	err = store.WriteCommitted(context.Background(), checkpoint.WriteCommittedOptions{
+		CheckpointID: id.MustCheckpointID(testCheckpointID),
+		SessionID:    "session-001",
+		Strategy:     "manual-commit",
+		Transcript:   []byte("transcript\n"),
+		Prompts:      []string{"add a <script> tag"},
+		FilesTouched: []string{"main.go"},
+		AuthorName:   "Test",
+		AuthorEmail:  "test@test.com",
+	})
+	if err != nil {
+		t.Fatalf("WriteCommitted() error = %v", err)
+	}
+	return store
+}
+
+func newTestServer(t *testing.T, srv *Server) *httptest.Server {
+	t.Helper()
+	srv.Store = newTestStore(t)
+	ts := httptest.NewServer(srv.Handler())
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func get(t *testing.T, url string, setup func(*http.Request)) (*http.Response, string) {
+	t.Helper()
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if setup != nil {
+		setup(req)
+	}
+	return do(t, req)
+}
+
+func postComment(t *testing.T, url, contentType, body string, setup func(*http.Request)) (*http.Response, string) {
+	t.Helper()
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, url+"/api/checkpoints/"+testCheckpointID+"/comments", strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", contentType)
+	if setup != nil {
+		setup(req)
+	}
+	return do(t, req)
+}
+
+func do(t *testing.T, req *http.Request) (*http.Response, string) {
+	t.Helper()
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("%s %s: %v", req.Method, req.URL, err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp, string(body)
+}
+
+func TestServer_Pages(t *testing.T) {
+	t.Parallel()
+	ts := newTestServer(t, &Server{})
+
+	resp, body := get(t, ts.URL+"/", nil)
+	if resp.StatusCode != http.StatusOK || !strings.Contains(body, `href="/checkpoints/`+testCheckpointID+`"`) {
+		t.Fatalf("GET / = %d, body:\n%s", resp.StatusCode, body)
+	}
+	if resp.Header.Get("Content-Security-Policy") == "" {
+		t.Error("pages should set a Content-Security-Policy")
+	}
+
+	resp, body = get(t, ts.URL+"/checkpoints/"+testCheckpointID, nil)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("GET checkpoint page = %d", resp.StatusCode)
+	}
+	if strings.Contains(body, "<script>") || !strings.Contains(body, "add a &lt;script&gt; tag") {
+		t.Errorf("prompts should be HTML-escaped, body:\n%s", body)
+	}
+
+	if resp, _ := get(t, ts.URL+"/checkpoints/ffffffffffff", nil); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("unknown checkpoint = %d, want 404", resp.StatusCode)
+	}
+}
+
+func TestServer_API(t *testing.T) {
+	t.Parallel()
+	ts := newTestServer(t, &Server{})
+
+	_, body := get(t, ts.URL+"/api/checkpoints", nil)
+	var items []checkpointListItem
+	if err := json.Unmarshal([]byte(body), &items); err != nil {
+		t.Fatalf("decoding list: %v\n%s", err, body)
+	}
+	if len(items) != 1 || items[0].CheckpointID.String() != testCheckpointID || items[0].Review != checkpoint.ReviewPending {
+		t.Errorf("list = %+v", items)
+	}
+
+	if resp, _ := get(t, ts.URL+"/api/checkpoints?limit=x", nil); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("bad limit = %d, want 400", resp.StatusCode)
+	}
+
+	resp, body := postComment(t, ts.URL, "application/json; charset=utf-8", `{"body":"ship it","verdict":"approved"}`, nil)
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("POST comment = %d: %s", resp.StatusCode, body)
+	}
+
+	_, body = get(t, ts.URL+"/api/checkpoints/"+testCheckpointID, nil)
+	var detail checkpointDetail
+	if err := json.Unmarshal([]byte(body), &detail); err != nil {
+		t.Fatalf("decoding detail: %v\n%s", err, body)
+	}
+	if len(detail.Comments) != 1 || detail.Comments[0].Body != "ship it" || detail.Review.State != checkpoint.ReviewApproved {
+		t.Errorf("detail = %+v", detail)
+	}
+	if len(detail.Prompts) != 1 || len(detail.FilesTouched) != 1 {
+		t.Errorf("detail should include prompts and files, got %+v", detail)
+	}
+}
+
+func TestServer_AddCommentValidation(t *testing.T) {
+	t.Parallel()
+	ts := newTestServer(t, &Server{})
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		want        int
+	}{
+		{"form post", "application/x-www-form-urlencoded", "body=hi", http.StatusUnsupportedMediaType},
+		{"empty", "application/json", `{"body":"  "}`, http.StatusBadRequest},
+		{"bad verdict", "application/json", `{"body":"x","verdict":"merged"}`, http.StatusBadRequest},
+		{"unknown field", "application/json", `{"text":"x"}`, http.StatusBadRequest},
+		{"unknown reply", "application/json", `{"body":"x","reply_to":"nope"}`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		if resp, body := postComment(t, ts.URL, tt.contentType, tt.body, nil); resp.StatusCode != tt.want {
+			t.Errorf("%s: status = %d, want %d (%s)", tt.name, resp.StatusCode, tt.want, body)
+		}
+	}
+}
+
+func TestServer_ReadOnly(t *testing.T) {
+	t.Parallel()
+	ts := newTestServer(t, &Server{ReadOnly: true})
+
+	if resp, _ := postComment(t, ts.URL, "application/json", `{"body":"hi"}`, nil); resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("POST in read-only mode = %d, want 405", resp.StatusCode)
+	}
+	if resp, _ := get(t, ts.URL+"/api/checkpoints/"+testCheckpointID, nil); resp.StatusCode != http.StatusOK {
+		t.Errorf("GET in read-only mode = %d, want 200", resp.StatusCode)
+	}
+}
+
+func TestServer_BasicAuth(t *testing.T) {
+	t.Parallel()
+	ts := newTestServer(t, &Server{Auth: NewBasicAuth("team", "s3cret")})
+
+	resp, _ := get(t, ts.URL+"/", nil)
+	if resp.StatusCode != http.StatusUnauthorized || !strings.HasPrefix(resp.Header.Get("WWW-Authenticate"), "Basic ") {
+		t.Errorf("unauthenticated GET = %d %q, want 401 with a Basic challenge", resp.StatusCode, resp.Header.Get("WWW-Authenticate"))
+	}
+	if resp, _ := get(t, ts.URL+"/", func(r *http.Request) { r.SetBasicAuth("team", "wrong") }); resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("wrong password = %d, want 401", resp.StatusCode)
+	}
+
+	authed := func(r *http.Request) { r.SetBasicAuth("team", "s3cret") }
+	if resp, _ := get(t, ts.URL+"/", authed); resp.StatusCode != http.StatusOK {
+		t.Errorf("authenticated GET = %d, want 200", resp.StatusCode)
+	}
+	resp, body := postComment(t, ts.URL, "application/json", `{"body":"hi"}`, authed)
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("authenticated POST = %d: %s", resp.StatusCode, body)
+	}
+	var comment checkpoint.Comment
+	if err := json.Unmarshal([]byte(body), &comment); err != nil {
+		t.Fatal(err)
+	}
+	if comment.AuthorName != "team" {
+		t.Errorf("comment author = %q, want the authenticated user", comment.AuthorName)
+	}
+}
+
+func TestCheckpointPage_EncryptedWithoutIdentity(t *testing.T) {
+	t.Parallel()
+	repo, err := git.PlainInit(t.TempDir(), false)
+	if err != nil {
+		t.Fatalf("failed to init git repo: %v", err)
+	}
+	identity, err := age.GenerateIdentity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := checkpoint.NewGitStore(repo)
+	store.SetIdentities(nil)
+	err = store.WriteCommitted(context.Background(), checkpoint.WriteCommittedOptions{
+		CheckpointID: id.MustCheckpointID(testCheckpointID),
+		SessionID:    "session-001",
+		Strategy:     "manual-commit",
+		Transcript:   []byte("transcript\n"),
+		Prompts:      []string{"secret prompt"},
+		FilesTouched: []string{"main.go"},
+		AuthorName:   "Test",
+		AuthorEmail:  "test@test.com",
+		EncryptTo:    []*age.Recipient{identity.Recipient()},
+	})
+	if err != nil {
+		t.Fatalf("WriteCommitted() error = %v", err)
+	}
+	ts := httptest.NewServer((&Server{Store: store}).Handler())
+	t.Cleanup(ts.Close)
+
+	resp, body := get(t, ts.URL+"/checkpoints/"+testCheckpointID, nil)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, body %s", resp.StatusCode, body)
+	}
+	if !strings.Contains(body, "Prompts are encrypted.") || strings.Contains(body, "secret prompt") {
+		t.Errorf("page should note encrypted prompts, got %s", body)
+	}
+	if !strings.Contains(body, "session-001") {
+		t.Error("page should still show the session metadata")
+	}
+}
@@ -0,0 +1,42 @@
+package cli
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestIsLoopbackBind(t *testing.T) {
+	t.Parallel()
+	tests := map[string]bool{
+		"127.0.0.1:8080": true,
+		"localhost:8080": true,
+		"[::1]:8080":     true,
+		"0.0.0.0:8080":   false,
+		":8080":          false,
+		"10.0.0.5:443":   false,
+		"bad":            false,
+	}
+	for addr, want := range tests {
+		if got := isLoopbackBind(addr); got != want {
+			t.Errorf("isLoopbackBind(%q) = %v, want %v", addr, got, want)
+		}
+	}
+}
+
+func TestRunServe_RefusesPublicBindWithoutAuth(t *testing.T) {
+	t.Parallel()
+	err := runServe(context.Background(), io.Discard, serveOptions{Bind: "0.0.0.0:0"})
+	if err == nil || !strings.Contains(err.Error(), "without authentication") {
+		t.Errorf("runServe() error = %v, want refusal", err)
+	}
+}
+
+func TestRunServe_BasicAuthNeedsPassword(t *testing.T) {
+	t.Parallel()
+	err := runServe(context.Background(), io.Discard, serveOptions{Bind: "127.0.0.1:0", BasicAuthUser: "team"})
+	if err == nil || !strings.Contains(err.Error(), servePasswordEnv) {
+		t.Errorf("runServe() error = %v, want missing password error", err)
+	}
+}
@@ -14,6 +14,7 @@ import (
 
 	"github.com/entireio/cli/cmd/entire/cli/agent"
 	"github.com/entireio/cli/cmd/entire/cli/agent/types"
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
 	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
 	"github.com/entireio/cli/cmd/entire/cli/jsonutil"
 	"github.com/entireio/cli/cmd/entire/cli/logging"
@@ -27,6 +28,10 @@ const (
 	// StaleSessionThreshold is the duration after which an ended session is considered stale
 	// and will be automatically deleted during load/list operations.
 	StaleSessionThreshold = 7 * 24 * time.Hour
+
+	// HeartbeatTimeout is how long an active session may go without a heartbeat
+	// before it is presumed crashed.
+	HeartbeatTimeout = 30 * time.Minute
 )
 
 // State represents the state of an active session.
@@ -75,6 +80,24 @@ type State struct {
 	//   - Persists until the next InitializeSession call generates a new one
 	TurnID string `json:"turn_id,omitempty"`
 
+	// TurnCount is the number of prompts submitted in this session. Sessions
+	// started before it was tracked count from their next prompt.
+	TurnCount int `json:"turn_count,omitempty"`
+
+	// TurnStartedAt is when the current turn's prompt was submitted.
+	// Set in InitializeSession, cleared once the turn's timing is recorded.
+	TurnStartedAt *time.Time `json:"turn_started_at,omitempty"`
+
+	// TurnTimings holds the timing of finished turns not yet condensed into a
+	// checkpoint. Appended at turn end, moved to checkpoint metadata on condensation.
+	TurnTimings []checkpoint.TurnTiming `json:"turn_timings,omitempty"`
+
+	// CorrelationID links this session to sessions in other repositories that
+	// belong to the same piece of work (e.g. a frontend and backend change).
+	// Taken from ENTIRE_CORRELATION_ID when the session starts, or set via
+	// `entire linked --set`. Empty means the session is not linked.
+	CorrelationID string `json:"correlation_id,omitempty"`
+
 	// TurnCheckpointIDs tracks all checkpoint IDs condensed during the current turn.
 	// Lifecycle:
 	//   - Set in PostCommit when a checkpoint is condensed for an ACTIVE session
@@ -88,6 +111,14 @@ type State struct {
 	// Used for stale session detection in "entire doctor".
 	LastInteractionTime *time.Time `json:"last_interaction_time,omitempty"`
 
+	// HeartbeatAt is when the agent last showed signs of life: any hook invocation,
+	// or the periodic heartbeat of the `entire exec` wrapper while the agent runs.
+	HeartbeatAt *time.Time `json:"heartbeat_at,omitempty"`
+
+	// CrashedAt is set when an active session went silent past HeartbeatTimeout
+	// and its work was checkpointed from the worktree. Cleared by the next heartbeat.
+	CrashedAt *time.Time `json:"crashed_at,omitempty"`
+
 	// StepCount is the number of checkpoints/steps created in this session.
 	// JSON tag kept as "checkpoint_count" for backward compatibility with existing state files.
 	StepCount int `json:"checkpoint_count"`
@@ -117,6 +148,13 @@ type State struct {
 	// AgentType identifies the agent that created this session (e.g., "Claude Code", "Gemini CLI", "Cursor")
 	AgentType types.AgentType `json:"agent_type,omitempty"`
 
+	// Model is the model that produced the most recent turn (e.g. "claude-sonnet-4-5-20250929"),
+	// from the hook payload or the transcript. Updated at each turn end.
+	Model string `json:"model,omitempty"`
+
+	// AgentVersion is the agent client version seen at the most recent turn end.
+	AgentVersion string `json:"agent_version,omitempty"`
+
 	// Token usage tracking (accumulated across all checkpoints in this session)
 	TokenUsage *agent.TokenUsage `json:"token_usage,omitempty"`
 
@@ -218,6 +256,39 @@ func (s *State) IsStale() bool {
 	return s.LastInteractionTime != nil && time.Since(*s.LastInteractionTime) > StaleSessionThreshold
 }
 
+// LastHeartbeat returns the latest sign of life of the session's agent,
+// falling back to LastInteractionTime for sessions without heartbeats.
+func (s *State) LastHeartbeat() *time.Time {
+	if s.HeartbeatAt == nil || (s.LastInteractionTime != nil && s.LastInteractionTime.After(*s.HeartbeatAt)) {
+		return s.LastInteractionTime
+	}
+	return s.HeartbeatAt
+}
+
+// IsSilent returns true when the session is mid-turn but its agent hasn't
+// sent a heartbeat for longer than HeartbeatTimeout.
+func (s *State) IsSilent(now time.Time) bool {
+	last := s.LastHeartbeat()
+	return s.Phase.IsActive() && s.EndedAt == nil && last != nil && now.Sub(*last) > HeartbeatTimeout
+}
+
+// RecordTurnTiming appends the timing of the current turn, which ended at
+// endedAt, to TurnTimings. Does nothing if the turn's start was not recorded,
+// so each turn is recorded at most once.
+func (s *State) RecordTurnTiming(endedAt time.Time, toolTime, modelTime time.Duration) {
+	if s.TurnStartedAt == nil {
+		return
+	}
+	s.TurnTimings = append(s.TurnTimings, checkpoint.TurnTiming{
+		TurnID:    s.TurnID,
+		StartedAt: *s.TurnStartedAt,
+		EndedAt:   endedAt,
+		ToolMs:    toolTime.Milliseconds(),
+		ModelMs:   modelTime.Milliseconds(),
+	})
+	s.TurnStartedAt = nil
+}
+
 // StateStore provides low-level operations for managing session state files.
 //
 // StateStore is a primitive for session state persistence. It is NOT the same as
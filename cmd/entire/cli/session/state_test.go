@@ -124,6 +124,26 @@ func TestState_NormalizeAfterLoad_JSONRoundTrip(t *testing.T) {
 	}
 }
 
+func TestState_RecordTurnTiming(t *testing.T) {
+	t.Parallel()
+
+	started := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	state := &State{TurnID: "turn-1", TurnStartedAt: &started}
+
+	state.RecordTurnTiming(started.Add(2*time.Minute), 30*time.Second, 45*time.Second)
+	require.Len(t, state.TurnTimings, 1)
+	turn := state.TurnTimings[0]
+	assert.Equal(t, "turn-1", turn.TurnID)
+	assert.Equal(t, 2*time.Minute, turn.Duration())
+	assert.Equal(t, 30*time.Second, turn.ToolTime())
+	assert.Equal(t, 45*time.Second, turn.ModelTime())
+	assert.Nil(t, state.TurnStartedAt)
+
+	// A second stop for the same turn is not recorded again
+	state.RecordTurnTiming(started.Add(3*time.Minute), 0, 0)
+	assert.Len(t, state.TurnTimings, 1)
+}
+
 func TestState_IsStale(t *testing.T) {
 	t.Parallel()
 
@@ -158,6 +178,38 @@ func TestState_IsStale(t *testing.T) {
 	})
 }
 
+func TestState_IsSilent(t *testing.T) {
+	t.Parallel()
+	now := time.Now()
+	old := now.Add(-2 * HeartbeatTimeout)
+	recent := now.Add(-time.Minute)
+
+	t.Run("active_without_recent_heartbeat_is_silent", func(t *testing.T) {
+		t.Parallel()
+		state := &State{Phase: PhaseActive, LastInteractionTime: &old}
+		assert.True(t, state.IsSilent(now))
+	})
+
+	t.Run("recent_heartbeat_is_not_silent", func(t *testing.T) {
+		t.Parallel()
+		state := &State{Phase: PhaseActive, LastInteractionTime: &old, HeartbeatAt: &recent}
+		assert.False(t, state.IsSilent(now))
+	})
+
+	t.Run("idle_session_is_not_silent", func(t *testing.T) {
+		t.Parallel()
+		// Idle sessions wait for the user; silence is expected
+		state := &State{Phase: PhaseIdle, LastInteractionTime: &old}
+		assert.False(t, state.IsSilent(now))
+	})
+
+	t.Run("no_recorded_activity_is_not_silent", func(t *testing.T) {
+		t.Parallel()
+		state := &State{Phase: PhaseActive}
+		assert.False(t, state.IsSilent(now))
+	})
+}
+
 func TestStateStore_Load_DeletesStaleSession(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,564 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/entireio/cli/cmd/entire/cli/agent"
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/logging"
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+	"github.com/entireio/cli/cmd/entire/cli/settings"
+	"github.com/entireio/cli/cmd/entire/cli/strategy"
+	"github.com/entireio/cli/cmd/entire/cli/stringutil"
+	"github.com/entireio/cli/cmd/entire/cli/versioninfo"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	// sessionMemoryTimeout bounds sending the memory record to each destination.
+	sessionMemoryTimeout = 30 * time.Second
+
+	// sessionMemoryCheckpoints caps how many of the session's checkpoints
+	// are read for summaries.
+	sessionMemoryCheckpoints = 20
+
+	// mcpExitGrace is how long an MCP server gets to exit once its stdin is closed.
+	mcpExitGrace = 2 * time.Second
+
+	// memoryExcerptLength caps the text the excerpt template function returns.
+	memoryExcerptLength = 200
+)
+
+// defaultSessionMemoryTemplate renders the memory record when the
+// session_memory setting has no template.
+const defaultSessionMemoryTemplate = `## {{.Agent}} session {{.SessionID}} ({{.EndedAt.Format "2006-01-02"}})
+{{with .Intent}}
+Intent: {{.}}{{end}}{{if .Outcome}}
+Outcome: {{.Outcome}}{{else if .Summary}}
+Outcome: {{excerpt .Summary}}{{end}}
+{{range .Prompts}}
+- Asked: {{excerpt .}}{{end}}{{range .Learnings}}
+- Learned: {{.}}{{end}}{{range .Friction}}
+- Friction: {{.}}{{end}}{{range .OpenItems}}
+- Open: {{.}}{{end}}{{with .Files}}
+- Files: {{join . ", "}}{{end}}`
+
+// sessionMemoryData is the data passed to the session_memory template.
+// Intent, Outcome, Learnings, Friction, and OpenItems come from the AI
+// summaries of the session's checkpoints and are empty without them.
+type sessionMemoryData struct {
+	SessionID   string    `json:"session_id"`
+	Agent       string    `json:"agent"`
+	Model       string    `json:"model,omitempty"`
+	Branch      string    `json:"branch,omitempty"`
+	StartedAt   time.Time `json:"started_at"`
+	EndedAt     time.Time `json:"ended_at"`
+	Prompts     []string  `json:"prompts,omitempty"`     // User prompts from the transcript
+	Summary     string    `json:"summary,omitempty"`     // The agent's final message
+	Files       []string  `json:"files,omitempty"`       // Files the session touched
+	Checkpoints []string  `json:"checkpoints,omitempty"` // Committed checkpoint IDs, oldest first
+	Intent      string    `json:"intent,omitempty"`
+	Outcome     string    `json:"outcome,omitempty"`
+	Learnings   []string  `json:"learnings,omitempty"`
+	Friction    []string  `json:"friction,omitempty"`
+	OpenItems   []string  `json:"open_items,omitempty"`
+}
+
+// sessionMemoryRecord is a rendered memory record with its data, as POSTed
+// to session_memory.url and passed to the MCP arguments template.
+type sessionMemoryRecord struct {
+	sessionMemoryData
+
+	Record string `json:"record"`
+}
+
+// sampleSessionMemoryData is used by `entire session-memory` to preview templates.
+var sampleSessionMemoryData = sessionMemoryData{
+	SessionID:   "2026-01-01-example-session",
+	Agent:       "Claude Code",
+	Model:       "claude-sonnet-4-5",
+	Branch:      "feature/login",
+	StartedAt:   time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC),
+	EndedAt:     time.Date(2026, 1, 1, 10, 30, 0, 0, time.UTC),
+	Prompts:     []string{"Fix the login redirect", "Add a test for expired sessions"},
+	Summary:     "The redirect now keeps the return URL, and expired sessions are covered by a test.",
+	Files:       []string{"auth/login.go", "auth/login_test.go"},
+	Checkpoints: []string{"a1b2c3d4e5f6"},
+	Intent:      "Fix the login redirect",
+	Outcome:     "Redirect keeps the return URL",
+	Learnings:   []string{"Sessions are validated in middleware, not in handlers"},
+	OpenItems:   []string{"Remember-me cookies are not covered"},
+}
+
+func newSessionMemoryCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "session-memory",
+		Short: "Preview the memory record sent when a session ends",
+		Long: `Session-memory renders the configured session memory record with sample data
+so you can check it before a session ends.
+
+When a session ends, Entire distills it into a memory record and sends it to
+the destinations set in .entire/settings.local.json, so later sessions can
+build on what earlier ones decided and learned:
+
+  "session_memory": {
+    "file": "docs/agent-memory.md",
+    "url": "https://memory.example.com/records",
+    "headers": {"Authorization": "Bearer ${MEMORY_TOKEN}"},
+    "header_env": ["MEMORY_TOKEN"],
+    "mcp": {"command": ["npx", "-y", "@modelcontextprotocol/server-memory"]}
+  }
+
+session_memory in the committed .entire/settings.json is ignored: it would
+let a cloned repository run commands and send your transcripts elsewhere.
+Headers expand only the environment variables listed in header_env.
+
+file appends the record to a file, url receives it and its fields as a JSON
+POST, and mcp calls a tool of an MCP memory server started over stdio
+("create_entities" unless mcp.tool is set). mcp.arguments is a template for
+the tool's JSON arguments, with the record as {{.Record}}; by default the
+session becomes an entity with the record's lines as observations.
+
+template is a Go text/template for the record. Fields:
+
+  {{.SessionID}}  {{.Agent}}  {{.Model}}  {{.Branch}}  {{.StartedAt}}  {{.EndedAt}}
+  {{.Prompts}}  {{.Summary}}  {{.Files}}  {{.Checkpoints}}
+  {{.Intent}}  {{.Outcome}}  {{.Learnings}}  {{.Friction}}  {{.OpenItems}}
+
+Prompts and Summary (the agent's final message) come from the transcript.
+Intent, Outcome, Learnings, Friction, and OpenItems come from the AI
+summaries of the session's checkpoints (strategy_options.summarize) and are
+empty without them. The functions join, excerpt (first line, shortened), and
+json are available.
+
+Sending is best-effort: a destination that fails is logged and doesn't stop
+the others or the session end.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runSessionMemoryPreview(cmd.Context(), cmd.OutOrStdout())
+		},
+	}
+}
+
+func runSessionMemoryPreview(ctx context.Context, w io.Writer) error {
+	s, err := LoadEntireSettings(ctx)
+	if err != nil {
+		return err
+	}
+	if s.SessionMemory == nil {
+		fmt.Fprintln(w, "No session memory configured.")
+		if committed, err := committedSessionMemory(ctx); err == nil && committed {
+			fmt.Fprintf(w, "session_memory in %s is ignored; set it in %s.\n", settings.EntireSettingsFile, settings.EntireSettingsLocalFile)
+		}
+		return nil
+	}
+
+	record, err := renderSessionMemory(s.SessionMemory.Template, sampleSessionMemoryData)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(w, "record:")
+	fmt.Fprintln(w, "  "+strings.ReplaceAll(record, "\n", "\n  "))
+	if s.SessionMemory.MCP != nil {
+		args, err := renderMCPArguments(s.SessionMemory.MCP.Arguments, sessionMemoryRecord{sampleSessionMemoryData, record})
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "mcp %s arguments:\n  %s\n", s.SessionMemory.MCP.GetTool(), args)
+	}
+	return nil
+}
+
+// committedSessionMemory reports whether the committed settings file sets
+// session_memory, which Load ignores.
+func committedSessionMemory(ctx context.Context) (bool, error) {
+	path, err := paths.AbsPath(ctx, settings.EntireSettingsFile)
+	if err != nil {
+		return false, fmt.Errorf("failed to resolve settings path: %w", err)
+	}
+	s, err := settings.LoadFromFile(path)
+	if err != nil {
+		return false, err //nolint:wrapcheck // already wrapped by settings
+	}
+	return s.SessionMemory != nil, nil
+}
+
+var sessionMemoryFuncs = template.FuncMap{
+	"join": strings.Join,
+	"excerpt": func(s string) string {
+		line, _, _ := strings.Cut(strings.TrimSpace(s), "\n")
+		return stringutil.TruncateRunes(strings.TrimSpace(line), memoryExcerptLength, "...")
+	},
+	"json": func(v any) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+}
+
+// renderSessionMemory renders the memory record with tmpl, or with the
+// default template if tmpl is empty.
+func renderSessionMemory(tmpl string, data sessionMemoryData) (string, error) {
+	if tmpl == "" {
+		tmpl = defaultSessionMemoryTemplate
+	}
+	t, err := template.New("session_memory").Funcs(sessionMemoryFuncs).Option("missingkey=error").Parse(tmpl)
+	if err != nil {
+		return "", fmt.Errorf("invalid session_memory template: %w", err)
+	}
+	var sb strings.Builder
+	if err := t.Execute(&sb, data); err != nil {
+		return "", fmt.Errorf("failed to render session_memory template: %w", err)
+	}
+	return strings.TrimSpace(sb.String()), nil
+}
+
+// renderMCPArguments returns the JSON arguments of the MCP tool call. An
+// empty tmpl creates an entity for the session with the record's lines as
+// observations.
+func renderMCPArguments(tmpl string, record sessionMemoryRecord) (json.RawMessage, error) {
+	if tmpl == "" {
+		var observations []string
+		for _, line := range strings.Split(record.Record, "\n") {
+			line = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "- "))
+			if line != "" {
+				observations = append(observations, line)
+			}
+		}
+		type entity struct {
+			Name         string   `json:"name"`
+			EntityType   string   `json:"entityType"`
+			Observations []string `json:"observations"`
+		}
+		data, err := json.Marshal(map[string][]entity{"entities": {{
+			Name:         "entire-session-" + record.SessionID,
+			EntityType:   "coding_session",
+			Observations: observations,
+		}}})
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode mcp arguments: %w", err)
+		}
+		return data, nil
+	}
+
+	t, err := template.New("session_memory.mcp.arguments").Funcs(sessionMemoryFuncs).Option("missingkey=error").Parse(tmpl)
+	if err != nil {
+		return nil, fmt.Errorf("invalid session_memory mcp arguments template: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, record); err != nil {
+		return nil, fmt.Errorf("failed to render session_memory mcp arguments template: %w", err)
+	}
+	if !json.Valid(buf.Bytes()) {
+		return nil, fmt.Errorf("session_memory mcp arguments template rendered invalid JSON: %s", buf.String())
+	}
+	return buf.Bytes(), nil
+}
+
+// emitSessionMemory sends the memory record of an ended session to the
+// destinations configured in session_memory. Failures are logged and never
+// fail the hook.
+func emitSessionMemory(ctx context.Context, ag agent.Agent, sessionID, sessionRef string) {
+	logCtx := logging.WithComponent(ctx, "session-memory")
+	s, err := settings.Load(ctx)
+	if err != nil || s.SessionMemory == nil {
+		return
+	}
+	state, err := strategy.LoadSessionState(ctx, sessionID)
+	if err != nil || state == nil {
+		return
+	}
+
+	data := collectSessionMemory(ctx, ag, sessionRef, state)
+	record, err := renderSessionMemory(s.SessionMemory.Template, data)
+	if err != nil {
+		logging.Warn(logCtx, "skipping session memory", slog.String("error", err.Error()))
+		return
+	}
+	for _, err := range sendSessionMemory(ctx, s.SessionMemory, sessionMemoryRecord{data, record}) {
+		logging.Warn(logCtx, "failed to send session memory",
+			slog.String("session_id", sessionID),
+			slog.String("error", err.Error()))
+	}
+}
+
+// collectSessionMemory gathers the memory record data of a session from its
+// state, its transcript, and the summaries of its committed checkpoints.
+func collectSessionMemory(ctx context.Context, ag agent.Agent, sessionRef string, state *strategy.SessionState) sessionMemoryData {
+	data := sessionMemoryData{
+		SessionID: state.SessionID,
+		Agent:     string(state.AgentType),
+		Model:     state.Model,
+		StartedAt: state.StartedAt,
+		EndedAt:   time.Now(),
+		Files:     slices.Clone(state.FilesTouched),
+	}
+	if state.EndedAt != nil {
+		data.EndedAt = *state.EndedAt
+	}
+	if sessionRef == "" {
+		sessionRef = state.TranscriptPath
+	}
+	if analyzer, ok := ag.(agent.TranscriptAnalyzer); ok && sessionRef != "" {
+		if prompts, err := analyzer.ExtractPrompts(sessionRef, 0); err == nil {
+			data.Prompts = prompts
+		}
+		if summary, err := analyzer.ExtractSummary(sessionRef); err == nil {
+			data.Summary = summary
+		}
+	}
+
+	repo, err := openRepository(ctx)
+	if err != nil {
+		return data
+	}
+	data.Branch = strategy.GetCurrentBranchName(repo)
+	store, err := strategy.CommittedStore(ctx, checkpoint.NewGitStore(repo))
+	if err != nil {
+		return data
+	}
+	page, err := store.ListCommittedPage(ctx, checkpoint.ListOptions{SessionID: state.SessionID, Limit: sessionMemoryCheckpoints})
+	if err != nil {
+		return data
+	}
+	// Oldest first, so the latest summary's intent and outcome win
+	for _, info := range slices.Backward(page.Checkpoints) {
+		data.Checkpoints = append(data.Checkpoints, info.CheckpointID.String())
+		data.Files = append(data.Files, info.FilesTouched...)
+		content, err := store.ReadSessionContentByID(ctx, info.CheckpointID, state.SessionID)
+		if err != nil || content.Metadata.Summary == nil {
+			continue
+		}
+		summary := content.Metadata.Summary
+		if summary.Intent != "" {
+			data.Intent = summary.Intent
+		}
+		if summary.Outcome != "" {
+			data.Outcome = summary.Outcome
+		}
+		data.Learnings = append(data.Learnings, summary.Learnings.Repo...)
+		for _, l := range summary.Learnings.Code {
+			data.Learnings = append(data.Learnings, l.Path+": "+l.Finding)
+		}
+		data.Learnings = append(data.Learnings, summary.Learnings.Workflow...)
+		data.Friction = append(data.Friction, summary.Friction...)
+		data.OpenItems = append(data.OpenItems, summary.OpenItems...)
+	}
+	slices.Sort(data.Files)
+	data.Files = slices.Compact(data.Files)
+	return data
+}
+
+// sendSessionMemory sends record to every configured destination and
+// returns the errors of those that failed.
+func sendSessionMemory(ctx context.Context, cfg *settings.SessionMemorySettings, record sessionMemoryRecord) []error {
+	var errs []error
+	if cfg.File != "" {
+		if err := appendSessionMemoryFile(ctx, cfg.File, record.Record); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if cfg.URL != "" {
+		if err := postSessionMemory(ctx, cfg, record); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if cfg.MCP != nil {
+		args, err := renderMCPArguments(cfg.MCP.Arguments, record)
+		if err == nil {
+			err = callMCPTool(ctx, cfg.MCP.Command, cfg.MCP.GetTool(), args)
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// appendSessionMemoryFile appends record to path, relative to the
+// repository root unless absolute.
+func appendSessionMemoryFile(ctx context.Context, path, record string) error {
+	if !filepath.IsAbs(path) {
+		root, err := paths.WorktreeRoot(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get worktree root: %w", err)
+		}
+		path = filepath.Join(root, path)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", path, err)
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644) //nolint:gosec // path comes from settings.local.json
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	if _, err := f.WriteString(record + "\n\n"); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// postSessionMemory POSTs record as JSON to the configured URL.
+func postSessionMemory(ctx context.Context, cfg *settings.SessionMemorySettings, record sessionMemoryRecord) error {
+	url := cfg.URL
+	body, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to encode session memory: %w", err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, sessionMemoryTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "entire-cli/"+versioninfo.Version)
+	for name, value := range cfg.Headers {
+		expanded, err := expandHeader(value, cfg.HeaderEnv)
+		if err != nil {
+			return fmt.Errorf("session_memory header %s: %w", name, err)
+		}
+		req.Header.Set(name, expanded)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send session memory to %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10)) //nolint:errcheck // draining for connection reuse
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("session memory endpoint %s returned %s", url, resp.Status)
+	}
+	return nil
+}
+
+// expandHeader expands $VAR and ${VAR} in a header value from the
+// environment. Only the variables in allowed may be referenced, so a header
+// can't pick up secrets it wasn't meant to send.
+func expandHeader(value string, allowed []string) (string, error) {
+	var denied string
+	expanded := os.Expand(value, func(name string) string {
+		if !slices.Contains(allowed, name) {
+			if denied == "" {
+				denied = name
+			}
+			return ""
+		}
+		return os.Getenv(name)
+	})
+	if denied != "" {
+		return "", fmt.Errorf("$%s is not listed in header_env", denied)
+	}
+	return expanded, nil
+}
+
+// mcpMessage is a JSON-RPC 2.0 message of the MCP stdio transport.
+type mcpMessage struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      *int            `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  any             `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+// callMCPTool starts the MCP server command over stdio, initializes it, and
+// calls tool with arguments.
+func callMCPTool(ctx context.Context, command []string, tool string, arguments json.RawMessage) error {
+	ctx, cancel := context.WithTimeout(ctx, sessionMemoryTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, command[0], command[1:]...) //nolint:gosec // command comes from settings.local.json
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return fmt.Errorf("failed to start mcp server: %w", err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("failed to start mcp server: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start mcp server %s: %w", command[0], err)
+	}
+	defer func() {
+		// Servers exit when stdin closes; stop those that don't
+		_ = stdin.Close()
+		timer := time.AfterFunc(mcpExitGrace, cancel)
+		defer timer.Stop()
+		_ = cmd.Wait() //nolint:errcheck // the result of the call is already known
+	}()
+
+	enc := json.NewEncoder(stdin)
+	dec := json.NewDecoder(stdout)
+	request := func(id int, method string, params any) (json.RawMessage, error) {
+		if err := enc.Encode(mcpMessage{JSONRPC: "2.0", ID: &id, Method: method, Params: params}); err != nil {
+			return nil, fmt.Errorf("failed to send mcp %s: %w", method, err)
+		}
+		for {
+			var msg mcpMessage
+			if err := dec.Decode(&msg); err != nil {
+				return nil, fmt.Errorf("failed to read mcp %s response: %w", method, err)
+			}
+			if msg.ID == nil || *msg.ID != id || msg.Method != "" {
+				continue // A notification or a request from the server
+			}
+			if msg.Error != nil {
+				return nil, fmt.Errorf("mcp %s failed: %s", method, msg.Error.Message)
+			}
+			return msg.Result, nil
+		}
+	}
+
+	_, err = request(1, "initialize", map[string]any{
+		"protocolVersion": "2025-06-18",
+		"capabilities":    map[string]any{},
+		"clientInfo":      map[string]string{"name": "entire", "version": versioninfo.Version},
+	})
+	if err != nil {
+		return err
+	}
+	if err := enc.Encode(mcpMessage{JSONRPC: "2.0", Method: "notifications/initialized"}); err != nil {
+		return fmt.Errorf("failed to send mcp initialized notification: %w", err)
+	}
+	result, err := request(2, "tools/call", map[string]any{"name": tool, "arguments": arguments})
+	if err != nil {
+		return err
+	}
+	var call struct {
+		IsError bool `json:"isError"`
+		Content []struct {
+			Text string `json:"text"`
+		} `json:"content"`
+	}
+	if err := json.Unmarshal(result, &call); err != nil {
+		return fmt.Errorf("failed to parse mcp %s result: %w", tool, err)
+	}
+	if call.IsError {
+		var texts []string
+		for _, c := range call.Content {
+			texts = append(texts, c.Text)
+		}
+		return errors.New("mcp tool " + tool + " failed: " + strings.Join(texts, " "))
+	}
+	return nil
+}
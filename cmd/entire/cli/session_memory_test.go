@@ -0,0 +1,190 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/entireio/cli/cmd/entire/cli/settings"
+)
+
+func TestRenderSessionMemory(t *testing.T) {
+	t.Parallel()
+
+	got, err := renderSessionMemory("", sampleSessionMemoryData)
+	if err != nil {
+		t.Fatalf("renderSessionMemory() error = %v", err)
+	}
+	for _, want := range []string{
+		"## Claude Code session 2026-01-01-example-session (2026-01-01)",
+		"Outcome: Redirect keeps the return URL",
+		"- Asked: Add a test for expired sessions",
+		"- Learned: Sessions are validated in middleware, not in handlers",
+		"- Open: Remember-me cookies are not covered",
+		"- Files: auth/login.go, auth/login_test.go",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("default record missing %q:\n%s", want, got)
+		}
+	}
+
+	// Without AI summaries, the agent's final message is the outcome
+	data := sampleSessionMemoryData
+	data.Outcome = ""
+	if got, err := renderSessionMemory("", data); err != nil || !strings.Contains(got, "Outcome: The redirect now keeps the return URL") {
+		t.Errorf("renderSessionMemory() without summary = %q, %v", got, err)
+	}
+
+	got, err = renderSessionMemory("{{.SessionID}}: {{join .Files \" \"}}", sampleSessionMemoryData)
+	if err != nil || got != "2026-01-01-example-session: auth/login.go auth/login_test.go" {
+		t.Errorf("renderSessionMemory(custom) = %q, %v", got, err)
+	}
+	if _, err := renderSessionMemory("{{.Decisions}}", sampleSessionMemoryData); err == nil {
+		t.Error("renderSessionMemory() with unknown field should fail")
+	}
+}
+
+func TestRenderMCPArguments(t *testing.T) {
+	t.Parallel()
+	record := sessionMemoryRecord{sampleSessionMemoryData, "## Session\n\n- Asked: Fix it\n- Files: a.go"}
+
+	args, err := renderMCPArguments("", record)
+	if err != nil {
+		t.Fatalf("renderMCPArguments() error = %v", err)
+	}
+	want := `{"entities":[{"name":"entire-session-2026-01-01-example-session","entityType":"coding_session","observations":["## Session","Asked: Fix it","Files: a.go"]}]}`
+	if string(args) != want {
+		t.Errorf("renderMCPArguments() = %s, want %s", args, want)
+	}
+
+	args, err = renderMCPArguments(`{"text": {{json .Record}}, "session": {{json .SessionID}}}`, record)
+	if err != nil {
+		t.Fatalf("renderMCPArguments(custom) error = %v", err)
+	}
+	var custom struct{ Text, Session string }
+	if err := json.Unmarshal(args, &custom); err != nil || custom.Text != record.Record || custom.Session != record.SessionID {
+		t.Errorf("renderMCPArguments(custom) = %s, %v", args, err)
+	}
+	if _, err := renderMCPArguments(`{"text": {{.Record}}}`, record); err == nil {
+		t.Error("renderMCPArguments() rendering invalid JSON should fail")
+	}
+}
+
+func TestSendSessionMemory(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake MCP server is a shell script")
+	}
+	dir := t.TempDir()
+	ctx := context.Background()
+	t.Setenv("ENTIRE_TEST_MEMORY_TOKEN", "secret")
+
+	var posted sessionMemoryRecord
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		_ = json.NewDecoder(r.Body).Decode(&posted) //nolint:errcheck // checked through posted
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	callPath := filepath.Join(dir, "call.json")
+	mcpServer := filepath.Join(dir, "mcp-server.sh")
+	script := `#!/bin/sh
+read -r initialize
+echo '{"jsonrpc":"2.0","method":"notifications/message","params":{"level":"info"}}'
+echo '{"jsonrpc":"2.0","id":1,"result":{"protocolVersion":"2025-06-18","capabilities":{},"serverInfo":{"name":"fake"}}}'
+read -r initialized
+read -r call
+printf '%s\n' "$call" > '` + callPath + `'
+echo '{"jsonrpc":"2.0","id":2,"result":{"content":[{"type":"text","text":"ok"}]}}'
+`
+	if err := os.WriteFile(mcpServer, []byte(script), 0o755); err != nil { //nolint:gosec // the fake server must be executable
+		t.Fatal(err)
+	}
+
+	memoryFile := filepath.Join(dir, "memory", "agent-memory.md")
+	cfg := &settings.SessionMemorySettings{
+		File:      memoryFile,
+		URL:       server.URL,
+		Headers:   map[string]string{"Authorization": "Bearer ${ENTIRE_TEST_MEMORY_TOKEN}"},
+		HeaderEnv: []string{"ENTIRE_TEST_MEMORY_TOKEN"},
+		MCP:       &settings.SessionMemoryMCPSettings{Command: []string{mcpServer}},
+	}
+	record := sessionMemoryRecord{sampleSessionMemoryData, "## Session\n- Learned: tests live next to the code"}
+	if errs := sendSessionMemory(ctx, cfg, record); len(errs) > 0 {
+		t.Fatalf("sendSessionMemory() errors = %v", errs)
+	}
+	if errs := sendSessionMemory(ctx, &settings.SessionMemorySettings{File: memoryFile}, record); len(errs) > 0 {
+		t.Fatalf("sendSessionMemory() again errors = %v", errs)
+	}
+
+	data, err := os.ReadFile(memoryFile)
+	if err != nil || strings.Count(string(data), "- Learned: tests live next to the code") != 2 {
+		t.Errorf("memory file = %q, %v; want the record appended twice", data, err)
+	}
+	if posted.Record != record.Record || posted.SessionID != record.SessionID || auth != "Bearer secret" {
+		t.Errorf("posted %+v with Authorization %q", posted, auth)
+	}
+	call, err := os.ReadFile(callPath)
+	if err != nil || !strings.Contains(string(call), `"name":"create_entities"`) || !strings.Contains(string(call), `"Learned: tests live next to the code"`) {
+		t.Errorf("mcp tool call = %s, %v", call, err)
+	}
+
+	// Headers may only expand the variables listed in header_env
+	cfg = &settings.SessionMemorySettings{URL: server.URL, Headers: map[string]string{"Authorization": "Bearer $ENTIRE_TEST_MEMORY_TOKEN"}}
+	if errs := sendSessionMemory(ctx, cfg, record); len(errs) != 1 || !strings.Contains(errs[0].Error(), "header_env") {
+		t.Errorf("sendSessionMemory() with an unlisted variable errors = %v, want a header_env error", errs)
+	}
+
+	cfg = &settings.SessionMemorySettings{URL: server.URL + "/missing", MCP: &settings.SessionMemoryMCPSettings{Command: []string{filepath.Join(dir, "no-such-server")}}}
+	server.Config.Handler = http.NotFoundHandler()
+	if errs := sendSessionMemory(ctx, cfg, record); len(errs) != 2 {
+		t.Errorf("sendSessionMemory() to failing destinations errors = %v, want 2", errs)
+	}
+}
+
+func TestRunSessionMemoryPreview(t *testing.T) {
+	setupCleanTestRepo(t)
+
+	var stdout strings.Builder
+	if err := runSessionMemoryPreview(context.Background(), &stdout); err != nil {
+		t.Fatalf("runSessionMemoryPreview() error = %v", err)
+	}
+	if !strings.Contains(stdout.String(), "No session memory configured") {
+		t.Errorf("unexpected output without settings:\n%s", stdout.String())
+	}
+
+	if err := os.MkdirAll(".entire", 0o755); err != nil {
+		t.Fatalf("failed to create .entire: %v", err)
+	}
+	settingsJSON := `{"enabled": true, "session_memory": {"template": "{{.Intent}}", "mcp": {"command": ["memory-server"]}}}`
+	if err := os.WriteFile(filepath.Join(".entire", "settings.json"), []byte(settingsJSON), 0o644); err != nil {
+		t.Fatalf("failed to write settings: %v", err)
+	}
+
+	// Committed settings can't configure session memory
+	stdout.Reset()
+	if err := runSessionMemoryPreview(context.Background(), &stdout); err != nil {
+		t.Fatalf("runSessionMemoryPreview() error = %v", err)
+	}
+	if !strings.Contains(stdout.String(), "No session memory configured") || !strings.Contains(stdout.String(), "is ignored") {
+		t.Errorf("expected committed session_memory to be ignored, got:\n%s", stdout.String())
+	}
+
+	if err := os.Rename(filepath.Join(".entire", "settings.json"), filepath.Join(".entire", "settings.local.json")); err != nil {
+		t.Fatal(err)
+	}
+	stdout.Reset()
+	if err := runSessionMemoryPreview(context.Background(), &stdout); err != nil {
+		t.Fatalf("runSessionMemoryPreview() error = %v", err)
+	}
+	if !strings.Contains(stdout.String(), "  Fix the login redirect") || !strings.Contains(stdout.String(), "mcp create_entities arguments:") {
+		t.Errorf("expected rendered preview, got:\n%s", stdout.String())
+	}
+}
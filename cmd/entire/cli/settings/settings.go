@@ -7,11 +7,16 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
+	"github.com/entireio/cli/cmd/entire/cli/age"
+	"github.com/entireio/cli/cmd/entire/cli/i18n"
 	"github.com/entireio/cli/cmd/entire/cli/jsonutil"
+	"github.com/entireio/cli/cmd/entire/cli/objstore"
 	"github.com/entireio/cli/cmd/entire/cli/paths"
 )
 
@@ -58,11 +63,485 @@ type EntireSettings struct {
 	// Defaults to "prompt" (preserves existing user behavior).
 	CommitLinking string `json:"commit_linking,omitempty"`
 
+	// Share configures where `entire share` uploads checkpoint bundles.
+	// Credentials are never read from settings; see ShareSettings.
+	Share *ShareSettings `json:"share,omitempty"`
+
+	// LinkedRepos lists other repositories (paths relative to this repository's
+	// root, or absolute) whose sessions may share correlation IDs with this one.
+	// Used by `entire linked` to find counterpart sessions.
+	LinkedRepos []string `json:"linked_repos,omitempty"`
+
+	// CommitMessageTemplate formats the commit message of each checkpoint on the
+	// shadow branch as a Go text/template. Available fields: {{.Type}} (feat, fix,
+	// refactor, test, docs), {{.Message}} (derived from the prompt), {{.Turn}}
+	// (the session's turn number), {{.Prompt}} (an excerpt of the prompt), and
+	// {{.Files}} (files changed). Empty uses the message as is.
+	CommitMessageTemplate string `json:"commit_message_template,omitempty"`
+
+	// HookResponse configures messages returned to the agent through the hook
+	// response channel, e.g. after each checkpoint.
+	HookResponse *HookResponseSettings `json:"hook_response,omitempty"`
+
+	// SessionMemory sends a memory record of each session to an agent memory
+	// system when the session ends, so later sessions can build on it. Nil
+	// sends nothing.
+	SessionMemory *SessionMemorySettings `json:"session_memory,omitempty"`
+
+	// PromptGuard checks the repository before each prompt and warns or blocks
+	// the agent turn when it is in a risky state. Nil disables the guard.
+	PromptGuard *PromptGuardSettings `json:"prompt_guard,omitempty"`
+
+	// EscapeDetection flags checkpoints whose transcripts suggest the agent
+	// acted outside its sandbox, for review. Nil disables it.
+	EscapeDetection *EscapeDetectionSettings `json:"escape_detection,omitempty"`
+
+	// AutoStash snapshots uncommitted human changes into a shadow ref at the
+	// start of each agent turn, so rewinding to before the turn restores them.
+	AutoStash bool `json:"auto_stash,omitempty"`
+
+	// SnapshotAgentConfig stores the agent's instruction and settings files
+	// (CLAUDE.md, .cursorrules, ...) in each checkpoint, so a session can later
+	// be reproduced with the instructions it ran under.
+	SnapshotAgentConfig bool `json:"snapshot_agent_config,omitempty"`
+
+	// CheckpointLink prints the checkpoint ID after each commit that creates
+	// one, with its dashboard URL while `entire serve` runs for the repository.
+	CheckpointLink bool `json:"checkpoint_link,omitempty"`
+
+	// PointRefs also names each rewind point with a refs/entire/points/<n>-<slug>
+	// ref, so plain git and CI can refer to checkpoints.
+	PointRefs bool `json:"point_refs,omitempty"`
+
+	// Matrix lists the agents and models `entire matrix run` runs a task with.
+	Matrix *MatrixSettings `json:"matrix,omitempty"`
+
+	// TranscriptStorage selects whether checkpoints hold a copy of the session
+	// transcript or only a pointer to the agent's transcript file. Nil copies.
+	TranscriptStorage *TranscriptStorageSettings `json:"transcript_storage,omitempty"`
+
+	// ContentLevel controls how much session content checkpoints store:
+	// "full" (default), "prompts" (no transcript), or "metadata" (no
+	// transcript, prompts, or context). Code changes are kept at every level.
+	ContentLevel string `json:"content_level,omitempty"`
+
+	// Sync configures how `entire sync` exchanges the metadata branch with remotes.
+	Sync *SyncSettings `json:"sync,omitempty"`
+
+	// Review configures the approvals `entire review` requires before a
+	// checkpoint counts as approved.
+	Review *ReviewSettings `json:"review,omitempty"`
+
+	// GC is the retention policy `entire gc` prunes committed checkpoints
+	// with. Nil keeps all checkpoints.
+	GC *GCSettings `json:"gc,omitempty"`
+
+	// StorageQuota caps the disk space Entire's checkpoints may use in the
+	// repository. Nil doesn't limit it.
+	StorageQuota *StorageQuotaSettings `json:"storage_quota,omitempty"`
+
+	// Encryption age-encrypts the transcript, prompts, and context of new
+	// checkpoints before they are committed. Nil stores them in plaintext.
+	Encryption *EncryptionSettings `json:"encryption,omitempty"`
+
+	// CheckpointStore keeps committed checkpoints in an object store bucket
+	// instead of the entire/checkpoints/v1 branch. Nil uses the branch.
+	CheckpointStore *CheckpointStoreSettings `json:"checkpoint_store,omitempty"`
+
+	// Theme selects the color palette: "default", or "high-contrast" for a
+	// palette that doesn't rely on telling red from green.
+	Theme string `json:"theme,omitempty"`
+
+	// Aliases maps alias names to the command line they run, as in git:
+	// "rw": "rewind --list" makes `entire rw` run `entire rewind --list`, and
+	// values starting with "!" run in the shell. Local settings add to and
+	// override the project's aliases; an empty value removes one. Shell
+	// aliases are read only from local settings (see dropLocalOnly).
+	Aliases map[string]string `json:"alias,omitempty"`
+
+	// Locale selects the language of messages: "en", "ja", or "zh". Empty
+	// detects it from LC_ALL, LC_MESSAGES, or LANG.
+	Locale string `json:"locale,omitempty"`
+
 	// Deprecated: no longer used. Exists to tolerate old settings files
 	// that still contain "strategy": "auto-commit" or similar.
 	Strategy string `json:"strategy,omitempty"`
 }
 
+// Share backend constants.
+const (
+	// ShareBackendEntire uploads bundles to the entire.io share service.
+	ShareBackendEntire = "entire"
+	// ShareBackendS3 uploads bundles to an S3 bucket and links to a presigned URL.
+	ShareBackendS3 = "s3"
+)
+
+// ShareSettings configures the share backend. Credentials come from the
+// environment (ENTIRE_SHARE_TOKEN for entire.io, the standard AWS_* variables
+// for S3) so they never end up in a committed settings file.
+type ShareSettings struct {
+	// Backend is "entire" (default) or "s3".
+	Backend string `json:"backend,omitempty"`
+
+	// Endpoint overrides the service URL (entire) or the S3 endpoint for
+	// S3-compatible stores. S3 endpoints use path-style addressing.
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// Bucket, Region, and Prefix locate uploaded bundles (s3 only).
+	Bucket string `json:"bucket,omitempty"`
+	Region string `json:"region,omitempty"`
+	Prefix string `json:"prefix,omitempty"`
+
+	// Expiry is the default link lifetime as a Go duration (e.g. "24h").
+	Expiry string `json:"expiry,omitempty"`
+}
+
+// HookResponseSettings holds Go text/templates for messages sent back to the
+// agent. An empty template sends nothing.
+type HookResponseSettings struct {
+	// Checkpoint is rendered after each checkpoint is saved at the end of a
+	// turn. See `entire hook-response` for the available fields.
+	Checkpoint string `json:"checkpoint,omitempty"`
+}
+
+// SessionMemorySettings configures the memory record sent when a session
+// ends. The record goes to every destination that is set. It is read only
+// from settings.local.json (see dropLocalOnly).
+type SessionMemorySettings struct {
+	// Template is a Go text/template that renders the record from the
+	// session's transcript and checkpoint summaries. Empty uses a Markdown
+	// record. See `entire session-memory` for the available fields.
+	Template string `json:"template,omitempty"`
+
+	// File appends the record to this file. Relative paths are relative to
+	// the repository root.
+	File string `json:"file,omitempty"`
+
+	// URL receives the record and its fields as a JSON POST.
+	URL string `json:"url,omitempty"`
+
+	// Headers are sent with the POST to URL. $VAR and ${VAR} are expanded
+	// from the environment for the variables listed in HeaderEnv, so tokens
+	// needn't be in the settings file.
+	Headers   map[string]string `json:"headers,omitempty"`
+	HeaderEnv []string          `json:"header_env,omitempty"`
+
+	// MCP calls a tool of an MCP memory server with the record.
+	MCP *SessionMemoryMCPSettings `json:"mcp,omitempty"`
+}
+
+// SessionMemoryMCPSettings names an MCP server started over stdio and the
+// tool that stores the memory record.
+type SessionMemoryMCPSettings struct {
+	// Command starts the server, e.g. ["npx", "-y", "@modelcontextprotocol/server-memory"].
+	Command []string `json:"command"`
+
+	// Tool is the tool to call. Empty calls "create_entities".
+	Tool string `json:"tool,omitempty"`
+
+	// Arguments is a Go text/template rendering the tool's JSON arguments,
+	// with the record as {{.Record}}. Empty creates an entity for the
+	// session with the record's lines as observations, which is what the
+	// reference memory server's create_entities expects.
+	Arguments string `json:"arguments,omitempty"`
+}
+
+// GetTool returns the MCP tool to call.
+func (m *SessionMemoryMCPSettings) GetTool() string {
+	if m.Tool == "" {
+		return "create_entities"
+	}
+	return m.Tool
+}
+
+// Prompt guard actions.
+const (
+	PromptGuardActionWarn  = "warn"
+	PromptGuardActionBlock = "block"
+)
+
+// PromptGuardSettings selects which preconditions the prompt guard checks.
+// Each check is off unless configured.
+type PromptGuardSettings struct {
+	// Action is "warn" (default) to show a message and continue, or "block"
+	// to stop the turn until the state is resolved.
+	Action string `json:"action,omitempty"`
+
+	// MergeInProgress flags a merge, rebase, cherry-pick, or revert in progress.
+	MergeInProgress bool `json:"merge_in_progress,omitempty"`
+
+	// MaxUncommittedLines flags more added plus removed lines than this in
+	// uncommitted changes. 0 disables the check.
+	MaxUncommittedLines int `json:"max_uncommitted_lines,omitempty"`
+
+	// ProtectedBranches flags prompts on branches matching these globs (e.g. "main", "release/*").
+	ProtectedBranches []string `json:"protected_branches,omitempty"`
+}
+
+// GetAction returns the effective action, defaulting to warn.
+func (p *PromptGuardSettings) GetAction() string {
+	if p.Action == PromptGuardActionBlock {
+		return PromptGuardActionBlock
+	}
+	return PromptGuardActionWarn
+}
+
+// EscapeDetectionSettings selects the heuristics that flag a checkpoint for
+// review. Each heuristic is off unless configured.
+type EscapeDetectionSettings struct {
+	// OutsideWrites flags writes to files outside the repository root.
+	OutsideWrites bool `json:"outside_writes,omitempty"`
+
+	// Sudo flags shell commands run with sudo.
+	Sudo bool `json:"sudo,omitempty"`
+
+	// Network flags web fetches and shell commands (curl, scp, git, ...)
+	// reaching hosts other than AllowedHosts, their subdomains, and loopback.
+	Network      bool     `json:"network,omitempty"`
+	AllowedHosts []string `json:"allowed_hosts,omitempty"`
+
+	// Webhook receives a JSON POST with the flags of each flagged checkpoint.
+	// Read only from settings.local.json (see dropLocalOnly).
+	Webhook string `json:"webhook,omitempty"`
+}
+
+// MatrixSettings configures `entire matrix run`.
+type MatrixSettings struct {
+	// Agents each run the task in their own workspace, in parallel.
+	Agents []MatrixAgent `json:"agents"`
+
+	// TestCommand runs in the shell in each workspace after its agent
+	// finishes; its exit status is reported as the test result.
+	TestCommand string `json:"test_command,omitempty"`
+}
+
+// MatrixAgent is an agent and model to run a matrix task with.
+type MatrixAgent struct {
+	// Name identifies the entry; its workspace is named <run>-<name>.
+	Name string `json:"name"`
+
+	// Command runs in the shell in the workspace with the task on stdin,
+	// e.g. "claude -p --model opus".
+	Command string `json:"command"`
+}
+
+// Transcript storage modes.
+const (
+	// TranscriptStorageCopy stores the redacted transcript in each checkpoint.
+	TranscriptStorageCopy = "copy"
+	// TranscriptStoragePointer stores only a content hash and the transcript's
+	// location; readers resolve the transcript through the pointer.
+	TranscriptStoragePointer = "pointer"
+)
+
+// TranscriptStorageSettings configures where checkpoint transcripts live.
+type TranscriptStorageSettings struct {
+	// Mode is "copy" (default) or "pointer".
+	Mode string `json:"mode,omitempty"`
+
+	// BlobURL, in pointer mode, is a base URL the redacted transcript is PUT to
+	// as <blob_url>/<sha256>, so it can still be read once the agent's
+	// transcript file is gone. Empty keeps transcripts on this machine only.
+	BlobURL string `json:"blob_url,omitempty"`
+}
+
+// IsPointer reports whether checkpoints store transcript pointers instead of copies.
+func (t *TranscriptStorageSettings) IsPointer() bool {
+	return t != nil && t.Mode == TranscriptStoragePointer
+}
+
+// Sync prune modes.
+const (
+	// SyncPrunesKeep merges rewritten remote history, keeping checkpoints the
+	// remote no longer has.
+	SyncPrunesKeep = "keep"
+	// SyncPrunesAdopt drops checkpoints the remote pruned from the local
+	// branch too, archiving the previous local branch first.
+	SyncPrunesAdopt = "adopt"
+)
+
+// DefaultMetadataRemote is the remote the metadata branch is exchanged with
+// unless sync.remote names another one.
+const DefaultMetadataRemote = "origin"
+
+// SyncSettings configures how the metadata branch is exchanged with remotes.
+type SyncSettings struct {
+	// Prunes is "keep" (default) or "adopt"; see SyncPrunesKeep and SyncPrunesAdopt.
+	Prunes string `json:"prunes,omitempty"`
+
+	// Remote is the git remote the metadata branch is pushed to and fetched
+	// from, e.g. a dedicated "entire-remote" on an internal server. When set,
+	// session data goes only there, whichever remote code is pushed to.
+	// Empty uses the remote being pushed to, and origin for fetches.
+	Remote string `json:"remote,omitempty"`
+}
+
+// AdoptsPrunes reports whether sync drops checkpoints the remote pruned.
+func (s *SyncSettings) AdoptsPrunes() bool {
+	return s != nil && s.Prunes == SyncPrunesAdopt
+}
+
+// GetMetadataRemote returns the remote the metadata branch is fetched from:
+// sync.remote if set, otherwise origin.
+func (s *EntireSettings) GetMetadataRemote() string {
+	if s.Sync != nil && s.Sync.Remote != "" {
+		return s.Sync.Remote
+	}
+	return DefaultMetadataRemote
+}
+
+// ReviewSettings configures checkpoint review.
+type ReviewSettings struct {
+	// RequiredApprovals is how many distinct reviewers must approve a
+	// checkpoint. Zero means approval is optional: one approval is enough.
+	RequiredApprovals int `json:"required_approvals,omitempty"`
+
+	// Handles lists the CODEOWNERS owners that mean you, e.g. "@alice" or
+	// "@org/backend", for `entire review --mine`. Your git email always
+	// counts. Usually set in settings.local.json.
+	Handles []string `json:"handles,omitempty"`
+}
+
+// GetReviewHandles returns review.handles, or nil if unset.
+func (s *EntireSettings) GetReviewHandles() []string {
+	if s.Review == nil {
+		return nil
+	}
+	return s.Review.Handles
+}
+
+// GetRequiredApprovals returns review.required_approvals, or 0 if unset.
+func (s *EntireSettings) GetRequiredApprovals() int {
+	if s.Review == nil {
+		return 0
+	}
+	return s.Review.RequiredApprovals
+}
+
+// GCSettings is the retention policy for committed checkpoints. Zero
+// values don't limit retention.
+type GCSettings struct {
+	// MaxAgeDays prunes checkpoints created more than this many days ago.
+	MaxAgeDays int `json:"max_age_days,omitempty"`
+
+	// MaxPerSession keeps only this many of each session's most recent
+	// checkpoints.
+	MaxPerSession int `json:"max_per_session,omitempty"`
+
+	// Merged also archives and removes the shadow branches of base commits
+	// whose branch was merged or deleted, as `entire gc --merged` does.
+	Merged bool `json:"merged,omitempty"`
+}
+
+// HasRetention reports whether the policy prunes any committed checkpoints.
+func (g GCSettings) HasRetention() bool {
+	return g.MaxAgeDays > 0 || g.MaxPerSession > 0
+}
+
+// GetGC returns the gc retention policy, or an empty policy if unset.
+func (s *EntireSettings) GetGC() GCSettings {
+	if s.GC == nil {
+		return GCSettings{}
+	}
+	return *s.GC
+}
+
+// Storage quota actions.
+const (
+	StorageQuotaActionBlock = "block"
+	StorageQuotaActionGC    = "gc"
+)
+
+// StorageQuotaSettings caps the storage of Entire's refs in the repository.
+type StorageQuotaSettings struct {
+	// MaxMB is the most disk space, in MiB, the objects only Entire's refs
+	// reach may take. 0 disables the quota.
+	MaxMB int `json:"max_mb,omitempty"`
+
+	// Action is "block" (default) to stop saving checkpoints while over the
+	// quota, or "gc" to first prune with the gc retention policy.
+	Action string `json:"action,omitempty"`
+}
+
+// GetAction returns the effective action, defaulting to block.
+func (q *StorageQuotaSettings) GetAction() string {
+	if q.Action == StorageQuotaActionGC {
+		return StorageQuotaActionGC
+	}
+	return StorageQuotaActionBlock
+}
+
+// GetStorageQuotaBytes returns storage_quota.max_mb in bytes, or 0 if unset.
+func (s *EntireSettings) GetStorageQuotaBytes() int64 {
+	if s.StorageQuota == nil {
+		return 0
+	}
+	return int64(s.StorageQuota.MaxMB) << 20
+}
+
+// EncryptionSettings configures encryption of checkpoint content at rest.
+type EncryptionSettings struct {
+	// Recipients are the age public keys ("age1...") checkpoint content is
+	// encrypted to. Everyone who should read checkpoints needs a key here.
+	Recipients []string `json:"recipients,omitempty"`
+}
+
+// GetEncryptionRecipients returns encryption.recipients, or nil if unset.
+func (s *EntireSettings) GetEncryptionRecipients() []string {
+	if s.Encryption == nil {
+		return nil
+	}
+	return s.Encryption.Recipients
+}
+
+// CheckpointStoreSettings selects the bucket committed checkpoints are
+// stored in. Credentials come from the environment, never from settings.
+type CheckpointStoreSettings struct {
+	// URL is s3://bucket/prefix, gs://bucket/prefix, or file:///path.
+	URL string `json:"url"`
+
+	// Region is the S3 region; empty uses $AWS_REGION, then us-east-1.
+	Region string `json:"region,omitempty"`
+
+	// Endpoint is the URL of an S3-compatible service such as MinIO.
+	Endpoint string `json:"endpoint,omitempty"`
+}
+
+// GetCheckpointStore returns checkpoint_store, or nil if checkpoints are
+// stored on the metadata branch.
+func (s *EntireSettings) GetCheckpointStore() *CheckpointStoreSettings {
+	if s.CheckpointStore == nil || s.CheckpointStore.URL == "" {
+		return nil
+	}
+	return s.CheckpointStore
+}
+
+// Content levels.
+const (
+	// ContentLevelFull stores transcripts, prompts, and context.
+	ContentLevelFull = "full"
+	// ContentLevelPrompts stores prompts and context but not the transcript.
+	ContentLevelPrompts = "prompts"
+	// ContentLevelMetadata stores only structural metadata.
+	ContentLevelMetadata = "metadata"
+)
+
+// GetContentLevel returns the effective content level, defaulting to "full".
+func (s *EntireSettings) GetContentLevel() string {
+	if s.ContentLevel != "" {
+		return s.ContentLevel
+	}
+	return ContentLevelFull
+}
+
+// Theme constants.
+const (
+	// ThemeDefault is the standard palette.
+	ThemeDefault = "default"
+	// ThemeHighContrast is the high-contrast, colorblind-friendly palette.
+	ThemeHighContrast = "high-contrast"
+)
+
 // GetCommitLinking returns the effective commit linking mode.
 // Returns the explicit value if set, otherwise defaults to "prompt"
 // to preserve existing user behavior.
@@ -93,6 +572,7 @@ func Load(ctx context.Context) (*EntireSettings, error) {
 	if err != nil {
 		return nil, fmt.Errorf("reading settings file: %w", err)
 	}
+	dropLocalOnly(settings)
 
 	// Apply local overrides if they exist
 	localData, err := os.ReadFile(localSettingsFileAbs) //nolint:gosec // path is from AbsPath or constant
@@ -110,6 +590,23 @@ func Load(ctx context.Context) (*EntireSettings, error) {
 	return settings, nil
 }
 
+// dropLocalOnly clears the settings that the committed settings.json may not
+// set: those that run commands or send session content elsewhere on the
+// user's behalf. A cloned repository could otherwise use them to run code or
+// exfiltrate transcripts, so they are read only from settings.local.json.
+func dropLocalOnly(s *EntireSettings) {
+	s.SessionMemory = nil
+	if s.EscapeDetection != nil {
+		s.EscapeDetection.Webhook = ""
+	}
+	// As in git, which never reads aliases from a repository's shared config
+	for name, value := range s.Aliases {
+		if strings.HasPrefix(value, "!") {
+			delete(s.Aliases, name)
+		}
+	}
+}
+
 // LoadFromFile loads settings from a specific file path without merging local overrides.
 // Returns default settings if the file doesn't exist.
 // Use this when you need to display individual settings files separately.
@@ -120,17 +617,22 @@ func LoadFromFile(filePath string) (*EntireSettings, error) {
 // loadFromFile loads settings from a specific file path.
 // Returns default settings if the file doesn't exist.
 func loadFromFile(filePath string) (*EntireSettings, error) {
-	settings := &EntireSettings{
-		Enabled: true, // Default to enabled
-	}
-
 	data, err := os.ReadFile(filePath) //nolint:gosec // path is from caller
 	if err != nil {
 		if os.IsNotExist(err) {
-			return settings, nil
+			return &EntireSettings{Enabled: true}, nil
 		}
 		return nil, fmt.Errorf("%w", err)
 	}
+	return Parse(data)
+}
+
+// Parse decodes settings file content, rejecting unknown fields.
+// Fields missing from data keep their defaults.
+func Parse(data []byte) (*EntireSettings, error) {
+	settings := &EntireSettings{
+		Enabled: true, // Default to enabled
+	}
 
 	dec := json.NewDecoder(bytes.NewReader(data))
 	dec.DisallowUnknownFields()
@@ -232,6 +734,308 @@ func mergeJSON(settings *EntireSettings, data []byte) error {
 		}
 	}
 
+	// Override commit_message_template if present
+	if tmplRaw, ok := raw["commit_message_template"]; ok {
+		var tmpl string
+		if err := json.Unmarshal(tmplRaw, &tmpl); err != nil {
+			return fmt.Errorf("parsing commit_message_template field: %w", err)
+		}
+		settings.CommitMessageTemplate = tmpl
+	}
+
+	// Override auto_stash if present
+	if stashRaw, ok := raw["auto_stash"]; ok {
+		var autoStash bool
+		if err := json.Unmarshal(stashRaw, &autoStash); err != nil {
+			return fmt.Errorf("parsing auto_stash field: %w", err)
+		}
+		settings.AutoStash = autoStash
+	}
+
+	// Override snapshot_agent_config if present
+	if snapshotRaw, ok := raw["snapshot_agent_config"]; ok {
+		var snapshot bool
+		if err := json.Unmarshal(snapshotRaw, &snapshot); err != nil {
+			return fmt.Errorf("parsing snapshot_agent_config field: %w", err)
+		}
+		settings.SnapshotAgentConfig = snapshot
+	}
+
+	// Override checkpoint_link if present
+	if linkRaw, ok := raw["checkpoint_link"]; ok {
+		var link bool
+		if err := json.Unmarshal(linkRaw, &link); err != nil {
+			return fmt.Errorf("parsing checkpoint_link field: %w", err)
+		}
+		settings.CheckpointLink = link
+	}
+
+	// Override point_refs if present
+	if pointRaw, ok := raw["point_refs"]; ok {
+		var pointRefs bool
+		if err := json.Unmarshal(pointRaw, &pointRefs); err != nil {
+			return fmt.Errorf("parsing point_refs field: %w", err)
+		}
+		settings.PointRefs = pointRefs
+	}
+
+	// Override linked_repos if present
+	if linkedRaw, ok := raw["linked_repos"]; ok {
+		var repos []string
+		if err := json.Unmarshal(linkedRaw, &repos); err != nil {
+			return fmt.Errorf("parsing linked_repos field: %w", err)
+		}
+		settings.LinkedRepos = repos
+	}
+
+	// Override hook_response if present (replaces the whole block)
+	if hookRaw, ok := raw["hook_response"]; ok {
+		var hr HookResponseSettings
+		if err := json.Unmarshal(hookRaw, &hr); err != nil {
+			return fmt.Errorf("parsing hook_response field: %w", err)
+		}
+		settings.HookResponse = &hr
+	}
+
+	// Override session_memory if present (replaces the whole block)
+	if memoryRaw, ok := raw["session_memory"]; ok {
+		var memory SessionMemorySettings
+		if err := json.Unmarshal(memoryRaw, &memory); err != nil {
+			return fmt.Errorf("parsing session_memory field: %w", err)
+		}
+		if memory.URL != "" && !strings.HasPrefix(memory.URL, "http://") && !strings.HasPrefix(memory.URL, "https://") {
+			return fmt.Errorf("invalid session_memory url %q: must be http:// or https://", memory.URL)
+		}
+		if memory.MCP != nil && len(memory.MCP.Command) == 0 {
+			return errors.New("invalid session_memory mcp: command is required")
+		}
+		settings.SessionMemory = &memory
+	}
+
+	// Override prompt_guard if present (replaces the whole block)
+	if guardRaw, ok := raw["prompt_guard"]; ok {
+		var guard PromptGuardSettings
+		if err := json.Unmarshal(guardRaw, &guard); err != nil {
+			return fmt.Errorf("parsing prompt_guard field: %w", err)
+		}
+		switch guard.Action {
+		case "", PromptGuardActionWarn, PromptGuardActionBlock:
+		default:
+			return fmt.Errorf("invalid prompt_guard action %q: must be %q or %q", guard.Action, PromptGuardActionWarn, PromptGuardActionBlock)
+		}
+		settings.PromptGuard = &guard
+	}
+
+	// Override escape_detection if present (replaces the whole block)
+	if escapeRaw, ok := raw["escape_detection"]; ok {
+		var escape EscapeDetectionSettings
+		if err := json.Unmarshal(escapeRaw, &escape); err != nil {
+			return fmt.Errorf("parsing escape_detection field: %w", err)
+		}
+		if escape.Webhook != "" && !strings.HasPrefix(escape.Webhook, "http://") && !strings.HasPrefix(escape.Webhook, "https://") {
+			return fmt.Errorf("invalid escape_detection webhook %q: must be http:// or https://", escape.Webhook)
+		}
+		settings.EscapeDetection = &escape
+	}
+
+	// Override matrix if present (replaces the whole block)
+	if matrixRaw, ok := raw["matrix"]; ok {
+		var matrix MatrixSettings
+		if err := json.Unmarshal(matrixRaw, &matrix); err != nil {
+			return fmt.Errorf("parsing matrix field: %w", err)
+		}
+		names := make(map[string]bool, len(matrix.Agents))
+		for _, agent := range matrix.Agents {
+			if agent.Name == "" || agent.Command == "" {
+				return errors.New("invalid matrix agent: name and command are required")
+			}
+			if names[agent.Name] {
+				return fmt.Errorf("invalid matrix agent: duplicate name %q", agent.Name)
+			}
+			names[agent.Name] = true
+		}
+		settings.Matrix = &matrix
+	}
+
+	// Override transcript_storage if present (replaces the whole block)
+	if storageRaw, ok := raw["transcript_storage"]; ok {
+		var storage TranscriptStorageSettings
+		if err := json.Unmarshal(storageRaw, &storage); err != nil {
+			return fmt.Errorf("parsing transcript_storage field: %w", err)
+		}
+		switch storage.Mode {
+		case "", TranscriptStorageCopy, TranscriptStoragePointer:
+		default:
+			return fmt.Errorf("invalid transcript_storage mode %q: must be %q or %q", storage.Mode, TranscriptStorageCopy, TranscriptStoragePointer)
+		}
+		settings.TranscriptStorage = &storage
+	}
+
+	// Override sync if present (replaces the whole block)
+	if syncRaw, ok := raw["sync"]; ok {
+		var syncSettings SyncSettings
+		if err := json.Unmarshal(syncRaw, &syncSettings); err != nil {
+			return fmt.Errorf("parsing sync field: %w", err)
+		}
+		switch syncSettings.Prunes {
+		case "", SyncPrunesKeep, SyncPrunesAdopt:
+		default:
+			return fmt.Errorf("invalid sync prunes %q: must be %q or %q", syncSettings.Prunes, SyncPrunesKeep, SyncPrunesAdopt)
+		}
+		if syncSettings.Remote != "" && !isValidRemoteName(syncSettings.Remote) {
+			return fmt.Errorf("invalid sync remote %q: must be the name of a git remote", syncSettings.Remote)
+		}
+		settings.Sync = &syncSettings
+	}
+
+	// Override review fields if present. Unlike other blocks, fields are
+	// merged so handles in settings.local.json keep the team's
+	// required_approvals from settings.json.
+	if reviewRaw, ok := raw["review"]; ok {
+		var review ReviewSettings
+		if settings.Review != nil {
+			review = *settings.Review
+		}
+		if err := json.Unmarshal(reviewRaw, &review); err != nil {
+			return fmt.Errorf("parsing review field: %w", err)
+		}
+		if review.RequiredApprovals < 0 {
+			return fmt.Errorf("invalid review required_approvals %d: must not be negative", review.RequiredApprovals)
+		}
+		settings.Review = &review
+	}
+
+	// Override gc if present (replaces the whole block)
+	if gcRaw, ok := raw["gc"]; ok {
+		var gc GCSettings
+		if err := json.Unmarshal(gcRaw, &gc); err != nil {
+			return fmt.Errorf("parsing gc field: %w", err)
+		}
+		if gc.MaxAgeDays < 0 || gc.MaxPerSession < 0 {
+			return fmt.Errorf("invalid gc policy %+v: max_age_days and max_per_session must not be negative", gc)
+		}
+		settings.GC = &gc
+	}
+
+	// Override storage_quota if present (replaces the whole block)
+	if quotaRaw, ok := raw["storage_quota"]; ok {
+		var quota StorageQuotaSettings
+		if err := json.Unmarshal(quotaRaw, &quota); err != nil {
+			return fmt.Errorf("parsing storage_quota field: %w", err)
+		}
+		if quota.MaxMB < 0 {
+			return fmt.Errorf("invalid storage_quota max_mb %d: must not be negative", quota.MaxMB)
+		}
+		switch quota.Action {
+		case "", StorageQuotaActionBlock, StorageQuotaActionGC:
+		default:
+			return fmt.Errorf("invalid storage_quota action %q: must be %q or %q", quota.Action, StorageQuotaActionBlock, StorageQuotaActionGC)
+		}
+		settings.StorageQuota = &quota
+	}
+
+	// Override content_level if present and non-empty
+	if levelRaw, ok := raw["content_level"]; ok {
+		var level string
+		if err := json.Unmarshal(levelRaw, &level); err != nil {
+			return fmt.Errorf("parsing content_level field: %w", err)
+		}
+		if level != "" {
+			switch level {
+			case ContentLevelFull, ContentLevelPrompts, ContentLevelMetadata:
+			default:
+				return fmt.Errorf("invalid content_level %q: must be %q, %q, or %q", level, ContentLevelFull, ContentLevelPrompts, ContentLevelMetadata)
+			}
+			settings.ContentLevel = level
+		}
+	}
+
+	// Override encryption if present (replaces the whole block)
+	if encryptionRaw, ok := raw["encryption"]; ok {
+		var encryption EncryptionSettings
+		if err := json.Unmarshal(encryptionRaw, &encryption); err != nil {
+			return fmt.Errorf("parsing encryption field: %w", err)
+		}
+		for _, recipient := range encryption.Recipients {
+			if _, err := age.ParseRecipient(recipient); err != nil {
+				return fmt.Errorf("invalid encryption recipient: %w", err)
+			}
+		}
+		settings.Encryption = &encryption
+	}
+
+	// Override checkpoint_store if present (replaces the whole block)
+	if storeRaw, ok := raw["checkpoint_store"]; ok {
+		var store CheckpointStoreSettings
+		if err := json.Unmarshal(storeRaw, &store); err != nil {
+			return fmt.Errorf("parsing checkpoint_store field: %w", err)
+		}
+		if store.URL != "" {
+			if err := objstore.ValidateURL(store.URL); err != nil {
+				return fmt.Errorf("invalid checkpoint_store: %w", err)
+			}
+		}
+		settings.CheckpointStore = &store
+	}
+
+	// Override theme if present and non-empty
+	if themeRaw, ok := raw["theme"]; ok {
+		var theme string
+		if err := json.Unmarshal(themeRaw, &theme); err != nil {
+			return fmt.Errorf("parsing theme field: %w", err)
+		}
+		if theme != "" {
+			if theme != ThemeDefault && theme != ThemeHighContrast {
+				return fmt.Errorf("invalid theme %q: must be %q or %q", theme, ThemeDefault, ThemeHighContrast)
+			}
+			settings.Theme = theme
+		}
+	}
+
+	// Merge aliases if present: each alias replaces the same-named one
+	if aliasRaw, ok := raw["alias"]; ok {
+		var aliases map[string]string
+		if err := json.Unmarshal(aliasRaw, &aliases); err != nil {
+			return fmt.Errorf("parsing alias field: %w", err)
+		}
+		for name, value := range aliases {
+			if name == "" || strings.ContainsAny(name, " \t\n") || strings.HasPrefix(name, "-") {
+				return fmt.Errorf("invalid alias name %q: must be a single word not starting with '-'", name)
+			}
+			if value == "" {
+				delete(settings.Aliases, name)
+				continue
+			}
+			if settings.Aliases == nil {
+				settings.Aliases = make(map[string]string)
+			}
+			settings.Aliases[name] = value
+		}
+	}
+
+	// Override locale if present and non-empty
+	if localeRaw, ok := raw["locale"]; ok {
+		var locale string
+		if err := json.Unmarshal(localeRaw, &locale); err != nil {
+			return fmt.Errorf("parsing locale field: %w", err)
+		}
+		if locale != "" {
+			if _, err := i18n.ParseLocale(locale); err != nil {
+				return err //nolint:wrapcheck // already names the locale
+			}
+			settings.Locale = locale
+		}
+	}
+
+	// Override share if present (replaces the whole block)
+	if shareRaw, ok := raw["share"]; ok {
+		var share ShareSettings
+		if err := json.Unmarshal(shareRaw, &share); err != nil {
+			return fmt.Errorf("parsing share field: %w", err)
+		}
+		settings.Share = &share
+	}
+
 	return nil
 }
 
@@ -271,6 +1075,16 @@ func IsSummarizeEnabled(ctx context.Context) bool {
 	return settings.IsSummarizeEnabled()
 }
 
+// IsSnapshotAgentConfigEnabled checks if agent config files should be stored in checkpoints.
+// Returns false by default if settings cannot be loaded.
+func IsSnapshotAgentConfigEnabled(ctx context.Context) bool {
+	settings, err := Load(ctx)
+	if err != nil {
+		return false
+	}
+	return settings.SnapshotAgentConfig
+}
+
 // IsSummarizeEnabled checks if auto-summarize is enabled in this settings instance.
 func (s *EntireSettings) IsSummarizeEnabled() bool {
 	if s.StrategyOptions == nil {
@@ -338,3 +1152,20 @@ func saveToFile(ctx context.Context, settings *EntireSettings, filePath string)
 	}
 	return nil
 }
+
+// isValidRemoteName reports whether name can be a git remote name. Names are
+// passed to git as arguments, so they must not look like options.
+func isValidRemoteName(name string) bool {
+	if name == "" || strings.HasPrefix(name, "-") {
+		return false
+	}
+	for _, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-', r == '_', r == '.', r == '/':
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -11,10 +11,12 @@ import (
 
 	"github.com/entireio/cli/cmd/entire/cli/agent"
 	"github.com/entireio/cli/cmd/entire/cli/agent/types"
+	"github.com/entireio/cli/cmd/entire/cli/i18n"
 	"github.com/entireio/cli/cmd/entire/cli/paths"
 	"github.com/entireio/cli/cmd/entire/cli/session"
 	"github.com/entireio/cli/cmd/entire/cli/settings"
 	"github.com/entireio/cli/cmd/entire/cli/strategy"
+	"github.com/entireio/cli/cmd/entire/cli/telemetry"
 
 	"github.com/charmbracelet/huh"
 	"github.com/spf13/cobra"
@@ -51,7 +53,7 @@ modifying your active branch.`,
 			// to prevent duplicate error output in main.go
 			if _, err := paths.WorktreeRoot(ctx); err != nil {
 				fmt.Fprintln(cmd.ErrOrStderr(), "Not a git repository. Please run 'entire enable' from within a git repository.")
-				return NewSilentError(errors.New("not a git repository"))
+				return NewSilentError(paths.ErrNotGitRepository)
 			}
 
 			if err := validateSetupFlags(useLocalSettings, useProjectSettings); err != nil {
@@ -563,7 +565,7 @@ func printWrongAgentError(w io.Writer, name string) {
 
 // setupAgentHooksNonInteractive sets up hooks for a specific agent non-interactively.
 // If strategyName is provided, it sets the strategy; otherwise uses default.
-func setupAgentHooksNonInteractive(ctx context.Context, w io.Writer, ag agent.Agent, localDev, forceHooks, skipPushSessions, telemetry bool) error {
+func setupAgentHooksNonInteractive(ctx context.Context, w io.Writer, ag agent.Agent, localDev, forceHooks, skipPushSessions, telemetryFlag bool) error {
 	agentName := ag.Name()
 	// Check if agent supports hooks
 	hookAgent, ok := ag.(agent.HookSupport)
@@ -605,7 +607,7 @@ func setupAgentHooksNonInteractive(ctx context.Context, w io.Writer, ag agent.Ag
 
 	// Handle telemetry for non-interactive mode
 	// Note: if telemetry is nil (not configured), it defaults to disabled
-	if !telemetry || os.Getenv("ENTIRE_TELEMETRY_OPTOUT") != "" {
+	if !telemetryFlag || telemetry.Disabled() {
 		f := false
 		settings.Telemetry = &f
 	}
@@ -882,7 +884,7 @@ func promptTelemetryConsent(settings *EntireSettings, telemetryFlag bool) error
 	}
 
 	// Skip if env var disables telemetry (record as disabled)
-	if os.Getenv("ENTIRE_TELEMETRY_OPTOUT") != "" {
+	if telemetry.Disabled() {
 		f := false
 		settings.Telemetry = &f
 		return nil
@@ -891,11 +893,9 @@ func promptTelemetryConsent(settings *EntireSettings, telemetryFlag bool) error
 	consent := true // Default to Yes
 	form := NewAccessibleForm(
 		huh.NewGroup(
-			huh.NewConfirm().
-				Title("Help improve Entire CLI?").
-				Description("Share anonymous usage data. No code or personal info collected.").
-				Affirmative("Yes").
-				Negative("No").
+			newConfirm().
+				Title(i18n.T(i18n.ConfirmTelemetry)).
+				Description(i18n.T(i18n.ConfirmTelemetryDesc)).
 				Value(&consent),
 		),
 	)
@@ -913,7 +913,7 @@ func runUninstall(ctx context.Context, w, errW io.Writer, force bool) error {
 	// Check if we're in a git repository
 	if _, err := paths.WorktreeRoot(ctx); err != nil {
 		fmt.Fprintln(errW, "Not a git repository. Nothing to uninstall.")
-		return NewSilentError(errors.New("not a git repository"))
+		return NewSilentError(paths.ErrNotGitRepository)
 	}
 
 	// Gather counts for display
@@ -960,9 +960,9 @@ func runUninstall(ctx context.Context, w, errW io.Writer, force bool) error {
 		form := NewAccessibleForm(
 			huh.NewGroup(
 				huh.NewConfirm().
-					Title("Are you sure you want to uninstall Entire?").
-					Affirmative("Yes, uninstall").
-					Negative("Cancel").
+					Title(i18n.T(i18n.ConfirmUninstall)).
+					Affirmative(i18n.T(i18n.ConfirmUninstallYes)).
+					Negative(i18n.T(i18n.Cancel)).
 					Value(&confirmed),
 			),
 		)
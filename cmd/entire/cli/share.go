@@ -0,0 +1,152 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/huh"
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+	"github.com/entireio/cli/cmd/entire/cli/i18n"
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+	"github.com/entireio/cli/cmd/entire/cli/share"
+	"github.com/entireio/cli/cmd/entire/cli/strategy"
+	"github.com/spf13/cobra"
+)
+
+func newShareCmd() *cobra.Command {
+	var expiryFlag time.Duration
+	var forceFlag bool
+
+	cmd := &cobra.Command{
+		Use:   "share <checkpoint-id>",
+		Short: "Upload a checkpoint and print a shareable link",
+		Long: `Share uploads a single committed checkpoint as a self-contained bundle and
+prints a link that expires after a set time. Use it to show a colleague one
+agent turn without pushing branches.
+
+The bundle contains the checkpoint metadata, transcripts, prompts, and context
+exactly as stored on entire/checkpoints/v1 (already redacted).
+
+The backend is configured in .entire/settings.json:
+
+  "share": {"backend": "entire"}                         (default, needs ENTIRE_SHARE_TOKEN)
+  "share": {"backend": "s3", "bucket": "...", "region": "..."}  (uses AWS_* credentials)
+
+Without --force, prompts for confirmation before uploading.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			if _, err := paths.WorktreeRoot(ctx); err != nil {
+				return paths.ErrNotGitRepository
+			}
+			if expiryFlag < 0 {
+				return errors.New("--expiry must be positive")
+			}
+			return runShare(ctx, cmd.OutOrStdout(), args[0], expiryFlag, forceFlag)
+		},
+	}
+
+	cmd.Flags().DurationVar(&expiryFlag, "expiry", 0, "How long the link stays valid (default from settings, or 24h)")
+	cmd.Flags().BoolVarP(&forceFlag, "force", "f", false, "Skip confirmation prompt")
+
+	return cmd
+}
+
+func runShare(ctx context.Context, w io.Writer, checkpointIDPrefix string, expiryFlag time.Duration, force bool) error {
+	repo, err := openRepository(ctx)
+	if err != nil {
+		return fmt.Errorf("not a git repository: %w", err)
+	}
+	store, err := strategy.CommittedStore(ctx, checkpoint.NewGitStore(repo))
+	if err != nil {
+		return err //nolint:wrapcheck // already names the setting
+	}
+
+	checkpointID, err := resolveCommittedCheckpointID(ctx, store, checkpointIDPrefix)
+	if err != nil {
+		return err
+	}
+
+	s, err := LoadEntireSettings(ctx)
+	if err != nil {
+		return err
+	}
+	expiry, err := share.ResolveExpiry(expiryFlag, s.Share)
+	if err != nil {
+		return err
+	}
+	backend, err := share.NewBackend(s.Share)
+	if err != nil {
+		return fmt.Errorf("share backend not configured: %w", err)
+	}
+
+	if !force {
+		var confirmed bool
+		form := NewAccessibleForm(
+			huh.NewGroup(
+				newConfirm().
+					Title(i18n.T(i18n.ConfirmShareUpload, checkpointID, backend.Name())).
+					Description(i18n.T(i18n.ConfirmShareUploadDesc)).
+					Value(&confirmed),
+			),
+		)
+		if err := form.Run(); err != nil {
+			if errors.Is(err, huh.ErrUserAborted) {
+				return nil
+			}
+			return fmt.Errorf("failed to get confirmation: %w", err)
+		}
+		if !confirmed {
+			return nil
+		}
+	}
+
+	bundle, err := share.BuildBundle(ctx, store, checkpointID)
+	if err != nil {
+		return err //nolint:wrapcheck // already wrapped by BuildBundle
+	}
+
+	link, err := backend.Upload(ctx, checkpointID.String()+".json.gz", bundle, expiry)
+	if err != nil {
+		return fmt.Errorf("failed to share checkpoint %s: %w", checkpointID, err)
+	}
+
+	fmt.Fprintf(w, "Shared checkpoint %s via %s\n", checkpointID, backend.Name())
+	fmt.Fprintf(w, "  Link:    %s\n", link.URL)
+	fmt.Fprintf(w, "  Expires: %s\n", link.ExpiresAt.Local().Format("2006-01-02 15:04:05 MST"))
+	return nil
+}
+
+// resolveCommittedCheckpointID expands a checkpoint ID prefix to a full ID,
+// failing if it matches no committed checkpoint or more than one.
+func resolveCommittedCheckpointID(ctx context.Context, store checkpoint.CommittedStore, prefix string) (id.CheckpointID, error) {
+	committed, err := store.ListCommitted(ctx)
+	if err != nil {
+		return id.EmptyCheckpointID, fmt.Errorf("failed to list checkpoints: %w", err)
+	}
+
+	var matches []id.CheckpointID
+	for _, info := range committed {
+		if strings.HasPrefix(info.CheckpointID.String(), prefix) {
+			matches = append(matches, info.CheckpointID)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return id.EmptyCheckpointID, fmt.Errorf("checkpoint not found: %s", prefix)
+	case 1:
+		return matches[0], nil
+	default:
+		examples := make([]string, 0, 5)
+		for i := 0; i < len(matches) && i < 5; i++ {
+			examples = append(examples, matches[i].String())
+		}
+		return id.EmptyCheckpointID, fmt.Errorf("ambiguous checkpoint prefix %q matches %d checkpoints: %s", prefix, len(matches), strings.Join(examples, ", "))
+	}
+}
@@ -0,0 +1,108 @@
+package share
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/entireio/cli/cmd/entire/cli/settings"
+	"github.com/entireio/cli/cmd/entire/cli/sigv4"
+)
+
+const (
+	// DefaultExpiry is used when neither the flag nor settings specify one.
+	DefaultExpiry = 24 * time.Hour
+
+	// DefaultServiceEndpoint is the entire.io share service.
+	DefaultServiceEndpoint = "https://entire.io"
+
+	// TokenEnvVar holds the entire.io API token used by the service backend.
+	TokenEnvVar = "ENTIRE_SHARE_TOKEN"
+
+	uploadTimeout = 60 * time.Second
+)
+
+// Link is a shareable URL for an uploaded bundle.
+type Link struct {
+	URL       string
+	ExpiresAt time.Time
+}
+
+// Backend uploads a bundle and returns a link that stops working after expiry.
+type Backend interface {
+	// Name identifies the backend in user-facing output.
+	Name() string
+	// Upload stores data under key and returns a link valid for expiry.
+	Upload(ctx context.Context, key string, data []byte, expiry time.Duration) (*Link, error)
+}
+
+// NewBackend builds the backend described by cfg. A nil cfg selects the
+// entire.io service with its default endpoint.
+func NewBackend(cfg *settings.ShareSettings) (Backend, error) { //nolint:ireturn // backend is chosen by configuration
+	if cfg == nil {
+		cfg = &settings.ShareSettings{}
+	}
+	client := &http.Client{Timeout: uploadTimeout}
+
+	switch cfg.Backend {
+	case "", settings.ShareBackendEntire:
+		endpoint := cfg.Endpoint
+		if endpoint == "" {
+			endpoint = DefaultServiceEndpoint
+		}
+		token := os.Getenv(TokenEnvVar)
+		if token == "" {
+			return nil, fmt.Errorf("%s is not set; create a token at %s and export it", TokenEnvVar, DefaultServiceEndpoint)
+		}
+		return &ServiceBackend{Endpoint: strings.TrimSuffix(endpoint, "/"), Token: token, Client: client}, nil
+
+	case settings.ShareBackendS3:
+		if cfg.Bucket == "" {
+			return nil, errors.New("share.bucket must be set for the s3 backend")
+		}
+		region := cfg.Region
+		if region == "" {
+			region = os.Getenv("AWS_REGION")
+		}
+		if region == "" {
+			return nil, errors.New("share.region or AWS_REGION must be set for the s3 backend")
+		}
+		creds := sigv4.CredentialsFromEnv()
+		if creds.AccessKeyID == "" || creds.SecretAccessKey == "" {
+			return nil, errors.New("AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY must be set for the s3 backend")
+		}
+		return &S3Backend{
+			Bucket:      cfg.Bucket,
+			Region:      region,
+			Prefix:      cfg.Prefix,
+			Endpoint:    strings.TrimSuffix(cfg.Endpoint, "/"),
+			Credentials: creds,
+			Client:      client,
+		}, nil
+
+	default:
+		return nil, fmt.Errorf("unknown share backend %q: must be %q or %q", cfg.Backend, settings.ShareBackendEntire, settings.ShareBackendS3)
+	}
+}
+
+// ResolveExpiry returns the flag value if set, then the settings value, then DefaultExpiry.
+func ResolveExpiry(flag time.Duration, cfg *settings.ShareSettings) (time.Duration, error) {
+	if flag > 0 {
+		return flag, nil
+	}
+	if cfg != nil && cfg.Expiry != "" {
+		d, err := time.ParseDuration(cfg.Expiry)
+		if err != nil {
+			return 0, fmt.Errorf("invalid share.expiry %q: %w", cfg.Expiry, err)
+		}
+		if d <= 0 {
+			return 0, fmt.Errorf("share.expiry must be positive, got %q", cfg.Expiry)
+		}
+		return d, nil
+	}
+	return DefaultExpiry, nil
+}
@@ -0,0 +1,96 @@
+// Package share uploads a single committed checkpoint as a self-contained
+// bundle and returns a time-limited link to it.
+package share
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+)
+
+// BundleFormatVersion is bumped when the bundle layout changes incompatibly.
+const BundleFormatVersion = 1
+
+// Bundle is the shareable representation of one checkpoint.
+// Transcripts and prompts are already redacted when the checkpoint is written,
+// so the bundle carries exactly what is on the metadata branch.
+type Bundle struct {
+	FormatVersion int                           `json:"format_version"`
+	CreatedAt     time.Time                     `json:"created_at"`
+	Checkpoint    *checkpoint.CheckpointSummary `json:"checkpoint"`
+	Sessions      []BundleSession               `json:"sessions"`
+}
+
+// BundleSession holds one session's content within a bundle.
+type BundleSession struct {
+	Metadata   checkpoint.CommittedMetadata `json:"metadata"`
+	Transcript string                       `json:"transcript"`
+	Prompts    string                       `json:"prompts,omitempty"`
+	Context    string                       `json:"context,omitempty"`
+}
+
+// BuildBundle reads a committed checkpoint and returns it as gzipped JSON.
+func BuildBundle(ctx context.Context, store checkpoint.CommittedStore, checkpointID id.CheckpointID) ([]byte, error) {
+	summary, err := store.ReadCommitted(ctx, checkpointID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checkpoint: %w", err)
+	}
+	if summary == nil {
+		return nil, checkpoint.ErrCheckpointNotFound
+	}
+
+	bundle := Bundle{
+		FormatVersion: BundleFormatVersion,
+		CreatedAt:     time.Now().UTC(),
+		Checkpoint:    summary,
+		Sessions:      make([]BundleSession, 0, len(summary.Sessions)),
+	}
+	for i := range summary.Sessions {
+		content, err := store.ReadSessionContent(ctx, checkpointID, i)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read session %d: %w", i, err)
+		}
+		bundle.Sessions = append(bundle.Sessions, BundleSession{
+			Metadata:   content.Metadata,
+			Transcript: string(content.Transcript),
+			Prompts:    content.Prompts,
+			Context:    content.Context,
+		})
+	}
+
+	data, err := json.Marshal(bundle)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal bundle: %w", err)
+	}
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if _, err := gz.Write(data); err != nil {
+		return nil, fmt.Errorf("failed to compress bundle: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return nil, fmt.Errorf("failed to compress bundle: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// ReadBundle decodes a gzipped bundle produced by BuildBundle.
+func ReadBundle(data []byte) (*Bundle, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress bundle: %w", err)
+	}
+	defer gz.Close()
+
+	var bundle Bundle
+	if err := json.NewDecoder(gz).Decode(&bundle); err != nil {
+		return nil, fmt.Errorf("failed to decode bundle: %w", err)
+	}
+	return &bundle, nil
+}
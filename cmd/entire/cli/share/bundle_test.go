@@ -0,0 +1,139 @@
+package share
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+	"github.com/entireio/cli/cmd/entire/cli/settings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+func setupShareTestStore(t *testing.T) *checkpoint.GitStore {
+	t.Helper()
+	dir := t.TempDir()
+	repo, err := git.PlainInit(dir, false)
+	if err != nil {
+		t.Fatalf("failed to init git repo: %v", err)
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		t.Fatalf("failed to get worktree: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("# Test"), 0o644); err != nil {
+		t.Fatalf("failed to write README: %v", err)
+	}
+	if _, err := worktree.Add("README.md"); err != nil {
+		t.Fatalf("failed to add README: %v", err)
+	}
+	if _, err := worktree.Commit("Initial commit", &git.CommitOptions{
+		Author: &object.Signature{Name: "Test", Email: "test@test.com", When: time.Now()},
+	}); err != nil {
+		t.Fatalf("failed to commit: %v", err)
+	}
+	return checkpoint.NewGitStore(repo)
+}
+
+func TestBuildBundle_RoundTrip(t *testing.T) {
+	t.Parallel()
+	store := setupShareTestStore(t)
+	ctx := context.Background()
+	cpID := id.MustCheckpointID("a1b2c3d4e5f6")
+
+	for _, sessionID := range []string{"session-1", "session-2"} {
+		if err := store.WriteCommitted(ctx, checkpoint.WriteCommittedOptions{
+			CheckpointID: cpID,
+			SessionID:    sessionID,
+			Strategy:     "manual-commit",
+			Transcript:   []byte(`{"type":"user","message":"` + sessionID + `"}` + "\n"),
+			Prompts:      []string{"prompt for " + sessionID},
+			AuthorName:   "Test",
+			AuthorEmail:  "test@test.com",
+		}); err != nil {
+			t.Fatalf("WriteCommitted() error = %v", err)
+		}
+	}
+
+	data, err := BuildBundle(ctx, store, cpID)
+	if err != nil {
+		t.Fatalf("BuildBundle() error = %v", err)
+	}
+	bundle, err := ReadBundle(data)
+	if err != nil {
+		t.Fatalf("ReadBundle() error = %v", err)
+	}
+
+	if bundle.FormatVersion != BundleFormatVersion {
+		t.Errorf("FormatVersion = %d, want %d", bundle.FormatVersion, BundleFormatVersion)
+	}
+	if bundle.Checkpoint == nil || bundle.Checkpoint.CheckpointID != cpID {
+		t.Fatalf("Checkpoint = %+v, want %s", bundle.Checkpoint, cpID)
+	}
+	if len(bundle.Sessions) != 2 {
+		t.Fatalf("got %d sessions, want 2", len(bundle.Sessions))
+	}
+	if bundle.Sessions[1].Metadata.SessionID != "session-2" || bundle.Sessions[1].Prompts != "prompt for session-2" {
+		t.Errorf("second session = %+v", bundle.Sessions[1])
+	}
+}
+
+func TestBuildBundle_NotFound(t *testing.T) {
+	t.Parallel()
+	store := setupShareTestStore(t)
+	if _, err := BuildBundle(context.Background(), store, id.MustCheckpointID("ffffffffffff")); err == nil {
+		t.Error("BuildBundle() should fail for a missing checkpoint")
+	}
+}
+
+func TestResolveExpiry(t *testing.T) {
+	t.Parallel()
+	if d, err := ResolveExpiry(0, nil); err != nil || d != DefaultExpiry {
+		t.Errorf("ResolveExpiry(0, nil) = %v, %v; want %v", d, err, DefaultExpiry)
+	}
+	cfg := &settings.ShareSettings{Expiry: "2h"}
+	if d, err := ResolveExpiry(0, cfg); err != nil || d != 2*time.Hour {
+		t.Errorf("ResolveExpiry(0, 2h) = %v, %v; want 2h", d, err)
+	}
+	if d, err := ResolveExpiry(time.Hour, cfg); err != nil || d != time.Hour {
+		t.Errorf("ResolveExpiry(1h, 2h) = %v, %v; flag should win", d, err)
+	}
+	if _, err := ResolveExpiry(0, &settings.ShareSettings{Expiry: "soon"}); err == nil {
+		t.Error("ResolveExpiry() should reject an unparseable expiry")
+	}
+}
+
+func TestNewBackend(t *testing.T) {
+	t.Setenv(TokenEnvVar, "")
+	if _, err := NewBackend(nil); err == nil {
+		t.Error("NewBackend(nil) without a token should fail")
+	}
+
+	t.Setenv(TokenEnvVar, "token")
+	b, err := NewBackend(nil)
+	if err != nil {
+		t.Fatalf("NewBackend(nil) error = %v", err)
+	}
+	if _, ok := b.(*ServiceBackend); !ok {
+		t.Errorf("NewBackend(nil) = %T, want *ServiceBackend", b)
+	}
+
+	t.Setenv("AWS_ACCESS_KEY_ID", "AK")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "SK")
+	b, err = NewBackend(&settings.ShareSettings{Backend: settings.ShareBackendS3, Bucket: "b", Region: "eu-west-1"})
+	if err != nil {
+		t.Fatalf("NewBackend(s3) error = %v", err)
+	}
+	if _, ok := b.(*S3Backend); !ok {
+		t.Errorf("NewBackend(s3) = %T, want *S3Backend", b)
+	}
+
+	if _, err := NewBackend(&settings.ShareSettings{Backend: "ftp"}); err == nil {
+		t.Error("NewBackend() should reject unknown backends")
+	}
+}
@@ -0,0 +1,105 @@
+package share
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/entireio/cli/cmd/entire/cli/sigv4"
+)
+
+// maxPresignExpiry is the longest lifetime S3 accepts for a SigV4 presigned URL.
+const maxPresignExpiry = 7 * 24 * time.Hour
+
+// uploadURLExpiry bounds the presigned PUT used for the upload itself.
+const uploadURLExpiry = 15 * time.Minute
+
+// S3Backend uploads bundles with a presigned PUT and links to a presigned GET.
+// Signing is done locally with the sigv4 package, so no AWS SDK is needed.
+type S3Backend struct {
+	Bucket string
+	Region string
+	Prefix string
+	// Endpoint selects an S3-compatible store (path-style). Empty means AWS.
+	Endpoint    string
+	Credentials sigv4.Credentials
+	Client      *http.Client
+
+	// now is overridable for tests.
+	now func() time.Time
+}
+
+// Name implements Backend.
+func (b *S3Backend) Name() string { return "s3://" + b.Bucket }
+
+// Upload implements Backend.
+func (b *S3Backend) Upload(ctx context.Context, key string, data []byte, expiry time.Duration) (*Link, error) {
+	if expiry > maxPresignExpiry {
+		return nil, fmt.Errorf("s3 presigned links cannot outlive %s (requested %s)", maxPresignExpiry, expiry)
+	}
+
+	objectKey := key
+	if b.Prefix != "" {
+		objectKey = strings.TrimSuffix(b.Prefix, "/") + "/" + key
+	}
+
+	now := b.clock()
+	putURL, err := b.Presign(http.MethodPut, objectKey, uploadURLExpiry, now)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, putURL, bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create upload request: %w", err)
+	}
+	req.ContentLength = int64(len(data))
+	req.Header.Set("Content-Type", "application/gzip")
+
+	resp, err := b.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upload bundle: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096)) //nolint:errcheck // best-effort error detail
+		return nil, fmt.Errorf("s3 upload returned %s: %s", resp.Status, bytes.TrimSpace(body))
+	}
+
+	getURL, err := b.Presign(http.MethodGet, objectKey, expiry, now)
+	if err != nil {
+		return nil, err
+	}
+	return &Link{URL: getURL, ExpiresAt: now.Add(expiry)}, nil
+}
+
+func (b *S3Backend) clock() time.Time {
+	if b.now != nil {
+		return b.now().UTC()
+	}
+	return time.Now().UTC()
+}
+
+// Presign returns a SigV4 query-signed URL for method on objectKey.
+func (b *S3Backend) Presign(method, objectKey string, expiry time.Duration, now time.Time) (string, error) {
+	if b.Endpoint != "" {
+		u, err := url.Parse(b.Endpoint)
+		if err != nil {
+			return "", fmt.Errorf("invalid s3 endpoint %q: %w", b.Endpoint, err)
+		}
+		target := &url.URL{Scheme: u.Scheme, Host: u.Host, Path: strings.TrimSuffix(u.Path, "/") + "/" + b.Bucket + "/" + objectKey}
+		return sigv4.Presign(method, target, b.Credentials, b.Region, expiry, now), nil
+	}
+
+	host := b.Bucket + ".s3." + b.Region + ".amazonaws.com"
+	if b.Region == "us-east-1" {
+		host = b.Bucket + ".s3.amazonaws.com"
+	}
+	target := &url.URL{Scheme: "https", Host: host, Path: "/" + objectKey}
+	return sigv4.Presign(method, target, b.Credentials, b.Region, expiry, now), nil
+}
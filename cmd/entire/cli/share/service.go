@@ -0,0 +1,72 @@
+package share
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// ServiceBackend uploads bundles to the entire.io share service.
+type ServiceBackend struct {
+	Endpoint string
+	Token    string
+	Client   *http.Client
+}
+
+// serviceResponse is the share service's reply to an upload.
+type serviceResponse struct {
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Name implements Backend.
+func (b *ServiceBackend) Name() string { return "entire.io" }
+
+// Upload implements Backend. The service stores the bundle and returns a link
+// that it stops serving once expiry has elapsed.
+func (b *ServiceBackend) Upload(ctx context.Context, key string, data []byte, expiry time.Duration) (*Link, error) {
+	query := url.Values{}
+	query.Set("name", key)
+	query.Set("expires_in", strconv.Itoa(int(expiry.Seconds())))
+	endpoint := b.Endpoint + "/api/v1/shares?" + query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create share request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+b.Token)
+	req.Header.Set("Content-Type", "application/gzip")
+
+	resp, err := b.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upload bundle: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read share response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("share service returned %s: %s", resp.Status, bytes.TrimSpace(body))
+	}
+
+	var out serviceResponse
+	if err := json.Unmarshal(body, &out); err != nil {
+		return nil, fmt.Errorf("failed to parse share response: %w", err)
+	}
+	if out.URL == "" {
+		return nil, errors.New("share service returned no URL")
+	}
+	if out.ExpiresAt.IsZero() {
+		out.ExpiresAt = time.Now().Add(expiry)
+	}
+	return &Link{URL: out.URL, ExpiresAt: out.ExpiresAt}, nil
+}
@@ -0,0 +1,55 @@
+package share
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestServiceBackend_Upload(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/shares" || r.Method != http.MethodPost {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer secret-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Query().Get("expires_in") != "3600" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"url": "https://entire.io/s/abc", "expires_at": "2026-01-01T01:00:00Z"}`)) //nolint:errcheck // test server
+	}))
+	defer server.Close()
+
+	b := &ServiceBackend{Endpoint: server.URL, Token: "secret-token", Client: server.Client()}
+	link, err := b.Upload(context.Background(), "a1b2c3d4e5f6", []byte("bundle"), time.Hour)
+	if err != nil {
+		t.Fatalf("Upload() error = %v", err)
+	}
+	if link.URL != "https://entire.io/s/abc" {
+		t.Errorf("URL = %q, want https://entire.io/s/abc", link.URL)
+	}
+	if !link.ExpiresAt.Equal(time.Date(2026, 1, 1, 1, 0, 0, 0, time.UTC)) {
+		t.Errorf("ExpiresAt = %v", link.ExpiresAt)
+	}
+}
+
+func TestServiceBackend_ErrorStatus(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "quota exceeded", http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	b := &ServiceBackend{Endpoint: server.URL, Token: "t", Client: server.Client()}
+	if _, err := b.Upload(context.Background(), "x", nil, time.Hour); err == nil {
+		t.Error("Upload() should fail on non-2xx response")
+	}
+}
@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+	"github.com/entireio/cli/cmd/entire/cli/share"
+)
+
+func TestResolveCommittedCheckpointID(t *testing.T) {
+	repo, _ := setupCleanTestRepo(t)
+	store := checkpoint.NewGitStore(repo)
+	ctx := context.Background()
+
+	for _, cpID := range []string{"a1b2c3d4e5f6", "a1b2ffffffff", "b00000000000"} {
+		if err := store.WriteCommitted(ctx, checkpoint.WriteCommittedOptions{
+			CheckpointID: id.MustCheckpointID(cpID),
+			SessionID:    "session-" + cpID,
+			Strategy:     "manual-commit",
+			Transcript:   []byte(`{"type":"user"}` + "\n"),
+			AuthorName:   "Test",
+			AuthorEmail:  "test@test.com",
+		}); err != nil {
+			t.Fatalf("WriteCommitted() error = %v", err)
+		}
+	}
+
+	got, err := resolveCommittedCheckpointID(ctx, store, "a1b2c")
+	if err != nil || got.String() != "a1b2c3d4e5f6" {
+		t.Errorf("resolveCommittedCheckpointID(a1b2c) = %v, %v; want a1b2c3d4e5f6", got, err)
+	}
+	if _, err := resolveCommittedCheckpointID(ctx, store, "a1b2"); err == nil || !strings.Contains(err.Error(), "ambiguous") {
+		t.Errorf("resolveCommittedCheckpointID(a1b2) error = %v, want ambiguous", err)
+	}
+	if _, err := resolveCommittedCheckpointID(ctx, store, "c0"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("resolveCommittedCheckpointID(c0) error = %v, want not found", err)
+	}
+}
+
+func TestRunShare_ServiceBackend(t *testing.T) {
+	repo, _ := setupCleanTestRepo(t)
+	store := checkpoint.NewGitStore(repo)
+	ctx := context.Background()
+
+	if err := store.WriteCommitted(ctx, checkpoint.WriteCommittedOptions{
+		CheckpointID: id.MustCheckpointID("a1b2c3d4e5f6"),
+		SessionID:    "session-1",
+		Strategy:     "manual-commit",
+		Transcript:   []byte(`{"type":"user"}` + "\n"),
+		AuthorName:   "Test",
+		AuthorEmail:  "test@test.com",
+	}); err != nil {
+		t.Fatalf("WriteCommitted() error = %v", err)
+	}
+
+	var uploaded []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		buf := new(bytes.Buffer)
+		_, _ = buf.ReadFrom(r.Body) //nolint:errcheck // test server
+		uploaded = buf.Bytes()
+		_, _ = w.Write([]byte(`{"url": "https://example.test/s/xyz", "expires_at": "2030-01-01T00:00:00Z"}`)) //nolint:errcheck // test server
+	}))
+	defer server.Close()
+
+	if err := os.MkdirAll(".entire", 0o755); err != nil {
+		t.Fatalf("failed to create .entire: %v", err)
+	}
+	settingsJSON := `{"enabled": true, "share": {"backend": "entire", "endpoint": "` + server.URL + `"}}`
+	if err := os.WriteFile(filepath.Join(".entire", "settings.json"), []byte(settingsJSON), 0o644); err != nil {
+		t.Fatalf("failed to write settings: %v", err)
+	}
+	t.Setenv(share.TokenEnvVar, "token")
+
+	var stdout bytes.Buffer
+	if err := runShare(ctx, &stdout, "a1b2c3", 0, true); err != nil {
+		t.Fatalf("runShare() error = %v", err)
+	}
+	if !strings.Contains(stdout.String(), "https://example.test/s/xyz") {
+		t.Errorf("expected link in output, got: %s", stdout.String())
+	}
+
+	bundle, err := share.ReadBundle(uploaded)
+	if err != nil {
+		t.Fatalf("uploaded data is not a bundle: %v", err)
+	}
+	if bundle.Checkpoint.CheckpointID.String() != "a1b2c3d4e5f6" {
+		t.Errorf("uploaded bundle checkpoint = %s", bundle.Checkpoint.CheckpointID)
+	}
+}
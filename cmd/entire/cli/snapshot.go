@@ -0,0 +1,242 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+	"github.com/entireio/cli/cmd/entire/cli/strategy"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// minSnapshotInterval keeps --every from snapshotting in a tight loop.
+	minSnapshotInterval = time.Minute
+
+	// defaultScheduledSnapshotKeep is how many snapshots --every keeps.
+	defaultScheduledSnapshotKeep = 12
+
+	// scheduledSnapshotMessage describes snapshots taken by --every without -m.
+	scheduledSnapshotMessage = "Scheduled snapshot"
+)
+
+func newSnapshotCmd() *cobra.Command {
+	var messageFlag string
+	var listFlag bool
+	var everyFlag time.Duration
+	var keepFlag int
+	var whileActiveFlag bool
+
+	cmd := &cobra.Command{
+		Use:   "snapshot",
+		Short: "Bookmark the current worktree as a rewind point",
+		Long: `Snapshot records your uncommitted changes, tracked and untracked but not
+ignored, as a rewind point, so you can bookmark your own state between agent
+turns. It is stored like the snapshots taken before each turn (see
+"auto_stash"), under refs/entire/snapshots/, and doesn't touch the index,
+the worktree, or HEAD.
+
+'entire rewind' offers the snapshots taken on the current HEAD. Rewinding to
+one restores its files.
+
+With --list, snapshot lists the manual snapshots, newest first.
+
+With --every, snapshot keeps running and takes a snapshot at each interval,
+for long-running autonomous agent jobs. A snapshot is skipped when the
+worktree hasn't changed since the previous one, or while Entire's storage is
+over "storage_quota". Only the latest --keep snapshots of the run are kept.
+With --while-session-active it stops once no agent session in this worktree
+is mid-turn; otherwise it runs until interrupted.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := cmd.Context()
+			if _, err := paths.WorktreeRoot(ctx); err != nil {
+				return paths.ErrNotGitRepository
+			}
+			if listFlag {
+				return runSnapshotList(ctx, cmd.OutOrStdout())
+			}
+			if everyFlag != 0 {
+				if everyFlag < minSnapshotInterval {
+					return fmt.Errorf("--every must be at least %s", minSnapshotInterval)
+				}
+				if keepFlag < 1 {
+					return errors.New("--keep must be at least 1")
+				}
+				return runSnapshotEvery(ctx, cmd.OutOrStdout(), messageFlag, everyFlag, keepFlag, whileActiveFlag)
+			}
+			if whileActiveFlag {
+				return errors.New("--while-session-active needs --every")
+			}
+			return runSnapshot(ctx, cmd.OutOrStdout(), messageFlag)
+		},
+	}
+
+	cmd.Flags().StringVarP(&messageFlag, "message", "m", "", "Describe the snapshot (shown by 'entire rewind')")
+	cmd.Flags().BoolVar(&listFlag, "list", false, "List manual snapshots")
+	cmd.Flags().DurationVar(&everyFlag, "every", 0, "Take a snapshot at this interval (e.g. 10m) until stopped")
+	cmd.Flags().IntVar(&keepFlag, "keep", defaultScheduledSnapshotKeep, "With --every, how many of the run's latest snapshots to keep")
+	cmd.Flags().BoolVar(&whileActiveFlag, "while-session-active", false, "With --every, stop once no agent session is mid-turn")
+
+	return cmd
+}
+
+func runSnapshot(ctx context.Context, w io.Writer, message string) error {
+	commitHash, written, err := GetStrategy(ctx).SnapshotWorktree(ctx, message, plumbing.ZeroHash)
+	if err != nil {
+		return err //nolint:wrapcheck // already wrapped by strategy
+	}
+	if !written {
+		fmt.Fprintln(w, "Nothing to snapshot: the working tree has no uncommitted changes.")
+		return nil
+	}
+	fmt.Fprintf(w, "Snapshot %s saved. Restore it with 'entire rewind'.\n", commitHash.String()[:7])
+	return nil
+}
+
+func runSnapshotList(ctx context.Context, w io.Writer) error {
+	repo, err := openRepository(ctx)
+	if err != nil {
+		return err
+	}
+	snapshots, err := checkpoint.NewGitStore(repo).ListSnapshots(ctx, checkpoint.ManualSnapshotSessionID)
+	if err != nil {
+		return fmt.Errorf("failed to list snapshots: %w", err)
+	}
+	if len(snapshots) == 0 {
+		fmt.Fprintln(w, "No snapshots. Take one with 'entire snapshot -m <message>'.")
+		return nil
+	}
+	sort.Slice(snapshots, func(i, j int) bool { return snapshots[i].Timestamp.After(snapshots[j].Timestamp) })
+
+	head, err := repo.Head()
+	if err != nil {
+		return fmt.Errorf("failed to get HEAD: %w", err)
+	}
+	for _, snap := range snapshots {
+		line := fmt.Sprintf("%s  %s  %s", snap.CommitHash.String()[:7], snap.Timestamp.Local().Format("2006-01-02 15:04:05"), snap.Message)
+		if snap.BaseCommit != head.Hash() {
+			line += fmt.Sprintf("  (on %s, not HEAD)", snap.BaseCommit.String()[:7])
+		}
+		fmt.Fprintln(w, line)
+	}
+	return nil
+}
+
+// snapshotSchedule is the state of a --every run.
+type snapshotSchedule struct {
+	message string
+	keep    int
+	taken   []plumbing.Hash // snapshots taken by this run, oldest first
+}
+
+// runSnapshotEvery takes a snapshot at each interval until ctx is done or,
+// with whileActive, no session in the worktree is mid-turn.
+func runSnapshotEvery(ctx context.Context, w io.Writer, message string, every time.Duration, keep int, whileActive bool) error {
+	if message == "" {
+		message = scheduledSnapshotMessage
+	}
+	schedule := &snapshotSchedule{message: message, keep: keep}
+	fmt.Fprintf(w, "Taking a snapshot every %s; press Ctrl+C to stop.\n", every)
+
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+	for {
+		if whileActive {
+			active, err := hasActiveSession(ctx)
+			if err != nil {
+				return err
+			}
+			if !active {
+				fmt.Fprintln(w, "No agent session is active; stopping.")
+				return nil
+			}
+		}
+		if err := schedule.tick(ctx, w); err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// tick takes the next snapshot, unless the worktree is unchanged or
+// storage is over quota, and deletes the run's snapshots beyond keep.
+func (sch *snapshotSchedule) tick(ctx context.Context, w io.Writer) error {
+	s, err := LoadEntireSettings(ctx)
+	if err != nil {
+		return err
+	}
+	if quotaMessage := checkStorageQuota(ctx, s); quotaMessage != "" {
+		fmt.Fprintln(w, quotaMessage)
+		return nil
+	}
+
+	previous := plumbing.ZeroHash
+	if len(sch.taken) > 0 {
+		previous = sch.taken[len(sch.taken)-1]
+	}
+	commitHash, written, err := GetStrategy(ctx).SnapshotWorktree(ctx, sch.message, previous)
+	if err != nil {
+		return err //nolint:wrapcheck // already wrapped by strategy
+	}
+	if !written {
+		return nil
+	}
+	sch.taken = append(sch.taken, commitHash)
+	fmt.Fprintf(w, "%s  snapshot %s\n", time.Now().Format("15:04:05"), commitHash.String()[:7])
+	if len(sch.taken) <= sch.keep {
+		return nil
+	}
+
+	repo, err := openRepository(ctx)
+	if err != nil {
+		return err
+	}
+	store := checkpoint.NewGitStore(repo)
+	snapshots, err := store.ListSnapshots(ctx, checkpoint.ManualSnapshotSessionID)
+	if err != nil {
+		return fmt.Errorf("failed to list snapshots: %w", err)
+	}
+	expired := sch.taken[:len(sch.taken)-sch.keep]
+	for _, snap := range snapshots {
+		if slices.Contains(expired, snap.CommitHash) {
+			if err := store.DeleteSnapshot(snap.RefName); err != nil {
+				return err //nolint:wrapcheck // already wrapped by checkpoint
+			}
+		}
+	}
+	sch.taken = slices.Clone(sch.taken[len(expired):])
+	return nil
+}
+
+// hasActiveSession reports whether an agent session in this worktree is
+// mid-turn and still sending heartbeats.
+func hasActiveSession(ctx context.Context) (bool, error) {
+	repoRoot, err := paths.WorktreeRoot(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to get worktree root: %w", err)
+	}
+	states, err := strategy.ListSessionStates(ctx)
+	if err != nil {
+		return false, err //nolint:wrapcheck // already wrapped by strategy
+	}
+	now := time.Now()
+	for _, state := range states {
+		if state.WorktreePath == repoRoot && state.Phase.IsActive() && state.EndedAt == nil && !state.IsSilent(now) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+)
+
+func TestRunSnapshot(t *testing.T) {
+	setupCleanTestRepo(t)
+	ctx := context.Background()
+
+	var stdout bytes.Buffer
+	if err := runSnapshot(ctx, &stdout, "clean"); err != nil {
+		t.Fatalf("runSnapshot() error = %v", err)
+	}
+	if !strings.Contains(stdout.String(), "Nothing to snapshot") {
+		t.Errorf("expected nothing to snapshot, got: %s", stdout.String())
+	}
+
+	if err := os.WriteFile("notes.md", []byte("draft\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	stdout.Reset()
+	if err := runSnapshot(ctx, &stdout, "Before trying the agent's refactor"); err != nil {
+		t.Fatalf("runSnapshot() error = %v", err)
+	}
+	if !strings.Contains(stdout.String(), "saved") {
+		t.Errorf("expected a saved snapshot, got: %s", stdout.String())
+	}
+
+	stdout.Reset()
+	if err := runSnapshotList(ctx, &stdout); err != nil {
+		t.Fatalf("runSnapshotList() error = %v", err)
+	}
+	if !strings.Contains(stdout.String(), "Before trying the agent's refactor") {
+		t.Errorf("expected the snapshot in the list, got: %s", stdout.String())
+	}
+
+	// The snapshot is a rewind point without an agent session
+	points, err := GetStrategy(ctx).GetRewindPoints(ctx, 10)
+	if err != nil {
+		t.Fatalf("GetRewindPoints() error = %v", err)
+	}
+	if len(points) != 1 || !points[0].IsSnapshot || points[0].Message != "Before trying the agent's refactor" {
+		t.Fatalf("GetRewindPoints() = %+v, want the manual snapshot", points)
+	}
+	if got := snapshotRestoredMessage(points[0]); got != `Restored snapshot "Before trying the agent's refactor".` {
+		t.Errorf("snapshotRestoredMessage() = %q", got)
+	}
+}
+
+func TestSnapshotSchedule(t *testing.T) {
+	repo, _ := setupCleanTestRepo(t)
+	ctx := context.Background()
+	store := checkpoint.NewGitStore(repo)
+
+	var stdout bytes.Buffer
+	schedule := &snapshotSchedule{message: scheduledSnapshotMessage, keep: 1}
+	for i, content := range []string{"one\n", "one\n", "two\n"} {
+		if err := os.WriteFile("notes.md", []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := schedule.tick(ctx, &stdout); err != nil {
+			t.Fatalf("tick %d error = %v", i, err)
+		}
+	}
+	// The unchanged worktree was skipped, and only the latest snapshot is kept
+	if got := strings.Count(stdout.String(), "snapshot "); got != 2 {
+		t.Errorf("took %d snapshots, want 2:\n%s", got, stdout.String())
+	}
+	snapshots, err := store.ListSnapshots(ctx, checkpoint.ManualSnapshotSessionID)
+	if err != nil {
+		t.Fatalf("ListSnapshots() error = %v", err)
+	}
+	if len(snapshots) != 1 || len(schedule.taken) != 1 || snapshots[0].CommitHash != schedule.taken[0] || snapshots[0].Message != scheduledSnapshotMessage {
+		t.Errorf("snapshots = %+v, want only the latest", snapshots)
+	}
+
+	// Without an active session, --while-session-active stops right away
+	stdout.Reset()
+	if err := runSnapshotEvery(ctx, &stdout, "", time.Minute, 1, true); err != nil {
+		t.Fatalf("runSnapshotEvery() error = %v", err)
+	}
+	if !strings.Contains(stdout.String(), "No agent session is active") {
+		t.Errorf("expected to stop without a session, got: %s", stdout.String())
+	}
+}
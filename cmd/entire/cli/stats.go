@@ -0,0 +1,285 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/entireio/cli/cmd/entire/cli/agent/types"
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/pathfilter"
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+	"github.com/entireio/cli/cmd/entire/cli/strategy"
+
+	"github.com/spf13/cobra"
+)
+
+// statsOptions controls what `entire stats` reports.
+type statsOptions struct {
+	Durations bool // turn duration percentiles per agent
+	Models    bool // acceptance rate per model
+	Filter    *pathfilter.Filter
+}
+
+func newStatsCmd() *cobra.Command {
+	var durationsFlag bool
+	var modelsFlag bool
+	var pathFlags []string
+
+	cmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Summarize checkpoints in this repository",
+		Long: `Stats summarizes the committed checkpoints on entire/checkpoints/v1.
+
+Use --durations to show how long agent turns take, from prompt submit to stop,
+as p50/p90/p99 per agent. Where the agent's transcript has timestamps, each
+turn is also split into time spent running tools and time spent waiting on the
+model. Timings are recorded for checkpoints written by this version onwards.
+
+Use --models to compare models by acceptance rate: the share of lines the
+agent wrote that were still in the commit after human edits. The model comes
+from the agent's hook payload or transcript.
+
+Use --path to only count checkpoints whose commits on the current branch
+touched matching files, with the same patterns as 'entire log --path':
+
+  entire stats --path services/billing`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := cmd.Context()
+			if _, err := paths.WorktreeRoot(ctx); err != nil {
+				return paths.ErrNotGitRepository
+			}
+			filter, err := pathfilter.New(pathFlags)
+			if err != nil {
+				return err //nolint:wrapcheck // already descriptive
+			}
+			return runStats(ctx, cmd.OutOrStdout(), statsOptions{Durations: durationsFlag, Models: modelsFlag, Filter: filter})
+		},
+	}
+
+	cmd.Flags().BoolVar(&durationsFlag, "durations", false, "Show turn duration percentiles per agent")
+	cmd.Flags().BoolVar(&modelsFlag, "models", false, "Show acceptance rate per model")
+	addPathFlag(cmd, &pathFlags, "Only count checkpoints that touched paths matching this glob")
+
+	return cmd
+}
+
+func runStats(ctx context.Context, w io.Writer, opts statsOptions) error {
+	repo, err := openRepository(ctx)
+	if err != nil {
+		return err
+	}
+	store, err := strategy.CommittedStore(ctx, checkpoint.NewGitStore(repo))
+	if err != nil {
+		return err //nolint:wrapcheck // already names the setting
+	}
+
+	committed, err := store.ListCommitted(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list checkpoints: %w", err)
+	}
+	touched, err := touchedCheckpoints(ctx, repo, opts.Filter)
+	if err != nil {
+		return err
+	}
+	if touched != nil {
+		committed = slices.DeleteFunc(committed, func(info checkpoint.CommittedInfo) bool { return !touched[info.CheckpointID] })
+	}
+	if len(committed) == 0 {
+		fmt.Fprintln(w, "No checkpoints found.")
+		return nil
+	}
+
+	if opts.Durations {
+		return writeDurationStats(ctx, w, store, committed)
+	}
+	if opts.Models {
+		return writeModelStats(ctx, w, store, committed)
+	}
+	writeSummaryStats(w, committed)
+	return nil
+}
+
+// writeSummaryStats prints checkpoint, session, and line counts.
+func writeSummaryStats(w io.Writer, committed []checkpoint.CommittedInfo) {
+	sessions := make(map[string]bool)
+	var withStats, insertions, deletions int
+	for _, info := range committed {
+		for _, sessionID := range info.SessionIDs {
+			sessions[sessionID] = true
+		}
+		if info.SessionID != "" {
+			sessions[info.SessionID] = true
+		}
+		if info.DiffStats != nil {
+			withStats++
+			insertions += info.DiffStats.Insertions
+			deletions += info.DiffStats.Deletions
+		}
+	}
+
+	fmt.Fprintf(w, "Checkpoints: %d\n", len(committed))
+	fmt.Fprintf(w, "Sessions:    %d\n", len(sessions))
+	if withStats > 0 {
+		fmt.Fprintf(w, "Lines:       +%d -%d (%d checkpoints with stats)\n", insertions, deletions, withStats)
+	}
+}
+
+// turnDurations collects turn timings for one agent.
+type turnDurations struct {
+	total, tool, model []time.Duration
+}
+
+// writeDurationStats prints turn duration percentiles grouped by agent.
+func writeDurationStats(ctx context.Context, w io.Writer, store checkpoint.CommittedStore, committed []checkpoint.CommittedInfo) error {
+	byAgent := make(map[types.AgentType]*turnDurations)
+	for _, info := range committed {
+		sessions, err := store.ReadSessionMetadata(ctx, info.CheckpointID)
+		if err != nil {
+			continue
+		}
+		for _, meta := range sessions {
+			if len(meta.Turns) == 0 {
+				continue
+			}
+			d := byAgent[meta.Agent]
+			if d == nil {
+				d = &turnDurations{}
+				byAgent[meta.Agent] = d
+			}
+			for _, turn := range meta.Turns {
+				d.total = append(d.total, turn.Duration())
+				if turn.ToolMs > 0 || turn.ModelMs > 0 {
+					d.tool = append(d.tool, turn.ToolTime())
+					d.model = append(d.model, turn.ModelTime())
+				}
+			}
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return err //nolint:wrapcheck // Propagating context cancellation
+	}
+
+	if len(byAgent) == 0 {
+		fmt.Fprintln(w, "No turn timings recorded yet.")
+		return nil
+	}
+
+	agents := make([]types.AgentType, 0, len(byAgent))
+	for agentType := range byAgent {
+		agents = append(agents, agentType)
+	}
+	sort.Slice(agents, func(i, j int) bool { return agents[i] < agents[j] })
+
+	for i, agentType := range agents {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		name := string(agentType)
+		if name == "" {
+			name = "Unknown agent"
+		}
+		d := byAgent[agentType]
+		fmt.Fprintf(w, "%s (%d turns)\n", name, len(d.total))
+		fmt.Fprintf(w, "  %-6s %8s %8s %8s\n", "", "p50", "p90", "p99")
+		writePercentileRow(w, "turn", d.total)
+		writePercentileRow(w, "tool", d.tool)
+		writePercentileRow(w, "model", d.model)
+	}
+	return nil
+}
+
+// modelAcceptance accumulates attribution for one model.
+type modelAcceptance struct {
+	sessions     int
+	agentKept    int // agent lines still in the commit
+	agentWritten int // agent lines before human edits
+}
+
+// writeModelStats prints, per model, how many checkpoint sessions it produced
+// and what share of its lines survived into the commit.
+func writeModelStats(ctx context.Context, w io.Writer, store checkpoint.CommittedStore, committed []checkpoint.CommittedInfo) error {
+	byModel := make(map[string]*modelAcceptance)
+	for _, info := range committed {
+		sessions, err := store.ReadSessionMetadata(ctx, info.CheckpointID)
+		if err != nil {
+			continue
+		}
+		for _, meta := range sessions {
+			model := meta.Model
+			if model == "" {
+				model = "unknown"
+			}
+			m := byModel[model]
+			if m == nil {
+				m = &modelAcceptance{}
+				byModel[model] = m
+			}
+			m.sessions++
+			// Older checkpoints don't record lines written, so they can't contribute a rate
+			if attr := meta.InitialAttribution; attr != nil && attr.AgentWritten > 0 {
+				m.agentKept += attr.AgentLines
+				m.agentWritten += attr.AgentWritten
+			}
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return err //nolint:wrapcheck // Propagating context cancellation
+	}
+
+	models := make([]string, 0, len(byModel))
+	width := len("Model")
+	for model := range byModel {
+		models = append(models, model)
+		width = max(width, len(model))
+	}
+	sort.Strings(models)
+
+	fmt.Fprintf(w, "%-*s  %8s  %12s  %10s\n", width, "Model", "Sessions", "Lines kept", "Acceptance")
+	for _, model := range models {
+		m := byModel[model]
+		kept, rate := "-", "-"
+		if m.agentWritten > 0 {
+			kept = fmt.Sprintf("%d/%d", m.agentKept, m.agentWritten)
+			rate = fmt.Sprintf("%.1f%%", float64(m.agentKept)/float64(m.agentWritten)*100)
+		}
+		fmt.Fprintf(w, "%-*s  %8d  %12s  %10s\n", width, model, m.sessions, kept, rate)
+	}
+	return nil
+}
+
+// writePercentileRow prints the p50/p90/p99 of values, or nothing if empty.
+func writePercentileRow(w io.Writer, label string, values []time.Duration) {
+	if len(values) == 0 {
+		return
+	}
+	sorted := append([]time.Duration(nil), values...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	fmt.Fprintf(w, "  %-6s %8s %8s %8s\n", label,
+		formatStatDuration(percentile(sorted, 50)),
+		formatStatDuration(percentile(sorted, 90)),
+		formatStatDuration(percentile(sorted, 99)))
+}
+
+// percentile returns the nearest-rank p-th percentile of sorted values.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	rank = max(rank, 1)
+	return sorted[rank-1]
+}
+
+// formatStatDuration rounds d to seconds, or milliseconds below one second.
+func formatStatDuration(d time.Duration) string {
+	if d < time.Second {
+		return d.Round(time.Millisecond).String()
+	}
+	return d.Round(time.Second).String()
+}
@@ -0,0 +1,155 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/entireio/cli/cmd/entire/cli/agent"
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+	"github.com/entireio/cli/cmd/entire/cli/pathfilter"
+)
+
+func TestPercentile(t *testing.T) {
+	t.Parallel()
+
+	sorted := make([]time.Duration, 0, 10)
+	for i := 1; i <= 10; i++ {
+		sorted = append(sorted, time.Duration(i)*time.Second)
+	}
+	for _, tc := range []struct {
+		p    float64
+		want time.Duration
+	}{
+		{50, 5 * time.Second},
+		{90, 9 * time.Second},
+		{99, 10 * time.Second},
+	} {
+		if got := percentile(sorted, tc.p); got != tc.want {
+			t.Errorf("percentile(p%v) = %v, want %v", tc.p, got, tc.want)
+		}
+	}
+	if got := percentile(nil, 50); got != 0 {
+		t.Errorf("percentile(nil) = %v, want 0", got)
+	}
+}
+
+func TestRunStats_Durations(t *testing.T) {
+	setupCleanTestRepo(t)
+	ctx := context.Background()
+
+	var stdout bytes.Buffer
+	if err := runStats(ctx, &stdout, statsOptions{Durations: true}); err != nil {
+		t.Fatalf("runStats() error = %v", err)
+	}
+	if !strings.Contains(stdout.String(), "No checkpoints found") {
+		t.Errorf("unexpected output without checkpoints:\n%s", stdout.String())
+	}
+
+	repo, err := openRepository(ctx)
+	if err != nil {
+		t.Fatalf("openRepository() error = %v", err)
+	}
+	start := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	if err := checkpoint.NewGitStore(repo).WriteCommitted(ctx, checkpoint.WriteCommittedOptions{
+		CheckpointID: id.MustCheckpointID("d4d4d4d4d4d4"),
+		SessionID:    "session-durations",
+		Strategy:     "manual-commit",
+		Agent:        agent.AgentTypeClaudeCode,
+		Transcript:   []byte(`{"type":"user"}` + "\n"),
+		AuthorName:   "Test",
+		AuthorEmail:  "test@test.com",
+		Turns: []checkpoint.TurnTiming{
+			{StartedAt: start, EndedAt: start.Add(90 * time.Second), ToolMs: 30000, ModelMs: 50000},
+			{StartedAt: start, EndedAt: start.Add(3 * time.Minute)},
+		},
+	}); err != nil {
+		t.Fatalf("WriteCommitted() error = %v", err)
+	}
+
+	stdout.Reset()
+	if err := runStats(ctx, &stdout, statsOptions{Durations: true}); err != nil {
+		t.Fatalf("runStats() error = %v", err)
+	}
+	out := stdout.String()
+	for _, want := range []string{"Claude Code (2 turns)", "turn      1m30s     3m0s     3m0s", "tool        30s", "model       50s"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+
+	stdout.Reset()
+	if err := runStats(ctx, &stdout, statsOptions{}); err != nil {
+		t.Fatalf("runStats() error = %v", err)
+	}
+	if !strings.Contains(stdout.String(), "Checkpoints: 1") || !strings.Contains(stdout.String(), "Sessions:    1") {
+		t.Errorf("unexpected summary output:\n%s", stdout.String())
+	}
+}
+
+func TestRunStats_Models(t *testing.T) {
+	setupCleanTestRepo(t)
+	ctx := context.Background()
+
+	repo, err := openRepository(ctx)
+	if err != nil {
+		t.Fatalf("openRepository() error = %v", err)
+	}
+	store := checkpoint.NewGitStore(repo)
+	for _, cp := range []struct {
+		id     string
+		model  string
+		attrib *checkpoint.InitialAttribution
+	}{
+		{"e5e5e5e5e5e5", "claude-sonnet-4-5", &checkpoint.InitialAttribution{AgentLines: 80, AgentWritten: 100}},
+		{"f6f6f6f6f6f6", "claude-sonnet-4-5", &checkpoint.InitialAttribution{AgentLines: 10, AgentWritten: 20}},
+		{"a7a7a7a7a7a7", "", &checkpoint.InitialAttribution{AgentLines: 5}},
+	} {
+		if err := store.WriteCommitted(ctx, checkpoint.WriteCommittedOptions{
+			CheckpointID:       id.MustCheckpointID(cp.id),
+			SessionID:          "session-" + cp.id,
+			Strategy:           "manual-commit",
+			Transcript:         []byte(`{"type":"user"}` + "\n"),
+			AuthorName:         "Test",
+			AuthorEmail:        "test@test.com",
+			Model:              cp.model,
+			InitialAttribution: cp.attrib,
+		}); err != nil {
+			t.Fatalf("WriteCommitted() error = %v", err)
+		}
+	}
+
+	var stdout bytes.Buffer
+	if err := runStats(ctx, &stdout, statsOptions{Models: true}); err != nil {
+		t.Fatalf("runStats() error = %v", err)
+	}
+	out := stdout.String()
+	for _, want := range []string{
+		"claude-sonnet-4-5         2        90/120       75.0%",
+		"unknown                   1             -           -",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+}
+
+func TestRunStats_PathFilter(t *testing.T) {
+	setupLogTestRepo(t)
+	writeLogTestCheckpoints(t)
+
+	filter, err := pathfilter.New([]string{"services/api"})
+	if err != nil {
+		t.Fatalf("pathfilter.New() error = %v", err)
+	}
+	var stdout bytes.Buffer
+	if err := runStats(context.Background(), &stdout, statsOptions{Filter: filter}); err != nil {
+		t.Fatalf("runStats() error = %v", err)
+	}
+	if !strings.Contains(stdout.String(), "Checkpoints: 1") {
+		t.Errorf("expected only the api checkpoint to be counted, got:\n%s", stdout.String())
+	}
+}
@@ -140,11 +140,11 @@ func formatSettingsStatusShort(ctx context.Context, s *EntireSettings, sty statu
 	var b strings.Builder
 
 	if s.Enabled {
-		b.WriteString(sty.render(sty.green, "●"))
+		b.WriteString(sty.render(sty.success, "●"))
 		b.WriteString(" ")
 		b.WriteString(sty.render(sty.bold, "Enabled"))
 	} else {
-		b.WriteString(sty.render(sty.red, "○"))
+		b.WriteString(sty.render(sty.failure, "○"))
 		b.WriteString(" ")
 		b.WriteString(sty.render(sty.bold, "Disabled"))
 	}
@@ -157,7 +157,7 @@ func formatSettingsStatusShort(ctx context.Context, s *EntireSettings, sty statu
 		if branch := resolveWorktreeBranch(ctx, repoRoot); branch != "" {
 			b.WriteString(sty.render(sty.dim, " · "))
 			b.WriteString("branch ")
-			b.WriteString(sty.render(sty.cyan, branch))
+			b.WriteString(sty.render(sty.info, branch))
 		}
 	}
 
@@ -301,11 +301,16 @@ func writeActiveSessions(ctx context.Context, w io.Writer, sty statusStyles) {
 				shortID = shortID[:7]
 			}
 
-			// Line 1: Agent · shortID
-			fmt.Fprintf(w, "%s %s %s\n",
+			// Line 1: Agent · shortID [· crashed?]
+			crashed := ""
+			if st.CrashedAt != nil || st.IsSilent(time.Now()) {
+				crashed = " " + sty.render(sty.dim, "·") + " " + sty.render(sty.failure, "crashed?")
+			}
+			fmt.Fprintf(w, "%s %s %s%s\n",
 				sty.render(sty.agent, agentLabel),
 				sty.render(sty.dim, "·"),
-				shortID)
+				shortID,
+				crashed)
 
 			// Line 2: > "first prompt" (chevron + quoted, truncated)
 			if st.FirstPrompt != "" {
@@ -10,6 +10,7 @@ import (
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/entireio/cli/cmd/entire/cli/agent"
+	"github.com/entireio/cli/cmd/entire/cli/theme"
 
 	"golang.org/x/term"
 )
@@ -19,14 +20,14 @@ type statusStyles struct {
 	colorEnabled bool
 	width        int
 
-	// Styles
-	green lipgloss.Style
-	red   lipgloss.Style
-	gray  lipgloss.Style
-	bold  lipgloss.Style
-	dim   lipgloss.Style
-	agent lipgloss.Style // amber/orange for agent names
-	cyan  lipgloss.Style
+	// Styles, colored by the selected theme palette
+	success lipgloss.Style
+	failure lipgloss.Style
+	muted   lipgloss.Style
+	bold    lipgloss.Style
+	dim     lipgloss.Style
+	agent   lipgloss.Style
+	info    lipgloss.Style
 }
 
 // newStatusStyles creates styles appropriate for the output writer.
@@ -40,13 +41,14 @@ func newStatusStyles(w io.Writer) statusStyles {
 	}
 
 	if useColor {
-		s.green = lipgloss.NewStyle().Foreground(lipgloss.Color("2"))
-		s.red = lipgloss.NewStyle().Foreground(lipgloss.Color("1"))
-		s.gray = lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+		p := theme.Current()
+		s.success = lipgloss.NewStyle().Foreground(p.Success)
+		s.failure = lipgloss.NewStyle().Foreground(p.Failure)
+		s.muted = lipgloss.NewStyle().Foreground(p.Muted)
 		s.bold = lipgloss.NewStyle().Bold(true)
 		s.dim = lipgloss.NewStyle().Faint(true)
-		s.agent = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("214"))
-		s.cyan = lipgloss.NewStyle().Foreground(lipgloss.Color("6"))
+		s.agent = lipgloss.NewStyle().Bold(true).Foreground(p.Agent)
+		s.info = lipgloss.NewStyle().Foreground(p.Info)
 	}
 
 	return s
@@ -60,15 +62,10 @@ func (s statusStyles) render(style lipgloss.Style, text string) string {
 	return style.Render(text)
 }
 
-// shouldUseColor returns true if the writer supports color output.
+// shouldUseColor returns true if output to the writer should be colored,
+// following --color and NO_COLOR.
 func shouldUseColor(w io.Writer) bool {
-	if os.Getenv("NO_COLOR") != "" {
-		return false
-	}
-	if f, ok := w.(*os.File); ok {
-		return term.IsTerminal(int(f.Fd())) //nolint:gosec // G115: uintptr->int is safe for fd
-	}
-	return false
+	return theme.Enabled(w)
 }
 
 // getTerminalWidth returns the terminal width, capped at 80 with a fallback of 60.
@@ -578,6 +578,49 @@ func TestWriteActiveSessions_EndedSessionsExcluded(t *testing.T) {
 	}
 }
 
+func TestWriteActiveSessions_CrashedSession(t *testing.T) {
+	setupTestRepo(t)
+
+	store, err := session.NewStateStore(context.Background())
+	if err != nil {
+		t.Fatalf("NewStateStore() error = %v", err)
+	}
+
+	silentSince := time.Now().Add(-2 * session.HeartbeatTimeout)
+	states := []*session.State{
+		{
+			SessionID:           "silent-session",
+			WorktreePath:        "/Users/test/repo",
+			StartedAt:           silentSince.Add(-time.Hour),
+			Phase:               session.PhaseActive,
+			LastInteractionTime: &silentSince,
+		},
+		{
+			SessionID:    "healthy-session",
+			WorktreePath: "/Users/test/repo",
+			StartedAt:    time.Now(),
+			Phase:        session.PhaseIdle,
+		},
+	}
+	for _, state := range states {
+		if err := store.Save(context.Background(), state); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+	}
+
+	var buf bytes.Buffer
+	sty := newStatusStyles(&buf)
+	writeActiveSessions(context.Background(), &buf, sty)
+
+	output := buf.String()
+	if !strings.Contains(output, "silent- · crashed?") {
+		t.Errorf("Expected the silent session to be flagged as crashed, got: %s", output)
+	}
+	if strings.Count(output, "crashed?") != 1 {
+		t.Errorf("Expected only the silent session to be flagged, got: %s", output)
+	}
+}
+
 func TestFormatTokenCount(t *testing.T) {
 	t.Parallel()
 
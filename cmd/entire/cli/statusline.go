@@ -0,0 +1,262 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/events"
+	"github.com/entireio/cli/cmd/entire/cli/jsonutil"
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+	"github.com/entireio/cli/cmd/entire/cli/session"
+	"github.com/entireio/cli/cmd/entire/cli/strategy"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	// statuslineCacheFile holds the last computed status line per worktree,
+	// kept in the git common dir next to session state.
+	statuslineCacheFile = "entire-statusline.json"
+
+	// statuslineCacheTTL bounds how stale a cached status line can get when
+	// nothing it watches has changed (e.g. a checkpoint fetched from a remote).
+	statuslineCacheTTL = 10 * time.Second
+)
+
+func newStatuslineCmd() *cobra.Command {
+	var noCacheFlag bool
+
+	cmd := &cobra.Command{
+		Use:   "statusline",
+		Short: "Print a one-line Entire summary for shell prompts and tmux",
+		Long: `Statusline prints a compact one-line summary of Entire in this worktree:
+the strategy, the active agent session, its turns, and the age of the last
+checkpoint, e.g.
+
+  manual-commit · Claude Code 1a2b3c4 · 3 turns · checkpoint 5m ago
+
+It is meant to run on every prompt, so the summary is cached in the git
+directory and only recomputed when session state, settings, or the event log
+change. Nothing is printed outside a git repository or when Entire is
+disabled.
+
+tmux:      set -g status-right '#(cd #{pane_current_path}; entire statusline)'
+starship:  [custom.entire]
+           command = "entire statusline"
+           when = "git rev-parse --git-dir"`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := cmd.Context()
+			worktreeRoot, err := paths.WorktreeRoot(ctx)
+			if err != nil {
+				return nil //nolint:nilerr // prompts outside a repository show nothing
+			}
+			commonDir, err := strategy.GetGitCommonDir(ctx)
+			if err != nil {
+				return err //nolint:wrapcheck // already descriptive
+			}
+			return runStatusline(ctx, cmd.OutOrStdout(), worktreeRoot, commonDir, !noCacheFlag)
+		},
+		// Runs on every prompt: skip telemetry and the version check, which
+		// would slow it down and print into the prompt
+		PersistentPostRun: func(*cobra.Command, []string) {},
+	}
+
+	cmd.Flags().BoolVar(&noCacheFlag, "no-cache", false, "Recompute the summary instead of using the cached one")
+
+	return cmd
+}
+
+// statuslineInfo is what the status line shows, as cached between prompts.
+// Ages are computed when printing, so a cached entry never shows a stale age.
+type statuslineInfo struct {
+	Enabled          bool      `json:"enabled"`
+	Agent            string    `json:"agent,omitempty"`
+	SessionID        string    `json:"session_id,omitempty"`
+	Turns            int       `json:"turns,omitempty"`
+	LastCheckpointAt time.Time `json:"last_checkpoint_at,omitzero"`
+}
+
+type statuslineCacheEntry struct {
+	WrittenAt time.Time      `json:"written_at"`
+	Info      statuslineInfo `json:"info"`
+}
+
+func runStatusline(ctx context.Context, w io.Writer, worktreeRoot, commonDir string, useCache bool) error {
+	cachePath := filepath.Join(commonDir, statuslineCacheFile)
+	watched := []string{
+		filepath.Join(commonDir, session.SessionStateDirName),
+		filepath.Join(commonDir, events.FileName),
+		filepath.Join(worktreeRoot, EntireSettingsFile),
+		filepath.Join(worktreeRoot, EntireSettingsLocalFile),
+	}
+
+	cache := loadStatuslineCache(cachePath)
+	entry, ok := cache[worktreeRoot]
+	if !useCache || !ok || !statuslineCacheFresh(entry, watched, time.Now()) {
+		info, err := collectStatusline(ctx, worktreeRoot)
+		if err != nil {
+			return err
+		}
+		entry = statuslineCacheEntry{WrittenAt: time.Now(), Info: info}
+		cache[worktreeRoot] = entry
+		saveStatuslineCache(cachePath, cache)
+	}
+
+	if line := formatStatusline(entry.Info); line != "" {
+		fmt.Fprintln(w, line)
+	}
+	return nil
+}
+
+// collectStatusline reads settings, session state, and the metadata branch
+// for the status line of the worktree at worktreeRoot.
+func collectStatusline(ctx context.Context, worktreeRoot string) (statuslineInfo, error) {
+	s, err := LoadEntireSettings(ctx)
+	if err != nil {
+		return statuslineInfo{}, fmt.Errorf("failed to load settings: %w", err)
+	}
+	if !s.Enabled {
+		return statuslineInfo{}, nil
+	}
+	info := statuslineInfo{Enabled: true}
+
+	if store, err := session.NewStateStore(ctx); err == nil {
+		states, err := store.List(ctx)
+		if err != nil {
+			return statuslineInfo{}, fmt.Errorf("failed to list sessions: %w", err)
+		}
+		if st := latestActiveSession(states, worktreeRoot); st != nil {
+			info.Agent = string(st.AgentType)
+			info.SessionID = st.SessionID
+			info.Turns = st.TurnCount
+		}
+	}
+
+	repo, err := openRepository(ctx)
+	if err != nil {
+		return statuslineInfo{}, fmt.Errorf("not a git repository: %w", err)
+	}
+	store, err := strategy.CommittedStore(ctx, checkpoint.NewGitStore(repo))
+	if err != nil {
+		return statuslineInfo{}, err //nolint:wrapcheck // already names the setting
+	}
+	page, err := store.ListCommittedPage(ctx, checkpoint.ListOptions{Limit: 1})
+	if err != nil {
+		return statuslineInfo{}, fmt.Errorf("failed to list checkpoints: %w", err)
+	}
+	if len(page.Checkpoints) > 0 {
+		info.LastCheckpointAt = page.Checkpoints[0].CreatedAt
+	}
+	return info, nil
+}
+
+// latestActiveSession returns the active session in the worktree that most
+// recently saw activity, or nil if there is none.
+func latestActiveSession(states []*session.State, worktreeRoot string) *session.State {
+	var latest *session.State
+	var latestAt time.Time
+	for _, st := range states {
+		if st.EndedAt != nil || st.WorktreePath != worktreeRoot {
+			continue
+		}
+		at := st.StartedAt
+		if st.LastInteractionTime != nil {
+			at = *st.LastInteractionTime
+		}
+		if latest == nil || at.After(latestAt) {
+			latest, latestAt = st, at
+		}
+	}
+	return latest
+}
+
+// formatStatusline renders info as one line, or "" when Entire is disabled.
+// Output format: "manual-commit · Claude Code 1a2b3c4 · 3 turns · checkpoint 5m ago"
+func formatStatusline(info statuslineInfo) string {
+	if !info.Enabled {
+		return ""
+	}
+	parts := []string{strategy.StrategyNameManualCommit}
+
+	if info.SessionID != "" {
+		agentLabel := info.Agent
+		if agentLabel == "" {
+			agentLabel = "session"
+		}
+		shortID := info.SessionID
+		if len(shortID) > 7 {
+			shortID = shortID[:7]
+		}
+		parts = append(parts, agentLabel+" "+shortID)
+		switch info.Turns {
+		case 0:
+		case 1:
+			parts = append(parts, "1 turn")
+		default:
+			parts = append(parts, strconv.Itoa(info.Turns)+" turns")
+		}
+	} else {
+		parts = append(parts, "no session")
+	}
+
+	if info.LastCheckpointAt.IsZero() {
+		parts = append(parts, "no checkpoints")
+	} else {
+		parts = append(parts, "checkpoint "+timeAgo(info.LastCheckpointAt))
+	}
+	return strings.Join(parts, " · ")
+}
+
+// statuslineCacheFresh reports whether entry can be shown as is: it is
+// younger than statuslineCacheTTL and none of the watched paths changed
+// since it was written. Missing paths count as unchanged.
+func statuslineCacheFresh(entry statuslineCacheEntry, watched []string, now time.Time) bool {
+	if now.Sub(entry.WrittenAt) > statuslineCacheTTL || entry.WrittenAt.After(now) {
+		return false
+	}
+	for _, path := range watched {
+		if fi, err := os.Stat(path); err == nil && !fi.ModTime().Before(entry.WrittenAt) {
+			return false
+		}
+	}
+	return true
+}
+
+// loadStatuslineCache reads the cache, keyed by worktree root. A missing or
+// unreadable cache is empty.
+func loadStatuslineCache(path string) map[string]statuslineCacheEntry {
+	cache := make(map[string]statuslineCacheEntry)
+	data, err := os.ReadFile(path) //nolint:gosec // path is in the git common dir
+	if err != nil {
+		return cache
+	}
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return make(map[string]statuslineCacheEntry)
+	}
+	return cache
+}
+
+// saveStatuslineCache writes the cache atomically. It is best-effort: the
+// status line is still printed when the git directory is read-only.
+func saveStatuslineCache(path string, cache map[string]statuslineCacheEntry) {
+	data, err := jsonutil.MarshalIndentWithNewline(cache, "", "  ")
+	if err != nil {
+		return
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+	}
+}
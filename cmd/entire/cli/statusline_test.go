@@ -0,0 +1,112 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/entireio/cli/cmd/entire/cli/session"
+)
+
+func TestFormatStatusline(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		info statuslineInfo
+		want string
+	}{
+		{"disabled", statuslineInfo{}, ""},
+		{"idle", statuslineInfo{Enabled: true}, "manual-commit · no session · no checkpoints"},
+		{
+			"active session",
+			statuslineInfo{
+				Enabled: true, Agent: "Claude Code", SessionID: "1a2b3c4d-5e6f", Turns: 3,
+				LastCheckpointAt: time.Now().Add(-5 * time.Minute),
+			},
+			"manual-commit · Claude Code 1a2b3c4 · 3 turns · checkpoint 5m ago",
+		},
+		{
+			"one turn",
+			statuslineInfo{Enabled: true, SessionID: "abc", Turns: 1},
+			"manual-commit · session abc · 1 turn · no checkpoints",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := formatStatusline(tt.info); got != tt.want {
+				t.Errorf("formatStatusline() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLatestActiveSession(t *testing.T) {
+	t.Parallel()
+	now := time.Now()
+	earlier := now.Add(-time.Hour)
+	states := []*session.State{
+		{SessionID: "other-worktree", WorktreePath: "/other", StartedAt: now},
+		{SessionID: "ended", WorktreePath: "/repo", StartedAt: now, EndedAt: &now},
+		{SessionID: "older", WorktreePath: "/repo", StartedAt: earlier, LastInteractionTime: &earlier},
+		{SessionID: "recent", WorktreePath: "/repo", StartedAt: earlier, LastInteractionTime: &now},
+	}
+	if got := latestActiveSession(states, "/repo"); got == nil || got.SessionID != "recent" {
+		t.Errorf("latestActiveSession() = %+v, want recent", got)
+	}
+	if got := latestActiveSession(states, "/none"); got != nil {
+		t.Errorf("latestActiveSession() = %+v, want nil", got)
+	}
+}
+
+func TestStatuslineCacheFresh(t *testing.T) {
+	t.Parallel()
+	watched := filepath.Join(t.TempDir(), "state")
+	if err := os.WriteFile(watched, nil, 0o600); err != nil {
+		t.Fatalf("failed to write watched file: %v", err)
+	}
+	now := time.Now()
+	if err := os.Chtimes(watched, now.Add(-time.Minute), now.Add(-time.Minute)); err != nil {
+		t.Fatalf("Chtimes() error = %v", err)
+	}
+	paths := []string{watched, filepath.Join(t.TempDir(), "missing")}
+
+	entry := statuslineCacheEntry{WrittenAt: now.Add(-time.Second)}
+	if !statuslineCacheFresh(entry, paths, now) {
+		t.Error("recent entry with unchanged paths is stale")
+	}
+	if statuslineCacheFresh(statuslineCacheEntry{WrittenAt: now.Add(-statuslineCacheTTL - time.Second)}, paths, now) {
+		t.Error("entry older than the TTL is fresh")
+	}
+	if err := os.Chtimes(watched, now, now); err != nil {
+		t.Fatalf("Chtimes() error = %v", err)
+	}
+	if statuslineCacheFresh(entry, paths, now) {
+		t.Error("entry written before a watched path changed is fresh")
+	}
+}
+
+func TestRunStatusline_UsesCache(t *testing.T) {
+	t.Parallel()
+	commonDir := t.TempDir()
+	worktreeRoot := t.TempDir()
+	saveStatuslineCache(filepath.Join(commonDir, statuslineCacheFile), map[string]statuslineCacheEntry{
+		worktreeRoot: {
+			WrittenAt: time.Now(),
+			Info:      statuslineInfo{Enabled: true, Agent: "Gemini CLI", SessionID: "cached-session", Turns: 2},
+		},
+	})
+
+	// A fresh entry is printed without reading settings or the repository,
+	// neither of which exists here
+	var out bytes.Buffer
+	if err := runStatusline(context.Background(), &out, worktreeRoot, commonDir, true); err != nil {
+		t.Fatalf("runStatusline() error = %v", err)
+	}
+	if want := "manual-commit · Gemini CLI cached- · 2 turns · no checkpoints\n"; out.String() != want {
+		t.Errorf("runStatusline() = %q, want %q", out.String(), want)
+	}
+}
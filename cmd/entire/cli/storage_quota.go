@@ -0,0 +1,58 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"time"
+
+	"github.com/entireio/cli/cmd/entire/cli/logging"
+	"github.com/entireio/cli/cmd/entire/cli/settings"
+	"github.com/entireio/cli/cmd/entire/cli/strategy"
+)
+
+// checkStorageQuota compares Entire's storage in the repository with the
+// storage_quota setting. With the "gc" action it first prunes with the gc
+// retention policy, as `entire gc` does. Returns a message explaining why
+// checkpoints aren't saved while over the quota, or "" if they may be.
+// Storage that can't be measured doesn't block checkpoints.
+func checkStorageQuota(ctx context.Context, s *EntireSettings) string {
+	limit := s.GetStorageQuotaBytes()
+	if limit <= 0 {
+		return ""
+	}
+	logCtx := logging.WithComponent(ctx, "storage-quota")
+
+	used, err := strategy.EntireStorageBytes(ctx)
+	if err != nil {
+		logging.Warn(logCtx, "failed to measure storage", slog.String("error", err.Error()))
+		return ""
+	}
+	if used <= limit {
+		return ""
+	}
+
+	if s.StorageQuota.GetAction() == settings.StorageQuotaActionGC {
+		logging.Info(logCtx, "storage quota exceeded, pruning",
+			slog.Int64("used_bytes", used),
+			slog.Int64("limit_bytes", limit))
+		policy := s.GetGC()
+		if s.GetCheckpointStore() != nil {
+			policy = settings.GCSettings{Merged: policy.Merged} // Checkpoints in a bucket aren't pruned here
+		}
+		if err := pruneEntireData(ctx, io.Discard, policy, false, time.Now()); err != nil {
+			logging.Warn(logCtx, "failed to prune", slog.String("error", err.Error()))
+		}
+		if used, err = strategy.EntireStorageBytes(ctx); err != nil || used <= limit {
+			return ""
+		}
+	}
+
+	logging.Warn(logCtx, "storage quota exceeded, not saving checkpoint",
+		slog.Int64("used_bytes", used),
+		slog.Int64("limit_bytes", limit))
+	return fmt.Sprintf("Entire storage quota exceeded: %d MiB used of %d MiB. "+
+		"Checkpoints are not saved until space is freed; run 'entire gc' (see 'entire gc --help') "+
+		"or raise storage_quota.max_mb.", used>>20, limit>>20)
+}
@@ -0,0 +1,87 @@
+package cli
+
+import (
+	"context"
+	"crypto/rand"
+	"strings"
+	"testing"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/settings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// writeLargeShadowBranch creates a shadow branch holding size bytes of
+// incompressible data that no user branch reaches.
+func writeLargeShadowBranch(t *testing.T, repo *git.Repository, parent plumbing.Hash, branch string, size int) {
+	t.Helper()
+	data := make([]byte, size)
+	if _, err := rand.Read(data); err != nil {
+		t.Fatalf("failed to generate data: %v", err)
+	}
+	blobHash, err := checkpoint.CreateBlobFromContent(repo, data)
+	if err != nil {
+		t.Fatalf("failed to create blob: %v", err)
+	}
+	treeHash, err := checkpoint.BuildTreeFromEntries(repo, map[string]object.TreeEntry{
+		"big.bin": {Name: "big.bin", Mode: filemode.Regular, Hash: blobHash},
+	})
+	if err != nil {
+		t.Fatalf("failed to build tree: %v", err)
+	}
+	sig := object.Signature{Name: "test", Email: "test@test.com"}
+	commit := &object.Commit{TreeHash: treeHash, ParentHashes: []plumbing.Hash{parent}, Author: sig, Committer: sig, Message: "checkpoint"}
+	obj := repo.Storer.NewEncodedObject()
+	if err := commit.Encode(obj); err != nil {
+		t.Fatalf("failed to encode commit: %v", err)
+	}
+	commitHash, err := repo.Storer.SetEncodedObject(obj)
+	if err != nil {
+		t.Fatalf("failed to store commit: %v", err)
+	}
+	if err := repo.Storer.SetReference(plumbing.NewHashReference(plumbing.NewBranchReferenceName(branch), commitHash)); err != nil {
+		t.Fatalf("failed to create %s: %v", branch, err)
+	}
+}
+
+func TestCheckStorageQuota(t *testing.T) {
+	repo, head := setupCleanTestRepo(t)
+	ctx := context.Background()
+	writeLargeShadowBranch(t, repo, head, "entire/abcdef1-123456", 2<<20)
+
+	if msg := checkStorageQuota(ctx, &EntireSettings{}); msg != "" {
+		t.Errorf("checkStorageQuota() without a quota = %q, want none", msg)
+	}
+	roomy := &EntireSettings{StorageQuota: &settings.StorageQuotaSettings{MaxMB: 10}}
+	if msg := checkStorageQuota(ctx, roomy); msg != "" {
+		t.Errorf("checkStorageQuota() under the quota = %q, want none", msg)
+	}
+
+	tight := &EntireSettings{StorageQuota: &settings.StorageQuotaSettings{MaxMB: 1}}
+	msg := checkStorageQuota(ctx, tight)
+	if !strings.Contains(msg, "storage quota exceeded") || !strings.Contains(msg, "of 1 MiB") {
+		t.Errorf("checkStorageQuota() over the quota = %q, want a quota message", msg)
+	}
+	if _, err := repo.Reference(plumbing.NewBranchReferenceName("entire/abcdef1-123456"), true); err != nil {
+		t.Errorf("the block action should leave the shadow branch alone: %v", err)
+	}
+}
+
+func TestCheckStorageQuota_GCFreesSpace(t *testing.T) {
+	repo, head := setupCleanTestRepo(t)
+	ctx := context.Background()
+	// No session uses the branch, so gc deletes it
+	writeLargeShadowBranch(t, repo, head, "entire/abcdef1-123456", 2<<20)
+
+	s := &EntireSettings{StorageQuota: &settings.StorageQuotaSettings{MaxMB: 1, Action: settings.StorageQuotaActionGC}}
+	if msg := checkStorageQuota(ctx, s); msg != "" {
+		t.Errorf("checkStorageQuota() = %q, want pruning to get under the quota", msg)
+	}
+	if _, err := repo.Reference(plumbing.NewBranchReferenceName("entire/abcdef1-123456"), true); err == nil {
+		t.Error("the gc action should have deleted the orphaned shadow branch")
+	}
+}
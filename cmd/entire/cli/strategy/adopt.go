@@ -0,0 +1,202 @@
+package strategy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+	"github.com/entireio/cli/cmd/entire/cli/session"
+	"github.com/entireio/cli/cmd/entire/cli/trailers"
+	"github.com/entireio/cli/cmd/entire/cli/versioninfo"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// AdoptResult reports what AdoptRemoteRefs set up locally.
+type AdoptResult struct {
+	Remote string
+
+	// Metadata describes the local entire/checkpoints/v1 branch: "created",
+	// "updated", "up to date", "diverged" (left for `entire sync` to merge),
+	// or "" if the remote doesn't have it.
+	Metadata string
+
+	// ShadowBranches are the remote shadow branches created locally.
+	ShadowBranches []string
+
+	// Skipped are remote shadow branches left alone because a local branch
+	// of the same name points elsewhere.
+	Skipped []string
+
+	// Sessions are the IDs of the session stubs created from the adopted
+	// shadow branches.
+	Sessions []string
+}
+
+// AdoptRemoteRefs fetches Entire's branches (entire/*) from remote into
+// remote-tracking refs and creates the local branches that are missing, so a
+// fresh clone sees the checkpoints colleagues pushed. Local branches are
+// never moved except to fast-forward the metadata branch.
+//
+// For each adopted shadow branch, an ended session state is reconstructed
+// from its commits so the sessions can be browsed. The stubs have no
+// worktree path, so hooks never resume or condense them.
+func AdoptRemoteRefs(ctx context.Context, remote string) (*AdoptResult, error) {
+	repo, err := OpenRepository(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open git repository: %w", err)
+	}
+
+	refSpec := fmt.Sprintf("+refs/heads/%s*:refs/remotes/%s/%s*", checkpoint.ShadowBranchPrefix, remote, checkpoint.ShadowBranchPrefix)
+	err = retryTransient(ctx, func() error {
+		_, err := runSyncGit(ctx, "fetch", "--no-write-fetch-head", remote, refSpec)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("fetch from %s failed: %w", remote, err)
+	}
+
+	result := &AdoptResult{Remote: remote}
+	if result.Metadata, err = adoptMetadataBranch(repo, remote); err != nil {
+		return nil, err
+	}
+
+	refs, err := repo.References()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get references: %w", err)
+	}
+	trackingPrefix := "refs/remotes/" + remote + "/"
+	var tracking []*plumbing.Reference
+	err = refs.ForEach(func(ref *plumbing.Reference) error {
+		name := strings.TrimPrefix(ref.Name().String(), trackingPrefix)
+		if name != ref.Name().String() && IsShadowBranch(name) {
+			tracking = append(tracking, ref)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to iterate references: %w", err)
+	}
+
+	stateStore, err := session.NewStateStore(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create state store: %w", err)
+	}
+	for _, ref := range tracking {
+		name := strings.TrimPrefix(ref.Name().String(), trackingPrefix)
+		branchRef := plumbing.NewBranchReferenceName(name)
+		if local, err := repo.Reference(branchRef, true); err == nil {
+			if local.Hash() != ref.Hash() {
+				result.Skipped = append(result.Skipped, name)
+			}
+			continue
+		}
+		if err := repo.Storer.SetReference(plumbing.NewHashReference(branchRef, ref.Hash())); err != nil {
+			return nil, fmt.Errorf("failed to create %s: %w", name, err)
+		}
+		result.ShadowBranches = append(result.ShadowBranches, name)
+
+		stubs, err := sessionStubsFromShadowBranch(repo, name, ref.Hash())
+		if err != nil {
+			continue // Browsing the branch still works without stubs
+		}
+		for _, stub := range stubs {
+			if existing, err := stateStore.Load(ctx, stub.SessionID); err != nil || existing != nil {
+				continue
+			}
+			if err := stateStore.Save(ctx, stub); err != nil {
+				return nil, fmt.Errorf("failed to save session %s: %w", stub.SessionID, err)
+			}
+			result.Sessions = append(result.Sessions, stub.SessionID)
+		}
+	}
+	return result, nil
+}
+
+// adoptMetadataBranch creates or fast-forwards the local metadata branch
+// from its remote-tracking ref.
+func adoptMetadataBranch(repo *git.Repository, remote string) (string, error) {
+	remoteRef, err := repo.Reference(plumbing.NewRemoteReferenceName(remote, paths.MetadataBranchName), true)
+	if err != nil {
+		return "", nil //nolint:nilerr // The remote has no checkpoints yet
+	}
+	branchRef := plumbing.NewBranchReferenceName(paths.MetadataBranchName)
+	local, err := repo.Reference(branchRef, true)
+	switch {
+	case err != nil:
+		if err := repo.Storer.SetReference(plumbing.NewHashReference(branchRef, remoteRef.Hash())); err != nil {
+			return "", fmt.Errorf("failed to create %s: %w", paths.MetadataBranchName, err)
+		}
+		return "created", nil
+	case local.Hash() == remoteRef.Hash() || isAncestorCommit(repo, remoteRef.Hash(), local.Hash()):
+		return "up to date", nil
+	case isAncestorCommit(repo, local.Hash(), remoteRef.Hash()):
+		if err := repo.Storer.SetReference(plumbing.NewHashReference(branchRef, remoteRef.Hash())); err != nil {
+			return "", fmt.Errorf("failed to update %s: %w", paths.MetadataBranchName, err)
+		}
+		return "updated", nil
+	default:
+		return "diverged", nil
+	}
+}
+
+// sessionStubsFromShadowBranch reconstructs ended session states from the
+// checkpoint commits of a shadow branch. Branches of linked worktrees and of
+// base commits that aren't in this clone yield no stubs: a stub must name
+// the branch through its base commit and worktree, or session listing
+// discards it.
+func sessionStubsFromShadowBranch(repo *git.Repository, branchName string, tip plumbing.Hash) ([]*session.State, error) {
+	commitPrefix, worktreeHash, ok := checkpoint.ParseShadowBranchName(branchName)
+	if !ok || worktreeHash != checkpoint.HashWorktreeID("") {
+		return nil, nil
+	}
+	baseHash, err := repo.ResolveRevision(plumbing.Revision(commitPrefix))
+	if err != nil {
+		return nil, fmt.Errorf("base commit %s not found: %w", commitPrefix, err)
+	}
+
+	stubs := make(map[string]*session.State)
+	var order []string
+	iter, err := repo.Log(&git.LogOptions{From: tip})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commit log: %w", err)
+	}
+	err = iter.ForEach(func(c *object.Commit) error {
+		sessionID, found := trailers.ParseSession(c.Message)
+		if !found {
+			return nil
+		}
+		when := c.Author.When
+		stub, seen := stubs[sessionID]
+		if !seen {
+			// The log runs newest first, so the first commit seen is the last step
+			ended := when
+			stub = &session.State{
+				SessionID:  sessionID,
+				CLIVersion: versioninfo.Version,
+				BaseCommit: baseHash.String(),
+				Phase:      session.PhaseEnded,
+				EndedAt:    &ended,
+			}
+			stubs[sessionID] = stub
+			order = append(order, sessionID)
+		}
+		stub.StartedAt = when
+		stub.StepCount++
+		return nil
+	})
+	if err != nil && !errors.Is(err, plumbing.ErrObjectNotFound) {
+		return nil, fmt.Errorf("failed to walk %s: %w", branchName, err)
+	}
+
+	result := make([]*session.State, 0, len(order))
+	for _, sessionID := range order {
+		result = append(result, stubs[sessionID])
+	}
+	return result, nil
+}
@@ -0,0 +1,125 @@
+package strategy
+
+import (
+	"context"
+	"os/exec"
+	"testing"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+	"github.com/entireio/cli/cmd/entire/cli/session"
+	"github.com/entireio/cli/cmd/entire/cli/trailers"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdoptRemoteRefs(t *testing.T) {
+	// The "remote" is a repo with checkpoints and a shadow branch for HEAD
+	remoteDir := setupGitRepo(t)
+	remoteRepo, err := git.PlainOpen(remoteDir)
+	require.NoError(t, err)
+	head, err := remoteRepo.Head()
+	require.NoError(t, err)
+	headCommit, err := remoteRepo.CommitObject(head.Hash())
+	require.NoError(t, err)
+
+	tip := head.Hash()
+	for _, sessionID := range []string{"session-1", "session-1", "session-2"} {
+		msg := trailers.FormatShadowCommit("Checkpoint", ".entire/metadata/"+sessionID, sessionID)
+		tip, err = createMergeCommitCommon(remoteRepo, headCommit.TreeHash, []plumbing.Hash{tip}, msg)
+		require.NoError(t, err)
+	}
+	shadowBranch := checkpoint.ShadowBranchNameForCommit(head.Hash().String(), "")
+	require.NoError(t, remoteRepo.Storer.SetReference(
+		plumbing.NewHashReference(plumbing.NewBranchReferenceName(shadowBranch), tip)))
+	metadataTip := addMetadataCommits(t, remoteRepo, "cp", 2)
+
+	cloneDir := t.TempDir()
+	out, err := exec.CommandContext(context.Background(), "git", "clone", "-q", remoteDir, cloneDir).CombinedOutput()
+	require.NoError(t, err, string(out))
+	t.Chdir(cloneDir)
+	ctx := context.Background()
+
+	result, err := AdoptRemoteRefs(ctx, "origin")
+	require.NoError(t, err)
+	assert.Equal(t, "created", result.Metadata)
+	assert.Equal(t, []string{shadowBranch}, result.ShadowBranches)
+	assert.Equal(t, []string{"session-2", "session-1"}, result.Sessions, "most recent first")
+
+	repo, err := git.PlainOpen(cloneDir)
+	require.NoError(t, err)
+	ref, err := repo.Reference(plumbing.NewBranchReferenceName(paths.MetadataBranchName), true)
+	require.NoError(t, err)
+	assert.Equal(t, metadataTip, ref.Hash())
+
+	store, err := session.NewStateStore(ctx)
+	require.NoError(t, err)
+	state, err := store.Load(ctx, "session-1")
+	require.NoError(t, err)
+	require.NotNil(t, state)
+	assert.Equal(t, head.Hash().String(), state.BaseCommit)
+	assert.Equal(t, 2, state.StepCount)
+	assert.Equal(t, session.PhaseEnded, state.Phase)
+	assert.Empty(t, state.WorktreePath, "stubs must not be picked up by hooks")
+
+	// The remote moves on; adopting again fast-forwards and creates nothing new
+	newMetadataTip := addMetadataCommits(t, remoteRepo, "more", 1)
+	result, err = AdoptRemoteRefs(ctx, "origin")
+	require.NoError(t, err)
+	assert.Equal(t, "updated", result.Metadata)
+	assert.Empty(t, result.ShadowBranches)
+	assert.Empty(t, result.Sessions)
+	ref, err = repo.Reference(plumbing.NewBranchReferenceName(paths.MetadataBranchName), true)
+	require.NoError(t, err)
+	assert.Equal(t, newMetadataTip, ref.Hash())
+
+	// Both sides add checkpoints: left for sync to merge
+	addMetadataCommits(t, remoteRepo, "remote", 1)
+	localTip := addMetadataCommits(t, repo, "local", 1)
+	result, err = AdoptRemoteRefs(ctx, "origin")
+	require.NoError(t, err)
+	assert.Equal(t, "diverged", result.Metadata)
+	ref, err = repo.Reference(plumbing.NewBranchReferenceName(paths.MetadataBranchName), true)
+	require.NoError(t, err)
+	assert.Equal(t, localTip, ref.Hash(), "a diverged branch is not moved")
+}
+
+func TestAdoptRemoteRefs_KeepsDifferingLocalShadowBranch(t *testing.T) {
+	remoteDir := setupGitRepo(t)
+	remoteRepo, err := git.PlainOpen(remoteDir)
+	require.NoError(t, err)
+	head, err := remoteRepo.Head()
+	require.NoError(t, err)
+	headCommit, err := remoteRepo.CommitObject(head.Hash())
+	require.NoError(t, err)
+
+	msg := trailers.FormatShadowCommit("Checkpoint", ".entire/metadata/remote-session", "remote-session")
+	remoteTip, err := createMergeCommitCommon(remoteRepo, headCommit.TreeHash, []plumbing.Hash{head.Hash()}, msg)
+	require.NoError(t, err)
+	shadowBranch := checkpoint.ShadowBranchNameForCommit(head.Hash().String(), "")
+	branchRef := plumbing.NewBranchReferenceName(shadowBranch)
+	require.NoError(t, remoteRepo.Storer.SetReference(plumbing.NewHashReference(branchRef, remoteTip)))
+
+	cloneDir := t.TempDir()
+	out, err := exec.CommandContext(context.Background(), "git", "clone", "-q", remoteDir, cloneDir).CombinedOutput()
+	require.NoError(t, err, string(out))
+	t.Chdir(cloneDir)
+	repo, err := git.PlainOpen(cloneDir)
+	require.NoError(t, err)
+	// The local session checkpointed on the same base commit
+	require.NoError(t, repo.Storer.SetReference(plumbing.NewHashReference(branchRef, head.Hash())))
+
+	result, err := AdoptRemoteRefs(context.Background(), "origin")
+	require.NoError(t, err)
+	assert.Empty(t, result.Metadata, "the remote has no metadata branch")
+	assert.Empty(t, result.ShadowBranches)
+	assert.Equal(t, []string{shadowBranch}, result.Skipped)
+	assert.Empty(t, result.Sessions)
+
+	ref, err := repo.Reference(branchRef, true)
+	require.NoError(t, err)
+	assert.Equal(t, head.Hash(), ref.Hash())
+}
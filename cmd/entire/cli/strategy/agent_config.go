@@ -0,0 +1,101 @@
+package strategy
+
+import (
+	"context"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/entireio/cli/cmd/entire/cli/agent"
+	"github.com/entireio/cli/cmd/entire/cli/logging"
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// maxAgentConfigFileSize skips unusually large files under config directories.
+const maxAgentConfigFileSize = 1 << 20
+
+// readAgentConfigFiles reads the agent's config files from the worktree, keyed
+// by repo-relative slash path. Returns nil if the agent doesn't declare any or
+// none exist.
+func readAgentConfigFiles(ctx context.Context, ag agent.Agent) map[string][]byte {
+	provider, ok := ag.(agent.ConfigFileProvider)
+	if !ok {
+		return nil
+	}
+	return readWorktreeFiles(ctx, provider.ConfigFiles())
+}
+
+// readInstructionVersions returns the git blob hash of each of the agent's
+// instruction files in the worktree, keyed by repo-relative slash path.
+// Returns nil if the agent doesn't declare any or none exist.
+func readInstructionVersions(ctx context.Context, ag agent.Agent) map[string]string {
+	provider, ok := ag.(agent.InstructionFileProvider)
+	if !ok {
+		return nil
+	}
+	files := readWorktreeFiles(ctx, provider.InstructionFiles())
+	if len(files) == 0 {
+		return nil
+	}
+	versions := make(map[string]string, len(files))
+	for path, content := range files {
+		versions[path] = plumbing.ComputeHash(plumbing.BlobObject, content).String()
+	}
+	return versions
+}
+
+// readWorktreeFiles reads the files at the given repo-relative paths, keyed
+// by slash path. Directories include every file beneath them; missing,
+// unreadable, and oversized files are skipped.
+func readWorktreeFiles(ctx context.Context, configPaths []string) map[string][]byte {
+	repoRoot, err := paths.WorktreeRoot(ctx)
+	if err != nil {
+		return nil
+	}
+
+	var files map[string][]byte
+	addFile := func(absPath string) {
+		info, statErr := os.Stat(absPath)
+		if statErr != nil || !info.Mode().IsRegular() || info.Size() > maxAgentConfigFileSize {
+			return
+		}
+		content, readErr := os.ReadFile(absPath) //nolint:gosec // path is under the repo root
+		if readErr != nil {
+			logging.Debug(ctx, "failed to read agent config file",
+				slog.String("path", absPath),
+				slog.String("error", readErr.Error()))
+			return
+		}
+		rel, relErr := filepath.Rel(repoRoot, absPath)
+		if relErr != nil {
+			return
+		}
+		if files == nil {
+			files = make(map[string][]byte)
+		}
+		files[filepath.ToSlash(rel)] = content
+	}
+
+	for _, configPath := range configPaths {
+		absPath := filepath.Join(repoRoot, filepath.FromSlash(configPath))
+		info, statErr := os.Stat(absPath)
+		if statErr != nil {
+			continue
+		}
+		if !info.IsDir() {
+			addFile(absPath)
+			continue
+		}
+		//nolint:errcheck // best-effort: unreadable entries are skipped
+		_ = filepath.WalkDir(absPath, func(path string, d fs.DirEntry, walkErr error) error {
+			if walkErr == nil && !d.IsDir() {
+				addFile(path)
+			}
+			return nil
+		})
+	}
+	return files
+}
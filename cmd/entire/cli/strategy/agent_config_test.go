@@ -0,0 +1,57 @@
+package strategy
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/entireio/cli/cmd/entire/cli/agent/claudecode"
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+func TestReadAgentConfigFiles(t *testing.T) {
+	dir := t.TempDir()
+	initTestRepo(t, dir)
+	t.Chdir(dir)
+	paths.ClearWorktreeRootCache()
+
+	for path, content := range map[string]string{
+		"CLAUDE.md":                "Use tabs.\n",
+		".claude/settings.json":    "{}\n",
+		".claude/commands/ship.md": "Ship it.\n",
+		"other.md":                 "not config\n",
+	} {
+		abs := filepath.Join(dir, filepath.FromSlash(path))
+		if err := os.MkdirAll(filepath.Dir(abs), 0o755); err != nil {
+			t.Fatalf("failed to create dir: %v", err)
+		}
+		if err := os.WriteFile(abs, []byte(content), 0o644); err != nil {
+			t.Fatalf("failed to write %s: %v", path, err)
+		}
+	}
+
+	files := readAgentConfigFiles(context.Background(), &claudecode.ClaudeCodeAgent{})
+	if len(files) != 3 {
+		t.Fatalf("readAgentConfigFiles() returned %d files, want 3: %v", len(files), files)
+	}
+	if string(files["CLAUDE.md"]) != "Use tabs.\n" || string(files[".claude/commands/ship.md"]) != "Ship it.\n" {
+		t.Errorf("unexpected config contents: %v", files)
+	}
+	if _, ok := files["other.md"]; ok {
+		t.Error("other.md is not an agent config file")
+	}
+
+	if files := readAgentConfigFiles(context.Background(), nil); files != nil {
+		t.Errorf("readAgentConfigFiles(nil) = %v, want nil", files)
+	}
+
+	// Instruction files are a subset, recorded by blob hash
+	versions := readInstructionVersions(context.Background(), &claudecode.ClaudeCodeAgent{})
+	want := plumbing.ComputeHash(plumbing.BlobObject, []byte("Use tabs.\n")).String()
+	if len(versions) != 1 || versions["CLAUDE.md"] != want {
+		t.Errorf("readInstructionVersions() = %v, want CLAUDE.md at %s", versions, want)
+	}
+}
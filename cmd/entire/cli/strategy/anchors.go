@@ -0,0 +1,124 @@
+package strategy
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/entireio/cli/cmd/entire/cli/session"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// AnchorRefPrefix is the ref namespace that keeps the commits session state
+// refers to reachable. Session state names its base commits by hash only, so
+// once the user's branches stop reaching them (a rebase, a deleted branch),
+// `git gc --prune` would remove them and attribution would break. Each
+// session gets refs/entire/anchors/<session-id>/base and, if it differs,
+// refs/entire/anchors/<session-id>/attribution.
+const AnchorRefPrefix = "refs/entire/anchors/"
+
+// AnchorResult reports what AnchorSessionCommits changed.
+type AnchorResult struct {
+	// Anchored is the number of anchor refs created or moved.
+	Anchored int
+
+	// Released is the number of anchor refs removed because their session
+	// no longer has state.
+	Released int
+}
+
+func anchorRefName(sessionID, kind string) plumbing.ReferenceName {
+	return plumbing.ReferenceName(AnchorRefPrefix + sessionID + "/" + kind)
+}
+
+// sessionAnchors returns the anchor refs a session state needs, and the
+// commits they point at.
+func sessionAnchors(state *session.State) map[plumbing.ReferenceName]string {
+	anchors := make(map[plumbing.ReferenceName]string)
+	if state.BaseCommit != "" {
+		anchors[anchorRefName(state.SessionID, "base")] = state.BaseCommit
+	}
+	if state.AttributionBaseCommit != "" && state.AttributionBaseCommit != state.BaseCommit {
+		anchors[anchorRefName(state.SessionID, "attribution")] = state.AttributionBaseCommit
+	}
+	return anchors
+}
+
+// anchorSessionCommits points the session's anchor refs at the commits its
+// state refers to. Commits that are already gone are skipped. Returns how
+// many refs were created or moved.
+func anchorSessionCommits(repo *git.Repository, state *session.State) (int, error) {
+	anchored := 0
+	for refName, commit := range sessionAnchors(state) {
+		hash := plumbing.NewHash(commit)
+		if ref, err := repo.Reference(refName, false); err == nil && ref.Hash() == hash {
+			continue
+		}
+		if _, err := repo.CommitObject(hash); err != nil {
+			continue
+		}
+		if err := repo.Storer.SetReference(plumbing.NewHashReference(refName, hash)); err != nil {
+			return anchored, fmt.Errorf("failed to set %s: %w", refName, err)
+		}
+		anchored++
+	}
+	return anchored, nil
+}
+
+// AnchorSessionCommits makes sure every commit a session state refers to is
+// reachable from an anchor ref, and removes the anchors of sessions that no
+// longer have state. Run it before `git gc` so pruning can't remove commits
+// sessions still need.
+func AnchorSessionCommits(ctx context.Context) (*AnchorResult, error) {
+	repo, err := OpenRepository(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open git repository: %w", err)
+	}
+	stateStore, err := session.NewStateStore(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create state store: %w", err)
+	}
+	states, err := stateStore.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list session states: %w", err)
+	}
+
+	result := &AnchorResult{}
+	wanted := make(map[plumbing.ReferenceName]bool)
+	for _, state := range states {
+		anchored, err := anchorSessionCommits(repo, state)
+		result.Anchored += anchored
+		if err != nil {
+			return result, err
+		}
+		for refName := range sessionAnchors(state) {
+			wanted[refName] = true
+		}
+	}
+
+	refs, err := repo.References()
+	if err != nil {
+		return result, fmt.Errorf("failed to get references: %w", err)
+	}
+	var stale []plumbing.ReferenceName
+	err = refs.ForEach(func(ref *plumbing.Reference) error {
+		if strings.HasPrefix(ref.Name().String(), AnchorRefPrefix) && !wanted[ref.Name()] {
+			stale = append(stale, ref.Name())
+		}
+		return nil
+	})
+	if err != nil {
+		return result, fmt.Errorf("failed to iterate references: %w", err)
+	}
+	for _, refName := range stale {
+		// Delete via CLI: refs are packed by `git gc`, and go-git v5's
+		// RemoveReference doesn't persist deletions of packed refs reliably
+		if _, err := runSyncGit(ctx, "update-ref", "-d", refName.String()); err != nil {
+			return result, fmt.Errorf("failed to remove %s: %w", refName, err)
+		}
+		result.Released++
+	}
+	return result, nil
+}
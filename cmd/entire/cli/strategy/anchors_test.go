@@ -0,0 +1,64 @@
+package strategy
+
+import (
+	"context"
+	"os/exec"
+	"testing"
+
+	"github.com/entireio/cli/cmd/entire/cli/session"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnchorSessionCommits_SurvivesGitGC(t *testing.T) {
+	dir := setupGitRepo(t)
+	t.Chdir(dir)
+	ctx := context.Background()
+	repo, err := git.PlainOpen(dir)
+	require.NoError(t, err)
+	head, err := repo.Head()
+	require.NoError(t, err)
+	headCommit, err := repo.CommitObject(head.Hash())
+	require.NoError(t, err)
+
+	// A base commit no branch reaches any more, e.g. after a rebase
+	dangling, err := createMergeCommitCommon(repo, headCommit.TreeHash, []plumbing.Hash{head.Hash()}, "rebased away")
+	require.NoError(t, err)
+
+	store, err := session.NewStateStore(ctx)
+	require.NoError(t, err)
+	require.NoError(t, store.Save(ctx, &session.State{
+		SessionID:             "session-1",
+		BaseCommit:            dangling.String(),
+		AttributionBaseCommit: head.Hash().String(),
+	}))
+
+	result, err := AnchorSessionCommits(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Anchored)
+	assert.Equal(t, 0, result.Released)
+
+	out, err := exec.CommandContext(ctx, "git", "gc", "--prune=now", "--quiet").CombinedOutput()
+	require.NoError(t, err, string(out))
+	repo, err = git.PlainOpen(dir)
+	require.NoError(t, err)
+	_, err = repo.CommitObject(dangling)
+	require.NoError(t, err, "anchored commit should survive git gc")
+
+	result, err = AnchorSessionCommits(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, result.Anchored, "anchors are already in place")
+
+	// Once the session's state is gone, its (now packed) anchors are released
+	require.NoError(t, store.Clear(ctx, "session-1"))
+	result, err = AnchorSessionCommits(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Released)
+	repo, err = git.PlainOpen(dir)
+	require.NoError(t, err)
+	_, err = repo.Reference(anchorRefName("session-1", "base"), false)
+	require.Error(t, err)
+}
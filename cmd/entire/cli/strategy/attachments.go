@@ -0,0 +1,74 @@
+package strategy
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+
+	cpkg "github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/logging"
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+)
+
+// PendingAttachmentsDir returns the absolute directory where files attached
+// to sessionID wait for the session's next checkpoint.
+func PendingAttachmentsDir(ctx context.Context, sessionID string) (string, error) {
+	dir, err := paths.AbsPath(ctx, paths.PendingAttachmentsDirFromSessionID(sessionID))
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve attachments directory: %w", err)
+	}
+	return dir, nil
+}
+
+// readPendingAttachments reads the files staged for sessionID, sorted by
+// name. Symlinks, directories, and files over the attachment size cap are
+// skipped. Returns nil if nothing is staged.
+func readPendingAttachments(ctx context.Context, sessionID string) []cpkg.Attachment {
+	dir, err := PendingAttachmentsDir(ctx, sessionID)
+	if err != nil {
+		return nil
+	}
+	dirEntries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+
+	var attachments []cpkg.Attachment
+	for _, entry := range dirEntries {
+		if !entry.Type().IsRegular() {
+			continue
+		}
+		info, infoErr := entry.Info()
+		if infoErr != nil || info.Size() > cpkg.MaxAttachmentBytes {
+			continue
+		}
+		data, readErr := os.ReadFile(filepath.Join(dir, entry.Name())) //nolint:gosec // name comes from the staging directory listing
+		if readErr != nil {
+			logging.Warn(ctx, "failed to read staged attachment",
+				slog.String("session_id", sessionID),
+				slog.String("name", entry.Name()),
+				slog.String("error", readErr.Error()))
+			continue
+		}
+		attachments = append(attachments, cpkg.Attachment{Name: entry.Name(), Data: data})
+	}
+	sort.Slice(attachments, func(i, j int) bool { return attachments[i].Name < attachments[j].Name })
+	return attachments
+}
+
+// clearPendingAttachments removes the files staged for sessionID once they
+// have been stored in a checkpoint.
+func clearPendingAttachments(ctx context.Context, sessionID string) {
+	dir, err := PendingAttachmentsDir(ctx, sessionID)
+	if err != nil {
+		return
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		logging.Warn(ctx, "failed to clear staged attachments",
+			slog.String("session_id", sessionID),
+			slog.String("error", err.Error()))
+	}
+}
@@ -0,0 +1,30 @@
+package strategy
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/logging"
+)
+
+// RecordAudit appends an entry to the audit log on the metadata branch.
+// Auditing is best-effort: failures are logged and never abort the
+// operation being audited.
+func RecordAudit(ctx context.Context, entry checkpoint.AuditEntry) {
+	logCtx := logging.WithComponent(ctx, "audit")
+
+	repo, err := OpenRepository(ctx)
+	if err != nil {
+		logging.Warn(logCtx, "failed to open repository for audit log",
+			slog.String("operation", entry.Operation),
+			slog.String("error", err.Error()))
+		return
+	}
+
+	if err := checkpoint.NewGitStore(repo).AppendAuditEntry(ctx, entry); err != nil {
+		logging.Warn(logCtx, "failed to record audit entry",
+			slog.String("operation", entry.Operation),
+			slog.String("error", err.Error()))
+	}
+}
@@ -0,0 +1,57 @@
+package strategy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/objstore"
+	"github.com/entireio/cli/cmd/entire/cli/settings"
+)
+
+// CommittedStore returns the store committed checkpoints are written to and
+// read from: local itself, or an object store when the checkpoint_store
+// setting names a bucket. Temporary checkpoints always use local.
+func CommittedStore(ctx context.Context, local *checkpoint.GitStore) (checkpoint.CommittedStore, error) {
+	s, err := settings.Load(ctx)
+	if err != nil {
+		return local, nil //nolint:nilerr // unreadable settings configure nothing, as in contentLevel
+	}
+	cfg := s.GetCheckpointStore()
+	if cfg == nil {
+		return local, nil
+	}
+	bucket, err := objstore.Open(objstore.Config{URL: cfg.URL, Region: cfg.Region, Endpoint: cfg.Endpoint})
+	if err != nil {
+		return nil, fmt.Errorf("invalid checkpoint_store setting: %w", err)
+	}
+	return checkpoint.NewObjectStore(local, bucket), nil
+}
+
+// ErrBranchStoreRequired is returned by operations that only work on the
+// entire/checkpoints/v1 branch when the checkpoint_store setting keeps
+// committed checkpoints in an object store.
+var ErrBranchStoreRequired = errors.New("not supported when checkpoint_store is set: committed checkpoints are in the object store, not on the entire/checkpoints/v1 branch")
+
+// RequireBranchStore returns ErrBranchStoreRequired, naming the operation,
+// when committed checkpoints are kept in an object store.
+func RequireBranchStore(ctx context.Context, operation string) error {
+	s, err := settings.Load(ctx)
+	if err != nil {
+		return nil //nolint:nilerr // unreadable settings configure nothing, as in CommittedStore
+	}
+	if s.GetCheckpointStore() != nil {
+		return fmt.Errorf("%s: %w", operation, ErrBranchStoreRequired)
+	}
+	return nil
+}
+
+// getCommittedStore returns CommittedStore for the strategy's checkpoint store.
+func (s *ManualCommitStrategy) getCommittedStore(ctx context.Context) (checkpoint.CommittedStore, error) {
+	local, err := s.getCheckpointStore()
+	if err != nil {
+		return nil, err
+	}
+	return CommittedStore(ctx, local)
+}
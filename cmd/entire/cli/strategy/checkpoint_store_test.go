@@ -0,0 +1,58 @@
+package strategy
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+
+	"github.com/go-git/go-git/v5"
+)
+
+func TestCommittedStore(t *testing.T) {
+	dir := t.TempDir()
+	initTestRepo(t, dir)
+	t.Chdir(dir)
+	paths.ClearWorktreeRootCache()
+
+	repo, err := git.PlainOpen(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	local := checkpoint.NewGitStore(repo)
+
+	store, err := CommittedStore(context.Background(), local)
+	if err != nil {
+		t.Fatalf("CommittedStore() error = %v", err)
+	}
+	if store != checkpoint.Store(local) {
+		t.Errorf("without checkpoint_store, CommittedStore() = %T, want the local GitStore", store)
+	}
+	if err := RequireBranchStore(context.Background(), "entire migrate"); err != nil {
+		t.Errorf("without checkpoint_store, RequireBranchStore() error = %v", err)
+	}
+
+	settingsDir := filepath.Join(dir, ".entire")
+	if err := os.MkdirAll(settingsDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	settingsJSON := `{"enabled": true, "checkpoint_store": {"url": "file://` + filepath.ToSlash(t.TempDir()) + `"}}`
+	if err := os.WriteFile(filepath.Join(settingsDir, "settings.json"), []byte(settingsJSON), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	store, err = CommittedStore(context.Background(), local)
+	if err != nil {
+		t.Fatalf("CommittedStore() error = %v", err)
+	}
+	if _, ok := store.(*checkpoint.ObjectStore); !ok {
+		t.Errorf("with checkpoint_store, CommittedStore() = %T, want *checkpoint.ObjectStore", store)
+	}
+	if err := RequireBranchStore(context.Background(), "entire migrate"); !errors.Is(err, ErrBranchStoreRequired) {
+		t.Errorf("with checkpoint_store, RequireBranchStore() error = %v, want ErrBranchStoreRequired", err)
+	}
+}
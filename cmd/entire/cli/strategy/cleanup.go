@@ -167,10 +167,12 @@ func ListOrphanedSessionStates(ctx context.Context) ([]CleanupItem, error) {
 	}
 
 	// Get all checkpoints to find which sessions have checkpoints
-	cpStore := checkpoint.NewGitStore(repo)
-
 	sessionsWithCheckpoints := make(map[string]bool)
-	checkpoints, listErr := cpStore.ListCommitted(ctx)
+	cpStore, listErr := CommittedStore(ctx, checkpoint.NewGitStore(repo))
+	var checkpoints []checkpoint.CommittedInfo
+	if listErr == nil {
+		checkpoints, listErr = cpStore.ListCommitted(ctx)
+	}
 	if listErr == nil {
 		for _, cp := range checkpoints {
 			sessionsWithCheckpoints[cp.SessionID] = true
@@ -468,6 +470,16 @@ func DeleteAllCleanupItems(ctx context.Context, items []CleanupItem) (*CleanupRe
 
 	// Log summary
 	totalDeleted := len(result.ShadowBranches) + len(result.SessionStates) + len(result.Checkpoints)
+	if totalDeleted > 0 {
+		removed := make([]string, 0, totalDeleted)
+		removed = append(removed, result.ShadowBranches...)
+		removed = append(removed, result.SessionStates...)
+		removed = append(removed, result.Checkpoints...)
+		RecordAudit(ctx, checkpoint.AuditEntry{
+			Operation: checkpoint.AuditOpClean,
+			Removed:   removed,
+		})
+	}
 	totalFailed := len(result.FailedBranches) + len(result.FailedStates) + len(result.FailedCheckpoints)
 	if totalDeleted > 0 || totalFailed > 0 {
 		logging.Info(logCtx, "cleanup completed",
@@ -0,0 +1,160 @@
+package strategy
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// ShadowArchiveRefPrefix is the ref namespace where `entire gc --merged`
+// keeps the shadow branches it removes, so their rewind points can still be
+// recovered: shadow branch entire/<commit>-<worktree> is archived as
+// refs/entire/shadow-archive/<commit>-<worktree>.
+const ShadowArchiveRefPrefix = "refs/entire/shadow-archive/"
+
+// MergedShadowBranch is a shadow branch whose base commit nobody works on
+// anymore, because its branch was merged or deleted.
+type MergedShadowBranch struct {
+	Branch     string
+	BaseCommit plumbing.Hash
+	Reason     string
+}
+
+// ListMergedShadowBranches returns the shadow branches of states whose base
+// commit is done with: it's in the default branch, or no branch reaches it
+// anymore (the branch was deleted, e.g. after a squash merge). A base commit
+// that is still HEAD or a branch tip is kept, and so are the shadow branches
+// of sessions in the middle of a turn. Shadow branches no session uses are
+// left to the orphan cleanup.
+func ListMergedShadowBranches(ctx context.Context, states []*SessionState) ([]MergedShadowBranch, error) {
+	repo, err := OpenRepository(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open git repository: %w", err)
+	}
+	shadowBranches, err := ListShadowBranches(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	bases := make(map[string]plumbing.Hash)
+	busy := make(map[string]bool)
+	for _, state := range states {
+		if state.BaseCommit == "" {
+			continue
+		}
+		branch := checkpoint.ShadowBranchNameForCommit(state.BaseCommit, state.WorktreeID)
+		bases[branch] = plumbing.NewHash(state.BaseCommit)
+		if state.Phase.IsActive() {
+			busy[branch] = true
+		}
+	}
+
+	tips, err := branchTips(repo)
+	if err != nil {
+		return nil, err
+	}
+	if head, err := repo.Head(); err == nil {
+		tips["HEAD"] = head.Hash()
+	}
+	defaultBranch := GetDefaultBranchName(repo)
+
+	var merged []MergedShadowBranch
+	for _, branch := range shadowBranches {
+		if err := ctx.Err(); err != nil {
+			return nil, err //nolint:wrapcheck // Propagating context cancellation
+		}
+		base, ok := bases[branch]
+		if !ok || busy[branch] {
+			continue
+		}
+		if reason := baseCommitDoneReason(repo, base, tips, defaultBranch); reason != "" {
+			merged = append(merged, MergedShadowBranch{Branch: branch, BaseCommit: base, Reason: reason})
+		}
+	}
+	sort.Slice(merged, func(i, j int) bool { return merged[i].Branch < merged[j].Branch })
+	return merged, nil
+}
+
+// baseCommitDoneReason says why base is no longer worked on, or returns ""
+// if it still is. tips are the commits of HEAD and of the local and
+// remote-tracking branches, by short name.
+func baseCommitDoneReason(repo *git.Repository, base plumbing.Hash, tips map[string]plumbing.Hash, defaultBranch string) string {
+	for _, tip := range tips {
+		if tip == base {
+			return ""
+		}
+	}
+	if defaultBranch != "" {
+		for _, name := range []string{defaultBranch, "origin/" + defaultBranch} {
+			if tip, ok := tips[name]; ok && isAncestorCommit(repo, base, tip) {
+				return "merged into " + name
+			}
+		}
+	}
+	for _, tip := range tips {
+		if isAncestorCommit(repo, base, tip) {
+			return ""
+		}
+	}
+	return "branch deleted"
+}
+
+// branchTips returns the commits of the local and remote-tracking branches,
+// other than Entire's own, by short name.
+func branchTips(repo *git.Repository) (map[string]plumbing.Hash, error) {
+	refs, err := repo.References()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get references: %w", err)
+	}
+	tips := make(map[string]plumbing.Hash)
+	err = refs.ForEach(func(ref *plumbing.Reference) error {
+		if ref.Type() != plumbing.HashReference || !(ref.Name().IsBranch() || ref.Name().IsRemote()) {
+			return nil
+		}
+		name := ref.Name().Short()
+		if strings.HasPrefix(name, "entire/") || strings.Contains(name, "/entire/") {
+			return nil
+		}
+		tips[name] = ref.Hash()
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to iterate references: %w", err)
+	}
+	return tips, nil
+}
+
+// ArchiveShadowBranches points a ref under ShadowArchiveRefPrefix at each
+// branch and then deletes the branch, replacing an earlier archive of the
+// same branch. Like DeleteShadowBranches, a failing branch doesn't stop the
+// others.
+func ArchiveShadowBranches(ctx context.Context, branches []string) (archived []string, failed []string, err error) {
+	repo, err := OpenRepository(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open git repository: %w", err)
+	}
+	for _, branch := range branches {
+		ref, err := repo.Reference(plumbing.NewBranchReferenceName(branch), true)
+		if err != nil {
+			failed = append(failed, branch)
+			continue
+		}
+		archive := plumbing.ReferenceName(ShadowArchiveRefPrefix + strings.TrimPrefix(branch, checkpoint.ShadowBranchPrefix))
+		if err := repo.Storer.SetReference(plumbing.NewHashReference(archive, ref.Hash())); err != nil {
+			failed = append(failed, branch)
+			continue
+		}
+		if err := DeleteBranchCLI(ctx, branch); err != nil {
+			failed = append(failed, branch)
+			continue
+		}
+		archived = append(archived, branch)
+	}
+	return archived, failed, nil
+}
@@ -0,0 +1,95 @@
+package strategy
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+	"github.com/entireio/cli/cmd/entire/cli/session"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListMergedShadowBranches(t *testing.T) {
+	dir := setupGitRepo(t)
+	t.Chdir(dir)
+	paths.ClearWorktreeRootCache()
+	ctx := context.Background()
+
+	repo, err := git.PlainOpen(dir)
+	require.NoError(t, err)
+	wt, err := repo.Worktree()
+	require.NoError(t, err)
+	head, err := repo.Head()
+	require.NoError(t, err)
+	initial := head.Hash()
+
+	commitOn := func(branch, content string, create bool) plumbing.Hash {
+		t.Helper()
+		require.NoError(t, wt.Checkout(&git.CheckoutOptions{Branch: plumbing.NewBranchReferenceName(branch), Create: create}))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "test.txt"), []byte(content), 0o644))
+		_, err := wt.Add("test.txt")
+		require.NoError(t, err)
+		hash, err := wt.Commit(content, &git.CommitOptions{})
+		require.NoError(t, err)
+		return hash
+	}
+
+	// feature is merged into master by a fast-forward and kept
+	merged := commitOn("feature", "feature work", true)
+	featureTip := commitOn("feature", "more feature work", false)
+	require.NoError(t, repo.Storer.SetReference(plumbing.NewHashReference(plumbing.NewBranchReferenceName("master"), featureTip)))
+	// squashed was squash-merged and deleted
+	require.NoError(t, wt.Checkout(&git.CheckoutOptions{Branch: plumbing.NewBranchReferenceName("master")}))
+	deleted := commitOn("squashed", "squashed work", true)
+	// wip is still being worked on
+	require.NoError(t, wt.Checkout(&git.CheckoutOptions{Branch: plumbing.NewBranchReferenceName("master")}))
+	wip := commitOn("wip", "wip work", true)
+	require.NoError(t, wt.Checkout(&git.CheckoutOptions{Branch: plumbing.NewBranchReferenceName("master")}))
+	require.NoError(t, repo.Storer.RemoveReference(plumbing.NewBranchReferenceName("squashed")))
+
+	states := []*SessionState{
+		{SessionID: "merged", BaseCommit: merged.String(), Phase: session.PhaseEnded},
+		{SessionID: "deleted", BaseCommit: deleted.String(), Phase: session.PhaseIdle},
+		{SessionID: "wip", BaseCommit: wip.String(), Phase: session.PhaseIdle},
+		{SessionID: "mid-turn", BaseCommit: initial.String(), Phase: session.PhaseActive},
+	}
+	for _, state := range states {
+		branch := plumbing.NewBranchReferenceName(checkpoint.ShadowBranchNameForCommit(state.BaseCommit, ""))
+		require.NoError(t, repo.Storer.SetReference(plumbing.NewHashReference(branch, initial)))
+	}
+
+	got, err := ListMergedShadowBranches(ctx, states)
+	require.NoError(t, err)
+	reasons := make(map[string]string)
+	for _, m := range got {
+		reasons[m.Branch] = m.Reason
+	}
+	mergedBranch := checkpoint.ShadowBranchNameForCommit(merged.String(), "")
+	deletedBranch := checkpoint.ShadowBranchNameForCommit(deleted.String(), "")
+	assert.Equal(t, map[string]string{
+		mergedBranch:  "merged into master",
+		deletedBranch: "branch deleted",
+	}, reasons)
+
+	archived, failed, err := ArchiveShadowBranches(ctx, []string{mergedBranch, deletedBranch})
+	require.NoError(t, err)
+	assert.Empty(t, failed)
+	assert.ElementsMatch(t, []string{mergedBranch, deletedBranch}, archived)
+
+	repo, err = git.PlainOpen(dir)
+	require.NoError(t, err)
+	for _, branch := range archived {
+		_, err := repo.Reference(plumbing.NewBranchReferenceName(branch), false)
+		require.ErrorIs(t, err, plumbing.ErrReferenceNotFound, "%s should be deleted", branch)
+		ref, err := repo.Reference(plumbing.ReferenceName(ShadowArchiveRefPrefix+branch[len(checkpoint.ShadowBranchPrefix):]), false)
+		require.NoError(t, err, "%s should be archived", branch)
+		assert.Equal(t, initial, ref.Hash())
+	}
+}
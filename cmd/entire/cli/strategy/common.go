@@ -13,11 +13,13 @@ import (
 	"sync"
 	"time"
 
+	"github.com/entireio/cli/cmd/entire/cli/age"
 	"github.com/entireio/cli/cmd/entire/cli/agent"
 	"github.com/entireio/cli/cmd/entire/cli/agent/types"
 	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
 	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
 	"github.com/entireio/cli/cmd/entire/cli/paths"
+	"github.com/entireio/cli/cmd/entire/cli/settings"
 	"github.com/entireio/cli/cmd/entire/cli/trailers"
 
 	"github.com/go-git/go-git/v5"
@@ -64,7 +66,7 @@ func EnsureSetup(ctx context.Context) error {
 	if err != nil {
 		return fmt.Errorf("failed to open git repository: %w", err)
 	}
-	if err := EnsureMetadataBranch(repo); err != nil {
+	if err := EnsureMetadataBranchFrom(repo, MetadataRemote(ctx)); err != nil {
 		return fmt.Errorf("failed to ensure metadata branch: %w", err)
 	}
 
@@ -281,6 +283,12 @@ func resolveAgentType(ctxAgentType types.AgentType, state *SessionState) types.A
 // If the remote-tracking branch (origin/entire/checkpoints/v1) exists, creates the local
 // branch from it to preserve existing checkpoint data. Otherwise creates an empty orphan.
 func EnsureMetadataBranch(repo *git.Repository) error {
+	return EnsureMetadataBranchFrom(repo, settings.DefaultMetadataRemote)
+}
+
+// EnsureMetadataBranchFrom is EnsureMetadataBranch with the metadata branch
+// taken from remote's tracking branch instead of origin's.
+func EnsureMetadataBranchFrom(repo *git.Repository, remote string) error {
 	refName := plumbing.NewBranchReferenceName(paths.MetadataBranchName)
 
 	// Check if local branch already exists
@@ -293,7 +301,7 @@ func EnsureMetadataBranch(repo *git.Repository) error {
 	}
 
 	// Local branch doesn't exist — create from remote if available
-	remoteRefName := plumbing.NewRemoteReferenceName("origin", paths.MetadataBranchName)
+	remoteRefName := plumbing.NewRemoteReferenceName(remote, paths.MetadataBranchName)
 	remoteRef, remoteErr := repo.Reference(remoteRefName, true)
 	if remoteErr != nil && !errors.Is(remoteErr, plumbing.ErrReferenceNotFound) {
 		return fmt.Errorf("failed to check remote metadata branch: %w", remoteErr)
@@ -443,37 +451,42 @@ func GetMetadataBranchTree(repo *git.Repository) (*object.Tree, error) {
 }
 
 // ExtractFirstPrompt extracts and truncates the first meaningful prompt from prompt content.
-// Prompts are separated by "\n\n---\n\n". Skips empty prompts and separator-only content.
+// Prompts are separated by checkpoint.PromptSeparator. Skips empty prompts and separator-only content.
 // Returns empty string if no valid prompt is found.
 func ExtractFirstPrompt(content string) string {
 	if content == "" {
 		return ""
 	}
+	return FirstPrompt(checkpoint.SplitPrompts(content))
+}
 
-	// Prompts are separated by "\n\n---\n\n"
-	// Find the first non-empty prompt
-	prompts := strings.Split(content, "\n\n---\n\n")
-	var firstPrompt string
+// FirstPrompt returns the first meaningful prompt, truncated for display.
+// Skips empty prompts and separator-only content.
+func FirstPrompt(prompts []string) string {
 	for _, p := range prompts {
 		cleaned := strings.TrimSpace(p)
 		// Skip empty prompts or prompts that are just dashes/separators
 		if cleaned == "" || isOnlySeparators(cleaned) {
 			continue
 		}
-		firstPrompt = cleaned
-		break
+		return TruncateDescription(cleaned, MaxDescriptionLength)
 	}
-
-	if firstPrompt == "" {
-		return ""
-	}
-
-	return TruncateDescription(firstPrompt, MaxDescriptionLength)
+	return ""
 }
 
 // ReadSessionPromptFromTree reads the first meaningful prompt from a checkpoint's prompt.txt file in a git tree.
-// Returns an empty string if the prompt cannot be read.
+// Returns an empty string if the prompt cannot be read, including when it is encrypted.
 func ReadSessionPromptFromTree(tree *object.Tree, checkpointPath string) string {
+	// Committed checkpoints also store prompts individually
+	if file, err := tree.File(checkpointPath + "/" + paths.PromptsFileName); err == nil {
+		if content, err := file.Contents(); err == nil {
+			var prompts []string
+			if json.Unmarshal([]byte(content), &prompts) == nil {
+				return FirstPrompt(prompts)
+			}
+		}
+	}
+
 	promptPath := checkpointPath + "/" + paths.PromptFileName
 	file, err := tree.File(promptPath)
 	if err != nil {
@@ -481,7 +494,7 @@ func ReadSessionPromptFromTree(tree *object.Tree, checkpointPath string) string
 	}
 
 	content, err := file.Contents()
-	if err != nil {
+	if err != nil || age.IsEncrypted([]byte(content)) {
 		return ""
 	}
 
@@ -561,9 +574,9 @@ func ReadAllSessionPromptsFromTree(tree *object.Tree, checkpointPath string, ses
 	return prompts
 }
 
-// GetRemoteMetadataBranchTree returns the tree object for origin/entire/checkpoints/v1.
-func GetRemoteMetadataBranchTree(repo *git.Repository) (*object.Tree, error) {
-	refName := plumbing.NewRemoteReferenceName("origin", paths.MetadataBranchName)
+// GetRemoteMetadataBranchTree returns the tree object for <remote>/entire/checkpoints/v1.
+func GetRemoteMetadataBranchTree(repo *git.Repository, remote string) (*object.Tree, error) {
+	refName := plumbing.NewRemoteReferenceName(remote, paths.MetadataBranchName)
 	ref, err := repo.Reference(refName, true)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get remote metadata branch reference: %w", err)
@@ -0,0 +1,65 @@
+package strategy
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+func TestInitializeSession_CorrelationIDFromEnv(t *testing.T) {
+	dir := t.TempDir()
+	repo, err := git.PlainInit(dir, false)
+	if err != nil {
+		t.Fatalf("failed to init repo: %v", err)
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		t.Fatalf("failed to get worktree: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("# Test"), 0o644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	if _, err := worktree.Add("README.md"); err != nil {
+		t.Fatalf("failed to stage file: %v", err)
+	}
+	if _, err := worktree.Commit("Initial commit", &git.CommitOptions{
+		Author: &object.Signature{Name: "Test", Email: "test@test.com", When: time.Now()},
+	}); err != nil {
+		t.Fatalf("failed to commit: %v", err)
+	}
+	t.Chdir(dir)
+
+	s := &ManualCommitStrategy{}
+	ctx := context.Background()
+
+	// Invalid IDs are ignored rather than failing the hook
+	t.Setenv(CorrelationIDEnvVar, "not valid/id")
+	if err := s.InitializeSession(ctx, "session-invalid", "Claude Code", "", ""); err != nil {
+		t.Fatalf("InitializeSession() error = %v", err)
+	}
+	state, err := s.loadSessionState(ctx, "session-invalid")
+	if err != nil || state == nil {
+		t.Fatalf("loadSessionState() = %v, %v", state, err)
+	}
+	if state.CorrelationID != "" {
+		t.Errorf("CorrelationID = %q, want empty for invalid env value", state.CorrelationID)
+	}
+
+	// A later turn picks up a valid ID exported after the session started
+	t.Setenv(CorrelationIDEnvVar, "PROJ-42")
+	if err := s.InitializeSession(ctx, "session-invalid", "Claude Code", "", ""); err != nil {
+		t.Fatalf("InitializeSession() second turn error = %v", err)
+	}
+	state, err = s.loadSessionState(ctx, "session-invalid")
+	if err != nil || state == nil {
+		t.Fatalf("loadSessionState() = %v, %v", state, err)
+	}
+	if state.CorrelationID != "PROJ-42" {
+		t.Errorf("CorrelationID = %q, want PROJ-42", state.CorrelationID)
+	}
+}
@@ -0,0 +1,128 @@
+package strategy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// ErrInsufficientDiskSpace is returned when a checkpoint write or rewind is
+// refused up front because the disk can't hold it.
+var ErrInsufficientDiskSpace = errors.New("not enough disk space")
+
+// diskSpaceMargin is kept free on top of an operation's estimate, for git's
+// temporary files and everything else on the disk.
+const diskSpaceMargin = 32 << 20
+
+// checkDiskSpace fails with ErrInsufficientDiskSpace if the filesystem
+// holding dir has less than need bytes, plus a margin, available. Checking
+// before writing fails early instead of leaving half-written objects or
+// files behind. Passes where free space can't be read.
+func checkDiskSpace(dir string, need int64, operation string) error {
+	available, ok := availableDiskSpace(dir)
+	if !ok || available >= need+diskSpaceMargin {
+		return nil
+	}
+	return fmt.Errorf("%w to %s: needs about %s in %s (including %s headroom), %s available",
+		ErrInsufficientDiskSpace, operation, formatDiskBytes(need+diskSpaceMargin), dir,
+		formatDiskBytes(diskSpaceMargin), formatDiskBytes(available))
+}
+
+// checkStepDiskSpace checks that the git directory has room for a step
+// checkpoint: at most the changed files and the session metadata, before
+// compression.
+func checkStepDiskSpace(ctx context.Context, step StepContext) error {
+	gitDir, err := GetGitCommonDir(ctx)
+	if err != nil {
+		return nil //nolint:nilerr // Writing the checkpoint will report this
+	}
+	repoRoot, err := paths.WorktreeRoot(ctx)
+	if err != nil {
+		return nil //nolint:nilerr // Writing the checkpoint will report this
+	}
+	files := make([]string, 0, len(step.ModifiedFiles)+len(step.NewFiles)+1)
+	files = append(files, step.ModifiedFiles...)
+	files = append(files, step.NewFiles...)
+	if step.MetadataDirAbs != "" {
+		files = append(files, step.MetadataDirAbs)
+	}
+	return checkDiskSpace(gitDir, pathsFootprint(repoRoot, files...), "save checkpoint")
+}
+
+// checkRewindDiskSpace checks that the working tree has room for the files
+// a rewind to tree restores. Files are rewritten in place, so only the amount
+// they grow by needs new space.
+func checkRewindDiskSpace(ctx context.Context, tree *object.Tree) error {
+	repoRoot, err := paths.WorktreeRoot(ctx)
+	if err != nil {
+		return nil //nolint:nilerr // The rewind will report this
+	}
+	var need int64
+	err = tree.Files().ForEach(func(f *object.File) error {
+		if err := ctx.Err(); err != nil {
+			return err //nolint:wrapcheck // Propagating context cancellation
+		}
+		if strings.HasPrefix(f.Name, entireDir) {
+			return nil
+		}
+		if growth := f.Size - fileSize(filepath.Join(repoRoot, f.Name)); growth > 0 {
+			need += growth
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list checkpoint files: %w", err)
+	}
+	return checkDiskSpace(repoRoot, need, "rewind")
+}
+
+// pathsFootprint returns the total size of the files at the given paths,
+// relative to root, descending into directories. Missing files count as zero.
+func pathsFootprint(root string, relPaths ...string) int64 {
+	var total int64
+	for _, rel := range relPaths {
+		path := rel
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(root, rel)
+		}
+		//nolint:errcheck // Unreadable entries count as zero
+		_ = filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return nil //nolint:nilerr // Skip what can't be read
+			}
+			if info, infoErr := d.Info(); infoErr == nil {
+				total += info.Size()
+			}
+			return nil
+		})
+	}
+	return total
+}
+
+// fileSize returns the size of the file at path, or 0 if it doesn't exist.
+func fileSize(path string) int64 {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0
+	}
+	return info.Size()
+}
+
+func formatDiskBytes(n int64) string {
+	switch {
+	case n >= 1<<30:
+		return fmt.Sprintf("%.1f GiB", float64(n)/(1<<30))
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MiB", float64(n)/(1<<20))
+	default:
+		return fmt.Sprintf("%d KiB", n>>10)
+	}
+}
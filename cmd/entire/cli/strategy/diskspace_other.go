@@ -0,0 +1,9 @@
+//go:build !unix
+
+package strategy
+
+// availableDiskSpace can't read free space on this platform, so preflight
+// checks are skipped.
+func availableDiskSpace(string) (int64, bool) {
+	return 0, false
+}
@@ -0,0 +1,46 @@
+package strategy
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckDiskSpace(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	if _, ok := availableDiskSpace(dir); !ok {
+		t.Skip("free space can't be read on this platform")
+	}
+
+	require.NoError(t, checkDiskSpace(dir, 0, "save checkpoint"))
+
+	err := checkDiskSpace(dir, 1<<60, "save checkpoint")
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrInsufficientDiskSpace)
+	assert.Contains(t, err.Error(), "to save checkpoint: needs about")
+	assert.Contains(t, err.Error(), dir)
+}
+
+func TestPathsFootprint(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, "a.txt"), []byte(strings.Repeat("a", 100)), 0o644))
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "meta", "sub"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "meta", "one"), []byte("12345"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "meta", "sub", "two"), []byte("123"), 0o644))
+
+	assert.Equal(t, int64(100), pathsFootprint(root, "a.txt", "missing.txt"))
+	assert.Equal(t, int64(108), pathsFootprint(root, "a.txt", filepath.Join(root, "meta")))
+}
+
+func TestFormatDiskBytes(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "512 KiB", formatDiskBytes(512<<10))
+	assert.Equal(t, "1.5 MiB", formatDiskBytes(3<<19))
+	assert.Equal(t, "2.0 GiB", formatDiskBytes(2<<30))
+}
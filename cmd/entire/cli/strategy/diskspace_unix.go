@@ -0,0 +1,15 @@
+//go:build unix
+
+package strategy
+
+import "syscall"
+
+// availableDiskSpace returns the bytes available to unprivileged users on the
+// filesystem holding dir.
+func availableDiskSpace(dir string) (int64, bool) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(dir, &stat); err != nil {
+		return 0, false
+	}
+	return int64(stat.Bavail) * int64(stat.Bsize), true //nolint:gosec,unconvert // field types differ between platforms
+}
@@ -0,0 +1,90 @@
+package strategy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/entireio/cli/cmd/entire/cli/agent/types"
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+	"github.com/entireio/cli/cmd/entire/cli/logging"
+	"github.com/entireio/cli/cmd/entire/cli/settings"
+	"github.com/entireio/cli/cmd/entire/cli/transcript"
+	"github.com/entireio/cli/cmd/entire/cli/versioninfo"
+)
+
+// escapeWebhookTimeout bounds the webhook POST so a slow endpoint doesn't hold up the commit.
+const escapeWebhookTimeout = 5 * time.Second
+
+// escapeDetectionSettings returns the configured escape heuristics, or nil
+// when escape detection is disabled or settings can't be read.
+func escapeDetectionSettings(ctx context.Context) *settings.EscapeDetectionSettings {
+	s, err := settings.Load(ctx)
+	if err != nil {
+		return nil
+	}
+	return s.EscapeDetection
+}
+
+// detectEscapes applies the configured heuristics to a checkpoint's
+// permission events.
+func detectEscapes(cfg *settings.EscapeDetectionSettings, permissions []transcript.ToolPermission) []transcript.EscapeFlag {
+	if cfg == nil {
+		return nil
+	}
+	return transcript.DetectEscapes(permissions, transcript.EscapeChecks{
+		OutsideWrites: cfg.OutsideWrites,
+		Sudo:          cfg.Sudo,
+		Network:       cfg.Network,
+		AllowedHosts:  cfg.AllowedHosts,
+	})
+}
+
+// escapeNotification is the JSON body POSTed to the escape detection webhook.
+type escapeNotification struct {
+	CheckpointID id.CheckpointID         `json:"checkpoint_id"`
+	SessionID    string                  `json:"session_id"`
+	Agent        types.AgentType         `json:"agent,omitempty"`
+	Branch       string                  `json:"branch,omitempty"`
+	Flags        []transcript.EscapeFlag `json:"flags"`
+}
+
+// notifyEscapeWebhook POSTs a flagged checkpoint to the configured webhook.
+// Failures are logged: the flags are already recorded in the checkpoint.
+func notifyEscapeWebhook(ctx context.Context, webhook string, notification escapeNotification) {
+	if err := postEscapeNotification(ctx, webhook, notification); err != nil {
+		logging.Warn(ctx, "escape detection webhook failed",
+			slog.String("checkpoint_id", notification.CheckpointID.String()),
+			slog.String("error", err.Error()))
+	}
+}
+
+func postEscapeNotification(ctx context.Context, webhook string, notification escapeNotification) error {
+	body, err := json.Marshal(notification)
+	if err != nil {
+		return fmt.Errorf("failed to encode notification: %w", err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, escapeWebhookTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "entire-cli/"+versioninfo.Version)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send notification to %s: %w", webhook, err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10)) //nolint:errcheck // draining for connection reuse
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook %s returned %s", webhook, resp.Status)
+	}
+	return nil
+}
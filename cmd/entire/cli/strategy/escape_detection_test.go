@@ -0,0 +1,61 @@
+package strategy
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+	"github.com/entireio/cli/cmd/entire/cli/settings"
+	"github.com/entireio/cli/cmd/entire/cli/transcript"
+)
+
+func TestDetectEscapes(t *testing.T) {
+	t.Parallel()
+
+	permissions := []transcript.ToolPermission{
+		{Tool: "Bash", Decision: transcript.PermissionAllowed, Command: "sudo make install"},
+	}
+	if flags := detectEscapes(nil, permissions); flags != nil {
+		t.Errorf("detectEscapes() without settings = %+v, want none", flags)
+	}
+	flags := detectEscapes(&settings.EscapeDetectionSettings{Sudo: true}, permissions)
+	if len(flags) != 1 || flags[0].Heuristic != transcript.EscapeSudo {
+		t.Errorf("detectEscapes() = %+v, want the sudo command", flags)
+	}
+}
+
+func TestPostEscapeNotification(t *testing.T) {
+	t.Parallel()
+
+	var got escapeNotification
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	notification := escapeNotification{
+		CheckpointID: id.MustCheckpointID("a1b2c3d4e5f6"),
+		SessionID:    "session-1",
+		Agent:        "Claude Code",
+		Flags:        []transcript.EscapeFlag{{Heuristic: transcript.EscapeNetwork, Detail: "example.com"}},
+	}
+	if err := postEscapeNotification(context.Background(), server.URL, notification); err != nil {
+		t.Fatalf("postEscapeNotification() error = %v", err)
+	}
+	if got.CheckpointID != notification.CheckpointID || got.SessionID != "session-1" || len(got.Flags) != 1 {
+		t.Errorf("webhook received %+v, want %+v", got, notification)
+	}
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+	if err := postEscapeNotification(context.Background(), failing.URL, notification); err == nil {
+		t.Error("postEscapeNotification() to a failing webhook succeeded, want an error")
+	}
+}
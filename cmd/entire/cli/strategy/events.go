@@ -0,0 +1,37 @@
+package strategy
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+
+	"github.com/entireio/cli/cmd/entire/cli/events"
+	"github.com/entireio/cli/cmd/entire/cli/logging"
+)
+
+// EventLogPath returns the path of the repository's event log.
+func EventLogPath(ctx context.Context) (string, error) {
+	commonDir, err := GetGitCommonDir(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to locate event log: %w", err)
+	}
+	return filepath.Join(commonDir, events.FileName), nil
+}
+
+// RecordEvent appends an event to the repository's event log (see
+// `entire events`). Like auditing, it is best-effort: failures are logged
+// and never abort the operation.
+func RecordEvent(ctx context.Context, event events.Event) {
+	logCtx := logging.WithComponent(ctx, "events")
+
+	path, err := EventLogPath(ctx)
+	if err == nil {
+		err = events.Append(path, event)
+	}
+	if err != nil {
+		logging.Debug(logCtx, "failed to record event",
+			slog.String("type", event.Type),
+			slog.String("error", err.Error()))
+	}
+}
@@ -0,0 +1,139 @@
+package strategy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+)
+
+// Git hooks are normally installed as POSIX sh scripts (see buildHookSpecs).
+// They need nothing beyond sh, so they also run under Git for Windows, which
+// runs hooks through its bundled sh. Systems without /bin/sh, such as minimal
+// containers, get binary shims instead: each hook is a symlink to the entire
+// binary, which recognizes the hook name it was invoked as (ParseGitHookShim).
+
+// shellAvailable reports whether git can run the sh script hooks.
+// Replaced in tests.
+var shellAvailable = func() bool {
+	return runtime.GOOS == "windows" || fileExists("/bin/sh")
+}
+
+// shimExecutable returns the binary that binary shims link to.
+// Replaced in tests.
+var shimExecutable = func() (string, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate entire binary: %w", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+		exe = resolved
+	}
+	return exe, nil
+}
+
+// binaryShimHook describes how a binary shim runs a hook, mirroring its script.
+type binaryShimHook struct {
+	args  int  // how many of git's arguments are passed on
+	quiet bool // stderr is discarded, like the script's 2>/dev/null
+	fatal bool // a failure aborts the git operation
+}
+
+var binaryShimHooks = map[string]binaryShimHook{
+	"prepare-commit-msg": {args: 2, quiet: true},
+	"commit-msg":         {args: 1, fatal: true},
+	"post-commit":        {quiet: true},
+	"pre-push":           {args: 1},
+}
+
+// isBinaryShim reports whether hookPath is a symlink to the entire binary.
+func isBinaryShim(hookPath string) bool {
+	target, err := os.Readlink(hookPath)
+	if err != nil {
+		return false
+	}
+	name := filepath.Base(target)
+	return name == "entire" || name == "entire.exe"
+}
+
+// writeHookSymlink points hookPath at the entire binary, replacing any file
+// there. Returns true if the link was written, false if it was already in place.
+func writeHookSymlink(hookPath, target string) (bool, error) {
+	if existing, err := os.Readlink(hookPath); err == nil && existing == target {
+		return false, nil
+	}
+	if err := os.Remove(hookPath); err != nil && !os.IsNotExist(err) {
+		return false, fmt.Errorf("failed to replace hook file %s: %w", hookPath, err)
+	}
+	if err := os.Symlink(target, hookPath); err != nil {
+		return false, fmt.Errorf("failed to link hook file %s: %w", hookPath, err)
+	}
+	return true, nil
+}
+
+// GitHookShim is a git hook invocation through a binary shim.
+type GitHookShim struct {
+	// HookPath is the hook git invoked.
+	HookPath string
+
+	// Args are the entire command line that handles the hook.
+	Args []string
+
+	// Quiet means the handler's stderr should be discarded.
+	Quiet bool
+
+	// Fatal means a handler error should fail the hook.
+	Fatal bool
+
+	gitArgs []string
+}
+
+// ParseGitHookShim recognizes an invocation of the entire binary through a
+// hook symlink, by the hook name in argv[0]. Returns false for normal invocations.
+func ParseGitHookShim(argv []string) (*GitHookShim, bool) {
+	if len(argv) == 0 {
+		return nil, false
+	}
+	name := filepath.Base(argv[0])
+	hook, ok := binaryShimHooks[name]
+	if !ok {
+		return nil, false
+	}
+
+	gitArgs := argv[1:]
+	passed := gitArgs[:min(hook.args, len(gitArgs))]
+	return &GitHookShim{
+		HookPath: argv[0],
+		Args:     append([]string{"hooks", "git", name}, passed...),
+		Quiet:    hook.quiet,
+		Fatal:    hook.fatal,
+		gitArgs:  gitArgs,
+	}, true
+}
+
+// RunBackup runs the pre-existing hook chained after ours, if there is one,
+// with git's arguments and stdin. Returns its exit code, 0 if there is none.
+func (s *GitHookShim) RunBackup(ctx context.Context) int {
+	backupPath := s.HookPath + backupSuffix
+	info, err := os.Stat(backupPath)
+	if err != nil || info.Mode().Perm()&0o111 == 0 {
+		return 0
+	}
+
+	cmd := exec.CommandContext(ctx, backupPath, s.gitArgs...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return exitErr.ExitCode()
+		}
+		fmt.Fprintf(os.Stderr, "[entire] failed to run %s: %v\n", filepath.Base(backupPath), err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,190 @@
+package strategy
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// useBinaryShims makes InstallGitHook behave as on a system without /bin/sh,
+// linking hooks to a stand-in entire binary. Returns the binary's path.
+func useBinaryShims(t *testing.T) string {
+	t.Helper()
+	target := filepath.Join(t.TempDir(), "entire")
+	if err := os.WriteFile(target, []byte("#!/bin/sh\n"), 0o755); err != nil {
+		t.Fatalf("failed to write stand-in binary: %v", err)
+	}
+
+	origShell, origExe := shellAvailable, shimExecutable
+	shellAvailable = func() bool { return false }
+	shimExecutable = func() (string, error) { return target, nil }
+	t.Cleanup(func() {
+		shellAvailable, shimExecutable = origShell, origExe
+	})
+	return target
+}
+
+func TestInstallGitHook_BinaryShimsWithoutShell(t *testing.T) {
+	_, hooksDir := initHooksTestRepo(t)
+	target := useBinaryShims(t)
+
+	customContent := "#!/bin/sh\necho 'user hook'\n"
+	if err := os.WriteFile(filepath.Join(hooksDir, "pre-push"), []byte(customContent), 0o755); err != nil {
+		t.Fatalf("failed to create custom hook: %v", err)
+	}
+
+	count, err := InstallGitHook(context.Background(), true, false)
+	if err != nil {
+		t.Fatalf("InstallGitHook() error = %v", err)
+	}
+	if count != len(gitHookNames) {
+		t.Errorf("InstallGitHook() installed %d hooks, want %d", count, len(gitHookNames))
+	}
+	for _, hook := range gitHookNames {
+		link, err := os.Readlink(filepath.Join(hooksDir, hook))
+		if err != nil || link != target {
+			t.Errorf("hook %s should link to %s, got %q (err %v)", hook, target, link, err)
+		}
+	}
+	if !IsGitHookInstalled(context.Background()) {
+		t.Error("IsGitHookInstalled() should recognize binary shims")
+	}
+
+	// Reinstalling leaves the links alone
+	count, err = InstallGitHook(context.Background(), true, false)
+	if err != nil {
+		t.Fatalf("second InstallGitHook() error = %v", err)
+	}
+	if count != 0 {
+		t.Errorf("second InstallGitHook() installed %d hooks, want 0", count)
+	}
+
+	// Removing restores the backed-up hook and never touches the binary
+	if _, err := RemoveGitHook(context.Background()); err != nil {
+		t.Fatalf("RemoveGitHook() error = %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(hooksDir, "pre-push"))
+	if err != nil || string(data) != customContent {
+		t.Errorf("pre-push should be restored to %q, got %q (err %v)", customContent, data, err)
+	}
+	if data, err := os.ReadFile(target); err != nil || string(data) != "#!/bin/sh\n" {
+		t.Errorf("entire binary should be untouched, got %q (err %v)", data, err)
+	}
+}
+
+func TestInstallGitHook_ScriptReplacesBinaryShim(t *testing.T) {
+	_, hooksDir := initHooksTestRepo(t)
+	target := useBinaryShims(t)
+
+	if _, err := InstallGitHook(context.Background(), true, false); err != nil {
+		t.Fatalf("InstallGitHook() error = %v", err)
+	}
+
+	// Once sh is available, scripts replace the links instead of writing through them
+	shellAvailable = func() bool { return true }
+	if _, err := InstallGitHook(context.Background(), true, false); err != nil {
+		t.Fatalf("InstallGitHook() error = %v", err)
+	}
+
+	hookPath := filepath.Join(hooksDir, "commit-msg")
+	if isBinaryShim(hookPath) {
+		t.Error("commit-msg should be a script, not a link")
+	}
+	if data, err := os.ReadFile(hookPath); err != nil || !strings.Contains(string(data), entireHookMarker) {
+		t.Errorf("commit-msg should be Entire's script, got %q (err %v)", data, err)
+	}
+	if data, err := os.ReadFile(target); err != nil || string(data) != "#!/bin/sh\n" {
+		t.Errorf("entire binary should be untouched, got %q (err %v)", data, err)
+	}
+}
+
+func TestParseGitHookShim(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		argv      []string
+		wantOK    bool
+		wantArgs  []string
+		wantQuiet bool
+		wantFatal bool
+	}{
+		{
+			name:      "prepare-commit-msg drops the commit sha",
+			argv:      []string{".git/hooks/prepare-commit-msg", ".git/COMMIT_EDITMSG", "commit", "abc123"},
+			wantOK:    true,
+			wantArgs:  []string{"hooks", "git", "prepare-commit-msg", ".git/COMMIT_EDITMSG", "commit"},
+			wantQuiet: true,
+		},
+		{
+			name:      "commit-msg is fatal",
+			argv:      []string{"/repo/.git/hooks/commit-msg", ".git/COMMIT_EDITMSG"},
+			wantOK:    true,
+			wantArgs:  []string{"hooks", "git", "commit-msg", ".git/COMMIT_EDITMSG"},
+			wantFatal: true,
+		},
+		{
+			name:     "pre-push passes the remote only",
+			argv:     []string{".git/hooks/pre-push", "origin", "git@example.com:repo.git"},
+			wantOK:   true,
+			wantArgs: []string{"hooks", "git", "pre-push", "origin"},
+		},
+		{
+			name: "normal invocation",
+			argv: []string{"/usr/local/bin/entire", "status"},
+		},
+		{
+			name: "backup hook name",
+			argv: []string{".git/hooks/pre-push" + backupSuffix, "origin"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			shim, ok := ParseGitHookShim(tt.argv)
+			if ok != tt.wantOK {
+				t.Fatalf("ParseGitHookShim() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if !ok {
+				return
+			}
+			if !reflect.DeepEqual(shim.Args, tt.wantArgs) {
+				t.Errorf("Args = %v, want %v", shim.Args, tt.wantArgs)
+			}
+			if shim.Quiet != tt.wantQuiet || shim.Fatal != tt.wantFatal {
+				t.Errorf("Quiet, Fatal = %v, %v, want %v, %v", shim.Quiet, shim.Fatal, tt.wantQuiet, tt.wantFatal)
+			}
+		})
+	}
+}
+
+func TestGitHookShim_RunBackup(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	hookPath := filepath.Join(dir, "pre-push")
+	outPath := filepath.Join(dir, "out")
+
+	shim, ok := ParseGitHookShim([]string{hookPath, "origin", "url"})
+	if !ok {
+		t.Fatal("ParseGitHookShim() should recognize pre-push")
+	}
+	if code := shim.RunBackup(context.Background()); code != 0 {
+		t.Errorf("RunBackup() without a backup = %d, want 0", code)
+	}
+
+	backup := "#!/bin/sh\necho \"$@\" > \"" + outPath + "\"\nexit 4\n"
+	if err := os.WriteFile(hookPath+backupSuffix, []byte(backup), 0o755); err != nil {
+		t.Fatalf("failed to write backup: %v", err)
+	}
+	if code := shim.RunBackup(context.Background()); code != 4 {
+		t.Errorf("RunBackup() = %d, want the backup's exit status 4", code)
+	}
+	// The backup gets all of git's arguments, not just the ones entire uses
+	if data, err := os.ReadFile(outPath); err != nil || strings.TrimSpace(string(data)) != "origin url" {
+		t.Errorf("backup saw %q (err %v), want %q", data, err, "origin url")
+	}
+}
@@ -1,8 +1,8 @@
 package strategy
 
 import (
+	"bytes"
 	"context"
-	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -10,6 +10,7 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/entireio/cli/cmd/entire/cli/paths"
 	"github.com/entireio/cli/cmd/entire/cli/settings"
 )
 
@@ -96,7 +97,7 @@ func getGitDirInPath(ctx context.Context, dir string) (string, error) {
 	cmd.Dir = dir
 	output, err := cmd.Output()
 	if err != nil {
-		return "", errors.New("not a git repository")
+		return "", paths.ErrNotGitRepository
 	}
 
 	gitDir := strings.TrimSpace(string(output))
@@ -119,7 +120,7 @@ func getHooksDirInPath(ctx context.Context, dir string) (string, error) {
 	cmd.Dir = dir
 	output, err := cmd.Output()
 	if err != nil {
-		return "", errors.New("not a git repository")
+		return "", paths.ErrNotGitRepository
 	}
 
 	hooksDir := strings.TrimSpace(string(output))
@@ -130,6 +131,80 @@ func getHooksDirInPath(ctx context.Context, dir string) (string, error) {
 	return filepath.Clean(hooksDir), nil
 }
 
+// HooksDirSource describes where the active hooks directory comes from.
+type HooksDirSource struct {
+	// Dir is the resolved hooks directory hooks are installed into.
+	Dir string
+
+	// HooksPath is the configured core.hooksPath value, empty when unset.
+	HooksPath string
+
+	// Scope is the git config scope HooksPath is set in ("system", "global",
+	// "local" or "worktree"), empty when unset.
+	Scope string
+}
+
+// DescribeHooksDir resolves the active hooks directory and reports whether it
+// comes from core.hooksPath, and from which config scope. Git resolves the
+// directory itself, so global, per-repo and per-worktree (extensions.worktreeConfig)
+// settings all apply exactly as they do when git runs hooks.
+func DescribeHooksDir(ctx context.Context) (HooksDirSource, error) {
+	dir, err := GetHooksDir(ctx)
+	if err != nil {
+		return HooksDirSource{}, err
+	}
+	if abs, absErr := filepath.Abs(dir); absErr == nil {
+		dir = abs
+	}
+	source := HooksDirSource{Dir: dir}
+
+	// Exits 1 when core.hooksPath isn't set anywhere
+	cmd := exec.CommandContext(ctx, "git", "config", "--show-scope", "--get", "core.hooksPath")
+	output, err := cmd.Output()
+	if err != nil {
+		return source, nil //nolint:nilerr // unset core.hooksPath means the default hooks directory
+	}
+	scope, value, found := strings.Cut(strings.TrimSpace(string(output)), "\t")
+	if found {
+		source.Scope = scope
+		source.HooksPath = value
+	}
+	return source, nil
+}
+
+// GitHookStatus is the install state of one managed git hook.
+type GitHookStatus struct {
+	Name string
+
+	// Installed is true when Entire's hook is in place.
+	Installed bool
+
+	// Backup is the file name of the pre-existing hook that was backed up and
+	// runs after ours, empty if there is none.
+	Backup string
+
+	// Replaced is true when another hook sits where Entire's should be.
+	Replaced bool
+}
+
+// GitHookStatuses reports the install state of each managed hook in hooksDir.
+func GitHookStatuses(hooksDir string) []GitHookStatus {
+	statuses := make([]GitHookStatus, 0, len(gitHookNames))
+	for _, hook := range gitHookNames {
+		hookPath := filepath.Join(hooksDir, hook)
+		status := GitHookStatus{Name: hook}
+		if fileExists(hookPath + backupSuffix) {
+			status.Backup = hook + backupSuffix
+		}
+		if _, ours, err := readHook(hookPath); err == nil {
+			status.Installed = ours
+			status.Replaced = !ours
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
 // IsGitHookInstalled checks if all generic Entire CLI hooks are installed.
 func IsGitHookInstalled(ctx context.Context) bool {
 	hooksDir, err := GetHooksDir(ctx)
@@ -152,18 +227,28 @@ func IsGitHookInstalledInDir(ctx context.Context, repoDir string) bool {
 // isGitHookInstalledInHooksDir checks if all hooks are installed in the given hooks directory.
 func isGitHookInstalledInHooksDir(hooksDir string) bool {
 	for _, hook := range gitHookNames {
-		hookPath := filepath.Join(hooksDir, hook)
-		data, err := os.ReadFile(hookPath) //nolint:gosec // Path is constructed from constants
-		if err != nil {
-			return false
-		}
-		if !strings.Contains(string(data), entireHookMarker) {
+		_, ours, err := readHook(filepath.Join(hooksDir, hook))
+		if err != nil || !ours {
 			return false
 		}
 	}
 	return true
 }
 
+// readHook reads the hook at hookPath and reports whether it is one of
+// Entire's: a script carrying the marker, or a binary shim (whose content,
+// the entire binary, isn't read).
+func readHook(hookPath string) (content []byte, ours bool, err error) {
+	if isBinaryShim(hookPath) {
+		return nil, true, nil
+	}
+	content, err = os.ReadFile(hookPath) //nolint:gosec // Path is constructed from constants
+	if err != nil {
+		return nil, false, err //nolint:wrapcheck // callers only check for existence
+	}
+	return content, strings.Contains(string(content), entireHookMarker), nil
+}
+
 // buildHookSpecs returns the hook specifications for all managed hooks.
 func buildHookSpecs(cmdPrefix string) []hookSpec {
 	return []hookSpec{
@@ -206,6 +291,7 @@ func buildHookSpecs(cmdPrefix string) []hookSpec {
 // These hooks work with any strategy - the strategy is determined at runtime.
 // If silent is true, no output is printed (except backup notifications, which always print).
 // localDev controls whether hooks use "go run" (true) or the "entire" binary (false).
+// Without /bin/sh, hooks are installed as binary shims instead of scripts (see hook_shim.go).
 // Returns the number of hooks that were installed (0 if all already up to date).
 func InstallGitHook(ctx context.Context, silent bool, localDev bool) (int, error) {
 	hooksDir, err := GetHooksDir(ctx)
@@ -220,32 +306,48 @@ func InstallGitHook(ctx context.Context, silent bool, localDev bool) (int, error
 	specs := buildHookSpecs(hookCmdPrefix(localDev))
 	installedCount := 0
 
+	var shimTarget string
+	if !localDev && !shellAvailable() {
+		if shimTarget, err = shimExecutable(); err != nil {
+			return 0, err
+		}
+	}
+
 	for _, spec := range specs {
 		hookPath := filepath.Join(hooksDir, spec.name)
 		backupPath := hookPath + backupSuffix
 		backupExists := fileExists(backupPath)
 
 		// Back up existing non-Entire hooks
-		existing, existingErr := os.ReadFile(hookPath) //nolint:gosec // path is controlled
-		if existingErr == nil && !strings.Contains(string(existing), entireHookMarker) {
-			if !backupExists {
+		existing, existingIsOurs, existingErr := readHook(hookPath)
+		if existingErr == nil && !existingIsOurs {
+			switch {
+			case !backupExists:
 				if err := os.Rename(hookPath, backupPath); err != nil {
 					return installedCount, fmt.Errorf("failed to back up %s: %w", spec.name, err)
 				}
 				fmt.Fprintf(os.Stderr, "[entire] Backed up existing %s to %s%s\n", spec.name, spec.name, backupSuffix)
-			} else {
+			case sameFileContent(backupPath, existing):
+				// The hook manager (e.g. husky on npm install) rewrote the same hook
+				// over ours; the backup already holds it, so nothing is lost.
+			default:
 				fmt.Fprintf(os.Stderr, "[entire] Warning: replacing %s (backup %s%s already exists from a previous install)\n", spec.name, spec.name, backupSuffix)
 			}
 			backupExists = true
 		}
 
-		// Chain to backup if one exists
+		// Chain to backup if one exists; binary shims chain at runtime
 		content := spec.content
 		if backupExists {
 			content = generateChainedContent(spec.content, spec.name)
 		}
 
-		written, err := writeHookFile(hookPath, content)
+		var written bool
+		if shimTarget != "" {
+			written, err = writeHookSymlink(hookPath, shimTarget)
+		} else {
+			written, err = writeHookFile(hookPath, content)
+		}
 		if err != nil {
 			return installedCount, fmt.Errorf("failed to install %s hook: %w", spec.name, err)
 		}
@@ -265,6 +367,13 @@ func InstallGitHook(ctx context.Context, silent bool, localDev bool) (int, error
 // writeHookFile writes a hook file if it doesn't exist or has different content.
 // Returns true if the file was written, false if it already had the same content.
 func writeHookFile(path, content string) (bool, error) {
+	// Replace a binary shim rather than writing through the link into the binary
+	if isBinaryShim(path) {
+		if err := os.Remove(path); err != nil {
+			return false, fmt.Errorf("failed to replace hook file %s: %w", path, err)
+		}
+	}
+
 	// Check if file already exists with same content
 	existing, err := os.ReadFile(path) //nolint:gosec // path is controlled
 	if err == nil && string(existing) == content {
@@ -295,8 +404,7 @@ func RemoveGitHook(ctx context.Context) (int, error) {
 		backupPath := hookPath + backupSuffix
 
 		// Remove the hook if it contains our marker
-		data, err := os.ReadFile(hookPath) //nolint:gosec // path is controlled
-		hookIsOurs := err == nil && strings.Contains(string(data), entireHookMarker)
+		_, hookIsOurs, err := readHook(hookPath)
 		hookExists := err == nil
 
 		if hookIsOurs {
@@ -326,15 +434,33 @@ func RemoveGitHook(ctx context.Context) (int, error) {
 	return removed, nil
 }
 
+// sameFileContent reports whether the file at path holds exactly content.
+func sameFileContent(path string, content []byte) bool {
+	data, err := os.ReadFile(path) //nolint:gosec // path is controlled
+	return err == nil && bytes.Equal(data, content)
+}
+
 // generateChainedContent appends a chain call to the base hook content,
 // so the pre-existing hook (backed up to .pre-entire) is called after our hook.
+// POSIX sh hooks are sourced in a subshell rather than executed, so $0 still
+// names the original hook: hook managers like husky dispatch on basename "$0".
 func generateChainedContent(baseContent, hookName string) string {
+	backup := fmt.Sprintf(`"$_entire_hook_dir/%s%s"`, hookName, backupSuffix)
 	return baseContent + fmt.Sprintf(`%s
 _entire_hook_dir="$(dirname "$0")"
-if [ -x "$_entire_hook_dir/%s%s" ]; then
-    "$_entire_hook_dir/%s%s" "$@"
+if [ -x %s ]; then
+    _entire_shebang=""
+    read -r _entire_shebang < %s
+    case "$_entire_shebang" in
+    "#!/bin/sh"|"#!/bin/sh "*|"#!/usr/bin/env sh"|"#!/usr/bin/env sh "*)
+        ( . %s )
+        ;;
+    *)
+        %s "$@"
+        ;;
+    esac
 fi
-`, chainComment, hookName, backupSuffix, hookName, backupSuffix)
+`, chainComment, backup, backup, backup, backup)
 }
 
 // hookCmdPrefix returns the command prefix for hook scripts and warning messages.
@@ -2,6 +2,7 @@ package strategy
 
 import (
 	"context"
+	"errors"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -668,6 +669,100 @@ func TestInstallGitHook_CoreHooksPathRelative(t *testing.T) {
 	}
 }
 
+func TestDescribeHooksDir(t *testing.T) {
+	tests := []struct {
+		name      string
+		config    [][]string
+		wantDir   string
+		wantScope string
+	}{
+		{name: "default", wantDir: filepath.Join(".git", "hooks")},
+		{
+			name:      "repository core.hooksPath",
+			config:    [][]string{{"core.hooksPath", ".githooks"}},
+			wantDir:   ".githooks",
+			wantScope: "local",
+		},
+		{
+			name: "worktree core.hooksPath",
+			config: [][]string{
+				{"core.hooksPath", ".githooks"},
+				{"extensions.worktreeConfig", "true"},
+				{"--worktree", "core.hooksPath", ".worktree-hooks"},
+			},
+			wantDir:   ".worktree-hooks",
+			wantScope: "worktree",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir, _ := initHooksTestRepo(t)
+			for _, args := range tt.config {
+				cmd := exec.CommandContext(context.Background(), "git", append([]string{"config"}, args...)...)
+				cmd.Dir = tmpDir
+				if output, err := cmd.CombinedOutput(); err != nil {
+					t.Fatalf("git config %v failed: %v\n%s", args, err, output)
+				}
+			}
+
+			source, err := DescribeHooksDir(context.Background())
+			if err != nil {
+				t.Fatalf("DescribeHooksDir() error = %v", err)
+			}
+			if want := filepath.Join(tmpDir, tt.wantDir); source.Dir != want {
+				t.Errorf("Dir = %q, want %q", source.Dir, want)
+			}
+			if source.Scope != tt.wantScope {
+				t.Errorf("Scope = %q, want %q", source.Scope, tt.wantScope)
+			}
+
+			if _, err := InstallGitHook(context.Background(), true, false); err != nil {
+				t.Fatalf("InstallGitHook() error = %v", err)
+			}
+			for _, status := range GitHookStatuses(source.Dir) {
+				if !status.Installed {
+					t.Errorf("hook %s should be installed in %s", status.Name, source.Dir)
+				}
+			}
+		})
+	}
+}
+
+func TestGitHookStatuses(t *testing.T) {
+	t.Parallel()
+	hooksDir := t.TempDir()
+
+	// Our hook chained to a backup, a foreign hook, and the rest missing
+	chained := generateChainedContent("#!/bin/sh\n# "+entireHookMarker+"\n", "commit-msg")
+	files := map[string]string{
+		"commit-msg":                chained,
+		"commit-msg" + backupSuffix: "#!/bin/sh\n",
+		"pre-push":                  "#!/bin/sh\necho other\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(hooksDir, name), []byte(content), 0o755); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+
+	got := make(map[string]GitHookStatus)
+	for _, status := range GitHookStatuses(hooksDir) {
+		got[status.Name] = status
+	}
+	want := map[string]GitHookStatus{
+		"prepare-commit-msg": {Name: "prepare-commit-msg"},
+		"commit-msg":         {Name: "commit-msg", Installed: true, Backup: "commit-msg" + backupSuffix},
+		"post-commit":        {Name: "post-commit"},
+		"pre-push":           {Name: "pre-push", Replaced: true},
+	}
+	for name, w := range want {
+		if got[name] != w {
+			t.Errorf("status of %s = %+v, want %+v", name, got[name], w)
+		}
+	}
+}
+
 func TestRemoveGitHook_CoreHooksPathRelative(t *testing.T) {
 	tmpDir, _ := initHooksTestRepo(t)
 	ctx := context.Background()
@@ -1119,6 +1214,102 @@ func TestGenerateChainedContent(t *testing.T) {
 	}
 }
 
+func TestGenerateChainedContent_RunsBackupUnderOriginalName(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		shebang  string
+		wantName string
+	}{
+		// Husky-style sh hooks look themselves up by basename "$0"
+		{name: "sh hook is sourced", shebang: "#!/usr/bin/env sh", wantName: "pre-push"},
+		{name: "other interpreters are executed", shebang: "#!/bin/bash", wantName: "pre-push" + backupSuffix},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			dir := t.TempDir()
+			outPath := filepath.Join(dir, "out")
+
+			backup := tt.shebang + "\necho \"$(basename \"$0\") $1\" > \"" + outPath + "\"\nexit 3\n"
+			if err := os.WriteFile(filepath.Join(dir, "pre-push"+backupSuffix), []byte(backup), 0o755); err != nil {
+				t.Fatalf("failed to write backup: %v", err)
+			}
+			hookPath := filepath.Join(dir, "pre-push")
+			if err := os.WriteFile(hookPath, []byte(generateChainedContent("#!/bin/sh\n", "pre-push")), 0o755); err != nil {
+				t.Fatalf("failed to write hook: %v", err)
+			}
+
+			err := exec.CommandContext(context.Background(), hookPath, "origin").Run()
+			var exitErr *exec.ExitError
+			if !errors.As(err, &exitErr) || exitErr.ExitCode() != 3 {
+				t.Errorf("hook error = %v, want the backup's exit status 3", err)
+			}
+
+			out, err := os.ReadFile(outPath)
+			if err != nil {
+				t.Fatalf("backup hook did not run: %v", err)
+			}
+			if got, want := strings.TrimSpace(string(out)), tt.wantName+" origin"; got != want {
+				t.Errorf("backup saw %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestInstallGitHook_HookManagerRewriteIsNotLost(t *testing.T) {
+	_, hooksDir := initHooksTestRepo(t)
+
+	// A hook manager installs its hook, entire chains it, then the manager
+	// rewrites the same hook over ours (as husky does on npm install)
+	managerContent := "#!/usr/bin/env sh\n. \"$(dirname \"$0\")/h\"\n"
+	hookPath := filepath.Join(hooksDir, "pre-push")
+	if err := os.WriteFile(hookPath, []byte(managerContent), 0o755); err != nil {
+		t.Fatalf("failed to create hook: %v", err)
+	}
+	if _, err := InstallGitHook(context.Background(), true, false); err != nil {
+		t.Fatalf("InstallGitHook() error = %v", err)
+	}
+	if err := os.WriteFile(hookPath, []byte(managerContent), 0o755); err != nil {
+		t.Fatalf("failed to rewrite hook: %v", err)
+	}
+	if _, err := InstallGitHook(context.Background(), true, false); err != nil {
+		t.Fatalf("second InstallGitHook() error = %v", err)
+	}
+
+	data, err := os.ReadFile(hookPath)
+	if err != nil {
+		t.Fatalf("hook should exist: %v", err)
+	}
+	if !strings.Contains(string(data), entireHookMarker) || !strings.Contains(string(data), chainComment) {
+		t.Errorf("hook should be Entire's with a chain call, got:\n%s", data)
+	}
+
+	// Uninstall restores the manager's hook byte for byte, still executable
+	if _, err := RemoveGitHook(context.Background()); err != nil {
+		t.Fatalf("RemoveGitHook() error = %v", err)
+	}
+	restored, err := os.ReadFile(hookPath)
+	if err != nil {
+		t.Fatalf("hook should be restored: %v", err)
+	}
+	if string(restored) != managerContent {
+		t.Errorf("restored hook = %q, want %q", restored, managerContent)
+	}
+	info, err := os.Stat(hookPath)
+	if err != nil {
+		t.Fatalf("stat restored hook: %v", err)
+	}
+	if info.Mode().Perm()&0o111 == 0 {
+		t.Errorf("restored hook mode = %v, want executable", info.Mode())
+	}
+	if fileExists(hookPath + backupSuffix) {
+		t.Error("backup should be consumed by the restore")
+	}
+}
+
 func TestInstallGitHook_InstallRemoveReinstall(t *testing.T) {
 	_, hooksDir := initHooksTestRepo(t)
 
@@ -326,6 +326,7 @@ func CalculateAttributionWithAccumulated(
 	return &checkpoint.InitialAttribution{
 		CalculatedAt:    time.Now().UTC(),
 		AgentLines:      agentLinesInCommit,
+		AgentWritten:    totalAgentAdded,
 		HumanAdded:      pureUserAdded,
 		HumanModified:   totalHumanModified, // Total modifications (for reporting)
 		HumanRemoved:    pureUserRemoved,
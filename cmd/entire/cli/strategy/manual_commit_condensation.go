@@ -9,12 +9,15 @@ import (
 	"os"
 	"strings"
 
+	"github.com/entireio/cli/cmd/entire/cli/age"
 	"github.com/entireio/cli/cmd/entire/cli/agent"
 	"github.com/entireio/cli/cmd/entire/cli/agent/geminicli"
 	"github.com/entireio/cli/cmd/entire/cli/agent/opencode"
 	"github.com/entireio/cli/cmd/entire/cli/agent/types"
 	cpkg "github.com/entireio/cli/cmd/entire/cli/checkpoint"
 	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+	"github.com/entireio/cli/cmd/entire/cli/classify"
+	"github.com/entireio/cli/cmd/entire/cli/events"
 	"github.com/entireio/cli/cmd/entire/cli/logging"
 	"github.com/entireio/cli/cmd/entire/cli/paths"
 	"github.com/entireio/cli/cmd/entire/cli/session"
@@ -30,9 +33,9 @@ import (
 )
 
 // listCheckpoints returns all checkpoints from the metadata branch.
-// Uses checkpoint.GitStore.ListCommitted() for reading from entire/checkpoints/v1.
+// Uses the committed checkpoint store: entire/checkpoints/v1 or the configured bucket.
 func (s *ManualCommitStrategy) listCheckpoints(ctx context.Context) ([]CheckpointInfo, error) {
-	store, err := s.getCheckpointStore()
+	store, err := s.getCommittedStore(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get checkpoint store: %w", err)
 	}
@@ -84,9 +87,9 @@ func (s *ManualCommitStrategy) getCheckpointsForSession(ctx context.Context, ses
 }
 
 // getCheckpointLog returns the transcript for a specific checkpoint ID.
-// Uses checkpoint.GitStore.ReadCommitted() for reading from entire/checkpoints/v1.
+// Uses the committed checkpoint store: entire/checkpoints/v1 or the configured bucket.
 func (s *ManualCommitStrategy) getCheckpointLog(ctx context.Context, checkpointID id.CheckpointID) ([]byte, error) {
-	store, err := s.getCheckpointStore()
+	store, err := s.getCommittedStore(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get checkpoint store: %w", err)
 	}
@@ -109,12 +112,80 @@ func (s *ManualCommitStrategy) getCheckpointLog(ctx context.Context, checkpointI
 type condenseOpts struct {
 	shadowRef *plumbing.Reference // Pre-resolved shadow branch ref (nil = resolve from repo)
 	headTree  *object.Tree        // Pre-resolved HEAD tree (passed through to calculateSessionAttributions)
+	diffStats *cpkg.DiffStats     // Diff stats of the commit being condensed into (nil = not linked to a commit)
+	batch     *condenseBatch      // Batch the checkpoint is written to (nil = write it to the store directly)
+}
+
+// condenseBatch collects the checkpoint writes of one post-commit hook, so
+// the metadata branch moves once however many sessions are condensed. Work
+// that must wait until the checkpoints are stored, such as saving session
+// state or clearing pending attachments, is deferred until commit. A nil
+// condenseBatch writes to the store and runs that work right away.
+type condenseBatch struct {
+	store *cpkg.GitStore
+	batch *cpkg.Batch // begun by the first write, so hooks that condense nothing don't create the branch
+	after []func()
+}
+
+// newCondenseBatch returns a batch for the metadata branch. Returns nil when
+// committed checkpoints go to an object store, which has no branch to batch.
+func (s *ManualCommitStrategy) newCondenseBatch(ctx context.Context) *condenseBatch {
+	store, err := s.getCommittedStore(ctx)
+	if err != nil {
+		return nil
+	}
+	gitStore, ok := store.(*cpkg.GitStore)
+	if !ok {
+		return nil
+	}
+	return &condenseBatch{store: gitStore}
+}
+
+// write stages opts in the batch, or writes them to store without one.
+func (b *condenseBatch) write(ctx context.Context, store cpkg.Store, opts cpkg.WriteCommittedOptions) error {
+	if b == nil {
+		return store.WriteCommitted(ctx, opts) //nolint:wrapcheck // Callers wrap
+	}
+	if b.batch == nil {
+		batch, err := b.store.Begin()
+		if err != nil {
+			return err //nolint:wrapcheck // Callers wrap
+		}
+		b.batch = batch
+	}
+	return b.batch.WriteCommitted(ctx, opts) //nolint:wrapcheck // Callers wrap
+}
+
+// afterCommit runs f once the batch is committed, or right away without one.
+func (b *condenseBatch) afterCommit(f func()) {
+	if b == nil {
+		f()
+		return
+	}
+	b.after = append(b.after, f)
+}
+
+// commit writes the staged checkpoints and runs the deferred work. If the
+// write fails, the deferred work is dropped: the sessions keep their saved
+// state and shadow branches, and the next commit condenses them again.
+func (b *condenseBatch) commit(ctx context.Context) error {
+	if b == nil || b.batch == nil {
+		return nil
+	}
+	if err := b.batch.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to write checkpoint metadata: %w", err)
+	}
+	for _, f := range b.after {
+		f()
+	}
+	return nil
 }
 
 // CondenseSession condenses a session's shadow branch to permanent storage.
 // checkpointID is the 12-hex-char value from the Entire-Checkpoint trailer.
 // Metadata is stored at sharded path: <checkpoint_id[:2]>/<checkpoint_id[2:]>/
-// Uses checkpoint.GitStore.WriteCommitted for the git operations.
+// Uses WriteCommitted of the committed checkpoint store (see CommittedStore),
+// or stages the write in the post-commit hook's batch when one is given.
 //
 // For mid-session commits (no Stop/SaveStep called yet), the shadow branch may not exist.
 // In this case, data is extracted from the live transcript instead.
@@ -204,8 +275,8 @@ func (s *ManualCommitStrategy) CondenseSession(ctx context.Context, repo *git.Re
 		}
 	}
 
-	// Get checkpoint store
-	store, err := s.getCheckpointStore()
+	// Get the store committed checkpoints are written to
+	store, err := s.getCommittedStore(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get checkpoint store: %w", err)
 	}
@@ -265,15 +336,53 @@ func (s *ManualCommitStrategy) CondenseSession(ctx context.Context, repo *git.Re
 		}
 	}
 
-	// Write checkpoint metadata using the checkpoint store
-	if err := store.WriteCommitted(ctx, cpkg.WriteCommittedOptions{
+	// Classify from the prompts and touched files; used by `entire log --type`
+	label := classify.Classify(classify.Input{
+		Prompts:       sessionData.Prompts,
+		ModifiedFiles: sessionData.FilesTouched,
+	})
+
+	// Model from the last turn end; a mid-turn commit before the first turn end reads the transcript
+	model, agentVersion := state.Model, state.AgentVersion
+	if model == "" {
+		model, agentVersion = transcript.ModelInfo(sessionData.Transcript)
+	}
+
+	// Permission events from this checkpoint's portion of JSONL transcripts
+	var permissions []transcript.ToolPermission
+	if state.AgentType == agent.AgentTypeClaudeCode || state.AgentType == agent.AgentTypeCursor || state.AgentType == agent.AgentTypeUnknown {
+		repoRoot, rootErr := paths.WorktreeRoot(ctx)
+		if rootErr != nil {
+			repoRoot = "" // without a root, no write counts as outside the repository
+		}
+		permissions = transcript.ToolPermissions(transcript.SliceFromLine(sessionData.Transcript, state.CheckpointTranscriptStart), repoRoot)
+	}
+	escapeSettings := escapeDetectionSettings(ctx)
+	escapeFlags := detectEscapes(escapeSettings, permissions)
+
+	var agentConfig map[string][]byte
+	if settings.IsSnapshotAgentConfigEnabled(ctx) {
+		agentConfig = readAgentConfigFiles(ctx, ag)
+	}
+	attachments := readPendingAttachments(ctx, state.SessionID)
+	recipients, err := encryptionRecipients(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Write checkpoint metadata using the checkpoint store, or the hook's batch
+	if err := o.batch.write(ctx, store, cpkg.WriteCommittedOptions{
 		CheckpointID:                checkpointID,
 		SessionID:                   state.SessionID,
 		Strategy:                    StrategyNameManualCommit,
 		Branch:                      branchName,
 		Transcript:                  sessionData.Transcript,
+		TranscriptPointer:           transcriptPointerOptions(ctx, state.TranscriptPath),
+		ContentLevel:                contentLevel(ctx),
+		EncryptTo:                   recipients,
 		Prompts:                     sessionData.Prompts,
 		Context:                     sessionData.Context,
+		StructuredContext:           sessionContextFromPrompts(state, sessionData.Prompts),
 		FilesTouched:                sessionData.FilesTouched,
 		CheckpointsCount:            state.StepCount,
 		EphemeralBranch:             shadowBranchName,
@@ -281,14 +390,47 @@ func (s *ManualCommitStrategy) CondenseSession(ctx context.Context, repo *git.Re
 		AuthorEmail:                 authorEmail,
 		Agent:                       state.AgentType,
 		TurnID:                      state.TurnID,
+		CorrelationID:               state.CorrelationID,
+		Label:                       string(label),
+		Model:                       model,
+		AgentVersion:                agentVersion,
+		Turns:                       state.TurnTimings,
+		AgentConfig:                 agentConfig,
+		Instructions:                readInstructionVersions(ctx, ag),
+		Permissions:                 permissions,
+		EscapeFlags:                 escapeFlags,
+		Attachments:                 attachments,
+		DiffStats:                   o.diffStats,
 		TranscriptIdentifierAtStart: state.TranscriptIdentifierAtStart,
 		CheckpointTranscriptStart:   state.CheckpointTranscriptStart,
 		TokenUsage:                  sessionData.TokenUsage,
+		EstimatedCostUSD:            agent.EstimateCost(model, sessionData.TokenUsage),
 		InitialAttribution:          attribution,
 		Summary:                     summary,
 	}); err != nil {
 		return nil, fmt.Errorf("failed to write checkpoint metadata: %w", err)
 	}
+	sessionID, agentType := state.SessionID, state.AgentType
+	o.batch.afterCommit(func() {
+		RecordEvent(ctx, events.Event{
+			Type:         events.TypeCheckpointCreated,
+			SessionID:    sessionID,
+			CheckpointID: checkpointID.String(),
+			Agent:        string(agentType),
+		})
+		if len(attachments) > 0 {
+			clearPendingAttachments(ctx, sessionID)
+		}
+		if len(escapeFlags) > 0 && escapeSettings.Webhook != "" {
+			notifyEscapeWebhook(ctx, escapeSettings.Webhook, escapeNotification{
+				CheckpointID: checkpointID,
+				SessionID:    sessionID,
+				Agent:        agentType,
+				Branch:       branchName,
+				Flags:        escapeFlags,
+			})
+		}
+	})
 
 	return &CondenseResult{
 		CheckpointID:         checkpointID,
@@ -715,6 +857,28 @@ func generateContextFromPrompts(prompts []string) []byte {
 	return []byte(buf.String())
 }
 
+// sessionContextFromPrompts builds the structured context stored next to
+// context.md: the agent the session ran with and the prompts it was given,
+// truncated like generateContextFromPrompts. Returns nil without prompts.
+func sessionContextFromPrompts(state *SessionState, prompts []string) *cpkg.SessionContext {
+	if len(prompts) == 0 {
+		return nil
+	}
+
+	// Keys are fixed and valid, so Set can't fail
+	c := &cpkg.SessionContext{}
+	_ = c.Set(cpkg.ContextEnvironment, "agent", string(state.AgentType))    //nolint:errcheck // valid key
+	_ = c.Set(cpkg.ContextEnvironment, "agent_version", state.AgentVersion) //nolint:errcheck // valid key
+	_ = c.Set(cpkg.ContextEnvironment, "model", state.Model)                //nolint:errcheck // valid key
+	const maxDisplayPromptRunes = 500
+	for i, prompt := range prompts {
+		// Zero-padded so keys sort in prompt order
+		key := fmt.Sprintf("prompt_%03d", i+1)
+		_ = c.Set(cpkg.ContextTask, key, stringutil.TruncateRunes(prompt, maxDisplayPromptRunes, "...")) //nolint:errcheck // valid key
+	}
+	return c
+}
+
 // CondenseSessionByID force-condenses a session by its ID and cleans up.
 // This is used by "entire doctor" to salvage stuck sessions.
 func (s *ManualCommitStrategy) CondenseSessionByID(ctx context.Context, sessionID string) error {
@@ -780,6 +944,7 @@ func (s *ManualCommitStrategy) CondenseSessionByID(ctx context.Context, sessionI
 	state.AttributionBaseCommit = state.BaseCommit
 	state.PromptAttributions = nil
 	state.PendingPromptAttribution = nil
+	state.TurnTimings = nil
 
 	if err := s.saveSessionState(ctx, state); err != nil {
 		return fmt.Errorf("failed to save session state: %w", err)
@@ -827,3 +992,41 @@ func (s *ManualCommitStrategy) cleanupShadowBranchIfUnused(ctx context.Context,
 	}
 	return nil
 }
+
+// transcriptPointerOptions returns pointer storage options for the transcript
+// at transcriptPath if settings keep transcripts out of checkpoints, else nil.
+func transcriptPointerOptions(ctx context.Context, transcriptPath string) *cpkg.TranscriptPointerOptions {
+	s, err := settings.Load(ctx)
+	if err != nil || !s.TranscriptStorage.IsPointer() || transcriptPath == "" {
+		return nil
+	}
+	opts := &cpkg.TranscriptPointerOptions{Path: transcriptPath}
+	if s.TranscriptStorage.BlobURL != "" {
+		opts.Blobs = cpkg.NewHTTPBlobStore(s.TranscriptStorage.BlobURL)
+	}
+	return opts
+}
+
+// contentLevel returns the configured level of session content checkpoints store.
+func contentLevel(ctx context.Context) cpkg.ContentLevel {
+	s, err := settings.Load(ctx)
+	if err != nil {
+		return cpkg.ContentFull
+	}
+	return cpkg.ContentLevel(s.GetContentLevel())
+}
+
+// encryptionRecipients returns the age recipients checkpoint content is
+// encrypted to, or nil if encryption isn't configured. Invalid recipients
+// are an error rather than a reason to store content unencrypted.
+func encryptionRecipients(ctx context.Context) ([]*age.Recipient, error) {
+	s, err := settings.Load(ctx)
+	if err != nil {
+		return nil, nil //nolint:nilerr // unreadable settings configure nothing, as in contentLevel
+	}
+	recipients, err := cpkg.ParseRecipients(s.GetEncryptionRecipients())
+	if err != nil {
+		return nil, fmt.Errorf("invalid encryption.recipients setting: %w", err)
+	}
+	return recipients, nil
+}
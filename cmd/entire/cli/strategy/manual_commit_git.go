@@ -79,6 +79,11 @@ func (s *ManualCommitStrategy) SaveStep(ctx context.Context, step StepContext) e
 		slog.Int("agent_removed", promptAttr.AgentLinesRemoved),
 		slog.String("session_id", sessionID))
 
+	// Fail before writing any objects if the disk can't hold the checkpoint
+	if err := checkStepDiskSpace(ctx, step); err != nil {
+		return err
+	}
+
 	// Use WriteTemporary to create the checkpoint
 	isFirstCheckpointOfSession := state.StepCount == 0
 	result, err := store.WriteTemporary(ctx, checkpoint.WriteTemporaryOptions{
@@ -138,6 +143,7 @@ func (s *ManualCommitStrategy) SaveStep(ctx context.Context, step StepContext) e
 	if err := s.saveSessionState(ctx, state); err != nil {
 		return fmt.Errorf("failed to save session state: %w", err)
 	}
+	createPointRef(ctx, repo, result.CommitHash, step.CommitMessage)
 
 	if !branchExisted {
 		logging.Info(logging.WithComponent(ctx, "checkpoint"), "created shadow branch and committed changes",
@@ -226,7 +232,7 @@ func (s *ManualCommitStrategy) SaveTaskStep(ctx context.Context, step TaskStepCo
 	)
 
 	// Use WriteTemporaryTask to create the checkpoint
-	_, err = store.WriteTemporaryTask(ctx, checkpoint.WriteTemporaryTaskOptions{
+	commitHash, err := store.WriteTemporaryTask(ctx, checkpoint.WriteTemporaryTaskOptions{
 		SessionID:              step.SessionID,
 		BaseCommit:             state.BaseCommit,
 		WorktreeID:             state.WorktreeID,
@@ -257,6 +263,7 @@ func (s *ManualCommitStrategy) SaveTaskStep(ctx context.Context, step TaskStepCo
 	if err := s.saveSessionState(ctx, state); err != nil {
 		return fmt.Errorf("failed to save session state: %w", err)
 	}
+	createPointRef(ctx, repo, commitHash, messageSubject)
 
 	if !branchExisted {
 		logging.Info(logging.WithComponent(ctx, "checkpoint"), "created shadow branch and committed task checkpoint",
@@ -10,10 +10,12 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/entireio/cli/cmd/entire/cli/agent"
 	"github.com/entireio/cli/cmd/entire/cli/agent/claudecode"
 	"github.com/entireio/cli/cmd/entire/cli/agent/types"
+	"github.com/entireio/cli/cmd/entire/cli/events"
 
 	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
 	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
@@ -583,10 +585,12 @@ type postCommitActionHandler struct {
 	// Cached git objects — resolved once per PostCommit invocation to avoid
 	// redundant reads across filesOverlapWithContent, filesWithRemainingAgentChanges,
 	// CondenseSession, and calculateSessionAttributions.
-	headTree   *object.Tree        // HEAD commit tree (shared across all sessions)
-	parentTree *object.Tree        // HEAD's first parent tree (shared, nil for initial commits)
-	shadowRef  *plumbing.Reference // Per-session shadow branch ref (nil if branch doesn't exist)
-	shadowTree *object.Tree        // Per-session shadow commit tree (nil if branch doesn't exist)
+	headTree   *object.Tree          // HEAD commit tree (shared across all sessions)
+	parentTree *object.Tree          // HEAD's first parent tree (shared, nil for initial commits)
+	diffStats  *checkpoint.DiffStats // HEAD's diff stats against parentTree (shared, nil if unavailable)
+	shadowRef  *plumbing.Reference   // Per-session shadow branch ref (nil if branch doesn't exist)
+	shadowTree *object.Tree          // Per-session shadow commit tree (nil if branch doesn't exist)
+	batch      *condenseBatch        // Post-commit batch condensations are written to (nil = write directly)
 
 	// Output: set by handler methods, read by caller after TransitionAndLog.
 	condensed bool
@@ -608,6 +612,8 @@ func (h *postCommitActionHandler) HandleCondense(state *session.State) error {
 		h.condensed = h.s.condenseAndUpdateState(h.ctx, h.repo, h.checkpointID, state, h.head, h.shadowBranchName, h.shadowBranchesToDelete, h.committedFileSet, condenseOpts{
 			shadowRef: h.shadowRef,
 			headTree:  h.headTree,
+			diffStats: h.diffStats,
+			batch:     h.batch,
 		})
 	} else {
 		h.s.updateBaseCommitIfChanged(h.ctx, state, h.newHead)
@@ -632,6 +638,8 @@ func (h *postCommitActionHandler) HandleCondenseIfFilesTouched(state *session.St
 		h.condensed = h.s.condenseAndUpdateState(h.ctx, h.repo, h.checkpointID, state, h.head, h.shadowBranchName, h.shadowBranchesToDelete, h.committedFileSet, condenseOpts{
 			shadowRef: h.shadowRef,
 			headTree:  h.headTree,
+			diffStats: h.diffStats,
+			batch:     h.batch,
 		})
 	} else {
 		h.s.updateBaseCommitIfChanged(h.ctx, state, h.newHead)
@@ -784,10 +792,30 @@ func (s *ManualCommitStrategy) PostCommit(ctx context.Context) error { //nolint:
 
 	committedFileSet := filesChangedInCommit(commit, headTree, parentTree)
 
+	// Diff stats are stored with each checkpoint so listings don't diff at read time
+	var diffStats *checkpoint.DiffStats
+	if headTree != nil {
+		if stats, err := checkpoint.ComputeDiffStats(parentTree, headTree); err == nil {
+			diffStats = stats
+		} else {
+			logging.Debug(logCtx, "post-commit: failed to compute diff stats",
+				slog.String("error", err.Error()))
+		}
+	}
+
+	// Sessions condensed into this commit are written to the metadata branch together
+	batch := s.newCondenseBatch(ctx)
 	for _, state := range sessions {
 		s.postCommitProcessSession(ctx, repo, state, &transitionCtx, checkpointID,
-			head, commit, newHead, headTree, parentTree, committedFileSet,
-			shadowBranchesToDelete, uncondensedActiveOnBranch)
+			head, commit, newHead, headTree, parentTree, committedFileSet, diffStats,
+			shadowBranchesToDelete, uncondensedActiveOnBranch, batch)
+	}
+	if err := batch.commit(ctx); err != nil {
+		logging.Warn(logCtx, "condensation failed",
+			slog.String("checkpoint_id", checkpointID.String()),
+			slog.String("error", err.Error()),
+		)
+		return nil // Shadow branches are kept for the next commit to condense
 	}
 
 	// Clean up shadow branches — only delete when ALL sessions on the branch are non-active
@@ -828,8 +856,10 @@ func (s *ManualCommitStrategy) postCommitProcessSession(
 	newHead string,
 	headTree, parentTree *object.Tree,
 	committedFileSet map[string]struct{},
+	diffStats *checkpoint.DiffStats,
 	shadowBranchesToDelete map[string]struct{},
 	uncondensedActiveOnBranch map[string]bool,
+	batch *condenseBatch,
 ) {
 	logCtx := logging.WithComponent(ctx, "checkpoint")
 	shadowBranchName := getShadowBranchNameForCommit(state.BaseCommit, state.WorktreeID)
@@ -910,8 +940,10 @@ func (s *ManualCommitStrategy) postCommitProcessSession(
 		filesTouchedBefore:     filesTouchedBefore,
 		headTree:               headTree,
 		parentTree:             parentTree,
+		diffStats:              diffStats,
 		shadowRef:              shadowRef,
 		shadowTree:             shadowTree,
+		batch:                  batch,
 	}
 
 	if err := TransitionAndLog(ctx, state, session.EventGitCommit, *transitionCtx, handler); err != nil {
@@ -952,11 +984,18 @@ func (s *ManualCommitStrategy) postCommitProcessSession(
 		}
 	}
 
-	// Save the updated state
-	if err := s.saveSessionState(ctx, state); err != nil {
-		logging.Warn(logCtx, "failed to update session state",
-			slog.String("session_id", state.SessionID),
-			slog.String("error", err.Error()))
+	// Save the updated state; a condensed session's once its checkpoint is written
+	save := func() {
+		if err := s.saveSessionState(ctx, state); err != nil {
+			logging.Warn(logCtx, "failed to update session state",
+				slog.String("session_id", state.SessionID),
+				slog.String("error", err.Error()))
+		}
+	}
+	if handler.condensed {
+		batch.afterCommit(save)
+	} else {
+		save()
 	}
 
 	// Only preserve shadow branch for active sessions that were NOT condensed.
@@ -1003,10 +1042,16 @@ func (s *ManualCommitStrategy) condenseAndUpdateState(
 	state.PromptAttributions = nil
 	state.PendingPromptAttribution = nil
 	state.FilesTouched = nil
+	state.TurnTimings = nil
 
 	// Save checkpoint ID so subsequent commits can reuse it (e.g., amend restores trailer)
 	state.LastCheckpointID = checkpointID
 
+	// The commit is now a logs-only rewind point
+	if commit, err := repo.CommitObject(head.Hash()); err == nil {
+		createPointRef(ctx, repo, head.Hash(), commit.Message)
+	}
+
 	logging.Info(logCtx, "session condensed",
 		slog.String("strategy", "manual-commit"),
 		slog.String("session_id", state.SessionID),
@@ -1637,6 +1682,9 @@ func (s *ManualCommitStrategy) InitializeSession(ctx context.Context, sessionID
 			return fmt.Errorf("failed to generate turn ID: %w", err)
 		}
 		state.TurnID = turnID.String()
+		state.TurnCount++
+		turnStart := time.Now()
+		state.TurnStartedAt = &turnStart
 
 		// Set AgentType from hook context if not yet set
 		if state.AgentType == "" && agentType != "" {
@@ -1648,6 +1696,11 @@ func (s *ManualCommitStrategy) InitializeSession(ctx context.Context, sessionID
 			state.FirstPrompt = truncatePromptForStorage(userPrompt)
 		}
 
+		// Pick up a correlation ID exported after the session started
+		if state.CorrelationID == "" {
+			state.CorrelationID = correlationIDFromEnv(ctx)
+		}
+
 		// Update transcript path if provided (may change on session resume)
 		if transcriptPath != "" && state.TranscriptPath != transcriptPath {
 			state.TranscriptPath = transcriptPath
@@ -1966,7 +2019,22 @@ func (s *ManualCommitStrategy) finalizeAllTurnCheckpoints(ctx context.Context, s
 		state.TurnCheckpointIDs = nil
 		return 1 // Count as error - all checkpoints will be skipped
 	}
-	store := checkpoint.NewGitStore(repo)
+	store, err := CommittedStore(ctx, checkpoint.NewGitStore(repo))
+	if err != nil {
+		logging.Warn(logCtx, "finalize: invalid checkpoint store settings, skipping",
+			slog.String("error", err.Error()),
+		)
+		state.TurnCheckpointIDs = nil
+		return 1 // Count as error - all checkpoints will be skipped
+	}
+	recipients, err := encryptionRecipients(ctx)
+	if err != nil {
+		logging.Warn(logCtx, "finalize: invalid encryption settings, skipping",
+			slog.String("error", err.Error()),
+		)
+		state.TurnCheckpointIDs = nil
+		return 1 // Count as error - all checkpoints will be skipped
+	}
 
 	// Update each checkpoint with the full transcript
 	for _, cpIDStr := range state.TurnCheckpointIDs {
@@ -1981,12 +2049,15 @@ func (s *ManualCommitStrategy) finalizeAllTurnCheckpoints(ctx context.Context, s
 		}
 
 		updateErr := store.UpdateCommitted(ctx, checkpoint.UpdateCommittedOptions{
-			CheckpointID: cpID,
-			SessionID:    state.SessionID,
-			Transcript:   fullTranscript,
-			Prompts:      prompts,
-			Context:      contextBytes,
-			Agent:        state.AgentType,
+			CheckpointID:      cpID,
+			SessionID:         state.SessionID,
+			Transcript:        fullTranscript,
+			TranscriptPointer: transcriptPointerOptions(ctx, state.TranscriptPath),
+			Prompts:           prompts,
+			Context:           contextBytes,
+			StructuredContext: sessionContextFromPrompts(state, prompts),
+			Agent:             state.AgentType,
+			EncryptTo:         recipients,
 		})
 		if updateErr != nil {
 			logging.Warn(logCtx, "finalize: failed to update checkpoint",
@@ -1997,6 +2068,12 @@ func (s *ManualCommitStrategy) finalizeAllTurnCheckpoints(ctx context.Context, s
 			continue
 		}
 
+		RecordEvent(ctx, events.Event{
+			Type:         events.TypeCheckpointUpdated,
+			SessionID:    state.SessionID,
+			CheckpointID: cpIDStr,
+			Agent:        string(state.AgentType),
+		})
 		logging.Info(logCtx, "finalize: checkpoint updated with full transcript",
 			slog.String("checkpoint_id", cpIDStr),
 			slog.String("session_id", state.SessionID),
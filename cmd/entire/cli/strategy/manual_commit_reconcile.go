@@ -0,0 +1,95 @@
+package strategy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/events"
+	"github.com/entireio/cli/cmd/entire/cli/logging"
+	"github.com/entireio/cli/redact"
+)
+
+// ReconcileTranscript updates the session's last committed checkpoint when the
+// transcript on disk has grown since the checkpoint was written. Some agents
+// flush their transcript only after the stop hook fired, so the checkpoint
+// condensed or finalized at that point holds a truncated transcript.
+//
+// Returns true if the checkpoint was updated. Sessions without a committed
+// checkpoint or a readable transcript are left alone. With strict, the update
+// fails if the checkpoint no longer has the session, instead of updating its
+// latest session (see UpdateCommittedOptions.Strict).
+func (s *ManualCommitStrategy) ReconcileTranscript(ctx context.Context, state *SessionState, strict bool) (bool, error) {
+	if state.LastCheckpointID.IsEmpty() || state.TranscriptPath == "" {
+		return false, nil
+	}
+	onDisk, err := os.ReadFile(state.TranscriptPath)
+	if err != nil || len(onDisk) == 0 {
+		return false, nil //nolint:nilerr // the agent may have cleaned up its transcript; nothing to reconcile
+	}
+	// Stored transcripts are redacted, so compare against the redacted form
+	redacted, err := redact.JSONLBytes(onDisk)
+	if err != nil {
+		return false, fmt.Errorf("failed to redact transcript secrets: %w", err)
+	}
+
+	repo, err := OpenRepository(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to open repository: %w", err)
+	}
+	store, err := CommittedStore(ctx, checkpoint.NewGitStore(repo))
+	if err != nil {
+		return false, err
+	}
+
+	content, err := store.ReadSessionContentByID(ctx, state.LastCheckpointID, state.SessionID)
+	// Without the key to an encrypted checkpoint there's nothing to compare against
+	if errors.Is(err, checkpoint.ErrCheckpointNotFound) || errors.Is(err, checkpoint.ErrNoIdentity) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to read checkpoint %s: %w", state.LastCheckpointID, err)
+	}
+	// Transcripts only grow while a session lives: a longer one on disk is the complete version.
+	// Checkpoints written without a transcript have nothing to complete.
+	if !content.Metadata.ContentLevel.StoresTranscript() || len(redacted) <= len(content.Transcript) {
+		return false, nil
+	}
+
+	recipients, err := encryptionRecipients(ctx)
+	if err != nil {
+		return false, err
+	}
+	prompts := extractUserPrompts(state.AgentType, string(onDisk))
+	if err := store.UpdateCommitted(ctx, checkpoint.UpdateCommittedOptions{
+		CheckpointID:      state.LastCheckpointID,
+		SessionID:         state.SessionID,
+		Transcript:        redacted,
+		TranscriptPointer: transcriptPointerOptions(ctx, state.TranscriptPath),
+		Prompts:           prompts,
+		Context:           generateContextFromPrompts(prompts),
+		StructuredContext: sessionContextFromPrompts(state, prompts),
+		Agent:             state.AgentType,
+		EncryptTo:         recipients,
+		Strict:            strict,
+	}); err != nil {
+		return false, fmt.Errorf("failed to update checkpoint %s: %w", state.LastCheckpointID, err)
+	}
+	RecordEvent(ctx, events.Event{
+		Type:         events.TypeCheckpointUpdated,
+		SessionID:    state.SessionID,
+		CheckpointID: state.LastCheckpointID.String(),
+		Agent:        string(state.AgentType),
+	})
+
+	logging.Info(logging.WithComponent(ctx, "checkpoint"), "reconciled late transcript",
+		slog.String("checkpoint_id", state.LastCheckpointID.String()),
+		slog.String("session_id", state.SessionID),
+		slog.Int("stored_bytes", len(content.Transcript)),
+		slog.Int("transcript_bytes", len(redacted)),
+	)
+	return true, nil
+}
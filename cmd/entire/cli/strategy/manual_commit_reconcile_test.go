@@ -0,0 +1,90 @@
+package strategy
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const reconcileTruncated = `{"type":"user","message":{"content":"add a flag"}}` + "\n"
+
+// setupReconcileSession writes a checkpoint holding the truncated transcript and
+// a session state pointing at transcript on disk.
+func setupReconcileSession(t *testing.T, onDisk string) *SessionState {
+	t.Helper()
+	dir := setupGitRepo(t)
+	t.Chdir(dir)
+	ctx := context.Background()
+
+	repo, err := OpenRepository(ctx)
+	require.NoError(t, err)
+	cpID := id.MustCheckpointID("a1b2c3d4e5f6")
+	require.NoError(t, checkpoint.NewGitStore(repo).WriteCommitted(ctx, checkpoint.WriteCommittedOptions{
+		CheckpointID: cpID,
+		SessionID:    "late-session",
+		Strategy:     StrategyNameManualCommit,
+		Transcript:   []byte(reconcileTruncated),
+		AuthorName:   "Test",
+		AuthorEmail:  "test@test.com",
+	}))
+
+	transcriptPath := filepath.Join(t.TempDir(), "transcript.jsonl")
+	require.NoError(t, os.WriteFile(transcriptPath, []byte(onDisk), 0o600))
+	return &SessionState{
+		SessionID:        "late-session",
+		AgentType:        "Claude Code",
+		TranscriptPath:   transcriptPath,
+		LastCheckpointID: cpID,
+	}
+}
+
+func readReconciledTranscript(t *testing.T, state *SessionState) string {
+	t.Helper()
+	repo, err := OpenRepository(context.Background())
+	require.NoError(t, err)
+	content, err := checkpoint.NewGitStore(repo).ReadSessionContentByID(context.Background(), state.LastCheckpointID, state.SessionID)
+	require.NoError(t, err)
+	return string(content.Transcript)
+}
+
+func TestReconcileTranscript_UpdatesTruncatedCheckpoint(t *testing.T) {
+	complete := reconcileTruncated + `{"type":"assistant","message":{"content":[{"type":"text","text":"Done."}]}}` + "\n"
+	state := setupReconcileSession(t, complete)
+	s := &ManualCommitStrategy{}
+
+	updated, err := s.ReconcileTranscript(context.Background(), state, false)
+	require.NoError(t, err)
+	assert.True(t, updated)
+	assert.Equal(t, complete, readReconciledTranscript(t, state))
+
+	// A second pass finds nothing left to do
+	updated, err = s.ReconcileTranscript(context.Background(), state, false)
+	require.NoError(t, err)
+	assert.False(t, updated)
+}
+
+func TestReconcileTranscript_CompleteCheckpointUnchanged(t *testing.T) {
+	state := setupReconcileSession(t, reconcileTruncated)
+	s := &ManualCommitStrategy{}
+
+	updated, err := s.ReconcileTranscript(context.Background(), state, false)
+	require.NoError(t, err)
+	assert.False(t, updated)
+	assert.Equal(t, reconcileTruncated, readReconciledTranscript(t, state))
+}
+
+func TestReconcileTranscript_NoCheckpoint(t *testing.T) {
+	t.Parallel()
+	s := &ManualCommitStrategy{}
+
+	updated, err := s.ReconcileTranscript(context.Background(), &SessionState{SessionID: "fresh", TranscriptPath: "/nonexistent"}, false)
+	require.NoError(t, err)
+	assert.False(t, updated)
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
 	"github.com/entireio/cli/cmd/entire/cli/paths"
 
 	"github.com/go-git/go-git/v5/plumbing"
@@ -78,13 +79,21 @@ func (s *ManualCommitStrategy) Reset(ctx context.Context) error {
 	}
 
 	// Delete the shadow branch if it exists
+	removed := clearedSessions
 	if hasShadowBranch {
 		if err := DeleteBranchCLI(ctx, shadowBranchName); err != nil {
 			return fmt.Errorf("failed to delete shadow branch: %w", err)
 		}
 		fmt.Fprintf(os.Stderr, "Deleted shadow branch %s\n", shadowBranchName)
+		removed = append(removed, shadowBranchName)
 	}
 
+	RecordAudit(ctx, checkpoint.AuditEntry{
+		Operation: checkpoint.AuditOpReset,
+		Refs:      []string{head.Hash().String()},
+		Removed:   removed,
+	})
+
 	return nil
 }
 
@@ -105,6 +114,7 @@ func (s *ManualCommitStrategy) ResetSession(ctx context.Context, sessionID strin
 		return fmt.Errorf("failed to clear session state: %w", err)
 	}
 	fmt.Fprintf(os.Stderr, "Cleared session state for %s\n", sessionID)
+	removed := []string{sessionID}
 
 	// Determine the shadow branch for this session
 	shadowBranchName := getShadowBranchNameForCommit(state.BaseCommit, state.WorktreeID)
@@ -123,8 +133,15 @@ func (s *ManualCommitStrategy) ResetSession(ctx context.Context, sessionID strin
 		// may be stale after CLI-based deletion with packed refs)
 		if err := branchExistsCLI(ctx, shadowBranchName); err != nil {
 			fmt.Fprintf(os.Stderr, "Deleted shadow branch %s\n", shadowBranchName)
+			removed = append(removed, shadowBranchName)
 		}
 	}
 
+	RecordAudit(ctx, checkpoint.AuditEntry{
+		Operation: checkpoint.AuditOpReset,
+		Refs:      []string{state.BaseCommit},
+		Removed:   removed,
+	})
+
 	return nil
 }
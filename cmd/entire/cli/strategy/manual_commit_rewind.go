@@ -14,6 +14,8 @@ import (
 	"github.com/entireio/cli/cmd/entire/cli/agent/types"
 	cpkg "github.com/entireio/cli/cmd/entire/cli/checkpoint"
 	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+	"github.com/entireio/cli/cmd/entire/cli/events"
+	"github.com/entireio/cli/cmd/entire/cli/i18n"
 	"github.com/entireio/cli/cmd/entire/cli/paths"
 	"github.com/entireio/cli/cmd/entire/cli/trailers"
 
@@ -83,8 +85,13 @@ func (s *ManualCommitStrategy) GetRewindPoints(ctx context.Context, limit int) (
 				Agent:            state.AgentType,
 			})
 		}
+
+		allPoints = append(allPoints, snapshotRewindPoints(ctx, store, head.Hash(), state.SessionID, state.AgentType)...)
 	}
 
+	// Manual snapshots belong to no session
+	allPoints = append(allPoints, snapshotRewindPoints(ctx, store, head.Hash(), cpkg.ManualSnapshotSessionID, "")...)
+
 	// Sort by date, most recent first
 	sort.Slice(allPoints, func(i, j int) bool {
 		return allPoints[i].Date.After(allPoints[j].Date)
@@ -271,11 +278,24 @@ func (s *ManualCommitStrategy) Rewind(ctx context.Context, point RewindPoint) er
 		return fmt.Errorf("failed to get tree: %w", err)
 	}
 
+	// Fail before touching the working tree if the disk can't hold the restored files
+	if err := checkRewindDiskSpace(ctx, tree); err != nil {
+		return err
+	}
+
+	// Back up the current state first, so a rewind that goes wrong can be undone
+	if _, err := s.BackupBeforeRewind(ctx); err != nil {
+		return fmt.Errorf("refusing to rewind without a backup: %w", err)
+	}
+
 	// Reset the shadow branch to the rewound checkpoint
-	// This ensures the next checkpoint will only include prompts from this point forward
-	if err := s.resetShadowBranchToCheckpoint(ctx, repo, commit); err != nil {
-		// Log warning but don't fail - file restoration is the primary operation
-		fmt.Fprintf(os.Stderr, "[entire] Warning: failed to reset shadow branch: %v\n", err)
+	// This ensures the next checkpoint will only include prompts from this point forward.
+	// Snapshots aren't on the shadow branch, so there is nothing to reset.
+	if !point.IsSnapshot {
+		if err := s.resetShadowBranchToCheckpoint(ctx, repo, commit); err != nil {
+			// Log warning but don't fail - file restoration is the primary operation
+			fmt.Fprintf(os.Stderr, "[entire] Warning: failed to reset shadow branch: %v\n", err)
+		}
 	}
 
 	// Load session state to get untracked files that existed at session start
@@ -338,6 +358,7 @@ func (s *ManualCommitStrategy) Rewind(ctx context.Context, point RewindPoint) er
 	}
 
 	// Find and delete untracked files that aren't in the checkpoint.
+	var deletedFiles []string
 	// Uses git ls-files to only consider non-ignored files, avoiding walks through
 	// large ignored directories like node_modules/.
 	untrackedNow, err := collectUntrackedFiles(ctx)
@@ -365,6 +386,7 @@ func (s *ManualCommitStrategy) Rewind(ctx context.Context, point RewindPoint) er
 		absPath := filepath.Join(repoRoot, relPath)
 		if removeErr := os.Remove(absPath); removeErr == nil {
 			fmt.Fprintf(os.Stderr, "  Deleted: %s\n", relPath)
+			deletedFiles = append(deletedFiles, relPath)
 		}
 	}
 
@@ -408,6 +430,10 @@ func (s *ManualCommitStrategy) Rewind(ctx context.Context, point RewindPoint) er
 		return fmt.Errorf("failed to iterate tree files: %w", err)
 	}
 
+	if err := verifyRestoredFiles(ctx, repoRoot, tree); err != nil {
+		return fmt.Errorf("%w\nRun 'entire rewind --abort' to return to the state before the rewind", err)
+	}
+
 	fmt.Println()
 	if len(point.ID) >= 7 {
 		fmt.Printf("Restored files from shadow commit %s\n", point.ID[:7])
@@ -416,6 +442,14 @@ func (s *ManualCommitStrategy) Rewind(ctx context.Context, point RewindPoint) er
 	}
 	fmt.Println()
 
+	RecordAudit(ctx, cpkg.AuditEntry{
+		Operation: cpkg.AuditOpRewind,
+		Refs:      []string{point.ID},
+		Removed:   deletedFiles,
+		Details:   "restored working tree from shadow commit",
+	})
+	RecordEvent(ctx, events.Event{Type: events.TypeRewind, SessionID: point.SessionID, Agent: string(point.Agent), Ref: point.ID})
+
 	return nil
 }
 
@@ -590,7 +624,7 @@ func (s *ManualCommitStrategy) RestoreLogsOnly(ctx context.Context, point Rewind
 	}
 
 	// Get checkpoint store
-	store, err := s.getCheckpointStore()
+	store, err := s.getCommittedStore(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get checkpoint store: %w", err)
 	}
@@ -676,7 +710,7 @@ func (s *ManualCommitStrategy) RestoreLogsOnly(ctx context.Context, point Rewind
 		sessionFile := sessionAgent.ResolveSessionFile(sessionAgentDir, sessionID)
 
 		// Get first prompt for display
-		promptPreview := ExtractFirstPrompt(content.Prompts)
+		promptPreview := FirstPrompt(content.PromptList)
 
 		if totalSessions > 1 {
 			isLatest := i == totalSessions-1
@@ -819,7 +853,7 @@ func (s *ManualCommitStrategy) classifySessionsForRestore(ctx context.Context, r
 
 		sessions = append(sessions, SessionRestoreInfo{
 			SessionID:      sessionID,
-			Prompt:         ExtractFirstPrompt(content.Prompts),
+			Prompt:         FirstPrompt(content.PromptList),
 			Status:         status,
 			LocalTime:      localTime,
 			CheckpointTime: checkpointTime,
@@ -911,7 +945,9 @@ func PromptOverwriteNewerLogs(sessions []SessionRestoreInfo) (bool, error) {
 	form := huh.NewForm(
 		huh.NewGroup(
 			huh.NewConfirm().
-				Title("Overwrite local session logs with checkpoint versions?").
+				Title(i18n.T(i18n.ConfirmOverwriteLogs)).
+				Affirmative(i18n.T(i18n.Yes)).
+				Negative(i18n.T(i18n.No)).
 				Value(&confirmed),
 		),
 	)
@@ -0,0 +1,206 @@
+package strategy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	cpkg "github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/events"
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// ErrRewindVerification is returned when the files on disk don't match the
+// checkpoint after a rewind.
+var ErrRewindVerification = errors.New("restored files don't match the checkpoint")
+
+// rewindBackupRef returns the rewind backup ref of the current worktree.
+func rewindBackupRef(ctx context.Context) (plumbing.ReferenceName, error) {
+	repoRoot, err := paths.WorktreeRoot(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to get worktree root: %w", err)
+	}
+	worktreeID, err := paths.GetWorktreeID(repoRoot)
+	if err != nil {
+		return "", fmt.Errorf("failed to get worktree ID: %w", err)
+	}
+	return cpkg.RewindBackupRefName(worktreeID), nil
+}
+
+// BackupBeforeRewind records HEAD and the working tree, including uncommitted
+// and untracked changes, so AbortRewind can return to them. Each rewind
+// replaces the previous backup.
+func (s *ManualCommitStrategy) BackupBeforeRewind(ctx context.Context) (plumbing.Hash, error) {
+	refName, err := rewindBackupRef(ctx)
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+	store, err := s.getCheckpointStore()
+	if err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("failed to get checkpoint store: %w", err)
+	}
+	authorName, authorEmail := GetGitAuthorFromRepo(store.Repository())
+	backup, err := store.WriteRewindBackup(ctx, refName, authorName, authorEmail)
+	if err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("failed to back up working tree: %w", err)
+	}
+	return backup, nil
+}
+
+// AbortRewind returns HEAD and the working tree to the backup taken before
+// the last rewind, then removes the backup. Changes that were staged before
+// the rewind come back unstaged. Returns the backup commit.
+func (s *ManualCommitStrategy) AbortRewind(ctx context.Context) (plumbing.Hash, error) {
+	refName, err := rewindBackupRef(ctx)
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+	store, err := s.getCheckpointStore()
+	if err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("failed to get checkpoint store: %w", err)
+	}
+	backupHash, err := store.ReadRewindBackup(refName)
+	if err != nil {
+		return plumbing.ZeroHash, err //nolint:wrapcheck // ErrNoRewindBackup is checked by callers
+	}
+	backup, err := store.Repository().CommitObject(backupHash)
+	if err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("failed to read rewind backup: %w", err)
+	}
+	if len(backup.ParentHashes) == 0 {
+		return plumbing.ZeroHash, errors.New("rewind backup has no base commit")
+	}
+	backupTree, err := backup.Tree()
+	if err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("failed to read rewind backup tree: %w", err)
+	}
+	backupFiles := make(map[string]bool)
+	if err := backupTree.Files().ForEach(func(f *object.File) error {
+		backupFiles[f.Name] = true
+		return nil
+	}); err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("failed to list rewind backup files: %w", err)
+	}
+
+	repoRoot, err := paths.WorktreeRoot(ctx)
+	if err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("failed to get worktree root: %w", err)
+	}
+
+	// Files the rewind created without committing them; reset leaves them behind
+	untracked, err := collectUntrackedFiles(ctx)
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+	if _, err := HardResetWithProtection(ctx, backup.ParentHashes[0]); err != nil {
+		return plumbing.ZeroHash, err
+	}
+	for _, relPath := range untracked {
+		if !backupFiles[relPath] {
+			_ = os.Remove(filepath.Join(repoRoot, relPath)) //nolint:errcheck // verification below catches leftovers that matter
+		}
+	}
+
+	// The reset restored HEAD; lay the uncommitted changes back on top
+	baseCommit, err := store.Repository().CommitObject(backup.ParentHashes[0])
+	if err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("failed to read backup base commit: %w", err)
+	}
+	baseTree, err := baseCommit.Tree()
+	if err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("failed to read backup base tree: %w", err)
+	}
+	if err := baseTree.Files().ForEach(func(f *object.File) error {
+		if !backupFiles[f.Name] {
+			_ = os.Remove(filepath.Join(repoRoot, f.Name)) //nolint:errcheck // already gone is fine
+		}
+		return nil
+	}); err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("failed to list backup base files: %w", err)
+	}
+	if err := writeTreeFiles(ctx, repoRoot, backupTree); err != nil {
+		return plumbing.ZeroHash, err
+	}
+	if err := verifyRestoredFiles(ctx, repoRoot, backupTree); err != nil {
+		return plumbing.ZeroHash, err
+	}
+
+	if err := store.DeleteRewindBackup(refName); err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("failed to remove rewind backup: %w", err)
+	}
+	RecordAudit(ctx, cpkg.AuditEntry{
+		Operation: cpkg.AuditOpRewind,
+		Refs:      []string{backupHash.String()},
+		Details:   "aborted rewind, restored working tree from backup",
+	})
+	RecordEvent(ctx, events.Event{Type: events.TypeRewind, Ref: backupHash.String()})
+	return backupHash, nil
+}
+
+// writeTreeFiles writes every file of tree, except Entire metadata, below repoRoot.
+func writeTreeFiles(ctx context.Context, repoRoot string, tree *object.Tree) error {
+	err := tree.Files().ForEach(func(f *object.File) error {
+		if err := ctx.Err(); err != nil {
+			return err //nolint:wrapcheck // Propagating context cancellation
+		}
+		if strings.HasPrefix(f.Name, entireDir) {
+			return nil
+		}
+		contents, err := f.Contents()
+		if err != nil {
+			return fmt.Errorf("failed to read file %s: %w", f.Name, err)
+		}
+		absPath := filepath.Join(repoRoot, f.Name)
+		//nolint:gosec // G301: Need 0o755 for user directories during rewind
+		if err := os.MkdirAll(filepath.Dir(absPath), 0o755); err != nil {
+			return fmt.Errorf("failed to create directory for %s: %w", f.Name, err)
+		}
+		var perm os.FileMode = 0o644
+		if f.Mode == filemode.Executable {
+			perm = 0o755
+		}
+		if err := os.WriteFile(absPath, []byte(contents), perm); err != nil {
+			return fmt.Errorf("failed to write file %s: %w", f.Name, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to restore files: %w", err)
+	}
+	return nil
+}
+
+// verifyRestoredFiles checks that every file of tree, except Entire metadata,
+// exists below repoRoot with the content the tree records for it.
+func verifyRestoredFiles(ctx context.Context, repoRoot string, tree *object.Tree) error {
+	var mismatched []string
+	err := tree.Files().ForEach(func(f *object.File) error {
+		if err := ctx.Err(); err != nil {
+			return err //nolint:wrapcheck // Propagating context cancellation
+		}
+		if strings.HasPrefix(f.Name, entireDir) {
+			return nil
+		}
+		data, readErr := os.ReadFile(filepath.Join(repoRoot, f.Name))
+		if readErr != nil || plumbing.ComputeHash(plumbing.BlobObject, data) != f.Hash {
+			mismatched = append(mismatched, f.Name)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to verify restored files: %w", err)
+	}
+	if len(mismatched) > 0 {
+		sort.Strings(mismatched)
+		return fmt.Errorf("%w: %s", ErrRewindVerification, strings.Join(mismatched, ", "))
+	}
+	return nil
+}
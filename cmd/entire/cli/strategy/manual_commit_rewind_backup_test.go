@@ -0,0 +1,102 @@
+package strategy
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAbortRewind_RestoresWorkingTree(t *testing.T) {
+	dir := setupGitRepo(t)
+	t.Chdir(dir)
+	s := &ManualCommitStrategy{}
+	ctx := context.Background()
+
+	// Uncommitted and untracked work before the rewind
+	require.NoError(t, writeTestFile(filepath.Join(dir, "test.txt"), "edited, not committed"))
+	require.NoError(t, writeTestFile(filepath.Join(dir, "notes.txt"), "untracked notes"))
+	_, err := s.BackupBeforeRewind(ctx)
+	require.NoError(t, err)
+
+	// A rewind that went wrong
+	require.NoError(t, writeTestFile(filepath.Join(dir, "test.txt"), "half restored"))
+	require.NoError(t, os.Remove(filepath.Join(dir, "notes.txt")))
+	require.NoError(t, writeTestFile(filepath.Join(dir, "stray.txt"), "left by the rewind"))
+
+	_, err = s.AbortRewind(ctx)
+	require.NoError(t, err)
+
+	got, err := os.ReadFile(filepath.Join(dir, "test.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "edited, not committed", string(got))
+	got, err = os.ReadFile(filepath.Join(dir, "notes.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "untracked notes", string(got))
+	assert.NoFileExists(t, filepath.Join(dir, "stray.txt"))
+
+	// The backup is used up
+	_, err = s.AbortRewind(ctx)
+	require.ErrorIs(t, err, checkpoint.ErrNoRewindBackup)
+}
+
+func TestAbortRewind_RestoresHeadAfterReset(t *testing.T) {
+	dir := setupGitRepo(t)
+	t.Chdir(dir)
+	s := &ManualCommitStrategy{}
+	ctx := context.Background()
+
+	repo, err := git.PlainOpen(dir)
+	require.NoError(t, err)
+	initial, err := repo.Head()
+	require.NoError(t, err)
+	wt, err := repo.Worktree()
+	require.NoError(t, err)
+	require.NoError(t, writeTestFile(filepath.Join(dir, "test.txt"), "second version"))
+	_, err = wt.Add("test.txt")
+	require.NoError(t, err)
+	second, err := wt.Commit("second commit", &git.CommitOptions{})
+	require.NoError(t, err)
+
+	_, err = s.BackupBeforeRewind(ctx)
+	require.NoError(t, err)
+	_, err = HardResetWithProtection(ctx, initial.Hash())
+	require.NoError(t, err)
+
+	_, err = s.AbortRewind(ctx)
+	require.NoError(t, err)
+
+	head, err := repo.Head()
+	require.NoError(t, err)
+	assert.Equal(t, second, head.Hash())
+	got, err := os.ReadFile(filepath.Join(dir, "test.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "second version", string(got))
+}
+
+func TestVerifyRestoredFiles(t *testing.T) {
+	dir := setupGitRepo(t)
+	ctx := context.Background()
+
+	repo, err := git.PlainOpen(dir)
+	require.NoError(t, err)
+	head, err := repo.Head()
+	require.NoError(t, err)
+	commit, err := repo.CommitObject(head.Hash())
+	require.NoError(t, err)
+	tree, err := commit.Tree()
+	require.NoError(t, err)
+
+	require.NoError(t, verifyRestoredFiles(ctx, dir, tree))
+
+	require.NoError(t, writeTestFile(filepath.Join(dir, "test.txt"), "not what the checkpoint holds"))
+	err = verifyRestoredFiles(ctx, dir, tree)
+	require.ErrorIs(t, err, ErrRewindVerification)
+	assert.Contains(t, err.Error(), "test.txt")
+}
@@ -3,12 +3,17 @@ package strategy
 import (
 	"context"
 	"fmt"
+	"log/slog"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/entireio/cli/cmd/entire/cli/agent/types"
 	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
 	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+	"github.com/entireio/cli/cmd/entire/cli/logging"
 	"github.com/entireio/cli/cmd/entire/cli/paths"
+	"github.com/entireio/cli/cmd/entire/cli/validation"
 	"github.com/entireio/cli/cmd/entire/cli/versioninfo"
 
 	"github.com/go-git/go-git/v5"
@@ -52,6 +57,7 @@ func (s *ManualCommitStrategy) clearSessionState(ctx context.Context, sessionID
 	if err := store.Clear(ctx, sessionID); err != nil {
 		return fmt.Errorf("failed to clear session state: %w", err)
 	}
+	s.deleteSessionSnapshots(ctx, sessionID, plumbing.ZeroHash)
 	return nil
 }
 
@@ -234,20 +240,45 @@ func (s *ManualCommitStrategy) initializeSession(ctx context.Context, repo *git.
 		StartedAt:             now,
 		LastInteractionTime:   &now,
 		TurnID:                turnID.String(),
+		TurnStartedAt:         &now,
+		TurnCount:             1,
 		StepCount:             0,
 		UntrackedFilesAtStart: untrackedFiles,
 		AgentType:             agentType,
 		TranscriptPath:        transcriptPath,
 		FirstPrompt:           truncatePromptForStorage(userPrompt),
+		CorrelationID:         correlationIDFromEnv(ctx),
 	}
 
 	if err := s.saveSessionState(ctx, state); err != nil {
 		return nil, err
 	}
+	// Keep the base commit reachable even if the user's branch moves away from it
+	if _, err := anchorSessionCommits(repo, state); err != nil {
+		logging.Warn(logging.WithComponent(ctx, "session"), "failed to anchor session base commit",
+			slog.String("error", err.Error()))
+	}
 
 	return state, nil
 }
 
+// CorrelationIDEnvVar names the environment variable that links a session to
+// sessions in other repositories. Export the same value before starting the
+// agent in each repository.
+const CorrelationIDEnvVar = "ENTIRE_CORRELATION_ID"
+
+// correlationIDFromEnv returns the correlation ID from the environment,
+// or "" if unset or invalid.
+func correlationIDFromEnv(ctx context.Context) string {
+	correlationID := strings.TrimSpace(os.Getenv(CorrelationIDEnvVar))
+	if err := validation.ValidateCorrelationID(correlationID); err != nil {
+		logging.Warn(logging.WithComponent(ctx, "session"), "ignoring invalid correlation ID",
+			slog.String("error", err.Error()))
+		return ""
+	}
+	return correlationID
+}
+
 // getShadowBranchNameForCommit returns the shadow branch name for the given base commit and worktree ID.
 // worktreeID should be empty for the main worktree or the internal git worktree name for linked worktrees.
 func getShadowBranchNameForCommit(baseCommit, worktreeID string) string {
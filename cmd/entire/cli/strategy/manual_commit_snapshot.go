@@ -0,0 +1,141 @@
+package strategy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/entireio/cli/cmd/entire/cli/agent/types"
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// snapshotMessage is the rewind point message for pre-turn snapshots.
+const snapshotMessage = "Before turn (uncommitted changes)"
+
+// SnapshotUncommittedChanges records the user's uncommitted changes into a
+// snapshot ref for the session's current turn, so rewinding to before the turn
+// restores them exactly. Must be called after InitializeSession, which assigns
+// the turn ID. Returns false if the working tree is clean.
+func (s *ManualCommitStrategy) SnapshotUncommittedChanges(ctx context.Context, sessionID string) (bool, error) {
+	state, err := s.loadSessionState(ctx, sessionID)
+	if err != nil {
+		return false, err
+	}
+	if state == nil || state.TurnID == "" {
+		return false, errors.New("session has no active turn")
+	}
+
+	store, err := s.getCheckpointStore()
+	if err != nil {
+		return false, fmt.Errorf("failed to get checkpoint store: %w", err)
+	}
+	repo := store.Repository()
+	authorName, authorEmail := GetGitAuthorFromRepo(repo)
+
+	// Snapshots taken on an earlier HEAD can no longer be offered as rewind points
+	if head, headErr := repo.Head(); headErr == nil {
+		s.deleteSessionSnapshots(ctx, sessionID, head.Hash())
+	}
+
+	commitHash, written, err := store.WriteSnapshot(ctx, checkpoint.WriteSnapshotOptions{
+		SessionID:   sessionID,
+		TurnID:      state.TurnID,
+		AuthorName:  authorName,
+		AuthorEmail: authorEmail,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	if written {
+		createPointRef(ctx, repo, commitHash, snapshotMessage)
+	}
+	return written, nil
+}
+
+// manualSnapshotMessage is the message of manual snapshots taken without one.
+const manualSnapshotMessage = "Manual snapshot"
+
+// SnapshotWorktree records the worktree's uncommitted changes as a manual
+// snapshot, outside any agent session, so it can be rewound to like a
+// pre-turn snapshot while HEAD stays the same. Returns false if the working
+// tree is clean or still matches the previous snapshot; pass
+// plumbing.ZeroHash to always take one.
+func (s *ManualCommitStrategy) SnapshotWorktree(ctx context.Context, message string, previous plumbing.Hash) (plumbing.Hash, bool, error) {
+	if message == "" {
+		message = manualSnapshotMessage
+	}
+	store, err := s.getCheckpointStore()
+	if err != nil {
+		return plumbing.ZeroHash, false, fmt.Errorf("failed to get checkpoint store: %w", err)
+	}
+	repo := store.Repository()
+	authorName, authorEmail := GetGitAuthorFromRepo(repo)
+
+	commitHash, written, err := store.WriteSnapshot(ctx, checkpoint.WriteSnapshotOptions{
+		SessionID:   checkpoint.ManualSnapshotSessionID,
+		TurnID:      time.Now().UTC().Format("20060102T150405.000000000Z"),
+		AuthorName:  authorName,
+		AuthorEmail: authorEmail,
+		Message:     message,
+		Previous:    previous,
+	})
+	if err != nil {
+		return plumbing.ZeroHash, false, fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	if written {
+		createPointRef(ctx, repo, commitHash, message)
+	}
+	return commitHash, written, nil
+}
+
+// snapshotRewindPoints returns rewind points for the session's pre-turn
+// snapshots taken on top of head. Snapshots on other commits are skipped
+// because restoring them would mix in a different base.
+func snapshotRewindPoints(ctx context.Context, store *checkpoint.GitStore, head plumbing.Hash, sessionID string, agentType types.AgentType) []RewindPoint {
+	snapshots, err := store.ListSnapshots(ctx, sessionID)
+	if err != nil {
+		return nil
+	}
+	var points []RewindPoint
+	for _, snap := range snapshots {
+		if snap.BaseCommit != head {
+			continue
+		}
+		message := snapshotMessage
+		if sessionID == checkpoint.ManualSnapshotSessionID {
+			message = snap.Message
+		}
+		points = append(points, RewindPoint{
+			ID:         snap.CommitHash.String(),
+			Message:    message,
+			Date:       snap.Timestamp,
+			SessionID:  snap.SessionID,
+			Agent:      agentType,
+			IsSnapshot: true,
+		})
+	}
+	return points
+}
+
+// deleteSessionSnapshots removes the session's snapshot refs, except those
+// taken on top of keepBase. Pass plumbing.ZeroHash to remove all of them.
+// Failures are ignored; leftover refs are harmless.
+func (s *ManualCommitStrategy) deleteSessionSnapshots(ctx context.Context, sessionID string, keepBase plumbing.Hash) {
+	store, err := s.getCheckpointStore()
+	if err != nil {
+		return
+	}
+	snapshots, err := store.ListSnapshots(ctx, sessionID)
+	if err != nil {
+		return
+	}
+	for _, snap := range snapshots {
+		if keepBase != plumbing.ZeroHash && snap.BaseCommit == keepBase {
+			continue
+		}
+		_ = store.DeleteSnapshot(snap.RefName) //nolint:errcheck // Best-effort cleanup
+	}
+}
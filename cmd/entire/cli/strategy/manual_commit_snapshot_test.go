@@ -0,0 +1,105 @@
+package strategy
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+func TestSnapshotUncommittedChanges_RewindRestoresUserChanges(t *testing.T) {
+	dir := t.TempDir()
+	repo, err := git.PlainInit(dir, false)
+	if err != nil {
+		t.Fatalf("failed to init repo: %v", err)
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		t.Fatalf("failed to get worktree: %v", err)
+	}
+	readme := filepath.Join(dir, "README.md")
+	if err := os.WriteFile(readme, []byte("# Test\n"), 0o644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	if _, err := worktree.Add("README.md"); err != nil {
+		t.Fatalf("failed to stage file: %v", err)
+	}
+	if _, err := worktree.Commit("Initial commit", &git.CommitOptions{
+		Author: &object.Signature{Name: "Test", Email: "test@test.com", When: time.Now()},
+	}); err != nil {
+		t.Fatalf("failed to commit: %v", err)
+	}
+	t.Chdir(dir)
+
+	s := &ManualCommitStrategy{}
+	ctx := context.Background()
+	sessionID := "2026-01-01-snapshot-session"
+
+	// The user has local edits when the turn starts
+	userContent := "# Test\n\nUser notes\n"
+	if err := os.WriteFile(readme, []byte(userContent), 0o644); err != nil {
+		t.Fatalf("failed to modify README: %v", err)
+	}
+	if err := s.InitializeSession(ctx, sessionID, "Claude Code", "", ""); err != nil {
+		t.Fatalf("InitializeSession() error = %v", err)
+	}
+	written, err := s.SnapshotUncommittedChanges(ctx, sessionID)
+	if err != nil || !written {
+		t.Fatalf("SnapshotUncommittedChanges() = %v, %v; want true, nil", written, err)
+	}
+
+	// The agent then edits the same file and adds another
+	if err := os.WriteFile(readme, []byte("# Rewritten by agent\n"), 0o644); err != nil {
+		t.Fatalf("failed to modify README: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "agent.go"), []byte("package main\n"), 0o644); err != nil {
+		t.Fatalf("failed to write agent file: %v", err)
+	}
+
+	points, err := s.GetRewindPoints(ctx, 10)
+	if err != nil {
+		t.Fatalf("GetRewindPoints() error = %v", err)
+	}
+	var snapshot *RewindPoint
+	for i := range points {
+		if points[i].IsSnapshot {
+			snapshot = &points[i]
+		}
+	}
+	if snapshot == nil {
+		t.Fatalf("GetRewindPoints() = %+v, want a snapshot point", points)
+	}
+	if snapshot.SessionID != sessionID {
+		t.Errorf("snapshot SessionID = %q, want %q", snapshot.SessionID, sessionID)
+	}
+
+	if err := s.Rewind(ctx, *snapshot); err != nil {
+		t.Fatalf("Rewind() error = %v", err)
+	}
+	got, err := os.ReadFile(readme)
+	if err != nil {
+		t.Fatalf("failed to read README: %v", err)
+	}
+	if string(got) != userContent {
+		t.Errorf("README after rewind = %q, want user's uncommitted content %q", got, userContent)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "agent.go")); !os.IsNotExist(err) {
+		t.Errorf("agent.go should be removed by rewind, stat err = %v", err)
+	}
+
+	// Clearing the session drops its snapshots
+	if err := s.clearSessionState(ctx, sessionID); err != nil {
+		t.Fatalf("clearSessionState() error = %v", err)
+	}
+	store, err := s.getCheckpointStore()
+	if err != nil {
+		t.Fatalf("getCheckpointStore() error = %v", err)
+	}
+	if snapshots, err := store.ListSnapshots(ctx, sessionID); err != nil || len(snapshots) != 0 {
+		t.Errorf("ListSnapshots() after clear = %v, %v; want none", snapshots, err)
+	}
+}
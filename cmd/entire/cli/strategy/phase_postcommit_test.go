@@ -4,6 +4,7 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -69,6 +70,52 @@ func TestPostCommit_ActiveSession_CondensesImmediately(t *testing.T) {
 		"StepCount should be reset after immediate condensation")
 }
 
+// TestPostCommit_TwoSessions_OneMetadataCommit verifies that sessions
+// condensed by the same commit are written to the metadata branch together.
+func TestPostCommit_TwoSessions_OneMetadataCommit(t *testing.T) {
+	dir := setupGitRepo(t)
+	t.Chdir(dir)
+
+	repo, err := git.PlainOpen(dir)
+	require.NoError(t, err)
+
+	s := &ManualCommitStrategy{}
+	for _, sessionID := range []string{"test-postcommit-batch-1", "test-postcommit-batch-2"} {
+		setupSessionWithCheckpoint(t, s, repo, dir, sessionID)
+		state, err := s.loadSessionState(context.Background(), sessionID)
+		require.NoError(t, err)
+		state.Phase = session.PhaseActive
+		require.NoError(t, s.saveSessionState(context.Background(), state))
+	}
+
+	commitWithCheckpointTrailer(t, repo, dir, "d4e5f6a1b2c3")
+	require.NoError(t, s.PostCommit(context.Background()))
+
+	summary, err := checkpoint.NewGitStore(repo).ReadCommitted(context.Background(), id.MustCheckpointID("d4e5f6a1b2c3"))
+	require.NoError(t, err)
+	require.NotNil(t, summary)
+	assert.Len(t, summary.Sessions, 2, "both sessions should be condensed into the checkpoint")
+
+	ref, err := repo.Reference(plumbing.NewBranchReferenceName(paths.MetadataBranchName), true)
+	require.NoError(t, err)
+	iter, err := repo.Log(&git.LogOptions{From: ref.Hash()})
+	require.NoError(t, err)
+	var checkpointCommits int
+	require.NoError(t, iter.ForEach(func(c *object.Commit) error {
+		if strings.Contains(c.Message, "d4e5f6a1b2c3") {
+			checkpointCommits++
+		}
+		return nil
+	}))
+	assert.Equal(t, 1, checkpointCommits, "both sessions should be written in one metadata commit")
+
+	for _, sessionID := range []string{"test-postcommit-batch-1", "test-postcommit-batch-2"} {
+		state, err := s.loadSessionState(context.Background(), sessionID)
+		require.NoError(t, err)
+		assert.Equal(t, 0, state.StepCount, "session state should be saved after the batch is written")
+	}
+}
+
 // TestPostCommit_IdleSession_Condenses verifies that PostCommit on an IDLE
 // session condenses session data and cleans up the shadow branch.
 func TestPostCommit_IdleSession_Condenses(t *testing.T) {
@@ -53,6 +53,7 @@ func TestInitializeSession_IdleToActive(t *testing.T) {
 	// Manually set to IDLE (simulating post-Stop state)
 	state, err := s.loadSessionState(context.Background(), "test-session-idle")
 	require.NoError(t, err)
+	assert.Equal(t, 1, state.TurnCount)
 	state.Phase = session.PhaseIdle
 	state.LastInteractionTime = nil
 	err = s.saveSessionState(context.Background(), state)
@@ -66,6 +67,7 @@ func TestInitializeSession_IdleToActive(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, session.PhaseActive, state.Phase)
 	require.NotNil(t, state.LastInteractionTime)
+	assert.Equal(t, 2, state.TurnCount, "each prompt should count a turn")
 }
 
 // TestInitializeSession_ActiveToActive_CtrlCRecovery verifies Ctrl-C recovery:
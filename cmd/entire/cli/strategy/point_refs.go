@@ -0,0 +1,126 @@
+package strategy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/entireio/cli/cmd/entire/cli/logging"
+	"github.com/entireio/cli/cmd/entire/cli/settings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+)
+
+// PointRefPrefix is the ref namespace of the human-friendly refs created for
+// rewind points when point_refs is set, so plain git and CI can name
+// checkpoints. Each point gets refs/entire/points/<n>-<slug>, numbered in
+// creation order and named after its prompt or commit subject, e.g.
+// refs/entire/points/12-add-a-login-form.
+const PointRefPrefix = "refs/entire/points/"
+
+// maxPointSlugLength bounds the slug part of a point ref name.
+const maxPointSlugLength = 40
+
+// createPointRef gives the rewind point at commit the next point ref when
+// point_refs is set. A commit that already has a point ref keeps it. The
+// checkpoint is saved either way, so failures are only logged.
+func createPointRef(ctx context.Context, repo *git.Repository, commit plumbing.Hash, message string) {
+	s, err := settings.Load(ctx)
+	if err != nil || !s.PointRefs || commit.IsZero() {
+		return
+	}
+	refName, err := nextPointRefName(repo, commit, message)
+	if err == nil && refName != "" {
+		err = repo.Storer.SetReference(plumbing.NewHashReference(refName, commit))
+	}
+	if err != nil {
+		logging.Warn(logging.WithComponent(ctx, "checkpoint"), "failed to create point ref",
+			slog.String("commit", commit.String()),
+			slog.String("error", err.Error()))
+		return
+	}
+	if refName != "" {
+		logging.Debug(logging.WithComponent(ctx, "checkpoint"), "created point ref",
+			slog.String("ref", refName.String()),
+			slog.String("commit", commit.String()))
+	}
+}
+
+// nextPointRefName returns the point ref to create for commit, numbered one
+// past the highest existing point, or "" if a point ref already names commit.
+func nextPointRefName(repo *git.Repository, commit plumbing.Hash, message string) (plumbing.ReferenceName, error) {
+	refs, err := repo.References()
+	if err != nil {
+		return "", fmt.Errorf("failed to get references: %w", err)
+	}
+	highest := 0
+	named := false
+	err = refs.ForEach(func(ref *plumbing.Reference) error {
+		name, ok := strings.CutPrefix(ref.Name().String(), PointRefPrefix)
+		if !ok {
+			return nil
+		}
+		if ref.Hash() == commit {
+			named = true
+			return storer.ErrStop
+		}
+		number, _, _ := strings.Cut(name, "-")
+		if n, err := strconv.Atoi(number); err == nil && n > highest {
+			highest = n
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, storer.ErrStop) {
+		return "", fmt.Errorf("failed to iterate references: %w", err)
+	}
+	if named {
+		return "", nil
+	}
+	return plumbing.ReferenceName(fmt.Sprintf("%s%d-%s", PointRefPrefix, highest+1, pointSlug(message))), nil
+}
+
+// pointSlug turns the first line of message into lowercase ASCII words
+// joined by dashes, cut at a word boundary, for use in a ref name. Returns
+// "checkpoint" if nothing usable is left.
+func pointSlug(message string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(message), "\n")
+	var words []string
+	var word strings.Builder
+	for _, r := range strings.ToLower(line) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			word.WriteRune(r)
+			continue
+		}
+		if word.Len() > 0 {
+			words = append(words, word.String())
+			word.Reset()
+		}
+	}
+	if word.Len() > 0 {
+		words = append(words, word.String())
+	}
+
+	slug := ""
+	for _, w := range words {
+		next := w
+		if slug != "" {
+			next = slug + "-" + w
+		}
+		if len(next) > maxPointSlugLength {
+			if slug == "" {
+				slug = w[:maxPointSlugLength]
+			}
+			break
+		}
+		slug = next
+	}
+	if slug == "" {
+		return "checkpoint"
+	}
+	return slug
+}
@@ -0,0 +1,115 @@
+package strategy
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPointSlug(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		message string
+		want    string
+	}{
+		{"Add a login form", "add-a-login-form"},
+		{"Fix bug #42: crash on save()\n\nEntire-Checkpoint: a1b2c3d4e5f6", "fix-bug-42-crash-on-save"},
+		{"  Überprüfe die API  ", "berpr-fe-die-api"},
+		{"Refactor the authentication middleware to use the new session store", "refactor-the-authentication-middleware"},
+		{"Supercalifragilisticexpialidociousandthensome-more", "supercalifragilisticexpialidociousandthe"},
+		{"", "checkpoint"},
+		{"???", "checkpoint"},
+	}
+	for _, tt := range tests {
+		if got := pointSlug(tt.message); got != tt.want {
+			t.Errorf("pointSlug(%q) = %q, want %q", tt.message, got, tt.want)
+		}
+	}
+}
+
+func TestNextPointRefName(t *testing.T) {
+	t.Parallel()
+	dir := setupGitRepo(t)
+	repo, err := git.PlainOpen(dir)
+	require.NoError(t, err)
+	head, err := repo.Head()
+	require.NoError(t, err)
+
+	name, err := nextPointRefName(repo, head.Hash(), "First point")
+	require.NoError(t, err)
+	assert.Equal(t, plumbing.ReferenceName(PointRefPrefix+"1-first-point"), name)
+
+	other := plumbing.NewHash("1234567890abcdef1234567890abcdef12345678")
+	for _, ref := range []string{"3-older", "12-newest", "not-numbered"} {
+		require.NoError(t, repo.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(PointRefPrefix+ref), other)))
+	}
+	name, err = nextPointRefName(repo, head.Hash(), "Next one")
+	require.NoError(t, err)
+	assert.Equal(t, plumbing.ReferenceName(PointRefPrefix+"13-next-one"), name)
+
+	require.NoError(t, repo.Storer.SetReference(plumbing.NewHashReference(name, head.Hash())))
+	name, err = nextPointRefName(repo, head.Hash(), "Again")
+	require.NoError(t, err)
+	assert.Empty(t, name, "a commit that has a point ref keeps it")
+}
+
+func TestSaveStep_CreatesPointRefs(t *testing.T) {
+	dir := setupGitRepo(t)
+	t.Chdir(dir)
+	ctx := context.Background()
+
+	s := &ManualCommitStrategy{}
+	sessionID := "2026-10-16-point-refs"
+	metadataDir := ".entire/metadata/" + sessionID
+	metadataDirAbs := filepath.Join(dir, metadataDir)
+	require.NoError(t, os.MkdirAll(metadataDirAbs, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(metadataDirAbs, paths.TranscriptFileName), []byte(testTranscript), 0o644))
+	require.NoError(t, s.InitializeSession(ctx, sessionID, "Claude Code", "", ""))
+
+	saveStep := func(content, message string) {
+		t.Helper()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "test.txt"), []byte(content), 0o644))
+		require.NoError(t, s.SaveStep(ctx, StepContext{
+			SessionID:      sessionID,
+			ModifiedFiles:  []string{"test.txt"},
+			MetadataDir:    metadataDir,
+			MetadataDirAbs: metadataDirAbs,
+			CommitMessage:  message,
+			AuthorName:     "Test",
+			AuthorEmail:    "test@test.com",
+		}))
+	}
+
+	// Off by default
+	saveStep("one", "Without point refs")
+	repo, err := git.PlainOpen(dir)
+	require.NoError(t, err)
+	name, err := nextPointRefName(repo, plumbing.ZeroHash, "Probe")
+	require.NoError(t, err)
+	assert.Equal(t, plumbing.ReferenceName(PointRefPrefix+"1-probe"), name, "no point refs without point_refs")
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".entire", "settings.json"), []byte(`{"enabled": true, "point_refs": true}`), 0o644))
+	saveStep("two", "Add a login form")
+	saveStep("three", "Validate the email field")
+
+	points, err := s.GetRewindPoints(ctx, 10)
+	require.NoError(t, err)
+	require.NotEmpty(t, points)
+
+	repo, err = git.PlainOpen(dir)
+	require.NoError(t, err)
+	second, err := repo.Reference(PointRefPrefix+"1-add-a-login-form", false)
+	require.NoError(t, err)
+	third, err := repo.Reference(PointRefPrefix+"2-validate-the-email-field", false)
+	require.NoError(t, err)
+	assert.Equal(t, points[0].ID, third.Hash().String(), "the latest point ref names the latest rewind point")
+	assert.Equal(t, points[1].ID, second.Hash().String())
+}
@@ -22,6 +22,9 @@ import (
 // Configuration (stored in .entire/settings.json under strategy_options.push_sessions):
 //   - false: disable automatic pushing
 //   - true or not set: push automatically (default)
+//
+// If sync.remote is set, the branch goes to that remote instead of the one
+// being pushed to, so session data can live apart from the code.
 func pushSessionsBranchCommon(ctx context.Context, remote, branchName string) error {
 	// Check if pushing is disabled
 	if isPushSessionsDisabled(ctx) {
@@ -33,6 +36,14 @@ func pushSessionsBranchCommon(ctx context.Context, remote, branchName string) er
 		return nil //nolint:nilerr // Hook must be silent on failure
 	}
 
+	if configured := configuredMetadataRemote(ctx); configured != "" {
+		if _, err := repo.Remote(configured); err != nil {
+			fmt.Fprintf(os.Stderr, "[entire] Warning: sync remote %q doesn't exist, session logs not pushed. Add it with: git remote add %s <url>\n", configured, configured)
+			return nil
+		}
+		remote = configured
+	}
+
 	// Check if branch exists locally
 	branchRef := plumbing.NewBranchReferenceName(branchName)
 	localRef, err := repo.Reference(branchRef, true)
@@ -66,6 +77,25 @@ func hasUnpushedSessionsCommon(repo *git.Repository, remote string, localHash pl
 	return localHash != remoteRef.Hash()
 }
 
+// MetadataRemote returns the remote the metadata branch is fetched from:
+// sync.remote from settings, or origin.
+func MetadataRemote(ctx context.Context) string {
+	s, err := settings.Load(ctx)
+	if err != nil {
+		return settings.DefaultMetadataRemote
+	}
+	return s.GetMetadataRemote()
+}
+
+// configuredMetadataRemote returns sync.remote from settings, or "" if unset.
+func configuredMetadataRemote(ctx context.Context) string {
+	s, err := settings.Load(ctx)
+	if err != nil || s.Sync == nil {
+		return ""
+	}
+	return s.Sync.Remote
+}
+
 // isPushSessionsDisabled checks if push_sessions is disabled in settings.
 // Returns true if push_sessions is explicitly set to false.
 func isPushSessionsDisabled(ctx context.Context) bool {
@@ -145,7 +175,21 @@ func fetchAndMergeSessionsCommon(ctx context.Context, remote, branchName string)
 	if err != nil {
 		return fmt.Errorf("failed to get local ref: %w", err)
 	}
-	localCommit, err := repo.CommitObject(localRef.Hash())
+
+	// Get remote (FETCH_HEAD)
+	fetchHeadRef, err := repo.Reference(plumbing.ReferenceName("FETCH_HEAD"), true)
+	if err != nil {
+		return fmt.Errorf("failed to get FETCH_HEAD: %w", err)
+	}
+
+	return mergeSessionsBranch(repo, branchName, localRef.Hash(), fetchHeadRef.Hash())
+}
+
+// mergeSessionsBranch merges the remote commit into the local sessions branch.
+// Since session logs are append-only (unique checkpoint directories), the
+// trees are combined and recorded in a merge commit with both parents.
+func mergeSessionsBranch(repo *git.Repository, branchName string, localHash, remoteHash plumbing.Hash) error {
+	localCommit, err := repo.CommitObject(localHash)
 	if err != nil {
 		return fmt.Errorf("failed to get local commit: %w", err)
 	}
@@ -154,12 +198,7 @@ func fetchAndMergeSessionsCommon(ctx context.Context, remote, branchName string)
 		return fmt.Errorf("failed to get local tree: %w", err)
 	}
 
-	// Get remote (FETCH_HEAD)
-	fetchHeadRef, err := repo.Reference(plumbing.ReferenceName("FETCH_HEAD"), true)
-	if err != nil {
-		return fmt.Errorf("failed to get FETCH_HEAD: %w", err)
-	}
-	remoteCommit, err := repo.CommitObject(fetchHeadRef.Hash())
+	remoteCommit, err := repo.CommitObject(remoteHash)
 	if err != nil {
 		return fmt.Errorf("failed to get remote commit: %w", err)
 	}
@@ -186,7 +225,7 @@ func fetchAndMergeSessionsCommon(ctx context.Context, remote, branchName string)
 
 	// Create merge commit with both parents
 	mergeCommitHash, err := createMergeCommitCommon(repo, mergedTreeHash,
-		[]plumbing.Hash{localRef.Hash(), fetchHeadRef.Hash()},
+		[]plumbing.Hash{localHash, remoteHash},
 		"Merge remote session logs")
 	if err != nil {
 		return fmt.Errorf("failed to create merge commit: %w", err)
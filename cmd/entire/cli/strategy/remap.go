@@ -0,0 +1,152 @@
+package strategy
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/session"
+
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// BranchRename is a shadow branch moved to the name of its rewritten base commit.
+type BranchRename struct {
+	From string
+	To   string
+}
+
+// RemapResult reports what RemapCommits changed, or would change on a dry run.
+type RemapResult struct {
+	// Sessions are the IDs of session states whose commits were remapped.
+	Sessions []string
+
+	// Orphaned are the IDs of sessions whose base commit the rewrite removed.
+	// Their state is left unchanged.
+	Orphaned []string
+
+	// Branches are the shadow branches renamed after their base commit.
+	Branches []BranchRename
+
+	// Conflicts are shadow branches left in place because their new name is
+	// taken or their commit prefix maps to several rewritten commits.
+	Conflicts []string
+}
+
+// RemapCommits updates Entire's references to commits after the history was
+// rewritten (e.g. by git filter-repo). mapping takes full old commit hashes to
+// their rewritten ones; a zero hash marks a commit the rewrite removed.
+//
+// Session states get their base commits remapped, and shadow branches, which
+// are named after the first characters of their base commit, are renamed to
+// match. The metadata branch needs no changes: code commits link to their
+// checkpoints through the Entire-Checkpoint trailer, which rewrites keep.
+func RemapCommits(ctx context.Context, mapping map[string]string, dryRun bool) (*RemapResult, error) {
+	repo, err := OpenRepository(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open git repository: %w", err)
+	}
+	stateStore, err := session.NewStateStore(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create state store: %w", err)
+	}
+	states, err := stateStore.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list session states: %w", err)
+	}
+
+	result := &RemapResult{}
+	for _, state := range states {
+		changed := false
+		if newHash, ok := mapping[state.BaseCommit]; ok {
+			if isRemovedCommit(newHash) {
+				result.Orphaned = append(result.Orphaned, state.SessionID)
+				continue
+			}
+			state.BaseCommit = newHash
+			changed = true
+		}
+		if newHash, ok := mapping[state.AttributionBaseCommit]; ok && !isRemovedCommit(newHash) {
+			state.AttributionBaseCommit = newHash
+			changed = true
+		}
+		if !changed {
+			continue
+		}
+		result.Sessions = append(result.Sessions, state.SessionID)
+		if dryRun {
+			continue
+		}
+		if err := stateStore.Save(ctx, state); err != nil {
+			return nil, fmt.Errorf("failed to save session %s: %w", state.SessionID, err)
+		}
+	}
+
+	branches, err := ListShadowBranches(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, branch := range branches {
+		commitPrefix, worktreeHash, ok := checkpoint.ParseShadowBranchName(branch)
+		if !ok || len(commitPrefix) < checkpoint.ShadowBranchHashLength {
+			continue
+		}
+		newPrefix, ambiguous := remappedPrefix(mapping, commitPrefix)
+		if ambiguous {
+			result.Conflicts = append(result.Conflicts, branch)
+			continue
+		}
+		if newPrefix == "" || newPrefix == commitPrefix {
+			continue
+		}
+		newBranch := checkpoint.ShadowBranchPrefix + newPrefix
+		if worktreeHash != "" {
+			newBranch += "-" + worktreeHash
+		}
+
+		newRefName := plumbing.NewBranchReferenceName(newBranch)
+		if _, err := repo.Reference(newRefName, true); err == nil {
+			result.Conflicts = append(result.Conflicts, branch)
+			continue
+		}
+		result.Branches = append(result.Branches, BranchRename{From: branch, To: newBranch})
+		if dryRun {
+			continue
+		}
+		oldRef, err := repo.Reference(plumbing.NewBranchReferenceName(branch), true)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", branch, err)
+		}
+		if err := repo.Storer.SetReference(plumbing.NewHashReference(newRefName, oldRef.Hash())); err != nil {
+			return nil, fmt.Errorf("failed to create %s: %w", newBranch, err)
+		}
+		// Delete via CLI (go-git v5's RemoveReference doesn't persist with packed refs/worktrees)
+		if err := DeleteBranchCLI(ctx, branch); err != nil {
+			return nil, fmt.Errorf("failed to remove %s after renaming it: %w", branch, err)
+		}
+	}
+	return result, nil
+}
+
+// remappedPrefix returns the shadow branch prefix of the commit that
+// commitPrefix was rewritten to, or "" if no mapped commit has that prefix
+// or all of them were removed. ambiguous is true if the mapped commits with
+// that prefix were rewritten to commits with different prefixes.
+func remappedPrefix(mapping map[string]string, commitPrefix string) (newPrefix string, ambiguous bool) {
+	for oldHash, newHash := range mapping {
+		if !strings.HasPrefix(oldHash, commitPrefix) || isRemovedCommit(newHash) {
+			continue
+		}
+		prefix := newHash[:min(len(newHash), len(commitPrefix))]
+		if newPrefix != "" && prefix != newPrefix {
+			return "", true
+		}
+		newPrefix = prefix
+	}
+	return newPrefix, false
+}
+
+func isRemovedCommit(hash string) bool {
+	return strings.Trim(hash, "0") == ""
+}
@@ -0,0 +1,100 @@
+package strategy
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/session"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemapCommits(t *testing.T) {
+	dir := setupGitRepo(t)
+	t.Chdir(dir)
+	ctx := context.Background()
+	repo, err := git.PlainOpen(dir)
+	require.NoError(t, err)
+	head, err := repo.Head()
+	require.NoError(t, err)
+
+	// Hashes from before the rewrite no longer exist in the repository
+	oldBase := strings.Repeat("1", 40)
+	oldDropped := strings.Repeat("2", 40)
+	newBase := head.Hash().String()
+	mapping := map[string]string{
+		oldBase:    newBase,
+		oldDropped: plumbing.ZeroHash.String(),
+	}
+
+	store, err := session.NewStateStore(ctx)
+	require.NoError(t, err)
+	require.NoError(t, store.Save(ctx, &session.State{SessionID: "remapped", BaseCommit: oldBase, AttributionBaseCommit: oldBase}))
+	require.NoError(t, store.Save(ctx, &session.State{SessionID: "dropped", BaseCommit: oldDropped}))
+	require.NoError(t, store.Save(ctx, &session.State{SessionID: "untouched", BaseCommit: newBase}))
+
+	oldBranch := checkpoint.ShadowBranchNameForCommit(oldBase, "")
+	require.NoError(t, repo.Storer.SetReference(
+		plumbing.NewHashReference(plumbing.NewBranchReferenceName(oldBranch), head.Hash())))
+	newBranch := checkpoint.ShadowBranchNameForCommit(newBase, "")
+
+	result, err := RemapCommits(ctx, mapping, true)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"remapped"}, result.Sessions)
+	assert.Equal(t, []string{"dropped"}, result.Orphaned)
+	assert.Equal(t, []BranchRename{{From: oldBranch, To: newBranch}}, result.Branches)
+	state, err := store.Load(ctx, "remapped")
+	require.NoError(t, err)
+	assert.Equal(t, oldBase, state.BaseCommit, "dry run must not change state")
+
+	_, err = RemapCommits(ctx, mapping, false)
+	require.NoError(t, err)
+	state, err = store.Load(ctx, "remapped")
+	require.NoError(t, err)
+	assert.Equal(t, newBase, state.BaseCommit)
+	assert.Equal(t, newBase, state.AttributionBaseCommit)
+	state, err = store.Load(ctx, "dropped")
+	require.NoError(t, err)
+	assert.Equal(t, oldDropped, state.BaseCommit)
+
+	_, err = repo.Reference(plumbing.NewBranchReferenceName(newBranch), true)
+	require.NoError(t, err, "shadow branch should be renamed")
+	_, err = repo.Reference(plumbing.NewBranchReferenceName(oldBranch), true)
+	require.Error(t, err, "old shadow branch should be removed")
+
+	// Remapping again finds nothing to do
+	result, err = RemapCommits(ctx, mapping, false)
+	require.NoError(t, err)
+	assert.Empty(t, result.Sessions)
+	assert.Empty(t, result.Branches)
+}
+
+func TestRemapCommits_KeepsBranchWhenNewNameTaken(t *testing.T) {
+	dir := setupGitRepo(t)
+	t.Chdir(dir)
+	ctx := context.Background()
+	repo, err := git.PlainOpen(dir)
+	require.NoError(t, err)
+	head, err := repo.Head()
+	require.NoError(t, err)
+
+	oldBase := strings.Repeat("3", 40)
+	oldBranch := checkpoint.ShadowBranchNameForCommit(oldBase, "")
+	newBranch := checkpoint.ShadowBranchNameForCommit(head.Hash().String(), "")
+	for _, branch := range []string{oldBranch, newBranch} {
+		require.NoError(t, repo.Storer.SetReference(
+			plumbing.NewHashReference(plumbing.NewBranchReferenceName(branch), head.Hash())))
+	}
+
+	result, err := RemapCommits(ctx, map[string]string{oldBase: head.Hash().String()}, false)
+	require.NoError(t, err)
+	assert.Empty(t, result.Branches)
+	assert.Equal(t, []string{oldBranch}, result.Conflicts)
+	_, err = repo.Reference(plumbing.NewBranchReferenceName(oldBranch), true)
+	require.NoError(t, err)
+}
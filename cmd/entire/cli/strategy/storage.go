@@ -0,0 +1,32 @@
+package strategy
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+)
+
+// EntireStorageBytes returns the disk space taken by objects that only
+// Entire's refs reach: the metadata branch, shadow branches, and refs under
+// refs/entire/ such as snapshots. Objects the user's branches and tags also
+// reach don't count, nor do the user commits anchor refs keep alive. Needs
+// git 2.31 or later.
+func EntireStorageBytes(ctx context.Context) (int64, error) {
+	cmd := exec.CommandContext(ctx, "git", "rev-list", "--objects", "--disk-usage",
+		"--glob=refs/heads/"+checkpoint.ShadowBranchPrefix+"*",
+		"--exclude="+AnchorRefPrefix+"*", "--glob=refs/entire/*",
+		"--not", "--exclude="+checkpoint.ShadowBranchPrefix+"*", "--branches", "--tags")
+	output, err := cmd.Output()
+	if err != nil {
+		return 0, fmt.Errorf("failed to measure Entire's storage: %w", err)
+	}
+	size, err := strconv.ParseInt(strings.TrimSpace(string(output)), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse git rev-list --disk-usage output %q: %w", strings.TrimSpace(string(output)), err)
+	}
+	return size, nil
+}
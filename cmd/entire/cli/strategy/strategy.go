@@ -68,6 +68,11 @@ type RewindPoint struct {
 	// The logs can be restored from entire/checkpoints/v1, but file state requires git checkout.
 	IsLogsOnly bool
 
+	// IsSnapshot indicates this is a snapshot of the user's uncommitted changes,
+	// taken before a turn (see auto_stash) or with `entire snapshot`. It has no
+	// transcript; rewinding only restores files.
+	IsSnapshot bool
+
 	// CheckpointID is the stable 12-hex-char identifier for logs-only points.
 	// Used to retrieve logs from entire/checkpoints/v1/<id[:2]>/<id[2:]>/full.jsonl
 	// Empty for shadow branch checkpoints (uncommitted).
@@ -0,0 +1,422 @@
+package strategy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os/exec"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+	"github.com/entireio/cli/cmd/entire/cli/logging"
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// SyncRefs are the branches `entire sync` exchanges with remotes.
+var SyncRefs = []string{paths.MetadataBranchName}
+
+// syncPushBatchSize is how many commits each incremental push sends, so an
+// interrupted sync keeps the batches that already reached the remote.
+var syncPushBatchSize = 50
+
+// syncRetryDelays are the waits between attempts of a network operation that
+// failed transiently. Its length is the number of retries.
+var syncRetryDelays = []time.Duration{2 * time.Second, 8 * time.Second, 30 * time.Second}
+
+// syncOpTimeout bounds a single fetch or push attempt.
+const syncOpTimeout = 5 * time.Minute
+
+// errRemoteRefMissing is returned when the remote doesn't have the ref yet.
+var errRemoteRefMissing = errors.New("remote ref does not exist")
+
+// errPushRejected is returned when the remote refuses a non-fast-forward push.
+var errPushRejected = errors.New("push rejected by remote")
+
+// SyncOptions tune how SyncRef transfers data.
+type SyncOptions struct {
+	// Pacer, if set, spaces out network operations to cap the average
+	// transfer rate. Share one pacer across refs and remotes.
+	Pacer *BandwidthPacer
+
+	// AdoptPrunes drops checkpoints a remote removed by rewriting its history,
+	// instead of merging them back in. See adoptUpstreamPrunes.
+	AdoptPrunes bool
+}
+
+// SyncResult reports the outcome of syncing one ref with one remote.
+type SyncResult struct {
+	Remote string
+	Ref    string
+
+	// Pulled is true if remote commits were merged into the local ref.
+	Pulled bool
+
+	// Pushed is the number of local commits the remote received.
+	Pushed int
+
+	// Pruned lists the checkpoints removed locally because the remote pruned
+	// them, and ArchiveRef where the branch was kept before removing them.
+	Pruned     []id.CheckpointID
+	ArchiveRef plumbing.ReferenceName
+
+	// Err is the failure that stopped this ref, if any. Commits pushed before
+	// the failure stay on the remote, so syncing again resumes from there.
+	Err error
+}
+
+// SyncRef pulls remote changes to ref from remote into the local branch and
+// pushes local commits back, retrying transient network failures. Pushes go
+// out in batches along the branch's history when the remote is strictly
+// behind, so progress survives a dropped connection. Pushes send thin packs
+// and fetches negotiate only from the ref's known history, so neither
+// transfers objects the other side already has.
+func SyncRef(ctx context.Context, remote, ref string, opts SyncOptions) SyncResult {
+	result := SyncResult{Remote: remote, Ref: ref}
+	logCtx := logging.WithComponent(ctx, "sync")
+
+	repo, err := OpenRepository(ctx)
+	if err != nil {
+		result.Err = fmt.Errorf("failed to open repository: %w", err)
+		return result
+	}
+
+	// A rejected push means the remote moved since the fetch; pull once more
+	for round := 0; round < 2; round++ {
+		remoteHash, err := pullSyncRef(ctx, repo, remote, ref, opts, &result)
+		if err != nil {
+			result.Err = err
+			return result
+		}
+		pushed, err := pushSyncRef(ctx, repo, remote, ref, remoteHash, opts)
+		result.Pushed += pushed
+		if errors.Is(err, errPushRejected) && round == 0 {
+			logging.Info(logCtx, "push rejected, pulling again",
+				slog.String("remote", remote), slog.String("ref", ref))
+			continue
+		}
+		result.Err = err
+		return result
+	}
+	return result
+}
+
+// pullSyncRef fetches ref from remote and integrates it into the local branch:
+// creating it, fast-forwarding it, or merging diverged histories. Returns the
+// remote's commit, or the zero hash if the remote doesn't have the ref.
+func pullSyncRef(ctx context.Context, repo *git.Repository, remote, ref string, opts SyncOptions, result *SyncResult) (plumbing.Hash, error) {
+	trackingRef := plumbing.ReferenceName(syncTrackingRef(remote, ref))
+	branchRef := plumbing.NewBranchReferenceName(ref)
+
+	// Only advertise this ref's history, not every branch and tag
+	args := []string{"fetch", "--no-write-fetch-head"}
+	var previous plumbing.Hash
+	for _, name := range []plumbing.ReferenceName{trackingRef, branchRef} {
+		if r, err := repo.Reference(name, true); err == nil {
+			args = append(args, "--negotiation-tip="+r.Hash().String())
+			if name == trackingRef {
+				previous = r.Hash()
+			}
+		}
+	}
+	args = append(args, remote, "+refs/heads/"+ref+":"+trackingRef.String())
+
+	start := time.Now()
+	err := retryTransient(ctx, func() error {
+		_, err := runSyncGit(ctx, args...)
+		return err
+	})
+	if errors.Is(err, errRemoteRefMissing) {
+		return plumbing.ZeroHash, nil
+	}
+	if err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("fetch failed: %w", err)
+	}
+
+	tracking, err := repo.Reference(trackingRef, true)
+	if err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("failed to read fetched ref: %w", err)
+	}
+	remoteHash := tracking.Hash()
+	if opts.Pacer != nil && remoteHash != previous {
+		// Git can't cap its own rate; wait out what the fetch used
+		if size, err := PackSize(ctx, previous, remoteHash); err == nil {
+			if err := opts.Pacer.Wait(ctx, size, time.Since(start)); err != nil {
+				return plumbing.ZeroHash, err
+			}
+		}
+	}
+
+	local, err := repo.Reference(branchRef, true)
+	if err != nil {
+		// Nothing local yet: take the remote's branch as is
+		if err := repo.Storer.SetReference(plumbing.NewHashReference(branchRef, remoteHash)); err != nil {
+			return plumbing.ZeroHash, fmt.Errorf("failed to create %s: %w", ref, err)
+		}
+		result.Pulled = true
+		return remoteHash, nil
+	}
+
+	rewritten := previous != plumbing.ZeroHash && remoteHash != previous && !isAncestorCommit(repo, previous, remoteHash)
+	if rewritten && local.Hash() != remoteHash {
+		if opts.AdoptPrunes {
+			pruned, archive, err := adoptUpstreamPrunes(repo, remote, ref, local.Hash(), previous, remoteHash, time.Now())
+			if err != nil {
+				return plumbing.ZeroHash, fmt.Errorf("failed to adopt remote prunes: %w", err)
+			}
+			result.Pruned, result.ArchiveRef, result.Pulled = pruned, archive, true
+			removed := make([]string, len(pruned))
+			for i, cpID := range pruned {
+				removed[i] = cpID.String()
+			}
+			RecordAudit(ctx, checkpoint.AuditEntry{
+				Operation: checkpoint.AuditOpAdoptPrunes,
+				Removed:   removed,
+				Refs:      []string{archive.String()},
+				Details:   fmt.Sprintf("adopted prunes of %s from %s", ref, remote),
+			})
+			return remoteHash, nil
+		}
+		logging.Warn(logging.WithComponent(ctx, "sync"), "remote history was rewritten; merging keeps checkpoints it pruned",
+			slog.String("remote", remote), slog.String("ref", ref))
+	}
+
+	switch {
+	case local.Hash() == remoteHash:
+	case isAncestorCommit(repo, local.Hash(), remoteHash):
+		if err := repo.Storer.SetReference(plumbing.NewHashReference(branchRef, remoteHash)); err != nil {
+			return plumbing.ZeroHash, fmt.Errorf("failed to fast-forward %s: %w", ref, err)
+		}
+		result.Pulled = true
+	case isAncestorCommit(repo, remoteHash, local.Hash()):
+		// Remote is behind; the push sends what it lacks
+	default:
+		if err := mergeSessionsBranch(repo, ref, local.Hash(), remoteHash); err != nil {
+			return plumbing.ZeroHash, err
+		}
+		result.Pulled = true
+	}
+	return remoteHash, nil
+}
+
+// pushSyncRef pushes the local branch to remote, whose copy is at remoteHash,
+// and returns the number of commits the remote received.
+func pushSyncRef(ctx context.Context, repo *git.Repository, remote, ref string, remoteHash plumbing.Hash, opts SyncOptions) (int, error) {
+	local, err := repo.Reference(plumbing.NewBranchReferenceName(ref), true)
+	if err != nil {
+		return 0, nil //nolint:nilerr // No local branch, nothing to push
+	}
+	if local.Hash() == remoteHash {
+		return 0, nil
+	}
+
+	pending, linear, err := pendingPushCommits(repo, local.Hash(), remoteHash)
+	if err != nil {
+		return 0, err
+	}
+	steps := []plumbing.Hash{local.Hash()}
+	if linear {
+		steps = steps[:0]
+		for end := syncPushBatchSize; end < len(pending); end += syncPushBatchSize {
+			steps = append(steps, pending[end-1])
+		}
+		steps = append(steps, local.Hash())
+	}
+
+	pushed := 0
+	base := remoteHash
+	for _, step := range steps {
+		var size int64
+		if opts.Pacer != nil {
+			if size, err = PackSize(ctx, base, step); err != nil {
+				return pushed, err
+			}
+		}
+		start := time.Now()
+		err := retryTransient(ctx, func() error {
+			_, err := runSyncGit(ctx, "push", "--no-verify", "--thin", remote, step.String()+":refs/heads/"+ref)
+			return err
+		})
+		if err != nil {
+			if errors.Is(err, errPushRejected) {
+				return pushed, err
+			}
+			return pushed, fmt.Errorf("push failed after %d of %d commits: %w", pushed, len(pending), err)
+		}
+		pushed = countUntil(pending, step)
+		base = step
+		// Remember the remote's progress for the next run
+		_ = repo.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(syncTrackingRef(remote, ref)), step)) //nolint:errcheck // refreshed by the next fetch
+		if opts.Pacer != nil {
+			if err := opts.Pacer.Wait(ctx, size, time.Since(start)); err != nil {
+				return pushed, err
+			}
+		}
+	}
+	return len(pending), nil
+}
+
+// pendingPushCommits returns the commits on the first-parent history of local
+// that come after remote, oldest first. linear is false if that history never
+// reaches remote (the branches were merged), in which case pending ends at the
+// first commit the remote already has and the push must go in one step.
+func pendingPushCommits(repo *git.Repository, local, remote plumbing.Hash) ([]plumbing.Hash, bool, error) {
+	chain, linear, err := firstParentChain(repo, local, func(h plumbing.Hash) bool { return h == remote })
+	if err != nil {
+		return nil, false, err
+	}
+	if !linear && remote != plumbing.ZeroHash {
+		chain, _, err = firstParentChain(repo, local, func(h plumbing.Hash) bool {
+			return isAncestorCommit(repo, h, remote)
+		})
+		if err != nil {
+			return nil, false, err
+		}
+	}
+	return chain, linear || remote == plumbing.ZeroHash, nil
+}
+
+// firstParentChain walks the first parents of tip until stop matches, and
+// returns the commits before that point, oldest first. found reports whether
+// stop matched before the root commit.
+func firstParentChain(repo *git.Repository, tip plumbing.Hash, stop func(plumbing.Hash) bool) ([]plumbing.Hash, bool, error) {
+	var chain []plumbing.Hash
+	found := false
+	for hash := tip; ; {
+		if stop(hash) {
+			found = true
+			break
+		}
+		chain = append(chain, hash)
+		commit, err := repo.CommitObject(hash)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to read commit %s: %w", hash, err)
+		}
+		if len(commit.ParentHashes) == 0 {
+			break
+		}
+		hash = commit.ParentHashes[0]
+	}
+	slices.Reverse(chain)
+	return chain, found, nil
+}
+
+// countUntil returns the number of commits up to and including hash.
+func countUntil(commits []plumbing.Hash, hash plumbing.Hash) int {
+	for i, c := range commits {
+		if c == hash {
+			return i + 1
+		}
+	}
+	return len(commits)
+}
+
+// isAncestorCommit reports whether ancestor is reachable from descendant.
+func isAncestorCommit(repo *git.Repository, ancestor, descendant plumbing.Hash) bool {
+	a, err := repo.CommitObject(ancestor)
+	if err != nil {
+		return false
+	}
+	d, err := repo.CommitObject(descendant)
+	if err != nil {
+		return false
+	}
+	ok, err := a.IsAncestor(d)
+	return err == nil && ok
+}
+
+// syncTrackingRef is where sync keeps the last known remote position of ref.
+func syncTrackingRef(remote, ref string) string {
+	return plumbing.NewRemoteReferenceName(remote, ref).String()
+}
+
+// retryTransient runs op, retrying with backoff while it fails transiently.
+func retryTransient(ctx context.Context, op func() error) error {
+	err := op()
+	for _, delay := range syncRetryDelays {
+		if err == nil || !isTransientGitError(err) {
+			return err
+		}
+		logging.Debug(logging.WithComponent(ctx, "sync"), "retrying after transient failure",
+			slog.String("error", err.Error()), slog.Duration("delay", delay))
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+		err = op()
+	}
+	return err
+}
+
+// transientGitErrors are output fragments of git network failures that are
+// worth retrying. Authentication and rejection errors are not.
+var transientGitErrors = []string{
+	"could not resolve host",
+	"connection timed out",
+	"connection reset",
+	"connection refused",
+	"operation timed out",
+	"early eof",
+	"the remote end hung up",
+	"rpc failed",
+	"unexpected disconnect",
+	"tls connection",
+	"ssl_error",
+	"gnutls",
+	"http 502",
+	"http 503",
+	"http 504",
+	"signal: killed",
+}
+
+// isTransientGitError reports whether err looks like a network hiccup.
+func isTransientGitError(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	msg := strings.ToLower(err.Error())
+	for _, fragment := range transientGitErrors {
+		if strings.Contains(msg, fragment) {
+			return true
+		}
+	}
+	return false
+}
+
+// runSyncGit runs a git network command and returns its output, mapping a
+// missing remote ref and a rejected push to errRemoteRefMissing and
+// errPushRejected.
+func runSyncGit(ctx context.Context, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, syncOpTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Stdin = nil
+	output, err := cmd.CombinedOutput()
+	if err == nil {
+		return string(output), nil
+	}
+	if ctx.Err() != nil {
+		return "", fmt.Errorf("git %s: %w", args[0], ctx.Err())
+	}
+	out := strings.TrimSpace(string(output))
+	lower := strings.ToLower(out)
+	switch {
+	case strings.Contains(lower, "couldn't find remote ref"):
+		return "", errRemoteRefMissing
+	case strings.Contains(lower, "non-fast-forward") || strings.Contains(lower, "[rejected]") || strings.Contains(lower, "fetch first"):
+		return "", errPushRejected
+	}
+	// git's first line names the problem; the rest is generic advice
+	if first, _, found := strings.Cut(out, "\n"); found {
+		out = first
+	}
+	return "", fmt.Errorf("git %s: %s", args[0], strings.TrimPrefix(out, "fatal: "))
+}
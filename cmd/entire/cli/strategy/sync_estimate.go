@@ -0,0 +1,179 @@
+package strategy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// BandwidthPacer caps the average transfer rate of a sync. Git can't limit
+// its own rate, so the pacer waits after each transfer until the bytes moved
+// so far fit the limit. Pushes go out in batches, which keeps bursts short.
+type BandwidthPacer struct {
+	bytesPerSecond int64
+	sleep          func(context.Context, time.Duration) error
+}
+
+// NewBandwidthPacer returns a pacer that limits transfers to bytesPerSecond.
+func NewBandwidthPacer(bytesPerSecond int64) *BandwidthPacer {
+	return &BandwidthPacer{bytesPerSecond: bytesPerSecond, sleep: sleepContext}
+}
+
+// Wait blocks until a transfer of size bytes that took elapsed averages no
+// more than the limit.
+func (p *BandwidthPacer) Wait(ctx context.Context, size int64, elapsed time.Duration) error {
+	delay := p.delay(size, elapsed)
+	if delay <= 0 {
+		return nil
+	}
+	return p.sleep(ctx, delay)
+}
+
+// delay returns how much longer a transfer of size bytes should have taken.
+func (p *BandwidthPacer) delay(size int64, elapsed time.Duration) time.Duration {
+	if p.bytesPerSecond <= 0 || size <= 0 {
+		return 0
+	}
+	budget := time.Duration(float64(size) / float64(p.bytesPerSecond) * float64(time.Second))
+	return budget - elapsed
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err() //nolint:wrapcheck // Propagating context cancellation
+	case <-timer.C:
+		return nil
+	}
+}
+
+// PackSize returns the size of the thin pack git would send to bring a
+// repository at from up to to. A zero from counts everything reachable
+// from to.
+func PackSize(ctx context.Context, from, to plumbing.Hash) (int64, error) {
+	revs := to.String() + "\n"
+	if from != plumbing.ZeroHash {
+		revs += "^" + from.String() + "\n"
+	}
+	cmd := exec.CommandContext(ctx, "git", "pack-objects", "--thin", "--stdout", "--revs", "-q")
+	cmd.Stdin = strings.NewReader(revs)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return 0, fmt.Errorf("failed to estimate pack size: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return 0, fmt.Errorf("failed to estimate pack size: %w", err)
+	}
+	size, copyErr := io.Copy(io.Discard, stdout)
+	if err := cmd.Wait(); err != nil {
+		return 0, fmt.Errorf("failed to estimate pack size: %w", err)
+	}
+	if copyErr != nil {
+		return 0, fmt.Errorf("failed to estimate pack size: %w", copyErr)
+	}
+	return size, nil
+}
+
+// SyncEstimate describes what SyncRef would transfer, without transferring it.
+type SyncEstimate struct {
+	Remote string
+	Ref    string
+
+	// PullPending is true if the remote has commits this repository lacks.
+	// Their size isn't known until they are fetched.
+	PullPending bool
+
+	// PushCommits and PushBytes are the commits the remote lacks and the
+	// size of the thin pack that would carry them.
+	PushCommits int
+	PushBytes   int64
+
+	Err error
+}
+
+// EstimateSync compares ref with its copy on remote and estimates the
+// transfer SyncRef would make. It only asks the remote for its ref; if the
+// remote moved to commits this repository doesn't have, the push estimate is
+// against the last position sync saw.
+func EstimateSync(ctx context.Context, remote, ref string) SyncEstimate {
+	estimate := SyncEstimate{Remote: remote, Ref: ref}
+
+	repo, err := OpenRepository(ctx)
+	if err != nil {
+		estimate.Err = fmt.Errorf("failed to open repository: %w", err)
+		return estimate
+	}
+
+	var output string
+	err = retryTransient(ctx, func() error {
+		var err error
+		output, err = runSyncGit(ctx, "ls-remote", "--refs", remote, "refs/heads/"+ref)
+		return err
+	})
+	if err != nil {
+		estimate.Err = fmt.Errorf("failed to query remote: %w", err)
+		return estimate
+	}
+	remoteHash, err := parseLsRemote(output, "refs/heads/"+ref)
+	if err != nil {
+		estimate.Err = err
+		return estimate
+	}
+
+	base := remoteHash
+	if remoteHash != plumbing.ZeroHash {
+		if _, err := repo.CommitObject(remoteHash); err != nil {
+			estimate.PullPending = true
+			base = plumbing.ZeroHash
+			if tracking, err := repo.Reference(plumbing.ReferenceName(syncTrackingRef(remote, ref)), true); err == nil {
+				base = tracking.Hash()
+			}
+		}
+	}
+
+	local, err := repo.Reference(plumbing.NewBranchReferenceName(ref), true)
+	if err != nil {
+		return estimate
+	}
+	if !estimate.PullPending && remoteHash != plumbing.ZeroHash && local.Hash() != remoteHash {
+		estimate.PullPending = !isAncestorCommit(repo, remoteHash, local.Hash())
+	}
+	if local.Hash() == base || (base != plumbing.ZeroHash && isAncestorCommit(repo, local.Hash(), base)) {
+		return estimate
+	}
+
+	pending, _, err := pendingPushCommits(repo, local.Hash(), base)
+	if err != nil {
+		estimate.Err = err
+		return estimate
+	}
+	estimate.PushCommits = len(pending)
+	if estimate.PushBytes, err = PackSize(ctx, base, local.Hash()); err != nil {
+		estimate.Err = err
+	}
+	return estimate
+}
+
+// parseLsRemote returns the hash git ls-remote printed for refName, or the
+// zero hash if it isn't listed.
+func parseLsRemote(output, refName string) (plumbing.Hash, error) {
+	for _, line := range strings.Split(output, "\n") {
+		hash, name, found := strings.Cut(strings.TrimSpace(line), "\t")
+		if !found || name != refName {
+			continue
+		}
+		if !plumbing.IsHash(hash) {
+			return plumbing.ZeroHash, errors.New("unexpected ls-remote output: " + line)
+		}
+		return plumbing.NewHash(hash), nil
+	}
+	return plumbing.ZeroHash, nil
+}
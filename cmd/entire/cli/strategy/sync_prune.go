@@ -0,0 +1,138 @@
+package strategy
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// PruneArchiveRefPrefix is the ref namespace where sync keeps the local
+// metadata branch as it was before adopting a remote's prunes, at
+// refs/entire/prune-archive/<remote>/<timestamp>.
+const PruneArchiveRefPrefix = "refs/entire/prune-archive/"
+
+// adoptUpstreamPrunes rebuilds the local branch on top of a remote whose
+// history was rewritten: checkpoints present at previousRemote but gone from
+// remoteHash are dropped, and checkpoints the remote never had are carried
+// over in a commit on top of remoteHash. The previous local branch is kept
+// under PruneArchiveRefPrefix. The branch is only moved if it is still at
+// localHash. Returns the checkpoints removed locally.
+func adoptUpstreamPrunes(repo *git.Repository, remote, branchName string, localHash, previousRemote, remoteHash plumbing.Hash, now time.Time) ([]id.CheckpointID, plumbing.ReferenceName, error) {
+	previousEntries, err := flattenCommit(repo, previousRemote)
+	if err != nil {
+		return nil, "", err
+	}
+	remoteEntries, err := flattenCommit(repo, remoteHash)
+	if err != nil {
+		return nil, "", err
+	}
+	localEntries, err := flattenCommit(repo, localHash)
+	if err != nil {
+		return nil, "", err
+	}
+
+	remoteCheckpoints := checkpointsInEntries(remoteEntries)
+	pruned := make(map[id.CheckpointID]bool)
+	for cpID := range checkpointsInEntries(previousEntries) {
+		if !remoteCheckpoints[cpID] {
+			pruned[cpID] = true
+		}
+	}
+
+	// Remote entries win, as in mergeSessionsBranch
+	merged := make(map[string]object.TreeEntry, len(remoteEntries))
+	var removed []id.CheckpointID
+	seen := make(map[id.CheckpointID]bool)
+	for path, entry := range localEntries {
+		if cpID, ok := checkpointOfPath(path); ok && pruned[cpID] {
+			if !seen[cpID] {
+				seen[cpID] = true
+				removed = append(removed, cpID)
+			}
+			continue
+		}
+		merged[path] = entry
+	}
+	for path, entry := range remoteEntries {
+		merged[path] = entry
+	}
+	sort.Slice(removed, func(i, j int) bool { return removed[i] < removed[j] })
+
+	archive := plumbing.ReferenceName(PruneArchiveRefPrefix + remote + "/" + now.UTC().Format("20060102T150405Z"))
+	if err := repo.Storer.SetReference(plumbing.NewHashReference(archive, localHash)); err != nil {
+		return nil, "", fmt.Errorf("failed to archive %s: %w", branchName, err)
+	}
+
+	treeHash, err := checkpoint.BuildTreeFromEntries(repo, merged)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to build tree: %w", err)
+	}
+	newHead := remoteHash
+	remoteCommit, err := repo.CommitObject(remoteHash)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read remote commit: %w", err)
+	}
+	if treeHash != remoteCommit.TreeHash {
+		newHead, err = createMergeCommitCommon(repo, treeHash, []plumbing.Hash{remoteHash},
+			"Carry local checkpoints over pruned remote history")
+		if err != nil {
+			return nil, "", err
+		}
+	}
+	// Fail rather than drop checkpoints written to the branch since it was read
+	branchRef := plumbing.NewBranchReferenceName(branchName)
+	if err := repo.Storer.CheckAndSetReference(plumbing.NewHashReference(branchRef, newHead), plumbing.NewHashReference(branchRef, localHash)); err != nil {
+		_ = repo.Storer.RemoveReference(archive) //nolint:errcheck // the branch is unchanged, the archive is redundant
+		return nil, "", fmt.Errorf("failed to update %s: %w", branchName, err)
+	}
+	return removed, archive, nil
+}
+
+// flattenCommit returns the files of a commit's tree keyed by full path.
+func flattenCommit(repo *git.Repository, hash plumbing.Hash) (map[string]object.TreeEntry, error) {
+	commit, err := repo.CommitObject(hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read commit %s: %w", hash, err)
+	}
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tree of %s: %w", hash, err)
+	}
+	entries := make(map[string]object.TreeEntry)
+	if err := checkpoint.FlattenTree(repo, tree, "", entries); err != nil {
+		return nil, fmt.Errorf("failed to flatten tree of %s: %w", hash, err)
+	}
+	return entries, nil
+}
+
+// checkpointsInEntries returns the checkpoints with files among entries.
+func checkpointsInEntries(entries map[string]object.TreeEntry) map[id.CheckpointID]bool {
+	checkpoints := make(map[id.CheckpointID]bool)
+	for path := range entries {
+		if cpID, ok := checkpointOfPath(path); ok {
+			checkpoints[cpID] = true
+		}
+	}
+	return checkpoints
+}
+
+// checkpointOfPath returns the checkpoint whose sharded directory holds path.
+func checkpointOfPath(path string) (id.CheckpointID, bool) {
+	parts := strings.SplitN(path, "/", 3)
+	if len(parts) < 3 {
+		return "", false
+	}
+	cpID, err := id.NewCheckpointID(parts[0] + parts[1])
+	if err != nil {
+		return "", false
+	}
+	return cpID, true
+}
@@ -0,0 +1,146 @@
+package strategy
+
+import (
+	"context"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// commitCheckpoints sets the metadata branch of repo to a commit holding a
+// metadata file for each checkpoint, on top of parent (or a new root).
+func commitCheckpoints(t *testing.T, repo *git.Repository, parent plumbing.Hash, cpIDs ...id.CheckpointID) plumbing.Hash {
+	t.Helper()
+	blobs := make(map[string]plumbing.Hash)
+	for _, cpID := range cpIDs {
+		obj := repo.Storer.NewEncodedObject()
+		obj.SetType(plumbing.BlobObject)
+		w, err := obj.Writer()
+		require.NoError(t, err)
+		_, err = w.Write([]byte(`{"checkpoint_id":"` + cpID.String() + `"}`))
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+		blobs[cpID.Path()+"/metadata.json"], err = repo.Storer.SetEncodedObject(obj)
+		require.NoError(t, err)
+	}
+	treeHash, err := checkpoint.BuildTreeFromEntries(repo, flatEntries(blobs))
+	require.NoError(t, err)
+	var parents []plumbing.Hash
+	if parent != plumbing.ZeroHash {
+		parents = []plumbing.Hash{parent}
+	}
+	hash, err := createMergeCommitCommon(repo, treeHash, parents, "checkpoints")
+	require.NoError(t, err)
+	require.NoError(t, repo.Storer.SetReference(plumbing.NewHashReference(plumbing.NewBranchReferenceName(paths.MetadataBranchName), hash)))
+	return hash
+}
+
+// setupPrunedRemote pushes cp1 and cp2 from a new repo, then has a
+// collaborator rewrite the remote branch to hold only cp2. Returns the repo,
+// which has not synced since.
+func setupPrunedRemote(t *testing.T, cp1, cp2 id.CheckpointID) *git.Repository {
+	t.Helper()
+	ctx := context.Background()
+	dir := setupGitRepo(t)
+	remoteDir := setupSyncRemote(t, dir)
+	repo, err := git.PlainOpen(dir)
+	require.NoError(t, err)
+	t.Chdir(dir)
+	commitCheckpoints(t, repo, plumbing.ZeroHash, cp1, cp2)
+	require.NoError(t, SyncRef(ctx, "origin", paths.MetadataBranchName, SyncOptions{}).Err)
+
+	otherDir := setupGitRepo(t)
+	otherRepo, err := git.PlainOpen(otherDir)
+	require.NoError(t, err)
+	_, err = otherRepo.CreateRemote(&config.RemoteConfig{Name: "origin", URLs: []string{remoteDir}})
+	require.NoError(t, err)
+	commitCheckpoints(t, otherRepo, plumbing.ZeroHash, cp2)
+	out, err := exec.Command("git", "-C", otherDir, "push", "--force", "origin", paths.MetadataBranchName).CombinedOutput()
+	require.NoError(t, err, string(out))
+	return repo
+}
+
+func branchCheckpoints(t *testing.T, repo *git.Repository) map[id.CheckpointID]bool {
+	t.Helper()
+	ref, err := repo.Reference(plumbing.NewBranchReferenceName(paths.MetadataBranchName), true)
+	require.NoError(t, err)
+	entries, err := flattenCommit(repo, ref.Hash())
+	require.NoError(t, err)
+	return checkpointsInEntries(entries)
+}
+
+func TestSyncRef_AdoptsUpstreamPrunes(t *testing.T) {
+	cp1, cp2, cp3 := id.MustCheckpointID("111111111111"), id.MustCheckpointID("222222222222"), id.MustCheckpointID("333333333333")
+	repo := setupPrunedRemote(t, cp1, cp2)
+	ref, err := repo.Reference(plumbing.NewBranchReferenceName(paths.MetadataBranchName), true)
+	require.NoError(t, err)
+	beforeSync := commitCheckpoints(t, repo, ref.Hash(), cp1, cp2, cp3)
+
+	result := SyncRef(context.Background(), "origin", paths.MetadataBranchName, SyncOptions{AdoptPrunes: true})
+	require.NoError(t, result.Err)
+	assert.Equal(t, []id.CheckpointID{cp1}, result.Pruned)
+	assert.Equal(t, map[id.CheckpointID]bool{cp2: true, cp3: true}, branchCheckpoints(t, repo))
+
+	archived, err := repo.Reference(result.ArchiveRef, true)
+	require.NoError(t, err)
+	assert.Equal(t, beforeSync, archived.Hash(), "the previous branch should be archived")
+
+	local, err := repo.Reference(plumbing.NewBranchReferenceName(paths.MetadataBranchName), true)
+	require.NoError(t, err)
+	assert.False(t, isAncestorCommit(repo, beforeSync, local.Hash()), "pruned history should not be pushed back")
+	assert.Equal(t, 2, result.Pushed, "the carried-over checkpoints and the audit entry")
+
+	entries, err := checkpoint.NewGitStore(repo).ReadAuditLog(context.Background())
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, checkpoint.AuditOpAdoptPrunes, entries[0].Operation)
+	assert.Equal(t, []string{cp1.String()}, entries[0].Removed)
+	assert.Equal(t, []string{result.ArchiveRef.String()}, entries[0].Refs)
+}
+
+func TestAdoptUpstreamPrunes_BranchMoved(t *testing.T) {
+	cp1, cp2, cp3 := id.MustCheckpointID("666666666666"), id.MustCheckpointID("777777777777"), id.MustCheckpointID("888888888888")
+	repo := setupPrunedRemote(t, cp1, cp2)
+	trackingRef := plumbing.ReferenceName(syncTrackingRef("origin", paths.MetadataBranchName))
+	previous, err := repo.Reference(trackingRef, true)
+	require.NoError(t, err)
+	out, err := exec.Command("git", "fetch", "origin", "+refs/heads/"+paths.MetadataBranchName+":"+trackingRef.String()).CombinedOutput()
+	require.NoError(t, err, string(out))
+	tracking, err := repo.Reference(trackingRef, true)
+	require.NoError(t, err)
+
+	// A checkpoint written after the branch was read must not be dropped
+	moved := commitCheckpoints(t, repo, previous.Hash(), cp1, cp2, cp3)
+	_, _, err = adoptUpstreamPrunes(repo, "origin", paths.MetadataBranchName, previous.Hash(), previous.Hash(), tracking.Hash(), time.Now())
+	require.Error(t, err)
+
+	ref, err := repo.Reference(plumbing.NewBranchReferenceName(paths.MetadataBranchName), true)
+	require.NoError(t, err)
+	assert.Equal(t, moved, ref.Hash())
+	refs, err := repo.References()
+	require.NoError(t, err)
+	require.NoError(t, refs.ForEach(func(r *plumbing.Reference) error {
+		assert.NotContains(t, r.Name().String(), PruneArchiveRefPrefix, "the archive should be removed")
+		return nil
+	}))
+}
+
+func TestSyncRef_KeepsUpstreamPrunesByDefault(t *testing.T) {
+	cp1, cp2 := id.MustCheckpointID("444444444444"), id.MustCheckpointID("555555555555")
+	repo := setupPrunedRemote(t, cp1, cp2)
+
+	result := SyncRef(context.Background(), "origin", paths.MetadataBranchName, SyncOptions{})
+	require.NoError(t, result.Err)
+	assert.Empty(t, result.Pruned)
+	assert.Equal(t, map[id.CheckpointID]bool{cp1: true, cp2: true}, branchCheckpoints(t, repo))
+}
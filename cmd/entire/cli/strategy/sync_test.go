@@ -0,0 +1,293 @@
+package strategy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// addMetadataCommits appends n commits to the metadata branch of repo, each
+// adding one file named prefix-<i>.
+func addMetadataCommits(t *testing.T, repo *git.Repository, prefix string, n int) plumbing.Hash {
+	t.Helper()
+
+	branchRef := plumbing.NewBranchReferenceName(paths.MetadataBranchName)
+	entries := map[string]plumbing.Hash{}
+	var parent plumbing.Hash
+	if ref, err := repo.Reference(branchRef, true); err == nil {
+		parent = ref.Hash()
+		commit, err := repo.CommitObject(parent)
+		require.NoError(t, err)
+		tree, err := commit.Tree()
+		require.NoError(t, err)
+		for _, e := range tree.Entries {
+			entries[e.Name] = e.Hash
+		}
+	}
+
+	for i := range n {
+		name := fmt.Sprintf("%s-%d", prefix, i)
+		obj := repo.Storer.NewEncodedObject()
+		obj.SetType(plumbing.BlobObject)
+		w, err := obj.Writer()
+		require.NoError(t, err)
+		_, err = w.Write([]byte(name))
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+		blobHash, err := repo.Storer.SetEncodedObject(obj)
+		require.NoError(t, err)
+		entries[name] = blobHash
+
+		treeHash, err := checkpoint.BuildTreeFromEntries(repo, flatEntries(entries))
+		require.NoError(t, err)
+		var parents []plumbing.Hash
+		if parent != plumbing.ZeroHash {
+			parents = []plumbing.Hash{parent}
+		}
+		parent, err = createMergeCommitCommon(repo, treeHash, parents, "add "+name)
+		require.NoError(t, err)
+	}
+	require.NoError(t, repo.Storer.SetReference(plumbing.NewHashReference(branchRef, parent)))
+	return parent
+}
+
+func flatEntries(blobs map[string]plumbing.Hash) map[string]object.TreeEntry {
+	entries := make(map[string]object.TreeEntry, len(blobs))
+	for name, hash := range blobs {
+		entries[name] = object.TreeEntry{Name: name, Mode: filemode.Regular, Hash: hash}
+	}
+	return entries
+}
+
+// setupSyncRemote creates a bare repository and registers it as origin of
+// the repo in dir.
+func setupSyncRemote(t *testing.T, dir string) string {
+	t.Helper()
+	remoteDir := t.TempDir()
+	_, err := git.PlainInit(remoteDir, true)
+	require.NoError(t, err)
+	repo, err := git.PlainOpen(dir)
+	require.NoError(t, err)
+	_, err = repo.CreateRemote(&config.RemoteConfig{Name: "origin", URLs: []string{remoteDir}})
+	require.NoError(t, err)
+	return remoteDir
+}
+
+func remoteBranchHash(t *testing.T, remoteDir string) plumbing.Hash {
+	t.Helper()
+	remote, err := git.PlainOpen(remoteDir)
+	require.NoError(t, err)
+	ref, err := remote.Reference(plumbing.NewBranchReferenceName(paths.MetadataBranchName), true)
+	require.NoError(t, err)
+	return ref.Hash()
+}
+
+func TestSyncRef_PushesInBatchesAndPulls(t *testing.T) {
+	dir := setupGitRepo(t)
+	remoteDir := setupSyncRemote(t, dir)
+	t.Chdir(dir)
+	ctx := context.Background()
+
+	oldBatch := syncPushBatchSize
+	syncPushBatchSize = 2
+	t.Cleanup(func() { syncPushBatchSize = oldBatch })
+
+	repo, err := git.PlainOpen(dir)
+	require.NoError(t, err)
+	tip := addMetadataCommits(t, repo, "local", 5)
+
+	result := SyncRef(ctx, "origin", paths.MetadataBranchName, SyncOptions{})
+	require.NoError(t, result.Err)
+	assert.Equal(t, 5, result.Pushed)
+	assert.False(t, result.Pulled)
+	assert.Equal(t, tip, remoteBranchHash(t, remoteDir))
+
+	// Another clone adds commits; syncing merges them in
+	otherDir := setupGitRepo(t)
+	otherRepo, err := git.PlainOpen(otherDir)
+	require.NoError(t, err)
+	_, err = otherRepo.CreateRemote(&config.RemoteConfig{Name: "origin", URLs: []string{remoteDir}})
+	require.NoError(t, err)
+	t.Chdir(otherDir)
+	result = SyncRef(ctx, "origin", paths.MetadataBranchName, SyncOptions{})
+	require.NoError(t, result.Err)
+	assert.True(t, result.Pulled)
+	otherTip := addMetadataCommits(t, otherRepo, "other", 1)
+	result = SyncRef(ctx, "origin", paths.MetadataBranchName, SyncOptions{})
+	require.NoError(t, result.Err)
+	assert.Equal(t, 1, result.Pushed)
+
+	t.Chdir(dir)
+	addMetadataCommits(t, repo, "diverged", 1)
+	result = SyncRef(ctx, "origin", paths.MetadataBranchName, SyncOptions{})
+	require.NoError(t, result.Err)
+	assert.True(t, result.Pulled)
+	assert.Equal(t, 2, result.Pushed, "the diverged commit and the merge")
+
+	merged, err := repo.Reference(plumbing.NewBranchReferenceName(paths.MetadataBranchName), true)
+	require.NoError(t, err)
+	assert.Equal(t, merged.Hash(), remoteBranchHash(t, remoteDir))
+	assert.True(t, isAncestorCommit(repo, otherTip, merged.Hash()), "merge should include the other clone's commit")
+}
+
+func TestSyncRef_ReportsFailure(t *testing.T) {
+	dir := setupGitRepo(t)
+	t.Chdir(dir)
+	oldDelays := syncRetryDelays
+	syncRetryDelays = nil
+	t.Cleanup(func() { syncRetryDelays = oldDelays })
+
+	repo, err := git.PlainOpen(dir)
+	require.NoError(t, err)
+	_, err = repo.CreateRemote(&config.RemoteConfig{Name: "broken", URLs: []string{t.TempDir() + "/missing"}})
+	require.NoError(t, err)
+	addMetadataCommits(t, repo, "local", 1)
+
+	result := SyncRef(context.Background(), "broken", paths.MetadataBranchName, SyncOptions{})
+	require.Error(t, result.Err)
+	assert.Equal(t, "broken", result.Remote)
+	assert.Equal(t, 0, result.Pushed)
+}
+
+func TestRetryTransient(t *testing.T) {
+	oldDelays := syncRetryDelays
+	syncRetryDelays = []time.Duration{time.Millisecond, time.Millisecond}
+	t.Cleanup(func() { syncRetryDelays = oldDelays })
+	ctx := context.Background()
+
+	attempts := 0
+	err := retryTransient(ctx, func() error {
+		attempts++
+		if attempts < 3 {
+			return errors.New("fatal: the remote end hung up unexpectedly")
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 3, attempts)
+
+	attempts = 0
+	err = retryTransient(ctx, func() error {
+		attempts++
+		return errors.New("remote: Permission to repo denied")
+	})
+	require.Error(t, err)
+	assert.Equal(t, 1, attempts, "permanent failures should not be retried")
+
+	attempts = 0
+	err = retryTransient(ctx, func() error {
+		attempts++
+		return errors.New("Could not resolve host: github.com")
+	})
+	require.Error(t, err)
+	assert.Equal(t, 3, attempts, "retries should stop after the last delay")
+}
+
+func TestEstimateSync(t *testing.T) {
+	dir := setupGitRepo(t)
+	setupSyncRemote(t, dir)
+	t.Chdir(dir)
+	ctx := context.Background()
+
+	repo, err := git.PlainOpen(dir)
+	require.NoError(t, err)
+	addMetadataCommits(t, repo, "local", 3)
+
+	estimate := EstimateSync(ctx, "origin", paths.MetadataBranchName)
+	require.NoError(t, estimate.Err)
+	assert.Equal(t, 3, estimate.PushCommits)
+	assert.Positive(t, estimate.PushBytes)
+	assert.False(t, estimate.PullPending)
+
+	result := SyncRef(ctx, "origin", paths.MetadataBranchName, SyncOptions{})
+	require.NoError(t, result.Err)
+	addMetadataCommits(t, repo, "more", 1)
+
+	estimate = EstimateSync(ctx, "origin", paths.MetadataBranchName)
+	require.NoError(t, estimate.Err)
+	assert.Equal(t, 1, estimate.PushCommits)
+	assert.Less(t, estimate.PushBytes, int64(1024), "only the new commit's objects should be counted")
+}
+
+func TestBandwidthPacer_Delay(t *testing.T) {
+	t.Parallel()
+	pacer := NewBandwidthPacer(1000)
+
+	assert.Equal(t, 2*time.Second, pacer.delay(2000, 0))
+	assert.Equal(t, 1500*time.Millisecond, pacer.delay(2000, 500*time.Millisecond))
+	assert.LessOrEqual(t, pacer.delay(500, time.Second), time.Duration(0), "a slow transfer needs no pause")
+
+	var slept time.Duration
+	pacer.sleep = func(_ context.Context, d time.Duration) error {
+		slept = d
+		return nil
+	}
+	require.NoError(t, pacer.Wait(context.Background(), 3000, time.Second))
+	assert.Equal(t, 2*time.Second, slept)
+}
+
+func TestSyncRef_PacesPushes(t *testing.T) {
+	dir := setupGitRepo(t)
+	setupSyncRemote(t, dir)
+	t.Chdir(dir)
+
+	oldBatch := syncPushBatchSize
+	syncPushBatchSize = 1
+	t.Cleanup(func() { syncPushBatchSize = oldBatch })
+
+	repo, err := git.PlainOpen(dir)
+	require.NoError(t, err)
+	addMetadataCommits(t, repo, "local", 3)
+
+	pacer := NewBandwidthPacer(1)
+	pauses := 0
+	pacer.sleep = func(_ context.Context, d time.Duration) error {
+		assert.Greater(t, d, time.Duration(0))
+		pauses++
+		return nil
+	}
+	result := SyncRef(context.Background(), "origin", paths.MetadataBranchName, SyncOptions{Pacer: pacer})
+	require.NoError(t, result.Err)
+	assert.Equal(t, 3, result.Pushed)
+	assert.Equal(t, 3, pauses, "each batch should be paced")
+}
+
+func TestPushSessionsBranch_UsesSyncRemote(t *testing.T) {
+	dir := setupGitRepo(t)
+	originDir := setupSyncRemote(t, dir)
+	t.Chdir(dir)
+
+	repo, err := git.PlainOpen(dir)
+	require.NoError(t, err)
+	sessionsDir := t.TempDir()
+	_, err = git.PlainInit(sessionsDir, true)
+	require.NoError(t, err)
+	_, err = repo.CreateRemote(&config.RemoteConfig{Name: "entire-remote", URLs: []string{sessionsDir}})
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, ".entire"), 0o755))
+	require.NoError(t, writeTestFile(filepath.Join(dir, ".entire", "settings.json"), `{"enabled": true, "sync": {"remote": "entire-remote"}}`))
+	tip := addMetadataCommits(t, repo, "local", 1)
+
+	require.NoError(t, pushSessionsBranchCommon(context.Background(), "origin", paths.MetadataBranchName))
+
+	assert.Equal(t, tip, remoteBranchHash(t, sessionsDir))
+	origin, err := git.PlainOpen(originDir)
+	require.NoError(t, err)
+	_, err = origin.Reference(plumbing.NewBranchReferenceName(paths.MetadataBranchName), true)
+	require.ErrorIs(t, err, plumbing.ErrReferenceNotFound, "session data should not reach the code remote")
+}
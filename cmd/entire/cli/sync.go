@@ -0,0 +1,246 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+	"github.com/entireio/cli/cmd/entire/cli/settings"
+	"github.com/entireio/cli/cmd/entire/cli/strategy"
+	"github.com/spf13/cobra"
+)
+
+func newSyncCmd() *cobra.Command {
+	var maxBandwidthFlag string
+	var dryRunFlag bool
+
+	cmd := &cobra.Command{
+		Use:   "sync [remote...]",
+		Short: "Exchange checkpoint data with remotes",
+		Long: `Pull checkpoint data from remotes and push local checkpoints back.
+
+Syncs the ` + paths.MetadataBranchName + ` branch with the remotes given as
+arguments. Without arguments, syncs with the sync.remote setting's remote,
+or with every configured remote if it isn't set. Diverged histories are
+merged.
+
+Transient network failures are retried with backoff, and local commits are
+pushed in batches, so an interrupted sync keeps the progress it made. Each
+remote is reported separately; if any of them fails, run 'entire sync'
+again to resume.
+
+When a remote's history was rewritten (e.g. a collaborator pruned old
+checkpoints), sync merges it by default, keeping the pruned checkpoints.
+With "sync": {"prunes": "adopt"} in settings, checkpoints the remote pruned
+are removed locally as well; the previous local branch is archived under
+refs/entire/prune-archive/ first.
+
+Pushes send thin packs and fetches only negotiate from the checkpoint
+branch's history, so unchanged objects aren't transferred. On constrained
+links, --max-bandwidth caps the average rate (e.g. 500K or 2M bytes per
+second) by pausing between transfers. --dry-run shows what would be pushed
+and its estimated size without transferring anything.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			if _, err := paths.WorktreeRoot(ctx); err != nil {
+				return paths.ErrNotGitRepository
+			}
+			var opts strategy.SyncOptions
+			if s, err := settings.Load(ctx); err == nil {
+				opts.AdoptPrunes = s.Sync.AdoptsPrunes()
+			}
+			if maxBandwidthFlag != "" {
+				limit, err := parseBandwidth(maxBandwidthFlag)
+				if err != nil {
+					return err
+				}
+				opts.Pacer = strategy.NewBandwidthPacer(limit)
+			}
+			if dryRunFlag {
+				return runSyncDryRun(ctx, cmd.OutOrStdout(), args)
+			}
+			return runSync(ctx, cmd.OutOrStdout(), args, opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&maxBandwidthFlag, "max-bandwidth", "", "Cap the average transfer rate, in bytes per second (K, M, G suffixes)")
+	cmd.Flags().BoolVar(&dryRunFlag, "dry-run", false, "Show estimated transfers without syncing")
+
+	return cmd
+}
+
+func runSync(ctx context.Context, w io.Writer, remotes []string, opts strategy.SyncOptions) error {
+	remotes, err := syncRemotes(ctx, remotes)
+	if err != nil {
+		return err
+	}
+	if len(remotes) == 0 {
+		fmt.Fprintln(w, "No remotes configured, nothing to sync.")
+		return nil
+	}
+
+	failed := 0
+	for _, remote := range remotes {
+		for _, ref := range strategy.SyncRefs {
+			result := strategy.SyncRef(ctx, remote, ref, opts)
+			if result.Err != nil {
+				failed++
+				fmt.Fprintf(w, "✗ %s %s: %v\n", remote, ref, result.Err)
+				if result.Pushed > 0 {
+					fmt.Fprintf(w, "  pushed %d commit(s) before failing\n", result.Pushed)
+				}
+				continue
+			}
+			fmt.Fprintf(w, "✓ %s %s: %s\n", remote, ref, describeSyncResult(result))
+			if result.ArchiveRef != "" {
+				fmt.Fprintf(w, "  remote history was pruned; removed %d checkpoint(s) locally, previous branch archived at %s\n",
+					len(result.Pruned), result.ArchiveRef)
+			}
+		}
+	}
+
+	total := len(remotes) * len(strategy.SyncRefs)
+	if failed > 0 {
+		fmt.Fprintf(w, "\n%d of %d ref(s) synced. Run 'entire sync' again to resume.\n", total-failed, total)
+		return NewSilentError(fmt.Errorf("sync incomplete: %d of %d ref(s) failed", failed, total))
+	}
+	fmt.Fprintf(w, "\n%d ref(s) synced.\n", total)
+	return nil
+}
+
+func runSyncDryRun(ctx context.Context, w io.Writer, remotes []string) error {
+	remotes, err := syncRemotes(ctx, remotes)
+	if err != nil {
+		return err
+	}
+	if len(remotes) == 0 {
+		fmt.Fprintln(w, "No remotes configured, nothing to sync.")
+		return nil
+	}
+
+	failed := 0
+	var totalBytes int64
+	for _, remote := range remotes {
+		for _, ref := range strategy.SyncRefs {
+			estimate := strategy.EstimateSync(ctx, remote, ref)
+			if estimate.Err != nil {
+				failed++
+				fmt.Fprintf(w, "✗ %s %s: %v\n", remote, ref, estimate.Err)
+				continue
+			}
+			totalBytes += estimate.PushBytes
+			fmt.Fprintf(w, "  %s %s: %s\n", remote, ref, describeSyncEstimate(estimate))
+		}
+	}
+
+	fmt.Fprintf(w, "\nDry run: nothing was transferred. Estimated upload: %s.\n", formatByteSize(totalBytes))
+	if failed > 0 {
+		return NewSilentError(fmt.Errorf("could not estimate %d ref(s)", failed))
+	}
+	return nil
+}
+
+// describeSyncEstimate summarizes what a sync would transfer.
+func describeSyncEstimate(estimate strategy.SyncEstimate) string {
+	var parts []string
+	if estimate.PushCommits > 0 {
+		parts = append(parts, fmt.Sprintf("push %d commit(s), ~%s", estimate.PushCommits, formatByteSize(estimate.PushBytes)))
+	}
+	if estimate.PullPending {
+		parts = append(parts, "pull new commits (size known once fetched)")
+	}
+	if len(parts) == 0 {
+		return "up to date"
+	}
+	return strings.Join(parts, "; ")
+}
+
+// describeSyncResult summarizes what a successful sync changed.
+func describeSyncResult(result strategy.SyncResult) string {
+	switch {
+	case result.Pulled && result.Pushed > 0:
+		return fmt.Sprintf("pulled, pushed %d commit(s)", result.Pushed)
+	case result.Pulled:
+		return "pulled"
+	case result.Pushed > 0:
+		return fmt.Sprintf("pushed %d commit(s)", result.Pushed)
+	default:
+		return "up to date"
+	}
+}
+
+// syncRemotes returns the remotes given on the command line. Without any,
+// it returns sync.remote if set, or else every configured remote.
+func syncRemotes(ctx context.Context, remotes []string) ([]string, error) {
+	if len(remotes) > 0 {
+		return remotes, nil
+	}
+	if s, err := settings.Load(ctx); err == nil && s.Sync != nil && s.Sync.Remote != "" {
+		return []string{s.Sync.Remote}, nil
+	}
+	return configuredRemotes(ctx)
+}
+
+// parseBandwidth parses a rate such as "500K", "2MB/s", or "1048576" into
+// bytes per second. Suffixes are binary multiples.
+func parseBandwidth(value string) (int64, error) {
+	s := strings.ToUpper(strings.TrimSpace(value))
+	s = strings.TrimSuffix(s, "/S")
+	s = strings.TrimSuffix(s, "IB")
+	s = strings.TrimSuffix(s, "B")
+	multiplier := int64(1)
+	if s != "" {
+		switch s[len(s)-1] {
+		case 'K':
+			multiplier = 1 << 10
+		case 'M':
+			multiplier = 1 << 20
+		case 'G':
+			multiplier = 1 << 30
+		}
+		if multiplier > 1 {
+			s = s[:len(s)-1]
+		}
+	}
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid --max-bandwidth %q: use a positive number of bytes per second, e.g. 500K or 2M", value)
+	}
+	return int64(n * float64(multiplier)), nil
+}
+
+// formatByteSize renders a byte count with a binary unit, e.g. "1.5 MB".
+func formatByteSize(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGT"[exp])
+}
+
+// configuredRemotes returns the names of the repository's remotes, sorted.
+func configuredRemotes(ctx context.Context) ([]string, error) {
+	repo, err := openRepository(ctx)
+	if err != nil {
+		return nil, err
+	}
+	list, err := repo.Remotes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list remotes: %w", err)
+	}
+	names := make([]string, 0, len(list))
+	for _, remote := range list {
+		names = append(names, remote.Config().Name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
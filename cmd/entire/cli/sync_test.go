@@ -0,0 +1,149 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+	"github.com/entireio/cli/cmd/entire/cli/strategy"
+)
+
+func TestRunSync_ReportsEachRemote(t *testing.T) {
+	dir := setupExecTestRepo(t)
+	writePurgeTestCheckpoint(t, id.MustCheckpointID("a7a7a7a7a7a7"))
+
+	goodRemote := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "--bare", goodRemote},
+		{"-C", dir, "remote", "add", "good", goodRemote},
+		{"-C", dir, "remote", "add", "bad", filepath.Join(t.TempDir(), "missing")},
+	} {
+		if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+
+	var out bytes.Buffer
+	err := runSync(context.Background(), &out, nil, strategy.SyncOptions{})
+	if err == nil || !strings.Contains(err.Error(), "1 of 2 ref(s) failed") {
+		t.Errorf("runSync() error = %v, want a partial failure", err)
+	}
+	if !strings.Contains(out.String(), "✓ good entire/checkpoints/v1: pushed ") {
+		t.Errorf("output should report the pushed remote, got: %s", out.String())
+	}
+	if !strings.Contains(out.String(), "✗ bad entire/checkpoints/v1: fetch failed") {
+		t.Errorf("output should report the failed remote, got: %s", out.String())
+	}
+	if !strings.Contains(out.String(), "Run 'entire sync' again to resume.") {
+		t.Errorf("output should explain how to resume, got: %s", out.String())
+	}
+
+	out.Reset()
+	if err := runSync(context.Background(), &out, []string{"good"}, strategy.SyncOptions{}); err != nil {
+		t.Fatalf("runSync(good) error = %v", err)
+	}
+	if !strings.Contains(out.String(), "✓ good entire/checkpoints/v1: up to date") {
+		t.Errorf("second sync should have nothing to do, got: %s", out.String())
+	}
+}
+
+func TestRunSyncDryRun(t *testing.T) {
+	dir := setupExecTestRepo(t)
+	writePurgeTestCheckpoint(t, id.MustCheckpointID("b8b8b8b8b8b8"))
+	remote := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "--bare", remote},
+		{"-C", dir, "remote", "add", "origin", remote},
+	} {
+		if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+
+	var out bytes.Buffer
+	if err := runSyncDryRun(context.Background(), &out, nil); err != nil {
+		t.Fatalf("runSyncDryRun() error = %v", err)
+	}
+	if !strings.Contains(out.String(), "origin entire/checkpoints/v1: push 2 commit(s), ~") {
+		t.Errorf("output should estimate the push, got: %s", out.String())
+	}
+	if !strings.Contains(out.String(), "Dry run: nothing was transferred.") {
+		t.Errorf("output should say nothing was transferred, got: %s", out.String())
+	}
+	if refs, err := exec.Command("git", "-C", remote, "for-each-ref").Output(); err != nil || len(refs) != 0 {
+		t.Errorf("dry run changed the remote: %s (%v)", refs, err)
+	}
+}
+
+func TestParseBandwidth(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		in      string
+		want    int64
+		wantErr bool
+	}{
+		{in: "1048576", want: 1 << 20},
+		{in: "500K", want: 500 << 10},
+		{in: "2MB/s", want: 2 << 20},
+		{in: "1.5m", want: 3 << 19},
+		{in: "1GiB", want: 1 << 30},
+		{in: "fast", wantErr: true},
+		{in: "0", wantErr: true},
+		{in: "-1M", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := parseBandwidth(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseBandwidth(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseBandwidth(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestFormatByteSize(t *testing.T) {
+	t.Parallel()
+	for in, want := range map[int64]string{
+		512:     "512 B",
+		1536:    "1.5 KB",
+		5 << 20: "5.0 MB",
+		3 << 30: "3.0 GB",
+	} {
+		if got := formatByteSize(in); got != want {
+			t.Errorf("formatByteSize(%d) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestSyncRemotes_UsesSyncRemoteSetting(t *testing.T) {
+	dir := setupExecTestRepo(t)
+	for _, name := range []string{"origin", "entire-remote"} {
+		if out, err := exec.Command("git", "-C", dir, "remote", "add", name, t.TempDir()).CombinedOutput(); err != nil {
+			t.Fatalf("git remote add: %v\n%s", err, out)
+		}
+	}
+	ctx := context.Background()
+
+	remotes, err := syncRemotes(ctx, nil)
+	if err != nil {
+		t.Fatalf("syncRemotes() error = %v", err)
+	}
+	if strings.Join(remotes, ",") != "entire-remote,origin" {
+		t.Errorf("syncRemotes() = %v, want every remote", remotes)
+	}
+
+	writeSettings(t, `{"enabled": true, "sync": {"remote": "entire-remote"}}`)
+	remotes, err = syncRemotes(ctx, nil)
+	if err != nil {
+		t.Fatalf("syncRemotes() error = %v", err)
+	}
+	if strings.Join(remotes, ",") != "entire-remote" {
+		t.Errorf("syncRemotes() = %v, want only the sync remote", remotes)
+	}
+}
@@ -0,0 +1,120 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+	"github.com/entireio/cli/cmd/entire/cli/strategy"
+	"github.com/spf13/cobra"
+)
+
+func newTagCmd() *cobra.Command {
+	var deleteFlag bool
+	var listFlag string
+
+	cmd := &cobra.Command{
+		Use:   "tag <checkpoint-id> [label...]",
+		Short: "Label a checkpoint, or show its labels",
+		Long: `Tag puts labels such as "good-state" or "before-refactor" on a committed
+checkpoint, so you can find it again. Labels are stored in the checkpoint's
+metadata on the entire/checkpoints/v1 branch; push the metadata branch to
+share them with your team.
+
+Without labels, the checkpoint's labels are shown. --delete removes the given
+labels instead. --list <label> lists the checkpoints carrying a label, and
+'entire rewind --tag <label>' offers only those checkpoints as rewind points.
+
+Labels can't contain whitespace or commas, or start with '-'.`,
+		Args: func(_ *cobra.Command, args []string) error {
+			if listFlag != "" {
+				return cobra.NoArgs(nil, args)
+			}
+			return cobra.MinimumNArgs(1)(nil, args)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			if _, err := paths.WorktreeRoot(ctx); err != nil {
+				return paths.ErrNotGitRepository
+			}
+			if listFlag != "" {
+				return runTagList(ctx, cmd.OutOrStdout(), listFlag)
+			}
+			if deleteFlag && len(args) == 1 {
+				return errors.New("--delete needs the labels to remove")
+			}
+			return runTag(ctx, cmd.OutOrStdout(), args[0], args[1:], deleteFlag)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&deleteFlag, "delete", "d", false, "Remove the labels instead of adding them")
+	cmd.Flags().StringVarP(&listFlag, "list", "l", "", "List the checkpoints carrying this label")
+	cmd.MarkFlagsMutuallyExclusive("delete", "list")
+
+	return cmd
+}
+
+func runTag(ctx context.Context, w io.Writer, checkpointIDPrefix string, tags []string, remove bool) error {
+	repo, err := openRepository(ctx)
+	if err != nil {
+		return fmt.Errorf("not a git repository: %w", err)
+	}
+	store, err := strategy.CommittedStore(ctx, checkpoint.NewGitStore(repo))
+	if err != nil {
+		return err //nolint:wrapcheck // already names the setting
+	}
+
+	checkpointID, err := resolveCommittedCheckpointID(ctx, store, checkpointIDPrefix)
+	if err != nil {
+		return err
+	}
+	switch {
+	case len(tags) == 0:
+	case remove:
+		if err := store.Untag(ctx, checkpointID, tags...); err != nil {
+			return fmt.Errorf("failed to remove labels: %w", err)
+		}
+	default:
+		if err := store.Tag(ctx, checkpointID, tags...); err != nil {
+			return fmt.Errorf("failed to add labels: %w", err)
+		}
+	}
+
+	summary, err := store.ReadCommitted(ctx, checkpointID)
+	if err != nil || summary == nil {
+		return fmt.Errorf("failed to read checkpoint %s: %w", checkpointID, err)
+	}
+	if len(summary.Tags) == 0 {
+		fmt.Fprintf(w, "Checkpoint %s has no labels.\n", checkpointID)
+		return nil
+	}
+	fmt.Fprintf(w, "Checkpoint %s: %s\n", checkpointID, strings.Join(summary.Tags, ", "))
+	return nil
+}
+
+func runTagList(ctx context.Context, w io.Writer, tag string) error {
+	repo, err := openRepository(ctx)
+	if err != nil {
+		return fmt.Errorf("not a git repository: %w", err)
+	}
+	store, err := strategy.CommittedStore(ctx, checkpoint.NewGitStore(repo))
+	if err != nil {
+		return err //nolint:wrapcheck // already names the setting
+	}
+	tagged, err := store.ListByTag(ctx, tag)
+	if err != nil {
+		return fmt.Errorf("failed to list checkpoints: %w", err)
+	}
+	if len(tagged) == 0 {
+		fmt.Fprintf(w, "No checkpoints labeled %q.\n", tag)
+		return nil
+	}
+	for _, info := range tagged {
+		fmt.Fprintf(w, "%s  %s  %s\n", info.CheckpointID, info.CreatedAt.Local().Format("2006-01-02 15:04"), strings.Join(info.Tags, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+)
+
+func TestRunTag_AddListDelete(t *testing.T) {
+	repo, _ := setupCleanTestRepo(t)
+	store := checkpoint.NewGitStore(repo)
+	ctx := context.Background()
+
+	if err := store.WriteCommitted(ctx, checkpoint.WriteCommittedOptions{
+		CheckpointID: id.MustCheckpointID("a1b2c3d4e5f6"),
+		SessionID:    "session-1",
+		Strategy:     "manual-commit",
+		Transcript:   []byte(`{"type":"user"}` + "\n"),
+		AuthorName:   "Test",
+		AuthorEmail:  "test@test.com",
+	}); err != nil {
+		t.Fatalf("WriteCommitted() error = %v", err)
+	}
+
+	var out bytes.Buffer
+	if err := runTag(ctx, &out, "a1b2", []string{"good-state", "before-refactor"}, false); err != nil {
+		t.Fatalf("runTag() error = %v", err)
+	}
+	if got := out.String(); got != "Checkpoint a1b2c3d4e5f6: before-refactor, good-state\n" {
+		t.Errorf("runTag() output = %q", got)
+	}
+
+	out.Reset()
+	if err := runTagList(ctx, &out, "good-state"); err != nil {
+		t.Fatalf("runTagList() error = %v", err)
+	}
+	if !strings.HasPrefix(out.String(), "a1b2c3d4e5f6  ") {
+		t.Errorf("runTagList() output = %q, want the checkpoint", out.String())
+	}
+
+	out.Reset()
+	if err := runTag(ctx, &out, "a1b2", []string{"good-state", "before-refactor"}, true); err != nil {
+		t.Fatalf("runTag() delete error = %v", err)
+	}
+	if got := out.String(); got != "Checkpoint a1b2c3d4e5f6 has no labels.\n" {
+		t.Errorf("runTag() delete output = %q", got)
+	}
+
+	out.Reset()
+	if err := runTagList(ctx, &out, "good-state"); err != nil {
+		t.Fatalf("runTagList() error = %v", err)
+	}
+	if !strings.Contains(out.String(), "No checkpoints labeled") {
+		t.Errorf("runTagList() after delete = %q", out.String())
+	}
+
+	if err := runTag(ctx, &out, "a1b2", []string{"two words"}, false); err == nil {
+		t.Error("runTag() with an invalid label succeeded, want an error")
+	}
+}
@@ -20,6 +20,24 @@ var (
 	PostHogEndpoint = "https://eu.i.posthog.com"
 )
 
+// OptOutEnvVar disables telemetry when set to any value, regardless of
+// settings. DO_NOT_TRACK=1 is honored the same way.
+const OptOutEnvVar = "ENTIRE_TELEMETRY_OPTOUT"
+
+// Disabled reports whether telemetry is switched off by the environment.
+// It is the hard off switch: nothing is built, spawned, or sent when true.
+func Disabled() bool {
+	if os.Getenv(OptOutEnvVar) != "" {
+		return true
+	}
+	switch strings.ToLower(strings.TrimSpace(os.Getenv("DO_NOT_TRACK"))) {
+	case "", "0", "false":
+		return false
+	default:
+		return true
+	}
+}
+
 // EventPayload represents the data passed to the detached subprocess.
 // Note: APIKey and Endpoint are intentionally excluded to avoid exposing
 // them in process listings (ps/top). SendEvent reads them from package-level vars.
@@ -86,8 +104,7 @@ func BuildEventPayload(cmd *cobra.Command, agent string, isEntireEnabled bool, v
 // TrackCommandDetached tracks a command execution by spawning a detached subprocess.
 // This returns immediately without blocking the CLI.
 func TrackCommandDetached(cmd *cobra.Command, agent string, isEntireEnabled bool, version string) {
-	// Check opt-out environment variables
-	if os.Getenv("ENTIRE_TELEMETRY_OPTOUT") != "" {
+	if Disabled() {
 		return
 	}
 
@@ -99,11 +116,14 @@ func TrackCommandDetached(cmd *cobra.Command, agent string, isEntireEnabled bool
 		return
 	}
 
-	payload := BuildEventPayload(cmd, agent, isEntireEnabled, version)
+	spawnPayload(BuildEventPayload(cmd, agent, isEntireEnabled, version))
+}
+
+// spawnPayload hands a payload to the detached sender.
+func spawnPayload(payload *EventPayload) {
 	if payload == nil {
 		return
 	}
-
 	if payloadJSON, err := json.Marshal(payload); err == nil {
 		spawnDetachedAnalytics(string(payloadJSON))
 	}
@@ -112,6 +132,11 @@ func TrackCommandDetached(cmd *cobra.Command, agent string, isEntireEnabled bool
 // SendEvent processes an event payload in the detached subprocess.
 // This is called by the hidden __send_analytics command.
 func SendEvent(payloadJSON string) {
+	// Checked again here in case the switch was flipped after spawning
+	if Disabled() {
+		return
+	}
+
 	var payload EventPayload
 	if err := json.Unmarshal([]byte(payloadJSON), &payload); err != nil {
 		return
@@ -0,0 +1,112 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"net"
+	"os/exec"
+	"runtime"
+	"time"
+
+	"github.com/denisbrodbeck/machineid"
+	"github.com/spf13/cobra"
+)
+
+// Error categories reported for failed commands. Only the category is sent,
+// never the error message, which may contain paths, prompts, or code.
+const (
+	ErrorCategoryCanceled   = "canceled"
+	ErrorCategoryTimeout    = "timeout"
+	ErrorCategoryPermission = "permission"
+	ErrorCategoryNotFound   = "not_found"
+	ErrorCategoryNetwork    = "network"
+	ErrorCategorySubprocess = "subprocess"
+	ErrorCategoryUsage      = "usage"
+	ErrorCategoryNotRepo    = "not_git_repository"
+	ErrorCategoryOther      = "other"
+)
+
+// ErrorCategories lists every category ErrorCategory can return.
+var ErrorCategories = []string{
+	ErrorCategoryCanceled,
+	ErrorCategoryTimeout,
+	ErrorCategoryPermission,
+	ErrorCategoryNotFound,
+	ErrorCategoryNetwork,
+	ErrorCategorySubprocess,
+	ErrorCategoryUsage,
+	ErrorCategoryNotRepo,
+	ErrorCategoryOther,
+}
+
+// ErrorCategory maps err to one of ErrorCategories using its type and
+// wrapped sentinels only.
+func ErrorCategory(err error) string {
+	var netErr net.Error
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+		return ""
+	case errors.Is(err, context.Canceled):
+		return ErrorCategoryCanceled
+	case errors.Is(err, context.DeadlineExceeded):
+		return ErrorCategoryTimeout
+	case errors.Is(err, fs.ErrPermission):
+		return ErrorCategoryPermission
+	case errors.Is(err, fs.ErrNotExist):
+		return ErrorCategoryNotFound
+	case errors.As(err, &netErr):
+		return ErrorCategoryNetwork
+	case errors.As(err, &exitErr):
+		return ErrorCategorySubprocess
+	default:
+		return ErrorCategoryOther
+	}
+}
+
+// BuildErrorPayload constructs the event sent when a command fails.
+// Exported for testing. Returns nil if the payload cannot be built.
+func BuildErrorPayload(cmd *cobra.Command, category, version string) *EventPayload {
+	if cmd == nil {
+		return nil
+	}
+
+	machineID, err := machineid.ProtectedID("entire-cli")
+	if err != nil {
+		return nil
+	}
+
+	return &EventPayload{
+		Event:      "cli_command_failed",
+		DistinctID: machineID,
+		Properties: map[string]any{
+			"command":        cmd.CommandPath(),
+			"error_category": category,
+			"cli_version":    version,
+			"os":             runtime.GOOS,
+			"arch":           runtime.GOARCH,
+		},
+		Timestamp: time.Now(),
+	}
+}
+
+// TrackErrorDetached reports a failed command's error category by spawning
+// a detached subprocess. This returns immediately without blocking the CLI.
+func TrackErrorDetached(cmd *cobra.Command, category, version string) {
+	if Disabled() {
+		return
+	}
+
+	if cmd == nil || category == "" {
+		return
+	}
+
+	for c := cmd; c != nil; c = c.Parent() {
+		if c.Hidden {
+			return
+		}
+	}
+
+	spawnPayload(BuildErrorPayload(cmd, category, version))
+}
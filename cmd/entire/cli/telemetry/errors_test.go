@@ -0,0 +1,84 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestErrorCategory(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"nil", nil, ""},
+		{"canceled", fmt.Errorf("sync: %w", context.Canceled), ErrorCategoryCanceled},
+		{"timeout", context.DeadlineExceeded, ErrorCategoryTimeout},
+		{"permission", &fs.PathError{Op: "open", Path: "/secret/prompt.txt", Err: fs.ErrPermission}, ErrorCategoryPermission},
+		{"not found", fmt.Errorf("read: %w", fs.ErrNotExist), ErrorCategoryNotFound},
+		{"network", &net.OpError{Op: "dial", Err: errors.New("refused")}, ErrorCategoryNetwork},
+		{"other", errors.New("something in /home/me/code"), ErrorCategoryOther},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := ErrorCategory(tt.err); got != tt.want {
+				t.Errorf("ErrorCategory() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildErrorPayloadOmitsMessage(t *testing.T) {
+	t.Parallel()
+	root := &cobra.Command{Use: "entire"}
+	sub := &cobra.Command{Use: "sync"}
+	root.AddCommand(sub)
+
+	payload := BuildErrorPayload(sub, ErrorCategoryNetwork, "1.0.0")
+	if payload == nil {
+		t.Fatal("Expected non-nil payload")
+		return
+	}
+	if payload.Event != "cli_command_failed" {
+		t.Errorf("Event = %q, want cli_command_failed", payload.Event)
+	}
+	if payload.Properties["command"] != "entire sync" || payload.Properties["error_category"] != ErrorCategoryNetwork {
+		t.Errorf("Properties = %v", payload.Properties)
+	}
+	for key := range payload.Properties {
+		switch key {
+		case "command", "error_category", "cli_version", "os", "arch":
+		default:
+			t.Errorf("unexpected property %q", key)
+		}
+	}
+}
+
+func TestDisabled(t *testing.T) {
+	tests := []struct {
+		optOut, doNotTrack string
+		want               bool
+	}{
+		{"", "", false},
+		{"1", "", true},
+		{"", "1", true},
+		{"", "true", true},
+		{"", "0", false},
+		{"", "false", false},
+	}
+	for _, tt := range tests {
+		t.Setenv(OptOutEnvVar, tt.optOut)
+		t.Setenv("DO_NOT_TRACK", tt.doNotTrack)
+		if got := Disabled(); got != tt.want {
+			t.Errorf("Disabled() with %s=%q DO_NOT_TRACK=%q = %v, want %v", OptOutEnvVar, tt.optOut, tt.doNotTrack, got, tt.want)
+		}
+	}
+}
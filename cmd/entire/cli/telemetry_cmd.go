@@ -0,0 +1,140 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+	"github.com/entireio/cli/cmd/entire/cli/telemetry"
+	"github.com/entireio/cli/cmd/entire/cli/versioninfo"
+	"github.com/spf13/cobra"
+)
+
+func newTelemetryCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "telemetry",
+		Short: "Inspect anonymous usage telemetry",
+		Long: `Telemetry is strictly opt-in: nothing is sent unless "telemetry": true is
+set in settings (entire enable asks once). It reports which command ran,
+the names of flags used (never their values), the installed agents, the
+CLI version, OS and architecture, and for failed commands a coarse error
+category. Prompts, transcripts, code, paths, and error messages are never
+sent.
+
+Setting ` + telemetry.OptOutEnvVar + ` or DO_NOT_TRACK=1 turns telemetry off
+everywhere, overriding settings.`,
+	}
+
+	cmd.AddCommand(newTelemetryPreviewCmd())
+
+	return cmd
+}
+
+func newTelemetryPreviewCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "preview [command...]",
+		Short: "Show exactly what telemetry would send for a command",
+		Long: `Preview prints the events telemetry would send for the given command line
+(e.g. 'entire telemetry preview status --all') without sending anything.
+Flags after the command are only used for their names.`,
+		DisableFlagParsing: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) > 0 && (args[0] == "-h" || args[0] == "--help") {
+				return cmd.Help()
+			}
+			return runTelemetryPreview(cmd.Context(), cmd.OutOrStdout(), cmd.Root(), args)
+		},
+	}
+}
+
+func runTelemetryPreview(ctx context.Context, w io.Writer, root *cobra.Command, args []string) error {
+	target, rest, err := root.Find(args)
+	if err != nil {
+		return fmt.Errorf("unknown command %q", strings.Join(args, " "))
+	}
+	if err := target.ParseFlags(rest); err != nil {
+		return fmt.Errorf("invalid flags for %s: %w", target.CommandPath(), err)
+	}
+
+	settings, loadErr := LoadEntireSettings(ctx)
+	consented := loadErr == nil && telemetryConsented(settings)
+	switch {
+	case telemetry.Disabled():
+		fmt.Fprintf(w, "Telemetry is off: disabled by %s or DO_NOT_TRACK.\n", telemetry.OptOutEnvVar)
+	case consented:
+		fmt.Fprintln(w, "Telemetry is on.")
+	default:
+		fmt.Fprintln(w, `Telemetry is off: "telemetry" is not true in settings.`)
+	}
+	for c := target; c != nil; c = c.Parent() {
+		if c.Hidden {
+			fmt.Fprintf(w, "%s is internal and is never reported.\n", target.CommandPath())
+			return nil
+		}
+	}
+
+	isEntireEnabled := loadErr == nil && settings.Enabled
+	agentStr := JoinAgentNames(GetAgentsWithHooksInstalled(ctx))
+	success := telemetry.BuildEventPayload(target, agentStr, isEntireEnabled, versioninfo.Version)
+	failure := telemetry.BuildErrorPayload(target, telemetry.ErrorCategoryOther, versioninfo.Version)
+	if success == nil || failure == nil {
+		return errors.New("could not build telemetry payload: machine ID unavailable")
+	}
+
+	fmt.Fprintln(w, "\nIf the command succeeds:")
+	if err := writePayloadJSON(w, success); err != nil {
+		return err
+	}
+	fmt.Fprintln(w, "\nIf the command fails (error_category is one of "+strings.Join(telemetry.ErrorCategories, ", ")+"):")
+	return writePayloadJSON(w, failure)
+}
+
+func writePayloadJSON(w io.Writer, payload *telemetry.EventPayload) error {
+	data, err := json.MarshalIndent(payload, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode payload: %w", err)
+	}
+	fmt.Fprintln(w, string(data))
+	return nil
+}
+
+// telemetryConsented reports whether the user opted in to telemetry.
+// telemetry.Disabled is checked separately where events are sent.
+func telemetryConsented(settings *EntireSettings) bool {
+	return settings != nil && settings.Telemetry != nil && *settings.Telemetry
+}
+
+// TrackCommandError reports the category of a failed command's error when
+// the user opted in to telemetry. Called by main after Execute returns.
+func TrackCommandError(cmd *cobra.Command, err error) {
+	if err == nil || cmd == nil || telemetry.Disabled() {
+		return
+	}
+	settings, loadErr := LoadEntireSettings(cmd.Context())
+	if loadErr != nil || !telemetryConsented(settings) {
+		return
+	}
+	telemetry.TrackErrorDetached(cmd, commandErrorCategory(err), versioninfo.Version)
+}
+
+// commandErrorCategory refines telemetry.ErrorCategory with errors only the
+// CLI knows about.
+func commandErrorCategory(err error) string {
+	var exitCode *ExitCodeError
+	msg := err.Error()
+	switch {
+	case errors.As(err, &exitCode):
+		return telemetry.ErrorCategorySubprocess
+	case errors.Is(err, paths.ErrNotGitRepository):
+		return telemetry.ErrorCategoryNotRepo
+	case strings.Contains(msg, "unknown command") || strings.Contains(msg, "unknown flag"),
+		strings.Contains(msg, "arg(s)"):
+		return telemetry.ErrorCategoryUsage
+	default:
+		return telemetry.ErrorCategory(err)
+	}
+}
@@ -0,0 +1,75 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+	"github.com/entireio/cli/cmd/entire/cli/telemetry"
+)
+
+func TestRunTelemetryPreview(t *testing.T) {
+	setupExecTestRepo(t)
+	writeSettings(t, `{"enabled": true, "telemetry": true}`)
+	t.Setenv(telemetry.OptOutEnvVar, "")
+	t.Setenv("DO_NOT_TRACK", "")
+
+	var out bytes.Buffer
+	if err := runTelemetryPreview(context.Background(), &out, NewRootCmd(), []string{"sync", "--max-bandwidth", "500K", "origin"}); err != nil {
+		t.Fatalf("runTelemetryPreview() error = %v", err)
+	}
+	got := out.String()
+	for _, want := range []string{
+		"Telemetry is on.",
+		`"event": "cli_command_executed"`,
+		`"command": "entire sync"`,
+		`"flags": "max-bandwidth"`,
+		`"event": "cli_command_failed"`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("preview missing %q:\n%s", want, got)
+		}
+	}
+	// Flag values and arguments are never part of the payload
+	if strings.Contains(got, "500K") || strings.Contains(got, "origin") {
+		t.Errorf("preview leaked flag values or arguments:\n%s", got)
+	}
+}
+
+func TestRunTelemetryPreview_OffSwitch(t *testing.T) {
+	setupExecTestRepo(t)
+	writeSettings(t, `{"enabled": true, "telemetry": true}`)
+	t.Setenv(telemetry.OptOutEnvVar, "1")
+
+	var out bytes.Buffer
+	if err := runTelemetryPreview(context.Background(), &out, NewRootCmd(), []string{"status"}); err != nil {
+		t.Fatalf("runTelemetryPreview() error = %v", err)
+	}
+	if !strings.HasPrefix(out.String(), "Telemetry is off: disabled by "+telemetry.OptOutEnvVar) {
+		t.Errorf("preview should report the off switch, got:\n%s", out.String())
+	}
+}
+
+func TestCommandErrorCategory(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{&ExitCodeError{Code: 2}, telemetry.ErrorCategorySubprocess},
+		{paths.ErrNotGitRepository, telemetry.ErrorCategoryNotRepo},
+		{fmt.Errorf("failed to install hooks: %w", paths.ErrNotGitRepository), telemetry.ErrorCategoryNotRepo},
+		{errors.New(`unknown command "foo" for "entire"`), telemetry.ErrorCategoryUsage},
+		{NewSilentError(fmt.Errorf("sync: %w", context.DeadlineExceeded)), telemetry.ErrorCategoryTimeout},
+		{errors.New("failed to read /home/me/prompt.txt"), telemetry.ErrorCategoryOther},
+	}
+	for _, tt := range tests {
+		if got := commandErrorCategory(tt.err); got != tt.want {
+			t.Errorf("commandErrorCategory(%v) = %q, want %q", tt.err, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,250 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/entireio/cli/cmd/entire/cli/configtemplate"
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+	"github.com/entireio/cli/cmd/entire/cli/policy"
+
+	"github.com/spf13/cobra"
+)
+
+func newInitCmd() *cobra.Command {
+	var templateFlag string
+	var forceFlag bool
+
+	cmd := &cobra.Command{
+		Use:   "init",
+		Short: "Initialize Entire configuration from a shared template",
+		Long: `Init writes this repository's Entire configuration from a shared template,
+so every repository in an org starts from the same settings and command policy.
+
+The template is a directory containing settings.json and/or policy.json, in
+the same format as the files under .entire/. It can be a local path, a git
+repository URL (e.g. git@github.com:acme/entire-template.git), or an HTTPS URL
+the files are served under.
+
+The template source is recorded in .entire/template.json. Commit it along with
+the settings so 'entire template update' can later pull template changes while
+keeping the repository's own overrides. Run 'entire enable' afterwards to
+install agent hooks.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := cmd.Context()
+			if _, err := paths.WorktreeRoot(ctx); err != nil {
+				return paths.ErrNotGitRepository
+			}
+			return runInitFromTemplate(ctx, cmd.OutOrStdout(), templateFlag, forceFlag)
+		},
+	}
+
+	cmd.Flags().StringVar(&templateFlag, "from-template", "", "Template directory, git repository URL, or HTTPS URL")
+	cmd.Flags().BoolVarP(&forceFlag, "force", "f", false, "Replace existing .entire/settings.json and policy.json")
+	cmd.MarkFlagRequired("from-template") //nolint:errcheck,gosec // flag is defined above
+
+	return cmd
+}
+
+func newTemplateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "template",
+		Short: "Manage the shared configuration template",
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "update",
+		Short: "Pull configuration changes from the template",
+		Long: `Update fetches the template this repository was initialized from and applies
+its changes to .entire/settings.json and .entire/policy.json.
+
+Settings are merged key by key: keys the repository hasn't changed take the
+template's new values, and keys it has changed are kept as local overrides.
+A policy.json with local changes is kept as is. Settings in
+.entire/settings.local.json are never touched.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := cmd.Context()
+			if _, err := paths.WorktreeRoot(ctx); err != nil {
+				return paths.ErrNotGitRepository
+			}
+			return runTemplateUpdate(ctx, cmd.OutOrStdout())
+		},
+	})
+
+	return cmd
+}
+
+// templateFiles resolves the absolute paths of the files a template manages.
+type templateFiles struct {
+	settings, policy, state string
+}
+
+func resolveTemplateFiles(ctx context.Context) (templateFiles, error) {
+	repoRoot, err := paths.WorktreeRoot(ctx)
+	if err != nil {
+		return templateFiles{}, fmt.Errorf("failed to get worktree path: %w", err)
+	}
+	return templateFiles{
+		settings: filepath.Join(repoRoot, EntireSettingsFile),
+		policy:   filepath.Join(repoRoot, policy.PolicyFile),
+		state:    filepath.Join(repoRoot, configtemplate.StateFile),
+	}, nil
+}
+
+func runInitFromTemplate(ctx context.Context, w io.Writer, source string, force bool) error {
+	files, err := resolveTemplateFiles(ctx)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(files.settings); err == nil && !force {
+		return fmt.Errorf("%s already exists; run 'entire template update' to pull template changes, or use --force to replace it", EntireSettingsFile)
+	}
+
+	// Record local templates by absolute path so updates work from any directory
+	if !strings.Contains(source, "://") && !strings.HasPrefix(source, "git@") {
+		if abs, absErr := filepath.Abs(source); absErr == nil {
+			source = abs
+		}
+	}
+
+	tmpl, err := configtemplate.Fetch(ctx, source)
+	if err != nil {
+		return err //nolint:wrapcheck // already descriptive
+	}
+
+	if err := os.MkdirAll(filepath.Dir(files.settings), 0o750); err != nil {
+		return fmt.Errorf("creating .entire directory: %w", err)
+	}
+	if tmpl.Settings != nil {
+		if err := writeTemplateFile(files.settings, tmpl.Settings); err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "Wrote %s\n", EntireSettingsFile)
+	}
+	if tmpl.Policy != nil {
+		if err := writeTemplateFile(files.policy, tmpl.Policy); err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "Wrote %s\n", policy.PolicyFile)
+	}
+
+	if err := configtemplate.SaveState(files.state, &configtemplate.State{
+		Source:    source,
+		UpdatedAt: time.Now().UTC(),
+		Settings:  string(tmpl.Settings),
+		Policy:    string(tmpl.Policy),
+	}); err != nil {
+		return err //nolint:wrapcheck // already descriptive
+	}
+
+	fmt.Fprintf(w, "Initialized from template %s\n", source)
+	fmt.Fprintln(w, "Run 'entire enable' to install agent hooks.")
+	return nil
+}
+
+func runTemplateUpdate(ctx context.Context, w io.Writer) error {
+	files, err := resolveTemplateFiles(ctx)
+	if err != nil {
+		return err
+	}
+	state, err := configtemplate.LoadState(files.state)
+	if errors.Is(err, configtemplate.ErrNoTemplate) {
+		return errors.New("this repository was not initialized from a template; run 'entire init --from-template <source>' first")
+	}
+	if err != nil {
+		return err //nolint:wrapcheck // already descriptive
+	}
+
+	tmpl, err := configtemplate.Fetch(ctx, state.Source)
+	if err != nil {
+		return err //nolint:wrapcheck // already descriptive
+	}
+
+	changed := false
+
+	if string(tmpl.Settings) != state.Settings {
+		localSettings, err := readOptionalFile(files.settings)
+		if err != nil {
+			return err
+		}
+		merged, overrides, err := configtemplate.MergeSettings([]byte(state.Settings), localSettings, tmpl.Settings)
+		if err != nil {
+			return err //nolint:wrapcheck // already descriptive
+		}
+		if !bytes.Equal(merged, localSettings) {
+			if err := writeTemplateFile(files.settings, merged); err != nil {
+				return err
+			}
+			fmt.Fprintf(w, "Updated %s\n", EntireSettingsFile)
+			changed = true
+		}
+		if len(overrides) > 0 {
+			fmt.Fprintf(w, "Kept local overrides in %s: %s\n", EntireSettingsFile, strings.Join(overrides, ", "))
+		}
+	}
+
+	localPolicy, err := readOptionalFile(files.policy)
+	if err != nil {
+		return err
+	}
+	switch {
+	case string(tmpl.Policy) == state.Policy, bytes.Equal(localPolicy, tmpl.Policy):
+		// Template policy unchanged, or already matched locally
+	case string(localPolicy) != state.Policy:
+		fmt.Fprintf(w, "Kept %s: it has local changes and the template changed it too\n", policy.PolicyFile)
+	case tmpl.Policy == nil:
+		if err := os.Remove(files.policy); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("removing %s: %w", policy.PolicyFile, err)
+		}
+		fmt.Fprintf(w, "Removed %s\n", policy.PolicyFile)
+		changed = true
+	default:
+		if err := writeTemplateFile(files.policy, tmpl.Policy); err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "Updated %s\n", policy.PolicyFile)
+		changed = true
+	}
+
+	state.UpdatedAt = time.Now().UTC()
+	state.Settings = string(tmpl.Settings)
+	state.Policy = string(tmpl.Policy)
+	if err := configtemplate.SaveState(files.state, state); err != nil {
+		return err //nolint:wrapcheck // already descriptive
+	}
+
+	if !changed {
+		fmt.Fprintf(w, "Already up to date with %s\n", state.Source)
+	}
+	return nil
+}
+
+// readOptionalFile returns the content of path, or nil if it doesn't exist.
+func readOptionalFile(path string) ([]byte, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // path is derived from repo root
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading %s: %w", filepath.Base(path), err)
+	}
+	return data, nil
+}
+
+// writeTemplateFile writes a config file managed by the template.
+func writeTemplateFile(path string, data []byte) error {
+	//nolint:gosec // G306: config files, not secrets; 0o644 is appropriate
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("writing %s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
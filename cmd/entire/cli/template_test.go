@@ -0,0 +1,83 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunInitFromTemplate_ThenUpdate(t *testing.T) {
+	setupCleanTestRepo(t)
+	ctx := context.Background()
+
+	templateDir := t.TempDir()
+	writeTemplate := func(settingsJSON, policyJSON string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(templateDir, "settings.json"), []byte(settingsJSON), 0o644); err != nil {
+			t.Fatalf("failed to write template settings: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(templateDir, "policy.json"), []byte(policyJSON), 0o644); err != nil {
+			t.Fatalf("failed to write template policy: %v", err)
+		}
+	}
+	writeTemplate(`{"enabled": true, "log_level": "info", "auto_stash": false}`, `{"environments": {"ci": {"disabled_commands": ["reset"]}}}`)
+
+	var stdout bytes.Buffer
+	if err := runInitFromTemplate(ctx, &stdout, templateDir, false); err != nil {
+		t.Fatalf("runInitFromTemplate() error = %v", err)
+	}
+	s, err := LoadEntireSettings(ctx)
+	if err != nil {
+		t.Fatalf("LoadEntireSettings() error = %v", err)
+	}
+	if s.LogLevel != "info" {
+		t.Errorf("LogLevel = %q, want info from template", s.LogLevel)
+	}
+	if err := runInitFromTemplate(ctx, &stdout, templateDir, false); err == nil {
+		t.Error("second init without --force should fail")
+	}
+
+	// The repository overrides log_level; the template then changes log_level and auto_stash
+	if err := os.WriteFile(EntireSettingsFile, []byte(`{"enabled": true, "log_level": "debug", "auto_stash": false}`), 0o644); err != nil {
+		t.Fatalf("failed to write settings: %v", err)
+	}
+	writeTemplate(`{"enabled": true, "log_level": "warn", "auto_stash": true}`, `{"environments": {"ci": {"disabled_commands": ["reset", "clean"]}}}`)
+
+	stdout.Reset()
+	if err := runTemplateUpdate(ctx, &stdout); err != nil {
+		t.Fatalf("runTemplateUpdate() error = %v", err)
+	}
+	if !strings.Contains(stdout.String(), "Kept local overrides in .entire/settings.json: log_level") {
+		t.Errorf("expected override report, got:\n%s", stdout.String())
+	}
+	s, err = LoadEntireSettings(ctx)
+	if err != nil {
+		t.Fatalf("LoadEntireSettings() error = %v", err)
+	}
+	if s.LogLevel != "debug" || !s.AutoStash {
+		t.Errorf("after update LogLevel = %q, AutoStash = %v; want debug (local override), true (template)", s.LogLevel, s.AutoStash)
+	}
+	policyData, err := os.ReadFile(".entire/policy.json")
+	if err != nil || !strings.Contains(string(policyData), "clean") {
+		t.Errorf("policy.json = %q, %v; want template update applied", policyData, err)
+	}
+
+	stdout.Reset()
+	if err := runTemplateUpdate(ctx, &stdout); err != nil {
+		t.Fatalf("runTemplateUpdate() error = %v", err)
+	}
+	if !strings.Contains(stdout.String(), "Already up to date") {
+		t.Errorf("expected no changes on second update, got:\n%s", stdout.String())
+	}
+}
+
+func TestRunTemplateUpdate_NotInitialized(t *testing.T) {
+	setupCleanTestRepo(t)
+
+	if err := runTemplateUpdate(context.Background(), &bytes.Buffer{}); err == nil || !strings.Contains(err.Error(), "entire init --from-template") {
+		t.Errorf("runTemplateUpdate() error = %v, want hint to init from a template", err)
+	}
+}
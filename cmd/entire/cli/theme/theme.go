@@ -0,0 +1,189 @@
+// Package theme holds the colors the CLI uses for terminal output and
+// interactive forms, and decides whether output is colored at all.
+package theme
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+	"golang.org/x/term"
+)
+
+// ColorMode is the value of the --color flag.
+type ColorMode string
+
+const (
+	// ColorAuto colors output written to a terminal unless NO_COLOR is set.
+	ColorAuto ColorMode = "auto"
+	// ColorAlways colors all output, even when NO_COLOR is set.
+	ColorAlways ColorMode = "always"
+	// ColorNever never colors output.
+	ColorNever ColorMode = "never"
+)
+
+// ParseColorMode parses a --color flag value.
+func ParseColorMode(s string) (ColorMode, error) {
+	switch mode := ColorMode(s); mode {
+	case ColorAuto, ColorAlways, ColorNever:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("invalid color mode %q: must be %q, %q, or %q", s, ColorAuto, ColorAlways, ColorNever)
+	}
+}
+
+// Palette names, as used by the "theme" setting.
+const (
+	DefaultName      = "default"
+	HighContrastName = "high-contrast"
+)
+
+// Palette maps the roles colors play in the CLI to terminal colors.
+type Palette struct {
+	Name string
+
+	Success  lipgloss.TerminalColor // active, passed, selected
+	Failure  lipgloss.TerminalColor // inactive, failed, errors
+	Muted    lipgloss.TerminalColor // secondary text
+	Info     lipgloss.TerminalColor // branch names and other identifiers
+	Agent    lipgloss.TerminalColor // agent names
+	Title    lipgloss.TerminalColor // form titles and focused buttons
+	Selector lipgloss.TerminalColor // form cursors and selection markers
+}
+
+// Default is the palette used unless the "theme" setting selects another.
+var Default = Palette{
+	Name:     DefaultName,
+	Success:  lipgloss.Color("2"),
+	Failure:  lipgloss.Color("1"),
+	Muted:    lipgloss.Color("8"),
+	Info:     lipgloss.Color("6"),
+	Agent:    lipgloss.Color("214"),
+	Title:    lipgloss.AdaptiveColor{Light: "#7c4dcc", Dark: "#bd93f9"},
+	Selector: lipgloss.AdaptiveColor{Light: "#8a6d00", Dark: "#f1fa8c"},
+}
+
+// HighContrast avoids telling states apart by red and green alone: success
+// is blue and failure orange, which stay distinct under the common forms of
+// color blindness, and secondary text stays readable on dark backgrounds.
+var HighContrast = Palette{
+	Name:     HighContrastName,
+	Success:  lipgloss.Color("33"),
+	Failure:  lipgloss.Color("208"),
+	Muted:    lipgloss.Color("250"),
+	Info:     lipgloss.Color("51"),
+	Agent:    lipgloss.Color("226"),
+	Title:    lipgloss.AdaptiveColor{Light: "0", Dark: "15"},
+	Selector: lipgloss.Color("226"),
+}
+
+// PaletteNamed returns the palette called name. An empty name is Default.
+func PaletteNamed(name string) (Palette, error) {
+	switch name {
+	case "", DefaultName:
+		return Default, nil
+	case HighContrastName:
+		return HighContrast, nil
+	default:
+		return Palette{}, fmt.Errorf("unknown theme %q: must be %q or %q", name, DefaultName, HighContrastName)
+	}
+}
+
+var (
+	mu      sync.RWMutex
+	mode    = ColorAuto
+	current = Default
+)
+
+// SetColorMode sets whether output is colored, for the rest of the process.
+func SetColorMode(m ColorMode) {
+	mu.Lock()
+	defer mu.Unlock()
+	mode = m
+
+	// lipgloss detects color support on its own; align it with the flag
+	switch m {
+	case ColorAlways:
+		lipgloss.SetColorProfile(termenv.ANSI256)
+	case ColorNever:
+		lipgloss.SetColorProfile(termenv.Ascii)
+	case ColorAuto:
+	}
+}
+
+// SetPalette selects the palette for the rest of the process.
+func SetPalette(p Palette) {
+	mu.Lock()
+	defer mu.Unlock()
+	current = p
+}
+
+// Current returns the selected palette.
+func Current() Palette {
+	mu.RLock()
+	defer mu.RUnlock()
+	return current
+}
+
+// Enabled reports whether output written to w should be colored.
+func Enabled(w io.Writer) bool {
+	mu.RLock()
+	m := mode
+	mu.RUnlock()
+
+	switch m {
+	case ColorAlways:
+		return true
+	case ColorNever:
+		return false
+	case ColorAuto:
+	}
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	if f, ok := w.(*os.File); ok {
+		return term.IsTerminal(int(f.Fd())) //nolint:gosec // G115: uintptr->int is safe for fd
+	}
+	return false
+}
+
+// Huh returns the form theme for the selected palette.
+func Huh() *huh.Theme {
+	p := Current()
+	t := huh.ThemeBase()
+
+	t.Focused.Base = t.Focused.Base.BorderForeground(p.Muted)
+	t.Focused.Card = t.Focused.Base
+	t.Focused.Title = t.Focused.Title.Foreground(p.Title).Bold(true)
+	t.Focused.NoteTitle = t.Focused.NoteTitle.Foreground(p.Title).Bold(true)
+	t.Focused.Description = t.Focused.Description.Foreground(p.Muted)
+	t.Focused.Directory = t.Focused.Directory.Foreground(p.Info)
+	t.Focused.ErrorIndicator = t.Focused.ErrorIndicator.Foreground(p.Failure)
+	t.Focused.ErrorMessage = t.Focused.ErrorMessage.Foreground(p.Failure)
+	t.Focused.SelectSelector = t.Focused.SelectSelector.Foreground(p.Selector)
+	t.Focused.NextIndicator = t.Focused.NextIndicator.Foreground(p.Selector)
+	t.Focused.PrevIndicator = t.Focused.PrevIndicator.Foreground(p.Selector)
+	t.Focused.MultiSelectSelector = t.Focused.MultiSelectSelector.Foreground(p.Selector)
+	t.Focused.SelectedOption = t.Focused.SelectedOption.Foreground(p.Success)
+	t.Focused.SelectedPrefix = t.Focused.SelectedPrefix.Foreground(p.Success)
+	t.Focused.UnselectedPrefix = t.Focused.UnselectedPrefix.Foreground(p.Muted)
+	t.Focused.FocusedButton = t.Focused.FocusedButton.Foreground(lipgloss.Color("0")).Background(p.Title).Bold(true)
+	t.Focused.Next = t.Focused.FocusedButton
+	t.Focused.TextInput.Cursor = t.Focused.TextInput.Cursor.Foreground(p.Selector)
+	t.Focused.TextInput.Placeholder = t.Focused.TextInput.Placeholder.Foreground(p.Muted)
+	t.Focused.TextInput.Prompt = t.Focused.TextInput.Prompt.Foreground(p.Selector)
+
+	t.Blurred = t.Focused
+	t.Blurred.Base = t.Blurred.Base.BorderStyle(lipgloss.HiddenBorder())
+	t.Blurred.Card = t.Blurred.Base
+	t.Blurred.NextIndicator = lipgloss.NewStyle()
+	t.Blurred.PrevIndicator = lipgloss.NewStyle()
+
+	t.Group.Title = t.Focused.Title
+	t.Group.Description = t.Focused.Description
+	return t
+}
@@ -0,0 +1,81 @@
+package theme
+
+import (
+	"bytes"
+	"os"
+	"testing"
+)
+
+func TestParseColorMode(t *testing.T) {
+	t.Parallel()
+
+	for _, s := range []string{"auto", "always", "never"} {
+		if mode, err := ParseColorMode(s); err != nil || string(mode) != s {
+			t.Errorf("ParseColorMode(%q) = %q, %v", s, mode, err)
+		}
+	}
+	if _, err := ParseColorMode("sometimes"); err == nil {
+		t.Error("ParseColorMode(sometimes) should fail")
+	}
+}
+
+func TestPaletteNamed(t *testing.T) {
+	t.Parallel()
+
+	for name, want := range map[string]string{"": DefaultName, DefaultName: DefaultName, HighContrastName: HighContrastName} {
+		p, err := PaletteNamed(name)
+		if err != nil || p.Name != want {
+			t.Errorf("PaletteNamed(%q) = %q, %v; want %q", name, p.Name, err, want)
+		}
+	}
+	if _, err := PaletteNamed("dracula"); err == nil {
+		t.Error("PaletteNamed(dracula) should fail")
+	}
+}
+
+// Tests below change the process-wide color mode, so they don't run in parallel.
+
+func TestEnabled_Modes(t *testing.T) {
+	t.Cleanup(func() { SetColorMode(ColorAuto) })
+	t.Setenv("NO_COLOR", "")
+
+	var buf bytes.Buffer
+	f, err := os.CreateTemp(t.TempDir(), "out")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	SetColorMode(ColorAuto)
+	if Enabled(&buf) || Enabled(f) {
+		t.Error("auto should not color output that isn't a terminal")
+	}
+
+	SetColorMode(ColorAlways)
+	if !Enabled(&buf) {
+		t.Error("always should color any output")
+	}
+	t.Setenv("NO_COLOR", "1")
+	if !Enabled(&buf) {
+		t.Error("always should override NO_COLOR")
+	}
+
+	SetColorMode(ColorNever)
+	t.Setenv("NO_COLOR", "")
+	if Enabled(&buf) {
+		t.Error("never should not color output")
+	}
+}
+
+func TestHuh_UsesSelectedPalette(t *testing.T) {
+	t.Cleanup(func() { SetPalette(Default) })
+
+	SetPalette(HighContrast)
+	if got := Huh().Focused.SelectedOption.GetForeground(); got != HighContrast.Success {
+		t.Errorf("SelectedOption foreground = %v, want %v", got, HighContrast.Success)
+	}
+	SetPalette(Default)
+	if got := Huh().Focused.ErrorMessage.GetForeground(); got != Default.Failure {
+		t.Errorf("ErrorMessage foreground = %v, want %v", got, Default.Failure)
+	}
+}
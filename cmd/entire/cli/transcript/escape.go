@@ -0,0 +1,104 @@
+package transcript
+
+import (
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Escape heuristics.
+const (
+	EscapeOutsideWrite = "outside_write"
+	EscapeSudo         = "sudo"
+	EscapeNetwork      = "network"
+)
+
+var (
+	// sudoPattern matches sudo as a command, not as part of a word or path.
+	sudoPattern = regexp.MustCompile(`(^|[\s;&|(` + "`" + `])sudo(\s|$)`)
+
+	// urlPattern matches URLs in shell commands; the first group is the host.
+	urlPattern = regexp.MustCompile(`[a-zA-Z][a-zA-Z0-9+.-]*://(?:[^@/\s]*@)?([^/\s:'"?#]+)`)
+
+	// scpPattern matches user@host: remotes of ssh, scp, rsync, and git.
+	scpPattern = regexp.MustCompile(`[\w.-]+@([\w-]+(?:\.[\w-]+)+):`)
+)
+
+// loopbackHosts are always known.
+var loopbackHosts = []string{"localhost", "127.0.0.1", "::1", "[::1]"}
+
+// EscapeChecks selects the heuristics DetectEscapes applies.
+type EscapeChecks struct {
+	// OutsideWrites flags writes to files outside the repository.
+	OutsideWrites bool
+
+	// Sudo flags shell commands run with sudo.
+	Sudo bool
+
+	// Network flags fetches and shell commands reaching hosts other than
+	// AllowedHosts, their subdomains, and loopback.
+	Network      bool
+	AllowedHosts []string
+}
+
+// EscapeFlag is a tool call that suggests the agent acted outside its sandbox.
+type EscapeFlag struct {
+	Heuristic string `json:"heuristic"` // EscapeOutsideWrite, EscapeSudo, or EscapeNetwork
+	Detail    string `json:"detail"`    // the path, command, or host involved
+}
+
+// DetectEscapes applies checks to the tool calls that ran. Denied calls are
+// left out: they didn't reach outside the sandbox.
+func DetectEscapes(permissions []ToolPermission, checks EscapeChecks) []EscapeFlag {
+	var flags []EscapeFlag
+	for _, p := range permissions {
+		if p.Decision != PermissionAllowed {
+			continue
+		}
+		if checks.OutsideWrites && p.OutsideRepo {
+			flags = append(flags, EscapeFlag{Heuristic: EscapeOutsideWrite, Detail: p.Path})
+		}
+		if checks.Sudo && sudoPattern.MatchString(p.Command) {
+			flags = append(flags, EscapeFlag{Heuristic: EscapeSudo, Detail: p.Command})
+		}
+		if checks.Network {
+			for _, host := range callHosts(p) {
+				if !knownHost(host, checks.AllowedHosts) {
+					flags = append(flags, EscapeFlag{Heuristic: EscapeNetwork, Detail: host})
+				}
+			}
+		}
+	}
+	return flags
+}
+
+// callHosts returns the hosts a tool call reaches: its URL's, or those named
+// in its shell command.
+func callHosts(p ToolPermission) []string {
+	if p.URL != "" {
+		if u, err := url.Parse(p.URL); err == nil && u.Hostname() != "" {
+			return []string{strings.ToLower(u.Hostname())}
+		}
+		return nil
+	}
+	var hosts []string
+	for _, pattern := range []*regexp.Regexp{urlPattern, scpPattern} {
+		for _, match := range pattern.FindAllStringSubmatch(p.Command, -1) {
+			hosts = append(hosts, strings.ToLower(match[1]))
+		}
+	}
+	return hosts
+}
+
+// knownHost reports whether host is loopback, an allowed host, or a
+// subdomain of one.
+func knownHost(host string, allowed []string) bool {
+	for _, known := range slices.Concat(allowed, loopbackHosts) {
+		known = strings.ToLower(known)
+		if host == known || strings.HasSuffix(host, "."+known) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,41 @@
+package transcript
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestDetectEscapes(t *testing.T) {
+	t.Parallel()
+
+	permissions := []ToolPermission{
+		{Tool: "Bash", Decision: PermissionAllowed, Command: "go test ./..."},
+		{Tool: "Bash", Decision: PermissionAllowed, Command: "cd /tmp && sudo apt-get install jq"},
+		{Tool: "Bash", Decision: PermissionAllowed, Command: "./scripts/pseudo-sudo.sh"},
+		{Tool: "Bash", Decision: PermissionDenied, Command: "sudo rm -rf /"},
+		{Tool: "Write", Decision: PermissionAllowed, Path: "/etc/hosts", OutsideRepo: true},
+		{Tool: "Bash", Decision: PermissionAllowed, Command: "curl -s https://api.github.com/repos && curl http://localhost:8080/health"},
+		{Tool: "Bash", Decision: PermissionAllowed, Command: "scp build.tar deploy@prod.example.com:/srv"},
+		{Tool: "WebFetch", Decision: PermissionAllowed, URL: "https://Evil.example.net/payload"},
+		{Tool: "WebFetch", Decision: PermissionDenied, URL: "https://denied.example.org"},
+	}
+
+	got := DetectEscapes(permissions, EscapeChecks{OutsideWrites: true, Sudo: true, Network: true, AllowedHosts: []string{"github.com"}})
+	want := []EscapeFlag{
+		{Heuristic: EscapeSudo, Detail: "cd /tmp && sudo apt-get install jq"},
+		{Heuristic: EscapeOutsideWrite, Detail: "/etc/hosts"},
+		{Heuristic: EscapeNetwork, Detail: "prod.example.com"},
+		{Heuristic: EscapeNetwork, Detail: "evil.example.net"},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("DetectEscapes() =\n%+v\nwant\n%+v", got, want)
+	}
+
+	// Heuristics are off unless selected
+	if got := DetectEscapes(permissions, EscapeChecks{}); len(got) != 0 {
+		t.Errorf("DetectEscapes() with no checks = %+v, want none", got)
+	}
+	if got := DetectEscapes(permissions, EscapeChecks{Sudo: true}); len(got) != 1 || got[0].Heuristic != EscapeSudo {
+		t.Errorf("DetectEscapes() with sudo = %+v, want the sudo command", got)
+	}
+}
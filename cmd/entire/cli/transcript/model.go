@@ -0,0 +1,43 @@
+package transcript
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+)
+
+// syntheticModel is the model name Claude Code writes on messages it generates
+// itself (e.g. interruption notices) rather than receiving from the API.
+const syntheticModel = "<synthetic>"
+
+// modelLine holds the fields needed to find the model and client version.
+type modelLine struct {
+	Type    string `json:"type"`
+	Version string `json:"version"`
+	Message struct {
+		Model string `json:"model"`
+	} `json:"message"`
+}
+
+// ModelInfo returns the model of the last assistant message in a JSONL
+// transcript and the agent client version recorded alongside it.
+// Either value is empty if the transcript doesn't carry it.
+func ModelInfo(content []byte) (model, agentVersion string) {
+	reader := bufio.NewReader(bytes.NewReader(content))
+	for {
+		lineBytes, err := reader.ReadBytes('\n')
+		var line modelLine
+		if len(lineBytes) > 0 && json.Unmarshal(lineBytes, &line) == nil {
+			if line.Version != "" {
+				agentVersion = line.Version
+			}
+			if line.Type == TypeAssistant && line.Message.Model != "" && line.Message.Model != syntheticModel {
+				model = line.Message.Model
+			}
+		}
+		if err != nil {
+			break
+		}
+	}
+	return model, agentVersion
+}
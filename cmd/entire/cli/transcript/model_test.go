@@ -0,0 +1,23 @@
+package transcript
+
+import "testing"
+
+func TestModelInfo(t *testing.T) {
+	t.Parallel()
+
+	content := []byte(`{"type":"user","version":"2.0.1","message":{"content":"hi"}}
+{"type":"assistant","version":"2.0.1","message":{"model":"claude-sonnet-4-5-20250929"}}
+{"type":"assistant","version":"2.0.2","message":{"model":"<synthetic>"}}
+`)
+	model, version := ModelInfo(content)
+	if model != "claude-sonnet-4-5-20250929" {
+		t.Errorf("model = %q, want claude-sonnet-4-5-20250929", model)
+	}
+	if version != "2.0.2" {
+		t.Errorf("agentVersion = %q, want 2.0.2", version)
+	}
+
+	if model, version := ModelInfo([]byte("not json\n")); model != "" || version != "" {
+		t.Errorf("ModelInfo(invalid) = %q, %q; want empty", model, version)
+	}
+}
@@ -0,0 +1,159 @@
+package transcript
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+)
+
+// Decisions on a tool call.
+const (
+	PermissionAllowed = "allowed"
+	PermissionDenied  = "denied"
+)
+
+// deniedResultPhrases appear in the tool result of a tool call the user
+// rejected, that wasn't granted permission, or that a hook blocked.
+var deniedResultPhrases = []string{
+	"doesn't want to proceed with this tool use",
+	"tool use was rejected",
+	"haven't granted it yet",
+	"denied this tool",
+}
+
+// fileWriteTools are the tools that write the file at their path input.
+var fileWriteTools = map[string]bool{
+	"Write":        true,
+	"Edit":         true,
+	"MultiEdit":    true,
+	"NotebookEdit": true,
+}
+
+// ToolPermission is a security-relevant tool call and whether it ran: a
+// shell command, a web fetch, a file write outside the repository, or any
+// call that was denied.
+type ToolPermission struct {
+	Tool     string `json:"tool"`
+	Decision string `json:"decision"` // PermissionAllowed or PermissionDenied
+
+	// Command is the shell command of a Bash call
+	Command string `json:"command,omitempty"`
+
+	// Path is the file a write tool targeted
+	Path string `json:"path,omitempty"`
+
+	// URL is the address of a web fetch
+	URL string `json:"url,omitempty"`
+
+	// OutsideRepo is true for writes to a path outside the repository
+	OutsideRepo bool `json:"outside_repo,omitempty"`
+}
+
+// permissionLine holds the fields needed to pair tool calls with their results.
+type permissionLine struct {
+	Message struct {
+		Content []struct {
+			Type      string          `json:"type"`
+			ID        string          `json:"id"`
+			Name      string          `json:"name"`
+			Input     ToolInput       `json:"input"`
+			ToolUseID string          `json:"tool_use_id"`
+			IsError   bool            `json:"is_error"`
+			Content   json.RawMessage `json:"content"`
+		} `json:"content"`
+	} `json:"message"`
+}
+
+// ToolPermissions returns the security-relevant tool calls of a JSONL
+// transcript in call order: every shell command and web fetch, writes to
+// files outside repoRoot, and every denied call. A call counts as allowed once its result
+// is in the transcript and doesn't say it was denied; calls still waiting
+// for a result are left out.
+func ToolPermissions(content []byte, repoRoot string) []ToolPermission {
+	var calls []ToolPermission
+	pending := make(map[string]int) // tool use ID -> index in calls
+	reader := bufio.NewReader(bytes.NewReader(content))
+	for {
+		lineBytes, err := reader.ReadBytes('\n')
+		var line permissionLine
+		if len(lineBytes) > 0 && json.Unmarshal(lineBytes, &line) == nil {
+			for _, block := range line.Message.Content {
+				switch block.Type {
+				case ContentTypeToolUse:
+					call := ToolPermission{Tool: block.Name, Command: block.Input.Command, URL: block.Input.URL}
+					if fileWriteTools[block.Name] {
+						call.Path = block.Input.FilePath
+						if call.Path == "" {
+							call.Path = block.Input.NotebookPath
+						}
+						call.OutsideRepo = outsideRepo(call.Path, repoRoot)
+					}
+					pending[block.ID] = len(calls)
+					calls = append(calls, call)
+				case ContentTypeToolResult:
+					i, ok := pending[block.ToolUseID]
+					if !ok {
+						continue
+					}
+					delete(pending, block.ToolUseID)
+					calls[i].Decision = PermissionAllowed
+					if block.IsError && isDeniedResult(block.Content) {
+						calls[i].Decision = PermissionDenied
+					}
+				}
+			}
+		}
+		if err != nil {
+			break
+		}
+	}
+
+	var permissions []ToolPermission
+	for _, call := range calls {
+		switch {
+		case call.Decision == "":
+			continue
+		case call.Decision == PermissionDenied, call.Command != "", call.URL != "", call.OutsideRepo:
+			permissions = append(permissions, call)
+		}
+	}
+	return permissions
+}
+
+// isDeniedResult reports whether a tool result's content, a string or
+// text blocks, says the call was denied.
+func isDeniedResult(content json.RawMessage) bool {
+	var text string
+	if json.Unmarshal(content, &text) != nil {
+		var blocks []ContentBlock
+		if json.Unmarshal(content, &blocks) != nil {
+			return false
+		}
+		parts := make([]string, 0, len(blocks))
+		for _, block := range blocks {
+			parts = append(parts, block.Text)
+		}
+		text = strings.Join(parts, "\n")
+	}
+	for _, phrase := range deniedResultPhrases {
+		if strings.Contains(text, phrase) {
+			return true
+		}
+	}
+	return false
+}
+
+// outsideRepo reports whether path, absolute or relative to repoRoot, is
+// outside repoRoot. Without a repoRoot nothing is outside.
+func outsideRepo(path, repoRoot string) bool {
+	if path == "" || repoRoot == "" {
+		return false
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(repoRoot, path)
+	}
+	rel, err := filepath.Rel(repoRoot, path)
+	return err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
@@ -0,0 +1,41 @@
+package transcript
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestToolPermissions(t *testing.T) {
+	t.Parallel()
+
+	content := []byte(`{"type":"assistant","message":{"content":[{"type":"tool_use","id":"t1","name":"Bash","input":{"command":"go test ./..."}}]}}
+{"type":"user","message":{"content":[{"type":"tool_result","tool_use_id":"t1","is_error":true,"content":"FAIL"}]}}
+{"type":"assistant","message":{"content":[{"type":"tool_use","id":"t2","name":"Bash","input":{"command":"rm -rf /tmp/build"}}]}}
+{"type":"user","message":{"content":[{"type":"tool_result","tool_use_id":"t2","is_error":true,"content":"The user doesn't want to proceed with this tool use. The tool use was rejected."}]}}
+{"type":"assistant","message":{"content":[{"type":"tool_use","id":"t3","name":"Write","input":{"file_path":"/repo/main.go","content":"package main"}},{"type":"tool_use","id":"t4","name":"Edit","input":{"file_path":"/home/me/.bashrc"}}]}}
+{"type":"user","message":{"content":[{"type":"tool_result","tool_use_id":"t3","content":"ok"},{"type":"tool_result","tool_use_id":"t4","content":[{"type":"text","text":"ok"}]}]}}
+{"type":"assistant","message":{"content":[{"type":"tool_use","id":"t5","name":"WebFetch","input":{"url":"https://example.com"}}]}}
+{"type":"user","message":{"content":[{"type":"tool_result","tool_use_id":"t5","is_error":true,"content":[{"type":"text","text":"Claude requested permissions to use WebFetch, but you haven't granted it yet."}]}]}}
+{"type":"assistant","message":{"content":[{"type":"tool_use","id":"t6","name":"Write","input":{"file_path":"../sibling/notes.md"}}]}}
+{"type":"user","message":{"content":[{"type":"tool_result","tool_use_id":"t6","content":"ok"}]}}
+{"type":"assistant","message":{"content":[{"type":"tool_use","id":"t7","name":"WebFetch","input":{"url":"https://go.dev/doc"}}]}}
+{"type":"user","message":{"content":[{"type":"tool_result","tool_use_id":"t7","content":"ok"}]}}
+{"type":"assistant","message":{"content":[{"type":"tool_use","id":"t8","name":"Bash","input":{"command":"make deploy"}}]}}
+`)
+	want := []ToolPermission{
+		{Tool: "Bash", Decision: PermissionAllowed, Command: "go test ./..."},
+		{Tool: "Bash", Decision: PermissionDenied, Command: "rm -rf /tmp/build"},
+		{Tool: "Edit", Decision: PermissionAllowed, Path: "/home/me/.bashrc", OutsideRepo: true},
+		{Tool: "WebFetch", Decision: PermissionDenied, URL: "https://example.com"},
+		{Tool: "Write", Decision: PermissionAllowed, Path: "../sibling/notes.md", OutsideRepo: true},
+		{Tool: "WebFetch", Decision: PermissionAllowed, URL: "https://go.dev/doc"},
+	}
+	if got := ToolPermissions(content, "/repo"); !slices.Equal(got, want) {
+		t.Errorf("ToolPermissions() =\n%+v\nwant\n%+v", got, want)
+	}
+
+	// Without a repository root no write is outside it
+	if got := ToolPermissions(content, ""); len(got) != 4 {
+		t.Errorf("ToolPermissions() without a root = %+v, want the commands, fetches, and denied calls", got)
+	}
+}
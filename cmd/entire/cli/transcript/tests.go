@@ -0,0 +1,58 @@
+package transcript
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"regexp"
+)
+
+// testCommandPattern matches shell commands that run a project's test suite.
+var testCommandPattern = regexp.MustCompile(`(^|[\s;&|(])(go test|(npm|pnpm|yarn|bun)( run)? test|npx (jest|vitest)|pytest|cargo test|make test|mise run test|mvn test|gradle test|\./gradlew test|rspec|jest|vitest)\b`)
+
+// toolCallLine holds the fields needed to pair tool calls with their results.
+type toolCallLine struct {
+	Type    string `json:"type"`
+	Message struct {
+		Content []struct {
+			Type      string `json:"type"`
+			ID        string `json:"id"`
+			ToolUseID string `json:"tool_use_id"`
+			IsError   bool   `json:"is_error"`
+			Input     struct {
+				Command string `json:"command"`
+			} `json:"input"`
+		} `json:"content"`
+	} `json:"message"`
+}
+
+// TestRuns counts test suite runs in a JSONL transcript: shell tool calls whose
+// command runs a known test runner, split by whether the tool reported an error.
+// Runs without a result in the transcript aren't counted.
+func TestRuns(content []byte) (passed, failed int) {
+	testCalls := make(map[string]bool)
+	reader := bufio.NewReader(bytes.NewReader(content))
+	for {
+		lineBytes, err := reader.ReadBytes('\n')
+		var line toolCallLine
+		if len(lineBytes) > 0 && json.Unmarshal(lineBytes, &line) == nil {
+			for _, block := range line.Message.Content {
+				switch {
+				case block.Type == ContentTypeToolUse && testCommandPattern.MatchString(block.Input.Command):
+					testCalls[block.ID] = true
+				case block.Type == ContentTypeToolResult && testCalls[block.ToolUseID]:
+					delete(testCalls, block.ToolUseID)
+					if block.IsError {
+						failed++
+					} else {
+						passed++
+					}
+				}
+			}
+		}
+		if err != nil {
+			break
+		}
+	}
+	return passed, failed
+}
@@ -0,0 +1,20 @@
+package transcript
+
+import "testing"
+
+func TestTestRuns(t *testing.T) {
+	t.Parallel()
+
+	content := []byte(`{"type":"assistant","message":{"content":[{"type":"tool_use","id":"t1","name":"Bash","input":{"command":"go test ./..."}}]}}
+{"type":"user","message":{"content":[{"type":"tool_result","tool_use_id":"t1","is_error":true,"content":"FAIL"}]}}
+{"type":"assistant","message":{"content":[{"type":"tool_use","id":"t2","name":"Bash","input":{"command":"ls -la"}}]}}
+{"type":"user","message":{"content":[{"type":"tool_result","tool_use_id":"t2","content":"ok"}]}}
+{"type":"assistant","message":{"content":[{"type":"tool_use","id":"t3","name":"Bash","input":{"command":"cd web && npm run test"}}]}}
+{"type":"user","message":{"content":[{"type":"tool_result","tool_use_id":"t3","content":"PASS"}]}}
+{"type":"assistant","message":{"content":[{"type":"tool_use","id":"t4","name":"Bash","input":{"command":"pytest -q"}}]}}
+`)
+	passed, failed := TestRuns(content)
+	if passed != 1 || failed != 1 {
+		t.Errorf("TestRuns() = %d passed, %d failed; want 1, 1", passed, failed)
+	}
+}
@@ -0,0 +1,75 @@
+package transcript
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"time"
+)
+
+// timedLine holds the fields needed to attribute time between transcript lines.
+type timedLine struct {
+	Type      string          `json:"type"`
+	Timestamp time.Time       `json:"timestamp"`
+	Message   json.RawMessage `json:"message"`
+}
+
+// Timing splits the time covered by a JSONL transcript into time spent running
+// tools and time spent waiting on model output, using per-line timestamps.
+// Transcripts without timestamps (or in other formats) yield zero for both.
+//
+// The gap before an assistant line counts as model time. The gap before a user
+// line carrying tool results counts as tool time. The gap before a user prompt
+// is idle time and isn't counted.
+func Timing(content []byte) (toolTime, modelTime time.Duration) {
+	var prev time.Time
+	reader := bufio.NewReader(bytes.NewReader(content))
+	for {
+		lineBytes, err := reader.ReadBytes('\n')
+		line, ok := parseTimedLine(lineBytes)
+		if ok && !prev.IsZero() {
+			if gap := line.Timestamp.Sub(prev); gap > 0 {
+				switch {
+				case line.Type == TypeAssistant:
+					modelTime += gap
+				case hasToolResult(line.Message):
+					toolTime += gap
+				}
+			}
+		}
+		if ok {
+			prev = line.Timestamp
+		}
+		if err != nil {
+			break
+		}
+	}
+	return toolTime, modelTime
+}
+
+// parseTimedLine parses a user or assistant line that has a timestamp.
+func parseTimedLine(lineBytes []byte) (timedLine, bool) {
+	var line timedLine
+	if len(lineBytes) == 0 || json.Unmarshal(lineBytes, &line) != nil || line.Timestamp.IsZero() {
+		return line, false
+	}
+	return line, line.Type == TypeUser || line.Type == TypeAssistant
+}
+
+// hasToolResult reports whether a user message contains a tool_result block.
+func hasToolResult(message json.RawMessage) bool {
+	var msg struct {
+		Content []struct {
+			Type string `json:"type"`
+		} `json:"content"`
+	}
+	if err := json.Unmarshal(message, &msg); err != nil {
+		return false
+	}
+	for _, block := range msg.Content {
+		if block.Type == ContentTypeToolResult {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,37 @@
+package transcript
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTiming(t *testing.T) {
+	t.Parallel()
+
+	content := []byte(`{"type":"user","timestamp":"2026-01-01T10:00:00Z","message":{"content":"fix the bug"}}
+{"type":"assistant","timestamp":"2026-01-01T10:00:05Z","message":{"content":[{"type":"tool_use","name":"Bash"}]}}
+{"type":"system","timestamp":"2026-01-01T10:00:06Z"}
+{"type":"user","timestamp":"2026-01-01T10:00:15Z","message":{"content":[{"type":"tool_result","content":"ok"}]}}
+{"type":"assistant","timestamp":"2026-01-01T10:00:18Z","message":{"content":[{"type":"text","text":"Done"}]}}
+{"type":"user","timestamp":"2026-01-01T10:05:00Z","message":{"content":"thanks"}}
+`)
+
+	toolTime, modelTime := Timing(content)
+	if toolTime != 10*time.Second {
+		t.Errorf("toolTime = %v, want 10s", toolTime)
+	}
+	if modelTime != 8*time.Second {
+		t.Errorf("modelTime = %v, want 8s", modelTime)
+	}
+}
+
+func TestTiming_NoTimestamps(t *testing.T) {
+	t.Parallel()
+
+	content := []byte(`{"type":"user","message":{"content":"hi"}}
+{"type":"assistant","message":{"content":[{"type":"text","text":"hello"}]}}`)
+
+	if toolTime, modelTime := Timing(content); toolTime != 0 || modelTime != 0 {
+		t.Errorf("Timing() = %v, %v; want 0, 0", toolTime, modelTime)
+	}
+}
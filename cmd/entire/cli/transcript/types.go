@@ -14,6 +14,9 @@ const (
 const (
 	ContentTypeText    = "text"
 	ContentTypeToolUse = "tool_use"
+
+	// ContentTypeToolResult carries a tool's output back to the model in a user message.
+	ContentTypeToolResult = "tool_result"
 )
 
 // Line represents a single line in a Claude Code or Cursor JSONL transcript.
@@ -5,6 +5,8 @@ import (
 	"os"
 
 	"github.com/charmbracelet/huh"
+	"github.com/entireio/cli/cmd/entire/cli/i18n"
+	"github.com/entireio/cli/cmd/entire/cli/theme"
 )
 
 // IsAccessibleMode returns true if accessibility mode should be enabled.
@@ -15,9 +17,9 @@ func IsAccessibleMode() bool {
 	return os.Getenv("ACCESSIBLE") != ""
 }
 
-// entireTheme returns the Dracula theme for consistent styling.
+// entireTheme returns the form theme for the selected palette.
 func entireTheme() *huh.Theme {
-	return huh.ThemeDracula()
+	return theme.Huh()
 }
 
 // NewAccessibleForm creates a new huh form with accessibility mode
@@ -32,6 +34,14 @@ func NewAccessibleForm(groups ...*huh.Group) *huh.Form {
 	return form
 }
 
+// newConfirm returns a confirmation field whose buttons are in the
+// selected locale.
+func newConfirm() *huh.Confirm {
+	return huh.NewConfirm().
+		Affirmative(i18n.T(i18n.Yes)).
+		Negative(i18n.T(i18n.No))
+}
+
 // fileExists checks if a file exists
 func fileExists(path string) bool {
 	_, err := os.Stat(path)
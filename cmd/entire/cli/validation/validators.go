@@ -60,3 +60,21 @@ func ValidateAgentSessionID(id string) error {
 	}
 	return nil
 }
+
+// maxCorrelationIDLength bounds correlation IDs so they stay readable in listings.
+const maxCorrelationIDLength = 128
+
+// ValidateCorrelationID validates a cross-repo correlation ID.
+// Correlation IDs are user-chosen, so they are restricted to path-safe characters.
+func ValidateCorrelationID(id string) error {
+	if id == "" {
+		return nil // Empty is allowed (session is not linked)
+	}
+	if len(id) > maxCorrelationIDLength {
+		return fmt.Errorf("invalid correlation ID: longer than %d characters", maxCorrelationIDLength)
+	}
+	if !pathSafeRegex.MatchString(id) {
+		return fmt.Errorf("invalid correlation ID %q: must be alphanumeric with underscores/hyphens only", id)
+	}
+	return nil
+}
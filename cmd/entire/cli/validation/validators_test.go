@@ -239,3 +239,27 @@ func TestValidateAgentSessionID(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateCorrelationID(t *testing.T) {
+	tests := []struct {
+		name    string
+		id      string
+		wantErr bool
+	}{
+		{name: "empty allowed", id: "", wantErr: false},
+		{name: "ticket style", id: "PROJ-1234", wantErr: false},
+		{name: "uuid", id: "a1b2c3d4-e5f6-7890-abcd-ef1234567890", wantErr: false},
+		{name: "path traversal", id: "../x", wantErr: true},
+		{name: "space rejected", id: "feature x", wantErr: true},
+		{name: "too long", id: strings.Repeat("a", 129), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateCorrelationID(tt.id)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateCorrelationID(%q) error = %v, wantErr %v", tt.id, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+	"github.com/entireio/cli/cmd/entire/cli/strategy"
+
+	"github.com/spf13/cobra"
+)
+
+func newVerifyCmd() *cobra.Command {
+	var jsonFlag bool
+
+	cmd := &cobra.Command{
+		Use:   "verify",
+		Short: "Check checkpoints for corrupted or missing data",
+		Long: `Check every checkpoint on the ` + paths.MetadataBranchName + ` branch.
+
+Each checkpoint's metadata.json files must be valid JSON with the fields
+Entire relies on, the files they list must be stored, and each transcript
+must match the sha256 recorded in its ` + paths.ContentHashFileName + `.
+
+A checkpoint is reported as incomplete if files are missing, and as
+corrupted if files are unreadable, invalid, or don't match their hash.
+Transcripts that can't be checked, such as ones encrypted to keys you don't
+have or stored outside git, are listed as unverified.
+
+Exits with a non-zero status if any checkpoint is incomplete or corrupted.
+--json prints one result per checkpoint for scripts.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := cmd.Context()
+			if _, err := paths.WorktreeRoot(ctx); err != nil {
+				return paths.ErrNotGitRepository
+			}
+			return runVerify(ctx, cmd.OutOrStdout(), jsonFlag)
+		},
+	}
+
+	cmd.Flags().BoolVar(&jsonFlag, "json", false, "Output results as JSON")
+
+	return cmd
+}
+
+// verifyReport is the --json output of entire verify.
+type verifyReport struct {
+	Checkpoints int                                 `json:"checkpoints"`
+	OK          int                                 `json:"ok"`
+	Incomplete  int                                 `json:"incomplete"`
+	Corrupted   int                                 `json:"corrupted"`
+	Results     []checkpoint.CheckpointVerification `json:"results"`
+}
+
+func runVerify(ctx context.Context, w io.Writer, asJSON bool) error {
+	repo, err := strategy.OpenRepository(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to open repository: %w", err)
+	}
+	store, err := strategy.CommittedStore(ctx, checkpoint.NewGitStore(repo))
+	if err != nil {
+		return err //nolint:wrapcheck // already names the setting
+	}
+	results, err := store.VerifyCommitted(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to verify checkpoints: %w", err)
+	}
+
+	report := verifyReport{Checkpoints: len(results), Results: results}
+	if report.Results == nil {
+		report.Results = []checkpoint.CheckpointVerification{}
+	}
+	unverified := 0
+	for _, r := range results {
+		switch r.Status {
+		case checkpoint.VerifyStatusOK:
+			report.OK++
+		case checkpoint.VerifyStatusIncomplete:
+			report.Incomplete++
+		case checkpoint.VerifyStatusCorrupted:
+			report.Corrupted++
+		}
+		unverified += len(r.Unverified)
+	}
+
+	if asJSON {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal verify results: %w", err)
+		}
+		fmt.Fprintln(w, string(data))
+	} else {
+		for _, r := range results {
+			if r.Status == checkpoint.VerifyStatusOK {
+				continue
+			}
+			fmt.Fprintf(w, "✗ %s %s\n", r.CheckpointID, r.Status)
+			for _, p := range r.Problems {
+				fmt.Fprintf(w, "    %s: %s: %s\n", p.Path, p.Kind, p.Message)
+			}
+		}
+		fmt.Fprintf(w, "Verified %d checkpoint(s): %d ok, %d incomplete, %d corrupted.\n",
+			report.Checkpoints, report.OK, report.Incomplete, report.Corrupted)
+		if unverified > 0 {
+			fmt.Fprintf(w, "%d transcript(s) couldn't be checked (encrypted to other keys, or stored outside git).\n", unverified)
+		}
+	}
+
+	if failed := report.Incomplete + report.Corrupted; failed > 0 {
+		return NewSilentError(fmt.Errorf("%d checkpoint(s) failed verification", failed))
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+)
+
+func TestRunVerify(t *testing.T) {
+	setupExecTestRepo(t)
+	writePurgeTestCheckpoint(t, id.MustCheckpointID("c9c9c9c9c9c9"))
+
+	var out bytes.Buffer
+	if err := runVerify(context.Background(), &out, false); err != nil {
+		t.Fatalf("runVerify() error = %v", err)
+	}
+	if !strings.Contains(out.String(), "Verified 1 checkpoint(s): 1 ok, 0 incomplete, 0 corrupted.") {
+		t.Errorf("runVerify() output = %q", out.String())
+	}
+
+	out.Reset()
+	if err := runVerify(context.Background(), &out, true); err != nil {
+		t.Fatalf("runVerify(json) error = %v", err)
+	}
+	var report verifyReport
+	if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+		t.Fatalf("runVerify(json) output isn't JSON: %v\n%s", err, out.String())
+	}
+	if report.Checkpoints != 1 || report.OK != 1 || len(report.Results) != 1 || report.Results[0].CheckpointID != "c9c9c9c9c9c9" {
+		t.Errorf("runVerify(json) report = %+v", report)
+	}
+}
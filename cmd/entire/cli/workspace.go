@@ -0,0 +1,508 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+	"github.com/entireio/cli/cmd/entire/cli/strategy"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	// workspacesFileName is the registry of workspaces in the git common dir,
+	// shared by all worktrees of the repository.
+	workspacesFileName = "entire-workspaces.json"
+
+	// workspaceBranchPrefix namespaces the branches of workspaces.
+	workspaceBranchPrefix = "workspace/"
+)
+
+// workspaceNamePattern keeps names usable in branch names and directory names.
+var workspaceNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// shortstatPattern parses the summary line of git diff --shortstat.
+var shortstatPattern = regexp.MustCompile(`(\d+) files? changed(?:, (\d+) insertions?\(\+\))?(?:, (\d+) deletions?\(-\))?`)
+
+// workspace is a worktree created by `entire workspace new`.
+type workspace struct {
+	Name      string    `json:"name"`
+	Path      string    `json:"path"`
+	Branch    string    `json:"branch"`
+	Base      string    `json:"base"` // commit the branch started from
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func newWorkspaceCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "workspace",
+		Short: "Run parallel experiments in named worktrees",
+		Long: `A workspace is a git worktree on its own branch, created from the same
+commit as its siblings, for running the same task with different agents or
+prompts side by side. Each workspace is a separate worktree, so its sessions,
+shadow branches, and rewind points are kept apart from the others'.
+
+Workspaces are recorded in the repository's git directory and shared by all
+of its worktrees.`,
+	}
+
+	cmd.AddCommand(newWorkspaceNewCmd())
+	cmd.AddCommand(newWorkspaceListCmd())
+	cmd.AddCommand(newWorkspaceCompareCmd())
+	cmd.AddCommand(newWorkspaceRemoveCmd())
+
+	return cmd
+}
+
+func newWorkspaceNewCmd() *cobra.Command {
+	var fromFlag string
+	var pathFlag string
+
+	cmd := &cobra.Command{
+		Use:   "new <name>",
+		Short: "Create a workspace",
+		Long: `New creates a worktree on the branch workspace/<name>, starting at HEAD or
+--from. The worktree is placed next to the repository as <repo>-<name>
+unless --path is given. Start an agent in it to run an experiment.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			if _, err := paths.WorktreeRoot(ctx); err != nil {
+				return paths.ErrNotGitRepository
+			}
+			return runWorkspaceNew(ctx, cmd.OutOrStdout(), args[0], fromFlag, pathFlag)
+		},
+	}
+
+	cmd.Flags().StringVar(&fromFlag, "from", "HEAD", "Commit or branch the workspace starts from")
+	cmd.Flags().StringVar(&pathFlag, "path", "", "Directory of the worktree (default: <repo>-<name> next to the repository)")
+
+	return cmd
+}
+
+func newWorkspaceListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List workspaces",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := cmd.Context()
+			if _, err := paths.WorktreeRoot(ctx); err != nil {
+				return paths.ErrNotGitRepository
+			}
+			return runWorkspaceList(ctx, cmd.OutOrStdout())
+		},
+	}
+}
+
+func newWorkspaceCompareCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "compare [name...]",
+		Short: "Compare the results of workspaces side by side",
+		Long: `Compare reports workspaces side by side, all of them unless names are
+given. Files and lines are the workspace's diff from the commit it started
+from, including uncommitted changes to tracked files. Agent, model, tests,
+tokens, and cost are summed from the committed checkpoints of the
+workspace's branch, as in 'entire compare-sessions'.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			if _, err := paths.WorktreeRoot(ctx); err != nil {
+				return paths.ErrNotGitRepository
+			}
+			return runWorkspaceCompare(ctx, cmd.OutOrStdout(), args)
+		},
+	}
+}
+
+func newWorkspaceRemoveCmd() *cobra.Command {
+	var forceFlag bool
+
+	cmd := &cobra.Command{
+		Use:   "remove <name>",
+		Short: "Remove a workspace's worktree",
+		Long: `Remove deletes the workspace's worktree and forgets the workspace. Its
+branch and checkpoints are kept. A worktree with uncommitted changes is only
+removed with --force.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			if _, err := paths.WorktreeRoot(ctx); err != nil {
+				return paths.ErrNotGitRepository
+			}
+			return runWorkspaceRemove(ctx, cmd.OutOrStdout(), args[0], forceFlag)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&forceFlag, "force", "f", false, "Remove even with uncommitted changes")
+
+	return cmd
+}
+
+func runWorkspaceNew(ctx context.Context, w io.Writer, name, from, path string) error {
+	ws, err := createWorkspace(ctx, name, from, path)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "Created workspace %s on branch %s at %s\n", ws.Name, ws.Branch, ws.Path)
+	fmt.Fprintf(w, "Start an agent there, then run 'entire workspace compare'.\n")
+	return nil
+}
+
+// createWorkspace adds a worktree on a new workspace branch starting at
+// from, and records it. An empty path places it next to the repository.
+func createWorkspace(ctx context.Context, name, from, path string) (workspace, error) {
+	if !workspaceNamePattern.MatchString(name) {
+		return workspace{}, fmt.Errorf("invalid workspace name %q: use letters, digits, '.', '_', and '-'", name)
+	}
+	workspaces, err := loadWorkspaces(ctx)
+	if err != nil {
+		return workspace{}, err
+	}
+	if slices.ContainsFunc(workspaces, func(ws workspace) bool { return ws.Name == name }) {
+		return workspace{}, fmt.Errorf("workspace %s already exists", name)
+	}
+
+	if strings.HasPrefix(from, "-") {
+		return workspace{}, fmt.Errorf("invalid --from %q", from)
+	}
+	base, err := gitOutput(ctx, "", "rev-parse", "--verify", from+"^{commit}")
+	if err != nil {
+		return workspace{}, fmt.Errorf("unknown --from %q: %w", from, err)
+	}
+
+	if path == "" {
+		commonDir, err := workspaceCommonDir(ctx)
+		if err != nil {
+			return workspace{}, err
+		}
+		mainRoot := filepath.Dir(commonDir)
+		path = filepath.Join(filepath.Dir(mainRoot), filepath.Base(mainRoot)+"-"+name)
+	}
+	if path, err = filepath.Abs(path); err != nil {
+		return workspace{}, fmt.Errorf("invalid --path: %w", err)
+	}
+
+	branch := workspaceBranchPrefix + name
+	if _, err := gitOutput(ctx, "", "worktree", "add", "-b", branch, path, base); err != nil {
+		return workspace{}, fmt.Errorf("failed to create worktree: %w", err)
+	}
+	// Sessions record the worktree root as git reports it, with symlinks resolved
+	if root, err := gitOutput(ctx, path, "rev-parse", "--show-toplevel"); err == nil {
+		path = root
+	}
+
+	ws := workspace{Name: name, Path: path, Branch: branch, Base: base, CreatedAt: time.Now()}
+	if err := saveWorkspaces(ctx, append(workspaces, ws)); err != nil {
+		return workspace{}, err
+	}
+	return ws, nil
+}
+
+func runWorkspaceList(ctx context.Context, w io.Writer) error {
+	workspaces, err := loadWorkspaces(ctx)
+	if err != nil {
+		return err
+	}
+	if len(workspaces) == 0 {
+		fmt.Fprintln(w, "No workspaces. Create one with 'entire workspace new <name>'.")
+		return nil
+	}
+	states, err := strategy.ListSessionStates(ctx)
+	if err != nil {
+		return err //nolint:wrapcheck // already wrapped by strategy
+	}
+
+	for _, ws := range workspaces {
+		sessions := 0
+		for _, state := range states {
+			if state.WorktreePath == ws.Path && state.EndedAt == nil {
+				sessions++
+			}
+		}
+		line := fmt.Sprintf("%-16s %-24s %s", ws.Name, ws.Branch, ws.Path)
+		switch _, statErr := os.Stat(ws.Path); {
+		case statErr != nil:
+			line += "  (missing)"
+		case sessions > 0:
+			line += fmt.Sprintf("  (%d open session(s))", sessions)
+		}
+		fmt.Fprintln(w, line)
+	}
+	return nil
+}
+
+// workspaceReport is a workspace's results for comparison.
+type workspaceReport struct {
+	workspace workspace
+	sessions  map[string]bool
+	report    *sessionReport
+
+	// Diff from the workspace's base; hasDiff is false if it couldn't be read
+	hasDiff      bool
+	filesChanged int
+	insertions   int
+	deletions    int
+}
+
+func runWorkspaceCompare(ctx context.Context, w io.Writer, names []string) error {
+	workspaces, err := loadWorkspaces(ctx)
+	if err != nil {
+		return err
+	}
+	if len(names) > 0 {
+		var selected []workspace
+		for _, name := range names {
+			i := slices.IndexFunc(workspaces, func(ws workspace) bool { return ws.Name == name })
+			if i < 0 {
+				return fmt.Errorf("no workspace named %s", name)
+			}
+			selected = append(selected, workspaces[i])
+		}
+		workspaces = selected
+	}
+	if len(workspaces) == 0 {
+		fmt.Fprintln(w, "No workspaces. Create one with 'entire workspace new <name>'.")
+		return nil
+	}
+
+	reports, err := compareWorkspaces(ctx, workspaces)
+	if err != nil {
+		return err
+	}
+	writeWorkspaceComparison(w, reports)
+	return nil
+}
+
+// compareWorkspaces collects the results of each workspace: its diff from
+// its base and the committed checkpoints of its branch.
+func compareWorkspaces(ctx context.Context, workspaces []workspace) ([]*workspaceReport, error) {
+	repo, err := openRepository(ctx)
+	if err != nil {
+		return nil, err
+	}
+	store, err := strategy.CommittedStore(ctx, checkpoint.NewGitStore(repo))
+	if err != nil {
+		return nil, err //nolint:wrapcheck // already names the setting
+	}
+	committed, err := store.ListCommitted(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list checkpoints: %w", err)
+	}
+
+	reports := make([]*workspaceReport, len(workspaces))
+	byBranch := make(map[string]*workspaceReport)
+	for i, ws := range workspaces {
+		reports[i] = &workspaceReport{
+			workspace: ws,
+			sessions:  make(map[string]bool),
+			report:    &sessionReport{Files: make(map[string]bool)},
+		}
+		byBranch[ws.Branch] = reports[i]
+		readWorkspaceDiff(ctx, reports[i])
+	}
+	for _, info := range committed {
+		r, ok := byBranch[info.Branch]
+		if !ok {
+			continue
+		}
+		for i := range info.SessionCount {
+			content, err := store.ReadSessionContent(ctx, info.CheckpointID, i)
+			if err != nil {
+				continue
+			}
+			r.sessions[content.Metadata.SessionID] = true
+			addToSessionReport(r.report, info, content)
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err //nolint:wrapcheck // Propagating context cancellation
+	}
+	return reports, nil
+}
+
+// readWorkspaceDiff fills in the diff of the workspace's worktree from its
+// base. A missing worktree leaves the diff unset.
+func readWorkspaceDiff(ctx context.Context, r *workspaceReport) {
+	out, err := gitOutput(ctx, r.workspace.Path, "diff", "--shortstat", r.workspace.Base)
+	if err != nil {
+		return
+	}
+	r.hasDiff = true
+	match := shortstatPattern.FindStringSubmatch(out)
+	if match == nil {
+		return
+	}
+	r.filesChanged, _ = strconv.Atoi(match[1]) //nolint:errcheck // the pattern only matches digits
+	r.insertions, _ = strconv.Atoi(match[2])   //nolint:errcheck // empty when there are none
+	r.deletions, _ = strconv.Atoi(match[3])    //nolint:errcheck // empty when there are none
+}
+
+// comparisonRow is an extra row of a workspace comparison, with a cell per workspace.
+type comparisonRow struct {
+	label string
+	cells []string
+}
+
+// writeWorkspaceComparison renders the reports as a table with a column per
+// workspace, followed by the extra rows.
+func writeWorkspaceComparison(w io.Writer, reports []*workspaceReport, extra ...comparisonRow) {
+	labels := []string{"Workspace", "Branch", "Sessions", "Agent", "Model", "Checkpoints", "Files", "Lines", "Turns", "Tests", "Tokens in", "Tokens out", "Cost"}
+	columns := make([][]string, len(reports))
+	for i, r := range reports {
+		files, lines := "-", "-"
+		if r.hasDiff {
+			files = strconv.Itoa(r.filesChanged)
+			lines = fmt.Sprintf("+%d -%d", r.insertions, r.deletions)
+		}
+		columns[i] = []string{
+			r.workspace.Name,
+			r.workspace.Branch,
+			countOrDash(len(r.sessions)),
+			orDash(r.report.Agent),
+			orDash(r.report.Model),
+			countOrDash(r.report.Checkpoints),
+			files,
+			lines,
+			countOrDash(r.report.Turns),
+			r.report.formatTests(),
+			countOrDash(r.report.InputTokens),
+			countOrDash(r.report.OutputTokens),
+			r.report.formatCost(),
+		}
+	}
+	for _, row := range extra {
+		labels = append(labels, row.label)
+		for i := range columns {
+			columns[i] = append(columns[i], row.cells[i])
+		}
+	}
+
+	widths := make([]int, len(columns))
+	for i, column := range columns {
+		for _, cell := range column {
+			widths[i] = max(widths[i], len(cell))
+		}
+	}
+	labelWidth := 0 // one past the longest label, so labels are followed by two spaces
+	for _, label := range labels {
+		labelWidth = max(labelWidth, len(label)+1)
+	}
+	for row, label := range labels {
+		line := fmt.Sprintf("%-*s", labelWidth, label)
+		for i, column := range columns {
+			line += fmt.Sprintf(" %-*s", widths[i], column[row])
+		}
+		fmt.Fprintln(w, strings.TrimRight(line, " "))
+	}
+}
+
+func runWorkspaceRemove(ctx context.Context, w io.Writer, name string, force bool) error {
+	workspaces, err := loadWorkspaces(ctx)
+	if err != nil {
+		return err
+	}
+	i := slices.IndexFunc(workspaces, func(ws workspace) bool { return ws.Name == name })
+	if i < 0 {
+		return fmt.Errorf("no workspace named %s", name)
+	}
+	ws := workspaces[i]
+
+	if _, statErr := os.Stat(ws.Path); statErr == nil {
+		args := []string{"worktree", "remove"}
+		if force {
+			args = append(args, "--force")
+		}
+		if _, err := gitOutput(ctx, "", append(args, ws.Path)...); err != nil {
+			return fmt.Errorf("failed to remove worktree: %w", err)
+		}
+	} else if _, err := gitOutput(ctx, "", "worktree", "prune"); err != nil {
+		return fmt.Errorf("failed to prune worktrees: %w", err)
+	}
+
+	if err := saveWorkspaces(ctx, slices.Delete(workspaces, i, i+1)); err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "Removed workspace %s. Branch %s and its checkpoints were kept.\n", name, ws.Branch)
+	return nil
+}
+
+// workspaceCommonDir returns the absolute path of the git common dir.
+func workspaceCommonDir(ctx context.Context) (string, error) {
+	commonDir, err := strategy.GetGitCommonDir(ctx)
+	if err != nil {
+		return "", err //nolint:wrapcheck // already wrapped by strategy
+	}
+	abs, err := filepath.Abs(commonDir)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve git common dir: %w", err)
+	}
+	return abs, nil
+}
+
+// loadWorkspaces reads the workspace registry. A missing registry has no workspaces.
+func loadWorkspaces(ctx context.Context) ([]workspace, error) {
+	commonDir, err := workspaceCommonDir(ctx)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(filepath.Join(commonDir, workspacesFileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read workspaces: %w", err)
+	}
+	var workspaces []workspace
+	if err := json.Unmarshal(data, &workspaces); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", workspacesFileName, err)
+	}
+	return workspaces, nil
+}
+
+// saveWorkspaces writes the workspace registry atomically.
+func saveWorkspaces(ctx context.Context, workspaces []workspace) error {
+	commonDir, err := workspaceCommonDir(ctx)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(workspaces, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode workspaces: %w", err)
+	}
+	path := filepath.Join(commonDir, workspacesFileName)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write workspaces: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write workspaces: %w", err)
+	}
+	return nil
+}
+
+// gitOutput runs git in dir (the current directory if empty) and returns
+// its trimmed output, or an error with git's message.
+func gitOutput(ctx context.Context, dir string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %s: %w", args[0], strings.TrimSpace(stderr.String()), err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
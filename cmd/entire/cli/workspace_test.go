@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunWorkspace(t *testing.T) {
+	setupCleanTestRepo(t)
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	var stdout bytes.Buffer
+	if err := runWorkspaceNew(ctx, &stdout, "../bad", "HEAD", ""); err == nil {
+		t.Error("runWorkspaceNew() with an invalid name succeeded, want an error")
+	}
+	for _, name := range []string{"claude", "codex"} {
+		if err := runWorkspaceNew(ctx, &stdout, name, "HEAD", filepath.Join(dir, name)); err != nil {
+			t.Fatalf("runWorkspaceNew(%s) error = %v", name, err)
+		}
+	}
+	if err := runWorkspaceNew(ctx, &stdout, "claude", "HEAD", filepath.Join(dir, "again")); err == nil {
+		t.Error("runWorkspaceNew() with an existing name succeeded, want an error")
+	}
+
+	stdout.Reset()
+	if err := runWorkspaceList(ctx, &stdout); err != nil {
+		t.Fatalf("runWorkspaceList() error = %v", err)
+	}
+	if !strings.Contains(stdout.String(), "workspace/claude") || !strings.Contains(stdout.String(), "workspace/codex") {
+		t.Errorf("expected both workspaces in the list, got: %s", stdout.String())
+	}
+
+	// A change in one workspace shows up only in its column
+	if err := os.WriteFile(filepath.Join(dir, "claude", "notes.md"), []byte("one\ntwo\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := gitOutput(ctx, filepath.Join(dir, "claude"), "add", "notes.md"); err != nil {
+		t.Fatal(err)
+	}
+	stdout.Reset()
+	if err := runWorkspaceCompare(ctx, &stdout, nil); err != nil {
+		t.Fatalf("runWorkspaceCompare() error = %v", err)
+	}
+	var lines string
+	for _, line := range strings.Split(stdout.String(), "\n") {
+		if strings.HasPrefix(line, "Lines") {
+			lines = line
+		}
+	}
+	if fields := strings.Fields(lines); len(fields) != 5 || fields[1] != "+2" || fields[2] != "-0" || fields[3] != "+0" {
+		t.Errorf("Lines row = %q, want +2 -0 for claude and +0 -0 for codex\n%s", lines, stdout.String())
+	}
+	if err := runWorkspaceCompare(ctx, &stdout, []string{"missing"}); err == nil {
+		t.Error("runWorkspaceCompare() with an unknown name succeeded, want an error")
+	}
+
+	// Uncommitted changes keep the worktree unless forced; the branch is kept
+	if err := runWorkspaceRemove(ctx, &stdout, "claude", false); err == nil {
+		t.Error("runWorkspaceRemove() with changes succeeded, want an error")
+	}
+	if err := runWorkspaceRemove(ctx, &stdout, "claude", true); err != nil {
+		t.Fatalf("runWorkspaceRemove() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "claude")); !os.IsNotExist(err) {
+		t.Errorf("worktree still exists after remove: %v", err)
+	}
+	if _, err := gitOutput(ctx, "", "rev-parse", "--verify", "workspace/claude"); err != nil {
+		t.Errorf("branch was deleted with the workspace: %v", err)
+	}
+	workspaces, err := loadWorkspaces(ctx)
+	if err != nil {
+		t.Fatalf("loadWorkspaces() error = %v", err)
+	}
+	if len(workspaces) != 1 || workspaces[0].Name != "codex" {
+		t.Errorf("workspaces = %+v, want only codex", workspaces)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"os/signal"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/entireio/cli/cmd/entire/cli"
 	"github.com/spf13/cobra"
@@ -25,20 +26,52 @@ func main() {
 		cancel()
 	}()
 
+	// Hooks installed as binary shims run entire under the hook's name
+	if code, ok := cli.RunGitHookShim(ctx, os.Args); ok {
+		cancel()
+		os.Exit(code)
+	}
+
 	// Create and execute root command
 	rootCmd := cli.NewRootCmd()
-	err := rootCmd.ExecuteContext(ctx)
+
+	// Expand user-defined aliases from the "alias" setting
+	alias, err := cli.ResolveAlias(ctx, rootCmd, os.Args[1:])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		cancel()
+		os.Exit(1)
+	}
+	if alias != nil {
+		if alias.Shell != "" {
+			code := alias.RunShell(ctx)
+			cancel()
+			os.Exit(code)
+		}
+		rootCmd.SetArgs(alias.Args)
+	}
+
+	startedAt := time.Now()
+	executedCmd, err := rootCmd.ExecuteContextC(ctx)
 
 	if err != nil {
+		cli.RecordFailedOperation(executedCmd, os.Args[1:], err, startedAt)
+		cli.TrackCommandError(executedCmd, err)
+
 		var silent *cli.SilentError
+		var exitCode *cli.ExitCodeError
 
 		switch {
+		case errors.As(err, &exitCode):
+			// A command run by entire failed and explained why itself
+			cancel()
+			os.Exit(exitCode.Code)
 		case errors.As(err, &silent):
 			// Command already printed the error
 		case strings.Contains(err.Error(), "unknown command") || strings.Contains(err.Error(), "unknown flag"):
 			showSuggestion(rootCmd, err)
 		default:
-			fmt.Fprintln(rootCmd.OutOrStderr(), err)
+			fmt.Fprintln(rootCmd.OutOrStderr(), cli.ErrorMessage(err))
 		}
 
 		cancel()